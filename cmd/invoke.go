@@ -0,0 +1,305 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/telemetry"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"github.com/spf13/cobra"
+)
+
+// invokeOptions holds the flags of the invoke subcommand.
+type invokeOptions struct {
+	args     []string
+	payload  string
+	tokens   []string
+	format   string
+	list     bool
+	logLevel server.StringLevel
+}
+
+// newInvokeCommand returns the invoke subcommand of cmd, which invokes a tool
+// of the tools file once, without starting a server.
+func newInvokeCommand(cmd *Command) *cobra.Command {
+	opts := &invokeOptions{logLevel: "warn"}
+	invokeCmd := &cobra.Command{
+		Use:   "invoke [tool]",
+		Short: "Invoke a tool of the tools file once, without starting a server.",
+		Long: "Invokes a tool of the tools file once, and prints its result to stdout. " +
+			"Only the source of the tool is initialized. With --list, prints the tools " +
+			"of the tools file and their parameters, without connecting to anything.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runInvoke(cmd, opts, args)
+		},
+	}
+
+	flags := invokeCmd.Flags()
+	flags.StringVar(&cmd.tools_file, "tools-file", "", "File path specifying the tool configuration. Cannot be used with --prebuilt, --tools-files, or --tools-folder.")
+	flags.StringSliceVar(&cmd.tools_files, "tools-files", []string{}, "Multiple file paths specifying tool configurations. Files will be merged. Cannot be used with --prebuilt, --tools-file, or --tools-folder.")
+	flags.StringVar(&cmd.tools_folder, "tools-folder", "", "Directory path containing YAML tool configuration files. Cannot be used with --prebuilt, --tools-file, or --tools-files.")
+	flags.StringVar(&cmd.prebuiltConfig, "prebuilt", "", "Use a prebuilt tool configuration by source type. Cannot be used with --tools-file.")
+	flags.StringArrayVar(&opts.args, "arg", []string{}, "Argument of the tool, as 'name=value'. Values of non-string parameters are decoded as JSON. May be repeated.")
+	flags.StringVar(&opts.payload, "json", "", "Arguments of the tool, as a JSON object. Overridden by --arg.")
+	flags.StringArrayVar(&opts.tokens, "token", []string{}, "Token verified by an auth service, as 'authService=token'. May be repeated.")
+	flags.StringVar(&opts.format, "format", "json", "Format of the output. Allowed: 'json' or 'table'. Results that are not rows are always printed as JSON.")
+	flags.BoolVar(&opts.list, "list", false, "Lists the tools of the tools file and their parameters instead of invoking one.")
+	flags.Var(&opts.logLevel, "log-level", "Specify the minimum level logged to stderr. Allowed: 'DEBUG', 'INFO', 'WARN', 'ERROR'.")
+	return invokeCmd
+}
+
+// invokeError is the error printed when an invocation fails.
+type invokeError struct {
+	Message string `json:"error"`
+	// Errors lists the invalid arguments, if any.
+	Errors tools.ParamErrors `json:"errors,omitempty"`
+}
+
+func runInvoke(cmd *Command, opts *invokeOptions, args []string) error {
+	if opts.format != "json" && opts.format != "table" {
+		return fmt.Errorf("invalid --format %q: must be one of \"json\" or \"table\"", opts.format)
+	}
+	err := invoke(cmd, opts, args)
+	if err == nil {
+		return nil
+	}
+	ie := invokeError{Message: err.Error()}
+	errors.As(err, &ie.Errors)
+	if opts.format == "table" {
+		fmt.Fprintf(cmd.outStream, "error: %s\n", ie.Message)
+		return err
+	}
+	if encErr := writeJSON(cmd.outStream, ie); encErr != nil {
+		return errors.Join(err, encErr)
+	}
+	return err
+}
+
+func invoke(cmd *Command, opts *invokeOptions, args []string) error {
+	ctx := cmd.Context()
+	logger, err := log.NewStdLogger(cmd.errStream, cmd.errStream, opts.logLevel.String())
+	if err != nil {
+		return fmt.Errorf("unable to initialize logger: %w", err)
+	}
+	cmd.logger = logger
+	ctx = util.WithLogger(ctx, cmd.logger)
+
+	toolsFile, err := loadToolsFiles(ctx, cmd)
+	if err != nil {
+		return err
+	}
+	applyToolsFile(ctx, cmd, toolsFile)
+
+	if opts.list {
+		if len(args) > 0 {
+			return fmt.Errorf("--list does not take a tool name")
+		}
+		return writeToolSummaries(cmd.outStream, server.ToolSummaries(cmd.cfg.ToolConfigs), opts.format)
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("the name of the tool to invoke is required, or --list to list them")
+	}
+	toolName := args[0]
+
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(versionString)
+	if err != nil {
+		return fmt.Errorf("unable to create telemetry instrumentation: %w", err)
+	}
+	ctx = util.WithInstrumentation(ctx, instrumentation)
+
+	tool, authServices, err := server.InitializeTool(ctx, cmd.cfg, toolName)
+	if err != nil {
+		return err
+	}
+	if tool.RequiresClientAuthorization() {
+		return fmt.Errorf("tool %q requires client authorization, which is not supported when invoking it from the command line", toolName)
+	}
+
+	// the tokens are verified the same way as the headers of a request to
+	// the server
+	header := make(http.Header)
+	for _, token := range opts.tokens {
+		name, value, ok := strings.Cut(token, "=")
+		if !ok || name == "" {
+			return fmt.Errorf("invalid --token %q: must be 'authService=token'", token)
+		}
+		if _, ok := authServices[name]; !ok {
+			return fmt.Errorf("invalid --token %q: authService %q does not exist", token, name)
+		}
+		header.Set(name+"_token", value)
+	}
+	claimsFromAuth := make(map[string]map[string]any)
+	for name, a := range authServices {
+		claims, err := a.GetClaimsFromHeader(ctx, header)
+		if err != nil {
+			return fmt.Errorf("unable to verify the token of authService %q: %w", name, err)
+		}
+		if claims != nil {
+			claimsFromAuth[name] = claims
+		}
+	}
+	verifiedAuthServices := slices.Collect(maps.Keys(claimsFromAuth))
+	if !tool.Authorized(verifiedAuthServices) {
+		return fmt.Errorf("tool invocation not authorized: tool %q requires a token of one of the authServices %q, set with --token", toolName, tool.Manifest().AuthRequired)
+	}
+	ctx = util.WithVerifiedAuthServices(ctx, verifiedAuthServices)
+	ctx = util.WithAuthClaims(ctx, claimsFromAuth)
+
+	data, err := invokeArgs(tool, opts.payload, opts.args)
+	if err != nil {
+		return err
+	}
+	params, err := tool.ParseParams(data, claimsFromAuth)
+	if err != nil {
+		return fmt.Errorf("provided parameters were invalid: %w", err)
+	}
+	cmd.logger.DebugContext(ctx, fmt.Sprintf("invocation params: %s", params.Redacted()))
+
+	inv := tools.Invocation{Claims: claimsFromAuth, Metadata: tools.RequestMetadata{Protocol: "cli"}}
+	res, err := tool.Invoke(ctx, inv.WithParams(params))
+	if err != nil {
+		// the values of sensitive parameters may be quoted by the error
+		return fmt.Errorf("error while invoking tool: %w", params.RedactError(err))
+	}
+	if mr, ok := res.(tools.MeteredResult); ok {
+		res = mr.Result
+	}
+	if it, ok := res.(tools.RowIterator); ok {
+		if res, err = tools.CollectRows(it); err != nil {
+			return fmt.Errorf("error while invoking tool: %w", err)
+		}
+	}
+	return writeResult(cmd.outStream, res, opts.format)
+}
+
+// invokeArgs returns the arguments of an invocation of tool: the object of
+// payload, overridden by the 'name=value' pairs. The values of the pairs are
+// taken as is for string parameters, and decoded as JSON otherwise.
+func invokeArgs(tool tools.Tool, payload string, pairs []string) (map[string]any, error) {
+	data := make(map[string]any)
+	if payload != "" {
+		if err := util.DecodeJSON(strings.NewReader(payload), &data); err != nil {
+			return nil, fmt.Errorf("invalid --json: must be a JSON object: %w", err)
+		}
+	}
+	paramTypes := make(map[string]string)
+	for _, p := range tool.Manifest().Parameters {
+		paramTypes[p.Name] = p.Type
+	}
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --arg %q: must be 'name=value'", pair)
+		}
+		if paramTypes[name] == "string" {
+			data[name] = value
+			continue
+		}
+		var v any
+		if err := util.DecodeJSON(strings.NewReader(value), &v); err != nil {
+			// left for the parameter to reject, with its expected type
+			v = value
+		}
+		data[name] = v
+	}
+	return data, nil
+}
+
+// writeResult writes the result of an invocation in format. Only results
+// that are rows are written as a table.
+func writeResult(w io.Writer, res any, format string) error {
+	rows, ok := res.([]any)
+	if format != "table" || !ok {
+		return writeJSON(w, res)
+	}
+	var columns []string
+	for _, row := range rows {
+		m, ok := row.(map[string]any)
+		if !ok {
+			return writeJSON(w, res)
+		}
+		for c := range m {
+			if !slices.Contains(columns, c) {
+				columns = append(columns, c)
+			}
+		}
+	}
+	slices.Sort(columns)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(columns, "\t"))
+	for _, row := range rows {
+		m := row.(map[string]any)
+		cells := make([]string, len(columns))
+		for i, c := range columns {
+			cells[i] = tableCell(m[c])
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+// tableCell formats a value of a row for a table: strings as is, and other
+// values as JSON.
+func tableCell(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// writeToolSummaries writes the summaries of the tools of the tools file in
+// format.
+func writeToolSummaries(w io.Writer, summaries []server.ToolSummary, format string) error {
+	if format != "table" {
+		return writeJSON(w, summaries)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tKIND\tSOURCE\tPARAMETERS")
+	for _, s := range summaries {
+		params := make([]string, len(s.Parameters))
+		for i, p := range s.Parameters {
+			params[i] = fmt.Sprintf("%s (%s)", p.Name, p.Type)
+			if !p.Required {
+				params[i] = fmt.Sprintf("%s (%s, optional)", p.Name, p.Type)
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", s.Name, s.Kind, s.Source, strings.Join(params, ", "))
+	}
+	return tw.Flush()
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,307 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+// invokeTestToolsFile holds a mock tool, a tool of a SQLite source, and an
+// unreachable Postgres source that must not be initialized.
+var invokeTestToolsFile = `
+	sources:
+		my-sqlite:
+			kind: sqlite
+			database: ":memory:"
+		unreachable-pg:
+			kind: postgres
+			host: 127.0.0.1
+			port: 1
+			database: db
+			user: user
+			password: password
+	authServices:
+		my-google-auth:
+			kind: google
+			clientId: my-client-id
+	tools:
+		weather:
+			kind: mock
+			description: the weather of a city
+			aliases:
+				- get_weather
+			parameters:
+				- name: city
+				  type: string
+				  description: the city
+				- name: days
+				  type: integer
+				  description: the number of days
+				  default: 1
+			responses:
+				- match:
+					  city: Paris
+				  result:
+					  temperature: 21
+				- match:
+					  city: "75001"
+				  result:
+					  temperature: 22
+				- match:
+					  city: "*"
+				  error: unknown city
+		select-names:
+			kind: sqlite-sql
+			source: my-sqlite
+			description: lists names
+			statement: SELECT ? AS name, 1 AS n UNION ALL SELECT 'bob', 2
+			parameters:
+				- name: name
+				  type: string
+				  description: the first name
+		secret:
+			kind: mock
+			description: a secret
+			default:
+				result: hidden
+			authRequired:
+				- my-google-auth
+	`
+
+// invokeTool runs the invoke subcommand with the tools file, and returns
+// what it wrote to stdout.
+func invokeTool(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "tools.yaml")
+	if err := os.WriteFile(path, testutils.FormatYaml(invokeTestToolsFile), 0o644); err != nil {
+		t.Fatalf("unable to write tools file: %s", err)
+	}
+	out := new(bytes.Buffer)
+	c := NewCommand(WithStreams(out, io.Discard))
+	c.SetArgs(append([]string{"invoke", "--tools-file", path}, args...))
+	err := c.Execute()
+	return out.String(), err
+}
+
+func TestInvokeTool(t *testing.T) {
+	tcs := []struct {
+		desc string
+		args []string
+		want any
+	}{
+		{
+			desc: "with args",
+			args: []string{"weather", "--arg", "city=Paris", "--arg", "days=2"},
+			want: map[string]any{"temperature": float64(21)},
+		},
+		{
+			desc: "with json",
+			args: []string{"weather", "--json", `{"city": "Paris"}`},
+			want: map[string]any{"temperature": float64(21)},
+		},
+		{
+			desc: "args override json",
+			args: []string{"weather", "--json", `{"city": "Rome"}`, "--arg", "city=Paris"},
+			want: map[string]any{"temperature": float64(21)},
+		},
+		{
+			desc: "by alias",
+			args: []string{"get_weather", "--arg", "city=Paris"},
+			want: map[string]any{"temperature": float64(21)},
+		},
+		{
+			desc: "string args are not decoded",
+			args: []string{"weather", "--arg", "city=75001"},
+			want: map[string]any{"temperature": float64(22)},
+		},
+		{
+			desc: "with sqlite source",
+			args: []string{"select-names", "--arg", "name=alice"},
+			want: []any{
+				map[string]any{"name": "alice", "n": float64(1)},
+				map[string]any{"name": "bob", "n": float64(2)},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			out, err := invokeTool(t, tc.args...)
+			if err != nil {
+				t.Fatalf("unexpected error: %s; output: %s", err, out)
+			}
+			var got any
+			if err := json.Unmarshal([]byte(out), &got); err != nil {
+				t.Fatalf("output is not JSON: %s: %q", err, out)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestInvokeToolTable(t *testing.T) {
+	out, err := invokeTool(t, "select-names", "--arg", "name=alice", "--format", "table")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := "n  name\n1  alice\n2  bob\n"
+	if out != want {
+		t.Fatalf("incorrect table: got %q, want %q", out, want)
+	}
+
+	// results that are not rows are printed as JSON
+	out, err = invokeTool(t, "weather", "--arg", "city=Paris", "--format", "table")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(out, `"temperature": 21`) {
+		t.Fatalf("result is not JSON: %q", out)
+	}
+}
+
+func TestInvokeToolErrors(t *testing.T) {
+	tcs := []struct {
+		desc       string
+		args       []string
+		wantErr    string
+		wantParams []string
+	}{
+		{
+			desc:    "failed invocation",
+			args:    []string{"weather", "--arg", "city=Rome"},
+			wantErr: "error while invoking tool: unknown city",
+		},
+		{
+			desc:       "invalid params",
+			args:       []string{"weather", "--arg", "days=many"},
+			wantErr:    "provided parameters were invalid",
+			wantParams: []string{"city", "days"},
+		},
+		{
+			desc:    "unknown tool",
+			args:    []string{"forecast"},
+			wantErr: `tool with name "forecast" does not exist`,
+		},
+		{
+			desc:    "missing tool name",
+			args:    []string{},
+			wantErr: "the name of the tool to invoke is required",
+		},
+		{
+			desc:    "invalid arg",
+			args:    []string{"weather", "--arg", "city"},
+			wantErr: `invalid --arg "city": must be 'name=value'`,
+		},
+		{
+			desc:    "invalid json",
+			args:    []string{"weather", "--json", `["Paris"]`},
+			wantErr: "invalid --json: must be a JSON object",
+		},
+		{
+			desc:    "missing token",
+			args:    []string{"secret"},
+			wantErr: `tool "secret" requires a token of one of the authServices ["my-google-auth"]`,
+		},
+		{
+			desc:    "token of unknown auth service",
+			args:    []string{"secret", "--token", "other-auth=abc"},
+			wantErr: `authService "other-auth" does not exist`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			out, err := invokeTool(t, tc.args...)
+			if err == nil {
+				t.Fatalf("expected an error, got output %q", out)
+			}
+			var got struct {
+				Error  string `json:"error"`
+				Errors []struct {
+					Name string `json:"name"`
+				} `json:"errors"`
+			}
+			if err := json.Unmarshal([]byte(out), &got); err != nil {
+				t.Fatalf("error is not JSON: %s: %q", err, out)
+			}
+			if !strings.Contains(got.Error, tc.wantErr) {
+				t.Fatalf("incorrect error: got %q, want it to contain %q", got.Error, tc.wantErr)
+			}
+			var gotParams []string
+			for _, e := range got.Errors {
+				gotParams = append(gotParams, e.Name)
+			}
+			if diff := cmp.Diff(tc.wantParams, gotParams); diff != "" {
+				t.Fatalf("incorrect invalid params (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestInvokeToolList(t *testing.T) {
+	out, err := invokeTool(t, "--list")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var got []struct {
+		Name       string   `json:"name"`
+		Kind       string   `json:"kind"`
+		Source     string   `json:"source"`
+		Aliases    []string `json:"aliases"`
+		Parameters []struct {
+			Name     string `json:"name"`
+			Required bool   `json:"required"`
+		} `json:"parameters"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("output is not JSON: %s: %q", err, out)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 tools, got %d: %q", len(got), out)
+	}
+	if got[0].Name != "secret" || got[1].Name != "select-names" || got[2].Name != "weather" {
+		t.Fatalf("tools are not sorted by name: %q", out)
+	}
+	if got[1].Kind != "sqlite-sql" || got[1].Source != "my-sqlite" {
+		t.Fatalf("incorrect kind or source of select-names: %+v", got[1])
+	}
+	if diff := cmp.Diff([]string{"get_weather"}, got[2].Aliases); diff != "" {
+		t.Fatalf("incorrect aliases (-want +got):\n%s", diff)
+	}
+	if len(got[2].Parameters) != 2 || !got[2].Parameters[0].Required || got[2].Parameters[1].Required {
+		t.Fatalf("incorrect parameters of weather: %+v", got[2].Parameters)
+	}
+
+	out, err = invokeTool(t, "--list", "--format", "table")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := "NAME          KIND        SOURCE     PARAMETERS\n" +
+		"secret        mock                   \n" +
+		"select-names  sqlite-sql  my-sqlite  name (string)\n" +
+		"weather       mock                   city (string), days (integer, optional)\n"
+	if out != want {
+		t.Fatalf("incorrect table: got %q, want %q", out, want)
+	}
+}
@@ -33,10 +33,12 @@ import (
 	"github.com/fsnotify/fsnotify"
 	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/events"
 	"github.com/googleapis/genai-toolbox/internal/log"
 	"github.com/googleapis/genai-toolbox/internal/prebuiltconfigs"
 	"github.com/googleapis/genai-toolbox/internal/server"
 	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/state"
 	"github.com/googleapis/genai-toolbox/internal/telemetry"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/util"
@@ -96,7 +98,13 @@ import (
 	_ "github.com/googleapis/genai-toolbox/internal/tools/firestore/firestorequerycollection"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/firestore/firestoreupdatedocument"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/firestore/firestorevalidaterules"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/gcs/gcsgetobject"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/gcs/gcslistobjects"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/gcs/gcsputobject"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/http"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/http/httprequest"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/kafka/kafkaconsumelatest"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/kafka/kafkaproduce"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/looker/lookeradddashboardelement"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/looker/lookerconversationalanalytics"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/looker/lookercreateprojectfile"
@@ -118,6 +126,7 @@ import (
 	_ "github.com/googleapis/genai-toolbox/internal/tools/looker/lookergetprojectfile"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/looker/lookergetprojectfiles"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/looker/lookergetprojects"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/looker/lookergetsql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/looker/lookerhealthanalyze"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/looker/lookerhealthpulse"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/looker/lookerhealthvacuum"
@@ -128,11 +137,20 @@ import (
 	_ "github.com/googleapis/genai-toolbox/internal/tools/looker/lookerqueryurl"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/looker/lookerrunlook"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/looker/lookerupdateprojectfile"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/mindsdb/mindsdbcreateknowledgebase"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/mindsdb/mindsdbcreateproject"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/mindsdb/mindsdbdescribemodel"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/mindsdb/mindsdbdropmodel"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/mindsdb/mindsdbexecutesql"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/mindsdb/mindsdbqueryknowledgebase"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/mindsdb/mindsdbretrainmodel"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/mindsdb/mindsdbsql"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/mindsdb/mindsdbuploadfile"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/mongodb/mongodbaggregate"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/mongodb/mongodbcountdocuments"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/mongodb/mongodbdeletemany"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/mongodb/mongodbdeleteone"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/mongodb/mongodbexists"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/mongodb/mongodbfind"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/mongodb/mongodbfindone"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/mongodb/mongodbinsertmany"
@@ -147,6 +165,7 @@ import (
 	_ "github.com/googleapis/genai-toolbox/internal/tools/mysql/mysqllisttablefragmentation"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/mysql/mysqllisttables"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/mysql/mysqllisttablesmissinguniqueindexes"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/mysql/mysqlsampletable"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/mysql/mysqlsql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/neo4j/neo4jcypher"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/neo4j/neo4jexecutecypher"
@@ -156,13 +175,18 @@ import (
 	_ "github.com/googleapis/genai-toolbox/internal/tools/oracle/oracleexecutesql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/oracle/oraclesql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/postgres/postgresexecutesql"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/postgres/postgresinsertrows"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/postgres/postgreslistactivequeries"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/postgres/postgreslistavailableextensions"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/postgres/postgreslistinstalledextensions"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/postgres/postgreslistmaterializedviews"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/postgres/postgreslisttables"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/postgres/postgreslistviews"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/postgres/postgresrefreshmaterializedview"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/postgres/postgressampletable"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/postgres/postgressql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/redis"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/redis/redispublish"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/serverlessspark/serverlesssparkgetbatch"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/serverlessspark/serverlesssparklistbatches"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/spanner/spannerexecutesql"
@@ -171,11 +195,16 @@ import (
 	_ "github.com/googleapis/genai-toolbox/internal/tools/sqlite/sqliteexecutesql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/sqlite/sqlitesql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/tidb/tidbexecutesql"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/tidb/tidbexplain"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/tidb/tidbsql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/trino/trinoexecutesql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/trino/trinosql"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/utility/mock"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/utility/sessionhistory"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/utility/sqldiff"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/utility/wait"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/valkey"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/vertexai/vertexaiembed"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/yugabytedbsql"
 
 	"github.com/spf13/cobra"
@@ -196,7 +225,9 @@ import (
 	_ "github.com/googleapis/genai-toolbox/internal/sources/dgraph"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/firebird"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/firestore"
+	_ "github.com/googleapis/genai-toolbox/internal/sources/gcs"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/http"
+	_ "github.com/googleapis/genai-toolbox/internal/sources/kafka"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/looker"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/mindsdb"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/mongodb"
@@ -302,6 +333,13 @@ func NewCommand(opts ...Option) *Command {
 	flags := cmd.Flags()
 	flags.StringVarP(&cmd.cfg.Address, "address", "a", "127.0.0.1", "Address of the interface the server will listen on.")
 	flags.IntVarP(&cmd.cfg.Port, "port", "p", 5000, "Port the server will listen on.")
+	flags.StringSliceVar(&cmd.cfg.Listen, "listen", []string{}, "Addresses the server will listen on, as 'tcp://host:port' or 'unix:///path/to.sock'. May be repeated. Overrides --address and --port.")
+	flags.Var(&cmd.cfg.SocketMode, "socket-mode", "File mode applied to Unix domain sockets, in octal.")
+	flags.StringVar(&cmd.cfg.TLSCertFile, "tls-cert-file", "", "PEM file of the TLS certificate of the server. Every listener serves over TLS if set. Requires --tls-key-file.")
+	flags.StringVar(&cmd.cfg.TLSKeyFile, "tls-key-file", "", "PEM file of the private key of --tls-cert-file.")
+	flags.StringVar(&cmd.cfg.TLSClientCAFile, "tls-client-ca-file", "", "PEM bundle of the CAs that client certificates are verified against, enabling mTLS.")
+	flags.StringVar(&cmd.cfg.TLSClientAuth, "tls-client-auth", "", "Verification of client certificates. Allowed: 'requireAndVerify' or 'verifyIfGiven'. Defaults to 'requireAndVerify' if --tls-client-ca-file is set.")
+	flags.BoolVar(&cmd.cfg.TLSReload, "tls-reload", false, "Reloads the TLS files when they change, so that certificates can be rotated without a restart.")
 
 	flags.StringVar(&cmd.tools_file, "tools_file", "", "File path specifying the tool configuration. Cannot be used with --prebuilt.")
 	// deprecate tools_file
@@ -323,9 +361,46 @@ func NewCommand(opts ...Option) *Command {
 	flags.BoolVar(&cmd.cfg.Stdio, "stdio", false, "Listens via MCP STDIO instead of acting as a remote HTTP server.")
 	flags.BoolVar(&cmd.cfg.DisableReload, "disable-reload", false, "Disables dynamic reloading of tools file.")
 	flags.BoolVar(&cmd.cfg.UI, "ui", false, "Launches the Toolbox UI web server.")
+	flags.StringVar(&cmd.cfg.AdminAuthService, "admin-auth-service", "", "Name of the authService that guards the admin endpoints, such as rotating source credentials. Admin endpoints are disabled if not set.")
+	flags.StringSliceVar(&cmd.cfg.AdminSubjects, "admin-subjects", []string{}, "Subjects ('sub' claims) verified by --admin-auth-service that are allowed to call the admin endpoints. May be repeated.")
+	flags.StringSliceVar(&cmd.cfg.AdminEmails, "admin-emails", []string{}, "Verified emails ('email' claims) verified by --admin-auth-service that are allowed to call the admin endpoints. May be repeated.")
+	flags.DurationVar(&cmd.cfg.SourceInitTimeout, "source-init-timeout", server.DefaultSourceInitTimeout, "Maximum time to initialize each source, such as '30s'.")
+	flags.IntVar(&cmd.cfg.SourceInitConcurrency, "source-init-concurrency", server.DefaultSourceInitConcurrency, "Maximum number of sources initialized at once.")
+	flags.StringVar(&cmd.cfg.ArtifactDir, "artifact-dir", "", "Directory that tools with 'spillToFile' write large results to. Defaults to a directory in the system temporary directory.")
+	flags.DurationVar(&cmd.cfg.ArtifactTTL, "artifact-ttl", server.DefaultArtifactTTL, "How long spilled results can be downloaded before they are removed, such as '1h'.")
+	flags.Int64Var(&cmd.cfg.ArtifactUploadMaxBytes, "artifact-upload-max-bytes", server.DefaultArtifactUploadMaxBytes, "Maximum size in bytes of an artifact uploaded to PUT /api/artifacts.")
+	flags.StringSliceVar(&cmd.cfg.ArtifactUploadAuthServices, "artifact-upload-auth-services", []string{}, "Names of the authServices that verify the clients uploading to PUT /api/artifacts. May be repeated. Uploads are disabled if not set.")
+	flags.Int64Var(&cmd.cfg.ArtifactMaxBytes, "artifact-max-bytes", server.DefaultArtifactMaxBytes, "Maximum total size in bytes of the stored artifacts. The oldest artifacts are removed to stay below it.")
+	flags.IntVar(&cmd.cfg.ArtifactMaxCount, "artifact-max-count", server.DefaultArtifactMaxCount, "Maximum number of stored artifacts. The oldest artifacts are removed to stay below it.")
+	flags.StringVar(&cmd.cfg.ClientAttribution, "client-attribution", "", "Forward the identity of callers to backends, such as the Postgres application_name. Allowed: 'auth' (subject of the verified auth token) or 'header' (the X-Toolbox-Client-Name header). Disabled if not set.")
+	flags.Int64Var(&cmd.cfg.McpWebsocketMaxMessageSize, "mcp-ws-max-message-size", server.DefaultMcpWebsocketMaxMessageSize, "Maximum size in bytes of a message received over the MCP websocket transport. Larger messages close the connection.")
+	flags.StringVar(&cmd.cfg.StateRedisAddress, "state-redis-address", "", "Address of a Redis or Valkey server that keeps the state shared by replicas, such as cached source schemas, as 'host:port' or a 'redis://' or 'rediss://' URL. Each replica keeps its own state in memory if not set.")
+	flags.BoolVar(&cmd.cfg.StateRedisTLS, "state-redis-tls", false, "Connects to the --state-redis-address server over TLS.")
+	flags.StringVar(&cmd.cfg.StateKeyPrefix, "state-key-prefix", state.DefaultKeyPrefix, "Prefix of the keys written to the --state-redis-address server.")
+	flags.DurationVar(&cmd.cfg.StateMaxTTL, "state-max-ttl", server.DefaultStateMaxTTL, "Maximum TTL of the keys written to the --state-redis-address server, such as '1h'.")
+	flags.StringVar(&cmd.cfg.EventsPubSubTopic, "events-pubsub-topic", "", "Pub/Sub topic that an event is published to for every invocation of a tool, such as 'projects/my-project/topics/toolbox-events'. Uses Application Default Credentials.")
+	flags.StringVar(&cmd.cfg.EventsFile, "events-file", "", "File that an event is appended to as a JSON line for every invocation of a tool, or '-' for stdout. Cannot be used with --events-pubsub-topic.")
+	flags.IntVar(&cmd.cfg.EventsBufferSize, "events-buffer-size", events.DefaultBufferSize, "Maximum number of invocation events waiting to be published. Further events are dropped and counted.")
+	flags.IntVar(&cmd.cfg.EventsBatchSize, "events-batch-size", events.DefaultBatchSize, "Maximum number of invocation events published at once.")
+	flags.DurationVar(&cmd.cfg.EventsFlushInterval, "events-flush-interval", events.DefaultFlushInterval, "How long an invocation event waits for its batch to fill up before it is published, such as '5s'.")
+	flags.IntVar(&cmd.cfg.SessionHistorySize, "session-history-size", 0, "Number of invocations of tools with a statement kept in the history of each session for the session-history tool. Disabled if 0.")
+	flags.DurationVar(&cmd.cfg.SessionHistoryTTL, "session-history-ttl", server.DefaultSessionHistoryTTL, "How long the history of a session is kept after its last invocation, such as '30m'.")
+	flags.DurationVar(&cmd.cfg.StickySessionTTL, "sticky-session-ttl", server.DefaultStickySessionTTL, "How long a connection pinned to a session is kept after its last invocation, such as '10m'.")
+	flags.IntVar(&cmd.cfg.StickySessionMaxConns, "sticky-session-max-conns", server.DefaultStickySessionMaxConns, "Maximum number of connections of each source pinned to sessions by tools with stickySession.")
+	flags.DurationVar(&cmd.cfg.IdempotencyTTL, "idempotency-ttl", server.DefaultIdempotencyTTL, "How long the result of an invocation is replayed to retries with the same idempotency key, such as '1h'.")
+	flags.IntVar(&cmd.cfg.BulkInvokeMaxLines, "bulk-invoke-max-lines", server.DefaultBulkInvokeMaxLines, "Maximum number of lines of a bulk invocation of a tool.")
+	flags.IntVar(&cmd.cfg.BulkInvokeConcurrency, "bulk-invoke-concurrency", server.DefaultBulkInvokeConcurrency, "Maximum number of lines of a bulk invocation of a tool invoked at once.")
+	flags.IntVar(&cmd.cfg.MaxPayloadDepth, "max-payload-depth", server.DefaultMaxPayloadDepth, "Maximum nesting depth of the JSON arguments of an invocation of a tool.")
+	flags.IntVar(&cmd.cfg.MaxPayloadKeys, "max-payload-keys", server.DefaultMaxPayloadKeys, "Maximum total number of keys of the objects of the JSON arguments of an invocation of a tool.")
+	flags.IntVar(&cmd.cfg.MaxPayloadStringLength, "max-payload-string-length", server.DefaultMaxPayloadStringLength, "Maximum length in bytes of a string of the JSON arguments of an invocation of a tool.")
+	flags.Int64Var(&cmd.cfg.MaxResolvedResourceBytes, "max-resolved-resource-bytes", server.DefaultMaxResolvedResourceBytes, "Maximum total length in bytes of the resources referenced by the arguments of an invocation of a tool.")
+	flags.StringSliceVar(&cmd.cfg.DisabledToolKinds, "disabled-tool-kinds", []string{}, "Kinds of tools that cannot be invoked, such as 'postgres-execute-sql'. Their tools are still listed, marked as disabled. May be repeated.")
+	flags.BoolVar(&cmd.cfg.HideDisabledTools, "hide-disabled-tools", false, "Removes the tools of the --disabled-tool-kinds from the listings of tools.")
+	flags.BoolVar(&cmd.cfg.Dev, "dev", false, "Enables endpoints meant for tool authors, such as rendering the statement of a tool. Do not use in production.")
 
 	// wrap RunE command so that we have access to original Command object
 	cmd.RunE = func(*cobra.Command, []string) error { return run(cmd) }
+	cmd.AddCommand(newInvokeCommand(cmd))
 
 	return cmd
 }
@@ -336,6 +411,20 @@ type ToolsFile struct {
 	AuthServices server.AuthServiceConfigs `yaml:"authServices"`
 	Tools        server.ToolConfigs        `yaml:"tools"`
 	Toolsets     server.ToolsetConfigs     `yaml:"toolsets"`
+	// Schedule is the default schedule of the tools that do not have one.
+	Schedule *tools.Schedule `yaml:"schedule"`
+	// IncludeUsageMetadata indicates if the responses of the tools that do not
+	// set `includeUsageMetadata` themselves include usage metadata.
+	IncludeUsageMetadata bool `yaml:"includeUsageMetadata"`
+	// ResultTimezone is the default timezone the timestamps of results are
+	// converted to.
+	ResultTimezone *tools.ResultTimezone `yaml:"resultTimezone"`
+	// DefaultAuthRequired lists the authServices required to invoke the tools
+	// that do not require any, by themselves or through their toolsets.
+	DefaultAuthRequired []string `yaml:"defaultAuthRequired"`
+	// ReferencedFiles lists the files loaded while parsing, such as the
+	// files referenced by `statementFile`.
+	ReferencedFiles []string `yaml:"-"`
 }
 
 // parseEnv replaces environment variables ${ENV_NAME} with their values.
@@ -379,6 +468,23 @@ func parseToolsFile(ctx context.Context, raw []byte) (ToolsFile, error) {
 	return toolsFile, nil
 }
 
+// loadToolsFile reads and parses the tools file at filePath. File references
+// within the tools file are resolved relative to its directory.
+func loadToolsFile(ctx context.Context, filePath string) (ToolsFile, error) {
+	buf, err := os.ReadFile(filePath)
+	if err != nil {
+		return ToolsFile{}, fmt.Errorf("unable to read tool file at %q: %w", filePath, err)
+	}
+
+	info := &util.ToolsFileInfo{Dir: filepath.Dir(filePath)}
+	toolsFile, err := parseToolsFile(util.WithToolsFileInfo(ctx, info), buf)
+	if err != nil {
+		return ToolsFile{}, fmt.Errorf("unable to parse tool file at %q: %w", filePath, err)
+	}
+	toolsFile.ReferencedFiles = info.ReferencedFiles
+	return toolsFile, nil
+}
+
 // mergeToolsFiles merges multiple ToolsFile structs into one.
 // Detects and raises errors for resource conflicts in sources, authServices, tools, and toolsets.
 // All resource names (sources, authServices, tools, toolsets) must be unique across all files.
@@ -393,6 +499,36 @@ func mergeToolsFiles(files ...ToolsFile) (ToolsFile, error) {
 	var conflicts []string
 
 	for fileIndex, file := range files {
+		merged.ReferencedFiles = append(merged.ReferencedFiles, file.ReferencedFiles...)
+
+		// Only one file can set the default schedule
+		if file.Schedule != nil {
+			if merged.Schedule != nil {
+				conflicts = append(conflicts, fmt.Sprintf("schedule (file #%d)", fileIndex+1))
+			} else {
+				merged.Schedule = file.Schedule
+			}
+		}
+		merged.IncludeUsageMetadata = merged.IncludeUsageMetadata || file.IncludeUsageMetadata
+
+		// Only one file can set the default result timezone
+		if file.ResultTimezone != nil {
+			if merged.ResultTimezone != nil {
+				conflicts = append(conflicts, fmt.Sprintf("resultTimezone (file #%d)", fileIndex+1))
+			} else {
+				merged.ResultTimezone = file.ResultTimezone
+			}
+		}
+
+		// Only one file can set the default authServices
+		if file.DefaultAuthRequired != nil {
+			if merged.DefaultAuthRequired != nil {
+				conflicts = append(conflicts, fmt.Sprintf("defaultAuthRequired (file #%d)", fileIndex+1))
+			} else {
+				merged.DefaultAuthRequired = file.DefaultAuthRequired
+			}
+		}
+
 		// Check for conflicts and merge sources
 		for name, source := range file.Sources {
 			if _, exists := merged.Sources[name]; exists {
@@ -441,7 +577,7 @@ func mergeToolsFiles(files ...ToolsFile) (ToolsFile, error) {
 
 	// If conflicts were detected, return an error
 	if len(conflicts) > 0 {
-		return ToolsFile{}, fmt.Errorf("resource conflicts detected:\n  - %s\n\nPlease ensure each source, authService, tool, and toolset has a unique name across all files", strings.Join(conflicts, "\n  - "))
+		return ToolsFile{}, fmt.Errorf("resource conflicts detected:\n  - %s\n\nPlease ensure each source, authService, tool, and toolset has a unique name across all files, and that at most one file sets the default schedule", strings.Join(conflicts, "\n  - "))
 	}
 
 	return merged, nil
@@ -452,14 +588,9 @@ func loadAndMergeToolsFiles(ctx context.Context, filePaths []string) (ToolsFile,
 	var toolsFiles []ToolsFile
 
 	for _, filePath := range filePaths {
-		buf, err := os.ReadFile(filePath)
+		toolsFile, err := loadToolsFile(ctx, filePath)
 		if err != nil {
-			return ToolsFile{}, fmt.Errorf("unable to read tool file at %q: %w", filePath, err)
-		}
-
-		toolsFile, err := parseToolsFile(ctx, buf)
-		if err != nil {
-			return ToolsFile{}, fmt.Errorf("unable to parse tool file at %q: %w", filePath, err)
+			return ToolsFile{}, err
 		}
 
 		toolsFiles = append(toolsFiles, toolsFile)
@@ -509,13 +640,83 @@ func loadAndMergeToolsFolder(ctx context.Context, folderPath string) (ToolsFile,
 	return loadAndMergeToolsFiles(ctx, allFiles)
 }
 
+// loadToolsFiles loads the tool configuration selected by the flags of cmd:
+// a prebuilt configuration, several tools files, a tools folder, or else a
+// single tools file, "tools.yaml" by default.
+func loadToolsFiles(ctx context.Context, cmd *Command) (ToolsFile, error) {
+	if cmd.prebuiltConfig != "" {
+		// Make sure --prebuilt and --tools-file/--tools-files/--tools-folder flags are mutually exclusive
+		if cmd.tools_file != "" || len(cmd.tools_files) > 0 || cmd.tools_folder != "" {
+			return ToolsFile{}, fmt.Errorf("--prebuilt and --tools-file/--tools-files/--tools-folder flags cannot be used simultaneously")
+		}
+		// Use prebuilt tools
+		buf, err := prebuiltconfigs.Get(cmd.prebuiltConfig)
+		if err != nil {
+			return ToolsFile{}, err
+		}
+		logMsg := fmt.Sprint("Using prebuilt tool configuration for ", cmd.prebuiltConfig)
+		cmd.logger.InfoContext(ctx, logMsg)
+		// Append prebuilt.source to Version string for the User Agent
+		cmd.cfg.Version += "+prebuilt." + cmd.prebuiltConfig
+
+		toolsFile, err := parseToolsFile(ctx, buf)
+		if err != nil {
+			return ToolsFile{}, fmt.Errorf("unable to parse prebuilt tool configuration: %w", err)
+		}
+		return toolsFile, nil
+	}
+	if len(cmd.tools_files) > 0 {
+		// Make sure --tools-file, --tools-files, and --tools-folder flags are mutually exclusive
+		if cmd.tools_file != "" || cmd.tools_folder != "" {
+			return ToolsFile{}, fmt.Errorf("--tools-file, --tools-files, and --tools-folder flags cannot be used simultaneously")
+		}
+
+		// Use multiple tools files
+		cmd.logger.InfoContext(ctx, fmt.Sprintf("Loading and merging %d tool configuration files", len(cmd.tools_files)))
+		return loadAndMergeToolsFiles(ctx, cmd.tools_files)
+	}
+	if cmd.tools_folder != "" {
+		// Make sure --tools-folder and other flags are mutually exclusive
+		if cmd.tools_file != "" {
+			return ToolsFile{}, fmt.Errorf("--tools-file, --tools-files, and --tools-folder flags cannot be used simultaneously")
+		}
+
+		// Use tools folder
+		cmd.logger.InfoContext(ctx, fmt.Sprintf("Loading and merging all YAML files from directory: %s", cmd.tools_folder))
+		return loadAndMergeToolsFolder(ctx, cmd.tools_folder)
+	}
+	// Set default value of tools-file flag to tools.yaml
+	if cmd.tools_file == "" {
+		cmd.tools_file = "tools.yaml"
+	}
+
+	// Read single tool file contents
+	return loadToolsFile(ctx, cmd.tools_file)
+}
+
+// applyToolsFile sets the configs of the server of cmd to the ones of
+// toolsFile.
+func applyToolsFile(ctx context.Context, cmd *Command, toolsFile ToolsFile) {
+	cmd.cfg.SourceConfigs, cmd.cfg.AuthServiceConfigs, cmd.cfg.ToolConfigs, cmd.cfg.ToolsetConfigs = toolsFile.Sources, toolsFile.AuthServices, toolsFile.Tools, toolsFile.Toolsets
+	cmd.cfg.DefaultSchedule = toolsFile.Schedule
+	cmd.cfg.IncludeUsageMetadata = toolsFile.IncludeUsageMetadata
+	cmd.cfg.DefaultResultTimezone = toolsFile.ResultTimezone
+	cmd.cfg.DefaultAuthRequired = toolsFile.DefaultAuthRequired
+	authSourceConfigs := toolsFile.AuthSources
+	if authSourceConfigs != nil {
+		cmd.logger.WarnContext(ctx, "`authSources` is deprecated, use `authServices` instead")
+		cmd.cfg.AuthServiceConfigs = authSourceConfigs
+	}
+}
+
 func handleDynamicReload(ctx context.Context, toolsFile ToolsFile, s *server.Server) error {
 	logger, err := util.LoggerFromContext(ctx)
 	if err != nil {
 		panic(err)
 	}
 
-	sourcesMap, authServicesMap, toolsMap, toolsetsMap, err := validateReloadEdits(ctx, toolsFile)
+	disabledToolKinds, hideDisabledTools := s.DisabledToolKinds()
+	sourcesMap, authServicesMap, toolsMap, toolsetsMap, err := validateReloadEdits(ctx, toolsFile, disabledToolKinds, hideDisabledTools)
 	if err != nil {
 		errMsg := fmt.Errorf("unable to validate reloaded edits: %w", err)
 		logger.WarnContext(ctx, errMsg.Error())
@@ -523,13 +724,18 @@ func handleDynamicReload(ctx context.Context, toolsFile ToolsFile, s *server.Ser
 	}
 
 	s.ResourceMgr.SetResources(sourcesMap, authServicesMap, toolsMap, toolsetsMap)
+	s.ResourceMgr.SetToolSources(server.ToolSources(toolsFile.Tools))
+	s.ResourceMgr.SetToolKinds(server.ToolKinds(toolsFile.Tools))
+	s.ResourceMgr.SetSourceDetails(server.SourceDetails(toolsFile.Sources))
+	s.ResourceMgr.SetSourceHashes(server.SourceHashes(toolsFile.Sources))
 
 	return nil
 }
 
-// validateReloadEdits checks that the reloaded tools file configs can initialized without failing
+// validateReloadEdits checks that the reloaded tools file configs can initialized without failing.
+// The tools of disabledToolKinds stay disabled.
 func validateReloadEdits(
-	ctx context.Context, toolsFile ToolsFile,
+	ctx context.Context, toolsFile ToolsFile, disabledToolKinds []string, hideDisabledTools bool,
 ) (map[string]sources.Source, map[string]auth.AuthService, map[string]tools.Tool, map[string]tools.Toolset, error,
 ) {
 	logger, err := util.LoggerFromContext(ctx)
@@ -548,11 +754,17 @@ func validateReloadEdits(
 	defer span.End()
 
 	reloadedConfig := server.ServerConfig{
-		Version:            versionString,
-		SourceConfigs:      toolsFile.Sources,
-		AuthServiceConfigs: toolsFile.AuthServices,
-		ToolConfigs:        toolsFile.Tools,
-		ToolsetConfigs:     toolsFile.Toolsets,
+		Version:               versionString,
+		SourceConfigs:         toolsFile.Sources,
+		AuthServiceConfigs:    toolsFile.AuthServices,
+		ToolConfigs:           toolsFile.Tools,
+		ToolsetConfigs:        toolsFile.Toolsets,
+		DefaultSchedule:       toolsFile.Schedule,
+		IncludeUsageMetadata:  toolsFile.IncludeUsageMetadata,
+		DefaultResultTimezone: toolsFile.ResultTimezone,
+		DefaultAuthRequired:   toolsFile.DefaultAuthRequired,
+		DisabledToolKinds:     disabledToolKinds,
+		HideDisabledTools:     hideDisabledTools,
 	}
 
 	sourcesMap, authServicesMap, toolsMap, toolsetsMap, err := server.InitializeConfigs(ctx, reloadedConfig)
@@ -565,8 +777,9 @@ func validateReloadEdits(
 	return sourcesMap, authServicesMap, toolsMap, toolsetsMap, nil
 }
 
-// watchChanges checks for changes in the provided yaml tools file(s) or folder.
-func watchChanges(ctx context.Context, watchDirs map[string]bool, watchedFiles map[string]bool, s *server.Server) {
+// watchChanges checks for changes in the provided yaml tools file(s) or folder,
+// as well as in the files referenced by them.
+func watchChanges(ctx context.Context, watchDirs map[string]bool, watchedFiles map[string]bool, referencedFiles []string, s *server.Server) {
 	logger, err := util.LoggerFromContext(ctx)
 	if err != nil {
 		panic(err)
@@ -599,15 +812,39 @@ func watchChanges(ctx context.Context, watchDirs map[string]bool, watchedFiles m
 		}
 	}
 
+	watchingDirs := make(map[string]bool)
 	for dir := range watchDirs {
 		err := w.Add(dir)
 		if err != nil {
 			logger.WarnContext(ctx, fmt.Sprintf("Error adding path %s to watcher: %s", dir, err))
 			break
 		}
+		watchingDirs[dir] = true
 		logger.DebugContext(ctx, fmt.Sprintf("Added directory %s to watcher.", dir))
 	}
 
+	// referenced tracks files loaded by the tools file(s), such as the files
+	// referenced by `statementFile`. It is refreshed after every reload.
+	var referenced map[string]bool
+	watchReferencedFiles := func(files []string) {
+		referenced = make(map[string]bool, len(files))
+		for _, f := range files {
+			cleanFile := filepath.Clean(f)
+			referenced[cleanFile] = true
+			dir := filepath.Dir(cleanFile)
+			if watchingDirs[dir] {
+				continue
+			}
+			if err := w.Add(dir); err != nil {
+				logger.WarnContext(ctx, fmt.Sprintf("Error adding path %s to watcher: %s", dir, err))
+				continue
+			}
+			watchingDirs[dir] = true
+			logger.DebugContext(ctx, fmt.Sprintf("Added directory %s to watcher.", dir))
+		}
+	}
+	watchReferencedFiles(referencedFiles)
+
 	// debounce timer is used to prevent multiple writes triggering multiple reloads
 	debounceDelay := 100 * time.Millisecond
 	debounce := time.NewTimer(1 * time.Minute)
@@ -646,7 +883,7 @@ func watchChanges(ctx context.Context, watchDirs map[string]bool, watchedFiles m
 			folderChanged := watchingFolder &&
 				(strings.HasSuffix(cleanedFilename, ".yaml") || strings.HasSuffix(cleanedFilename, ".yml"))
 
-			if folderChanged || watchedFiles[cleanedFilename] {
+			if folderChanged || watchedFiles[cleanedFilename] || referenced[cleanedFilename] {
 				// indicates the write event is on a relevant file
 				debounce.Reset(debounceDelay)
 			}
@@ -673,10 +910,11 @@ func watchChanges(ctx context.Context, watchDirs map[string]bool, watchedFiles m
 
 			err = handleDynamicReload(ctx, reloadedToolsFile, s)
 			if err != nil {
-				errMsg := fmt.Errorf("unable to parse reloaded tools file at %q: %w", reloadedToolsFile, err)
+				errMsg := fmt.Errorf("unable to parse reloaded tools file(s): %w", err)
 				logger.WarnContext(ctx, errMsg.Error())
 				continue
 			}
+			watchReferencedFiles(reloadedToolsFile.ReferencedFiles)
 		}
 	}
 }
@@ -773,92 +1011,12 @@ func run(cmd *Command) error {
 		}
 	}()
 
-	var toolsFile ToolsFile
-
-	if cmd.prebuiltConfig != "" {
-		// Make sure --prebuilt and --tools-file/--tools-files/--tools-folder flags are mutually exclusive
-		if cmd.tools_file != "" || len(cmd.tools_files) > 0 || cmd.tools_folder != "" {
-			errMsg := fmt.Errorf("--prebuilt and --tools-file/--tools-files/--tools-folder flags cannot be used simultaneously")
-			cmd.logger.ErrorContext(ctx, errMsg.Error())
-			return errMsg
-		}
-		// Use prebuilt tools
-		buf, err := prebuiltconfigs.Get(cmd.prebuiltConfig)
-		if err != nil {
-			cmd.logger.ErrorContext(ctx, err.Error())
-			return err
-		}
-		logMsg := fmt.Sprint("Using prebuilt tool configuration for ", cmd.prebuiltConfig)
-		cmd.logger.InfoContext(ctx, logMsg)
-		// Append prebuilt.source to Version string for the User Agent
-		cmd.cfg.Version += "+prebuilt." + cmd.prebuiltConfig
-
-		toolsFile, err = parseToolsFile(ctx, buf)
-		if err != nil {
-			errMsg := fmt.Errorf("unable to parse prebuilt tool configuration: %w", err)
-			cmd.logger.ErrorContext(ctx, errMsg.Error())
-			return errMsg
-		}
-	} else if len(cmd.tools_files) > 0 {
-		// Make sure --tools-file, --tools-files, and --tools-folder flags are mutually exclusive
-		if cmd.tools_file != "" || cmd.tools_folder != "" {
-			errMsg := fmt.Errorf("--tools-file, --tools-files, and --tools-folder flags cannot be used simultaneously")
-			cmd.logger.ErrorContext(ctx, errMsg.Error())
-			return errMsg
-		}
-
-		// Use multiple tools files
-		cmd.logger.InfoContext(ctx, fmt.Sprintf("Loading and merging %d tool configuration files", len(cmd.tools_files)))
-		var err error
-		toolsFile, err = loadAndMergeToolsFiles(ctx, cmd.tools_files)
-		if err != nil {
-			cmd.logger.ErrorContext(ctx, err.Error())
-			return err
-		}
-	} else if cmd.tools_folder != "" {
-		// Make sure --tools-folder and other flags are mutually exclusive
-		if cmd.tools_file != "" || len(cmd.tools_files) > 0 {
-			errMsg := fmt.Errorf("--tools-file, --tools-files, and --tools-folder flags cannot be used simultaneously")
-			cmd.logger.ErrorContext(ctx, errMsg.Error())
-			return errMsg
-		}
-
-		// Use tools folder
-		cmd.logger.InfoContext(ctx, fmt.Sprintf("Loading and merging all YAML files from directory: %s", cmd.tools_folder))
-		var err error
-		toolsFile, err = loadAndMergeToolsFolder(ctx, cmd.tools_folder)
-		if err != nil {
-			cmd.logger.ErrorContext(ctx, err.Error())
-			return err
-		}
-	} else {
-		// Set default value of tools-file flag to tools.yaml
-		if cmd.tools_file == "" {
-			cmd.tools_file = "tools.yaml"
-		}
-
-		// Read single tool file contents
-		buf, err := os.ReadFile(cmd.tools_file)
-		if err != nil {
-			errMsg := fmt.Errorf("unable to read tool file at %q: %w", cmd.tools_file, err)
-			cmd.logger.ErrorContext(ctx, errMsg.Error())
-			return errMsg
-		}
-
-		toolsFile, err = parseToolsFile(ctx, buf)
-		if err != nil {
-			errMsg := fmt.Errorf("unable to parse tool file at %q: %w", cmd.tools_file, err)
-			cmd.logger.ErrorContext(ctx, errMsg.Error())
-			return errMsg
-		}
-	}
-
-	cmd.cfg.SourceConfigs, cmd.cfg.AuthServiceConfigs, cmd.cfg.ToolConfigs, cmd.cfg.ToolsetConfigs = toolsFile.Sources, toolsFile.AuthServices, toolsFile.Tools, toolsFile.Toolsets
-	authSourceConfigs := toolsFile.AuthSources
-	if authSourceConfigs != nil {
-		cmd.logger.WarnContext(ctx, "`authSources` is deprecated, use `authServices` instead")
-		cmd.cfg.AuthServiceConfigs = authSourceConfigs
+	toolsFile, err := loadToolsFiles(ctx, cmd)
+	if err != nil {
+		cmd.logger.ErrorContext(ctx, err.Error())
+		return err
 	}
+	applyToolsFile(ctx, cmd, toolsFile)
 
 	instrumentation, err := telemetry.CreateTelemetryInstrumentation(versionString)
 	if err != nil {
@@ -894,9 +1052,27 @@ func run(cmd *Command) error {
 			cmd.logger.ErrorContext(ctx, errMsg.Error())
 			return errMsg
 		}
+		for _, addr := range s.Addrs() {
+			cmd.logger.InfoContext(ctx, fmt.Sprintf("Server listening on %s", addr))
+		}
 		cmd.logger.InfoContext(ctx, "Server ready to serve!")
+		readyLine := log.NewReadyLine(s.Addrs())
+		if s.TLSEnabled() && readyLine.URL != "" {
+			readyLine.URL = "https://" + strings.TrimPrefix(readyLine.URL, "http://")
+		}
+		if err := log.WriteReadyLine(cmd.outStream, readyLine); err != nil {
+			cmd.logger.WarnContext(ctx, err.Error())
+		}
 		if cmd.cfg.UI {
-			cmd.logger.InfoContext(ctx, fmt.Sprintf("Toolbox UI is up and running at: http://%s:%d/ui", cmd.cfg.Address, cmd.cfg.Port))
+			for _, addr := range s.Addrs() {
+				if hostPort, ok := strings.CutPrefix(addr, "tcp://"); ok {
+					scheme := "http"
+					if s.TLSEnabled() {
+						scheme = "https"
+					}
+					cmd.logger.InfoContext(ctx, fmt.Sprintf("Toolbox UI is up and running at: %s://%s/ui", scheme, hostPort))
+				}
+			}
 		}
 
 		go func() {
@@ -912,7 +1088,7 @@ func run(cmd *Command) error {
 
 	if !cmd.cfg.DisableReload {
 		// start watching the file(s) or folder for changes to trigger dynamic reloading
-		go watchChanges(ctx, watchDirs, watchedFiles, s)
+		go watchChanges(ctx, watchDirs, watchedFiles, toolsFile.ReferencedFiles, s)
 	}
 
 	// wait for either the server to error out or the command's context to be canceled
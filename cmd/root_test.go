@@ -18,13 +18,18 @@ import (
 	"bytes"
 	"context"
 	_ "embed"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"net"
+	nethttp "net/http"
 	"os"
 	"path"
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -32,15 +37,19 @@ import (
 	"github.com/google/go-cmp/cmp"
 
 	"github.com/googleapis/genai-toolbox/internal/auth/google"
+	"github.com/googleapis/genai-toolbox/internal/events"
 	"github.com/googleapis/genai-toolbox/internal/log"
 	"github.com/googleapis/genai-toolbox/internal/prebuiltconfigs"
+	"github.com/googleapis/genai-toolbox/internal/secrets"
 	"github.com/googleapis/genai-toolbox/internal/server"
 	cloudsqlpgsrc "github.com/googleapis/genai-toolbox/internal/sources/cloudsqlpg"
 	httpsrc "github.com/googleapis/genai-toolbox/internal/sources/http"
+	"github.com/googleapis/genai-toolbox/internal/state"
 	"github.com/googleapis/genai-toolbox/internal/telemetry"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/tools/http"
+	"github.com/googleapis/genai-toolbox/internal/tools/postgres/postgresexecutesql"
 	"github.com/googleapis/genai-toolbox/internal/tools/postgres/postgressql"
 	"github.com/googleapis/genai-toolbox/internal/util"
 	"github.com/spf13/cobra"
@@ -57,9 +66,90 @@ func withDefaults(c server.ServerConfig) server.ServerConfig {
 	if c.Port == 0 {
 		c.Port = 5000
 	}
+	if c.Listen == nil {
+		c.Listen = []string{}
+	}
+	if c.DisabledToolKinds == nil {
+		c.DisabledToolKinds = []string{}
+	}
+	if c.AdminSubjects == nil {
+		c.AdminSubjects = []string{}
+	}
+	if c.AdminEmails == nil {
+		c.AdminEmails = []string{}
+	}
+	if c.ArtifactUploadAuthServices == nil {
+		c.ArtifactUploadAuthServices = []string{}
+	}
 	if c.TelemetryServiceName == "" {
 		c.TelemetryServiceName = "toolbox"
 	}
+	if c.SourceInitTimeout == 0 {
+		c.SourceInitTimeout = server.DefaultSourceInitTimeout
+	}
+	if c.SourceInitConcurrency == 0 {
+		c.SourceInitConcurrency = server.DefaultSourceInitConcurrency
+	}
+	if c.ArtifactTTL == 0 {
+		c.ArtifactTTL = server.DefaultArtifactTTL
+	}
+	if c.ArtifactUploadMaxBytes == 0 {
+		c.ArtifactUploadMaxBytes = server.DefaultArtifactUploadMaxBytes
+	}
+	if c.ArtifactMaxBytes == 0 {
+		c.ArtifactMaxBytes = server.DefaultArtifactMaxBytes
+	}
+	if c.ArtifactMaxCount == 0 {
+		c.ArtifactMaxCount = server.DefaultArtifactMaxCount
+	}
+	if c.MaxResolvedResourceBytes == 0 {
+		c.MaxResolvedResourceBytes = server.DefaultMaxResolvedResourceBytes
+	}
+	if c.McpWebsocketMaxMessageSize == 0 {
+		c.McpWebsocketMaxMessageSize = server.DefaultMcpWebsocketMaxMessageSize
+	}
+	if c.StateKeyPrefix == "" {
+		c.StateKeyPrefix = state.DefaultKeyPrefix
+	}
+	if c.StateMaxTTL == 0 {
+		c.StateMaxTTL = server.DefaultStateMaxTTL
+	}
+	if c.IdempotencyTTL == 0 {
+		c.IdempotencyTTL = server.DefaultIdempotencyTTL
+	}
+	if c.SessionHistoryTTL == 0 {
+		c.SessionHistoryTTL = server.DefaultSessionHistoryTTL
+	}
+	if c.StickySessionTTL == 0 {
+		c.StickySessionTTL = server.DefaultStickySessionTTL
+	}
+	if c.StickySessionMaxConns == 0 {
+		c.StickySessionMaxConns = server.DefaultStickySessionMaxConns
+	}
+	if c.BulkInvokeMaxLines == 0 {
+		c.BulkInvokeMaxLines = server.DefaultBulkInvokeMaxLines
+	}
+	if c.BulkInvokeConcurrency == 0 {
+		c.BulkInvokeConcurrency = server.DefaultBulkInvokeConcurrency
+	}
+	if c.MaxPayloadDepth == 0 {
+		c.MaxPayloadDepth = server.DefaultMaxPayloadDepth
+	}
+	if c.MaxPayloadKeys == 0 {
+		c.MaxPayloadKeys = server.DefaultMaxPayloadKeys
+	}
+	if c.MaxPayloadStringLength == 0 {
+		c.MaxPayloadStringLength = server.DefaultMaxPayloadStringLength
+	}
+	if c.EventsBufferSize == 0 {
+		c.EventsBufferSize = events.DefaultBufferSize
+	}
+	if c.EventsBatchSize == 0 {
+		c.EventsBatchSize = events.DefaultBatchSize
+	}
+	if c.EventsFlushInterval == 0 {
+		c.EventsFlushInterval = events.DefaultFlushInterval
+	}
 	return c
 }
 
@@ -142,6 +232,31 @@ func TestServerConfigFlags(t *testing.T) {
 				Port: 5050,
 			}),
 		},
+		{
+			desc: "listen",
+			args: []string{"--listen", "unix:///tmp/toolbox.sock", "--listen", "tcp://0.0.0.0:5000"},
+			want: withDefaults(server.ServerConfig{
+				Listen: []string{"unix:///tmp/toolbox.sock", "tcp://0.0.0.0:5000"},
+			}),
+		},
+		{
+			desc: "socket mode",
+			args: []string{"--socket-mode", "0600"},
+			want: withDefaults(server.ServerConfig{
+				SocketMode: 0o600,
+			}),
+		},
+		{
+			desc: "tls",
+			args: []string{"--tls-cert-file", "server.pem", "--tls-key-file", "server-key.pem", "--tls-client-ca-file", "ca.pem", "--tls-client-auth", "verifyIfGiven", "--tls-reload"},
+			want: withDefaults(server.ServerConfig{
+				TLSCertFile:     "server.pem",
+				TLSKeyFile:      "server-key.pem",
+				TLSClientCAFile: "ca.pem",
+				TLSClientAuth:   "verifyIfGiven",
+				TLSReload:       true,
+			}),
+		},
 		{
 			desc: "logging format",
 			args: []string{"--logging-format", "JSON"},
@@ -184,6 +299,137 @@ func TestServerConfigFlags(t *testing.T) {
 				Stdio: true,
 			}),
 		},
+		{
+			desc: "admin auth service",
+			args: []string{"--admin-auth-service", "my-admin-auth", "--admin-subjects", "1234567890", "--admin-emails", "ops@example.com,oncall@example.com"},
+			want: withDefaults(server.ServerConfig{
+				AdminAuthService: "my-admin-auth",
+				AdminSubjects:    []string{"1234567890"},
+				AdminEmails:      []string{"ops@example.com", "oncall@example.com"},
+			}),
+		},
+		{
+			desc: "source init",
+			args: []string{"--source-init-timeout", "1m", "--source-init-concurrency", "2"},
+			want: withDefaults(server.ServerConfig{
+				SourceInitTimeout:     time.Minute,
+				SourceInitConcurrency: 2,
+			}),
+		},
+		{
+			desc: "artifacts",
+			args: []string{"--artifact-dir", "/var/lib/toolbox/artifacts", "--artifact-ttl", "15m"},
+			want: withDefaults(server.ServerConfig{
+				ArtifactDir: "/var/lib/toolbox/artifacts",
+				ArtifactTTL: 15 * time.Minute,
+			}),
+		},
+		{
+			desc: "resource references",
+			args: []string{"--artifact-upload-max-bytes", "1048576", "--max-resolved-resource-bytes", "4194304"},
+			want: withDefaults(server.ServerConfig{
+				ArtifactUploadMaxBytes:   1 << 20,
+				MaxResolvedResourceBytes: 4 << 20,
+			}),
+		},
+		{
+			desc: "artifact uploads and quota",
+			args: []string{"--artifact-upload-auth-services", "my-google-auth,my-oidc", "--artifact-max-bytes", "1073741824", "--artifact-max-count", "50"},
+			want: withDefaults(server.ServerConfig{
+				ArtifactUploadAuthServices: []string{"my-google-auth", "my-oidc"},
+				ArtifactMaxBytes:           1 << 30,
+				ArtifactMaxCount:           50,
+			}),
+		},
+		{
+			desc: "state",
+			args: []string{"--state-redis-address", "redis.internal:6380", "--state-redis-tls", "--state-key-prefix", "staging:", "--state-max-ttl", "10m"},
+			want: withDefaults(server.ServerConfig{
+				StateRedisAddress: "redis.internal:6380",
+				StateRedisTLS:     true,
+				StateKeyPrefix:    "staging:",
+				StateMaxTTL:       10 * time.Minute,
+			}),
+		},
+		{
+			desc: "events",
+			args: []string{"--events-pubsub-topic", "projects/my-project/topics/toolbox-events", "--events-buffer-size", "500", "--events-batch-size", "20", "--events-flush-interval", "1s"},
+			want: withDefaults(server.ServerConfig{
+				EventsPubSubTopic:   "projects/my-project/topics/toolbox-events",
+				EventsBufferSize:    500,
+				EventsBatchSize:     20,
+				EventsFlushInterval: time.Second,
+			}),
+		},
+		{
+			desc: "session history",
+			args: []string{"--session-history-size", "50", "--session-history-ttl", "1h"},
+			want: withDefaults(server.ServerConfig{
+				SessionHistorySize: 50,
+				SessionHistoryTTL:  time.Hour,
+			}),
+		},
+		{
+			desc: "sticky sessions",
+			args: []string{"--sticky-session-ttl", "5m", "--sticky-session-max-conns", "4"},
+			want: withDefaults(server.ServerConfig{
+				StickySessionTTL:      5 * time.Minute,
+				StickySessionMaxConns: 4,
+			}),
+		},
+		{
+			desc: "idempotency TTL",
+			args: []string{"--idempotency-ttl", "24h"},
+			want: withDefaults(server.ServerConfig{
+				IdempotencyTTL: 24 * time.Hour,
+			}),
+		},
+		{
+			desc: "client attribution",
+			args: []string{"--client-attribution", "header"},
+			want: withDefaults(server.ServerConfig{
+				ClientAttribution: "header",
+			}),
+		},
+		{
+			desc: "mcp websocket max message size",
+			args: []string{"--mcp-ws-max-message-size", "1024"},
+			want: withDefaults(server.ServerConfig{
+				McpWebsocketMaxMessageSize: 1024,
+			}),
+		},
+		{
+			desc: "bulk invoke",
+			args: []string{"--bulk-invoke-max-lines", "500", "--bulk-invoke-concurrency", "16"},
+			want: withDefaults(server.ServerConfig{
+				BulkInvokeMaxLines:    500,
+				BulkInvokeConcurrency: 16,
+			}),
+		},
+		{
+			desc: "payload limits",
+			args: []string{"--max-payload-depth", "20", "--max-payload-keys", "500", "--max-payload-string-length", "1024"},
+			want: withDefaults(server.ServerConfig{
+				MaxPayloadDepth:        20,
+				MaxPayloadKeys:         500,
+				MaxPayloadStringLength: 1024,
+			}),
+		},
+		{
+			desc: "disabled tool kinds",
+			args: []string{"--disabled-tool-kinds", "postgres-execute-sql,mysql-execute-sql", "--hide-disabled-tools"},
+			want: withDefaults(server.ServerConfig{
+				DisabledToolKinds: []string{"postgres-execute-sql", "mysql-execute-sql"},
+				HideDisabledTools: true,
+			}),
+		},
+		{
+			desc: "dev",
+			args: []string{"--dev"},
+			want: withDefaults(server.ServerConfig{
+				Dev: true,
+			}),
+		},
 		{
 			desc: "disable reload",
 			args: []string{"--disable-reload"},
@@ -420,6 +666,14 @@ func TestFailServerConfigFlags(t *testing.T) {
 			desc: "debug logs",
 			args: []string{"--log-level", "fail"},
 		},
+		{
+			desc: "socket mode not octal",
+			args: []string{"--socket-mode", "rw"},
+		},
+		{
+			desc: "socket mode out of range",
+			args: []string{"--socket-mode", "01777"},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -466,89 +720,1451 @@ func TestParseToolFile(t *testing.T) {
 		wantToolsFile ToolsFile
 	}{
 		{
-			description: "basic example",
+			description: "basic example",
+			in: `
+			sources:
+				my-pg-instance:
+					kind: cloud-sql-postgres
+					project: my-project
+					region: my-region
+					instance: my-instance
+					database: my_db
+					user: my_user
+					password: my_pass
+			tools:
+				example_tool:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: |
+						SELECT * FROM SQL_STATEMENT;
+					parameters:
+						- name: country
+							type: string
+							description: some description
+			toolsets:
+				example_toolset:
+					- example_tool
+			`,
+			wantToolsFile: ToolsFile{
+				Sources: server.SourceConfigs{
+					"my-pg-instance": cloudsqlpgsrc.Config{
+						Name:     "my-pg-instance",
+						Kind:     cloudsqlpgsrc.SourceKind,
+						Project:  "my-project",
+						Region:   "my-region",
+						Instance: "my-instance",
+						IPType:   "public",
+						Database: "my_db",
+						User:     "my_user",
+						Password: "my_pass",
+					},
+				},
+				Tools: server.ToolConfigs{
+					"example_tool": postgressql.Config{
+						Name:        "example_tool",
+						Kind:        "postgres-sql",
+						Source:      "my-pg-instance",
+						Description: "some description",
+						Statement:   "SELECT * FROM SQL_STATEMENT;\n",
+						Parameters: []tools.Parameter{
+							tools.NewStringParameter("country", "some description"),
+						},
+						AuthRequired: []string{},
+					},
+				},
+				Toolsets: server.ToolsetConfigs{
+					"example_toolset": tools.ToolsetConfig{
+						Name:      "example_toolset",
+						ToolNames: []string{"example_tool"},
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.description, func(t *testing.T) {
+			toolsFile, err := parseToolsFile(ctx, testutils.FormatYaml(tc.in))
+			if err != nil {
+				t.Fatalf("failed to parse input: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantToolsFile.Sources, toolsFile.Sources); diff != "" {
+				t.Fatalf("incorrect sources parse: diff %v", diff)
+			}
+			if diff := cmp.Diff(tc.wantToolsFile.AuthServices, toolsFile.AuthServices); diff != "" {
+				t.Fatalf("incorrect authServices parse: diff %v", diff)
+			}
+			if diff := cmp.Diff(tc.wantToolsFile.Tools, toolsFile.Tools); diff != "" {
+				t.Fatalf("incorrect tools parse: diff %v", diff)
+			}
+			if diff := cmp.Diff(tc.wantToolsFile.Toolsets, toolsFile.Toolsets); diff != "" {
+				t.Fatalf("incorrect tools parse: diff %v", diff)
+			}
+		})
+	}
+
+}
+
+func TestParseToolFileWithToolsetDescriptionOverrides(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	toolsets:
+		en_toolset:
+			- search_hotels
+		ja_toolset:
+			tools:
+				- search_hotels
+			descriptionOverrides:
+				search_hotels:
+					description: 名前でホテルを検索する。
+					parameters:
+						name: ホテルの名前
+	`
+	toolsFile, err := parseToolsFile(ctx, testutils.FormatYaml(in))
+	if err != nil {
+		t.Fatalf("failed to parse input: %v", err)
+	}
+	want := server.ToolsetConfigs{
+		"en_toolset": tools.ToolsetConfig{
+			Name:      "en_toolset",
+			ToolNames: []string{"search_hotels"},
+		},
+		"ja_toolset": tools.ToolsetConfig{
+			Name:      "ja_toolset",
+			ToolNames: []string{"search_hotels"},
+			DescriptionOverrides: map[string]tools.DescriptionOverride{
+				"search_hotels": {
+					Description: "名前でホテルを検索する。",
+					Parameters:  map[string]string{"name": "ホテルの名前"},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, toolsFile.Toolsets); diff != "" {
+		t.Fatalf("incorrect toolsets parse: diff %v", diff)
+	}
+
+	in = `
+	toolsets:
+		ja_toolset:
+			tools:
+				- search_hotels
+			descriptionOverride:
+				search_hotels:
+					description: 名前でホテルを検索する。
+	`
+	_, err = parseToolsFile(ctx, testutils.FormatYaml(in))
+	if err == nil || !strings.Contains(err.Error(), `invalid toolset "ja_toolset"`) {
+		t.Fatalf("expected an invalid toolset error, got %v", err)
+	}
+}
+
+func TestParseToolFileWithAliases(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		example_tool:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: SELECT 1;
+			aliases:
+				- old_tool
+			deprecated: true
+			deprecationMessage: use example_tool instead
+	`
+	want := server.ToolConfigs{
+		"example_tool": tools.AliasConfig{
+			ToolConfig: postgressql.Config{
+				Name:         "example_tool",
+				Kind:         "postgres-sql",
+				Source:       "my-pg-instance",
+				Description:  "some description",
+				Statement:    "SELECT 1;",
+				AuthRequired: []string{},
+			},
+			Aliases:            []string{"old_tool"},
+			Deprecated:         true,
+			DeprecationMessage: "use example_tool instead",
+		},
+	}
+	toolsFile, err := parseToolsFile(ctx, testutils.FormatYaml(in))
+	if err != nil {
+		t.Fatalf("failed to parse input: %v", err)
+	}
+	if diff := cmp.Diff(want, toolsFile.Tools); diff != "" {
+		t.Fatalf("incorrect tools parse: diff %v", diff)
+	}
+}
+
+func TestFailParseToolFileWithAliases(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		description string
+		in          string
+		errString   string
+	}{
+		{
+			description: "alias conflicts with tool name",
+			in: `
+			tools:
+				tool_a:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: SELECT 1;
+					aliases:
+						- tool_b
+				tool_b:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: SELECT 1;
+			`,
+			errString: `alias "tool_b" of tool "tool_a" conflicts with an existing tool name`,
+		},
+		{
+			description: "invalid aliases type",
+			in: `
+			tools:
+				tool_a:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: SELECT 1;
+					aliases: tool_b
+			`,
+			errString: `invalid 'aliases' field for tool "tool_a" (must be a list of strings)`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.description, func(t *testing.T) {
+			_, err := parseToolsFile(ctx, testutils.FormatYaml(tc.in))
+			if err == nil {
+				t.Fatalf("expected parsing to fail")
+			}
+			if !strings.Contains(err.Error(), tc.errString) {
+				t.Fatalf("incorrect error string: got %s, want %s", err, tc.errString)
+			}
+		})
+	}
+}
+
+func TestParseToolFileWithSerialize(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		migrate:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: SELECT 1;
+			serialize: true
+			queueTimeout: 30s
+			aliases:
+				- old_migrate
+		refresh:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: SELECT 1;
+			serialize: true
+		other:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: SELECT 1;
+			serialize: false
+	`
+	want := server.ToolConfigs{
+		"migrate": tools.AliasConfig{
+			ToolConfig: tools.SerializeConfig{
+				ToolConfig: postgressql.Config{
+					Name:         "migrate",
+					Kind:         "postgres-sql",
+					Source:       "my-pg-instance",
+					Description:  "some description",
+					Statement:    "SELECT 1;",
+					AuthRequired: []string{},
+				},
+				QueueTimeout: 30 * time.Second,
+			},
+			Aliases: []string{"old_migrate"},
+		},
+		"refresh": tools.SerializeConfig{
+			ToolConfig: postgressql.Config{
+				Name:         "refresh",
+				Kind:         "postgres-sql",
+				Source:       "my-pg-instance",
+				Description:  "some description",
+				Statement:    "SELECT 1;",
+				AuthRequired: []string{},
+			},
+		},
+		"other": postgressql.Config{
+			Name:         "other",
+			Kind:         "postgres-sql",
+			Source:       "my-pg-instance",
+			Description:  "some description",
+			Statement:    "SELECT 1;",
+			AuthRequired: []string{},
+		},
+	}
+	toolsFile, err := parseToolsFile(ctx, testutils.FormatYaml(in))
+	if err != nil {
+		t.Fatalf("failed to parse input: %v", err)
+	}
+	if diff := cmp.Diff(want, toolsFile.Tools); diff != "" {
+		t.Fatalf("incorrect tools parse: diff %v", diff)
+	}
+}
+
+func TestFailParseToolFileWithSerialize(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		description string
+		in          string
+		errString   string
+	}{
+		{
+			description: "queueTimeout without serialize",
+			in: `
+			tools:
+				migrate:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: SELECT 1;
+					queueTimeout: 30s
+			`,
+			errString: "`queueTimeout` requires `serialize: true` for tool \"migrate\"",
+		},
+		{
+			description: "invalid queueTimeout",
+			in: `
+			tools:
+				migrate:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: SELECT 1;
+					serialize: true
+					queueTimeout: soon
+			`,
+			errString: `invalid 'queueTimeout' field for tool "migrate"`,
+		},
+		{
+			description: "invalid serialize type",
+			in: `
+			tools:
+				migrate:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: SELECT 1;
+					serialize: yes please
+			`,
+			errString: `invalid 'serialize' field for tool "migrate" (must be a boolean)`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.description, func(t *testing.T) {
+			_, err := parseToolsFile(ctx, testutils.FormatYaml(tc.in))
+			if err == nil {
+				t.Fatalf("expected parsing to fail")
+			}
+			if !strings.Contains(err.Error(), tc.errString) {
+				t.Fatalf("incorrect error string: got %s, want %s", err, tc.errString)
+			}
+		})
+	}
+}
+
+func TestParseToolFileWithResultTransform(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		customers:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: SELECT cust_nm, first_nm, last_nm FROM customers;
+			serialize: true
+			resultTransform:
+				rename:
+					cust_nm: customer_name
+				drop:
+					- last_nm
+				compute:
+					full_name: first_nm || ' ' || last_nm
+	`
+	toolsFile, err := parseToolsFile(ctx, testutils.FormatYaml(in))
+	if err != nil {
+		t.Fatalf("failed to parse input: %v", err)
+	}
+	serializeCfg, ok := toolsFile.Tools["customers"].(tools.SerializeConfig)
+	if !ok {
+		t.Fatalf("expected a serialized tool config, got %T", toolsFile.Tools["customers"])
+	}
+	transformCfg, ok := serializeCfg.ToolConfig.(tools.TransformConfig)
+	if !ok {
+		t.Fatalf("expected a transformed tool config, got %T", serializeCfg.ToolConfig)
+	}
+	want := postgressql.Config{
+		Name:         "customers",
+		Kind:         "postgres-sql",
+		Source:       "my-pg-instance",
+		Description:  "some description",
+		Statement:    "SELECT cust_nm, first_nm, last_nm FROM customers;",
+		AuthRequired: []string{},
+	}
+	if diff := cmp.Diff(want, transformCfg.ToolConfig); diff != "" {
+		t.Fatalf("incorrect tools parse: diff %v", diff)
+	}
+	got := transformCfg.Transform.Apply(map[string]any{"cust_nm": "ACME", "first_nm": "Ada", "last_nm": "Lovelace"})
+	wantRow := map[string]any{"customer_name": "ACME", "first_nm": "Ada", "full_name": "Ada Lovelace"}
+	if diff := cmp.Diff(wantRow, got); diff != "" {
+		t.Fatalf("incorrect transform: diff %v", diff)
+	}
+}
+
+func TestFailParseToolFileWithResultTransform(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		description string
+		in          string
+		errString   string
+	}{
+		{
+			description: "invalid expression",
+			in: `
+			tools:
+				customers:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: SELECT 1;
+					resultTransform:
+						compute:
+							total: price * (quantity
+			`,
+			errString: `invalid 'resultTransform' field for tool "customers": invalid expression for computed column "total"`,
+		},
+		{
+			description: "unknown field",
+			in: `
+			tools:
+				customers:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: SELECT 1;
+					resultTransform:
+						renames:
+							cust_nm: customer_name
+			`,
+			errString: `invalid 'resultTransform' field for tool "customers"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.description, func(t *testing.T) {
+			_, err := parseToolsFile(ctx, testutils.FormatYaml(tc.in))
+			if err == nil {
+				t.Fatalf("expected parsing to fail")
+			}
+			if !strings.Contains(err.Error(), tc.errString) {
+				t.Fatalf("incorrect error string: got %s, want %s", err, tc.errString)
+			}
+		})
+	}
+}
+
+func TestParseToolFileWithSpillToFile(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		export_sql:
+			kind: postgres-execute-sql
+			source: my-pg-instance
+			description: some description
+			spillToFile:
+				maxRows: 1000
+				format: ndjson
+	`
+	toolsFile, err := parseToolsFile(ctx, testutils.FormatYaml(in))
+	if err != nil {
+		t.Fatalf("failed to parse input: %v", err)
+	}
+	spillCfg, ok := toolsFile.Tools["export_sql"].(tools.SpillConfig)
+	if !ok {
+		t.Fatalf("expected a spilled tool config, got %T", toolsFile.Tools["export_sql"])
+	}
+	want := postgresexecutesql.Config{
+		Name:         "export_sql",
+		Kind:         "postgres-execute-sql",
+		Source:       "my-pg-instance",
+		Description:  "some description",
+		AuthRequired: []string{},
+	}
+	if diff := cmp.Diff(want, spillCfg.ToolConfig); diff != "" {
+		t.Fatalf("incorrect tools parse: diff %v", diff)
+	}
+	if spillCfg.Spill == nil {
+		t.Fatalf("expected a spill, got nil")
+	}
+}
+
+func TestFailParseToolFileWithSpillToFile(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		description string
+		in          string
+		errString   string
+	}{
+		{
+			description: "not an execute-sql tool",
+			in: `
+			tools:
+				customers:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: SELECT 1;
+					spillToFile:
+						maxRows: 10
+			`,
+			errString: "`spillToFile` is only supported by execute-sql tools, but tool \"customers\" is of kind \"postgres-sql\"",
+		},
+		{
+			description: "invalid format",
+			in: `
+			tools:
+				export_sql:
+					kind: postgres-execute-sql
+					source: my-pg-instance
+					description: some description
+					spillToFile:
+						format: xlsx
+			`,
+			errString: `invalid 'spillToFile' field for tool "export_sql": format must be "csv" or "ndjson", got "xlsx"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.description, func(t *testing.T) {
+			_, err := parseToolsFile(ctx, testutils.FormatYaml(tc.in))
+			if err == nil {
+				t.Fatalf("expected parsing to fail")
+			}
+			if !strings.Contains(err.Error(), tc.errString) {
+				t.Fatalf("incorrect error string: got %s, want %s", err, tc.errString)
+			}
+		})
+	}
+}
+
+func TestParseToolFileWithOutputSchema(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"count": map[string]any{"type": "integer"},
+		},
+		"required": []any{"count"},
+	}
+	for desc, in := range map[string]string{
+		"mapping": `
+		tools:
+			count_flights:
+				kind: postgres-sql
+				source: my-pg-instance
+				description: some description
+				statement: SELECT count(*) FROM flights;
+				outputSchema:
+					type: object
+					properties:
+						count:
+							type: integer
+					required: [count]
+		`,
+		"json string": `
+		tools:
+			count_flights:
+				kind: postgres-sql
+				source: my-pg-instance
+				description: some description
+				statement: SELECT count(*) FROM flights;
+				outputSchema: '{"type": "object", "properties": {"count": {"type": "integer"}}, "required": ["count"]}'
+		`,
+	} {
+		t.Run(desc, func(t *testing.T) {
+			toolsFile, err := parseToolsFile(ctx, testutils.FormatYaml(in))
+			if err != nil {
+				t.Fatalf("failed to parse input: %v", err)
+			}
+			schemaCfg, ok := toolsFile.Tools["count_flights"].(tools.OutputSchemaConfig)
+			if !ok {
+				t.Fatalf("expected an output schema tool config, got %T", toolsFile.Tools["count_flights"])
+			}
+			if diff := cmp.Diff(want, schemaCfg.Schema); diff != "" {
+				t.Fatalf("incorrect output schema: diff %v", diff)
+			}
+			if _, ok := schemaCfg.ToolConfig.(postgressql.Config); !ok {
+				t.Fatalf("expected a postgres-sql tool config, got %T", schemaCfg.ToolConfig)
+			}
+		})
+	}
+}
+
+func TestFailParseToolFileWithOutputSchema(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		description string
+		schema      string
+		errString   string
+	}{
+		{
+			description: "not an object schema",
+			schema:      "{type: array, items: {type: object}}",
+			errString:   `invalid 'outputSchema' field for tool "count_flights": the output schema must be of type "object"`,
+		},
+		{
+			description: "invalid type",
+			schema:      "{type: object, properties: {count: {type: int}}}",
+			errString:   `invalid 'outputSchema' field for tool "count_flights": schema.properties.count.type has an invalid type int`,
+		},
+		{
+			description: "invalid required",
+			schema:      "{type: object, required: count}",
+			errString:   `invalid 'outputSchema' field for tool "count_flights": schema.required must be a list of strings`,
+		},
+		{
+			description: "not a schema",
+			schema:      "[count]",
+			errString:   `invalid 'outputSchema' field for tool "count_flights" (must be a JSON Schema object)`,
+		},
+		{
+			description: "invalid JSON",
+			schema:      `'{"type": "object"'`,
+			errString:   `invalid 'outputSchema' field for tool "count_flights": unexpected end of JSON input`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.description, func(t *testing.T) {
+			in := fmt.Sprintf(`
+			tools:
+				count_flights:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: SELECT count(*) FROM flights;
+					outputSchema: %s
+			`, tc.schema)
+			_, err := parseToolsFile(ctx, testutils.FormatYaml(in))
+			if err == nil {
+				t.Fatalf("expected parsing to fail")
+			}
+			if !strings.Contains(err.Error(), tc.errString) {
+				t.Fatalf("incorrect error string: got %s, want %s", err, tc.errString)
+			}
+		})
+	}
+}
+
+func TestParseToolFileWithSecretReferences(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	fake := testutils.NewFakeSecretManager(t, map[string]string{
+		"projects/p/secrets/db-pass/versions/latest":   "s3cret",
+		"projects/p/secrets/client-id/versions/latest": "my-client-id",
+	})
+	fake.Deny("projects/p/secrets/forbidden/versions/latest")
+	ctx = secrets.WithResolver(ctx, secrets.NewResolver(fake.Client(), fake.URL()))
+
+	dir := t.TempDir()
+	sourcesFile := `
+	sources:
+		my-pg-instance:
+			kind: cloud-sql-postgres
+			project: my-project
+			region: my-region
+			instance: my-instance
+			database: my_db
+			user: my_user
+			password: sm://projects/p/secrets/db-pass/versions/latest
+	`
+	authFile := `
+	authServices:
+		my-google-service:
+			kind: google
+			clientId: sm://projects/p/secrets/client-id
+	`
+	var paths []string
+	for i, in := range []string{sourcesFile, authFile} {
+		path := filepath.Join(dir, fmt.Sprintf("tools%d.yaml", i))
+		if err := os.WriteFile(path, testutils.FormatYaml(in), 0o644); err != nil {
+			t.Fatalf("unable to write tools file: %s", err)
+		}
+		paths = append(paths, path)
+	}
+
+	toolsFile, err := loadAndMergeToolsFiles(ctx, paths)
+	if err != nil {
+		t.Fatalf("failed to load tools files: %v", err)
+	}
+	wantSources := server.SourceConfigs{
+		"my-pg-instance": cloudsqlpgsrc.Config{
+			Name:     "my-pg-instance",
+			Kind:     cloudsqlpgsrc.SourceKind,
+			Project:  "my-project",
+			Region:   "my-region",
+			Instance: "my-instance",
+			IPType:   "public",
+			Database: "my_db",
+			User:     "my_user",
+			Password: "s3cret",
+		},
+	}
+	if diff := cmp.Diff(wantSources, toolsFile.Sources); diff != "" {
+		t.Fatalf("incorrect sources parse: diff %v", diff)
+	}
+	wantAuthServices := server.AuthServiceConfigs{
+		"my-google-service": google.Config{
+			Name:     "my-google-service",
+			Kind:     google.AuthServiceKind,
+			ClientID: "my-client-id",
+		},
+	}
+	if diff := cmp.Diff(wantAuthServices, toolsFile.AuthServices); diff != "" {
+		t.Fatalf("incorrect authServices parse: diff %v", diff)
+	}
+	if got := fake.Requests(); got != 2 {
+		t.Fatalf("unexpected number of Secret Manager requests: got %d, want 2", got)
+	}
+
+	tcs := []struct {
+		description string
+		in          string
+		errString   string
+	}{
+		{
+			description: "permission denied",
+			in: `
+			sources:
+				my-pg-instance:
+					kind: cloud-sql-postgres
+					project: my-project
+					region: my-region
+					instance: my-instance
+					database: my_db
+					user: my_user
+					password: sm://projects/p/secrets/forbidden
+			`,
+			errString: `unable to resolve secrets of source "my-pg-instance": permission denied to access secret "projects/p/secrets/forbidden/versions/latest"`,
+		},
+		{
+			description: "malformed reference",
+			in: `
+			authServices:
+				my-google-service:
+					kind: google
+					clientId: sm://secrets/client-id
+			`,
+			errString: `unable to resolve secrets of "my-google-service": invalid secret reference "sm://secrets/client-id"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.description, func(t *testing.T) {
+			_, err := parseToolsFile(ctx, testutils.FormatYaml(tc.in))
+			if err == nil {
+				t.Fatalf("expected parsing to fail")
+			}
+			if !strings.Contains(err.Error(), tc.errString) {
+				t.Fatalf("incorrect error string: got %s, want %s", err, tc.errString)
+			}
+		})
+	}
+}
+
+func TestParseToolFileWithSchedule(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	schedule:
+		timezone: America/New_York
+		windows:
+			- days: Sat,Sun
+	tools:
+		heavy_report:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: SELECT 1;
+			schedule:
+				timezone: Europe/Paris
+				windows:
+					- days: Mon-Fri
+						hours: "18:00-08:00"
+					- cron: "* * * * 6,0"
+				overrideAuthServices:
+					- dba-auth
+	`
+	toolsFile, err := parseToolsFile(ctx, testutils.FormatYaml(in))
+	if err != nil {
+		t.Fatalf("failed to parse input: %v", err)
+	}
+	if toolsFile.Schedule == nil {
+		t.Fatalf("expected a default schedule")
+	}
+	wantDefault := tools.ScheduleSpec{Timezone: "America/New_York", Windows: []tools.ScheduleWindow{{Days: "Sat,Sun"}}}
+	if diff := cmp.Diff(wantDefault, toolsFile.Schedule.Spec()); diff != "" {
+		t.Fatalf("incorrect default schedule: diff %v", diff)
+	}
+
+	scheduleCfg, ok := toolsFile.Tools["heavy_report"].(tools.ScheduleConfig)
+	if !ok {
+		t.Fatalf("expected a scheduled tool config, got %T", toolsFile.Tools["heavy_report"])
+	}
+	wantSpec := tools.ScheduleSpec{
+		Timezone: "Europe/Paris",
+		Windows: []tools.ScheduleWindow{
+			{Days: "Mon-Fri", Hours: "18:00-08:00"},
+			{Cron: "* * * * 6,0"},
+		},
+		OverrideAuthServices: []string{"dba-auth"},
+	}
+	if diff := cmp.Diff(wantSpec, scheduleCfg.Schedule.Spec()); diff != "" {
+		t.Fatalf("incorrect tool schedule: diff %v", diff)
+	}
+	want := postgressql.Config{
+		Name:         "heavy_report",
+		Kind:         "postgres-sql",
+		Source:       "my-pg-instance",
+		Description:  "some description",
+		Statement:    "SELECT 1;",
+		AuthRequired: []string{},
+	}
+	if diff := cmp.Diff(want, scheduleCfg.ToolConfig); diff != "" {
+		t.Fatalf("incorrect tools parse: diff %v", diff)
+	}
+}
+
+func TestFailParseToolFileWithSchedule(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		description string
+		in          string
+		errString   string
+	}{
+		{
+			description: "invalid tool schedule",
+			in: `
+			tools:
+				heavy_report:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: SELECT 1;
+					schedule:
+						windows:
+							- hours: 9-17
+			`,
+			errString: `invalid 'schedule' field for tool "heavy_report": window 0: invalid time "9"`,
+		},
+		{
+			description: "unknown field",
+			in: `
+			tools:
+				heavy_report:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: SELECT 1;
+					schedule:
+						timezone: UTC
+						window:
+							- days: Sat
+			`,
+			errString: `invalid 'schedule' field for tool "heavy_report"`,
+		},
+		{
+			description: "invalid default schedule",
+			in: `
+			schedule:
+				timezone: Nowhere/Special
+				windows:
+					- days: Sat
+			`,
+			errString: `invalid 'schedule' field: invalid timezone`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.description, func(t *testing.T) {
+			_, err := parseToolsFile(ctx, testutils.FormatYaml(tc.in))
+			if err == nil {
+				t.Fatalf("expected parsing to fail")
+			}
+			if !strings.Contains(err.Error(), tc.errString) {
+				t.Fatalf("incorrect error string: got %s, want %s", err, tc.errString)
+			}
+		})
+	}
+}
+
+func TestMergeToolsFilesWithSchedule(t *testing.T) {
+	schedule, err := tools.NewSchedule(tools.ScheduleSpec{Windows: []tools.ScheduleWindow{{Days: "Sat,Sun"}}})
+	if err != nil {
+		t.Fatalf("unable to create schedule: %s", err)
+	}
+
+	merged, err := mergeToolsFiles(ToolsFile{}, ToolsFile{Schedule: schedule})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if merged.Schedule != schedule {
+		t.Fatalf("expected the default schedule to be kept")
+	}
+
+	_, err = mergeToolsFiles(ToolsFile{Schedule: schedule}, ToolsFile{Schedule: schedule})
+	if err == nil || !strings.Contains(err.Error(), "schedule (file #2)") {
+		t.Fatalf("expected a schedule conflict, got %v", err)
+	}
+}
+
+func TestParseToolFileWithResultTimezone(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	resultTimezone: America/New_York
+	tools:
+		list_flights:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: SELECT * FROM flights;
+			resultTimezone: Europe/Berlin
+	`
+	toolsFile, err := parseToolsFile(ctx, testutils.FormatYaml(in))
+	if err != nil {
+		t.Fatalf("failed to parse input: %v", err)
+	}
+	if toolsFile.ResultTimezone == nil || toolsFile.ResultTimezone.String() != "America/New_York" {
+		t.Fatalf("unexpected default result timezone: %v", toolsFile.ResultTimezone)
+	}
+	tzCfg, ok := toolsFile.Tools["list_flights"].(tools.ResultTimezoneConfig)
+	if !ok {
+		t.Fatalf("expected a result timezone config, got %T", toolsFile.Tools["list_flights"])
+	}
+	if tzCfg.Timezone.String() != "Europe/Berlin" {
+		t.Fatalf("unexpected result timezone: %s", tzCfg.Timezone)
+	}
+	want := postgressql.Config{
+		Name:         "list_flights",
+		Kind:         "postgres-sql",
+		Source:       "my-pg-instance",
+		Description:  "some description",
+		Statement:    "SELECT * FROM flights;",
+		AuthRequired: []string{},
+	}
+	if diff := cmp.Diff(want, tzCfg.ToolConfig); diff != "" {
+		t.Fatalf("incorrect tools parse: diff %v", diff)
+	}
+}
+
+func TestFailParseToolFileWithResultTimezone(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		description string
+		in          string
+		errString   string
+	}{
+		{
+			description: "invalid tool timezone",
+			in: `
+			tools:
+				list_flights:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: SELECT * FROM flights;
+					resultTimezone: Nowhere/Special
+			`,
+			errString: `invalid 'resultTimezone' field for tool "list_flights": invalid timezone "Nowhere/Special"`,
+		},
+		{
+			description: "tool timezone not a string",
+			in: `
+			tools:
+				list_flights:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: SELECT * FROM flights;
+					resultTimezone:
+						name: UTC
+			`,
+			errString: `invalid 'resultTimezone' field for tool "list_flights"`,
+		},
+		{
+			description: "invalid default timezone",
+			in: `
+			resultTimezone: Nowhere/Special
+			`,
+			errString: `invalid 'resultTimezone' field: invalid timezone "Nowhere/Special"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.description, func(t *testing.T) {
+			_, err := parseToolsFile(ctx, testutils.FormatYaml(tc.in))
+			if err == nil {
+				t.Fatalf("expected parsing to fail")
+			}
+			if !strings.Contains(err.Error(), tc.errString) {
+				t.Fatalf("incorrect error string: got %s, want %s", err, tc.errString)
+			}
+		})
+	}
+}
+
+func TestMergeToolsFilesWithResultTimezone(t *testing.T) {
+	tz, err := tools.NewResultTimezone("UTC")
+	if err != nil {
+		t.Fatalf("unable to create timezone: %s", err)
+	}
+
+	merged, err := mergeToolsFiles(ToolsFile{}, ToolsFile{ResultTimezone: tz})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if merged.ResultTimezone != tz {
+		t.Fatalf("expected the default result timezone to be kept")
+	}
+
+	_, err = mergeToolsFiles(ToolsFile{ResultTimezone: tz}, ToolsFile{ResultTimezone: tz})
+	if err == nil || !strings.Contains(err.Error(), "resultTimezone (file #2)") {
+		t.Fatalf("expected a result timezone conflict, got %v", err)
+	}
+}
+
+func TestParseToolFileWithDefaultAuthRequired(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	defaultAuthRequired:
+		- my-corp-auth
+	tools:
+		list_flights:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: SELECT * FROM flights;
+			authRequired: []
+			allowUnauthenticated: true
+	toolsets:
+		production:
+			tools:
+				- list_flights
+			authRequired:
+				- my-google-auth
+	`
+	toolsFile, err := parseToolsFile(ctx, testutils.FormatYaml(in))
+	if err != nil {
+		t.Fatalf("failed to parse input: %v", err)
+	}
+	if diff := cmp.Diff([]string{"my-corp-auth"}, toolsFile.DefaultAuthRequired); diff != "" {
+		t.Fatalf("incorrect default authRequired: diff %v", diff)
+	}
+	wantTools := server.ToolConfigs{
+		"list_flights": tools.AllowUnauthenticatedConfig{
+			ToolConfig: postgressql.Config{
+				Name:         "list_flights",
+				Kind:         "postgres-sql",
+				Source:       "my-pg-instance",
+				Description:  "some description",
+				Statement:    "SELECT * FROM flights;",
+				AuthRequired: []string{},
+			},
+			AllowUnauthenticated: true,
+		},
+	}
+	if diff := cmp.Diff(wantTools, toolsFile.Tools); diff != "" {
+		t.Fatalf("incorrect tools parse: diff %v", diff)
+	}
+	wantToolsets := server.ToolsetConfigs{
+		"production": tools.ToolsetConfig{
+			Name:         "production",
+			ToolNames:    []string{"list_flights"},
+			AuthRequired: []string{"my-google-auth"},
+		},
+	}
+	if diff := cmp.Diff(wantToolsets, toolsFile.Toolsets); diff != "" {
+		t.Fatalf("incorrect toolsets parse: diff %v", diff)
+	}
+}
+
+func TestMergeToolsFilesWithDefaultAuthRequired(t *testing.T) {
+	authRequired := []string{"my-google-auth"}
+
+	merged, err := mergeToolsFiles(ToolsFile{}, ToolsFile{DefaultAuthRequired: authRequired})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(authRequired, merged.DefaultAuthRequired); diff != "" {
+		t.Fatalf("incorrect default authRequired: diff %v", diff)
+	}
+
+	_, err = mergeToolsFiles(ToolsFile{DefaultAuthRequired: authRequired}, ToolsFile{DefaultAuthRequired: authRequired})
+	if err == nil || !strings.Contains(err.Error(), "defaultAuthRequired (file #2)") {
+		t.Fatalf("expected a default authRequired conflict, got %v", err)
+	}
+}
+
+
+func TestParseToolFileWithPreInvokeWebhook(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		drop_table:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: DROP TABLE flights;
+			serialize: true
+			preInvokeWebhook:
+				url: https://approvals.example.com/toolbox
+				secret: s3cr3t
+				timeout: 2s
+				failOpen: true
+			schedule:
+				windows:
+					- days: Mon-Fri
+	`
+	toolsFile, err := parseToolsFile(ctx, testutils.FormatYaml(in))
+	if err != nil {
+		t.Fatalf("failed to parse input: %v", err)
+	}
+	// invocations are approved after their schedule is checked, and before
+	// they wait for their turn
+	scheduleCfg, ok := toolsFile.Tools["drop_table"].(tools.ScheduleConfig)
+	if !ok {
+		t.Fatalf("expected a scheduled tool config, got %T", toolsFile.Tools["drop_table"])
+	}
+	webhookCfg, ok := scheduleCfg.ToolConfig.(tools.PreInvokeWebhookConfig)
+	if !ok {
+		t.Fatalf("expected a pre-invoke webhook config, got %T", scheduleCfg.ToolConfig)
+	}
+	if webhookCfg.Webhook == nil {
+		t.Fatalf("expected a webhook")
+	}
+	if _, ok := webhookCfg.ToolConfig.(tools.SerializeConfig); !ok {
+		t.Fatalf("expected a serialized tool config, got %T", webhookCfg.ToolConfig)
+	}
+}
+
+func TestFailParseToolFileWithPreInvokeWebhook(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		description string
+		webhook     string
+		errString   string
+	}{
+		{
+			description: "missing secret",
+			webhook: `
+						url: https://approvals.example.com/toolbox`,
+			errString: `invalid 'preInvokeWebhook' field for tool "drop_table"`,
+		},
+		{
+			description: "timeout too long",
+			webhook: `
+						url: https://approvals.example.com/toolbox
+						secret: s3cr3t
+						timeout: 5m`,
+			errString: `invalid 'preInvokeWebhook' field for tool "drop_table": timeout must be positive and at most 30s`,
+		},
+		{
+			description: "unknown field",
+			webhook: `
+						url: https://approvals.example.com/toolbox
+						secret: s3cr3t
+						failClosed: true`,
+			errString: `invalid 'preInvokeWebhook' field for tool "drop_table"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.description, func(t *testing.T) {
+			in := `
+			tools:
+				drop_table:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: DROP TABLE flights;
+					preInvokeWebhook:` + tc.webhook
+			_, err := parseToolsFile(ctx, testutils.FormatYaml(in))
+			if err == nil {
+				t.Fatalf("expected parsing to fail")
+			}
+			if !strings.Contains(err.Error(), tc.errString) {
+				t.Fatalf("incorrect error string: got %s, want %s", err, tc.errString)
+			}
+		})
+	}
+}
+
+func TestParseToolFileWithUsageMetadata(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	includeUsageMetadata: true
+	tools:
+		quiet_tool:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: SELECT 1;
+			includeUsageMetadata: false
+	`
+	toolsFile, err := parseToolsFile(ctx, testutils.FormatYaml(in))
+	if err != nil {
+		t.Fatalf("failed to parse input: %v", err)
+	}
+	if !toolsFile.IncludeUsageMetadata {
+		t.Fatalf("expected usage metadata to be included by default")
+	}
+	usageCfg, ok := toolsFile.Tools["quiet_tool"].(tools.UsageMetadataConfig)
+	if !ok {
+		t.Fatalf("expected a usage metadata tool config, got %T", toolsFile.Tools["quiet_tool"])
+	}
+	if usageCfg.Include {
+		t.Fatalf("expected the tool to opt out of usage metadata")
+	}
+
+	in = `
+	tools:
+		quiet_tool:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: SELECT 1;
+			includeUsageMetadata: sometimes
+	`
+	_, err = parseToolsFile(ctx, testutils.FormatYaml(in))
+	if err == nil || !strings.Contains(err.Error(), `invalid 'includeUsageMetadata' field for tool "quiet_tool" (must be a boolean)`) {
+		t.Fatalf("expected an invalid field error, got %v", err)
+	}
+
+	merged, err := mergeToolsFiles(ToolsFile{}, ToolsFile{IncludeUsageMetadata: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !merged.IncludeUsageMetadata {
+		t.Fatalf("expected usage metadata to be included when any file includes it")
+	}
+}
+
+func TestLoadToolsFileWithStatementFile(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "sql"), 0o755); err != nil {
+		t.Fatalf("unable to create directory: %s", err)
+	}
+	statement := "SELECT *\nFROM flights\nWHERE id = $1;\n"
+	statementPath := filepath.Join(dir, "sql", "flights.sql")
+	if err := os.WriteFile(statementPath, []byte(statement), 0o644); err != nil {
+		t.Fatalf("unable to write statement file: %s", err)
+	}
+	absPath := filepath.Join(dir, "abs.sql")
+	if err := os.WriteFile(absPath, []byte("SELECT 1;"), 0o644); err != nil {
+		t.Fatalf("unable to write statement file: %s", err)
+	}
+
+	in := fmt.Sprintf(`
+	tools:
+		relative_tool:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statementFile: sql/flights.sql
+		absolute_tool:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statementFile: %s
+	`, absPath)
+	toolsFilePath := filepath.Join(dir, "tools.yaml")
+	if err := os.WriteFile(toolsFilePath, testutils.FormatYaml(in), 0o644); err != nil {
+		t.Fatalf("unable to write tools file: %s", err)
+	}
+
+	toolsFile, err := loadToolsFile(ctx, toolsFilePath)
+	if err != nil {
+		t.Fatalf("failed to load tools file: %v", err)
+	}
+	want := server.ToolConfigs{
+		"relative_tool": postgressql.Config{
+			Name:         "relative_tool",
+			Kind:         "postgres-sql",
+			Source:       "my-pg-instance",
+			Description:  "some description",
+			Statement:    statement,
+			AuthRequired: []string{},
+		},
+		"absolute_tool": postgressql.Config{
+			Name:         "absolute_tool",
+			Kind:         "postgres-sql",
+			Source:       "my-pg-instance",
+			Description:  "some description",
+			Statement:    "SELECT 1;",
+			AuthRequired: []string{},
+		},
+	}
+	if diff := cmp.Diff(want, toolsFile.Tools); diff != "" {
+		t.Fatalf("incorrect tools parse: diff %v", diff)
+	}
+	wantReferenced := []string{absPath, statementPath}
+	gotReferenced := slices.Sorted(slices.Values(toolsFile.ReferencedFiles))
+	if diff := cmp.Diff(wantReferenced, gotReferenced); diff != "" {
+		t.Fatalf("incorrect referenced files: diff %v", diff)
+	}
+}
+
+func TestFailLoadToolsFileWithStatementFile(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "query.sql"), []byte("SELECT 1;"), 0o644); err != nil {
+		t.Fatalf("unable to write statement file: %s", err)
+	}
+
+	tcs := []struct {
+		description string
+		in          string
+		errString   string
+	}{
+		{
+			description: "missing statement file",
+			in: `
+			tools:
+				example_tool:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statementFile: missing.sql
+			`,
+			errString: fmt.Sprintf(`unable to read statementFile %q for tool "example_tool"`, filepath.Join(dir, "missing.sql")),
+		},
+		{
+			description: "statement and statementFile both set",
 			in: `
-			sources:
-				my-pg-instance:
-					kind: cloud-sql-postgres
-					project: my-project
-					region: my-region
-					instance: my-instance
-					database: my_db
-					user: my_user
-					password: my_pass
 			tools:
 				example_tool:
 					kind: postgres-sql
 					source: my-pg-instance
 					description: some description
-					statement: |
-						SELECT * FROM SQL_STATEMENT;
-					parameters:
-						- name: country
-							type: string
-							description: some description
-			toolsets:
-				example_toolset:
-					- example_tool
+					statement: SELECT 1;
+					statementFile: query.sql
 			`,
-			wantToolsFile: ToolsFile{
-				Sources: server.SourceConfigs{
-					"my-pg-instance": cloudsqlpgsrc.Config{
-						Name:     "my-pg-instance",
-						Kind:     cloudsqlpgsrc.SourceKind,
-						Project:  "my-project",
-						Region:   "my-region",
-						Instance: "my-instance",
-						IPType:   "public",
-						Database: "my_db",
-						User:     "my_user",
-						Password: "my_pass",
-					},
-				},
-				Tools: server.ToolConfigs{
-					"example_tool": postgressql.Config{
-						Name:        "example_tool",
-						Kind:        "postgres-sql",
-						Source:      "my-pg-instance",
-						Description: "some description",
-						Statement:   "SELECT * FROM SQL_STATEMENT;\n",
-						Parameters: []tools.Parameter{
-							tools.NewStringParameter("country", "some description"),
-						},
-						AuthRequired: []string{},
-					},
-				},
-				Toolsets: server.ToolsetConfigs{
-					"example_toolset": tools.ToolsetConfig{
-						Name:      "example_toolset",
-						ToolNames: []string{"example_tool"},
-					},
-				},
-			},
+			errString: "`statement` and `statementFile` are mutually exclusive for tool \"example_tool\"",
+		},
+		{
+			description: "neither statement nor statementFile set",
+			in: `
+			tools:
+				example_tool:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+			`,
+			errString: "Statement",
+		},
+		{
+			description: "empty statementFile",
+			in: `
+			tools:
+				example_tool:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statementFile: ""
+			`,
+			errString: `invalid 'statementFile' field for tool "example_tool" (must be a non-empty string)`,
 		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.description, func(t *testing.T) {
-			toolsFile, err := parseToolsFile(ctx, testutils.FormatYaml(tc.in))
-			if err != nil {
-				t.Fatalf("failed to parse input: %v", err)
-			}
-			if diff := cmp.Diff(tc.wantToolsFile.Sources, toolsFile.Sources); diff != "" {
-				t.Fatalf("incorrect sources parse: diff %v", diff)
+			toolsFilePath := filepath.Join(dir, "tools.yaml")
+			if err := os.WriteFile(toolsFilePath, testutils.FormatYaml(tc.in), 0o644); err != nil {
+				t.Fatalf("unable to write tools file: %s", err)
 			}
-			if diff := cmp.Diff(tc.wantToolsFile.AuthServices, toolsFile.AuthServices); diff != "" {
-				t.Fatalf("incorrect authServices parse: diff %v", diff)
-			}
-			if diff := cmp.Diff(tc.wantToolsFile.Tools, toolsFile.Tools); diff != "" {
-				t.Fatalf("incorrect tools parse: diff %v", diff)
+			_, err := loadToolsFile(ctx, toolsFilePath)
+			if err == nil {
+				t.Fatalf("expected loading to fail")
 			}
-			if diff := cmp.Diff(tc.wantToolsFile.Toolsets, toolsFile.Toolsets); diff != "" {
-				t.Fatalf("incorrect tools parse: diff %v", diff)
+			if !strings.Contains(err.Error(), tc.errString) {
+				t.Fatalf("incorrect error string: got %s, want %s", err, tc.errString)
 			}
 		})
 	}
-
 }
 
 func TestParseToolFileWithAuth(t *testing.T) {
@@ -580,6 +2196,7 @@ func TestParseToolFileWithAuth(t *testing.T) {
 				other-google-service:
 					kind: google
 					clientId: other-client-id
+					disableClaimsCache: true
 
 			tools:
 				example_tool:
@@ -632,9 +2249,10 @@ func TestParseToolFileWithAuth(t *testing.T) {
 						ClientID: "my-client-id",
 					},
 					"other-google-service": google.Config{
-						Name:     "other-google-service",
-						Kind:     google.AuthServiceKind,
-						ClientID: "other-client-id",
+						Name:               "other-google-service",
+						Kind:               google.AuthServiceKind,
+						ClientID:           "other-client-id",
+						DisableClaimsCache: true,
 					},
 				},
 				Tools: server.ToolConfigs{
@@ -1199,7 +2817,7 @@ func TestSingleEdit(t *testing.T) {
 	watchedFiles := map[string]bool{cleanFileToWatch: true}
 	watchDirs := map[string]bool{watchDir: true}
 
-	go watchChanges(ctx, watchDirs, watchedFiles, mockServer)
+	go watchChanges(ctx, watchDirs, watchedFiles, nil, mockServer)
 
 	// escape backslash so regex doesn't fail on windows filepaths
 	regexEscapedPathFile := strings.ReplaceAll(cleanFileToWatch, `\`, `\\\\*\\`)
@@ -1227,6 +2845,75 @@ func TestSingleEdit(t *testing.T) {
 	}
 }
 
+func TestReferencedFileEdit(t *testing.T) {
+	ctx, cancelCtx := context.WithTimeout(context.Background(), time.Minute)
+	defer cancelCtx()
+
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	defer pr.Close()
+
+	toolsDir := t.TempDir()
+	sqlDir := t.TempDir()
+	toolsFile := filepath.Join(toolsDir, "tools.yaml")
+	if err := os.WriteFile(toolsFile, []byte("initial content"), 0o644); err != nil {
+		t.Fatalf("error writing tools file %s", err)
+	}
+	statementFile := filepath.Join(sqlDir, "query.sql")
+	if err := os.WriteFile(statementFile, []byte("SELECT 1;"), 0o644); err != nil {
+		t.Fatalf("error writing statement file %s", err)
+	}
+
+	logger, err := log.NewStdLogger(pw, pw, "DEBUG")
+	if err != nil {
+		t.Fatalf("failed to setup logger %s", err)
+	}
+	ctx = util.WithLogger(ctx, logger)
+
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(versionString)
+	if err != nil {
+		t.Fatalf("failed to setup instrumentation %s", err)
+	}
+	ctx = util.WithInstrumentation(ctx, instrumentation)
+
+	mockServer := &server.Server{}
+
+	watchedFiles := map[string]bool{filepath.Clean(toolsFile): true}
+	watchDirs := map[string]bool{filepath.Clean(toolsDir): true}
+
+	go watchChanges(ctx, watchDirs, watchedFiles, []string{statementFile}, mockServer)
+
+	// escape backslash so regex doesn't fail on windows filepaths
+	regexEscapedPathFile := strings.ReplaceAll(filepath.Clean(statementFile), `\`, `\\\\*\\`)
+	regexEscapedPathFile = path.Clean(regexEscapedPathFile)
+
+	regexEscapedPathDir := strings.ReplaceAll(filepath.Clean(sqlDir), `\`, `\\\\*\\`)
+	regexEscapedPathDir = path.Clean(regexEscapedPathDir)
+
+	begunWatchingDir := regexp.MustCompile(fmt.Sprintf(`DEBUG "Added directory %s to watcher."`, regexEscapedPathDir))
+	_, err = testutils.WaitForString(ctx, begunWatchingDir, pr)
+	if err != nil {
+		t.Fatalf("timeout or error waiting for watcher to start: %s", err)
+	}
+
+	err = os.WriteFile(statementFile, []byte("SELECT 2;"), 0o644)
+	if err != nil {
+		t.Fatalf("error writing to file: %v", err)
+	}
+
+	detectedFileChange := regexp.MustCompile(fmt.Sprintf(`event detected in %s"`, regexEscapedPathFile))
+	_, err = testutils.WaitForString(ctx, detectedFileChange, pr)
+	if err != nil {
+		t.Fatalf("timeout or error waiting for file to detect write: %s", err)
+	}
+
+	reloaded := regexp.MustCompile(`DEBUG "Reloading tools file\(s\)."`)
+	_, err = testutils.WaitForString(ctx, reloaded, pr)
+	if err != nil {
+		t.Fatalf("timeout or error waiting for reload: %s", err)
+	}
+}
+
 func TestPrebuiltTools(t *testing.T) {
 	// Get prebuilt configs
 	alloydb_admin_config, _ := prebuiltconfigs.Get("alloydb-postgres-admin")
@@ -1712,3 +3399,326 @@ func TestFileLoadingErrors(t *testing.T) {
 		}
 	})
 }
+
+func TestListenUnixSocket(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	toolsFile, cleanup, err := tmpFileWithCleanup([]byte("tools: {}\n"))
+	if err != nil {
+		t.Fatalf("error creating tools file: %s", err)
+	}
+	defer cleanup()
+	socket := filepath.Join(t.TempDir(), "toolbox.sock")
+
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	c := NewCommand(WithStreams(pw, pw))
+	c.SetArgs([]string{"--tools-file", toolsFile, "--listen", "unix://" + socket, "--disable-reload"})
+	done := make(chan error)
+	go func() {
+		defer close(done)
+		defer pw.Close()
+		done <- c.ExecuteContext(ctx)
+	}()
+
+	_, out, err := testutils.WaitForServerReady(ctx, pr)
+	if err != nil {
+		t.Fatalf("toolbox didn't start successfully: %s\n%s", err, out)
+	}
+	// keep draining the output so that the command does not block on logging
+	go func() { _, _ = io.Copy(io.Discard, pr) }()
+	if !strings.Contains(out, "Server listening on unix://"+socket) {
+		t.Fatalf("listen address missing from output: %s", out)
+	}
+
+	client := &nethttp.Client{
+		Transport: &nethttp.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socket)
+			},
+		},
+	}
+	resp, err := client.Get("http://unix/api/toolset")
+	if err != nil {
+		t.Fatalf("error when sending a request: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != nethttp.StatusOK {
+		t.Fatalf("response status code is not 200, got %d", resp.StatusCode)
+	}
+
+	cancel()
+	<-done
+	if _, err := os.Stat(socket); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected socket to be removed on shutdown, got: %v", err)
+	}
+}
+
+func TestParseToolFileWithTags(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		quarterly_report:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: SELECT 1;
+			tags: [reporting, finance, reporting]
+		untagged_tool:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: SELECT 1;
+			tags: []
+	`
+	toolsFile, err := parseToolsFile(ctx, testutils.FormatYaml(in))
+	if err != nil {
+		t.Fatalf("failed to parse input: %v", err)
+	}
+	tagsCfg, ok := toolsFile.Tools["quarterly_report"].(tools.TagsConfig)
+	if !ok {
+		t.Fatalf("expected a tagged tool config, got %T", toolsFile.Tools["quarterly_report"])
+	}
+	if diff := cmp.Diff([]string{"reporting", "finance"}, tagsCfg.Tags); diff != "" {
+		t.Fatalf("unexpected tags (-want +got):\n%s", diff)
+	}
+	if _, ok := toolsFile.Tools["untagged_tool"].(tools.TagsConfig); ok {
+		t.Fatalf("expected an empty tags list to be ignored")
+	}
+}
+
+func TestParseToolFileWithGenerateExamples(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		declared_examples:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: SELECT 1;
+			generateExamples: false
+		generated_examples:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: SELECT 1;
+			generateExamples: true
+	`
+	toolsFile, err := parseToolsFile(ctx, testutils.FormatYaml(in))
+	if err != nil {
+		t.Fatalf("failed to parse input: %v", err)
+	}
+	if _, ok := toolsFile.Tools["declared_examples"].(tools.ExamplesConfig); !ok {
+		t.Fatalf("expected an examples tool config, got %T", toolsFile.Tools["declared_examples"])
+	}
+	if _, ok := toolsFile.Tools["generated_examples"].(tools.ExamplesConfig); ok {
+		t.Fatalf("expected generateExamples: true to be ignored")
+	}
+
+	in = `
+	tools:
+		declared_examples:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: SELECT 1;
+			generateExamples: never
+	`
+	_, err = parseToolsFile(ctx, testutils.FormatYaml(in))
+	want := `invalid 'generateExamples' field for tool "declared_examples" (must be a boolean)`
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Fatalf("unexpected error: got %v, want %s", err, want)
+	}
+}
+
+func TestParseToolFileWithIdempotencyKeys(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		insert_order:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: INSERT INTO orders VALUES (1);
+			idempotencyKeys: accepted
+		list_orders:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: SELECT * FROM orders;
+			idempotencyKeys: ignored
+	`
+	toolsFile, err := parseToolsFile(ctx, testutils.FormatYaml(in))
+	if err != nil {
+		t.Fatalf("failed to parse input: %v", err)
+	}
+	idempotencyCfg, ok := toolsFile.Tools["insert_order"].(tools.IdempotencyConfig)
+	if !ok {
+		t.Fatalf("expected an idempotency tool config, got %T", toolsFile.Tools["insert_order"])
+	}
+	if idempotencyCfg.Name != "insert_order" {
+		t.Fatalf("unexpected name: got %q, want %q", idempotencyCfg.Name, "insert_order")
+	}
+	if _, ok := toolsFile.Tools["list_orders"].(tools.IdempotencyConfig); ok {
+		t.Fatalf("expected idempotencyKeys: ignored to be ignored")
+	}
+
+	in = `
+	tools:
+		insert_order:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: INSERT INTO orders VALUES (1);
+			idempotencyKeys: required
+	`
+	_, err = parseToolsFile(ctx, testutils.FormatYaml(in))
+	want := `invalid 'idempotencyKeys' field for tool "insert_order" (must be "accepted" or "ignored")`
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Fatalf("unexpected error: got %v, want %s", err, want)
+	}
+}
+
+func TestParseToolFileWithPollable(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		job_status:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: SELECT status FROM jobs;
+			pollable: true
+		training_state:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: SELECT state FROM training;
+			pollable:
+				ttl: 30s
+				trustCache: true
+		list_jobs:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: SELECT * FROM jobs;
+			pollable: false
+	`
+	toolsFile, err := parseToolsFile(ctx, testutils.FormatYaml(in))
+	if err != nil {
+		t.Fatalf("failed to parse input: %v", err)
+	}
+	for _, name := range []string{"job_status", "training_state"} {
+		if _, ok := toolsFile.Tools[name].(tools.PollableConfig); !ok {
+			t.Fatalf("expected a pollable tool config for %q, got %T", name, toolsFile.Tools[name])
+		}
+	}
+	if _, ok := toolsFile.Tools["list_jobs"].(tools.PollableConfig); ok {
+		t.Fatalf("expected pollable: false to be ignored")
+	}
+
+	tcs := []struct {
+		desc     string
+		pollable string
+		want     string
+	}{
+		{
+			desc:     "not a boolean or mapping",
+			pollable: "pollable: yes please",
+			want:     `invalid 'pollable' field for tool "job_status" (must be a boolean or a mapping)`,
+		},
+		{
+			desc:     "invalid ttl",
+			pollable: "pollable:\n\t\t\t\tttl: 0s",
+			want:     `invalid 'pollable' field for tool "job_status": invalid ttl "0s": must be positive`,
+		},
+		{
+			desc:     "unknown field",
+			pollable: "pollable:\n\t\t\t\tmaxAge: 10s",
+			want:     `invalid 'pollable' field for tool "job_status"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			in := `
+	tools:
+		job_status:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: SELECT status FROM jobs;
+			` + tc.pollable + `
+	`
+			_, err := parseToolsFile(ctx, testutils.FormatYaml(in))
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("unexpected error: got %v, want %s", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestFailParseToolFileWithTags(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		description string
+		tags        string
+		errString   string
+	}{
+		{
+			description: "not a list",
+			tags:        "reporting",
+			errString:   `invalid 'tags' field for tool "quarterly_report" (must be a list of strings)`,
+		},
+		{
+			description: "not a string",
+			tags:        "[reporting, 3]",
+			errString:   `invalid tag 3 for tool "quarterly_report" (must be a string)`,
+		},
+		{
+			description: "uppercase",
+			tags:        "[Reporting]",
+			errString:   `invalid 'tags' field for tool "quarterly_report": tag "Reporting" must only contain lowercase letters`,
+		},
+		{
+			description: "invalid character",
+			tags:        "[reporting/q3]",
+			errString:   `invalid 'tags' field for tool "quarterly_report": tag "reporting/q3" must only contain lowercase letters`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.description, func(t *testing.T) {
+			in := `
+			tools:
+				quarterly_report:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: SELECT 1;
+					tags: ` + tc.tags
+			_, err := parseToolsFile(ctx, testutils.FormatYaml(in))
+			if err == nil {
+				t.Fatalf("expected parsing to fail")
+			}
+			if !strings.Contains(err.Error(), tc.errString) {
+				t.Fatalf("incorrect error string: got %s, want %s", err, tc.errString)
+			}
+		})
+	}
+}
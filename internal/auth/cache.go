@@ -0,0 +1,138 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultClaimsCacheSize is the number of tokens a ClaimsCache holds.
+	DefaultClaimsCacheSize = 1024
+	// DefaultClaimsCacheMaxTTL bounds how long the claims of a valid token
+	// are cached, even if it expires later.
+	DefaultClaimsCacheMaxTTL = 5 * time.Minute
+	// DefaultClaimsCacheNegativeTTL is how long the error of an invalid
+	// token is cached.
+	DefaultClaimsCacheNegativeTTL = 10 * time.Second
+)
+
+// VerifyFunc verifies token, returning its claims and when it expires.
+type VerifyFunc func(ctx context.Context, token string) (claims map[string]any, expires time.Time, err error)
+
+// ClaimsCache caches the result of verifying tokens, so that the invocations
+// of a burst carrying the same token verify it once. The claims of a valid
+// token are cached until it expires, up to maxTTL, and the error of an
+// invalid token for negativeTTL. Once it holds size tokens, the least
+// recently used one is evicted. Tokens are only held as their hash. A nil
+// *ClaimsCache verifies every token.
+type ClaimsCache struct {
+	size        int
+	maxTTL      time.Duration
+	negativeTTL time.Duration
+	now         func() time.Time
+
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]*list.Element
+	// order holds the entries from the most to the least recently used.
+	order *list.List
+}
+
+type claimsEntry struct {
+	key     [sha256.Size]byte
+	claims  map[string]any
+	err     error
+	expires time.Time
+}
+
+// NewClaimsCache returns a ClaimsCache of size tokens.
+func NewClaimsCache(size int, maxTTL, negativeTTL time.Duration) *ClaimsCache {
+	return &ClaimsCache{
+		size:        size,
+		maxTTL:      maxTTL,
+		negativeTTL: negativeTTL,
+		now:         time.Now,
+		entries:     make(map[[sha256.Size]byte]*list.Element),
+		order:       list.New(),
+	}
+}
+
+// Verify returns the claims of token, calling verify unless the result of a
+// previous verification of token is still cached.
+func (c *ClaimsCache) Verify(ctx context.Context, token string, verify VerifyFunc) (map[string]any, error) {
+	if c == nil {
+		claims, _, err := verify(ctx, token)
+		return claims, err
+	}
+	key := sha256.Sum256([]byte(token))
+	if claims, err, ok := c.get(key); ok {
+		return claims, err
+	}
+
+	claims, expires, err := verify(ctx, token)
+	now := c.now()
+	switch {
+	case err != nil:
+		// an error due to the request rather than the token is not cached
+		if ctx.Err() == nil {
+			c.put(&claimsEntry{key: key, err: err, expires: now.Add(c.negativeTTL)})
+		}
+	case expires.After(now):
+		if limit := now.Add(c.maxTTL); expires.After(limit) {
+			expires = limit
+		}
+		c.put(&claimsEntry{key: key, claims: claims, expires: expires})
+	}
+	return claims, err
+}
+
+// get returns the cached result of key, if any and not expired.
+func (c *ClaimsCache) get(key [sha256.Size]byte) (map[string]any, error, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, nil, false
+	}
+	e := el.Value.(*claimsEntry)
+	if !c.now().Before(e.expires) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return nil, nil, false
+	}
+	c.order.MoveToFront(el)
+	return e.claims, e.err, true
+}
+
+// put caches e, evicting the least recently used entries over the size.
+func (c *ClaimsCache) put(e *claimsEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[e.key]; ok {
+		el.Value = e
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[e.key] = c.order.PushFront(e)
+	for c.order.Len() > c.size {
+		el := c.order.Back()
+		c.order.Remove(el)
+		delete(c.entries, el.Value.(*claimsEntry).key)
+	}
+}
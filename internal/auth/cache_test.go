@@ -0,0 +1,183 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeVerifier counts the verifications of each token. Tokens in valid expire
+// at the given time, and other tokens are invalid.
+type fakeVerifier struct {
+	valid map[string]time.Time
+	calls map[string]int
+}
+
+func (v *fakeVerifier) verify(_ context.Context, token string) (map[string]any, time.Time, error) {
+	v.calls[token]++
+	expires, ok := v.valid[token]
+	if !ok {
+		return nil, time.Time{}, errors.New("invalid token")
+	}
+	return map[string]any{"sub": token}, expires, nil
+}
+
+func newTestCache(size int, now *time.Time) *ClaimsCache {
+	c := NewClaimsCache(size, 5*time.Minute, 10*time.Second)
+	c.now = func() time.Time { return *now }
+	return c
+}
+
+func TestClaimsCacheVerifiesOnce(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	v := &fakeVerifier{valid: map[string]time.Time{"a": now.Add(time.Hour)}, calls: map[string]int{}}
+	c := newTestCache(10, &now)
+
+	for i := 0; i < 10; i++ {
+		claims, err := c.Verify(context.Background(), "a", v.verify)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if diff := cmp.Diff(map[string]any{"sub": "a"}, claims); diff != "" {
+			t.Fatalf("incorrect claims: diff %v", diff)
+		}
+	}
+	if v.calls["a"] != 1 {
+		t.Fatalf("got %d verifications, want 1", v.calls["a"])
+	}
+
+	// a nil cache verifies every token
+	var nilCache *ClaimsCache
+	for i := 0; i < 3; i++ {
+		if _, err := nilCache.Verify(context.Background(), "a", v.verify); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if v.calls["a"] != 4 {
+		t.Fatalf("got %d verifications, want 4", v.calls["a"])
+	}
+}
+
+func TestClaimsCacheExpiry(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	v := &fakeVerifier{
+		valid: map[string]time.Time{
+			"short":   now.Add(time.Minute),
+			"long":    now.Add(time.Hour),
+			"expired": now.Add(-time.Minute),
+		},
+		calls: map[string]int{},
+	}
+	c := newTestCache(10, &now)
+	verify := func(token string) {
+		t.Helper()
+		if _, err := c.Verify(context.Background(), token, v.verify); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	for _, token := range []string{"short", "long", "expired"} {
+		verify(token)
+	}
+	now = now.Add(59 * time.Second)
+	for _, token := range []string{"short", "long", "expired"} {
+		verify(token)
+	}
+	want := map[string]int{"short": 1, "long": 1, "expired": 2}
+	if diff := cmp.Diff(want, v.calls); diff != "" {
+		t.Fatalf("incorrect verifications: diff %v", diff)
+	}
+
+	// short is cached until it expires
+	now = now.Add(time.Second)
+	verify("short")
+	verify("long")
+	want = map[string]int{"short": 2, "long": 1, "expired": 2}
+	if diff := cmp.Diff(want, v.calls); diff != "" {
+		t.Fatalf("incorrect verifications: diff %v", diff)
+	}
+
+	// long is cached for no more than the max TTL
+	now = now.Add(4 * time.Minute)
+	verify("long")
+	want = map[string]int{"short": 2, "long": 2, "expired": 2}
+	if diff := cmp.Diff(want, v.calls); diff != "" {
+		t.Fatalf("incorrect verifications: diff %v", diff)
+	}
+}
+
+func TestClaimsCacheNegative(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	v := &fakeVerifier{valid: map[string]time.Time{}, calls: map[string]int{}}
+	c := newTestCache(10, &now)
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.Verify(context.Background(), "bad", v.verify); err == nil {
+			t.Fatalf("expected an error")
+		}
+	}
+	if v.calls["bad"] != 1 {
+		t.Fatalf("got %d verifications, want 1", v.calls["bad"])
+	}
+
+	// the error is only cached briefly
+	now = now.Add(10 * time.Second)
+	if _, err := c.Verify(context.Background(), "bad", v.verify); err == nil {
+		t.Fatalf("expected an error")
+	}
+	if v.calls["bad"] != 2 {
+		t.Fatalf("got %d verifications, want 2", v.calls["bad"])
+	}
+
+	// errors of canceled requests are not cached
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i := 0; i < 2; i++ {
+		if _, err := c.Verify(ctx, "other", v.verify); err == nil {
+			t.Fatalf("expected an error")
+		}
+	}
+	if v.calls["other"] != 2 {
+		t.Fatalf("got %d verifications, want 2", v.calls["other"])
+	}
+}
+
+func TestClaimsCacheEviction(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	v := &fakeVerifier{
+		valid: map[string]time.Time{"a": now.Add(time.Hour), "b": now.Add(time.Hour), "c": now.Add(time.Hour)},
+		calls: map[string]int{},
+	}
+	c := newTestCache(2, &now)
+
+	// a is used more recently than b, so b is evicted for c
+	for _, token := range []string{"a", "b", "a", "c", "a", "b"} {
+		if _, err := c.Verify(context.Background(), token, v.verify); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	want := map[string]int{"a": 1, "b": 2, "c": 1}
+	if diff := cmp.Diff(want, v.calls); diff != "" {
+		t.Fatalf("incorrect verifications: diff %v", diff)
+	}
+	if len(c.entries) != 2 || c.order.Len() != 2 {
+		t.Fatalf("got %d entries, want 2", len(c.entries))
+	}
+}
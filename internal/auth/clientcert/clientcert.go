@@ -0,0 +1,144 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientcert
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/googleapis/genai-toolbox/internal/auth"
+)
+
+const AuthServiceKind string = "client-certificate"
+
+// validate interface
+var _ auth.AuthServiceConfig = Config{}
+
+// Auth service configuration
+type Config struct {
+	Name string `yaml:"name" validate:"required"`
+	Kind string `yaml:"kind" validate:"required"`
+	// AllowedNames, if set, restricts the callers to the certificates whose
+	// common name or one of whose subject alternative names is listed.
+	// Otherwise any certificate verified by the server is accepted.
+	AllowedNames []string `yaml:"allowedNames"`
+}
+
+// Returns the auth service kind
+func (cfg Config) AuthServiceConfigKind() string {
+	return AuthServiceKind
+}
+
+// Initialize a client certificate auth service
+func (cfg Config) Initialize() (auth.AuthService, error) {
+	a := &AuthService{
+		Name:         cfg.Name,
+		Kind:         AuthServiceKind,
+		AllowedNames: cfg.AllowedNames,
+	}
+	return a, nil
+}
+
+var _ auth.AuthService = AuthService{}
+
+// struct used to store auth service info
+type AuthService struct {
+	Name         string   `yaml:"name"`
+	Kind         string   `yaml:"kind"`
+	AllowedNames []string `yaml:"allowedNames"`
+}
+
+// Returns the auth service kind
+func (a AuthService) AuthServiceKind() string {
+	return AuthServiceKind
+}
+
+// Returns the name of the auth service
+func (a AuthService) GetName() string {
+	return a.Name
+}
+
+// GetClaimsFromHeader returns the claims of the client certificate verified
+// during the TLS handshake of the request. The header is not used, since a
+// certificate cannot be sent in a header.
+func (a AuthService) GetClaimsFromHeader(ctx context.Context, _ http.Header) (map[string]any, error) {
+	cert := FromContext(ctx)
+	if cert == nil {
+		return nil, nil
+	}
+	names := Names(cert)
+	if len(a.AllowedNames) > 0 && !slices.ContainsFunc(names, func(n string) bool { return slices.Contains(a.AllowedNames, n) }) {
+		return nil, fmt.Errorf("client certificate %q is not allowed by auth service %q", cert.Subject.CommonName, a.Name)
+	}
+	return Claims(cert), nil
+}
+
+// Names returns the common name and the subject alternative names of cert.
+func Names(cert *x509.Certificate) []string {
+	var names []string
+	if cert.Subject.CommonName != "" {
+		names = append(names, cert.Subject.CommonName)
+	}
+	names = append(names, cert.DNSNames...)
+	names = append(names, cert.EmailAddresses...)
+	for _, u := range cert.URIs {
+		names = append(names, u.String())
+	}
+	return names
+}
+
+// Claims returns the identity of the client certificate cert as claims, which
+// authenticated parameters can reference like the claims of a token. `sub` is
+// the common name of the certificate.
+func Claims(cert *x509.Certificate) map[string]any {
+	uris := make([]any, 0, len(cert.URIs))
+	for _, u := range cert.URIs {
+		uris = append(uris, u.String())
+	}
+	return map[string]any{
+		"sub":            cert.Subject.CommonName,
+		"commonName":     cert.Subject.CommonName,
+		"dnsNames":       toAny(cert.DNSNames),
+		"emailAddresses": toAny(cert.EmailAddresses),
+		"uris":           uris,
+		"serialNumber":   cert.SerialNumber.String(),
+	}
+}
+
+func toAny(s []string) []any {
+	out := make([]any, 0, len(s))
+	for _, v := range s {
+		out = append(out, v)
+	}
+	return out
+}
+
+type contextKey struct{}
+
+// WithCertificate returns a context carrying cert, the client certificate
+// verified during the TLS handshake of a request.
+func WithCertificate(ctx context.Context, cert *x509.Certificate) context.Context {
+	return context.WithValue(ctx, contextKey{}, cert)
+}
+
+// FromContext returns the verified client certificate of ctx, or nil if the
+// request did not present one.
+func FromContext(ctx context.Context) *x509.Certificate {
+	cert, _ := ctx.Value(contextKey{}).(*x509.Certificate)
+	return cert
+}
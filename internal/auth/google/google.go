@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/googleapis/genai-toolbox/internal/auth"
 	"google.golang.org/api/idtoken"
@@ -33,6 +34,10 @@ type Config struct {
 	Name     string `yaml:"name" validate:"required"`
 	Kind     string `yaml:"kind" validate:"required"`
 	ClientID string `yaml:"clientId" validate:"required"`
+	// DisableClaimsCache, if set, verifies the token of every invocation
+	// rather than caching its claims, so that revoked tokens are rejected
+	// right away.
+	DisableClaimsCache bool `yaml:"disableClaimsCache"`
 }
 
 // Returns the auth service kind
@@ -47,6 +52,9 @@ func (cfg Config) Initialize() (auth.AuthService, error) {
 		Kind:     AuthServiceKind,
 		ClientID: cfg.ClientID,
 	}
+	if !cfg.DisableClaimsCache {
+		a.cache = auth.NewClaimsCache(auth.DefaultClaimsCacheSize, auth.DefaultClaimsCacheMaxTTL, auth.DefaultClaimsCacheNegativeTTL)
+	}
 	return a, nil
 }
 
@@ -57,6 +65,8 @@ type AuthService struct {
 	Name     string `yaml:"name"`
 	Kind     string `yaml:"kind"`
 	ClientID string `yaml:"clientId"`
+
+	cache *auth.ClaimsCache
 }
 
 // Returns the auth service kind
@@ -72,11 +82,16 @@ func (a AuthService) GetName() string {
 // Verifies Google ID token and return claims
 func (a AuthService) GetClaimsFromHeader(ctx context.Context, h http.Header) (map[string]any, error) {
 	if token := h.Get(a.Name + "_token"); token != "" {
-		payload, err := idtoken.Validate(ctx, token, a.ClientID)
-		if err != nil {
-			return nil, fmt.Errorf("Google ID token verification failure: %w", err) //nolint:staticcheck
-		}
-		return payload.Claims, nil
+		return a.cache.Verify(ctx, token, a.verify)
 	}
 	return nil, nil
 }
+
+// verify validates a Google ID token and returns its claims.
+func (a AuthService) verify(ctx context.Context, token string) (map[string]any, time.Time, error) {
+	payload, err := idtoken.Validate(ctx, token, a.ClientID)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("Google ID token verification failure: %w", err) //nolint:staticcheck
+	}
+	return payload.Claims, time.Unix(payload.Expires, 0), nil
+}
@@ -0,0 +1,233 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package events publishes an event for every invocation of a tool to a sink,
+// such as a Pub/Sub topic, for usage analytics. An event describes an
+// invocation, not its arguments or its result. Events are published
+// asynchronously and in batches, so that invocations never wait on the sink.
+package events
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/log"
+)
+
+const (
+	// DefaultBufferSize is the number of events buffered while they wait to
+	// be published, unless configured otherwise.
+	DefaultBufferSize = 10000
+	// DefaultBatchSize is the most events published at once, unless
+	// configured otherwise.
+	DefaultBatchSize = 100
+	// DefaultFlushInterval is how long an event waits for its batch to fill
+	// up before it is published anyway, unless configured otherwise.
+	DefaultFlushInterval = 5 * time.Second
+)
+
+// publishTimeout bounds the publication of a batch, so that an unavailable
+// sink holds up the following batches for a bounded time.
+const publishTimeout = 30 * time.Second
+
+// Event describes an invocation of a tool.
+type Event struct {
+	Tool string `json:"tool"`
+	// Kind is the kind of the tool, if known.
+	Kind string `json:"kind,omitempty"`
+	// Source is the name of the source the tool acts on, if any.
+	Source     string `json:"source,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+	// Status is either "success" or "error".
+	Status string `json:"status"`
+	// CallerHash is the hash of the subject of the caller, if it was
+	// authenticated, so that callers can be told apart without being named.
+	CallerHash string    `json:"callerHash,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// HashSubject returns the CallerHash of the caller with the given subject.
+func HashSubject(subject string) string {
+	if subject == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(subject))
+	return hex.EncodeToString(sum[:])
+}
+
+// Sink is where events are published.
+type Sink interface {
+	// Publish publishes a batch of events.
+	Publish(ctx context.Context, events []Event) error
+	// Close releases the resources of the sink.
+	Close() error
+}
+
+// PublisherConfig configures a Publisher.
+type PublisherConfig struct {
+	// BufferSize is the number of events buffered while they wait to be
+	// published. If zero, DefaultBufferSize is used.
+	BufferSize int
+	// BatchSize is the most events published at once. If zero,
+	// DefaultBatchSize is used.
+	BatchSize int
+	// FlushInterval is how long an event waits for its batch to fill up
+	// before it is published anyway. If zero, DefaultFlushInterval is used.
+	FlushInterval time.Duration
+}
+
+// Publisher publishes events to a Sink in the background, in batches. While
+// the sink is slow or unavailable, events are buffered up to a bound, and
+// the events that do not fit are dropped and counted instead of holding up
+// invocations. The events of a batch that fails to publish are dropped and
+// counted too.
+type Publisher struct {
+	sink          Sink
+	logger        log.Logger
+	events        chan Event
+	batchSize     int
+	flushInterval time.Duration
+	// dropped is the number of events dropped since startup.
+	dropped atomic.Int64
+	// degraded is set while the sink fails to publish, so that its failures
+	// are logged once rather than for every batch.
+	degraded  bool
+	closed    atomic.Bool
+	closeOnce sync.Once
+	// stop passes the context of Close to the publishing goroutine, which
+	// flushes the buffered events and closes done.
+	stop chan context.Context
+	done chan struct{}
+}
+
+// NewPublisher returns a Publisher publishing to sink, and starts publishing.
+// It must be closed to flush its events.
+func NewPublisher(sink Sink, cfg PublisherConfig, logger log.Logger) (*Publisher, error) {
+	if cfg.BufferSize < 0 || cfg.BatchSize < 0 || cfg.FlushInterval < 0 {
+		return nil, fmt.Errorf("invalid event publisher config %+v: must not be negative", cfg)
+	}
+	if cfg.BufferSize == 0 {
+		cfg.BufferSize = DefaultBufferSize
+	}
+	if cfg.BatchSize == 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.FlushInterval == 0 {
+		cfg.FlushInterval = DefaultFlushInterval
+	}
+	p := &Publisher{
+		sink:          sink,
+		logger:        logger,
+		events:        make(chan Event, cfg.BufferSize),
+		batchSize:     cfg.BatchSize,
+		flushInterval: cfg.FlushInterval,
+		stop:          make(chan context.Context, 1),
+		done:          make(chan struct{}),
+	}
+	go p.run()
+	return p, nil
+}
+
+// Emit queues e to be published. It never blocks: if the buffer is full, or
+// the Publisher is closed, e is dropped.
+func (p *Publisher) Emit(e Event) {
+	if p.closed.Load() {
+		p.dropped.Add(1)
+		return
+	}
+	select {
+	case p.events <- e:
+	default:
+		p.dropped.Add(1)
+	}
+}
+
+// Dropped returns the number of events dropped since startup.
+func (p *Publisher) Dropped() int64 {
+	return p.dropped.Load()
+}
+
+// Close publishes the buffered events, within the deadline of ctx, and closes
+// the sink. The events emitted afterwards are dropped.
+func (p *Publisher) Close(ctx context.Context) error {
+	var err error
+	p.closeOnce.Do(func() {
+		p.closed.Store(true)
+		p.stop <- ctx
+		select {
+		case <-p.done:
+		case <-ctx.Done():
+			err = fmt.Errorf("unable to flush events: %w", ctx.Err())
+		}
+		if cerr := p.sink.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("unable to close event sink: %w", cerr)
+		}
+	})
+	return err
+}
+
+// run publishes the events as their batches fill up or their flush interval
+// elapses, until the Publisher is closed.
+func (p *Publisher) run() {
+	defer close(p.done)
+	ticker := time.NewTicker(p.flushInterval)
+	defer ticker.Stop()
+	batch := make([]Event, 0, p.batchSize)
+	for {
+		select {
+		case e := <-p.events:
+			batch = append(batch, e)
+			if len(batch) >= p.batchSize {
+				batch = p.publish(context.Background(), batch)
+			}
+		case <-ticker.C:
+			batch = p.publish(context.Background(), batch)
+		case ctx := <-p.stop:
+			// run is the only reader of events, so that they are all drained
+			for len(p.events) > 0 {
+				batch = append(batch, <-p.events)
+				if len(batch) >= p.batchSize {
+					batch = p.publish(ctx, batch)
+				}
+			}
+			p.publish(ctx, batch)
+			return
+		}
+	}
+}
+
+// publish publishes batch, if it is not empty, and returns the next batch.
+func (p *Publisher) publish(ctx context.Context, batch []Event) []Event {
+	if len(batch) == 0 {
+		return batch
+	}
+	ctx, cancel := context.WithTimeout(ctx, publishTimeout)
+	defer cancel()
+	if err := p.sink.Publish(ctx, batch); err != nil {
+		p.dropped.Add(int64(len(batch)))
+		if !p.degraded {
+			p.degraded = true
+			p.logger.WarnContext(ctx, fmt.Sprintf("event sink is unavailable, dropping invocation events until it recovers: %s", err))
+		}
+	} else if p.degraded {
+		p.degraded = false
+		p.logger.InfoContext(ctx, "event sink recovered")
+	}
+	return make([]Event, 0, p.batchSize)
+}
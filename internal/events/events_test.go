@@ -0,0 +1,280 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/events"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"google.golang.org/api/option"
+)
+
+// fakeSink records the batches published to it. If block is set, Publish
+// signals published and waits for block to be closed.
+type fakeSink struct {
+	mu        sync.Mutex
+	batches   [][]string
+	err       error
+	closed    bool
+	block     chan struct{}
+	published chan struct{}
+}
+
+func (s *fakeSink) Publish(ctx context.Context, evts []events.Event) error {
+	if s.block != nil {
+		s.published <- struct{}{}
+		<-s.block
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	batch := make([]string, len(evts))
+	for i, e := range evts {
+		batch[i] = e.Tool
+	}
+	s.batches = append(s.batches, batch)
+	return nil
+}
+
+func (s *fakeSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func (s *fakeSink) got() [][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.batches
+}
+
+func newPublisher(t *testing.T, sink events.Sink, cfg events.PublisherConfig) *events.Publisher {
+	t.Helper()
+	logger, err := log.NewStdLogger(io.Discard, io.Discard, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	p, err := events.NewPublisher(sink, cfg, logger)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return p
+}
+
+func emit(p *events.Publisher, tools ...string) {
+	for _, tool := range tools {
+		p.Emit(events.Event{Tool: tool, Status: "success"})
+	}
+}
+
+// waitFor waits for cond to hold.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for the events to be published")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestPublisherBatches(t *testing.T) {
+	sink := &fakeSink{}
+	p := newPublisher(t, sink, events.PublisherConfig{BatchSize: 3, FlushInterval: time.Hour})
+	emit(p, "a", "b", "c", "d", "e", "f", "g")
+	waitFor(t, func() bool { return len(sink.got()) == 2 })
+
+	// the partial batch waits for the flush interval, or for Close
+	if err := p.Close(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := [][]string{{"a", "b", "c"}, {"d", "e", "f"}, {"g"}}
+	if diff := cmp.Diff(want, sink.got()); diff != "" {
+		t.Fatalf("unexpected batches (-want +got):\n%s", diff)
+	}
+	if !sink.closed {
+		t.Fatalf("expected the sink to be closed")
+	}
+	if p.Dropped() != 0 {
+		t.Fatalf("unexpected dropped events: %d", p.Dropped())
+	}
+}
+
+func TestPublisherFlushInterval(t *testing.T) {
+	sink := &fakeSink{}
+	p := newPublisher(t, sink, events.PublisherConfig{FlushInterval: 10 * time.Millisecond})
+	defer p.Close(context.Background())
+	emit(p, "a", "b")
+	waitFor(t, func() bool { return len(sink.got()) == 1 })
+	if diff := cmp.Diff([][]string{{"a", "b"}}, sink.got()); diff != "" {
+		t.Fatalf("unexpected batches (-want +got):\n%s", diff)
+	}
+}
+
+func TestPublisherDropsUnderBackpressure(t *testing.T) {
+	sink := &fakeSink{block: make(chan struct{}), published: make(chan struct{}, 1)}
+	p := newPublisher(t, sink, events.PublisherConfig{BufferSize: 2, BatchSize: 1})
+	emit(p, "a")
+	// the sink is stuck publishing a, while b and c fill up the buffer
+	<-sink.published
+	emit(p, "b", "c", "d", "e", "f")
+	if p.Dropped() != 3 {
+		t.Fatalf("unexpected dropped events: got %d, want 3", p.Dropped())
+	}
+
+	sink.published = make(chan struct{}, 3)
+	close(sink.block)
+	if err := p.Close(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff([][]string{{"a"}, {"b"}, {"c"}}, sink.got()); diff != "" {
+		t.Fatalf("unexpected batches (-want +got):\n%s", diff)
+	}
+
+	// the events emitted once closed are dropped
+	emit(p, "g")
+	if p.Dropped() != 4 {
+		t.Fatalf("unexpected dropped events: got %d, want 4", p.Dropped())
+	}
+}
+
+func TestPublisherDropsFailedBatches(t *testing.T) {
+	sink := &fakeSink{err: errors.New("topic not found")}
+	p := newPublisher(t, sink, events.PublisherConfig{BatchSize: 2, FlushInterval: time.Hour})
+	emit(p, "a", "b", "c")
+	if err := p.Close(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if p.Dropped() != 3 {
+		t.Fatalf("unexpected dropped events: got %d, want 3", p.Dropped())
+	}
+}
+
+func TestPublisherCloseDeadline(t *testing.T) {
+	sink := &fakeSink{block: make(chan struct{}), published: make(chan struct{}, 1)}
+	defer close(sink.block)
+	p := newPublisher(t, sink, events.PublisherConfig{FlushInterval: time.Hour})
+	emit(p, "a")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := p.Close(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("unexpected error: got %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestNewPublisherErrors(t *testing.T) {
+	logger, err := log.NewStdLogger(io.Discard, io.Discard, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	_, err = events.NewPublisher(&fakeSink{}, events.PublisherConfig{BatchSize: -1}, logger)
+	if err == nil || !strings.Contains(err.Error(), "must not be negative") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestWriterSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := events.NewWriterSink(&buf)
+	at := time.Date(2025, 10, 15, 12, 0, 0, 0, time.UTC)
+	err := sink.Publish(context.Background(), []events.Event{
+		{Tool: "search", Kind: "postgres-sql", Source: "my-pg", DurationMs: 12, Status: "success", CallerHash: events.HashSubject("alice"), Timestamp: at},
+		{Tool: "list", DurationMs: 3, Status: "error", Timestamp: at},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := `{"tool":"search","kind":"postgres-sql","source":"my-pg","durationMs":12,"status":"success","callerHash":"2bd806c97f0e00af1a1fc3328fa763a9269723c8db8fac4f93af71db186d6e90","timestamp":"2025-10-15T12:00:00Z"}
+{"tool":"list","durationMs":3,"status":"error","timestamp":"2025-10-15T12:00:00Z"}
+`
+	if got := buf.String(); got != want {
+		t.Fatalf("unexpected output: got %s, want %s", got, want)
+	}
+}
+
+func TestPubSubSink(t *testing.T) {
+	var got []map[string]any
+	var attrs []map[string]string
+	var path string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		var req struct {
+			Messages []struct {
+				Data       string            `json:"data"`
+				Attributes map[string]string `json:"attributes"`
+			} `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("unable to decode request: %s", err)
+		}
+		for _, m := range req.Messages {
+			data, err := base64.StdEncoding.DecodeString(m.Data)
+			if err != nil {
+				t.Errorf("unable to decode message: %s", err)
+			}
+			var e map[string]any
+			if err := json.Unmarshal(data, &e); err != nil {
+				t.Errorf("unable to decode event: %s", err)
+			}
+			got = append(got, e)
+			attrs = append(attrs, m.Attributes)
+		}
+		_, _ = w.Write([]byte(`{"messageIds": ["1"]}`))
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	sink, err := events.NewPubSubSink(ctx, "projects/my-project/topics/toolbox-events", option.WithEndpoint(ts.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	at := time.Date(2025, 10, 15, 12, 0, 0, 0, time.UTC)
+	if err := sink.Publish(ctx, []events.Event{{Tool: "search", DurationMs: 12, Status: "success", Timestamp: at}}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if path != "/v1/projects/my-project/topics/toolbox-events:publish" {
+		t.Fatalf("unexpected request path: %s", path)
+	}
+	want := []map[string]any{{"tool": "search", "durationMs": float64(12), "status": "success", "timestamp": "2025-10-15T12:00:00Z"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected events (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]map[string]string{{"tool": "search", "status": "success"}}, attrs); diff != "" {
+		t.Fatalf("unexpected attributes (-want +got):\n%s", diff)
+	}
+
+	_, err = events.NewPubSubSink(ctx, "toolbox-events")
+	if err == nil || !strings.Contains(err.Error(), "must be of the form projects/<project>/topics/<topic>") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
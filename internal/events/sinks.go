@@ -0,0 +1,133 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sync"
+
+	"google.golang.org/api/option"
+	pubsub "google.golang.org/api/pubsub/v1"
+)
+
+// maxPubSubMessages is the most messages Pub/Sub accepts in a publish
+// request.
+const maxPubSubMessages = 1000
+
+// topicRegexp matches the full name of a Pub/Sub topic.
+var topicRegexp = regexp.MustCompile(`^projects/[^/]+/topics/[^/]+$`)
+
+// PubSubSink publishes events to a Pub/Sub topic, as a JSON message each.
+// The messages have the tool and the status of their event as attributes,
+// so that subscriptions can filter them.
+type PubSubSink struct {
+	topic   string
+	service *pubsub.Service
+}
+
+// validate interface
+var _ Sink = &PubSubSink{}
+
+// NewPubSubSink returns a PubSubSink publishing to topic, the full name of a
+// topic such as `projects/my-project/topics/toolbox-events`. It
+// authenticates with the Application Default Credentials, unless opts
+// specify otherwise.
+func NewPubSubSink(ctx context.Context, topic string, opts ...option.ClientOption) (*PubSubSink, error) {
+	if !topicRegexp.MatchString(topic) {
+		return nil, fmt.Errorf("invalid Pub/Sub topic %q: must be of the form projects/<project>/topics/<topic>", topic)
+	}
+	service, err := pubsub.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create Pub/Sub client: %w", err)
+	}
+	return &PubSubSink{topic: topic, service: service}, nil
+}
+
+func (s *PubSubSink) Publish(ctx context.Context, events []Event) error {
+	for len(events) > 0 {
+		n := min(len(events), maxPubSubMessages)
+		messages := make([]*pubsub.PubsubMessage, n)
+		for i, e := range events[:n] {
+			data, err := json.Marshal(e)
+			if err != nil {
+				return fmt.Errorf("unable to marshal event: %w", err)
+			}
+			messages[i] = &pubsub.PubsubMessage{
+				Data:       base64.StdEncoding.EncodeToString(data),
+				Attributes: map[string]string{"tool": e.Tool, "status": e.Status},
+			}
+		}
+		req := &pubsub.PublishRequest{Messages: messages}
+		if _, err := s.service.Projects.Topics.Publish(s.topic, req).Context(ctx).Do(); err != nil {
+			return fmt.Errorf("unable to publish events to %s: %w", s.topic, err)
+		}
+		events = events[n:]
+	}
+	return nil
+}
+
+func (s *PubSubSink) Close() error {
+	return nil
+}
+
+// WriterSink writes events to a writer as JSON lines, such as to inspect
+// them locally or to test a deployment without a Pub/Sub topic.
+type WriterSink struct {
+	mu     sync.Mutex
+	enc    *json.Encoder
+	closer io.Closer
+}
+
+// validate interface
+var _ Sink = &WriterSink{}
+
+// NewWriterSink returns a WriterSink writing to w.
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{enc: json.NewEncoder(w)}
+}
+
+// NewFileSink returns a WriterSink appending to the file at path, which is
+// created if needed, and closed with the sink.
+func NewFileSink(path string) (*WriterSink, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open event file: %w", err)
+	}
+	return &WriterSink{enc: json.NewEncoder(f), closer: f}, nil
+}
+
+func (s *WriterSink) Publish(_ context.Context, events []Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range events {
+		if err := s.enc.Encode(e); err != nil {
+			return fmt.Errorf("unable to write event: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *WriterSink) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
@@ -483,3 +483,56 @@ func TestStructuredLoggerDebugLog(t *testing.T) {
 		})
 	}
 }
+
+func TestNewReadyLine(t *testing.T) {
+	tcs := []struct {
+		name  string
+		addrs []string
+		want  ReadyLine
+	}{
+		{
+			name:  "tcp",
+			addrs: []string{"tcp://127.0.0.1:5000"},
+			want:  ReadyLine{Event: ServerReadyEvent, Address: "127.0.0.1", Port: 5000, URL: "http://127.0.0.1:5000", Listeners: []string{"tcp://127.0.0.1:5000"}},
+		},
+		{
+			name:  "unspecified ipv4",
+			addrs: []string{"tcp://0.0.0.0:5000"},
+			want:  ReadyLine{Event: ServerReadyEvent, Address: "0.0.0.0", Port: 5000, URL: "http://127.0.0.1:5000", Listeners: []string{"tcp://0.0.0.0:5000"}},
+		},
+		{
+			name:  "unspecified ipv6",
+			addrs: []string{"tcp://[::]:5000"},
+			want:  ReadyLine{Event: ServerReadyEvent, Address: "::", Port: 5000, URL: "http://[::1]:5000", Listeners: []string{"tcp://[::]:5000"}},
+		},
+		{
+			name:  "first tcp listener",
+			addrs: []string{"unix:///tmp/toolbox.sock", "tcp://127.0.0.1:5001", "tcp://127.0.0.1:5002"},
+			want:  ReadyLine{Event: ServerReadyEvent, Address: "127.0.0.1", Port: 5001, URL: "http://127.0.0.1:5001", Listeners: []string{"unix:///tmp/toolbox.sock", "tcp://127.0.0.1:5001", "tcp://127.0.0.1:5002"}},
+		},
+		{
+			name:  "unix only",
+			addrs: []string{"unix:///tmp/toolbox.sock"},
+			want:  ReadyLine{Event: ServerReadyEvent, Listeners: []string{"unix:///tmp/toolbox.sock"}},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got := NewReadyLine(tc.addrs)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected ready line (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWriteReadyLine(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteReadyLine(&buf, NewReadyLine([]string{"tcp://127.0.0.1:5000"})); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := `{"event":"server_ready","address":"127.0.0.1","port":5000,"url":"http://127.0.0.1:5000","listeners":["tcp://127.0.0.1:5000"]}` + "\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("unexpected ready line: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// ServerReadyEvent is the event of the line written once the server is ready
+// to serve.
+const ServerReadyEvent = "server_ready"
+
+// ReadyLine is a machine-readable line written once the server is ready to
+// serve, whatever the format and level of the logs. Scripts and tests should
+// wait for it rather than for the wording of the logs meant for humans.
+type ReadyLine struct {
+	Event string `json:"event"`
+	// Address and Port are those of the first TCP listener. They are empty
+	// if the server only listens on Unix domain sockets.
+	Address string `json:"address,omitempty"`
+	Port    int    `json:"port,omitempty"`
+	// URL is the base URL of the first TCP listener, with unspecified
+	// addresses replaced by the loopback address.
+	URL string `json:"url,omitempty"`
+	// Listeners are all the addresses the server listens on, such as
+	// `tcp://127.0.0.1:5000` or `unix:///tmp/toolbox.sock`.
+	Listeners []string `json:"listeners"`
+}
+
+// NewReadyLine returns the ReadyLine of a server listening on addrs.
+func NewReadyLine(addrs []string) ReadyLine {
+	line := ReadyLine{Event: ServerReadyEvent, Listeners: addrs}
+	for _, addr := range addrs {
+		hostPort, ok := strings.CutPrefix(addr, "tcp://")
+		if !ok {
+			continue
+		}
+		host, port, err := net.SplitHostPort(hostPort)
+		if err != nil {
+			continue
+		}
+		p, err := strconv.Atoi(port)
+		if err != nil {
+			continue
+		}
+		line.Address, line.Port = host, p
+		if ip := net.ParseIP(host); ip != nil && ip.IsUnspecified() {
+			host = "127.0.0.1"
+			if ip.To4() == nil {
+				host = "::1"
+			}
+		}
+		line.URL = "http://" + net.JoinHostPort(host, port)
+		break
+	}
+	return line
+}
+
+// WriteReadyLine writes line to w as a single line of JSON.
+func WriteReadyLine(w io.Writer, line ReadyLine) error {
+	b, err := json.Marshal(line)
+	if err != nil {
+		return fmt.Errorf("unable to marshal ready line: %w", err)
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
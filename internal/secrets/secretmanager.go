@@ -0,0 +1,232 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package secrets resolves references to Google Secret Manager secrets, of
+// the form `sm://projects/<project>/secrets/<secret>/versions/<version>`,
+// found in configuration values.
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+
+	"golang.org/x/oauth2/google"
+)
+
+// Scheme is the prefix of Secret Manager references.
+const Scheme = "sm://"
+
+// DefaultEndpoint is the Secret Manager API endpoint.
+const DefaultEndpoint = "https://secretmanager.googleapis.com"
+
+const cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+// secretNameRegex matches the resource name of a secret version. The version
+// may be omitted, in which case the latest version is used.
+var secretNameRegex = regexp.MustCompile(`^projects/[^/]+/secrets/[^/]+(/versions/[^/]+)?$`)
+
+// Resolver fetches secrets from Secret Manager. Fetched values are cached for
+// the lifetime of the Resolver.
+type Resolver struct {
+	endpoint string
+	// newClient returns the HTTP client that authenticates requests. It is
+	// called once, the first time a secret is fetched.
+	newClient func(ctx context.Context) (*http.Client, error)
+
+	clientOnce sync.Once
+	client     *http.Client
+	clientErr  error
+
+	mu    sync.Mutex
+	cache map[string]string
+}
+
+// NewResolver returns a Resolver that sends its requests to endpoint with
+// client, which is responsible for authentication.
+func NewResolver(client *http.Client, endpoint string) *Resolver {
+	return &Resolver{
+		endpoint:  strings.TrimSuffix(endpoint, "/"),
+		newClient: func(context.Context) (*http.Client, error) { return client, nil },
+		cache:     make(map[string]string),
+	}
+}
+
+// defaultResolver authenticates with Application Default Credentials. It is
+// shared by the whole process, so that secrets are only fetched once even if
+// the configuration is reloaded.
+var defaultResolver = &Resolver{
+	endpoint: DefaultEndpoint,
+	newClient: func(ctx context.Context) (*http.Client, error) {
+		// the client outlives the context of the first resolution
+		client, err := google.DefaultClient(context.WithoutCancel(ctx), cloudPlatformScope)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find default Google Cloud credentials: %w", err)
+		}
+		return client, nil
+	},
+	cache: make(map[string]string),
+}
+
+type contextKey string
+
+// resolverKey is the key used to store the Resolver within context
+const resolverKey contextKey = "secretResolver"
+
+// WithResolver adds a Resolver into the context as a value
+func WithResolver(ctx context.Context, r *Resolver) context.Context {
+	return context.WithValue(ctx, resolverKey, r)
+}
+
+// ResolverFromContext retrieves the Resolver, or the process-wide Resolver
+// using Application Default Credentials if none is set
+func ResolverFromContext(ctx context.Context) *Resolver {
+	if r, ok := ctx.Value(resolverKey).(*Resolver); ok {
+		return r
+	}
+	return defaultResolver
+}
+
+// IsReference reports whether s is a Secret Manager reference.
+func IsReference(s string) bool {
+	return strings.HasPrefix(s, Scheme)
+}
+
+// parseReference returns the resource name of the secret version referenced
+// by ref.
+func parseReference(ref string) (string, error) {
+	name := strings.TrimPrefix(ref, Scheme)
+	if !secretNameRegex.MatchString(name) {
+		return "", fmt.Errorf("invalid secret reference %q: must be of the form %sprojects/<project>/secrets/<secret>/versions/<version>", ref, Scheme)
+	}
+	if !strings.Contains(name, "/versions/") {
+		name += "/versions/latest"
+	}
+	return name, nil
+}
+
+// Resolve returns the value of the secret referenced by ref. Errors name the
+// secret, never its value.
+func (r *Resolver) Resolve(ctx context.Context, ref string) (string, error) {
+	name, err := parseReference(ref)
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if v, ok := r.cache[name]; ok {
+		return v, nil
+	}
+
+	r.clientOnce.Do(func() {
+		r.client, r.clientErr = r.newClient(ctx)
+	})
+	if r.clientErr != nil {
+		return "", fmt.Errorf("unable to access secret %q: %w", name, r.clientErr)
+	}
+
+	v, err := r.access(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	r.cache[name] = v
+	return v, nil
+}
+
+// access calls the AccessSecretVersion method of the Secret Manager API.
+func (r *Resolver) access(ctx context.Context, name string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v1/%s:access", r.endpoint, name), nil)
+	if err != nil {
+		return "", fmt.Errorf("unable to access secret %q: %w", name, err)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("unable to access secret %q: %w", name, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("unable to access secret %q: %w", name, err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusForbidden, http.StatusUnauthorized:
+		return "", fmt.Errorf("permission denied to access secret %q", name)
+	case http.StatusNotFound:
+		return "", fmt.Errorf("secret %q not found", name)
+	default:
+		return "", fmt.Errorf("unable to access secret %q: unexpected status code %d", name, resp.StatusCode)
+	}
+
+	var out struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return "", fmt.Errorf("unable to parse secret %q: %w", name, err)
+	}
+	data, err := base64.StdEncoding.DecodeString(out.Payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("unable to decode secret %q: %w", name, err)
+	}
+	return string(data), nil
+}
+
+// ResolveAll replaces the Secret Manager references found in the string
+// values of v, including in nested maps and lists, with the secrets they
+// reference.
+func (r *Resolver) ResolveAll(ctx context.Context, v map[string]any) error {
+	for k, val := range v {
+		resolved, err := r.resolveValue(ctx, val)
+		if err != nil {
+			return err
+		}
+		v[k] = resolved
+	}
+	return nil
+}
+
+func (r *Resolver) resolveValue(ctx context.Context, v any) (any, error) {
+	switch val := v.(type) {
+	case string:
+		if !IsReference(val) {
+			return val, nil
+		}
+		return r.Resolve(ctx, val)
+	case map[string]any:
+		if err := r.ResolveAll(ctx, val); err != nil {
+			return nil, err
+		}
+		return val, nil
+	case []any:
+		for i, item := range val {
+			resolved, err := r.resolveValue(ctx, item)
+			if err != nil {
+				return nil, err
+			}
+			val[i] = resolved
+		}
+		return val, nil
+	}
+	return v, nil
+}
@@ -0,0 +1,174 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/secrets"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+func TestResolve(t *testing.T) {
+	fake := testutils.NewFakeSecretManager(t, map[string]string{
+		"projects/p/secrets/db-pass/versions/latest": "s3cret",
+		"projects/p/secrets/db-pass/versions/2":      "older",
+	})
+	fake.Deny("projects/p/secrets/forbidden/versions/latest")
+	r := secrets.NewResolver(fake.Client(), fake.URL())
+	ctx := context.Background()
+
+	tcs := []struct {
+		desc string
+		ref  string
+		want string
+		err  string
+	}{
+		{desc: "latest", ref: "sm://projects/p/secrets/db-pass/versions/latest", want: "s3cret"},
+		{desc: "default version", ref: "sm://projects/p/secrets/db-pass", want: "s3cret"},
+		{desc: "pinned version", ref: "sm://projects/p/secrets/db-pass/versions/2", want: "older"},
+		{
+			desc: "permission denied",
+			ref:  "sm://projects/p/secrets/forbidden/versions/latest",
+			err:  `permission denied to access secret "projects/p/secrets/forbidden/versions/latest"`,
+		},
+		{
+			desc: "not found",
+			ref:  "sm://projects/p/secrets/missing",
+			err:  `secret "projects/p/secrets/missing/versions/latest" not found`,
+		},
+		{
+			desc: "missing secret",
+			ref:  "sm://projects/p",
+			err:  `invalid secret reference "sm://projects/p"`,
+		},
+		{
+			desc: "wrong collection",
+			ref:  "sm://projects/p/keys/db-pass",
+			err:  `invalid secret reference "sm://projects/p/keys/db-pass"`,
+		},
+		{
+			desc: "trailing slash",
+			ref:  "sm://projects/p/secrets/db-pass/versions/",
+			err:  `invalid secret reference "sm://projects/p/secrets/db-pass/versions/"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := r.Resolve(ctx, tc.ref)
+			if tc.err != "" {
+				if err == nil {
+					t.Fatalf("expected an error, got %q", got)
+				}
+				if !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("unexpected error: got %q, want substring %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != tc.want {
+				t.Fatalf("unexpected value: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestResolveCachesValues(t *testing.T) {
+	fake := testutils.NewFakeSecretManager(t, map[string]string{
+		"projects/p/secrets/db-pass/versions/latest": "s3cret",
+	})
+	r := secrets.NewResolver(fake.Client(), fake.URL())
+	ctx := context.Background()
+
+	for _, ref := range []string{
+		"sm://projects/p/secrets/db-pass/versions/latest",
+		"sm://projects/p/secrets/db-pass",
+		"sm://projects/p/secrets/db-pass/versions/latest",
+	} {
+		if _, err := r.Resolve(ctx, ref); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if got := fake.Requests(); got != 1 {
+		t.Fatalf("unexpected number of requests: got %d, want 1", got)
+	}
+}
+
+func TestResolveAll(t *testing.T) {
+	fake := testutils.NewFakeSecretManager(t, map[string]string{
+		"projects/p/secrets/db-pass/versions/latest": "s3cret",
+		"projects/p/secrets/api-key/versions/latest": "k3y",
+	})
+	r := secrets.NewResolver(fake.Client(), fake.URL())
+
+	v := map[string]any{
+		"kind":     "postgres",
+		"port":     5432,
+		"password": "sm://projects/p/secrets/db-pass",
+		"headers":  map[string]any{"X-Api-Key": "sm://projects/p/secrets/api-key"},
+		"hosts":    []any{"a", "sm://projects/p/secrets/db-pass"},
+	}
+	if err := r.ResolveAll(context.Background(), v); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := map[string]any{
+		"kind":     "postgres",
+		"port":     5432,
+		"password": "s3cret",
+		"headers":  map[string]any{"X-Api-Key": "k3y"},
+		"hosts":    []any{"a", "s3cret"},
+	}
+	if diff := cmp.Diff(want, v); diff != "" {
+		t.Fatalf("unexpected values: diff %v", diff)
+	}
+}
+
+func TestResolveAllDoesNotLeakValues(t *testing.T) {
+	fake := testutils.NewFakeSecretManager(t, map[string]string{
+		"projects/p/secrets/db-pass/versions/latest": "s3cret",
+	})
+	fake.Deny("projects/p/secrets/forbidden/versions/latest")
+	r := secrets.NewResolver(fake.Client(), fake.URL())
+
+	v := map[string]any{
+		"password": "sm://projects/p/secrets/db-pass",
+		"user":     "sm://projects/p/secrets/forbidden",
+	}
+	err := r.ResolveAll(context.Background(), v)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(), "projects/p/secrets/forbidden") {
+		t.Fatalf("error does not name the secret: %q", err)
+	}
+	if strings.Contains(err.Error(), "s3cret") {
+		t.Fatalf("error contains a secret value: %q", err)
+	}
+}
+
+func TestResolverFromContext(t *testing.T) {
+	r := secrets.NewResolver(nil, "http://localhost")
+	if got := secrets.ResolverFromContext(secrets.WithResolver(context.Background(), r)); got != r {
+		t.Fatalf("unexpected resolver: got %p, want %p", got, r)
+	}
+	if got := secrets.ResolverFromContext(context.Background()); got == nil || got == r {
+		t.Fatalf("expected the default resolver, got %p", got)
+	}
+}
@@ -0,0 +1,513 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/telemetry"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// connectionFields are the fields of a source config that identify what it
+// connects to. Only these fields are reported by the admin endpoints, so that
+// credentials are never exposed.
+var connectionFields = []string{
+	"address", "baseUrl", "cluster", "database", "databaseId", "dgraphUrl",
+	"host", "hosts", "httpUrl", "instance", "ipType", "keyspace", "location",
+	"namespace", "port", "project", "projectId", "region", "schema", "uri",
+	"user", "username",
+}
+
+// SourceDetails returns the sanitized connection details of each source,
+// keyed by source name. See connectionFields.
+func SourceDetails(cfgs SourceConfigs) map[string]map[string]any {
+	details := make(map[string]map[string]any, len(cfgs))
+	for name, sc := range cfgs {
+		details[name] = connectionDetails(sc)
+	}
+	return details
+}
+
+// connectionDetails returns the non-zero connection fields of a source config.
+func connectionDetails(sc sources.SourceConfig) map[string]any {
+	sc = unwrapSourceConfig(sc)
+	details := make(map[string]any)
+	v := reflect.ValueOf(sc)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return details
+	}
+	for i := 0; i < v.NumField(); i++ {
+		name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("yaml"), ",")
+		if !slices.Contains(connectionFields, name) {
+			continue
+		}
+		f := v.Field(i)
+		if !f.CanInterface() || f.IsZero() {
+			continue
+		}
+		switch f.Kind() {
+		case reflect.String:
+			details[name] = redactURL(f.String())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			details[name] = f.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			details[name] = f.Uint()
+		case reflect.Slice:
+			if f.Type().Elem().Kind() != reflect.String {
+				continue
+			}
+			values := make([]string, f.Len())
+			for j := range values {
+				values[j] = redactURL(f.Index(j).String())
+			}
+			details[name] = values
+		}
+	}
+	return details
+}
+
+// redactURL removes the user info and query of s if it is a URL, as either
+// may hold credentials.
+func redactURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return s
+	}
+	u.User = nil
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String()
+}
+
+// poolStats returns the statistics of the connection pool of a source, or nil
+// if it has none. The pool is found through the accessors the tools use to
+// reach it, such as PostgresPool.
+func poolStats(s sources.Source) map[string]any {
+	switch src := s.(type) {
+	case interface{ PostgresPool() *pgxpool.Pool }:
+		return pgxPoolStats(src.PostgresPool())
+	case interface{ YugabyteDBPool() *pgxpool.Pool }:
+		return pgxPoolStats(src.YugabyteDBPool())
+	case interface{ MySQLPool() *sql.DB }:
+		return sqlDBStats(src.MySQLPool())
+	case interface{ TiDBPool() *sql.DB }:
+		return sqlDBStats(src.TiDBPool())
+	case interface{ MindsDBPool() *sql.DB }:
+		return sqlDBStats(src.MindsDBPool())
+	case interface{ OceanBasePool() *sql.DB }:
+		return sqlDBStats(src.OceanBasePool())
+	case interface{ ClickHousePool() *sql.DB }:
+		return sqlDBStats(src.ClickHousePool())
+	case interface{ MSSQLDB() *sql.DB }:
+		return sqlDBStats(src.MSSQLDB())
+	case interface{ OracleDB() *sql.DB }:
+		return sqlDBStats(src.OracleDB())
+	case interface{ SQLiteDB() *sql.DB }:
+		return sqlDBStats(src.SQLiteDB())
+	case interface{ FirebirdDB() *sql.DB }:
+		return sqlDBStats(src.FirebirdDB())
+	case interface{ TrinoDB() *sql.DB }:
+		return sqlDBStats(src.TrinoDB())
+	}
+	return nil
+}
+
+func sqlDBStats(db *sql.DB) map[string]any {
+	if db == nil {
+		return nil
+	}
+	st := db.Stats()
+	return map[string]any{
+		"maxConnections":   st.MaxOpenConnections,
+		"totalConnections": st.OpenConnections,
+		"inUseConnections": st.InUse,
+		"idleConnections":  st.Idle,
+		"waitCount":        st.WaitCount,
+		"waitDuration":     st.WaitDuration.String(),
+	}
+}
+
+func pgxPoolStats(pool *pgxpool.Pool) map[string]any {
+	if pool == nil {
+		return nil
+	}
+	st := pool.Stat()
+	return map[string]any{
+		"maxConnections":   st.MaxConns(),
+		"totalConnections": st.TotalConns(),
+		"inUseConnections": st.AcquiredConns(),
+		"idleConnections":  st.IdleConns(),
+		"waitCount":        st.EmptyAcquireCount(),
+		"waitDuration":     st.EmptyAcquireWaitTime().String(),
+	}
+}
+
+// recentWindow is how far back the recent invocations of a source are
+// counted. It is divided into one minute buckets.
+const recentWindow = 15 * time.Minute
+
+type invocationBucket struct {
+	minute      int64
+	invocations int
+	errors      int
+}
+
+type sourceInvocations struct {
+	total   int
+	errors  int
+	buckets [recentWindow / time.Minute]invocationBucket
+}
+
+// invocationStats counts the tool invocations made against each source.
+type invocationStats struct {
+	mu      sync.Mutex
+	sources map[string]*sourceInvocations
+	now     func() time.Time
+}
+
+func newInvocationStats() *invocationStats {
+	return &invocationStats{sources: make(map[string]*sourceInvocations), now: time.Now}
+}
+
+// record counts an invocation against source.
+func (s *invocationStats) record(source string, failed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	si, ok := s.sources[source]
+	if !ok {
+		si = &sourceInvocations{}
+		s.sources[source] = si
+	}
+	minute := s.now().Unix() / 60
+	b := &si.buckets[minute%int64(len(si.buckets))]
+	if b.minute != minute {
+		*b = invocationBucket{minute: minute}
+	}
+	si.total++
+	b.invocations++
+	if failed {
+		si.errors++
+		b.errors++
+	}
+}
+
+// summary returns the invocation counts of source, or nil if it was never
+// invoked.
+func (s *invocationStats) summary(source string) map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	si, ok := s.sources[source]
+	if !ok {
+		return nil
+	}
+	minute := s.now().Unix() / 60
+	recent, recentErrors := 0, 0
+	for _, b := range si.buckets {
+		if minute-b.minute < int64(len(si.buckets)) {
+			recent += b.invocations
+			recentErrors += b.errors
+		}
+	}
+	return map[string]any{
+		"total":        si.total,
+		"errors":       si.errors,
+		"recent":       recent,
+		"recentErrors": recentErrors,
+		"recentWindow": recentWindow.String(),
+	}
+}
+
+// recordInvocation counts an invocation of the named tool against the source
+// it acts on, if any.
+func (r *ResourceManager) recordInvocation(toolName string, failed bool) {
+	r.mu.RLock()
+	source, ok := r.toolSources[toolName]
+	r.mu.RUnlock()
+	if ok {
+		r.invocations.record(source, failed)
+	}
+}
+
+// mcpToolName returns the name of the tool called by an MCP tools/call
+// request.
+func mcpToolName(body []byte) string {
+	var req struct {
+		Params struct {
+			Name string `json:"name"`
+		} `json:"params"`
+	}
+	_ = json.Unmarshal(body, &req)
+	return req.Params.Name
+}
+
+// mcpToolArguments returns the raw arguments of an MCP tools/call request.
+func mcpToolArguments(body []byte) json.RawMessage {
+	var req struct {
+		Params struct {
+			Arguments json.RawMessage `json:"arguments"`
+		} `json:"params"`
+	}
+	_ = json.Unmarshal(body, &req)
+	return req.Params.Arguments
+}
+
+// mcpCallFailed reports whether the response to an MCP tools/call request is
+// an error, either as a JSON-RPC error or as a result with `isError` set.
+func mcpCallFailed(res any) bool {
+	switch r := res.(type) {
+	case jsonrpc.JSONRPCError:
+		return true
+	case jsonrpc.JSONRPCResponse:
+		// the result type differs between protocol versions
+		v := reflect.ValueOf(r.Result)
+		if v.Kind() == reflect.Struct {
+			f := v.FieldByName("IsError")
+			return f.Kind() == reflect.Bool && f.Bool()
+		}
+	}
+	return false
+}
+
+// sourceSummary describes a source for the admin endpoints.
+type sourceSummary struct {
+	Name        string         `json:"name"`
+	Kind        string         `json:"kind"`
+	Connection  map[string]any `json:"connection"`
+	Pool        map[string]any `json:"pool,omitempty"`
+	Tools       int            `json:"tools"`
+	Invocations map[string]any `json:"invocations,omitempty"`
+	// CircuitBreakers are the states of the breakers of the source or of its
+	// tools.
+	CircuitBreakers []tools.CircuitBreakerStatus `json:"circuitBreakers,omitempty"`
+}
+
+// sourceSummaries returns the summaries of the named sources. Sources that do
+// not exist are skipped. Aliases are not counted as tools.
+func (r *ResourceManager) sourceSummaries(names []string) []sourceSummary {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	toolCounts := make(map[string]int)
+	for name, t := range r.tools {
+		if _, ok := t.(tools.AliasTool); ok {
+			continue
+		}
+		if source, ok := r.toolSources[name]; ok {
+			toolCounts[source]++
+		}
+	}
+	breakers := r.circuitBreakers()
+	summaries := make([]sourceSummary, 0, len(names))
+	for _, name := range names {
+		s, ok := r.sources[name]
+		if !ok {
+			continue
+		}
+		connection := r.sourceDetails[name]
+		if connection == nil {
+			connection = map[string]any{}
+		}
+		summaries = append(summaries, sourceSummary{
+			Name:            name,
+			Kind:            s.SourceKind(),
+			Connection:      connection,
+			Pool:            poolStats(s),
+			Tools:           toolCounts[name],
+			CircuitBreakers: breakers[name],
+		})
+	}
+	return summaries
+}
+
+// circuitBreakers returns the states of the circuit breakers of the tools,
+// keyed by the source the tools act on, "" for tools without one. A breaker
+// shared by several tools, or by a tool and its aliases, is reported once.
+// The caller must hold r.mu.
+func (r *ResourceManager) circuitBreakers() map[string][]tools.CircuitBreakerStatus {
+	seen := make(map[*tools.CircuitBreaker]bool)
+	breakers := make(map[string][]tools.CircuitBreakerStatus)
+	for name, t := range r.tools {
+		bt, ok := tools.As[tools.CircuitBreakerTool](t)
+		if !ok || seen[bt.Breaker] {
+			continue
+		}
+		seen[bt.Breaker] = true
+		source := r.toolSources[name]
+		breakers[source] = append(breakers[source], bt.Breaker.Status())
+	}
+	for _, statuses := range breakers {
+		slices.SortFunc(statuses, func(a, b tools.CircuitBreakerStatus) int {
+			return cmp.Or(cmp.Compare(a.Scope, b.Scope), cmp.Compare(a.Name, b.Name))
+		})
+	}
+	return breakers
+}
+
+// breakerStateValues are the values of the states of the circuit breakers
+// reported by the circuit breaker metrics.
+var breakerStateValues = map[tools.BreakerState]int64{
+	tools.BreakerClosed:   0,
+	tools.BreakerHalfOpen: 1,
+	tools.BreakerOpen:     2,
+}
+
+// circuitBreakerStates returns the states of the circuit breakers of the
+// tools, for the circuit breaker metrics.
+func (r *ResourceManager) circuitBreakerStates() []telemetry.CircuitBreakerState {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var states []telemetry.CircuitBreakerState
+	for _, statuses := range r.circuitBreakers() {
+		for _, s := range statuses {
+			states = append(states, telemetry.CircuitBreakerState{
+				Scope: s.Scope,
+				Name:  s.Name,
+				State: breakerStateValues[s.State],
+				Trips: int64(s.Trips),
+			})
+		}
+	}
+	return states
+}
+
+// authorizeAdmin checks that the request is authorized by the admin auth
+// service, for one of the admin subjects or emails. It returns the status code
+// to respond with if it is not.
+func (s *Server) authorizeAdmin(ctx context.Context, r *http.Request, action string) (int, error) {
+	if s.adminAuthService == "" {
+		return http.StatusNotFound, fmt.Errorf("admin endpoints are disabled: no admin auth service is configured")
+	}
+	aS, ok := s.ResourceMgr.GetAuthService(s.adminAuthService)
+	if !ok {
+		return http.StatusInternalServerError, fmt.Errorf("admin auth service %q is not configured", s.adminAuthService)
+	}
+	claims, err := aS.GetClaimsFromHeader(ctx, r.Header)
+	if err != nil || claims == nil {
+		return http.StatusUnauthorized, fmt.Errorf("%s not authorized. Please make sure your specify correct auth headers", action)
+	}
+	if sub, ok := claims["sub"].(string); ok && slices.Contains(s.adminSubjects, sub) {
+		return 0, nil
+	}
+	// an email only identifies the caller once its owner is verified
+	if email, ok := claims["email"].(string); ok && claims["email_verified"] != false && slices.Contains(s.adminEmails, email) {
+		return 0, nil
+	}
+	return http.StatusForbidden, fmt.Errorf("%s not allowed: the caller is not an admin", action)
+}
+
+// adminSourcesHandler handles the admin request to list the sources.
+func adminSourcesHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/admin/sources/list")
+	r = r.WithContext(ctx)
+	defer span.End()
+
+	if status, err := s.authorizeAdmin(ctx, r, "listing sources"); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, status))
+		return
+	}
+	s.ResourceMgr.mu.RLock()
+	names := slices.Sorted(maps.Keys(s.ResourceMgr.sources))
+	s.ResourceMgr.mu.RUnlock()
+	render.JSON(w, r, map[string]any{"sources": s.ResourceMgr.sourceSummaries(names)})
+}
+
+// adminSourceHandler handles the admin request for a single source, including
+// the counts of the invocations made against it.
+func adminSourceHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/admin/sources/get")
+	r = r.WithContext(ctx)
+	defer span.End()
+
+	sourceName := chi.URLParam(r, "sourceName")
+	span.SetAttributes(attribute.String("source_name", sourceName))
+	if status, err := s.authorizeAdmin(ctx, r, "getting source"); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, status))
+		return
+	}
+	summaries := s.ResourceMgr.sourceSummaries([]string{sourceName})
+	if len(summaries) == 0 {
+		err := fmt.Errorf("source %q does not exist", sourceName)
+		span.SetStatus(codes.Error, err.Error())
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
+		return
+	}
+	summary := summaries[0]
+	summary.Invocations = s.ResourceMgr.invocations.summary(sourceName)
+	render.JSON(w, r, summary)
+}
+
+// slowQueries returns the plans captured by the tools with
+// `slowQueryExplain`, newest first. Aliases are skipped so that each plan is
+// reported once.
+func (r *ResourceManager) slowQueries() []tools.SlowQueryPlan {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	plans := []tools.SlowQueryPlan{}
+	for _, t := range r.tools {
+		if _, ok := t.(tools.AliasTool); ok {
+			continue
+		}
+		if st, ok := tools.As[tools.SlowQueryTool](t); ok {
+			plans = append(plans, st.SlowQueries()...)
+		}
+	}
+	slices.SortFunc(plans, func(a, b tools.SlowQueryPlan) int {
+		return b.CapturedAt.Compare(a.CapturedAt)
+	})
+	return plans
+}
+
+// adminSlowQueriesHandler handles the admin request to list the plans
+// captured for slow tool invocations.
+func adminSlowQueriesHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/admin/slow-queries/list")
+	r = r.WithContext(ctx)
+	defer span.End()
+
+	if status, err := s.authorizeAdmin(ctx, r, "listing slow queries"); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, status))
+		return
+	}
+	render.JSON(w, r, map[string]any{"slowQueries": s.ResourceMgr.slowQueries()})
+}
@@ -15,15 +15,21 @@
 package server
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
+	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/util"
 	"go.opentelemetry.io/otel/attribute"
@@ -35,9 +41,11 @@ import (
 func apiRouter(s *Server) (chi.Router, error) {
 	r := chi.NewRouter()
 
-	r.Use(middleware.AllowContentType("application/json"))
+	r.Use(middleware.AllowContentType("application/json", yamlContentType, "application/x-yaml", "text/yaml", multipartContentType, ndjsonContentType, "text/plain", "application/octet-stream"))
 	r.Use(middleware.StripSlashes)
 	r.Use(render.SetContentType(render.ContentTypeJSON))
+	r.Use(s.artifactContext)
+	r.Use(s.idempotencyContext)
 
 	r.Get("/toolset", func(w http.ResponseWriter, r *http.Request) { toolsetHandler(s, w, r) })
 	r.Get("/toolset/{toolsetName}", func(w http.ResponseWriter, r *http.Request) { toolsetHandler(s, w, r) })
@@ -45,8 +53,22 @@ func apiRouter(s *Server) (chi.Router, error) {
 	r.Route("/tool/{toolName}", func(r chi.Router) {
 		r.Get("/", func(w http.ResponseWriter, r *http.Request) { toolGetHandler(s, w, r) })
 		r.Post("/invoke", func(w http.ResponseWriter, r *http.Request) { toolInvokeHandler(s, w, r) })
+		r.Post("/invoke/bulk", func(w http.ResponseWriter, r *http.Request) { toolBulkInvokeHandler(s, w, r) })
+		r.Get("/render", func(w http.ResponseWriter, r *http.Request) { toolRenderHandler(s, w, r) })
+		r.Post("/render", func(w http.ResponseWriter, r *http.Request) { toolRenderHandler(s, w, r) })
 	})
 
+	r.Post("/source/{sourceName}/rotate", func(w http.ResponseWriter, r *http.Request) { sourceRotateHandler(s, w, r) })
+	r.Get("/admin/sources", func(w http.ResponseWriter, r *http.Request) { adminSourcesHandler(s, w, r) })
+	r.Get("/admin/sources/{sourceName}", func(w http.ResponseWriter, r *http.Request) { adminSourceHandler(s, w, r) })
+	r.Get("/admin/slow-queries", func(w http.ResponseWriter, r *http.Request) { adminSlowQueriesHandler(s, w, r) })
+	if s.artifacts != nil {
+		if len(s.artifactUploadAuthServices) > 0 {
+			r.Put("/artifacts", func(w http.ResponseWriter, r *http.Request) { artifactUploadHandler(s, w, r) })
+		}
+		r.Get("/artifacts/{artifactId}", func(w http.ResponseWriter, r *http.Request) { artifactHandler(s, w, r) })
+	}
+
 	return r, nil
 }
 
@@ -84,7 +106,14 @@ func toolsetHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
 		return
 	}
-	render.JSON(w, r, toolset.Manifest)
+	tags, err := tools.ParseTags(r.URL.Query().Get("tags"))
+	if err != nil {
+		err = fmt.Errorf("invalid 'tags' query parameter: %w", err)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		return
+	}
+	renderManifest(w, r, toolset.WithTags(tags).Manifest)
 }
 
 // toolGetHandler handles requests for a single Tool.
@@ -128,7 +157,7 @@ func toolGetHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		},
 	}
 
-	render.JSON(w, r, m)
+	renderManifest(w, r, m)
 }
 
 // toolInvokeHandler handles the API request to invoke a specific Tool.
@@ -140,7 +169,10 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	toolName := chi.URLParam(r, "toolName")
 	s.logger.DebugContext(ctx, fmt.Sprintf("tool name: %s", toolName))
 	span.SetAttributes(attribute.String("tool_name", toolName))
+	start := time.Now()
 	var err error
+	// callerClaims are the claims of the caller, once verified
+	var callerClaims map[string]map[string]any
 	defer func() {
 		if err != nil {
 			span.SetStatus(codes.Error, err.Error())
@@ -157,6 +189,8 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 			metric.WithAttributes(attribute.String("toolbox.name", toolName)),
 			metric.WithAttributes(attribute.String("toolbox.operation.status", status)),
 		)
+		s.ResourceMgr.recordInvocation(toolName, err != nil)
+		s.publishInvocation(toolName, start, err != nil, callerClaims)
 	}()
 
 	tool, ok := s.ResourceMgr.GetTool(toolName)
@@ -167,6 +201,20 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ctx, err = withScheduleOverride(ctx, r)
+	if err != nil {
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		return
+	}
+	if key := r.Header.Get("Idempotency-Key"); key != "" {
+		ctx = tools.WithIdempotencyKey(ctx, key)
+	}
+	ctx, poll := tools.WithPollState(ctx, r.Header.Get("If-None-Match"))
+	ctx = s.withSession(ctx, r.Header.Get(sessionHeader))
+
+	ctx = s.withClientAttribution(ctx, r)
+
 	// Extract OAuth access token from the "Authorization" header (currently for
 	// BigQuery end-user credentials usage only)
 	accessToken := tools.AccessToken(r.Header.Get("Authorization"))
@@ -183,27 +231,10 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 
 	// Tool authentication
 	// claimsFromAuth maps the name of the authservice to the claims retrieved from it.
-	claimsFromAuth := make(map[string]map[string]any)
-	for _, aS := range s.ResourceMgr.GetAuthServiceMap() {
-		claims, err := aS.GetClaimsFromHeader(ctx, r.Header)
-		if err != nil {
-			s.logger.DebugContext(ctx, err.Error())
-			continue
-		}
-		if claims == nil {
-			// authService not present in header
-			continue
-		}
-		claimsFromAuth[aS.GetName()] = claims
-	}
-
-	// Tool authorization check
-	verifiedAuthServices := make([]string, len(claimsFromAuth))
-	i := 0
-	for k := range claimsFromAuth {
-		verifiedAuthServices[i] = k
-		i++
-	}
+	claimsFromAuth, verifiedAuthServices := s.authClaims(ctx, r.Header)
+	callerClaims = claimsFromAuth
+	ctx = util.WithVerifiedAuthServices(ctx, verifiedAuthServices)
+	ctx = util.WithAuthClaims(ctx, claimsFromAuth)
 
 	// Check if any of the specified auth services is verified
 	isAuthorized := tool.Authorized(verifiedAuthServices)
@@ -216,9 +247,34 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	s.logger.DebugContext(ctx, "tool invocation authorized")
 
 	var data map[string]any
-	if err = util.DecodeJSON(r.Body, &data); err != nil {
+	if isMultipart(r) {
+		var statusCode int
+		data, statusCode, err = multipartParams(w, r, tool, s.payloadLimits)
+		if r.MultipartForm != nil {
+			defer func() { _ = r.MultipartForm.RemoveAll() }()
+		}
+		if err != nil {
+			s.logger.DebugContext(ctx, err.Error())
+			_ = render.Render(w, r, newErrResponse(err, statusCode))
+			return
+		}
+	} else if isYAML(r) {
+		if err = decodeYAML(r.Body, &data, s.payloadLimits); err != nil {
+			err = payloadError("YAML", err)
+			s.logger.DebugContext(ctx, err.Error())
+			_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+			return
+		}
+	} else if err = util.DecodeJSONWithLimits(r.Body, &data, s.payloadLimits); err != nil {
 		render.Status(r, http.StatusBadRequest)
-		err = fmt.Errorf("request body was invalid JSON: %w", err)
+		err = payloadError("JSON", err)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	if err = tools.ResolveResourceReferences(ctx, data); err != nil {
+		err = fmt.Errorf("provided parameters were invalid: %w", err)
 		s.logger.DebugContext(ctx, err.Error())
 		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
 		return
@@ -237,9 +293,9 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
 		return
 	}
-	s.logger.DebugContext(ctx, fmt.Sprintf("invocation params: %s", params))
+	s.logger.DebugContext(ctx, fmt.Sprintf("invocation params: %s", params.Redacted()))
 
-	res, err := tool.Invoke(ctx, params, accessToken)
+	res, err := tool.Invoke(ctx, httpInvocation(r, accessToken, claimsFromAuth).WithParams(params))
 
 	// Determine what error to return to the users.
 	if err != nil {
@@ -253,6 +309,8 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		case strings.Contains(errStr, "Error 403"):
 			statusCode = http.StatusForbidden
 		}
+		// the values of sensitive parameters may be quoted by the error
+		err = params.RedactError(err)
 
 		if statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden {
 			if tool.RequiresClientAuthorization() {
@@ -267,46 +325,671 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 			_ = render.Render(w, r, newErrResponse(internalErr, http.StatusInternalServerError))
 			return
 		}
+		if errors.Is(err, tools.ErrToolBusy) {
+			s.logger.DebugContext(ctx, err.Error())
+			_ = render.Render(w, r, newErrResponse(err, http.StatusServiceUnavailable))
+			return
+		}
+		var openErr *tools.CircuitOpenError
+		if errors.As(err, &openErr) {
+			s.logger.DebugContext(ctx, err.Error())
+			resp := newErrResponse(err, http.StatusServiceUnavailable)
+			resp.AvailableAt = openErr.Until.Format(time.RFC3339)
+			w.Header().Set("Retry-After", strconv.Itoa(int(openErr.RetryAfter().Seconds())))
+			_ = render.Render(w, r, resp)
+			return
+		}
+		var poolErr *tools.PoolExhaustedError
+		if errors.As(err, &poolErr) {
+			s.logger.WarnContext(ctx, err.Error())
+			resp := newErrResponse(err, http.StatusServiceUnavailable)
+			resp.AvailableAt = time.Now().Add(poolErr.RetryAfter).Format(time.RFC3339)
+			w.Header().Set("Retry-After", strconv.Itoa(int(poolErr.RetryAfter.Seconds())))
+			_ = render.Render(w, r, resp)
+			return
+		}
+		var unavailableErr *tools.UnavailableError
+		if errors.As(err, &unavailableErr) {
+			s.logger.DebugContext(ctx, err.Error())
+			resp := newErrResponse(err, http.StatusServiceUnavailable)
+			if !unavailableErr.Until.IsZero() {
+				resp.AvailableAt = unavailableErr.Until.Format(time.RFC3339)
+				w.Header().Set("Retry-After", strconv.Itoa(max(1, int(time.Until(unavailableErr.Until).Seconds()))))
+			}
+			_ = render.Render(w, r, resp)
+			return
+		}
+		if errors.Is(err, tools.ErrUnauthorized) {
+			s.logger.DebugContext(ctx, err.Error())
+			_ = render.Render(w, r, newErrResponse(err, http.StatusUnauthorized))
+			return
+		}
+		var disabledErr *tools.KindDisabledError
+		if errors.As(err, &disabledErr) {
+			s.logger.DebugContext(ctx, err.Error())
+			_ = render.Render(w, r, newErrResponse(err, http.StatusForbidden))
+			return
+		}
+		var deniedErr *tools.PolicyDeniedError
+		if errors.As(err, &deniedErr) {
+			s.logger.DebugContext(ctx, err.Error())
+			_ = render.Render(w, r, newErrResponse(err, http.StatusForbidden))
+			return
+		}
+		var statusErr *tools.StatusError
+		if errors.As(err, &statusErr) {
+			err = fmt.Errorf("error while invoking tool: %w", err)
+			s.logger.DebugContext(ctx, err.Error())
+			_ = render.Render(w, r, newErrResponse(err, statusErr.Code))
+			return
+		}
 		err = fmt.Errorf("error while invoking tool: %w", err)
 		s.logger.DebugContext(ctx, err.Error())
 		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
 		return
 	}
 
-	resMarshal, err := json.Marshal(res)
+	if poll.ETag != "" {
+		w.Header().Set("ETag", poll.ETag)
+	}
+	if poll.NotModified {
+		w.WriteHeader(http.StatusNotModified)
+		tools.RecordInvocation(ctx, toolName, tool, params, 0)
+		return
+	}
+
+	rw := &resultWriter{w: w}
+	if mr, ok := res.(tools.MeteredResult); ok {
+		res, rw.usage = mr.Result, mr.NewCounter()
+	}
+	if err = rw.writeResult(res); err != nil {
+		statusCode := http.StatusInternalServerError
+		var rowsErr *rowsError
+		if errors.As(err, &rowsErr) {
+			// the rows of the result failed to be read
+			err = fmt.Errorf("error while invoking tool: %w", params.RedactError(rowsErr.err))
+			statusCode = http.StatusBadRequest
+		} else {
+			err = fmt.Errorf("unable to marshal result: %w", err)
+		}
+		if rw.started {
+			// the response is already partially written, so the status code
+			// can no longer be changed.
+			s.logger.ErrorContext(ctx, err.Error())
+			rw.writeError(err)
+			return
+		}
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, statusCode))
+		return
+	}
+	tools.RecordInvocation(ctx, toolName, tool, params, rw.rows)
+}
+
+// toolRenderHandler handles the API request to render the statement of a
+// Tool. It takes the same arguments as an invocation, but only resolves the
+// statement, without running it. It is only available in dev mode.
+func toolRenderHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/tool/render")
+	r = r.WithContext(ctx)
+	ctx = util.WithLogger(r.Context(), s.logger)
+
+	toolName := chi.URLParam(r, "toolName")
+	span.SetAttributes(attribute.String("tool_name", toolName))
+	var err error
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	if !s.dev {
+		err = fmt.Errorf("rendering tools is only available when the server runs with --dev")
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
+		return
+	}
+
+	tool, ok := s.ResourceMgr.GetTool(toolName)
+	if !ok {
+		err = fmt.Errorf("invalid tool name: tool with name %q does not exist", toolName)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
+		return
+	}
+	renderer, ok := tools.As[tools.Renderer](tool)
+	if !ok {
+		err = fmt.Errorf("tool %q does not run a statement that can be rendered", toolName)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	if tool.RequiresClientAuthorization() && r.Header.Get("Authorization") == "" {
+		err = fmt.Errorf("tool requires client authorization but access token is missing from the request header")
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusUnauthorized))
+		return
+	}
+	claimsFromAuth, verifiedAuthServices := s.authClaims(ctx, r.Header)
+	if !tool.Authorized(verifiedAuthServices) {
+		err = fmt.Errorf("tool invocation not authorized. Please make sure your specify correct auth headers")
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusUnauthorized))
+		return
+	}
+
+	// the arguments may be omitted, such as in GET requests
+	data := make(map[string]any)
+	if err = util.DecodeJSONWithLimits(r.Body, &data, s.payloadLimits); err != nil && !errors.Is(err, io.EOF) {
+		err = payloadError("JSON", err)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	params, err := tool.ParseParams(data, claimsFromAuth)
+	if err != nil {
+		if errors.Is(err, tools.ErrUnauthorized) {
+			s.logger.DebugContext(ctx, fmt.Sprintf("error parsing authenticated parameters from ID token: %s", err))
+			_ = render.Render(w, r, newErrResponse(err, http.StatusUnauthorized))
+			return
+		}
+		err = fmt.Errorf("provided parameters were invalid: %w", err)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	rendered, err := renderer.Render(params)
+	if err != nil {
+		err = fmt.Errorf("unable to render statement: %w", err)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		return
+	}
+	render.JSON(w, r, rendered)
+}
+
+// authClaims returns the claims retrieved from the header by each
+// authService, and the names of the authServices that verified the caller.
+func (s *Server) authClaims(ctx context.Context, header http.Header) (map[string]map[string]any, []string) {
+	claimsFromAuth := make(map[string]map[string]any)
+	for _, aS := range s.ResourceMgr.GetAuthServiceMap() {
+		claims, err := aS.GetClaimsFromHeader(ctx, header)
+		if err != nil {
+			s.logger.DebugContext(ctx, err.Error())
+			continue
+		}
+		if claims == nil {
+			// authService not present in header
+			continue
+		}
+		claimsFromAuth[aS.GetName()] = claims
+	}
+	verifiedAuthServices := make([]string, 0, len(claimsFromAuth))
+	for k := range claimsFromAuth {
+		verifiedAuthServices = append(verifiedAuthServices, k)
+	}
+	return claimsFromAuth, verifiedAuthServices
+}
+
+// artifactHandler serves a result that a tool spilled to a file. The request
+// must carry the signature of the download URL returned by the tool, and is
+// authorized the same way as invoking the tool.
+func artifactHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/artifact/get")
+	r = r.WithContext(ctx)
+	defer span.End()
+
+	artifactID := chi.URLParam(r, "artifactId")
+	span.SetAttributes(attribute.String("artifact_id", artifactID))
+	var err error
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}()
+
+	meta, f, err := s.artifacts.open(artifactID, r.URL.Query())
+	switch {
+	case errors.Is(err, errArtifactSignature):
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusForbidden))
+		return
+	case errors.Is(err, errArtifactNotFound):
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
+		return
+	case err != nil:
+		err = fmt.Errorf("unable to open artifact: %w", err)
+		s.logger.ErrorContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusInternalServerError))
+		return
+	}
+	defer f.Close()
+
+	tool, ok := s.ResourceMgr.GetTool(meta.Tool)
+	if !ok {
+		err = fmt.Errorf("tool %q of the artifact no longer exists", meta.Tool)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
+		return
+	}
+	if tool.RequiresClientAuthorization() && r.Header.Get("Authorization") == "" {
+		err = fmt.Errorf("tool requires client authorization but access token is missing from the request header")
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusUnauthorized))
+		return
+	}
+	_, verifiedAuthServices := s.authClaims(ctx, r.Header)
+	if !tool.Authorized(verifiedAuthServices) {
+		err = fmt.Errorf("artifact download not authorized. Please make sure your specify correct auth headers")
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusUnauthorized))
+		return
+	}
+
+	info, err := f.Stat()
 	if err != nil {
-		err = fmt.Errorf("unable to marshal result: %w", err)
+		err = fmt.Errorf("unable to open artifact: %w", err)
+		s.logger.ErrorContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusInternalServerError))
+		return
+	}
+	name := fmt.Sprintf("%s-%s.%s", meta.Tool, artifactID, meta.Format)
+	w.Header().Set("Content-Type", artifactContentTypes[meta.Format])
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	http.ServeContent(w, r, name, info.ModTime(), f)
+}
+
+// artifactUploadHandler stores the body of the request as an artifact, which
+// the arguments of tools can reference as `{"$resource": uri}` until it
+// expires. The uploader must be verified by one of the upload authServices,
+// and only the callers verified as the same subject by one of them can
+// reference it.
+func artifactUploadHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/artifact/put")
+	r = r.WithContext(ctx)
+	defer span.End()
+
+	var err error
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}()
+
+	claimsFromAuth, _ := s.authClaims(ctx, r.Header)
+	owners := make(map[string]string)
+	for _, name := range s.artifactUploadAuthServices {
+		if sub, ok := claimsFromAuth[name]["sub"].(string); ok && sub != "" {
+			owners[name] = sub
+		}
+	}
+	if len(owners) == 0 {
+		err = fmt.Errorf("artifact upload not authorized. Please make sure you specify the auth headers of one of the authServices %q", s.artifactUploadAuthServices)
 		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusUnauthorized))
+		return
+	}
+
+	body := r.Body
+	if s.artifactUploadMaxBytes > 0 {
+		body = http.MaxBytesReader(w, r.Body, s.artifactUploadMaxBytes)
+	}
+	artifact, err := s.artifacts.upload(body, owners)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			err = fmt.Errorf("artifact exceeds the maximum size of %d bytes", maxBytesErr.Limit)
+			s.logger.DebugContext(ctx, err.Error())
+			_ = render.Render(w, r, newErrResponse(err, http.StatusRequestEntityTooLarge))
+			return
+		}
+		err = fmt.Errorf("unable to store artifact: %w", err)
+		s.logger.ErrorContext(ctx, err.Error())
 		_ = render.Render(w, r, newErrResponse(err, http.StatusInternalServerError))
 		return
 	}
+	span.SetAttributes(attribute.String("artifact_id", artifact.ID))
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, artifact)
+}
+
+// sourceRotateHandler handles the admin request to rotate the credentials of
+// a source. The body holds the new credentials. An empty body makes the source
+// re-read its credentials from its configuration.
+func sourceRotateHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/source/rotate")
+	r = r.WithContext(ctx)
+	defer span.End()
+
+	sourceName := chi.URLParam(r, "sourceName")
+	span.SetAttributes(attribute.String("source_name", sourceName))
+	var err error
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}()
+
+	var status int
+	if status, err = s.authorizeAdmin(ctx, r, "source rotation"); err != nil {
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, status))
+		return
+	}
+
+	source, ok := s.ResourceMgr.GetSource(sourceName)
+	if !ok {
+		err = fmt.Errorf("source %q does not exist", sourceName)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
+		return
+	}
+	rs, ok := source.(sources.RotatableSource)
+	if !ok {
+		err = fmt.Errorf("source %q of kind %q does not support credential rotation", sourceName, source.SourceKind())
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		return
+	}
 
-	_ = render.Render(w, r, &resultResponse{Result: string(resMarshal)})
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		err = fmt.Errorf("unable to read request body: %w", err)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		return
+	}
+	var creds *sources.Credentials
+	if len(strings.TrimSpace(string(body))) > 0 {
+		creds = &sources.Credentials{}
+		if err = json.Unmarshal(body, creds); err != nil {
+			err = fmt.Errorf("request body was invalid JSON: %w", err)
+			s.logger.DebugContext(ctx, err.Error())
+			_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+			return
+		}
+	}
+
+	if err = rs.RotateCredentials(ctx, creds); err != nil {
+		err = fmt.Errorf("unable to rotate credentials of source %q: %w", sourceName, err)
+		s.logger.WarnContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		return
+	}
+	s.logger.InfoContext(ctx, fmt.Sprintf("rotated credentials of source %q", sourceName))
+	render.JSON(w, r, map[string]string{"result": "credentials rotated"})
 }
 
-var _ render.Renderer = &resultResponse{} // Renderer interface for managing response payloads.
+// resultBufferSize is the size of the start of a result that is buffered
+// before the status of the response is sent, so that a tools.RowIterator
+// failing within it fails the response.
+const resultBufferSize = 64 * 1024
 
-// resultResponse is the response sent back when the tool was invocated successfully.
-type resultResponse struct {
-	Result string `json:"result"` // result of tool invocation
+// resultWriter streams the result of a tool invocation to the client. The
+// payload is identical to rendering `{"result": <result as JSON string>}`,
+// but the result is encoded directly into the response instead of first being
+// marshaled into an intermediate string. If usage is set, the payload also
+// has a `_meta` field holding the usage metadata of the result, which is
+// counted while the result is encoded.
+//
+// The first resultBufferSize bytes of the result are buffered, and only sent
+// with the status once they are exceeded or the result is complete.
+type resultWriter struct {
+	w       http.ResponseWriter
+	started bool
+	buf     bytes.Buffer
+	usage   *tools.UsageCounter
+	// rows is the number of rows written.
+	rows int
 }
 
-// Render renders a single payload and respond to the client request.
-func (rr resultResponse) Render(w http.ResponseWriter, r *http.Request) error {
-	render.Status(r, http.StatusOK)
-	return nil
+// rowsError is the error of a tools.RowIterator, returned by writeResult to
+// tell the failures of the invocation from those of the encoding.
+type rowsError struct {
+	err error
+}
+
+func (e *rowsError) Error() string {
+	return e.err.Error()
+}
+
+func (e *rowsError) Unwrap() error {
+	return e.err
+}
+
+// writeResult encodes res into the response. If res is a tools.RowIterator,
+// its rows are encoded one at a time, and its errors are returned as a
+// *rowsError. Nothing is sent if it fails before the result is started.
+func (rw *resultWriter) writeResult(res any) error {
+	var err error
+	if it, ok := res.(tools.RowIterator); ok {
+		err = rw.writeRows(it)
+	} else {
+		err = json.NewEncoder(rw).Encode(res)
+		rw.rows = tools.RowCount(res)
+		if rw.usage != nil {
+			rw.usage.AddRows(rw.rows)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if err := rw.start(); err != nil {
+		return err
+	}
+	if rw.usage == nil {
+		_, err = io.WriteString(rw.w, "\"}\n")
+		return err
+	}
+	meta, err := json.Marshal(rw.usage.Metadata())
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(rw.w, "\",\"_meta\":%s}\n", meta)
+	return err
+}
+
+// writeRows encodes the rows of a tools.RowIterator as a JSON array.
+func (rw *resultWriter) writeRows(it tools.RowIterator) error {
+	defer it.Close()
+	enc := json.NewEncoder(rw)
+	first := true
+	for it.Next() {
+		row, err := it.Row()
+		if err != nil {
+			return &rowsError{err: err}
+		}
+		sep := ","
+		if first {
+			sep = "["
+			first = false
+		}
+		if _, err := io.WriteString(rw, sep); err != nil {
+			return err
+		}
+		if err := enc.Encode(row); err != nil {
+			return err
+		}
+		rw.rows++
+		if rw.usage != nil {
+			rw.usage.AddRows(1)
+		}
+	}
+	if err := it.Err(); err != nil {
+		return &rowsError{err: err}
+	}
+	// an empty result is encoded the same way as a nil slice
+	end := "]"
+	if first {
+		end = "null"
+	}
+	_, err := io.WriteString(rw, end)
+	return err
+}
+
+// writeError ends a started result with err, in an `error` field following
+// the truncated result, since the status can no longer be changed.
+func (rw *resultWriter) writeError(err error) {
+	msg, _ := json.Marshal(err.Error())
+	_, _ = fmt.Fprintf(rw.w, "\",\"error\":%s}\n", msg)
+}
+
+// start sends the status, the start of the payload and the buffered result,
+// unless they are already sent.
+func (rw *resultWriter) start() error {
+	if rw.started {
+		return nil
+	}
+	rw.started = true
+	rw.w.Header().Set("Content-Type", "application/json")
+	rw.w.WriteHeader(http.StatusOK)
+	if _, err := io.WriteString(rw.w, `{"result":"`); err != nil {
+		return err
+	}
+	_, err := rw.buf.WriteTo(rw.w)
+	return err
+}
+
+var (
+	escapedQuote     = []byte(`\"`)
+	escapedBackslash = []byte(`\\`)
+)
+
+// Write escapes p as the content of a JSON string, and buffers it or writes it
+// to the response once the buffer is full. Raw newlines are dropped: encoded
+// JSON only contains them as the trailing newline added by json.Encoder.
+func (rw *resultWriter) Write(p []byte) (int, error) {
+	if !rw.started && rw.buf.Len()+len(p) > resultBufferSize {
+		if err := rw.start(); err != nil {
+			return 0, err
+		}
+	}
+	if rw.usage != nil {
+		// the trailing newline added by json.Encoder is not part of the result
+		_, _ = rw.usage.Write(bytes.TrimRight(p, "\n"))
+	}
+	var w io.Writer = rw.w
+	if !rw.started {
+		w = &rw.buf
+	}
+	start := 0
+	for i, c := range p {
+		var esc []byte
+		switch {
+		case c == '\n':
+		case c == '"':
+			esc = escapedQuote
+		case c == '\\':
+			esc = escapedBackslash
+		case c < 0x20:
+			esc = fmt.Appendf(nil, `\u%04x`, c)
+		default:
+			continue
+		}
+		if _, err := w.Write(p[start:i]); err != nil {
+			return 0, err
+		}
+		if _, err := w.Write(esc); err != nil {
+			return 0, err
+		}
+		start = i + 1
+	}
+	if _, err := w.Write(p[start:]); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// withScheduleOverride marks the context as requesting to invoke tools outside
+// of their schedule if the `override` query parameter of r is true.
+func withScheduleOverride(ctx context.Context, r *http.Request) (context.Context, error) {
+	raw := r.URL.Query().Get("override")
+	if raw == "" {
+		return ctx, nil
+	}
+	override, err := strconv.ParseBool(raw)
+	if err != nil {
+		return ctx, fmt.Errorf("invalid 'override' query parameter %q: must be a boolean", raw)
+	}
+	if override {
+		ctx = util.WithScheduleOverride(ctx)
+	}
+	return ctx, nil
+}
+
+// withClientAttribution marks the context as forwarding the identity of the
+// caller of r to backends, if client attribution is enabled.
+func (s *Server) withClientAttribution(ctx context.Context, r *http.Request) context.Context {
+	if s.clientAttribution == "" {
+		return ctx
+	}
+	return util.WithClientAttribution(ctx, s.clientAttribution, r.Header.Get(tools.ClientNameHeader))
+}
+
+// httpInvocation returns what tools are invoked with by r, the access token
+// and the claims of the verified auth services of r, without parameters.
+func httpInvocation(r *http.Request, accessToken tools.AccessToken, claims map[string]map[string]any) tools.Invocation {
+	return tools.Invocation{
+		AccessToken: accessToken,
+		Claims:      claims,
+		Metadata:    tools.RequestMetadata{Protocol: "http", ClientName: r.Header.Get(tools.ClientNameHeader)},
+	}
 }
 
 var _ render.Renderer = &errResponse{} // Renderer interface for managing response payloads.
 
+// payloadError returns the error of a request body in a format that could not
+// be decoded because of err.
+func payloadError(format string, err error) error {
+	var limitErr *util.PayloadLimitError
+	if errors.As(err, &limitErr) {
+		return fmt.Errorf("request body was rejected: %w", err)
+	}
+	return fmt.Errorf("request body was invalid %s: %w", format, err)
+}
+
 // newErrResponse is a helper function initializing an ErrResponse
 func newErrResponse(err error, code int) *errResponse {
+	var paramErrs tools.ParamErrors
+	errors.As(err, &paramErrs)
+	var templateErr *tools.TemplateError
+	errors.As(err, &templateErr)
+	var budgetErr *tools.BudgetExceededError
+	errors.As(err, &budgetErr)
+	var tooLargeErr *tools.ResultTooLargeError
+	errors.As(err, &tooLargeErr)
+	var openErr *tools.CircuitOpenError
+	errors.As(err, &openErr)
+	var category string
+	var poolErr *tools.PoolExhaustedError
+	if errors.As(err, &poolErr) {
+		category = tools.CategoryPoolExhausted
+	}
+	var bulk *tools.BulkResult
+	var bulkErr *tools.BulkFailedError
+	if errors.As(err, &bulkErr) {
+		bulk = bulkErr.Result
+	}
 	return &errResponse{
 		Err:            err,
 		HTTPStatusCode: code,
 
 		StatusText: http.StatusText(code),
 		ErrorText:  err.Error(),
+		Errors:     paramErrs,
+		Template:   templateErr,
+		Budget:     budgetErr,
+		ResultSize: tooLargeErr,
+		Circuit:    openErr,
+		Category:   category,
+		Pool:       poolErr,
+		Bulk:       bulk,
+		Hint:       tools.ErrorHint(err),
 	}
 }
 
@@ -315,8 +998,36 @@ type errResponse struct {
 	Err            error `json:"-"` // low-level runtime error
 	HTTPStatusCode int   `json:"-"` // http response status code
 
-	StatusText string `json:"status"`          // user-level status message
-	ErrorText  string `json:"error,omitempty"` // application-level error message, for debugging
+	StatusText string            `json:"status"`           // user-level status message
+	ErrorText  string            `json:"error,omitempty"`  // application-level error message, for debugging
+	Errors     tools.ParamErrors `json:"errors,omitempty"` // invalid parameters, if any
+	// Template locates the template expression that failed to resolve, if
+	// any.
+	Template *tools.TemplateError `json:"template,omitempty"`
+	// Budget holds the estimate and the limit of a query rejected for being
+	// over the budget of its tool, if any.
+	Budget *tools.BudgetExceededError `json:"budget,omitempty"`
+	// ResultSize holds the size and the limit of a result aborted for being
+	// over the maxResultBytes of its tool, if any.
+	ResultSize *tools.ResultTooLargeError `json:"resultSize,omitempty"`
+	// Bulk holds the per-item errors of a bulk operation none of whose items
+	// succeeded, if any.
+	Bulk *tools.BulkResult `json:"bulk,omitempty"`
+	// Circuit holds the circuit breaker that rejected an invocation while
+	// open, if any.
+	Circuit *tools.CircuitOpenError `json:"circuitBreaker,omitempty"`
+	// Category is the category of the error, such as "pool_exhausted", if
+	// any.
+	Category string `json:"category,omitempty"`
+	// Pool holds the connections of the exhausted pool that an invocation
+	// failed to get a connection of, if any.
+	Pool *tools.PoolExhaustedError `json:"pool,omitempty"`
+	// AvailableAt is when a tool invoked outside of its schedule, or behind
+	// an open circuit breaker, or on an exhausted pool, becomes available
+	// again, formatted as RFC 3339.
+	AvailableAt string `json:"availableAt,omitempty"`
+	// Hint is an actionable hint on how to resolve the error, if any.
+	Hint string `json:"hint,omitempty"`
 }
 
 func (e *errResponse) Render(w http.ResponseWriter, r *http.Request) error {
@@ -16,14 +16,31 @@ package server
 
 import (
 	"bytes"
+	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/telemetry"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/utility/mock"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	_ "modernc.org/sqlite"
 )
 
 func TestToolsetEndpoint(t *testing.T) {
@@ -123,6 +140,76 @@ func TestToolsetEndpoint(t *testing.T) {
 	}
 }
 
+func TestToolsetEndpointTags(t *testing.T) {
+	toolsMap, toolsets := setUpTaggedResources(t)
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	testCases := []struct {
+		name       string
+		query      string
+		statusCode int
+		tools      map[string][]string
+	}{
+		{
+			name:       "no filter",
+			query:      "",
+			statusCode: http.StatusOK,
+			tools:      map[string][]string{tool1.Name: {"reporting"}, tool2.Name: {"reporting", "finance"}, tool3.Name: nil},
+		},
+		{
+			name:       "single tag",
+			query:      "?tags=finance",
+			statusCode: http.StatusOK,
+			tools:      map[string][]string{tool2.Name: {"reporting", "finance"}},
+		},
+		{
+			name:       "any of the tags",
+			query:      "?tags=reporting,audit",
+			statusCode: http.StatusOK,
+			tools:      map[string][]string{tool1.Name: {"reporting"}, tool2.Name: {"reporting", "finance"}},
+		},
+		{
+			name:       "no matching tool",
+			query:      "?tags=audit",
+			statusCode: http.StatusOK,
+			tools:      map[string][]string{},
+		},
+		{
+			name:       "invalid tag",
+			query:      "?tags=Finance",
+			statusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodGet, "/toolset/"+tc.query, nil, nil)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.statusCode {
+				t.Fatalf("unexpected status code: want %d, got %d: %s", tc.statusCode, resp.StatusCode, body)
+			}
+			if tc.statusCode != http.StatusOK {
+				return
+			}
+			var m tools.ToolsetManifest
+			if err := json.Unmarshal(body, &m); err != nil {
+				t.Fatalf("unable to parse ToolsetManifest: %s", err)
+			}
+			got := make(map[string][]string)
+			for name, manifest := range m.ToolsManifest {
+				got[name] = manifest.Tags
+			}
+			if !reflect.DeepEqual(got, tc.tools) {
+				t.Fatalf("unexpected tools: got %+v, want %+v", got, tc.tools)
+			}
+		})
+	}
+}
+
 func TestToolGetEndpoint(t *testing.T) {
 	mockTools := []MockTool{tool1, tool2}
 	toolsMap, toolsets := setUpResources(t, mockTools)
@@ -295,3 +382,1667 @@ func TestToolInvokeEndpoint(t *testing.T) {
 		})
 	}
 }
+
+func TestToolInvokeEndpointInvalidParams(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tool2.Name), bytes.NewBuffer([]byte(`{"param1": "one"}`)), nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("unexpected status code: got %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("unexpected error unmarshalling body: %s", err)
+	}
+	want := []any{
+		map[string]any{"name": "param1", "problem": `"one" not type "integer"`, "expected": "integer"},
+		map[string]any{"name": "param2", "problem": "is required", "expected": "integer"},
+	}
+	if !reflect.DeepEqual(got["errors"], want) {
+		t.Fatalf("unexpected errors: got %+v, want %+v", got["errors"], want)
+	}
+}
+
+func TestToolInvokeEndpointUsageMetadata(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	toolsMap[tool1.Name] = tools.UsageMetadataTool{Tool: tool1}
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	testCases := []struct {
+		name        string
+		toolName    string
+		requestBody string
+		want        string
+	}{
+		{
+			name:        "with usage metadata",
+			toolName:    tool1.Name,
+			requestBody: `{}`,
+			want:        `{"result":"[\"no_params\"]","_meta":{"bytes":13,"rows":1,"estimatedTokens":4}}` + "\n",
+		},
+		{
+			name:        "without usage metadata",
+			toolName:    tool2.Name,
+			requestBody: `{"param1": 1, "param2": 2}`,
+			want:        `{"result":"[\"some_params\"]"}` + "\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tc.toolName), bytes.NewBufferString(tc.requestBody), nil)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("response status code is not 200, got %d, %s", resp.StatusCode, string(body))
+			}
+			if got := string(body); got != tc.want {
+				t.Fatalf("unexpected value: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestToolAliasInvokeEndpoint(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	toolsMap["old_no_params"] = tools.AliasTool{Tool: toolsMap[tool1.Name], Name: "old_no_params", Target: tool1.Name}
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	for _, toolName := range []string{tool1.Name, "old_no_params"} {
+		t.Run(toolName, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", toolName), bytes.NewBuffer([]byte(`{}`)), nil)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("response status code is not 200, got %d, %s", resp.StatusCode, string(body))
+			}
+			want := `{"result":"[\"no_params\"]"}` + "\n"
+			if got := string(body); got != want {
+				t.Fatalf("unexpected value: got %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+// renderResult renders a result the same way as the previous resultResponse
+// renderer, for comparison with resultWriter.
+func renderResult(t testing.TB, res any) string {
+	b, err := json.Marshal(res)
+	if err != nil {
+		t.Fatalf("unable to marshal result: %s", err)
+	}
+	w := httptest.NewRecorder()
+	render.JSON(w, httptest.NewRequest(http.MethodPost, "/", nil), map[string]string{"result": string(b)})
+	return w.Body.String()
+}
+
+func TestResultWriter(t *testing.T) {
+	rows := []any{
+		map[string]any{"id": 1, "name": `quote " and backslash \`},
+		map[string]any{"id": 2, "name": "<html> & \n newline \t tab"},
+		map[string]any{"id": 3, "name": "unicode ✓  "},
+	}
+	testCases := []struct {
+		name string
+		res  any
+		want any
+	}{
+		{name: "nil", res: nil, want: nil},
+		{name: "string", res: "some result", want: "some result"},
+		{name: "rows", res: rows, want: rows},
+		{name: "iterator", res: tools.NewSliceRowIterator(rows), want: rows},
+		{name: "empty iterator", res: tools.NewSliceRowIterator(nil), want: []any(nil)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			rw := &resultWriter{w: w}
+			if err := rw.writeResult(tc.res); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if w.Code != http.StatusOK {
+				t.Fatalf("unexpected status code: got %d", w.Code)
+			}
+			if contentType := w.Header().Get("Content-Type"); contentType != "application/json" {
+				t.Fatalf("unexpected content-type header: got %s", contentType)
+			}
+			want := renderResult(t, tc.want)
+			if got := w.Body.String(); got != want {
+				t.Fatalf("unexpected body: got %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestResultWriterError(t *testing.T) {
+	w := httptest.NewRecorder()
+	rw := &resultWriter{w: w}
+	if err := rw.writeResult(map[string]any{"fn": func() {}}); err == nil {
+		t.Fatalf("expected error for unsupported type")
+	}
+	if rw.started {
+		t.Fatalf("response should not be started when encoding fails")
+	}
+}
+
+func TestResultWriterUsageMetadata(t *testing.T) {
+	rows := []any{
+		map[string]any{"id": 1, "name": `quote " and backslash \`},
+		map[string]any{"id": 2, "name": "unicode ✓"},
+	}
+	testCases := []struct {
+		name     string
+		res      any
+		want     any
+		wantRows int
+	}{
+		{name: "nil", res: nil, want: nil, wantRows: 0},
+		{name: "string", res: "some result", want: "some result", wantRows: 1},
+		{name: "rows", res: rows, want: rows, wantRows: 2},
+		{name: "iterator", res: tools.NewSliceRowIterator(rows), want: rows, wantRows: 2},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			rw := &resultWriter{w: w, usage: tools.NewUsageCounter(nil)}
+			if err := rw.writeResult(tc.res); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			var got struct {
+				Result string              `json:"result"`
+				Meta   tools.UsageMetadata `json:"_meta"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("unexpected error unmarshalling body %q: %s", w.Body.String(), err)
+			}
+			wantResult, err := json.Marshal(tc.want)
+			if err != nil {
+				t.Fatalf("unable to marshal result: %s", err)
+			}
+			if got.Result != string(wantResult) {
+				t.Fatalf("unexpected result: got %q, want %q", got.Result, wantResult)
+			}
+			want := tools.UsageMetadata{
+				Bytes:           len(wantResult),
+				Rows:            tc.wantRows,
+				EstimatedTokens: (len(wantResult) + 3) / 4,
+			}
+			if got.Meta != want {
+				t.Fatalf("unexpected usage metadata: got %+v, want %+v", got.Meta, want)
+			}
+		})
+	}
+}
+
+func syntheticRow(i int) any {
+	return map[string]any{"id": i, "name": fmt.Sprintf("row-%d", i), "value": float64(i) / 3}
+}
+
+// syntheticRowIterator generates rows on demand, similar to a database cursor.
+type syntheticRowIterator struct {
+	n, pos int
+}
+
+func (it *syntheticRowIterator) Next() bool {
+	it.pos++
+	return it.pos <= it.n
+}
+
+func (it *syntheticRowIterator) Row() (any, error) {
+	return syntheticRow(it.pos), nil
+}
+
+func (it *syntheticRowIterator) Err() error {
+	return nil
+}
+
+func (it *syntheticRowIterator) Close() {}
+
+// failingRowIterator is a syntheticRowIterator failing after failAt rows,
+// like a query failing midway.
+type failingRowIterator struct {
+	syntheticRowIterator
+	failAt int
+}
+
+func (it *failingRowIterator) Next() bool {
+	return it.syntheticRowIterator.Next() && it.pos <= it.failAt
+}
+
+func (it *failingRowIterator) Err() error {
+	if it.pos > it.failAt {
+		return errors.New("connection reset by peer")
+	}
+	return nil
+}
+
+// rowsTool is a MockTool returning the rows of a failingRowIterator.
+type rowsTool struct {
+	MockTool
+	n, failAt int
+}
+
+func (t rowsTool) Invoke(context.Context, tools.Invocation) (any, error) {
+	return &failingRowIterator{syntheticRowIterator: syntheticRowIterator{n: t.n}, failAt: t.failAt}, nil
+}
+
+func TestInvokeRowsFailing(t *testing.T) {
+	testCases := []struct {
+		name       string
+		failAt     int
+		wantStatus int
+	}{
+		// the rows failing within the buffered start of the result fail the
+		// response
+		{name: "within the buffer", failAt: 3, wantStatus: http.StatusBadRequest},
+		// once the result is sent, the error ends it
+		{name: "after the buffer", failAt: 10000, wantStatus: http.StatusOK},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+			toolsMap[tool1.Name] = rowsTool{MockTool: tool1, n: 20000, failAt: tc.failAt}
+			r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+			defer shutdown()
+			ts := runServer(r, false)
+			defer ts.Close()
+
+			resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tool1.Name), bytes.NewBuffer([]byte(`{}`)), nil)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.wantStatus {
+				t.Fatalf("unexpected status code: got %d, want %d", resp.StatusCode, tc.wantStatus)
+			}
+			var got struct {
+				Error string `json:"error"`
+			}
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unable to unmarshal body %q: %s", body, err)
+			}
+			if want := "error while invoking tool: connection reset by peer"; got.Error != want {
+				t.Fatalf("unexpected error: got %q, want %q", got.Error, want)
+			}
+		})
+	}
+}
+
+// discardResponseWriter is a http.ResponseWriter that discards the response
+// body, so that benchmarks only measure the memory used by the server.
+type discardResponseWriter struct {
+	header http.Header
+}
+
+func (w *discardResponseWriter) Header() http.Header {
+	if w.header == nil {
+		w.header = make(http.Header)
+	}
+	return w.header
+}
+
+func (w *discardResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (w *discardResponseWriter) WriteHeader(int) {}
+
+func BenchmarkWriteResult(b *testing.B) {
+	const numRows = 100000
+	b.Run("slice", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			// the rows are materialized, as by the tools that return a []any
+			rows, err := tools.CollectRows(&syntheticRowIterator{n: numRows})
+			if err != nil {
+				b.Fatalf("unexpected error: %s", err)
+			}
+			rw := &resultWriter{w: &discardResponseWriter{}}
+			if err := rw.writeResult(rows); err != nil {
+				b.Fatalf("unexpected error: %s", err)
+			}
+		}
+	})
+	b.Run("iterator", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			rw := &resultWriter{w: &discardResponseWriter{}}
+			if err := rw.writeResult(&syntheticRowIterator{n: numRows}); err != nil {
+				b.Fatalf("unexpected error: %s", err)
+			}
+		}
+	})
+}
+
+// blockingTool is a MockTool whose invocations block until release is closed.
+type blockingTool struct {
+	MockTool
+	started chan struct{}
+	release chan struct{}
+}
+
+func (t blockingTool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	t.started <- struct{}{}
+	<-t.release
+	return t.MockTool.Invoke(ctx, inv)
+}
+
+func TestSerializedToolSharedAcrossEndpoints(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	blocking := blockingTool{MockTool: tool1, started: make(chan struct{}, 1), release: make(chan struct{})}
+	toolsMap[tool1.Name] = tools.NewSerializedTool(blocking, 100*time.Millisecond)
+
+	apiR, apiShutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer apiShutdown()
+	apiServer := runServer(apiR, false)
+	defer apiServer.Close()
+	mcpR, mcpShutdown := setUpServer(t, "mcp", toolsMap, toolsets)
+	defer mcpShutdown()
+	mcpServer := runServer(mcpR, false)
+	defer mcpServer.Close()
+
+	invokePath := fmt.Sprintf("/tool/%s/invoke", tool1.Name)
+	firstStatus := make(chan int, 1)
+	go func() {
+		resp, _, err := runRequest(apiServer, http.MethodPost, invokePath, bytes.NewBuffer([]byte(`{}`)), nil)
+		if err != nil {
+			t.Errorf("unexpected error during request: %s", err)
+			firstStatus <- 0
+			return
+		}
+		firstStatus <- resp.StatusCode
+	}()
+	<-blocking.started
+
+	// the HTTP endpoint reports the busy tool with a 503
+	resp, body, err := runRequest(apiServer, http.MethodPost, invokePath, bytes.NewBuffer([]byte(`{}`)), nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("unexpected status code: got %d, want %d, %s", resp.StatusCode, http.StatusServiceUnavailable, string(body))
+	}
+
+	// the MCP endpoint waits on the same lock
+	callTool := func(name string) map[string]any {
+		reqBody := fmt.Sprintf(`{"jsonrpc":"2.0","id":"tools-call","method":"tools/call","params":{"name":%q,"arguments":{"param1":1,"param2":2}}}`, name)
+		_, body, err := runRequest(mcpServer, http.MethodPost, "/", bytes.NewBufferString(reqBody), nil)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		var got map[string]any
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Fatalf("unexpected error unmarshalling body: %s", err)
+		}
+		result, ok := got["result"].(map[string]any)
+		if !ok {
+			t.Fatalf("unexpected response: %s", string(body))
+		}
+		return result
+	}
+	result := callTool(tool1.Name)
+	if result["isError"] != true || !strings.Contains(fmt.Sprint(result["content"]), tools.ErrToolBusy.Error()) {
+		t.Fatalf("expected tool busy error, got %v", result)
+	}
+
+	// other tools are unaffected
+	result = callTool(tool2.Name)
+	if result["isError"] == true {
+		t.Fatalf("unexpected error calling %q: %v", tool2.Name, result)
+	}
+
+	close(blocking.release)
+	if status := <-firstStatus; status != http.StatusOK {
+		t.Fatalf("unexpected status code for first invocation: got %d, want %d", status, http.StatusOK)
+	}
+}
+
+// poolSource is a source with a database/sql pool.
+type poolSource struct {
+	monitor *sources.PoolMonitor
+}
+
+func (poolSource) SourceKind() string {
+	return "pool"
+}
+
+func (s poolSource) PoolMonitor() *sources.PoolMonitor {
+	return s.monitor
+}
+
+// queryTool is a MockTool querying a database/sql pool, with a query timeout.
+type queryTool struct {
+	MockTool
+	db *sql.DB
+}
+
+func (t queryTool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	var v int
+	if err := t.db.QueryRowContext(ctx, "SELECT 1").Scan(&v); err != nil {
+		return nil, fmt.Errorf("unable to execute query: %w", err)
+	}
+	return v, nil
+}
+
+func TestPoolExhaustedTool(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("unable to open database: %s", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	monitor := sources.NewSQLPoolMonitor(db)
+	toolsMap[tool1.Name] = tools.PoolExhaustionTool{
+		Tool:       queryTool{MockTool: tool1, db: db},
+		SourceName: "my-db",
+		Source:     poolSource{monitor: monitor},
+	}
+	monitor.RecordInvocation(1500 * time.Millisecond)
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	// the only connection is held, as by a slow query
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatalf("unable to get a connection: %s", err)
+	}
+	invokePath := fmt.Sprintf("/tool/%s/invoke", tool1.Name)
+	resp, body, err := runRequest(ts, http.MethodPost, invokePath, bytes.NewBuffer([]byte(`{}`)), nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("unexpected status code: got %d, want %d, %s", resp.StatusCode, http.StatusServiceUnavailable, string(body))
+	}
+	if got := resp.Header.Get("Retry-After"); got != "2" {
+		t.Fatalf("unexpected Retry-After header: got %q, want %q", got, "2")
+	}
+	var got struct {
+		Category    string          `json:"category"`
+		Pool        json.RawMessage `json:"pool"`
+		AvailableAt string          `json:"availableAt"`
+	}
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("unexpected error unmarshalling body: %s", err)
+	}
+	if got.Category != "pool_exhausted" {
+		t.Fatalf("unexpected category: got %q, want %q", got.Category, "pool_exhausted")
+	}
+	if want := `{"source":"my-db","inUse":1,"idle":0,"max":1}`; string(got.Pool) != want {
+		t.Fatalf("unexpected pool: got %s, want %s", got.Pool, want)
+	}
+	if got.AvailableAt == "" {
+		t.Fatalf("expected availableAt to be set: %s", string(body))
+	}
+
+	// invocations succeed again once the connection is released
+	if err := conn.Close(); err != nil {
+		t.Fatalf("unable to release the connection: %s", err)
+	}
+	resp, body, err = runRequest(ts, http.MethodPost, invokePath, bytes.NewBuffer([]byte(`{}`)), nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: got %d, want %d, %s", resp.StatusCode, http.StatusOK, string(body))
+	}
+}
+
+func TestScheduledToolOutsideOfSchedule(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	schedule, err := tools.NewSchedule(tools.ScheduleSpec{
+		Timezone:             "UTC",
+		Windows:              []tools.ScheduleWindow{{Days: "Sat,Sun"}},
+		OverrideAuthServices: []string{"dba-auth"},
+	})
+	if err != nil {
+		t.Fatalf("unable to create schedule: %s", err)
+	}
+	// Monday 12:00 UTC
+	now := time.Date(2025, time.June, 2, 12, 0, 0, 0, time.UTC)
+	toolsMap[tool1.Name] = tools.NewScheduledToolWithClock(tool1, schedule, func() time.Time { return now })
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	testCases := []struct {
+		name     string
+		query    string
+		wantCode int
+		want     string
+	}{
+		{
+			name:     "outside of schedule",
+			wantCode: http.StatusServiceUnavailable,
+			want:     "tool unavailable until Sat 00:00 UTC",
+		},
+		{
+			name:     "unauthorized override",
+			query:    "?override=true",
+			wantCode: http.StatusUnauthorized,
+			want:     "overriding the schedule",
+		},
+		{
+			name:     "invalid override",
+			query:    "?override=maybe",
+			wantCode: http.StatusBadRequest,
+			want:     "invalid 'override' query parameter",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke%s", tool1.Name, tc.query), bytes.NewBuffer([]byte(`{}`)), nil)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.wantCode {
+				t.Fatalf("unexpected status code: got %d, want %d, %s", resp.StatusCode, tc.wantCode, string(body))
+			}
+			if !strings.Contains(string(body), tc.want) {
+				t.Fatalf("unexpected body: got %s, want substring %q", string(body), tc.want)
+			}
+		})
+	}
+
+	// the time at which the tool becomes available is reported
+	_, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tool1.Name), bytes.NewBuffer([]byte(`{}`)), nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("unexpected error unmarshalling body: %s", err)
+	}
+	if want := "2025-06-07T00:00:00Z"; got["availableAt"] != want {
+		t.Fatalf("unexpected availableAt: got %v, want %q", got["availableAt"], want)
+	}
+}
+
+func TestToolDeniedByPreInvokeWebhook(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	approval := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"decision": "deny", "reason": "outside of the change window"}`))
+	}))
+	defer approval.Close()
+	webhook, err := tools.NewPreInvokeWebhook(tools.PreInvokeWebhookSpec{URL: approval.URL, Secret: "s3cr3t"})
+	if err != nil {
+		t.Fatalf("unable to create webhook: %s", err)
+	}
+	toolsMap[tool2.Name] = tools.PreInvokeWebhookTool{Tool: tool2, Webhook: webhook}
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tool2.Name), bytes.NewBuffer([]byte(`{"param1": 1, "param2": 2}`)), nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("unexpected status code: got %d, want %d, %s", resp.StatusCode, http.StatusForbidden, string(body))
+	}
+	if want := "denied by policy: outside of the change window"; !strings.Contains(string(body), want) {
+		t.Fatalf("unexpected body: got %s, want substring %q", string(body), want)
+	}
+}
+
+// setUpDisabledResources is like setUpResources, but the kind of tool2 is
+// disabled. The toolsets hide it if hide is set.
+func setUpDisabledResources(t *testing.T, hide bool) (map[string]tools.Tool, map[string]tools.Toolset) {
+	toolsMap := map[string]tools.Tool{
+		tool1.Name: tool1,
+		tool2.Name: tools.DisabledTool{Tool: tool2, Kind: "mock-execute-sql"},
+	}
+	tc := tools.ToolsetConfig{Name: "", ToolNames: []string{tool1.Name, tool2.Name}}
+	toolset, err := tc.Initialize(fakeVersionString, toolsMap)
+	if err != nil {
+		t.Fatalf("unable to initialize toolset: %s", err)
+	}
+	if hide {
+		toolset = toolset.WithoutDisabled()
+	}
+	return toolsMap, map[string]tools.Toolset{"": toolset}
+}
+
+func TestDisabledToolKind(t *testing.T) {
+	for _, hide := range []bool{false, true} {
+		t.Run(fmt.Sprintf("hide %t", hide), func(t *testing.T) {
+			toolsMap, toolsets := setUpDisabledResources(t, hide)
+			r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+			defer shutdown()
+			ts := runServer(r, false)
+			defer ts.Close()
+
+			_, body, err := runRequest(ts, http.MethodGet, "/toolset/", nil, nil)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			var m tools.ToolsetManifest
+			if err := json.Unmarshal(body, &m); err != nil {
+				t.Fatalf("unable to parse ToolsetManifest: %s", err)
+			}
+			if m.ToolsManifest[tool1.Name].Disabled {
+				t.Errorf("%q is marked as disabled", tool1.Name)
+			}
+			disabled, ok := m.ToolsManifest[tool2.Name]
+			if hide && ok {
+				t.Errorf("%q is listed, want it hidden", tool2.Name)
+			}
+			if !hide && !disabled.Disabled {
+				t.Errorf("%q is not marked as disabled: %s", tool2.Name, body)
+			}
+
+			resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tool2.Name), bytes.NewBuffer([]byte(`{"param1": 1, "param2": 2}`)), nil)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != http.StatusForbidden {
+				t.Fatalf("unexpected status code: got %d, want %d, %s", resp.StatusCode, http.StatusForbidden, string(body))
+			}
+			if want := "tool kind disabled by operator"; !strings.Contains(string(body), want) {
+				t.Fatalf("unexpected body: got %s, want substring %q", string(body), want)
+			}
+
+			// tools of other kinds are unaffected
+			resp, body, err = runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tool1.Name), bytes.NewBuffer([]byte(`{}`)), nil)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("unexpected status code: got %d, want %d, %s", resp.StatusCode, http.StatusOK, string(body))
+			}
+		})
+	}
+}
+
+// hintedErrorTool is a MockTool whose invocations fail with a hint.
+type hintedErrorTool struct {
+	MockTool
+}
+
+func (t hintedErrorTool) Invoke(context.Context, tools.Invocation) (any, error) {
+	return nil, &tools.HintError{Err: errors.New("Table not found: xyz"), Hint: "run SHOW DATABASES"}
+}
+
+func TestToolErrorHint(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	toolsMap[tool1.Name] = hintedErrorTool{MockTool: tool1}
+
+	apiR, apiShutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer apiShutdown()
+	apiServer := runServer(apiR, false)
+	defer apiServer.Close()
+	mcpR, mcpShutdown := setUpServer(t, "mcp", toolsMap, toolsets)
+	defer mcpShutdown()
+	mcpServer := runServer(mcpR, false)
+	defer mcpServer.Close()
+
+	// the HTTP endpoint reports the hint in its own field
+	_, body, err := runRequest(apiServer, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tool1.Name), bytes.NewBuffer([]byte(`{}`)), nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("unexpected error unmarshalling body: %s", err)
+	}
+	if got["hint"] != "run SHOW DATABASES" || !strings.Contains(fmt.Sprint(got["error"]), "Table not found: xyz") {
+		t.Fatalf("unexpected response: %s", string(body))
+	}
+
+	// the MCP endpoint reports the hint alongside the original message
+	reqBody := fmt.Sprintf(`{"jsonrpc":"2.0","id":"tools-call","method":"tools/call","params":{"name":%q,"arguments":{"param1":1,"param2":2}}}`, tool1.Name)
+	_, body, err = runRequest(mcpServer, http.MethodPost, "/", bytes.NewBufferString(reqBody), nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	var resp struct {
+		Result struct {
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+			IsError bool `json:"isError"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		t.Fatalf("unexpected error unmarshalling body: %s", err)
+	}
+	if !resp.Result.IsError || len(resp.Result.Content) != 1 {
+		t.Fatalf("unexpected response: %s", string(body))
+	}
+	var text map[string]string
+	if err := json.Unmarshal([]byte(resp.Result.Content[0].Text), &text); err != nil {
+		t.Fatalf("unexpected error unmarshalling content: %s", err)
+	}
+	want := map[string]string{"error": "Table not found: xyz", "hint": "run SHOW DATABASES"}
+	if !reflect.DeepEqual(want, text) {
+		t.Fatalf("unexpected content: got %v, want %v", text, want)
+	}
+}
+
+// identityTool returns the client identity it is invoked with.
+type identityTool struct {
+	MockTool
+}
+
+func (t identityTool) Invoke(ctx context.Context, _ tools.Invocation) (any, error) {
+	return util.ClientIdentityFromContext(ctx), nil
+}
+
+func TestClientAttribution(t *testing.T) {
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+	identity := identityTool{MockTool: MockTool{Name: "identity"}}
+	toolsMap := map[string]tools.Tool{"identity": identity, tool1.Name: tool1}
+	tc := tools.ToolsetConfig{Name: "", ToolNames: []string{"identity", tool1.Name}}
+	toolset, err := tc.Initialize(fakeVersionString, toolsMap)
+	if err != nil {
+		t.Fatalf("unable to initialize toolset: %s", err)
+	}
+	authServices := map[string]auth.AuthService{"admin": fakeAuthService{name: "admin"}}
+
+	tcs := []struct {
+		desc        string
+		attribution string
+		header      map[string]string
+		want        string
+	}{
+		{desc: "disabled", header: map[string]string{"X-Toolbox-Client-Name": "agent", "admin_token": "valid"}, want: ""},
+		{desc: "header", attribution: "header", header: map[string]string{"X-Toolbox-Client-Name": "Billing Agent@v2"}, want: "billing_agent_v2"},
+		{desc: "header missing", attribution: "header", want: ""},
+		{desc: "auth", attribution: "auth", header: map[string]string{"X-Toolbox-Client-Name": "agent", "admin_token": "valid"}, want: "admin"},
+		{desc: "auth unverified", attribution: "auth", header: map[string]string{"X-Toolbox-Client-Name": "agent"}, want: ""},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			s := &Server{
+				version:           fakeVersionString,
+				logger:            testLogger,
+				instrumentation:   instrumentation,
+				clientAttribution: tc.attribution,
+				sseManager:        newSseManager(context.Background()),
+				ResourceMgr:       NewResourceManager(nil, authServices, toolsMap, map[string]tools.Toolset{"": toolset}),
+			}
+			apiR, err := apiRouter(s)
+			if err != nil {
+				t.Fatalf("unable to initialize api router: %s", err)
+			}
+			mcpR, err := mcpRouter(s)
+			if err != nil {
+				t.Fatalf("unable to initialize mcp router: %s", err)
+			}
+			r := chi.NewRouter()
+			r.Mount("/api", apiR)
+			r.Mount("/mcp", mcpR)
+			ts := runServer(r, false)
+			defer ts.Close()
+
+			resp, body, err := runRequest(ts, http.MethodPost, "/api/tool/identity/invoke", bytes.NewBufferString(`{}`), tc.header)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, http.StatusOK, body)
+			}
+			var got map[string]any
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unexpected error unmarshalling body: %s", err)
+			}
+			// the result of the tool is JSON encoded
+			wantResult := fmt.Sprintf("%q", tc.want)
+			if got["result"] != wantResult {
+				t.Fatalf("unexpected api identity: got %v, want %s", got["result"], wantResult)
+			}
+
+			mcpBody := bytes.NewBufferString(`{"jsonrpc":"2.0","id":"1","method":"tools/call","params":{"name":"identity","arguments":{}}}`)
+			resp, body, err = runRequest(ts, http.MethodPost, "/mcp/", mcpBody, tc.header)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, http.StatusOK, body)
+			}
+			if want := fmt.Sprintf(`"text":%q`, wantResult); !strings.Contains(string(body), want) {
+				t.Fatalf("unexpected mcp response: got %s, want it to contain %s", body, want)
+			}
+		})
+	}
+}
+
+// invocationTool requires client authorization, and returns what it was
+// invoked with.
+type invocationTool struct {
+	MockTool
+}
+
+func (t invocationTool) Invoke(_ context.Context, inv tools.Invocation) (any, error) {
+	return map[string]any{
+		"accessToken": string(inv.AccessToken),
+		"claims":      inv.Claims,
+		"metadata":    inv.Metadata,
+		"params":      inv.Params.AsMap(),
+	}, nil
+}
+
+func TestToolInvocation(t *testing.T) {
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+	tool := invocationTool{MockTool: MockTool{
+		Name:                         "invocation",
+		Params:                       []tools.Parameter{tools.NewStringParameter("name", "some description")},
+		requiresClientAuthrorization: true,
+	}}
+	toolsMap := map[string]tools.Tool{"invocation": tool}
+	tc := tools.ToolsetConfig{Name: "", ToolNames: []string{"invocation"}}
+	toolset, err := tc.Initialize(fakeVersionString, toolsMap)
+	if err != nil {
+		t.Fatalf("unable to initialize toolset: %s", err)
+	}
+	s := &Server{
+		version:         fakeVersionString,
+		logger:          testLogger,
+		instrumentation: instrumentation,
+		sseManager:      newSseManager(context.Background()),
+		ResourceMgr:     NewResourceManager(nil, map[string]auth.AuthService{"admin": fakeAuthService{name: "admin"}}, toolsMap, map[string]tools.Toolset{"": toolset}),
+	}
+	apiR, err := apiRouter(s)
+	if err != nil {
+		t.Fatalf("unable to initialize api router: %s", err)
+	}
+	mcpR, err := mcpRouter(s)
+	if err != nil {
+		t.Fatalf("unable to initialize mcp router: %s", err)
+	}
+	r := chi.NewRouter()
+	r.Mount("/api", apiR)
+	r.Mount("/mcp", mcpR)
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	header := map[string]string{"Authorization": "Bearer client-token", "X-Toolbox-Client-Name": "agent", "admin_token": "valid"}
+	want := func(protocol string) map[string]any {
+		return map[string]any{
+			"accessToken": "Bearer client-token",
+			"claims":      map[string]any{"admin": map[string]any{"sub": "admin"}},
+			"metadata":    map[string]any{"Protocol": protocol, "ClientName": "agent"},
+			"params":      map[string]any{"name": "Alice"},
+		}
+	}
+
+	resp, body, err := runRequest(ts, http.MethodPost, "/api/tool/invocation/invoke", bytes.NewBufferString(`{"name": "Alice"}`), header)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, http.StatusOK, body)
+	}
+	var apiResp struct {
+		Result string `json:"result"`
+	}
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		t.Fatalf("unexpected error unmarshalling body: %s", err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal([]byte(apiResp.Result), &got); err != nil {
+		t.Fatalf("unexpected error unmarshalling result: %s", err)
+	}
+	if w := want("http"); !reflect.DeepEqual(got, w) {
+		t.Fatalf("unexpected api invocation: got %v, want %v", got, w)
+	}
+
+	mcpBody := bytes.NewBufferString(`{"jsonrpc":"2.0","id":"1","method":"tools/call","params":{"name":"invocation","arguments":{"name":"Alice"}}}`)
+	resp, body, err = runRequest(ts, http.MethodPost, "/mcp/", mcpBody, header)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, http.StatusOK, body)
+	}
+	var mcpResp struct {
+		Result struct {
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(body, &mcpResp); err != nil {
+		t.Fatalf("unexpected error unmarshalling body: %s", err)
+	}
+	if len(mcpResp.Result.Content) != 1 {
+		t.Fatalf("unexpected mcp response: %s", body)
+	}
+	got = nil
+	if err := json.Unmarshal([]byte(mcpResp.Result.Content[0].Text), &got); err != nil {
+		t.Fatalf("unexpected error unmarshalling result: %s", err)
+	}
+	if w := want("mcp"); !reflect.DeepEqual(got, w) {
+		t.Fatalf("unexpected mcp invocation: got %v, want %v", got, w)
+	}
+
+	// the tool is not invoked without an access token
+	delete(header, "Authorization")
+	resp, body, err = runRequest(ts, http.MethodPost, "/api/tool/invocation/invoke", bytes.NewBufferString(`{"name": "Alice"}`), header)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, http.StatusUnauthorized, body)
+	}
+}
+
+func TestMockToolEndpoints(t *testing.T) {
+	cfg := mock.Config{
+		Name:        "weather",
+		Kind:        "mock",
+		Description: "Returns the weather of a city.",
+		Parameters:  tools.Parameters{tools.NewStringParameter("city", "the city")},
+		Responses: []mock.Response{
+			{Match: map[string]any{"city": "Paris"}, Result: []any{map[string]any{"temperature": 21}}},
+			{Match: map[string]any{"city": "Atlantis"}, Error: "city not found", StatusCode: http.StatusNotFound},
+		},
+		Default: &mock.Response{Result: []any{map[string]any{"temperature": nil}}},
+	}
+	weather, err := cfg.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize mock tool: %s", err)
+	}
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	toolsMap[cfg.Name] = weather
+
+	apiR, apiShutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer apiShutdown()
+	apiServer := runServer(apiR, false)
+	defer apiServer.Close()
+	mcpR, mcpShutdown := setUpServer(t, "mcp", toolsMap, toolsets)
+	defer mcpShutdown()
+	mcpServer := runServer(mcpR, false)
+	defer mcpServer.Close()
+
+	invokeTcs := []struct {
+		city       string
+		wantStatus int
+		wantBody   string
+	}{
+		{city: "Paris", wantStatus: http.StatusOK, wantBody: `{"result":"[{\"temperature\":21}]"}`},
+		{city: "Oslo", wantStatus: http.StatusOK, wantBody: `{"result":"[{\"temperature\":null}]"}`},
+		{city: "Atlantis", wantStatus: http.StatusNotFound, wantBody: `{"status":"Not Found","error":"error while invoking tool: city not found"}`},
+	}
+	for _, tc := range invokeTcs {
+		t.Run("invoke "+tc.city, func(t *testing.T) {
+			resp, body, err := runRequest(apiServer, http.MethodPost, "/tool/weather/invoke", bytes.NewBufferString(fmt.Sprintf(`{"city": %q}`, tc.city)), nil)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.wantStatus {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, tc.wantStatus, body)
+			}
+			if got := strings.TrimSpace(string(body)); got != tc.wantBody {
+				t.Fatalf("unexpected body: got %s, want %s", got, tc.wantBody)
+			}
+		})
+	}
+
+	mcpTcs := []struct {
+		city        string
+		wantText    string
+		wantIsError bool
+	}{
+		{city: "Paris", wantText: `{"temperature":21}`},
+		{city: "Atlantis", wantText: "city not found", wantIsError: true},
+	}
+	for _, tc := range mcpTcs {
+		t.Run("mcp "+tc.city, func(t *testing.T) {
+			reqBody := fmt.Sprintf(`{"jsonrpc":"2.0","id":"tools-call","method":"tools/call","params":{"name":"weather","arguments":{"city":%q}}}`, tc.city)
+			_, body, err := runRequest(mcpServer, http.MethodPost, "/", bytes.NewBufferString(reqBody), nil)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			var resp struct {
+				Result struct {
+					Content []struct {
+						Text string `json:"text"`
+					} `json:"content"`
+					IsError bool `json:"isError"`
+				} `json:"result"`
+			}
+			if err := json.Unmarshal(body, &resp); err != nil {
+				t.Fatalf("unexpected error unmarshalling body: %s", err)
+			}
+			if resp.Result.IsError != tc.wantIsError || len(resp.Result.Content) != 1 || resp.Result.Content[0].Text != tc.wantText {
+				t.Fatalf("unexpected response: %s", body)
+			}
+		})
+	}
+}
+
+// echoErrorTool is a MockTool whose invocations fail with an error quoting
+// the statement they would run.
+type echoErrorTool struct {
+	MockTool
+}
+
+func (t echoErrorTool) Invoke(_ context.Context, inv tools.Invocation) (any, error) {
+	m := inv.Params.AsMap()
+	return nil, fmt.Errorf("unable to execute query: SELECT id FROM users WHERE email = '%s' AND password = '%s'", m["email"], m["password"])
+}
+
+func TestSensitiveParamsRedactedFromErrors(t *testing.T) {
+	password := tools.NewStringParameter("password", "the password of the user")
+	password.Sensitive = true
+	login := echoErrorTool{MockTool: MockTool{
+		Name:   "login",
+		Params: tools.Parameters{tools.NewStringParameter("email", "the email of the user"), password},
+	}}
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	toolsMap[login.Name] = login
+
+	apiR, apiShutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer apiShutdown()
+	apiServer := runServer(apiR, false)
+	defer apiServer.Close()
+	mcpR, mcpShutdown := setUpServer(t, "mcp", toolsMap, toolsets)
+	defer mcpShutdown()
+	mcpServer := runServer(mcpR, false)
+	defer mcpServer.Close()
+
+	wantMsg := "unable to execute query: SELECT id FROM users WHERE email = 'jane@example.com' AND password = '[REDACTED]'"
+
+	resp, body, err := runRequest(apiServer, http.MethodPost, "/tool/login/invoke", bytes.NewBufferString(`{"email": "jane@example.com", "password": "hunter2"}`), nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, http.StatusBadRequest, body)
+	}
+	if got, want := strings.TrimSpace(string(body)), fmt.Sprintf(`{"status":"Bad Request","error":"error while invoking tool: %s"}`, wantMsg); got != want {
+		t.Fatalf("unexpected body: got %s, want %s", got, want)
+	}
+
+	reqBody := `{"jsonrpc":"2.0","id":"tools-call","method":"tools/call","params":{"name":"login","arguments":{"email":"jane@example.com","password":"hunter2"}}}`
+	_, body, err = runRequest(mcpServer, http.MethodPost, "/", bytes.NewBufferString(reqBody), nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if strings.Contains(string(body), "hunter2") {
+		t.Fatalf("response leaks the password: %s", body)
+	}
+	if !strings.Contains(string(body), wantMsg) {
+		t.Fatalf("unexpected response: got %s, want it to contain %q", body, wantMsg)
+	}
+}
+
+type renderTool struct {
+	MockTool
+	statement      string
+	templateParams tools.Parameters
+}
+
+func (t renderTool) ParseParams(data map[string]any, claimsMap map[string]map[string]any) (tools.ParamValues, error) {
+	allParams := append(slices.Clone(t.templateParams), t.Params...)
+	return tools.ParseParams(allParams, data, claimsMap)
+}
+
+func (t renderTool) Render(params tools.ParamValues) (tools.RenderedStatement, error) {
+	return tools.RenderStatement(t.statement, t.templateParams, t.Params, params)
+}
+
+func TestToolRenderEndpoint(t *testing.T) {
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+	search := renderTool{
+		MockTool: MockTool{
+			Name:   "search",
+			Params: tools.Parameters{tools.NewStringParameter("name", "the name of the user")},
+		},
+		statement:      "SELECT * FROM {{.tableName}} WHERE name = $1",
+		templateParams: tools.Parameters{tools.NewStringParameter("tableName", "the table to search")},
+	}
+	broken := renderTool{
+		MockTool:  MockTool{Name: "broken"},
+		statement: "SELECT *\nFROM {{.tableName .wrong}}",
+	}
+	toolsMap := map[string]tools.Tool{search.Name: search, broken.Name: broken, tool1.Name: tool1}
+
+	tcs := []struct {
+		desc       string
+		dev        bool
+		method     string
+		tool       string
+		body       string
+		wantStatus int
+		want       string
+	}{
+		{
+			desc:       "valid",
+			dev:        true,
+			method:     http.MethodPost,
+			tool:       "search",
+			body:       `{"tableName": "users", "name": "Alice"}`,
+			wantStatus: http.StatusOK,
+			want:       `{"statement":"SELECT * FROM users WHERE name = $1","params":[{"name":"name","value":"Alice"}]}`,
+		},
+		{
+			desc:       "template error",
+			dev:        true,
+			method:     http.MethodGet,
+			tool:       "broken",
+			wantStatus: http.StatusBadRequest,
+			want:       `{"status":"Bad Request","error":"unable to render statement: template expression {{.tableName .wrong}} at line 2, column 6 failed: error executing go template template: statement:2:7: executing \"statement\" at \u003c.tableName\u003e: tableName is not a method but has arguments","template":{"line":2,"column":6,"expression":"{{.tableName .wrong}}"}}`,
+		},
+		{
+			desc:       "invalid params",
+			dev:        true,
+			method:     http.MethodPost,
+			tool:       "search",
+			body:       `{"tableName": "users"}`,
+			wantStatus: http.StatusBadRequest,
+			want:       `{"status":"Bad Request","error":"provided parameters were invalid: parameter \"name\" is required","errors":[{"name":"name","problem":"is required","expected":"string"}]}`,
+		},
+		{
+			desc:       "no statement",
+			dev:        true,
+			method:     http.MethodPost,
+			tool:       tool1.Name,
+			body:       `{}`,
+			wantStatus: http.StatusBadRequest,
+			want:       `{"status":"Bad Request","error":"tool \"no_params\" does not run a statement that can be rendered"}`,
+		},
+		{
+			desc:       "not dev",
+			method:     http.MethodPost,
+			tool:       "search",
+			body:       `{"tableName": "users", "name": "Alice"}`,
+			wantStatus: http.StatusNotFound,
+			want:       `{"status":"Not Found","error":"rendering tools is only available when the server runs with --dev"}`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			s := &Server{
+				version:         fakeVersionString,
+				logger:          testLogger,
+				instrumentation: instrumentation,
+				dev:             tc.dev,
+				sseManager:      newSseManager(context.Background()),
+				ResourceMgr:     NewResourceManager(nil, nil, toolsMap, nil),
+			}
+			r, err := apiRouter(s)
+			if err != nil {
+				t.Fatalf("unable to initialize api router: %s", err)
+			}
+			ts := runServer(r, false)
+			defer ts.Close()
+
+			resp, body, err := runRequest(ts, tc.method, fmt.Sprintf("/tool/%s/render", tc.tool), bytes.NewBufferString(tc.body), nil)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.wantStatus {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, tc.wantStatus, body)
+			}
+			if got := strings.TrimSpace(string(body)); got != tc.want {
+				t.Fatalf("unexpected body: got %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestToolsetEndpointDescriptionOverrides(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	tc := tools.ToolsetConfig{
+		Name:      "ja",
+		ToolNames: []string{tool1.Name, tool2.Name},
+		DescriptionOverrides: map[string]tools.DescriptionOverride{
+			tool2.Name: {
+				Description: "いくつかのパラメータを持つツール",
+				Parameters:  map[string]string{"param1": "最初のパラメータ"},
+			},
+		},
+	}
+	toolset, err := tc.Initialize(fakeVersionString, toolsMap)
+	if err != nil {
+		t.Fatalf("unable to initialize toolset: %s", err)
+	}
+	toolsets["ja"] = toolset
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	getManifest := func(toolsetName string) tools.ToolsetManifest {
+		_, body, err := runRequest(ts, http.MethodGet, fmt.Sprintf("/toolset/%s", toolsetName), nil, nil)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		var m tools.ToolsetManifest
+		if err := json.Unmarshal(body, &m); err != nil {
+			t.Fatalf("unable to parse ToolsetManifest: %s", err)
+		}
+		return m
+	}
+
+	base := getManifest("")
+	localized := getManifest("ja")
+	wantBase := tool2.Manifest()
+	if got := base.ToolsManifest[tool2.Name]; got.Description != wantBase.Description || got.Parameters[0].Description != wantBase.Parameters[0].Description {
+		t.Fatalf("unexpected default manifest: %+v", got)
+	}
+	got := localized.ToolsManifest[tool2.Name]
+	if got.Description != "いくつかのパラメータを持つツール" {
+		t.Fatalf("unexpected overridden description: %q", got.Description)
+	}
+	if got.Parameters[0].Description != "最初のパラメータ" {
+		t.Fatalf("unexpected overridden parameter description: %q", got.Parameters[0].Description)
+	}
+	// missing overrides fall back to the descriptions of the tool
+	if got.Parameters[1].Description != wantBase.Parameters[1].Description {
+		t.Fatalf("unexpected parameter description: %q", got.Parameters[1].Description)
+	}
+	if !reflect.DeepEqual(base.ToolsManifest[tool1.Name], localized.ToolsManifest[tool1.Name]) {
+		t.Fatalf("unexpected manifest of a tool without override: got %+v, want %+v", localized.ToolsManifest[tool1.Name], base.ToolsManifest[tool1.Name])
+	}
+}
+
+func TestToolsetDescriptionOverridesErrors(t *testing.T) {
+	toolsMap, _ := setUpResources(t, []MockTool{tool1, tool2})
+	testCases := []struct {
+		name      string
+		overrides map[string]tools.DescriptionOverride
+		wantErr   string
+	}{
+		{
+			name:      "unknown tool",
+			overrides: map[string]tools.DescriptionOverride{"some_imaginary_tool": {Description: "desc"}},
+			wantErr:   `description override for tool "some_imaginary_tool" that is not in the toolset`,
+		},
+		{
+			name:      "tool not in the toolset",
+			overrides: map[string]tools.DescriptionOverride{tool1.Name: {Description: "desc"}},
+			wantErr:   `description override for tool "no_params" that is not in the toolset`,
+		},
+		{
+			name:      "unknown parameter",
+			overrides: map[string]tools.DescriptionOverride{tool2.Name: {Parameters: map[string]string{"param3": "desc"}}},
+			wantErr:   `invalid description override for tool "some_params": parameter "param3" does not exist`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := tools.ToolsetConfig{Name: "ja", ToolNames: []string{tool2.Name}, DescriptionOverrides: tc.overrides}
+			_, err := cfg.Initialize(fakeVersionString, toolsMap)
+			if err == nil || err.Error() != tc.wantErr {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+// overBudgetTool is a MockTool whose invocations are over budget.
+type overBudgetTool struct {
+	MockTool
+}
+
+func (t overBudgetTool) Invoke(context.Context, tools.Invocation) (any, error) {
+	return nil, &tools.HintError{
+		Err:  &tools.BudgetExceededError{Tool: t.Name, Metric: tools.BudgetBytesProcessed, Estimate: 2000, Limit: 1000, CanOverride: true},
+		Hint: "narrow the query",
+	}
+}
+
+func TestToolOverBudget(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	toolsMap[tool1.Name] = overBudgetTool{MockTool: tool1}
+
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tool1.Name), bytes.NewBuffer([]byte(`{}`)), nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("unexpected status code: got %d, want %d, %s", resp.StatusCode, http.StatusBadRequest, string(body))
+	}
+	var got struct {
+		Budget map[string]any `json:"budget"`
+		Hint   string         `json:"hint"`
+	}
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("unexpected error unmarshalling body: %s", err)
+	}
+	want := map[string]any{"tool": tool1.Name, "metric": "bytesProcessed", "estimate": float64(2000), "limit": float64(1000), "canOverride": true}
+	if !reflect.DeepEqual(want, got.Budget) || got.Hint != "narrow the query" {
+		t.Fatalf("unexpected response: %s", string(body))
+	}
+}
+
+// tooLargeTool is a MockTool whose results are over its maxResultBytes.
+type tooLargeTool struct {
+	MockTool
+}
+
+func (t tooLargeTool) Invoke(context.Context, tools.Invocation) (any, error) {
+	return nil, tools.NewResultSizeLimit(10).Add("a value over the limit")
+}
+
+func TestToolResultTooLarge(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	toolsMap[tool1.Name] = tooLargeTool{MockTool: tool1}
+
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tool1.Name), bytes.NewBuffer([]byte(`{}`)), nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("unexpected status code: got %d, want %d, %s", resp.StatusCode, http.StatusBadRequest, string(body))
+	}
+	var got struct {
+		ResultSize map[string]any `json:"resultSize"`
+		Hint       string         `json:"hint"`
+	}
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("unexpected error unmarshalling body: %s", err)
+	}
+	want := map[string]any{"size": float64(38), "limit": float64(10)}
+	if !reflect.DeepEqual(want, got.ResultSize) || got.Hint == "" {
+		t.Fatalf("unexpected response: %s", string(body))
+	}
+}
+
+// bulkTool is a MockTool whose invocations are bulk operations in which the
+// items in failed fail.
+type bulkTool struct {
+	MockTool
+	failed []bool
+}
+
+func (t bulkTool) Invoke(context.Context, tools.Invocation) (any, error) {
+	result := tools.NewBulkResult(len(t.failed))
+	for i, failed := range t.failed {
+		if failed {
+			result.Add(nil, fmt.Errorf("item %d failed", i))
+			continue
+		}
+		result.Add(i, nil)
+	}
+	if err := result.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func TestToolBulkResult(t *testing.T) {
+	tcs := []struct {
+		desc       string
+		failed     []bool
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			desc:       "partial success",
+			failed:     []bool{false, true},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"result":"{\"succeeded\":1,\"failed\":1,\"errors\":[{\"index\":1,\"message\":\"item 1 failed\"}],\"results\":[0,null]}"}`,
+		},
+		{
+			desc:       "total failure",
+			failed:     []bool{true, true},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"status":"Bad Request","error":"error while invoking tool: all 2 items failed, first at index 0: item 0 failed","bulk":{"succeeded":0,"failed":2,"errors":[{"index":0,"message":"item 0 failed"},{"index":1,"message":"item 1 failed"}],"results":[null,null]}}`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			mockTools := []MockTool{tool1, tool2}
+			toolsMap, toolsets := setUpResources(t, mockTools)
+			toolsMap[tool1.Name] = bulkTool{MockTool: tool1, failed: tc.failed}
+
+			r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+			defer shutdown()
+			ts := runServer(r, false)
+			defer ts.Close()
+
+			resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tool1.Name), bytes.NewBuffer([]byte(`{}`)), nil)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.wantStatus {
+				t.Fatalf("unexpected status code: got %d, want %d, %s", resp.StatusCode, tc.wantStatus, string(body))
+			}
+			if got := strings.TrimSpace(string(body)); got != tc.wantBody {
+				t.Fatalf("unexpected response: got %s, want %s", got, tc.wantBody)
+			}
+		})
+	}
+}
+
+// countedTool is a MockTool that returns the number of times it was invoked.
+type countedTool struct {
+	MockTool
+	calls *int
+}
+
+func (t countedTool) Invoke(context.Context, tools.Invocation) (any, error) {
+	*t.calls++
+	return []any{*t.calls}, nil
+}
+
+func TestToolInvokeIdempotencyKey(t *testing.T) {
+	tcs := []struct {
+		name      string
+		accepted  bool
+		wantCalls int
+	}{
+		{name: "accepted", accepted: true, wantCalls: 1},
+		{name: "ignored", accepted: false, wantCalls: 2},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			mockTools := []MockTool{tool1, tool2}
+			toolsMap, toolsets := setUpResources(t, mockTools)
+			calls := 0
+			var tool tools.Tool = countedTool{MockTool: tool2, calls: &calls}
+			if tc.accepted {
+				tool = tools.IdempotentTool{Tool: tool, Name: tool2.Name}
+			}
+			toolsMap[tool2.Name] = tool
+
+			r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+			defer shutdown()
+			ts := runServer(r, false)
+			defer ts.Close()
+
+			header := map[string]string{"Idempotency-Key": "order-42"}
+			var bodies []string
+			for i := 0; i < 2; i++ {
+				resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tool2.Name), bytes.NewBuffer([]byte(`{"param1": 1, "param2": 2}`)), header)
+				if err != nil {
+					t.Fatalf("unexpected error during request: %s", err)
+				}
+				if resp.StatusCode != http.StatusOK {
+					t.Fatalf("unexpected status code: got %d, want %d, %s", resp.StatusCode, http.StatusOK, string(body))
+				}
+				bodies = append(bodies, string(body))
+			}
+			if calls != tc.wantCalls {
+				t.Fatalf("unexpected number of invocations: got %d, want %d", calls, tc.wantCalls)
+			}
+			if tc.accepted && bodies[0] != bodies[1] {
+				t.Fatalf("expected the retry to replay the result: got %q, want %q", bodies[1], bodies[0])
+			}
+
+			// reusing the key with other arguments is a conflict
+			if tc.accepted {
+				resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tool2.Name), bytes.NewBuffer([]byte(`{"param1": 3, "param2": 2}`)), header)
+				if err != nil {
+					t.Fatalf("unexpected error during request: %s", err)
+				}
+				if resp.StatusCode != http.StatusConflict {
+					t.Fatalf("unexpected status code: got %d, want %d, %s", resp.StatusCode, http.StatusConflict, string(body))
+				}
+			}
+		})
+	}
+}
+
+func TestToolInvokePollable(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	poll, err := tools.NewPoll(tool2.Name, tools.PollableSpec{})
+	if err != nil {
+		t.Fatalf("unable to create poll: %s", err)
+	}
+	toolsMap[tool2.Name] = tools.PollableTool{Tool: tool2, Poll: poll}
+
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	invoke := func(header map[string]string) (*http.Response, []byte) {
+		resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tool2.Name), bytes.NewBuffer([]byte(`{"param1": 1, "param2": 2}`)), header)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		return resp, body
+	}
+
+	resp, body := invoke(nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: got %d, want %d, %s", resp.StatusCode, http.StatusOK, string(body))
+	}
+	etag := resp.Header.Get("ETag")
+	wantETag, err := tools.ResultETag([]any{tool2.Name})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if etag != wantETag {
+		t.Fatalf("unexpected ETag: got %q, want %q", etag, wantETag)
+	}
+
+	resp, body = invoke(map[string]string{"If-None-Match": etag})
+	if resp.StatusCode != http.StatusNotModified {
+		t.Fatalf("unexpected status code: got %d, want %d, %s", resp.StatusCode, http.StatusNotModified, string(body))
+	}
+	if len(body) != 0 || resp.Header.Get("ETag") != etag {
+		t.Fatalf("unexpected response: ETag %q, body %q", resp.Header.Get("ETag"), string(body))
+	}
+
+	resp, body = invoke(map[string]string{"If-None-Match": `"stale"`})
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), tool2.Name) {
+		t.Fatalf("unexpected response: got %d, %s", resp.StatusCode, string(body))
+	}
+
+	// tools that are not pollable ignore the header
+	resp, body, err = runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tool1.Name), bytes.NewBuffer([]byte(`{}`)), map[string]string{"If-None-Match": "*"})
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("ETag") != "" {
+		t.Fatalf("unexpected response: got %d with ETag %q, %s", resp.StatusCode, resp.Header.Get("ETag"), string(body))
+	}
+}
+
+func TestToolInvokePayloadLimits(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	tcs := []struct {
+		desc string
+		body string
+		want string
+	}{
+		{
+			desc: "nesting depth",
+			body: `{"vis_config": ` + strings.Repeat(`{"a": `, DefaultMaxPayloadDepth) + `1` + strings.Repeat(`}`, DefaultMaxPayloadDepth) + `}`,
+			want: fmt.Sprintf("maximum nesting depth of %d", DefaultMaxPayloadDepth),
+		},
+		{
+			desc: "number of keys",
+			body: `{"vis_config": {"k": 0` + strings.Repeat(`, "k": 0`, DefaultMaxPayloadKeys) + `}}`,
+			want: fmt.Sprintf("maximum number of keys of %d", DefaultMaxPayloadKeys),
+		},
+		{
+			desc: "string length",
+			body: `{"query": "` + strings.Repeat("x", DefaultMaxPayloadStringLength+1) + `"}`,
+			want: fmt.Sprintf("maximum string length of %d", DefaultMaxPayloadStringLength),
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tool1.Name), strings.NewReader(tc.body), nil)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Fatalf("unexpected status code: got %d, want %d, %s", resp.StatusCode, http.StatusBadRequest, string(body))
+			}
+			if !strings.Contains(string(body), tc.want) {
+				t.Fatalf("unexpected body: got %s, want substring %q", string(body), tc.want)
+			}
+		})
+	}
+}
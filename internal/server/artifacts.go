@@ -0,0 +1,601 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+const (
+	// artifactKeyFile is the file in the artifact directory holding the key
+	// that download URLs are signed with, so that they stay valid across
+	// restarts.
+	artifactKeyFile = "signing.key"
+	artifactMetaExt = ".json"
+	artifactDataExt = ".data"
+	artifactTmpExt  = ".tmp"
+)
+
+var (
+	errArtifactNotFound     = errors.New("artifact does not exist or has expired")
+	errArtifactSignature    = errors.New("invalid or expired artifact signature")
+	errArtifactUnauthorized = errors.New("not authorized to read the artifact")
+)
+
+// artifactMeta is stored next to the data of each artifact.
+type artifactMeta struct {
+	// Tool is the tool that spilled the artifact, empty if uploaded.
+	Tool      string    `json:"tool"`
+	Format    string    `json:"format"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	// Owners maps the authServices that verified the uploader of an artifact
+	// to the subject they verified.
+	Owners map[string]string `json:"owners,omitempty"`
+}
+
+// artifactStore keeps the results spilled by tools in a directory until they
+// expire. The directory is only created once the first result is spilled.
+type artifactStore struct {
+	dir    string
+	ttl    time.Duration
+	logger log.Logger
+	// maxBytes and maxCount bound the total size and the number of the
+	// artifacts, if not zero. The oldest artifacts are evicted to stay below
+	// them.
+	maxBytes int64
+	maxCount int
+
+	// mu guards key, which is loaded or created on first use.
+	mu  sync.Mutex
+	key []byte
+	// evictMu serializes the evictions.
+	evictMu sync.Mutex
+}
+
+// newArtifactStore returns an artifactStore for dir. It removes the artifacts
+// that expired while the server was not running, and keeps removing expired
+// artifacts until ctx is done.
+func newArtifactStore(ctx context.Context, logger log.Logger, dir string, ttl time.Duration, maxBytes int64, maxCount int) *artifactStore {
+	s := &artifactStore{dir: dir, ttl: ttl, logger: logger, maxBytes: maxBytes, maxCount: maxCount}
+	s.cleanup(ctx)
+	go s.cleanupRoutine(ctx)
+	return s
+}
+
+func (s *artifactStore) cleanupRoutine(ctx context.Context) {
+	ticker := time.NewTicker(min(s.ttl, time.Minute))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.cleanup(ctx)
+		}
+	}
+}
+
+// cleanup removes the expired artifacts, and the files of artifacts that were
+// never committed once they are older than the TTL.
+func (s *artifactStore) cleanup(ctx context.Context) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			s.logger.WarnContext(ctx, fmt.Sprintf("unable to clean up artifacts in %q: %s", s.dir, err))
+		}
+		return
+	}
+	now := time.Now()
+	committed := make(map[string]bool)
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), artifactMetaExt)
+		if !ok {
+			continue
+		}
+		meta, err := s.readMeta(id)
+		if err == nil && now.Before(meta.ExpiresAt) {
+			committed[id] = true
+			continue
+		}
+		s.remove(id)
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if name == artifactKeyFile || strings.HasSuffix(name, artifactMetaExt) {
+			continue
+		}
+		if committed[strings.TrimSuffix(strings.TrimSuffix(name, artifactTmpExt), artifactDataExt)] {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil || now.Sub(info.ModTime()) < s.ttl {
+			continue
+		}
+		_ = os.Remove(filepath.Join(s.dir, name))
+	}
+}
+
+// evict removes the oldest artifacts until the artifacts fit in the quota of
+// the store, except keep, the artifact that was just committed.
+func (s *artifactStore) evict(keep string) {
+	if s.maxBytes == 0 && s.maxCount == 0 {
+		return
+	}
+	s.evictMu.Lock()
+	defer s.evictMu.Unlock()
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return
+	}
+	type stored struct {
+		id      string
+		size    int64
+		created time.Time
+	}
+	var artifacts []stored
+	var total int64
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), artifactMetaExt)
+		if !ok {
+			continue
+		}
+		// the data of an artifact is never written again once committed
+		info, err := os.Stat(filepath.Join(s.dir, id+artifactDataExt))
+		if err != nil {
+			continue
+		}
+		artifacts = append(artifacts, stored{id: id, size: info.Size(), created: info.ModTime()})
+		total += info.Size()
+	}
+	slices.SortFunc(artifacts, func(a, b stored) int { return a.created.Compare(b.created) })
+	count := len(artifacts)
+	for _, a := range artifacts {
+		if (s.maxBytes == 0 || total <= s.maxBytes) && (s.maxCount == 0 || count <= s.maxCount) {
+			return
+		}
+		if a.id == keep {
+			continue
+		}
+		s.remove(a.id)
+		total -= a.size
+		count--
+	}
+}
+
+func (s *artifactStore) remove(id string) {
+	_ = os.Remove(filepath.Join(s.dir, id+artifactDataExt))
+	_ = os.Remove(filepath.Join(s.dir, id+artifactMetaExt))
+}
+
+func (s *artifactStore) readMeta(id string) (artifactMeta, error) {
+	var meta artifactMeta
+	b, err := os.ReadFile(filepath.Join(s.dir, id+artifactMetaExt))
+	if err != nil {
+		return meta, err
+	}
+	err = json.Unmarshal(b, &meta)
+	return meta, err
+}
+
+// signingKey returns the key that download URLs are signed with, creating the
+// artifact directory and the key if needed.
+func (s *artifactStore) signingKey() ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.key != nil {
+		return s.key, nil
+	}
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(s.dir, artifactKeyFile)
+	key, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+		err = os.WriteFile(path, key, 0o600)
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.key = key
+	return key, nil
+}
+
+func (s *artifactStore) sign(id string, expires int64) (string, error) {
+	key, err := s.signingKey()
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, key)
+	fmt.Fprintf(mac, "%s.%d", id, expires)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// open verifies the signature of a download request for the artifact id, and
+// returns the artifact and its data.
+func (s *artifactStore) open(id string, query url.Values) (artifactMeta, *os.File, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return artifactMeta{}, nil, errArtifactNotFound
+	}
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil || time.Now().Unix() >= expires {
+		return artifactMeta{}, nil, errArtifactSignature
+	}
+	want, err := s.sign(id, expires)
+	if err != nil {
+		return artifactMeta{}, nil, err
+	}
+	if !hmac.Equal([]byte(want), []byte(query.Get("signature"))) {
+		return artifactMeta{}, nil, errArtifactSignature
+	}
+	return s.openData(id)
+}
+
+// openData returns the artifact id and its data, unless it expired.
+func (s *artifactStore) openData(id string) (artifactMeta, *os.File, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return artifactMeta{}, nil, errArtifactNotFound
+	}
+	meta, err := s.readMeta(id)
+	if err != nil || !time.Now().Before(meta.ExpiresAt) {
+		return artifactMeta{}, nil, errArtifactNotFound
+	}
+	f, err := os.Open(filepath.Join(s.dir, id+artifactDataExt))
+	if err != nil {
+		return artifactMeta{}, nil, errArtifactNotFound
+	}
+	return meta, f, nil
+}
+
+// writeMeta writes the metadata of the artifact id, which makes it available.
+func (s *artifactStore) writeMeta(id string, meta artifactMeta) error {
+	b, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	metaPath := filepath.Join(s.dir, id+artifactMetaExt)
+	if err := os.WriteFile(metaPath+artifactTmpExt, b, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(metaPath+artifactTmpExt, metaPath)
+}
+
+// uploadedArtifact describes an artifact uploaded by a client.
+type uploadedArtifact struct {
+	ID string `json:"id"`
+	// URI references the artifact in the arguments of tools, as
+	// `{"$resource": uri}`.
+	URI       string    `json:"uri"`
+	Bytes     int64     `json:"bytes"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// upload stores the content read from r as an artifact that only the callers
+// verified as one of owners can reference.
+func (s *artifactStore) upload(r io.Reader, owners map[string]string) (uploadedArtifact, error) {
+	if _, err := s.signingKey(); err != nil {
+		return uploadedArtifact{}, err
+	}
+	id := uuid.New().String()
+	dataPath := filepath.Join(s.dir, id+artifactDataExt)
+	f, err := os.OpenFile(dataPath+artifactTmpExt, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return uploadedArtifact{}, err
+	}
+	n, err := io.Copy(f, r)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), dataPath)
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return uploadedArtifact{}, err
+	}
+	expiresAt := time.Now().Add(s.ttl).Truncate(time.Second)
+	if err := s.writeMeta(id, artifactMeta{Format: artifactFormatUpload, ExpiresAt: expiresAt, Owners: owners}); err != nil {
+		_ = os.Remove(dataPath)
+		return uploadedArtifact{}, err
+	}
+	s.evict(id)
+	return uploadedArtifact{
+		ID:        id,
+		URI:       tools.ArtifactResourcePrefix + id,
+		Bytes:     n,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// forBaseURL returns a tools.ArtifactStore creating artifacts whose download
+// URLs start with baseURL, such as "https://toolbox.example.com".
+func (s *artifactStore) forBaseURL(baseURL string) tools.ArtifactStore {
+	return boundArtifactStore{store: s, baseURL: baseURL}
+}
+
+// artifactContext adds the artifact store of the server, with the base URL of
+// the request, into the context of the request.
+func (s *Server) artifactContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.artifacts == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		scheme := "http"
+		if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+			scheme = "https"
+		}
+		store := s.artifacts.forBaseURL(scheme + "://" + r.Host)
+		ctx := tools.WithArtifactStore(r.Context(), store)
+		ctx = tools.WithResourceResolver(ctx, s.artifactResolver())
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// artifactResolver returns the tools.ResourceResolver of the references to
+// the artifacts of the server.
+func (s *Server) artifactResolver() tools.ResourceResolver {
+	return artifactResolver{store: s.artifacts, getTool: s.ResourceMgr.GetTool, maxBytes: s.maxResolvedResourceBytes}
+}
+
+// artifactResolver resolves the references to artifacts in the arguments of
+// tools. An artifact spilled by a tool is authorized the same way as
+// downloading it, and an uploaded artifact for its owners only.
+type artifactResolver struct {
+	store    *artifactStore
+	getTool  func(string) (tools.Tool, bool)
+	maxBytes int64
+}
+
+func (a artifactResolver) MaxResolvedBytes() int64 {
+	return a.maxBytes
+}
+
+func (a artifactResolver) Resolve(ctx context.Context, uri string, limit int64) (string, error) {
+	id, ok := strings.CutPrefix(uri, tools.ArtifactResourcePrefix)
+	if !ok {
+		return "", fmt.Errorf("unsupported resource: URIs must start with %q", tools.ArtifactResourcePrefix)
+	}
+	meta, f, err := a.store.openData(id)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if !a.authorized(ctx, meta) {
+		return "", errArtifactUnauthorized
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	if info.Size() > limit {
+		return "", tools.ErrResourceTooLarge
+	}
+	b, err := io.ReadAll(f)
+	if err != nil {
+		return "", err
+	}
+	if !utf8.Valid(b) {
+		return "", fmt.Errorf("artifact is not UTF-8 text")
+	}
+	return string(b), nil
+}
+
+func (a artifactResolver) authorized(ctx context.Context, meta artifactMeta) bool {
+	if meta.Tool != "" {
+		tool, ok := a.getTool(meta.Tool)
+		return ok && tool.Authorized(util.VerifiedAuthServicesFromContext(ctx))
+	}
+	claims := util.AuthClaimsFromContext(ctx)
+	for name, sub := range meta.Owners {
+		if c, ok := claims[name]; ok && c["sub"] == sub {
+			return true
+		}
+	}
+	return false
+}
+
+type boundArtifactStore struct {
+	store   *artifactStore
+	baseURL string
+}
+
+func (b boundArtifactStore) Create(ctx context.Context, toolName string, format string) (tools.ArtifactWriter, error) {
+	s := b.store
+	if _, err := s.signingKey(); err != nil {
+		return nil, err
+	}
+	id := uuid.New().String()
+	f, err := os.OpenFile(filepath.Join(s.dir, id+artifactDataExt+artifactTmpExt), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	w := &artifactWriter{
+		store:   s,
+		baseURL: b.baseURL,
+		id:      id,
+		tool:    toolName,
+		format:  format,
+		f:       f,
+		buf:     bufio.NewWriter(f),
+	}
+	if format == tools.SpillFormatCSV {
+		w.csv = csv.NewWriter(w.buf)
+	}
+	return w, nil
+}
+
+// artifactWriter writes the data of an artifact to a temporary file, which is
+// renamed once the artifact is committed.
+type artifactWriter struct {
+	store   *artifactStore
+	baseURL string
+	id      string
+	tool    string
+	format  string
+	f       *os.File
+	buf     *bufio.Writer
+	csv     *csv.Writer
+	// columns are the CSV columns, taken from the first row.
+	columns []string
+	rows    int
+	done    bool
+}
+
+func (w *artifactWriter) WriteRow(row any) error {
+	w.rows++
+	if w.csv == nil {
+		b, err := json.Marshal(row)
+		if err != nil {
+			return err
+		}
+		_, err = w.buf.Write(append(b, '\n'))
+		return err
+	}
+
+	m, ok := row.(map[string]any)
+	if !ok {
+		m = map[string]any{"value": row}
+	}
+	if w.columns == nil {
+		w.columns = slices.Sorted(maps.Keys(m))
+		if err := w.csv.Write(w.columns); err != nil {
+			return err
+		}
+	}
+	record := make([]string, len(w.columns))
+	for i, c := range w.columns {
+		v, err := csvValue(m[c])
+		if err != nil {
+			return err
+		}
+		record[i] = v
+	}
+	return w.csv.Write(record)
+}
+
+// csvValue formats a value of a row as a CSV field. Strings are written as
+// they are, NULLs as empty fields and other values as JSON.
+func csvValue(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	// values that marshal to a JSON string, such as timestamps, are unquoted
+	var s string
+	if json.Unmarshal(b, &s) == nil {
+		return s, nil
+	}
+	return string(b), nil
+}
+
+func (w *artifactWriter) Commit() (tools.Artifact, error) {
+	s := w.store
+	if w.csv != nil {
+		w.csv.Flush()
+		if err := w.csv.Error(); err != nil {
+			return tools.Artifact{}, err
+		}
+	}
+	if err := w.buf.Flush(); err != nil {
+		return tools.Artifact{}, err
+	}
+	if err := w.f.Close(); err != nil {
+		return tools.Artifact{}, err
+	}
+	// the data is in place before the metadata, so that an artifact never
+	// has metadata without data
+	dataPath := filepath.Join(s.dir, w.id+artifactDataExt)
+	if err := os.Rename(w.f.Name(), dataPath); err != nil {
+		return tools.Artifact{}, err
+	}
+	expiresAt := time.Now().Add(s.ttl).Truncate(time.Second)
+	if err := s.writeMeta(w.id, artifactMeta{Tool: w.tool, Format: w.format, ExpiresAt: expiresAt}); err != nil {
+		return tools.Artifact{}, err
+	}
+	w.done = true
+	s.evict(w.id)
+
+	signature, err := s.sign(w.id, expiresAt.Unix())
+	if err != nil {
+		return tools.Artifact{}, err
+	}
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expiresAt.Unix(), 10))
+	query.Set("signature", signature)
+	return tools.Artifact{
+		ID:          w.id,
+		Format:      w.format,
+		Rows:        w.rows,
+		DownloadURL: fmt.Sprintf("%s/api/artifacts/%s?%s", w.baseURL, w.id, query.Encode()),
+		ExpiresAt:   expiresAt,
+	}, nil
+}
+
+func (w *artifactWriter) Abort() {
+	if w.done {
+		return
+	}
+	w.done = true
+	_ = w.f.Close()
+	_ = os.Remove(w.f.Name())
+	_ = os.Remove(filepath.Join(w.store.dir, w.id+artifactDataExt))
+}
+
+// artifactFormatUpload is the format of the artifacts uploaded by clients,
+// which are stored as they were received.
+const artifactFormatUpload = "upload"
+
+// artifactContentTypes maps the formats of artifacts to their content types.
+var artifactContentTypes = map[string]string{
+	tools.SpillFormatCSV:    "text/csv",
+	tools.SpillFormatNDJSON: "application/x-ndjson",
+}
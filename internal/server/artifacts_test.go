@@ -0,0 +1,742 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
+	"github.com/googleapis/genai-toolbox/internal/telemetry"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// exportTool returns rows and is authorized by the given authServices.
+type exportTool struct {
+	MockTool
+	rows         []any
+	authRequired []string
+}
+
+func (t exportTool) Invoke(context.Context, tools.Invocation) (any, error) {
+	return t.rows, nil
+}
+
+func (t exportTool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.authRequired, verifiedAuthServices)
+}
+
+func newTestArtifactStore(t *testing.T, dir string, ttl time.Duration) *artifactStore {
+	t.Helper()
+	logger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	return newArtifactStore(ctx, logger, dir, ttl, 0, 0)
+}
+
+// setUpArtifactServer starts an API server with a tool named "export" that
+// spills its result to an artifact, and an authService named "admin".
+func setUpArtifactServer(t *testing.T, store *artifactStore, authRequired []string) *httptest.Server {
+	t.Helper()
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+	spill, err := tools.NewSpill(tools.SpillSpec{MaxRows: 2, PreviewRows: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	rows := []any{
+		map[string]any{"id": 1, "name": "Ada", "note": nil},
+		map[string]any{"id": 2, "name": "Grace, Hopper", "note": "line\nbreak"},
+		map[string]any{"id": 3, "name": `"Bob"`, "note": true},
+	}
+	toolsMap := map[string]tools.Tool{
+		"export": tools.SpillTool{
+			Tool:  exportTool{MockTool: MockTool{Name: "export"}, rows: rows, authRequired: authRequired},
+			Spill: spill,
+		},
+	}
+	authServices := map[string]auth.AuthService{"admin": fakeAuthService{name: "admin"}}
+
+	s := &Server{
+		version:         fakeVersionString,
+		logger:          testLogger,
+		instrumentation: instrumentation,
+		artifacts:       store,
+		ResourceMgr:     NewResourceManager(nil, authServices, toolsMap, nil),
+	}
+	r, err := apiRouter(s)
+	if err != nil {
+		t.Fatalf("unable to initialize api router: %s", err)
+	}
+	ts := runServer(r, false)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// invokeExport invokes the "export" tool and returns the artifact it spilled.
+func invokeExport(t *testing.T, ts *httptest.Server, header map[string]string) tools.Artifact {
+	t.Helper()
+	resp, body, err := runRequest(ts, http.MethodPost, "/tool/export/invoke", bytes.NewBufferString(`{}`), header)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code %d: %s", resp.StatusCode, body)
+	}
+	var envelope struct {
+		Result string `json:"result"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		t.Fatalf("unable to decode response %q: %s", body, err)
+	}
+	var result struct {
+		Preview  []map[string]any `json:"preview"`
+		Artifact tools.Artifact   `json:"artifact"`
+	}
+	if err := json.Unmarshal([]byte(envelope.Result), &result); err != nil {
+		t.Fatalf("unable to decode result %q: %s", envelope.Result, err)
+	}
+	wantPreview := []map[string]any{{"id": float64(1), "name": "Ada", "note": nil}}
+	if diff := cmp.Diff(wantPreview, result.Preview); diff != "" {
+		t.Fatalf("unexpected preview: diff %v", diff)
+	}
+	if result.Artifact.Rows != 3 || result.Artifact.Format != "csv" {
+		t.Fatalf("unexpected artifact: %+v", result.Artifact)
+	}
+	return result.Artifact
+}
+
+func download(t *testing.T, rawURL string, header map[string]string) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		t.Fatalf("unable to create request: %s", err)
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unable to send request: %s", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body: %s", err)
+	}
+	return resp, string(body)
+}
+
+func TestArtifactDownload(t *testing.T) {
+	store := newTestArtifactStore(t, filepath.Join(t.TempDir(), "artifacts"), time.Hour)
+	ts := setUpArtifactServer(t, store, nil)
+	artifact := invokeExport(t, ts, nil)
+
+	if !strings.HasPrefix(artifact.DownloadURL, ts.URL+"/api/artifacts/"+artifact.ID+"?") {
+		t.Fatalf("unexpected download URL %q", artifact.DownloadURL)
+	}
+	// the test server is mounted without the /api prefix
+	rawURL := strings.Replace(artifact.DownloadURL, "/api/artifacts/", "/artifacts/", 1)
+	resp, body := download(t, rawURL, nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code %d: %s", resp.StatusCode, body)
+	}
+	want := "id,name,note\n1,Ada,\n2,\"Grace, Hopper\",\"line\nbreak\"\n3,\"\"\"Bob\"\"\",true\n"
+	if body != want {
+		t.Fatalf("unexpected artifact: got %q, want %q", body, want)
+	}
+	if got := resp.Header.Get("Content-Type"); got != "text/csv" {
+		t.Fatalf("unexpected content type %q", got)
+	}
+
+	t.Run("invalid signature", func(t *testing.T) {
+		u, _ := url.Parse(rawURL)
+		q := u.Query()
+		q.Set("signature", "forged")
+		u.RawQuery = q.Encode()
+		if resp, body := download(t, u.String(), nil); resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("unexpected status code %d: %s", resp.StatusCode, body)
+		}
+	})
+	t.Run("extended expiry", func(t *testing.T) {
+		u, _ := url.Parse(rawURL)
+		q := u.Query()
+		q.Set("expires", "99999999999")
+		u.RawQuery = q.Encode()
+		if resp, body := download(t, u.String(), nil); resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("unexpected status code %d: %s", resp.StatusCode, body)
+		}
+	})
+	t.Run("signature of another artifact", func(t *testing.T) {
+		u := strings.Replace(rawURL, artifact.ID, "00000000-0000-0000-0000-000000000000", 1)
+		if resp, body := download(t, u, nil); resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("unexpected status code %d: %s", resp.StatusCode, body)
+		}
+	})
+}
+
+func TestArtifactDownloadAuth(t *testing.T) {
+	store := newTestArtifactStore(t, t.TempDir(), time.Hour)
+	ts := setUpArtifactServer(t, store, []string{"admin"})
+	artifact := invokeExport(t, ts, adminHeader)
+	rawURL := strings.Replace(artifact.DownloadURL, "/api/artifacts/", "/artifacts/", 1)
+
+	tcs := []struct {
+		desc   string
+		header map[string]string
+		want   int
+	}{
+		{desc: "without auth header", want: http.StatusUnauthorized},
+		{desc: "with invalid auth header", header: map[string]string{"admin_token": "invalid"}, want: http.StatusUnauthorized},
+		{desc: "with auth header", header: adminHeader, want: http.StatusOK},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			if resp, body := download(t, rawURL, tc.header); resp.StatusCode != tc.want {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, tc.want, body)
+			}
+		})
+	}
+}
+
+func TestArtifactNDJSON(t *testing.T) {
+	dir := t.TempDir()
+	store := newTestArtifactStore(t, dir, time.Hour)
+	w, err := store.forBaseURL("").Create(context.Background(), "export", tools.SpillFormatNDJSON)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for _, row := range []any{map[string]any{"id": 1}, map[string]any{"id": 2, "tags": []any{"a"}}} {
+		if err := w.WriteRow(row); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	artifact, err := w.Commit()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, artifact.ID+artifactDataExt))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "{\"id\":1}\n{\"id\":2,\"tags\":[\"a\"]}\n"; string(b) != want {
+		t.Fatalf("unexpected artifact: got %q, want %q", b, want)
+	}
+}
+
+func TestArtifactAbort(t *testing.T) {
+	dir := t.TempDir()
+	store := newTestArtifactStore(t, dir, time.Hour)
+	w, err := store.forBaseURL("").Create(context.Background(), "export", tools.SpillFormatCSV)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := w.WriteRow(map[string]any{"id": 1}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	w.Abort()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != artifactKeyFile {
+		t.Fatalf("expected only the signing key to remain, got %v", entries)
+	}
+}
+
+func TestArtifactCleanup(t *testing.T) {
+	dir := t.TempDir()
+	create := func(ttl time.Duration) string {
+		store := &artifactStore{dir: dir, ttl: ttl}
+		w, err := store.forBaseURL("").Create(context.Background(), "export", tools.SpillFormatCSV)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		artifact, err := w.Commit()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		return artifact.ID
+	}
+	expired := create(time.Nanosecond)
+	valid := create(time.Hour)
+
+	// a file left behind by a write that was interrupted long ago
+	orphan := filepath.Join(dir, "11111111-1111-1111-1111-111111111111"+artifactDataExt+artifactTmpExt)
+	if err := os.WriteFile(orphan, []byte("id\n"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(orphan, old, old); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// artifacts that expired while the server was stopped are removed on boot
+	newTestArtifactStore(t, dir, time.Hour)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Name())
+	}
+	want := []string{valid + artifactDataExt, valid + artifactMetaExt, artifactKeyFile}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected files after cleanup of %q: diff %v", expired, diff)
+	}
+}
+
+// setUpResourceRefServer starts a server with the API mounted at /api and MCP at
+// /mcp, a tool named "echo" returning its "text" and "extra" arguments, a
+// tool named "export" that spills its result and requires the "admin"
+// authService, and the authServices "admin", which verifies the clients
+// uploading artifacts, and "other".
+func setUpResourceRefServer(t *testing.T, store *artifactStore, maxUploadBytes, maxResolvedBytes int64) *httptest.Server {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+	spill, err := tools.NewSpill(tools.SpillSpec{MaxRows: 1, PreviewRows: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	toolsMap := map[string]tools.Tool{
+		"echo": echoTool{MockTool: MockTool{
+			Name: "echo",
+			Params: tools.Parameters{
+				tools.NewStringParameter("text", "The text to echo."),
+				tools.NewStringParameterWithDefault("extra", "", "More text to echo."),
+			},
+		}},
+		"export": tools.SpillTool{
+			Tool: exportTool{
+				MockTool:     MockTool{Name: "export"},
+				rows:         []any{map[string]any{"id": 1}, map[string]any{"id": 2}},
+				authRequired: []string{"admin"},
+			},
+			Spill: spill,
+		},
+	}
+	toolset, err := tools.ToolsetConfig{Name: "", ToolNames: []string{"echo", "export"}}.Initialize(fakeVersionString, toolsMap)
+	if err != nil {
+		t.Fatalf("unable to initialize toolset: %s", err)
+	}
+	authServices := map[string]auth.AuthService{
+		"admin": fakeAuthService{name: "admin"},
+		"other": fakeAuthService{name: "other"},
+	}
+
+	s := &Server{
+		version:                    fakeVersionString,
+		logger:                     testLogger,
+		instrumentation:            instrumentation,
+		sseManager:                 newSseManager(ctx),
+		wsManager:                  newWsManager(),
+		wsMaxMessageSize:           DefaultMcpWebsocketMaxMessageSize,
+		artifacts:                  store,
+		artifactUploadMaxBytes:     maxUploadBytes,
+		artifactUploadAuthServices: []string{"admin"},
+		maxResolvedResourceBytes:   maxResolvedBytes,
+		ResourceMgr:                NewResourceManager(nil, authServices, toolsMap, map[string]tools.Toolset{"": toolset}),
+	}
+	apiR, err := apiRouter(s)
+	if err != nil {
+		t.Fatalf("unable to initialize api router: %s", err)
+	}
+	mcpR, err := mcpRouter(s)
+	if err != nil {
+		t.Fatalf("unable to initialize mcp router: %s", err)
+	}
+	r := chi.NewRouter()
+	r.Mount("/api", apiR)
+	r.Mount("/mcp", mcpR)
+	ts := runServer(r, false)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// upload uploads content as an artifact and returns it.
+func upload(t *testing.T, ts *httptest.Server, content string, header map[string]string) uploadedArtifact {
+	t.Helper()
+	h := map[string]string{"Content-Type": "text/plain"}
+	maps.Copy(h, header)
+	resp, body, err := runRequest(ts, http.MethodPut, "/api/artifacts", strings.NewReader(content), h)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("unexpected status code %d: %s", resp.StatusCode, body)
+	}
+	var artifact uploadedArtifact
+	if err := json.Unmarshal(body, &artifact); err != nil {
+		t.Fatalf("unable to decode response %q: %s", body, err)
+	}
+	return artifact
+}
+
+// invokeEcho invokes the "echo" tool with args, and returns the status code
+// and either the arguments it echoed or the error.
+func invokeEcho(t *testing.T, ts *httptest.Server, args map[string]any, header map[string]string) (int, map[string]any, string) {
+	t.Helper()
+	b, err := json.Marshal(args)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resp, body, err := runRequest(ts, http.MethodPost, "/api/tool/echo/invoke", bytes.NewReader(b), header)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	var envelope struct {
+		Result string `json:"result"`
+		Error  string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		t.Fatalf("unable to decode response %q: %s", body, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, nil, envelope.Error
+	}
+	var echoed []map[string]any
+	if err := json.Unmarshal([]byte(envelope.Result), &echoed); err != nil || len(echoed) != 1 {
+		t.Fatalf("unable to decode result %q: %v", envelope.Result, err)
+	}
+	return resp.StatusCode, echoed[0], ""
+}
+
+func resourceRef(uri string) map[string]any {
+	return map[string]any{tools.ResourceReferenceKey: uri}
+}
+
+func TestArtifactUploadReference(t *testing.T) {
+	store := newTestArtifactStore(t, t.TempDir(), time.Hour)
+	ts := setUpResourceRefServer(t, store, 0, 0)
+	content := "SELECT *\nFROM orders\nWHERE note = 'été';"
+	artifact := upload(t, ts, content, adminHeader)
+	if artifact.URI != "toolbox://artifacts/"+artifact.ID || artifact.Bytes != int64(len(content)) {
+		t.Fatalf("unexpected artifact: %+v", artifact)
+	}
+
+	t.Run("http", func(t *testing.T) {
+		status, echoed, errMsg := invokeEcho(t, ts, map[string]any{"text": resourceRef(artifact.URI)}, adminHeader)
+		if status != http.StatusOK {
+			t.Fatalf("unexpected status code %d: %s", status, errMsg)
+		}
+		if diff := cmp.Diff(map[string]any{"text": content, "extra": ""}, echoed); diff != "" {
+			t.Fatalf("unexpected arguments: diff %v", diff)
+		}
+	})
+	t.Run("mcp", func(t *testing.T) {
+		reqMarshal, err := json.Marshal(jsonrpc.JSONRPCRequest{
+			Jsonrpc: jsonrpcVersion,
+			Id:      "resource-reference",
+			Request: jsonrpc.Request{Method: "tools/call"},
+			Params: map[string]any{
+				"name":      "echo",
+				"arguments": map[string]any{"text": resourceRef(artifact.URI), "extra": resourceRef(artifact.URI)},
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error during marshaling of body")
+		}
+		header := map[string]string{"MCP-Protocol-Version": protocolVersion20250618}
+		maps.Copy(header, adminHeader)
+		_, body, err := runRequest(ts, http.MethodPost, "/mcp", bytes.NewBuffer(reqMarshal), header)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		var got struct {
+			Result struct {
+				Content []struct {
+					Text string `json:"text"`
+				} `json:"content"`
+			} `json:"result"`
+		}
+		if err := json.Unmarshal(body, &got); err != nil || len(got.Result.Content) != 1 {
+			t.Fatalf("unexpected response %s", body)
+		}
+		var echoed map[string]any
+		if err := json.Unmarshal([]byte(got.Result.Content[0].Text), &echoed); err != nil {
+			t.Fatalf("unable to decode result %q: %s", got.Result.Content[0].Text, err)
+		}
+		if diff := cmp.Diff(map[string]any{"text": content, "extra": content}, echoed); diff != "" {
+			t.Fatalf("unexpected arguments: diff %v", diff)
+		}
+	})
+	t.Run("mcp invalid reference", func(t *testing.T) {
+		reqMarshal, err := json.Marshal(jsonrpc.JSONRPCRequest{
+			Jsonrpc: jsonrpcVersion,
+			Id:      "resource-reference",
+			Request: jsonrpc.Request{Method: "tools/call"},
+			Params: map[string]any{
+				"name":      "echo",
+				"arguments": map[string]any{"text": resourceRef("toolbox://artifacts/unknown")},
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error during marshaling of body")
+		}
+		header := map[string]string{"MCP-Protocol-Version": protocolVersion20250618}
+		_, body, err := runRequest(ts, http.MethodPost, "/mcp", bytes.NewBuffer(reqMarshal), header)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		var got jsonrpc.JSONRPCError
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Fatalf("unable to decode response %q: %s", body, err)
+		}
+		if got.Error.Code != jsonrpc.INVALID_PARAMS || !strings.Contains(got.Error.Message, `"toolbox://artifacts/unknown"`) {
+			t.Fatalf("unexpected error: %+v", got.Error)
+		}
+	})
+	t.Run("spilled artifact", func(t *testing.T) {
+		resp, body, err := runRequest(ts, http.MethodPost, "/api/tool/export/invoke", bytes.NewBufferString(`{}`), adminHeader)
+		if err != nil || resp.StatusCode != http.StatusOK {
+			t.Fatalf("unable to invoke export: %v: %s", err, body)
+		}
+		var envelope struct {
+			Result string `json:"result"`
+		}
+		var result struct {
+			Artifact tools.Artifact `json:"artifact"`
+		}
+		if err := json.Unmarshal(body, &envelope); err != nil {
+			t.Fatalf("unable to decode response %q: %s", body, err)
+		}
+		if err := json.Unmarshal([]byte(envelope.Result), &result); err != nil {
+			t.Fatalf("unable to decode result %q: %s", envelope.Result, err)
+		}
+		uri := tools.ArtifactResourcePrefix + result.Artifact.ID
+		status, echoed, errMsg := invokeEcho(t, ts, map[string]any{"text": resourceRef(uri)}, adminHeader)
+		if status != http.StatusOK {
+			t.Fatalf("unexpected status code %d: %s", status, errMsg)
+		}
+		if echoed["text"] != "id\n1\n2\n" {
+			t.Fatalf("unexpected arguments: %v", echoed)
+		}
+		// the caller must be authorized to invoke the tool that spilled it
+		status, _, errMsg = invokeEcho(t, ts, map[string]any{"text": resourceRef(uri)}, nil)
+		if status != http.StatusBadRequest || !strings.Contains(errMsg, uri) {
+			t.Fatalf("unexpected status code %d: %s", status, errMsg)
+		}
+	})
+}
+
+func TestArtifactReferenceAuth(t *testing.T) {
+	store := newTestArtifactStore(t, t.TempDir(), time.Hour)
+	ts := setUpResourceRefServer(t, store, 0, 0)
+	owned := upload(t, ts, "owned", adminHeader)
+	otherHeader := map[string]string{"other_token": "valid"}
+
+	tcs := []struct {
+		desc     string
+		artifact uploadedArtifact
+		header   map[string]string
+		want     int
+	}{
+		{desc: "owner", artifact: owned, header: adminHeader, want: http.StatusOK},
+		{desc: "without auth header", artifact: owned, want: http.StatusBadRequest},
+		{desc: "same subject of another authService", artifact: owned, header: otherHeader, want: http.StatusBadRequest},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			status, _, errMsg := invokeEcho(t, ts, map[string]any{"text": resourceRef(tc.artifact.URI)}, tc.header)
+			if status != tc.want {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", status, tc.want, errMsg)
+			}
+			if status != http.StatusOK && !strings.Contains(errMsg, fmt.Sprintf("%q: not authorized", tc.artifact.URI)) {
+				t.Fatalf("unexpected error: %s", errMsg)
+			}
+		})
+	}
+}
+
+func TestArtifactReferenceExpired(t *testing.T) {
+	// the artifacts of this store expire as soon as they are uploaded
+	store := &artifactStore{dir: t.TempDir(), ttl: time.Nanosecond}
+	ts := setUpResourceRefServer(t, store, 0, 0)
+	artifact := upload(t, ts, "expired", adminHeader)
+
+	status, _, errMsg := invokeEcho(t, ts, map[string]any{"text": resourceRef(artifact.URI)}, adminHeader)
+	if status != http.StatusBadRequest {
+		t.Fatalf("unexpected status code %d: %s", status, errMsg)
+	}
+	want := fmt.Sprintf(`unable to resolve resource %q: %s`, artifact.URI, errArtifactNotFound)
+	if !strings.Contains(errMsg, want) {
+		t.Fatalf("unexpected error: got %q, want it to contain %q", errMsg, want)
+	}
+}
+
+func TestArtifactReferenceLimit(t *testing.T) {
+	store := newTestArtifactStore(t, t.TempDir(), time.Hour)
+	ts := setUpResourceRefServer(t, store, 8, 10)
+	artifact := upload(t, ts, "123456", adminHeader)
+
+	status, _, errMsg := invokeEcho(t, ts, map[string]any{"text": resourceRef(artifact.URI)}, adminHeader)
+	if status != http.StatusOK {
+		t.Fatalf("unexpected status code %d: %s", status, errMsg)
+	}
+	// the limit applies to the total of the resources of an invocation
+	status, _, errMsg = invokeEcho(t, ts, map[string]any{"text": resourceRef(artifact.URI), "extra": resourceRef(artifact.URI)}, adminHeader)
+	if status != http.StatusBadRequest || !strings.Contains(errMsg, "exceed the limit of 10 bytes per invocation") {
+		t.Fatalf("unexpected status code %d: %s", status, errMsg)
+	}
+
+	resp, body, err := runRequest(ts, http.MethodPut, "/api/artifacts", strings.NewReader("123456789"), map[string]string{"Content-Type": "text/plain", "admin_token": "valid"})
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusRequestEntityTooLarge || !strings.Contains(string(body), "maximum size of 8 bytes") {
+		t.Fatalf("unexpected status code %d: %s", resp.StatusCode, body)
+	}
+}
+
+func TestArtifactUploadAuth(t *testing.T) {
+	store := newTestArtifactStore(t, t.TempDir(), time.Hour)
+	ts := setUpResourceRefServer(t, store, 0, 0)
+
+	tcs := []struct {
+		desc   string
+		header map[string]string
+		want   int
+	}{
+		{desc: "without auth header", want: http.StatusUnauthorized},
+		{desc: "with invalid auth header", header: map[string]string{"admin_token": "invalid"}, want: http.StatusUnauthorized},
+		{desc: "with auth header of another authService", header: map[string]string{"other_token": "valid"}, want: http.StatusUnauthorized},
+		{desc: "with auth header", header: adminHeader, want: http.StatusCreated},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			h := map[string]string{"Content-Type": "text/plain"}
+			maps.Copy(h, tc.header)
+			resp, body, err := runRequest(ts, http.MethodPut, "/api/artifacts", strings.NewReader("content"), h)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.want {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, tc.want, body)
+			}
+		})
+	}
+}
+
+func TestArtifactRoutesDisabled(t *testing.T) {
+	t.Run("uploads", func(t *testing.T) {
+		// the artifact server spills results, but has no upload authServices
+		ts := setUpArtifactServer(t, newTestArtifactStore(t, t.TempDir(), time.Hour), nil)
+		resp, body, err := runRequest(ts, http.MethodPut, "/artifacts", strings.NewReader("content"), map[string]string{"Content-Type": "text/plain", "admin_token": "valid"})
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		if resp.StatusCode != http.StatusNotFound {
+			t.Fatalf("unexpected status code %d: %s", resp.StatusCode, body)
+		}
+	})
+	t.Run("artifacts", func(t *testing.T) {
+		ts := setUpArtifactServer(t, nil, nil)
+		resp, body := download(t, ts.URL+"/artifacts/00000000-0000-0000-0000-000000000000", nil)
+		if resp.StatusCode != http.StatusNotFound {
+			t.Fatalf("unexpected status code %d: %s", resp.StatusCode, body)
+		}
+	})
+}
+
+func TestArtifactQuota(t *testing.T) {
+	owners := map[string]string{"admin": "admin"}
+	exists := func(store *artifactStore, id string) bool {
+		_, f, err := store.openData(id)
+		if err != nil {
+			return false
+		}
+		f.Close()
+		return true
+	}
+
+	t.Run("count", func(t *testing.T) {
+		store := &artifactStore{dir: t.TempDir(), ttl: time.Hour, maxCount: 2}
+		var ids []string
+		for _, content := range []string{"a", "b", "c"} {
+			artifact, err := store.upload(strings.NewReader(content), owners)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			ids = append(ids, artifact.ID)
+			// the modification times of the artifacts order them
+			time.Sleep(10 * time.Millisecond)
+		}
+		w, err := store.forBaseURL("").Create(context.Background(), "export", tools.SpillFormatCSV)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		spilled, err := w.Commit()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		got := []bool{exists(store, ids[0]), exists(store, ids[1]), exists(store, ids[2]), exists(store, spilled.ID)}
+		if diff := cmp.Diff([]bool{false, false, true, true}, got); diff != "" {
+			t.Fatalf("unexpected artifacts after eviction: diff %v", diff)
+		}
+	})
+	t.Run("bytes", func(t *testing.T) {
+		store := &artifactStore{dir: t.TempDir(), ttl: time.Hour, maxBytes: 10}
+		var ids []string
+		for _, content := range []string{"123456", "123456", strings.Repeat("x", 20)} {
+			artifact, err := store.upload(strings.NewReader(content), owners)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			ids = append(ids, artifact.ID)
+			time.Sleep(10 * time.Millisecond)
+		}
+		// an artifact larger than the quota evicts all the others, but is kept
+		got := []bool{exists(store, ids[0]), exists(store, ids[1]), exists(store, ids[2])}
+		if diff := cmp.Diff([]bool{false, false, true}, got); diff != "" {
+			t.Fatalf("unexpected artifacts after eviction: diff %v", diff)
+		}
+	})
+}
@@ -0,0 +1,315 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// ndjsonContentType is the content type of bulk invocations and of their
+// results, one JSON value per line.
+const ndjsonContentType = "application/x-ndjson"
+
+// bulkLine is a non-empty line of the body of a bulk invocation.
+type bulkLine struct {
+	// number is the 1-based number of the line in the body.
+	number int
+	data   []byte
+}
+
+// bulkLineResult is the outcome of the invocation of one line of a bulk
+// invocation. Either Result or Error is set.
+type bulkLineResult struct {
+	Line       int             `json:"line"`
+	Status     int             `json:"status"`
+	DurationMs int64           `json:"durationMs"`
+	Result     json.RawMessage `json:"result,omitempty"`
+	Error      string          `json:"error,omitempty"`
+}
+
+// toolBulkInvokeHandler handles the API request to invoke a Tool once per
+// line of an NDJSON body. Authorization is checked once for the request, and
+// the parameters of each line are validated on their own. The results are
+// streamed back as NDJSON in the order of the lines, with up to
+// s.bulkConcurrency lines, or the `concurrency` query parameter if lower,
+// invoked at once.
+func toolBulkInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/tool/invoke/bulk")
+	r = r.WithContext(ctx)
+	ctx = util.WithLogger(r.Context(), s.logger)
+
+	toolName := chi.URLParam(r, "toolName")
+	span.SetAttributes(attribute.String("tool_name", toolName))
+	var err error
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+
+		status := "success"
+		if err != nil {
+			status = "error"
+		}
+		s.instrumentation.ToolInvoke.Add(
+			r.Context(),
+			1,
+			metric.WithAttributes(attribute.String("toolbox.name", toolName)),
+			metric.WithAttributes(attribute.String("toolbox.operation.status", status)),
+		)
+	}()
+
+	tool, ok := s.ResourceMgr.GetTool(toolName)
+	if !ok {
+		err = fmt.Errorf("invalid tool name: tool with name %q does not exist", toolName)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
+		return
+	}
+
+	concurrency := s.bulkConcurrency
+	if raw := r.URL.Query().Get("concurrency"); raw != "" {
+		n, convErr := strconv.Atoi(raw)
+		if convErr != nil || n < 1 {
+			err = fmt.Errorf("invalid 'concurrency' query parameter %q: must be a positive integer", raw)
+			s.logger.DebugContext(ctx, err.Error())
+			_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+			return
+		}
+		concurrency = min(concurrency, n)
+	}
+
+	ctx, err = withScheduleOverride(ctx, r)
+	if err != nil {
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		return
+	}
+	ctx = s.withSession(ctx, r.Header.Get(sessionHeader))
+	ctx = s.withClientAttribution(ctx, r)
+
+	accessToken := tools.AccessToken(r.Header.Get("Authorization"))
+	if tool.RequiresClientAuthorization() && accessToken == "" {
+		err = fmt.Errorf("tool requires client authorization but access token is missing from the request header")
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusUnauthorized))
+		return
+	}
+
+	claimsFromAuth, verifiedAuthServices := s.authClaims(ctx, r.Header)
+	ctx = util.WithVerifiedAuthServices(ctx, verifiedAuthServices)
+	ctx = util.WithAuthClaims(ctx, claimsFromAuth)
+	if !tool.Authorized(verifiedAuthServices) {
+		err = fmt.Errorf("tool invocation not authorized. Please make sure your specify correct auth headers")
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusUnauthorized))
+		return
+	}
+
+	inv := httpInvocation(r, accessToken, claimsFromAuth)
+
+	lines, err := readBulkLines(r.Body, s.bulkMaxLines)
+	if err != nil {
+		var tooManyErr *tooManyLinesError
+		code := http.StatusBadRequest
+		if errors.As(err, &tooManyErr) {
+			code = http.StatusRequestEntityTooLarge
+		}
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, code))
+		return
+	}
+	s.logger.DebugContext(ctx, fmt.Sprintf("bulk invocation of %d lines with a concurrency of %d", len(lines), concurrency))
+
+	// the invocations stop once the response can no longer be written
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]chan bulkLineResult, len(lines))
+	for i := range results {
+		results[i] = make(chan bulkLineResult, 1)
+	}
+	go func() {
+		sem := make(chan struct{}, concurrency)
+		for i, line := range lines {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			go func() {
+				defer func() { <-sem }()
+				results[i] <- s.invokeBulkLine(ctx, toolName, tool, inv, line)
+			}()
+		}
+	}()
+
+	w.Header().Set("Content-Type", ndjsonContentType)
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	enc := json.NewEncoder(w)
+	for _, ch := range results {
+		var res bulkLineResult
+		select {
+		case res = <-ch:
+		case <-ctx.Done():
+			err = ctx.Err()
+			return
+		}
+		if err = enc.Encode(res); err != nil {
+			// the status code was already sent
+			s.logger.DebugContext(ctx, fmt.Sprintf("unable to write bulk invocation result: %s", err))
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
+
+// invokeBulkLine invokes tool as inv with the arguments of line.
+func (s *Server) invokeBulkLine(ctx context.Context, toolName string, tool tools.Tool, inv tools.Invocation, line bulkLine) (res bulkLineResult) {
+	start := time.Now()
+	res.Line = line.number
+	defer func() {
+		res.DurationMs = time.Since(start).Milliseconds()
+		s.ResourceMgr.recordInvocation(toolName, res.Error != "")
+		s.publishInvocation(toolName, start, res.Error != "", inv.Claims)
+	}()
+	fail := func(code int, err error) bulkLineResult {
+		res.Status, res.Error = code, err.Error()
+		return res
+	}
+
+	var data map[string]any
+	if err := util.DecodeJSONWithLimits(bytes.NewReader(line.data), &data, s.payloadLimits); err != nil {
+		var limitErr *util.PayloadLimitError
+		if errors.As(err, &limitErr) {
+			return fail(http.StatusBadRequest, fmt.Errorf("line was rejected: %w", err))
+		}
+		return fail(http.StatusBadRequest, fmt.Errorf("line was invalid JSON: %w", err))
+	}
+	if err := tools.ResolveResourceReferences(ctx, data); err != nil {
+		return fail(http.StatusBadRequest, fmt.Errorf("provided parameters were invalid: %w", err))
+	}
+	params, err := tool.ParseParams(data, inv.Claims)
+	if err != nil {
+		if errors.Is(err, tools.ErrUnauthorized) {
+			return fail(http.StatusUnauthorized, err)
+		}
+		return fail(http.StatusBadRequest, fmt.Errorf("provided parameters were invalid: %w", err))
+	}
+
+	result, err := tool.Invoke(ctx, inv.WithParams(params))
+	if mr, ok := result.(tools.MeteredResult); ok {
+		result = mr.Result
+	}
+	if it, ok := result.(tools.RowIterator); ok && err == nil {
+		result, err = tools.CollectRows(it)
+	}
+	if err != nil {
+		code := bulkErrorStatus(tool, err)
+		return fail(code, fmt.Errorf("error while invoking tool: %w", params.RedactError(err)))
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		return fail(http.StatusInternalServerError, fmt.Errorf("unable to marshal result: %w", err))
+	}
+	tools.RecordInvocation(ctx, toolName, tool, params, tools.RowCount(result))
+	res.Status, res.Result = http.StatusOK, b
+	return res
+}
+
+// bulkErrorStatus returns the status of a line of a bulk invocation whose
+// invocation failed with err, as toolInvokeHandler would respond with.
+func bulkErrorStatus(tool tools.Tool, err error) int {
+	var disabledErr *tools.KindDisabledError
+	var deniedErr *tools.PolicyDeniedError
+	var statusErr *tools.StatusError
+	errStr := err.Error()
+	switch {
+	case strings.Contains(errStr, "Error 401"), strings.Contains(errStr, "Error 403"):
+		if !tool.RequiresClientAuthorization() {
+			// ADC lacking permission or credentials configuration error.
+			return http.StatusInternalServerError
+		}
+		if strings.Contains(errStr, "Error 401") {
+			return http.StatusUnauthorized
+		}
+		return http.StatusForbidden
+	case errors.Is(err, tools.ErrToolBusy), errors.Is(err, tools.ErrToolUnavailable), errors.Is(err, tools.ErrBackendUnavailable):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, tools.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.As(err, &disabledErr), errors.As(err, &deniedErr):
+		return http.StatusForbidden
+	case errors.As(err, &statusErr):
+		return statusErr.Code
+	}
+	return http.StatusBadRequest
+}
+
+// tooManyLinesError is returned when a bulk invocation has more lines than
+// allowed.
+type tooManyLinesError struct {
+	max int
+}
+
+func (e *tooManyLinesError) Error() string {
+	return fmt.Sprintf("bulk invocation has more than %d lines", e.max)
+}
+
+// readBulkLines reads the non-empty lines of body, up to maxLines of them.
+func readBulkLines(body io.Reader, maxLines int) ([]bulkLine, error) {
+	var lines []bulkLine
+	br := bufio.NewReader(body)
+	for number := 1; ; number++ {
+		data, err := br.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("unable to read request body: %w", err)
+		}
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 {
+			if len(lines) == maxLines {
+				return nil, &tooManyLinesError{max: maxLines}
+			}
+			lines = append(lines, bulkLine{number: number, data: trimmed})
+		}
+		if err != nil {
+			break
+		}
+	}
+	if len(lines) == 0 {
+		return nil, errors.New("bulk invocation has no lines")
+	}
+	return lines, nil
+}
@@ -0,0 +1,225 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// sleepyTool is a MockTool that sleeps for `delay` milliseconds before
+// returning it, and tracks how many of its invocations run at once.
+type sleepyTool struct {
+	MockTool
+	running *atomic.Int32
+	peak    *atomic.Int32
+}
+
+func (t sleepyTool) Invoke(_ context.Context, inv tools.Invocation) (any, error) {
+	n := t.running.Add(1)
+	defer t.running.Add(-1)
+	for p := t.peak.Load(); n > p && !t.peak.CompareAndSwap(p, n); p = t.peak.Load() {
+	}
+	delay := inv.Params.AsMap()["delay"].(int)
+	time.Sleep(time.Duration(delay) * time.Millisecond)
+	return delay, nil
+}
+
+func newSleepyTool() sleepyTool {
+	return sleepyTool{
+		MockTool: MockTool{
+			Name:   "sleepy",
+			Params: tools.Parameters{tools.NewIntParameter("delay", "How long to sleep, in milliseconds.")},
+		},
+		running: new(atomic.Int32),
+		peak:    new(atomic.Int32),
+	}
+}
+
+// parseBulkResults decodes the NDJSON results of a bulk invocation.
+func parseBulkResults(t *testing.T, body []byte) []bulkLineResult {
+	t.Helper()
+	var results []bulkLineResult
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		var res bulkLineResult
+		if err := json.Unmarshal(scanner.Bytes(), &res); err != nil {
+			t.Fatalf("unable to parse result line %q: %s", scanner.Text(), err)
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+func TestToolBulkInvokeOrder(t *testing.T) {
+	tool := newSleepyTool()
+	toolsMap := map[string]tools.Tool{tool.Name: tool}
+	r, shutdown := setUpServer(t, "api", toolsMap, nil)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	// the first lines take the longest, so they finish last
+	var body strings.Builder
+	var want []int
+	for i := range 12 {
+		delay := 60 - 5*i
+		fmt.Fprintf(&body, "{\"delay\": %d}\n", delay)
+		want = append(want, delay)
+	}
+
+	resp, respBody, err := runRequest(ts, http.MethodPost, "/tool/sleepy/invoke/bulk?concurrency=4", strings.NewReader(body.String()), map[string]string{"Content-Type": ndjsonContentType})
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: got %d, want %d, %s", resp.StatusCode, http.StatusOK, respBody)
+	}
+	if got := resp.Header.Get("Content-Type"); got != ndjsonContentType {
+		t.Fatalf("unexpected content type: got %q, want %q", got, ndjsonContentType)
+	}
+
+	var got []int
+	for i, res := range parseBulkResults(t, respBody) {
+		if res.Line != i+1 || res.Status != http.StatusOK {
+			t.Fatalf("unexpected result %d: %+v", i, res)
+		}
+		var delay int
+		if err := json.Unmarshal(res.Result, &delay); err != nil {
+			t.Fatalf("unable to parse result %d: %s", i, err)
+		}
+		if res.DurationMs < int64(delay) {
+			t.Errorf("result %d took %dms, want at least %dms", i, res.DurationMs, delay)
+		}
+		got = append(got, delay)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("incorrect results: diff %v", diff)
+	}
+	if peak := tool.peak.Load(); peak < 2 || peak > 4 {
+		t.Fatalf("got %d invocations at once, want between 2 and 4", peak)
+	}
+}
+
+func TestToolBulkInvokeMalformedLine(t *testing.T) {
+	tool := newSleepyTool()
+	toolsMap := map[string]tools.Tool{tool.Name: tool}
+	r, shutdown := setUpServer(t, "api", toolsMap, nil)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	body := "{\"delay\": 1}\n\n{\"delay\": \n{\"delay\": \"two\"}\n{\"delay\": 3}\n"
+	resp, respBody, err := runRequest(ts, http.MethodPost, "/tool/sleepy/invoke/bulk", strings.NewReader(body), map[string]string{"Content-Type": ndjsonContentType})
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: got %d, want %d, %s", resp.StatusCode, http.StatusOK, respBody)
+	}
+
+	results := parseBulkResults(t, respBody)
+	type summary struct {
+		Line, Status int
+		Result       string
+		IsErr        bool
+	}
+	var got []summary
+	for _, res := range results {
+		got = append(got, summary{Line: res.Line, Status: res.Status, Result: string(res.Result), IsErr: res.Error != ""})
+	}
+	// blank lines are skipped, but keep their numbers
+	want := []summary{
+		{Line: 1, Status: http.StatusOK, Result: "1"},
+		{Line: 3, Status: http.StatusBadRequest, IsErr: true},
+		{Line: 4, Status: http.StatusBadRequest, IsErr: true},
+		{Line: 5, Status: http.StatusOK, Result: "3"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("incorrect results: diff %v", diff)
+	}
+	if !strings.Contains(results[1].Error, "line was invalid JSON") {
+		t.Errorf("unexpected error of line 3: %q", results[1].Error)
+	}
+	if !strings.Contains(results[2].Error, "provided parameters were invalid") {
+		t.Errorf("unexpected error of line 4: %q", results[2].Error)
+	}
+}
+
+func TestToolBulkInvokeLineCap(t *testing.T) {
+	tool := newSleepyTool()
+	toolsMap := map[string]tools.Tool{tool.Name: tool, tool4.Name: tool4}
+	r, shutdown := setUpServer(t, "api", toolsMap, nil)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	tcs := []struct {
+		desc       string
+		tool       string
+		body       string
+		wantStatus int
+	}{
+		{
+			desc:       "too many lines",
+			tool:       tool.Name,
+			body:       strings.Repeat("{\"delay\": 0}\n", DefaultBulkInvokeMaxLines+1),
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			desc:       "as many lines as allowed",
+			tool:       tool.Name,
+			body:       strings.Repeat("{\"delay\": 0}\n", DefaultBulkInvokeMaxLines),
+			wantStatus: http.StatusOK,
+		},
+		{
+			desc:       "no lines",
+			tool:       tool.Name,
+			body:       "\n\n",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			desc:       "unauthorized",
+			tool:       tool4.Name,
+			body:       "{}\n",
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			resp, respBody, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke/bulk", tc.tool), strings.NewReader(tc.body), map[string]string{"Content-Type": ndjsonContentType})
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.wantStatus {
+				t.Fatalf("unexpected status code: got %d, want %d, %s", resp.StatusCode, tc.wantStatus, respBody)
+			}
+		})
+	}
+	if n := tool.peak.Load(); n > DefaultBulkInvokeConcurrency {
+		t.Fatalf("got %d invocations at once, want at most %d", n, DefaultBulkInvokeConcurrency)
+	}
+}
@@ -25,8 +25,10 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/state"
 	"github.com/googleapis/genai-toolbox/internal/telemetry"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
 )
 
 // fakeVersionString is used as a temporary version string in tests
@@ -44,7 +46,7 @@ type MockTool struct {
 	requiresClientAuthrorization bool
 }
 
-func (t MockTool) Invoke(context.Context, tools.ParamValues, tools.AccessToken) (any, error) {
+func (t MockTool) Invoke(context.Context, tools.Invocation) (any, error) {
 	mock := []any{t.Name}
 	return mock, nil
 }
@@ -168,8 +170,24 @@ func setUpResources(t *testing.T, mockTools []MockTool) (map[string]tools.Tool,
 	return toolsMap, toolsets
 }
 
+// setUpTaggedResources is like setUpResources, but tool1 is tagged
+// "reporting" and tool2 is tagged "reporting" and "finance".
+func setUpTaggedResources(t *testing.T) (map[string]tools.Tool, map[string]tools.Toolset) {
+	toolsMap := map[string]tools.Tool{
+		tool1.Name: tools.TaggedTool{Tool: tool1, Tags: []string{"reporting"}},
+		tool2.Name: tools.TaggedTool{Tool: tool2, Tags: []string{"reporting", "finance"}},
+		tool3.Name: tool3,
+	}
+	tc := tools.ToolsetConfig{Name: "", ToolNames: []string{tool1.Name, tool2.Name, tool3.Name}}
+	toolset, err := tc.Initialize(fakeVersionString, toolsMap)
+	if err != nil {
+		t.Fatalf("unable to initialize toolset: %s", err)
+	}
+	return toolsMap, map[string]tools.Toolset{"": toolset}
+}
+
 // setUpServer create a new server with tools and toolsets that are given
-func setUpServer(t *testing.T, router string, tools map[string]tools.Tool, toolsets map[string]tools.Toolset) (chi.Router, func()) {
+func setUpServer(t *testing.T, router string, toolsMap map[string]tools.Tool, toolsets map[string]tools.Toolset) (chi.Router, func()) {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
@@ -189,15 +207,26 @@ func setUpServer(t *testing.T, router string, tools map[string]tools.Tool, tools
 
 	sseManager := newSseManager(ctx)
 
-	resourceManager := NewResourceManager(nil, nil, tools, toolsets)
+	resourceManager := NewResourceManager(nil, nil, toolsMap, toolsets)
 
 	server := Server{
-		version:         fakeVersionString,
-		logger:          testLogger,
-		instrumentation: instrumentation,
-		sseManager:      sseManager,
-		ResourceMgr:     resourceManager,
+		version:          fakeVersionString,
+		logger:           testLogger,
+		instrumentation:  instrumentation,
+		sseManager:       sseManager,
+		wsManager:        newWsManager(),
+		wsMaxMessageSize: DefaultMcpWebsocketMaxMessageSize,
+		idempotency:      &tools.Idempotency{Store: state.NewMemory(), TTL: DefaultIdempotencyTTL},
+		bulkMaxLines:     DefaultBulkInvokeMaxLines,
+		bulkConcurrency:  DefaultBulkInvokeConcurrency,
+		payloadLimits: util.PayloadLimits{
+			MaxDepth:        DefaultMaxPayloadDepth,
+			MaxKeys:         DefaultMaxPayloadKeys,
+			MaxStringLength: DefaultMaxPayloadStringLength,
+		},
+		ResourceMgr: resourceManager,
 	}
+	server.histories = newSessionHistories(10, DefaultSessionHistoryTTL, resourceManager.GetTool)
 
 	var r chi.Router
 	switch router {
@@ -15,12 +15,19 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/auth/clientcert"
 	"github.com/googleapis/genai-toolbox/internal/auth/google"
+	"github.com/googleapis/genai-toolbox/internal/secrets"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/util"
@@ -33,6 +40,26 @@ type ServerConfig struct {
 	Address string
 	// Port is the port the server will listen on.
 	Port int
+	// Listen is a list of addresses the server will listen on, such as
+	// `tcp://host:port` or `unix:///path/to.sock`. If empty, the server listens
+	// on Address and Port.
+	Listen []string
+	// SocketMode is the file mode applied to Unix domain sockets.
+	SocketMode SocketMode
+	// TLSCertFile and TLSKeyFile are the PEM files of the certificate and
+	// private key of the server. If set, every listener serves over TLS.
+	TLSCertFile string
+	TLSKeyFile  string
+	// TLSClientCAFile is a PEM bundle of the CAs that client certificates
+	// are verified against.
+	TLSClientCAFile string
+	// TLSClientAuth is either TLSClientAuthVerifyIfGiven or
+	// TLSClientAuthRequireAndVerify. If empty, client certificates are
+	// required if TLSClientCAFile is set.
+	TLSClientAuth string
+	// TLSReload reloads the TLS files when they change, so that certificates
+	// can be rotated without a restart.
+	TLSReload bool
 	// SourceConfigs defines what sources of data are available for tools.
 	SourceConfigs SourceConfigs
 	// AuthServiceConfigs defines what sources of authentication are available for tools.
@@ -57,8 +84,197 @@ type ServerConfig struct {
 	DisableReload bool
 	// UI indicates if Toolbox UI endpoints (/ui) are available
 	UI bool
+	// DefaultSchedule restricts when the tools that do not have a `schedule`
+	// of their own can be invoked. If nil, they can be invoked at any time.
+	DefaultSchedule *tools.Schedule
+	// IncludeUsageMetadata indicates if the responses of the tools that do not
+	// set `includeUsageMetadata` themselves include usage metadata.
+	IncludeUsageMetadata bool
+	// DefaultResultTimezone, if set, is the timezone the timestamps of the
+	// results of the tools that do not set `resultTimezone` are converted to.
+	DefaultResultTimezone *tools.ResultTimezone
+	// DefaultAuthRequired lists the authServices required to invoke the tools
+	// that neither require authServices themselves, nor inherit them from
+	// their toolsets, nor use client OAuth.
+	DefaultAuthRequired []string
+	// AdminAuthService is the name of the authService that guards the admin
+	// endpoints, such as rotating source credentials. If empty, the admin
+	// endpoints are disabled.
+	AdminAuthService string
+	// AdminSubjects lists the `sub` claims verified by AdminAuthService that
+	// are allowed to call the admin endpoints.
+	AdminSubjects []string
+	// AdminEmails lists the verified `email` claims verified by
+	// AdminAuthService that are allowed to call the admin endpoints. Either
+	// AdminSubjects or AdminEmails is required if AdminAuthService is set.
+	AdminEmails []string
+	// SourceInitTimeout bounds the initialization of each source. If zero,
+	// DefaultSourceInitTimeout is used.
+	SourceInitTimeout time.Duration
+	// SourceInitConcurrency is the maximum number of sources initialized at
+	// once. If zero, DefaultSourceInitConcurrency is used.
+	SourceInitConcurrency int
+	// ArtifactDir is the directory that tools with `spillToFile` write large
+	// results to, and uploaded artifacts are stored in. Artifacts are only
+	// enabled if it is set, if ArtifactUploadAuthServices is set or if a tool
+	// sets `spillToFile`. If empty, a directory in the system temporary
+	// directory is used.
+	ArtifactDir string
+	// ArtifactTTL is how long spilled results can be downloaded before they
+	// are removed. If zero, DefaultArtifactTTL is used.
+	ArtifactTTL time.Duration
+	// ArtifactUploadMaxBytes is the maximum size in bytes of an artifact
+	// uploaded by a client. If zero, DefaultArtifactUploadMaxBytes is used.
+	ArtifactUploadMaxBytes int64
+	// ArtifactUploadAuthServices lists the authServices that verify the
+	// clients uploading artifacts. If empty, uploads are disabled.
+	ArtifactUploadAuthServices []string
+	// ArtifactMaxBytes is the maximum total size in bytes of the stored
+	// artifacts. The oldest artifacts are removed to stay below it. If zero,
+	// DefaultArtifactMaxBytes is used.
+	ArtifactMaxBytes int64
+	// ArtifactMaxCount is the maximum number of stored artifacts. The oldest
+	// artifacts are removed to stay below it. If zero, DefaultArtifactMaxCount
+	// is used.
+	ArtifactMaxCount int
+	// MaxResolvedResourceBytes is the maximum total length in bytes of the
+	// resources referenced by the arguments of an invocation. If zero,
+	// DefaultMaxResolvedResourceBytes is used.
+	MaxResolvedResourceBytes int64
+	// ClientAttribution is how the caller of a tool is identified to the
+	// backends it queries, either "auth" or "header". If empty, callers are
+	// not identified.
+	ClientAttribution string
+	// McpWebsocketMaxMessageSize is the maximum size in bytes of a message
+	// received over the MCP websocket transport. If zero,
+	// DefaultMcpWebsocketMaxMessageSize is used.
+	McpWebsocketMaxMessageSize int64
+	// Dev enables endpoints meant for tool authors, such as rendering the
+	// statement of a tool. It must not be set in production.
+	Dev bool
+	// StateRedisAddress is the address of a Redis or Valkey server that keeps
+	// the state shared by the replicas of the server, such as cached source
+	// schemas. It is either `host:port` or a `redis://` or `rediss://` URL.
+	// If empty, each replica keeps its own state in memory.
+	StateRedisAddress string
+	// StateRedisTLS connects to StateRedisAddress over TLS.
+	StateRedisTLS bool
+	// StateKeyPrefix is prepended to the keys written to StateRedisAddress.
+	// If empty, state.DefaultKeyPrefix is used.
+	StateKeyPrefix string
+	// StateMaxTTL is the ceiling of the TTL of the keys written to
+	// StateRedisAddress. If zero, DefaultStateMaxTTL is used.
+	StateMaxTTL time.Duration
+	// IdempotencyTTL is how long the result of an invocation of a tool with
+	// `idempotencyKeys: accepted` is replayed to invocations with the same
+	// idempotency key. If zero, DefaultIdempotencyTTL is used.
+	IdempotencyTTL time.Duration
+	// SessionHistorySize is the number of invocations of tools with a
+	// statement kept in the history of each session, for the
+	// `session-history` tool. If zero, no history is kept.
+	SessionHistorySize int
+	// SessionHistoryTTL is how long the history of a session is kept after
+	// its last invocation. If zero, DefaultSessionHistoryTTL is used.
+	SessionHistoryTTL time.Duration
+	// StickySessionTTL is how long a connection pinned to a session by a tool
+	// with `stickySession` is kept after its last invocation. If zero,
+	// DefaultStickySessionTTL is used.
+	StickySessionTTL time.Duration
+	// StickySessionMaxConns is the most connections of each source pinned to
+	// sessions at once. If zero, DefaultStickySessionMaxConns is used.
+	StickySessionMaxConns int
+	// DisabledToolKinds lists the kinds of tools that cannot be invoked. The
+	// tools of these kinds are still listed, marked as disabled.
+	DisabledToolKinds []string
+	// HideDisabledTools removes the tools of DisabledToolKinds from the
+	// listings of tools.
+	HideDisabledTools bool
+	// BulkInvokeMaxLines is the maximum number of lines of a bulk invocation.
+	// If zero, DefaultBulkInvokeMaxLines is used.
+	BulkInvokeMaxLines int
+	// BulkInvokeConcurrency is the maximum number of lines of a bulk
+	// invocation invoked at once. If zero, DefaultBulkInvokeConcurrency is
+	// used.
+	BulkInvokeConcurrency int
+	// MaxPayloadDepth is the maximum nesting depth of the JSON arguments of
+	// an invocation. If zero, DefaultMaxPayloadDepth is used.
+	MaxPayloadDepth int
+	// MaxPayloadKeys is the maximum total number of keys of the objects of
+	// the JSON arguments of an invocation. If zero, DefaultMaxPayloadKeys is
+	// used.
+	MaxPayloadKeys int
+	// MaxPayloadStringLength is the maximum length in bytes of a string of
+	// the JSON arguments of an invocation. If zero,
+	// DefaultMaxPayloadStringLength is used.
+	MaxPayloadStringLength int
+	// EventsPubSubTopic is the full name of a Pub/Sub topic that an event is
+	// published to for every invocation of a tool, such as
+	// `projects/my-project/topics/toolbox-events`. If empty, and EventsFile
+	// is empty too, no events are published.
+	EventsPubSubTopic string
+	// EventsFile is a file that the invocation events are written to as JSON
+	// lines instead, or "-" for stdout.
+	EventsFile string
+	// EventsBufferSize is the number of invocation events buffered while
+	// they wait to be published. If zero, events.DefaultBufferSize is used.
+	EventsBufferSize int
+	// EventsBatchSize is the most invocation events published at once. If
+	// zero, events.DefaultBatchSize is used.
+	EventsBatchSize int
+	// EventsFlushInterval is how long an invocation event waits for its
+	// batch to fill up before it is published anyway. If zero,
+	// events.DefaultFlushInterval is used.
+	EventsFlushInterval time.Duration
 }
 
+const (
+	// DefaultSourceInitTimeout is the default of ServerConfig.SourceInitTimeout.
+	DefaultSourceInitTimeout = 30 * time.Second
+	// DefaultSourceInitConcurrency is the default of
+	// ServerConfig.SourceInitConcurrency.
+	DefaultSourceInitConcurrency = 8
+	// DefaultArtifactTTL is the default of ServerConfig.ArtifactTTL.
+	DefaultArtifactTTL = time.Hour
+	// DefaultArtifactUploadMaxBytes is the default of
+	// ServerConfig.ArtifactUploadMaxBytes.
+	DefaultArtifactUploadMaxBytes = 10 << 20
+	// DefaultArtifactMaxBytes is the default of ServerConfig.ArtifactMaxBytes.
+	DefaultArtifactMaxBytes = 1 << 30
+	// DefaultArtifactMaxCount is the default of ServerConfig.ArtifactMaxCount.
+	DefaultArtifactMaxCount = 1000
+	// DefaultMaxResolvedResourceBytes is the default of
+	// ServerConfig.MaxResolvedResourceBytes.
+	DefaultMaxResolvedResourceBytes = 10 << 20
+	// DefaultMcpWebsocketMaxMessageSize is the default of
+	// ServerConfig.McpWebsocketMaxMessageSize.
+	DefaultMcpWebsocketMaxMessageSize = 4 << 20
+	// DefaultStateMaxTTL is the default of ServerConfig.StateMaxTTL.
+	DefaultStateMaxTTL = time.Hour
+	// DefaultIdempotencyTTL is the default of ServerConfig.IdempotencyTTL.
+	DefaultIdempotencyTTL = time.Hour
+	// DefaultBulkInvokeMaxLines is the default of
+	// ServerConfig.BulkInvokeMaxLines.
+	DefaultBulkInvokeMaxLines = 1000
+	// DefaultBulkInvokeConcurrency is the default of
+	// ServerConfig.BulkInvokeConcurrency.
+	DefaultBulkInvokeConcurrency = 8
+	// DefaultMaxPayloadDepth is the default of ServerConfig.MaxPayloadDepth.
+	DefaultMaxPayloadDepth = 100
+	// DefaultMaxPayloadKeys is the default of ServerConfig.MaxPayloadKeys.
+	DefaultMaxPayloadKeys = 100000
+	// DefaultMaxPayloadStringLength is the default of
+	// ServerConfig.MaxPayloadStringLength.
+	DefaultMaxPayloadStringLength = 16 << 20
+	// DefaultSessionHistoryTTL is the default of
+	// ServerConfig.SessionHistoryTTL.
+	DefaultSessionHistoryTTL = 30 * time.Minute
+	// DefaultStickySessionTTL is the default of ServerConfig.StickySessionTTL.
+	DefaultStickySessionTTL = 10 * time.Minute
+	// DefaultStickySessionMaxConns is the default of
+	// ServerConfig.StickySessionMaxConns.
+	DefaultStickySessionMaxConns = 10
+)
+
 type logFormat string
 
 // String is used by both fmt.Print and by Cobra in help text
@@ -136,11 +352,25 @@ func (c *SourceConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(interf
 		if !ok {
 			return fmt.Errorf("missing 'kind' field for source %q", name)
 		}
+
+		if err := secrets.ResolverFromContext(ctx).ResolveAll(ctx, v); err != nil {
+			return fmt.Errorf("unable to resolve secrets of source %q: %w", name, err)
+		}
 		kindStr, ok := kind.(string)
 		if !ok {
 			return fmt.Errorf("invalid 'kind' field for source %q (must be a string)", name)
 		}
 
+		lazy, err := extractLazyInit(name, v)
+		if err != nil {
+			return err
+		}
+
+		breaker, err := extractCircuitBreaker(tools.BreakerScopeSource, name, v)
+		if err != nil {
+			return err
+		}
+
 		yamlDecoder, err := util.NewStrictDecoder(v)
 		if err != nil {
 			return fmt.Errorf("error creating YAML decoder for source %q: %w", name, err)
@@ -150,6 +380,14 @@ func (c *SourceConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(interf
 		if err != nil {
 			return err
 		}
+		if lazy {
+			if sourceConfig, err = sources.NewLazyConfig(name, sourceConfig); err != nil {
+				return err
+			}
+		}
+		if breaker != nil {
+			sourceConfig = CircuitBreakerSourceConfig{SourceConfig: sourceConfig, Breaker: breaker}
+		}
 		(*c)[name] = sourceConfig
 	}
 	return nil
@@ -180,6 +418,10 @@ func (c *AuthServiceConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(i
 			return fmt.Errorf("missing 'kind' field for %q", name)
 		}
 
+		if err := secrets.ResolverFromContext(ctx).ResolveAll(ctx, v); err != nil {
+			return fmt.Errorf("unable to resolve secrets of %q: %w", name, err)
+		}
+
 		dec, err := util.NewStrictDecoder(v)
 		if err != nil {
 			return fmt.Errorf("error creating decoder: %w", err)
@@ -191,6 +433,12 @@ func (c *AuthServiceConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(i
 				return fmt.Errorf("unable to parse as %q: %w", kind, err)
 			}
 			(*c)[name] = actual
+		case clientcert.AuthServiceKind:
+			actual := clientcert.Config{Name: name}
+			if err := dec.DecodeContext(ctx, &actual); err != nil {
+				return fmt.Errorf("unable to parse as %q: %w", kind, err)
+			}
+			(*c)[name] = actual
 		default:
 			return fmt.Errorf("%q is not a valid kind of auth source", kind)
 		}
@@ -223,6 +471,11 @@ func (c *ToolConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(interfac
 			return fmt.Errorf("`authRequired` and `useClientOAuth` are mutually exclusive. Choose only one authentication method")
 		}
 
+		allowUnauthenticated, err := extractAllowUnauthenticated(name, v)
+		if err != nil {
+			return err
+		}
+
 		// Make `authRequired` an empty list instead of nil for Tool manifest
 		if v["authRequired"] == nil {
 			v["authRequired"] = []string{}
@@ -237,6 +490,80 @@ func (c *ToolConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(interfac
 			return fmt.Errorf("invalid 'kind' field for tool %q (must be a string)", name)
 		}
 
+		tagsCfg, err := extractTagsConfig(name, v)
+		if err != nil {
+			return err
+		}
+
+		examplesCfg, err := extractExamplesConfig(name, v)
+		if err != nil {
+			return err
+		}
+
+		timezoneCfg, err := extractResultTimezoneConfig(name, v)
+		if err != nil {
+			return err
+		}
+
+		aliasCfg, err := extractAliasConfig(name, v)
+		if err != nil {
+			return err
+		}
+
+		serializeCfg, err := extractSerializeConfig(name, v)
+		if err != nil {
+			return err
+		}
+
+		transformCfg, err := extractTransformConfig(name, v)
+		if err != nil {
+			return err
+		}
+
+		scheduleCfg, err := extractScheduleConfig(name, v)
+		if err != nil {
+			return err
+		}
+
+		webhookCfg, err := extractPreInvokeWebhookConfig(name, v)
+		if err != nil {
+			return err
+		}
+
+		breaker, err := extractCircuitBreaker(tools.BreakerScopeTool, name, v)
+		if err != nil {
+			return err
+		}
+
+		usageCfg, err := extractUsageMetadataConfig(name, v)
+		if err != nil {
+			return err
+		}
+
+		spillCfg, err := extractSpillConfig(name, kindStr, v)
+		if err != nil {
+			return err
+		}
+
+		outputSchemaCfg, err := extractOutputSchemaConfig(name, v)
+		if err != nil {
+			return err
+		}
+
+		idempotencyCfg, err := extractIdempotencyConfig(name, v)
+		if err != nil {
+			return err
+		}
+
+		pollableCfg, err := extractPollableConfig(name, v)
+		if err != nil {
+			return err
+		}
+
+		if err := resolveStatementFile(ctx, name, v); err != nil {
+			return err
+		}
+
 		yamlDecoder, err := util.NewStrictDecoder(v)
 		if err != nil {
 			return fmt.Errorf("error creating YAML decoder for tool %q: %w", name, err)
@@ -246,8 +573,785 @@ func (c *ToolConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(interfac
 		if err != nil {
 			return err
 		}
+		if allowUnauthenticated {
+			toolCfg = tools.AllowUnauthenticatedConfig{ToolConfig: toolCfg, AllowUnauthenticated: true}
+		}
+		if tagsCfg != nil {
+			tagsCfg.ToolConfig = toolCfg
+			toolCfg = *tagsCfg
+		}
+		if examplesCfg != nil {
+			examplesCfg.ToolConfig = toolCfg
+			toolCfg = *examplesCfg
+		}
+		if timezoneCfg != nil {
+			timezoneCfg.ToolConfig = toolCfg
+			toolCfg = *timezoneCfg
+		}
+		if transformCfg != nil {
+			transformCfg.ToolConfig = toolCfg
+			toolCfg = *transformCfg
+		}
+		if spillCfg != nil {
+			spillCfg.ToolConfig = toolCfg
+			toolCfg = *spillCfg
+		}
+		if outputSchemaCfg != nil {
+			outputSchemaCfg.ToolConfig = toolCfg
+			toolCfg = *outputSchemaCfg
+		}
+		if usageCfg != nil {
+			usageCfg.ToolConfig = toolCfg
+			toolCfg = *usageCfg
+		}
+		if serializeCfg != nil {
+			serializeCfg.ToolConfig = toolCfg
+			toolCfg = *serializeCfg
+		}
+		// unchanged results are still approved and wait for their turn
+		if pollableCfg != nil {
+			pollableCfg.ToolConfig = toolCfg
+			toolCfg = *pollableCfg
+		}
+		// invocations are approved before they wait for their turn
+		if webhookCfg != nil {
+			webhookCfg.ToolConfig = toolCfg
+			toolCfg = *webhookCfg
+		}
+		// invocations fail fast before they are approved or wait for their turn
+		if breaker != nil {
+			toolCfg = tools.CircuitBreakerConfig{ToolConfig: toolCfg, Breaker: breaker}
+		}
+		if scheduleCfg != nil {
+			scheduleCfg.ToolConfig = toolCfg
+			toolCfg = *scheduleCfg
+		}
+		// replays are returned without approval or waiting for a turn
+		if idempotencyCfg != nil {
+			idempotencyCfg.ToolConfig = toolCfg
+			toolCfg = *idempotencyCfg
+		}
+		if aliasCfg != nil {
+			aliasCfg.ToolConfig = toolCfg
+			toolCfg = *aliasCfg
+		}
 		(*c)[name] = toolCfg
 	}
+	return validateToolAliases(*c)
+}
+
+// extractAllowUnauthenticated removes the kind-agnostic `allowUnauthenticated`
+// field from a raw tool config. It can only be set along with an explicit
+// empty `authRequired`, so that public tools are declared as such.
+func extractAllowUnauthenticated(name string, v map[string]any) (bool, error) {
+	raw, ok := v["allowUnauthenticated"]
+	if !ok {
+		return false, nil
+	}
+	delete(v, "allowUnauthenticated")
+	allow, ok := raw.(bool)
+	if !ok {
+		return false, fmt.Errorf("invalid 'allowUnauthenticated' field for tool %q (must be a boolean)", name)
+	}
+	if !allow {
+		return false, nil
+	}
+	authRequired, ok := v["authRequired"].([]any)
+	if !ok || len(authRequired) > 0 {
+		return false, fmt.Errorf("`allowUnauthenticated` requires an explicit empty `authRequired` for tool %q", name)
+	}
+	if v["useClientOAuth"] == true {
+		return false, fmt.Errorf("`allowUnauthenticated` and `useClientOAuth` are mutually exclusive for tool %q", name)
+	}
+	return true, nil
+}
+
+// extractLazyInit removes the kind-agnostic `lazyInit` field from a raw
+// source config.
+func extractLazyInit(name string, v map[string]any) (bool, error) {
+	raw, ok := v["lazyInit"]
+	delete(v, "lazyInit")
+	if !ok {
+		return false, nil
+	}
+	lazy, ok := raw.(bool)
+	if !ok {
+		return false, fmt.Errorf("invalid 'lazyInit' field for source %q (must be a boolean)", name)
+	}
+	return lazy, nil
+}
+
+// extractCircuitBreaker removes the kind-agnostic `circuitBreaker` field from
+// a raw tool or source config, of scope, and returns the breaker it
+// describes. It returns nil if the field is not set.
+func extractCircuitBreaker(scope, name string, v map[string]any) (*tools.CircuitBreaker, error) {
+	raw, ok := v["circuitBreaker"]
+	delete(v, "circuitBreaker")
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	decoder, err := util.NewStrictDecoder(raw)
+	if err != nil {
+		return nil, fmt.Errorf("error creating YAML decoder for 'circuitBreaker' of %s %q: %w", scope, name, err)
+	}
+	var spec tools.CircuitBreakerSpec
+	if err := decoder.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("invalid 'circuitBreaker' field for %s %q: %w", scope, name, err)
+	}
+	breaker, err := tools.NewCircuitBreaker(scope, name, spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid 'circuitBreaker' field for %s %q: %w", scope, name, err)
+	}
+	return breaker, nil
+}
+
+// CircuitBreakerSourceConfig wraps the config of a source that sets
+// `circuitBreaker`. Its breaker is shared by the tools of the source that do
+// not set one of their own.
+type CircuitBreakerSourceConfig struct {
+	sources.SourceConfig
+	Breaker *tools.CircuitBreaker
+}
+
+// unwrapSourceConfig returns the config of a source without the kind-agnostic
+// wrappers of its fields.
+func unwrapSourceConfig(sc sources.SourceConfig) sources.SourceConfig {
+	if bc, ok := sc.(CircuitBreakerSourceConfig); ok {
+		sc = bc.SourceConfig
+	}
+	if lc, ok := sc.(sources.LazyConfig); ok {
+		sc = lc.SourceConfig
+	}
+	return sc
+}
+
+// isLazySourceConfig reports whether the config of a source sets `lazyInit`.
+func isLazySourceConfig(sc sources.SourceConfig) bool {
+	if bc, ok := sc.(CircuitBreakerSourceConfig); ok {
+		sc = bc.SourceConfig
+	}
+	_, ok := sc.(sources.LazyConfig)
+	return ok
+}
+
+// extractExamplesConfig removes the kind-agnostic `generateExamples` field
+// from a raw tool config. It returns nil unless the field is false.
+func extractExamplesConfig(name string, v map[string]any) (*tools.ExamplesConfig, error) {
+	raw, ok := v["generateExamples"]
+	delete(v, "generateExamples")
+	if !ok {
+		return nil, nil
+	}
+	generate, ok := raw.(bool)
+	if !ok {
+		return nil, fmt.Errorf("invalid 'generateExamples' field for tool %q (must be a boolean)", name)
+	}
+	if generate {
+		return nil, nil
+	}
+	return &tools.ExamplesConfig{}, nil
+}
+
+// extractTagsConfig removes the kind-agnostic `tags` field from a raw tool
+// config and validates it. It returns nil if the field is not set or empty.
+func extractTagsConfig(name string, v map[string]any) (*tools.TagsConfig, error) {
+	raw, ok := v["tags"]
+	delete(v, "tags")
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	list, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid 'tags' field for tool %q (must be a list of strings)", name)
+	}
+	cfg := &tools.TagsConfig{}
+	for _, t := range list {
+		tag, ok := t.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid tag %v for tool %q (must be a string)", t, name)
+		}
+		if err := tools.ValidateTag(tag); err != nil {
+			return nil, fmt.Errorf("invalid 'tags' field for tool %q: %w", name, err)
+		}
+		if !slices.Contains(cfg.Tags, tag) {
+			cfg.Tags = append(cfg.Tags, tag)
+		}
+	}
+	if len(cfg.Tags) == 0 {
+		return nil, nil
+	}
+	return cfg, nil
+}
+
+// extractAliasConfig removes the kind-agnostic `aliases`, `deprecated` and
+// `deprecationMessage` fields from a raw tool config. It returns nil if none
+// of them are set.
+func extractAliasConfig(name string, v map[string]any) (*tools.AliasConfig, error) {
+	rawAliases, hasAliases := v["aliases"]
+	rawDeprecated, hasDeprecated := v["deprecated"]
+	rawMessage, hasMessage := v["deprecationMessage"]
+	delete(v, "aliases")
+	delete(v, "deprecated")
+	delete(v, "deprecationMessage")
+	if !hasAliases && !hasDeprecated && !hasMessage {
+		return nil, nil
+	}
+
+	cfg := &tools.AliasConfig{}
+	if hasAliases {
+		list, ok := rawAliases.([]any)
+		if !ok {
+			return nil, fmt.Errorf("invalid 'aliases' field for tool %q (must be a list of strings)", name)
+		}
+		for _, a := range list {
+			alias, ok := a.(string)
+			if !ok || !tools.IsValidName(alias) || alias == "" {
+				return nil, fmt.Errorf("invalid alias %v for tool %q", a, name)
+			}
+			cfg.Aliases = append(cfg.Aliases, alias)
+		}
+	}
+	if hasDeprecated {
+		deprecated, ok := rawDeprecated.(bool)
+		if !ok {
+			return nil, fmt.Errorf("invalid 'deprecated' field for tool %q (must be a boolean)", name)
+		}
+		cfg.Deprecated = deprecated
+	}
+	if hasMessage {
+		message, ok := rawMessage.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid 'deprecationMessage' field for tool %q (must be a string)", name)
+		}
+		cfg.DeprecationMessage = message
+	}
+	return cfg, nil
+}
+
+// extractSerializeConfig removes the kind-agnostic `serialize` and
+// `queueTimeout` fields from a raw tool config. It returns nil unless
+// `serialize` is true.
+func extractSerializeConfig(name string, v map[string]any) (*tools.SerializeConfig, error) {
+	rawSerialize, hasSerialize := v["serialize"]
+	rawTimeout, hasTimeout := v["queueTimeout"]
+	delete(v, "serialize")
+	delete(v, "queueTimeout")
+
+	serialize := false
+	if hasSerialize {
+		var ok bool
+		serialize, ok = rawSerialize.(bool)
+		if !ok {
+			return nil, fmt.Errorf("invalid 'serialize' field for tool %q (must be a boolean)", name)
+		}
+	}
+	if !serialize {
+		if hasTimeout {
+			return nil, fmt.Errorf("`queueTimeout` requires `serialize: true` for tool %q", name)
+		}
+		return nil, nil
+	}
+
+	cfg := &tools.SerializeConfig{}
+	if hasTimeout {
+		timeout, ok := rawTimeout.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid 'queueTimeout' field for tool %q (must be a duration string)", name)
+		}
+		d, err := time.ParseDuration(timeout)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid 'queueTimeout' field for tool %q: must be a positive duration such as \"30s\"", name)
+		}
+		cfg.QueueTimeout = d
+	}
+	return cfg, nil
+}
+
+// extractTransformConfig removes the kind-agnostic `resultTransform` field
+// from a raw tool config and validates it. It returns nil if the field is not
+// set.
+func extractTransformConfig(name string, v map[string]any) (*tools.TransformConfig, error) {
+	raw, ok := v["resultTransform"]
+	delete(v, "resultTransform")
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	decoder, err := util.NewStrictDecoder(raw)
+	if err != nil {
+		return nil, fmt.Errorf("error creating YAML decoder for 'resultTransform' of tool %q: %w", name, err)
+	}
+	var spec tools.ResultTransformSpec
+	if err := decoder.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("invalid 'resultTransform' field for tool %q: %w", name, err)
+	}
+	transform, err := tools.NewResultTransform(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid 'resultTransform' field for tool %q: %w", name, err)
+	}
+	return &tools.TransformConfig{Transform: transform}, nil
+}
+
+// extractScheduleConfig removes the kind-agnostic `schedule` field from a raw
+// tool config and validates it. It returns nil if the field is not set.
+func extractScheduleConfig(name string, v map[string]any) (*tools.ScheduleConfig, error) {
+	raw, ok := v["schedule"]
+	delete(v, "schedule")
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	decoder, err := util.NewStrictDecoder(raw)
+	if err != nil {
+		return nil, fmt.Errorf("error creating YAML decoder for 'schedule' of tool %q: %w", name, err)
+	}
+	var spec tools.ScheduleSpec
+	if err := decoder.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("invalid 'schedule' field for tool %q: %w", name, err)
+	}
+	schedule, err := tools.NewSchedule(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid 'schedule' field for tool %q: %w", name, err)
+	}
+	return &tools.ScheduleConfig{Schedule: schedule}, nil
+}
+
+// extractPreInvokeWebhookConfig removes the kind-agnostic `preInvokeWebhook`
+// field from a raw tool config and validates it. It returns nil if the field is
+// not set.
+func extractPreInvokeWebhookConfig(name string, v map[string]any) (*tools.PreInvokeWebhookConfig, error) {
+	raw, ok := v["preInvokeWebhook"]
+	delete(v, "preInvokeWebhook")
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	decoder, err := util.NewStrictDecoder(raw)
+	if err != nil {
+		return nil, fmt.Errorf("error creating YAML decoder for 'preInvokeWebhook' of tool %q: %w", name, err)
+	}
+	var spec tools.PreInvokeWebhookSpec
+	if err := decoder.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("invalid 'preInvokeWebhook' field for tool %q: %w", name, err)
+	}
+	webhook, err := tools.NewPreInvokeWebhook(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid 'preInvokeWebhook' field for tool %q: %w", name, err)
+	}
+	return &tools.PreInvokeWebhookConfig{Webhook: webhook}, nil
+}
+
+// extractIdempotencyConfig removes the kind-agnostic `idempotencyKeys` field
+// from a raw tool config. It returns nil unless the field is "accepted".
+func extractIdempotencyConfig(name string, v map[string]any) (*tools.IdempotencyConfig, error) {
+	raw, ok := v["idempotencyKeys"]
+	delete(v, "idempotencyKeys")
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	switch raw {
+	case tools.IdempotencyKeysAccepted:
+		return &tools.IdempotencyConfig{Name: name}, nil
+	case tools.IdempotencyKeysIgnored:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("invalid 'idempotencyKeys' field for tool %q (must be %q or %q)", name, tools.IdempotencyKeysAccepted, tools.IdempotencyKeysIgnored)
+}
+
+// extractPollableConfig removes the kind-agnostic `pollable` field from a raw
+// tool config and validates it. The field is true, or a mapping of options.
+// It returns nil if the field is not set or false.
+func extractPollableConfig(name string, v map[string]any) (*tools.PollableConfig, error) {
+	raw, ok := v["pollable"]
+	delete(v, "pollable")
+	if !ok || raw == nil || raw == false {
+		return nil, nil
+	}
+
+	var spec tools.PollableSpec
+	if raw != true {
+		if _, ok := raw.(map[string]any); !ok {
+			return nil, fmt.Errorf("invalid 'pollable' field for tool %q (must be a boolean or a mapping)", name)
+		}
+		decoder, err := util.NewStrictDecoder(raw)
+		if err != nil {
+			return nil, fmt.Errorf("error creating YAML decoder for 'pollable' of tool %q: %w", name, err)
+		}
+		if err := decoder.Decode(&spec); err != nil {
+			return nil, fmt.Errorf("invalid 'pollable' field for tool %q: %w", name, err)
+		}
+	}
+	poll, err := tools.NewPoll(name, spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid 'pollable' field for tool %q: %w", name, err)
+	}
+	return &tools.PollableConfig{Poll: poll}, nil
+}
+
+// extractUsageMetadataConfig removes the kind-agnostic `includeUsageMetadata`
+// field from a raw tool config. It returns nil if the field is not set.
+func extractUsageMetadataConfig(name string, v map[string]any) (*tools.UsageMetadataConfig, error) {
+	raw, ok := v["includeUsageMetadata"]
+	delete(v, "includeUsageMetadata")
+	if !ok {
+		return nil, nil
+	}
+	include, ok := raw.(bool)
+	if !ok {
+		return nil, fmt.Errorf("invalid 'includeUsageMetadata' field for tool %q (must be a boolean)", name)
+	}
+	return &tools.UsageMetadataConfig{Include: include}, nil
+}
+
+// extractSpillConfig removes the kind-agnostic `spillToFile` field from a raw
+// tool config and validates it. It returns nil if the field is not set.
+func extractSpillConfig(name, kind string, v map[string]any) (*tools.SpillConfig, error) {
+	raw, ok := v["spillToFile"]
+	delete(v, "spillToFile")
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	if !strings.HasSuffix(kind, "-execute-sql") {
+		return nil, fmt.Errorf("`spillToFile` is only supported by execute-sql tools, but tool %q is of kind %q", name, kind)
+	}
+
+	decoder, err := util.NewStrictDecoder(raw)
+	if err != nil {
+		return nil, fmt.Errorf("error creating YAML decoder for 'spillToFile' of tool %q: %w", name, err)
+	}
+	var spec tools.SpillSpec
+	if err := decoder.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("invalid 'spillToFile' field for tool %q: %w", name, err)
+	}
+	spill, err := tools.NewSpill(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid 'spillToFile' field for tool %q: %w", name, err)
+	}
+	return &tools.SpillConfig{Spill: spill}, nil
+}
+
+// extractResultTimezoneConfig removes the kind-agnostic `resultTimezone`
+// field from a raw tool config and validates it. It returns nil if the field
+// is not set.
+func extractResultTimezoneConfig(name string, v map[string]any) (*tools.ResultTimezoneConfig, error) {
+	raw, ok := v["resultTimezone"]
+	delete(v, "resultTimezone")
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	tzName, ok := raw.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid 'resultTimezone' field for tool %q (must be a string)", name)
+	}
+	tz, err := tools.NewResultTimezone(tzName)
+	if err != nil {
+		return nil, fmt.Errorf("invalid 'resultTimezone' field for tool %q: %w", name, err)
+	}
+	return &tools.ResultTimezoneConfig{Timezone: tz}, nil
+}
+
+// extractOutputSchemaConfig removes the kind-agnostic `outputSchema` field
+// from a raw tool config and validates it. The field holds a JSON Schema, as
+// a mapping or as a JSON string. It returns nil if the field is not set.
+func extractOutputSchemaConfig(name string, v map[string]any) (*tools.OutputSchemaConfig, error) {
+	raw, ok := v["outputSchema"]
+	delete(v, "outputSchema")
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	if str, ok := raw.(string); ok {
+		if err := json.Unmarshal([]byte(str), &raw); err != nil {
+			return nil, fmt.Errorf("invalid 'outputSchema' field for tool %q: %w", name, err)
+		}
+	}
+	// round-trip the schema through JSON, so that it holds the values that
+	// are sent to clients
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid 'outputSchema' field for tool %q: %w", name, err)
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(b, &schema); err != nil {
+		return nil, fmt.Errorf("invalid 'outputSchema' field for tool %q (must be a JSON Schema object)", name)
+	}
+	if err := tools.ValidateOutputSchema(schema); err != nil {
+		return nil, fmt.Errorf("invalid 'outputSchema' field for tool %q: %w", name, err)
+	}
+	return &tools.OutputSchemaConfig{Schema: schema}, nil
+}
+
+// withDefaultSchedule wraps a tool config with the default schedule, unless it
+// has a schedule of its own.
+func withDefaultSchedule(tc tools.ToolConfig, schedule *tools.Schedule) tools.ToolConfig {
+	switch c := tc.(type) {
+	case tools.AliasConfig:
+		// aliases stay outermost so that they resolve to the scheduled tool
+		c.ToolConfig = withDefaultSchedule(c.ToolConfig, schedule)
+		return c
+	case tools.ScheduleConfig:
+		return c
+	}
+	return tools.ScheduleConfig{ToolConfig: tc, Schedule: schedule}
+}
+
+// withSourceCircuitBreaker wraps a tool config with the breaker of its source,
+// unless it sets `circuitBreaker` itself.
+func withSourceCircuitBreaker(tc tools.ToolConfig, breaker *tools.CircuitBreaker) tools.ToolConfig {
+	switch c := tc.(type) {
+	case tools.AliasConfig:
+		c.ToolConfig = withSourceCircuitBreaker(c.ToolConfig, breaker)
+		return c
+	case tools.IdempotencyConfig:
+		c.ToolConfig = withSourceCircuitBreaker(c.ToolConfig, breaker)
+		return c
+	case tools.ScheduleConfig:
+		c.ToolConfig = withSourceCircuitBreaker(c.ToolConfig, breaker)
+		return c
+	case tools.CircuitBreakerConfig:
+		return c
+	}
+	return tools.CircuitBreakerConfig{ToolConfig: tc, Breaker: breaker}
+}
+
+// withUsageMetadata wraps a tool config so that its responses include usage
+// metadata, unless it sets `includeUsageMetadata` itself.
+func withUsageMetadata(tc tools.ToolConfig) tools.ToolConfig {
+	switch c := tc.(type) {
+	case tools.AliasConfig:
+		c.ToolConfig = withUsageMetadata(c.ToolConfig)
+		return c
+	case tools.ScheduleConfig:
+		c.ToolConfig = withUsageMetadata(c.ToolConfig)
+		return c
+	case tools.SerializeConfig:
+		c.ToolConfig = withUsageMetadata(c.ToolConfig)
+		return c
+	case tools.PollableConfig:
+		c.ToolConfig = withUsageMetadata(c.ToolConfig)
+		return c
+	case tools.CircuitBreakerConfig:
+		c.ToolConfig = withUsageMetadata(c.ToolConfig)
+		return c
+	case tools.PreInvokeWebhookConfig:
+		c.ToolConfig = withUsageMetadata(c.ToolConfig)
+		return c
+	case tools.UsageMetadataConfig:
+		return c
+	}
+	return tools.UsageMetadataConfig{ToolConfig: tc, Include: true}
+}
+
+// withDefaultOutputSchema wraps a tool config with the output schema
+// registered for its kind, unless it sets `outputSchema` itself. The results
+// of transformed or spilled tools lose the shape of their kind, so they get
+// none.
+func withDefaultOutputSchema(tc tools.ToolConfig) tools.ToolConfig {
+	switch c := tc.(type) {
+	case tools.AliasConfig:
+		c.ToolConfig = withDefaultOutputSchema(c.ToolConfig)
+		return c
+	case tools.ScheduleConfig:
+		c.ToolConfig = withDefaultOutputSchema(c.ToolConfig)
+		return c
+	case tools.SerializeConfig:
+		c.ToolConfig = withDefaultOutputSchema(c.ToolConfig)
+		return c
+	case tools.PollableConfig:
+		c.ToolConfig = withDefaultOutputSchema(c.ToolConfig)
+		return c
+	case tools.CircuitBreakerConfig:
+		c.ToolConfig = withDefaultOutputSchema(c.ToolConfig)
+		return c
+	case tools.PreInvokeWebhookConfig:
+		c.ToolConfig = withDefaultOutputSchema(c.ToolConfig)
+		return c
+	case tools.UsageMetadataConfig:
+		c.ToolConfig = withDefaultOutputSchema(c.ToolConfig)
+		return c
+	case tools.OutputSchemaConfig, tools.TransformConfig, tools.SpillConfig:
+		return c
+	}
+	schema := tools.DefaultOutputSchema(tc.ToolConfigKind())
+	if schema == nil {
+		return tc
+	}
+	return tools.OutputSchemaConfig{ToolConfig: tc, Schema: schema}
+}
+
+// withDefaultResultTimezone wraps a tool config so that the timestamps of its
+// results are converted to tz, unless it sets `resultTimezone` itself.
+func withDefaultResultTimezone(tc tools.ToolConfig, tz *tools.ResultTimezone) tools.ToolConfig {
+	switch c := tc.(type) {
+	case tools.AliasConfig:
+		c.ToolConfig = withDefaultResultTimezone(c.ToolConfig, tz)
+		return c
+	case tools.ScheduleConfig:
+		c.ToolConfig = withDefaultResultTimezone(c.ToolConfig, tz)
+		return c
+	case tools.SerializeConfig:
+		c.ToolConfig = withDefaultResultTimezone(c.ToolConfig, tz)
+		return c
+	case tools.PollableConfig:
+		c.ToolConfig = withDefaultResultTimezone(c.ToolConfig, tz)
+		return c
+	case tools.CircuitBreakerConfig:
+		c.ToolConfig = withDefaultResultTimezone(c.ToolConfig, tz)
+		return c
+	case tools.PreInvokeWebhookConfig:
+		c.ToolConfig = withDefaultResultTimezone(c.ToolConfig, tz)
+		return c
+	case tools.UsageMetadataConfig:
+		c.ToolConfig = withDefaultResultTimezone(c.ToolConfig, tz)
+		return c
+	case tools.OutputSchemaConfig:
+		c.ToolConfig = withDefaultResultTimezone(c.ToolConfig, tz)
+		return c
+	case tools.SpillConfig:
+		c.ToolConfig = withDefaultResultTimezone(c.ToolConfig, tz)
+		return c
+	case tools.TransformConfig:
+		c.ToolConfig = withDefaultResultTimezone(c.ToolConfig, tz)
+		return c
+	case tools.ResultTimezoneConfig:
+		return c
+	}
+	return tools.ResultTimezoneConfig{ToolConfig: tc, Timezone: tz}
+}
+
+// toolsetAuthRequired returns the sets of authServices each tool inherits from
+// the toolsets listing it, by the name of the tool. Toolsets listing a tool by
+// an alias require them for the tool too.
+func toolsetAuthRequired(toolConfigs ToolConfigs, toolsetConfigs ToolsetConfigs) map[string][][]string {
+	targets := make(map[string]string)
+	for name, tc := range toolConfigs {
+		if aliasCfg, ok := tc.(tools.AliasConfig); ok {
+			for _, alias := range aliasCfg.Aliases {
+				targets[alias] = name
+			}
+		}
+	}
+	inherited := make(map[string][][]string)
+	for _, ts := range toolsetConfigs {
+		if len(ts.AuthRequired) == 0 {
+			continue
+		}
+		authRequired := slices.Clone(ts.AuthRequired)
+		slices.Sort(authRequired)
+		authRequired = slices.Compact(authRequired)
+		for _, toolName := range ts.ToolNames {
+			if target, ok := targets[toolName]; ok {
+				toolName = target
+			}
+			if !slices.ContainsFunc(inherited[toolName], func(r []string) bool { return slices.Equal(r, authRequired) }) {
+				inherited[toolName] = append(inherited[toolName], authRequired)
+			}
+		}
+	}
+	for _, sets := range inherited {
+		slices.SortFunc(sets, slices.Compare)
+	}
+	return inherited
+}
+
+// withInheritedAuth requires the sets of authServices inherited from the
+// toolsets of t, the tool of config tc, in addition to its own: one of each set
+// must be verified. Tools requiring none of them require defaultAuthRequired
+// instead, unless they use client OAuth. Tools set `allowUnauthenticated` are
+// left as they are.
+func withInheritedAuth(t tools.Tool, tc tools.ToolConfig, inherited [][]string, defaultAuthRequired []string) (tools.Tool, error) {
+	if f := toolConfigField(tc, "AllowUnauthenticated"); f.IsValid() && f.Bool() {
+		return t, nil
+	}
+	if t.RequiresClientAuthorization() {
+		if len(inherited) > 0 {
+			return nil, fmt.Errorf("the tool uses client OAuth, but its toolsets require the authServices %q", slices.Concat(inherited...))
+		}
+		return t, nil
+	}
+	own := t.Manifest().AuthRequired
+	if len(own) == 0 && len(inherited) == 0 {
+		if len(defaultAuthRequired) == 0 {
+			return t, nil
+		}
+		return tools.AuthRequiredTool{Tool: t, AuthRequired: defaultAuthRequired, Requirements: [][]string{defaultAuthRequired}}, nil
+	}
+	if len(inherited) == 0 {
+		return t, nil
+	}
+	var requirements [][]string
+	if len(own) > 0 {
+		requirements = append(requirements, own)
+	}
+	requirements = append(requirements, inherited...)
+	var all []string
+	for _, r := range inherited {
+		all = append(all, r...)
+	}
+	slices.Sort(all)
+	authRequired := slices.Clone(own)
+	for _, a := range slices.Compact(all) {
+		if !slices.Contains(authRequired, a) {
+			authRequired = append(authRequired, a)
+		}
+	}
+	return tools.AuthRequiredTool{Tool: t, AuthRequired: authRequired, Requirements: requirements}, nil
+}
+
+// resolveStatementFile replaces the `statementFile` field of a raw tool config
+// with a `statement` field holding the contents of the referenced file.
+// Relative paths are resolved against the directory of the tools file.
+func resolveStatementFile(ctx context.Context, name string, v map[string]any) error {
+	rawPath, ok := v["statementFile"]
+	if !ok {
+		return nil
+	}
+	if _, ok := v["statement"]; ok {
+		return fmt.Errorf("`statement` and `statementFile` are mutually exclusive for tool %q. Choose only one", name)
+	}
+	path, ok := rawPath.(string)
+	if !ok || path == "" {
+		return fmt.Errorf("invalid 'statementFile' field for tool %q (must be a non-empty string)", name)
+	}
+
+	info := util.ToolsFileInfoFromContext(ctx)
+	if info != nil && !filepath.IsAbs(path) {
+		path = filepath.Join(info.Dir, path)
+	}
+	path = filepath.Clean(path)
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to read statementFile %q for tool %q: %w", path, name, err)
+	}
+	if info != nil {
+		info.ReferencedFiles = append(info.ReferencedFiles, path)
+	}
+
+	delete(v, "statementFile")
+	v["statement"] = string(buf)
+	return nil
+}
+
+// validateToolAliases verifies that tool aliases do not collide with each
+// other or with the name of any configured tool.
+func validateToolAliases(toolConfigs map[string]tools.ToolConfig) error {
+	seen := make(map[string]string)
+	for name, tc := range toolConfigs {
+		aliasCfg, ok := tc.(tools.AliasConfig)
+		if !ok {
+			continue
+		}
+		for _, alias := range aliasCfg.Aliases {
+			if _, exists := toolConfigs[alias]; exists {
+				return fmt.Errorf("alias %q of tool %q conflicts with an existing tool name", alias, name)
+			}
+			if other, exists := seen[alias]; exists {
+				return fmt.Errorf("alias %q is declared by both tool %q and tool %q", alias, other, name)
+			}
+			seen[alias] = name
+		}
+	}
 	return nil
 }
 
@@ -260,13 +1364,30 @@ var _ yaml.InterfaceUnmarshalerContext = &ToolsetConfigs{}
 func (c *ToolsetConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(interface{}) error) error {
 	*c = make(ToolsetConfigs)
 
-	var raw map[string][]string
+	var raw map[string]any
 	if err := unmarshal(&raw); err != nil {
 		return err
 	}
 
-	for name, toolList := range raw {
-		(*c)[name] = tools.ToolsetConfig{Name: name, ToolNames: toolList}
+	for name, v := range raw {
+		// a toolset is either a list of tool names, or a mapping that also
+		// overrides the descriptions of its tools, or requires authServices
+		var toolset struct {
+			Tools                []string                             `yaml:"tools"`
+			DescriptionOverrides map[string]tools.DescriptionOverride `yaml:"descriptionOverrides"`
+			AuthRequired         []string                             `yaml:"authRequired"`
+		}
+		if _, ok := v.(map[string]any); !ok {
+			v = map[string]any{"tools": v}
+		}
+		decoder, err := util.NewStrictDecoder(v)
+		if err != nil {
+			return fmt.Errorf("error creating YAML decoder for toolset %q: %w", name, err)
+		}
+		if err := decoder.Decode(&toolset); err != nil {
+			return fmt.Errorf("invalid toolset %q: %w", name, err)
+		}
+		(*c)[name] = tools.ToolsetConfig{Name: name, ToolNames: toolset.Tools, DescriptionOverrides: toolset.DescriptionOverrides, AuthRequired: toolset.AuthRequired}
 	}
 	return nil
 }
@@ -0,0 +1,111 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/events"
+	"github.com/googleapis/genai-toolbox/internal/log"
+)
+
+// newEventPublisher returns the publisher of the invocation events to the
+// sink configured by cfg, or nil if none is.
+func newEventPublisher(ctx context.Context, cfg ServerConfig, l log.Logger) (*events.Publisher, error) {
+	var sink events.Sink
+	switch {
+	case cfg.EventsPubSubTopic != "" && cfg.EventsFile != "":
+		return nil, fmt.Errorf("only one of the events Pub/Sub topic and the events file may be set")
+	case cfg.EventsPubSubTopic != "":
+		s, err := events.NewPubSubSink(ctx, cfg.EventsPubSubTopic)
+		if err != nil {
+			return nil, err
+		}
+		sink = s
+	case cfg.EventsFile == "-":
+		sink = events.NewWriterSink(os.Stdout)
+	case cfg.EventsFile != "":
+		s, err := events.NewFileSink(cfg.EventsFile)
+		if err != nil {
+			return nil, err
+		}
+		sink = s
+	default:
+		return nil, nil
+	}
+	p, err := events.NewPublisher(sink, events.PublisherConfig{
+		BufferSize:    cfg.EventsBufferSize,
+		BatchSize:     cfg.EventsBatchSize,
+		FlushInterval: cfg.EventsFlushInterval,
+	}, l)
+	if err != nil {
+		_ = sink.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+// eventClaims returns the claims retrieved from the header, to identify the
+// caller in the invocation events. It returns nil without verifying the
+// header if no events are published.
+func (s *Server) eventClaims(ctx context.Context, header http.Header) map[string]map[string]any {
+	if s.events == nil {
+		return nil
+	}
+	claims, _ := s.authClaims(ctx, header)
+	return claims
+}
+
+// publishInvocation publishes the event of an invocation of the named tool
+// that started at start, if events are published.
+func (s *Server) publishInvocation(toolName string, start time.Time, failed bool, claims map[string]map[string]any) {
+	if s.events == nil {
+		return
+	}
+	r := s.ResourceMgr
+	r.mu.RLock()
+	kind, source := r.toolKinds[toolName], r.toolSources[toolName]
+	r.mu.RUnlock()
+	status := "success"
+	if failed {
+		status = "error"
+	}
+	s.events.Emit(events.Event{
+		Tool:       toolName,
+		Kind:       kind,
+		Source:     source,
+		DurationMs: time.Since(start).Milliseconds(),
+		Status:     status,
+		CallerHash: events.HashSubject(callerSubject(claims)),
+		Timestamp:  start.UTC(),
+	})
+}
+
+// callerSubject returns the subject of the caller verified by the first
+// authService, by name, that has one.
+func callerSubject(claims map[string]map[string]any) string {
+	for _, name := range slices.Sorted(maps.Keys(claims)) {
+		if sub, ok := claims[name]["sub"].(string); ok && sub != "" {
+			return sub
+		}
+	}
+	return ""
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/events"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/telemetry"
+)
+
+func TestInvocationEvents(t *testing.T) {
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+	var buf bytes.Buffer
+	publisher, err := events.NewPublisher(events.NewWriterSink(&buf), events.PublisherConfig{FlushInterval: time.Hour}, testLogger)
+	if err != nil {
+		t.Fatalf("unable to create publisher: %s", err)
+	}
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	s := &Server{
+		version:         fakeVersionString,
+		logger:          testLogger,
+		instrumentation: instrumentation,
+		sseManager:      newSseManager(context.Background()),
+		events:          publisher,
+		ResourceMgr:     NewResourceManager(nil, map[string]auth.AuthService{"admin": fakeAuthService{name: "admin"}}, toolsMap, toolsets),
+	}
+	s.ResourceMgr.SetToolKinds(map[string]string{"no_params": "mock", "some_params": "mock"})
+	apiR, err := apiRouter(s)
+	if err != nil {
+		t.Fatalf("unable to initialize api router: %s", err)
+	}
+	mcpR, err := mcpRouter(s)
+	if err != nil {
+		t.Fatalf("unable to initialize mcp router: %s", err)
+	}
+	r := chi.NewRouter()
+	r.Mount("/api", apiR)
+	r.Mount("/mcp", mcpR)
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	requests := []struct {
+		path   string
+		body   string
+		header map[string]string
+	}{
+		{path: "/api/tool/no_params/invoke", body: `{}`, header: map[string]string{"admin_token": "valid"}},
+		{path: "/api/tool/some_params/invoke", body: `{"param1": "one"}`},
+		{path: "/mcp/", body: `{"jsonrpc":"2.0","id":"1","method":"tools/call","params":{"name":"no_params","arguments":{}}}`, header: map[string]string{"admin_token": "valid"}},
+	}
+	for _, req := range requests {
+		if _, body, err := runRequest(ts, http.MethodPost, req.path, bytes.NewBufferString(req.body), req.header); err != nil {
+			t.Fatalf("unexpected error during request: %s: %s", err, body)
+		}
+	}
+
+	// the events are batched until they are flushed on shutdown
+	if buf.Len() != 0 {
+		t.Fatalf("unexpected events before shutdown: %s", buf.String())
+	}
+	if err := s.events.Close(context.Background()); err != nil {
+		t.Fatalf("unexpected error flushing events: %s", err)
+	}
+
+	var got []events.Event
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var e events.Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("unable to unmarshal event %q: %s", scanner.Text(), err)
+		}
+		if e.Timestamp.IsZero() {
+			t.Fatalf("expected event to have a timestamp: %s", scanner.Text())
+		}
+		got = append(got, e)
+	}
+	admin := events.HashSubject("admin")
+	want := []events.Event{
+		{Tool: "no_params", Kind: "mock", Status: "success", CallerHash: admin},
+		{Tool: "some_params", Kind: "mock", Status: "error"},
+		{Tool: "no_params", Kind: "mock", Status: "success", CallerHash: admin},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(events.Event{}, "DurationMs", "Timestamp")); diff != "" {
+		t.Fatalf("unexpected events (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// sessionHeader identifies the session of an invocation through the HTTP
+// API, whose invocations are otherwise unrelated.
+const sessionHeader = "Toolbox-Session-Id"
+
+// sessionHistories keeps the history of the invocations of each session, in
+// the memory of the replica. The history of a session is removed when it
+// ends, or once it was not used for ttl.
+type sessionHistories struct {
+	size   int
+	ttl    time.Duration
+	lookup func(name string) (tools.Tool, bool)
+	now    func() time.Time
+
+	mu       sync.Mutex
+	sessions map[string]*sessionHistory
+}
+
+type sessionHistory struct {
+	history  *tools.SessionHistory
+	lastUsed time.Time
+}
+
+func newSessionHistories(size int, ttl time.Duration, lookup func(name string) (tools.Tool, bool)) *sessionHistories {
+	return &sessionHistories{
+		size:     size,
+		ttl:      ttl,
+		lookup:   lookup,
+		now:      time.Now,
+		sessions: make(map[string]*sessionHistory),
+	}
+}
+
+// get returns the history of the session id, creating it if needed.
+func (h *sessionHistories) get(id string) *tools.SessionHistory {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	now := h.now()
+	for k, s := range h.sessions {
+		if now.Sub(s.lastUsed) >= h.ttl {
+			delete(h.sessions, k)
+		}
+	}
+	s, ok := h.sessions[id]
+	if !ok {
+		s = &sessionHistory{history: tools.NewSessionHistory(h.size, h.lookup)}
+		h.sessions[id] = s
+	}
+	s.lastUsed = now
+	return s.history
+}
+
+// end removes the history of the session id.
+func (h *sessionHistories) end(id string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.sessions, id)
+}
+
+// withSession adds the session id into ctx, with its history if enabled. It
+// returns ctx unchanged if there is no session.
+func (s *Server) withSession(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	if s.sticky != nil {
+		ctx = tools.WithStickySession(ctx, s.sticky, id)
+	}
+	if s.histories == nil {
+		return ctx
+	}
+	return tools.WithSessionHistory(ctx, s.histories.get(id))
+}
+
+// endSession removes the history of the session id, and closes the
+// connections pinned to it.
+func (s *Server) endSession(id string) {
+	if id == "" {
+		return
+	}
+	if s.histories != nil {
+		s.histories.end(id)
+	}
+	if s.sticky != nil {
+		s.sticky.End(id)
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/utility/sessionhistory"
+)
+
+// renderedTool is a MockTool with a statement.
+type renderedTool struct {
+	MockTool
+}
+
+func (t renderedTool) Render(params tools.ParamValues) (tools.RenderedStatement, error) {
+	return tools.NewRenderedStatement("SELECT $1, $2", params, params), nil
+}
+
+func TestSessionHistoryIsolation(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	toolsMap[tool2.Name] = renderedTool{MockTool: tool2}
+	history, err := sessionhistory.Config{Name: "history", Kind: "session-history", Description: "history"}.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	toolsMap["history"] = history
+
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	invoke := func(session string, param1 int) {
+		t.Helper()
+		var header map[string]string
+		if session != "" {
+			header = map[string]string{sessionHeader: session}
+		}
+		body := fmt.Sprintf(`{"param1": %d, "param2": 2}`, param1)
+		resp, respBody, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tool2.Name), bytes.NewBufferString(body), header)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status code: got %d, want %d, %s", resp.StatusCode, http.StatusOK, string(respBody))
+		}
+	}
+	invoke("session-a", 1)
+	invoke("session-a", 2)
+	invoke("session-b", 3)
+	// invocations without a session are not recorded
+	invoke("", 4)
+
+	tcs := []struct {
+		session string
+		want    []float64
+	}{
+		{session: "session-a", want: []float64{2, 1}},
+		{session: "session-b", want: []float64{3}},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.session, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodPost, "/tool/history/invoke", bytes.NewBufferString(`{}`), map[string]string{sessionHeader: tc.session})
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("unexpected status code: got %d, want %d, %s", resp.StatusCode, http.StatusOK, string(body))
+			}
+			var res struct {
+				Result string `json:"result"`
+			}
+			if err := json.Unmarshal(body, &res); err != nil {
+				t.Fatalf("unexpected error unmarshalling body: %s", err)
+			}
+			var entries []tools.HistoryEntry
+			if err := json.Unmarshal([]byte(res.Result), &entries); err != nil {
+				t.Fatalf("unexpected error unmarshalling result: %s", err)
+			}
+			var got []float64
+			for _, e := range entries {
+				if e.Tool != tool2.Name {
+					t.Fatalf("unexpected tool of entry %+v", e)
+				}
+				got = append(got, e.Params["param1"].(float64))
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected entries (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSessionHistoriesExpiry(t *testing.T) {
+	now := time.Now()
+	h := newSessionHistories(10, time.Minute, nil)
+	h.now = func() time.Time { return now }
+
+	first := h.get("session-a")
+	if h.get("session-a") != first {
+		t.Fatalf("expected the history of a session to be kept")
+	}
+	h.end("session-a")
+	if h.get("session-a") == first {
+		t.Fatalf("expected the history of an ended session to be removed")
+	}
+
+	second := h.get("session-a")
+	now = now.Add(time.Minute)
+	if h.get("session-a") == second {
+		t.Fatalf("expected the history of an idle session to expire")
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultSocketMode is the file mode applied to Unix domain sockets when no
+// mode is configured.
+const DefaultSocketMode os.FileMode = 0o660
+
+const (
+	tcpScheme  = "tcp://"
+	unixScheme = "unix://"
+)
+
+// SocketMode is the file mode applied to Unix domain sockets. It is parsed
+// from and printed as an octal number.
+type SocketMode os.FileMode
+
+// String is used by both fmt.Print and by Cobra in help text
+func (m *SocketMode) String() string {
+	return fmt.Sprintf("%#o", m.FileMode())
+}
+
+// validate socket mode flag
+func (m *SocketMode) Set(v string) error {
+	mode, err := strconv.ParseUint(v, 8, 32)
+	if err != nil || mode > 0o777 {
+		return fmt.Errorf("socket mode must be an octal permission between 0 and 0777, got %q", v)
+	}
+	*m = SocketMode(mode)
+	return nil
+}
+
+// Type is used in Cobra help text
+func (m *SocketMode) Type() string {
+	return "socketMode"
+}
+
+// FileMode returns the file mode to apply, using DefaultSocketMode if none is
+// set.
+func (m SocketMode) FileMode() os.FileMode {
+	if m == 0 {
+		return DefaultSocketMode
+	}
+	return os.FileMode(m)
+}
+
+// parseListenAddress splits a listen address of the form `tcp://host:port`,
+// `unix:///path/to.sock` or `host:port` into a network and an address.
+func parseListenAddress(addr string) (string, string, error) {
+	switch {
+	case strings.HasPrefix(addr, unixScheme):
+		path := strings.TrimPrefix(addr, unixScheme)
+		if path == "" {
+			return "", "", fmt.Errorf("invalid listen address %q: missing socket path", addr)
+		}
+		return "unix", path, nil
+	case strings.Contains(addr, "://") && !strings.HasPrefix(addr, tcpScheme):
+		return "", "", fmt.Errorf("invalid listen address %q: scheme must be one of %q or %q", addr, tcpScheme, unixScheme)
+	}
+	hostPort := strings.TrimPrefix(addr, tcpScheme)
+	if _, _, err := net.SplitHostPort(hostPort); err != nil {
+		return "", "", fmt.Errorf("invalid listen address %q: %w", addr, err)
+	}
+	return "tcp", hostPort, nil
+}
+
+// listenAddrString formats the address of a listener the same way listen
+// addresses are configured.
+func listenAddrString(addr net.Addr) string {
+	if addr.Network() == "unix" {
+		return unixScheme + addr.String()
+	}
+	return tcpScheme + addr.String()
+}
+
+// listen opens a listener for a listen address. For Unix domain sockets, a
+// stale socket left at the path is removed first and mode is applied to the
+// new socket.
+func listen(ctx context.Context, addr string, mode os.FileMode) (net.Listener, error) {
+	network, address, err := parseListenAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+	lc := net.ListenConfig{KeepAlive: 30 * time.Second}
+	if network == "tcp" {
+		return lc.Listen(ctx, network, address)
+	}
+
+	if err := removeStaleSocket(address); err != nil {
+		return nil, err
+	}
+	l, err := lc.Listen(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(address, mode); err != nil {
+		l.Close()
+		return nil, fmt.Errorf("unable to set mode of socket %q: %w", address, err)
+	}
+	return l, nil
+}
+
+// removeStaleSocket removes the socket at path if no server is accepting
+// connections on it. It fails if path is in use or is not a socket.
+func removeStaleSocket(path string) error {
+	fi, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to check socket %q: %w", path, err)
+	}
+	if fi.Mode()&fs.ModeSocket == 0 {
+		return fmt.Errorf("%q already exists and is not a socket", path)
+	}
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		conn.Close()
+		return fmt.Errorf("socket %q is already in use", path)
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("unable to remove stale socket %q: %w", path, err)
+	}
+	return nil
+}
@@ -220,17 +220,21 @@ func mcpRouter(s *Server) (chi.Router, error) {
 	r.Use(middleware.AllowContentType("application/json", "application/json-rpc", "application/jsonrequest"))
 	r.Use(middleware.StripSlashes)
 	r.Use(render.SetContentType(render.ContentTypeJSON))
+	r.Use(s.artifactContext)
+	r.Use(s.idempotencyContext)
 
 	r.Get("/sse", func(w http.ResponseWriter, r *http.Request) { sseHandler(s, w, r) })
+	r.Get("/ws", func(w http.ResponseWriter, r *http.Request) { wsHandler(s, w, r) })
 	r.Get("/", func(w http.ResponseWriter, r *http.Request) { methodNotAllowed(s, w, r) })
 	r.Post("/", func(w http.ResponseWriter, r *http.Request) { httpHandler(s, w, r) })
-	r.Delete("/", func(w http.ResponseWriter, r *http.Request) {})
+	r.Delete("/", func(w http.ResponseWriter, r *http.Request) { deleteHandler(s, w, r) })
 
 	r.Route("/{toolsetName}", func(r chi.Router) {
 		r.Get("/sse", func(w http.ResponseWriter, r *http.Request) { sseHandler(s, w, r) })
+		r.Get("/ws", func(w http.ResponseWriter, r *http.Request) { wsHandler(s, w, r) })
 		r.Get("/", func(w http.ResponseWriter, r *http.Request) { methodNotAllowed(s, w, r) })
 		r.Post("/", func(w http.ResponseWriter, r *http.Request) { httpHandler(s, w, r) })
-		r.Delete("/", func(w http.ResponseWriter, r *http.Request) {})
+		r.Delete("/", func(w http.ResponseWriter, r *http.Request) { deleteHandler(s, w, r) })
 	})
 
 	return r, nil
@@ -285,6 +289,7 @@ func sseHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	}
 	s.sseManager.add(sessionId, session)
 	defer s.sseManager.remove(sessionId)
+	defer s.endSession(sessionId)
 
 	// https scheme formatting if (forwarded) request is a TLS request
 	proto := r.Header.Get("X-Forwarded-Proto")
@@ -330,6 +335,12 @@ func methodNotAllowed(s *Server, w http.ResponseWriter, r *http.Request) {
 	_ = render.Render(w, r, newErrResponse(err, http.StatusMethodNotAllowed))
 }
 
+// deleteHandler ends the streamable HTTP session of the `Mcp-Session-Id`
+// header.
+func deleteHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	s.endSession(r.Header.Get("Mcp-Session-Id"))
+}
+
 // httpHandler handles all mcp messages.
 func httpHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -361,18 +372,6 @@ func httpHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		protocolVersion = v20250326.PROTOCOL_VERSION
 	}
 
-	// check if client have `MCP-Protocol-Version` header
-	// Only supported for v2025-06-18+.
-	headerProtocolVersion := r.Header.Get("MCP-Protocol-Version")
-	if headerProtocolVersion != "" {
-		if !mcp.VerifyProtocolVersion(headerProtocolVersion) {
-			err := fmt.Errorf("invalid protocol version: %s", headerProtocolVersion)
-			_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
-			return
-		}
-		protocolVersion = headerProtocolVersion
-	}
-
 	toolsetName := chi.URLParam(r, "toolsetName")
 	s.logger.DebugContext(ctx, fmt.Sprintf("toolset name: %s", toolsetName))
 	span.SetAttributes(attribute.String("toolset_name", toolsetName))
@@ -396,6 +395,21 @@ func httpHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		)
 	}()
 
+	ctx, err = withScheduleOverride(ctx, r)
+	if err != nil {
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		return
+	}
+	ctx = s.withClientAttribution(ctx, r)
+	// sse sessions are identified by their query parameter, and streamable
+	// HTTP sessions by their header
+	if paramSessionId != "" {
+		ctx = s.withSession(ctx, paramSessionId)
+	} else {
+		ctx = s.withSession(ctx, headerSessionId)
+	}
+
 	// Read and returns a body from io.Reader
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
@@ -406,6 +420,21 @@ func httpHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// check if client have `MCP-Protocol-Version` header
+	// Only supported for v2025-06-18+. The version of an initialize request
+	// is negotiated from its body instead, so an unknown version in the
+	// header must not fail the initialization.
+	headerProtocolVersion := r.Header.Get("MCP-Protocol-Version")
+	if headerProtocolVersion != "" {
+		if mcp.VerifyProtocolVersion(headerProtocolVersion) {
+			protocolVersion = headerProtocolVersion
+		} else if !isInitializeRequest(body) {
+			err = fmt.Errorf("invalid protocol version: %s", headerProtocolVersion)
+			_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+			return
+		}
+	}
+
 	v, res, err := processMcpMessage(ctx, body, s, protocolVersion, toolsetName, r.Header)
 	if err != nil {
 		s.logger.DebugContext(ctx, fmt.Errorf("error processing message: %w", err).Error())
@@ -442,10 +471,18 @@ func httpHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		switch code {
 		case jsonrpc.INTERNAL_ERROR:
 			w.WriteHeader(http.StatusInternalServerError)
+		case jsonrpc.INVALID_PARAMS:
+			var limitErr *util.PayloadLimitError
+			if errors.As(err, &limitErr) {
+				w.WriteHeader(http.StatusBadRequest)
+			}
 		case jsonrpc.INVALID_REQUEST:
 			errStr := err.Error()
+			var disabledErr *tools.KindDisabledError
 			if errors.Is(err, tools.ErrUnauthorized) {
 				w.WriteHeader(http.StatusUnauthorized)
+			} else if errors.As(err, &disabledErr) {
+				w.WriteHeader(http.StatusForbidden)
 			} else if strings.Contains(errStr, "Error 401") {
 				w.WriteHeader(http.StatusUnauthorized)
 			} else if strings.Contains(errStr, "Error 403") {
@@ -458,6 +495,15 @@ func httpHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, res)
 }
 
+// isInitializeRequest reports whether body is an initialize request.
+func isInitializeRequest(body []byte) bool {
+	var baseMessage jsonrpc.BaseMessage
+	if err := json.Unmarshal(body, &baseMessage); err != nil {
+		return false
+	}
+	return baseMessage.Method == mcputil.INITIALIZE
+}
+
 // processMcpMessage process the messages received from clients
 func processMcpMessage(ctx context.Context, body []byte, s *Server, protocolVersion string, toolsetName string, header http.Header) (string, any, error) {
 	logger, err := util.LoggerFromContext(ctx)
@@ -501,20 +547,54 @@ func processMcpMessage(ctx context.Context, body []byte, s *Server, protocolVers
 		return "", nil, err
 	}
 
-	switch baseMessage.Method {
-	case mcputil.INITIALIZE:
-		res, v, err := mcp.InitializeResponse(ctx, baseMessage.Id, body, s.version)
+	if baseMessage.Method == mcputil.INITIALIZE {
+		res, v, err := mcp.InitializeResponse(ctx, baseMessage.Id, body, s.version, s.ResourceMgr.hasSchemaSource())
 		if err != nil {
 			return "", res, err
 		}
+		s.instrumentation.McpInit.Add(
+			ctx,
+			1,
+			metric.WithAttributes(attribute.String("toolbox.mcp.protocol_version", v)),
+			metric.WithAttributes(attribute.Bool("toolbox.mcp.protocol_version.fallback", v != mcp.RequestedProtocolVersion(body))),
+		)
 		return v, res, err
+	}
+
+	toolset, ok := s.ResourceMgr.GetToolset(toolsetName)
+	if !ok {
+		err = fmt.Errorf("toolset does not exist")
+		return "", jsonrpc.NewError(baseMessage.Id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
+	switch baseMessage.Method {
+	case mcputil.RESOURCES_LIST, mcputil.RESOURCES_READ:
+		// resources are only offered if they are advertised in initialize
+		if !s.ResourceMgr.hasSchemaSource() {
+			err = fmt.Errorf("invalid method %s", baseMessage.Method)
+			return "", jsonrpc.NewError(baseMessage.Id, jsonrpc.METHOD_NOT_FOUND, err.Error(), nil), err
+		}
+		resources := s.ResourceMgr.mcpResources(toolset)
+		if baseMessage.Method == mcputil.RESOURCES_LIST {
+			res, err := mcp.ListResourcesResponse(baseMessage.Id, body, resources)
+			return "", res, err
+		}
+		res, err := mcp.ReadResourceResponse(ctx, baseMessage.Id, body, resources, s.ResourceMgr.GetAuthServiceMap(), header)
+		return "", res, err
 	default:
-		toolset, ok := s.ResourceMgr.GetToolset(toolsetName)
-		if !ok {
-			err = fmt.Errorf("toolset does not exist")
-			return "", jsonrpc.NewError(baseMessage.Id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+		start := time.Now()
+		if baseMessage.Method == mcputil.TOOLS_CALL {
+			if err := s.payloadLimits.Check(mcpToolArguments(body)); err != nil {
+				s.ResourceMgr.recordInvocation(mcpToolName(body), true)
+				s.publishInvocation(mcpToolName(body), start, true, s.eventClaims(ctx, header))
+				err = fmt.Errorf("tool arguments were rejected: %w", err)
+				return "", jsonrpc.NewError(baseMessage.Id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+			}
 		}
 		res, err := mcp.ProcessMethod(ctx, protocolVersion, baseMessage.Id, baseMessage.Method, toolset, s.ResourceMgr.GetToolsMap(), s.ResourceMgr.GetAuthServiceMap(), body, header)
+		if baseMessage.Method == mcputil.TOOLS_CALL {
+			s.ResourceMgr.recordInvocation(mcpToolName(body), err != nil || mcpCallFailed(res))
+			s.publishInvocation(mcpToolName(body), start, err != nil || mcpCallFailed(res), s.eventClaims(ctx, header))
+		}
 		return "", res, err
 	}
 }
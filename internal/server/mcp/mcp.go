@@ -28,6 +28,7 @@ import (
 	v20250326 "github.com/googleapis/genai-toolbox/internal/server/mcp/v20250326"
 	v20250618 "github.com/googleapis/genai-toolbox/internal/server/mcp/v20250618"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
 )
 
 // LATEST_PROTOCOL_VERSION is the latest version of the MCP protocol supported.
@@ -41,32 +42,70 @@ var SUPPORTED_PROTOCOL_VERSIONS = []string{
 	v20250618.PROTOCOL_VERSION,
 }
 
+// NegotiateProtocolVersion returns the protocol version agreed with a client
+// that requested the given version. A supported version is agreed as is. Any
+// other version, whether it is newer than the server knows of or not a
+// version at all, falls back to the latest supported version, which the client
+// may accept or disconnect from.
+func NegotiateProtocolVersion(requested string) string {
+	if VerifyProtocolVersion(requested) {
+		return requested
+	}
+	return LATEST_PROTOCOL_VERSION
+}
+
+// RequestedProtocolVersion returns the protocol version requested by an
+// initialize request, or an empty string if body is not one.
+func RequestedProtocolVersion(body []byte) string {
+	var req mcputil.InitializeRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return ""
+	}
+	return req.Params.ProtocolVersion
+}
+
+// serverCapabilities returns the capabilities advertised for a protocol
+// version. Only capabilities defined by that version may be advertised; the
+// tools and resources capabilities are defined by every supported version.
+// The resources capability is only advertised if hasResources is true.
+func serverCapabilities(protocolVersion string, hasResources bool) mcputil.ServerCapabilities {
+	toolsListChanged := false
+	capabilities := mcputil.ServerCapabilities{
+		Tools: &mcputil.ListChanged{
+			ListChanged: &toolsListChanged,
+		},
+	}
+	if hasResources {
+		resourcesListChanged := false
+		capabilities.Resources = &mcputil.ResourcesCapability{
+			ListChanged: &resourcesListChanged,
+		}
+	}
+	return capabilities
+}
+
 // InitializeResponse runs capability negotiation and protocol version agreement.
 // This is the Initialization phase of the lifecycle for MCP client-server connections.
-// Always start with the latest protocol version supported.
-func InitializeResponse(ctx context.Context, id jsonrpc.RequestId, body []byte, toolboxVersion string) (any, string, error) {
+// The capabilities advertised are the ones of the agreed protocol version.
+// The resources capability is only advertised if hasResources is true.
+func InitializeResponse(ctx context.Context, id jsonrpc.RequestId, body []byte, toolboxVersion string, hasResources bool) (any, string, error) {
 	var req mcputil.InitializeRequest
 	if err := json.Unmarshal(body, &req); err != nil {
 		err = fmt.Errorf("invalid mcp initialize request: %w", err)
 		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), "", err
 	}
 
-	var protocolVersion string
 	v := req.Params.ProtocolVersion
-	if slices.Contains(SUPPORTED_PROTOCOL_VERSIONS, v) {
-		protocolVersion = v
-	} else {
-		protocolVersion = LATEST_PROTOCOL_VERSION
+	protocolVersion := NegotiateProtocolVersion(v)
+	if protocolVersion != v {
+		if logger, err := util.LoggerFromContext(ctx); err == nil {
+			logger.DebugContext(ctx, fmt.Sprintf("unsupported protocol version %q requested, falling back to %s", v, protocolVersion))
+		}
 	}
 
-	toolsListChanged := false
 	result := mcputil.InitializeResult{
 		ProtocolVersion: protocolVersion,
-		Capabilities: mcputil.ServerCapabilities{
-			Tools: &mcputil.ListChanged{
-				ListChanged: &toolsListChanged,
-			},
-		},
+		Capabilities:    serverCapabilities(protocolVersion, hasResources),
 		ServerInfo: mcputil.Implementation{
 			BaseMetadata: mcputil.BaseMetadata{
 				Name: mcputil.SERVER_NAME,
@@ -95,6 +134,8 @@ func NotificationHandler(ctx context.Context, body []byte) error {
 
 // ProcessMethod returns a response for the request.
 // This is the Operation phase of the lifecycle for MCP client-server connections.
+// Requests are dispatched to the handlers of mcpVersion, and to the ones of
+// the oldest supported version when no version is known, such as for SSE.
 func ProcessMethod(ctx context.Context, mcpVersion string, id jsonrpc.RequestId, method string, toolset tools.Toolset, tools map[string]tools.Tool, authServices map[string]auth.AuthService, body []byte, header http.Header) (any, error) {
 	switch mcpVersion {
 	case v20250618.PROTOCOL_VERSION:
@@ -0,0 +1,130 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
+	mcputil "github.com/googleapis/genai-toolbox/internal/server/mcp/util"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// ResourceEntry is a resource offered to MCP clients together with what is
+// needed to authorize and read it.
+type ResourceEntry struct {
+	mcputil.Resource
+	// Tools are the tools whose authorization requirements also apply to
+	// reading the resource. The caller must be authorized for all of them.
+	Tools []tools.Tool
+	// Read returns the text contents of the resource.
+	Read func(ctx context.Context) (string, error)
+}
+
+// ListResourcesResponse handles the "resources/list" method.
+func ListResourcesResponse(id jsonrpc.RequestId, body []byte, resources []ResourceEntry) (any, error) {
+	var req mcputil.ListResourcesRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		err = fmt.Errorf("invalid mcp resources list request: %w", err)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
+
+	result := mcputil.ListResourcesResult{
+		Resources: make([]mcputil.Resource, 0, len(resources)),
+	}
+	for _, r := range resources {
+		result.Resources = append(result.Resources, r.Resource)
+	}
+	return jsonrpc.JSONRPCResponse{
+		Jsonrpc: jsonrpc.JSONRPC_VERSION,
+		Id:      id,
+		Result:  result,
+	}, nil
+}
+
+// ReadResourceResponse handles the "resources/read" method. The caller must
+// pass the same auth checks as a call to each tool attached to the resource.
+func ReadResourceResponse(ctx context.Context, id jsonrpc.RequestId, body []byte, resources []ResourceEntry, authServices map[string]auth.AuthService, header http.Header) (any, error) {
+	logger, err := util.LoggerFromContext(ctx)
+	if err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INTERNAL_ERROR, err.Error(), nil), err
+	}
+
+	var req mcputil.ReadResourceRequest
+	if err = json.Unmarshal(body, &req); err != nil {
+		err = fmt.Errorf("invalid mcp resources read request: %w", err)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
+
+	uri := req.Params.URI
+	logger.DebugContext(ctx, fmt.Sprintf("resource uri: %s", uri))
+	var resource *ResourceEntry
+	for i := range resources {
+		if resources[i].URI == uri {
+			resource = &resources[i]
+			break
+		}
+	}
+	if resource == nil {
+		err = fmt.Errorf("resource not found: %q", uri)
+		return jsonrpc.NewError(id, mcputil.RESOURCE_NOT_FOUND, err.Error(), map[string]any{"uri": uri}), err
+	}
+
+	accessToken := header.Get("Authorization")
+	verifiedAuthServices := make([]string, 0)
+	// if using stdio, header will be nil and auth will not be supported
+	if header != nil {
+		for _, aS := range authServices {
+			claims, err := aS.GetClaimsFromHeader(ctx, header)
+			if err != nil {
+				logger.DebugContext(ctx, err.Error())
+				continue
+			}
+			if claims == nil {
+				// authService not present in header
+				continue
+			}
+			verifiedAuthServices = append(verifiedAuthServices, aS.GetName())
+		}
+	}
+	for _, tool := range resource.Tools {
+		if tool.RequiresClientAuthorization() && accessToken == "" {
+			return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, "missing access token in the 'Authorization' header", nil), tools.ErrUnauthorized
+		}
+		if !tool.Authorized(verifiedAuthServices) {
+			err = fmt.Errorf("unauthorized resource read: Please make sure your specify correct auth headers: %w", tools.ErrUnauthorized)
+			return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+		}
+	}
+	logger.DebugContext(ctx, "resource read authorized")
+
+	text, err := resource.Read(ctx)
+	if err != nil {
+		err = fmt.Errorf("unable to read resource %q: %w", uri, err)
+		return jsonrpc.NewError(id, jsonrpc.INTERNAL_ERROR, err.Error(), nil), err
+	}
+	return jsonrpc.JSONRPCResponse{
+		Jsonrpc: jsonrpc.JSONRPC_VERSION,
+		Id:      id,
+		Result: mcputil.ReadResourceResult{
+			Contents: []mcputil.TextResourceContents{{URI: uri, MimeType: resource.MimeType, Text: text}},
+		},
+	}, nil
+}
@@ -88,7 +88,8 @@ type ClientCapabilities struct {
 // capabilities are defined here, in this schema, but this is not a closed set: any
 // server can define its own, additional capabilities.
 type ServerCapabilities struct {
-	Tools *ListChanged `json:"tools,omitempty"`
+	Resources *ResourcesCapability `json:"resources,omitempty"`
+	Tools     *ListChanged         `json:"tools,omitempty"`
 }
 
 // Base interface for metadata with name (identifier) and title (display name) properties.
@@ -0,0 +1,90 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
+)
+
+// The resources methods are the same in every supported protocol version.
+const (
+	RESOURCES_LIST = "resources/list"
+	RESOURCES_READ = "resources/read"
+)
+
+// RESOURCE_NOT_FOUND is the error code returned when a resource URI is unknown.
+const RESOURCE_NOT_FOUND = -32002
+
+// ResourcesCapability is present if the server offers any resources to read.
+type ResourcesCapability struct {
+	// Whether this server supports subscribing to resource updates.
+	Subscribe *bool `json:"subscribe,omitempty"`
+	// Whether this server supports notifications for changes to the resource list.
+	ListChanged *bool `json:"listChanged,omitempty"`
+}
+
+// Resource is a known resource that the server is capable of reading.
+type Resource struct {
+	// The URI of this resource.
+	URI string `json:"uri"`
+	// A human-readable name for this resource.
+	Name string `json:"name"`
+	// A description of what this resource represents.
+	Description string `json:"description,omitempty"`
+	// The MIME type of this resource, if known.
+	MimeType string `json:"mimeType,omitempty"`
+}
+
+// ListResourcesRequest is sent from the client to request a list of resources
+// the server has.
+type ListResourcesRequest struct {
+	jsonrpc.Request
+	Params struct {
+		// An opaque token representing the current pagination position.
+		Cursor string `json:"cursor,omitempty"`
+	} `json:"params,omitempty"`
+}
+
+// ListResourcesResult is the server's response to a resources/list request.
+type ListResourcesResult struct {
+	jsonrpc.Result
+	NextCursor string     `json:"nextCursor,omitempty"`
+	Resources  []Resource `json:"resources"`
+}
+
+// ReadResourceRequest is sent from the client to read a specific resource URI.
+type ReadResourceRequest struct {
+	jsonrpc.Request
+	Params struct {
+		// The URI of the resource to read.
+		URI string `json:"uri"`
+	} `json:"params,omitempty"`
+}
+
+// TextResourceContents is the text contents of a resource.
+type TextResourceContents struct {
+	// The URI of this resource.
+	URI string `json:"uri"`
+	// The MIME type of this resource, if known.
+	MimeType string `json:"mimeType,omitempty"`
+	// The text of the item.
+	Text string `json:"text"`
+}
+
+// ReadResourceResult is the server's response to a resources/read request.
+type ReadResourceResult struct {
+	jsonrpc.Result
+	Contents []TextResourceContents `json:"contents"`
+}
@@ -0,0 +1,52 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// TOOLS_CALL is the same in every supported protocol version.
+const TOOLS_CALL = "tools/call"
+
+// WithoutOutputSchemas returns a copy of manifests without their output
+// schemas, for the protocol versions that do not support them.
+func WithoutOutputSchemas(manifests []tools.McpManifest) []tools.McpManifest {
+	stripped := make([]tools.McpManifest, len(manifests))
+	for i, m := range manifests {
+		m.OutputSchema = nil
+		stripped[i] = m
+	}
+	return stripped
+}
+
+// ToolsFilter is an experimental argument of tools/list restricting the
+// listed tools to those that have at least one of Tags.
+type ToolsFilter struct {
+	Tags []string `json:"tags,omitempty"`
+}
+
+// Apply returns the toolset restricted by f. A nil filter keeps every tool.
+func (f *ToolsFilter) Apply(toolset tools.Toolset) (tools.Toolset, error) {
+	if f == nil {
+		return toolset, nil
+	}
+	for _, tag := range f.Tags {
+		if err := tools.ValidateTag(tag); err != nil {
+			return toolset, err
+		}
+	}
+	return toolset.WithTags(f.Tags), nil
+}
@@ -59,6 +59,11 @@ func toolsListHandler(id jsonrpc.RequestId, toolset tools.Toolset, body []byte)
 		err = fmt.Errorf("invalid mcp tools list request: %w", err)
 		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
 	}
+	toolset, err := req.Params.Filter.Apply(toolset)
+	if err != nil {
+		err = fmt.Errorf("invalid tools list filter: %w", err)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+	}
 
 	result := ListToolsResult{
 		Tools: toolset.McpManifest,
@@ -143,6 +148,8 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, toolsMap map[st
 		verifiedAuthServices[i] = k
 		i++
 	}
+	ctx = util.WithVerifiedAuthServices(ctx, verifiedAuthServices)
+	ctx = util.WithAuthClaims(ctx, claimsFromAuth)
 
 	// Check if any of the specified auth services is verified
 	isAuthorized := tool.Authorized(verifiedAuthServices)
@@ -152,21 +159,52 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, toolsMap map[st
 	}
 	logger.DebugContext(ctx, "tool invocation authorized")
 
+	if err = tools.ResolveResourceReferences(ctx, data); err != nil {
+		err = fmt.Errorf("provided parameters were invalid: %w", err)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+	}
+
 	params, err := tool.ParseParams(data, claimsFromAuth)
 	if err != nil {
+		var errData any
+		var paramErrs tools.ParamErrors
+		if errors.As(err, &paramErrs) {
+			errData = paramErrs
+		}
 		err = fmt.Errorf("provided parameters were invalid: %w", err)
-		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), errData), err
 	}
-	logger.DebugContext(ctx, fmt.Sprintf("invocation params: %s", params))
+	logger.DebugContext(ctx, fmt.Sprintf("invocation params: %s", params.Redacted()))
 
 	// run tool invocation and generate response.
-	results, err := tool.Invoke(ctx, params, accessToken)
+	invocation := tools.Invocation{
+		Params:      params,
+		AccessToken: accessToken,
+		Claims:      claimsFromAuth,
+		Metadata:    tools.RequestMetadata{Protocol: "mcp", ClientName: header.Get(tools.ClientNameHeader)},
+	}
+	ctx, poll := tools.WithPollState(ctx, "")
+	results, err := tool.Invoke(ctx, invocation)
+	var usage *tools.UsageCounter
+	if mr, ok := results.(tools.MeteredResult); ok {
+		results, usage = mr.Result, mr.NewCounter()
+	}
+	if it, ok := results.(tools.RowIterator); ok && err == nil {
+		results, err = tools.CollectRows(it)
+	}
 	if err != nil {
 		errStr := err.Error()
+		// the values of sensitive parameters may be quoted by the error
+		err = params.RedactError(err)
 		// Missing authService tokens.
 		if errors.Is(err, tools.ErrUnauthorized) {
 			return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
 		}
+		// Tools of a kind disabled by the operator.
+		var disabledErr *tools.KindDisabledError
+		if errors.As(err, &disabledErr) {
+			return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+		}
 		// Upstream auth error
 		if strings.Contains(errStr, "Error 401") || strings.Contains(errStr, "Error 403") {
 			if tool.RequiresClientAuthorization() {
@@ -180,6 +218,12 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, toolsMap map[st
 			Type: "text",
 			Text: err.Error(),
 		}
+		if hint := tools.ErrorHint(err); hint != "" {
+			// keep the original message alongside the hint
+			if b, mErr := json.Marshal(map[string]string{"error": err.Error(), "hint": hint}); mErr == nil {
+				text.Text = string(b)
+			}
+		}
 		return jsonrpc.JSONRPCResponse{
 			Jsonrpc: jsonrpc.JSONRPC_VERSION,
 			Id:      id,
@@ -187,6 +231,8 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, toolsMap map[st
 		}, nil
 	}
 
+	tools.RecordInvocation(ctx, toolName, tool, params, tools.RowCount(results))
+
 	content := make([]TextContent, 0)
 
 	sliceRes, ok := results.([]any)
@@ -201,13 +247,39 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, toolsMap map[st
 			text.Text = fmt.Sprintf("fail to marshal: %s, result: %s", err, d)
 		} else {
 			text.Text = string(dM)
+			if usage != nil {
+				_, _ = usage.Write(dM)
+			}
 		}
 		content = append(content, text)
 	}
 
+	result := CallToolResult{Content: content}
+	// an unchanged result does not have the shape of the output schema
+	if st, ok := tools.As[tools.StructuredTool](tool); ok && !poll.Unchanged {
+		structured, err := st.StructuredContent(results)
+		if err != nil {
+			logger.DebugContext(ctx, fmt.Sprintf("unable to build structured content: %s", err))
+		}
+		result.StructuredContent = structured
+	}
+	if usage != nil {
+		usage.AddRows(tools.RowCount(results))
+		if result.StructuredContent == nil {
+			result.StructuredContent = make(map[string]any)
+		}
+		result.StructuredContent["_meta"] = usage.Metadata()
+	}
+	if poll.ETag != "" {
+		// to be passed as the _ifNoneMatch argument of the next invocation
+		if result.Meta == nil {
+			result.Meta = make(map[string]any)
+		}
+		result.Meta["toolbox/etag"] = poll.ETag
+	}
 	return jsonrpc.JSONRPCResponse{
 		Jsonrpc: jsonrpc.JSONRPC_VERSION,
 		Id:      id,
-		Result:  CallToolResult{Content: content},
+		Result:  result,
 	}, nil
 }
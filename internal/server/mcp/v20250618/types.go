@@ -16,6 +16,7 @@ package v20250618
 
 import (
 	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
+	mcputil "github.com/googleapis/genai-toolbox/internal/server/mcp/util"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 )
 
@@ -62,7 +63,15 @@ type PaginatedResult struct {
 
 // Sent from the client to request a list of tools the server has.
 type ListToolsRequest struct {
-	PaginatedRequest
+	jsonrpc.Request
+	Params struct {
+		// An opaque token representing the current pagination position.
+		// If provided, the server should return results starting after this cursor.
+		Cursor Cursor `json:"cursor,omitempty"`
+		// Filter is an experimental argument restricting the listed tools
+		// to those that have at least one of its tags.
+		Filter *mcputil.ToolsFilter `json:"filter,omitempty"`
+	} `json:"params,omitempty"`
 }
 
 // The server's response to a tools/list request from the client.
@@ -28,9 +28,12 @@ import (
 	"testing"
 
 	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/server/mcp"
 	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
 	"github.com/googleapis/genai-toolbox/internal/telemetry"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 )
 
 const jsonrpcVersion = "2.0"
@@ -278,6 +281,295 @@ func TestMcpEndpointWithoutInitialized(t *testing.T) {
 	}
 }
 
+func TestMcpOutputSchema(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	toolsMap[tool1.Name] = tools.OutputSchemaTool{Tool: tool1, Schema: tools.RowsOutputSchema()}
+	tc := tools.ToolsetConfig{Name: "", ToolNames: []string{tool1.Name, tool2.Name}}
+	toolset, err := tc.Initialize(fakeVersionString, toolsMap)
+	if err != nil {
+		t.Fatalf("unable to initialize toolset: %s", err)
+	}
+	toolsets[""] = toolset
+	r, shutdown := setUpServer(t, "mcp", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	// round-trip the schema, as the response is decoded
+	var schema map[string]any
+	b, _ := json.Marshal(tools.RowsOutputSchema())
+	_ = json.Unmarshal(b, &schema)
+
+	testCases := []struct {
+		protocol       string
+		wantSchema     bool
+		wantStructured map[string]any
+	}{
+		{protocol: protocolVersion20241105},
+		{protocol: protocolVersion20250326},
+		{
+			protocol:       protocolVersion20250618,
+			wantSchema:     true,
+			wantStructured: map[string]any{"rows": []any{"no_params"}, "rowCount": 1.0},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.protocol, func(t *testing.T) {
+			initWant := map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "mcp-initialize",
+				"result": map[string]any{
+					"protocolVersion": tc.protocol,
+					"capabilities": map[string]any{
+						"tools": map[string]any{"listChanged": false},
+					},
+					"serverInfo": map[string]any{"name": serverName, "version": fakeVersionString},
+				},
+			}
+			sessionId := runInitializeLifecycle(t, ts, tc.protocol, initWant, tc.protocol == protocolVersion20250326)
+			header := map[string]string{}
+			if sessionId != "" {
+				header["Mcp-Session-Id"] = sessionId
+			}
+			if tc.protocol == protocolVersion20250618 {
+				header["MCP-Protocol-Version"] = tc.protocol
+			}
+
+			send := func(method string, params map[string]any) map[string]any {
+				reqMarshal, err := json.Marshal(jsonrpc.JSONRPCRequest{
+					Jsonrpc: jsonrpcVersion,
+					Id:      "output-schema",
+					Request: jsonrpc.Request{Method: method},
+					Params:  params,
+				})
+				if err != nil {
+					t.Fatalf("unexpected error during marshaling of body")
+				}
+				_, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), header)
+				if err != nil {
+					t.Fatalf("unexpected error during request: %s", err)
+				}
+				var got struct {
+					Result map[string]any `json:"result"`
+				}
+				if err := json.Unmarshal(body, &got); err != nil {
+					t.Fatalf("unexpected error unmarshalling body: %s", err)
+				}
+				return got.Result
+			}
+
+			list := send("tools/list", nil)
+			manifests, _ := list["tools"].([]any)
+			if len(manifests) != 2 {
+				t.Fatalf("unexpected tools: %+v", list)
+			}
+			for _, m := range manifests {
+				m := m.(map[string]any)
+				got, ok := m["outputSchema"]
+				switch {
+				case m["name"] == tool2.Name && ok:
+					t.Fatalf("unexpected output schema for tool %q: %+v", tool2.Name, got)
+				case m["name"] == tool1.Name && ok != tc.wantSchema:
+					t.Fatalf("unexpected output schema for tool %q: %+v", tool1.Name, got)
+				case m["name"] == tool1.Name && ok && !reflect.DeepEqual(got, schema):
+					t.Fatalf("unexpected output schema: got %+v, want %+v", got, schema)
+				}
+			}
+
+			call := send("tools/call", map[string]any{"name": tool1.Name})
+			got, ok := call["structuredContent"]
+			if tc.wantStructured == nil {
+				if ok {
+					t.Fatalf("unexpected structured content: %+v", got)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tc.wantStructured) {
+				t.Fatalf("unexpected structured content: got %+v, want %+v", got, tc.wantStructured)
+			}
+		})
+	}
+}
+
+func TestMcpToolsetDescriptionOverrides(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	tc := tools.ToolsetConfig{
+		Name:      "ja",
+		ToolNames: []string{tool2.Name},
+		DescriptionOverrides: map[string]tools.DescriptionOverride{
+			tool2.Name: {
+				Description: "いくつかのパラメータを持つツール",
+				Parameters:  map[string]string{"param2": "二番目のパラメータ"},
+			},
+		},
+	}
+	toolset, err := tc.Initialize(fakeVersionString, toolsMap)
+	if err != nil {
+		t.Fatalf("unable to initialize toolset: %s", err)
+	}
+	toolsets["ja"] = toolset
+	r, shutdown := setUpServer(t, "mcp", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	header := map[string]string{"MCP-Protocol-Version": protocolVersion20250618}
+	send := func(url, method string, params map[string]any) map[string]any {
+		reqMarshal, err := json.Marshal(jsonrpc.JSONRPCRequest{
+			Jsonrpc: jsonrpcVersion,
+			Id:      "description-overrides",
+			Request: jsonrpc.Request{Method: method},
+			Params:  params,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error during marshaling of body")
+		}
+		_, body, err := runRequest(ts, http.MethodPost, url, bytes.NewBuffer(reqMarshal), header)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		var got struct {
+			Result map[string]any `json:"result"`
+		}
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Fatalf("unexpected error unmarshalling body: %s", err)
+		}
+		return got.Result
+	}
+	toolManifest := func(url string) map[string]any {
+		list := send(url, "tools/list", nil)
+		manifests, _ := list["tools"].([]any)
+		for _, m := range manifests {
+			if m := m.(map[string]any); m["name"] == tool2.Name {
+				return m
+			}
+		}
+		t.Fatalf("tool %q not found in %+v", tool2.Name, list)
+		return nil
+	}
+	description := func(m map[string]any, param string) any {
+		props := m["inputSchema"].(map[string]any)["properties"].(map[string]any)
+		return props[param].(map[string]any)["description"]
+	}
+
+	base := toolManifest("/")
+	localized := toolManifest("/ja")
+	if _, ok := base["description"]; ok {
+		t.Fatalf("unexpected default description: %v", base["description"])
+	}
+	if localized["description"] != "いくつかのパラメータを持つツール" {
+		t.Fatalf("unexpected overridden description: %v", localized["description"])
+	}
+	if got := description(base, "param2"); got != "This is the second parameter." {
+		t.Fatalf("unexpected default parameter description: %v", got)
+	}
+	if got := description(localized, "param2"); got != "二番目のパラメータ" {
+		t.Fatalf("unexpected overridden parameter description: %v", got)
+	}
+	if got := description(localized, "param1"); got != "This is the first parameter." {
+		t.Fatalf("unexpected parameter description without override: %v", got)
+	}
+
+	// the tool behaves the same in both toolsets
+	args := map[string]any{"name": tool2.Name, "arguments": map[string]any{"param1": 1, "param2": 2}}
+	want := send("/", "tools/call", args)
+	if want["isError"] == true {
+		t.Fatalf("unexpected error result: %+v", want)
+	}
+	if got := send("/ja", "tools/call", args); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected result in the toolset: got %+v, want %+v", got, want)
+	}
+}
+
+func TestMcpToolsListTagFilter(t *testing.T) {
+	toolsMap, toolsets := setUpTaggedResources(t)
+	r, shutdown := setUpServer(t, "mcp", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	testCases := []struct {
+		name    string
+		params  map[string]any
+		want    map[string]any
+		wantErr bool
+	}{
+		{
+			name:   "no filter",
+			params: nil,
+			want:   map[string]any{tool1.Name: []any{"reporting"}, tool2.Name: []any{"reporting", "finance"}, tool3.Name: nil},
+		},
+		{
+			name:   "single tag",
+			params: map[string]any{"filter": map[string]any{"tags": []string{"finance"}}},
+			want:   map[string]any{tool2.Name: []any{"reporting", "finance"}},
+		},
+		{
+			name:   "any of the tags",
+			params: map[string]any{"filter": map[string]any{"tags": []string{"audit", "reporting"}}},
+			want:   map[string]any{tool1.Name: []any{"reporting"}, tool2.Name: []any{"reporting", "finance"}},
+		},
+		{
+			name:   "no matching tool",
+			params: map[string]any{"filter": map[string]any{"tags": []string{"audit"}}},
+			want:   map[string]any{},
+		},
+		{
+			name:    "invalid tag",
+			params:  map[string]any{"filter": map[string]any{"tags": []string{"Audit"}}},
+			wantErr: true,
+		},
+	}
+	for _, protocolVersion := range []string{protocolVersion20241105, protocolVersion20250326, protocolVersion20250618} {
+		for _, tc := range testCases {
+			t.Run(protocolVersion+"/"+tc.name, func(t *testing.T) {
+				reqMarshal, err := json.Marshal(jsonrpc.JSONRPCRequest{
+					Jsonrpc: jsonrpcVersion,
+					Id:      "tools-list-tags",
+					Request: jsonrpc.Request{Method: "tools/list"},
+					Params:  tc.params,
+				})
+				if err != nil {
+					t.Fatalf("unexpected error during marshaling of body")
+				}
+				header := map[string]string{"MCP-Protocol-Version": protocolVersion}
+				_, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), header)
+				if err != nil {
+					t.Fatalf("unexpected error during request: %s", err)
+				}
+				var got struct {
+					Result struct {
+						Tools []map[string]any `json:"tools"`
+					} `json:"result"`
+					Error *jsonrpc.Error `json:"error"`
+				}
+				if err := json.Unmarshal(body, &got); err != nil {
+					t.Fatalf("unexpected error unmarshalling body: %s", err)
+				}
+				if tc.wantErr {
+					if got.Error == nil || got.Error.Code != jsonrpc.INVALID_PARAMS {
+						t.Fatalf("expected an invalid params error, got %s", body)
+					}
+					return
+				}
+				gotTags := make(map[string]any)
+				for _, m := range got.Result.Tools {
+					var tags any
+					if meta, ok := m["_meta"].(map[string]any); ok {
+						tags = meta["toolbox/tags"]
+					}
+					gotTags[m["name"].(string)] = tags
+				}
+				if !reflect.DeepEqual(gotTags, tc.want) {
+					t.Fatalf("unexpected tools: got %+v, want %+v", gotTags, tc.want)
+				}
+			})
+		}
+	}
+}
+
 func runInitializeLifecycle(t *testing.T, ts *httptest.Server, protocolVersion string, initializeWant map[string]any, idHeader bool) string {
 	initializeRequestBody := map[string]any{
 		"jsonrpc": jsonrpcVersion,
@@ -650,6 +942,34 @@ func TestMcpEndpoint(t *testing.T) {
 						},
 					},
 				},
+				{
+					name: "call tool2 with invalid parameters",
+					url:  "/",
+					body: jsonrpc.JSONRPCRequest{
+						Jsonrpc: jsonrpcVersion,
+						Id:      "tools-call-tool2-invalid",
+						Request: jsonrpc.Request{
+							Method: "tools/call",
+						},
+						Params: map[string]any{
+							"name":      "some_params",
+							"arguments": map[string]any{"param1": "one"},
+						},
+					},
+					wantStatusCode: http.StatusOK,
+					want: map[string]any{
+						"jsonrpc": "2.0",
+						"id":      "tools-call-tool2-invalid",
+						"error": map[string]any{
+							"code":    -32602.0,
+							"message": `provided parameters were invalid: unable to parse value for "param1": "one" not type "integer"; parameter "param2" is required`,
+							"data": []any{
+								map[string]any{"name": "param1", "problem": `"one" not type "integer"`, "expected": "integer"},
+								map[string]any{"name": "param2", "problem": "is required", "expected": "integer"},
+							},
+						},
+					},
+				},
 				{
 					name: "call tool4 unauthorized tool",
 					url:  "/",
@@ -742,6 +1062,279 @@ func TestMcpEndpoint(t *testing.T) {
 	}
 }
 
+func TestMcpToolsCallUsageMetadata(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	toolsMap[tool1.Name] = tools.UsageMetadataTool{Tool: tool1}
+	r, shutdown := setUpServer(t, "mcp", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	// the content is `"no_params"`
+	usage := map[string]any{"bytes": 11.0, "rows": 1.0, "estimatedTokens": 3.0}
+	content := []any{map[string]any{"type": "text", "text": `"no_params"`}}
+	testCases := []struct {
+		protocol string
+		want     map[string]any
+	}{
+		{
+			protocol: protocolVersion20241105,
+			want:     map[string]any{"content": content, "_meta": map[string]any{"toolbox/usage": usage}},
+		},
+		{
+			protocol: protocolVersion20250326,
+			want:     map[string]any{"content": content, "_meta": map[string]any{"toolbox/usage": usage}},
+		},
+		{
+			protocol: protocolVersion20250618,
+			want:     map[string]any{"content": content, "structuredContent": map[string]any{"_meta": usage}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.protocol, func(t *testing.T) {
+			initWant := map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "mcp-initialize",
+				"result": map[string]any{
+					"protocolVersion": tc.protocol,
+					"capabilities": map[string]any{
+						"tools": map[string]any{"listChanged": false},
+					},
+					"serverInfo": map[string]any{"name": serverName, "version": fakeVersionString},
+				},
+			}
+			sessionId := runInitializeLifecycle(t, ts, tc.protocol, initWant, tc.protocol == protocolVersion20250326)
+			header := map[string]string{}
+			if sessionId != "" {
+				header["Mcp-Session-Id"] = sessionId
+			}
+			if tc.protocol == protocolVersion20250618 {
+				header["MCP-Protocol-Version"] = tc.protocol
+			}
+
+			reqMarshal, err := json.Marshal(jsonrpc.JSONRPCRequest{
+				Jsonrpc: jsonrpcVersion,
+				Id:      "tools-call-usage",
+				Request: jsonrpc.Request{Method: "tools/call"},
+				Params:  map[string]any{"name": tool1.Name},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error during marshaling of body")
+			}
+			_, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), header)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			var got map[string]any
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unexpected error unmarshalling body: %s", err)
+			}
+			if !reflect.DeepEqual(got["result"], tc.want) {
+				t.Fatalf("unexpected result: got %+v, want %+v", got["result"], tc.want)
+			}
+		})
+	}
+}
+
+func TestMcpToolsCallPollable(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	poll, err := tools.NewPoll(tool1.Name, tools.PollableSpec{})
+	if err != nil {
+		t.Fatalf("unable to create poll: %s", err)
+	}
+	toolsMap[tool1.Name] = tools.PollableTool{Tool: tool1, Poll: poll}
+	r, shutdown := setUpServer(t, "mcp", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	etag, err := tools.ResultETag([]any{tool1.Name})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	meta := map[string]any{"toolbox/etag": etag}
+	unchanged, err := json.Marshal(map[string]any{"unchanged": true, "etag": etag})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for _, protocol := range []string{protocolVersion20241105, protocolVersion20250326, protocolVersion20250618} {
+		t.Run(protocol, func(t *testing.T) {
+			initWant := map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "mcp-initialize",
+				"result": map[string]any{
+					"protocolVersion": protocol,
+					"capabilities": map[string]any{
+						"tools": map[string]any{"listChanged": false},
+					},
+					"serverInfo": map[string]any{"name": serverName, "version": fakeVersionString},
+				},
+			}
+			sessionId := runInitializeLifecycle(t, ts, protocol, initWant, protocol == protocolVersion20250326)
+			header := map[string]string{}
+			if sessionId != "" {
+				header["Mcp-Session-Id"] = sessionId
+			}
+			if protocol == protocolVersion20250618 {
+				header["MCP-Protocol-Version"] = protocol
+			}
+
+			for _, tc := range []struct {
+				desc string
+				args map[string]any
+				text string
+			}{
+				{desc: "first call", args: map[string]any{}, text: `"no_params"`},
+				{desc: "unchanged", args: map[string]any{tools.IfNoneMatchParameter: etag}, text: string(unchanged)},
+				{desc: "stale ETag", args: map[string]any{tools.IfNoneMatchParameter: `"stale"`}, text: `"no_params"`},
+			} {
+				reqMarshal, err := json.Marshal(jsonrpc.JSONRPCRequest{
+					Jsonrpc: jsonrpcVersion,
+					Id:      "tools-call-pollable",
+					Request: jsonrpc.Request{Method: "tools/call"},
+					Params:  map[string]any{"name": tool1.Name, "arguments": tc.args},
+				})
+				if err != nil {
+					t.Fatalf("unexpected error during marshaling of body")
+				}
+				_, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), header)
+				if err != nil {
+					t.Fatalf("unexpected error during request: %s", err)
+				}
+				var got map[string]any
+				if err := json.Unmarshal(body, &got); err != nil {
+					t.Fatalf("unexpected error unmarshalling body: %s", err)
+				}
+				want := map[string]any{
+					"content": []any{map[string]any{"type": "text", "text": tc.text}},
+					"_meta":   meta,
+				}
+				if !reflect.DeepEqual(got["result"], want) {
+					t.Fatalf("%s: unexpected result: got %+v, want %+v", tc.desc, got["result"], want)
+				}
+			}
+		})
+	}
+}
+
+func TestMcpDisabledToolKind(t *testing.T) {
+	for _, hide := range []bool{false, true} {
+		t.Run(fmt.Sprintf("hide %t", hide), func(t *testing.T) {
+			toolsMap, toolsets := setUpDisabledResources(t, hide)
+			r, shutdown := setUpServer(t, "mcp", toolsMap, toolsets)
+			defer shutdown()
+			ts := runServer(r, false)
+			defer ts.Close()
+
+			initWant := map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "mcp-initialize",
+				"result": map[string]any{
+					"protocolVersion": protocolVersion20250618,
+					"capabilities": map[string]any{
+						"tools": map[string]any{"listChanged": false},
+					},
+					"serverInfo": map[string]any{"name": serverName, "version": fakeVersionString},
+				},
+			}
+			runInitializeLifecycle(t, ts, protocolVersion20250618, initWant, false)
+			header := map[string]string{"MCP-Protocol-Version": protocolVersion20250618}
+
+			// tools/list marks or hides the disabled tool
+			reqMarshal, err := json.Marshal(jsonrpc.JSONRPCRequest{
+				Jsonrpc: jsonrpcVersion,
+				Id:      "tools-list",
+				Request: jsonrpc.Request{Method: "tools/list"},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error during marshaling of body")
+			}
+			_, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), header)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			var list struct {
+				Result struct {
+					Tools []tools.McpManifest `json:"tools"`
+				} `json:"result"`
+			}
+			if err := json.Unmarshal(body, &list); err != nil {
+				t.Fatalf("unexpected error unmarshalling body: %s", err)
+			}
+			got := make(map[string]any)
+			for _, m := range list.Result.Tools {
+				got[m.Name] = m.Metadata["toolbox/disabled"]
+			}
+			want := map[string]any{tool1.Name: nil, tool2.Name: true}
+			if hide {
+				want = map[string]any{tool1.Name: nil}
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("unexpected tools: got %v, want %v", got, want)
+			}
+
+			// tools/call of the disabled tool is rejected
+			reqMarshal, err = json.Marshal(jsonrpc.JSONRPCRequest{
+				Jsonrpc: jsonrpcVersion,
+				Id:      "tools-call-disabled",
+				Request: jsonrpc.Request{Method: "tools/call"},
+				Params:  map[string]any{"name": tool2.Name, "arguments": map[string]any{"param1": 1, "param2": 2}},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error during marshaling of body")
+			}
+			resp, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), header)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != http.StatusForbidden {
+				t.Fatalf("unexpected status code: got %d, want %d, %s", resp.StatusCode, http.StatusForbidden, string(body))
+			}
+			if want := "tool kind disabled by operator"; !strings.Contains(string(body), want) {
+				t.Fatalf("unexpected body: got %s, want substring %q", string(body), want)
+			}
+		})
+	}
+}
+
+func TestMcpPayloadLimits(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	r, shutdown := setUpServer(t, "mcp", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	initWant := map[string]any{
+		"jsonrpc": "2.0",
+		"id":      "mcp-initialize",
+		"result": map[string]any{
+			"protocolVersion": protocolVersion20250618,
+			"capabilities": map[string]any{
+				"tools": map[string]any{"listChanged": false},
+			},
+			"serverInfo": map[string]any{"name": serverName, "version": fakeVersionString},
+		},
+	}
+	runInitializeLifecycle(t, ts, protocolVersion20250618, initWant, false)
+	header := map[string]string{"MCP-Protocol-Version": protocolVersion20250618}
+
+	arguments := `{"vis_config": ` + strings.Repeat(`[`, DefaultMaxPayloadDepth) + strings.Repeat(`]`, DefaultMaxPayloadDepth) + `}`
+	reqBody := fmt.Sprintf(`{"jsonrpc": "2.0", "id": "tools-call-deep", "method": "tools/call", "params": {"name": %q, "arguments": %s}}`, tool1.Name, arguments)
+	resp, body, err := runRequest(ts, http.MethodPost, "/", strings.NewReader(reqBody), header)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("unexpected status code: got %d, want %d, %s", resp.StatusCode, http.StatusBadRequest, string(body))
+	}
+	if want := fmt.Sprintf("maximum nesting depth of %d", DefaultMaxPayloadDepth); !strings.Contains(string(body), want) {
+		t.Fatalf("unexpected body: got %s, want substring %q", string(body), want)
+	}
+}
+
 func TestInvalidProtocolVersionHeader(t *testing.T) {
 	toolsMap, toolsets := map[string]tools.Tool{}, map[string]tools.Toolset{}
 	r, shutdown := setUpServer(t, "mcp", toolsMap, toolsets)
@@ -769,6 +1362,142 @@ func TestInvalidProtocolVersionHeader(t *testing.T) {
 	}
 }
 
+func TestMcpProtocolVersionNegotiation(t *testing.T) {
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	instrumentation.McpInit, err = provider.Meter("test").Int64Counter("toolbox.server.mcp.initialize.count")
+	if err != nil {
+		t.Fatalf("unable to create counter: %s", err)
+	}
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	s := &Server{
+		version:         fakeVersionString,
+		logger:          testLogger,
+		instrumentation: instrumentation,
+		sseManager:      newSseManager(context.Background()),
+		ResourceMgr:     NewResourceManager(nil, nil, toolsMap, toolsets),
+	}
+	r, err := mcpRouter(s)
+	if err != nil {
+		t.Fatalf("unable to initialize mcp router: %s", err)
+	}
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	tcs := []struct {
+		name      string
+		requested string
+		want      string
+	}{
+		{name: "known", requested: protocolVersion20241105, want: protocolVersion20241105},
+		{name: "unknown newer", requested: "2099-01-01", want: protocolVersion20250618},
+		{name: "garbage", requested: "not-a-version", want: protocolVersion20250618},
+		{name: "empty", requested: "", want: protocolVersion20250618},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			// clients may also send the requested version as a header
+			header := map[string]string{"MCP-Protocol-Version": tc.requested}
+			initBody, _ := json.Marshal(map[string]any{
+				"jsonrpc": jsonrpcVersion,
+				"id":      "mcp-initialize",
+				"method":  "initialize",
+				"params":  map[string]any{"protocolVersion": tc.requested},
+			})
+			resp, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(initBody), header)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("unexpected status code: got %d: %s", resp.StatusCode, body)
+			}
+			var got map[string]any
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unexpected error unmarshalling body: %s", err)
+			}
+			wantResult := map[string]any{
+				"protocolVersion": tc.want,
+				"capabilities": map[string]any{
+					"tools": map[string]any{"listChanged": false},
+				},
+				"serverInfo": map[string]any{"name": serverName, "version": fakeVersionString},
+			}
+			if !reflect.DeepEqual(got["result"], wantResult) {
+				t.Fatalf("unexpected result: got %+v, want %+v", got["result"], wantResult)
+			}
+
+			// the handlers of the negotiated version serve later requests
+			header = map[string]string{"MCP-Protocol-Version": tc.want}
+			for _, method := range []string{"tools/list", "tools/call"} {
+				reqBody, _ := json.Marshal(map[string]any{
+					"jsonrpc": jsonrpcVersion,
+					"id":      method,
+					"method":  method,
+					"params":  map[string]any{"name": tool1.Name},
+				})
+				resp, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqBody), header)
+				if err != nil {
+					t.Fatalf("unexpected error during request: %s", err)
+				}
+				var got map[string]any
+				if err := json.Unmarshal(body, &got); err != nil {
+					t.Fatalf("unexpected error unmarshalling body: %s", err)
+				}
+				if resp.StatusCode != http.StatusOK || got["result"] == nil {
+					t.Fatalf("unexpected %s response: %d %s", method, resp.StatusCode, body)
+				}
+			}
+		})
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("unable to collect metrics: %s", err)
+	}
+	got := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			if !ok {
+				continue
+			}
+			for _, dp := range sum.DataPoints {
+				v, _ := dp.Attributes.Value("toolbox.mcp.protocol_version")
+				fallback, _ := dp.Attributes.Value("toolbox.mcp.protocol_version.fallback")
+				got[fmt.Sprintf("%s fallback=%t", v.AsString(), fallback.AsBool())] += dp.Value
+			}
+		}
+	}
+	want := map[string]int64{
+		protocolVersion20241105 + " fallback=false": 1,
+		protocolVersion20250618 + " fallback=true":  3,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected negotiated versions: got %v, want %v", got, want)
+	}
+}
+
+func TestNegotiateProtocolVersion(t *testing.T) {
+	for _, v := range mcp.SUPPORTED_PROTOCOL_VERSIONS {
+		if got := mcp.NegotiateProtocolVersion(v); got != v {
+			t.Fatalf("unexpected version for %q: got %q", v, got)
+		}
+	}
+	for _, v := range []string{"2099-01-01", "2024-01-01", "1.0", "", "2025-06-18 "} {
+		if got := mcp.NegotiateProtocolVersion(v); got != mcp.LATEST_PROTOCOL_VERSION {
+			t.Fatalf("unexpected version for %q: got %q, want %q", v, got, mcp.LATEST_PROTOCOL_VERSION)
+		}
+	}
+}
+
 func TestDeleteEndpoint(t *testing.T) {
 	toolsMap, toolsets := map[string]tools.Tool{}, map[string]tools.Toolset{}
 	r, shutdown := setUpServer(t, "mcp", toolsMap, toolsets)
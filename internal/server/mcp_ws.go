@@ -0,0 +1,365 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"github.com/gorilla/websocket"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+const (
+	// wsPingInterval is how often a ping is sent to keep the connection
+	// alive.
+	wsPingInterval = 30 * time.Second
+	// wsPongWait is how long the connection stays open without hearing from
+	// the client.
+	wsPongWait = 2 * wsPingInterval
+	// wsWriteWait bounds the time to write a message.
+	wsWriteWait = 10 * time.Second
+	// wsCloseWait is how long the client has to acknowledge the close of the
+	// connection.
+	wsCloseWait = time.Second
+
+	// cancelledNotification is sent by clients to cancel an in-flight request.
+	cancelledNotification = "notifications/cancelled"
+)
+
+var wsUpgrader = websocket.Upgrader{
+	// MCP clients are not browsers, so the origin is not meaningful
+	CheckOrigin: func(*http.Request) bool { return true },
+}
+
+// wsManager tracks the open websocket sessions, so that they can be closed
+// when the server shuts down. Unlike other requests, the connections of
+// websocket sessions are not tracked by http.Server once upgraded.
+type wsManager struct {
+	mu       sync.Mutex
+	sessions map[*wsSession]struct{}
+	closed   bool
+}
+
+func newWsManager() *wsManager {
+	return &wsManager{sessions: make(map[*wsSession]struct{})}
+}
+
+// add tracks session. It returns false if the server is shutting down.
+func (m *wsManager) add(session *wsSession) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return false
+	}
+	m.sessions[session] = struct{}{}
+	return true
+}
+
+func (m *wsManager) remove(session *wsSession) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, session)
+}
+
+// shutdown closes all sessions once their in-flight requests are done, or
+// ctx is done.
+func (m *wsManager) shutdown(ctx context.Context) {
+	m.mu.Lock()
+	m.closed = true
+	sessions := make([]*wsSession, 0, len(m.sessions))
+	for session := range m.sessions {
+		sessions = append(sessions, session)
+	}
+	m.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, session := range sessions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			session.shutdown(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+// wsSession is an MCP session over a websocket connection. Requests are
+// processed concurrently, and their responses carry their id.
+type wsSession struct {
+	server      *Server
+	conn        *websocket.Conn
+	toolsetName string
+	// header is the header of the upgrade request, which authorizes the tool
+	// calls of the session.
+	header http.Header
+
+	writeMu sync.Mutex
+
+	mu       sync.Mutex
+	protocol string
+	// inFlight holds the cancel function of each request being processed,
+	// keyed by the JSON encoding of its id.
+	inFlight map[string]context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// wsHandler upgrades the request to a websocket connection, over which it
+// serves an MCP session until the connection closes.
+func wsHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/mcp/ws")
+	r = r.WithContext(ctx)
+	ctx = util.WithLogger(ctx, s.logger)
+
+	sessionId := uuid.New().String()
+	toolsetName := chi.URLParam(r, "toolsetName")
+	span.SetAttributes(attribute.String("session_id", sessionId))
+	span.SetAttributes(attribute.String("toolset_name", toolsetName))
+
+	var err error
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	ctx, err = withScheduleOverride(ctx, r)
+	if err != nil {
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		return
+	}
+	ctx = s.withClientAttribution(ctx, r)
+
+	// the upgrader responds to the request itself if it fails
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		s.logger.DebugContext(ctx, fmt.Sprintf("unable to upgrade to websocket: %s", err))
+		return
+	}
+	defer conn.Close()
+	conn.SetReadLimit(s.wsMaxMessageSize)
+
+	session := &wsSession{
+		server:      s,
+		conn:        conn,
+		toolsetName: toolsetName,
+		header:      r.Header.Clone(),
+		inFlight:    make(map[string]context.CancelFunc),
+	}
+	if !s.wsManager.add(session) {
+		session.close(websocket.CloseGoingAway, "server is shutting down")
+		return
+	}
+	defer s.wsManager.remove(session)
+
+	s.logger.DebugContext(ctx, fmt.Sprintf("websocket session %s opened", sessionId))
+	defer s.endSession(sessionId)
+	err = session.serve(s.withSession(ctx, sessionId))
+	s.logger.DebugContext(ctx, fmt.Sprintf("websocket session %s closed: %s", sessionId, err))
+	if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+		err = nil
+	}
+}
+
+// serve reads the messages of the client until the connection closes, and
+// returns the error that closed it.
+func (ws *wsSession) serve(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer func() {
+		// abandon the in-flight requests, whose responses can no longer be
+		// sent
+		cancel()
+		ws.wg.Wait()
+	}()
+
+	_ = ws.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	ws.conn.SetPongHandler(func(string) error {
+		return ws.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+	go ws.keepAlive(ctx)
+
+	for {
+		_, body, err := ws.conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		// any message shows the client is alive
+		_ = ws.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+		ws.handle(ctx, body)
+	}
+}
+
+// keepAlive pings the client until ctx is done.
+func (ws *wsSession) keepAlive(ctx context.Context) {
+	ticker := time.NewTicker(wsPingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := ws.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// handle processes a message of the client. Requests other than initialize
+// are processed in the background, so that a slow tool call does not hold up
+// the session.
+func (ws *wsSession) handle(ctx context.Context, body []byte) {
+	var baseMessage jsonrpc.BaseMessage
+	if err := json.Unmarshal(body, &baseMessage); err == nil {
+		switch {
+		case baseMessage.Method == cancelledNotification:
+			ws.cancel(body)
+			return
+		case baseMessage.Id != nil && !isInitializeRequest(body):
+			ws.handleAsync(ctx, baseMessage.Id, body)
+			return
+		}
+	}
+
+	ws.mu.Lock()
+	protocol := ws.protocol
+	ws.mu.Unlock()
+	v, res, err := processMcpMessage(ctx, body, ws.server, protocol, ws.toolsetName, ws.header)
+	if err != nil {
+		ws.server.logger.DebugContext(ctx, fmt.Errorf("error processing message: %w", err).Error())
+	}
+	if v != "" {
+		ws.mu.Lock()
+		ws.protocol = v
+		ws.mu.Unlock()
+	}
+	// notifications do not expect a response
+	if res != nil {
+		ws.write(ctx, res)
+	}
+}
+
+func (ws *wsSession) handleAsync(ctx context.Context, id jsonrpc.RequestId, body []byte) {
+	key := requestKey(id)
+	reqCtx, cancel := context.WithCancel(ctx)
+
+	ws.mu.Lock()
+	if _, ok := ws.inFlight[key]; ok {
+		ws.mu.Unlock()
+		cancel()
+		err := fmt.Errorf("request id %s is already in use", key)
+		ws.write(ctx, jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil))
+		return
+	}
+	ws.inFlight[key] = cancel
+	protocol := ws.protocol
+	ws.wg.Add(1)
+	ws.mu.Unlock()
+
+	go func() {
+		defer ws.wg.Done()
+		defer func() {
+			ws.mu.Lock()
+			delete(ws.inFlight, key)
+			ws.mu.Unlock()
+			cancel()
+		}()
+
+		_, res, err := processMcpMessage(reqCtx, body, ws.server, protocol, ws.toolsetName, ws.header)
+		if err != nil {
+			ws.server.logger.DebugContext(ctx, fmt.Errorf("error processing message: %w", err).Error())
+		}
+		// the client does not expect a response to a cancelled request
+		if reqCtx.Err() != nil {
+			return
+		}
+		ws.write(ctx, res)
+	}()
+}
+
+// cancel cancels the request named by a cancelled notification.
+func (ws *wsSession) cancel(body []byte) {
+	var notification struct {
+		Params struct {
+			RequestId jsonrpc.RequestId `json:"requestId"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(body, &notification); err != nil || notification.Params.RequestId == nil {
+		return
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if cancel, ok := ws.inFlight[requestKey(notification.Params.RequestId)]; ok {
+		cancel()
+	}
+}
+
+func (ws *wsSession) write(ctx context.Context, res any) {
+	ws.writeMu.Lock()
+	defer ws.writeMu.Unlock()
+	_ = ws.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+	if err := ws.conn.WriteJSON(res); err != nil {
+		ws.server.logger.DebugContext(ctx, fmt.Sprintf("unable to write websocket message: %s", err))
+	}
+}
+
+// shutdown closes the session once its in-flight requests are done, or ctx
+// is done.
+func (ws *wsSession) shutdown(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		ws.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+	ws.close(websocket.CloseGoingAway, "server is shutting down")
+}
+
+// close sends a close message, after which the client is expected to close
+// the connection.
+func (ws *wsSession) close(code int, text string) {
+	ws.writeMu.Lock()
+	defer ws.writeMu.Unlock()
+	err := ws.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, text), time.Now().Add(wsWriteWait))
+	if err != nil && !errors.Is(err, websocket.ErrCloseSent) {
+		_ = ws.conn.Close()
+		return
+	}
+	// stop waiting for the client if it does not close the connection
+	_ = ws.conn.SetReadDeadline(time.Now().Add(wsCloseWait))
+}
+
+// requestKey identifies a request by the JSON encoding of its id, so that
+// numeric and string ids are distinct.
+func requestKey(id jsonrpc.RequestId) string {
+	b, _ := json.Marshal(id)
+	return string(b)
+}
@@ -0,0 +1,311 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/server/mcp/v20250618"
+	"github.com/googleapis/genai-toolbox/internal/telemetry"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/gorilla/websocket"
+)
+
+// cancellableTool is a MockTool whose invocations block until their context
+// is cancelled.
+type cancellableTool struct {
+	MockTool
+	started   chan struct{}
+	cancelled chan struct{}
+}
+
+func (t cancellableTool) Invoke(ctx context.Context, _ tools.Invocation) (any, error) {
+	t.started <- struct{}{}
+	<-ctx.Done()
+	t.cancelled <- struct{}{}
+	return nil, ctx.Err()
+}
+
+// setUpWsServer runs an MCP server with the given max websocket message size.
+func setUpWsServer(t *testing.T, toolsMap map[string]tools.Tool, toolsets map[string]tools.Toolset, maxMessageSize int64) (*Server, *httptest.Server) {
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+	s := &Server{
+		version:          fakeVersionString,
+		logger:           testLogger,
+		instrumentation:  instrumentation,
+		sseManager:       newSseManager(context.Background()),
+		wsManager:        newWsManager(),
+		wsMaxMessageSize: maxMessageSize,
+		ResourceMgr:      NewResourceManager(nil, nil, toolsMap, toolsets),
+	}
+	r, err := mcpRouter(s)
+	if err != nil {
+		t.Fatalf("unable to initialize mcp router: %s", err)
+	}
+	ts := httptest.NewServer(r)
+	t.Cleanup(ts.Close)
+	return s, ts
+}
+
+func dialWs(t *testing.T, ts *httptest.Server, path string, header http.Header) *websocket.Conn {
+	t.Helper()
+	url := "ws" + strings.TrimPrefix(ts.URL, "http") + path
+	conn, resp, err := websocket.DefaultDialer.Dial(url, header)
+	if err != nil {
+		t.Fatalf("unable to dial websocket: %s", err)
+	}
+	resp.Body.Close()
+	t.Cleanup(func() { conn.Close() })
+	_ = conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	return conn
+}
+
+func writeWs(t *testing.T, conn *websocket.Conn, msg string) {
+	t.Helper()
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(msg)); err != nil {
+		t.Fatalf("unable to write message: %s", err)
+	}
+}
+
+func readWs(t *testing.T, conn *websocket.Conn) map[string]any {
+	t.Helper()
+	var got map[string]any
+	if err := conn.ReadJSON(&got); err != nil {
+		t.Fatalf("unable to read message: %s", err)
+	}
+	return got
+}
+
+func TestWsHandshake(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	_, ts := setUpWsServer(t, toolsMap, toolsets, DefaultMcpWebsocketMaxMessageSize)
+	conn := dialWs(t, ts, "/ws", nil)
+
+	writeWs(t, conn, `{"jsonrpc":"2.0","id":"init","method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"test","version":"1.0"}}}`)
+	got := readWs(t, conn)
+	if got["id"] != "init" {
+		t.Fatalf("unexpected id: got %v, want %q", got["id"], "init")
+	}
+	result, _ := got["result"].(map[string]any)
+	if result["protocolVersion"] != v20250618.PROTOCOL_VERSION {
+		t.Fatalf("unexpected protocol version: got %v, want %q", result["protocolVersion"], v20250618.PROTOCOL_VERSION)
+	}
+
+	// notifications do not get a response, so the next message answers the
+	// list
+	writeWs(t, conn, `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	writeWs(t, conn, `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+	got = readWs(t, conn)
+	if got["id"] != float64(1) {
+		t.Fatalf("unexpected id: got %v, want 1", got["id"])
+	}
+	result, _ = got["result"].(map[string]any)
+	listed, _ := result["tools"].([]any)
+	var names []string
+	for _, tool := range listed {
+		names = append(names, tool.(map[string]any)["name"].(string))
+	}
+	if diff := cmp.Diff([]string{tool1.Name, tool2.Name}, names); diff != "" {
+		t.Fatalf("unexpected tools: diff %v", diff)
+	}
+
+	writeWs(t, conn, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"some_params","arguments":{"param1":1,"param2":2}}}`)
+	got = readWs(t, conn)
+	want := map[string]any{
+		"jsonrpc": "2.0",
+		"id":      float64(2),
+		"result": map[string]any{
+			"content": []any{map[string]any{"type": "text", "text": `"some_params"`}},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected response: diff %v", diff)
+	}
+
+	if err := conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")); err != nil {
+		t.Fatalf("unable to close: %s", err)
+	}
+	_, _, err := conn.ReadMessage()
+	if !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+		t.Fatalf("unexpected close: %v", err)
+	}
+}
+
+func TestWsToolsetPath(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	_, ts := setUpWsServer(t, toolsMap, toolsets, DefaultMcpWebsocketMaxMessageSize)
+
+	conn := dialWs(t, ts, "/tool1_only/ws", nil)
+	writeWs(t, conn, `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+	got := readWs(t, conn)
+	result, _ := got["result"].(map[string]any)
+	if listed, _ := result["tools"].([]any); len(listed) != 1 {
+		t.Fatalf("unexpected tools: got %v, want only %q", listed, tool1.Name)
+	}
+
+	conn = dialWs(t, ts, "/missing/ws", nil)
+	writeWs(t, conn, `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+	got = readWs(t, conn)
+	if _, ok := got["error"]; !ok {
+		t.Fatalf("expected an error for a missing toolset, got %v", got)
+	}
+}
+
+func TestWsUpgradeHeaders(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool5, tool1})
+	_, ts := setUpWsServer(t, toolsMap, toolsets, DefaultMcpWebsocketMaxMessageSize)
+	call := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"require_client_auth_tool","arguments":{}}}`
+
+	conn := dialWs(t, ts, "/ws", nil)
+	writeWs(t, conn, call)
+	got := readWs(t, conn)
+	if _, ok := got["error"]; !ok {
+		t.Fatalf("expected an error without an Authorization header, got %v", got)
+	}
+
+	// the headers of the upgrade request authorize every call of the session
+	conn = dialWs(t, ts, "/ws", http.Header{"Authorization": []string{"Bearer token"}})
+	for i := 0; i < 2; i++ {
+		writeWs(t, conn, call)
+		got = readWs(t, conn)
+		if _, ok := got["result"]; !ok {
+			t.Fatalf("expected a result with an Authorization header, got %v", got)
+		}
+	}
+}
+
+func TestWsConcurrentRequests(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	blocking := blockingTool{MockTool: tool1, started: make(chan struct{}, 1), release: make(chan struct{})}
+	toolsMap[tool1.Name] = blocking
+	_, ts := setUpWsServer(t, toolsMap, toolsets, DefaultMcpWebsocketMaxMessageSize)
+	conn := dialWs(t, ts, "/ws", nil)
+
+	writeWs(t, conn, `{"jsonrpc":"2.0","id":"slow","method":"tools/call","params":{"name":"no_params","arguments":{}}}`)
+	<-blocking.started
+
+	// a later request is answered while the first is in flight
+	writeWs(t, conn, `{"jsonrpc":"2.0","id":"fast","method":"tools/call","params":{"name":"some_params","arguments":{"param1":1,"param2":2}}}`)
+	if got := readWs(t, conn); got["id"] != "fast" {
+		t.Fatalf("unexpected id: got %v, want %q", got["id"], "fast")
+	}
+
+	// ids of in-flight requests cannot be reused
+	writeWs(t, conn, `{"jsonrpc":"2.0","id":"slow","method":"tools/list"}`)
+	got := readWs(t, conn)
+	if _, ok := got["error"]; got["id"] != "slow" || !ok {
+		t.Fatalf("expected an error for a duplicate id, got %v", got)
+	}
+
+	close(blocking.release)
+	got = readWs(t, conn)
+	if _, ok := got["result"]; got["id"] != "slow" || !ok {
+		t.Fatalf("expected the result of the slow request, got %v", got)
+	}
+}
+
+func TestWsCancellation(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	cancellable := cancellableTool{MockTool: tool1, started: make(chan struct{}, 1), cancelled: make(chan struct{}, 1)}
+	toolsMap[tool1.Name] = cancellable
+	_, ts := setUpWsServer(t, toolsMap, toolsets, DefaultMcpWebsocketMaxMessageSize)
+	conn := dialWs(t, ts, "/ws", nil)
+
+	writeWs(t, conn, `{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"no_params","arguments":{}}}`)
+	<-cancellable.started
+	// a string id does not cancel a numeric one
+	writeWs(t, conn, `{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":"7"}}`)
+	writeWs(t, conn, `{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":7,"reason":"user aborted"}}`)
+	select {
+	case <-cancellable.cancelled:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("the tool call was not cancelled")
+	}
+
+	// the cancelled request is not answered, so the next message answers the
+	// list
+	writeWs(t, conn, `{"jsonrpc":"2.0","id":8,"method":"tools/list"}`)
+	if got := readWs(t, conn); got["id"] != float64(8) {
+		t.Fatalf("unexpected id: got %v, want 8", got["id"])
+	}
+}
+
+func TestWsOversizedMessage(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	_, ts := setUpWsServer(t, toolsMap, toolsets, 64)
+	conn := dialWs(t, ts, "/ws", nil)
+
+	writeWs(t, conn, `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+	readWs(t, conn)
+
+	writeWs(t, conn, fmt.Sprintf(`{"jsonrpc":"2.0","id":2,"method":"tools/list","params":{"padding":%q}}`, strings.Repeat("x", 64)))
+	_, _, err := conn.ReadMessage()
+	if !websocket.IsCloseError(err, websocket.CloseMessageTooBig) {
+		t.Fatalf("unexpected error: got %v, want close %d", err, websocket.CloseMessageTooBig)
+	}
+}
+
+func TestWsShutdown(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	blocking := blockingTool{MockTool: tool1, started: make(chan struct{}, 1), release: make(chan struct{})}
+	toolsMap[tool1.Name] = blocking
+	s, ts := setUpWsServer(t, toolsMap, toolsets, DefaultMcpWebsocketMaxMessageSize)
+	conn := dialWs(t, ts, "/ws", nil)
+
+	writeWs(t, conn, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"no_params","arguments":{}}}`)
+	<-blocking.started
+
+	done := make(chan struct{})
+	go func() {
+		s.wsManager.shutdown(context.Background())
+		close(done)
+	}()
+	// in-flight requests are answered before the connection is closed
+	close(blocking.release)
+	got := readWs(t, conn)
+	if _, ok := got["result"]; !ok {
+		t.Fatalf("expected the result of the in-flight request, got %v", got)
+	}
+	_, _, err := conn.ReadMessage()
+	if !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+		t.Fatalf("unexpected error: got %v, want close %d", err, websocket.CloseGoingAway)
+	}
+	<-done
+
+	// new connections are refused once the server is shutting down
+	conn = dialWs(t, ts, "/ws", nil)
+	_, _, err = conn.ReadMessage()
+	var closeErr *websocket.CloseError
+	if !errors.As(err, &closeErr) || closeErr.Code != websocket.CloseGoingAway {
+		t.Fatalf("unexpected error: got %v, want close %d", err, websocket.CloseGoingAway)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+const (
+	// multipartContentType is the content type of invocations uploading
+	// files.
+	multipartContentType = "multipart/form-data"
+	// multipartOverhead is the room left for the headers and text fields of
+	// a multipart/form-data request on top of the size of its file.
+	multipartOverhead = 1 << 20
+	// multipartMemory is how much of the files of a multipart/form-data
+	// request is kept in memory. The rest is stored in temporary files.
+	multipartMemory = 8 << 20
+)
+
+// isMultipart reports whether r has a multipart/form-data body.
+func isMultipart(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == multipartContentType
+}
+
+// multipartParams reads the parameters of an invocation of tool from a
+// multipart/form-data request. File parts are passed as *tools.File. Text
+// fields are decoded as JSON, unless the parameter of the same name is a
+// string or the field is not valid JSON. On failure, it also returns the HTTP
+// status code to respond with.
+//
+// The caller must remove the temporary files of the request with
+// r.MultipartForm.RemoveAll once the invocation is done.
+func multipartParams(w http.ResponseWriter, r *http.Request, tool tools.Tool, limits util.PayloadLimits) (map[string]any, int, error) {
+	ft, ok := tools.As[tools.FileTool](tool)
+	if !ok {
+		return nil, http.StatusUnsupportedMediaType, fmt.Errorf("tool does not accept %s requests", multipartContentType)
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, ft.MaxUploadBytes()+multipartOverhead)
+	if err := r.ParseMultipartForm(multipartMemory); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, http.StatusRequestEntityTooLarge, fmt.Errorf("request body exceeds the limit of %d bytes for file uploads", ft.MaxUploadBytes())
+		}
+		return nil, http.StatusBadRequest, fmt.Errorf("request body was invalid %s: %w", multipartContentType, err)
+	}
+
+	paramTypes := make(map[string]string)
+	for _, p := range tool.Manifest().Parameters {
+		paramTypes[p.Name] = p.Type
+	}
+
+	data := make(map[string]any)
+	for name, values := range r.MultipartForm.Value {
+		if len(values) != 1 {
+			return nil, http.StatusBadRequest, fmt.Errorf("field %q must be sent once, got %d", name, len(values))
+		}
+		v, err := decodeFormValue(values[0], paramTypes[name], limits)
+		if err != nil {
+			return nil, http.StatusBadRequest, payloadError(multipartContentType, fmt.Errorf("field %q: %w", name, err))
+		}
+		data[name] = v
+	}
+	for name, headers := range r.MultipartForm.File {
+		if len(headers) != 1 {
+			return nil, http.StatusBadRequest, fmt.Errorf("file %q must be sent once, got %d", name, len(headers))
+		}
+		if _, ok := data[name]; ok {
+			return nil, http.StatusBadRequest, fmt.Errorf("%q is sent both as a field and as a file", name)
+		}
+		data[name] = newUploadedFile(headers[0])
+	}
+	return data, 0, nil
+}
+
+// decodeFormValue decodes the value of a text field of a multipart/form-data
+// request for a parameter of type paramType. It only fails if the value
+// exceeds limits.
+func decodeFormValue(value, paramType string, limits util.PayloadLimits) (any, error) {
+	if paramType == "string" {
+		return value, nil
+	}
+	var v any
+	if err := util.DecodeJSONWithLimits(strings.NewReader(value), &v, limits); err != nil {
+		var limitErr *util.PayloadLimitError
+		if errors.As(err, &limitErr) {
+			return nil, err
+		}
+		return value, nil
+	}
+	return v, nil
+}
+
+// newUploadedFile returns the tools.File of a file part.
+func newUploadedFile(h *multipart.FileHeader) *tools.File {
+	return &tools.File{
+		Filename: h.Filename,
+		Size:     h.Size,
+		Open: func() (io.ReadCloser, error) {
+			return h.Open()
+		},
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// uploadTool is a tools.FileTool that returns its parameters, with the
+// content of its file.
+type uploadTool struct {
+	MockTool
+	maxBytes int64
+}
+
+func (t uploadTool) MaxUploadBytes() int64 {
+	return t.maxBytes
+}
+
+func (t uploadTool) Invoke(_ context.Context, inv tools.Invocation) (any, error) {
+	out := make(map[string]any)
+	for k, v := range inv.Params.AsMap() {
+		f, ok := v.(*tools.File)
+		if !ok {
+			out[k] = v
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		b, err := io.ReadAll(rc)
+		if err != nil {
+			return nil, err
+		}
+		out[k] = fmt.Sprintf("%s: %s", f.Filename, b)
+	}
+	return out, nil
+}
+
+// multipartBody returns a multipart/form-data body with the given text fields
+// and files, and its content type.
+func multipartBody(t *testing.T, fields map[string]string, files map[string]string) (io.Reader, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	for k, v := range fields {
+		if err := mw.WriteField(k, v); err != nil {
+			t.Fatalf("unable to write field: %s", err)
+		}
+	}
+	for k, v := range files {
+		part, err := mw.CreateFormFile(k, k+".csv")
+		if err != nil {
+			t.Fatalf("unable to create file part: %s", err)
+		}
+		if _, err := part.Write([]byte(v)); err != nil {
+			t.Fatalf("unable to write file part: %s", err)
+		}
+	}
+	if err := mw.Close(); err != nil {
+		t.Fatalf("unable to close multipart writer: %s", err)
+	}
+	return &buf, mw.FormDataContentType()
+}
+
+func TestToolInvokeMultipart(t *testing.T) {
+	upload := uploadTool{
+		MockTool: MockTool{
+			Name: "upload",
+			Params: tools.Parameters{
+				tools.NewStringParameter("table", "the table"),
+				tools.NewIntParameterWithRequired("limit", "a limit", false),
+				tools.NewFileParameter("file", "the file", 16),
+			},
+		},
+		maxBytes: 16,
+	}
+	toolsMap := map[string]tools.Tool{
+		"upload":     upload,
+		"upload_old": tools.AliasTool{Tool: upload, Name: "upload_old", Target: "upload"},
+		"no_params":  tool1,
+	}
+	r, shutdown := setUpServer(t, "api", toolsMap, map[string]tools.Toolset{})
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	tcs := []struct {
+		desc   string
+		tool   string
+		fields map[string]string
+		files  map[string]string
+		want   int
+		result map[string]any
+		err    string
+	}{
+		{
+			desc:   "file and fields",
+			tool:   "upload",
+			fields: map[string]string{"table": "123", "limit": "5"},
+			files:  map[string]string{"file": "id\n1\n"},
+			want:   http.StatusOK,
+			result: map[string]any{"table": "123", "limit": float64(5), "file": "file.csv: id\n1\n"},
+		},
+		{
+			desc:   "through an alias",
+			tool:   "upload_old",
+			fields: map[string]string{"table": "sales"},
+			files:  map[string]string{"file": "id\n"},
+			want:   http.StatusOK,
+			result: map[string]any{"table": "sales", "limit": nil, "file": "file.csv: id\n"},
+		},
+		{
+			desc:   "file exceeding the parameter limit",
+			tool:   "upload",
+			fields: map[string]string{"table": "sales"},
+			files:  map[string]string{"file": strings.Repeat("x", 17)},
+			want:   http.StatusBadRequest,
+			err:    "exceeds the limit of 16 bytes",
+		},
+		{
+			desc:   "body exceeding the upload limit",
+			tool:   "upload",
+			fields: map[string]string{"table": "sales"},
+			files:  map[string]string{"file": strings.Repeat("x", 2<<20)},
+			want:   http.StatusRequestEntityTooLarge,
+			err:    "exceeds the limit of 16 bytes for file uploads",
+		},
+		{
+			desc:   "invalid field",
+			tool:   "upload",
+			fields: map[string]string{"table": "sales", "limit": "five"},
+			files:  map[string]string{"file": "id\n"},
+			want:   http.StatusBadRequest,
+			err:    `unable to parse value for "limit"`,
+		},
+		{
+			desc:   "field exceeding the payload limits",
+			tool:   "upload",
+			fields: map[string]string{"table": "sales", "limit": strings.Repeat("[", DefaultMaxPayloadDepth+1) + strings.Repeat("]", DefaultMaxPayloadDepth+1)},
+			files:  map[string]string{"file": "id\n"},
+			want:   http.StatusBadRequest,
+			err:    fmt.Sprintf(`request body was rejected: field "limit": payload exceeds the maximum nesting depth of %d`, DefaultMaxPayloadDepth),
+		},
+		{
+			desc:   "missing file",
+			tool:   "upload",
+			fields: map[string]string{"table": "sales"},
+			want:   http.StatusBadRequest,
+			err:    `parameter "file" is required`,
+		},
+		{
+			desc:   "tool without file uploads",
+			tool:   "no_params",
+			fields: map[string]string{"table": "sales"},
+			want:   http.StatusUnsupportedMediaType,
+			err:    "tool does not accept multipart/form-data requests",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			body, contentType := multipartBody(t, tc.fields, tc.files)
+			resp, respBody, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tc.tool), body, map[string]string{"Content-Type": contentType})
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.want {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, tc.want, respBody)
+			}
+			var got map[string]any
+			if err := json.Unmarshal(respBody, &got); err != nil {
+				t.Fatalf("unexpected error unmarshalling body: %s", err)
+			}
+			if tc.err != "" {
+				if msg, _ := got["error"].(string); !strings.Contains(msg, tc.err) {
+					t.Fatalf("unexpected error: got %q, want substring %q", msg, tc.err)
+				}
+				return
+			}
+			var result map[string]any
+			if err := json.Unmarshal([]byte(got["result"].(string)), &result); err != nil {
+				t.Fatalf("unexpected error unmarshalling result: %s", err)
+			}
+			if !reflect.DeepEqual(result, tc.result) {
+				t.Fatalf("unexpected result: got %v, want %v", result, tc.result)
+			}
+		})
+	}
+}
@@ -0,0 +1,372 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/server/mcp"
+	mcputil "github.com/googleapis/genai-toolbox/internal/server/mcp/util"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/state"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// schemaCacheTTL is how long a generated source schema is reused before it is
+// generated again.
+const schemaCacheTTL = 5 * time.Minute
+
+// ToolSources returns the name of the source each tool acts on, keyed by tool
+// name and by alias. Tools without a `source` field are omitted.
+func ToolSources(cfgs ToolConfigs) map[string]string {
+	toolSources := make(map[string]string)
+	for name, tc := range cfgs {
+		source := toolSourceName(tc)
+		if source == "" {
+			continue
+		}
+		toolSources[name] = source
+		if aliasCfg, ok := tc.(tools.AliasConfig); ok {
+			for _, alias := range aliasCfg.Aliases {
+				toolSources[alias] = source
+			}
+		}
+	}
+	return toolSources
+}
+
+// ToolKinds returns the kind of each tool, keyed by tool name and by alias.
+func ToolKinds(cfgs ToolConfigs) map[string]string {
+	toolKinds := make(map[string]string)
+	for name, tc := range cfgs {
+		toolKinds[name] = tc.ToolConfigKind()
+		if aliasCfg, ok := tc.(tools.AliasConfig); ok {
+			for _, alias := range aliasCfg.Aliases {
+				toolKinds[alias] = tc.ToolConfigKind()
+			}
+		}
+	}
+	return toolKinds
+}
+
+// ToolSummary describes a tool from its config alone, without initializing
+// it or its source.
+type ToolSummary struct {
+	Name        string   `json:"name"`
+	Kind        string   `json:"kind"`
+	Source      string   `json:"source,omitempty"`
+	Description string   `json:"description"`
+	Aliases     []string `json:"aliases,omitempty"`
+	// Parameters are the parameters declared in the config of the tool. The
+	// tools whose parameters are fixed by their kind have none.
+	Parameters []tools.ParameterManifest `json:"parameters"`
+}
+
+// ToolSummaries returns the summaries of the tools of cfgs, sorted by name.
+func ToolSummaries(cfgs ToolConfigs) []ToolSummary {
+	summaries := make([]ToolSummary, 0, len(cfgs))
+	for _, name := range slices.Sorted(maps.Keys(cfgs)) {
+		tc := cfgs[name]
+		summary := ToolSummary{Name: name, Kind: tc.ToolConfigKind(), Source: toolSourceName(tc)}
+		if f := toolConfigField(tc, "Description"); f.IsValid() && f.Kind() == reflect.String {
+			summary.Description = f.String()
+		}
+		if aliasCfg, ok := tc.(tools.AliasConfig); ok {
+			summary.Aliases = aliasCfg.Aliases
+		}
+		if f := toolConfigField(tc, "Parameters"); f.IsValid() && f.CanInterface() {
+			params, _ := f.Interface().(tools.Parameters)
+			summary.Parameters = params.Manifest()
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+// toolSourceName returns the value of the `Source` field of a tool config, or
+// "" if it has none.
+func toolSourceName(tc tools.ToolConfig) string {
+	f := toolConfigField(tc, "Source")
+	if !f.IsValid() || f.Kind() != reflect.String {
+		return ""
+	}
+	return f.String()
+}
+
+// toolArrayParam returns the name of the first array parameter of a tool
+// config bound to its statement, or "" if it has none. Template parameters,
+// which are interpolated into the statement, are not bound.
+func toolArrayParam(tc tools.ToolConfig) string {
+	f := toolConfigField(tc, "Parameters")
+	if !f.IsValid() || !f.CanInterface() {
+		return ""
+	}
+	params, _ := f.Interface().(tools.Parameters)
+	for _, p := range params {
+		if p.GetType() == "array" {
+			return p.GetName()
+		}
+	}
+	return ""
+}
+
+// spillsToFile reports whether any of toolConfigs sets `spillToFile`.
+func spillsToFile(toolConfigs ToolConfigs) bool {
+	for _, tc := range toolConfigs {
+		if f := toolConfigField(tc, "Spill"); f.Kind() == reflect.Pointer && !f.IsNil() {
+			return true
+		}
+	}
+	return false
+}
+
+// toolConfigField returns the named field of a tool config, looking through
+// the configs wrapping it, or the zero Value if it has none.
+func toolConfigField(tc tools.ToolConfig, name string) reflect.Value {
+	switch c := tc.(type) {
+	case tools.AliasConfig:
+		return toolConfigField(c.ToolConfig, name)
+	case tools.SerializeConfig:
+		return toolConfigField(c.ToolConfig, name)
+	case tools.TransformConfig:
+		return toolConfigField(c.ToolConfig, name)
+	case tools.SpillConfig:
+		if name == "Spill" {
+			return reflect.ValueOf(c.Spill)
+		}
+		return toolConfigField(c.ToolConfig, name)
+	case tools.ScheduleConfig:
+		return toolConfigField(c.ToolConfig, name)
+	case tools.CircuitBreakerConfig:
+		return toolConfigField(c.ToolConfig, name)
+	case tools.UsageMetadataConfig:
+		return toolConfigField(c.ToolConfig, name)
+	case tools.TagsConfig:
+		return toolConfigField(c.ToolConfig, name)
+	case tools.ExamplesConfig:
+		return toolConfigField(c.ToolConfig, name)
+	case tools.IdempotencyConfig:
+		return toolConfigField(c.ToolConfig, name)
+	case tools.PollableConfig:
+		return toolConfigField(c.ToolConfig, name)
+	case tools.OutputSchemaConfig:
+		return toolConfigField(c.ToolConfig, name)
+	case tools.ResultTimezoneConfig:
+		return toolConfigField(c.ToolConfig, name)
+	case tools.PreInvokeWebhookConfig:
+		return toolConfigField(c.ToolConfig, name)
+	case tools.AllowUnauthenticatedConfig:
+		if name == "AllowUnauthenticated" {
+			return reflect.ValueOf(c.AllowUnauthenticated)
+		}
+		return toolConfigField(c.ToolConfig, name)
+	}
+	v := reflect.ValueOf(tc)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}
+	}
+	return v.FieldByName(name)
+}
+
+// withCapabilities exposes the capabilities of src, if it describes them, in
+// the manifests of t, the tool of config tc. Tools with array parameters are
+// rejected if src cannot bind arrays, rather than failing when invoked.
+func withCapabilities(t tools.Tool, tc tools.ToolConfig, src sources.Source) (tools.Tool, error) {
+	cs, ok := src.(sources.CapabilitiesSource)
+	if !ok {
+		return t, nil
+	}
+	caps := cs.Capabilities()
+	if e, ok := t.(tools.ArrayParamsExpander); ok && e.ExpandsArrayParams() {
+		caps.SupportsArrayParams = true
+	}
+	if p := toolArrayParam(tc); p != "" && !caps.SupportsArrayParams {
+		return nil, fmt.Errorf("parameter %q is an array, but sources of kind %q do not support array parameters", p, src.SourceKind())
+	}
+	return tools.CapabilitiesTool{Tool: t, Capabilities: caps}, nil
+}
+
+// withDescriptionTemplate resolves the description templates of t, the tool of
+// a config bound to the source name of config sc, if any. Placeholders are
+// resolved from the name, the kind and the connection details of the source,
+// so that its credentials are never interpolated. See connectionDetails.
+func withDescriptionTemplate(t tools.Tool, name string, sc sources.SourceConfig) (tools.Tool, error) {
+	description, mcpDescription := t.Manifest().Description, t.McpManifest().Description
+	if !tools.IsDescriptionTemplate(description) && !tools.IsDescriptionTemplate(mcpDescription) {
+		return t, nil
+	}
+	data := map[string]any{}
+	if sc != nil {
+		source := connectionDetails(sc)
+		source["name"] = name
+		source["kind"] = sc.SourceConfigKind()
+		data["source"] = source
+	}
+	var err error
+	if description, err = tools.ResolveDescription(description, data); err != nil {
+		return nil, err
+	}
+	if mcpDescription, err = tools.ResolveDescription(mcpDescription, data); err != nil {
+		return nil, err
+	}
+	return tools.DescriptionTool{Tool: t, Description: description, McpDescription: mcpDescription}, nil
+}
+
+// schemaCache lazily generates source schemas and keeps them in a state store
+// for a TTL, keyed by the hash of the source config so that a schema is not
+// reused once the config of its source changes.
+type schemaCache struct {
+	store state.Store
+	ttl   time.Duration
+	// mu ensures that a replica generates a schema at most once at a time.
+	mu sync.Mutex
+}
+
+func newSchemaCache(store state.Store, ttl time.Duration) *schemaCache {
+	return &schemaCache{store: store, ttl: ttl}
+}
+
+// get returns the schema of the named source as JSON, generating it if it is
+// not cached or has expired. hash is the hash of the source config.
+func (c *schemaCache) get(ctx context.Context, name, hash string, src sources.SchemaSource) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := fmt.Sprintf("schema:%s:%s", name, hash)
+	if b, ok, err := c.store.Get(ctx, key); err == nil && ok {
+		return string(b), nil
+	}
+	schema, err := src.Schema(ctx)
+	if err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(schema)
+	if err != nil {
+		return "", fmt.Errorf("unable to marshal schema: %w", err)
+	}
+	// a schema that is not cached is generated again on the next read
+	_ = c.store.Set(ctx, key, b, c.ttl)
+	return string(b), nil
+}
+
+// SourceHashes returns the hash of the config of each source, keyed by source
+// name.
+func SourceHashes(cfgs SourceConfigs) map[string]string {
+	hashes := make(map[string]string, len(cfgs))
+	for name, sc := range cfgs {
+		b, err := json.Marshal(sc)
+		if err != nil {
+			b = []byte(fmt.Sprintf("%#v", sc))
+		}
+		sum := sha256.Sum256(b)
+		hashes[name] = hex.EncodeToString(sum[:])
+	}
+	return hashes
+}
+
+// hasSchemaSource reports whether any source is able to describe its schema.
+func (r *ResourceManager) hasSchemaSource() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, s := range r.sources {
+		if _, ok := s.(sources.SchemaSource); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// mcpResources returns the resources offered over MCP for a toolset: the
+// schema of every schema-capable source used by a tool in the toolset, and the
+// manifest of every tool in the toolset. Reading a source schema requires the
+// same auth as every tool that acts on that source.
+func (r *ResourceManager) mcpResources(toolset tools.Toolset) []mcp.ResourceEntry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	toolNames := slices.Sorted(maps.Keys(toolset.Manifest.ToolsManifest))
+
+	// tools acting on each source, across all toolsets
+	sourceTools := make(map[string][]tools.Tool)
+	for _, name := range slices.Sorted(maps.Keys(r.toolSources)) {
+		if t, ok := r.tools[name]; ok {
+			sourceTools[r.toolSources[name]] = append(sourceTools[r.toolSources[name]], t)
+		}
+	}
+
+	resources := make([]mcp.ResourceEntry, 0)
+	seen := make(map[string]bool)
+	for _, toolName := range toolNames {
+		sourceName, ok := r.toolSources[toolName]
+		if !ok || seen[sourceName] {
+			continue
+		}
+		seen[sourceName] = true
+		src, ok := r.sources[sourceName].(sources.SchemaSource)
+		if !ok {
+			continue
+		}
+		schemas := r.schemas
+		hash := r.sourceHashes[sourceName]
+		resources = append(resources, mcp.ResourceEntry{
+			Resource: mcputil.Resource{
+				URI:         fmt.Sprintf("toolbox://sources/%s/schema", sourceName),
+				Name:        fmt.Sprintf("%s schema", sourceName),
+				Description: fmt.Sprintf("Tables and columns of the %q source.", sourceName),
+				MimeType:    "application/json",
+			},
+			Tools: sourceTools[sourceName],
+			Read: func(ctx context.Context) (string, error) {
+				return schemas.get(ctx, sourceName, hash, src)
+			},
+		})
+	}
+
+	for _, toolName := range toolNames {
+		tool, ok := r.tools[toolName]
+		if !ok {
+			continue
+		}
+		manifest := toolset.Manifest.ToolsManifest[toolName]
+		resources = append(resources, mcp.ResourceEntry{
+			Resource: mcputil.Resource{
+				URI:         fmt.Sprintf("toolbox://tools/%s/manifest", toolName),
+				Name:        fmt.Sprintf("%s manifest", toolName),
+				Description: fmt.Sprintf("Manifest of the %q tool.", toolName),
+				MimeType:    "application/json",
+			},
+			Tools: []tools.Tool{tool},
+			Read: func(ctx context.Context) (string, error) {
+				b, err := json.Marshal(manifest)
+				if err != nil {
+					return "", fmt.Errorf("unable to marshal manifest: %w", err)
+				}
+				return string(b), nil
+			},
+		})
+	}
+	return resources
+}
@@ -0,0 +1,755 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/server/mcp"
+	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
+	mcputil "github.com/googleapis/genai-toolbox/internal/server/mcp/util"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/bigquery"
+	"github.com/googleapis/genai-toolbox/internal/sources/postgres"
+	"github.com/googleapis/genai-toolbox/internal/state"
+	"github.com/googleapis/genai-toolbox/internal/telemetry"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+var _ sources.SchemaSource = &fakeSchemaSource{}
+
+// fakeSchemaSource returns a canned schema and counts how often it was asked.
+type fakeSchemaSource struct {
+	calls atomic.Int64
+}
+
+func (s *fakeSchemaSource) SourceKind() string {
+	return "fake-schema"
+}
+
+func (s *fakeSchemaSource) Schema(context.Context) (*sources.Schema, error) {
+	s.calls.Add(1)
+	return &sources.Schema{
+		Tables: []sources.TableSchema{
+			{
+				Name: "flights",
+				Columns: []sources.ColumnSchema{
+					{Name: "id", Type: "integer"},
+					{Name: "airline", Type: "text", Nullable: true},
+				},
+			},
+		},
+	}, nil
+}
+
+// fakeSource is a source that cannot describe its schema.
+type fakeSource struct{}
+
+func (fakeSource) SourceKind() string {
+	return "fake"
+}
+
+// mockToolConfig is a tool config acting on a source.
+type mockToolConfig struct {
+	Source     string
+	Parameters tools.Parameters
+}
+
+func (mockToolConfig) ToolConfigKind() string {
+	return "mock"
+}
+
+func (mockToolConfig) Initialize(map[string]sources.Source) (tools.Tool, error) {
+	return tool1, nil
+}
+
+const cannedSchema = `{"tables":[{"name":"flights","columns":[{"name":"id","type":"integer","nullable":false},{"name":"airline","type":"text","nullable":true}]}]}`
+
+// setUpResourceServer starts an MCP server with the given sources, and with
+// no_params and some_params acting on "my-db", and unauthorized_tool acting
+// on "secure-db".
+func setUpResourceServer(t *testing.T, srcs map[string]sources.Source) *httptest.Server {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2, tool4})
+	resourceManager := NewResourceManager(srcs, nil, toolsMap, toolsets)
+	resourceManager.SetToolSources(map[string]string{
+		tool1.Name: "my-db",
+		tool2.Name: "my-db",
+		tool4.Name: "secure-db",
+	})
+
+	s := &Server{
+		version:         fakeVersionString,
+		logger:          testLogger,
+		instrumentation: instrumentation,
+		sseManager:      newSseManager(ctx),
+		ResourceMgr:     resourceManager,
+	}
+	r, err := mcpRouter(s)
+	if err != nil {
+		t.Fatalf("unable to initialize mcp router: %s", err)
+	}
+	ts := runServer(r, false)
+	t.Cleanup(func() {
+		ts.Close()
+		cancel()
+	})
+	return ts
+}
+
+// runMcpMethod sends a JSON-RPC request and returns the status code and the
+// decoded response.
+func runMcpMethod(t *testing.T, ts *httptest.Server, path, method string, params any) (int, map[string]any) {
+	t.Helper()
+	reqBody := map[string]any{
+		"jsonrpc": jsonrpcVersion,
+		"id":      method,
+		"method":  method,
+	}
+	if params != nil {
+		reqBody["params"] = params
+	}
+	reqMarshal, err := json.Marshal(reqBody)
+	if err != nil {
+		t.Fatalf("unexpected error during marshaling of body: %s", err)
+	}
+	header := map[string]string{"MCP-Protocol-Version": mcp.LATEST_PROTOCOL_VERSION}
+	resp, body, err := runRequest(ts, http.MethodPost, path, bytes.NewBuffer(reqMarshal), header)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("unexpected error unmarshalling body %q: %s", body, err)
+	}
+	return resp.StatusCode, got
+}
+
+func TestMcpResourcesCapability(t *testing.T) {
+	tcs := []struct {
+		name string
+		srcs map[string]sources.Source
+		want bool
+	}{
+		{
+			name: "schema source",
+			srcs: map[string]sources.Source{"my-db": &fakeSchemaSource{}, "secure-db": fakeSource{}},
+			want: true,
+		},
+		{
+			name: "no schema source",
+			srcs: map[string]sources.Source{"my-db": fakeSource{}, "secure-db": fakeSource{}},
+			want: false,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := setUpResourceServer(t, tc.srcs)
+			_, got := runMcpMethod(t, ts, "/", "initialize", map[string]any{"protocolVersion": mcp.LATEST_PROTOCOL_VERSION})
+			capabilities := got["result"].(map[string]any)["capabilities"].(map[string]any)
+			if _, ok := capabilities["resources"]; ok != tc.want {
+				t.Fatalf("unexpected resources capability: got %v, want %v", capabilities, tc.want)
+			}
+
+			_, got = runMcpMethod(t, ts, "/", "resources/list", nil)
+			_, isErr := got["error"]
+			if isErr == tc.want {
+				t.Fatalf("unexpected resources/list response: %v", got)
+			}
+			if !tc.want {
+				if code := got["error"].(map[string]any)["code"]; code != float64(jsonrpc.METHOD_NOT_FOUND) {
+					t.Fatalf("unexpected error code: got %v, want %d", code, jsonrpc.METHOD_NOT_FOUND)
+				}
+			}
+		})
+	}
+}
+
+func TestMcpResourcesList(t *testing.T) {
+	srcs := map[string]sources.Source{"my-db": &fakeSchemaSource{}, "secure-db": fakeSource{}}
+	ts := setUpResourceServer(t, srcs)
+
+	tcs := []struct {
+		name string
+		path string
+		want []string
+	}{
+		{
+			name: "default toolset",
+			path: "/",
+			want: []string{
+				"toolbox://sources/my-db/schema",
+				"toolbox://tools/no_params/manifest",
+				"toolbox://tools/some_params/manifest",
+				"toolbox://tools/unauthorized_tool/manifest",
+			},
+		},
+		{
+			name: "toolset with a single tool",
+			path: "/tool1_only",
+			want: []string{
+				"toolbox://sources/my-db/schema",
+				"toolbox://tools/no_params/manifest",
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, got := runMcpMethod(t, ts, tc.path, "resources/list", nil)
+			result, ok := got["result"].(map[string]any)
+			if !ok {
+				t.Fatalf("unexpected response: %v", got)
+			}
+			var uris []string
+			for _, r := range result["resources"].([]any) {
+				uris = append(uris, r.(map[string]any)["uri"].(string))
+			}
+			if diff := cmp.Diff(tc.want, uris); diff != "" {
+				t.Fatalf("unexpected resources (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMcpResourcesRead(t *testing.T) {
+	src := &fakeSchemaSource{}
+	ts := setUpResourceServer(t, map[string]sources.Source{"my-db": src, "secure-db": fakeSource{}})
+
+	manifest, err := json.Marshal(tool2.Manifest())
+	if err != nil {
+		t.Fatalf("unable to marshal manifest: %s", err)
+	}
+
+	tcs := []struct {
+		name string
+		uri  string
+		want string
+	}{
+		{
+			name: "source schema",
+			uri:  "toolbox://sources/my-db/schema",
+			want: cannedSchema,
+		},
+		{
+			name: "cached source schema",
+			uri:  "toolbox://sources/my-db/schema",
+			want: cannedSchema,
+		},
+		{
+			name: "tool manifest",
+			uri:  "toolbox://tools/some_params/manifest",
+			want: string(manifest),
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			status, got := runMcpMethod(t, ts, "/", "resources/read", map[string]any{"uri": tc.uri})
+			if status != http.StatusOK {
+				t.Fatalf("unexpected status: got %d, response %v", status, got)
+			}
+			want := map[string]any{
+				"contents": []any{
+					map[string]any{"uri": tc.uri, "mimeType": "application/json", "text": tc.want},
+				},
+			}
+			if diff := cmp.Diff(want, got["result"]); diff != "" {
+				t.Fatalf("unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+	if calls := src.calls.Load(); calls != 1 {
+		t.Fatalf("expected schema to be generated once, got %d", calls)
+	}
+}
+
+func TestMcpResourcesReadFailure(t *testing.T) {
+	srcs := map[string]sources.Source{"my-db": &fakeSchemaSource{}, "secure-db": &fakeSchemaSource{}}
+	ts := setUpResourceServer(t, srcs)
+
+	tcs := []struct {
+		name       string
+		uri        string
+		wantStatus int
+		wantCode   int
+		wantErr    string
+	}{
+		{
+			name:       "source used by an unauthorized tool",
+			uri:        "toolbox://sources/secure-db/schema",
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   jsonrpc.INVALID_REQUEST,
+			wantErr:    "unauthorized resource read",
+		},
+		{
+			name:       "manifest of an unauthorized tool",
+			uri:        "toolbox://tools/unauthorized_tool/manifest",
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   jsonrpc.INVALID_REQUEST,
+			wantErr:    "unauthorized resource read",
+		},
+		{
+			name:       "unknown resource",
+			uri:        "toolbox://sources/other-db/schema",
+			wantStatus: http.StatusOK,
+			wantCode:   mcputil.RESOURCE_NOT_FOUND,
+			wantErr:    "resource not found",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			status, got := runMcpMethod(t, ts, "/", "resources/read", map[string]any{"uri": tc.uri})
+			if status != tc.wantStatus {
+				t.Fatalf("unexpected status: got %d, want %d", status, tc.wantStatus)
+			}
+			rpcErr, ok := got["error"].(map[string]any)
+			if !ok {
+				t.Fatalf("expected error response, got %v", got)
+			}
+			if code := rpcErr["code"]; code != float64(tc.wantCode) {
+				t.Fatalf("unexpected error code: got %v, want %d", code, tc.wantCode)
+			}
+			if msg := rpcErr["message"].(string); !strings.Contains(msg, tc.wantErr) {
+				t.Fatalf("unexpected error message: got %q, want to contain %q", msg, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestSchemaCacheShared(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	newStore := func() state.Store {
+		s, err := state.NewRedis(state.RedisConfig{Address: mr.Addr()})
+		if err != nil {
+			t.Fatalf("unable to create Redis store: %s", err)
+		}
+		t.Cleanup(func() { s.Close() })
+		return s
+	}
+	// caches of two replicas
+	a := newSchemaCache(newStore(), schemaCacheTTL)
+	b := newSchemaCache(newStore(), schemaCacheTTL)
+	src := &fakeSchemaSource{}
+
+	for _, c := range []*schemaCache{a, b} {
+		got, err := c.get(ctx, "my-db", "hash1", src)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if got != cannedSchema {
+			t.Fatalf("unexpected schema: got %s, want %s", got, cannedSchema)
+		}
+	}
+	if calls := src.calls.Load(); calls != 1 {
+		t.Fatalf("expected schema to be generated once, got %d", calls)
+	}
+
+	// a changed source config does not reuse the cached schema
+	if _, err := b.get(ctx, "my-db", "hash2", src); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if calls := src.calls.Load(); calls != 2 {
+		t.Fatalf("expected schema to be generated again, got %d calls", calls)
+	}
+}
+
+func TestSourceHashes(t *testing.T) {
+	cfg := postgres.Config{Name: "orders", Kind: postgres.SourceKind, Host: "10.0.0.1", Port: "5432", Database: "orders"}
+	moved := cfg
+	moved.Host = "10.0.0.2"
+
+	got := SourceHashes(SourceConfigs{"orders": cfg, "same": cfg, "moved": moved})
+	if len(got["orders"]) != 64 {
+		t.Fatalf("expected a sha256 hash, got %q", got["orders"])
+	}
+	if got["orders"] != got["same"] {
+		t.Errorf("expected identical configs to have the same hash")
+	}
+	if got["orders"] == got["moved"] {
+		t.Errorf("expected different configs to have different hashes")
+	}
+}
+
+func TestToolSources(t *testing.T) {
+	cfgs := ToolConfigs{
+		"my-tool": tools.AliasConfig{
+			ToolConfig: mockToolConfig{Source: "my-db"},
+			Aliases:    []string{"old-tool"},
+		},
+		"serialized-tool": tools.SerializeConfig{ToolConfig: mockToolConfig{Source: "other-db"}},
+		"scheduled-tool":  tools.ScheduleConfig{ToolConfig: mockToolConfig{Source: "my-db"}},
+		"examples-tool":   tools.ExamplesConfig{ToolConfig: mockToolConfig{Source: "other-db"}},
+		"no-source-tool":  mockToolConfig{},
+	}
+	want := map[string]string{
+		"my-tool":         "my-db",
+		"old-tool":        "my-db",
+		"serialized-tool": "other-db",
+		"scheduled-tool":  "my-db",
+		"examples-tool":   "other-db",
+	}
+	if diff := cmp.Diff(want, ToolSources(cfgs)); diff != "" {
+		t.Fatalf("unexpected tool sources (-want +got):\n%s", diff)
+	}
+}
+
+func TestSpillsToFile(t *testing.T) {
+	spill, err := tools.NewSpill(tools.SpillSpec{MaxRows: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	cfgs := ToolConfigs{"my-tool": mockToolConfig{Source: "my-db"}}
+	if spillsToFile(cfgs) {
+		t.Fatalf("expected no tool to spill to files")
+	}
+	cfgs["export-tool"] = tools.AliasConfig{
+		ToolConfig: tools.SpillConfig{ToolConfig: mockToolConfig{Source: "my-db"}, Spill: spill},
+		Aliases:    []string{"old-export-tool"},
+	}
+	if !spillsToFile(cfgs) {
+		t.Fatalf("expected export-tool to spill to files")
+	}
+}
+
+// noArraysSource is a source that cannot bind arrays.
+type noArraysSource struct{}
+
+func (noArraysSource) SourceKind() string {
+	return "no-arrays"
+}
+
+func (noArraysSource) Capabilities() sources.Capabilities {
+	return sources.Capabilities{SupportsPreparedStatements: true}
+}
+
+// expandingTool is a MockTool that expands its array parameters itself.
+type expandingTool struct {
+	MockTool
+}
+
+func (expandingTool) ExpandsArrayParams() bool {
+	return true
+}
+
+func TestWithCapabilities(t *testing.T) {
+	arrayCfg := tools.TagsConfig{ToolConfig: mockToolConfig{
+		Source:     "my-db",
+		Parameters: tools.Parameters{tools.NewArrayParameter("ids", "some ids", tools.NewIntParameter("id", "an id"))},
+	}}
+
+	t.Run("source without capabilities", func(t *testing.T) {
+		got, err := withCapabilities(tool1, arrayCfg, &fakeSchemaSource{})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if got.Manifest().Capabilities != nil {
+			t.Fatalf("unexpected capabilities: %+v", got.Manifest().Capabilities)
+		}
+	})
+
+	t.Run("source with capabilities", func(t *testing.T) {
+		got, err := withCapabilities(tool1, arrayCfg, &postgres.Source{})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		want := sources.Capabilities{
+			SupportsPreparedStatements: true,
+			SupportsArrayParams:        true,
+			SupportsTransactions:       true,
+			MaxIdentifierLength:        63,
+		}
+		if diff := cmp.Diff(&want, got.Manifest().Capabilities); diff != "" {
+			t.Fatalf("unexpected manifest capabilities (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(want, got.McpManifest().Metadata["toolbox/capabilities"]); diff != "" {
+			t.Fatalf("unexpected mcp capabilities (-want +got):\n%s", diff)
+		}
+		if _, ok := tools.As[MockTool](got); !ok {
+			t.Fatalf("expected the tool to be wrapped, got %T", got)
+		}
+	})
+
+	t.Run("array parameter on source without arrays", func(t *testing.T) {
+		_, err := withCapabilities(tool1, arrayCfg, noArraysSource{})
+		if err == nil || !strings.Contains(err.Error(), `parameter "ids" is an array`) {
+			t.Fatalf("got error %v, want one for the array parameter", err)
+		}
+		got, err := withCapabilities(tool1, mockToolConfig{Source: "my-db"}, noArraysSource{})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if got.Manifest().Capabilities.SupportsArrayParams {
+			t.Fatalf("expected no support for array parameters")
+		}
+	})
+
+	t.Run("tool expanding array parameters", func(t *testing.T) {
+		got, err := withCapabilities(expandingTool{MockTool: tool1}, arrayCfg, noArraysSource{})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !got.Manifest().Capabilities.SupportsArrayParams {
+			t.Fatalf("expected support for array parameters")
+		}
+	})
+}
+
+func TestWithDefaultSchedule(t *testing.T) {
+	defaultSchedule, err := tools.NewSchedule(tools.ScheduleSpec{Windows: []tools.ScheduleWindow{{Days: "Sat,Sun"}}})
+	if err != nil {
+		t.Fatalf("unable to create schedule: %s", err)
+	}
+	ownSchedule, err := tools.NewSchedule(tools.ScheduleSpec{Windows: []tools.ScheduleWindow{{Hours: "18:00-06:00"}}})
+	if err != nil {
+		t.Fatalf("unable to create schedule: %s", err)
+	}
+
+	t.Run("tool without schedule", func(t *testing.T) {
+		got, ok := withDefaultSchedule(mockToolConfig{}, defaultSchedule).(tools.ScheduleConfig)
+		if !ok || got.Schedule != defaultSchedule {
+			t.Fatalf("expected the default schedule, got %#v", got)
+		}
+	})
+	t.Run("tool with its own schedule", func(t *testing.T) {
+		got, ok := withDefaultSchedule(tools.ScheduleConfig{ToolConfig: mockToolConfig{}, Schedule: ownSchedule}, defaultSchedule).(tools.ScheduleConfig)
+		if !ok || got.Schedule != ownSchedule {
+			t.Fatalf("expected the schedule of the tool, got %#v", got)
+		}
+	})
+	t.Run("aliased tool", func(t *testing.T) {
+		got, ok := withDefaultSchedule(tools.AliasConfig{ToolConfig: mockToolConfig{}, Aliases: []string{"old"}}, defaultSchedule).(tools.AliasConfig)
+		if !ok {
+			t.Fatalf("expected the alias config to stay outermost, got %T", got)
+		}
+		if inner, ok := got.ToolConfig.(tools.ScheduleConfig); !ok || inner.Schedule != defaultSchedule {
+			t.Fatalf("expected the default schedule, got %#v", got.ToolConfig)
+		}
+	})
+}
+
+func TestWithUsageMetadata(t *testing.T) {
+	t.Run("tool without setting", func(t *testing.T) {
+		got, ok := withUsageMetadata(mockToolConfig{}).(tools.UsageMetadataConfig)
+		if !ok || !got.Include {
+			t.Fatalf("expected usage metadata to be included, got %#v", got)
+		}
+	})
+	t.Run("tool opting out", func(t *testing.T) {
+		got, ok := withUsageMetadata(tools.UsageMetadataConfig{ToolConfig: mockToolConfig{}, Include: false}).(tools.UsageMetadataConfig)
+		if !ok || got.Include {
+			t.Fatalf("expected the setting of the tool, got %#v", got)
+		}
+	})
+	t.Run("serialized tool", func(t *testing.T) {
+		got, ok := withUsageMetadata(tools.SerializeConfig{ToolConfig: mockToolConfig{}}).(tools.SerializeConfig)
+		if !ok {
+			t.Fatalf("expected the serialize config to stay outermost, got %T", got)
+		}
+		if inner, ok := got.ToolConfig.(tools.UsageMetadataConfig); !ok || !inner.Include {
+			t.Fatalf("expected usage metadata to be included, got %#v", got.ToolConfig)
+		}
+	})
+}
+
+// mockRowsToolConfig is a tool config of a kind with a registered output
+// schema.
+type mockRowsToolConfig struct {
+	mockToolConfig
+}
+
+func (mockRowsToolConfig) ToolConfigKind() string {
+	return "mock-rows"
+}
+
+func TestWithDefaultOutputSchema(t *testing.T) {
+	tools.RegisterOutputSchema("mock-rows", tools.RowsOutputSchema)
+	ownSchema := map[string]any{"type": "object"}
+
+	t.Run("tool of a kind without schema", func(t *testing.T) {
+		if got, ok := withDefaultOutputSchema(mockToolConfig{}).(mockToolConfig); !ok {
+			t.Fatalf("expected the config to be unchanged, got %#v", got)
+		}
+	})
+	t.Run("tool of a kind with schema", func(t *testing.T) {
+		got, ok := withDefaultOutputSchema(mockRowsToolConfig{}).(tools.OutputSchemaConfig)
+		if !ok || !cmp.Equal(got.Schema, tools.RowsOutputSchema()) {
+			t.Fatalf("expected the schema of the kind, got %#v", got)
+		}
+	})
+	t.Run("tool with its own schema", func(t *testing.T) {
+		got, ok := withDefaultOutputSchema(tools.OutputSchemaConfig{ToolConfig: mockRowsToolConfig{}, Schema: ownSchema}).(tools.OutputSchemaConfig)
+		if !ok || !cmp.Equal(got.Schema, ownSchema) {
+			t.Fatalf("expected the schema of the tool, got %#v", got)
+		}
+	})
+	t.Run("transformed tool", func(t *testing.T) {
+		if got, ok := withDefaultOutputSchema(tools.TransformConfig{ToolConfig: mockRowsToolConfig{}}).(tools.TransformConfig); !ok {
+			t.Fatalf("expected the config to be unchanged, got %#v", got)
+		}
+	})
+	t.Run("aliased tool", func(t *testing.T) {
+		got, ok := withDefaultOutputSchema(tools.AliasConfig{ToolConfig: mockRowsToolConfig{}, Aliases: []string{"old"}}).(tools.AliasConfig)
+		if !ok {
+			t.Fatalf("expected the alias config to stay outermost, got %T", got)
+		}
+		if _, ok := got.ToolConfig.(tools.OutputSchemaConfig); !ok {
+			t.Fatalf("expected the schema of the kind, got %#v", got.ToolConfig)
+		}
+	})
+}
+
+func TestWithDefaultResultTimezone(t *testing.T) {
+	tz, err := tools.NewResultTimezone("America/New_York")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	own, err := tools.NewResultTimezone("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	t.Run("tool without timezone", func(t *testing.T) {
+		got, ok := withDefaultResultTimezone(mockToolConfig{}, tz).(tools.ResultTimezoneConfig)
+		if !ok || got.Timezone != tz {
+			t.Fatalf("expected the default timezone, got %#v", got)
+		}
+	})
+	t.Run("tool with its own timezone", func(t *testing.T) {
+		got, ok := withDefaultResultTimezone(tools.ResultTimezoneConfig{ToolConfig: mockToolConfig{}, Timezone: own}, tz).(tools.ResultTimezoneConfig)
+		if !ok || got.Timezone != own {
+			t.Fatalf("expected the timezone of the tool, got %#v", got)
+		}
+	})
+	t.Run("aliased tool", func(t *testing.T) {
+		got, ok := withDefaultResultTimezone(tools.AliasConfig{ToolConfig: mockToolConfig{}, Aliases: []string{"old"}}, tz).(tools.AliasConfig)
+		if !ok {
+			t.Fatalf("expected the alias config to stay outermost, got %T", got)
+		}
+		if _, ok := got.ToolConfig.(tools.ResultTimezoneConfig); !ok {
+			t.Fatalf("expected the default timezone, got %#v", got.ToolConfig)
+		}
+	})
+}
+
+func TestWithDescriptionTemplate(t *testing.T) {
+	pg := postgres.Config{Name: "my-pg", Kind: postgres.SourceKind, Host: "db.internal", Port: "5432", User: "reader", Password: "hunter2", Database: "orders"}
+	bq := bigquery.Config{Name: "my-bq", Kind: bigquery.SourceKind, Project: "my-project", Location: "EU"}
+	tcs := []struct {
+		desc        string
+		source      sources.SourceConfig
+		description string
+		want        string
+		err         string
+	}{
+		{
+			desc:        "postgres fields",
+			source:      pg,
+			description: "Query the {{.source.database}} orders schema on {{.source.host}}:{{.source.port}} as {{.source.user}}.",
+			want:        "Query the orders orders schema on db.internal:5432 as reader.",
+		},
+		{
+			desc:        "bigquery fields",
+			source:      bq,
+			description: "Query {{.source.name}}, a {{.source.kind}} source of project {{.source.project}} in {{.source.location}}.",
+			want:        "Query my-source, a bigquery source of project my-project in EU.",
+		},
+		{
+			desc:        "lazy source",
+			source:      sources.LazyConfig{SourceConfig: pg},
+			description: "Query {{.source.database}}.",
+			want:        "Query orders.",
+		},
+		{
+			desc:        "without template",
+			source:      pg,
+			description: "Query the orders.",
+			want:        "Query the orders.",
+		},
+		{
+			desc:        "postgres secret field",
+			source:      pg,
+			description: "Query {{.source.database}} with {{.source.password}}.",
+			err:         `map has no entry for key "password"`,
+		},
+		{
+			desc:        "bigquery unknown field",
+			source:      bq,
+			description: "Query the {{.source.dataset}} dataset.",
+			err:         `map has no entry for key "dataset"`,
+		},
+		{
+			desc:        "unset field",
+			source:      bq,
+			description: "Query {{.source.region}}.",
+			err:         `map has no entry for key "region"`,
+		},
+		{
+			desc:        "no source",
+			description: "Query {{.source.database}}.",
+			err:         `map has no entry for key "source"`,
+		},
+		{
+			desc:        "invalid template",
+			source:      pg,
+			description: "Query {{.source.database}.",
+			err:         "invalid description template",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			name := ""
+			if tc.source != nil {
+				name = "my-source"
+			}
+			got, err := withDescriptionTemplate(MockTool{Name: "my-tool", Description: tc.description}, name, tc.source)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("unexpected error: got %v, want substring %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got.Manifest().Description != tc.want {
+				t.Fatalf("unexpected description: got %q, want %q", got.Manifest().Description, tc.want)
+			}
+			if got.McpManifest().Description != tc.want {
+				t.Fatalf("unexpected mcp description: got %q, want %q", got.McpManifest().Description, tc.want)
+			}
+		})
+	}
+}
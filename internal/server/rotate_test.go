@@ -0,0 +1,413 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/telemetry"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+var errFakeAuth = errors.New("password authentication failed")
+
+// fakePool is a connection pool opened with a set of credentials.
+type fakePool struct {
+	creds    sources.Credentials
+	inFlight sync.WaitGroup
+	closed   atomic.Bool
+}
+
+var _ sources.RotatableSource = &fakeRotatableSource{}
+
+// fakeRotatableSource accepts a single password. Rotating its credentials
+// replaces its pool, and the old pool is closed once its in-flight queries
+// are done.
+type fakeRotatableSource struct {
+	accepted string
+	rotator  *sources.CredentialRotator
+	mu       sync.Mutex
+	pool     *fakePool
+	// drained receives each pool after it was closed
+	drained chan *fakePool
+}
+
+func newFakeRotatableSource(t *testing.T, provider sources.CredentialProvider, accepted string) *fakeRotatableSource {
+	t.Helper()
+	s := &fakeRotatableSource{accepted: accepted, drained: make(chan *fakePool, 1)}
+	validate := func(_ context.Context, creds sources.Credentials) error {
+		if creds.Password != s.accepted {
+			return errFakeAuth
+		}
+		return nil
+	}
+	drain := func() {
+		s.mu.Lock()
+		old := s.pool
+		s.pool = &fakePool{creds: s.rotator.Current()}
+		s.mu.Unlock()
+		go func() {
+			old.inFlight.Wait()
+			old.closed.Store(true)
+			s.drained <- old
+		}()
+	}
+	rotator, err := sources.NewCredentialRotator(provider, validate, drain)
+	if err != nil {
+		t.Fatalf("unable to create rotator: %s", err)
+	}
+	s.rotator = rotator
+	s.pool = &fakePool{creds: rotator.Current()}
+	return s
+}
+
+func (s *fakeRotatableSource) SourceKind() string {
+	return "fake-rotatable"
+}
+
+func (s *fakeRotatableSource) RotateCredentials(ctx context.Context, creds *sources.Credentials) error {
+	return s.rotator.Rotate(ctx, creds)
+}
+
+func (s *fakeRotatableSource) IsAuthError(err error) bool {
+	return errors.Is(err, errFakeAuth)
+}
+
+// acquire starts a query on the active pool. The returned function ends it.
+func (s *fakeRotatableSource) acquire() (*fakePool, func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := s.pool
+	p.inFlight.Add(1)
+	return p, p.inFlight.Done
+}
+
+// fakeAuthService accepts the token "valid", for the subject "admin", and the
+// token "user", for the subject "user", in the `<name>_token` header.
+type fakeAuthService struct {
+	name string
+}
+
+func (a fakeAuthService) AuthServiceKind() string {
+	return "fake"
+}
+
+func (a fakeAuthService) GetName() string {
+	return a.name
+}
+
+func (a fakeAuthService) GetClaimsFromHeader(_ context.Context, h http.Header) (map[string]any, error) {
+	token := h.Get(a.name + "_token")
+	switch token {
+	case "":
+		return nil, nil
+	case "valid":
+		return map[string]any{"sub": "admin"}, nil
+	case "user":
+		return map[string]any{"sub": "user", "email": "user@example.com", "email_verified": true}, nil
+	default:
+		return nil, fmt.Errorf("invalid token")
+	}
+}
+
+// setUpRotateServer starts an API server with the given sources and an
+// authService named "admin".
+func setUpRotateServer(t *testing.T, srcs map[string]sources.Source, adminAuthService string) *httptest.Server {
+	t.Helper()
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+	authServices := map[string]auth.AuthService{"admin": fakeAuthService{name: "admin"}}
+
+	s := &Server{
+		version:          fakeVersionString,
+		logger:           testLogger,
+		instrumentation:  instrumentation,
+		adminAuthService: adminAuthService,
+		adminSubjects:    []string{"admin"},
+		ResourceMgr:      NewResourceManager(srcs, authServices, nil, nil),
+	}
+	r, err := apiRouter(s)
+	if err != nil {
+		t.Fatalf("unable to initialize api router: %s", err)
+	}
+	ts := runServer(r, false)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+var adminHeader = map[string]string{"admin_token": "valid"}
+
+func TestRotateSourceCredentials(t *testing.T) {
+	provider, _ := sources.NewCredentialProvider("user", "old", "", "")
+	src := newFakeRotatableSource(t, provider, "new")
+	ts := setUpRotateServer(t, map[string]sources.Source{"my-db": src}, "admin")
+
+	// a query that is still running on the old pool during the rotation
+	oldPool, release := src.acquire()
+
+	body := bytes.NewBufferString(`{"user": "user", "password": "new"}`)
+	resp, respBody, err := runRequest(ts, http.MethodPost, "/source/my-db/rotate", body, adminHeader)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, http.StatusOK, respBody)
+	}
+
+	// new queries use the new pool
+	newPool, releaseNew := src.acquire()
+	releaseNew()
+	if newPool == oldPool {
+		t.Fatalf("expected a new pool after the rotation")
+	}
+	if want := (sources.Credentials{User: "user", Password: "new"}); newPool.creds != want {
+		t.Fatalf("unexpected credentials of new pool: got %+v, want %+v", newPool.creds, want)
+	}
+
+	// the old pool is drained once its in-flight query completes
+	select {
+	case <-src.drained:
+		t.Fatalf("old pool was closed while a query was in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if oldPool.closed.Load() {
+		t.Fatalf("old pool was closed while a query was in flight")
+	}
+	release()
+	select {
+	case p := <-src.drained:
+		if p != oldPool || !p.closed.Load() {
+			t.Fatalf("expected the old pool to be closed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("old pool was not drained")
+	}
+}
+
+func TestRotateSourceCredentialsFailure(t *testing.T) {
+	provider, _ := sources.NewCredentialProvider("user", "old", "", "")
+	src := newFakeRotatableSource(t, provider, "old")
+	ts := setUpRotateServer(t, map[string]sources.Source{"my-db": src, "other-db": fakeSource{}}, "admin")
+	oldPool, release := src.acquire()
+	release()
+
+	tcs := []struct {
+		name   string
+		path   string
+		body   string
+		header map[string]string
+		want   int
+		errStr string
+	}{
+		{
+			name:   "rejected credentials",
+			path:   "/source/my-db/rotate",
+			body:   `{"user": "user", "password": "wrong"}`,
+			header: adminHeader,
+			want:   http.StatusBadRequest,
+			errStr: "new credentials were rejected",
+		},
+		{
+			name:   "missing admin token",
+			path:   "/source/my-db/rotate",
+			body:   `{"user": "user", "password": "old"}`,
+			want:   http.StatusUnauthorized,
+			errStr: "not authorized",
+		},
+		{
+			name:   "invalid admin token",
+			path:   "/source/my-db/rotate",
+			body:   `{"user": "user", "password": "old"}`,
+			header: map[string]string{"admin_token": "invalid"},
+			want:   http.StatusUnauthorized,
+			errStr: "not authorized",
+		},
+		{
+			name:   "admin token of another subject",
+			path:   "/source/my-db/rotate",
+			body:   `{"user": "user", "password": "old"}`,
+			header: map[string]string{"admin_token": "user"},
+			want:   http.StatusForbidden,
+			errStr: "not an admin",
+		},
+		{
+			name:   "unknown source",
+			path:   "/source/missing-db/rotate",
+			body:   `{"user": "user", "password": "old"}`,
+			header: adminHeader,
+			want:   http.StatusNotFound,
+			errStr: "does not exist",
+		},
+		{
+			name:   "source without rotation",
+			path:   "/source/other-db/rotate",
+			body:   `{"user": "user", "password": "old"}`,
+			header: adminHeader,
+			want:   http.StatusBadRequest,
+			errStr: "does not support credential rotation",
+		},
+		{
+			name:   "invalid body",
+			path:   "/source/my-db/rotate",
+			body:   `{"user": `,
+			header: adminHeader,
+			want:   http.StatusBadRequest,
+			errStr: "request body was invalid JSON",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodPost, tc.path, bytes.NewBufferString(tc.body), tc.header)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.want {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, tc.want, body)
+			}
+			if !strings.Contains(string(body), tc.errStr) {
+				t.Fatalf("unexpected error: got %s, want to contain %q", body, tc.errStr)
+			}
+		})
+	}
+
+	// the old pool is still the active one
+	pool, release := src.acquire()
+	release()
+	if pool != oldPool || pool.closed.Load() {
+		t.Fatalf("expected the old pool to stay active after failed rotations")
+	}
+	if want := (sources.Credentials{User: "user", Password: "old"}); src.rotator.Current() != want {
+		t.Fatalf("unexpected credentials: got %+v, want %+v", src.rotator.Current(), want)
+	}
+}
+
+func TestRotateSourceCredentialsDisabled(t *testing.T) {
+	provider, _ := sources.NewCredentialProvider("user", "old", "", "")
+	src := newFakeRotatableSource(t, provider, "new")
+	ts := setUpRotateServer(t, map[string]sources.Source{"my-db": src}, "")
+
+	body := bytes.NewBufferString(`{"user": "user", "password": "new"}`)
+	resp, respBody, err := runRequest(ts, http.MethodPost, "/source/my-db/rotate", body, adminHeader)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, http.StatusNotFound, respBody)
+	}
+	if got := src.rotator.Current().Password; got != "old" {
+		t.Fatalf("expected credentials to be unchanged, got password %q", got)
+	}
+}
+
+func TestRotateSourceCredentialsFromFile(t *testing.T) {
+	passwordFile := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(passwordFile, []byte("old\n"), 0o600); err != nil {
+		t.Fatalf("unable to write password file: %s", err)
+	}
+	provider, err := sources.NewCredentialProvider("user", "", passwordFile, "")
+	if err != nil {
+		t.Fatalf("unable to create provider: %s", err)
+	}
+	src := newFakeRotatableSource(t, provider, "new")
+	ts := setUpRotateServer(t, map[string]sources.Source{"my-db": src}, "admin")
+
+	if err := os.WriteFile(passwordFile, []byte("new\n"), 0o600); err != nil {
+		t.Fatalf("unable to write password file: %s", err)
+	}
+	// an empty body re-reads the credentials from the password file
+	resp, respBody, err := runRequest(ts, http.MethodPost, "/source/my-db/rotate", nil, adminHeader)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, http.StatusOK, respBody)
+	}
+	if got := src.rotator.Current().Password; got != "new" {
+		t.Fatalf("unexpected password: got %q, want %q", got, "new")
+	}
+}
+
+// authFailingTool fails with an auth error while the source uses the wrong
+// password.
+type authFailingTool struct {
+	MockTool
+	src *fakeRotatableSource
+}
+
+func (t authFailingTool) Invoke(context.Context, tools.Invocation) (any, error) {
+	pool, release := t.src.acquire()
+	defer release()
+	if pool.creds.Password != t.src.accepted {
+		return nil, fmt.Errorf("unable to connect: %w", errFakeAuth)
+	}
+	return "ok", nil
+}
+
+func TestCredentialRefreshTool(t *testing.T) {
+	passwordFile := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(passwordFile, []byte("old"), 0o600); err != nil {
+		t.Fatalf("unable to write password file: %s", err)
+	}
+	provider, err := sources.NewCredentialProvider("user", "", passwordFile, "")
+	if err != nil {
+		t.Fatalf("unable to create provider: %s", err)
+	}
+	src := newFakeRotatableSource(t, provider, "new")
+	tool := tools.CredentialRefreshTool{Tool: authFailingTool{MockTool: tool1, src: src}, Source: src}
+
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	ctx := util.WithLogger(context.Background(), testLogger)
+
+	// the secret was rotated in the file, but the source still uses the old one
+	if err := os.WriteFile(passwordFile, []byte("new"), 0o600); err != nil {
+		t.Fatalf("unable to write password file: %s", err)
+	}
+	if _, err := tool.Invoke(ctx, tools.Invocation{}); !errors.Is(err, errFakeAuth) {
+		t.Fatalf("expected an auth error, got %v", err)
+	}
+	// the auth failure made the source re-read the password file
+	got, err := tool.Invoke(ctx, tools.Invocation{})
+	if err != nil {
+		t.Fatalf("unexpected error after reloading credentials: %s", err)
+	}
+	if got != "ok" {
+		t.Fatalf("unexpected result: got %v, want %q", got, "ok")
+	}
+}
@@ -16,10 +16,16 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -28,9 +34,12 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/httplog/v2"
+	"github.com/google/uuid"
 	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/events"
 	"github.com/googleapis/genai-toolbox/internal/log"
 	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/state"
 	"github.com/googleapis/genai-toolbox/internal/telemetry"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/util"
@@ -40,14 +49,68 @@ import (
 
 // Server contains info for running an instance of Toolbox. Should be instantiated with NewServer().
 type Server struct {
-	version         string
-	srv             *http.Server
-	listener        net.Listener
+	version     string
+	srv         *http.Server
+	listenAddrs []string
+	socketMode  SocketMode
+	// tls, if set, are the credentials the listeners serve TLS with, which
+	// are reloaded when their files change if tlsReload is set.
+	tls       *tlsCredentials
+	tlsReload bool
+	// adminAuthService guards the admin endpoints. See ServerConfig.
+	adminAuthService string
+	// adminSubjects and adminEmails are the claims of the callers allowed to
+	// call the admin endpoints.
+	adminSubjects []string
+	adminEmails   []string
+	// clientAttribution is how callers are identified to backends. See
+	// ServerConfig.
+	clientAttribution string
+	// dev enables the endpoints meant for tool authors. See ServerConfig.
+	dev             bool
+	listeners       []net.Listener
 	root            chi.Router
 	logger          log.Logger
 	instrumentation *telemetry.Instrumentation
 	sseManager      *sseManager
-	ResourceMgr     *ResourceManager
+	wsManager       *wsManager
+	// wsMaxMessageSize is the maximum size of a message received over a
+	// websocket. See ServerConfig.
+	wsMaxMessageSize int64
+	// artifacts stores the results spilled by tools with `spillToFile` and
+	// the uploaded artifacts. It is nil if artifacts are not enabled.
+	artifacts *artifactStore
+	// artifactUploadMaxBytes is the maximum size in bytes of an uploaded artifact.
+	artifactUploadMaxBytes int64
+	// artifactUploadAuthServices verify the clients uploading artifacts.
+	// Uploads are disabled if empty.
+	artifactUploadAuthServices []string
+	// maxResolvedResourceBytes bounds the resources resolved for an
+	// invocation. See ServerConfig.MaxResolvedResourceBytes.
+	maxResolvedResourceBytes int64
+	// state keeps the state shared by the replicas of the server.
+	state state.Store
+	// idempotency records the invocations of tools with `idempotencyKeys`.
+	idempotency *tools.Idempotency
+	// histories keeps the history of each session, if enabled.
+	histories *sessionHistories
+	// sticky pins connections to sessions for tools with `stickySession`.
+	sticky *tools.StickySessions
+	// disabledToolKinds and hideDisabledTools are kept for the configs
+	// reloaded later. See ServerConfig.
+	disabledToolKinds []string
+	hideDisabledTools bool
+	// bulkMaxLines and bulkConcurrency bound bulk invocations. See
+	// ServerConfig.
+	bulkMaxLines    int
+	bulkConcurrency int
+	// payloadLimits bounds the JSON arguments of invocations. See
+	// ServerConfig.
+	payloadLimits util.PayloadLimits
+	// events publishes an event for every invocation, if an event sink is
+	// configured.
+	events      *events.Publisher
+	ResourceMgr *ResourceManager
 }
 
 // ResourceManager contains available resources for the server. Should be initialized with NewResourceManager().
@@ -57,6 +120,18 @@ type ResourceManager struct {
 	authServices map[string]auth.AuthService
 	tools        map[string]tools.Tool
 	toolsets     map[string]tools.Toolset
+	// toolSources maps each tool name to the name of the source it acts on.
+	toolSources map[string]string
+	// toolKinds maps each tool name to its kind.
+	toolKinds map[string]string
+	// sourceDetails holds the sanitized connection details of each source.
+	sourceDetails map[string]map[string]any
+	// sourceHashes holds the hash of the config of each source, so that
+	// state derived from a source is not reused once its config changes.
+	sourceHashes map[string]string
+	invocations  *invocationStats
+	state        state.Store
+	schemas      *schemaCache
 }
 
 func NewResourceManager(
@@ -70,7 +145,11 @@ func NewResourceManager(
 		authServices: authServicesMap,
 		tools:        toolsMap,
 		toolsets:     toolsetsMap,
+		toolSources:  make(map[string]string),
+		toolKinds:    make(map[string]string),
+		invocations:  newInvocationStats(),
 	}
+	resourceMgr.SetStateStore(state.NewMemory())
 
 	return resourceMgr
 }
@@ -110,6 +189,46 @@ func (r *ResourceManager) SetResources(sourcesMap map[string]sources.Source, aut
 	r.authServices = authServicesMap
 	r.tools = toolsMap
 	r.toolsets = toolsetsMap
+	r.schemas = newSchemaCache(r.state, schemaCacheTTL)
+}
+
+// SetStateStore sets the store of the state derived from the resources, such
+// as cached source schemas.
+func (r *ResourceManager) SetStateStore(store state.Store) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.state = store
+	r.schemas = newSchemaCache(store, schemaCacheTTL)
+}
+
+// SetToolSources sets the source that each tool acts on. See ToolSources.
+func (r *ResourceManager) SetToolSources(toolSources map[string]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.toolSources = toolSources
+}
+
+// SetToolKinds sets the kind of each tool. See ToolKinds.
+func (r *ResourceManager) SetToolKinds(toolKinds map[string]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.toolKinds = toolKinds
+}
+
+// SetSourceDetails sets the sanitized connection details of each source. See
+// SourceDetails.
+func (r *ResourceManager) SetSourceDetails(sourceDetails map[string]map[string]any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sourceDetails = sourceDetails
+}
+
+// SetSourceHashes sets the hash of the config of each source. See
+// SourceHashes.
+func (r *ResourceManager) SetSourceHashes(sourceHashes map[string]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sourceHashes = sourceHashes
 }
 
 func (r *ResourceManager) GetAuthServiceMap() map[string]auth.AuthService {
@@ -124,6 +243,107 @@ func (r *ResourceManager) GetToolsMap() map[string]tools.Tool {
 	return r.tools
 }
 
+// initializeSources initializes the sources concurrently, at most
+// cfg.SourceInitConcurrency at a time, and fails the sources that take longer
+// than cfg.SourceInitTimeout. It reports the errors of every failed source.
+func initializeSources(ctx context.Context, cfg ServerConfig) (map[string]sources.Source, error) {
+	instrumentation, err := util.InstrumentationFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	l, err := util.LoggerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	timeout := cfg.SourceInitTimeout
+	if timeout <= 0 {
+		timeout = DefaultSourceInitTimeout
+	}
+	concurrency := cfg.SourceInitConcurrency
+	if concurrency <= 0 {
+		concurrency = DefaultSourceInitConcurrency
+	}
+
+	names := slices.Sorted(maps.Keys(cfg.SourceConfigs))
+	initialized := make([]sources.Source, len(names))
+	errs := make([]error, len(names))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, name := range names {
+		sc := cfg.SourceConfigs[name]
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			start := time.Now()
+			initialized[i], errs[i] = initializeSource(ctx, instrumentation.Tracer, name, sc, timeout)
+			if errs[i] != nil {
+				return
+			}
+			msg := fmt.Sprintf("Initialized source %q in %s", name, time.Since(start).Round(time.Millisecond))
+			if isLazySourceConfig(sc) {
+				msg += "; it connects on first use"
+			}
+			l.InfoContext(ctx, msg)
+		}()
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	sourcesMap := make(map[string]sources.Source, len(names))
+	for i, name := range names {
+		sourcesMap[name] = initialized[i]
+	}
+	return sourcesMap, nil
+}
+
+// initializeSource initializes a source, giving up after timeout. The context
+// of the source is only canceled when it times out, as some sources keep
+// using it after they are initialized.
+func initializeSource(ctx context.Context, tracer trace.Tracer, name string, sc sources.SourceConfig, timeout time.Duration) (sources.Source, error) {
+	ctx, span := tracer.Start(
+		ctx,
+		"toolbox/server/source/init",
+		trace.WithAttributes(attribute.String("source_kind", sc.SourceConfigKind())),
+		trace.WithAttributes(attribute.String("source_name", name)),
+	)
+	defer span.End()
+
+	initCtx, cancel := context.WithCancel(ctx)
+	timer := time.AfterFunc(timeout, cancel)
+	type result struct {
+		s   sources.Source
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		s, err := sc.Initialize(initCtx, tracer)
+		done <- result{s, err}
+	}()
+
+	select {
+	case r := <-done:
+		if !timer.Stop() {
+			return nil, fmt.Errorf("unable to initialize source %q: timed out after %s", name, timeout)
+		}
+		if r.err != nil {
+			return nil, fmt.Errorf("unable to initialize source %q: %w", name, r.err)
+		}
+		return r.s, nil
+	case <-initCtx.Done():
+		// the source may ignore its context, so it is abandoned rather than
+		// waited for
+		timer.Stop()
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("unable to initialize source %q: %w", name, ctx.Err())
+		}
+		return nil, fmt.Errorf("unable to initialize source %q: timed out after %s", name, timeout)
+	}
+}
+
 func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 	map[string]sources.Source,
 	map[string]auth.AuthService,
@@ -143,26 +363,9 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 	}
 
 	// initialize and validate the sources from configs
-	sourcesMap := make(map[string]sources.Source)
-	for name, sc := range cfg.SourceConfigs {
-		s, err := func() (sources.Source, error) {
-			childCtx, span := instrumentation.Tracer.Start(
-				ctx,
-				"toolbox/server/source/init",
-				trace.WithAttributes(attribute.String("source_kind", sc.SourceConfigKind())),
-				trace.WithAttributes(attribute.String("source_name", name)),
-			)
-			defer span.End()
-			s, err := sc.Initialize(childCtx, instrumentation.Tracer)
-			if err != nil {
-				return nil, fmt.Errorf("unable to initialize source %q: %w", name, err)
-			}
-			return s, nil
-		}()
-		if err != nil {
-			return nil, nil, nil, nil, err
-		}
-		sourcesMap[name] = s
+	sourcesMap, err := initializeSources(ctx, cfg)
+	if err != nil {
+		return nil, nil, nil, nil, err
 	}
 	sourceNames := make([]string, 0, len(sourcesMap))
 	for name := range sourcesMap {
@@ -171,26 +374,9 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 	l.InfoContext(ctx, fmt.Sprintf("Initialized %d sources: %s", len(sourcesMap), strings.Join(sourceNames, ", ")))
 
 	// initialize and validate the auth services from configs
-	authServicesMap := make(map[string]auth.AuthService)
-	for name, sc := range cfg.AuthServiceConfigs {
-		a, err := func() (auth.AuthService, error) {
-			_, span := instrumentation.Tracer.Start(
-				ctx,
-				"toolbox/server/auth/init",
-				trace.WithAttributes(attribute.String("auth_kind", sc.AuthServiceConfigKind())),
-				trace.WithAttributes(attribute.String("auth_name", name)),
-			)
-			defer span.End()
-			a, err := sc.Initialize()
-			if err != nil {
-				return nil, fmt.Errorf("unable to initialize auth service %q: %w", name, err)
-			}
-			return a, nil
-		}()
-		if err != nil {
-			return nil, nil, nil, nil, err
-		}
-		authServicesMap[name] = a
+	authServicesMap, err := initializeAuthServices(ctx, cfg)
+	if err != nil {
+		return nil, nil, nil, nil, err
 	}
 	authServiceNames := make([]string, 0, len(authServicesMap))
 	for name := range authServicesMap {
@@ -200,37 +386,47 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 
 	// initialize and validate the tools from configs
 	toolsMap := make(map[string]tools.Tool)
+	inheritedAuth := toolsetAuthRequired(cfg.ToolConfigs, cfg.ToolsetConfigs)
 	for name, tc := range cfg.ToolConfigs {
-		t, err := func() (tools.Tool, error) {
-			_, span := instrumentation.Tracer.Start(
-				ctx,
-				"toolbox/server/tool/init",
-				trace.WithAttributes(attribute.String("tool_kind", tc.ToolConfigKind())),
-				trace.WithAttributes(attribute.String("tool_name", name)),
-			)
-			defer span.End()
-			t, err := tc.Initialize(sourcesMap)
-			if err != nil {
-				return nil, fmt.Errorf("unable to initialize tool %q: %w", name, err)
-			}
-			return t, nil
-		}()
+		t, err := initializeTool(ctx, cfg, name, tc, sourcesMap, inheritedAuth[name])
 		if err != nil {
 			return nil, nil, nil, nil, err
 		}
 		toolsMap[name] = t
 	}
 	toolNames := make([]string, 0, len(toolsMap))
-	for name := range toolsMap {
+	var disabledToolNames []string
+	for name, t := range toolsMap {
 		toolNames = append(toolNames, name)
+		if _, ok := tools.As[tools.DisabledTool](t); ok {
+			disabledToolNames = append(disabledToolNames, name)
+		}
 	}
 	l.InfoContext(ctx, fmt.Sprintf("Initialized %d tools: %s", len(toolsMap), strings.Join(toolNames, ", ")))
+	if len(cfg.DisabledToolKinds) > 0 {
+		slices.Sort(disabledToolNames)
+		l.WarnContext(ctx, fmt.Sprintf("Disabled %d tools of the kinds %q: %s", len(disabledToolNames), cfg.DisabledToolKinds, strings.Join(disabledToolNames, ", ")))
+	}
 
 	// create a default toolset that contains all tools
 	allToolNames := make([]string, 0, len(toolsMap))
 	for name := range toolsMap {
 		allToolNames = append(allToolNames, name)
 	}
+
+	// register aliases so that tools are reachable by their old names
+	if err := validateToolAliases(cfg.ToolConfigs); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	for name, tc := range cfg.ToolConfigs {
+		aliasCfg, ok := tc.(tools.AliasConfig)
+		if !ok {
+			continue
+		}
+		for _, alias := range aliasCfg.Aliases {
+			toolsMap[alias] = tools.AliasTool{Tool: toolsMap[name], Name: alias, Target: name}
+		}
+	}
 	if cfg.ToolsetConfigs == nil {
 		cfg.ToolsetConfigs = make(ToolsetConfigs)
 	}
@@ -255,6 +451,9 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 		if err != nil {
 			return nil, nil, nil, nil, err
 		}
+		if cfg.HideDisabledTools {
+			t = t.WithoutDisabled()
+		}
 		toolsetsMap[name] = t
 	}
 	toolsetNames := make([]string, 0, len(toolsetsMap))
@@ -270,6 +469,156 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 	return sourcesMap, authServicesMap, toolsMap, toolsetsMap, nil
 }
 
+// initializeAuthServices initializes the auth services of cfg.
+func initializeAuthServices(ctx context.Context, cfg ServerConfig) (map[string]auth.AuthService, error) {
+	instrumentation, err := util.InstrumentationFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	authServicesMap := make(map[string]auth.AuthService)
+	for name, sc := range cfg.AuthServiceConfigs {
+		a, err := func() (auth.AuthService, error) {
+			_, span := instrumentation.Tracer.Start(
+				ctx,
+				"toolbox/server/auth/init",
+				trace.WithAttributes(attribute.String("auth_kind", sc.AuthServiceConfigKind())),
+				trace.WithAttributes(attribute.String("auth_name", name)),
+			)
+			defer span.End()
+			a, err := sc.Initialize()
+			if err != nil {
+				return nil, fmt.Errorf("unable to initialize auth service %q: %w", name, err)
+			}
+			return a, nil
+		}()
+		if err != nil {
+			return nil, err
+		}
+		authServicesMap[name] = a
+	}
+	return authServicesMap, nil
+}
+
+// initializeTool initializes the tool name of config tc, applying the
+// defaults of cfg and wrapping it in the behaviors of its source. inheritedAuth
+// lists the sets of authServices it inherits from its toolsets.
+func initializeTool(ctx context.Context, cfg ServerConfig, name string, tc tools.ToolConfig, sourcesMap map[string]sources.Source, inheritedAuth [][]string) (tools.Tool, error) {
+	instrumentation, err := util.InstrumentationFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	l, err := util.LoggerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if w, ok := tc.(tools.ConfigWarner); ok {
+		for _, warning := range w.ConfigWarnings() {
+			l.WarnContext(ctx, fmt.Sprintf("tool %q: %s", name, warning))
+		}
+	}
+	if bc, ok := cfg.SourceConfigs[toolSourceName(tc)].(CircuitBreakerSourceConfig); ok {
+		tc = withSourceCircuitBreaker(tc, bc.Breaker)
+	}
+	if cfg.DefaultSchedule != nil {
+		tc = withDefaultSchedule(tc, cfg.DefaultSchedule)
+	}
+	if cfg.IncludeUsageMetadata {
+		tc = withUsageMetadata(tc)
+	}
+	tc = withDefaultOutputSchema(tc)
+	if cfg.DefaultResultTimezone != nil {
+		tc = withDefaultResultTimezone(tc, cfg.DefaultResultTimezone)
+	}
+	t, err := func() (tools.Tool, error) {
+		_, span := instrumentation.Tracer.Start(
+			ctx,
+			"toolbox/server/tool/init",
+			trace.WithAttributes(attribute.String("tool_kind", tc.ToolConfigKind())),
+			trace.WithAttributes(attribute.String("tool_name", name)),
+		)
+		defer span.End()
+		t, err := tc.Initialize(sourcesMap)
+		if err != nil {
+			return nil, fmt.Errorf("unable to initialize tool %q: %w", name, err)
+		}
+		t, err = withCapabilities(t, tc, sourcesMap[toolSourceName(tc)])
+		if err != nil {
+			return nil, fmt.Errorf("unable to initialize tool %q: %w", name, err)
+		}
+		t, err = withDescriptionTemplate(t, toolSourceName(tc), cfg.SourceConfigs[toolSourceName(tc)])
+		if err != nil {
+			return nil, fmt.Errorf("unable to initialize tool %q: %w", name, err)
+		}
+		// tell the invocations failing to get a connection of the source
+		if ps, ok := sourcesMap[toolSourceName(tc)].(sources.PoolSource); ok {
+			t = tools.PoolExhaustionTool{Tool: t, SourceName: toolSourceName(tc), Source: ps}
+		}
+		// reload the credentials of the source when they are rejected
+		if rs, ok := sourcesMap[toolSourceName(tc)].(sources.RotatableSource); ok {
+			t = tools.CredentialRefreshTool{Tool: t, Source: rs}
+		}
+		t, err = withInheritedAuth(t, tc, inheritedAuth, cfg.DefaultAuthRequired)
+		if err != nil {
+			return nil, fmt.Errorf("unable to initialize tool %q: %w", name, err)
+		}
+		return t, nil
+	}()
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(cfg.DisabledToolKinds, tc.ToolConfigKind()) {
+		t = tools.DisabledTool{Tool: t, Kind: tc.ToolConfigKind()}
+	}
+	return t, nil
+}
+
+// InitializeTool initializes the tool named name, which may be an alias, for
+// invoking it outside of a server, such as from the command line. Only the
+// source of the tool is initialized, along with the auth services, which
+// verify the tokens of the invocations.
+func InitializeTool(ctx context.Context, cfg ServerConfig, name string) (tools.Tool, map[string]auth.AuthService, error) {
+	ctx = util.WithUserAgent(ctx, cfg.Version)
+
+	target := name
+	if _, ok := cfg.ToolConfigs[name]; !ok {
+		target = ""
+		for toolName, tc := range cfg.ToolConfigs {
+			if aliasCfg, ok := tc.(tools.AliasConfig); ok && slices.Contains(aliasCfg.Aliases, name) {
+				target = toolName
+				break
+			}
+		}
+		if target == "" {
+			return nil, nil, fmt.Errorf("tool with name %q does not exist", name)
+		}
+	}
+	tc := cfg.ToolConfigs[target]
+
+	sourceCfg := cfg
+	sourceCfg.SourceConfigs = SourceConfigs{}
+	if sc, ok := cfg.SourceConfigs[toolSourceName(tc)]; ok {
+		sourceCfg.SourceConfigs[toolSourceName(tc)] = sc
+	}
+	sourcesMap, err := initializeSources(ctx, sourceCfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	authServicesMap, err := initializeAuthServices(ctx, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	inheritedAuth := toolsetAuthRequired(cfg.ToolConfigs, cfg.ToolsetConfigs)
+	t, err := initializeTool(ctx, cfg, target, tc, sourcesMap, inheritedAuth[target])
+	if err != nil {
+		return nil, nil, err
+	}
+	if target != name {
+		t = tools.AliasTool{Tool: t, Name: name, Target: target}
+	}
+	return t, authServicesMap, nil
+}
+
 // NewServer returns a Server object based on provided Config.
 func NewServer(ctx context.Context, cfg ServerConfig) (*Server, error) {
 	instrumentation, err := util.InstrumentationFromContext(ctx)
@@ -288,6 +637,7 @@ func NewServer(ctx context.Context, cfg ServerConfig) (*Server, error) {
 	// set up http serving
 	r := chi.NewRouter()
 	r.Use(middleware.Recoverer)
+	r.Use(clientCertificateContext)
 	// logging
 	logLevel, err := log.SeverityToLevel(cfg.LogLevel.String())
 	if err != nil {
@@ -324,21 +674,219 @@ func NewServer(ctx context.Context, cfg ServerConfig) (*Server, error) {
 		return nil, fmt.Errorf("unable to initialize configs: %w", err)
 	}
 
-	addr := net.JoinHostPort(cfg.Address, strconv.Itoa(cfg.Port))
-	srv := &http.Server{Addr: addr, Handler: r}
+	listenAddrs := cfg.Listen
+	if len(listenAddrs) == 0 {
+		listenAddrs = []string{tcpScheme + net.JoinHostPort(cfg.Address, strconv.Itoa(cfg.Port))}
+	}
+	for _, addr := range listenAddrs {
+		if _, _, err := parseListenAddress(addr); err != nil {
+			return nil, err
+		}
+	}
+	srv := &http.Server{Handler: r}
+	tlsCreds, err := newTLSCredentials(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.AdminAuthService != "" {
+		if _, ok := authServicesMap[cfg.AdminAuthService]; !ok {
+			return nil, fmt.Errorf("admin auth service %q is not configured", cfg.AdminAuthService)
+		}
+		if len(cfg.AdminSubjects) == 0 && len(cfg.AdminEmails) == 0 {
+			return nil, fmt.Errorf("admin auth service %q requires admin subjects or admin emails to allow", cfg.AdminAuthService)
+		}
+	}
+
+	switch cfg.ClientAttribution {
+	case "", util.ClientAttributionAuth, util.ClientAttributionHeader:
+	default:
+		return nil, fmt.Errorf("invalid client attribution %q: must be %q or %q", cfg.ClientAttribution, util.ClientAttributionAuth, util.ClientAttributionHeader)
+	}
 
 	sseManager := newSseManager(ctx)
 
+	wsMaxMessageSize := cfg.McpWebsocketMaxMessageSize
+	if wsMaxMessageSize < 0 {
+		return nil, fmt.Errorf("invalid MCP websocket max message size %d: must not be negative", wsMaxMessageSize)
+	}
+	if wsMaxMessageSize == 0 {
+		wsMaxMessageSize = DefaultMcpWebsocketMaxMessageSize
+	}
+
+	artifactDir := cfg.ArtifactDir
+	if artifactDir == "" {
+		artifactDir = filepath.Join(os.TempDir(), "toolbox-artifacts")
+	}
+	artifactTTL := cfg.ArtifactTTL
+	if artifactTTL < 0 {
+		return nil, fmt.Errorf("invalid artifact TTL %s: must not be negative", artifactTTL)
+	}
+	if artifactTTL == 0 {
+		artifactTTL = DefaultArtifactTTL
+	}
+	artifactMaxBytes := cfg.ArtifactMaxBytes
+	if artifactMaxBytes < 0 {
+		return nil, fmt.Errorf("invalid artifact max bytes %d: must not be negative", artifactMaxBytes)
+	}
+	if artifactMaxBytes == 0 {
+		artifactMaxBytes = DefaultArtifactMaxBytes
+	}
+	artifactMaxCount := cfg.ArtifactMaxCount
+	if artifactMaxCount < 0 {
+		return nil, fmt.Errorf("invalid artifact max count %d: must not be negative", artifactMaxCount)
+	}
+	if artifactMaxCount == 0 {
+		artifactMaxCount = DefaultArtifactMaxCount
+	}
+	for _, name := range cfg.ArtifactUploadAuthServices {
+		if _, ok := authServicesMap[name]; !ok {
+			return nil, fmt.Errorf("artifact upload auth service %q is not configured", name)
+		}
+	}
+	// the artifact store, and its routes, only exist if artifacts are used
+	var artifacts *artifactStore
+	if cfg.ArtifactDir != "" || len(cfg.ArtifactUploadAuthServices) > 0 || spillsToFile(cfg.ToolConfigs) {
+		artifacts = newArtifactStore(ctx, l, artifactDir, artifactTTL, artifactMaxBytes, artifactMaxCount)
+	}
+	artifactUploadMaxBytes := cfg.ArtifactUploadMaxBytes
+	if artifactUploadMaxBytes < 0 {
+		return nil, fmt.Errorf("invalid artifact upload max bytes %d: must not be negative", artifactUploadMaxBytes)
+	}
+	if artifactUploadMaxBytes == 0 {
+		artifactUploadMaxBytes = DefaultArtifactUploadMaxBytes
+	}
+	maxResolvedResourceBytes := cfg.MaxResolvedResourceBytes
+	if maxResolvedResourceBytes < 0 {
+		return nil, fmt.Errorf("invalid max resolved resource bytes %d: must not be negative", maxResolvedResourceBytes)
+	}
+	if maxResolvedResourceBytes == 0 {
+		maxResolvedResourceBytes = DefaultMaxResolvedResourceBytes
+	}
+
+	stateStore, err := newStateStore(ctx, cfg, l)
+	if err != nil {
+		return nil, err
+	}
+	idempotencyTTL := cfg.IdempotencyTTL
+	if idempotencyTTL < 0 {
+		return nil, fmt.Errorf("invalid idempotency TTL %s: must not be negative", idempotencyTTL)
+	}
+	if idempotencyTTL == 0 {
+		idempotencyTTL = DefaultIdempotencyTTL
+	}
+	if cfg.SessionHistorySize < 0 {
+		return nil, fmt.Errorf("invalid session history size %d: must not be negative", cfg.SessionHistorySize)
+	}
+	sessionHistoryTTL := cfg.SessionHistoryTTL
+	if sessionHistoryTTL < 0 {
+		return nil, fmt.Errorf("invalid session history TTL %s: must not be negative", sessionHistoryTTL)
+	}
+	if sessionHistoryTTL == 0 {
+		sessionHistoryTTL = DefaultSessionHistoryTTL
+	}
+	stickySessionTTL := cfg.StickySessionTTL
+	if stickySessionTTL < 0 {
+		return nil, fmt.Errorf("invalid sticky session TTL %s: must not be negative", stickySessionTTL)
+	}
+	if stickySessionTTL == 0 {
+		stickySessionTTL = DefaultStickySessionTTL
+	}
+	stickySessionMaxConns := cfg.StickySessionMaxConns
+	if stickySessionMaxConns < 0 {
+		return nil, fmt.Errorf("invalid sticky session max conns %d: must not be negative", stickySessionMaxConns)
+	}
+	if stickySessionMaxConns == 0 {
+		stickySessionMaxConns = DefaultStickySessionMaxConns
+	}
+
+	bulkMaxLines := cfg.BulkInvokeMaxLines
+	if bulkMaxLines < 0 {
+		return nil, fmt.Errorf("invalid bulk invoke max lines %d: must not be negative", bulkMaxLines)
+	}
+	if bulkMaxLines == 0 {
+		bulkMaxLines = DefaultBulkInvokeMaxLines
+	}
+	bulkConcurrency := cfg.BulkInvokeConcurrency
+	if bulkConcurrency < 0 {
+		return nil, fmt.Errorf("invalid bulk invoke concurrency %d: must not be negative", bulkConcurrency)
+	}
+	if bulkConcurrency == 0 {
+		bulkConcurrency = DefaultBulkInvokeConcurrency
+	}
+	payloadLimits := util.PayloadLimits{
+		MaxDepth:        cfg.MaxPayloadDepth,
+		MaxKeys:         cfg.MaxPayloadKeys,
+		MaxStringLength: cfg.MaxPayloadStringLength,
+	}
+	if payloadLimits.MaxDepth < 0 || payloadLimits.MaxKeys < 0 || payloadLimits.MaxStringLength < 0 {
+		return nil, fmt.Errorf("invalid payload limits %+v: must not be negative", payloadLimits)
+	}
+	if payloadLimits.MaxDepth == 0 {
+		payloadLimits.MaxDepth = DefaultMaxPayloadDepth
+	}
+	if payloadLimits.MaxKeys == 0 {
+		payloadLimits.MaxKeys = DefaultMaxPayloadKeys
+	}
+	if payloadLimits.MaxStringLength == 0 {
+		payloadLimits.MaxStringLength = DefaultMaxPayloadStringLength
+	}
+
+	eventPublisher, err := newEventPublisher(ctx, cfg, l)
+	if err != nil {
+		return nil, err
+	}
+	if eventPublisher != nil {
+		if err := instrumentation.ObserveDroppedEvents(eventPublisher.Dropped); err != nil {
+			return nil, err
+		}
+	}
+
 	resourceManager := NewResourceManager(sourcesMap, authServicesMap, toolsMap, toolsetsMap)
+	resourceManager.SetStateStore(stateStore)
+	resourceManager.SetToolSources(ToolSources(cfg.ToolConfigs))
+	resourceManager.SetToolKinds(ToolKinds(cfg.ToolConfigs))
+	resourceManager.SetSourceDetails(SourceDetails(cfg.SourceConfigs))
+	resourceManager.SetSourceHashes(SourceHashes(cfg.SourceConfigs))
+	if err := instrumentation.ObserveCircuitBreakers(resourceManager.circuitBreakerStates); err != nil {
+		return nil, err
+	}
 
 	s := &Server{
-		version:         cfg.Version,
-		srv:             srv,
-		root:            r,
-		logger:          l,
-		instrumentation: instrumentation,
-		sseManager:      sseManager,
-		ResourceMgr:     resourceManager,
+		version:                    cfg.Version,
+		srv:                        srv,
+		listenAddrs:                listenAddrs,
+		socketMode:                 cfg.SocketMode,
+		tls:                        tlsCreds,
+		tlsReload:                  cfg.TLSReload,
+		adminAuthService:           cfg.AdminAuthService,
+		adminSubjects:              cfg.AdminSubjects,
+		adminEmails:                cfg.AdminEmails,
+		clientAttribution:          cfg.ClientAttribution,
+		dev:                        cfg.Dev,
+		root:                       r,
+		logger:                     l,
+		instrumentation:            instrumentation,
+		sseManager:                 sseManager,
+		wsManager:                  newWsManager(),
+		wsMaxMessageSize:           wsMaxMessageSize,
+		artifacts:                  artifacts,
+		artifactUploadMaxBytes:     artifactUploadMaxBytes,
+		artifactUploadAuthServices: cfg.ArtifactUploadAuthServices,
+		maxResolvedResourceBytes:   maxResolvedResourceBytes,
+		state:                      stateStore,
+		idempotency:                &tools.Idempotency{Store: stateStore, TTL: idempotencyTTL},
+		disabledToolKinds:          cfg.DisabledToolKinds,
+		hideDisabledTools:          cfg.HideDisabledTools,
+		bulkMaxLines:               bulkMaxLines,
+		bulkConcurrency:            bulkConcurrency,
+		payloadLimits:              payloadLimits,
+		events:                     eventPublisher,
+		sticky:                     tools.NewStickySessions(stickySessionTTL, stickySessionMaxConns),
+		ResourceMgr:                resourceManager,
+	}
+	if cfg.SessionHistorySize > 0 {
+		s.histories = newSessionHistories(cfg.SessionHistorySize, sessionHistoryTTL, resourceManager.GetTool)
 	}
 	// control plane
 	apiR, err := apiRouter(s)
@@ -366,31 +914,108 @@ func NewServer(ctx context.Context, cfg ServerConfig) (*Server, error) {
 	return s, nil
 }
 
-// Listen starts a listener for the given Server instance.
+// DisabledToolKinds returns the kinds of tools disabled by the operator of
+// the server, and whether their tools are hidden from the listings.
+func (s *Server) DisabledToolKinds() ([]string, bool) {
+	return s.disabledToolKinds, s.hideDisabledTools
+}
+
+// Listen starts a listener for each listen address of the given Server instance.
 func (s *Server) Listen(ctx context.Context) error {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	if s.listener != nil {
-		return fmt.Errorf("server is already listening: %s", s.listener.Addr().String())
+	if len(s.listeners) != 0 {
+		return fmt.Errorf("server is already listening: %s", strings.Join(s.Addrs(), ", "))
 	}
-	lc := net.ListenConfig{KeepAlive: 30 * time.Second}
-	var err error
-	if s.listener, err = lc.Listen(ctx, "tcp", s.srv.Addr); err != nil {
-		return fmt.Errorf("failed to open listener for %q: %w", s.srv.Addr, err)
+	listeners := make([]net.Listener, 0, len(s.listenAddrs))
+	for _, addr := range s.listenAddrs {
+		l, err := listen(ctx, addr, s.socketMode.FileMode())
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return fmt.Errorf("failed to open listener for %q: %w", addr, err)
+		}
+		if s.tls != nil {
+			l = tls.NewListener(l, s.tls.config())
+		}
+		listeners = append(listeners, l)
+		s.logger.DebugContext(ctx, fmt.Sprintf("server listening on %s", listenAddrString(l.Addr())))
 	}
-	s.logger.DebugContext(ctx, fmt.Sprintf("server listening on %s", s.srv.Addr))
+	s.listeners = listeners
 	return nil
 }
 
-// Serve starts an HTTP server for the given Server instance.
+// TLSEnabled reports whether the listeners of the server serve over TLS.
+func (s *Server) TLSEnabled() bool {
+	return s.tls != nil
+}
+
+// Addrs returns the addresses the server is listening on, such as
+// `tcp://127.0.0.1:5000` or `unix:///path/to.sock`.
+func (s *Server) Addrs() []string {
+	addrs := make([]string, 0, len(s.listeners))
+	for _, l := range s.listeners {
+		addrs = append(addrs, listenAddrString(l.Addr()))
+	}
+	return addrs
+}
+
+// Serve starts an HTTP server on every listener of the given Server instance.
+// It returns once all listeners have stopped, with the first error encountered.
 func (s *Server) Serve(ctx context.Context) error {
 	s.logger.DebugContext(ctx, "Starting a HTTP server.")
-	return s.srv.Serve(s.listener)
+	if s.tls != nil && s.tlsReload {
+		go s.tls.watch(ctx, s.logger)
+	}
+	errCh := make(chan error, len(s.listeners))
+	for _, l := range s.listeners {
+		go func(l net.Listener) {
+			errCh <- s.srv.Serve(l)
+		}(l)
+	}
+	var firstErr error
+	for range s.listeners {
+		err := <-errCh
+		if err == nil || firstErr != nil {
+			continue
+		}
+		firstErr = err
+		if !errors.Is(err, http.ErrServerClosed) {
+			// stop the remaining listeners so that Serve returns
+			_ = s.srv.Close()
+		}
+	}
+	return firstErr
+}
+
+// idempotencyContext adds the record of the invocations of tools with
+// `idempotencyKeys` into the context of the request.
+func (s *Server) idempotencyContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.idempotency == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(tools.WithIdempotency(r.Context(), s.idempotency)))
+	})
 }
 
 // ServeStdio starts a new stdio session for mcp.
 func (s *Server) ServeStdio(ctx context.Context, stdin io.Reader, stdout io.Writer) error {
+	if s.artifacts != nil {
+		// there is no request to take the host from, so download URLs are
+		// relative to the HTTP server
+		ctx = tools.WithArtifactStore(ctx, s.artifacts.forBaseURL(""))
+		ctx = tools.WithResourceResolver(ctx, s.artifactResolver())
+	}
+	if s.idempotency != nil {
+		ctx = tools.WithIdempotency(ctx, s.idempotency)
+	}
+	sessionId := uuid.New().String()
+	defer s.endSession(sessionId)
+	ctx = s.withSession(ctx, sessionId)
 	stdioServer := NewStdioSession(s, stdin, stdout)
 	return stdioServer.Start(ctx)
 }
@@ -399,5 +1024,53 @@ func (s *Server) ServeStdio(ctx context.Context, stdin io.Reader, stdout io.Writ
 // connections. It uses http.Server.Shutdown() and has the same functionality.
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.logger.DebugContext(ctx, "shutting down the server.")
-	return s.srv.Shutdown(ctx)
+	// upgraded websocket connections are not closed by srv.Shutdown
+	s.wsManager.shutdown(ctx)
+	err := s.srv.Shutdown(ctx)
+	if s.sticky != nil {
+		// the invocations are done, so that no pinned connection is in use
+		s.sticky.Close()
+	}
+	if s.events != nil {
+		// the invocations are done, so that their events are all flushed
+		if cerr := s.events.Close(ctx); cerr != nil {
+			s.logger.WarnContext(ctx, cerr.Error())
+		}
+	}
+	if s.state != nil {
+		if cerr := s.state.Close(); cerr != nil {
+			s.logger.WarnContext(ctx, fmt.Sprintf("unable to close state store: %s", cerr))
+		}
+	}
+	return err
+}
+
+// newStateStore returns the store of the state shared by the replicas of the
+// server: a Redis store if one is configured, or else a Memory store. A Redis
+// store degrades to memory while it is unreachable, rather than failing
+// invocations.
+func newStateStore(ctx context.Context, cfg ServerConfig, l log.Logger) (state.Store, error) {
+	if cfg.StateRedisAddress == "" {
+		return state.NewMemory(), nil
+	}
+	maxTTL := cfg.StateMaxTTL
+	if maxTTL < 0 {
+		return nil, fmt.Errorf("invalid state max TTL %s: must not be negative", maxTTL)
+	}
+	if maxTTL == 0 {
+		maxTTL = DefaultStateMaxTTL
+	}
+	r, err := state.NewRedis(state.RedisConfig{
+		Address:   cfg.StateRedisAddress,
+		TLS:       cfg.StateRedisTLS,
+		KeyPrefix: cfg.StateKeyPrefix,
+		MaxTTL:    maxTTL,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := r.Ping(ctx); err != nil {
+		l.WarnContext(ctx, fmt.Sprintf("state store is unreachable, keeping state local to this replica until it recovers: %s", err))
+	}
+	return state.NewFallback(r, l), nil
 }
@@ -16,23 +16,37 @@ package server_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"net"
 	"net/http"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/auth/google"
 	"github.com/googleapis/genai-toolbox/internal/log"
 	"github.com/googleapis/genai-toolbox/internal/server"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/sources/alloydbpg"
+	_ "github.com/googleapis/genai-toolbox/internal/sources/http"
+	_ "github.com/googleapis/genai-toolbox/internal/sources/postgres"
 	"github.com/googleapis/genai-toolbox/internal/telemetry"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/postgres/postgresexecutesql"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/postgres/postgressql"
 	"github.com/googleapis/genai-toolbox/internal/util"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestServe(t *testing.T) {
@@ -172,3 +186,1022 @@ func TestUpdateServer(t *testing.T) {
 		t.Errorf("error updating server, toolset (-want +got):\n%s", diff)
 	}
 }
+
+func newListenTestServer(t *testing.T, cfg server.ServerConfig) (context.Context, *server.Server) {
+	t.Helper()
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("error setting up logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(cfg.Version)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx = util.WithInstrumentation(ctx, instrumentation)
+
+	s, err := server.NewServer(ctx, cfg)
+	if err != nil {
+		t.Fatalf("unable to initialize server: %v", err)
+	}
+	return ctx, s
+}
+
+// unixClient returns an HTTP client that sends every request to the socket at path.
+func unixClient(path string) *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", path)
+			},
+		},
+	}
+}
+
+func TestServeUnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "toolbox.sock")
+	cfg := server.ServerConfig{
+		Version:    "0.0.0",
+		Listen:     []string{"unix://" + socket, "tcp://127.0.0.1:0"},
+		SocketMode: 0o600,
+	}
+	ctx, s := newListenTestServer(t, cfg)
+	if err := s.Listen(ctx); err != nil {
+		t.Fatalf("unable to start server: %v", err)
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.Serve(ctx)
+	}()
+
+	addrs := s.Addrs()
+	if len(addrs) != 2 || addrs[0] != "unix://"+socket || !strings.HasPrefix(addrs[1], "tcp://127.0.0.1:") {
+		t.Fatalf("unexpected listen addresses: %v", addrs)
+	}
+	fi, err := os.Stat(socket)
+	if err != nil {
+		t.Fatalf("unable to stat socket: %s", err)
+	}
+	if got := fi.Mode().Perm(); got != 0o600 {
+		t.Fatalf("unexpected socket mode: got %#o, want %#o", got, 0o600)
+	}
+
+	clients := map[string]struct {
+		client *http.Client
+		url    string
+	}{
+		"unix": {client: unixClient(socket), url: "http://unix/api/toolset"},
+		"tcp":  {client: http.DefaultClient, url: "http://" + strings.TrimPrefix(addrs[1], "tcp://") + "/api/toolset"},
+	}
+	for name, c := range clients {
+		t.Run(name, func(t *testing.T) {
+			resp, err := c.client.Get(c.url)
+			if err != nil {
+				t.Fatalf("error when sending a request: %s", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("response status code is not 200, got %d", resp.StatusCode)
+			}
+		})
+	}
+
+	if err := s.Shutdown(ctx); err != nil {
+		t.Fatalf("unable to shut down server: %s", err)
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		t.Fatalf("unexpected error from Serve: %v", err)
+	}
+	if _, err := os.Stat(socket); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected socket to be removed on shutdown, got: %v", err)
+	}
+}
+
+func TestListenStaleUnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "toolbox.sock")
+
+	// leave a socket behind that nothing is accepting connections on
+	l, err := net.ListenUnix("unix", &net.UnixAddr{Name: socket, Net: "unix"})
+	if err != nil {
+		t.Fatalf("unable to create socket: %s", err)
+	}
+	l.SetUnlinkOnClose(false)
+	l.Close()
+	if _, err := os.Stat(socket); err != nil {
+		t.Fatalf("expected stale socket to exist: %s", err)
+	}
+
+	ctx, s := newListenTestServer(t, server.ServerConfig{Version: "0.0.0", Listen: []string{"unix://" + socket}})
+	if err := s.Listen(ctx); err != nil {
+		t.Fatalf("unable to start server over stale socket: %v", err)
+	}
+	go func() { _ = s.Serve(ctx) }()
+	defer func() { _ = s.Shutdown(ctx) }()
+
+	resp, err := unixClient(socket).Get("http://unix/")
+	if err != nil {
+		t.Fatalf("error when sending a request: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("response status code is not 200, got %d", resp.StatusCode)
+	}
+}
+
+func TestListenUnixSocketFailure(t *testing.T) {
+	dir := t.TempDir()
+
+	inUse := filepath.Join(dir, "in-use.sock")
+	l, err := net.Listen("unix", inUse)
+	if err != nil {
+		t.Fatalf("unable to create socket: %s", err)
+	}
+	defer l.Close()
+
+	notSocket := filepath.Join(dir, "file.sock")
+	if err := os.WriteFile(notSocket, []byte("data"), 0o600); err != nil {
+		t.Fatalf("unable to write file: %s", err)
+	}
+
+	tcs := []struct {
+		desc string
+		path string
+		want string
+	}{
+		{
+			desc: "socket in use",
+			path: inUse,
+			want: "is already in use",
+		},
+		{
+			desc: "not a socket",
+			path: notSocket,
+			want: "is not a socket",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx, s := newListenTestServer(t, server.ServerConfig{Version: "0.0.0", Listen: []string{"unix://" + tc.path}})
+			err := s.Listen(ctx)
+			if err == nil {
+				t.Fatalf("expected an error, but got nil")
+			}
+			if !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("unexpected error: got %q, want to contain %q", err, tc.want)
+			}
+			if _, err := os.Stat(tc.path); err != nil {
+				t.Fatalf("expected %q to be left in place: %s", tc.path, err)
+			}
+		})
+	}
+}
+
+func TestNewServerInvalidListenAddress(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("error setting up logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation("0.0.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx = util.WithInstrumentation(ctx, instrumentation)
+
+	tcs := []struct {
+		desc string
+		addr string
+		want string
+	}{
+		{
+			desc: "unsupported scheme",
+			addr: "udp://127.0.0.1:5000",
+			want: `invalid listen address "udp://127.0.0.1:5000": scheme must be one of "tcp://" or "unix://"`,
+		},
+		{
+			desc: "missing socket path",
+			addr: "unix://",
+			want: `invalid listen address "unix://": missing socket path`,
+		},
+		{
+			desc: "missing port",
+			addr: "tcp://127.0.0.1",
+			want: `invalid listen address "tcp://127.0.0.1": address 127.0.0.1: missing port in address`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := server.NewServer(ctx, server.ServerConfig{Version: "0.0.0", Listen: []string{tc.addr}})
+			if err == nil {
+				t.Fatalf("expected an error, but got nil")
+			}
+			if err.Error() != tc.want {
+				t.Fatalf("unexpected error: got %q, want %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestNewServerUnknownAdminAuthService(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("error setting up logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation("0.0.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx = util.WithInstrumentation(ctx, instrumentation)
+
+	_, err = server.NewServer(ctx, server.ServerConfig{Version: "0.0.0", AdminAuthService: "my-admin-auth"})
+	want := `admin auth service "my-admin-auth" is not configured`
+	if err == nil || err.Error() != want {
+		t.Fatalf("unexpected error: got %v, want %q", err, want)
+	}
+}
+
+func TestNewServerAdminAuthServiceWithoutAllowList(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("error setting up logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation("0.0.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx = util.WithInstrumentation(ctx, instrumentation)
+
+	_, err = server.NewServer(ctx, server.ServerConfig{
+		Version:          "0.0.0",
+		AdminAuthService: "my-admin-auth",
+		AuthServiceConfigs: server.AuthServiceConfigs{
+			"my-admin-auth": google.Config{Name: "my-admin-auth", Kind: google.AuthServiceKind, ClientID: "my-client-id"},
+		},
+	})
+	want := `admin auth service "my-admin-auth" requires admin subjects or admin emails to allow`
+	if err == nil || err.Error() != want {
+		t.Fatalf("unexpected error: got %v, want %q", err, want)
+	}
+}
+
+// fakeSourceConfig is a source that takes delay to initialize, and tracks how
+// many sources initialize at once.
+type fakeSourceConfig struct {
+	delay time.Duration
+	// block makes the source ignore its context and never finish initializing.
+	block   bool
+	err     error
+	running *atomic.Int32
+	peak    *atomic.Int32
+}
+
+func (c fakeSourceConfig) SourceConfigKind() string {
+	return "fake"
+}
+
+func (c fakeSourceConfig) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
+	if c.running != nil {
+		n := c.running.Add(1)
+		defer c.running.Add(-1)
+		for p := c.peak.Load(); n > p && !c.peak.CompareAndSwap(p, n); p = c.peak.Load() {
+		}
+	}
+	if c.block {
+		select {}
+	}
+	time.Sleep(c.delay)
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &alloydbpg.Source{Kind: "fake"}, nil
+}
+
+func newInitTestContext(t *testing.T) context.Context {
+	t.Helper()
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("error setting up logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation("0.0.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return util.WithInstrumentation(ctx, instrumentation)
+}
+
+func TestInitializeSourcesInParallel(t *testing.T) {
+	ctx := newInitTestContext(t)
+	tcs := []struct {
+		desc        string
+		concurrency int
+		wantPeak    int32
+		maxElapsed  time.Duration
+	}{
+		{desc: "all at once", concurrency: 4, wantPeak: 4, maxElapsed: 350 * time.Millisecond},
+		{desc: "bounded", concurrency: 2, wantPeak: 2, maxElapsed: 550 * time.Millisecond},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			var running, peak atomic.Int32
+			cfg := server.ServerConfig{Version: "0.0.0", SourceConfigs: server.SourceConfigs{}, SourceInitConcurrency: tc.concurrency}
+			for i := range 4 {
+				cfg.SourceConfigs[fmt.Sprintf("source-%d", i)] = fakeSourceConfig{delay: 200 * time.Millisecond, running: &running, peak: &peak}
+			}
+
+			start := time.Now()
+			srcs, _, _, _, err := server.InitializeConfigs(ctx, cfg)
+			elapsed := time.Since(start)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if len(srcs) != 4 {
+				t.Fatalf("expected 4 sources, got %d", len(srcs))
+			}
+			if got := peak.Load(); got != tc.wantPeak {
+				t.Fatalf("expected %d sources to initialize at once, got %d", tc.wantPeak, got)
+			}
+			if elapsed > tc.maxElapsed {
+				t.Fatalf("expected sources to initialize within %s, took %s", tc.maxElapsed, elapsed)
+			}
+		})
+	}
+}
+
+func TestInitializeSourcesFailures(t *testing.T) {
+	ctx := newInitTestContext(t)
+	cfg := server.ServerConfig{
+		Version: "0.0.0",
+		SourceConfigs: server.SourceConfigs{
+			"healthy": fakeSourceConfig{},
+			"hanging": fakeSourceConfig{block: true},
+			"broken":  fakeSourceConfig{err: errors.New("connection refused")},
+		},
+		SourceInitTimeout: 100 * time.Millisecond,
+	}
+
+	start := time.Now()
+	_, _, _, _, err := server.InitializeConfigs(ctx, cfg)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected a hanging source to time out, took %s", elapsed)
+	}
+	want := `unable to initialize source "broken": connection refused` + "\n" +
+		`unable to initialize source "hanging": timed out after 100ms`
+	if err == nil || err.Error() != want {
+		t.Fatalf("unexpected error: got %v, want %q", err, want)
+	}
+}
+
+func TestLazyInitSource(t *testing.T) {
+	ctx := newInitTestContext(t)
+	// nothing listens on port 1, so connecting fails
+	in := `
+	sources:
+		my-pg:
+			kind: postgres
+			host: 127.0.0.1
+			port: 1
+			user: user
+			password: password
+			database: db
+			lazyInit: %t
+	tools:
+		my-query:
+			kind: postgres-sql
+			source: my-pg
+			description: some description
+			statement: SELECT 1
+	`
+	parse := func(lazy bool) server.ServerConfig {
+		got := struct {
+			Sources server.SourceConfigs `yaml:"sources"`
+			Tools   server.ToolConfigs   `yaml:"tools"`
+		}{}
+		if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(fmt.Sprintf(in, lazy)), &got); err != nil {
+			t.Fatalf("unable to unmarshal: %s", err)
+		}
+		return server.ServerConfig{Version: "0.0.0", SourceConfigs: got.Sources, ToolConfigs: got.Tools}
+	}
+
+	if _, _, _, _, err := server.InitializeConfigs(ctx, parse(false)); err == nil {
+		t.Fatalf("expected an unreachable source to fail to initialize")
+	}
+
+	cfg := parse(true)
+	if _, ok := cfg.SourceConfigs["my-pg"].(sources.LazyConfig); !ok {
+		t.Fatalf("expected a lazy source config, got %T", cfg.SourceConfigs["my-pg"])
+	}
+	_, _, toolsMap, _, err := server.InitializeConfigs(ctx, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// the first invocation connects, and fails as the source is down
+	tool := toolsMap["my-query"]
+	params, err := tool.ParseParams(map[string]any{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := tool.Invoke(ctx, tools.Invocation{Params: params}); err == nil || !strings.Contains(err.Error(), "connect") {
+		t.Fatalf("expected invocation to fail to connect, got %v", err)
+	}
+}
+
+func TestInitializeDescriptionTemplates(t *testing.T) {
+	ctx := newInitTestContext(t)
+	in := `
+	sources:
+		my-pg:
+			kind: postgres
+			host: db.internal
+			port: 1
+			user: user
+			password: hunter2
+			database: orders
+			lazyInit: true
+	tools:
+		my-query:
+			kind: postgres-sql
+			source: my-pg
+			description: Query the {{.source.database}} database on {{.source.host}}.
+			statement: SELECT 1
+		%s
+	`
+	parse := func(extra string) server.ServerConfig {
+		got := struct {
+			Sources server.SourceConfigs `yaml:"sources"`
+			Tools   server.ToolConfigs   `yaml:"tools"`
+		}{}
+		if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(fmt.Sprintf(in, extra)), &got); err != nil {
+			t.Fatalf("unable to unmarshal: %s", err)
+		}
+		return server.ServerConfig{Version: "0.0.0", SourceConfigs: got.Sources, ToolConfigs: got.Tools}
+	}
+
+	_, _, toolsMap, toolsets, err := server.InitializeConfigs(ctx, parse(""))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := "Query the orders database on db.internal."
+	if got := toolsMap["my-query"].Manifest().Description; got != want {
+		t.Fatalf("unexpected description: got %q, want %q", got, want)
+	}
+	if got := toolsMap["my-query"].McpManifest().Description; got != want {
+		t.Fatalf("unexpected mcp description: got %q, want %q", got, want)
+	}
+	if got := toolsets[""].Manifest.ToolsManifest["my-query"].Description; got != want {
+		t.Fatalf("unexpected toolset description: got %q, want %q", got, want)
+	}
+
+	// the password of the source is not exposed to templates
+	_, _, _, _, err = server.InitializeConfigs(ctx, parse(`my-leak:
+			kind: postgres-sql
+			source: my-pg
+			description: Connect with {{.source.password}}.
+			statement: SELECT 1`))
+	wantErr := `unable to initialize tool "my-leak"`
+	if err == nil || !strings.Contains(err.Error(), wantErr) || !strings.Contains(err.Error(), `map has no entry for key "password"`) {
+		t.Fatalf("unexpected error: got %v, want %q", err, wantErr)
+	}
+	if strings.Contains(err.Error(), "hunter2") {
+		t.Fatalf("expected the error not to expose the password: %s", err)
+	}
+}
+
+func TestFailParseLazyInit(t *testing.T) {
+	ctx := newInitTestContext(t)
+	tcs := []struct {
+		desc string
+		in   string
+		err  string
+	}{
+		{
+			desc: "unsupported kind",
+			in: `
+			sources:
+				my-http:
+					kind: http
+					baseUrl: http://example.com
+					lazyInit: true
+			`,
+			err: `source "my-http" of kind "http" does not support 'lazyInit'`,
+		},
+		{
+			desc: "not a boolean",
+			in: `
+			sources:
+				my-pg:
+					kind: postgres
+					host: 127.0.0.1
+					port: 5432
+					user: user
+					password: password
+					database: db
+					lazyInit: sometimes
+			`,
+			err: `invalid 'lazyInit' field for source "my-pg" (must be a boolean)`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Sources server.SourceConfigs `yaml:"sources"`
+			}{}
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestInitializeCircuitBreakers(t *testing.T) {
+	ctx := newInitTestContext(t)
+	// nothing listens on port 1, so every invocation fails
+	in := `
+	sources:
+		my-pg:
+			kind: postgres
+			host: 127.0.0.1
+			port: 1
+			user: user
+			password: password
+			database: db
+			lazyInit: true
+			circuitBreaker:
+				failureThreshold: 2
+				window: 1m
+	tools:
+		my-query:
+			kind: postgres-sql
+			source: my-pg
+			description: some description
+			statement: SELECT 1
+			aliases:
+				- my-old-query
+		my-own:
+			kind: postgres-sql
+			source: my-pg
+			description: some description
+			statement: SELECT 2
+			circuitBreaker:
+				failureThreshold: 1
+				openDuration: 1m
+	`
+	got := struct {
+		Sources server.SourceConfigs `yaml:"sources"`
+		Tools   server.ToolConfigs   `yaml:"tools"`
+	}{}
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	cfg := server.ServerConfig{Version: "0.0.0", SourceConfigs: got.Sources, ToolConfigs: got.Tools}
+	_, _, toolsMap, _, err := server.InitializeConfigs(ctx, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	invoke := func(name string) error {
+		t.Helper()
+		tool := toolsMap[name]
+		params, err := tool.ParseParams(map[string]any{}, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		_, err = tool.Invoke(ctx, tools.Invocation{Params: params})
+		return err
+	}
+	// the tool and its alias share the breaker of the source
+	for _, name := range []string{"my-query", "my-old-query"} {
+		if err := invoke(name); err == nil || errors.Is(err, tools.ErrBackendUnavailable) {
+			t.Fatalf("expected invocation to fail to connect, got %v", err)
+		}
+	}
+	var openErr *tools.CircuitOpenError
+	if err := invoke("my-query"); !errors.As(err, &openErr) || openErr.Scope != tools.BreakerScopeSource || openErr.Name != "my-pg" {
+		t.Fatalf("unexpected error: got %v, want the breaker of the source to be open", err)
+	}
+
+	// tools with a breaker of their own do not share the breaker of the source
+	if err := invoke("my-own"); err == nil || errors.Is(err, tools.ErrBackendUnavailable) {
+		t.Fatalf("expected invocation to fail to connect, got %v", err)
+	}
+	if err := invoke("my-own"); !errors.As(err, &openErr) || openErr.Scope != tools.BreakerScopeTool || openErr.Name != "my-own" {
+		t.Fatalf("unexpected error: got %v, want the breaker of the tool to be open", err)
+	}
+}
+
+func TestFailParseCircuitBreaker(t *testing.T) {
+	ctx := newInitTestContext(t)
+	tcs := []struct {
+		desc string
+		in   string
+		err  string
+	}{
+		{
+			desc: "invalid source window",
+			in: `
+			sources:
+				my-pg:
+					kind: postgres
+					host: 127.0.0.1
+					port: 5432
+					user: user
+					password: password
+					database: db
+					circuitBreaker:
+						window: soon
+			`,
+			err: `invalid 'circuitBreaker' field for source "my-pg": invalid window "soon"`,
+		},
+		{
+			desc: "unknown tool field",
+			in: `
+			tools:
+				my-query:
+					kind: postgres-sql
+					source: my-pg
+					description: some description
+					statement: SELECT 1
+					circuitBreaker:
+						threshold: 3
+			`,
+			err: `invalid 'circuitBreaker' field for tool "my-query"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Sources server.SourceConfigs `yaml:"sources"`
+				Tools   server.ToolConfigs   `yaml:"tools"`
+			}{}
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestInitializeDisabledToolKinds(t *testing.T) {
+	ctx := newInitTestContext(t)
+	in := `
+	sources:
+		my-pg:
+			kind: postgres
+			host: 127.0.0.1
+			port: 1
+			user: user
+			password: password
+			database: db
+			lazyInit: true
+	tools:
+		my-query:
+			kind: postgres-sql
+			source: my-pg
+			description: some description
+			statement: SELECT 1
+		my-exec:
+			kind: postgres-execute-sql
+			source: my-pg
+			description: some description
+	`
+	got := struct {
+		Sources server.SourceConfigs `yaml:"sources"`
+		Tools   server.ToolConfigs   `yaml:"tools"`
+	}{}
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+
+	for _, hide := range []bool{false, true} {
+		t.Run(fmt.Sprintf("hide %t", hide), func(t *testing.T) {
+			cfg := server.ServerConfig{
+				Version:           "0.0.0",
+				SourceConfigs:     got.Sources,
+				ToolConfigs:       got.Tools,
+				DisabledToolKinds: []string{"postgres-execute-sql"},
+				HideDisabledTools: hide,
+			}
+			_, _, toolsMap, toolsets, err := server.InitializeConfigs(ctx, cfg)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			exec := toolsMap["my-exec"]
+			params, err := exec.ParseParams(map[string]any{"sql": "DROP TABLE t"}, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			_, err = exec.Invoke(ctx, tools.Invocation{Params: params})
+			var disabledErr *tools.KindDisabledError
+			if !errors.As(err, &disabledErr) || disabledErr.Kind != "postgres-execute-sql" {
+				t.Fatalf("unexpected error: got %v, want a KindDisabledError", err)
+			}
+			if toolsMap["my-query"].Manifest().Disabled {
+				t.Fatalf("tools of other kinds must not be disabled")
+			}
+
+			manifests := toolsets[""].Manifest.ToolsManifest
+			if _, ok := manifests["my-query"]; !ok {
+				t.Fatalf("expected my-query to be listed")
+			}
+			m, ok := manifests["my-exec"]
+			if hide == ok {
+				t.Fatalf("unexpected listing of my-exec: got %t, want %t", ok, !hide)
+			}
+			if !hide && !m.Disabled {
+				t.Fatalf("expected my-exec to be marked as disabled")
+			}
+		})
+	}
+}
+
+func TestInitializeInheritedAuthRequired(t *testing.T) {
+	ctx := newInitTestContext(t)
+	in := `
+	sources:
+		my-pg:
+			kind: postgres
+			host: 127.0.0.1
+			port: 1
+			user: user
+			password: password
+			database: db
+			lazyInit: true
+	tools:
+		my-query:
+			kind: postgres-sql
+			source: my-pg
+			description: some description
+			statement: SELECT 1
+		my-own:
+			kind: postgres-sql
+			source: my-pg
+			description: some description
+			statement: SELECT 1
+			authRequired:
+				- my-github
+		my-other:
+			kind: postgres-sql
+			source: my-pg
+			description: some description
+			statement: SELECT 1
+		my-explicit:
+			kind: postgres-sql
+			source: my-pg
+			description: some description
+			statement: SELECT 1
+			authRequired: []
+		my-aliased:
+			kind: postgres-sql
+			source: my-pg
+			description: some description
+			statement: SELECT 1
+			aliases:
+				- my-old
+		my-public:
+			kind: postgres-sql
+			source: my-pg
+			description: some description
+			statement: SELECT 1
+			authRequired: []
+			allowUnauthenticated: true
+	toolsets:
+		production:
+			tools:
+				- my-query
+				- my-own
+				- my-old
+				- my-public
+			authRequired:
+				- my-google
+		audited:
+			tools:
+				- my-own
+			authRequired:
+				- my-audit
+				- my-google
+		staging:
+			- my-other
+	`
+	got := struct {
+		Sources  server.SourceConfigs  `yaml:"sources"`
+		Tools    server.ToolConfigs    `yaml:"tools"`
+		Toolsets server.ToolsetConfigs `yaml:"toolsets"`
+	}{}
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+
+	tcs := []struct {
+		desc                string
+		defaultAuthRequired []string
+		want                map[string][]string
+		// authorizedWith holds the fewest authServices authorizing each tool
+		authorizedWith map[string][]string
+	}{
+		{
+			desc: "toolset authRequired",
+			want: map[string][]string{
+				"my-query":    {"my-google"},
+				"my-own":      {"my-github", "my-audit", "my-google"},
+				"my-other":    {},
+				"my-explicit": {},
+				"my-aliased":  {"my-google"},
+				"my-old":      {"my-google"},
+				"my-public":   {},
+			},
+			authorizedWith: map[string][]string{
+				"my-query":   {"my-google"},
+				"my-own":     {"my-github", "my-google"},
+				"my-aliased": {"my-google"},
+				"my-old":     {"my-google"},
+			},
+		},
+		{
+			desc:                "toolset and default authRequired",
+			defaultAuthRequired: []string{"my-corp"},
+			want: map[string][]string{
+				"my-query":    {"my-google"},
+				"my-own":      {"my-github", "my-audit", "my-google"},
+				"my-other":    {"my-corp"},
+				"my-explicit": {"my-corp"},
+				"my-aliased":  {"my-google"},
+				"my-old":      {"my-google"},
+				"my-public":   {},
+			},
+			authorizedWith: map[string][]string{
+				"my-query":    {"my-google"},
+				"my-own":      {"my-github", "my-google"},
+				"my-other":    {"my-corp"},
+				"my-explicit": {"my-corp"},
+				"my-aliased":  {"my-google"},
+				"my-old":      {"my-google"},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg := server.ServerConfig{
+				Version:             "0.0.0",
+				SourceConfigs:       got.Sources,
+				ToolConfigs:         got.Tools,
+				ToolsetConfigs:      got.Toolsets,
+				DefaultAuthRequired: tc.defaultAuthRequired,
+			}
+			_, _, toolsMap, toolsets, err := server.InitializeConfigs(ctx, cfg)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			for name, want := range tc.want {
+				tool := toolsMap[name]
+				if diff := cmp.Diff(want, tool.Manifest().AuthRequired); diff != "" {
+					t.Fatalf("incorrect authRequired of %q: diff %v", name, diff)
+				}
+				authInvoke := tool.McpManifest().Metadata["toolbox/authInvoke"]
+				if len(want) == 0 && authInvoke != nil {
+					t.Fatalf("unexpected authInvoke of %q: %v", name, authInvoke)
+				}
+				if len(want) > 0 && !cmp.Equal(want, authInvoke) {
+					t.Fatalf("incorrect authInvoke of %q: got %v, want %v", name, authInvoke, want)
+				}
+				if tool.Authorized(nil) != (len(want) == 0) {
+					t.Fatalf("unexpected authorization of %q without authServices", name)
+				}
+				authorizedWith := tc.authorizedWith[name]
+				if !tool.Authorized(authorizedWith) {
+					t.Fatalf("expected %q to be authorized with %q", name, authorizedWith)
+				}
+				// the authServices of its toolsets are required on top of its own
+				for i := range authorizedWith {
+					without := slices.Delete(slices.Clone(authorizedWith), i, i+1)
+					if tool.Authorized(without) {
+						t.Fatalf("unexpected authorization of %q with %q", name, without)
+					}
+				}
+			}
+			// the tools require the same authServices in every toolset
+			for _, name := range []string{"", "production", "staging"} {
+				for toolName, m := range toolsets[name].Manifest.ToolsManifest {
+					if diff := cmp.Diff(tc.want[toolName], m.AuthRequired); diff != "" {
+						t.Fatalf("incorrect authRequired of %q in toolset %q: diff %v", toolName, name, diff)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestFailParseAllowUnauthenticated(t *testing.T) {
+	ctx := newInitTestContext(t)
+	tcs := []struct {
+		desc string
+		in   string
+		err  string
+	}{
+		{
+			desc: "without authRequired",
+			in: `
+			tools:
+				my-query:
+					kind: postgres-sql
+					source: my-pg
+					description: some description
+					statement: SELECT 1
+					allowUnauthenticated: true
+			`,
+			err: "`allowUnauthenticated` requires an explicit empty `authRequired` for tool \"my-query\"",
+		},
+		{
+			desc: "with authRequired",
+			in: `
+			tools:
+				my-query:
+					kind: postgres-sql
+					source: my-pg
+					description: some description
+					statement: SELECT 1
+					authRequired:
+						- my-google
+					allowUnauthenticated: true
+			`,
+			err: "`allowUnauthenticated` requires an explicit empty `authRequired` for tool \"my-query\"",
+		},
+		{
+			desc: "not a boolean",
+			in: `
+			tools:
+				my-query:
+					kind: postgres-sql
+					source: my-pg
+					description: some description
+					statement: SELECT 1
+					authRequired: []
+					allowUnauthenticated: yes please
+			`,
+			err: `invalid 'allowUnauthenticated' field for tool "my-query" (must be a boolean)`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestInitializePoolExhaustionTool(t *testing.T) {
+	ctx := newInitTestContext(t)
+	in := `
+	sources:
+		my-pg:
+			kind: postgres
+			host: 127.0.0.1
+			port: 1
+			user: user
+			password: password
+			database: db
+			maxConns: 1
+			lazyInit: true
+	tools:
+		my-query:
+			kind: postgres-sql
+			source: my-pg
+			description: some description
+			statement: SELECT 1
+	`
+	got := struct {
+		Sources server.SourceConfigs `yaml:"sources"`
+		Tools   server.ToolConfigs   `yaml:"tools"`
+	}{}
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	_, _, toolsMap, _, err := server.InitializeConfigs(ctx, server.ServerConfig{Version: "0.0.0", SourceConfigs: got.Sources, ToolConfigs: got.Tools})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	pt, ok := tools.As[tools.PoolExhaustionTool](toolsMap["my-query"])
+	if !ok {
+		t.Fatalf("expected the tools of pooled sources to tell exhausted pools")
+	}
+	if pt.SourceName != "my-pg" {
+		t.Fatalf("unexpected source name: got %q, want %q", pt.SourceName, "my-pg")
+	}
+
+	// connection failures are not reported as an exhausted pool
+	params, err := toolsMap["my-query"].ParseParams(map[string]any{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	invokeCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	_, err = toolsMap["my-query"].Invoke(invokeCtx, tools.Invocation{Params: params})
+	var poolErr *tools.PoolExhaustedError
+	if err == nil || errors.As(err, &poolErr) {
+		t.Fatalf("unexpected error: got %v, want a connection error", err)
+	}
+}
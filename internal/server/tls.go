@@ -0,0 +1,200 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/googleapis/genai-toolbox/internal/auth/clientcert"
+	"github.com/googleapis/genai-toolbox/internal/log"
+)
+
+const (
+	// TLSClientAuthVerifyIfGiven verifies the client certificates that
+	// clients present, without requiring one.
+	TLSClientAuthVerifyIfGiven = "verifyIfGiven"
+	// TLSClientAuthRequireAndVerify rejects the clients that do not present a
+	// certificate signed by one of the client CAs.
+	TLSClientAuthRequireAndVerify = "requireAndVerify"
+)
+
+// tlsCredentials are the certificate of the server and the CAs its client
+// certificates are verified against, loaded from files that can be reloaded
+// while the server is running.
+type tlsCredentials struct {
+	certFile     string
+	keyFile      string
+	clientCAFile string
+	clientAuth   tls.ClientAuthType
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+}
+
+// newTLSCredentials validates the TLS settings of cfg and loads their files.
+// It returns nil if TLS is not configured.
+func newTLSCredentials(cfg ServerConfig) (*tlsCredentials, error) {
+	if cfg.TLSCertFile == "" && cfg.TLSKeyFile == "" {
+		if cfg.TLSClientCAFile != "" || cfg.TLSClientAuth != "" || cfg.TLSReload {
+			return nil, fmt.Errorf("client certificates and TLS reload require a TLS certificate and key")
+		}
+		return nil, nil
+	}
+	if cfg.TLSCertFile == "" || cfg.TLSKeyFile == "" {
+		return nil, fmt.Errorf("both a TLS certificate and a TLS key are required")
+	}
+
+	c := &tlsCredentials{
+		certFile:     cfg.TLSCertFile,
+		keyFile:      cfg.TLSKeyFile,
+		clientCAFile: cfg.TLSClientCAFile,
+		clientAuth:   tls.NoClientCert,
+	}
+	switch cfg.TLSClientAuth {
+	case "":
+		if c.clientCAFile != "" {
+			c.clientAuth = tls.RequireAndVerifyClientCert
+		}
+	case TLSClientAuthVerifyIfGiven:
+		c.clientAuth = tls.VerifyClientCertIfGiven
+	case TLSClientAuthRequireAndVerify:
+		c.clientAuth = tls.RequireAndVerifyClientCert
+	default:
+		return nil, fmt.Errorf("invalid TLS client auth %q: must be %q or %q", cfg.TLSClientAuth, TLSClientAuthVerifyIfGiven, TLSClientAuthRequireAndVerify)
+	}
+	if c.clientAuth != tls.NoClientCert && c.clientCAFile == "" {
+		return nil, fmt.Errorf("TLS client auth %q requires a client CA file", cfg.TLSClientAuth)
+	}
+	if err := c.reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// reload loads the files of c. On error, c keeps its previous credentials.
+func (c *tlsCredentials) reload() error {
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return fmt.Errorf("unable to load TLS certificate: %w", err)
+	}
+	var clientCAs *x509.CertPool
+	if c.clientCAFile != "" {
+		pem, err := os.ReadFile(c.clientCAFile)
+		if err != nil {
+			return fmt.Errorf("unable to read TLS client CA file: %w", err)
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in TLS client CA file %q", c.clientCAFile)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cert = &cert
+	c.clientCAs = clientCAs
+	return nil
+}
+
+// config returns the TLS configuration of the listeners. Each handshake uses
+// the credentials of c at the time, so that reloads apply to new connections.
+func (c *tlsCredentials) config() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			c.mu.RLock()
+			defer c.mu.RUnlock()
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*c.cert},
+				ClientAuth:   c.clientAuth,
+				ClientCAs:    c.clientCAs,
+				// websocket upgrades of the MCP endpoint need HTTP/1.1
+				NextProtos: []string{"http/1.1"},
+			}, nil
+		},
+	}
+}
+
+// watch reloads the credentials of c whenever the directories of their files
+// change, until ctx is done. Directories are watched rather than files, so
+// that certificates replaced by a rename, such as mounted Kubernetes secrets,
+// are picked up.
+func (c *tlsCredentials) watch(ctx context.Context, logger log.Logger) {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		logger.WarnContext(ctx, fmt.Sprintf("unable to watch TLS files, they will not be reloaded: %s", err))
+		return
+	}
+	defer w.Close()
+
+	var dirs []string
+	for _, f := range []string{c.certFile, c.keyFile, c.clientCAFile} {
+		if dir := filepath.Dir(f); f != "" && !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	for _, dir := range dirs {
+		if err := w.Add(dir); err != nil {
+			logger.WarnContext(ctx, fmt.Sprintf("unable to watch %q, TLS files will not be reloaded: %s", dir, err))
+			return
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err, ok := <-w.Errors:
+			if !ok {
+				return
+			}
+			logger.WarnContext(ctx, fmt.Sprintf("error watching TLS files: %s", err))
+		case _, ok := <-w.Events:
+			if !ok {
+				return
+			}
+			// the files may be written in several steps, so a failed reload
+			// is retried on the next event
+			if err := c.reload(); err != nil {
+				logger.WarnContext(ctx, fmt.Sprintf("unable to reload TLS files, keeping the previous ones: %s", err))
+				continue
+			}
+			logger.InfoContext(ctx, "reloaded TLS files")
+		}
+	}
+}
+
+// clientCertificateContext adds the client certificate verified during the
+// TLS handshake of a request to its context, for client-certificate auth
+// services.
+func clientCertificateContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0 {
+			r = r.WithContext(clientcert.WithCertificate(r.Context(), r.TLS.VerifiedChains[0][0]))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,408 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/auth/clientcert"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/tools/utility/mock"
+)
+
+// testCA issues the certificates of the TLS tests.
+type testCA struct {
+	cert   *x509.Certificate
+	key    *ecdsa.PrivateKey
+	pem    []byte
+	serial int64
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unable to generate key: %s", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "toolbox test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("unable to create CA certificate: %s", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("unable to parse CA certificate: %s", err)
+	}
+	return &testCA{
+		cert:   cert,
+		key:    key,
+		pem:    pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		serial: 1,
+	}
+}
+
+// issue returns a certificate for commonName signed by ca, as PEM, along with
+// its serial number. Server certificates are valid for 127.0.0.1.
+func (ca *testCA) issue(t *testing.T, commonName string, client bool) (certPEM, keyPEM []byte, serial int64) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unable to generate key: %s", err)
+	}
+	ca.serial++
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(ca.serial),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	if client {
+		tmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+		tmpl.IPAddresses = nil
+		tmpl.DNSNames = []string{commonName + ".agents.example.com"}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("unable to create certificate: %s", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("unable to marshal key: %s", err)
+	}
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM, ca.serial
+}
+
+// tlsFiles are the files of the TLS configuration of a test server.
+type tlsFiles struct {
+	cert, key, clientCA string
+}
+
+// writeServerFiles writes a new server certificate issued by ca to dir, and
+// returns its serial number.
+func writeServerFiles(t *testing.T, ca *testCA, files tlsFiles) int64 {
+	t.Helper()
+	certPEM, keyPEM, serial := ca.issue(t, "toolbox", false)
+	// the key is written first, so that a reload triggered by the
+	// certificate never pairs it with the previous key
+	if err := os.WriteFile(files.key, keyPEM, 0o600); err != nil {
+		t.Fatalf("unable to write key: %s", err)
+	}
+	if err := os.WriteFile(files.cert, certPEM, 0o600); err != nil {
+		t.Fatalf("unable to write certificate: %s", err)
+	}
+	return serial
+}
+
+func newTLSFiles(t *testing.T, ca *testCA) tlsFiles {
+	t.Helper()
+	dir := t.TempDir()
+	files := tlsFiles{
+		cert:     filepath.Join(dir, "server.pem"),
+		key:      filepath.Join(dir, "server-key.pem"),
+		clientCA: filepath.Join(dir, "ca.pem"),
+	}
+	writeServerFiles(t, ca, files)
+	if err := os.WriteFile(files.clientCA, ca.pem, 0o600); err != nil {
+		t.Fatalf("unable to write CA: %s", err)
+	}
+	return files
+}
+
+// tlsClient returns a client trusting ca, presenting the client certificate
+// of commonName issued by ca unless commonName is empty.
+func tlsClient(t *testing.T, ca *testCA, commonName string) *http.Client {
+	t.Helper()
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	cfg := &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}
+	if commonName != "" {
+		certPEM, keyPEM, _ := ca.issue(t, commonName, true)
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			t.Fatalf("unable to load client certificate: %s", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: cfg, DisableKeepAlives: true}}
+}
+
+// startTLSServer starts a server with cfg on a random port, and returns its
+// base URL.
+func startTLSServer(t *testing.T, cfg server.ServerConfig) string {
+	t.Helper()
+	cfg.Version = "0.0.0"
+	cfg.Listen = []string{"tcp://127.0.0.1:0"}
+	cfg.AuthServiceConfigs = server.AuthServiceConfigs{
+		"my-mtls": clientcert.Config{Name: "my-mtls", Kind: clientcert.AuthServiceKind},
+	}
+	cfg.ToolConfigs = server.ToolConfigs{
+		"my-mtls-tool": mock.Config{
+			Name:         "my-mtls-tool",
+			Kind:         "mock",
+			Description:  "A tool requiring a client certificate.",
+			Default:      &mock.Response{Result: "completed successfully"},
+			AuthRequired: []string{"my-mtls"},
+		},
+	}
+	ctx, s := newListenTestServer(t, cfg)
+	ctx, cancel := context.WithCancel(ctx)
+	if err := s.Listen(ctx); err != nil {
+		t.Fatalf("unable to start server: %v", err)
+	}
+	go func() { _ = s.Serve(ctx) }()
+	t.Cleanup(func() {
+		_ = s.Shutdown(context.Background())
+		cancel()
+	})
+	if !s.TLSEnabled() {
+		t.Fatalf("expected TLS to be enabled")
+	}
+	return "https://" + strings.TrimPrefix(s.Addrs()[0], "tcp://")
+}
+
+// invokeMtlsTool invokes the tool requiring the client certificate auth
+// service, over HTTP and over MCP, and returns their responses.
+func invokeMtlsTool(t *testing.T, client *http.Client, url string) (int, string) {
+	t.Helper()
+	resp, err := client.Post(url+"/api/tool/my-mtls-tool/invoke", "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("unable to invoke tool: %s", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	mcpResp, err := client.Post(url+"/mcp", "application/json", strings.NewReader(`{"jsonrpc": "2.0", "id": "1", "method": "tools/call", "params": {"name": "my-mtls-tool", "arguments": {}}}`))
+	if err != nil {
+		t.Fatalf("unable to call tool over MCP: %s", err)
+	}
+	defer mcpResp.Body.Close()
+	mcpBody, _ := io.ReadAll(mcpResp.Body)
+	return resp.StatusCode, string(body) + "\n" + string(mcpBody)
+}
+
+func TestServeTLS(t *testing.T) {
+	ca := newTestCA(t)
+	files := newTLSFiles(t, ca)
+	url := startTLSServer(t, server.ServerConfig{TLSCertFile: files.cert, TLSKeyFile: files.key})
+
+	resp, err := tlsClient(t, ca, "").Get(url + "/api/toolset")
+	if err != nil {
+		t.Fatalf("unable to send request over TLS: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: got %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if resp.TLS == nil {
+		t.Fatalf("expected the response to be served over TLS")
+	}
+
+	// the TLS listener answers plain HTTP requests with a bad request
+	if resp, err := http.Get("http" + strings.TrimPrefix(url, "https") + "/api/toolset"); err == nil {
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected a plain HTTP request to fail, got status code %d", resp.StatusCode)
+		}
+	}
+
+	// without client CAs, no certificate verifies the caller
+	status, body := invokeMtlsTool(t, tlsClient(t, ca, ""), url)
+	if status != http.StatusUnauthorized {
+		t.Fatalf("unexpected status code: got %d, want %d: %s", status, http.StatusUnauthorized, body)
+	}
+}
+
+func TestServeMTLS(t *testing.T) {
+	ca := newTestCA(t)
+	files := newTLSFiles(t, ca)
+	url := startTLSServer(t, server.ServerConfig{
+		TLSCertFile:     files.cert,
+		TLSKeyFile:      files.key,
+		TLSClientCAFile: files.clientCA,
+	})
+
+	t.Run("client certificate", func(t *testing.T) {
+		status, body := invokeMtlsTool(t, tlsClient(t, ca, "agent-1"), url)
+		if status != http.StatusOK {
+			t.Fatalf("unexpected status code: got %d, want %d: %s", status, http.StatusOK, body)
+		}
+		if n := strings.Count(body, "completed successfully"); n != 2 {
+			t.Fatalf("expected the tool to succeed over HTTP and MCP, got: %s", body)
+		}
+	})
+	t.Run("missing client certificate", func(t *testing.T) {
+		resp, err := tlsClient(t, ca, "").Get(url + "/api/toolset")
+		if err == nil {
+			resp.Body.Close()
+			t.Fatalf("expected a request without a client certificate to fail, got status code %d", resp.StatusCode)
+		}
+	})
+	t.Run("untrusted client certificate", func(t *testing.T) {
+		other := newTestCA(t)
+		client := tlsClient(t, other, "agent-1")
+		client.Transport.(*http.Transport).TLSClientConfig.RootCAs.AddCert(ca.cert)
+		resp, err := client.Get(url + "/api/toolset")
+		if err == nil {
+			resp.Body.Close()
+			t.Fatalf("expected a request with an untrusted client certificate to fail, got status code %d", resp.StatusCode)
+		}
+	})
+}
+
+func TestServeMTLSVerifyIfGiven(t *testing.T) {
+	ca := newTestCA(t)
+	files := newTLSFiles(t, ca)
+	url := startTLSServer(t, server.ServerConfig{
+		TLSCertFile:     files.cert,
+		TLSKeyFile:      files.key,
+		TLSClientCAFile: files.clientCA,
+		TLSClientAuth:   server.TLSClientAuthVerifyIfGiven,
+	})
+
+	resp, err := tlsClient(t, ca, "").Get(url + "/api/toolset")
+	if err != nil {
+		t.Fatalf("unable to send request without a client certificate: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: got %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if status, body := invokeMtlsTool(t, tlsClient(t, ca, ""), url); status != http.StatusUnauthorized {
+		t.Fatalf("unexpected status code: got %d, want %d: %s", status, http.StatusUnauthorized, body)
+	}
+	if status, body := invokeMtlsTool(t, tlsClient(t, ca, "agent-1"), url); status != http.StatusOK {
+		t.Fatalf("unexpected status code: got %d, want %d: %s", status, http.StatusOK, body)
+	}
+}
+
+func TestServeTLSReload(t *testing.T) {
+	ca := newTestCA(t)
+	files := newTLSFiles(t, ca)
+	url := startTLSServer(t, server.ServerConfig{
+		TLSCertFile: files.cert,
+		TLSKeyFile:  files.key,
+		TLSReload:   true,
+	})
+	client := tlsClient(t, ca, "")
+	servedSerial := func() int64 {
+		resp, err := client.Get(url + "/")
+		if err != nil {
+			t.Fatalf("unable to send request: %s", err)
+		}
+		resp.Body.Close()
+		return resp.TLS.PeerCertificates[0].SerialNumber.Int64()
+	}
+
+	before := servedSerial()
+	rotated := writeServerFiles(t, ca, files)
+	if rotated == before {
+		t.Fatalf("expected a new certificate")
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for servedSerial() != rotated {
+		if time.Now().After(deadline) {
+			t.Fatalf("server still serves certificate %d, want rotated certificate %d", before, rotated)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	// a broken certificate is not picked up
+	if err := os.WriteFile(files.cert, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("unable to write certificate: %s", err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	if got := servedSerial(); got != rotated {
+		t.Fatalf("server serves certificate %d, want previous certificate %d", got, rotated)
+	}
+}
+
+func TestNewServerInvalidTLS(t *testing.T) {
+	ca := newTestCA(t)
+	files := newTLSFiles(t, ca)
+	tcs := []struct {
+		desc    string
+		cfg     server.ServerConfig
+		wantErr string
+	}{
+		{
+			desc:    "certificate without key",
+			cfg:     server.ServerConfig{TLSCertFile: files.cert},
+			wantErr: "both a TLS certificate and a TLS key are required",
+		},
+		{
+			desc:    "client CA without certificate",
+			cfg:     server.ServerConfig{TLSClientCAFile: files.clientCA},
+			wantErr: "require a TLS certificate and key",
+		},
+		{
+			desc:    "client auth without client CA",
+			cfg:     server.ServerConfig{TLSCertFile: files.cert, TLSKeyFile: files.key, TLSClientAuth: server.TLSClientAuthRequireAndVerify},
+			wantErr: "requires a client CA file",
+		},
+		{
+			desc:    "invalid client auth",
+			cfg:     server.ServerConfig{TLSCertFile: files.cert, TLSKeyFile: files.key, TLSClientCAFile: files.clientCA, TLSClientAuth: "always"},
+			wantErr: "invalid TLS client auth",
+		},
+		{
+			desc:    "missing certificate file",
+			cfg:     server.ServerConfig{TLSCertFile: files.cert + ".missing", TLSKeyFile: files.key},
+			wantErr: "unable to load TLS certificate",
+		},
+		{
+			desc:    "invalid client CA file",
+			cfg:     server.ServerConfig{TLSCertFile: files.cert, TLSKeyFile: files.key, TLSClientCAFile: files.key},
+			wantErr: "no certificates found",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			tc.cfg.Version = "0.0.0"
+			_, err := server.NewServer(newInitTestContext(t), tc.cfg)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("got error %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
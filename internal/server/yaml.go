@@ -0,0 +1,116 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/render"
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// yamlContentType is the content type of YAML manifests and invocation
+// arguments.
+const yamlContentType = "application/yaml"
+
+// yamlMediaTypes are the media types accepted for YAML.
+var yamlMediaTypes = map[string]bool{
+	yamlContentType:      true,
+	"application/x-yaml": true,
+	"text/yaml":          true,
+}
+
+// isYAML reports whether r has a YAML body.
+func isYAML(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && yamlMediaTypes[mediaType]
+}
+
+// prefersYAML reports whether the Accept header of r prefers YAML to JSON.
+// Media ranges without a quality value have a quality of 1, and ties go to the
+// media range listed first. Without an Accept header, JSON is preferred.
+func prefersYAML(r *http.Request) bool {
+	var yamlQ, jsonQ float64
+	yamlIdx, jsonIdx := -1, -1
+	for i, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		switch {
+		case yamlMediaTypes[mediaType] && q > yamlQ:
+			yamlQ, yamlIdx = q, i
+		case (mediaType == "application/json" || mediaType == "application/*" || mediaType == "*/*") && q > jsonQ:
+			jsonQ, jsonIdx = q, i
+		}
+	}
+	if yamlQ != jsonQ {
+		return yamlQ > jsonQ
+	}
+	return yamlQ > 0 && yamlIdx < jsonIdx
+}
+
+// renderManifest writes v as YAML if the client prefers it, and as JSON
+// otherwise. The YAML document has the keys of the JSON one, in the same
+// order.
+func renderManifest(w http.ResponseWriter, r *http.Request, v any) {
+	if !prefersYAML(r) {
+		render.JSON(w, r, v)
+		return
+	}
+	b, err := json.Marshal(v)
+	if err == nil {
+		b, err = yaml.JSONToYAML(b)
+	}
+	if err != nil {
+		_ = render.Render(w, r, newErrResponse(err, http.StatusInternalServerError))
+		return
+	}
+	w.Header().Set("Content-Type", yamlContentType)
+	if status, ok := r.Context().Value(render.StatusCtxKey).(int); ok {
+		w.WriteHeader(status)
+	}
+	_, _ = w.Write(b)
+}
+
+// decodeYAML decodes the YAML body of r into v. Values are decoded as they
+// would be from the equivalent JSON body. Syntax errors report their line and
+// column. The equivalent JSON is checked against limits.
+func decodeYAML(r io.Reader, v any, limits util.PayloadLimits) error {
+	defer io.Copy(io.Discard, r) //nolint:errcheck
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	b, err = yaml.YAMLToJSON(b)
+	if err != nil {
+		return errors.New(yaml.FormatError(err, false, false))
+	}
+	return util.DecodeJSONWithLimits(bytes.NewReader(b), v, limits)
+}
@@ -0,0 +1,203 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestPrefersYAML(t *testing.T) {
+	tcs := []struct {
+		accept string
+		want   bool
+	}{
+		{accept: "", want: false},
+		{accept: "application/json", want: false},
+		{accept: "application/yaml", want: true},
+		{accept: "application/x-yaml", want: true},
+		{accept: "text/yaml; charset=utf-8", want: true},
+		{accept: "application/json, application/yaml", want: false},
+		{accept: "application/yaml, application/json", want: true},
+		{accept: "application/json;q=0.5, application/yaml", want: true},
+		{accept: "application/yaml;q=0.5, */*", want: false},
+		{accept: "application/yaml;q=0", want: false},
+		{accept: "text/html", want: false},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.accept, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodGet, "/", nil)
+			if err != nil {
+				t.Fatalf("unable to create request: %s", err)
+			}
+			if tc.accept != "" {
+				r.Header.Set("Accept", tc.accept)
+			}
+			if got := prefersYAML(r); got != tc.want {
+				t.Fatalf("unexpected result: got %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestManifestYAML(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	for _, path := range []string{"/toolset", fmt.Sprintf("/tool/%s", tool2.Name)} {
+		t.Run(path, func(t *testing.T) {
+			// without an Accept header, the manifest is JSON
+			resp, jsonBody, err := runRequest(ts, http.MethodGet, path, nil, nil)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if got := resp.Header.Get("Content-Type"); !strings.HasPrefix(got, "application/json") {
+				t.Fatalf("unexpected content type: got %q, want application/json", got)
+			}
+
+			var yamlBodies [][]byte
+			for i := 0; i < 2; i++ {
+				resp, body, err := runRequest(ts, http.MethodGet, path, nil, map[string]string{"Accept": "application/yaml"})
+				if err != nil {
+					t.Fatalf("unexpected error during request: %s", err)
+				}
+				if resp.StatusCode != http.StatusOK {
+					t.Fatalf("unexpected status code: got %d, want %d, %s", resp.StatusCode, http.StatusOK, string(body))
+				}
+				if got := resp.Header.Get("Content-Type"); got != yamlContentType {
+					t.Fatalf("unexpected content type: got %q, want %q", got, yamlContentType)
+				}
+				yamlBodies = append(yamlBodies, body)
+			}
+			if !bytes.Equal(yamlBodies[0], yamlBodies[1]) {
+				t.Fatalf("expected stable YAML manifests: got %q, then %q", yamlBodies[0], yamlBodies[1])
+			}
+
+			// the YAML manifest has the content of the JSON one
+			converted, err := yaml.YAMLToJSON(yamlBodies[0])
+			if err != nil {
+				t.Fatalf("unable to convert YAML manifest: %s", err)
+			}
+			var got, want tools.ToolsetManifest
+			if err := json.Unmarshal(converted, &got); err != nil {
+				t.Fatalf("unable to parse YAML manifest: %s", err)
+			}
+			if err := json.Unmarshal(jsonBody, &want); err != nil {
+				t.Fatalf("unable to parse JSON manifest: %s", err)
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Fatalf("unexpected manifest (-want +got):\n%s", diff)
+			}
+
+			// and the keys of the JSON one, in the same order
+			wantYAML, err := yaml.JSONToYAML(jsonBody)
+			if err != nil {
+				t.Fatalf("unable to convert JSON manifest: %s", err)
+			}
+			if diff := cmp.Diff(string(wantYAML), string(yamlBodies[0])); diff != "" {
+				t.Fatalf("unexpected key order (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// echoTool returns the parameters it is invoked with.
+type echoTool struct {
+	MockTool
+}
+
+func (t echoTool) Invoke(_ context.Context, inv tools.Invocation) (any, error) {
+	return []any{inv.Params.AsMap()}, nil
+}
+
+func TestToolInvokeYAML(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	toolsMap[tool2.Name] = echoTool{MockTool: tool2}
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	path := fmt.Sprintf("/tool/%s/invoke", tool2.Name)
+	_, want, err := runRequest(ts, http.MethodPost, path, bytes.NewBufferString(`{"param1": 1, "param2": 2}`), nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+
+	tcs := []struct {
+		name        string
+		contentType string
+		body        string
+		statusCode  int
+		want        string
+	}{
+		{
+			name:        "yaml",
+			contentType: "application/yaml",
+			body:        "param1: 1\nparam2: 2\n",
+			statusCode:  http.StatusOK,
+			want:        string(want),
+		},
+		{
+			name:        "flow style",
+			contentType: "application/x-yaml; charset=utf-8",
+			body:        "{param1: 1, param2: 2}",
+			statusCode:  http.StatusOK,
+			want:        string(want),
+		},
+		{
+			name:        "malformed",
+			contentType: "application/yaml",
+			body:        "param1: 1\nparam2: [2\n",
+			statusCode:  http.StatusBadRequest,
+			want:        "request body was invalid YAML: [2:9]",
+		},
+		{
+			name:        "invalid parameter",
+			contentType: "application/yaml",
+			body:        "param1: one\nparam2: 2\n",
+			statusCode:  http.StatusBadRequest,
+			want:        "provided parameters were invalid",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodPost, path, bytes.NewBufferString(tc.body), map[string]string{"Content-Type": tc.contentType})
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.statusCode {
+				t.Fatalf("unexpected status code: got %d, want %d, %s", resp.StatusCode, tc.statusCode, string(body))
+			}
+			if !strings.Contains(string(body), tc.want) {
+				t.Fatalf("unexpected response: got %q, want it to contain %q", string(body), tc.want)
+			}
+		})
+	}
+}
@@ -120,6 +120,45 @@ func TestParseFromYamlAlloyDBPg(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "pool settings",
+			in: `
+			sources:
+				my-pg-instance:
+					kind: alloydb-postgres
+					project: my-project
+					region: my-region
+					cluster: my-cluster
+					instance: my-instance
+					database: my_db
+					user: my_user
+					password: my_pass
+					maxConns: 10
+					minConns: 1
+					maxConnIdleTime: 10m
+					statementCacheMode: describe
+			`,
+			want: map[string]sources.SourceConfig{
+				"my-pg-instance": alloydbpg.Config{
+					Name:     "my-pg-instance",
+					Kind:     alloydbpg.SourceKind,
+					Project:  "my-project",
+					Region:   "my-region",
+					Cluster:  "my-cluster",
+					Instance: "my-instance",
+					IPType:   "public",
+					Database: "my_db",
+					User:     "my_user",
+					Password: "my_pass",
+					PgPoolConfig: sources.PgPoolConfig{
+						MaxConns:           10,
+						MinConns:           1,
+						MaxConnIdleTime:    "10m",
+						StatementCacheMode: "describe",
+					},
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -0,0 +1,53 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"github.com/jackc/pgx/v5"
+)
+
+// pgApplicationNameKey is the key of the custom data of a Postgres connection
+// that holds the client identity its application_name is set to.
+const pgApplicationNameKey = "toolbox.clientIdentity"
+
+// PrepareAttributedPgConn is a pgxpool PrepareConn hook that sets the
+// application_name of the acquired connection to the identity of the caller
+// found in ctx, so that queries can be attributed in pg_stat_activity. When
+// the caller is not identified, the application_name the connection was
+// opened with is restored. The application_name is only changed when it
+// differs from the identity of the previous caller of the connection.
+func PrepareAttributedPgConn(ctx context.Context, conn *pgx.Conn) (bool, error) {
+	identity := util.ClientIdentityFromContext(ctx)
+	data := conn.PgConn().CustomData()
+	if current, _ := data[pgApplicationNameKey].(string); current == identity {
+		return true, nil
+	}
+	var err error
+	if identity == "" {
+		_, err = conn.Exec(ctx, "RESET application_name")
+	} else {
+		_, err = conn.Exec(ctx, "SELECT set_config('application_name', $1, false)", identity)
+	}
+	if err != nil {
+		// destroy the connection, whose application_name is unknown
+		return false, fmt.Errorf("unable to set application_name: %w", err)
+	}
+	data[pgApplicationNameKey] = identity
+	return true, nil
+}
@@ -422,7 +422,9 @@ func initBigQueryConnection(
 	var tokenSource oauth2.TokenSource
 	var opts []option.ClientOption
 
-	if impersonateServiceAccount != "" {
+	if injected, ok := sources.GoogleClientOptions(ctx, userAgent); ok {
+		opts = injected
+	} else if impersonateServiceAccount != "" {
 		// Create impersonated credentials token source with cloud-platform scope
 		// This broader scope is needed for tools like conversational analytics
 		cloudPlatformTokenSource, err := impersonate.CredentialsTokenSource(ctx, impersonate.CredentialsConfig{
@@ -547,6 +549,12 @@ func initDataplexConnection(
 
 	if useClientOAuth {
 		clientCreator = newDataplexClientCreator(ctx, project, userAgent)
+	} else if injected, ok := sources.GoogleClientOptions(ctx, userAgent); ok {
+		// An injected HTTP client can only be used by the REST transport
+		client, err = dataplexapi.NewCatalogRESTClient(ctx, injected...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create Dataplex client for project %q: %w", project, err)
+		}
 	} else {
 		var opts []option.ClientOption
 
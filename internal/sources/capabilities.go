@@ -0,0 +1,37 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sources
+
+// CapabilitiesSource is implemented by sources that describe what their
+// database supports, so that clients can adapt to it. It is exposed in the
+// manifests of the tools of the source.
+type CapabilitiesSource interface {
+	Source
+	Capabilities() Capabilities
+}
+
+// Capabilities describes what the database of a source supports.
+type Capabilities struct {
+	// SupportsPreparedStatements is set if the parameters of statements are
+	// bound by the database rather than interpolated into them.
+	SupportsPreparedStatements bool `json:"supportsPreparedStatements"`
+	// SupportsArrayParams is set if arrays can be bound to parameters.
+	SupportsArrayParams bool `json:"supportsArrayParams"`
+	// SupportsTransactions is set if statements can run in transactions.
+	SupportsTransactions bool `json:"supportsTransactions"`
+	// MaxIdentifierLength is the maximum length in bytes of the names of
+	// objects such as tables and columns, or zero if unknown.
+	MaxIdentifierLength int `json:"maxIdentifierLength,omitempty"`
+}
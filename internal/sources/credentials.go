@@ -0,0 +1,164 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sources
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Credentials are the user name and password a source connects with.
+type Credentials struct {
+	User     string `json:"user"`
+	Password string `json:"password"`
+}
+
+// CredentialProvider supplies the credentials of a source. Providers backed by
+// a file or an environment variable read it on every call, so that rotated
+// secrets are picked up without a restart.
+type CredentialProvider interface {
+	Credentials() (Credentials, error)
+}
+
+// NewCredentialProvider returns a provider for user and exactly one of
+// password, passwordFile or passwordEnv.
+func NewCredentialProvider(user, password, passwordFile, passwordEnv string) (CredentialProvider, error) {
+	set := 0
+	for _, v := range []string{password, passwordFile, passwordEnv} {
+		if v != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return nil, fmt.Errorf("exactly one of password, passwordFile or passwordEnv must be set")
+	}
+	switch {
+	case passwordFile != "":
+		return fileCredentials{user: user, path: passwordFile}, nil
+	case passwordEnv != "":
+		return envCredentials{user: user, name: passwordEnv}, nil
+	default:
+		return staticCredentials{Credentials{User: user, Password: password}}, nil
+	}
+}
+
+type staticCredentials struct {
+	creds Credentials
+}
+
+func (p staticCredentials) Credentials() (Credentials, error) {
+	return p.creds, nil
+}
+
+type fileCredentials struct {
+	user string
+	path string
+}
+
+func (p fileCredentials) Credentials() (Credentials, error) {
+	b, err := os.ReadFile(p.path)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("unable to read password file: %w", err)
+	}
+	return Credentials{User: p.user, Password: strings.TrimSpace(string(b))}, nil
+}
+
+type envCredentials struct {
+	user string
+	name string
+}
+
+func (p envCredentials) Credentials() (Credentials, error) {
+	v, ok := os.LookupEnv(p.name)
+	if !ok {
+		return Credentials{}, fmt.Errorf("environment variable %q is not set", p.name)
+	}
+	return Credentials{User: p.user, Password: v}, nil
+}
+
+// RotatableSource is a Source whose credentials can be replaced while it is
+// running.
+type RotatableSource interface {
+	Source
+	// RotateCredentials switches the source to creds. If creds is nil, the
+	// source re-reads its credentials from its CredentialProvider. New
+	// connections use the new credentials, while connections in use complete
+	// their work before they are closed. If the rotation fails, the source
+	// keeps its current credentials.
+	RotateCredentials(ctx context.Context, creds *Credentials) error
+	// IsAuthError reports whether err, returned by a tool acting on the
+	// source, was caused by the database rejecting the credentials.
+	IsAuthError(err error) bool
+}
+
+// CredentialRotator keeps track of the active credentials of a
+// RotatableSource. Sources use it to serialize rotations, validate new
+// credentials before using them and drain connections made with the old ones.
+type CredentialRotator struct {
+	// mu serializes rotations.
+	mu       sync.Mutex
+	current  atomic.Pointer[Credentials]
+	provider CredentialProvider
+	validate func(context.Context, Credentials) error
+	drain    func()
+}
+
+// NewCredentialRotator returns a CredentialRotator using the credentials of
+// provider. validate is called with new credentials before a rotation, and
+// drain once the rotation succeeded.
+func NewCredentialRotator(provider CredentialProvider, validate func(context.Context, Credentials) error, drain func()) (*CredentialRotator, error) {
+	creds, err := provider.Credentials()
+	if err != nil {
+		return nil, err
+	}
+	r := &CredentialRotator{provider: provider, validate: validate, drain: drain}
+	r.current.Store(&creds)
+	return r, nil
+}
+
+// Current returns the active credentials.
+func (r *CredentialRotator) Current() Credentials {
+	return *r.current.Load()
+}
+
+// Rotate validates creds and makes them the active credentials, then drains
+// the connections made with the old ones. If creds is nil, the credentials are
+// re-read from the provider, and nothing happens if they did not change.
+func (r *CredentialRotator) Rotate(ctx context.Context, creds *Credentials) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if creds == nil {
+		c, err := r.provider.Credentials()
+		if err != nil {
+			return fmt.Errorf("unable to read credentials: %w", err)
+		}
+		if c == r.Current() {
+			return nil
+		}
+		creds = &c
+	}
+	if err := r.validate(ctx, *creds); err != nil {
+		return fmt.Errorf("new credentials were rejected: %w", err)
+	}
+	c := *creds
+	r.current.Store(&c)
+	r.drain()
+	return nil
+}
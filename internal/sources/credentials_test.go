@@ -0,0 +1,116 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sources_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+)
+
+func TestNewCredentialProvider(t *testing.T) {
+	passwordFile := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(passwordFile, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatalf("unable to write password file: %s", err)
+	}
+	t.Setenv("TEST_CREDENTIALS_PASSWORD", "from-env")
+
+	tcs := []struct {
+		desc         string
+		password     string
+		passwordFile string
+		passwordEnv  string
+		want         string
+	}{
+		{
+			desc:     "password",
+			password: "static",
+			want:     "static",
+		},
+		{
+			desc:         "password file",
+			passwordFile: passwordFile,
+			want:         "from-file",
+		},
+		{
+			desc:        "password env",
+			passwordEnv: "TEST_CREDENTIALS_PASSWORD",
+			want:        "from-env",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			p, err := sources.NewCredentialProvider("user", tc.password, tc.passwordFile, tc.passwordEnv)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			got, err := p.Credentials()
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if want := (sources.Credentials{User: "user", Password: tc.want}); got != want {
+				t.Fatalf("unexpected credentials: got %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestFailNewCredentialProvider(t *testing.T) {
+	tcs := []struct {
+		desc         string
+		password     string
+		passwordFile string
+		passwordEnv  string
+		err          string
+	}{
+		{
+			desc: "no password",
+			err:  "exactly one of password, passwordFile or passwordEnv must be set",
+		},
+		{
+			desc:         "password and password file",
+			password:     "static",
+			passwordFile: "/run/secrets/password",
+			err:          "exactly one of password, passwordFile or passwordEnv must be set",
+		},
+		{
+			desc:         "missing password file",
+			passwordFile: filepath.Join(t.TempDir(), "missing"),
+			err:          "unable to read password file",
+		},
+		{
+			desc:        "missing password env",
+			passwordEnv: "TEST_CREDENTIALS_MISSING",
+			err:         `environment variable "TEST_CREDENTIALS_MISSING" is not set`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			p, err := sources.NewCredentialProvider("user", tc.password, tc.passwordFile, tc.passwordEnv)
+			if err == nil {
+				_, err = p.Credentials()
+			}
+			if err == nil {
+				t.Fatalf("expected an error, but got nil")
+			}
+			if !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("unexpected error: got %q, want to contain %q", err, tc.err)
+			}
+		})
+	}
+}
@@ -107,14 +107,23 @@ func initDataplexConnection(
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
 	defer span.End()
 
-	cred, err := google.FindDefaultCredentials(ctx)
+	userAgent, err := util.UserAgentFromContext(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find default Google Cloud credentials: %w", err)
+		return nil, err
 	}
 
-	userAgent, err := util.UserAgentFromContext(ctx)
+	// An injected HTTP client can only be used by the REST transport
+	if opts, ok := sources.GoogleClientOptions(ctx, userAgent); ok {
+		client, err := dataplexapi.NewCatalogRESTClient(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Dataplex client for project %q: %w", project, err)
+		}
+		return client, nil
+	}
+
+	cred, err := google.FindDefaultCredentials(ctx)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to find default Google Cloud credentials: %w", err)
 	}
 
 	client, err := dataplexapi.NewCatalogClient(ctx, option.WithUserAgent(userAgent), option.WithCredentials(cred))
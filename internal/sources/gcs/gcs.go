@@ -0,0 +1,130 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/api/option"
+	storageapi "google.golang.org/api/storage/v1"
+)
+
+const SourceKind string = "gcs"
+
+// emulatorHostEnv is the environment variable that points the source to a
+// Cloud Storage emulator, as with the Cloud Storage client libraries.
+const emulatorHostEnv = "STORAGE_EMULATOR_HOST"
+
+// validate interface
+var _ sources.SourceConfig = Config{}
+
+func init() {
+	if !sources.Register(SourceKind, newConfig) {
+		panic(fmt.Sprintf("source kind %q already registered", SourceKind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources.SourceConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type Config struct {
+	Name    string `yaml:"name" validate:"required"`
+	Kind    string `yaml:"kind" validate:"required"`
+	Project string `yaml:"project" validate:"required"`
+	Bucket  string `yaml:"bucket"` // Optional, the bucket of tools invoked without one
+}
+
+func (r Config) SourceConfigKind() string {
+	return SourceKind
+}
+
+func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
+	service, err := initGCSService(ctx, tracer, r.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Source{
+		Name:    r.Name,
+		Kind:    SourceKind,
+		Project: r.Project,
+		Bucket:  r.Bucket,
+		Service: service,
+	}
+	return s, nil
+}
+
+var _ sources.Source = &Source{}
+
+type Source struct {
+	Name    string `yaml:"name"`
+	Kind    string `yaml:"kind"`
+	Project string `yaml:"project"`
+	Bucket  string `yaml:"bucket"`
+	Service *storageapi.Service
+}
+
+func (s *Source) SourceKind() string {
+	return SourceKind
+}
+
+func (s *Source) StorageService() *storageapi.Service {
+	return s.Service
+}
+
+func (s *Source) DefaultBucket() string {
+	return s.Bucket
+}
+
+func initGCSService(ctx context.Context, tracer trace.Tracer, name string) (*storageapi.Service, error) {
+	//nolint:all // Reassigned ctx
+	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
+	defer span.End()
+
+	userAgent, err := util.UserAgentFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	opts, ok := sources.GoogleClientOptions(ctx, userAgent)
+	if !ok {
+		// Use Application Default Credentials, unless an emulator is used
+		opts = []option.ClientOption{option.WithUserAgent(userAgent)}
+		if host := os.Getenv(emulatorHostEnv); host != "" {
+			if !strings.Contains(host, "://") {
+				host = "http://" + host
+			}
+			opts = append(opts, option.WithEndpoint(strings.TrimSuffix(host, "/")+"/storage/v1/"), option.WithoutAuthentication())
+		}
+	}
+
+	service, err := storageapi.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Cloud Storage service: %w", err)
+	}
+	return service, nil
+}
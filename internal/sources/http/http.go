@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"os"
 	"time"
 
 	"github.com/goccy/go-yaml"
@@ -54,6 +55,8 @@ type Config struct {
 	DefaultHeaders         map[string]string `yaml:"headers"`
 	QueryParams            map[string]string `yaml:"queryParams"`
 	DisableSslVerification bool              `yaml:"disableSslVerification"`
+	AuthHeaderEnv          string            `yaml:"authHeaderEnv"`
+	AuthHeaderName         string            `yaml:"authHeaderName"`
 }
 
 func (r Config) SourceConfigKind() string {
@@ -105,6 +108,22 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 	}
 	r.DefaultHeaders["User-Agent"] = ua
 
+	// Read the auth header from the environment, so that the secret does not
+	// need to appear in the configuration file.
+	if r.AuthHeaderEnv != "" {
+		value, ok := os.LookupEnv(r.AuthHeaderEnv)
+		if !ok {
+			return nil, fmt.Errorf("environment variable %q for the auth header is not set", r.AuthHeaderEnv)
+		}
+		name := r.AuthHeaderName
+		if name == "" {
+			name = "Authorization"
+		}
+		r.DefaultHeaders[name] = value
+	} else if r.AuthHeaderName != "" {
+		return nil, fmt.Errorf("authHeaderName requires authHeaderEnv to be set")
+	}
+
 	s := &Source{
 		Name:           r.Name,
 		Kind:           SourceKind,
@@ -23,6 +23,7 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/sources/http"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 func TestParseFromYamlHttp(t *testing.T) {
@@ -77,6 +78,27 @@ func TestParseFromYamlHttp(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "auth header from env",
+			in: `
+			sources:
+				my-http-instance:
+					kind: http
+					baseUrl: http://test_server/
+					authHeaderEnv: API_TOKEN
+					authHeaderName: X-Api-Key
+			`,
+			want: map[string]sources.SourceConfig{
+				"my-http-instance": http.Config{
+					Name:           "my-http-instance",
+					Kind:           http.SourceKind,
+					BaseURL:        "http://test_server/",
+					Timeout:        "30s",
+					AuthHeaderEnv:  "API_TOKEN",
+					AuthHeaderName: "X-Api-Key",
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -144,3 +166,59 @@ func TestFailParseFromYaml(t *testing.T) {
 		})
 	}
 }
+
+func TestInitializeAuthHeaderFromEnv(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	t.Setenv("HTTP_SOURCE_TEST_TOKEN", "Bearer secret")
+
+	tcs := []struct {
+		desc string
+		cfg  http.Config
+		want map[string]string
+		err  string
+	}{
+		{
+			desc: "default header name",
+			cfg:  http.Config{Name: "src", Kind: http.SourceKind, BaseURL: "http://test_server/", Timeout: "30s", AuthHeaderEnv: "HTTP_SOURCE_TEST_TOKEN"},
+			want: map[string]string{"Authorization": "Bearer secret"},
+		},
+		{
+			desc: "custom header name",
+			cfg:  http.Config{Name: "src", Kind: http.SourceKind, BaseURL: "http://test_server/", Timeout: "30s", AuthHeaderEnv: "HTTP_SOURCE_TEST_TOKEN", AuthHeaderName: "X-Api-Key"},
+			want: map[string]string{"X-Api-Key": "Bearer secret"},
+		},
+		{
+			desc: "unset variable",
+			cfg:  http.Config{Name: "src", Kind: http.SourceKind, BaseURL: "http://test_server/", Timeout: "30s", AuthHeaderEnv: "HTTP_SOURCE_TEST_UNSET"},
+			err:  `environment variable "HTTP_SOURCE_TEST_UNSET" for the auth header is not set`,
+		},
+		{
+			desc: "name without env",
+			cfg:  http.Config{Name: "src", Kind: http.SourceKind, BaseURL: "http://test_server/", Timeout: "30s", AuthHeaderName: "X-Api-Key"},
+			err:  "authHeaderName requires authHeaderEnv to be set",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			src, err := tc.cfg.Initialize(ctx, noop.NewTracerProvider().Tracer(""))
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Fatalf("unexpected error: got %v, want %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			headers := src.(*http.Source).DefaultHeaders
+			for k, v := range tc.want {
+				if headers[k] != v {
+					t.Errorf("header %q: got %q, want %q", k, headers[k], v)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sources
+
+import (
+	"context"
+	"net/http"
+
+	"google.golang.org/api/option"
+)
+
+type contextKey string
+
+// httpClientKey is the key used to store the HTTP client for Google APIs
+// within context
+const httpClientKey contextKey = "googleHTTPClient"
+
+// WithGoogleHTTPClient adds an HTTP client into the context as a value.
+// Sources backed by Google SDKs initialized with this context send their API
+// requests through the client, which is responsible for authentication, and
+// do not look up Application Default Credentials. Tests use it to record and
+// replay API traffic.
+func WithGoogleHTTPClient(ctx context.Context, client *http.Client) context.Context {
+	return context.WithValue(ctx, httpClientKey, client)
+}
+
+// GoogleClientOptions returns the client options that replace credentials when
+// an HTTP client was added with WithGoogleHTTPClient. It returns false if none
+// was added.
+func GoogleClientOptions(ctx context.Context, userAgent string) ([]option.ClientOption, bool) {
+	client, ok := ctx.Value(httpClientKey).(*http.Client)
+	if !ok || client == nil {
+		return nil, false
+	}
+	return []option.ClientOption{option.WithUserAgent(userAgent), option.WithHTTPClient(client)}, true
+}
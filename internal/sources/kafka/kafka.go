@@ -0,0 +1,198 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sasl"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+	"github.com/twmb/franz-go/pkg/sasl/scram"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const SourceKind string = "kafka"
+
+// defaultClientID is the client id used when `clientId` is not configured.
+const defaultClientID = "genai-toolbox"
+
+// validate interface
+var _ sources.SourceConfig = Config{}
+
+func init() {
+	if !sources.Register(SourceKind, newConfig) {
+		panic(fmt.Sprintf("source kind %q already registered", SourceKind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources.SourceConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type Config struct {
+	Name     string   `yaml:"name" validate:"required"`
+	Kind     string   `yaml:"kind" validate:"required"`
+	Brokers  []string `yaml:"brokers" validate:"required"`
+	ClientID string   `yaml:"clientId"`
+	// TLS enables TLS. It is implied by CAPath, CertPath and KeyPath.
+	TLS                bool   `yaml:"tls"`
+	CAPath             string `yaml:"caPath"`
+	CertPath           string `yaml:"certPath"`
+	KeyPath            string `yaml:"keyPath"`
+	InsecureSkipVerify bool   `yaml:"insecureSkipVerify"`
+	// SASLMechanism is one of PLAIN, SCRAM-SHA-256 or SCRAM-SHA-512. SASL is
+	// disabled if it is empty.
+	SASLMechanism string `yaml:"saslMechanism"`
+	Username      string `yaml:"username"`
+	Password      string `yaml:"password"`
+}
+
+func (r Config) SourceConfigKind() string {
+	return SourceKind
+}
+
+func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
+	//nolint:all // Reassigned ctx
+	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, r.Name)
+	defer span.End()
+
+	opts, err := r.clientOpts()
+	if err != nil {
+		return nil, err
+	}
+	client, err := kgo.NewClient(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create Kafka client: %w", err)
+	}
+	if err := client.Ping(ctx); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("unable to connect to Kafka brokers %q: %w", r.Brokers, err)
+	}
+
+	s := &Source{
+		Name:   r.Name,
+		Kind:   SourceKind,
+		Client: client,
+		opts:   opts,
+	}
+	return s, nil
+}
+
+// clientOpts returns the options of the clients connecting to the brokers.
+func (r Config) clientOpts() ([]kgo.Opt, error) {
+	clientID := r.ClientID
+	if clientID == "" {
+		clientID = defaultClientID
+	}
+	opts := []kgo.Opt{
+		kgo.SeedBrokers(r.Brokers...),
+		kgo.ClientID(clientID),
+	}
+
+	if r.TLS || r.CAPath != "" || r.CertPath != "" || r.KeyPath != "" {
+		tlsCfg, err := r.tlsConfig()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, kgo.DialTLSConfig(tlsCfg))
+	}
+
+	if r.SASLMechanism != "" {
+		mechanism, err := r.saslMechanism()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, kgo.SASL(mechanism))
+	} else if r.Username != "" || r.Password != "" {
+		return nil, fmt.Errorf("invalid Kafka configuration: `username` and `password` require `saslMechanism`")
+	}
+	return opts, nil
+}
+
+func (r Config) tlsConfig() (*tls.Config, error) {
+	//nolint:gosec // skipping verification is opt-in
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: r.InsecureSkipVerify}
+	if r.CAPath != "" {
+		ca, err := os.ReadFile(r.CAPath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read CA certificate: %w", err)
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no valid certificate found in %q", r.CAPath)
+		}
+	}
+	if (r.CertPath == "") != (r.KeyPath == "") {
+		return nil, fmt.Errorf("invalid Kafka configuration: `certPath` and `keyPath` must be set together")
+	}
+	if r.CertPath != "" {
+		cert, err := tls.LoadX509KeyPair(r.CertPath, r.KeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+func (r Config) saslMechanism() (sasl.Mechanism, error) {
+	switch strings.ToUpper(r.SASLMechanism) {
+	case "PLAIN":
+		return plain.Auth{User: r.Username, Pass: r.Password}.AsMechanism(), nil
+	case "SCRAM-SHA-256":
+		return scram.Auth{User: r.Username, Pass: r.Password}.AsSha256Mechanism(), nil
+	case "SCRAM-SHA-512":
+		return scram.Auth{User: r.Username, Pass: r.Password}.AsSha512Mechanism(), nil
+	}
+	return nil, fmt.Errorf("invalid `saslMechanism` %q: must be one of PLAIN, SCRAM-SHA-256 or SCRAM-SHA-512", r.SASLMechanism)
+}
+
+var _ sources.Source = &Source{}
+
+type Source struct {
+	Name   string `yaml:"name"`
+	Kind   string `yaml:"kind"`
+	Client *kgo.Client
+	// opts are the options Client was created with.
+	opts []kgo.Opt
+}
+
+func (s *Source) SourceKind() string {
+	return SourceKind
+}
+
+func (s *Source) KafkaClient() *kgo.Client {
+	return s.Client
+}
+
+// NewKafkaClient creates a new client connecting to the same brokers as
+// KafkaClient, with additional options such as the partitions to consume. The
+// caller must close it.
+func (s *Source) NewKafkaClient(opts ...kgo.Opt) (*kgo.Client, error) {
+	all := append(append([]kgo.Opt{}, s.opts...), opts...)
+	return kgo.NewClient(all...)
+}
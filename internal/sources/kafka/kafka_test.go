@@ -0,0 +1,177 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources/kafka"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestParseFromYamlKafka(t *testing.T) {
+	tcs := []struct {
+		desc string
+		in   string
+		want server.SourceConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			sources:
+				my-kafka-instance:
+					kind: kafka
+					brokers:
+						- localhost:9092
+			`,
+			want: server.SourceConfigs{
+				"my-kafka-instance": kafka.Config{
+					Name:    "my-kafka-instance",
+					Kind:    kafka.SourceKind,
+					Brokers: []string{"localhost:9092"},
+				},
+			},
+		},
+		{
+			desc: "advanced example",
+			in: `
+			sources:
+				my-kafka-instance:
+					kind: kafka
+					brokers:
+						- broker-1:9093
+						- broker-2:9093
+					clientId: my-agent
+					tls: true
+					caPath: /etc/kafka/ca.pem
+					saslMechanism: SCRAM-SHA-512
+					username: my-user
+					password: my-pass
+			`,
+			want: server.SourceConfigs{
+				"my-kafka-instance": kafka.Config{
+					Name:          "my-kafka-instance",
+					Kind:          kafka.SourceKind,
+					Brokers:       []string{"broker-1:9093", "broker-2:9093"},
+					ClientID:      "my-agent",
+					TLS:           true,
+					CAPath:        "/etc/kafka/ca.pem",
+					SASLMechanism: "SCRAM-SHA-512",
+					Username:      "my-user",
+					Password:      "my-pass",
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Sources server.SourceConfigs `yaml:"sources"`
+			}{}
+			// Parse contents
+			err := yaml.Unmarshal(testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Sources); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestFailParseFromYaml(t *testing.T) {
+	tcs := []struct {
+		desc string
+		in   string
+		err  string
+	}{
+		{
+			desc: "extra field",
+			in: `
+			sources:
+				my-kafka-instance:
+					kind: kafka
+					brokers:
+						- localhost:9092
+					topic: orders
+			`,
+			err: "unable to parse source \"my-kafka-instance\" as \"kafka\": [4:1] unknown field \"topic\"",
+		},
+		{
+			desc: "missing required field",
+			in: `
+			sources:
+				my-kafka-instance:
+					kind: kafka
+			`,
+			err: "unable to parse source \"my-kafka-instance\" as \"kafka\": Key: 'Config.Brokers' Error:Field validation for 'Brokers' failed on the 'required' tag",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Sources server.SourceConfigs `yaml:"sources"`
+			}{}
+			// Parse contents
+			err := yaml.Unmarshal(testutils.FormatYaml(tc.in), &got)
+			if err == nil {
+				t.Fatalf("expect parsing to fail")
+			}
+			errStr := err.Error()
+			if !strings.Contains(errStr, tc.err) {
+				t.Fatalf("unexpected error: got %q, want %q", errStr, tc.err)
+			}
+		})
+	}
+}
+
+func TestInitializeInvalidConfig(t *testing.T) {
+	tcs := []struct {
+		desc string
+		cfg  kafka.Config
+		err  string
+	}{
+		{
+			desc: "unknown sasl mechanism",
+			cfg:  kafka.Config{Name: "my-kafka", Kind: kafka.SourceKind, Brokers: []string{"localhost:9092"}, SASLMechanism: "GSSAPI"},
+			err:  "invalid `saslMechanism` \"GSSAPI\": must be one of PLAIN, SCRAM-SHA-256 or SCRAM-SHA-512",
+		},
+		{
+			desc: "credentials without sasl mechanism",
+			cfg:  kafka.Config{Name: "my-kafka", Kind: kafka.SourceKind, Brokers: []string{"localhost:9092"}, Username: "my-user"},
+			err:  "`username` and `password` require `saslMechanism`",
+		},
+		{
+			desc: "certificate without key",
+			cfg:  kafka.Config{Name: "my-kafka", Kind: kafka.SourceKind, Brokers: []string{"localhost:9092"}, CertPath: "/etc/kafka/client.pem"},
+			err:  "`certPath` and `keyPath` must be set together",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := tc.cfg.Initialize(context.Background(), noop.NewTracerProvider().Tracer("test"))
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.err)
+			}
+		})
+	}
+}
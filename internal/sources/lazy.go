@@ -0,0 +1,50 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sources
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// LazyInitializer is implemented by the configs of the source kinds that can
+// defer connecting until they are first used.
+type LazyInitializer interface {
+	// InitializeLazy initializes the source without connecting to it. The
+	// connection is established by the first tool invocation, which fails if
+	// the source is unreachable.
+	InitializeLazy(ctx context.Context, tracer trace.Tracer) (Source, error)
+}
+
+// LazyConfig wraps the config of a source that sets `lazyInit`, so that the
+// source does not connect when it is initialized.
+type LazyConfig struct {
+	SourceConfig
+}
+
+// NewLazyConfig wraps sc so that it is initialized lazily. It returns an error
+// if the kind of sc does not support lazy initialization.
+func NewLazyConfig(name string, sc SourceConfig) (LazyConfig, error) {
+	if _, ok := sc.(LazyInitializer); !ok {
+		return LazyConfig{}, fmt.Errorf("source %q of kind %q does not support 'lazyInit'", name, sc.SourceConfigKind())
+	}
+	return LazyConfig{SourceConfig: sc}, nil
+}
+
+func (c LazyConfig) Initialize(ctx context.Context, tracer trace.Tracer) (Source, error) {
+	return c.SourceConfig.(LazyInitializer).InitializeLazy(ctx, tracer)
+}
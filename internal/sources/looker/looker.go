@@ -129,7 +129,7 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 		if r.ClientId == "" || r.ClientSecret == "" {
 			return nil, fmt.Errorf("client_id and client_secret need to be specified")
 		}
-		s.Client = v4.NewLookerSDK(rtl.NewAuthSession(cfg))
+		s.Client = v4.NewLookerSDK(NewAuthSession(cfg, nil))
 		resp, err := s.Client.Me("", s.ApiSettings)
 		if err != nil {
 			return nil, fmt.Errorf("incorrect settings: %w", err)
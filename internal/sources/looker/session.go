@@ -0,0 +1,111 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package looker
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/looker-open-source/sdk-codegen/go/rtl"
+	v4 "github.com/looker-open-source/sdk-codegen/go/sdk/v4"
+	"golang.org/x/oauth2"
+)
+
+// validate interface
+var _ v4.AuthSessionDoer = &AuthSession{}
+
+// AuthSession is a Looker API session that logs in with a client ID and
+// secret. When Looker rejects the token of the session, as it does once the
+// token expired on the Looker side, the session logs in again and retries the
+// call once.
+type AuthSession struct {
+	config    rtl.ApiSettings
+	transport http.RoundTripper
+
+	mu      sync.Mutex
+	session *rtl.AuthSession
+	// generation is incremented each time the session logs in again, so that
+	// the calls rejected with the same token cause a single login.
+	generation int
+}
+
+// NewAuthSession returns an AuthSession sending its requests through
+// transport. If transport is nil, the TLS settings of cfg are used.
+func NewAuthSession(cfg rtl.ApiSettings, transport http.RoundTripper) *AuthSession {
+	if transport == nil {
+		transport = &http.Transport{
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: !cfg.VerifySsl,
+			},
+		}
+	}
+	return &AuthSession{
+		config:    cfg,
+		transport: transport,
+		session:   rtl.NewAuthSessionWithTransport(cfg, transport),
+	}
+}
+
+func (s *AuthSession) Do(result interface{}, method, ver, path string, reqPars map[string]interface{}, body interface{}, options *rtl.ApiSettings) error {
+	session, generation := s.current()
+	err := session.Do(result, method, ver, path, reqPars, body, options)
+	if !isUnauthorized(err) {
+		return loginError(err)
+	}
+
+	err = s.refresh(generation).Do(result, method, ver, path, reqPars, body, options)
+	if isUnauthorized(err) {
+		return fmt.Errorf("looker rejected the request even after logging in again, check the permissions of the client_id: %w", err)
+	}
+	return loginError(err)
+}
+
+func (s *AuthSession) current() (*rtl.AuthSession, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.session, s.generation
+}
+
+// refresh replaces the session whose token was rejected, unless a concurrent
+// call already did. The new session logs in on its first request, once for
+// all the calls sharing it.
+func (s *AuthSession) refresh(generation int) *rtl.AuthSession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.generation == generation {
+		s.session = rtl.NewAuthSessionWithTransport(s.config, s.transport)
+		s.generation++
+	}
+	return s.session
+}
+
+// isUnauthorized reports whether err is the error returned by the Looker SDK
+// for a 401 response.
+func isUnauthorized(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "status=401")
+}
+
+// loginError distinguishes the errors of the login endpoint, which mean the
+// credentials of the source are invalid, from those of the call itself.
+func loginError(err error) error {
+	var rErr *oauth2.RetrieveError
+	if errors.As(err, &rErr) {
+		return fmt.Errorf("unable to log in to looker, check the client_id and client_secret of the source: %w", err)
+	}
+	return err
+}
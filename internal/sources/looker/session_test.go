@@ -0,0 +1,157 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package looker_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/sources/looker"
+	"github.com/looker-open-source/sdk-codegen/go/rtl"
+	v4 "github.com/looker-open-source/sdk-codegen/go/sdk/v4"
+)
+
+// fakeLooker is a transport answering the login endpoint with the tokens
+// "token-1", "token-2", ... and the other endpoints with a user, as long as
+// the request carries an accepted token.
+type fakeLooker struct {
+	mu     sync.Mutex
+	logins int
+	calls  int
+	// badCredentials makes the login endpoint reject the client secret.
+	badCredentials bool
+	// accepted reports whether the n-th token is accepted.
+	accepted func(n int) bool
+}
+
+func (f *fakeLooker) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if strings.HasSuffix(req.URL.Path, "/login") {
+		if f.badCredentials {
+			return response(http.StatusNotFound, `{"message":"Not found"}`), nil
+		}
+		f.logins++
+		return response(http.StatusOK, fmt.Sprintf(`{"access_token":"token-%d","token_type":"Bearer","expires_in":3600}`, f.logins)), nil
+	}
+	f.calls++
+	var n int
+	if _, err := fmt.Sscanf(req.Header.Get("Authorization"), "Bearer token-%d", &n); err != nil || !f.accepted(n) {
+		return response(http.StatusUnauthorized, `{"message":"Requires authentication."}`), nil
+	}
+	return response(http.StatusOK, `{"id":"1","first_name":"Ada","last_name":"Lovelace"}`), nil
+}
+
+func response(code int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: code,
+		Status:     fmt.Sprintf("%d %s", code, http.StatusText(code)),
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func newSDK(f *fakeLooker) *v4.LookerSDK {
+	cfg := rtl.ApiSettings{
+		BaseUrl:      "https://looker.example.com",
+		ApiVersion:   "4.0",
+		ClientId:     "id",
+		ClientSecret: "secret",
+	}
+	return v4.NewLookerSDK(looker.NewAuthSession(cfg, f))
+}
+
+func TestAuthSessionRefresh(t *testing.T) {
+	// the first token expires after the first call
+	f := &fakeLooker{}
+	f.accepted = func(n int) bool { return n > 1 || f.calls == 1 }
+	sdk := newSDK(f)
+
+	for i := 0; i < 3; i++ {
+		user, err := sdk.Me("", nil)
+		if err != nil {
+			t.Fatalf("call %d: unexpected error: %s", i, err)
+		}
+		if *user.FirstName != "Ada" {
+			t.Fatalf("call %d: unexpected user: %v", i, user)
+		}
+	}
+	// the second call is rejected and retried
+	if f.logins != 2 || f.calls != 4 {
+		t.Fatalf("got %d logins and %d calls, want 2 and 4", f.logins, f.calls)
+	}
+}
+
+func TestAuthSessionConcurrentRefresh(t *testing.T) {
+	// the first token is never accepted
+	f := &fakeLooker{accepted: func(n int) bool { return n > 1 }}
+	sdk := newSDK(f)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := sdk.Me("", nil); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if f.logins != 2 {
+		t.Fatalf("got %d logins, want 2", f.logins)
+	}
+}
+
+func TestAuthSessionErrors(t *testing.T) {
+	tcs := []struct {
+		desc       string
+		fake       *fakeLooker
+		wantErr    string
+		wantLogins int
+	}{
+		{
+			desc:       "token always rejected",
+			fake:       &fakeLooker{accepted: func(int) bool { return false }},
+			wantErr:    "looker rejected the request even after logging in again",
+			wantLogins: 2,
+		},
+		{
+			desc:       "bad credentials",
+			fake:       &fakeLooker{badCredentials: true},
+			wantErr:    "unable to log in to looker, check the client_id and client_secret of the source",
+			wantLogins: 0,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := newSDK(tc.fake).Me("", nil)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("got error %v, want one containing %q", err, tc.wantErr)
+			}
+			if tc.fake.logins != tc.wantLogins {
+				t.Fatalf("got %d logins, want %d", tc.fake.logins, tc.wantLogins)
+			}
+		})
+	}
+}
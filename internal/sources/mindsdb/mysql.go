@@ -18,6 +18,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
@@ -46,14 +48,26 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources
 }
 
 type Config struct {
-	Name         string `yaml:"name" validate:"required"`
-	Kind         string `yaml:"kind" validate:"required"`
-	Host         string `yaml:"host" validate:"required"`
-	Port         string `yaml:"port" validate:"required"`
-	User         string `yaml:"user" validate:"required"`
-	Password     string `yaml:"password"`
-	Database     string `yaml:"database" validate:"required"`
+	Name     string `yaml:"name" validate:"required"`
+	Kind     string `yaml:"kind" validate:"required"`
+	Host     string `yaml:"host" validate:"required"`
+	Port     string `yaml:"port" validate:"required"`
+	User     string `yaml:"user" validate:"required"`
+	Password string `yaml:"password"`
+	// Database is the default database of the connections. If it is not set,
+	// the connections have no default database, and MindsDB resolves
+	// unqualified table names against the mindsdb project.
+	Database     string `yaml:"database"`
 	QueryTimeout string `yaml:"queryTimeout"`
+	// MaxOpenConns is the most connections of the pool. It is unlimited if
+	// not set.
+	MaxOpenConns int `yaml:"maxOpenConns"`
+	// HTTPURL is the base URL of the MindsDB HTTP API, such as
+	// http://127.0.0.1:47334. It is used to upload files.
+	HTTPURL string `yaml:"httpUrl"`
+	// SSHTunnel, if set, connects to MindsDB through an SSH bastion. The
+	// HTTP API is not tunneled.
+	SSHTunnel *sources.SSHTunnelConfig `yaml:"sshTunnel"`
 }
 
 func (r Config) SourceConfigKind() string {
@@ -61,36 +75,71 @@ func (r Config) SourceConfigKind() string {
 }
 
 func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
-	pool, err := initMindsDBConnectionPool(ctx, tracer, r.Name, r.Host, r.Port, r.User, r.Password, r.Database, r.QueryTimeout)
+	if r.HTTPURL != "" {
+		u, err := url.Parse(r.HTTPURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid httpUrl %q: must be an http or https URL", r.HTTPURL)
+		}
+	}
+	if r.MaxOpenConns < 0 {
+		return nil, fmt.Errorf("maxOpenConns must not be negative, got %d", r.MaxOpenConns)
+	}
+
+	tunnel, err := sources.NewSSHTunnel(ctx, r.SSHTunnel)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := initMindsDBConnectionPool(ctx, tracer, r.Name, tunnel.MySQLNetwork(), r.Host, r.Port, r.User, r.Password, r.Database, r.QueryTimeout)
 	if err != nil {
+		_ = tunnel.Close()
 		return nil, fmt.Errorf("unable to create pool: %w", err)
 	}
+	pool.SetMaxOpenConns(r.MaxOpenConns)
 
 	err = pool.PingContext(ctx)
 	if err != nil {
+		pool.Close()
+		_ = tunnel.Close()
 		return nil, fmt.Errorf("unable to connect successfully: %w", err)
 	}
 
 	s := &Source{
-		Name: r.Name,
-		Kind: SourceKind,
-		Pool: pool,
+		Name:    r.Name,
+		Kind:    SourceKind,
+		Pool:    pool,
+		HTTPURL: strings.TrimRight(r.HTTPURL, "/"),
+		monitor: sources.NewSQLPoolMonitor(pool),
 	}
 	return s, nil
 }
 
 var _ sources.Source = &Source{}
+var _ sources.CapabilitiesSource = &Source{}
+var _ sources.PoolSource = &Source{}
 
 type Source struct {
-	Name string `yaml:"name"`
-	Kind string `yaml:"kind"`
-	Pool *sql.DB
+	Name    string `yaml:"name"`
+	Kind    string `yaml:"kind"`
+	Pool    *sql.DB
+	HTTPURL string
+	monitor *sources.PoolMonitor
 }
 
 func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Capabilities returns what MindsDB supports. It binds parameters through
+// MySQL prepared statements, but has neither array types nor transactions.
+func (s *Source) Capabilities() sources.Capabilities {
+	return sources.Capabilities{
+		SupportsPreparedStatements: true,
+		SupportsArrayParams:        false,
+		SupportsTransactions:       false,
+		MaxIdentifierLength:        64,
+	}
+}
+
 func (s *Source) MindsDBPool() *sql.DB {
 	return s.Pool
 }
@@ -99,7 +148,19 @@ func (s *Source) MySQLPool() *sql.DB {
 	return s.Pool
 }
 
-func initMindsDBConnectionPool(ctx context.Context, tracer trace.Tracer, name, host, port, user, pass, dbname, queryTimeout string) (*sql.DB, error) {
+// PoolMonitor tells the invocations that failed waiting for a connection of
+// the pool.
+func (s *Source) PoolMonitor() *sources.PoolMonitor {
+	return s.monitor
+}
+
+// MindsDBHTTPURL returns the base URL of the MindsDB HTTP API, or an empty
+// string if it is not configured.
+func (s *Source) MindsDBHTTPURL() string {
+	return s.HTTPURL
+}
+
+func initMindsDBConnectionPool(ctx context.Context, tracer trace.Tracer, name, network, host, port, user, pass, dbname, queryTimeout string) (*sql.DB, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
 	defer span.End()
@@ -108,10 +169,10 @@ func initMindsDBConnectionPool(ctx context.Context, tracer trace.Tracer, name, h
 	var dsn string
 	if pass == "" {
 		// Connect without password
-		dsn = fmt.Sprintf("%s@tcp(%s:%s)/%s?parseTime=true", user, host, port, dbname)
+		dsn = fmt.Sprintf("%s@%s(%s:%s)/%s?parseTime=true", user, network, host, port, dbname)
 	} else {
 		// Connect with password
-		dsn = fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true", user, pass, host, port, dbname)
+		dsn = fmt.Sprintf("%s:%s@%s(%s:%s)/%s?parseTime=true", user, pass, network, host, port, dbname)
 	}
 
 	// Add query timeout to DSN if specified
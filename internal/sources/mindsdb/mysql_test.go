@@ -20,6 +20,7 @@ import (
 	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/sources/mindsdb"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
 )
@@ -54,6 +55,28 @@ func TestParseFromYamlMindsDB(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "without database",
+			in: `
+			sources:
+				my-mindsdb-instance:
+					kind: mindsdb
+					host: 0.0.0.0
+					port: my-port
+					user: my_user
+					password: my_pass
+			`,
+			want: server.SourceConfigs{
+				"my-mindsdb-instance": mindsdb.Config{
+					Name:     "my-mindsdb-instance",
+					Kind:     mindsdb.SourceKind,
+					Host:     "0.0.0.0",
+					Port:     "my-port",
+					User:     "my_user",
+					Password: "my_pass",
+				},
+			},
+		},
 		{
 			desc: "with query timeout",
 			in: `
@@ -80,6 +103,56 @@ func TestParseFromYamlMindsDB(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with max open conns",
+			in: `
+			sources:
+				my-mindsdb-instance:
+					kind: mindsdb
+					host: 0.0.0.0
+					port: my-port
+					database: my_db
+					user: my_user
+					password: my_pass
+					maxOpenConns: 10
+			`,
+			want: server.SourceConfigs{
+				"my-mindsdb-instance": mindsdb.Config{
+					Name:         "my-mindsdb-instance",
+					Kind:         mindsdb.SourceKind,
+					Host:         "0.0.0.0",
+					Port:         "my-port",
+					Database:     "my_db",
+					User:         "my_user",
+					Password:     "my_pass",
+					MaxOpenConns: 10,
+				},
+			},
+		},
+		{
+			desc: "with http url",
+			in: `
+			sources:
+				my-mindsdb-instance:
+					kind: mindsdb
+					host: 0.0.0.0
+					port: my-port
+					database: my_db
+					user: my_user
+					httpUrl: http://0.0.0.0:47334
+			`,
+			want: server.SourceConfigs{
+				"my-mindsdb-instance": mindsdb.Config{
+					Name:     "my-mindsdb-instance",
+					Kind:     mindsdb.SourceKind,
+					Host:     "0.0.0.0",
+					Port:     "my-port",
+					Database: "my_db",
+					User:     "my_user",
+					HTTPURL:  "http://0.0.0.0:47334",
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -151,3 +224,15 @@ func TestFailParseFromYaml(t *testing.T) {
 		})
 	}
 }
+
+func TestCapabilities(t *testing.T) {
+	want := sources.Capabilities{
+		SupportsPreparedStatements: true,
+		SupportsArrayParams:        false,
+		SupportsTransactions:       false,
+		MaxIdentifierLength:        64,
+	}
+	if diff := cmp.Diff(want, (&mindsdb.Source{}).Capabilities()); diff != "" {
+		t.Fatalf("unexpected capabilities (-want +got):\n%s", diff)
+	}
+}
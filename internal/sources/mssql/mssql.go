@@ -64,16 +64,28 @@ func (r Config) SourceConfigKind() string {
 }
 
 func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
+	return r.initialize(ctx, tracer, true)
+}
+
+// InitializeLazy creates the connection pool without connecting. The pool
+// connects when the first query is made.
+func (r Config) InitializeLazy(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
+	return r.initialize(ctx, tracer, false)
+}
+
+func (r Config) initialize(ctx context.Context, tracer trace.Tracer, ping bool) (sources.Source, error) {
 	// Initializes a MSSQL source
 	db, err := initMssqlConnection(ctx, tracer, r.Name, r.Host, r.Port, r.User, r.Password, r.Database, r.Encrypt)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create db connection: %w", err)
 	}
 
-	// Verify db connection
-	err = db.PingContext(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("unable to connect successfully: %w", err)
+	if ping {
+		// Verify db connection
+		err = db.PingContext(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to connect successfully: %w", err)
+		}
 	}
 
 	s := &Source{
@@ -84,6 +96,7 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 	return s, nil
 }
 
+var _ sources.LazyInitializer = Config{}
 var _ sources.Source = &Source{}
 
 type Source struct {
@@ -19,6 +19,7 @@ import (
 	"database/sql"
 	"fmt"
 	"net/url"
+	"slices"
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
@@ -57,21 +58,69 @@ type Config struct {
 	Database     string            `yaml:"database" validate:"required"`
 	QueryTimeout string            `yaml:"queryTimeout"`
 	QueryParams  map[string]string `yaml:"queryParams"`
+	// TLS is the TLS mode of connections: "true", "false", "skip-verify" or
+	// "preferred".
+	TLS string `yaml:"tls"`
+	// MaxOpenConns, MaxIdleConns, ConnMaxLifetime and ConnMaxIdleTime tune
+	// the connection pool. They are left to the defaults of database/sql
+	// when not set.
+	MaxOpenConns    int    `yaml:"maxOpenConns"`
+	MaxIdleConns    int    `yaml:"maxIdleConns"`
+	ConnMaxLifetime string `yaml:"connMaxLifetime"`
+	ConnMaxIdleTime string `yaml:"connMaxIdleTime"`
+	// SSHTunnel, if set, connects to the database through an SSH bastion.
+	SSHTunnel *sources.SSHTunnelConfig `yaml:"sshTunnel"`
 }
 
+// tlsModes are the TLS modes supported by the driver, besides the names of
+// custom TLS configs, which cannot be registered from a tools file.
+var tlsModes = []string{"true", "false", "skip-verify", "preferred"}
+
 func (r Config) SourceConfigKind() string {
 	return SourceKind
 }
 
 func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
-	pool, err := initMySQLConnectionPool(ctx, tracer, r.Name, r.Host, r.Port, r.User, r.Password, r.Database, r.QueryTimeout, r.QueryParams)
+	return r.initialize(ctx, tracer, true)
+}
+
+// InitializeLazy creates the connection pool without connecting. The pool
+// connects when the first query is made.
+func (r Config) InitializeLazy(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
+	return r.initialize(ctx, tracer, false)
+}
+
+func (r Config) initialize(ctx context.Context, tracer trace.Tracer, ping bool) (sources.Source, error) {
+	if r.TLS != "" {
+		if !slices.Contains(tlsModes, r.TLS) {
+			return nil, fmt.Errorf("invalid tls %q: must be one of %q", r.TLS, tlsModes)
+		}
+		if _, ok := r.QueryParams["tls"]; ok {
+			return nil, fmt.Errorf("tls cannot be set both as a field and in queryParams")
+		}
+	}
+	tunnel, err := sources.NewSSHTunnel(ctx, r.SSHTunnel)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := initMySQLConnectionPool(ctx, tracer, r.Name, tunnel.MySQLNetwork(), r.Host, r.Port, r.User, r.Password, r.Database, r.QueryTimeout, r.TLS, r.QueryParams)
 	if err != nil {
+		_ = tunnel.Close()
 		return nil, fmt.Errorf("unable to create pool: %w", err)
 	}
+	if err := r.configurePool(pool); err != nil {
+		pool.Close()
+		_ = tunnel.Close()
+		return nil, err
+	}
 
-	err = pool.PingContext(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("unable to connect successfully: %w", err)
+	if ping {
+		err = pool.PingContext(ctx)
+		if err != nil {
+			pool.Close()
+			_ = tunnel.Close()
+			return nil, fmt.Errorf("unable to connect successfully: %w", err)
+		}
 	}
 
 	s := &Source{
@@ -82,7 +131,39 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 	return s, nil
 }
 
-var _ sources.Source = &Source{}
+// configurePool applies the pool tuning of the config to pool.
+func (r Config) configurePool(pool *sql.DB) error {
+	if r.MaxOpenConns < 0 {
+		return fmt.Errorf("maxOpenConns must not be negative, got %d", r.MaxOpenConns)
+	}
+	if r.MaxIdleConns < 0 {
+		return fmt.Errorf("maxIdleConns must not be negative, got %d", r.MaxIdleConns)
+	}
+	if r.MaxOpenConns > 0 {
+		pool.SetMaxOpenConns(r.MaxOpenConns)
+	}
+	if r.MaxIdleConns > 0 {
+		pool.SetMaxIdleConns(r.MaxIdleConns)
+	}
+	if r.ConnMaxLifetime != "" {
+		d, err := time.ParseDuration(r.ConnMaxLifetime)
+		if err != nil {
+			return fmt.Errorf("invalid connMaxLifetime %q: %w", r.ConnMaxLifetime, err)
+		}
+		pool.SetConnMaxLifetime(d)
+	}
+	if r.ConnMaxIdleTime != "" {
+		d, err := time.ParseDuration(r.ConnMaxIdleTime)
+		if err != nil {
+			return fmt.Errorf("invalid connMaxIdleTime %q: %w", r.ConnMaxIdleTime, err)
+		}
+		pool.SetConnMaxIdleTime(d)
+	}
+	return nil
+}
+
+var _ sources.LazyInitializer = Config{}
+var _ sources.SchemaSource = &Source{}
 
 type Source struct {
 	Name string `yaml:"name"`
@@ -98,7 +179,38 @@ func (s *Source) MySQLPool() *sql.DB {
 	return s.Pool
 }
 
-func initMySQLConnectionPool(ctx context.Context, tracer trace.Tracer, name, host, port, user, pass, dbname, queryTimeout string, queryParams map[string]string) (*sql.DB, error) {
+// schemaStatement lists the columns of all tables and views in the current
+// database.
+const schemaStatement = `
+	SELECT table_name, column_name, column_type, is_nullable = 'YES'
+	FROM information_schema.columns
+	WHERE table_schema = DATABASE()
+	ORDER BY table_name, ordinal_position`
+
+// Schema returns the tables and columns of the database.
+func (s *Source) Schema(ctx context.Context) (*sources.Schema, error) {
+	rows, err := s.Pool.QueryContext(ctx, schemaStatement)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query schema: %w", err)
+	}
+	defer rows.Close()
+
+	schema := &sources.Schema{Tables: []sources.TableSchema{}}
+	for rows.Next() {
+		var table string
+		var column sources.ColumnSchema
+		if err := rows.Scan(&table, &column.Name, &column.Type, &column.Nullable); err != nil {
+			return nil, fmt.Errorf("unable to parse schema row: %w", err)
+		}
+		schema.AppendColumn("", table, column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("unable to query schema: %w", err)
+	}
+	return schema, nil
+}
+
+func initMySQLConnectionPool(ctx context.Context, tracer trace.Tracer, name, network, host, port, user, pass, dbname, queryTimeout, tls string, queryParams map[string]string) (*sql.DB, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
 	defer span.End()
@@ -115,6 +227,10 @@ func initMySQLConnectionPool(ctx context.Context, tracer trace.Tracer, name, hos
 		values.Set("readTimeout", timeout.String())
 	}
 
+	if tls != "" {
+		values.Set("tls", tls)
+	}
+
 	// Custom user parameters
 	for k, v := range queryParams {
 		if v == "" {
@@ -127,7 +243,7 @@ func initMySQLConnectionPool(ctx context.Context, tracer trace.Tracer, name, hos
 	if err != nil {
 		return nil, err
 	}
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true&connectionAttributes=program_name:%s", user, pass, host, port, dbname, url.QueryEscape(userAgent))
+	dsn := fmt.Sprintf("%s:%s@%s(%s:%s)/%s?parseTime=true&connectionAttributes=program_name:%s", user, pass, network, host, port, dbname, url.QueryEscape(userAgent))
 	if enc := values.Encode(); enc != "" {
 		dsn += "&" + enc
 	}
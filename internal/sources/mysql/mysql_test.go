@@ -25,8 +25,10 @@ import (
 	"go.opentelemetry.io/otel/trace/noop"
 
 	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/sources/mysql"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/util"
 )
 
 func TestParseFromYamlCloudSQLMySQL(t *testing.T) {
@@ -116,6 +118,79 @@ func TestParseFromYamlCloudSQLMySQL(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with tls and pool tuning",
+			in: `
+			sources:
+				my-mysql-instance:
+					kind: mysql
+					host: 0.0.0.0
+					port: my-port
+					database: my_db
+					user: my_user
+					password: my_pass
+					tls: skip-verify
+					maxOpenConns: 20
+					maxIdleConns: 5
+					connMaxLifetime: 30m
+					connMaxIdleTime: 5m
+			`,
+			want: server.SourceConfigs{
+				"my-mysql-instance": mysql.Config{
+					Name:            "my-mysql-instance",
+					Kind:            mysql.SourceKind,
+					Host:            "0.0.0.0",
+					Port:            "my-port",
+					Database:        "my_db",
+					User:            "my_user",
+					Password:        "my_pass",
+					TLS:             "skip-verify",
+					MaxOpenConns:    20,
+					MaxIdleConns:    5,
+					ConnMaxLifetime: "30m",
+					ConnMaxIdleTime: "5m",
+				},
+			},
+		},
+		{
+			desc: "with ssh tunnel",
+			in: `
+			sources:
+				my-mysql-instance:
+					kind: mysql
+					host: db.internal
+					port: 3306
+					database: my_db
+					user: my_user
+					password: my_pass
+					sshTunnel:
+						host: bastion.example.com
+						port: 2222
+						user: tunnel
+						privateKeyEnv: BASTION_KEY
+						insecureSkipHostKeyVerification: true
+						keepAliveInterval: 10s
+			`,
+			want: server.SourceConfigs{
+				"my-mysql-instance": mysql.Config{
+					Name:     "my-mysql-instance",
+					Kind:     mysql.SourceKind,
+					Host:     "db.internal",
+					Port:     "3306",
+					Database: "my_db",
+					User:     "my_user",
+					Password: "my_pass",
+					SSHTunnel: &sources.SSHTunnelConfig{
+						Host:                            "bastion.example.com",
+						Port:                            "2222",
+						User:                            "tunnel",
+						PrivateKeyEnv:                   "BASTION_KEY",
+						InsecureSkipHostKeyVerification: true,
+						KeepAliveInterval:               "10s",
+					},
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -209,6 +284,86 @@ func TestFailParseFromYaml(t *testing.T) {
 func TestFailInitialization(t *testing.T) {
 	t.Parallel()
 
+	base := mysql.Config{
+		Name:     "instance",
+		Kind:     "mysql",
+		Host:     "localhost",
+		Port:     "3306",
+		Database: "db",
+		User:     "user",
+		Password: "pass",
+	}
+	tcs := []struct {
+		desc   string
+		modify func(*mysql.Config)
+		err    string
+	}{
+		{
+			desc:   "invalid query timeout",
+			modify: func(c *mysql.Config) { c.QueryTimeout = "abc" },
+			err:    "invalid queryTimeout",
+		},
+		{
+			desc:   "invalid tls",
+			modify: func(c *mysql.Config) { c.TLS = "required" },
+			err:    `invalid tls "required"`,
+		},
+		{
+			desc: "ssh tunnel without key",
+			modify: func(c *mysql.Config) {
+				c.SSHTunnel = &sources.SSHTunnelConfig{Host: "bastion", User: "tunnel", InsecureSkipHostKeyVerification: true}
+			},
+			err: "invalid sshTunnel: exactly one of privateKeyFile or privateKeyEnv must be set",
+		},
+		{
+			desc: "tls set twice",
+			modify: func(c *mysql.Config) {
+				c.TLS = "true"
+				c.QueryParams = map[string]string{"tls": "false"}
+			},
+			err: "tls cannot be set both as a field and in queryParams",
+		},
+		{
+			desc:   "negative max open conns",
+			modify: func(c *mysql.Config) { c.MaxOpenConns = -1 },
+			err:    "maxOpenConns must not be negative",
+		},
+		{
+			desc:   "negative max idle conns",
+			modify: func(c *mysql.Config) { c.MaxIdleConns = -1 },
+			err:    "maxIdleConns must not be negative",
+		},
+		{
+			desc:   "invalid conn max lifetime",
+			modify: func(c *mysql.Config) { c.ConnMaxLifetime = "forever" },
+			err:    "invalid connMaxLifetime",
+		},
+		{
+			desc:   "invalid conn max idle time",
+			modify: func(c *mysql.Config) { c.ConnMaxIdleTime = "a while" },
+			err:    "invalid connMaxIdleTime",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			cfg := base
+			tc.modify(&cfg)
+			ctx := util.WithUserAgent(context.Background(), "test")
+			_, err := cfg.Initialize(ctx, noop.NewTracerProvider().Tracer("test"))
+			if err == nil {
+				t.Fatalf("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("unexpected error: got %q, want substring %q", err, tc.err)
+			}
+		})
+	}
+}
+
+// TestInitializeLazyPoolTuning verifies the pool tuning is applied without
+// connecting.
+func TestInitializeLazyPoolTuning(t *testing.T) {
 	cfg := mysql.Config{
 		Name:         "instance",
 		Kind:         "mysql",
@@ -217,13 +372,16 @@ func TestFailInitialization(t *testing.T) {
 		Database:     "db",
 		User:         "user",
 		Password:     "pass",
-		QueryTimeout: "abc", // invalid duration
+		TLS:          "preferred",
+		MaxOpenConns: 7,
 	}
-	_, err := cfg.Initialize(context.Background(), noop.NewTracerProvider().Tracer("test"))
-	if err == nil {
-		t.Fatalf("expected error for invalid queryTimeout, got nil")
+	src, err := cfg.InitializeLazy(util.WithUserAgent(context.Background(), "test"), noop.NewTracerProvider().Tracer("test"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
 	}
-	if !strings.Contains(err.Error(), "invalid queryTimeout") {
-		t.Fatalf("unexpected error: %v", err)
+	pool := src.(*mysql.Source).MySQLPool()
+	defer pool.Close()
+	if got := pool.Stats().MaxOpenConnections; got != 7 {
+		t.Fatalf("got %d max open connections, want 7", got)
 	}
 }
@@ -0,0 +1,95 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sources
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PgPoolConfig tunes the connection pool of the Postgres sources. The fields
+// that are not set are left to the defaults of pgxpool.
+type PgPoolConfig struct {
+	MaxConns          int32  `yaml:"maxConns"`
+	MinConns          int32  `yaml:"minConns"`
+	MaxConnLifetime   string `yaml:"maxConnLifetime"`
+	MaxConnIdleTime   string `yaml:"maxConnIdleTime"`
+	HealthCheckPeriod string `yaml:"healthCheckPeriod"`
+	// StatementCacheMode is how statements are prepared: "prepare" caches
+	// prepared statements on each connection, "describe" only caches their
+	// descriptions, and "disabled" prepares them again on every query, as
+	// needed behind pgbouncer in transaction mode. Defaults to "prepare".
+	StatementCacheMode string `yaml:"statementCacheMode"`
+}
+
+// statementCacheModes maps the values of PgPoolConfig.StatementCacheMode to
+// the query execution modes of pgx.
+var statementCacheModes = map[string]pgx.QueryExecMode{
+	"prepare":  pgx.QueryExecModeCacheStatement,
+	"describe": pgx.QueryExecModeCacheDescribe,
+	"disabled": pgx.QueryExecModeExec,
+}
+
+// Apply validates the tuning and applies it to config.
+func (c PgPoolConfig) Apply(config *pgxpool.Config) error {
+	if c.MaxConns < 0 {
+		return fmt.Errorf("maxConns must not be negative, got %d", c.MaxConns)
+	}
+	if c.MinConns < 0 {
+		return fmt.Errorf("minConns must not be negative, got %d", c.MinConns)
+	}
+	if c.MaxConns > 0 {
+		config.MaxConns = c.MaxConns
+	}
+	if c.MinConns > config.MaxConns {
+		return fmt.Errorf("minConns (%d) must not be greater than maxConns (%d)", c.MinConns, config.MaxConns)
+	}
+	if c.MinConns > 0 {
+		config.MinConns = c.MinConns
+	}
+	durations := []struct {
+		field string
+		value string
+		dst   *time.Duration
+	}{
+		{"maxConnLifetime", c.MaxConnLifetime, &config.MaxConnLifetime},
+		{"maxConnIdleTime", c.MaxConnIdleTime, &config.MaxConnIdleTime},
+		{"healthCheckPeriod", c.HealthCheckPeriod, &config.HealthCheckPeriod},
+	}
+	for _, d := range durations {
+		if d.value == "" {
+			continue
+		}
+		v, err := time.ParseDuration(d.value)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: %w", d.field, d.value, err)
+		}
+		if v <= 0 {
+			return fmt.Errorf("invalid %s %q: must be positive", d.field, d.value)
+		}
+		*d.dst = v
+	}
+	if c.StatementCacheMode != "" {
+		mode, ok := statementCacheModes[c.StatementCacheMode]
+		if !ok {
+			return fmt.Errorf(`invalid statementCacheMode %q: must be "prepare", "describe" or "disabled"`, c.StatementCacheMode)
+		}
+		config.ConnConfig.DefaultQueryExecMode = mode
+	}
+	return nil
+}
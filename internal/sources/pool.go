@@ -0,0 +1,166 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sources
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PoolSource is implemented by the sources whose queries wait for a
+// connection of a pool of limited size.
+type PoolSource interface {
+	Source
+	PoolMonitor() *PoolMonitor
+}
+
+// PoolStats are the connections of a pool.
+type PoolStats struct {
+	InUse int `json:"inUse"`
+	Idle  int `json:"idle"`
+	Max   int `json:"max"`
+}
+
+// PoolMonitor tells the invocations that failed waiting for a connection of
+// the exhausted pool of a source, and estimates when connections are
+// released from the durations of the recent invocations.
+type PoolMonitor struct {
+	// db is the pool of a database/sql source, whose acquisitions cannot be
+	// traced. It is nil for pgx pools.
+	db *sql.DB
+
+	mu      sync.Mutex
+	average time.Duration
+}
+
+// NewPgPoolMonitor traces the acquisitions of the pool of config, which must
+// not have a tracer of its own, and returns its PoolMonitor.
+func NewPgPoolMonitor(config *pgxpool.Config) *PoolMonitor {
+	config.ConnConfig.Tracer = pgPoolTracer{}
+	return &PoolMonitor{}
+}
+
+// NewSQLPoolMonitor returns the PoolMonitor of a database/sql pool. As its
+// acquisitions cannot be traced, an invocation is deemed to have failed
+// waiting for a connection if it failed with a context error while all the
+// connections of the pool were in use, and queries had to wait for them.
+func NewSQLPoolMonitor(db *sql.DB) *PoolMonitor {
+	return &PoolMonitor{db: db}
+}
+
+// averageWeight is the weight of each invocation in the average duration.
+const averageWeight = 0.2
+
+// RecordInvocation records the duration of a successful invocation.
+func (m *PoolMonitor) RecordInvocation(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.average == 0 {
+		m.average = d
+		return
+	}
+	m.average += time.Duration(averageWeight * float64(d-m.average))
+}
+
+// RetryAfter is how long to wait for a connection to be released: the
+// average duration of the recent invocations, rounded up to the second and
+// at least a second.
+func (m *PoolMonitor) RetryAfter() time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	seconds := math.Ceil(m.average.Seconds())
+	return time.Duration(max(1, seconds)) * time.Second
+}
+
+type poolWatchKey struct{}
+
+// PoolWatch watches the queries of an invocation for failures to acquire a
+// connection of an exhausted pool.
+type PoolWatch struct {
+	monitor *PoolMonitor
+	// waits is the number of waits for a connection of a database/sql pool
+	// when the invocation started.
+	waits int64
+
+	mu    sync.Mutex
+	stats *PoolStats
+}
+
+// Watch returns ctx watched for the queries made with it.
+func (m *PoolMonitor) Watch(ctx context.Context) (context.Context, *PoolWatch) {
+	w := &PoolWatch{monitor: m}
+	if m.db != nil {
+		w.waits = m.db.Stats().WaitCount
+	}
+	return context.WithValue(ctx, poolWatchKey{}, w), w
+}
+
+// Exhausted reports whether err, returned by the invocation, was caused by
+// waiting for a connection of the exhausted pool, and the stats of the pool
+// then.
+func (w *PoolWatch) Exhausted(err error) (PoolStats, bool) {
+	if !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, context.Canceled) {
+		return PoolStats{}, false
+	}
+	if db := w.monitor.db; db != nil {
+		s := db.Stats()
+		if s.MaxOpenConnections == 0 || s.InUse < s.MaxOpenConnections || s.WaitCount == w.waits {
+			return PoolStats{}, false
+		}
+		return PoolStats{InUse: s.InUse, Idle: s.Idle, Max: s.MaxOpenConnections}, true
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.stats == nil {
+		return PoolStats{}, false
+	}
+	return *w.stats, true
+}
+
+// pgPoolTracer records the failed acquisitions of a pgx pool whose
+// connections are all in use in the PoolWatch of their context.
+type pgPoolTracer struct{}
+
+func (pgPoolTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryStartData) context.Context {
+	return ctx
+}
+
+func (pgPoolTracer) TraceQueryEnd(context.Context, *pgx.Conn, pgx.TraceQueryEndData) {}
+
+func (pgPoolTracer) TraceAcquireStart(ctx context.Context, _ *pgxpool.Pool, _ pgxpool.TraceAcquireStartData) context.Context {
+	return ctx
+}
+
+func (pgPoolTracer) TraceAcquireEnd(ctx context.Context, pool *pgxpool.Pool, data pgxpool.TraceAcquireEndData) {
+	w, ok := ctx.Value(poolWatchKey{}).(*PoolWatch)
+	if !ok || data.Err == nil {
+		return
+	}
+	s := pool.Stat()
+	if s.AcquiredConns() < s.MaxConns() {
+		// the acquisition failed to connect, rather than waiting
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.stats = &PoolStats{InUse: int(s.AcquiredConns()), Idle: int(s.IdleConns()), Max: int(s.MaxConns())}
+}
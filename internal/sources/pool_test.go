@@ -0,0 +1,114 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sources_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	_ "modernc.org/sqlite"
+)
+
+// newSingleConnDB returns a database/sql pool of at most one connection.
+func newSingleConnDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("unable to open database: %s", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	db.SetMaxOpenConns(1)
+	return db
+}
+
+// query runs a query with a short deadline, watched by monitor.
+func query(monitor *sources.PoolMonitor, db *sql.DB) (*sources.PoolWatch, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	ctx, watch := monitor.Watch(ctx)
+	var v int
+	return watch, db.QueryRowContext(ctx, "SELECT 1").Scan(&v)
+}
+
+func TestSQLPoolMonitorExhausted(t *testing.T) {
+	db := newSingleConnDB(t)
+	monitor := sources.NewSQLPoolMonitor(db)
+
+	// the only connection is held, as by a slow query
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatalf("unable to get a connection: %s", err)
+	}
+	watch, err := query(monitor, db)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("unexpected error: got %v, want a deadline error", err)
+	}
+	stats, ok := watch.Exhausted(err)
+	if !ok {
+		t.Fatalf("expected the pool to be reported exhausted")
+	}
+	if want := (sources.PoolStats{InUse: 1, Idle: 0, Max: 1}); stats != want {
+		t.Fatalf("unexpected stats: got %+v, want %+v", stats, want)
+	}
+
+	// queries succeed again once the connection is released
+	if err := conn.Close(); err != nil {
+		t.Fatalf("unable to release the connection: %s", err)
+	}
+	if _, err := query(monitor, db); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestSQLPoolMonitorNotExhausted(t *testing.T) {
+	db := newSingleConnDB(t)
+	monitor := sources.NewSQLPoolMonitor(db)
+
+	tcs := []struct {
+		desc string
+		err  error
+	}{
+		{desc: "other error", err: errors.New("syntax error")},
+		{desc: "deadline without waiting", err: context.DeadlineExceeded},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, watch := monitor.Watch(context.Background())
+			if _, ok := watch.Exhausted(tc.err); ok {
+				t.Fatalf("unexpected exhausted pool for %v", tc.err)
+			}
+		})
+	}
+}
+
+func TestPoolMonitorRetryAfter(t *testing.T) {
+	monitor := sources.NewSQLPoolMonitor(nil)
+	if got := monitor.RetryAfter(); got != time.Second {
+		t.Fatalf("unexpected retry after without invocations: got %s, want 1s", got)
+	}
+	monitor.RecordInvocation(2500 * time.Millisecond)
+	if got := monitor.RetryAfter(); got != 3*time.Second {
+		t.Fatalf("unexpected retry after: got %s, want 3s", got)
+	}
+	// recent invocations weigh a fifth of the average
+	monitor.RecordInvocation(12500 * time.Millisecond)
+	if got := monitor.RetryAfter(); got != 5*time.Second {
+		t.Fatalf("unexpected retry after: got %s, want 5s", got)
+	}
+}
@@ -16,6 +16,7 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
 	"strings"
@@ -23,6 +24,8 @@ import (
 	"github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/util"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -47,14 +50,24 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources
 }
 
 type Config struct {
-	Name        string            `yaml:"name" validate:"required"`
-	Kind        string            `yaml:"kind" validate:"required"`
-	Host        string            `yaml:"host" validate:"required"`
-	Port        string            `yaml:"port" validate:"required"`
-	User        string            `yaml:"user" validate:"required"`
-	Password    string            `yaml:"password" validate:"required"`
+	Name     string `yaml:"name" validate:"required"`
+	Kind     string `yaml:"kind" validate:"required"`
+	Host     string `yaml:"host" validate:"required"`
+	Port     string `yaml:"port" validate:"required"`
+	User     string `yaml:"user" validate:"required"`
+	Password string `yaml:"password" validate:"required_without_all=PasswordFile PasswordEnv"`
+	// PasswordFile is a file the password is read from. It is read again
+	// when the database rejects the credentials.
+	PasswordFile string `yaml:"passwordFile"`
+	// PasswordEnv is an environment variable the password is read from. It is
+	// read again when the database rejects the credentials.
+	PasswordEnv string            `yaml:"passwordEnv"`
 	Database    string            `yaml:"database" validate:"required"`
 	QueryParams map[string]string `yaml:"queryParams"`
+	// SSHTunnel, if set, connects to the database through an SSH bastion.
+	SSHTunnel *sources.SSHTunnelConfig `yaml:"sshTunnel"`
+	// PgPoolConfig tunes the connection pool.
+	sources.PgPoolConfig `yaml:",inline"`
 }
 
 func (r Config) SourceConfigKind() string {
@@ -62,30 +75,61 @@ func (r Config) SourceConfigKind() string {
 }
 
 func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
-	pool, err := initPostgresConnectionPool(ctx, tracer, r.Name, r.Host, r.Port, r.User, r.Password, r.Database, r.QueryParams)
+	return r.initialize(ctx, tracer, true)
+}
+
+// InitializeLazy creates the connection pool without connecting. The pool
+// connects when the first query is made.
+func (r Config) InitializeLazy(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
+	return r.initialize(ctx, tracer, false)
+}
+
+func (r Config) initialize(ctx context.Context, tracer trace.Tracer, ping bool) (sources.Source, error) {
+	provider, err := sources.NewCredentialProvider(r.User, r.Password, r.PasswordFile, r.PasswordEnv)
+	if err != nil {
+		return nil, err
+	}
+	tunnel, err := sources.NewSSHTunnel(ctx, r.SSHTunnel)
+	if err != nil {
+		return nil, err
+	}
+	pool, rotator, monitor, err := initPostgresConnectionPool(ctx, tracer, r.Name, r.Host, r.Port, provider, r.Database, r.QueryParams, r.PgPoolConfig, tunnel)
 	if err != nil {
+		_ = tunnel.Close()
 		return nil, fmt.Errorf("unable to create pool: %w", err)
 	}
 
-	err = pool.Ping(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("unable to connect successfully: %w", err)
+	if ping {
+		err = pool.Ping(ctx)
+		if err != nil {
+			pool.Close()
+			_ = tunnel.Close()
+			return nil, fmt.Errorf("unable to connect successfully: %w", err)
+		}
 	}
 
 	s := &Source{
-		Name: r.Name,
-		Kind: SourceKind,
-		Pool: pool,
+		Name:    r.Name,
+		Kind:    SourceKind,
+		Pool:    pool,
+		rotator: rotator,
+		monitor: monitor,
 	}
 	return s, nil
 }
 
-var _ sources.Source = &Source{}
+var _ sources.LazyInitializer = Config{}
+var _ sources.SchemaSource = &Source{}
+var _ sources.RotatableSource = &Source{}
+var _ sources.CapabilitiesSource = &Source{}
+var _ sources.PoolSource = &Source{}
 
 type Source struct {
-	Name string `yaml:"name"`
-	Kind string `yaml:"kind"`
-	Pool *pgxpool.Pool
+	Name    string `yaml:"name"`
+	Kind    string `yaml:"kind"`
+	Pool    *pgxpool.Pool
+	rotator *sources.CredentialRotator
+	monitor *sources.PoolMonitor
 }
 
 func (s *Source) SourceKind() string {
@@ -96,7 +140,76 @@ func (s *Source) PostgresPool() *pgxpool.Pool {
 	return s.Pool
 }
 
-func initPostgresConnectionPool(ctx context.Context, tracer trace.Tracer, name, host, port, user, pass, dbname string, queryParams map[string]string) (*pgxpool.Pool, error) {
+// PoolMonitor tells the invocations that failed waiting for a connection of
+// the pool.
+func (s *Source) PoolMonitor() *sources.PoolMonitor {
+	return s.monitor
+}
+
+// Capabilities returns what Postgres supports. Arrays are bound as Postgres
+// arrays, such as for `= ANY($1)`.
+func (s *Source) Capabilities() sources.Capabilities {
+	return sources.Capabilities{
+		SupportsPreparedStatements: true,
+		SupportsArrayParams:        true,
+		SupportsTransactions:       true,
+		MaxIdentifierLength:        63,
+	}
+}
+
+// RotateCredentials switches the pool to new credentials. Idle connections are
+// closed right away and connections in use are closed once they are released,
+// so that new connections are made with the new credentials.
+func (s *Source) RotateCredentials(ctx context.Context, creds *sources.Credentials) error {
+	if s.rotator == nil {
+		return fmt.Errorf("source %q does not support credential rotation", s.Name)
+	}
+	return s.rotator.Rotate(ctx, creds)
+}
+
+// IsAuthError reports whether err was caused by the server rejecting the
+// user name or password.
+func (s *Source) IsAuthError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	// invalid_password and invalid_authorization_specification
+	return pgErr.Code == "28P01" || pgErr.Code == "28000"
+}
+
+// schemaStatement lists the columns of all user tables and views.
+const schemaStatement = `
+	SELECT table_schema, table_name, column_name, data_type, is_nullable = 'YES'
+	FROM information_schema.columns
+	WHERE table_schema NOT IN ('pg_catalog', 'information_schema')
+		AND table_schema NOT LIKE 'pg_toast%'
+	ORDER BY table_schema, table_name, ordinal_position`
+
+// Schema returns the tables and columns of the database.
+func (s *Source) Schema(ctx context.Context) (*sources.Schema, error) {
+	rows, err := s.Pool.Query(ctx, schemaStatement)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query schema: %w", err)
+	}
+	defer rows.Close()
+
+	schema := &sources.Schema{Tables: []sources.TableSchema{}}
+	for rows.Next() {
+		var tableSchema, table string
+		var column sources.ColumnSchema
+		if err := rows.Scan(&tableSchema, &table, &column.Name, &column.Type, &column.Nullable); err != nil {
+			return nil, fmt.Errorf("unable to parse schema row: %w", err)
+		}
+		schema.AppendColumn(tableSchema, table, column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("unable to query schema: %w", err)
+	}
+	return schema, nil
+}
+
+func initPostgresConnectionPool(ctx context.Context, tracer trace.Tracer, name, host, port string, provider sources.CredentialProvider, dbname string, queryParams map[string]string, poolConfig sources.PgPoolConfig, tunnel *sources.SSHTunnel) (*pgxpool.Pool, *sources.CredentialRotator, *sources.PoolMonitor, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
 	defer span.End()
@@ -112,20 +225,58 @@ func initPostgresConnectionPool(ctx context.Context, tracer trace.Tracer, name,
 		queryParams["application_name"] = userAgent
 	}
 
-	// urlExample := "postgres:dd//username:password@localhost:5432/database_name"
+	// urlExample := "postgres:dd//localhost:5432/database_name"
+	// the credentials are set on each new connection so that they can be rotated
 	url := &url.URL{
 		Scheme:   "postgres",
-		User:     url.UserPassword(user, pass),
 		Host:     fmt.Sprintf("%s:%s", host, port),
 		Path:     dbname,
 		RawQuery: ConvertParamMapToRawQuery(queryParams),
 	}
-	pool, err := pgxpool.New(ctx, url.String())
+	config, err := pgxpool.ParseConfig(url.String())
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("unable to parse connection config: %w", err)
+	}
+	if err := poolConfig.Apply(config); err != nil {
+		return nil, nil, nil, err
+	}
+	monitor := sources.NewPgPoolMonitor(config)
+	if tunnel != nil {
+		config.ConnConfig.DialFunc = tunnel.DialContext
+		// the host is resolved by the bastion, which may be the only one
+		// able to
+		config.ConnConfig.LookupFunc = func(_ context.Context, host string) ([]string, error) {
+			return []string{host}, nil
+		}
+	}
+
+	var pool *pgxpool.Pool
+	validate := func(ctx context.Context, creds sources.Credentials) error {
+		connConfig := config.ConnConfig.Copy()
+		connConfig.User, connConfig.Password = creds.User, creds.Password
+		conn, err := pgx.ConnectConfig(ctx, connConfig)
+		if err != nil {
+			return err
+		}
+		return conn.Close(ctx)
+	}
+	rotator, err := sources.NewCredentialRotator(provider, validate, func() { pool.Reset() })
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	config.BeforeConnect = func(_ context.Context, connConfig *pgx.ConnConfig) error {
+		creds := rotator.Current()
+		connConfig.User, connConfig.Password = creds.User, creds.Password
+		return nil
+	}
+	config.PrepareConn = sources.PrepareAttributedPgConn
+
+	pool, err = pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
-		return nil, fmt.Errorf("unable to create connection pool: %w", err)
+		return nil, nil, nil, fmt.Errorf("unable to create connection pool: %w", err)
 	}
 
-	return pool, nil
+	return pool, rotator, monitor, nil
 }
 
 func ConvertParamMapToRawQuery(queryParams map[string]string) string {
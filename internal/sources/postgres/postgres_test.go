@@ -15,15 +15,21 @@
 package postgres_test
 
 import (
+	"context"
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/sources/postgres"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 func TestParseFromYamlPostgres(t *testing.T) {
@@ -87,6 +93,103 @@ func TestParseFromYamlPostgres(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "example with password file",
+			in: `
+			sources:
+				my-pg-instance:
+					kind: postgres
+					host: my-host
+					port: my-port
+					database: my_db
+					user: my_user
+					passwordFile: /run/secrets/pg_password
+			`,
+			want: server.SourceConfigs{
+				"my-pg-instance": postgres.Config{
+					Name:         "my-pg-instance",
+					Kind:         postgres.SourceKind,
+					Host:         "my-host",
+					Port:         "my-port",
+					Database:     "my_db",
+					User:         "my_user",
+					PasswordFile: "/run/secrets/pg_password",
+				},
+			},
+		},
+		{
+			desc: "example with ssh tunnel",
+			in: `
+			sources:
+				my-pg-instance:
+					kind: postgres
+					host: db.internal
+					port: 5432
+					database: my_db
+					user: my_user
+					password: my_pass
+					sshTunnel:
+						host: bastion.example.com
+						user: tunnel
+						privateKeyFile: /run/secrets/id_ed25519
+						knownHostsFile: /etc/ssh/ssh_known_hosts
+			`,
+			want: server.SourceConfigs{
+				"my-pg-instance": postgres.Config{
+					Name:     "my-pg-instance",
+					Kind:     postgres.SourceKind,
+					Host:     "db.internal",
+					Port:     "5432",
+					Database: "my_db",
+					User:     "my_user",
+					Password: "my_pass",
+					SSHTunnel: &sources.SSHTunnelConfig{
+						Host:           "bastion.example.com",
+						User:           "tunnel",
+						PrivateKeyFile: "/run/secrets/id_ed25519",
+						KnownHostsFile: "/etc/ssh/ssh_known_hosts",
+					},
+				},
+			},
+		},
+		{
+			desc: "example with pool settings",
+			in: `
+			sources:
+				my-pg-instance:
+					kind: postgres
+					host: my-host
+					port: my-port
+					database: my_db
+					user: my_user
+					password: my_pass
+					maxConns: 20
+					minConns: 2
+					maxConnLifetime: 30m
+					maxConnIdleTime: 5m
+					healthCheckPeriod: 30s
+					statementCacheMode: disabled
+			`,
+			want: server.SourceConfigs{
+				"my-pg-instance": postgres.Config{
+					Name:     "my-pg-instance",
+					Kind:     postgres.SourceKind,
+					Host:     "my-host",
+					Port:     "my-port",
+					Database: "my_db",
+					User:     "my_user",
+					Password: "my_pass",
+					PgPoolConfig: sources.PgPoolConfig{
+						MaxConns:           20,
+						MinConns:           2,
+						MaxConnLifetime:    "30m",
+						MaxConnIdleTime:    "5m",
+						HealthCheckPeriod:  "30s",
+						StatementCacheMode: "disabled",
+					},
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -138,7 +241,24 @@ func TestFailParseFromYaml(t *testing.T) {
 					database: my_db
 					user: my_user
 			`,
-			err: "unable to parse source \"my-pg-instance\" as \"postgres\": Key: 'Config.Password' Error:Field validation for 'Password' failed on the 'required' tag",
+			err: "unable to parse source \"my-pg-instance\" as \"postgres\": Key: 'Config.Password' Error:Field validation for 'Password' failed on the 'required_without_all' tag",
+		},
+		{
+			desc: "missing ssh tunnel user",
+			in: `
+			sources:
+				my-pg-instance:
+					kind: postgres
+					host: my-host
+					port: my-port
+					database: my_db
+					user: my_user
+					password: my_pass
+					sshTunnel:
+						host: bastion.example.com
+						privateKeyEnv: SSH_KEY
+			`,
+			err: "unable to parse source \"my-pg-instance\" as \"postgres\": [6:10] Key: 'SSHTunnelConfig.User' Error:Field validation for 'User' failed on the 'required' tag\n   3 | kind: postgres\n   4 | password: my_pass\n   5 | port: my-port\n>  6 | sshTunnel:\n                ^\n   7 |   host: bastion.example.com\n   8 |   privateKeyEnv: SSH_KEY\n   9 | user: my_user",
 		},
 	}
 	for _, tc := range tcs {
@@ -200,3 +320,106 @@ func TestConvertParamMapToRawQuery(t *testing.T) {
 		})
 	}
 }
+
+// TestInitializeLazyPoolConfig verifies the pool settings are applied to the
+// pool without connecting.
+func TestInitializeLazyPoolConfig(t *testing.T) {
+	cfg := postgres.Config{
+		Name:     "my-pg-instance",
+		Kind:     postgres.SourceKind,
+		Host:     "localhost",
+		Port:     "5432",
+		Database: "my_db",
+		User:     "my_user",
+		Password: "my_pass",
+		PgPoolConfig: sources.PgPoolConfig{
+			MaxConns:           20,
+			MinConns:           2,
+			MaxConnLifetime:    "30m",
+			MaxConnIdleTime:    "5m",
+			HealthCheckPeriod:  "30s",
+			StatementCacheMode: "describe",
+		},
+	}
+	src, err := cfg.InitializeLazy(util.WithUserAgent(context.Background(), "test"), noop.NewTracerProvider().Tracer("test"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	pool := src.(*postgres.Source).PostgresPool()
+	defer pool.Close()
+
+	got := pool.Config()
+	if got.MaxConns != 20 || got.MinConns != 2 {
+		t.Fatalf("unexpected pool size: got min %d and max %d, want 2 and 20", got.MinConns, got.MaxConns)
+	}
+	if got.MaxConnLifetime != 30*time.Minute || got.MaxConnIdleTime != 5*time.Minute || got.HealthCheckPeriod != 30*time.Second {
+		t.Fatalf("unexpected durations: lifetime %s, idle time %s, health check period %s", got.MaxConnLifetime, got.MaxConnIdleTime, got.HealthCheckPeriod)
+	}
+	if got.ConnConfig.DefaultQueryExecMode != pgx.QueryExecModeCacheDescribe {
+		t.Fatalf("unexpected query exec mode: got %s, want %s", got.ConnConfig.DefaultQueryExecMode, pgx.QueryExecModeCacheDescribe)
+	}
+}
+
+func TestInitializePoolConfigErrors(t *testing.T) {
+	tcs := []struct {
+		desc string
+		pool sources.PgPoolConfig
+		err  string
+	}{
+		{
+			desc: "negative max conns",
+			pool: sources.PgPoolConfig{MaxConns: -1},
+			err:  "maxConns must not be negative, got -1",
+		},
+		{
+			desc: "min conns greater than max conns",
+			pool: sources.PgPoolConfig{MaxConns: 2, MinConns: 5},
+			err:  "minConns (5) must not be greater than maxConns (2)",
+		},
+		{
+			desc: "invalid duration",
+			pool: sources.PgPoolConfig{MaxConnLifetime: "forever"},
+			err:  `invalid maxConnLifetime "forever"`,
+		},
+		{
+			desc: "non-positive duration",
+			pool: sources.PgPoolConfig{HealthCheckPeriod: "0s"},
+			err:  `invalid healthCheckPeriod "0s": must be positive`,
+		},
+		{
+			desc: "invalid statement cache mode",
+			pool: sources.PgPoolConfig{StatementCacheMode: "off"},
+			err:  `invalid statementCacheMode "off"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg := postgres.Config{
+				Name:         "my-pg-instance",
+				Kind:         postgres.SourceKind,
+				Host:         "localhost",
+				Port:         "5432",
+				Database:     "my_db",
+				User:         "my_user",
+				Password:     "my_pass",
+				PgPoolConfig: tc.pool,
+			}
+			_, err := cfg.InitializeLazy(util.WithUserAgent(context.Background(), "test"), noop.NewTracerProvider().Tracer("test"))
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("unexpected error: got %v, want substring %q", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestCapabilities(t *testing.T) {
+	want := sources.Capabilities{
+		SupportsPreparedStatements: true,
+		SupportsArrayParams:        true,
+		SupportsTransactions:       true,
+		MaxIdentifierLength:        63,
+	}
+	if diff := cmp.Diff(want, (&postgres.Source{}).Capabilities()); diff != "" {
+		t.Fatalf("unexpected capabilities (-want +got):\n%s", diff)
+	}
+}
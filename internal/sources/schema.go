@@ -0,0 +1,53 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sources
+
+import "context"
+
+// SchemaSource is implemented by sources that can describe the tables they
+// contain. It is used to expose the schema of a source as an MCP resource.
+type SchemaSource interface {
+	Source
+	Schema(ctx context.Context) (*Schema, error)
+}
+
+// Schema is a summary of the tables in a source.
+type Schema struct {
+	Tables []TableSchema `json:"tables"`
+}
+
+// TableSchema describes a single table and its columns.
+type TableSchema struct {
+	Schema  string         `json:"schema,omitempty"`
+	Name    string         `json:"name"`
+	Columns []ColumnSchema `json:"columns"`
+}
+
+// ColumnSchema describes a single column of a table.
+type ColumnSchema struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Nullable bool   `json:"nullable"`
+}
+
+// AppendColumn adds a column to the schema, starting a new table whenever the
+// table differs from the last one added. Rows must be ordered by table.
+func (s *Schema) AppendColumn(schema, table string, column ColumnSchema) {
+	if n := len(s.Tables); n == 0 || s.Tables[n-1].Schema != schema || s.Tables[n-1].Name != table {
+		s.Tables = append(s.Tables, TableSchema{Schema: schema, Name: table, Columns: []ColumnSchema{}})
+	}
+	last := &s.Tables[len(s.Tables)-1]
+	last.Columns = append(last.Columns, column)
+}
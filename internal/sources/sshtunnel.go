@@ -0,0 +1,314 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const (
+	defaultSSHPort = "22"
+	// defaultSSHKeepAliveInterval is the time between two keepalives when
+	// keepAliveInterval is not set.
+	defaultSSHKeepAliveInterval = 30 * time.Second
+	// sshConnectTimeout bounds the time to connect to the bastion.
+	sshConnectTimeout = 30 * time.Second
+)
+
+// sshReconnectBackoff is the first and the maximum delay between two
+// attempts to re-establish a tunnel that dropped.
+var sshReconnectBackoff = struct{ min, max time.Duration }{time.Second, 30 * time.Second}
+
+// ErrSSHTunnelDown is returned when a connection is dialed through a tunnel
+// that dropped and is not re-established yet.
+var ErrSSHTunnelDown = errors.New("ssh tunnel is down")
+
+// SSHTunnelConfig is the `sshTunnel` block of a source config. The source
+// connects to its database through an SSH bastion.
+type SSHTunnelConfig struct {
+	// Host and Port are the address of the bastion. Port defaults to 22.
+	Host string `yaml:"host" validate:"required"`
+	Port string `yaml:"port"`
+	User string `yaml:"user" validate:"required"`
+	// PrivateKeyFile is a file the private key is read from.
+	PrivateKeyFile string `yaml:"privateKeyFile"`
+	// PrivateKeyEnv is an environment variable the private key is read from.
+	PrivateKeyEnv string `yaml:"privateKeyEnv"`
+	// KnownHostsFile is the known_hosts file the host key of the bastion is
+	// verified against. It defaults to ~/.ssh/known_hosts.
+	KnownHostsFile string `yaml:"knownHostsFile"`
+	// InsecureSkipHostKeyVerification accepts any host key. It is meant for
+	// development only.
+	InsecureSkipHostKeyVerification bool `yaml:"insecureSkipHostKeyVerification"`
+	// KeepAliveInterval is the time between two keepalives, which detect
+	// tunnels that dropped, e.g. "30s".
+	KeepAliveInterval string `yaml:"keepAliveInterval"`
+}
+
+// clientConfig returns the configuration of the SSH connections to the
+// bastion.
+func (c SSHTunnelConfig) clientConfig() (*ssh.ClientConfig, error) {
+	if (c.PrivateKeyFile == "") == (c.PrivateKeyEnv == "") {
+		return nil, fmt.Errorf("exactly one of privateKeyFile or privateKeyEnv must be set")
+	}
+	var key []byte
+	if c.PrivateKeyFile != "" {
+		b, err := os.ReadFile(c.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read private key file: %w", err)
+		}
+		key = b
+	} else {
+		v, ok := os.LookupEnv(c.PrivateKeyEnv)
+		if !ok {
+			return nil, fmt.Errorf("environment variable %q is not set", c.PrivateKeyEnv)
+		}
+		key = []byte(v)
+	}
+	signer, err := ssh.ParsePrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse private key: %w", err)
+	}
+
+	var hostKeyCallback ssh.HostKeyCallback
+	switch {
+	case c.InsecureSkipHostKeyVerification && c.KnownHostsFile != "":
+		return nil, fmt.Errorf("knownHostsFile cannot be set when insecureSkipHostKeyVerification is true")
+	case c.InsecureSkipHostKeyVerification:
+		hostKeyCallback = ssh.InsecureIgnoreHostKey()
+	default:
+		path := c.KnownHostsFile
+		if path == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return nil, fmt.Errorf("unable to find the default known_hosts file: %w", err)
+			}
+			path = filepath.Join(home, ".ssh", "known_hosts")
+		}
+		hostKeyCallback, err = knownhosts.New(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read known_hosts file: %w", err)
+		}
+	}
+
+	return &ssh.ClientConfig{
+		User:            c.User,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         sshConnectTimeout,
+	}, nil
+}
+
+// SSHTunnel dials connections through an SSH connection to a bastion. When
+// the SSH connection drops, it is re-established in the background with
+// exponential backoff, and dials fail with ErrSSHTunnelDown in the interim.
+// Its methods are safe for concurrent use.
+type SSHTunnel struct {
+	addr      string
+	config    *ssh.ClientConfig
+	keepAlive time.Duration
+
+	mu     sync.Mutex
+	client *ssh.Client
+	// err is why the tunnel is down, while client is nil.
+	err    error
+	closed bool
+	done   chan struct{}
+}
+
+// NewSSHTunnel connects to the bastion of cfg. It returns nil if cfg is nil.
+func NewSSHTunnel(ctx context.Context, cfg *SSHTunnelConfig) (*SSHTunnel, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	config, err := cfg.clientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid sshTunnel: %w", err)
+	}
+	keepAlive := defaultSSHKeepAliveInterval
+	if cfg.KeepAliveInterval != "" {
+		keepAlive, err = time.ParseDuration(cfg.KeepAliveInterval)
+		if err != nil || keepAlive <= 0 {
+			return nil, fmt.Errorf("invalid sshTunnel keepAliveInterval %q: must be a positive duration", cfg.KeepAliveInterval)
+		}
+	}
+	port := cfg.Port
+	if port == "" {
+		port = defaultSSHPort
+	}
+	t := &SSHTunnel{
+		addr:      net.JoinHostPort(cfg.Host, port),
+		config:    config,
+		keepAlive: keepAlive,
+		done:      make(chan struct{}),
+	}
+	client, err := t.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	t.client = client
+	go t.watch(client)
+	return t, nil
+}
+
+// connect establishes an SSH connection to the bastion.
+func (t *SSHTunnel) connect(ctx context.Context) (*ssh.Client, error) {
+	d := net.Dialer{Timeout: t.config.Timeout}
+	conn, err := d.DialContext(ctx, "tcp", t.addr)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to ssh bastion %s: %w", t.addr, err)
+	}
+	c, chans, reqs, err := ssh.NewClientConn(conn, t.addr, t.config)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("unable to establish ssh connection to bastion %s: %w", t.addr, err)
+	}
+	return ssh.NewClient(c, chans, reqs), nil
+}
+
+// watch sends keepalives over client until it drops, and then re-establishes
+// the tunnel.
+func (t *SSHTunnel) watch(client *ssh.Client) {
+	dropped := make(chan error, 1)
+	go func() { dropped <- client.Wait() }()
+
+	ticker := time.NewTicker(t.keepAlive)
+	defer ticker.Stop()
+	var err error
+watch:
+	for {
+		select {
+		case <-t.done:
+			return
+		case err = <-dropped:
+			break watch
+		case <-ticker.C:
+			if _, _, kaErr := client.SendRequest("keepalive@openssh.com", true, nil); kaErr != nil {
+				err = kaErr
+				client.Close()
+				break watch
+			}
+		}
+	}
+	if err == nil {
+		err = errors.New("connection closed")
+	}
+
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return
+	}
+	t.client, t.err = nil, err
+	t.mu.Unlock()
+	t.reconnect()
+}
+
+// reconnect re-establishes the tunnel, retrying with exponential backoff
+// until it succeeds or the tunnel is closed.
+func (t *SSHTunnel) reconnect() {
+	backoff := sshReconnectBackoff.min
+	for {
+		select {
+		case <-t.done:
+			return
+		case <-time.After(backoff):
+		}
+		client, err := t.connect(context.Background())
+		t.mu.Lock()
+		if t.closed {
+			t.mu.Unlock()
+			if client != nil {
+				client.Close()
+			}
+			return
+		}
+		if err == nil {
+			t.client, t.err = client, nil
+			t.mu.Unlock()
+			go t.watch(client)
+			return
+		}
+		t.err = err
+		t.mu.Unlock()
+		backoff = min(2*backoff, sshReconnectBackoff.max)
+	}
+}
+
+// DialContext dials addr, as seen from the bastion, through the tunnel.
+func (t *SSHTunnel) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	t.mu.Lock()
+	client, tunnelErr, closed := t.client, t.err, t.closed
+	t.mu.Unlock()
+	if closed {
+		return nil, fmt.Errorf("%w: tunnel is closed", ErrSSHTunnelDown)
+	}
+	if client == nil {
+		return nil, fmt.Errorf("%w, reconnecting to %s: %w", ErrSSHTunnelDown, t.addr, tunnelErr)
+	}
+	conn, err := client.DialContext(ctx, network, addr)
+	if err != nil {
+		return nil, fmt.Errorf("unable to dial %s through ssh tunnel: %w", addr, err)
+	}
+	return conn, nil
+}
+
+// Close closes the tunnel. Connections dialed through it are closed too. It
+// does nothing if t is nil.
+func (t *SSHTunnel) Close() error {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return nil
+	}
+	t.closed = true
+	close(t.done)
+	if t.client == nil {
+		return nil
+	}
+	return t.client.Close()
+}
+
+// mysqlNetworks counts the networks registered with the MySQL driver, so
+// that each tunnel gets its own.
+var mysqlNetworks atomic.Int64
+
+// MySQLNetwork registers t as a network of the MySQL driver and returns its
+// name, to use in DSNs in place of "tcp". It returns "tcp" if t is nil.
+func (t *SSHTunnel) MySQLNetwork() string {
+	if t == nil {
+		return "tcp"
+	}
+	name := fmt.Sprintf("sshtunnel%d", mysqlNetworks.Add(1))
+	mysql.RegisterDialContext(name, func(ctx context.Context, addr string) (net.Conn, error) {
+		return t.DialContext(ctx, "tcp", addr)
+	})
+	return name
+}
@@ -0,0 +1,320 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sources
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	gssh "github.com/gliderlabs/ssh"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// newTestKey returns a new ed25519 signer and its private key in PEM.
+func newTestKey(t *testing.T) (ssh.Signer, []byte) {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("unable to generate key: %s", err)
+	}
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatalf("unable to create signer: %s", err)
+	}
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	if err != nil {
+		t.Fatalf("unable to marshal key: %s", err)
+	}
+	return signer, pem.EncodeToMemory(block)
+}
+
+// startEchoServer starts a TCP server standing in for a database, which
+// echoes what it reads, and returns its address.
+func startEchoServer(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %s", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+// startBastion starts an SSH server on addr, or on a free port if addr is
+// empty, which accepts the client key and forwards connections. It returns
+// the server and its address.
+func startBastion(t *testing.T, addr string, hostKey ssh.Signer, clientKey ssh.PublicKey) (*gssh.Server, string) {
+	t.Helper()
+	if addr == "" {
+		addr = "127.0.0.1:0"
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Fatalf("unable to listen: %s", err)
+	}
+	srv := &gssh.Server{
+		Handler: func(gssh.Session) {},
+		PublicKeyHandler: func(_ gssh.Context, key gssh.PublicKey) bool {
+			return gssh.KeysEqual(key, clientKey)
+		},
+		LocalPortForwardingCallback: func(gssh.Context, string, uint32) bool { return true },
+		ChannelHandlers: map[string]gssh.ChannelHandler{
+			"direct-tcpip": gssh.DirectTCPIPHandler,
+		},
+	}
+	srv.AddHostKey(hostKey)
+	go func() { _ = srv.Serve(ln) }()
+	t.Cleanup(func() { srv.Close() })
+	return srv, ln.Addr().String()
+}
+
+// writeKnownHosts writes a known_hosts file holding key for addr.
+func writeKnownHosts(t *testing.T, addr string, key ssh.PublicKey) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "known_hosts")
+	if err := os.WriteFile(path, []byte(knownhosts.Line([]string{addr}, key)+"\n"), 0o600); err != nil {
+		t.Fatalf("unable to write known_hosts: %s", err)
+	}
+	return path
+}
+
+// writeKey writes a private key to a file and returns its path.
+func writeKey(t *testing.T, key []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "id_ed25519")
+	if err := os.WriteFile(path, key, 0o600); err != nil {
+		t.Fatalf("unable to write key: %s", err)
+	}
+	return path
+}
+
+// tunnelConfig returns the config of a tunnel to the bastion at addr.
+func tunnelConfig(t *testing.T, addr string) *SSHTunnelConfig {
+	t.Helper()
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		t.Fatalf("invalid address %q: %s", addr, err)
+	}
+	return &SSHTunnelConfig{Host: host, Port: port, User: "toolbox"}
+}
+
+// assertEcho checks that a connection dialed through tunnel reaches the echo
+// server at addr.
+func assertEcho(t *testing.T, tunnel *SSHTunnel, addr string) {
+	t.Helper()
+	conn, err := tunnel.DialContext(context.Background(), "tcp", addr)
+	if err != nil {
+		t.Fatalf("unable to dial through tunnel: %s", err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte("ping")); err != nil {
+		t.Fatalf("unable to write: %s", err)
+	}
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		t.Fatalf("unable to read: %s", err)
+	}
+	if string(buf) != "ping" {
+		t.Fatalf("unexpected echo: got %q, want %q", buf, "ping")
+	}
+}
+
+func TestSSHTunnelKeyAuth(t *testing.T) {
+	hostKey, _ := newTestKey(t)
+	clientKey, clientPEM := newTestKey(t)
+	_, bastion := startBastion(t, "", hostKey, clientKey.PublicKey())
+	echo := startEchoServer(t)
+
+	t.Run("key from file", func(t *testing.T) {
+		cfg := tunnelConfig(t, bastion)
+		cfg.PrivateKeyFile = writeKey(t, clientPEM)
+		cfg.KnownHostsFile = writeKnownHosts(t, bastion, hostKey.PublicKey())
+		tunnel, err := NewSSHTunnel(context.Background(), cfg)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		defer tunnel.Close()
+		assertEcho(t, tunnel, echo)
+	})
+
+	t.Run("key from env", func(t *testing.T) {
+		t.Setenv("TOOLBOX_TEST_SSH_KEY", string(clientPEM))
+		cfg := tunnelConfig(t, bastion)
+		cfg.PrivateKeyEnv = "TOOLBOX_TEST_SSH_KEY"
+		cfg.InsecureSkipHostKeyVerification = true
+		tunnel, err := NewSSHTunnel(context.Background(), cfg)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		defer tunnel.Close()
+		assertEcho(t, tunnel, echo)
+	})
+
+	t.Run("unauthorized key", func(t *testing.T) {
+		_, otherPEM := newTestKey(t)
+		cfg := tunnelConfig(t, bastion)
+		cfg.PrivateKeyFile = writeKey(t, otherPEM)
+		cfg.InsecureSkipHostKeyVerification = true
+		_, err := NewSSHTunnel(context.Background(), cfg)
+		if err == nil || !strings.Contains(err.Error(), "unable to authenticate") {
+			t.Fatalf("unexpected error: got %v, want an authentication error", err)
+		}
+	})
+}
+
+func TestSSHTunnelHostKeyMismatch(t *testing.T) {
+	hostKey, _ := newTestKey(t)
+	otherHostKey, _ := newTestKey(t)
+	clientKey, clientPEM := newTestKey(t)
+	_, bastion := startBastion(t, "", hostKey, clientKey.PublicKey())
+
+	cfg := tunnelConfig(t, bastion)
+	cfg.PrivateKeyFile = writeKey(t, clientPEM)
+	cfg.KnownHostsFile = writeKnownHosts(t, bastion, otherHostKey.PublicKey())
+	_, err := NewSSHTunnel(context.Background(), cfg)
+	var keyErr *knownhosts.KeyError
+	if !errors.As(err, &keyErr) || len(keyErr.Want) == 0 {
+		t.Fatalf("unexpected error: got %v, want a host key mismatch", err)
+	}
+}
+
+func TestSSHTunnelConfigErrors(t *testing.T) {
+	_, clientPEM := newTestKey(t)
+	keyFile := writeKey(t, clientPEM)
+	tcs := []struct {
+		desc    string
+		cfg     SSHTunnelConfig
+		wantErr string
+	}{
+		{
+			desc:    "no key",
+			cfg:     SSHTunnelConfig{Host: "bastion", User: "toolbox", InsecureSkipHostKeyVerification: true},
+			wantErr: "invalid sshTunnel: exactly one of privateKeyFile or privateKeyEnv must be set",
+		},
+		{
+			desc:    "both keys",
+			cfg:     SSHTunnelConfig{Host: "bastion", User: "toolbox", PrivateKeyFile: keyFile, PrivateKeyEnv: "KEY"},
+			wantErr: "invalid sshTunnel: exactly one of privateKeyFile or privateKeyEnv must be set",
+		},
+		{
+			desc:    "invalid key",
+			cfg:     SSHTunnelConfig{Host: "bastion", User: "toolbox", PrivateKeyFile: writeKey(t, []byte("not a key")), InsecureSkipHostKeyVerification: true},
+			wantErr: "invalid sshTunnel: unable to parse private key",
+		},
+		{
+			desc:    "insecure with known hosts",
+			cfg:     SSHTunnelConfig{Host: "bastion", User: "toolbox", PrivateKeyFile: keyFile, KnownHostsFile: "known_hosts", InsecureSkipHostKeyVerification: true},
+			wantErr: "invalid sshTunnel: knownHostsFile cannot be set when insecureSkipHostKeyVerification is true",
+		},
+		{
+			desc:    "invalid keepalive",
+			cfg:     SSHTunnelConfig{Host: "bastion", User: "toolbox", PrivateKeyFile: keyFile, InsecureSkipHostKeyVerification: true, KeepAliveInterval: "soon"},
+			wantErr: `invalid sshTunnel keepAliveInterval "soon": must be a positive duration`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := NewSSHTunnel(context.Background(), &tc.cfg)
+			if err == nil || !strings.HasPrefix(err.Error(), tc.wantErr) {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+	if tunnel, err := NewSSHTunnel(context.Background(), nil); tunnel != nil || err != nil {
+		t.Fatalf("unexpected tunnel for nil config: %v, %v", tunnel, err)
+	}
+}
+
+func TestSSHTunnelReconnect(t *testing.T) {
+	backoff := sshReconnectBackoff
+	sshReconnectBackoff.min, sshReconnectBackoff.max = 10*time.Millisecond, 50*time.Millisecond
+	t.Cleanup(func() { sshReconnectBackoff = backoff })
+
+	hostKey, _ := newTestKey(t)
+	clientKey, clientPEM := newTestKey(t)
+	srv, bastion := startBastion(t, "", hostKey, clientKey.PublicKey())
+	echo := startEchoServer(t)
+
+	cfg := tunnelConfig(t, bastion)
+	cfg.PrivateKeyFile = writeKey(t, clientPEM)
+	cfg.KnownHostsFile = writeKnownHosts(t, bastion, hostKey.PublicKey())
+	tunnel, err := NewSSHTunnel(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer tunnel.Close()
+	assertEcho(t, tunnel, echo)
+
+	// dials fail cleanly while the bastion is down
+	srv.Close()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, err := tunnel.DialContext(context.Background(), "tcp", echo)
+		if errors.Is(err, ErrSSHTunnelDown) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("tunnel did not report the drop, last error: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// the tunnel is re-established once the bastion is back
+	startBastion(t, bastion, hostKey, clientKey.PublicKey())
+	deadline = time.Now().Add(5 * time.Second)
+	for {
+		conn, err := tunnel.DialContext(context.Background(), "tcp", echo)
+		if err == nil {
+			conn.Close()
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("tunnel was not re-established, last error: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assertEcho(t, tunnel, echo)
+
+	// dials fail once the tunnel is closed
+	if err := tunnel.Close(); err != nil {
+		t.Fatalf("unexpected error closing tunnel: %s", err)
+	}
+	if _, err := tunnel.DialContext(context.Background(), "tcp", echo); !errors.Is(err, ErrSSHTunnelDown) {
+		t.Fatalf("unexpected error after close: %v", err)
+	}
+}
@@ -61,6 +61,11 @@ type Config struct {
 	Password string `yaml:"password" validate:"required"`
 	Database string `yaml:"database" validate:"required"`
 	UseSSL   bool   `yaml:"ssl"`
+	// MaxOpenConns is the most connections of the pool. It is unlimited if
+	// not set.
+	MaxOpenConns int `yaml:"maxOpenConns"`
+	// SSHTunnel, if set, connects to the database through an SSH bastion.
+	SSHTunnel *sources.SSHTunnelConfig `yaml:"sshTunnel"`
 }
 
 func (r Config) SourceConfigKind() string {
@@ -68,30 +73,44 @@ func (r Config) SourceConfigKind() string {
 }
 
 func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
-	pool, err := initTiDBConnectionPool(ctx, tracer, r.Name, r.Host, r.Port, r.User, r.Password, r.Database, r.UseSSL)
+	if r.MaxOpenConns < 0 {
+		return nil, fmt.Errorf("maxOpenConns must not be negative, got %d", r.MaxOpenConns)
+	}
+	tunnel, err := sources.NewSSHTunnel(ctx, r.SSHTunnel)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := initTiDBConnectionPool(ctx, tracer, r.Name, tunnel.MySQLNetwork(), r.Host, r.Port, r.User, r.Password, r.Database, r.UseSSL)
 	if err != nil {
+		_ = tunnel.Close()
 		return nil, fmt.Errorf("unable to create pool: %w", err)
 	}
+	pool.SetMaxOpenConns(r.MaxOpenConns)
 
 	err = pool.PingContext(ctx)
 	if err != nil {
+		pool.Close()
+		_ = tunnel.Close()
 		return nil, fmt.Errorf("unable to connect successfully: %w", err)
 	}
 
 	s := &Source{
-		Name: r.Name,
-		Kind: SourceKind,
-		Pool: pool,
+		Name:    r.Name,
+		Kind:    SourceKind,
+		Pool:    pool,
+		monitor: sources.NewSQLPoolMonitor(pool),
 	}
 	return s, nil
 }
 
 var _ sources.Source = &Source{}
+var _ sources.PoolSource = &Source{}
 
 type Source struct {
-	Name string `yaml:"name"`
-	Kind string `yaml:"kind"`
-	Pool *sql.DB
+	Name    string `yaml:"name"`
+	Kind    string `yaml:"kind"`
+	Pool    *sql.DB
+	monitor *sources.PoolMonitor
 }
 
 func (s *Source) SourceKind() string {
@@ -102,6 +121,12 @@ func (s *Source) TiDBPool() *sql.DB {
 	return s.Pool
 }
 
+// PoolMonitor tells the invocations that failed waiting for a connection of
+// the pool.
+func (s *Source) PoolMonitor() *sources.PoolMonitor {
+	return s.monitor
+}
+
 func IsTiDBCloudHost(host string) bool {
 	pattern := `gateway\d{2}\.(.+)\.(prod|dev|staging)\.(.+)\.tidbcloud\.com`
 	match, err := regexp.MatchString(pattern, host)
@@ -111,13 +136,13 @@ func IsTiDBCloudHost(host string) bool {
 	return match
 }
 
-func initTiDBConnectionPool(ctx context.Context, tracer trace.Tracer, name, host, port, user, pass, dbname string, useSSL bool) (*sql.DB, error) {
+func initTiDBConnectionPool(ctx context.Context, tracer trace.Tracer, name, network, host, port, user, pass, dbname string, useSSL bool) (*sql.DB, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
 	defer span.End()
 
 	// Configure the driver to connect to the database
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true&charset=utf8mb4&tls=%t", user, pass, host, port, dbname, useSSL)
+	dsn := fmt.Sprintf("%s:%s@%s(%s:%s)/%s?parseTime=true&charset=utf8mb4&tls=%t", user, pass, network, host, port, dbname, useSSL)
 
 	// Interact with the driver directly as you normally would
 	pool, err := sql.Open("mysql", dsn)
@@ -81,6 +81,32 @@ func TestParseFromYamlTiDB(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with max open conns",
+			in: `
+			sources:
+				my-tidb-instance:
+					kind: tidb
+					host: 0.0.0.0
+					port: my-port
+					database: my_db
+					user: my_user
+					password: my_pass
+					maxOpenConns: 10
+			`,
+			want: server.SourceConfigs{
+				"my-tidb-instance": tidb.Config{
+					Name:         "my-tidb-instance",
+					Kind:         tidb.SourceKind,
+					Host:         "0.0.0.0",
+					Port:         "my-port",
+					Database:     "my_db",
+					User:         "my_user",
+					Password:     "my_pass",
+					MaxOpenConns: 10,
+				},
+			},
+		},
 		{
 			desc: "Change SSL enabled due to TiDB Cloud host",
 			in: `
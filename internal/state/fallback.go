@@ -0,0 +1,123 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/log"
+)
+
+// Fallback is a Store that degrades to a Memory store while its primary store
+// fails, so that invocations keep working with state local to the replica
+// rather than failing. A warning is logged when it degrades, and a message
+// when the primary store recovers.
+type Fallback struct {
+	primary Store
+	local   *Memory
+	logger  log.Logger
+
+	mu       sync.Mutex
+	degraded bool
+}
+
+// validate interface
+var _ Store = &Fallback{}
+
+// NewFallback returns a Fallback store over primary.
+func NewFallback(primary Store, logger log.Logger) *Fallback {
+	return &Fallback{primary: primary, local: NewMemory(), logger: logger}
+}
+
+// Degraded reports whether the last operation on the primary store failed.
+func (f *Fallback) Degraded() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.degraded
+}
+
+// observe records the outcome of an operation on the primary store, and
+// reports whether it failed.
+func (f *Fallback) observe(ctx context.Context, err error) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err != nil {
+		if !f.degraded {
+			f.degraded = true
+			f.logger.WarnContext(ctx, fmt.Sprintf("state store is unavailable, keeping state local to this replica until it recovers: %s", err))
+		}
+		return true
+	}
+	if f.degraded {
+		f.degraded = false
+		f.logger.InfoContext(ctx, "state store recovered")
+	}
+	return false
+}
+
+func (f *Fallback) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	v, ok, err := f.primary.Get(ctx, key)
+	if f.observe(ctx, err) {
+		return f.local.Get(ctx, key)
+	}
+	return v, ok, nil
+}
+
+func (f *Fallback) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	err := f.primary.Set(ctx, key, value, ttl)
+	if f.observe(ctx, err) {
+		return f.local.Set(ctx, key, value, ttl)
+	}
+	return nil
+}
+
+func (f *Fallback) Add(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	added, err := f.primary.Add(ctx, key, value, ttl)
+	if f.observe(ctx, err) {
+		return f.local.Add(ctx, key, value, ttl)
+	}
+	return added, nil
+}
+
+func (f *Fallback) Delete(ctx context.Context, key string) error {
+	err := f.primary.Delete(ctx, key)
+	if f.observe(ctx, err) {
+		return f.local.Delete(ctx, key)
+	}
+	return nil
+}
+
+func (f *Fallback) Incr(ctx context.Context, key string, window time.Duration) (int64, error) {
+	n, err := f.primary.Incr(ctx, key, window)
+	if f.observe(ctx, err) {
+		return f.local.Incr(ctx, key, window)
+	}
+	return n, nil
+}
+
+func (f *Fallback) Take(ctx context.Context, key string) ([]byte, bool, error) {
+	v, ok, err := f.primary.Take(ctx, key)
+	if f.observe(ctx, err) {
+		return f.local.Take(ctx, key)
+	}
+	return v, ok, nil
+}
+
+func (f *Fallback) Close() error {
+	return f.primary.Close()
+}
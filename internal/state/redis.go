@@ -0,0 +1,154 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/maintnotifications"
+)
+
+// DefaultKeyPrefix is the prefix of the keys a Redis store writes, unless
+// configured otherwise.
+const DefaultKeyPrefix = "toolbox:"
+
+// incrScript increments a counter and sets the expiry of new counters, in a
+// single step so that a counter never outlives its window.
+var incrScript = redis.NewScript(`
+local n = redis.call('INCR', KEYS[1])
+if n == 1 and tonumber(ARGV[1]) > 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return n
+`)
+
+// RedisConfig configures a Redis store.
+type RedisConfig struct {
+	// Address is the `host:port` of the Redis server, or a `redis://` or
+	// `rediss://` URL, which may hold credentials and a database number.
+	Address string
+	// TLS connects to a `host:port` Address over TLS.
+	TLS bool
+	// KeyPrefix is prepended to every key, so that several deployments can
+	// share a Redis server. If empty, DefaultKeyPrefix is used.
+	KeyPrefix string
+	// MaxTTL, if set, is the ceiling of the TTL of every entry, including
+	// those written without one.
+	MaxTTL time.Duration
+}
+
+// Redis is a Store kept in Redis, or any server speaking its protocol such
+// as Valkey, so that it is shared by the replicas of a server.
+type Redis struct {
+	client *redis.Client
+	prefix string
+	maxTTL time.Duration
+}
+
+// validate interface
+var _ Store = &Redis{}
+
+// NewRedis returns a Redis store. The connection is established lazily, so
+// that an unreachable server does not prevent the store from being created.
+func NewRedis(cfg RedisConfig) (*Redis, error) {
+	if cfg.Address == "" {
+		return nil, errors.New("invalid Redis state store: address must be set")
+	}
+	if cfg.MaxTTL < 0 {
+		return nil, fmt.Errorf("invalid Redis state store: max TTL must not be negative, got %s", cfg.MaxTTL)
+	}
+	var opts *redis.Options
+	if strings.HasPrefix(cfg.Address, "redis://") || strings.HasPrefix(cfg.Address, "rediss://") {
+		var err error
+		opts, err = redis.ParseURL(cfg.Address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Redis state store address: %w", err)
+		}
+	} else {
+		opts = &redis.Options{Addr: cfg.Address}
+	}
+	// Maintenance notifications are specific to Redis Enterprise, and other
+	// servers reject the handshake that enables them.
+	opts.MaintNotificationsConfig = &maintnotifications.Config{Mode: maintnotifications.ModeDisabled}
+	if cfg.TLS && opts.TLSConfig == nil {
+		opts.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	prefix := cfg.KeyPrefix
+	if prefix == "" {
+		prefix = DefaultKeyPrefix
+	}
+	return &Redis{client: redis.NewClient(opts), prefix: prefix, maxTTL: cfg.MaxTTL}, nil
+}
+
+// ttl returns ttl capped by the TTL ceiling of the store.
+func (r *Redis) ttl(ttl time.Duration) time.Duration {
+	if r.maxTTL > 0 && (ttl <= 0 || ttl > r.maxTTL) {
+		return r.maxTTL
+	}
+	return ttl
+}
+
+// Ping checks that the Redis server is reachable.
+func (r *Redis) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
+func (r *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	v, err := r.client.Get(ctx, r.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return v, true, nil
+}
+
+func (r *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return r.client.Set(ctx, r.prefix+key, value, r.ttl(ttl)).Err()
+}
+
+func (r *Redis) Add(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, r.prefix+key, value, r.ttl(ttl)).Result()
+}
+
+func (r *Redis) Delete(ctx context.Context, key string) error {
+	return r.client.Del(ctx, r.prefix+key).Err()
+}
+
+func (r *Redis) Incr(ctx context.Context, key string, window time.Duration) (int64, error) {
+	return incrScript.Run(ctx, r.client, []string{r.prefix + key}, r.ttl(window).Milliseconds()).Int64()
+}
+
+func (r *Redis) Take(ctx context.Context, key string) ([]byte, bool, error) {
+	v, err := r.client.GetDel(ctx, r.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return v, true, nil
+}
+
+func (r *Redis) Close() error {
+	return r.client.Close()
+}
@@ -0,0 +1,178 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package state holds the state that the features of a server keep between
+// invocations, such as cached results and rate limit counters. By default it
+// is kept in memory, so that each replica of a server has its own. It can be
+// kept in Redis instead, so that replicas share it.
+package state
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often a Memory store removes its expired entries.
+const sweepInterval = time.Minute
+
+// Store is a key-value store of state. Entries expire after the TTL they are
+// written with; a zero TTL means they do not expire, unless the store has a
+// TTL ceiling.
+type Store interface {
+	// Get returns the value at key, and whether it is set.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set sets the value at key.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Add sets the value at key unless it is already set, and reports
+	// whether it did, so that only one of several writers claims a key.
+	Add(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
+	// Delete deletes the value or counter at key.
+	Delete(ctx context.Context, key string) error
+	// Incr increments the counter at key and returns its new value. A new
+	// counter expires after window, so that it counts the events of a fixed
+	// window, as rate limiters do.
+	Incr(ctx context.Context, key string, window time.Duration) (int64, error)
+	// Take returns the value at key and deletes it, so that a value such as
+	// a confirmation token is only taken once.
+	Take(ctx context.Context, key string) ([]byte, bool, error)
+	// Close releases the resources of the store.
+	Close() error
+}
+
+type memoryEntry struct {
+	value   []byte
+	counter int64
+	expires time.Time
+}
+
+func (e memoryEntry) expired(now time.Time) bool {
+	return !e.expires.IsZero() && !now.Before(e.expires)
+}
+
+// Memory is a Store kept in the memory of the process.
+type Memory struct {
+	mu        sync.Mutex
+	entries   map[string]memoryEntry
+	lastSweep time.Time
+}
+
+// validate interface
+var _ Store = &Memory{}
+
+// NewMemory returns an empty Memory store.
+func NewMemory() *Memory {
+	return &Memory{entries: make(map[string]memoryEntry), lastSweep: time.Now()}
+}
+
+// get returns the unexpired entry at key. It must be called with mu held.
+func (m *Memory) get(key string, now time.Time) (memoryEntry, bool) {
+	e, ok := m.entries[key]
+	if !ok {
+		return memoryEntry{}, false
+	}
+	if e.expired(now) {
+		delete(m.entries, key)
+		return memoryEntry{}, false
+	}
+	return e, true
+}
+
+// sweep removes the expired entries, at most once per sweepInterval. It must
+// be called with mu held.
+func (m *Memory) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < sweepInterval {
+		return
+	}
+	m.lastSweep = now
+	for k, e := range m.entries {
+		if e.expired(now) {
+			delete(m.entries, k)
+		}
+	}
+}
+
+func expiry(now time.Time, ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return now.Add(ttl)
+}
+
+func (m *Memory) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.get(key, time.Now())
+	if !ok || e.value == nil {
+		return nil, false, nil
+	}
+	return e.value, true, nil
+}
+
+func (m *Memory) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	m.sweep(now)
+	m.entries[key] = memoryEntry{value: value, expires: expiry(now, ttl)}
+	return nil
+}
+
+func (m *Memory) Add(_ context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	m.sweep(now)
+	if _, ok := m.get(key, now); ok {
+		return false, nil
+	}
+	m.entries[key] = memoryEntry{value: value, expires: expiry(now, ttl)}
+	return true, nil
+}
+
+func (m *Memory) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+	return nil
+}
+
+func (m *Memory) Incr(_ context.Context, key string, window time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	m.sweep(now)
+	e, ok := m.get(key, now)
+	if !ok {
+		e = memoryEntry{expires: expiry(now, window)}
+	}
+	e.counter++
+	m.entries[key] = e
+	return e.counter, nil
+}
+
+func (m *Memory) Take(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.get(key, time.Now())
+	if !ok || e.value == nil {
+		return nil, false, nil
+	}
+	delete(m.entries, key)
+	return e.value, true, nil
+}
+
+func (m *Memory) Close() error {
+	return nil
+}
@@ -0,0 +1,269 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/state"
+)
+
+func newRedis(t *testing.T, mr *miniredis.Miniredis, prefix string, maxTTL time.Duration) *state.Redis {
+	t.Helper()
+	s, err := state.NewRedis(state.RedisConfig{Address: mr.Addr(), KeyPrefix: prefix, MaxTTL: maxTTL})
+	if err != nil {
+		t.Fatalf("unable to create Redis store: %s", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// testStore checks the behavior common to every Store.
+func testStore(t *testing.T, s state.Store) {
+	ctx := context.Background()
+
+	if _, ok, err := s.Get(ctx, "missing"); err != nil || ok {
+		t.Fatalf("Get(missing) = %v, %v, want not found", ok, err)
+	}
+	if err := s.Set(ctx, "k", []byte("v"), time.Minute); err != nil {
+		t.Fatalf("unexpected error from Set: %s", err)
+	}
+	v, ok, err := s.Get(ctx, "k")
+	if err != nil || !ok || string(v) != "v" {
+		t.Fatalf("Get(k) = %q, %v, %v, want \"v\"", v, ok, err)
+	}
+
+	for want := int64(1); want <= 3; want++ {
+		n, err := s.Incr(ctx, "counter", time.Minute)
+		if err != nil {
+			t.Fatalf("unexpected error from Incr: %s", err)
+		}
+		if n != want {
+			t.Fatalf("Incr = %d, want %d", n, want)
+		}
+	}
+
+	if added, err := s.Add(ctx, "k", []byte("other"), time.Minute); err != nil || added {
+		t.Fatalf("Add(k) = %v, %v, want not added", added, err)
+	}
+	if added, err := s.Add(ctx, "new", []byte("v"), time.Minute); err != nil || !added {
+		t.Fatalf("Add(new) = %v, %v, want added", added, err)
+	}
+	if err := s.Delete(ctx, "new"); err != nil {
+		t.Fatalf("unexpected error from Delete: %s", err)
+	}
+	if _, ok, err := s.Get(ctx, "new"); err != nil || ok {
+		t.Fatalf("Get(new) after Delete = %v, %v, want not found", ok, err)
+	}
+
+	v, ok, err = s.Take(ctx, "k")
+	if err != nil || !ok || string(v) != "v" {
+		t.Fatalf("Take(k) = %q, %v, %v, want \"v\"", v, ok, err)
+	}
+	if _, ok, err := s.Take(ctx, "k"); err != nil || ok {
+		t.Fatalf("second Take(k) = %v, %v, want not found", ok, err)
+	}
+}
+
+func TestMemory(t *testing.T) {
+	testStore(t, state.NewMemory())
+}
+
+func TestMemoryExpiry(t *testing.T) {
+	ctx := context.Background()
+	s := state.NewMemory()
+	if err := s.Set(ctx, "k", []byte("v"), time.Millisecond); err != nil {
+		t.Fatalf("unexpected error from Set: %s", err)
+	}
+	if _, err := s.Incr(ctx, "counter", time.Millisecond); err != nil {
+		t.Fatalf("unexpected error from Incr: %s", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, ok, _ := s.Get(ctx, "k"); ok {
+		t.Errorf("expected entry to expire")
+	}
+	if n, _ := s.Incr(ctx, "counter", time.Minute); n != 1 {
+		t.Errorf("Incr after window = %d, want 1", n)
+	}
+}
+
+func TestRedis(t *testing.T) {
+	testStore(t, newRedis(t, miniredis.RunT(t), "", 0))
+}
+
+func TestRedisShared(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	a := newRedis(t, mr, "", 0)
+	b := newRedis(t, mr, "", 0)
+
+	if err := a.Set(ctx, "k", []byte("v"), time.Minute); err != nil {
+		t.Fatalf("unexpected error from Set: %s", err)
+	}
+	if v, ok, err := b.Get(ctx, "k"); err != nil || !ok || string(v) != "v" {
+		t.Fatalf("Get(k) from other store = %q, %v, %v, want \"v\"", v, ok, err)
+	}
+
+	if _, err := a.Incr(ctx, "counter", time.Minute); err != nil {
+		t.Fatalf("unexpected error from Incr: %s", err)
+	}
+	if n, err := b.Incr(ctx, "counter", time.Minute); err != nil || n != 2 {
+		t.Fatalf("Incr from other store = %d, %v, want 2", n, err)
+	}
+
+	if _, ok, _ := a.Take(ctx, "k"); !ok {
+		t.Fatalf("expected Take(k) to find the value")
+	}
+	if _, ok, _ := b.Take(ctx, "k"); ok {
+		t.Fatalf("expected value to be taken only once")
+	}
+}
+
+func TestRedisWindow(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	s := newRedis(t, mr, "", 0)
+
+	for i := 0; i < 2; i++ {
+		if _, err := s.Incr(ctx, "counter", time.Minute); err != nil {
+			t.Fatalf("unexpected error from Incr: %s", err)
+		}
+	}
+	mr.FastForward(time.Minute)
+	if n, err := s.Incr(ctx, "counter", time.Minute); err != nil || n != 1 {
+		t.Fatalf("Incr after window = %d, %v, want 1", n, err)
+	}
+}
+
+func TestRedisPrefixAndMaxTTL(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	s := newRedis(t, mr, "test:", time.Minute)
+
+	if err := s.Set(ctx, "forever", []byte("v"), 0); err != nil {
+		t.Fatalf("unexpected error from Set: %s", err)
+	}
+	if err := s.Set(ctx, "long", []byte("v"), time.Hour); err != nil {
+		t.Fatalf("unexpected error from Set: %s", err)
+	}
+	if err := s.Set(ctx, "short", []byte("v"), time.Second); err != nil {
+		t.Fatalf("unexpected error from Set: %s", err)
+	}
+
+	tcs := map[string]time.Duration{
+		"test:forever": time.Minute,
+		"test:long":    time.Minute,
+		"test:short":   time.Second,
+	}
+	for key, want := range tcs {
+		if !mr.Exists(key) {
+			t.Errorf("expected key %q to exist", key)
+			continue
+		}
+		if got := mr.TTL(key); got != want {
+			t.Errorf("TTL(%q) = %s, want %s", key, got, want)
+		}
+	}
+}
+
+func TestNewRedisErrors(t *testing.T) {
+	tcs := []struct {
+		desc string
+		cfg  state.RedisConfig
+		want string
+	}{
+		{
+			desc: "no address",
+			cfg:  state.RedisConfig{},
+			want: "invalid Redis state store: address must be set",
+		},
+		{
+			desc: "negative max TTL",
+			cfg:  state.RedisConfig{Address: "localhost:6379", MaxTTL: -time.Second},
+			want: "invalid Redis state store: max TTL must not be negative, got -1s",
+		},
+		{
+			desc: "invalid URL",
+			cfg:  state.RedisConfig{Address: "redis://localhost:6379/notadb"},
+			want: "invalid Redis state store address",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := state.NewRedis(tc.cfg)
+			if err == nil {
+				t.Fatalf("expected error")
+			}
+			if !strings.HasPrefix(err.Error(), tc.want) {
+				t.Fatalf("unexpected error: got %q, want prefix %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestFallback(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	var out, errOut bytes.Buffer
+	logger, err := log.NewStdLogger(&out, &errOut, "info")
+	if err != nil {
+		t.Fatalf("unable to create logger: %s", err)
+	}
+	s := state.NewFallback(newRedis(t, mr, "", 0), logger)
+
+	testStore(t, s)
+	if s.Degraded() {
+		t.Fatalf("expected store not to be degraded")
+	}
+
+	addr := mr.Addr()
+	mr.Close()
+
+	// Operations keep working, with state local to the store.
+	testStore(t, s)
+	if !s.Degraded() {
+		t.Fatalf("expected store to be degraded")
+	}
+	if got := strings.Count(errOut.String(), "state store is unavailable"); got != 1 {
+		t.Fatalf("expected one warning, got %d: %s", got, errOut.String())
+	}
+
+	if err := mr.StartAddr(addr); err != nil {
+		t.Fatalf("unable to restart miniredis: %s", err)
+	}
+	// The client backs off from dialing a server that failed, so that
+	// recovery may take a few attempts.
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		if _, err := s.Incr(ctx, "counter", time.Minute); err != nil {
+			t.Fatalf("unexpected error from Incr: %s", err)
+		}
+		if !s.Degraded() {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected store to recover")
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if !strings.Contains(out.String(), "state store recovered") {
+		t.Fatalf("expected recovery to be logged, got %q", out.String())
+	}
+}
@@ -15,9 +15,11 @@
 package telemetry
 
 import (
+	"context"
 	"fmt"
 
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -31,6 +33,12 @@ const (
 	toolInvokeCountName = "toolbox.server.tool.invoke.count"
 	mcpSseCountName     = "toolbox.server.mcp.sse.count"
 	mcpPostCountName    = "toolbox.server.mcp.post.count"
+	mcpInitCountName    = "toolbox.server.mcp.initialize.count"
+
+	circuitBreakerStateName     = "toolbox.server.circuit_breaker.state"
+	circuitBreakerTripCountName = "toolbox.server.circuit_breaker.trip.count"
+
+	eventDroppedCountName = "toolbox.server.event.dropped.count"
 )
 
 // Instrumentation defines the telemetry instrumentation for toolbox
@@ -42,6 +50,7 @@ type Instrumentation struct {
 	ToolInvoke metric.Int64Counter
 	McpSse     metric.Int64Counter
 	McpPost    metric.Int64Counter
+	McpInit    metric.Int64Counter
 }
 
 func CreateTelemetryInstrumentation(versionString string) (*Instrumentation, error) {
@@ -96,6 +105,15 @@ func CreateTelemetryInstrumentation(versionString string) (*Instrumentation, err
 		return nil, fmt.Errorf("unable to create %s metric: %w", mcpPostCountName, err)
 	}
 
+	mcpInit, err := meter.Int64Counter(
+		mcpInitCountName,
+		metric.WithDescription("Number of MCP initialize requests by negotiated protocol version."),
+		metric.WithUnit("{call}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s metric: %w", mcpInitCountName, err)
+	}
+
 	instrumentation := &Instrumentation{
 		Tracer:     tracer,
 		meter:      meter,
@@ -104,6 +122,74 @@ func CreateTelemetryInstrumentation(versionString string) (*Instrumentation, err
 		ToolInvoke: toolInvoke,
 		McpSse:     mcpSse,
 		McpPost:    mcpPost,
+		McpInit:    mcpInit,
 	}
 	return instrumentation, nil
 }
+
+// CircuitBreakerState is the state of a circuit breaker, as reported by the
+// circuit breaker metrics.
+type CircuitBreakerState struct {
+	// Scope is "tool" or "source", and Name the name of the tool or of the
+	// source declaring the breaker.
+	Scope string
+	Name  string
+	// State is 0 when closed, 1 when half-open and 2 when open.
+	State int64
+	// Trips is the number of times the breaker opened since startup.
+	Trips int64
+}
+
+// ObserveCircuitBreakers registers the circuit breaker metrics, which report
+// the breakers returned by states on every collection.
+func (i *Instrumentation) ObserveCircuitBreakers(states func() []CircuitBreakerState) error {
+	state, err := i.meter.Int64ObservableGauge(
+		circuitBreakerStateName,
+		metric.WithDescription("State of the circuit breakers: 0 when closed, 1 when half-open and 2 when open."),
+		metric.WithUnit("{state}"),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to create %s metric: %w", circuitBreakerStateName, err)
+	}
+	trips, err := i.meter.Int64ObservableCounter(
+		circuitBreakerTripCountName,
+		metric.WithDescription("Number of times the circuit breakers opened."),
+		metric.WithUnit("{trip}"),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to create %s metric: %w", circuitBreakerTripCountName, err)
+	}
+	_, err = i.meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		for _, s := range states() {
+			attrs := metric.WithAttributes(
+				attribute.String("toolbox.circuit_breaker.scope", s.Scope),
+				attribute.String("toolbox.circuit_breaker.name", s.Name),
+			)
+			o.ObserveInt64(state, s.State, attrs)
+			o.ObserveInt64(trips, s.Trips, attrs)
+		}
+		return nil
+	}, state, trips)
+	if err != nil {
+		return fmt.Errorf("unable to register the circuit breaker metrics: %w", err)
+	}
+	return nil
+}
+
+// ObserveDroppedEvents registers the metric of the invocation events dropped,
+// which reports the count returned by dropped on every collection.
+func (i *Instrumentation) ObserveDroppedEvents(dropped func() int64) error {
+	_, err := i.meter.Int64ObservableCounter(
+		eventDroppedCountName,
+		metric.WithDescription("Number of invocation events dropped without being published."),
+		metric.WithUnit("{event}"),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			o.Observe(dropped())
+			return nil
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to create %s metric: %w", eventDroppedCountName, err)
+	}
+	return nil
+}
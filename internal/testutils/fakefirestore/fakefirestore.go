@@ -0,0 +1,160 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fakefirestore is an in-memory emulator of the parts of the
+// Firestore API used to list collections and documents. Firestore clients use
+// it when FIRESTORE_EMULATOR_HOST is set to the Host of a Server.
+package fakefirestore
+
+import (
+	"context"
+	"encoding/base64"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"cloud.google.com/go/firestore/apiv1/firestorepb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server holds documents by path, such as "users/alice". The collections and
+// documents that are their ancestors exist implicitly, like missing documents
+// in Firestore.
+type Server struct {
+	firestorepb.UnimplementedFirestoreServer
+	lis net.Listener
+
+	mu        sync.Mutex
+	documents map[string]bool
+}
+
+// NewServer starts a Server that is stopped when the test ends.
+func NewServer(t *testing.T) *Server {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %s", err)
+	}
+	s := &Server{lis: lis, documents: make(map[string]bool)}
+	srv := grpc.NewServer()
+	firestorepb.RegisterFirestoreServer(srv, s)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+	return s
+}
+
+// Host returns the host and port of the server.
+func (s *Server) Host() string {
+	return s.lis.Addr().String()
+}
+
+// AddDocument adds a document to the server.
+func (s *Server) AddDocument(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.documents[path] = true
+}
+
+// relativePath returns the path of a resource name relative to the documents
+// of its database, such as "users/alice" for
+// "projects/p/databases/(default)/documents/users/alice".
+func relativePath(name string) (string, error) {
+	_, path, ok := strings.Cut(name, "/documents")
+	if !ok || (path != "" && !strings.HasPrefix(path, "/")) {
+		return "", status.Errorf(codes.InvalidArgument, "invalid resource name %q", name)
+	}
+	return strings.TrimPrefix(path, "/"), nil
+}
+
+// children returns the sorted ids of the collections, or documents, directly
+// under the document, or collection, at parent.
+func (s *Server) children(parent string) []string {
+	prefix := ""
+	if parent != "" {
+		prefix = parent + "/"
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seen := make(map[string]bool)
+	for path := range s.documents {
+		rest, ok := strings.CutPrefix(path, prefix)
+		if !ok || rest == "" {
+			continue
+		}
+		id, _, _ := strings.Cut(rest, "/")
+		seen[id] = true
+	}
+	ids := make([]string, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// page returns the ids after the one encoded in pageToken, at most pageSize
+// of them, and the page token of the next ones.
+func page(ids []string, pageSize int32, pageToken string) ([]string, string, error) {
+	if pageToken != "" {
+		last, err := base64.StdEncoding.DecodeString(pageToken)
+		if err != nil {
+			return nil, "", status.Errorf(codes.InvalidArgument, "invalid page token %q", pageToken)
+		}
+		i := sort.SearchStrings(ids, string(last))
+		if i < len(ids) && ids[i] == string(last) {
+			i++
+		}
+		ids = ids[i:]
+	}
+	if pageSize <= 0 || int(pageSize) >= len(ids) {
+		return ids, "", nil
+	}
+	ids = ids[:pageSize]
+	return ids, base64.StdEncoding.EncodeToString([]byte(ids[len(ids)-1])), nil
+}
+
+func (s *Server) ListCollectionIds(_ context.Context, req *firestorepb.ListCollectionIdsRequest) (*firestorepb.ListCollectionIdsResponse, error) {
+	parent, err := relativePath(req.GetParent())
+	if err != nil {
+		return nil, err
+	}
+	ids, next, err := page(s.children(parent), req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+	return &firestorepb.ListCollectionIdsResponse{CollectionIds: ids, NextPageToken: next}, nil
+}
+
+func (s *Server) ListDocuments(_ context.Context, req *firestorepb.ListDocumentsRequest) (*firestorepb.ListDocumentsResponse, error) {
+	parent, err := relativePath(req.GetParent())
+	if err != nil {
+		return nil, err
+	}
+	collection := req.GetCollectionId()
+	if parent != "" {
+		collection = parent + "/" + collection
+	}
+	ids, next, err := page(s.children(collection), req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+	resp := &firestorepb.ListDocumentsResponse{NextPageToken: next}
+	for _, id := range ids {
+		resp.Documents = append(resp.Documents, &firestorepb.Document{Name: req.GetParent() + "/" + req.GetCollectionId() + "/" + id})
+	}
+	return resp, nil
+}
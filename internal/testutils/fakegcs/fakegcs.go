@@ -0,0 +1,262 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fakegcs is an in-memory fake of the parts of the Cloud Storage JSON
+// API used by the GCS source and tools. The source uses it when
+// STORAGE_EMULATOR_HOST is set to the Host of a Server.
+package fakegcs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type object struct {
+	contentType string
+	data        []byte
+	generation  int64
+	updated     time.Time
+}
+
+// Server serves the buckets and objects it holds. Buckets are created when
+// an object is first added to them.
+type Server struct {
+	srv *httptest.Server
+
+	mu         sync.Mutex
+	buckets    map[string]map[string]*object
+	generation int64
+}
+
+// NewServer starts a Server that is closed when the test ends.
+func NewServer(t *testing.T) *Server {
+	s := &Server{buckets: make(map[string]map[string]*object)}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.srv.Close)
+	return s
+}
+
+// Host returns the host and port of the server.
+func (s *Server) Host() string {
+	return strings.TrimPrefix(s.srv.URL, "http://")
+}
+
+// PutObject adds an object to the server.
+func (s *Server) PutObject(bucket, name, contentType string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.putLocked(bucket, name, contentType, data)
+}
+
+// Object returns the content and content type of an object.
+func (s *Server) Object(bucket, name string) ([]byte, string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok := s.buckets[bucket][name]
+	if !ok {
+		return nil, "", false
+	}
+	return o.data, o.contentType, true
+}
+
+func (s *Server) putLocked(bucket, name, contentType string, data []byte) *object {
+	if s.buckets[bucket] == nil {
+		s.buckets[bucket] = make(map[string]*object)
+	}
+	s.generation++
+	o := &object{contentType: contentType, data: data, generation: s.generation, updated: time.Now().UTC()}
+	s.buckets[bucket][name] = o
+	return o
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := r.URL.EscapedPath()
+	switch {
+	case r.Method == http.MethodPost && strings.HasPrefix(path, "/upload/storage/v1/b/"):
+		bucket, rest, _ := strings.Cut(strings.TrimPrefix(path, "/upload/storage/v1/b/"), "/")
+		if rest != "o" {
+			writeError(w, http.StatusNotFound, "not found")
+			return
+		}
+		s.insert(w, r, bucket)
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "/storage/v1/b/"):
+		bucket, rest, _ := strings.Cut(strings.TrimPrefix(path, "/storage/v1/b/"), "/")
+		if rest == "o" {
+			s.list(w, r, bucket)
+			return
+		}
+		escapedName, ok := strings.CutPrefix(rest, "o/")
+		if !ok {
+			writeError(w, http.StatusNotFound, "not found")
+			return
+		}
+		name, err := url.PathUnescape(escapedName)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.get(w, r, bucket, name)
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request, bucket string) {
+	objects, ok := s.buckets[bucket]
+	if !ok {
+		writeError(w, http.StatusNotFound, "The specified bucket does not exist.")
+		return
+	}
+	q := r.URL.Query()
+	prefix, delimiter := q.Get("prefix"), q.Get("delimiter")
+	maxResults := 1000
+	if v := q.Get("maxResults"); v != "" {
+		maxResults, _ = strconv.Atoi(v)
+	}
+
+	names := make([]string, 0, len(objects))
+	for name := range objects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	items := []any{}
+	prefixes := []string{}
+	seenPrefixes := make(map[string]bool)
+	truncated := false
+	for _, name := range names {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if len(items)+len(prefixes) == maxResults {
+			truncated = true
+			break
+		}
+		if delimiter != "" {
+			if i := strings.Index(name[len(prefix):], delimiter); i >= 0 {
+				p := name[:len(prefix)+i+len(delimiter)]
+				if !seenPrefixes[p] {
+					seenPrefixes[p] = true
+					prefixes = append(prefixes, p)
+				}
+				continue
+			}
+		}
+		items = append(items, objectResource(bucket, name, objects[name]))
+	}
+	resp := map[string]any{"kind": "storage#objects", "items": items, "prefixes": prefixes}
+	if truncated {
+		resp["nextPageToken"] = "more"
+	}
+	writeJSON(w, resp)
+}
+
+func (s *Server) get(w http.ResponseWriter, r *http.Request, bucket, name string) {
+	o, ok := s.buckets[bucket][name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "No such object: "+bucket+"/"+name)
+		return
+	}
+	q := r.URL.Query()
+	if g := q.Get("generation"); g != "" && g != strconv.FormatInt(o.generation, 10) {
+		writeError(w, http.StatusNotFound, "No such object generation")
+		return
+	}
+	if q.Get("alt") == "media" {
+		w.Header().Set("Content-Type", o.contentType)
+		_, _ = w.Write(o.data)
+		return
+	}
+	writeJSON(w, objectResource(bucket, name, o))
+}
+
+// insert handles a multipart upload, whose first part is the metadata of the
+// object and second part its content.
+func (s *Server) insert(w http.ResponseWriter, r *http.Request, bucket string) {
+	_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	mr := multipart.NewReader(r.Body, params["boundary"])
+	metaPart, err := mr.NextPart()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var meta struct {
+		Name        string `json:"name"`
+		ContentType string `json:"contentType"`
+	}
+	if err := json.NewDecoder(metaPart).Decode(&meta); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	mediaPart, err := mr.NextPart()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	data, err := io.ReadAll(mediaPart)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if meta.Name == "" {
+		meta.Name = r.URL.Query().Get("name")
+	}
+	if meta.ContentType == "" {
+		meta.ContentType = mediaPart.Header.Get("Content-Type")
+	}
+	o := s.putLocked(bucket, meta.Name, meta.ContentType, data)
+	writeJSON(w, objectResource(bucket, meta.Name, o))
+}
+
+func objectResource(bucket, name string, o *object) map[string]any {
+	return map[string]any{
+		"kind":        "storage#object",
+		"bucket":      bucket,
+		"name":        name,
+		"size":        strconv.Itoa(len(o.data)),
+		"contentType": o.contentType,
+		"generation":  strconv.FormatInt(o.generation, 10),
+		"updated":     o.updated.Format(time.RFC3339Nano),
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_, _ = fmt.Fprintf(w, `{"error":{"code":%d,"message":%q}}`, code, message)
+}
@@ -0,0 +1,150 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fakesql is a database/sql driver serving canned results, so that
+// tools can be tested for how they iterate rows and release connections
+// without a database.
+package fakesql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+)
+
+// Column is a column of a Result, with the type name the driver reports.
+type Column struct {
+	Name string
+	Type string
+}
+
+// Result is the result of every query of a DB.
+type Result struct {
+	Columns []Column
+	Rows    [][]driver.Value
+	// Err, if set, fails reading the row at index FailAt, as a connection
+	// broken mid-iteration would.
+	Err    error
+	FailAt int
+	// BeforeRow, if set, is called before each row is read, such as to
+	// cancel the context of the query mid-iteration.
+	BeforeRow func(i int)
+	// OnQuery, if set, is called with the statement of each query.
+	OnQuery func(query string)
+}
+
+// Open returns a DB whose every query returns result. It is closed when the
+// test ends.
+func Open(t testing.TB, result Result) *sql.DB {
+	db := sql.OpenDB(connector{result: result})
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+type connector struct {
+	result Result
+}
+
+func (c connector) Connect(context.Context) (driver.Conn, error) {
+	return &conn{result: c.result}, nil
+}
+
+func (c connector) Driver() driver.Driver {
+	return fakeDriver{}
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("fakesql: use Open")
+}
+
+type conn struct {
+	result Result
+}
+
+var (
+	_ driver.QueryerContext = &conn{}
+	_ driver.ExecerContext  = &conn{}
+)
+
+func (c *conn) Prepare(string) (driver.Stmt, error) {
+	return nil, driver.ErrSkip
+}
+
+func (c *conn) Close() error {
+	return nil
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return nil, errors.New("fakesql: transactions are not supported")
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	if c.result.OnQuery != nil {
+		c.result.OnQuery(query)
+	}
+	return &rows{ctx: ctx, result: c.result}, nil
+}
+
+func (c *conn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	return driver.RowsAffected(0), nil
+}
+
+type rows struct {
+	ctx    context.Context
+	result Result
+	next   int
+}
+
+var _ driver.RowsColumnTypeDatabaseTypeName = &rows{}
+
+func (r *rows) Columns() []string {
+	names := make([]string, len(r.result.Columns))
+	for i, c := range r.result.Columns {
+		names[i] = c.Name
+	}
+	return names
+}
+
+func (r *rows) ColumnTypeDatabaseTypeName(i int) string {
+	return r.result.Columns[i].Type
+}
+
+func (r *rows) Close() error {
+	return nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.result.BeforeRow != nil {
+		r.result.BeforeRow(r.next)
+	}
+	// rows are read from the connection as they are iterated, as long as the
+	// query is not cancelled
+	if err := r.ctx.Err(); err != nil {
+		return err
+	}
+	if r.result.Err != nil && r.next == r.result.FailAt {
+		return r.result.Err
+	}
+	if r.next >= len(r.result.Rows) {
+		return io.EOF
+	}
+	copy(dest, r.result.Rows[r.next])
+	r.next++
+	return nil
+}
@@ -0,0 +1,324 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package httpreplay records the HTTP traffic of integration tests to golden
+// files and replays it, so that tests against cloud APIs can run without
+// credentials or live resources.
+//
+// When the TOOLBOX_TEST_RECORD environment variable is set to 1, requests are
+// sent to the real API and the sanitized responses are written to the golden
+// file when the Recorder is closed. Otherwise, requests are answered from the
+// golden file. Requests are matched on their method, URL and normalized body.
+package httpreplay
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"unicode/utf8"
+)
+
+// RecordEnv is the environment variable that switches tests from replaying
+// golden files to recording them.
+const RecordEnv = "TOOLBOX_TEST_RECORD"
+
+// Recording reports whether tests should record golden files.
+func Recording() bool {
+	return os.Getenv(RecordEnv) == "1"
+}
+
+// ScrubbedEmail replaces the email addresses found in recorded traffic.
+const ScrubbedEmail = "user@example.com"
+
+// ScrubbedProjectNumber replaces project numbers found in recorded traffic.
+const ScrubbedProjectNumber = "000000000000"
+
+// defaultScrubbers remove personal data that appears in most Google API
+// responses.
+var defaultScrubbers = []scrubber{
+	{re: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`), repl: ScrubbedEmail},
+	{re: regexp.MustCompile(`projects/[0-9]+`), repl: "projects/" + ScrubbedProjectNumber},
+}
+
+type scrubber struct {
+	re   *regexp.Regexp
+	repl string
+}
+
+// Golden is the content of a golden file.
+type Golden struct {
+	// Vars holds values generated while recording, such as the names of
+	// temporary resources, that tests need to reuse when replaying.
+	Vars         map[string]string `json:"vars,omitempty"`
+	Interactions []Interaction     `json:"interactions"`
+}
+
+// Interaction is a recorded request and its response.
+type Interaction struct {
+	Method   string   `json:"method"`
+	URL      string   `json:"url"`
+	Body     string   `json:"body,omitempty"`
+	Response Response `json:"response"`
+}
+
+// Response is a recorded response.
+type Response struct {
+	StatusCode  int    `json:"statusCode"`
+	ContentType string `json:"contentType,omitempty"`
+	Body        string `json:"body,omitempty"`
+	// BodyBase64 holds the body instead of Body if it is not valid UTF-8.
+	BodyBase64 string `json:"bodyBase64,omitempty"`
+}
+
+// Option configures a Recorder.
+type Option func(*Recorder)
+
+// WithScrub replaces every occurrence of old with replacement in recorded
+// traffic, e.g. a project id. Requests are scrubbed the same way before they
+// are matched, so tests can replay using replacement in place of old.
+func WithScrub(old, replacement string) Option {
+	return func(r *Recorder) {
+		if old != "" {
+			r.scrubbers = append(r.scrubbers, scrubber{re: regexp.MustCompile(regexp.QuoteMeta(old)), repl: replacement})
+		}
+	}
+}
+
+// WithTransport sets the transport that sends requests while recording,
+// typically one that authenticates them. It defaults to
+// http.DefaultTransport.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(r *Recorder) {
+		r.transport = rt
+	}
+}
+
+// Recorder is an http.RoundTripper that records or replays HTTP traffic.
+type Recorder struct {
+	path      string
+	recording bool
+	transport http.RoundTripper
+	scrubbers []scrubber
+
+	mu     sync.Mutex
+	golden Golden
+	// next is the index of the next interaction to replay for each request
+	// key.
+	next map[string]int
+}
+
+// New returns a Recorder for the golden file at path. When replaying, the
+// returned error wraps fs.ErrNotExist if the golden file does not exist.
+func New(path string, opts ...Option) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		recording: Recording(),
+		transport: http.DefaultTransport,
+		next:      make(map[string]int),
+	}
+	for _, o := range opts {
+		o(r)
+	}
+	// user-supplied values are replaced before the generic patterns
+	r.scrubbers = append(r.scrubbers, defaultScrubbers...)
+
+	if r.recording {
+		r.golden.Vars = make(map[string]string)
+		return r, nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read golden file, record it with %s=1: %w", RecordEnv, err)
+	}
+	if err := json.Unmarshal(b, &r.golden); err != nil {
+		return nil, fmt.Errorf("unable to parse golden file %q: %w", path, err)
+	}
+	return r, nil
+}
+
+// Recording reports whether the Recorder records traffic.
+func (r *Recorder) Recording() bool {
+	return r.recording
+}
+
+// Client returns an HTTP client sending its requests through the Recorder.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Var returns the value of a variable stored in the golden file. While
+// recording, the value is produced by generate and stored.
+func (r *Recorder) Var(name string, generate func() string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.recording {
+		v := generate()
+		r.golden.Vars[name] = r.scrub(v)
+		return v
+	}
+	return r.golden.Vars[name]
+}
+
+// Close writes the golden file when recording.
+func (r *Recorder) Close() error {
+	if !r.recording {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, err := json.MarshalIndent(r.golden, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode golden file: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("unable to create golden file directory: %w", err)
+	}
+	return os.WriteFile(r.path, append(b, '\n'), 0o644)
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("unable to read request body: %w", err)
+		}
+		// the caller's request must not be modified
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	method, u, b := req.Method, r.normalizeURL(req.URL), r.normalizeBody(body)
+
+	if r.recording {
+		return r.record(req, method, u, b)
+	}
+	return r.replay(req, method, u, b)
+}
+
+func (r *Recorder) record(req *http.Request, method, u, body string) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	recorded := Response{StatusCode: resp.StatusCode, ContentType: resp.Header.Get("Content-Type")}
+	if utf8.Valid(respBody) {
+		recorded.Body = r.scrub(string(respBody))
+	} else {
+		recorded.BodyBase64 = base64.StdEncoding.EncodeToString(respBody)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.golden.Interactions = append(r.golden.Interactions, Interaction{Method: method, URL: u, Body: body, Response: recorded})
+	return resp, nil
+}
+
+// replay answers a request with the recorded interactions matching it, in the
+// order they were recorded. Once they are used up, the last one is repeated.
+func (r *Recorder) replay(req *http.Request, method, u, body string) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := method + " " + u + "\n" + body
+	var matches []Interaction
+	for _, in := range r.golden.Interactions {
+		if in.Method == method && in.URL == u && in.Body == body {
+			matches = append(matches, in)
+		}
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no recorded response for %s %s with body %q in %q, record it with %s=1", method, u, body, r.path, RecordEnv)
+	}
+	i := min(r.next[key], len(matches)-1)
+	r.next[key] = i + 1
+
+	recorded := matches[i].Response
+	respBody := []byte(recorded.Body)
+	if recorded.BodyBase64 != "" {
+		var err error
+		if respBody, err = base64.StdEncoding.DecodeString(recorded.BodyBase64); err != nil {
+			return nil, fmt.Errorf("invalid recorded response body: %w", err)
+		}
+	}
+	header := make(http.Header)
+	if recorded.ContentType != "" {
+		header.Set("Content-Type", recorded.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(respBody)),
+		ContentLength: int64(len(respBody)),
+		Request:       req,
+	}, nil
+}
+
+func (r *Recorder) scrub(s string) string {
+	for _, sc := range r.scrubbers {
+		s = sc.re.ReplaceAllString(s, sc.repl)
+	}
+	return s
+}
+
+// normalizeURL scrubs a URL and sorts its query parameters.
+func (r *Recorder) normalizeURL(u *url.URL) string {
+	c := *u
+	c.RawQuery = c.Query().Encode()
+	return r.scrub(c.String())
+}
+
+// normalizeBody scrubs a request body. JSON bodies are re-encoded, so that
+// the formatting and the order of object keys do not affect matching.
+func (r *Recorder) normalizeBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err == nil {
+		if b, err := json.Marshal(v); err == nil {
+			body = b
+		}
+	}
+	return r.scrub(string(body))
+}
+
+// IsNotRecorded reports whether err was returned by New because the golden
+// file does not exist.
+func IsNotRecorded(err error) bool {
+	return errors.Is(err, os.ErrNotExist)
+}
@@ -0,0 +1,141 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpreplay_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/testutils/httpreplay"
+)
+
+func get(t *testing.T, c *http.Client, method, url, body string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("unable to create request: %s", err)
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("unable to send request: %s", err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response: %s", err)
+	}
+	return resp.StatusCode, string(b)
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	var calls atomic.Int64
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		b, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Path, "missing") {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		fmt.Fprintf(w, `{"call": %d, "path": %q, "body": %q, "owner": "alice@corp.com", "parent": "projects/123456/locations/us"}`, n, r.URL.Path, b)
+	}))
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "testdata", "golden.json")
+	base := ts.URL + "/v1/projects/my-secret-project"
+
+	// record
+	t.Setenv(httpreplay.RecordEnv, "1")
+	rec, err := httpreplay.New(path, httpreplay.WithScrub("my-secret-project", "test-project"))
+	if err != nil {
+		t.Fatalf("unable to create recorder: %s", err)
+	}
+	table := rec.Var("table", func() string { return "table_my-secret-project_1" })
+	if table != "table_my-secret-project_1" {
+		t.Fatalf("unexpected var while recording: %q", table)
+	}
+	c := rec.Client()
+	get(t, c, http.MethodPost, base+"/entries?b=2&a=1", `{"y": 1, "x": "a"}`)
+	get(t, c, http.MethodPost, base+"/entries?b=2&a=1", `{"y": 1, "x": "a"}`)
+	get(t, c, http.MethodGet, base+"/missing", "")
+	if err := rec.Close(); err != nil {
+		t.Fatalf("unable to write golden file: %s", err)
+	}
+
+	golden, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unable to read golden file: %s", err)
+	}
+	for _, secret := range []string{"my-secret-project", "alice@corp.com", "123456"} {
+		if strings.Contains(string(golden), secret) {
+			t.Errorf("golden file contains %q:\n%s", secret, golden)
+		}
+	}
+
+	// replay
+	t.Setenv(httpreplay.RecordEnv, "")
+	rep, err := httpreplay.New(path, httpreplay.WithScrub("my-secret-project", "test-project"))
+	if err != nil {
+		t.Fatalf("unable to create replayer: %s", err)
+	}
+	if got := rep.Var("table", nil); got != "table_test-project_1" {
+		t.Errorf("unexpected var while replaying: %q", got)
+	}
+	before := calls.Load()
+	c = rep.Client()
+	replayBase := ts.URL + "/v1/projects/test-project"
+
+	// query parameters and JSON keys are matched regardless of their order
+	_, body := get(t, c, http.MethodPost, replayBase+"/entries?a=1&b=2", `{"x":"a","y":1}`)
+	if !strings.Contains(body, `"call": 1`) || !strings.Contains(body, httpreplay.ScrubbedEmail) {
+		t.Errorf("unexpected first response: %s", body)
+	}
+	_, body = get(t, c, http.MethodPost, replayBase+"/entries?a=1&b=2", `{"x":"a","y":1}`)
+	if !strings.Contains(body, `"call": 2`) {
+		t.Errorf("unexpected second response: %s", body)
+	}
+	// the last match is repeated once the recordings are used up
+	_, body = get(t, c, http.MethodPost, replayBase+"/entries?a=1&b=2", `{"x":"a","y":1}`)
+	if !strings.Contains(body, `"call": 2`) {
+		t.Errorf("unexpected third response: %s", body)
+	}
+	status, _ := get(t, c, http.MethodGet, replayBase+"/missing", "")
+	if status != http.StatusNotFound {
+		t.Errorf("unexpected status: got %d, want %d", status, http.StatusNotFound)
+	}
+	if calls.Load() != before {
+		t.Errorf("the server was called while replaying")
+	}
+
+	// requests that were not recorded fail
+	req, _ := http.NewRequest(http.MethodPost, replayBase+"/entries?a=1&b=2", strings.NewReader(`{"x":"b"}`))
+	if _, err := c.Do(req); err == nil || !strings.Contains(err.Error(), "no recorded response") {
+		t.Errorf("expected an unmatched request error, got %v", err)
+	}
+}
+
+func TestReplayMissingGoldenFile(t *testing.T) {
+	t.Setenv(httpreplay.RecordEnv, "")
+	_, err := httpreplay.New(filepath.Join(t.TempDir(), "missing.json"))
+	if !httpreplay.IsNotRecorded(err) {
+		t.Fatalf("expected a not recorded error, got %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testutils
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+const (
+	// batteryTimeout is how long a battery of invocations is given to
+	// return, as leaked connections may block the invocations that follow.
+	batteryTimeout = 30 * time.Second
+	// leakTimeout is how long goroutines and connections are given to be
+	// released after a battery of invocations.
+	leakTimeout = 5 * time.Second
+)
+
+// CheckLeaks runs battery, typically a series of failing invocations, and
+// fails t if it leaves more goroutines running, or more connections in use as
+// reported by inUse, than before. They may be released asynchronously, so
+// they are polled for a while before failing. battery runs in its own
+// goroutine, so it must report failures with t.Error rather than t.Fatal.
+func CheckLeaks(t testing.TB, inUse func() int, battery func()) {
+	t.Helper()
+	goroutinesBefore, inUseBefore := runtime.NumGoroutine(), inUse()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		battery()
+	}()
+	select {
+	case <-done:
+	case <-time.After(batteryTimeout):
+		t.Fatalf("invocations did not return within %s, connections in use: %d", batteryTimeout, inUse())
+	}
+
+	deadline := time.Now().Add(leakTimeout)
+	for {
+		goroutines, conns := runtime.NumGoroutine(), inUse()
+		if goroutines <= goroutinesBefore && conns <= inUseBefore {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("leak after invocations: %d goroutines and %d connections in use, want at most %d and %d", goroutines, conns, goroutinesBefore, inUseBefore)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testutils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// FakeSecretManager serves the AccessSecretVersion method of the Secret
+// Manager REST API from an in-memory set of secrets.
+type FakeSecretManager struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	secrets  map[string]string
+	denied   map[string]bool
+	requests int
+}
+
+// NewFakeSecretManager starts a FakeSecretManager serving secrets, keyed by
+// the resource name of their version. It is stopped when the test ends.
+func NewFakeSecretManager(t *testing.T, secrets map[string]string) *FakeSecretManager {
+	f := &FakeSecretManager{secrets: secrets, denied: make(map[string]bool)}
+	f.server = httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+// Deny makes requests for the secret version name fail with a permission
+// denied error.
+func (f *FakeSecretManager) Deny(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.denied[name] = true
+}
+
+// URL returns the endpoint of the fake.
+func (f *FakeSecretManager) URL() string {
+	return f.server.URL
+}
+
+// Client returns an HTTP client for the fake.
+func (f *FakeSecretManager) Client() *http.Client {
+	return f.server.Client()
+}
+
+// Requests returns the number of requests received.
+func (f *FakeSecretManager) Requests() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests
+}
+
+func (f *FakeSecretManager) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests++
+
+	name, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/v1/"), ":access")
+	if r.Method != http.MethodGet || !ok {
+		http.Error(w, `{"error":{"code":400,"status":"INVALID_ARGUMENT"}}`, http.StatusBadRequest)
+		return
+	}
+	if f.denied[name] {
+		http.Error(w, `{"error":{"code":403,"status":"PERMISSION_DENIED"}}`, http.StatusForbidden)
+		return
+	}
+	v, ok := f.secrets[name]
+	if !ok {
+		http.Error(w, `{"error":{"code":404,"status":"NOT_FOUND"}}`, http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"name":    name,
+		"payload": map[string]string{"data": base64.StdEncoding.EncodeToString([]byte(v))},
+	})
+}
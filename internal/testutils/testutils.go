@@ -17,6 +17,8 @@ package testutils
 import (
 	"bufio"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -105,3 +107,64 @@ func WaitForString(ctx context.Context, re *regexp.Regexp, pr io.ReadCloser) (st
 		}
 	}
 }
+
+// readyLinePrefix starts the ready line of the server. Lines are searched for
+// it rather than parsed as a whole, as other output may have been written to
+// the same stream before the ready line ended the line.
+const readyLinePrefix = `{"event":"` + log.ServerReadyEvent + `"`
+
+// WaitForServerReady waits until the server writes its ready line to pr, and
+// returns the base URL of its first TCP listener, or "" if it only listens on
+// Unix domain sockets. It also returns the output read so far.
+func WaitForServerReady(ctx context.Context, pr io.Reader) (string, string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		line *log.ReadyLine
+		s    string
+		err  error
+	}
+	output := make(chan result)
+	go func() {
+		defer close(output)
+		in := bufio.NewReader(pr)
+		for {
+			s, err := in.ReadString('\n')
+			r := result{s: s, err: err}
+			if i := strings.Index(s, readyLinePrefix); i >= 0 {
+				var line log.ReadyLine
+				if json.NewDecoder(strings.NewReader(s[i:])).Decode(&line) == nil {
+					r.line = &line
+				}
+			}
+			select {
+			case output <- r:
+			case <-ctx.Done():
+				return
+			}
+			if r.line != nil || err != nil {
+				return
+			}
+		}
+	}()
+
+	var sb strings.Builder
+	for {
+		select {
+		case <-ctx.Done():
+			return "", sb.String(), ctx.Err()
+		case r := <-output:
+			sb.WriteString(r.s)
+			if r.line != nil {
+				return r.line.URL, sb.String(), nil
+			}
+			if errors.Is(r.err, io.EOF) {
+				return "", sb.String(), fmt.Errorf("output ended before the server was ready")
+			}
+			if r.err != nil {
+				return "", sb.String(), r.err
+			}
+		}
+	}
+}
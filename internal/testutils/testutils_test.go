@@ -0,0 +1,148 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testutils_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+const readyLine = `{"event":"server_ready","address":"127.0.0.1","port":5000,"url":"http://127.0.0.1:5000","listeners":["tcp://127.0.0.1:5000"]}` + "\n"
+
+func TestWaitForServerReady(t *testing.T) {
+	tcs := []struct {
+		name   string
+		writes []string
+		want   string
+	}{
+		{
+			name:   "ready line only",
+			writes: []string{readyLine},
+			want:   "http://127.0.0.1:5000",
+		},
+		{
+			name: "after logs",
+			writes: []string{
+				"2025-01-01T00:00:00Z INFO \"Server listening on tcp://127.0.0.1:5000\"\n",
+				"2025-01-01T00:00:00Z INFO \"Server ready to serve!\"\n",
+				readyLine,
+			},
+			want: "http://127.0.0.1:5000",
+		},
+		{
+			name: "split across writes",
+			writes: []string{
+				"2025-01-01T00:00:00Z INFO \"Server ready",
+				" to serve!\"\n{\"event\":\"server_",
+				readyLine[len(`{"event":"server_`):],
+			},
+			want: "http://127.0.0.1:5000",
+		},
+		{
+			name: "interleaved partial line",
+			writes: []string{
+				"2025-01-01T00:00:00Z WARN \"some wa",
+				readyLine,
+			},
+			want: "http://127.0.0.1:5000",
+		},
+		{
+			name: "other json lines",
+			writes: []string{
+				`{"severity":"INFO","message":"Server ready to serve!"}` + "\n",
+				`{"event":"server_starting"}` + "\n",
+				readyLine,
+			},
+			want: "http://127.0.0.1:5000",
+		},
+		{
+			name:   "unix only",
+			writes: []string{`{"event":"server_ready","listeners":["unix:///tmp/toolbox.sock"]}` + "\n"},
+			want:   "",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			pr, pw := io.Pipe()
+			defer pr.Close()
+			go func() {
+				for _, w := range tc.writes {
+					if _, err := io.WriteString(pw, w); err != nil {
+						return
+					}
+				}
+				// the server keeps writing logs once it is ready
+				_, _ = io.WriteString(pw, "2025-01-01T00:00:00Z INFO \"more logs\"\n")
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			got, out, err := testutils.WaitForServerReady(ctx, pr)
+			if err != nil {
+				t.Fatalf("unexpected error: %s\n%s", err, out)
+			}
+			if got != tc.want {
+				t.Fatalf("unexpected base URL: got %q, want %q", got, tc.want)
+			}
+			if want := strings.Join(tc.writes, ""); out != want {
+				t.Fatalf("unexpected output: got %q, want %q", out, want)
+			}
+		})
+	}
+}
+
+func TestWaitForServerReadyTimeout(t *testing.T) {
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	go func() {
+		_, _ = io.WriteString(pw, "2025-01-01T00:00:00Z INFO \"Server ready to serve!\"\n")
+		// a ready line that never ends
+		_, _ = io.WriteString(pw, readyLine[:20])
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, out, err := testutils.WaitForServerReady(ctx, pr)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+	if !strings.Contains(out, "Server ready to serve!") {
+		t.Fatalf("unexpected output: %q", out)
+	}
+}
+
+func TestWaitForServerReadyEndOfOutput(t *testing.T) {
+	pr, pw := io.Pipe()
+	go func() {
+		_, _ = io.WriteString(pw, "2025-01-01T00:00:00Z ERROR \"toolbox failed to start listener\"\n")
+		pw.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, out, err := testutils.WaitForServerReady(ctx, pr)
+	if err == nil || !strings.Contains(err.Error(), "output ended before the server was ready") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "toolbox failed to start listener") {
+		t.Fatalf("unexpected output: %q", out)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// AliasConfig wraps a ToolConfig with the kind-agnostic `aliases`,
+// `deprecated` and `deprecationMessage` fields. It allows a tool to be renamed
+// without breaking clients that still refer to it by its old name.
+type AliasConfig struct {
+	ToolConfig
+	Aliases            []string
+	Deprecated         bool
+	DeprecationMessage string
+}
+
+// validate interface
+var _ ToolConfig = AliasConfig{}
+
+func (cfg AliasConfig) Initialize(srcs map[string]sources.Source) (Tool, error) {
+	t, err := cfg.ToolConfig.Initialize(srcs)
+	if err != nil {
+		return nil, err
+	}
+	if !cfg.Deprecated {
+		return t, nil
+	}
+	return deprecatedTool{Tool: t, message: cfg.DeprecationMessage}, nil
+}
+
+// deprecatedTool decorates the manifests of a Tool with deprecation info.
+type deprecatedTool struct {
+	Tool
+	message string
+}
+
+func (t deprecatedTool) unwrap() Tool {
+	return t.Tool
+}
+
+func (t deprecatedTool) Manifest() Manifest {
+	m := t.Tool.Manifest()
+	m.Deprecated = true
+	m.DeprecationMessage = t.message
+	return m
+}
+
+func (t deprecatedTool) McpManifest() McpManifest {
+	m := t.Tool.McpManifest()
+	title := "Deprecated"
+	if t.message != "" {
+		title = fmt.Sprintf("Deprecated: %s", t.message)
+	}
+	annotations := McpToolAnnotations{}
+	if m.Annotations != nil {
+		annotations = *m.Annotations
+	}
+	annotations.Title = title
+	m.Annotations = &annotations
+	return m
+}
+
+// AliasTool makes a Tool reachable under an alternative name. Each invocation
+// through the alias logs a warning pointing to the tool's current name.
+type AliasTool struct {
+	Tool
+	Name   string
+	Target string
+}
+
+func (t AliasTool) unwrap() Tool {
+	return t.Tool
+}
+
+func (t AliasTool) Invoke(ctx context.Context, inv Invocation) (any, error) {
+	if logger, err := util.LoggerFromContext(ctx); err == nil {
+		logger.WarnContext(ctx, fmt.Sprintf("tool %q was invoked using deprecated alias %q", t.Target, t.Name))
+	}
+	return t.Tool.Invoke(ctx, inv)
+}
+
+func (t AliasTool) McpManifest() McpManifest {
+	m := t.Tool.McpManifest()
+	m.Name = t.Name
+	return m
+}
@@ -70,19 +70,23 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		return nil, fmt.Errorf("invalid source for %q tool: source kind must be `alloydb-admin`", kind)
 	}
 
+	password := tools.NewStringParameter("password", "A secure password for the initial user.")
+	password.Sensitive = true
 	allParameters := tools.Parameters{
 		tools.NewStringParameter("project", "The GCP project ID."),
 		tools.NewStringParameterWithDefault("location", "us-central1", "The location to create the cluster in. The default value is us-central1. If quota is exhausted then use other regions."),
 		tools.NewStringParameter("cluster", "A unique ID for the AlloyDB cluster."),
-		tools.NewStringParameter("password", "A secure password for the initial user."),
+		password,
 		tools.NewStringParameterWithDefault("network", "default", "The name of the VPC network to connect the cluster to (e.g., 'default')."),
+		tools.NewStringParameterWithRequired("allocatedIpRange", "An optional name of the allocated IP range for the private IPs of the cluster, in the VPC network. If not set, any allocated range of the network is used.", false),
 		tools.NewStringParameterWithDefault("user", "postgres", "The name for the initial superuser. Defaults to 'postgres' if not provided."),
+		tools.NewBooleanParameterWithDefault("validateOnly", false, "If true, the request is only validated, and no cluster is created. Default is false."),
 	}
 	paramManifest := allParameters.Manifest()
 
 	description := cfg.Description
 	if description == "" {
-		description = "Creates a new AlloyDB cluster. This is a long-running operation, but the API call returns quickly. This will return operation id to be used by get operations tool. Take all parameters from user in one go."
+		description = "Creates a new AlloyDB cluster. This is a long-running operation, but the API call returns quickly. This will return the operation, whose name ends with the operation id to be used by the wait for operation tool. Take all parameters from user in one go."
 	}
 	mcpManifest := tools.GetMcpManifest(cfg.Name, description, cfg.AuthRequired, allParameters)
 
@@ -96,6 +100,9 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	}, nil
 }
 
+// validate interface
+var _ tools.Tool = Tool{}
+
 // Tool represents the create-cluster tool.
 type Tool struct {
 	Name        string `yaml:"name"`
@@ -110,8 +117,8 @@ type Tool struct {
 }
 
 // Invoke executes the tool's logic.
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	paramsMap := params.AsMap()
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
 	project, ok := paramsMap["project"].(string)
 	if !ok || project == "" {
 		return nil, fmt.Errorf("invalid or missing 'project' parameter; expected a non-empty string")
@@ -142,7 +149,12 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		return nil, fmt.Errorf("invalid 'user' parameter; expected a string")
 	}
 
-	service, err := t.Source.GetService(ctx, string(accessToken))
+	validateOnly, ok := paramsMap["validateOnly"].(bool)
+	if !ok {
+		return nil, fmt.Errorf("invalid 'validateOnly' parameter; expected a boolean")
+	}
+
+	service, err := t.Source.GetService(ctx, string(inv.AccessToken))
 	if err != nil {
 		return nil, err
 	}
@@ -159,9 +171,18 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 			Password: password,
 		},
 	}
+	if allocatedIpRange, ok := paramsMap["allocatedIpRange"].(string); ok && allocatedIpRange != "" {
+		clusterBody.NetworkConfig.AllocatedIpRange = allocatedIpRange
+	}
 
-	// The Create API returns a long-running operation.
-	resp, err := service.Projects.Locations.Clusters.Create(urlString, clusterBody).ClusterId(clusterID).Do()
+	// The Create API returns a long-running operation. The errors of the API,
+	// such as invalid settings, are returned as they are for the agent to
+	// correct them.
+	call := service.Projects.Locations.Clusters.Create(urlString, clusterBody).ClusterId(clusterID)
+	if validateOnly {
+		call = call.ValidateOnly(true)
+	}
+	resp, err := call.Context(ctx).Do()
 	if err != nil {
 		return nil, fmt.Errorf("error creating AlloyDB cluster: %w", err)
 	}
@@ -15,13 +15,24 @@
 package alloydbcreatecluster_test
 
 import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	alloydbadmin "github.com/googleapis/genai-toolbox/internal/sources/alloydbadmin"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
 	alloydbcreatecluster "github.com/googleapis/genai-toolbox/internal/tools/alloydb/alloydbcreatecluster"
+	"google.golang.org/api/alloydb/v1"
+	"google.golang.org/api/option"
 )
 
 func TestParseFromYaml(t *testing.T) {
@@ -92,3 +103,134 @@ func TestParseFromYaml(t *testing.T) {
 		})
 	}
 }
+
+// mockRequest is a request received by the mock AlloyDB Admin API.
+type mockRequest struct {
+	Path  string
+	Query map[string]string
+	Body  map[string]any
+}
+
+// newMockAlloyDB starts a server that answers the create calls of the
+// AlloyDB Admin API with status and body, and returns an alloydb-admin source
+// using it.
+func newMockAlloyDB(t *testing.T, status int, body string) (*alloydbadmin.Source, *mockRequest) {
+	t.Helper()
+	got := &mockRequest{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.Path = r.URL.Path
+		got.Query = map[string]string{}
+		for k := range r.URL.Query() {
+			if k != "alt" && k != "prettyPrint" {
+				got.Query[k] = r.URL.Query().Get(k)
+			}
+		}
+		b, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(b, &got.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(ts.Close)
+	service, err := alloydb.NewService(context.Background(), option.WithEndpoint(ts.URL+"/"), option.WithHTTPClient(ts.Client()))
+	if err != nil {
+		t.Fatalf("unable to create service: %s", err)
+	}
+	return &alloydbadmin.Source{Name: "my-alloydb-admin-source", Kind: alloydbadmin.SourceKind, Service: service}, got
+}
+
+func TestInvoke(t *testing.T) {
+	operation := `{"name":"projects/p1/locations/l1/operations/op-1","metadata":{"verb":"create"}}`
+	apiError := `{"error":{"code":400,"message":"Invalid cluster ID: must start with a letter.","status":"INVALID_ARGUMENT"}}`
+	tcs := []struct {
+		desc      string
+		status    int
+		body      string
+		args      map[string]any
+		wantQuery map[string]string
+		wantBody  map[string]any
+		wantErr   string
+	}{
+		{
+			desc:      "create",
+			status:    http.StatusOK,
+			body:      operation,
+			args:      map[string]any{"project": "p1", "location": "l1", "cluster": "c1", "password": "hunter2"},
+			wantQuery: map[string]string{"clusterId": "c1"},
+			wantBody: map[string]any{
+				"networkConfig": map[string]any{"network": "projects/p1/global/networks/default"},
+				"initialUser":   map[string]any{"user": "postgres", "password": "hunter2"},
+			},
+		},
+		{
+			desc:      "validate only",
+			status:    http.StatusOK,
+			body:      operation,
+			args:      map[string]any{"project": "p1", "location": "l1", "cluster": "c1", "password": "hunter2", "network": "my-vpc", "allocatedIpRange": "my-range", "validateOnly": true},
+			wantQuery: map[string]string{"clusterId": "c1", "validateOnly": "true"},
+			wantBody: map[string]any{
+				"networkConfig": map[string]any{"network": "projects/p1/global/networks/my-vpc", "allocatedIpRange": "my-range"},
+				"initialUser":   map[string]any{"user": "postgres", "password": "hunter2"},
+			},
+		},
+		{
+			desc:    "api validation error",
+			status:  http.StatusBadRequest,
+			body:    apiError,
+			args:    map[string]any{"project": "p1", "location": "l1", "cluster": "1c", "password": "hunter2", "validateOnly": true},
+			wantErr: "Invalid cluster ID: must start with a letter.",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			src, got := newMockAlloyDB(t, tc.status, tc.body)
+			cfg := alloydbcreatecluster.Config{Name: "create-cluster", Kind: "alloydb-create-cluster", Source: src.Name}
+			tool, err := cfg.Initialize(map[string]sources.Source{src.Name: src})
+			if err != nil {
+				t.Fatalf("unable to initialize tool: %s", err)
+			}
+			params, err := tool.ParseParams(tc.args, nil)
+			if err != nil {
+				t.Fatalf("unable to parse params: %s", err)
+			}
+			res, err := tool.Invoke(context.Background(), tools.Invocation{Params: params})
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got.Path != "/v1/projects/p1/locations/l1/clusters" {
+				t.Fatalf("unexpected path: %s", got.Path)
+			}
+			if diff := cmp.Diff(tc.wantQuery, got.Query); diff != "" {
+				t.Fatalf("unexpected query: diff %v", diff)
+			}
+			if diff := cmp.Diff(tc.wantBody, got.Body); diff != "" {
+				t.Fatalf("unexpected request body: diff %v", diff)
+			}
+			if op, ok := res.(*alloydb.Operation); !ok || op.Name != "projects/p1/locations/l1/operations/op-1" {
+				t.Fatalf("unexpected result: %#v", res)
+			}
+		})
+	}
+}
+
+func TestPasswordIsSensitive(t *testing.T) {
+	src, _ := newMockAlloyDB(t, http.StatusOK, `{}`)
+	cfg := alloydbcreatecluster.Config{Name: "create-cluster", Kind: "alloydb-create-cluster", Source: src.Name}
+	tool, err := cfg.Initialize(map[string]sources.Source{src.Name: src})
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	params, err := tool.ParseParams(map[string]any{"project": "p1", "cluster": "c1", "password": "hunter2"}, nil)
+	if err != nil {
+		t.Fatalf("unable to parse params: %s", err)
+	}
+	if got := params.String(); strings.Contains(got, "hunter2") || !strings.Contains(got, tools.Redacted) {
+		t.Fatalf("password is not redacted: %s", got)
+	}
+}
@@ -27,6 +27,9 @@ import (
 
 const kind string = "alloydb-create-instance"
 
+// cpuCounts are the numbers of vCPUs of the machines of AlloyDB instances.
+var cpuCounts = []any{2, 4, 8, 16, 32, 64, 96, 128}
+
 func init() {
 	if !tools.Register(kind, newConfig) {
 		panic(fmt.Sprintf("tool kind %q already registered", kind))
@@ -70,6 +73,8 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		return nil, fmt.Errorf("invalid source for %q tool: source kind must be `alloydb-admin`", kind)
 	}
 
+	cpuCount := tools.NewIntParameterWithDefault("cpuCount", 2, "The number of vCPUs of each node of the instance. Valid values are: 2, 4, 8, 16, 32, 64, 96 and 128. Default is 2.")
+	cpuCount.AllowedValues = cpuCounts
 	allParameters := tools.Parameters{
 		tools.NewStringParameter("project", "The GCP project ID."),
 		tools.NewStringParameter("location", "The location of the cluster (e.g., 'us-central1')."),
@@ -78,12 +83,15 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		tools.NewStringParameterWithDefault("instanceType", "PRIMARY", "The type of instance to create. Valid values are: PRIMARY and READ_POOL. Default is PRIMARY"),
 		tools.NewStringParameterWithRequired("displayName", "An optional, user-friendly name for the instance.", false),
 		tools.NewIntParameterWithDefault("nodeCount", 1, "The number of nodes in the read pool. Required only if instanceType is READ_POOL. Default is 1."),
+		cpuCount,
+		tools.NewBooleanParameterWithDefault("enablePublicIp", true, "Whether the instance can be connected to with a public IP. Default is true."),
+		tools.NewBooleanParameterWithDefault("validateOnly", false, "If true, the request is only validated, and no instance is created. Default is false."),
 	}
 	paramManifest := allParameters.Manifest()
 
 	description := cfg.Description
 	if description == "" {
-		description = "Creates a new AlloyDB instance (PRIMARY or READ_POOL) within a cluster. This is a long-running operation. This will return operation id to be used by get operations tool. Take all parameters from user in one go."
+		description = "Creates a new AlloyDB instance (PRIMARY or READ_POOL) within a cluster. This is a long-running operation. This will return the operation, whose name ends with the operation id to be used by the wait for operation tool. Take all parameters from user in one go."
 	}
 	mcpManifest := tools.GetMcpManifest(cfg.Name, description, cfg.AuthRequired, allParameters)
 
@@ -97,6 +105,9 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	}, nil
 }
 
+// validate interface
+var _ tools.Tool = Tool{}
+
 // Tool represents the create-instance tool.
 type Tool struct {
 	Name        string `yaml:"name"`
@@ -111,8 +122,8 @@ type Tool struct {
 }
 
 // Invoke executes the tool's logic.
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	paramsMap := params.AsMap()
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
 	project, ok := paramsMap["project"].(string)
 	if !ok || project == "" {
 		return nil, fmt.Errorf("invalid or missing 'project' parameter; expected a non-empty string")
@@ -138,7 +149,22 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		return nil, fmt.Errorf("invalid 'instanceType' parameter; expected 'PRIMARY' or 'READ_POOL'")
 	}
 
-	service, err := t.Source.GetService(ctx, string(accessToken))
+	cpuCount, ok := paramsMap["cpuCount"].(int)
+	if !ok {
+		return nil, fmt.Errorf("invalid 'cpuCount' parameter; expected an integer")
+	}
+
+	enablePublicIp, ok := paramsMap["enablePublicIp"].(bool)
+	if !ok {
+		return nil, fmt.Errorf("invalid 'enablePublicIp' parameter; expected a boolean")
+	}
+
+	validateOnly, ok := paramsMap["validateOnly"].(bool)
+	if !ok {
+		return nil, fmt.Errorf("invalid 'validateOnly' parameter; expected a boolean")
+	}
+
+	service, err := t.Source.GetService(ctx, string(inv.AccessToken))
 	if err != nil {
 		return nil, err
 	}
@@ -148,8 +174,11 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	// Build the request body using the type-safe Instance struct.
 	instance := &alloydb.Instance{
 		InstanceType: instanceType,
+		MachineConfig: &alloydb.MachineConfig{
+			CpuCount: int64(cpuCount),
+		},
 		NetworkConfig: &alloydb.InstanceNetworkConfig{
-			EnablePublicIp: true,
+			EnablePublicIp: enablePublicIp,
 		},
 		DatabaseFlags: map[string]string{
 			"password.enforce_complexity": "on",
@@ -170,8 +199,14 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		}
 	}
 
-	// The Create API returns a long-running operation.
-	resp, err := service.Projects.Locations.Clusters.Instances.Create(urlString, instance).InstanceId(instanceID).Do()
+	// The Create API returns a long-running operation. The errors of the API,
+	// such as invalid settings, are returned as they are for the agent to
+	// correct them.
+	call := service.Projects.Locations.Clusters.Instances.Create(urlString, instance).InstanceId(instanceID)
+	if validateOnly {
+		call = call.ValidateOnly(true)
+	}
+	resp, err := call.Context(ctx).Do()
 	if err != nil {
 		return nil, fmt.Errorf("error creating AlloyDB instance: %w", err)
 	}
@@ -15,13 +15,24 @@
 package alloydbcreateinstance_test
 
 import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	alloydbadmin "github.com/googleapis/genai-toolbox/internal/sources/alloydbadmin"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
 	alloydbcreateinstance "github.com/googleapis/genai-toolbox/internal/tools/alloydb/alloydbcreateinstance"
+	"google.golang.org/api/alloydb/v1"
+	"google.golang.org/api/option"
 )
 
 func TestParseFromYaml(t *testing.T) {
@@ -92,3 +103,136 @@ func TestParseFromYaml(t *testing.T) {
 		})
 	}
 }
+
+// mockRequest is a request received by the mock AlloyDB Admin API.
+type mockRequest struct {
+	Path  string
+	Query map[string]string
+	Body  map[string]any
+}
+
+// newMockAlloyDB starts a server that answers the create calls of the
+// AlloyDB Admin API with status and body, and returns an alloydb-admin source
+// using it.
+func newMockAlloyDB(t *testing.T, status int, body string) (*alloydbadmin.Source, *mockRequest) {
+	t.Helper()
+	got := &mockRequest{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.Path = r.URL.Path
+		got.Query = map[string]string{}
+		for k := range r.URL.Query() {
+			if k != "alt" && k != "prettyPrint" {
+				got.Query[k] = r.URL.Query().Get(k)
+			}
+		}
+		b, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(b, &got.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(ts.Close)
+	service, err := alloydb.NewService(context.Background(), option.WithEndpoint(ts.URL+"/"), option.WithHTTPClient(ts.Client()))
+	if err != nil {
+		t.Fatalf("unable to create service: %s", err)
+	}
+	return &alloydbadmin.Source{Name: "my-alloydb-admin-source", Kind: alloydbadmin.SourceKind, Service: service}, got
+}
+
+func TestInvoke(t *testing.T) {
+	operation := `{"name":"projects/p1/locations/l1/operations/op-1","metadata":{"verb":"create"}}`
+	apiError := `{"error":{"code":400,"message":"Read pool node count must be between 1 and 20.","status":"INVALID_ARGUMENT"}}`
+	tcs := []struct {
+		desc      string
+		status    int
+		body      string
+		args      map[string]any
+		wantQuery map[string]string
+		wantBody  map[string]any
+		wantErr   string
+	}{
+		{
+			desc:      "create primary",
+			status:    http.StatusOK,
+			body:      operation,
+			args:      map[string]any{"project": "p1", "location": "l1", "cluster": "c1", "instance": "i1"},
+			wantQuery: map[string]string{"instanceId": "i1"},
+			wantBody: map[string]any{
+				"instanceType":  "PRIMARY",
+				"machineConfig": map[string]any{"cpuCount": float64(2)},
+				"networkConfig": map[string]any{"enablePublicIp": true},
+				"databaseFlags": map[string]any{"password.enforce_complexity": "on"},
+			},
+		},
+		{
+			desc:      "validate only read pool",
+			status:    http.StatusOK,
+			body:      operation,
+			args:      map[string]any{"project": "p1", "location": "l1", "cluster": "c1", "instance": "i1", "instanceType": "READ_POOL", "nodeCount": 3, "cpuCount": 8, "enablePublicIp": false, "validateOnly": true},
+			wantQuery: map[string]string{"instanceId": "i1", "validateOnly": "true"},
+			wantBody: map[string]any{
+				"instanceType":   "READ_POOL",
+				"machineConfig":  map[string]any{"cpuCount": float64(8)},
+				"networkConfig":  map[string]any{},
+				"readPoolConfig": map[string]any{"nodeCount": float64(3)},
+				"databaseFlags":  map[string]any{"password.enforce_complexity": "on"},
+			},
+		},
+		{
+			desc:    "api validation error",
+			status:  http.StatusBadRequest,
+			body:    apiError,
+			args:    map[string]any{"project": "p1", "location": "l1", "cluster": "c1", "instance": "i1", "instanceType": "READ_POOL", "nodeCount": 50, "validateOnly": true},
+			wantErr: "Read pool node count must be between 1 and 20.",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			src, got := newMockAlloyDB(t, tc.status, tc.body)
+			cfg := alloydbcreateinstance.Config{Name: "create-instance", Kind: "alloydb-create-instance", Source: src.Name}
+			tool, err := cfg.Initialize(map[string]sources.Source{src.Name: src})
+			if err != nil {
+				t.Fatalf("unable to initialize tool: %s", err)
+			}
+			params, err := tool.ParseParams(tc.args, nil)
+			if err != nil {
+				t.Fatalf("unable to parse params: %s", err)
+			}
+			res, err := tool.Invoke(context.Background(), tools.Invocation{Params: params})
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got.Path != "/v1/projects/p1/locations/l1/clusters/c1/instances" {
+				t.Fatalf("unexpected path: %s", got.Path)
+			}
+			if diff := cmp.Diff(tc.wantQuery, got.Query); diff != "" {
+				t.Fatalf("unexpected query: diff %v", diff)
+			}
+			if diff := cmp.Diff(tc.wantBody, got.Body); diff != "" {
+				t.Fatalf("unexpected request body: diff %v", diff)
+			}
+			if op, ok := res.(*alloydb.Operation); !ok || op.Name != "projects/p1/locations/l1/operations/op-1" {
+				t.Fatalf("unexpected result: %#v", res)
+			}
+		})
+	}
+}
+
+func TestInvalidCpuCount(t *testing.T) {
+	src, _ := newMockAlloyDB(t, http.StatusOK, `{}`)
+	cfg := alloydbcreateinstance.Config{Name: "create-instance", Kind: "alloydb-create-instance", Source: src.Name}
+	tool, err := cfg.Initialize(map[string]sources.Source{src.Name: src})
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	_, err = tool.ParseParams(map[string]any{"project": "p1", "location": "l1", "cluster": "c1", "instance": "i1", "cpuCount": 3}, nil)
+	if err == nil {
+		t.Fatalf("expected an error for an invalid cpu count")
+	}
+}
@@ -97,6 +97,9 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	}, nil
 }
 
+// validate interface
+var _ tools.Tool = Tool{}
+
 // Tool represents the create-user tool.
 type Tool struct {
 	Name        string `yaml:"name"`
@@ -111,8 +114,8 @@ type Tool struct {
 }
 
 // Invoke executes the tool's logic.
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	paramsMap := params.AsMap()
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
 	project, ok := paramsMap["project"].(string)
 	if !ok || project == "" {
 		return nil, fmt.Errorf("invalid or missing 'project' parameter; expected a non-empty string")
@@ -138,7 +141,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		return nil, fmt.Errorf("invalid or missing 'userType' parameter; expected 'ALLOYDB_BUILT_IN' or 'ALLOYDB_IAM_USER'")
 	}
 
-	service, err := t.Source.GetService(ctx, string(accessToken))
+	service, err := t.Source.GetService(ctx, string(inv.AccessToken))
 	if err != nil {
 		return nil, err
 	}
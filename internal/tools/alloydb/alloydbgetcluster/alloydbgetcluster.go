@@ -93,6 +93,9 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	}, nil
 }
 
+// validate interface
+var _ tools.Tool = Tool{}
+
 // Tool represents the get-cluster tool.
 type Tool struct {
 	Name string `yaml:"name"`
@@ -106,8 +109,8 @@ type Tool struct {
 }
 
 // Invoke executes the tool's logic.
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	paramsMap := params.AsMap()
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
 
 	project, ok := paramsMap["project"].(string)
 	if !ok {
@@ -122,7 +125,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		return nil, fmt.Errorf("invalid 'cluster' parameter; expected a string")
 	}
 
-	service, err := t.Source.GetService(ctx, string(accessToken))
+	service, err := t.Source.GetService(ctx, string(inv.AccessToken))
 	if err != nil {
 		return nil, err
 	}
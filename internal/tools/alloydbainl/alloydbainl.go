@@ -152,8 +152,8 @@ type Tool struct {
 	mcpManifest tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	sliceParams := params.AsSlice()
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	sliceParams := inv.Params.AsSlice()
 	allParamValues := make([]any, len(sliceParams)+1)
 	allParamValues[0] = fmt.Sprintf("%s", sliceParams[0]) // nl_question
 	allParamValues[1] = t.NLConfig                        // nl_config
@@ -165,6 +165,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute query: %w. Query: %v , Values: %v", err, t.Statement, allParamValues)
 	}
+	defer results.Close()
 
 	fields := results.FieldDescriptions()
 
@@ -180,6 +181,9 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		}
 		out = append(out, vMap)
 	}
+	if err := results.Err(); err != nil {
+		return nil, fmt.Errorf("error reading query results: %w", err)
+	}
 
 	return out, nil
 }
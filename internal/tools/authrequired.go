@@ -0,0 +1,67 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+// AllowUnauthenticatedConfig is the config of a tool set
+// `allowUnauthenticated`, which can be invoked without authentication even if
+// its toolsets, or the server, require authServices by default.
+type AllowUnauthenticatedConfig struct {
+	ToolConfig
+	AllowUnauthenticated bool
+}
+
+// validate interface
+var _ ToolConfig = AllowUnauthenticatedConfig{}
+
+// AuthRequiredTool requires the authServices inherited by a Tool from its
+// toolsets, or from the server, on top of its own. Requirements holds its own
+// and each inherited set of authServices: any one of each set must be
+// verified. AuthRequired holds all of them, and replaces the `authRequired` of
+// the manifests of the tool.
+type AuthRequiredTool struct {
+	Tool
+	AuthRequired []string
+	Requirements [][]string
+}
+
+func (t AuthRequiredTool) unwrap() Tool {
+	return t.Tool
+}
+
+func (t AuthRequiredTool) Authorized(verifiedAuthServices []string) bool {
+	for _, r := range t.Requirements {
+		if !IsAuthorized(r, verifiedAuthServices) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t AuthRequiredTool) Manifest() Manifest {
+	m := t.Tool.Manifest()
+	m.AuthRequired = t.AuthRequired
+	return m
+}
+
+func (t AuthRequiredTool) McpManifest() McpManifest {
+	m := t.Tool.McpManifest()
+	meta := make(map[string]any, len(m.Metadata)+1)
+	for k, v := range m.Metadata {
+		meta[k] = v
+	}
+	meta["toolbox/authInvoke"] = t.AuthRequired
+	m.Metadata = meta
+	return m
+}
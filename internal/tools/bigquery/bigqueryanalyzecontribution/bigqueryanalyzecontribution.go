@@ -173,8 +173,8 @@ type Tool struct {
 }
 
 // Invoke runs the contribution analysis.
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	paramsMap := params.AsMap()
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
 	inputData, ok := paramsMap["input_data"].(string)
 	if !ok {
 		return nil, fmt.Errorf("unable to cast input_data parameter %s", paramsMap["input_data"])
@@ -186,7 +186,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 
 	// Initialize new client if using user OAuth token
 	if t.UseClientOAuth {
-		tokenStr, err := accessToken.ParseBearerToken()
+		tokenStr, err := inv.AccessToken.ParseBearerToken()
 		if err != nil {
 			return nil, fmt.Errorf("error parsing access token: %w", err)
 		}
@@ -288,6 +288,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	)
 
 	createModelQuery := bqClient.Query(createModelSQL)
+	bqutil.SetJobLabels(ctx, createModelQuery)
 
 	// Get session from provider if in protected mode.
 	// Otherwise, a new session will be created by the first query.
@@ -332,6 +333,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 
 	getInsightsQuery := bqClient.Query(getInsightsSQL)
 	getInsightsQuery.ConnectionProperties = []*bigqueryapi.ConnectionProperty{{Key: "session_id", Value: sessionID}}
+	bqutil.SetJobLabels(ctx, getInsightsQuery)
 
 	job, err := getInsightsQuery.Run(ctx)
 	if err != nil {
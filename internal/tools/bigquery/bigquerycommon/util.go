@@ -19,9 +19,12 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	bigqueryapi "cloud.google.com/go/bigquery"
+	"cloud.google.com/go/civil"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
 	bigqueryrestapi "google.golang.org/api/bigquery/v2"
 )
 
@@ -57,6 +60,32 @@ func DryRunQuery(ctx context.Context, restService *bigqueryrestapi.Service, proj
 	return insertResponse, nil
 }
 
+// DryRunEstimate returns the number of bytes processed by the query of job,
+// as estimated by its dry run.
+func DryRunEstimate(job *bigqueryrestapi.Job) (tools.BudgetEstimate, error) {
+	if job == nil || job.Statistics == nil {
+		return nil, fmt.Errorf("dry run returned no statistics to estimate the query with")
+	}
+	return tools.BudgetEstimate{tools.BudgetBytesProcessed: float64(job.Statistics.TotalBytesProcessed)}, nil
+}
+
+// ClientLabel is the job label that holds the identity of the caller of a
+// tool, when client attribution is enabled.
+const ClientLabel = "toolbox_client"
+
+// SetJobLabels labels the job of q with the identity of the caller found in
+// ctx, if any, so that jobs can be attributed to clients.
+func SetJobLabels(ctx context.Context, q *bigqueryapi.Query) {
+	identity := util.ClientIdentityFromContext(ctx)
+	if identity == "" {
+		return
+	}
+	if q.Labels == nil {
+		q.Labels = make(map[string]string)
+	}
+	q.Labels[ClientLabel] = identity
+}
+
 // BQTypeStringFromToolType converts a tool parameter type string to a BigQuery standard SQL type string.
 func BQTypeStringFromToolType(toolType string) (string, error) {
 	switch toolType {
@@ -118,3 +147,20 @@ func InitializeDatasetParameters(
 
 	return projectParam, datasetParam
 }
+
+// ConvertValue converts v, a value of a row, to the result timezone of ctx,
+// if any. TIMESTAMP values are converted, and so are DATETIME values, which
+// are taken as UTC. DATE and TIME values are returned as they are, as are
+// the values of other types.
+func ConvertValue(ctx context.Context, v bigqueryapi.Value) any {
+	switch v := v.(type) {
+	case time.Time:
+		return tools.ConvertTimestamp(ctx, v)
+	case civil.DateTime:
+		if tools.ResultTimezoneFromContext(ctx) == nil {
+			return v
+		}
+		return tools.ConvertTimestamp(ctx, v.In(time.UTC))
+	}
+	return v
+}
@@ -0,0 +1,179 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bigquerycommon_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	bigqueryapi "cloud.google.com/go/bigquery"
+	"cloud.google.com/go/civil"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerycommon"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	bigqueryrestapi "google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/option"
+)
+
+func TestSetJobLabels(t *testing.T) {
+	tcs := []struct {
+		desc string
+		ctx  context.Context
+		want map[string]string
+	}{
+		{desc: "not attributed", ctx: context.Background(), want: nil},
+		{
+			desc: "attributed",
+			ctx:  util.WithClientAttribution(context.Background(), util.ClientAttributionHeader, "Billing Agent"),
+			want: map[string]string{"team": "finance", "toolbox_client": "billing_agent"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			// a BigQuery API that records the configuration of inserted jobs
+			var got map[string]string
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || !strings.HasSuffix(r.URL.Path, "/projects/my-project/jobs") {
+					http.Error(w, "unexpected request", http.StatusNotFound)
+					return
+				}
+				var job struct {
+					JobReference  map[string]any `json:"jobReference"`
+					Configuration struct {
+						Labels map[string]string `json:"labels"`
+					} `json:"configuration"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&job); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				got = job.Configuration.Labels
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(map[string]any{
+					"jobReference": job.JobReference,
+					"status":       map[string]any{"state": "DONE"},
+				})
+			}))
+			defer ts.Close()
+
+			client, err := bigqueryapi.NewClient(context.Background(), "my-project", option.WithEndpoint(ts.URL), option.WithoutAuthentication())
+			if err != nil {
+				t.Fatalf("unable to create client: %s", err)
+			}
+			defer client.Close()
+
+			q := client.Query("SELECT 1")
+			if tc.want != nil {
+				q.Labels = map[string]string{"team": "finance"}
+			}
+			bigquerycommon.SetJobLabels(tc.ctx, q)
+			if _, err := q.Run(tc.ctx); err != nil {
+				t.Fatalf("unable to run query: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected job labels: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestConvertValue(t *testing.T) {
+	tz, err := tools.NewResultTimezone("America/Los_Angeles")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx := tools.WithResultTimezone(context.Background(), tz)
+	date := civil.Date{Year: 2025, Month: time.November, Day: 2}
+	timeOfDay := civil.Time{Hour: 1, Minute: 30}
+	dateTime := civil.DateTime{Date: date, Time: civil.Time{Hour: 9, Minute: 30}}
+	tcs := []struct {
+		desc string
+		ctx  context.Context
+		in   bigqueryapi.Value
+		want any
+	}{
+		{
+			desc: "timestamp before fall back",
+			ctx:  ctx,
+			in:   time.Date(2025, 11, 2, 8, 30, 0, 0, time.UTC),
+			want: "2025-11-02T01:30:00-07:00",
+		},
+		{
+			desc: "timestamp after fall back",
+			ctx:  ctx,
+			in:   time.Date(2025, 11, 2, 9, 30, 0, 0, time.UTC),
+			want: "2025-11-02T01:30:00-08:00",
+		},
+		{
+			desc: "datetime taken as UTC",
+			ctx:  ctx,
+			in:   dateTime,
+			want: "2025-11-02T01:30:00-08:00",
+		},
+		{
+			desc: "datetime without timezone",
+			ctx:  context.Background(),
+			in:   dateTime,
+			want: dateTime,
+		},
+		{
+			desc: "date",
+			ctx:  ctx,
+			in:   date,
+			want: date,
+		},
+		{
+			desc: "time",
+			ctx:  ctx,
+			in:   timeOfDay,
+			want: timeOfDay,
+		},
+		{
+			desc: "string",
+			ctx:  ctx,
+			in:   "2025-11-02",
+			want: "2025-11-02",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := bigquerycommon.ConvertValue(tc.ctx, tc.in); got != tc.want {
+				t.Fatalf("unexpected value: got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDryRunEstimate(t *testing.T) {
+	job := &bigqueryrestapi.Job{Statistics: &bigqueryrestapi.JobStatistics{TotalBytesProcessed: 15_000_000_000}}
+	got, err := bigquerycommon.DryRunEstimate(job)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := tools.BudgetEstimate{tools.BudgetBytesProcessed: 15_000_000_000}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("incorrect estimate: diff %v", diff)
+	}
+
+	if _, err := bigquerycommon.DryRunEstimate(&bigqueryrestapi.Job{}); err == nil {
+		t.Fatalf("expected an error for a job without statistics")
+	}
+}
@@ -203,17 +203,17 @@ type Tool struct {
 	AllowedDatasets    []string
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
 	var tokenStr string
 	var err error
 
 	// Get credentials for the API call
 	if t.UseClientOAuth {
 		// Use client-side access token
-		if accessToken == "" {
+		if inv.AccessToken == "" {
 			return nil, fmt.Errorf("tool is configured for client OAuth but no token was provided in the request header: %w", tools.ErrUnauthorized)
 		}
-		tokenStr, err = accessToken.ParseBearerToken()
+		tokenStr, err = inv.AccessToken.ParseBearerToken()
 		if err != nil {
 			return nil, fmt.Errorf("error parsing access token: %w", err)
 		}
@@ -230,7 +230,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	}
 
 	// Extract parameters from the map
-	mapParams := params.AsMap()
+	mapParams := inv.Params.AsMap()
 	userQuery, _ := mapParams["user_query_with_context"].(string)
 
 	finalQueryText := fmt.Sprintf("%s\n**User Query and Context:**\n%s", instructions, userQuery)
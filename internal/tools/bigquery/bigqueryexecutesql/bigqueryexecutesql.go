@@ -37,6 +37,7 @@ func init() {
 	if !tools.Register(kind, newConfig) {
 		panic(fmt.Sprintf("tool kind %q already registered", kind))
 	}
+	tools.RegisterOutputSchema(kind, tools.RowsOutputSchema)
 }
 
 func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
@@ -69,6 +70,9 @@ type Config struct {
 	Source       string   `yaml:"source" validate:"required"`
 	Description  string   `yaml:"description" validate:"required"`
 	AuthRequired []string `yaml:"authRequired"`
+	// Budget, if set, rejects queries whose dry run processes more bytes
+	// than its limit.
+	Budget *tools.BudgetSpec `yaml:"budget"`
 }
 
 // validate interface
@@ -122,6 +126,11 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		}
 	}
 
+	budget, err := tools.NewBudget(cfg.Budget, tools.BudgetBytesProcessed)
+	if err != nil {
+		return nil, err
+	}
+
 	sqlParameter := tools.NewStringParameter("sql", sqlDescriptionBuilder.String())
 	dryRunParameter := tools.NewBooleanParameterWithDefault(
 		"dry_run",
@@ -129,7 +138,7 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		"If set to true, the query will be validated and information about the execution will be returned "+
 			"without running the query. Defaults to false.",
 	)
-	parameters := tools.Parameters{sqlParameter, dryRunParameter}
+	parameters := append(tools.Parameters{sqlParameter, dryRunParameter}, budget.Parameters()...)
 	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, parameters)
 
 	// finish tool setup
@@ -146,6 +155,7 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		SessionProvider:  s.BigQuerySession(),
 		IsDatasetAllowed: s.IsDatasetAllowed,
 		AllowedDatasets:  allowedDatasets,
+		Budget:           budget,
 		manifest:         tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
 		mcpManifest:      mcpManifest,
 	}
@@ -169,12 +179,13 @@ type Tool struct {
 	ClientCreator    bigqueryds.BigqueryClientCreator
 	IsDatasetAllowed func(projectID, datasetID string) bool
 	AllowedDatasets  []string
+	Budget           *tools.Budget
 	manifest         tools.Manifest
 	mcpManifest      tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	paramsMap := params.AsMap()
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
 	sql, ok := paramsMap["sql"].(string)
 	if !ok {
 		return nil, fmt.Errorf("unable to cast sql parameter %s", paramsMap["sql"])
@@ -190,7 +201,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	var err error
 	// Initialize new client if using user OAuth token
 	if t.UseClientOAuth {
-		tokenStr, err := accessToken.ParseBearerToken()
+		tokenStr, err := inv.AccessToken.ParseBearerToken()
 		if err != nil {
 			return nil, fmt.Errorf("error parsing access token: %w", err)
 		}
@@ -299,8 +310,18 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		return "Dry run was requested, but no job information was returned.", nil
 	}
 
+	// the dry run above already estimated the query, so checking it against
+	// the budget takes no extra round-trip
+	err = t.Budget.Enforce(ctx, t.Name, inv.Params, func() (tools.BudgetEstimate, error) {
+		return bqutil.DryRunEstimate(dryRunJob)
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	query := bqClient.Query(sql)
 	query.Location = bqClient.Location
+	bqutil.SetJobLabels(ctx, query)
 
 	query.ConnectionProperties = connProps
 
@@ -334,7 +355,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		}
 		vMap := make(map[string]any)
 		for key, value := range row {
-			vMap[key] = value
+			vMap[key] = bqutil.ConvertValue(ctx, value)
 		}
 		out = append(out, vMap)
 	}
@@ -21,6 +21,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigqueryexecutesql"
 )
 
@@ -53,6 +54,29 @@ func TestParseFromYamlBigQueryExecuteSql(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with budget",
+			in: `
+			tools:
+				example_tool:
+					kind: bigquery-execute-sql
+					source: my-instance
+					description: some description
+					budget:
+						maxBytesProcessed: 10000000000
+						allowOverride: true
+			`,
+			want: server.ToolConfigs{
+				"example_tool": bigqueryexecutesql.Config{
+					Name:         "example_tool",
+					Kind:         "bigquery-execute-sql",
+					Source:       "my-instance",
+					Description:  "some description",
+					AuthRequired: []string{},
+					Budget:       &tools.BudgetSpec{MaxBytesProcessed: 10_000_000_000, AllowOverride: true},
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
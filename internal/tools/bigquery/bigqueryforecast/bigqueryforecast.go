@@ -152,8 +152,8 @@ type Tool struct {
 	mcpManifest      tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	paramsMap := params.AsMap()
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
 	historyData, ok := paramsMap["history_data"].(string)
 	if !ok {
 		return nil, fmt.Errorf("unable to cast history_data parameter %v", paramsMap["history_data"])
@@ -193,7 +193,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 
 	// Initialize new client if using user OAuth token
 	if t.UseClientOAuth {
-		tokenStr, err := accessToken.ParseBearerToken()
+		tokenStr, err := inv.AccessToken.ParseBearerToken()
 		if err != nil {
 			return nil, fmt.Errorf("error parsing access token: %w", err)
 		}
@@ -278,6 +278,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	// JobStatistics.QueryStatistics.StatementType
 	query := bqClient.Query(sql)
 	query.Location = bqClient.Location
+	bqutil.SetJobLabels(ctx, query)
 	session, err := t.SessionProvider(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get BigQuery session: %w", err)
@@ -16,7 +16,10 @@ package bigquerygetdatasetinfo
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"strings"
 
 	bigqueryapi "cloud.google.com/go/bigquery"
 	yaml "github.com/goccy/go-yaml"
@@ -29,11 +32,54 @@ import (
 const kind string = "bigquery-get-dataset-info"
 const projectKey string = "project"
 const datasetKey string = "dataset"
+const includeAccessKey string = "includeAccess"
 
 func init() {
 	if !tools.Register(kind, newConfig) {
 		panic(fmt.Sprintf("tool kind %q already registered", kind))
 	}
+	tools.RegisterOutputSchema(kind, outputSchema)
+}
+
+// outputSchema describes the dataset metadata returned by the tool, as
+// encoded from bigquery.DatasetMetadata. Durations are in nanoseconds.
+func outputSchema() map[string]any {
+	str := map[string]any{"type": "string"}
+	duration := map[string]any{"type": "integer", "description": "A duration in nanoseconds."}
+	timestamp := map[string]any{"type": "string", "format": "date-time"}
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"Name":                   str,
+			"Description":            str,
+			"Location":               str,
+			"DefaultTableExpiration": duration,
+			"Labels":                 map[string]any{"type": []any{"object", "null"}, "additionalProperties": str},
+			"Access": map[string]any{
+				"type":        "array",
+				"description": "The access entries of the dataset, only returned if includeAccess is set.",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"role":       str,
+						"entityType": str,
+						"entity":     str,
+					},
+				},
+			},
+			"DefaultPartitionExpiration": duration,
+			"DefaultCollation":           str,
+			"MaxTimeTravel":              duration,
+			"StorageBillingModel":        str,
+			"CreationTime":               timestamp,
+			"LastModifiedTime":           timestamp,
+			"FullID":                     map[string]any{"type": "string", "description": "The full dataset ID in the form projectID:datasetID."},
+			"Tags":                       map[string]any{"type": []any{"array", "null"}, "items": map[string]any{"type": "object"}},
+			"IsCaseInsensitive":          map[string]any{"type": "boolean"},
+			"ETag":                       str,
+		},
+		"required": []any{"FullID", "Location"},
+	}
 }
 
 func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
@@ -64,6 +110,9 @@ type Config struct {
 	Source       string   `yaml:"source" validate:"required"`
 	Description  string   `yaml:"description" validate:"required"`
 	AuthRequired []string `yaml:"authRequired"`
+	// RedactPrincipals, if set, hashes the emails of the users granted
+	// access to datasets. Groups, domains and special groups are kept.
+	RedactPrincipals bool `yaml:"redactPrincipals"`
 }
 
 // validate interface
@@ -97,7 +146,8 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		defaultProjectID,
 		projectKey, datasetKey,
 		projectDescription, datasetDescription)
-	parameters := tools.Parameters{projectParameter, datasetParameter}
+	includeAccessParameter := tools.NewBooleanParameterWithDefault(includeAccessKey, false, "Whether to return who can access the dataset, as the roles granted to users, groups, domains and other entities.")
+	parameters := tools.Parameters{projectParameter, datasetParameter, includeAccessParameter}
 
 	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, parameters)
 
@@ -111,6 +161,7 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		ClientCreator:    s.BigQueryClientCreator(),
 		Client:           s.BigQueryClient(),
 		IsDatasetAllowed: s.IsDatasetAllowed,
+		RedactPrincipals: cfg.RedactPrincipals,
 		manifest:         tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
 		mcpManifest:      mcpManifest,
 	}
@@ -126,6 +177,8 @@ type Tool struct {
 	AuthRequired   []string         `yaml:"authRequired"`
 	UseClientOAuth bool             `yaml:"useClientOAuth"`
 	Parameters     tools.Parameters `yaml:"parameters"`
+	// RedactPrincipals is set to hash the emails of users. See Config.
+	RedactPrincipals bool `yaml:"redactPrincipals"`
 
 	Client           *bigqueryapi.Client
 	ClientCreator    bigqueryds.BigqueryClientCreator
@@ -135,8 +188,8 @@ type Tool struct {
 	mcpManifest      tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	mapParams := params.AsMap()
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	mapParams := inv.Params.AsMap()
 	projectId, ok := mapParams[projectKey].(string)
 	if !ok {
 		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected a string", projectKey)
@@ -152,7 +205,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 
 	// Initialize new client if using user OAuth token
 	if t.UseClientOAuth {
-		tokenStr, err := accessToken.ParseBearerToken()
+		tokenStr, err := inv.AccessToken.ParseBearerToken()
 		if err != nil {
 			return nil, fmt.Errorf("error parsing access token: %w", err)
 		}
@@ -173,7 +226,78 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		return nil, fmt.Errorf("failed to get metadata for dataset %s (in project %s): %w", datasetId, projectId, err)
 	}
 
-	return metadata, nil
+	// the raw access entries are never returned, as they may name principals
+	result := Result{DatasetMetadata: metadata}
+	if includeAccess, _ := mapParams[includeAccessKey].(bool); includeAccess {
+		result.Access = NormalizeAccess(metadata.Access, t.RedactPrincipals)
+	}
+	metadata.Access = nil
+	return result, nil
+}
+
+// Result is the metadata of a dataset, whose access entries are replaced by
+// their normalized form.
+type Result struct {
+	*bigqueryapi.DatasetMetadata
+	// Access shadows the raw access entries of the metadata. It is only set
+	// if the access entries are requested.
+	Access []AccessEntry `json:"Access,omitempty"`
+}
+
+// AccessEntry is a role granted to an entity on a dataset.
+type AccessEntry struct {
+	Role string `json:"role"`
+	// EntityType is the kind of the entity, named after the field of the
+	// BigQuery API holding it, such as "userByEmail" or "groupByEmail".
+	EntityType string `json:"entityType"`
+	Entity     string `json:"entity"`
+}
+
+// entityTypes names the kinds of entities as the BigQuery API does.
+var entityTypes = map[bigqueryapi.EntityType]string{
+	bigqueryapi.DomainEntity:       "domain",
+	bigqueryapi.GroupEmailEntity:   "groupByEmail",
+	bigqueryapi.UserEmailEntity:    "userByEmail",
+	bigqueryapi.SpecialGroupEntity: "specialGroup",
+	bigqueryapi.ViewEntity:         "view",
+	bigqueryapi.IAMMemberEntity:    "iamMember",
+	bigqueryapi.RoutineEntity:      "routine",
+	bigqueryapi.DatasetEntity:      "dataset",
+}
+
+// NormalizeAccess returns the access entries of a dataset as AccessEntry
+// values. Views, routines and datasets are identified by their qualified
+// names. If redact is set, the emails of users are hashed.
+func NormalizeAccess(entries []*bigqueryapi.AccessEntry, redact bool) []AccessEntry {
+	out := make([]AccessEntry, 0, len(entries))
+	for _, e := range entries {
+		entityType, ok := entityTypes[e.EntityType]
+		if !ok {
+			entityType = "unknown"
+		}
+		entity := e.Entity
+		switch {
+		case e.EntityType == bigqueryapi.ViewEntity && e.View != nil:
+			entity = strings.Join([]string{e.View.ProjectID, e.View.DatasetID, e.View.TableID}, ".")
+		case e.EntityType == bigqueryapi.RoutineEntity && e.Routine != nil:
+			entity = strings.Join([]string{e.Routine.ProjectID, e.Routine.DatasetID, e.Routine.RoutineID}, ".")
+		case e.EntityType == bigqueryapi.DatasetEntity && e.Dataset != nil && e.Dataset.Dataset != nil:
+			entity = e.Dataset.Dataset.ProjectID + "." + e.Dataset.Dataset.DatasetID
+		case redact && e.EntityType == bigqueryapi.UserEmailEntity:
+			entity = RedactEmail(entity)
+		case redact && e.EntityType == bigqueryapi.IAMMemberEntity && strings.HasPrefix(entity, "user:"):
+			entity = "user:" + RedactEmail(strings.TrimPrefix(entity, "user:"))
+		}
+		out = append(out, AccessEntry{Role: string(e.Role), EntityType: entityType, Entity: entity})
+	}
+	return out
+}
+
+// RedactEmail replaces an email by a hash of it, so that the entries of the
+// same user can still be matched.
+func RedactEmail(email string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(email)))
+	return "sha256:" + hex.EncodeToString(sum[:8])
 }
 
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
@@ -15,11 +15,16 @@
 package bigquerygetdatasetinfo_test
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 
+	bigqueryapi "cloud.google.com/go/bigquery"
 	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	bigqueryds "github.com/googleapis/genai-toolbox/internal/sources/bigquery"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
 	"github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerygetdatasetinfo"
 )
@@ -34,6 +39,27 @@ func TestParseFromYamlBigQueryGetDatasetInfo(t *testing.T) {
 		in   string
 		want server.ToolConfigs
 	}{
+		{
+			desc: "redacting principals",
+			in: `
+			tools:
+				example_tool:
+					kind: bigquery-get-dataset-info
+					source: my-instance
+					description: some description
+					redactPrincipals: true
+			`,
+			want: server.ToolConfigs{
+				"example_tool": bigquerygetdatasetinfo.Config{
+					Name:             "example_tool",
+					Kind:             "bigquery-get-dataset-info",
+					Source:           "my-instance",
+					Description:      "some description",
+					AuthRequired:     []string{},
+					RedactPrincipals: true,
+				},
+			},
+		},
 		{
 			desc: "basic example",
 			in: `
@@ -71,3 +97,110 @@ func TestParseFromYamlBigQueryGetDatasetInfo(t *testing.T) {
 	}
 
 }
+
+type fakeSource struct{}
+
+func (fakeSource) SourceKind() string                                      { return "bigquery" }
+func (fakeSource) BigQueryProject() string                                 { return "my-project" }
+func (fakeSource) BigQueryClient() *bigqueryapi.Client                     { return nil }
+func (fakeSource) BigQueryClientCreator() bigqueryds.BigqueryClientCreator { return nil }
+func (fakeSource) UseClientAuthorization() bool                            { return false }
+func (fakeSource) IsDatasetAllowed(string, string) bool                    { return true }
+func (fakeSource) BigQueryAllowedDatasets() []string                       { return nil }
+
+func TestManifest(t *testing.T) {
+	cfg := bigquerygetdatasetinfo.Config{
+		Name:        "example_tool",
+		Kind:        "bigquery-get-dataset-info",
+		Source:      "my-instance",
+		Description: "some description",
+	}
+	tool, err := cfg.Initialize(map[string]sources.Source{"my-instance": fakeSource{}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	got, err := json.Marshal(tool.Manifest().Parameters)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// the project defaults to the project of the source, so it is not
+	// required
+	want := `[` +
+		`{"name":"project","type":"string","required":false,"description":"The Google Cloud project ID containing the dataset.","authSources":[],"default":"my-project","examples":["my-project"]},` +
+		`{"name":"dataset","type":"string","required":true,"description":"The dataset to get metadata information. Can be in ` + "`project.dataset`" + ` format.","authSources":[],"examples":["example"]},` +
+		`{"name":"includeAccess","type":"boolean","required":false,"description":"Whether to return who can access the dataset, as the roles granted to users, groups, domains and other entities.","authSources":[],"default":false,"examples":[false]}` +
+		`]`
+	if string(got) != want {
+		t.Fatalf("unexpected manifest parameters:\ngot  %s\nwant %s", got, want)
+	}
+
+	// the MCP input schema agrees on which parameters are required
+	if diff := cmp.Diff([]string{"dataset"}, tool.McpManifest().InputSchema.Required); diff != "" {
+		t.Fatalf("incorrect required parameters: diff %v", diff)
+	}
+}
+
+func TestNormalizeAccess(t *testing.T) {
+	entries := []*bigqueryapi.AccessEntry{
+		{Role: bigqueryapi.OwnerRole, EntityType: bigqueryapi.SpecialGroupEntity, Entity: "projectOwners"},
+		{Role: bigqueryapi.ReaderRole, EntityType: bigqueryapi.UserEmailEntity, Entity: "Alice@example.com"},
+		{Role: bigqueryapi.ReaderRole, EntityType: bigqueryapi.GroupEmailEntity, Entity: "analysts@example.com"},
+		{Role: bigqueryapi.ReaderRole, EntityType: bigqueryapi.DomainEntity, Entity: "example.com"},
+		{Role: "roles/bigquery.dataViewer", EntityType: bigqueryapi.IAMMemberEntity, Entity: "user:alice@example.com"},
+		{Role: "roles/bigquery.dataViewer", EntityType: bigqueryapi.IAMMemberEntity, Entity: "group:admins@example.com"},
+		{EntityType: bigqueryapi.ViewEntity, View: &bigqueryapi.Table{ProjectID: "p", DatasetID: "d", TableID: "v"}},
+		{EntityType: bigqueryapi.RoutineEntity, Routine: &bigqueryapi.Routine{ProjectID: "p", DatasetID: "d", RoutineID: "r"}},
+		{EntityType: bigqueryapi.DatasetEntity, Dataset: &bigqueryapi.DatasetAccessEntry{Dataset: &bigqueryapi.Dataset{ProjectID: "p", DatasetID: "shared"}}},
+	}
+	want := []bigquerygetdatasetinfo.AccessEntry{
+		{Role: "OWNER", EntityType: "specialGroup", Entity: "projectOwners"},
+		{Role: "READER", EntityType: "userByEmail", Entity: "Alice@example.com"},
+		{Role: "READER", EntityType: "groupByEmail", Entity: "analysts@example.com"},
+		{Role: "READER", EntityType: "domain", Entity: "example.com"},
+		{Role: "roles/bigquery.dataViewer", EntityType: "iamMember", Entity: "user:alice@example.com"},
+		{Role: "roles/bigquery.dataViewer", EntityType: "iamMember", Entity: "group:admins@example.com"},
+		{EntityType: "view", Entity: "p.d.v"},
+		{EntityType: "routine", Entity: "p.d.r"},
+		{EntityType: "dataset", Entity: "p.shared"},
+	}
+	if diff := cmp.Diff(want, bigquerygetdatasetinfo.NormalizeAccess(entries, false)); diff != "" {
+		t.Fatalf("unexpected access entries (-want +got):\n%s", diff)
+	}
+
+	// the emails of users are hashed, the same user hashing the same
+	hash := bigquerygetdatasetinfo.RedactEmail("alice@example.com")
+	if !strings.HasPrefix(hash, "sha256:") || strings.Contains(hash, "alice") {
+		t.Fatalf("unexpected redacted email: %s", hash)
+	}
+	want[1].Entity = hash
+	want[4].Entity = "user:" + hash
+	if diff := cmp.Diff(want, bigquerygetdatasetinfo.NormalizeAccess(entries, true)); diff != "" {
+		t.Fatalf("unexpected redacted access entries (-want +got):\n%s", diff)
+	}
+}
+
+func TestResultStripsAccess(t *testing.T) {
+	metadata := &bigqueryapi.DatasetMetadata{
+		Location: "US",
+		Access:   []*bigqueryapi.AccessEntry{{Role: bigqueryapi.ReaderRole, EntityType: bigqueryapi.UserEmailEntity, Entity: "alice@example.com"}},
+	}
+	result := bigquerygetdatasetinfo.Result{DatasetMetadata: metadata}
+	metadata.Access = nil
+	got, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if strings.Contains(string(got), "Access") || strings.Contains(string(got), "alice") {
+		t.Fatalf("expected the access entries to be stripped, got %s", got)
+	}
+
+	result.Access = []bigquerygetdatasetinfo.AccessEntry{{Role: "READER", EntityType: "userByEmail", Entity: "sha256:0123"}}
+	got, err = json.Marshal(result)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := `"Access":[{"role":"READER","entityType":"userByEmail","entity":"sha256:0123"}]`
+	if !strings.Contains(string(got), want) || !strings.Contains(string(got), `"Location":"US"`) {
+		t.Fatalf("unexpected result: got %s, want it to contain %s", got, want)
+	}
+}
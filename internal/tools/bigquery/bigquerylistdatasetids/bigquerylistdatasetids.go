@@ -134,11 +134,11 @@ type Tool struct {
 	mcpManifest     tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
 	if len(t.AllowedDatasets) > 0 {
 		return t.AllowedDatasets, nil
 	}
-	mapParams := params.AsMap()
+	mapParams := inv.Params.AsMap()
 	projectId, ok := mapParams[projectKey].(string)
 	if !ok {
 		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected a string", projectKey)
@@ -147,7 +147,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	bqClient := t.Client
 	// Initialize new client if using user OAuth token
 	if t.UseClientOAuth {
-		tokenStr, err := accessToken.ParseBearerToken()
+		tokenStr, err := inv.AccessToken.ParseBearerToken()
 		if err != nil {
 			return nil, fmt.Errorf("error parsing access token: %w", err)
 		}
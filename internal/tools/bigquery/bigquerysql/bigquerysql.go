@@ -37,6 +37,7 @@ func init() {
 	if !tools.Register(kind, newConfig) {
 		panic(fmt.Sprintf("tool kind %q already registered", kind))
 	}
+	tools.RegisterOutputSchema(kind, tools.RowsOutputSchema)
 }
 
 func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
@@ -122,6 +123,7 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 // validate interface
 var _ tools.Tool = Tool{}
+var _ tools.Renderer = Tool{}
 
 type Tool struct {
 	Name               string           `yaml:"name"`
@@ -141,11 +143,11 @@ type Tool struct {
 	mcpManifest     tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
 	highLevelParams := make([]bigqueryapi.QueryParameter, 0, len(t.Parameters))
 	lowLevelParams := make([]*bigqueryrestapi.QueryParameter, 0, len(t.Parameters))
 
-	paramsMap := params.AsMap()
+	paramsMap := inv.Params.AsMap()
 	newStatement, err := tools.ResolveTemplateParams(t.TemplateParameters, t.Statement, paramsMap)
 	if err != nil {
 		return nil, fmt.Errorf("unable to extract template params %w", err)
@@ -223,7 +225,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 
 	// Initialize new client if using user OAuth token
 	if t.UseClientOAuth {
-		tokenStr, err := accessToken.ParseBearerToken()
+		tokenStr, err := inv.AccessToken.ParseBearerToken()
 		if err != nil {
 			return nil, fmt.Errorf("error parsing access token: %w", err)
 		}
@@ -236,6 +238,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	query := bqClient.Query(newStatement)
 	query.Parameters = highLevelParams
 	query.Location = bqClient.Location
+	bqutil.SetJobLabels(ctx, query)
 
 	connProps := []*bigqueryapi.ConnectionProperty{}
 	if t.SessionProvider != nil {
@@ -280,7 +283,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		}
 		vMap := make(map[string]any)
 		for key, value := range row {
-			vMap[key] = value
+			vMap[key] = bqutil.ConvertValue(ctx, value)
 		}
 		out = append(out, vMap)
 	}
@@ -301,6 +304,11 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	return "Query executed successfully and returned no content.", nil
 }
 
+// Render resolves the statement of an invocation without running it.
+func (t Tool) Render(params tools.ParamValues) (tools.RenderedStatement, error) {
+	return tools.RenderStatement(t.Statement, t.TemplateParameters, t.Parameters, params)
+}
+
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
 	return tools.ParseParams(t.AllParams, data, claims)
 }
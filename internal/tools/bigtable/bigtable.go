@@ -31,6 +31,7 @@ func init() {
 	if !tools.Register(kind, newConfig) {
 		panic(fmt.Sprintf("tool kind %q already registered", kind))
 	}
+	tools.RegisterOutputSchema(kind, tools.RowsOutputSchema)
 }
 
 func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
@@ -106,6 +107,7 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 // validate interface
 var _ tools.Tool = Tool{}
+var _ tools.Renderer = Tool{}
 
 type Tool struct {
 	Name               string           `yaml:"name"`
@@ -160,8 +162,8 @@ func getMapParamsType(tparams tools.Parameters, params tools.ParamValues) (map[s
 	return btParamTypes, nil
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	paramsMap := params.AsMap()
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
 	newStatement, err := tools.ResolveTemplateParams(t.TemplateParameters, t.Statement, paramsMap)
 	if err != nil {
 		return nil, fmt.Errorf("unable to extract template params %w", err)
@@ -213,6 +215,11 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	return out, nil
 }
 
+// Render resolves the statement of an invocation without running it.
+func (t Tool) Render(params tools.ParamValues) (tools.RenderedStatement, error) {
+	return tools.RenderStatement(t.Statement, t.TemplateParameters, t.Parameters, params)
+}
+
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
 	return tools.ParseParams(t.AllParams, data, claims)
 }
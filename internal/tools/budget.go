@@ -0,0 +1,232 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+const (
+	// ConfirmBudgetParameter is the parameter confirming that an invocation
+	// is to run even if it is over the budget of its tool.
+	ConfirmBudgetParameter = "_confirmBudget"
+	// SkipBudgetParameter is the parameter asking for an invocation to run
+	// without estimating it first.
+	SkipBudgetParameter = "_skipBudget"
+)
+
+// BudgetMetric is an estimate of the size of a query, which a budget limits.
+type BudgetMetric string
+
+const (
+	// BudgetBytesProcessed is the number of bytes a query processes.
+	BudgetBytesProcessed BudgetMetric = "bytesProcessed"
+	// BudgetRows is the number of rows the planner estimates a query returns.
+	BudgetRows BudgetMetric = "rows"
+	// BudgetCost is the total cost the planner estimates for a query.
+	BudgetCost BudgetMetric = "cost"
+)
+
+// BudgetEstimate holds the estimates of a query, by metric.
+type BudgetEstimate map[BudgetMetric]float64
+
+// BudgetSpec is the `budget` block of a SQL tool config. Before a query is
+// run, it is estimated, and the query is rejected if an estimate is above
+// its limit. Limits left unset, or set to zero, are not enforced.
+type BudgetSpec struct {
+	// MaxBytesProcessed limits the number of bytes a query processes.
+	MaxBytesProcessed int64 `yaml:"maxBytesProcessed"`
+	// MaxRows limits the number of rows the planner estimates.
+	MaxRows float64 `yaml:"maxRows"`
+	// MaxCost limits the total cost the planner estimates.
+	MaxCost float64 `yaml:"maxCost"`
+	// AllowOverride lets callers run a query over budget by retrying it with
+	// ConfirmBudgetParameter set.
+	AllowOverride bool `yaml:"allowOverride"`
+	// SkipAuthServices are the authServices whose users may skip the
+	// estimate with SkipBudgetParameter.
+	SkipAuthServices []string `yaml:"skipAuthServices"`
+}
+
+// limits returns the limits of spec, by metric.
+func (s BudgetSpec) limits() map[BudgetMetric]float64 {
+	return map[BudgetMetric]float64{
+		BudgetBytesProcessed: float64(s.MaxBytesProcessed),
+		BudgetRows:           s.MaxRows,
+		BudgetCost:           s.MaxCost,
+	}
+}
+
+// budgetLimitNames are the names of the fields setting the limit of each
+// metric.
+var budgetLimitNames = map[BudgetMetric]string{
+	BudgetBytesProcessed: "maxBytesProcessed",
+	BudgetRows:           "maxRows",
+	BudgetCost:           "maxCost",
+}
+
+// Budget is a validated BudgetSpec.
+type Budget struct {
+	spec BudgetSpec
+	// metrics are the metrics limited by the budget, in the order they are
+	// checked.
+	metrics []BudgetMetric
+}
+
+// NewBudget validates spec for a tool able to estimate the given metrics.
+// It returns nil if spec is nil.
+func NewBudget(spec *BudgetSpec, metrics ...BudgetMetric) (*Budget, error) {
+	if spec == nil {
+		return nil, nil
+	}
+	supported := make([]string, 0, len(metrics))
+	for _, m := range metrics {
+		supported = append(supported, budgetLimitNames[m])
+	}
+	limits := spec.limits()
+	var limited []BudgetMetric
+	for _, m := range []BudgetMetric{BudgetBytesProcessed, BudgetRows, BudgetCost} {
+		limit := limits[m]
+		if limit < 0 {
+			return nil, fmt.Errorf("budget %s must not be negative, got %s", budgetLimitNames[m], formatBudgetValue(limit))
+		}
+		if limit == 0 {
+			continue
+		}
+		if !slices.Contains(metrics, m) {
+			return nil, fmt.Errorf("budget %s is not supported by this tool, must be one of %q", budgetLimitNames[m], supported)
+		}
+		limited = append(limited, m)
+	}
+	if len(limited) == 0 {
+		return nil, fmt.Errorf("budget must set at least one of %q", supported)
+	}
+	return &Budget{spec: *spec, metrics: limited}, nil
+}
+
+// Spec returns the BudgetSpec b was created from.
+func (b *Budget) Spec() BudgetSpec {
+	return b.spec
+}
+
+// Parameters returns the optional ConfirmBudgetParameter and
+// SkipBudgetParameter that tools with a budget add to their parameters, as
+// allowed by the budget, or none if b is nil.
+func (b *Budget) Parameters() Parameters {
+	if b == nil {
+		return nil
+	}
+	var params Parameters
+	if b.spec.AllowOverride {
+		params = append(params, NewBooleanParameterWithDefault(ConfirmBudgetParameter, false,
+			"Set to true to run the query even if it is over budget, after a previous invocation was rejected for it."))
+	}
+	if len(b.spec.SkipAuthServices) > 0 {
+		params = append(params, NewBooleanParameterWithDefault(SkipBudgetParameter, false,
+			"Set to true to run the query without checking it against the budget. Only allowed to trusted callers."))
+	}
+	return params
+}
+
+// Required reports whether an invocation of the tool named name must be
+// estimated and checked before it runs. It is not when b is nil, when the
+// caller confirmed the invocation, or when a trusted caller skips the check,
+// and it fails when an untrusted caller tries to.
+func (b *Budget) Required(ctx context.Context, name string, params ParamValues) (bool, error) {
+	if b == nil {
+		return false, nil
+	}
+	paramsMap := params.AsMap()
+	if skip, _ := paramsMap[SkipBudgetParameter].(bool); skip {
+		verified := util.VerifiedAuthServicesFromContext(ctx)
+		if !slices.ContainsFunc(b.spec.SkipAuthServices, func(a string) bool { return slices.Contains(verified, a) }) {
+			return false, fmt.Errorf("skipping the budget of %q requires one of the authServices %q: %w", name, b.spec.SkipAuthServices, ErrUnauthorized)
+		}
+		if logger, err := util.LoggerFromContext(ctx); err == nil {
+			logger.DebugContext(ctx, fmt.Sprintf("tool %q skipped its budget for authServices %q", name, verified))
+		}
+		return false, nil
+	}
+	if confirm, _ := paramsMap[ConfirmBudgetParameter].(bool); confirm && b.spec.AllowOverride {
+		if logger, err := util.LoggerFromContext(ctx); err == nil {
+			logger.WarnContext(ctx, fmt.Sprintf("tool %q was invoked over its budget, as confirmed by the caller", name))
+		}
+		return false, nil
+	}
+	return true, nil
+}
+
+// Check returns an error if estimate is over one of the limits of b. The
+// error wraps a BudgetExceededError, with a hint on how to proceed.
+func (b *Budget) Check(name string, estimate BudgetEstimate) error {
+	limits := b.spec.limits()
+	for _, m := range b.metrics {
+		v, ok := estimate[m]
+		if !ok || v <= limits[m] {
+			continue
+		}
+		exceeded := &BudgetExceededError{Tool: name, Metric: m, Estimate: v, Limit: limits[m], CanOverride: b.spec.AllowOverride}
+		hint := "Narrow the query, e.g. by filtering or selecting fewer columns, so that its estimate is within the limit."
+		if b.spec.AllowOverride {
+			hint += fmt.Sprintf(" To run it anyway, invoke the tool again with `%s: true`.", ConfirmBudgetParameter)
+		}
+		return &HintError{Err: exceeded, Hint: hint}
+	}
+	return nil
+}
+
+// Enforce checks an invocation of the tool named name against b, estimating
+// it with estimate, unless the check is not Required. It does nothing if b
+// is nil.
+func (b *Budget) Enforce(ctx context.Context, name string, params ParamValues, estimate func() (BudgetEstimate, error)) error {
+	required, err := b.Required(ctx, name, params)
+	if err != nil || !required {
+		return err
+	}
+	e, err := estimate()
+	if err != nil {
+		return err
+	}
+	return b.Check(name, e)
+}
+
+// BudgetExceededError is returned when the estimate of a query is over the
+// budget of its tool.
+type BudgetExceededError struct {
+	Tool     string       `json:"tool"`
+	Metric   BudgetMetric `json:"metric"`
+	Estimate float64      `json:"estimate"`
+	Limit    float64      `json:"limit"`
+	// CanOverride is whether the query can be run anyway by confirming it.
+	CanOverride bool `json:"canOverride"`
+}
+
+func (e *BudgetExceededError) Error() string {
+	return fmt.Sprintf("query of %q is over budget: estimated %s is %s, above the limit of %s", e.Tool, e.Metric, formatBudgetValue(e.Estimate), formatBudgetValue(e.Limit))
+}
+
+// formatBudgetValue formats v without an exponent, so that large numbers of
+// bytes read naturally.
+func formatBudgetValue(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 2, 64)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
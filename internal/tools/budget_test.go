@@ -0,0 +1,260 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+func TestNewBudget(t *testing.T) {
+	tcs := []struct {
+		desc    string
+		spec    tools.BudgetSpec
+		metrics []tools.BudgetMetric
+		wantErr string
+	}{
+		{
+			desc:    "bytes",
+			spec:    tools.BudgetSpec{MaxBytesProcessed: 1000},
+			metrics: []tools.BudgetMetric{tools.BudgetBytesProcessed},
+		},
+		{
+			desc:    "rows and cost",
+			spec:    tools.BudgetSpec{MaxRows: 100, MaxCost: 5000.5},
+			metrics: []tools.BudgetMetric{tools.BudgetRows, tools.BudgetCost},
+		},
+		{
+			desc:    "no limit",
+			spec:    tools.BudgetSpec{AllowOverride: true},
+			metrics: []tools.BudgetMetric{tools.BudgetRows, tools.BudgetCost},
+			wantErr: `budget must set at least one of ["maxRows" "maxCost"]`,
+		},
+		{
+			desc:    "negative limit",
+			spec:    tools.BudgetSpec{MaxRows: -1},
+			metrics: []tools.BudgetMetric{tools.BudgetRows},
+			wantErr: "budget maxRows must not be negative, got -1",
+		},
+		{
+			desc:    "unsupported limit",
+			spec:    tools.BudgetSpec{MaxRows: 10},
+			metrics: []tools.BudgetMetric{tools.BudgetBytesProcessed},
+			wantErr: `budget maxRows is not supported by this tool, must be one of ["maxBytesProcessed"]`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			b, err := tools.NewBudget(&tc.spec, tc.metrics...)
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Fatalf("unexpected error: got %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.spec, b.Spec()); diff != "" {
+				t.Fatalf("incorrect spec: diff %v", diff)
+			}
+		})
+	}
+	if b, err := tools.NewBudget(nil, tools.BudgetRows); b != nil || err != nil {
+		t.Fatalf("unexpected budget for nil spec: %v, %v", b, err)
+	}
+}
+
+func TestBudgetParameters(t *testing.T) {
+	var nilBudget *tools.Budget
+	if params := nilBudget.Parameters(); params != nil {
+		t.Fatalf("unexpected parameters for nil budget: %v", params)
+	}
+	tcs := []struct {
+		desc string
+		spec tools.BudgetSpec
+		want []string
+	}{
+		{desc: "none", spec: tools.BudgetSpec{MaxRows: 10}},
+		{desc: "override", spec: tools.BudgetSpec{MaxRows: 10, AllowOverride: true}, want: []string{tools.ConfirmBudgetParameter}},
+		{
+			desc: "override and skip",
+			spec: tools.BudgetSpec{MaxRows: 10, AllowOverride: true, SkipAuthServices: []string{"admins"}},
+			want: []string{tools.ConfirmBudgetParameter, tools.SkipBudgetParameter},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			b, err := tools.NewBudget(&tc.spec, tools.BudgetRows)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			var got []string
+			for _, p := range b.Parameters() {
+				if p.GetType() != "boolean" || p.GetDefault() != false {
+					t.Fatalf("parameter %q is not a boolean defaulting to false", p.GetName())
+				}
+				got = append(got, p.GetName())
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect parameters: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestBudgetEnforce(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	spec := tools.BudgetSpec{MaxRows: 1000, MaxCost: 50, AllowOverride: true, SkipAuthServices: []string{"admins"}}
+	b, err := tools.NewBudget(&spec, tools.BudgetRows, tools.BudgetCost)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	strict, err := tools.NewBudget(&tools.BudgetSpec{MaxRows: 1000}, tools.BudgetRows, tools.BudgetCost)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	tcs := []struct {
+		desc     string
+		budget   *tools.Budget
+		params   tools.ParamValues
+		verified []string
+		estimate tools.BudgetEstimate
+		// wantEstimated is whether the invocation is estimated
+		wantEstimated bool
+		wantExceeded  *tools.BudgetExceededError
+		wantErr       string
+	}{
+		{
+			desc:          "under budget",
+			budget:        b,
+			estimate:      tools.BudgetEstimate{tools.BudgetRows: 1000, tools.BudgetCost: 12.5},
+			wantEstimated: true,
+		},
+		{
+			desc:          "over row budget",
+			budget:        b,
+			estimate:      tools.BudgetEstimate{tools.BudgetRows: 250000, tools.BudgetCost: 12.5},
+			wantEstimated: true,
+			wantExceeded:  &tools.BudgetExceededError{Tool: "my-tool", Metric: tools.BudgetRows, Estimate: 250000, Limit: 1000, CanOverride: true},
+			wantErr:       `query of "my-tool" is over budget: estimated rows is 250000, above the limit of 1000`,
+		},
+		{
+			desc:          "over cost budget",
+			budget:        b,
+			estimate:      tools.BudgetEstimate{tools.BudgetRows: 10, tools.BudgetCost: 50.25},
+			wantEstimated: true,
+			wantExceeded:  &tools.BudgetExceededError{Tool: "my-tool", Metric: tools.BudgetCost, Estimate: 50.25, Limit: 50, CanOverride: true},
+			wantErr:       `query of "my-tool" is over budget: estimated cost is 50.25, above the limit of 50`,
+		},
+		{
+			desc:     "confirmed override",
+			budget:   b,
+			params:   tools.ParamValues{{Name: tools.ConfirmBudgetParameter, Value: true}},
+			estimate: tools.BudgetEstimate{tools.BudgetRows: 250000},
+		},
+		{
+			desc:          "override not allowed",
+			budget:        strict,
+			params:        tools.ParamValues{{Name: tools.ConfirmBudgetParameter, Value: true}},
+			estimate:      tools.BudgetEstimate{tools.BudgetRows: 250000},
+			wantEstimated: true,
+			wantExceeded:  &tools.BudgetExceededError{Tool: "my-tool", Metric: tools.BudgetRows, Estimate: 250000, Limit: 1000},
+			wantErr:       `query of "my-tool" is over budget: estimated rows is 250000, above the limit of 1000`,
+		},
+		{
+			desc:     "skipped by trusted caller",
+			budget:   b,
+			params:   tools.ParamValues{{Name: tools.SkipBudgetParameter, Value: true}},
+			verified: []string{"admins"},
+			estimate: tools.BudgetEstimate{tools.BudgetRows: 250000},
+		},
+		{
+			desc:     "skipped by untrusted caller",
+			budget:   b,
+			params:   tools.ParamValues{{Name: tools.SkipBudgetParameter, Value: true}},
+			verified: []string{"users"},
+			estimate: tools.BudgetEstimate{tools.BudgetRows: 250000},
+			wantErr:  `skipping the budget of "my-tool" requires one of the authServices ["admins"]: unauthorized`,
+		},
+		{
+			desc:     "no budget",
+			estimate: tools.BudgetEstimate{tools.BudgetRows: 250000},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx := util.WithVerifiedAuthServices(ctx, tc.verified)
+			estimated := false
+			err := tc.budget.Enforce(ctx, "my-tool", tc.params, func() (tools.BudgetEstimate, error) {
+				estimated = true
+				return tc.estimate, nil
+			})
+			if estimated != tc.wantEstimated {
+				t.Fatalf("unexpected estimate: got %t, want %t", estimated, tc.wantEstimated)
+			}
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.wantErr {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.wantErr)
+			}
+			var exceeded *tools.BudgetExceededError
+			errors.As(err, &exceeded)
+			if diff := cmp.Diff(tc.wantExceeded, exceeded); diff != "" {
+				t.Fatalf("incorrect budget error: diff %v", diff)
+			}
+			if tc.wantExceeded == nil {
+				if !errors.Is(err, tools.ErrUnauthorized) {
+					t.Fatalf("expected an unauthorized error, got %v", err)
+				}
+				return
+			}
+			if hint := tools.ErrorHint(err); hint == "" {
+				t.Fatalf("expected a hint with the budget error")
+			}
+		})
+	}
+}
+
+func TestBudgetEnforceEstimateError(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	b, err := tools.NewBudget(&tools.BudgetSpec{MaxBytesProcessed: 1000}, tools.BudgetBytesProcessed)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := errors.New("dry run failed")
+	err = b.Enforce(ctx, "my-tool", nil, func() (tools.BudgetEstimate, error) {
+		return nil, want
+	})
+	if !errors.Is(err, want) {
+		t.Fatalf("unexpected error: got %v, want %v", err, want)
+	}
+}
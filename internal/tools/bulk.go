@@ -0,0 +1,72 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import "fmt"
+
+// BulkItemError is the error of a single item of a bulk operation.
+type BulkItemError struct {
+	// Index is the position of the item in the input of the operation.
+	Index   int    `json:"index"`
+	Message string `json:"message"`
+}
+
+// BulkResult is the result of a bulk operation that may succeed for some of
+// its items and fail for others. Results holds one entry per item, in input
+// order, which is nil for the items that failed.
+type BulkResult struct {
+	Succeeded int             `json:"succeeded"`
+	Failed    int             `json:"failed"`
+	Errors    []BulkItemError `json:"errors"`
+	Results   []any           `json:"results"`
+}
+
+// NewBulkResult returns an empty BulkResult for an operation on n items.
+func NewBulkResult(n int) *BulkResult {
+	return &BulkResult{Errors: []BulkItemError{}, Results: make([]any, 0, n)}
+}
+
+// Add records the outcome of the next item of the operation: its result if
+// err is nil, and err otherwise.
+func (r *BulkResult) Add(result any, err error) {
+	if err != nil {
+		r.Errors = append(r.Errors, BulkItemError{Index: len(r.Results), Message: err.Error()})
+		r.Failed++
+		r.Results = append(r.Results, nil)
+		return
+	}
+	r.Succeeded++
+	r.Results = append(r.Results, result)
+}
+
+// Err returns a *BulkFailedError if every item of the operation failed, and
+// nil otherwise.
+func (r *BulkResult) Err() error {
+	if r.Failed == 0 || r.Succeeded > 0 {
+		return nil
+	}
+	return &BulkFailedError{Result: r}
+}
+
+// BulkFailedError is returned by a bulk operation none of whose items
+// succeeded.
+type BulkFailedError struct {
+	Result *BulkResult
+}
+
+func (e *BulkFailedError) Error() string {
+	first := e.Result.Errors[0]
+	return fmt.Sprintf("all %d items failed, first at index %d: %s", e.Result.Failed, first.Index, first.Message)
+}
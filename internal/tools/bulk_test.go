@@ -0,0 +1,92 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestBulkResult(t *testing.T) {
+	errDup := errors.New("duplicate key")
+	type outcome struct {
+		result any
+		err    error
+	}
+	tcs := []struct {
+		desc     string
+		outcomes []outcome
+		want     *tools.BulkResult
+		wantErr  string
+	}{
+		{
+			desc:     "all succeeded",
+			outcomes: []outcome{{result: "a"}, {result: "b"}},
+			want:     &tools.BulkResult{Succeeded: 2, Errors: []tools.BulkItemError{}, Results: []any{"a", "b"}},
+		},
+		{
+			desc:     "mixed",
+			outcomes: []outcome{{result: "a"}, {err: errDup}, {result: "c"}},
+			want: &tools.BulkResult{
+				Succeeded: 2,
+				Failed:    1,
+				Errors:    []tools.BulkItemError{{Index: 1, Message: "duplicate key"}},
+				Results:   []any{"a", nil, "c"},
+			},
+		},
+		{
+			desc:     "none succeeded",
+			outcomes: []outcome{{err: errDup}, {err: errors.New("denied")}},
+			want: &tools.BulkResult{
+				Failed:  2,
+				Errors:  []tools.BulkItemError{{Index: 0, Message: "duplicate key"}, {Index: 1, Message: "denied"}},
+				Results: []any{nil, nil},
+			},
+			wantErr: "all 2 items failed, first at index 0: duplicate key",
+		},
+		{
+			desc: "no items",
+			want: &tools.BulkResult{Errors: []tools.BulkItemError{}, Results: []any{}},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := tools.NewBulkResult(len(tc.outcomes))
+			for _, o := range tc.outcomes {
+				got.Add(o.result, o.err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect result: diff %v", diff)
+			}
+			err := got.Err()
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			var bulkErr *tools.BulkFailedError
+			if !errors.As(err, &bulkErr) || bulkErr.Result != got {
+				t.Fatalf("expected a *BulkFailedError holding the result, got %v", err)
+			}
+			if err.Error() != tc.wantErr {
+				t.Fatalf("unexpected error: got %q, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import "github.com/googleapis/genai-toolbox/internal/sources"
+
+// ArrayParamsExpander is implemented by tools that expand array parameters
+// into one placeholder per element, so that they accept array parameters
+// even if their source cannot bind arrays.
+type ArrayParamsExpander interface {
+	ExpandsArrayParams() bool
+}
+
+// CapabilitiesTool exposes the capabilities of the source of a Tool in its
+// manifests.
+type CapabilitiesTool struct {
+	Tool
+	Capabilities sources.Capabilities
+}
+
+func (t CapabilitiesTool) unwrap() Tool {
+	return t.Tool
+}
+
+func (t CapabilitiesTool) Manifest() Manifest {
+	m := t.Tool.Manifest()
+	caps := t.Capabilities
+	m.Capabilities = &caps
+	return m
+}
+
+func (t CapabilitiesTool) McpManifest() McpManifest {
+	m := t.Tool.McpManifest()
+	meta := make(map[string]any, len(m.Metadata)+1)
+	for k, v := range m.Metadata {
+		meta[k] = v
+	}
+	meta["toolbox/capabilities"] = t.Capabilities
+	m.Metadata = meta
+	return m
+}
@@ -128,8 +128,8 @@ func (t Tool) Authorized(verifiedAuthServices []string) bool {
 }
 
 // Invoke implements tools.Tool.
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	paramsMap := params.AsMap()
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
 	newStatement, err := tools.ResolveTemplateParams(t.TemplateParameters, t.Statement, paramsMap)
 	if err != nil {
 		return nil, fmt.Errorf("unable to extract template params %w", err)
@@ -171,8 +171,14 @@ func (t Tool) McpManifest() tools.McpManifest {
 }
 
 // ParseParams implements tools.Tool.
+// Render resolves the statement of an invocation without running it.
+func (t Tool) Render(params tools.ParamValues) (tools.RenderedStatement, error) {
+	return tools.RenderStatement(t.Statement, t.TemplateParameters, t.Parameters, params)
+}
+
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
 	return tools.ParseParams(t.AllParams, data, claims)
 }
 
 var _ tools.Tool = Tool{}
+var _ tools.Renderer = Tool{}
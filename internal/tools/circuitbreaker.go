@@ -0,0 +1,397 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+)
+
+// ErrBackendUnavailable is wrapped by the errors returned when an invocation
+// is rejected by an open circuit breaker.
+var ErrBackendUnavailable = errors.New("backend temporarily unavailable")
+
+const (
+	// DefaultBreakerFailureThreshold is the default number of failures within
+	// the window that opens a circuit breaker.
+	DefaultBreakerFailureThreshold = 5
+	// DefaultBreakerWindow is the default rolling window failures are counted
+	// over.
+	DefaultBreakerWindow = time.Minute
+	// DefaultBreakerOpenDuration is the default time a circuit breaker stays
+	// open before letting probes through.
+	DefaultBreakerOpenDuration = 30 * time.Second
+	// DefaultBreakerHalfOpenProbes is the default number of probes that must
+	// succeed to close a half-open circuit breaker.
+	DefaultBreakerHalfOpenProbes = 1
+)
+
+const (
+	// BreakerScopeTool is the scope of a circuit breaker declared by a tool.
+	BreakerScopeTool = "tool"
+	// BreakerScopeSource is the scope of a circuit breaker declared by a
+	// source, shared by the tools of the source.
+	BreakerScopeSource = "source"
+)
+
+// BreakerState is the state of a circuit breaker.
+type BreakerState string
+
+const (
+	// BreakerClosed lets every invocation through, counting their failures.
+	BreakerClosed BreakerState = "closed"
+	// BreakerOpen rejects every invocation until its open duration elapses.
+	BreakerOpen BreakerState = "open"
+	// BreakerHalfOpen lets a limited number of probes through, rejecting the
+	// other invocations until the probes succeed or one of them fails.
+	BreakerHalfOpen BreakerState = "halfOpen"
+)
+
+// CircuitBreakerSpec is the kind-agnostic `circuitBreaker` block of a tool or
+// a source. Unset fields take their default.
+type CircuitBreakerSpec struct {
+	// FailureThreshold is the number of failures within Window that opens
+	// the breaker.
+	FailureThreshold int `yaml:"failureThreshold" json:"failureThreshold"`
+	// Window is the rolling window failures are counted over, such as "1m".
+	Window string `yaml:"window" json:"window"`
+	// OpenDuration is how long the breaker rejects invocations once open,
+	// such as "30s".
+	OpenDuration string `yaml:"openDuration" json:"openDuration"`
+	// HalfOpenProbes is the number of invocations let through once the open
+	// duration elapses, all of which must succeed to close the breaker.
+	HalfOpenProbes int `yaml:"halfOpenProbes" json:"halfOpenProbes"`
+}
+
+// CircuitBreaker stops invoking a backend after repeated failures, so that a
+// failing backend is given time to recover and callers fail fast meanwhile.
+// It is safe for concurrent use.
+type CircuitBreaker struct {
+	scope        string
+	name         string
+	threshold    int
+	window       time.Duration
+	openDuration time.Duration
+	probes       int
+	// now returns the current time. It defaults to time.Now.
+	now func() time.Time
+
+	mu    sync.Mutex
+	state BreakerState
+	// failures are the times of the failures within the window, oldest first.
+	failures  []time.Time
+	openUntil time.Time
+	// generation is incremented on every state change, so that the outcome
+	// of an invocation let through in a previous state is ignored.
+	generation uint64
+	// inFlight and succeeded count the probes of the half-open state.
+	inFlight  int
+	succeeded int
+	trips     int
+}
+
+// NewCircuitBreaker validates spec and returns the closed CircuitBreaker it
+// describes, for the tool or the source of the scope named name.
+func NewCircuitBreaker(scope, name string, spec CircuitBreakerSpec) (*CircuitBreaker, error) {
+	return NewCircuitBreakerWithClock(scope, name, spec, time.Now)
+}
+
+// NewCircuitBreakerWithClock returns a CircuitBreaker that reads the time
+// from now, for tests.
+func NewCircuitBreakerWithClock(scope, name string, spec CircuitBreakerSpec, now func() time.Time) (*CircuitBreaker, error) {
+	b := &CircuitBreaker{
+		scope:        scope,
+		name:         name,
+		threshold:    DefaultBreakerFailureThreshold,
+		window:       DefaultBreakerWindow,
+		openDuration: DefaultBreakerOpenDuration,
+		probes:       DefaultBreakerHalfOpenProbes,
+		now:          now,
+		state:        BreakerClosed,
+	}
+	if spec.FailureThreshold < 0 {
+		return nil, fmt.Errorf("failureThreshold must not be negative")
+	}
+	if spec.FailureThreshold > 0 {
+		b.threshold = spec.FailureThreshold
+	}
+	if spec.HalfOpenProbes < 0 {
+		return nil, fmt.Errorf("halfOpenProbes must not be negative")
+	}
+	if spec.HalfOpenProbes > 0 {
+		b.probes = spec.HalfOpenProbes
+	}
+	var err error
+	if b.window, err = parseBreakerDuration("window", spec.Window, b.window); err != nil {
+		return nil, err
+	}
+	if b.openDuration, err = parseBreakerDuration("openDuration", spec.OpenDuration, b.openDuration); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func parseBreakerDuration(field, s string, def time.Duration) (time.Duration, error) {
+	if s == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", field, s, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("%s must be positive, got %q", field, s)
+	}
+	return d, nil
+}
+
+// breakerCall is an invocation let through by a CircuitBreaker, whose
+// outcome is reported with done.
+type breakerCall struct {
+	generation uint64
+	probe      bool
+}
+
+// allow lets an invocation through, or returns a *CircuitOpenError if the
+// breaker is open or its probes are all in flight.
+func (b *CircuitBreaker) allow() (breakerCall, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	if b.state == BreakerOpen {
+		if now.Before(b.openUntil) {
+			return breakerCall{}, b.openError(b.openUntil, now)
+		}
+		b.transition(BreakerHalfOpen)
+	}
+	if b.state == BreakerHalfOpen {
+		if b.inFlight+b.succeeded >= b.probes {
+			// the outcome of the probes is awaited, so retrying in a second
+			// is as good a guess as any
+			return breakerCall{}, b.openError(now.Add(time.Second), now)
+		}
+		b.inFlight++
+		return breakerCall{generation: b.generation, probe: true}, nil
+	}
+	return breakerCall{generation: b.generation}, nil
+}
+
+// done records the outcome of an invocation let through by allow.
+func (b *CircuitBreaker) done(call breakerCall, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if call.generation != b.generation {
+		return
+	}
+	now := b.now()
+	if call.probe {
+		b.inFlight--
+		if failed {
+			b.trip(now)
+			return
+		}
+		b.succeeded++
+		if b.succeeded >= b.probes {
+			b.transition(BreakerClosed)
+		}
+		return
+	}
+	if !failed {
+		return
+	}
+	b.failures = append(b.pruned(now), now)
+	if len(b.failures) >= b.threshold {
+		b.trip(now)
+	}
+}
+
+// trip opens the breaker for its open duration.
+func (b *CircuitBreaker) trip(now time.Time) {
+	b.openUntil = now.Add(b.openDuration)
+	b.trips++
+	b.transition(BreakerOpen)
+}
+
+// transition moves the breaker to state, resetting the counts of the
+// previous one.
+func (b *CircuitBreaker) transition(state BreakerState) {
+	b.state = state
+	b.generation++
+	b.failures = nil
+	b.inFlight = 0
+	b.succeeded = 0
+}
+
+// pruned returns the failures still within the window at now.
+func (b *CircuitBreaker) pruned(now time.Time) []time.Time {
+	start := now.Add(-b.window)
+	i := 0
+	for i < len(b.failures) && !b.failures[i].After(start) {
+		i++
+	}
+	return b.failures[i:]
+}
+
+func (b *CircuitBreaker) openError(until, now time.Time) *CircuitOpenError {
+	return &CircuitOpenError{Scope: b.scope, Name: b.name, Until: until, at: now}
+}
+
+// CircuitBreakerStatus is a snapshot of the state of a CircuitBreaker.
+type CircuitBreakerStatus struct {
+	// Scope is BreakerScopeTool or BreakerScopeSource.
+	Scope string `json:"scope"`
+	// Name is the name of the tool or of the source declaring the breaker.
+	Name  string       `json:"name"`
+	State BreakerState `json:"state"`
+	// RecentFailures is the number of failures within the window of a closed
+	// breaker.
+	RecentFailures int `json:"recentFailures"`
+	// OpenUntil is when an open breaker lets probes through.
+	OpenUntil *time.Time `json:"openUntil,omitempty"`
+	// Trips is the number of times the breaker opened since startup.
+	Trips int `json:"trips"`
+}
+
+// Status returns the current state of the breaker. An open breaker whose
+// open duration elapsed is reported half-open, as its next invocation is a
+// probe.
+func (b *CircuitBreaker) Status() CircuitBreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	status := CircuitBreakerStatus{Scope: b.scope, Name: b.name, State: b.state, Trips: b.trips}
+	switch b.state {
+	case BreakerClosed:
+		status.RecentFailures = len(b.pruned(now))
+	case BreakerOpen:
+		if !now.Before(b.openUntil) {
+			status.State = BreakerHalfOpen
+			break
+		}
+		until := b.openUntil
+		status.OpenUntil = &until
+	}
+	return status
+}
+
+// CircuitOpenError is returned when an invocation is rejected by an open
+// circuit breaker.
+type CircuitOpenError struct {
+	Scope string `json:"scope"`
+	Name  string `json:"name"`
+	// Until is when the breaker lets invocations through again.
+	Until time.Time `json:"retryAt"`
+	// at is when the invocation was rejected.
+	at time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%s, retry after %ds: the circuit breaker of %s %q is open after repeated failures", ErrBackendUnavailable, int(e.RetryAfter().Seconds()), e.Scope, e.Name)
+}
+
+func (e *CircuitOpenError) Unwrap() error {
+	return ErrBackendUnavailable
+}
+
+// RetryAfter is how long to wait before retrying, rounded up to the second
+// and at least a second.
+func (e *CircuitOpenError) RetryAfter() time.Duration {
+	at := e.at
+	if at.IsZero() {
+		at = time.Now()
+	}
+	seconds := math.Ceil(e.Until.Sub(at).Seconds())
+	return time.Duration(max(1, seconds)) * time.Second
+}
+
+// isBreakerFailure reports whether err, returned by an invocation, is a
+// failure of the backend. Invocations rejected before reaching the backend,
+// or canceled by their caller, are not.
+func isBreakerFailure(ctx context.Context, err error) bool {
+	var budgetErr *BudgetExceededError
+	var disabledErr *KindDisabledError
+	var deniedErr *PolicyDeniedError
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, context.Canceled) && ctx.Err() != nil:
+		return false
+	case errors.Is(err, ErrUnauthorized), errors.Is(err, ErrToolUnavailable), errors.Is(err, ErrToolBusy), errors.Is(err, ErrBackendUnavailable):
+		return false
+	case errors.As(err, &budgetErr), errors.As(err, &disabledErr), errors.As(err, &deniedErr):
+		return false
+	}
+	return true
+}
+
+// CircuitBreakerConfig wraps a ToolConfig with the kind-agnostic
+// `circuitBreaker` field, or with the breaker of its source.
+type CircuitBreakerConfig struct {
+	ToolConfig
+	Breaker *CircuitBreaker
+}
+
+// validate interface
+var _ ToolConfig = CircuitBreakerConfig{}
+
+func (cfg CircuitBreakerConfig) Initialize(srcs map[string]sources.Source) (Tool, error) {
+	t, err := cfg.ToolConfig.Initialize(srcs)
+	if err != nil {
+		return nil, err
+	}
+	return CircuitBreakerTool{Tool: t, Breaker: cfg.Breaker}, nil
+}
+
+// CircuitBreakerTool fails fast while its CircuitBreaker is open, and reports
+// the outcome of the other invocations to it.
+type CircuitBreakerTool struct {
+	Tool
+	Breaker *CircuitBreaker
+}
+
+func (t CircuitBreakerTool) unwrap() Tool {
+	return t.Tool
+}
+
+func (t CircuitBreakerTool) Invoke(ctx context.Context, inv Invocation) (any, error) {
+	call, err := t.Breaker.allow()
+	if err != nil {
+		return nil, err
+	}
+	// a panicking invocation is a failure, and must not leave a probe in
+	// flight forever
+	failed, streamed := true, false
+	defer func() {
+		if !streamed {
+			t.Breaker.done(call, failed)
+		}
+	}()
+	res, err := t.Tool.Invoke(ctx, inv)
+	failed = isBreakerFailure(ctx, err)
+	if err != nil {
+		return res, err
+	}
+	// the outcome of a streamed result is only known once its rows are read
+	res, streamed = OnRowsClosed(res, func(err error) {
+		t.Breaker.done(call, isBreakerFailure(ctx, err))
+	})
+	return res, nil
+}
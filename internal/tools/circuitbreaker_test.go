@@ -0,0 +1,291 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// backendTool fails its invocations with err, calling during first if set.
+type backendTool struct {
+	mockTool
+	err     *error
+	calls   *int
+	during  *func()
+	results string
+}
+
+func (t backendTool) Invoke(context.Context, tools.Invocation) (any, error) {
+	*t.calls++
+	if *t.during != nil {
+		during := *t.during
+		*t.during = nil
+		during()
+	}
+	if *t.err != nil {
+		return nil, *t.err
+	}
+	return t.results, nil
+}
+
+// breakerFixture is a CircuitBreakerTool over a backendTool, with a fake
+// clock.
+type breakerFixture struct {
+	tool    tools.CircuitBreakerTool
+	breaker *tools.CircuitBreaker
+	now     time.Time
+	err     error
+	calls   int
+	during  func()
+}
+
+func newBreakerFixture(t *testing.T, spec tools.CircuitBreakerSpec) *breakerFixture {
+	t.Helper()
+	f := &breakerFixture{now: mustParseTime(t, "2025-01-06T12:00:00Z")}
+	b, err := tools.NewCircuitBreakerWithClock(tools.BreakerScopeTool, "flaky", spec, func() time.Time { return f.now })
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	inner := backendTool{mockTool: mockTool{name: "flaky"}, err: &f.err, calls: &f.calls, during: &f.during, results: "done"}
+	f.breaker = b
+	f.tool = tools.CircuitBreakerTool{Tool: inner, Breaker: b}
+	return f
+}
+
+func (f *breakerFixture) advance(d time.Duration) {
+	f.now = f.now.Add(d)
+}
+
+// fail invokes the tool with a failing backend.
+func (f *breakerFixture) fail(t *testing.T) {
+	t.Helper()
+	f.err = errors.New("connection refused")
+	if _, err := f.tool.Invoke(context.Background(), tools.Invocation{}); err == nil || errors.Is(err, tools.ErrBackendUnavailable) {
+		t.Fatalf("expected the backend to fail, got %v", err)
+	}
+}
+
+// succeed invokes the tool with a healthy backend.
+func (f *breakerFixture) succeed(t *testing.T) {
+	t.Helper()
+	f.err = nil
+	if _, err := f.tool.Invoke(context.Background(), tools.Invocation{}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+// rejected invokes the tool and checks that it fails fast, asking to retry
+// after retryAfter.
+func (f *breakerFixture) rejected(t *testing.T, retryAfter time.Duration) {
+	t.Helper()
+	calls := f.calls
+	_, err := f.tool.Invoke(context.Background(), tools.Invocation{})
+	var openErr *tools.CircuitOpenError
+	if !errors.As(err, &openErr) {
+		t.Fatalf("expected a CircuitOpenError, got %v", err)
+	}
+	if !errors.Is(err, tools.ErrBackendUnavailable) {
+		t.Fatalf("expected error to wrap ErrBackendUnavailable")
+	}
+	if got := openErr.RetryAfter(); got != retryAfter {
+		t.Fatalf("unexpected retry after: got %s, want %s", got, retryAfter)
+	}
+	if f.calls != calls {
+		t.Fatalf("expected the backend not to be invoked")
+	}
+}
+
+func (f *breakerFixture) status(t *testing.T, want tools.CircuitBreakerStatus) {
+	t.Helper()
+	want.Scope, want.Name = tools.BreakerScopeTool, "flaky"
+	if diff := cmp.Diff(want, f.breaker.Status()); diff != "" {
+		t.Fatalf("unexpected status (-want +got):\n%s", diff)
+	}
+}
+
+func timePtr(v time.Time) *time.Time {
+	return &v
+}
+
+func TestCircuitBreakerTrip(t *testing.T) {
+	f := newBreakerFixture(t, tools.CircuitBreakerSpec{FailureThreshold: 3, Window: "1m", OpenDuration: "30s"})
+	start := f.now
+
+	f.fail(t)
+	f.advance(10 * time.Second)
+	f.fail(t)
+	f.succeed(t)
+	f.status(t, tools.CircuitBreakerStatus{State: tools.BreakerClosed, RecentFailures: 2})
+
+	// the first failure leaves the window
+	f.advance(55 * time.Second)
+	f.fail(t)
+	f.status(t, tools.CircuitBreakerStatus{State: tools.BreakerClosed, RecentFailures: 2})
+
+	f.advance(4 * time.Second)
+	f.fail(t)
+	openUntil := start.Add(99 * time.Second)
+	f.status(t, tools.CircuitBreakerStatus{State: tools.BreakerOpen, OpenUntil: timePtr(openUntil), Trips: 1})
+
+	f.rejected(t, 30*time.Second)
+	f.advance(4500 * time.Millisecond)
+	f.rejected(t, 26*time.Second)
+
+	_, err := f.tool.Invoke(context.Background(), tools.Invocation{})
+	want := `backend temporarily unavailable, retry after 26s: the circuit breaker of tool "flaky" is open after repeated failures`
+	if err == nil || err.Error() != want {
+		t.Fatalf("unexpected error: got %v, want %q", err, want)
+	}
+}
+
+func TestCircuitBreakerStreamedRows(t *testing.T) {
+	b, err := tools.NewCircuitBreaker(tools.BreakerScopeTool, "export", tools.CircuitBreakerSpec{FailureThreshold: 2, Window: "1m", OpenDuration: "30s"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	inner := streamingTool{mockTool: mockTool{name: "export"}, rows: []any{1, 2}, err: errors.New("connection reset by peer")}
+	tool := tools.CircuitBreakerTool{Tool: inner, Breaker: b}
+	for i := 0; i < 2; i++ {
+		res, err := tool.Invoke(context.Background(), tools.Invocation{})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		// the failure is only known once the rows are read
+		if got := b.Status().State; got != tools.BreakerClosed {
+			t.Fatalf("unexpected state before the rows were read: %s", got)
+		}
+		if _, err := tools.CollectRows(res.(tools.RowIterator)); err == nil {
+			t.Fatalf("expected the rows to fail")
+		}
+	}
+	if got := b.Status().State; got != tools.BreakerOpen {
+		t.Fatalf("expected the breaker to open after the failing rows, got %s", got)
+	}
+}
+
+func TestCircuitBreakerIgnoredErrors(t *testing.T) {
+	f := newBreakerFixture(t, tools.CircuitBreakerSpec{FailureThreshold: 1})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	errs := []error{
+		fmt.Errorf("%w: missing credentials", tools.ErrUnauthorized),
+		fmt.Errorf("%w: queue is full", tools.ErrToolBusy),
+		&tools.BudgetExceededError{Tool: "flaky", Metric: tools.BudgetBytesProcessed},
+		context.Canceled,
+	}
+	for _, e := range errs {
+		f.err = e
+		if _, err := f.tool.Invoke(ctx, tools.Invocation{}); !errors.Is(err, e) {
+			t.Fatalf("unexpected error: got %v, want %v", err, e)
+		}
+	}
+	f.status(t, tools.CircuitBreakerStatus{State: tools.BreakerClosed})
+
+	// the deadline of the invocation is a failure of the backend
+	f.err = context.DeadlineExceeded
+	if _, err := f.tool.Invoke(context.Background(), tools.Invocation{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	f.status(t, tools.CircuitBreakerStatus{State: tools.BreakerOpen, OpenUntil: timePtr(f.now.Add(tools.DefaultBreakerOpenDuration)), Trips: 1})
+}
+
+func TestCircuitBreakerHalfOpenProbeSuccess(t *testing.T) {
+	f := newBreakerFixture(t, tools.CircuitBreakerSpec{FailureThreshold: 1, OpenDuration: "30s"})
+	f.fail(t)
+	f.advance(30 * time.Second)
+	f.status(t, tools.CircuitBreakerStatus{State: tools.BreakerHalfOpen, Trips: 1})
+
+	// invocations made while the probe is in flight fail fast
+	f.during = func() { f.rejected(t, time.Second) }
+	f.succeed(t)
+	if f.during != nil {
+		t.Fatalf("expected the probe to reach the backend")
+	}
+	f.status(t, tools.CircuitBreakerStatus{State: tools.BreakerClosed, Trips: 1})
+	f.succeed(t)
+}
+
+func TestCircuitBreakerHalfOpenProbeFailure(t *testing.T) {
+	f := newBreakerFixture(t, tools.CircuitBreakerSpec{FailureThreshold: 1, OpenDuration: "30s"})
+	f.fail(t)
+	f.advance(45 * time.Second)
+	f.fail(t)
+	f.status(t, tools.CircuitBreakerStatus{State: tools.BreakerOpen, OpenUntil: timePtr(f.now.Add(30 * time.Second)), Trips: 2})
+	f.rejected(t, 30*time.Second)
+
+	f.advance(30 * time.Second)
+	f.succeed(t)
+	f.status(t, tools.CircuitBreakerStatus{State: tools.BreakerClosed, Trips: 2})
+}
+
+func TestCircuitBreakerHalfOpenProbes(t *testing.T) {
+	f := newBreakerFixture(t, tools.CircuitBreakerSpec{FailureThreshold: 1, OpenDuration: "30s", HalfOpenProbes: 2})
+	f.fail(t)
+	f.advance(30 * time.Second)
+
+	f.succeed(t)
+	f.status(t, tools.CircuitBreakerStatus{State: tools.BreakerHalfOpen, Trips: 1})
+	f.succeed(t)
+	f.status(t, tools.CircuitBreakerStatus{State: tools.BreakerClosed, Trips: 1})
+
+	// a failing probe opens the breaker again, even after another succeeded
+	f.fail(t)
+	f.advance(30 * time.Second)
+	f.succeed(t)
+	f.fail(t)
+	f.status(t, tools.CircuitBreakerStatus{State: tools.BreakerOpen, OpenUntil: timePtr(f.now.Add(30 * time.Second)), Trips: 3})
+}
+
+func TestCircuitBreakerStaleOutcome(t *testing.T) {
+	f := newBreakerFixture(t, tools.CircuitBreakerSpec{FailureThreshold: 1, OpenDuration: "30s"})
+	// the breaker trips while an invocation is in flight, whose failure is
+	// then not counted against the open breaker
+	f.during = func() {
+		f.err = errors.New("connection refused")
+		_, _ = f.tool.Invoke(context.Background(), tools.Invocation{})
+		f.err = errors.New("timeout")
+	}
+	f.fail(t)
+	f.status(t, tools.CircuitBreakerStatus{State: tools.BreakerOpen, OpenUntil: timePtr(f.now.Add(30 * time.Second)), Trips: 1})
+}
+
+func TestNewCircuitBreakerErrors(t *testing.T) {
+	tcs := []struct {
+		desc string
+		spec tools.CircuitBreakerSpec
+		err  string
+	}{
+		{desc: "negative threshold", spec: tools.CircuitBreakerSpec{FailureThreshold: -1}, err: "failureThreshold must not be negative"},
+		{desc: "negative probes", spec: tools.CircuitBreakerSpec{HalfOpenProbes: -1}, err: "halfOpenProbes must not be negative"},
+		{desc: "invalid window", spec: tools.CircuitBreakerSpec{Window: "a minute"}, err: `invalid window "a minute"`},
+		{desc: "zero open duration", spec: tools.CircuitBreakerSpec{OpenDuration: "0s"}, err: `openDuration must be positive, got "0s"`},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := tools.NewCircuitBreaker(tools.BreakerScopeTool, "flaky", tc.spec)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("unexpected error: got %v, want substring %q", err, tc.err)
+			}
+		})
+	}
+}
@@ -101,8 +101,8 @@ type ExecuteSQLTool struct {
 	mcpManifest tools.McpManifest
 }
 
-func (t ExecuteSQLTool) Invoke(ctx context.Context, params tools.ParamValues, token tools.AccessToken) (any, error) {
-	paramsMap := params.AsMap()
+func (t ExecuteSQLTool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
 	sql, ok := paramsMap["sql"].(string)
 	if !ok {
 		return nil, fmt.Errorf("unable to cast sql parameter %s", paramsMap["sql"])
@@ -102,7 +102,7 @@ type Tool struct {
 	mcpManifest tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, token tools.AccessToken) (any, error) {
+func (t Tool) Invoke(ctx context.Context, _ tools.Invocation) (any, error) {
 	// Query to list all databases
 	query := "SHOW DATABASES"
 
@@ -93,6 +93,7 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 }
 
 var _ tools.Tool = Tool{}
+var _ tools.Renderer = Tool{}
 
 type Tool struct {
 	Name               string           `yaml:"name"`
@@ -108,8 +109,8 @@ type Tool struct {
 	mcpManifest tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, token tools.AccessToken) (any, error) {
-	paramsMap := params.AsMap()
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
 	newStatement, err := tools.ResolveTemplateParams(t.TemplateParameters, t.Statement, paramsMap)
 	if err != nil {
 		return nil, fmt.Errorf("unable to extract template params: %w", err)
@@ -181,6 +182,11 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, token tools.
 	return out, nil
 }
 
+// Render resolves the statement of an invocation without running it.
+func (t Tool) Render(params tools.ParamValues) (tools.RenderedStatement, error) {
+	return tools.RenderStatement(t.Statement, t.TemplateParameters, t.Parameters, params)
+}
+
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
 	return tools.ParseParams(t.AllParams, data, claims)
 }
@@ -107,8 +107,8 @@ type Tool struct {
 	mcpManifest tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	paramsMap := params.AsMap()
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
 	projectID, ok := paramsMap["projectId"].(string)
 	if !ok {
 		return nil, fmt.Errorf("projectId parameter not found or not a string")
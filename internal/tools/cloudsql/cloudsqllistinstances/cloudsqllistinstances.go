@@ -90,6 +90,9 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	}, nil
 }
 
+// validate interface
+var _ tools.Tool = Tool{}
+
 // Tool represents the list-instance tool.
 type Tool struct {
 	Name         string   `yaml:"name"`
@@ -104,15 +107,15 @@ type Tool struct {
 }
 
 // Invoke executes the tool's logic.
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	paramsMap := params.AsMap()
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
 
 	project, ok := paramsMap["project"].(string)
 	if !ok {
 		return nil, fmt.Errorf("missing 'project' parameter")
 	}
 
-	service, err := t.source.GetService(ctx, string(accessToken))
+	service, err := t.source.GetService(ctx, string(inv.AccessToken))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,267 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const typeTimestamp = "timestamp"
+
+// columnTypeNames are the types that a column can be coerced to.
+var columnTypeNames = []string{typeInt, typeFloat, typeBool, typeTimestamp, typeJSON, typeString}
+
+// timestampLayouts are the layouts tried, in order, when coercing a string to
+// a timestamp.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	time.DateOnly,
+}
+
+// ColumnTypes is the `columnTypes` field of SQL tools. It maps column names
+// to the type their values are coerced to after being read from the driver,
+// for drivers that report ambiguous types such as TEXT for numeric columns.
+type ColumnTypes map[string]string
+
+func (c *ColumnTypes) UnmarshalYAML(ctx context.Context, unmarshal func(interface{}) error) error {
+	var m map[string]string
+	if err := unmarshal(&m); err != nil {
+		return fmt.Errorf("columnTypes invalid: must be a map of column names to types: %w", err)
+	}
+	for name, typ := range m {
+		if !slices.Contains(columnTypeNames, typ) {
+			return fmt.Errorf("columnTypes invalid: type %q of column %q must be one of %q", typ, name, columnTypeNames)
+		}
+	}
+	*c = m
+	return nil
+}
+
+// CoerceRow converts the values of row in place to the types of their
+// columns. NULL values and columns without a type are left as is. A value
+// that cannot be converted is an error, or is replaced by NULL if lenient is
+// true.
+func (c ColumnTypes) CoerceRow(row map[string]any, lenient bool) error {
+	for name, typ := range c {
+		v, ok := row[name]
+		if !ok || v == nil {
+			continue
+		}
+		coerced, err := CoerceValue(v, typ)
+		if err != nil {
+			if lenient {
+				row[name] = nil
+				continue
+			}
+			return fmt.Errorf("unable to coerce column %q: %w", name, err)
+		}
+		row[name] = coerced
+	}
+	return nil
+}
+
+// CoerceValue converts a non-NULL value returned by a driver to typ, one of
+// integer, float, boolean, timestamp, json or string.
+func CoerceValue(v any, typ string) (any, error) {
+	// values such as pgtype.Numeric are converted to their driver value first
+	if valuer, ok := v.(driver.Valuer); ok {
+		dv, err := valuer.Value()
+		if err != nil {
+			return nil, err
+		}
+		if dv == nil {
+			return nil, nil
+		}
+		v = dv
+	}
+	if b, ok := v.([]byte); ok {
+		v = string(b)
+	}
+
+	var rtn any
+	var ok bool
+	switch typ {
+	case typeInt:
+		rtn, ok = coerceInt(v)
+	case typeFloat:
+		rtn, ok = coerceFloat(v)
+	case typeBool:
+		rtn, ok = coerceBool(v)
+	case typeTimestamp:
+		rtn, ok = coerceTimestamp(v)
+	case typeJSON:
+		rtn, ok = coerceJSON(v)
+	case typeString:
+		rtn, ok = coerceString(v)
+	default:
+		return nil, fmt.Errorf("unknown column type %q", typ)
+	}
+	if !ok {
+		if s, isString := v.(string); isString {
+			return nil, fmt.Errorf("cannot convert %q to %s", s, typ)
+		}
+		return nil, fmt.Errorf("cannot convert %v (%T) to %s", v, v, typ)
+	}
+	return rtn, nil
+}
+
+func coerceInt(v any) (any, bool) {
+	switch x := v.(type) {
+	case int:
+		return int64(x), true
+	case int8:
+		return int64(x), true
+	case int16:
+		return int64(x), true
+	case int32:
+		return int64(x), true
+	case int64:
+		return x, true
+	case uint:
+		return int64(x), uint64(x) <= math.MaxInt64
+	case uint8:
+		return int64(x), true
+	case uint16:
+		return int64(x), true
+	case uint32:
+		return int64(x), true
+	case uint64:
+		return int64(x), x <= math.MaxInt64
+	case float32:
+		return floatToInt(float64(x))
+	case float64:
+		return floatToInt(x)
+	case string:
+		s := strings.TrimSpace(x)
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i, true
+		}
+		// e.g. "3.0", which some drivers return for integer columns
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return floatToInt(f)
+		}
+	}
+	return nil, false
+}
+
+// floatToInt converts f to an int64 if it has no fractional part and is in
+// range.
+func floatToInt(f float64) (any, bool) {
+	if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return nil, false
+	}
+	return int64(f), true
+}
+
+func coerceFloat(v any) (any, bool) {
+	switch x := v.(type) {
+	case float32:
+		return float64(x), true
+	case float64:
+		return x, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(x), 64)
+		if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+			// JSON cannot represent infinities or NaN
+			return nil, false
+		}
+		return f, true
+	}
+	if i, ok := coerceInt(v); ok {
+		return float64(i.(int64)), true
+	}
+	return nil, false
+}
+
+func coerceBool(v any) (any, bool) {
+	switch x := v.(type) {
+	case bool:
+		return x, true
+	case string:
+		b, err := strconv.ParseBool(strings.TrimSpace(x))
+		return b, err == nil
+	}
+	if i, ok := coerceInt(v); ok {
+		switch i.(int64) {
+		case 0:
+			return false, true
+		case 1:
+			return true, true
+		}
+	}
+	return nil, false
+}
+
+func coerceTimestamp(v any) (any, bool) {
+	switch x := v.(type) {
+	case time.Time:
+		return x, true
+	case string:
+		s := strings.TrimSpace(x)
+		for _, layout := range timestampLayouts {
+			if ts, err := time.Parse(layout, s); err == nil {
+				return ts, true
+			}
+		}
+	}
+	return nil, false
+}
+
+func coerceJSON(v any) (any, bool) {
+	s, ok := v.(string)
+	if !ok {
+		// values that are not text were already decoded by the driver
+		return v, true
+	}
+	var rtn any
+	if err := json.Unmarshal([]byte(s), &rtn); err != nil {
+		return nil, false
+	}
+	return rtn, true
+}
+
+func coerceString(v any) (any, bool) {
+	switch x := v.(type) {
+	case string:
+		return x, true
+	case bool:
+		return strconv.FormatBool(x), true
+	case time.Time:
+		return x.Format(time.RFC3339Nano), true
+	case float32:
+		return strconv.FormatFloat(float64(x), 'f', -1, 32), true
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64), true
+	}
+	if i, ok := coerceInt(v); ok {
+		return strconv.FormatInt(i.(int64), 10), true
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, false
+	}
+	return string(b), true
+}
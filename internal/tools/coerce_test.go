@@ -0,0 +1,187 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestCoerceValue(t *testing.T) {
+	ts := time.Date(2024, time.March, 10, 8, 30, 0, 0, time.UTC)
+	tcs := []struct {
+		desc string
+		typ  string
+		in   any
+		want any
+	}{
+		{desc: "integer from text", typ: "integer", in: " 42 ", want: int64(42)},
+		{desc: "integer from bytes", typ: "integer", in: []byte("-7"), want: int64(-7)},
+		{desc: "integer from integral float text", typ: "integer", in: "3.0", want: int64(3)},
+		{desc: "integer from float", typ: "integer", in: float64(12), want: int64(12)},
+		{desc: "integer from int32", typ: "integer", in: int32(5), want: int64(5)},
+		{desc: "integer from numeric", typ: "integer", in: pgtype.Numeric{Int: big.NewInt(1500), Exp: -2, Valid: true}, want: int64(15)},
+		{desc: "float from text", typ: "float", in: "19.99", want: 19.99},
+		{desc: "float from integer", typ: "float", in: int64(2), want: float64(2)},
+		{desc: "float from numeric", typ: "float", in: pgtype.Numeric{Int: big.NewInt(1999), Exp: -2, Valid: true}, want: 19.99},
+		{desc: "boolean from text", typ: "boolean", in: "TRUE", want: true},
+		{desc: "boolean from digit", typ: "boolean", in: "0", want: false},
+		{desc: "boolean from integer", typ: "boolean", in: int64(1), want: true},
+		{desc: "timestamp from rfc3339", typ: "timestamp", in: "2024-03-10T08:30:00Z", want: ts},
+		{desc: "timestamp from datetime", typ: "timestamp", in: "2024-03-10 08:30:00", want: ts},
+		{desc: "timestamp from date", typ: "timestamp", in: "2024-03-10", want: time.Date(2024, time.March, 10, 0, 0, 0, 0, time.UTC)},
+		{desc: "timestamp from time", typ: "timestamp", in: ts, want: ts},
+		{desc: "json from text", typ: "json", in: `{"a":[1,2]}`, want: map[string]any{"a": []any{float64(1), float64(2)}}},
+		{desc: "json already decoded", typ: "json", in: map[string]any{"a": "b"}, want: map[string]any{"a": "b"}},
+		{desc: "string from integer", typ: "string", in: int64(10), want: "10"},
+		{desc: "string from float", typ: "string", in: 0.5, want: "0.5"},
+		{desc: "string from bytes", typ: "string", in: []byte("abc"), want: "abc"},
+		{desc: "string from time", typ: "string", in: ts, want: "2024-03-10T08:30:00Z"},
+		{desc: "null numeric", typ: "integer", in: pgtype.Numeric{}, want: nil},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := tools.CoerceValue(tc.in, tc.typ)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected value: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestCoerceRow(t *testing.T) {
+	columnTypes := tools.ColumnTypes{"id": "integer", "price": "float", "active": "boolean", "missing": "integer"}
+	tcs := []struct {
+		desc    string
+		lenient bool
+		in      map[string]any
+		want    map[string]any
+		err     string
+	}{
+		{
+			desc: "converts typed columns",
+			in:   map[string]any{"id": "1", "price": "2.5", "active": "false", "name": "7"},
+			want: map[string]any{"id": int64(1), "price": 2.5, "active": false, "name": "7"},
+		},
+		{
+			desc: "keeps nulls",
+			in:   map[string]any{"id": nil, "price": nil, "active": nil},
+			want: map[string]any{"id": nil, "price": nil, "active": nil},
+		},
+		{
+			desc: "strict invalid integer",
+			in:   map[string]any{"id": "abc"},
+			err:  `unable to coerce column "id": cannot convert "abc" to integer`,
+		},
+		{
+			desc: "strict fractional integer",
+			in:   map[string]any{"id": 1.5},
+			err:  `unable to coerce column "id": cannot convert 1.5 (float64) to integer`,
+		},
+		{
+			desc: "strict invalid boolean",
+			in:   map[string]any{"active": int64(2)},
+			err:  `unable to coerce column "active": cannot convert 2 (int64) to boolean`,
+		},
+		{
+			desc:    "lenient invalid values",
+			lenient: true,
+			in:      map[string]any{"id": "abc", "price": "NaN", "active": "yes"},
+			want:    map[string]any{"id": nil, "price": nil, "active": nil},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := columnTypes.CoerceRow(tc.in, tc.lenient)
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Fatalf("unexpected error: got %v, want %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, tc.in); diff != "" {
+				t.Fatalf("unexpected row: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestCoerceValueInvalid(t *testing.T) {
+	tcs := []struct {
+		typ string
+		in  any
+	}{
+		{typ: "integer", in: "9223372036854775808"},
+		{typ: "integer", in: uint64(1 << 63)},
+		{typ: "integer", in: true},
+		{typ: "float", in: "twelve"},
+		{typ: "float", in: "Inf"},
+		{typ: "boolean", in: "maybe"},
+		{typ: "timestamp", in: "10/03/2024"},
+		{typ: "timestamp", in: int64(1710059400)},
+		{typ: "json", in: "{not json"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.typ, func(t *testing.T) {
+			if got, err := tools.CoerceValue(tc.in, tc.typ); err == nil {
+				t.Fatalf("expected an error, got %v", got)
+			}
+		})
+	}
+}
+
+func TestUnmarshalColumnTypes(t *testing.T) {
+	tcs := []struct {
+		desc string
+		in   string
+		want tools.ColumnTypes
+		err  string
+	}{
+		{desc: "valid", in: `{id: integer, created: timestamp}`, want: tools.ColumnTypes{"id": "integer", "created": "timestamp"}},
+		{desc: "unknown type", in: `{id: int}`, err: `type "int" of column "id" must be one of`},
+		{desc: "not a map", in: `[id]`, err: "must be a map of column names to types"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			var got tools.ColumnTypes
+			err := yaml.Unmarshal([]byte(tc.in), &got)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("unexpected error: got %v, want substring %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected result: diff %v", diff)
+			}
+		})
+	}
+}
@@ -72,6 +72,8 @@ func ConvertAnySliceToTyped(s []any, itemType string) (any, error) {
 			tempSlice[j] = b
 		}
 		typedSlice = tempSlice
+	default:
+		return nil, fmt.Errorf("unsupported item type %q", itemType)
 	}
 	return typedSlice, nil
 }
@@ -295,3 +295,67 @@ func TestPopulateTemplateWithJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertAnySliceToTyped(t *testing.T) {
+	tcs := []struct {
+		name     string
+		in       []any
+		itemType string
+		want     any
+		wantErr  string
+	}{
+		{
+			name:     "string",
+			in:       []any{"a", "b"},
+			itemType: "string",
+			want:     []string{"a", "b"},
+		},
+		{
+			name:     "integer",
+			in:       []any{1, 2},
+			itemType: "integer",
+			want:     []int64{1, 2},
+		},
+		{
+			name:     "float",
+			in:       []any{1.5, 2.0},
+			itemType: "float",
+			want:     []float64{1.5, 2.0},
+		},
+		{
+			name:     "boolean",
+			in:       []any{true, false},
+			itemType: "boolean",
+			want:     []bool{true, false},
+		},
+		{
+			name:     "mismatched item",
+			in:       []any{1, "two"},
+			itemType: "integer",
+			wantErr:  "expected item at index 1 to be integer, got string",
+		},
+		{
+			name:     "unsupported item type",
+			in:       []any{map[string]any{}},
+			itemType: "map",
+			wantErr:  `unsupported item type "map"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tools.ConvertAnySliceToTyped(tc.in, tc.itemType)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("unexpected error: got %v, want to contain %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// CredentialRefreshTool wraps a Tool acting on a RotatableSource. When an
+// invocation fails because the source rejected its credentials, the source
+// re-reads them, so that later invocations use rotated credentials.
+type CredentialRefreshTool struct {
+	Tool
+	Source sources.RotatableSource
+}
+
+func (t CredentialRefreshTool) unwrap() Tool {
+	return t.Tool
+}
+
+func (t CredentialRefreshTool) Invoke(ctx context.Context, inv Invocation) (any, error) {
+	res, err := t.Tool.Invoke(ctx, inv)
+	if err == nil || !t.Source.IsAuthError(err) {
+		return res, err
+	}
+	if rotateErr := t.Source.RotateCredentials(ctx, nil); rotateErr != nil {
+		if logger, lErr := util.LoggerFromContext(ctx); lErr == nil {
+			logger.WarnContext(ctx, fmt.Sprintf("unable to reload credentials after an authentication failure: %s", rotateErr))
+		}
+	}
+	return res, err
+}
@@ -17,6 +17,7 @@ package dataplexlookupentry
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	dataplexapi "cloud.google.com/go/dataplex/apiv1"
 	dataplexpb "cloud.google.com/go/dataplex/apiv1/dataplexpb"
@@ -28,6 +29,13 @@ import (
 
 const kind string = "dataplex-lookup-entry"
 
+const (
+	// targetKey is the name of the union of the ways to identify the entry.
+	targetKey   = "target"
+	entryBranch = "entry"
+	nameBranch  = "name"
+)
+
 func init() {
 	if !tools.Register(kind, newConfig) {
 		panic(fmt.Sprintf("tool kind %q already registered", kind))
@@ -79,26 +87,25 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
 	}
 
-	viewDesc := `
-				## Argument: view
-
-				**Type:** Integer
-
-				**Description:** Specifies the parts of the entry and its aspects to return.
-
-				**Possible Values:**
-
-				*   1 (BASIC): Returns entry without aspects.
-				*   2 (FULL): Return all required aspects and the keys of non-required aspects. (Default)
-				*   3 (CUSTOM): Return the entry and aspects requested in aspect_types field (at most 100 aspects). Always use this view when aspect_types is not empty.
-				*   4 (ALL): Return the entry and both required and optional aspects (at most 100 aspects)
-				`
-
-	name := tools.NewStringParameter("name", "The project to which the request should be attributed in the following form: projects/{project}/locations/{location}.")
-	view := tools.NewIntParameterWithDefault("view", 2, viewDesc)
+	entry := tools.NewOneOfParameter(targetKey, "The entry to look up, either by its resource name or by its entry group and ID.",
+		tools.OneOfBranch{
+			Name: entryBranch,
+			Parameters: tools.Parameters{
+				tools.NewStringParameter("entry", "The resource name of the Entry in the following form: projects/{project}/locations/{location}/entryGroups/{entryGroup}/entries/{entry}. The request is attributed to the project and location of the entry."),
+			},
+		},
+		tools.OneOfBranch{
+			Name: nameBranch,
+			Parameters: tools.Parameters{
+				tools.NewStringParameter("name", "The project and location of the entry, to which the request is attributed, in the following form: projects/{project}/locations/{location}."),
+				tools.NewStringParameter("entryGroup", "The ID of the entry group of the entry, such as @bigquery."),
+				tools.NewStringParameter("entryId", "The ID of the entry, such as bigquery.googleapis.com/projects/{project}/datasets/{dataset}."),
+			},
+		},
+	)
+	view := viewParameter{}
 	aspectTypes := tools.NewArrayParameterWithDefault("aspectTypes", []any{}, "Limits the aspects returned to the provided aspect types. It only works when used together with CUSTOM view.", tools.NewStringParameter("aspectType", "The types of aspects to be included in the response in the format `projects/{project}/locations/{location}/aspectTypes/{aspectType}`."))
-	entry := tools.NewStringParameter("entry", "The resource name of the Entry in the following form: projects/{project}/locations/{location}/entryGroups/{entryGroup}/entries/{entry}.")
-	parameters := tools.Parameters{name, view, aspectTypes, entry}
+	parameters := tools.Parameters{entry, view, aspectTypes}
 
 	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, parameters)
 
@@ -118,6 +125,9 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	return t, nil
 }
 
+// validate interface
+var _ tools.Tool = Tool{}
+
 type Tool struct {
 	Name          string
 	Kind          string
@@ -128,17 +138,20 @@ type Tool struct {
 	mcpManifest   tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	paramsMap := params.AsMap()
-	viewMap := map[int]dataplexpb.EntryView{
-		1: dataplexpb.EntryView_BASIC,
-		2: dataplexpb.EntryView_FULL,
-		3: dataplexpb.EntryView_CUSTOM,
-		4: dataplexpb.EntryView_ALL,
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
+	target, ok := paramsMap[targetKey].(tools.OneOfValue)
+	if !ok {
+		return nil, fmt.Errorf("invalid or missing '%s' parameter", targetKey)
+	}
+	name, entry, err := lookupTarget(target)
+	if err != nil {
+		return nil, err
+	}
+	view, err := entryView(ctx, paramsMap[viewKey])
+	if err != nil {
+		return nil, err
 	}
-	name, _ := paramsMap["name"].(string)
-	entry, _ := paramsMap["entry"].(string)
-	view, _ := paramsMap["view"].(int)
 	aspectTypeSlice, err := tools.ConvertAnySliceToTyped(paramsMap["aspectTypes"].([]any), "string")
 	if err != nil {
 		return nil, fmt.Errorf("can't convert aspectTypes to array of strings: %s", err)
@@ -147,7 +160,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 
 	req := &dataplexpb.LookupEntryRequest{
 		Name:        name,
-		View:        viewMap[view],
+		View:        view,
 		AspectTypes: aspectTypes,
 		Entry:       entry,
 	}
@@ -159,6 +172,28 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	return result, nil
 }
 
+// lookupTarget returns the project and location to which the lookup is
+// attributed, and the resource name of the entry to look up.
+func lookupTarget(target tools.OneOfValue) (string, string, error) {
+	values := target.Params.AsMap()
+	switch target.Branch {
+	case entryBranch:
+		entry, _ := values["entry"].(string)
+		// projects/{project}/locations/{location}/entryGroups/...
+		parts := strings.SplitN(entry, "/", 5)
+		if len(parts) < 5 || parts[0] != "projects" || parts[2] != "locations" {
+			return "", "", fmt.Errorf("invalid entry %q: must be of the form projects/{project}/locations/{location}/entryGroups/{entryGroup}/entries/{entry}", entry)
+		}
+		return strings.Join(parts[:4], "/"), entry, nil
+	case nameBranch:
+		name, _ := values["name"].(string)
+		entryGroup, _ := values["entryGroup"].(string)
+		entryID, _ := values["entryId"].(string)
+		return name, fmt.Sprintf("%s/entryGroups/%s/entries/%s", name, entryGroup, entryID), nil
+	}
+	return "", "", fmt.Errorf("unknown branch %q of the '%s' parameter", target.Branch, targetKey)
+}
+
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
 	// Parse parameters from the provided data
 	return tools.ParseParams(t.Parameters, data, claims)
@@ -20,6 +20,8 @@ import (
 	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	dataplexds "github.com/googleapis/genai-toolbox/internal/sources/dataplex"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/tools/dataplex/dataplexlookupentry"
@@ -115,3 +117,57 @@ func TestParseFromYamlDataplexLookupEntry(t *testing.T) {
 	}
 
 }
+
+func TestParseParamsEntry(t *testing.T) {
+	cfg := dataplexlookupentry.Config{Name: "lookup_entry", Kind: "dataplex-lookup-entry", Source: "my-instance", Description: "some description"}
+	tool, err := cfg.Initialize(map[string]sources.Source{"my-instance": &dataplexds.Source{}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	entry := "projects/p/locations/us/entryGroups/@bigquery/entries/bigquery.googleapis.com/projects/p/datasets/d"
+	tcs := []struct {
+		desc       string
+		in         map[string]any
+		wantBranch string
+		wantErr    string
+	}{
+		{
+			desc:    "no entry",
+			in:      map[string]any{"view": "BASIC"},
+			wantErr: "exactly one of (entry) or (name, entryGroup, entryId) must be provided, but none was provided",
+		},
+		{
+			desc:       "entry resource name",
+			in:         map[string]any{"entry": entry},
+			wantBranch: "entry",
+		},
+		{
+			desc:       "entry group and id",
+			in:         map[string]any{"name": "projects/p/locations/us", "entryGroup": "@bigquery", "entryId": "bigquery.googleapis.com/projects/p/datasets/d"},
+			wantBranch: "name",
+		},
+		{
+			desc:    "both",
+			in:      map[string]any{"name": "projects/p/locations/us", "entry": entry},
+			wantErr: "exactly one of (entry) or (name, entryGroup, entryId) must be provided, but (entry) and (name, entryGroup, entryId) were all provided",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			params, err := tool.ParseParams(tc.in, nil)
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Fatalf("unexpected error: got %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			target, ok := params.AsMap()["target"].(tools.OneOfValue)
+			if !ok || target.Branch != tc.wantBranch {
+				t.Fatalf("unexpected target: got %v, want branch %q", params.AsMap()["target"], tc.wantBranch)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplexlookupentry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	dataplexpb "cloud.google.com/go/dataplex/apiv1/dataplexpb"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+const (
+	viewKey     = "view"
+	defaultView = "FULL"
+)
+
+// viewNames are the values of the view parameter, in the order of the
+// EntryView enum.
+var viewNames = []string{"BASIC", "FULL", "CUSTOM", "ALL"}
+
+// entryViews maps the values of the view parameter to the EntryView enum.
+var entryViews = map[string]dataplexpb.EntryView{
+	"BASIC":  dataplexpb.EntryView_BASIC,
+	"FULL":   dataplexpb.EntryView_FULL,
+	"CUSTOM": dataplexpb.EntryView_CUSTOM,
+	"ALL":    dataplexpb.EntryView_ALL,
+}
+
+const viewDescription = `Specifies the parts of the entry and its aspects to return. One of:
+
+*   BASIC: Returns entry without aspects.
+*   FULL: Return all required aspects and the keys of non-required aspects. (Default)
+*   CUSTOM: Return the entry and aspects requested in aspectTypes (at most 100 aspects). Always use this view when aspectTypes is not empty.
+*   ALL: Return the entry and both required and optional aspects (at most 100 aspects).`
+
+// viewParameter is the view parameter. It takes the name of a view, in any
+// case, which it parses to upper case. For compatibility, it also takes the
+// number of the view in the EntryView enum, which is deprecated.
+type viewParameter struct{}
+
+var _ tools.Parameter = viewParameter{}
+
+func (p viewParameter) GetName() string                           { return viewKey }
+func (p viewParameter) GetType() string                           { return "string" }
+func (p viewParameter) GetDefault() any                           { return defaultView }
+func (p viewParameter) GetRequired() bool                         { return false }
+func (p viewParameter) GetAuthServices() []tools.ParamAuthService { return nil }
+
+func (p viewParameter) Parse(v any) (any, error) {
+	var n int64
+	switch v := v.(type) {
+	case string:
+		name := strings.ToUpper(v)
+		if _, ok := entryViews[name]; !ok {
+			return nil, fmt.Errorf("invalid view %q: must be one of %s", v, strings.Join(viewNames, ", "))
+		}
+		return name, nil
+	case int:
+		n = int64(v)
+	case int32:
+		n = int64(v)
+	case int64:
+		n = v
+	case json.Number:
+		i, err := v.Int64()
+		if err != nil {
+			return nil, &tools.ParseTypeError{Name: viewKey, Type: "string", Value: v}
+		}
+		n = i
+	default:
+		return nil, &tools.ParseTypeError{Name: viewKey, Type: "string", Value: v}
+	}
+	if n < 1 || n > int64(len(viewNames)) {
+		return nil, fmt.Errorf("invalid view %d: must be one of %s", n, strings.Join(viewNames, ", "))
+	}
+	return int(n), nil
+}
+
+func (p viewParameter) Manifest() tools.ParameterManifest {
+	return tools.ParameterManifest{
+		Name:         viewKey,
+		Type:         "string",
+		Required:     false,
+		Description:  viewDescription,
+		AuthServices: []string{},
+		Enum:         viewNames,
+	}
+}
+
+func (p viewParameter) McpManifest() (tools.ParameterMcpManifest, []string) {
+	return tools.ParameterMcpManifest{
+		Type:        "string",
+		Description: viewDescription,
+		Enum:        viewNames,
+	}, []string{}
+}
+
+// entryView returns the EntryView of v, the parsed value of the view
+// parameter. A warning is logged when v is the deprecated number of a view.
+func entryView(ctx context.Context, v any) (dataplexpb.EntryView, error) {
+	switch v := v.(type) {
+	case string:
+		return entryViews[v], nil
+	case int:
+		name := viewNames[v-1]
+		if logger, err := util.LoggerFromContext(ctx); err == nil {
+			logger.WarnContext(ctx, fmt.Sprintf("passing the %q parameter of %q as the integer %d is deprecated and will stop being supported in a future release, use %q instead", viewKey, kind, v, name))
+		}
+		return entryViews[name], nil
+	}
+	return dataplexpb.EntryView_ENTRY_VIEW_UNSPECIFIED, fmt.Errorf("invalid value %v of the '%s' parameter", v, viewKey)
+}
@@ -0,0 +1,124 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplexlookupentry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	dataplexpb "cloud.google.com/go/dataplex/apiv1/dataplexpb"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+func TestViewParameterParse(t *testing.T) {
+	tcs := []struct {
+		desc    string
+		in      any
+		want    any
+		wantErr string
+	}{
+		{desc: "name", in: "CUSTOM", want: "CUSTOM"},
+		{desc: "lower case name", in: "basic", want: "BASIC"},
+		{desc: "mixed case name", in: "All", want: "ALL"},
+		{desc: "unknown name", in: "PARTIAL", wantErr: `invalid view "PARTIAL": must be one of BASIC, FULL, CUSTOM, ALL`},
+		{desc: "deprecated integer", in: 3, want: 3},
+		{desc: "deprecated json number", in: json.Number("1"), want: 1},
+		{desc: "integer out of range", in: 5, wantErr: "invalid view 5: must be one of BASIC, FULL, CUSTOM, ALL"},
+		{desc: "float", in: json.Number("1.5"), wantErr: `not type "string"`},
+		{desc: "bool", in: true, wantErr: `not type "string"`},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := viewParameter{}.Parse(tc.in)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("unexpected error: got %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != tc.want {
+				t.Fatalf("unexpected value: got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestViewParameterManifest(t *testing.T) {
+	mcp, _ := viewParameter{}.McpManifest()
+	if mcp.Type != "string" {
+		t.Fatalf("unexpected type: %q", mcp.Type)
+	}
+	if diff := cmp.Diff([]string{"BASIC", "FULL", "CUSTOM", "ALL"}, mcp.Enum); diff != "" {
+		t.Fatalf("incorrect enum: diff %v", diff)
+	}
+	if diff := cmp.Diff(mcp.Enum, viewParameter{}.Manifest().Enum); diff != "" {
+		t.Fatalf("incorrect manifest enum: diff %v", diff)
+	}
+}
+
+func TestEntryView(t *testing.T) {
+	tcs := []struct {
+		desc        string
+		in          any
+		want        dataplexpb.EntryView
+		wantWarning string
+	}{
+		{desc: "basic", in: "BASIC", want: dataplexpb.EntryView_BASIC},
+		{desc: "full", in: "FULL", want: dataplexpb.EntryView_FULL},
+		{desc: "custom", in: "CUSTOM", want: dataplexpb.EntryView_CUSTOM},
+		{desc: "all", in: "ALL", want: dataplexpb.EntryView_ALL},
+		{
+			desc:        "deprecated integer",
+			in:          3,
+			want:        dataplexpb.EntryView_CUSTOM,
+			wantWarning: `passing the \"view\" parameter of \"dataplex-lookup-entry\" as the integer 3 is deprecated and will stop being supported in a future release, use \"CUSTOM\" instead`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			var logs bytes.Buffer
+			logger, err := log.NewStdLogger(&logs, &logs, "info")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			ctx := util.WithLogger(context.Background(), logger)
+
+			got, err := entryView(ctx, tc.in)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != tc.want {
+				t.Fatalf("unexpected view: got %v, want %v", got, tc.want)
+			}
+			if tc.wantWarning == "" {
+				if logs.Len() != 0 {
+					t.Fatalf("unexpected logs: %s", logs.String())
+				}
+				return
+			}
+			if !strings.Contains(logs.String(), "WARN") || !strings.Contains(logs.String(), tc.wantWarning) {
+				t.Fatalf("missing deprecation warning: got %q", logs.String())
+			}
+		})
+	}
+}
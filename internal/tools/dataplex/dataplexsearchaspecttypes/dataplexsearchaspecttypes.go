@@ -104,6 +104,9 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	return t, nil
 }
 
+// validate interface
+var _ tools.Tool = Tool{}
+
 type Tool struct {
 	Name          string
 	Kind          string
@@ -115,9 +118,9 @@ type Tool struct {
 	mcpManifest   tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
 	// Invoke the tool with the provided parameters
-	paramsMap := params.AsMap()
+	paramsMap := inv.Params.AsMap()
 	query, _ := paramsMap["query"].(string)
 	pageSize := int32(paramsMap["pageSize"].(int))
 	orderBy, _ := paramsMap["orderBy"].(string)
@@ -17,6 +17,8 @@ package dataplexsearchentries
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"regexp"
 
 	dataplexapi "cloud.google.com/go/dataplex/apiv1"
 	dataplexpb "cloud.google.com/go/dataplex/apiv1/dataplexpb"
@@ -28,6 +30,14 @@ import (
 
 const kind string = "dataplex-search-entries"
 
+// orderByValues are the orderings of the results documented by Dataplex.
+// Results are ordered by descending last_modified_timestamp unless asc is
+// given.
+var orderByValues = []any{"relevance", "last_modified_timestamp", "last_modified_timestamp asc", "last_modified_timestamp desc"}
+
+// scopeRe matches the scope of a search: a project or an organization.
+var scopeRe = regexp.MustCompile(`^(projects|organizations)/[^/]+$`)
+
 func init() {
 	if !tools.Register(kind, newConfig) {
 		panic(fmt.Sprintf("tool kind %q already registered", kind))
@@ -81,8 +91,10 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 	query := tools.NewStringParameter("query", "The query against which entries in scope should be matched.")
 	pageSize := tools.NewIntParameterWithDefault("pageSize", 5, "Number of results in the search page.")
-	orderBy := tools.NewStringParameterWithDefault("orderBy", "relevance", "Specifies the ordering of results. Supported values are: relevance, last_modified_timestamp, last_modified_timestamp asc")
-	parameters := tools.Parameters{query, pageSize, orderBy}
+	orderBy := tools.NewStringParameterWithDefault("orderBy", "relevance", "Specifies the ordering of results. Supported values are: relevance, last_modified_timestamp, last_modified_timestamp asc, last_modified_timestamp desc")
+	orderBy.AllowedValues = orderByValues
+	scope := tools.NewStringParameterWithDefault("scope", "", "Optional: The project or organization to search in, such as 'projects/my-project' or 'organizations/123'. Defaults to all the projects the caller can access.")
+	parameters := tools.Parameters{query, pageSize, orderBy, scope}
 
 	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, parameters)
 
@@ -103,6 +115,9 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	return t, nil
 }
 
+// validate interface
+var _ tools.Tool = Tool{}
+
 type Tool struct {
 	Name          string
 	Kind          string
@@ -114,18 +129,10 @@ type Tool struct {
 	mcpManifest   tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	paramsMap := params.AsMap()
-	query, _ := paramsMap["query"].(string)
-	pageSize := int32(paramsMap["pageSize"].(int))
-	orderBy, _ := paramsMap["orderBy"].(string)
-
-	req := &dataplexpb.SearchEntriesRequest{
-		Query:          query,
-		Name:           fmt.Sprintf("projects/%s/locations/global", t.ProjectID),
-		PageSize:       pageSize,
-		OrderBy:        orderBy,
-		SemanticSearch: true,
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	req, err := NewSearchEntriesRequest(t.ProjectID, inv.Params.AsMap())
+	if err != nil {
+		return nil, &tools.StatusError{Err: err, Code: http.StatusBadRequest}
 	}
 
 	it := t.CatalogClient.SearchEntries(ctx, req)
@@ -144,6 +151,35 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	return results, nil
 }
 
+// NewSearchEntriesRequest returns the request searching the entries of the
+// Dataplex Catalog as projectID with the parameters of an invocation. It
+// returns an error if the query or the scope is invalid.
+func NewSearchEntriesRequest(projectID string, paramsMap map[string]any) (*dataplexpb.SearchEntriesRequest, error) {
+	query, _ := paramsMap["query"].(string)
+	if err := ValidateQuery(query); err != nil {
+		return nil, err
+	}
+	pageSize, _ := paramsMap["pageSize"].(int)
+	orderBy, _ := paramsMap["orderBy"].(string)
+	// descending is the default order of last_modified_timestamp, which
+	// Dataplex does not accept explicitly
+	if orderBy == "last_modified_timestamp desc" {
+		orderBy = "last_modified_timestamp"
+	}
+	scope, _ := paramsMap["scope"].(string)
+	if scope != "" && !scopeRe.MatchString(scope) {
+		return nil, fmt.Errorf("invalid scope %q: must be 'projects/<project>' or 'organizations/<organization>'", scope)
+	}
+	return &dataplexpb.SearchEntriesRequest{
+		Query:          query,
+		Name:           fmt.Sprintf("projects/%s/locations/global", projectID),
+		PageSize:       int32(pageSize),
+		OrderBy:        orderBy,
+		Scope:          scope,
+		SemanticSearch: true,
+	}, nil
+}
+
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
 	// Parse parameters from the provided data
 	return tools.ParseParams(t.Parameters, data, claims)
@@ -15,13 +15,18 @@
 package dataplexsearchentries_test
 
 import (
+	"strings"
 	"testing"
 
+	dataplexpb "cloud.google.com/go/dataplex/apiv1/dataplexpb"
 	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	dataplexds "github.com/googleapis/genai-toolbox/internal/sources/dataplex"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
 	"github.com/googleapis/genai-toolbox/internal/tools/dataplex/dataplexsearchentries"
+	"google.golang.org/protobuf/testing/protocmp"
 )
 
 func TestParseFromYamlDataplexSearchEntries(t *testing.T) {
@@ -71,3 +76,113 @@ func TestParseFromYamlDataplexSearchEntries(t *testing.T) {
 	}
 
 }
+
+func TestValidateQuery(t *testing.T) {
+	tcs := []struct {
+		desc  string
+		query string
+		err   string
+	}{
+		{desc: "free text", query: "customer orders"},
+		{desc: "free text with punctuation", query: "sales 2024: revenue = profit - costs, see https://example.com/report"},
+		{desc: "quoted phrase", query: `"customer orders" table`},
+		{desc: "escaped quote", query: `description:"the \"gold\" tier"`},
+		{desc: "predicates", query: "projectid=my-project type=TABLE column:customer_id"},
+		{desc: "qualified predicates", query: "label.env=prod aspect:my-project.global.quality.score>0.9"},
+		{desc: "boolean operators", query: "(displayname:orders OR name:sales) AND -system=BIGQUERY"},
+		{desc: "time predicate", query: "updatetime>2024-01-01"},
+		{desc: "case insensitive key", query: "ProjectId=my-project"},
+		{desc: "unbalanced quote", query: `displayname:"orders`, err: "unbalanced quotes"},
+		{desc: "unbalanced quotes", query: `"a" "b`, err: "unbalanced quotes"},
+		{desc: "unknown predicate", query: "dataset=sales orders", err: `unknown predicates ["dataset"]`},
+		{desc: "unknown predicates", query: "owner:alice -(owner:bob) tablename=orders", err: `unknown predicates ["owner" "tablename"]`},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := dataplexsearchentries.ValidateQuery(tc.query)
+			if tc.err == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("unexpected error: got %v, want substring %q", err, tc.err)
+			}
+			if strings.Contains(tc.err, "unknown") && !strings.Contains(err.Error(), "supported predicates are: aspect, column") {
+				t.Fatalf("expected error to list the supported predicates: %s", err)
+			}
+		})
+	}
+}
+
+func TestNewSearchEntriesRequest(t *testing.T) {
+	tcs := []struct {
+		desc   string
+		params map[string]any
+		want   *dataplexpb.SearchEntriesRequest
+		err    string
+	}{
+		{
+			desc:   "defaults",
+			params: map[string]any{"query": "orders", "pageSize": 5, "orderBy": "relevance", "scope": ""},
+			want:   &dataplexpb.SearchEntriesRequest{Name: "projects/my-project/locations/global", Query: "orders", PageSize: 5, OrderBy: "relevance", SemanticSearch: true},
+		},
+		{
+			desc:   "organization scope",
+			params: map[string]any{"query": "orders", "pageSize": 10, "orderBy": "last_modified_timestamp asc", "scope": "organizations/123"},
+			want:   &dataplexpb.SearchEntriesRequest{Name: "projects/my-project/locations/global", Query: "orders", PageSize: 10, OrderBy: "last_modified_timestamp asc", Scope: "organizations/123", SemanticSearch: true},
+		},
+		{
+			desc:   "project scope and descending order",
+			params: map[string]any{"query": "orders", "pageSize": 5, "orderBy": "last_modified_timestamp desc", "scope": "projects/other-project"},
+			want:   &dataplexpb.SearchEntriesRequest{Name: "projects/my-project/locations/global", Query: "orders", PageSize: 5, OrderBy: "last_modified_timestamp", Scope: "projects/other-project", SemanticSearch: true},
+		},
+		{
+			desc:   "invalid scope",
+			params: map[string]any{"query": "orders", "pageSize": 5, "orderBy": "relevance", "scope": "folders/456"},
+			err:    `invalid scope "folders/456"`,
+		},
+		{
+			desc:   "invalid query",
+			params: map[string]any{"query": "owner=alice", "pageSize": 5, "orderBy": "relevance", "scope": ""},
+			err:    `unknown predicates ["owner"]`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := dataplexsearchentries.NewSearchEntriesRequest("my-project", tc.params)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("unexpected error: got %v, want substring %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got, protocmp.Transform()); diff != "" {
+				t.Fatalf("unexpected request (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseParamsOrderBy(t *testing.T) {
+	cfg := dataplexsearchentries.Config{Name: "search", Kind: "dataplex-search-entries", Source: "my-dataplex", Description: "some description"}
+	tool, err := cfg.Initialize(map[string]sources.Source{"my-dataplex": &dataplexds.Source{}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	params, err := tool.ParseParams(map[string]any{"query": "orders"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := map[string]any{"query": "orders", "pageSize": 5, "orderBy": "relevance", "scope": ""}
+	if diff := cmp.Diff(want, params.AsMap()); diff != "" {
+		t.Fatalf("unexpected params (-want +got):\n%s", diff)
+	}
+	if _, err := tool.ParseParams(map[string]any{"query": "orders", "orderBy": "name"}, nil); err == nil {
+		t.Fatalf("expected an error for an unsupported orderBy")
+	}
+}
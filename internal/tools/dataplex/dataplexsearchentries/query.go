@@ -0,0 +1,101 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplexsearchentries
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Predicates are the keys of the qualified predicates of the Dataplex search
+// syntax, such as `projectid=my-project` or `column:customer_id`.
+var Predicates = []string{
+	"aspect",
+	"column",
+	"createtime",
+	"description",
+	"displayname",
+	"fully_qualified_name",
+	"label",
+	"location",
+	"name",
+	"orgid",
+	"parent",
+	"projectid",
+	"system",
+	"type",
+	"updatetime",
+}
+
+// predicateRe matches a term qualified by a key, capturing the key. The key
+// must look like an identifier, so that free text such as a URL is not
+// mistaken for a predicate.
+var predicateRe = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)(\.[A-Za-z0-9_.-]+)?(:|=|>=|<=|>|<)[^/]`)
+
+// ValidateQuery returns an error if query has unbalanced quotes or
+// predicates with unknown keys. Free text is not validated.
+func ValidateQuery(query string) error {
+	terms, ok := splitTerms(query)
+	if !ok {
+		return fmt.Errorf("query %q has unbalanced quotes", query)
+	}
+	var unknown []string
+	for _, term := range terms {
+		term = strings.TrimLeft(term, "(-")
+		m := predicateRe.FindStringSubmatch(term)
+		if m == nil {
+			continue
+		}
+		key := strings.ToLower(m[1])
+		if !slices.Contains(Predicates, key) && !slices.Contains(unknown, m[1]) {
+			unknown = append(unknown, m[1])
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("query %q has unknown predicates %q; supported predicates are: %s", query, unknown, strings.Join(Predicates, ", "))
+	}
+	return nil
+}
+
+// splitTerms splits query on the whitespace outside of double quotes. It
+// reports false if a quote is not closed.
+func splitTerms(query string) ([]string, bool) {
+	var terms []string
+	var term strings.Builder
+	quoted, escaped := false, false
+	for _, r := range query {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+		case !quoted && (r == ' ' || r == '\t' || r == '\n'):
+			if term.Len() > 0 {
+				terms = append(terms, term.String())
+				term.Reset()
+			}
+			continue
+		}
+		term.WriteRune(r)
+	}
+	if term.Len() > 0 {
+		terms = append(terms, term.String())
+	}
+	return terms, !quoted
+}
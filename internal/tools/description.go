@@ -0,0 +1,66 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// IsDescriptionTemplate returns whether a description holds template
+// placeholders, such as `{{.source.database}}`.
+func IsDescriptionTemplate(description string) bool {
+	return strings.Contains(description, "{{")
+}
+
+// ResolveDescription resolves the placeholders of a description template
+// from data. Placeholders missing from data are an error, rather than being
+// replaced by "<no value>".
+func ResolveDescription(description string, data map[string]any) (string, error) {
+	tmpl, err := template.New("description").Option("missingkey=error").Parse(description)
+	if err != nil {
+		return "", fmt.Errorf("invalid description template: %w", err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("unable to resolve description template: %w", err)
+	}
+	return b.String(), nil
+}
+
+// DescriptionTool replaces the descriptions of the manifests of a Tool, such
+// as with their description templates resolved.
+type DescriptionTool struct {
+	Tool
+	Description    string
+	McpDescription string
+}
+
+func (t DescriptionTool) unwrap() Tool {
+	return t.Tool
+}
+
+func (t DescriptionTool) Manifest() Manifest {
+	m := t.Tool.Manifest()
+	m.Description = t.Description
+	return m
+}
+
+func (t DescriptionTool) McpManifest() McpManifest {
+	m := t.Tool.McpManifest()
+	m.Description = t.McpDescription
+	return m
+}
@@ -0,0 +1,381 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DeterministicOrderMode is how a tool orders the rows of statements that do
+// not order them.
+type DeterministicOrderMode string
+
+const (
+	// DeterministicOrderRewrite appends an ORDER BY over all the columns of
+	// the result to the statement.
+	DeterministicOrderRewrite DeterministicOrderMode = "rewrite"
+	// DeterministicOrderSort sorts the rows of the result once they are read.
+	DeterministicOrderSort DeterministicOrderMode = "sort"
+)
+
+// maxOrderedStatements is the number of statements whose columns are kept by
+// a DeterministicOrder in rewrite mode. Template parameters can make every
+// invocation run a different statement, so the columns are forgotten once
+// there are more.
+const maxOrderedStatements = 128
+
+// DeterministicOrderSpec is the `deterministicOrder` block of a SQL tool
+// config. The rows of statements that have no ORDER BY are returned in a
+// stable order, so that the results of the same invocation can be compared.
+type DeterministicOrderSpec struct {
+	Mode DeterministicOrderMode `yaml:"mode" validate:"required"`
+}
+
+// DeterministicOrder is a validated DeterministicOrderSpec.
+//
+// Rows are ordered by all of their columns, ascending, with NULLs last. In
+// rewrite mode, the statement is wrapped in a query ordering its rows, whose
+// columns are found by running it once with `LIMIT 0`. In sort mode, the
+// rows are sorted by the name-ordered values of their columns, compared with
+// CompareValues.
+//
+// Statements that already have an ORDER BY on their outermost query are left
+// as they are. Statements limiting their rows without ordering them are
+// refused in both modes, as the rows returned, not only their order, vary.
+type DeterministicOrder struct {
+	mode    DeterministicOrderMode
+	dialect ScopeDialect
+
+	mu      sync.Mutex
+	columns map[string][]string
+}
+
+// NewDeterministicOrder validates spec. It returns nil if spec is nil.
+func NewDeterministicOrder(spec *DeterministicOrderSpec, dialect ScopeDialect) (*DeterministicOrder, error) {
+	if spec == nil {
+		return nil, nil
+	}
+	switch spec.Mode {
+	case DeterministicOrderRewrite, DeterministicOrderSort:
+	case "":
+		return nil, fmt.Errorf("deterministicOrder mode is required: must be one of %q or %q", DeterministicOrderRewrite, DeterministicOrderSort)
+	default:
+		return nil, fmt.Errorf("invalid deterministicOrder mode %q: must be one of %q or %q", spec.Mode, DeterministicOrderRewrite, DeterministicOrderSort)
+	}
+	return &DeterministicOrder{mode: spec.Mode, dialect: dialect, columns: make(map[string][]string)}, nil
+}
+
+// outerQuery is what the clauses of the outermost query of a statement tell
+// about the order of its rows.
+type outerQuery struct {
+	// query is whether the statement is a single query, which can be
+	// wrapped in another.
+	query bool
+	// ordered is whether the outermost query has an ORDER BY.
+	ordered bool
+	// limit is the clause limiting the rows of the outermost query, such
+	// as "LIMIT", if any.
+	limit string
+	// unwrappable is the clause that prevents the query from being wrapped
+	// in another, such as "FOR" of `FOR UPDATE`, if any.
+	unwrappable string
+}
+
+// queryStarts are the keywords that queries start with.
+var queryStarts = []string{"select", "with", "values", "table"}
+
+// analyzeOuterQuery finds the clauses of the outermost query of statement,
+// at the top level of its parentheses.
+func analyzeOuterQuery(statement string, dialect ScopeDialect) outerQuery {
+	tokens := scanSQL(statement, dialect)
+	// a trailing semicolon ends the statement, others start another one
+	for len(tokens) > 0 && tokens[len(tokens)-1].is(sqlPunct, ";") {
+		tokens = tokens[:len(tokens)-1]
+	}
+	var q outerQuery
+	if len(tokens) == 0 {
+		return q
+	}
+	first := 0
+	for first < len(tokens) && tokens[first].is(sqlPunct, "(") {
+		first++
+	}
+	q.query = first < len(tokens) && slices.Contains(queryStarts, tokens[first].keyword())
+
+	depth := 0
+	for i, t := range tokens {
+		switch {
+		case t.is(sqlPunct, "("):
+			depth++
+		case t.is(sqlPunct, ")"):
+			depth--
+		case t.is(sqlPunct, ";"):
+			q.query = false
+		}
+		if depth != 0 || i == 0 {
+			continue
+		}
+		switch kw := t.keyword(); kw {
+		case "order":
+			if i+1 < len(tokens) && tokens[i+1].keyword() == "by" {
+				q.ordered = true
+			}
+		case "limit", "offset", "fetch":
+			if q.limit == "" {
+				q.limit = strings.ToUpper(kw)
+			}
+		case "for", "into", "lock":
+			if q.unwrappable == "" {
+				q.unwrappable = strings.ToUpper(kw)
+			}
+		}
+	}
+	return q
+}
+
+// Check returns an error if d cannot order the rows of statement. A nil
+// DeterministicOrder accepts all statements.
+func (d *DeterministicOrder) Check(statement string) error {
+	if d == nil {
+		return nil
+	}
+	q := analyzeOuterQuery(statement, d.dialect)
+	if q.ordered {
+		return nil
+	}
+	if q.limit != "" {
+		return fmt.Errorf("deterministicOrder cannot order a statement with %s but no ORDER BY, as the rows it returns vary: add an ORDER BY to the statement", q.limit)
+	}
+	if d.mode != DeterministicOrderRewrite {
+		return nil
+	}
+	if !q.query {
+		return fmt.Errorf("deterministicOrder cannot rewrite a statement that is not a single query: use mode %q", DeterministicOrderSort)
+	}
+	if q.unwrappable != "" {
+		return fmt.Errorf("deterministicOrder cannot rewrite a query with an outermost %s clause: use mode %q", q.unwrappable, DeterministicOrderSort)
+	}
+	return nil
+}
+
+// Rewrites returns whether d rewrites statement, which Check accepted, to
+// order its rows. The columns of statement must then be passed to Statement.
+func (d *DeterministicOrder) Rewrites(statement string) bool {
+	return d != nil && d.mode == DeterministicOrderRewrite && !analyzeOuterQuery(statement, d.dialect).ordered
+}
+
+// ColumnsStatement returns the statement returning no rows, but the columns
+// of statement.
+func (d *DeterministicOrder) ColumnsStatement(statement string) string {
+	return fmt.Sprintf("SELECT * FROM (\n%s\n) AS deterministic_order LIMIT 0", trimStatement(statement))
+}
+
+// Columns returns the columns of statement, calling find with its
+// ColumnsStatement unless they are already known.
+func (d *DeterministicOrder) Columns(statement string, find func(columnsStatement string) ([]string, error)) ([]string, error) {
+	d.mu.Lock()
+	columns, ok := d.columns[statement]
+	d.mu.Unlock()
+	if ok {
+		return columns, nil
+	}
+	columns, err := find(d.ColumnsStatement(statement))
+	if err != nil {
+		return nil, fmt.Errorf("unable to find the columns to order the rows by: %w", err)
+	}
+	for i, c := range columns {
+		if slices.Contains(columns[:i], c) {
+			return nil, fmt.Errorf("deterministicOrder cannot rewrite a query returning column %q more than once: use mode %q", c, DeterministicOrderSort)
+		}
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.columns) >= maxOrderedStatements {
+		clear(d.columns)
+	}
+	d.columns[statement] = columns
+	return columns, nil
+}
+
+// Statement returns statement wrapped in a query ordering its rows by
+// columns, ascending, with NULLs last.
+func (d *DeterministicOrder) Statement(statement string, columns []string) string {
+	order := make([]string, len(columns))
+	for i, c := range columns {
+		if d.dialect == MySQLDialect {
+			// MySQL sorts NULLs first, and has no NULLS LAST
+			name := "`" + strings.ReplaceAll(c, "`", "``") + "`"
+			order[i] = fmt.Sprintf("%s IS NULL, %s", name, name)
+			continue
+		}
+		name := `"` + strings.ReplaceAll(c, `"`, `""`) + `"`
+		order[i] = name + " NULLS LAST"
+	}
+	return fmt.Sprintf("SELECT * FROM (\n%s\n) AS deterministic_order\nORDER BY %s", trimStatement(statement), strings.Join(order, ", "))
+}
+
+// Sorts returns whether d sorts the rows of statement, which Check accepted,
+// once they are read. They must then be passed to Sort.
+func (d *DeterministicOrder) Sorts(statement string) bool {
+	return d != nil && d.mode == DeterministicOrderSort && !analyzeOuterQuery(statement, d.dialect).ordered
+}
+
+// Sort sorts rows in place if d sorts the rows of statement, which Check
+// accepted. Rows are maps of column names to values.
+func (d *DeterministicOrder) Sort(statement string, rows []any) {
+	if !d.Sorts(statement) {
+		return
+	}
+	var columns []string
+	for _, row := range rows {
+		m, _ := row.(map[string]any)
+		for c := range m {
+			if !slices.Contains(columns, c) {
+				columns = append(columns, c)
+			}
+		}
+	}
+	slices.Sort(columns)
+	slices.SortStableFunc(rows, func(a, b any) int {
+		ma, _ := a.(map[string]any)
+		mb, _ := b.(map[string]any)
+		for _, c := range columns {
+			if n := CompareValues(ma[c], mb[c]); n != 0 {
+				return n
+			}
+		}
+		return 0
+	})
+}
+
+func trimStatement(statement string) string {
+	return strings.TrimRight(strings.TrimSpace(statement), ";")
+}
+
+// Ranks of the types of values compared by CompareValues, in order.
+const (
+	rankBool = iota
+	rankNumber
+	rankString
+	rankTime
+	rankBytes
+	rankOther
+	rankNull
+)
+
+// CompareValues compares two values of the rows of a result, returning -1,
+// 0 or +1. Values of different types are ordered by type: booleans, numbers,
+// strings, times, bytes, other values, and NULLs last. Numbers of different
+// types are compared by value, and other values, such as JSON documents, by
+// their JSON encoding.
+func CompareValues(a, b any) int {
+	ra, va := rankValue(a)
+	rb, vb := rankValue(b)
+	if ra != rb {
+		return cmp.Compare(ra, rb)
+	}
+	switch ra {
+	case rankBool:
+		return compareBools(va.(bool), vb.(bool))
+	case rankNumber:
+		return compareNumbers(va.(*big.Float), vb.(*big.Float))
+	case rankString:
+		return strings.Compare(va.(string), vb.(string))
+	case rankTime:
+		return va.(time.Time).Compare(vb.(time.Time))
+	case rankBytes, rankOther:
+		return bytes.Compare(va.([]byte), vb.([]byte))
+	}
+	return 0
+}
+
+// rankValue returns the rank of the type of v, and the value compared: a
+// bool, a *big.Float for numbers, with nil for NaN, a string, a time.Time, or
+// the bytes of v or of its JSON encoding.
+func rankValue(v any) (int, any) {
+	switch v := v.(type) {
+	case nil:
+		return rankNull, nil
+	case bool:
+		return rankBool, v
+	case string:
+		return rankString, v
+	case time.Time:
+		return rankTime, v
+	case []byte:
+		return rankBytes, v
+	case json.Number:
+		if f, ok := new(big.Float).SetString(string(v)); ok {
+			return rankNumber, f
+		}
+		return rankString, string(v)
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rankNumber, new(big.Float).SetInt64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rankNumber, new(big.Float).SetUint64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsNaN(f) {
+			return rankNumber, (*big.Float)(nil)
+		}
+		return rankNumber, big.NewFloat(f)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return rankOther, []byte(fmt.Sprint(v))
+	}
+	// numbers of driver types, such as decimals, encode as JSON numbers
+	if f, ok := new(big.Float).SetString(string(b)); ok {
+		return rankNumber, f
+	}
+	return rankOther, b
+}
+
+// compareNumbers compares two numbers, nil standing for NaN, which comes
+// after all numbers.
+func compareNumbers(a, b *big.Float) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+	return a.Cmp(b)
+}
+
+func compareBools(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case !a:
+		return -1
+	}
+	return 1
+}
@@ -0,0 +1,341 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func newDeterministicOrder(t *testing.T, mode tools.DeterministicOrderMode, dialect tools.ScopeDialect) *tools.DeterministicOrder {
+	t.Helper()
+	d, err := tools.NewDeterministicOrder(&tools.DeterministicOrderSpec{Mode: mode}, dialect)
+	if err != nil {
+		t.Fatalf("unable to create deterministic order: %s", err)
+	}
+	return d
+}
+
+func TestNewDeterministicOrder(t *testing.T) {
+	d, err := tools.NewDeterministicOrder(nil, tools.PostgresDialect)
+	if err != nil || d != nil {
+		t.Fatalf("expected nil for a nil spec, got %v, %v", d, err)
+	}
+	for _, tc := range []struct {
+		mode    tools.DeterministicOrderMode
+		wantErr string
+	}{
+		{mode: "", wantErr: "deterministicOrder mode is required"},
+		{mode: "shuffle", wantErr: `invalid deterministicOrder mode "shuffle"`},
+	} {
+		_, err := tools.NewDeterministicOrder(&tools.DeterministicOrderSpec{Mode: tc.mode}, tools.PostgresDialect)
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("mode %q: got error %v, want it to contain %q", tc.mode, err, tc.wantErr)
+		}
+	}
+}
+
+func TestDeterministicOrderCheck(t *testing.T) {
+	rewrite, sort := tools.DeterministicOrderRewrite, tools.DeterministicOrderSort
+	tcs := []struct {
+		desc         string
+		dialect      tools.ScopeDialect
+		mode         tools.DeterministicOrderMode
+		statement    string
+		wantErr      string
+		wantRewrites bool
+	}{
+		{
+			desc:         "unordered query",
+			mode:         rewrite,
+			statement:    "SELECT id, name FROM users WHERE id = $1;",
+			wantRewrites: true,
+		},
+		{
+			desc:      "ordered query",
+			mode:      rewrite,
+			statement: "SELECT id, name FROM users ORDER BY id LIMIT 10",
+		},
+		{
+			desc:         "order by of a subquery",
+			mode:         rewrite,
+			statement:    "SELECT * FROM (SELECT id FROM users ORDER BY id) AS u",
+			wantRewrites: true,
+		},
+		{
+			desc:         "order by of a window function",
+			mode:         rewrite,
+			statement:    "SELECT id, row_number() OVER (ORDER BY id) FROM users",
+			wantRewrites: true,
+		},
+		{
+			desc:         "order by in strings and comments",
+			mode:         rewrite,
+			statement:    "SELECT 'ORDER BY id' AS s -- ORDER BY s\nFROM users /* ORDER BY */",
+			wantRewrites: true,
+		},
+		{
+			desc:         "union",
+			mode:         rewrite,
+			statement:    "(SELECT id FROM users) UNION ALL (SELECT id FROM admins)",
+			wantRewrites: true,
+		},
+		{
+			desc:         "common table expression",
+			mode:         rewrite,
+			statement:    "WITH u AS (SELECT id FROM users ORDER BY id LIMIT 5) SELECT * FROM u",
+			wantRewrites: true,
+		},
+		{
+			desc:      "limit without order by in rewrite mode",
+			mode:      rewrite,
+			statement: "SELECT id FROM users LIMIT 10",
+			wantErr:   "deterministicOrder cannot order a statement with LIMIT but no ORDER BY",
+		},
+		{
+			desc:      "limit without order by in sort mode",
+			mode:      sort,
+			statement: "SELECT id FROM users LIMIT 10",
+			wantErr:   "deterministicOrder cannot order a statement with LIMIT but no ORDER BY",
+		},
+		{
+			desc:      "limit of an ordered subquery",
+			mode:      sort,
+			statement: "SELECT * FROM (SELECT id FROM users ORDER BY id) AS u LIMIT 10",
+			wantErr:   "with LIMIT but no ORDER BY",
+		},
+		{
+			desc:      "fetch first without order by",
+			mode:      rewrite,
+			statement: "SELECT id FROM users FETCH FIRST 10 ROWS ONLY",
+			wantErr:   "with FETCH but no ORDER BY",
+		},
+		{
+			desc:      "offset without order by",
+			mode:      rewrite,
+			statement: "SELECT id FROM users OFFSET 10",
+			wantErr:   "with OFFSET but no ORDER BY",
+		},
+		{
+			desc:      "locking clause in rewrite mode",
+			mode:      rewrite,
+			statement: "SELECT id FROM users FOR UPDATE",
+			wantErr:   `cannot rewrite a query with an outermost FOR clause: use mode "sort"`,
+		},
+		{
+			desc:      "locking clause in sort mode",
+			mode:      sort,
+			statement: "SELECT id FROM users FOR UPDATE",
+		},
+		{
+			desc:      "returning in rewrite mode",
+			mode:      rewrite,
+			statement: "INSERT INTO users (name) VALUES ($1) RETURNING id",
+			wantErr:   "cannot rewrite a statement that is not a single query",
+		},
+		{
+			desc:      "returning in sort mode",
+			mode:      sort,
+			statement: "INSERT INTO users (name) VALUES ($1) RETURNING id",
+		},
+		{
+			desc:      "several statements",
+			mode:      rewrite,
+			statement: "SELECT 1; SELECT 2",
+			wantErr:   "cannot rewrite a statement that is not a single query",
+		},
+		{
+			desc:      "mysql limit",
+			dialect:   tools.MySQLDialect,
+			mode:      rewrite,
+			statement: "SELECT id FROM users LIMIT 5, 10",
+			wantErr:   "with LIMIT but no ORDER BY",
+		},
+		{
+			desc:         "mysql comment",
+			dialect:      tools.MySQLDialect,
+			mode:         rewrite,
+			statement:    "SELECT id FROM users # LIMIT 5",
+			wantRewrites: true,
+		},
+		{
+			desc:      "mysql locking read",
+			dialect:   tools.MySQLDialect,
+			mode:      rewrite,
+			statement: "SELECT id FROM users LOCK IN SHARE MODE",
+			wantErr:   "outermost LOCK clause",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			d := newDeterministicOrder(t, tc.mode, tc.dialect)
+			err := d.Check(tc.statement)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("got error %v, want it to contain %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := d.Rewrites(tc.statement); got != tc.wantRewrites {
+				t.Fatalf("incorrect Rewrites: got %t, want %t", got, tc.wantRewrites)
+			}
+		})
+	}
+
+	var nilOrder *tools.DeterministicOrder
+	if err := nilOrder.Check("SELECT id FROM users LIMIT 10"); err != nil {
+		t.Fatalf("unexpected error of a nil deterministic order: %s", err)
+	}
+}
+
+func TestDeterministicOrderStatement(t *testing.T) {
+	columns := []string{"id", `my "name"`}
+	pg := newDeterministicOrder(t, tools.DeterministicOrderRewrite, tools.PostgresDialect)
+	want := "SELECT * FROM (\nSELECT id, name AS \"my \"\"name\"\"\" FROM users\n) AS deterministic_order\nORDER BY \"id\" NULLS LAST, \"my \"\"name\"\"\" NULLS LAST"
+	if got := pg.Statement(`SELECT id, name AS "my ""name""" FROM users;`, columns); got != want {
+		t.Errorf("incorrect postgres statement:\ngot  %q\nwant %q", got, want)
+	}
+
+	columns = []string{"id", "my `name`"}
+	mysql := newDeterministicOrder(t, tools.DeterministicOrderRewrite, tools.MySQLDialect)
+	want = "SELECT * FROM (\nSELECT id, name AS `my ``name``` FROM users\n) AS deterministic_order\nORDER BY `id` IS NULL, `id`, `my ``name``` IS NULL, `my ``name```"
+	if got := mysql.Statement("SELECT id, name AS `my ``name``` FROM users", columns); got != want {
+		t.Errorf("incorrect mysql statement:\ngot  %q\nwant %q", got, want)
+	}
+}
+
+func TestDeterministicOrderColumns(t *testing.T) {
+	d := newDeterministicOrder(t, tools.DeterministicOrderRewrite, tools.PostgresDialect)
+	var found []string
+	find := func(columnsStatement string) ([]string, error) {
+		found = append(found, columnsStatement)
+		return []string{"id", "name"}, nil
+	}
+	for range 2 {
+		columns, err := d.Columns("SELECT id, name FROM users;", find)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if diff := cmp.Diff([]string{"id", "name"}, columns); diff != "" {
+			t.Fatalf("incorrect columns (-want +got):\n%s", diff)
+		}
+	}
+	// the columns are only found once
+	want := []string{"SELECT * FROM (\nSELECT id, name FROM users\n) AS deterministic_order LIMIT 0"}
+	if diff := cmp.Diff(want, found); diff != "" {
+		t.Fatalf("incorrect statements run to find the columns (-want +got):\n%s", diff)
+	}
+
+	_, err := d.Columns("SELECT u.id, a.id FROM users u JOIN admins a USING (name)", func(string) ([]string, error) {
+		return []string{"id", "id"}, nil
+	})
+	if err == nil || !strings.Contains(err.Error(), `returning column "id" more than once`) {
+		t.Fatalf("got error %v, want a duplicate column error", err)
+	}
+}
+
+func TestCompareValues(t *testing.T) {
+	t1 := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	tcs := []struct {
+		desc string
+		a, b any
+		want int
+	}{
+		{desc: "equal ints", a: 1, b: int64(1), want: 0},
+		{desc: "int and float", a: int32(2), b: 2.5, want: -1},
+		{desc: "uint and int", a: uint64(math.MaxUint64), b: int64(math.MaxInt64), want: 1},
+		{desc: "json number and int", a: json.Number("10"), b: 9, want: 1},
+		{desc: "decimal and float", a: jsonNumber("12.50"), b: 12.5, want: 0},
+		{desc: "decimals by value", a: jsonNumber("9"), b: jsonNumber("10"), want: -1},
+		{desc: "NaN after numbers", a: math.NaN(), b: math.Inf(1), want: 1},
+		{desc: "NaNs", a: math.NaN(), b: math.NaN(), want: 0},
+		{desc: "strings", a: "apple", b: "banana", want: -1},
+		{desc: "bools", a: false, b: true, want: -1},
+		{desc: "times", a: t1.Add(time.Hour), b: t1, want: 1},
+		{desc: "bytes", a: []byte{1, 2}, b: []byte{1, 3}, want: -1},
+		{desc: "documents", a: map[string]any{"a": 2}, b: map[string]any{"a": 1}, want: 1},
+		{desc: "bool before number", a: true, b: 0, want: -1},
+		{desc: "number before string", a: 100, b: "1", want: -1},
+		{desc: "string before time", a: "z", b: t1, want: -1},
+		{desc: "time before bytes", a: t1, b: []byte("a"), want: -1},
+		{desc: "bytes before documents", a: []byte("z"), b: []any{1}, want: -1},
+		{desc: "nulls last", a: nil, b: map[string]any{}, want: 1},
+		{desc: "nulls", a: nil, b: nil, want: 0},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := tools.CompareValues(tc.a, tc.b); got != tc.want {
+				t.Fatalf("CompareValues(%v, %v) = %d, want %d", tc.a, tc.b, got, tc.want)
+			}
+			if got := tools.CompareValues(tc.b, tc.a); got != -tc.want {
+				t.Fatalf("CompareValues(%v, %v) = %d, want %d", tc.b, tc.a, got, -tc.want)
+			}
+		})
+	}
+}
+
+// jsonNumber is a number of a driver type, encoded as a JSON number.
+type jsonNumber string
+
+func (d jsonNumber) MarshalJSON() ([]byte, error) {
+	return []byte(d), nil
+}
+
+func TestDeterministicOrderSort(t *testing.T) {
+	rows := func() []any {
+		return []any{
+			map[string]any{"id": 2, "name": "bob"},
+			map[string]any{"id": 1, "name": nil},
+			map[string]any{"id": 1, "name": "alice"},
+			map[string]any{"name": "carol"},
+		}
+	}
+	want := []any{
+		map[string]any{"id": 1, "name": "alice"},
+		map[string]any{"id": 1, "name": nil},
+		map[string]any{"id": 2, "name": "bob"},
+		map[string]any{"name": "carol"},
+	}
+
+	d := newDeterministicOrder(t, tools.DeterministicOrderSort, tools.PostgresDialect)
+	got := rows()
+	d.Sort("SELECT id, name FROM users", got)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("incorrect order (-want +got):\n%s", diff)
+	}
+
+	// ordered statements keep their order
+	got = rows()
+	d.Sort("SELECT id, name FROM users ORDER BY name DESC", got)
+	if diff := cmp.Diff(rows(), got); diff != "" {
+		t.Fatalf("ordered rows were sorted (-want +got):\n%s", diff)
+	}
+
+	// rows are only sorted in sort mode
+	got = rows()
+	newDeterministicOrder(t, tools.DeterministicOrderRewrite, tools.PostgresDialect).Sort("SELECT id, name FROM users", got)
+	if diff := cmp.Diff(rows(), got); diff != "" {
+		t.Fatalf("rows were sorted in rewrite mode (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+)
+
+// KindDisabledError is returned when a tool whose kind was disabled by the
+// operator of the server is invoked.
+type KindDisabledError struct {
+	Tool string
+	Kind string
+}
+
+func (e *KindDisabledError) Error() string {
+	return fmt.Sprintf("tool kind disabled by operator: %q is of kind %q", e.Tool, e.Kind)
+}
+
+// DisabledTool rejects every invocation of a Tool whose kind was disabled by
+// the operator of the server. It is still listed, with a `disabled` marker.
+type DisabledTool struct {
+	Tool
+	Kind string
+}
+
+func (t DisabledTool) unwrap() Tool {
+	return t.Tool
+}
+
+func (t DisabledTool) Invoke(ctx context.Context, _ Invocation) (any, error) {
+	return nil, &KindDisabledError{Tool: t.McpManifest().Name, Kind: t.Kind}
+}
+
+func (t DisabledTool) Manifest() Manifest {
+	m := t.Tool.Manifest()
+	m.Disabled = true
+	return m
+}
+
+func (t DisabledTool) McpManifest() McpManifest {
+	m := t.Tool.McpManifest()
+	meta := make(map[string]any, len(m.Metadata)+1)
+	for k, v := range m.Metadata {
+		meta[k] = v
+	}
+	meta["toolbox/disabled"] = true
+	m.Metadata = meta
+	return m
+}
+
+// WithoutDisabled returns a copy of the toolset without the tools whose kind
+// was disabled by the operator of the server.
+func (t Toolset) WithoutDisabled() Toolset {
+	filtered := Toolset{
+		Name: t.Name,
+		Manifest: ToolsetManifest{
+			ServerVersion: t.Manifest.ServerVersion,
+			ToolsManifest: make(map[string]Manifest),
+		},
+		McpManifest: []McpManifest{},
+	}
+	for name, m := range t.Manifest.ToolsManifest {
+		if !m.Disabled {
+			filtered.Manifest.ToolsManifest[name] = m
+		}
+	}
+	for _, m := range t.McpManifest {
+		if _, ok := filtered.Manifest.ToolsManifest[m.Name]; ok {
+			filtered.McpManifest = append(filtered.McpManifest, m)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,176 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+)
+
+// Values of the examples generated for parameters that do not declare one.
+const (
+	exampleString    = "example"
+	exampleInt       = 42
+	exampleFloat     = 3.14
+	exampleBool      = true
+	exampleTimestamp = "2024-01-15T00:00:00Z"
+	exampleDate      = "2024-01-15"
+)
+
+// paramExamples returns the examples of a parameter for its manifests: the
+// example it declares, or else one generated from its default, its allowed
+// values, enum, or type. generated reports whether the example was
+// generated. Parameters filled from auth services and sensitive parameters
+// only have the examples they declare.
+func paramExamples(p Parameter, enum []string) (examples []any, generated bool) {
+	if e, ok := p.(interface{ GetExample() any }); ok && e.GetExample() != nil {
+		return []any{e.GetExample()}, false
+	}
+	if len(p.GetAuthServices()) > 0 || isSensitive(p) {
+		return nil, false
+	}
+	if v, ok := generateExample(p, enum); ok {
+		return []any{v}, true
+	}
+	return nil, false
+}
+
+// generateExample derives an example value of p. Empty defaults, which
+// usually stand for an unset value, are not used as examples.
+func generateExample(p Parameter, enum []string) (any, bool) {
+	if d := p.GetDefault(); d != nil && !isEmptyValue(d) {
+		return d, true
+	}
+	if a, ok := p.(interface{ GetAllowedValues() []any }); ok && len(a.GetAllowedValues()) > 0 {
+		return a.GetAllowedValues()[0], true
+	}
+	if len(enum) > 0 {
+		return enum[0], true
+	}
+	switch p := p.(type) {
+	case *ArrayParameter:
+		item, ok := generateExample(p.Items, nil)
+		if !ok {
+			return nil, false
+		}
+		return []any{item}, true
+	case *MapParameter:
+		if p.ValueType == "" {
+			return map[string]any{"key": "value"}, true
+		}
+		v, ok := exampleOfType(p.ValueType, "")
+		if !ok {
+			return nil, false
+		}
+		return map[string]any{"key": v}, true
+	case *JSONParameter:
+		return map[string]any{"key": "value"}, true
+	}
+	return exampleOfType(p.GetType(), p.GetName())
+}
+
+// isEmptyValue reports whether v is an empty string, slice or map.
+func isEmptyValue(v any) bool {
+	switch v := v.(type) {
+	case string:
+		return v == ""
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	}
+	return false
+}
+
+// exampleOfType returns an example of a scalar parameter of type typ. The
+// examples of strings whose name refers to a time or a date are formatted as
+// such.
+func exampleOfType(typ, name string) (any, bool) {
+	switch typ {
+	case typeString:
+		n := strings.ToLower(name)
+		switch {
+		case strings.Contains(n, "time") || strings.HasSuffix(n, "_at") || strings.HasSuffix(name, "At"):
+			return exampleTimestamp, true
+		case strings.Contains(n, "date"):
+			return exampleDate, true
+		}
+		return exampleString, true
+	case typeInt:
+		return exampleInt, true
+	case typeFloat:
+		return exampleFloat, true
+	case typeBool:
+		return exampleBool, true
+	}
+	return nil, false
+}
+
+// ExamplesConfig wraps a ToolConfig whose kind-agnostic `generateExamples`
+// field is false, so that its manifests only hold the examples declared by
+// its parameters.
+type ExamplesConfig struct {
+	ToolConfig
+}
+
+// validate interface
+var _ ToolConfig = ExamplesConfig{}
+
+func (cfg ExamplesConfig) Initialize(srcs map[string]sources.Source) (Tool, error) {
+	t, err := cfg.ToolConfig.Initialize(srcs)
+	if err != nil {
+		return nil, err
+	}
+	return DeclaredExamplesTool{Tool: t}, nil
+}
+
+// DeclaredExamplesTool removes the generated examples from the manifests of
+// a Tool, for tools whose parameters could be misrepresented by them.
+type DeclaredExamplesTool struct {
+	Tool
+}
+
+func (t DeclaredExamplesTool) unwrap() Tool {
+	return t.Tool
+}
+
+func (t DeclaredExamplesTool) Manifest() Manifest {
+	m := t.Tool.Manifest()
+	params := make([]ParameterManifest, len(m.Parameters))
+	for i, p := range m.Parameters {
+		if p.ExampleGenerated {
+			p.Examples = nil
+			p.ExampleGenerated = false
+		}
+		params[i] = p
+	}
+	m.Parameters = params
+	return m
+}
+
+func (t DeclaredExamplesTool) McpManifest() McpManifest {
+	m := t.Tool.McpManifest()
+	props := make(map[string]ParameterMcpManifest, len(m.InputSchema.Properties))
+	for name, p := range m.InputSchema.Properties {
+		if p.ExampleGenerated {
+			p.Examples = nil
+			p.ExampleGenerated = false
+		}
+		props[name] = p
+	}
+	m.InputSchema.Properties = props
+	return m
+}
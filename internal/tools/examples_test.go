@@ -0,0 +1,289 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestParameterExamples(t *testing.T) {
+	authServices := []tools.ParamAuthService{{Name: "my-google-auth-service", Field: "email"}}
+	tcs := []struct {
+		desc          string
+		param         tools.Parameter
+		want          []any
+		wantGenerated bool
+	}{
+		{
+			desc: "declared",
+			param: func() tools.Parameter {
+				p := tools.NewStringParameterWithDefault("city", "Paris", "a city")
+				p.Example = "Tokyo"
+				return p
+			}(),
+			want: []any{"Tokyo"},
+		},
+		{
+			desc: "declared for a sensitive parameter",
+			param: func() tools.Parameter {
+				p := tools.NewStringParameter("token", "a token")
+				p.Sensitive = true
+				p.Example = "tok_123"
+				return p
+			}(),
+			want: []any{"tok_123"},
+		},
+		{
+			desc:          "from the default",
+			param:         tools.NewIntParameterWithDefault("limit", 10, "a limit"),
+			want:          []any{10},
+			wantGenerated: true,
+		},
+		{
+			desc:          "empty default",
+			param:         tools.NewStringParameterWithDefault("title", "", "a title"),
+			want:          []any{"example"},
+			wantGenerated: true,
+		},
+		{
+			desc:          "from the allowed values",
+			param:         tools.NewStringParameterWithAllowedValues("color", "a color", []any{"red", "blue"}),
+			want:          []any{"red"},
+			wantGenerated: true,
+		},
+		{
+			desc:          "string",
+			param:         tools.NewStringParameter("city", "a city"),
+			want:          []any{"example"},
+			wantGenerated: true,
+		},
+		{
+			desc:          "timestamp",
+			param:         tools.NewStringParameter("created_at", "a time of creation"),
+			want:          []any{"2024-01-15T00:00:00Z"},
+			wantGenerated: true,
+		},
+		{
+			desc:          "camel case timestamp",
+			param:         tools.NewStringParameter("startTime", "a start time"),
+			want:          []any{"2024-01-15T00:00:00Z"},
+			wantGenerated: true,
+		},
+		{
+			desc:          "date",
+			param:         tools.NewStringParameter("birth_date", "a birth date"),
+			want:          []any{"2024-01-15"},
+			wantGenerated: true,
+		},
+		{
+			desc:          "integer",
+			param:         tools.NewIntParameter("id", "an id"),
+			want:          []any{42},
+			wantGenerated: true,
+		},
+		{
+			desc:          "float",
+			param:         tools.NewFloatParameter("price", "a price"),
+			want:          []any{3.14},
+			wantGenerated: true,
+		},
+		{
+			desc:          "boolean",
+			param:         tools.NewBooleanParameter("active", "whether active"),
+			want:          []any{true},
+			wantGenerated: true,
+		},
+		{
+			desc:          "array",
+			param:         tools.NewArrayParameter("ids", "some ids", tools.NewIntParameter("id", "an id")),
+			want:          []any{[]any{42}},
+			wantGenerated: true,
+		},
+		{
+			desc:          "map",
+			param:         tools.NewMapParameter("counts", "some counts", "integer"),
+			want:          []any{map[string]any{"key": 42}},
+			wantGenerated: true,
+		},
+		{
+			desc:          "json",
+			param:         tools.NewJSONParameter("payload", "a payload"),
+			want:          []any{map[string]any{"key": "value"}},
+			wantGenerated: true,
+		},
+		{
+			desc: "sensitive",
+			param: func() tools.Parameter {
+				p := tools.NewStringParameter("token", "a token")
+				p.Sensitive = true
+				return p
+			}(),
+		},
+		{
+			desc:  "from an auth service",
+			param: tools.NewStringParameterWithAuth("email", "an email", authServices),
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			params := tools.Parameters{tc.param}
+			m := params.Manifest()[0]
+			if diff := cmp.Diff(tc.want, m.Examples); diff != "" {
+				t.Fatalf("unexpected examples (-want +got):\n%s", diff)
+			}
+			if m.ExampleGenerated != tc.wantGenerated {
+				t.Fatalf("unexpected generated flag: got %t, want %t", m.ExampleGenerated, tc.wantGenerated)
+			}
+			schema, _ := params.McpManifest()
+			mcp := schema.Properties[tc.param.GetName()]
+			if diff := cmp.Diff(tc.want, mcp.Examples); diff != "" {
+				t.Fatalf("unexpected mcp examples (-want +got):\n%s", diff)
+			}
+			if mcp.ExampleGenerated != tc.wantGenerated {
+				t.Fatalf("unexpected mcp generated flag: got %t, want %t", mcp.ExampleGenerated, tc.wantGenerated)
+			}
+		})
+	}
+}
+
+func TestParameterExampleUnmarshal(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	- name: limit
+	  type: integer
+	  description: the number of rows
+	  example: 7
+	`
+	var got tools.Parameters
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	want := tools.NewIntParameter("limit", "the number of rows")
+	want.Example = uint64(7)
+	if diff := cmp.Diff(tools.Parameters{want}, got); diff != "" {
+		t.Fatalf("incorrect parse: diff %v", diff)
+	}
+}
+
+func TestParameterExamplesSerialization(t *testing.T) {
+	city := tools.NewStringParameter("city", "a city")
+	city.Example = "Tokyo"
+	params := tools.Parameters{city, tools.NewIntParameter("limit", "a limit")}
+
+	b, err := json.Marshal(params.Manifest())
+	if err != nil {
+		t.Fatalf("unable to marshal manifest: %s", err)
+	}
+	want := `[{"name":"city","type":"string","required":true,"description":"a city","authSources":[],"examples":["Tokyo"]},` +
+		`{"name":"limit","type":"integer","required":true,"description":"a limit","authSources":[],"examples":[42]}]`
+	if string(b) != want {
+		t.Fatalf("unexpected manifest: got %s, want %s", b, want)
+	}
+
+	schema, _ := params.McpManifest()
+	b, err = json.Marshal(schema)
+	if err != nil {
+		t.Fatalf("unable to marshal mcp manifest: %s", err)
+	}
+	want = `{"type":"object","properties":{"city":{"type":"string","description":"a city","examples":["Tokyo"]},` +
+		`"limit":{"type":"integer","description":"a limit","examples":[42]}},"required":["city","limit"]}`
+	if string(b) != want {
+		t.Fatalf("unexpected mcp manifest: got %s, want %s", b, want)
+	}
+}
+
+// examplesToolConfig initializes an examplesTool with its parameters.
+type examplesToolConfig struct {
+	params tools.Parameters
+}
+
+func (c examplesToolConfig) ToolConfigKind() string {
+	return "mock"
+}
+
+func (c examplesToolConfig) Initialize(map[string]sources.Source) (tools.Tool, error) {
+	return newExamplesTool(c.params), nil
+}
+
+// examplesTool is a tool whose manifests are built from its parameters when
+// it is initialized, as most tools do.
+type examplesTool struct {
+	tools.Tool
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+func newExamplesTool(params tools.Parameters) examplesTool {
+	return examplesTool{
+		manifest:    tools.Manifest{Description: "mock tool", Parameters: params.Manifest(), AuthRequired: []string{}},
+		mcpManifest: tools.GetMcpManifest("mock", "mock tool", nil, params),
+	}
+}
+
+func (t examplesTool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t examplesTool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func TestExamplesConfig(t *testing.T) {
+	city := tools.NewStringParameter("city", "a city")
+	city.Example = "Tokyo"
+	params := tools.Parameters{city, tools.NewIntParameter("limit", "a limit")}
+
+	tool, err := tools.ExamplesConfig{ToolConfig: examplesToolConfig{params: params}}.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	inner := tool.(tools.DeclaredExamplesTool).Tool
+
+	m := tool.Manifest()
+	got := make(map[string][]any)
+	for _, p := range m.Parameters {
+		got[p.Name] = p.Examples
+	}
+	want := map[string][]any{"city": {"Tokyo"}, "limit": nil}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected manifest examples (-want +got):\n%s", diff)
+	}
+
+	got = make(map[string][]any)
+	for name, p := range tool.McpManifest().InputSchema.Properties {
+		got[name] = p.Examples
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected mcp manifest examples (-want +got):\n%s", diff)
+	}
+
+	// the manifests of the wrapped tool are left as they are
+	if examples := inner.Manifest().Parameters[1].Examples; len(examples) != 1 {
+		t.Fatalf("expected the wrapped tool to keep its generated examples, got %v", examples)
+	}
+	if examples := inner.McpManifest().InputSchema.Properties["limit"].Examples; len(examples) != 1 {
+		t.Fatalf("expected the wrapped tool to keep its generated mcp examples, got %v", examples)
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const typeFile = "file"
+
+// wrapper is implemented by the kind-agnostic Tool wrappers, such as
+// AliasTool, to expose the Tool they decorate.
+type wrapper interface {
+	unwrap() Tool
+}
+
+// As returns the first Tool implementing T found by unwrapping the
+// kind-agnostic wrappers of t, starting with t itself.
+func As[T any](t Tool) (T, bool) {
+	for {
+		if v, ok := t.(T); ok {
+			return v, true
+		}
+		w, ok := t.(wrapper)
+		if !ok {
+			var zero T
+			return zero, false
+		}
+		t = w.unwrap()
+	}
+}
+
+// FileTool is implemented by tools that accept file uploads. Their invoke
+// endpoint also accepts multipart/form-data requests, whose file parts are
+// passed to FileParameters as *File values.
+type FileTool interface {
+	// MaxUploadBytes is the largest file the tool accepts.
+	MaxUploadBytes() int64
+}
+
+// File is a file passed to a FileParameter.
+type File struct {
+	// Filename is the name of the file on the client, if known.
+	Filename string
+	// Size is the size of the file in bytes.
+	Size int64
+	// Open returns a reader of the file content. It may be called more than
+	// once.
+	Open func() (io.ReadCloser, error)
+}
+
+// String describes the file without its content.
+func (f *File) String() string {
+	return fmt.Sprintf("file %q (%d bytes)", f.Filename, f.Size)
+}
+
+// NewTextFile returns a File with the given content.
+func NewTextFile(filename, content string) *File {
+	return &File{
+		Filename: filename,
+		Size:     int64(len(content)),
+		Open: func() (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader(content)), nil
+		},
+	}
+}
+
+// FileParameter is a parameter representing a file. Over multipart/form-data
+// the file is sent as a file part; otherwise, such as over MCP, its content is
+// sent as a string. Values are parsed into a *File.
+type FileParameter struct {
+	CommonParameter `yaml:",inline"`
+	// MaxSize is the largest size of a file in bytes. Zero means no limit.
+	MaxSize int64 `yaml:"maxSize"`
+}
+
+// Ensure FileParameter implements the Parameter interface.
+var _ Parameter = &FileParameter{}
+
+// NewFileParameter is a convenience function for initializing a FileParameter.
+func NewFileParameter(name string, desc string, maxSize int64) *FileParameter {
+	return &FileParameter{
+		CommonParameter: CommonParameter{
+			Name: name,
+			Type: typeFile,
+			Desc: desc,
+		},
+		MaxSize: maxSize,
+	}
+}
+
+// Parse validates and parses an incoming value for the file parameter.
+func (p *FileParameter) Parse(v any) (any, error) {
+	var f *File
+	switch v := v.(type) {
+	case *File:
+		f = v
+	case string:
+		f = NewTextFile("", v)
+	default:
+		return nil, &ParseTypeError{p.Name, p.Type, v}
+	}
+	if p.MaxSize > 0 && f.Size > p.MaxSize {
+		return nil, fmt.Errorf("file is %d bytes, which exceeds the limit of %d bytes", f.Size, p.MaxSize)
+	}
+	return f, nil
+}
+
+func (p *FileParameter) GetAuthServices() []ParamAuthService {
+	return p.AuthServices
+}
+
+func (p *FileParameter) GetDefault() any {
+	return nil
+}
+
+// Manifest returns the manifest for the FileParameter. Files are described
+// as strings, which is how clients that cannot upload files send them.
+func (p *FileParameter) Manifest() ParameterManifest {
+	authServiceNames := getAuthServiceNames(p.AuthServices)
+	r := CheckParamRequired(p.GetRequired(), p.GetDefault())
+	return ParameterManifest{
+		Name:         p.Name,
+		Type:         typeString,
+		Required:     r,
+		Description:  p.Desc,
+		AuthServices: authServiceNames,
+	}
+}
+
+// McpManifest returns the MCP manifest for the FileParameter.
+func (p *FileParameter) McpManifest() (ParameterMcpManifest, []string) {
+	authServiceNames := getAuthServiceNames(p.AuthServices)
+	return ParameterMcpManifest{
+		Type:        typeString,
+		Description: p.Desc,
+	}, authServiceNames
+}
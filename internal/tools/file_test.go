@@ -0,0 +1,76 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// fileTool is a mockTool accepting file uploads.
+type fileTool struct {
+	mockTool
+}
+
+func (t fileTool) MaxUploadBytes() int64 {
+	return 42
+}
+
+func TestAs(t *testing.T) {
+	inner := fileTool{mockTool{name: "upload"}}
+	wrapped := tools.SpillTool{Tool: tools.AliasTool{Tool: inner, Name: "upload_old", Target: "upload"}}
+	ft, ok := tools.As[tools.FileTool](wrapped)
+	if !ok || ft.MaxUploadBytes() != 42 {
+		t.Fatalf("unexpected result: got %v, %t", ft, ok)
+	}
+	if _, ok := tools.As[tools.FileTool](tools.AliasTool{Tool: mockTool{name: "other"}}); ok {
+		t.Fatalf("unexpected FileTool")
+	}
+}
+
+func TestFileParameterParse(t *testing.T) {
+	p := tools.NewFileParameter("file", "a file", 8)
+
+	v, err := p.Parse("id\n1\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	f, ok := v.(*tools.File)
+	if !ok {
+		t.Fatalf("unexpected value type: %T", v)
+	}
+	// files can be read more than once
+	for range 2 {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		b, _ := io.ReadAll(rc)
+		rc.Close()
+		if string(b) != "id\n1\n" {
+			t.Fatalf("unexpected content: %q", b)
+		}
+	}
+
+	if _, err := p.Parse(tools.NewTextFile("big.csv", "123456789")); err == nil || !strings.Contains(err.Error(), "exceeds the limit of 8 bytes") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := p.Parse(42); err == nil {
+		t.Fatalf("expected an error for a number")
+	}
+}
@@ -146,8 +146,8 @@ type Tool struct {
 	mcpManifest tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	mapParams := params.AsMap()
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	mapParams := inv.Params.AsMap()
 
 	// Get collection path
 	collectionPath, ok := mapParams[collectionPathKey].(string)
@@ -58,6 +58,9 @@ type Config struct {
 	Source       string   `yaml:"source" validate:"required"`
 	Description  string   `yaml:"description" validate:"required"`
 	AuthRequired []string `yaml:"authRequired"`
+	// FailFast deletes the documents in a single transaction, so that none
+	// is deleted if any deletion fails.
+	FailFast bool `yaml:"failFast"`
 }
 
 // validate interface
@@ -91,6 +94,7 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		Kind:         kind,
 		Parameters:   parameters,
 		AuthRequired: cfg.AuthRequired,
+		FailFast:     cfg.FailFast,
 		Client:       s.FirestoreClient(),
 		manifest:     tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
 		mcpManifest:  mcpManifest,
@@ -106,14 +110,15 @@ type Tool struct {
 	Kind         string           `yaml:"kind"`
 	AuthRequired []string         `yaml:"authRequired"`
 	Parameters   tools.Parameters `yaml:"parameters"`
+	FailFast     bool             `yaml:"failFast"`
 
 	Client      *firestoreapi.Client
 	manifest    tools.Manifest
 	mcpManifest tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	mapParams := params.AsMap()
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	mapParams := inv.Params.AsMap()
 	documentPathsRaw, ok := mapParams[documentPathsKey].([]any)
 	if !ok {
 		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected an array", documentPathsKey)
@@ -141,11 +146,30 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		}
 	}
 
+	if t.FailFast {
+		err := t.Client.RunTransaction(ctx, func(ctx context.Context, tx *firestoreapi.Transaction) error {
+			for _, path := range documentPaths {
+				if err := tx.Delete(t.Client.Doc(path)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete documents: %w", err)
+		}
+		result := tools.NewBulkResult(len(documentPaths))
+		for _, path := range documentPaths {
+			result.Add(path, nil)
+		}
+		return result, nil
+	}
+
 	// Create a BulkWriter to handle multiple deletions efficiently
 	bulkWriter := t.Client.BulkWriter(ctx)
 
 	// Keep track of jobs for each document
-	jobs := make([]*firestoreapi.BulkWriterJob, len(documentPaths))
+	jobs := make([]bulkJob, len(documentPaths))
 
 	// Add all delete operations to the BulkWriter
 	for i, path := range documentPaths {
@@ -160,25 +184,27 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	// End the BulkWriter to execute all operations
 	bulkWriter.End()
 
-	// Collect results
-	results := make([]any, len(documentPaths))
-	for i, job := range jobs {
-		docData := make(map[string]any)
-		docData["path"] = documentPaths[i]
+	result := deleteResult(documentPaths, jobs)
+	if err := result.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
 
-		// Wait for the job to complete and get the result
-		_, err := job.Results()
-		if err != nil {
-			docData["success"] = false
-			docData["error"] = err.Error()
-		} else {
-			docData["success"] = true
-		}
+// bulkJob is a single write of a BulkWriter.
+type bulkJob interface {
+	Results() (*firestoreapi.WriteResult, error)
+}
 
-		results[i] = docData
+// deleteResult waits for the deletion of each path and returns their bulk
+// result.
+func deleteResult(paths []string, jobs []bulkJob) *tools.BulkResult {
+	result := tools.NewBulkResult(len(paths))
+	for i, job := range jobs {
+		_, err := job.Results()
+		result.Add(paths[i], err)
 	}
-
-	return results, nil
+	return result
 }
 
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
@@ -75,6 +75,27 @@ func TestParseFromYamlFirestoreDeleteDocuments(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "fail fast",
+			in: `
+			tools:
+				atomic_delete_docs:
+					kind: firestore-delete-documents
+					source: my-firestore-instance
+					description: Delete documents atomically
+					failFast: true
+			`,
+			want: server.ToolConfigs{
+				"atomic_delete_docs": firestoredeletedocuments.Config{
+					Name:         "atomic_delete_docs",
+					Kind:         "firestore-delete-documents",
+					Source:       "my-firestore-instance",
+					Description:  "Delete documents atomically",
+					AuthRequired: []string{},
+					FailFast:     true,
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -0,0 +1,90 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package firestoredeletedocuments
+
+import (
+	"errors"
+	"testing"
+
+	firestoreapi "cloud.google.com/go/firestore"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// fakeJob is a bulkJob with a fixed outcome.
+type fakeJob struct {
+	err error
+}
+
+func (j fakeJob) Results() (*firestoreapi.WriteResult, error) {
+	if j.err != nil {
+		return nil, j.err
+	}
+	return &firestoreapi.WriteResult{}, nil
+}
+
+func TestDeleteResult(t *testing.T) {
+	errDenied := errors.New("rpc error: code = PermissionDenied")
+	tcs := []struct {
+		desc      string
+		paths     []string
+		jobs      []bulkJob
+		want      *tools.BulkResult
+		wantTotal bool
+	}{
+		{
+			desc:  "all deleted",
+			paths: []string{"users/a", "users/b"},
+			jobs:  []bulkJob{fakeJob{}, fakeJob{}},
+			want:  &tools.BulkResult{Succeeded: 2, Errors: []tools.BulkItemError{}, Results: []any{"users/a", "users/b"}},
+		},
+		{
+			desc:  "mixed",
+			paths: []string{"users/a", "admins/b", "users/c"},
+			jobs:  []bulkJob{fakeJob{}, fakeJob{err: errDenied}, fakeJob{}},
+			want: &tools.BulkResult{
+				Succeeded: 2,
+				Failed:    1,
+				Errors:    []tools.BulkItemError{{Index: 1, Message: errDenied.Error()}},
+				Results:   []any{"users/a", nil, "users/c"},
+			},
+		},
+		{
+			desc:  "none deleted",
+			paths: []string{"admins/a", "admins/b"},
+			jobs:  []bulkJob{fakeJob{err: errDenied}, fakeJob{err: errDenied}},
+			want: &tools.BulkResult{
+				Failed: 2,
+				Errors: []tools.BulkItemError{
+					{Index: 0, Message: errDenied.Error()},
+					{Index: 1, Message: errDenied.Error()},
+				},
+				Results: []any{nil, nil},
+			},
+			wantTotal: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := deleteResult(tc.paths, tc.jobs)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect result: diff %v", diff)
+			}
+			if gotTotal := got.Err() != nil; gotTotal != tc.wantTotal {
+				t.Fatalf("unexpected total failure: got %v, want %v", gotTotal, tc.wantTotal)
+			}
+		})
+	}
+}
@@ -116,7 +116,7 @@ type Tool struct {
 	mcpManifest tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+func (t Tool) Invoke(ctx context.Context, _ tools.Invocation) (any, error) {
 	// Get the latest release for Firestore
 	releaseName := fmt.Sprintf("projects/%s/releases/cloud.firestore/%s", t.ProjectId, t.DatabaseId)
 	release, err := t.RulesClient.Projects.Releases.Get(releaseName).Context(ctx).Do()
@@ -16,7 +16,11 @@ package firestorelistcollections
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"strings"
 
 	firestoreapi "cloud.google.com/go/firestore"
 	yaml "github.com/goccy/go-yaml"
@@ -24,10 +28,30 @@ import (
 	firestoreds "github.com/googleapis/genai-toolbox/internal/sources/firestore"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/tools/firestore/util"
+	"google.golang.org/api/iterator"
 )
 
 const kind string = "firestore-list-collections"
-const parentPathKey string = "parentPath"
+
+const (
+	parentPathKey          = "parentPath"
+	prefixKey              = "prefix"
+	limitKey               = "limit"
+	pageTokenKey           = "pageToken"
+	checkSubcollectionsKey = "checkSubcollections"
+
+	defaultLimit = 100
+	maxLimit     = 1000
+)
+
+// fetchSize is the number of collection ids fetched from Firestore at a time.
+// Page tokens hold an offset within such a fetch, so it must not change
+// across invocations.
+const fetchSize = 300
+
+// subcollectionSample is the most documents of a collection checked for
+// subcollections.
+const subcollectionSample = 10
 
 func init() {
 	if !tools.Register(kind, newConfig) {
@@ -82,7 +106,17 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 	emptyString := ""
 	parentPathParameter := tools.NewStringParameterWithDefault(parentPathKey, emptyString, "Relative parent document path to list subcollections from (e.g., 'users/userId'). If not provided, lists root collections. Note: This is a relative path, NOT an absolute path like 'projects/{project_id}/databases/{database_id}/documents/...'")
-	parameters := tools.Parameters{parentPathParameter}
+	minLimit, maxLimitV := 1, maxLimit
+	limitParameter := tools.NewIntParameterWithRange(limitKey, fmt.Sprintf("The maximum number of collections to return. Defaults to %d.", defaultLimit), &minLimit, &maxLimitV)
+	defaultLimitV := defaultLimit
+	limitParameter.Default = &defaultLimitV
+	parameters := tools.Parameters{
+		parentPathParameter,
+		tools.NewStringParameterWithDefault(prefixKey, emptyString, "Only list collections whose ids begin with this prefix."),
+		limitParameter,
+		tools.NewStringParameterWithDefault(pageTokenKey, emptyString, "The nextPageToken returned by a previous call with the same parentPath and prefix, to list the next collections."),
+		tools.NewBooleanParameterWithDefault(checkSubcollectionsKey, false, "Whether to check if the documents of each collection have subcollections, which takes a few more requests per collection."),
+	}
 
 	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, parameters)
 
@@ -113,33 +147,96 @@ type Tool struct {
 	mcpManifest tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	mapParams := params.AsMap()
+// pageToken is the position of a listing after the collections returned by
+// an invocation. It is returned base64 encoded, as an opaque string.
+type pageToken struct {
+	ParentPath string `json:"parentPath,omitempty"`
+	Prefix     string `json:"prefix,omitempty"`
+	// Token is the Firestore page token of the fetch holding the next
+	// collection, and Offset its index in that fetch.
+	Token  string `json:"token,omitempty"`
+	Offset int    `json:"offset,omitempty"`
+}
 
-	var collectionRefs []*firestoreapi.CollectionRef
-	var err error
+func (p pageToken) encode() string {
+	b, _ := json.Marshal(p)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
 
-	// Check if parentPath is provided
-	parentPath, hasParent := mapParams[parentPathKey].(string)
+// decodePageToken decodes a page token returned for parentPath and prefix.
+func decodePageToken(s, parentPath, prefix string) (pageToken, error) {
+	if s == "" {
+		return pageToken{ParentPath: parentPath, Prefix: prefix}, nil
+	}
+	var p pageToken
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return p, fmt.Errorf("invalid '%s' parameter: not a page token", pageTokenKey)
+	}
+	if err := json.Unmarshal(b, &p); err != nil || p.Offset < 0 || (p.Token == "" && p.Offset == 0) {
+		return p, fmt.Errorf("invalid '%s' parameter: not a page token", pageTokenKey)
+	}
+	if p.ParentPath != parentPath || p.Prefix != prefix {
+		return p, fmt.Errorf("invalid '%s' parameter: it was returned for another '%s' or '%s'", pageTokenKey, parentPathKey, prefixKey)
+	}
+	return p, nil
+}
 
-	if hasParent && parentPath != "" {
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	mapParams := inv.Params.AsMap()
+	parentPath, _ := mapParams[parentPathKey].(string)
+	prefix, _ := mapParams[prefixKey].(string)
+	rawToken, _ := mapParams[pageTokenKey].(string)
+	checkSubcollections, _ := mapParams[checkSubcollectionsKey].(bool)
+	limit, ok := mapParams[limitKey].(int)
+	if !ok {
+		return nil, fmt.Errorf("invalid '%s' parameter; expected an integer", limitKey)
+	}
+
+	list := t.Client.Collections
+	if parentPath != "" {
 		// Validate parent document path
 		if err := util.ValidateDocumentPath(parentPath); err != nil {
 			return nil, fmt.Errorf("invalid parent document path: %w", err)
 		}
+		list = t.Client.Doc(parentPath).Collections
+	}
+	token, err := decodePageToken(rawToken, parentPath, prefix)
+	if err != nil {
+		return nil, &tools.StatusError{Err: err, Code: http.StatusBadRequest}
+	}
 
-		// List subcollections of the specified document
-		docRef := t.Client.Doc(parentPath)
-		collectionRefs, err = docRef.Collections(ctx).GetAll()
-		if err != nil {
-			return nil, fmt.Errorf("failed to list subcollections of document %q: %w", parentPath, err)
-		}
-	} else {
-		// List root collections
-		collectionRefs, err = t.Client.Collections(ctx).GetAll()
-		if err != nil {
+	// Firestore cannot filter collection ids, so the ones without the prefix
+	// are skipped, fetching more until limit collections are listed.
+	var collectionRefs []*firestoreapi.CollectionRef
+	var next *pageToken
+	for {
+		var refs []*firestoreapi.CollectionRef
+		nextToken, err := iterator.NewPager(list(ctx), fetchSize, token.Token).NextPage(&refs)
+		if err != nil && err != iterator.Done {
+			if parentPath != "" {
+				return nil, fmt.Errorf("failed to list subcollections of document %q: %w", parentPath, err)
+			}
 			return nil, fmt.Errorf("failed to list root collections: %w", err)
 		}
+		for i := token.Offset; i < len(refs); i++ {
+			if !strings.HasPrefix(refs[i].ID, prefix) {
+				continue
+			}
+			if len(collectionRefs) == limit {
+				next = &pageToken{ParentPath: parentPath, Prefix: prefix, Token: token.Token, Offset: i}
+				break
+			}
+			collectionRefs = append(collectionRefs, refs[i])
+		}
+		if next != nil || nextToken == "" {
+			break
+		}
+		if len(collectionRefs) == limit {
+			next = &pageToken{ParentPath: parentPath, Prefix: prefix, Token: nextToken}
+			break
+		}
+		token.Token, token.Offset = nextToken, 0
 	}
 
 	// Convert collection references to response data
@@ -154,10 +251,53 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 			collData["parent"] = collRef.Parent.Path
 		}
 
+		if checkSubcollections {
+			has, err := hasSubcollections(ctx, collRef)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check subcollections of collection %q: %w", collRef.Path, err)
+			}
+			if has != nil {
+				collData["hasSubcollections"] = *has
+			}
+		}
+
 		results[i] = collData
 	}
 
-	return results, nil
+	response := map[string]any{"collections": results}
+	if next != nil {
+		response["nextPageToken"] = next.encode()
+	}
+	return response, nil
+}
+
+// hasSubcollections reports whether any of the first subcollectionSample
+// documents of a collection has subcollections. It returns nil if none has,
+// but the collection has more documents.
+func hasSubcollections(ctx context.Context, collRef *firestoreapi.CollectionRef) (*bool, error) {
+	// the documents listed include the missing ones, which only exist as the
+	// parents of subcollections
+	var docRefs []*firestoreapi.DocumentRef
+	nextToken, err := iterator.NewPager(collRef.DocumentRefs(ctx), subcollectionSample, "").NextPage(&docRefs)
+	if err != nil && err != iterator.Done {
+		return nil, err
+	}
+	for _, docRef := range docRefs {
+		_, err := docRef.Collections(ctx).Next()
+		if err == iterator.Done {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		has := true
+		return &has, nil
+	}
+	if nextToken != "" {
+		return nil, nil
+	}
+	has := false
+	return &has, nil
 }
 
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
@@ -15,12 +15,21 @@
 package firestorelistcollections_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
 	"testing"
 
+	firestoreapi "cloud.google.com/go/firestore"
 	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	firestoreds "github.com/googleapis/genai-toolbox/internal/sources/firestore"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/testutils/fakefirestore"
+	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/tools/firestore/firestorelistcollections"
 )
 
@@ -154,3 +163,194 @@ func TestParseFromYamlMultipleTools(t *testing.T) {
 		t.Fatalf("incorrect parse: diff %v", diff)
 	}
 }
+
+// initTool initializes a tool listing the collections of a fake Firestore
+// holding documents at paths.
+func initTool(t *testing.T, paths ...string) tools.Tool {
+	t.Helper()
+	fake := fakefirestore.NewServer(t)
+	for _, path := range paths {
+		fake.AddDocument(path)
+	}
+	t.Setenv("FIRESTORE_EMULATOR_HOST", fake.Host())
+	client, err := firestoreapi.NewClient(context.Background(), "my-project")
+	if err != nil {
+		t.Fatalf("unable to create client: %s", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	cfg := firestorelistcollections.Config{Name: "list", Kind: "firestore-list-collections", Source: "my-firestore", Description: "list collections"}
+	tool, err := cfg.Initialize(map[string]sources.Source{"my-firestore": &firestoreds.Source{Client: client}})
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	return tool
+}
+
+// invoke invokes tool with params, returning the ids of the collections
+// listed, their hasSubcollections, and the next page token.
+func invoke(t *testing.T, tool tools.Tool, params map[string]any) ([]string, map[string]any, string, error) {
+	t.Helper()
+	parsed, err := tool.ParseParams(params, nil)
+	if err != nil {
+		t.Fatalf("unable to parse params: %s", err)
+	}
+	got, err := tool.Invoke(context.Background(), tools.Invocation{Params: parsed})
+	if err != nil {
+		return nil, nil, "", err
+	}
+	result := got.(map[string]any)
+	ids := []string{}
+	subcollections := map[string]any{}
+	for _, c := range result["collections"].([]any) {
+		c := c.(map[string]any)
+		ids = append(ids, c["id"].(string))
+		if has, ok := c["hasSubcollections"]; ok {
+			subcollections[c["id"].(string)] = has
+		}
+	}
+	next, _ := result["nextPageToken"].(string)
+	return ids, subcollections, next, nil
+}
+
+func TestInvokeFirestoreListCollections(t *testing.T) {
+	var paths, all []string
+	for i := range 650 {
+		id := fmt.Sprintf("c%03d", i)
+		paths = append(paths, id+"/doc")
+		all = append(all, id)
+	}
+	paths = append(paths, "tenant_a/doc", "tenant_b/doc", "users/alice")
+	all = append(all, "tenant_a", "tenant_b", "users")
+	tool := initTool(t, paths...)
+
+	tcs := []struct {
+		desc      string
+		params    map[string]any
+		wantPages [][]string
+	}{
+		{
+			desc:      "default limit",
+			params:    map[string]any{"prefix": "t"},
+			wantPages: [][]string{{"tenant_a", "tenant_b"}},
+		},
+		{
+			desc:      "prefix",
+			params:    map[string]any{"prefix": "c1", "limit": 40},
+			wantPages: [][]string{all[100:140], all[140:180], all[180:200]},
+		},
+		{
+			desc:      "pages across fetches",
+			params:    map[string]any{"limit": 250},
+			wantPages: [][]string{all[:250], all[250:500], all[500:]},
+		},
+		{
+			desc:      "limit at the end",
+			params:    map[string]any{"prefix": "tenant", "limit": 2},
+			wantPages: [][]string{{"tenant_a", "tenant_b"}},
+		},
+		{
+			desc:      "no match",
+			params:    map[string]any{"prefix": "orders"},
+			wantPages: [][]string{{}},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			params := tc.params
+			for i, want := range tc.wantPages {
+				ids, _, next, err := invoke(t, tool, params)
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				if diff := cmp.Diff(want, ids); diff != "" {
+					t.Fatalf("incorrect page %d: diff %v", i, diff)
+				}
+				if last := i == len(tc.wantPages)-1; last != (next == "") {
+					t.Fatalf("unexpected next page token %q on page %d of %d", next, i, len(tc.wantPages))
+				}
+				// the token is stable across invocations
+				if _, _, again, _ := invoke(t, tool, params); again != next {
+					t.Fatalf("unstable next page token: got %q, then %q", next, again)
+				}
+				params = map[string]any{"pageToken": next}
+				for k, v := range tc.params {
+					params[k] = v
+				}
+			}
+		})
+	}
+}
+
+func TestInvokeFirestoreListSubcollections(t *testing.T) {
+	paths := []string{
+		"users/alice/orders/o1",
+		"users/alice/orders/o2/items/i1",
+		"users/alice/profile/main",
+		"users/bob",
+	}
+	// documents beyond the sample of each collection are not checked
+	for i := range 11 {
+		paths = append(paths, fmt.Sprintf("users/alice/events/e%02d", i))
+	}
+	paths = append(paths, "users/alice/events/e99/details/d1")
+	tool := initTool(t, paths...)
+
+	ids, subcollections, next, err := invoke(t, tool, map[string]any{"parentPath": "users/alice", "checkSubcollections": true})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff([]string{"events", "orders", "profile"}, ids); diff != "" {
+		t.Fatalf("incorrect collections: diff %v", diff)
+	}
+	if diff := cmp.Diff(map[string]any{"orders": true, "profile": false}, subcollections); diff != "" {
+		t.Fatalf("incorrect subcollections: diff %v", diff)
+	}
+	if next != "" {
+		t.Fatalf("unexpected next page token %q", next)
+	}
+
+	// subcollections are not checked unless asked
+	_, subcollections, _, err = invoke(t, tool, map[string]any{"parentPath": "users/alice"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(subcollections) != 0 {
+		t.Fatalf("unexpected subcollections: %v", subcollections)
+	}
+
+	ids, _, _, err = invoke(t, tool, map[string]any{"parentPath": "users/bob"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(ids) != 0 {
+		t.Fatalf("unexpected collections: %v", ids)
+	}
+}
+
+func TestInvokeFirestoreListCollectionsInvalidToken(t *testing.T) {
+	tool := initTool(t, "a/doc", "b/doc", "c/doc")
+	_, _, next, err := invoke(t, tool, map[string]any{"limit": 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	tcs := []struct {
+		desc   string
+		params map[string]any
+	}{
+		{desc: "not base64", params: map[string]any{"limit": 1, "pageToken": "not a token!"}},
+		{desc: "not a page token", params: map[string]any{"limit": 1, "pageToken": "e30"}},
+		{desc: "another prefix", params: map[string]any{"limit": 1, "prefix": "b", "pageToken": next}},
+		{desc: "another parent", params: map[string]any{"limit": 1, "parentPath": "a/doc", "pageToken": next}},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, _, _, err := invoke(t, tool, tc.params)
+			var statusErr *tools.StatusError
+			if !errors.As(err, &statusErr) || statusErr.Code != http.StatusBadRequest {
+				t.Fatalf("expected a bad request error, got %v", err)
+			}
+		})
+	}
+}
@@ -264,9 +264,9 @@ type QueryResponse struct {
 }
 
 // Invoke executes the Firestore query based on the provided parameters
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
 	// Parse parameters
-	queryParams, err := t.parseQueryParameters(params)
+	queryParams, err := t.parseQueryParameters(inv.Params)
 	if err != nil {
 		return nil, err
 	}
@@ -152,8 +152,8 @@ type ValidationResult struct {
 	RawIssues       []Issue `json:"rawIssues,omitempty"`
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	mapParams := params.AsMap()
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	mapParams := inv.Params.AsMap()
 
 	// Get source parameter
 	source, ok := mapParams[sourceKey].(string)
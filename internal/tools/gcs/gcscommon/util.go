@@ -0,0 +1,46 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcscommon
+
+import (
+	"fmt"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	storageapi "google.golang.org/api/storage/v1"
+)
+
+const BucketKey = "bucket"
+
+// NewBucketParameter returns the `bucket` parameter of the GCS tools. It is
+// optional if the source has a default bucket.
+func NewBucketParameter(defaultBucket string) tools.Parameter {
+	if defaultBucket == "" {
+		return tools.NewStringParameter(BucketKey, "The name of the Cloud Storage bucket.")
+	}
+	desc := fmt.Sprintf("The name of the Cloud Storage bucket. Defaults to %q.", defaultBucket)
+	return tools.NewStringParameterWithDefault(BucketKey, defaultBucket, desc)
+}
+
+// ObjectMetadata returns the metadata of o that is returned by the GCS tools.
+func ObjectMetadata(o *storageapi.Object) map[string]any {
+	return map[string]any{
+		"bucket":      o.Bucket,
+		"name":        o.Name,
+		"size":        o.Size,
+		"contentType": o.ContentType,
+		"updated":     o.Updated,
+		"generation":  o.Generation,
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsgetobject
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	gcsds "github.com/googleapis/genai-toolbox/internal/sources/gcs"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/gcs/gcscommon"
+	storageapi "google.golang.org/api/storage/v1"
+)
+
+const kind string = "gcs-get-object"
+
+const (
+	nameKey = "name"
+
+	// defaultMaxSize is the default maximum size of an object that can be
+	// read, in bytes.
+	defaultMaxSize = 1 << 20
+)
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	StorageService() *storageapi.Service
+	DefaultBucket() string
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &gcsds.Source{}
+
+var compatibleSources = [...]string{gcsds.SourceKind}
+
+type Config struct {
+	Name         string   `yaml:"name" validate:"required"`
+	Kind         string   `yaml:"kind" validate:"required"`
+	Source       string   `yaml:"source" validate:"required"`
+	Description  string   `yaml:"description" validate:"required"`
+	MaxSize      int64    `yaml:"maxSize"`
+	AuthRequired []string `yaml:"authRequired"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	maxSize := cfg.MaxSize
+	if maxSize < 0 {
+		return nil, fmt.Errorf("maxSize must not be negative, got %d", maxSize)
+	}
+	if maxSize == 0 {
+		maxSize = defaultMaxSize
+	}
+
+	parameters := tools.Parameters{
+		gcscommon.NewBucketParameter(s.DefaultBucket()),
+		tools.NewStringParameter(nameKey, "The name of the object to read."),
+	}
+
+	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, parameters)
+
+	// finish tool setup
+	t := Tool{
+		Name:         cfg.Name,
+		Kind:         kind,
+		Parameters:   parameters,
+		AuthRequired: cfg.AuthRequired,
+		MaxSize:      maxSize,
+		Service:      s.StorageService(),
+		manifest:     tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string           `yaml:"name"`
+	Kind         string           `yaml:"kind"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+	MaxSize      int64            `yaml:"maxSize"`
+
+	Service     *storageapi.Service
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	mapParams := inv.Params.AsMap()
+	bucket, ok := mapParams[gcscommon.BucketKey].(string)
+	if !ok || bucket == "" {
+		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected a non-empty string", gcscommon.BucketKey)
+	}
+	name, ok := mapParams[nameKey].(string)
+	if !ok || name == "" {
+		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected a non-empty string", nameKey)
+	}
+
+	obj, err := t.Service.Objects.Get(bucket, name).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object %q of bucket %q: %w", name, bucket, err)
+	}
+	if obj.Size > uint64(t.MaxSize) {
+		return nil, fmt.Errorf("object %q is %d bytes, which exceeds the maximum size of %d bytes", name, obj.Size, t.MaxSize)
+	}
+
+	// read the generation whose size was checked
+	resp, err := t.Service.Objects.Get(bucket, name).Generation(obj.Generation).Context(ctx).Download()
+	if err != nil {
+		return nil, fmt.Errorf("failed to download object %q of bucket %q: %w", name, bucket, err)
+	}
+	defer resp.Body.Close()
+	content, err := io.ReadAll(io.LimitReader(resp.Body, t.MaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object %q of bucket %q: %w", name, bucket, err)
+	}
+	if int64(len(content)) > t.MaxSize {
+		return nil, fmt.Errorf("object %q exceeds the maximum size of %d bytes", name, t.MaxSize)
+	}
+
+	result := gcscommon.ObjectMetadata(obj)
+	if utf8.Valid(content) {
+		result["encoding"] = "text"
+		result["content"] = string(content)
+	} else {
+		result["encoding"] = "base64"
+		result["content"] = base64.StdEncoding.EncodeToString(content)
+	}
+	return result, nil
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return false
+}
@@ -0,0 +1,205 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsgetobject_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/gcs"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/testutils/fakegcs"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/gcs/gcsgetobject"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestParseFromYamlGCSGetObject(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				example_tool:
+					kind: gcs-get-object
+					source: my-gcs-source
+					description: some description
+			`,
+			want: server.ToolConfigs{
+				"example_tool": gcsgetobject.Config{
+					Name:         "example_tool",
+					Kind:         "gcs-get-object",
+					Source:       "my-gcs-source",
+					Description:  "some description",
+					AuthRequired: []string{},
+				},
+			},
+		},
+		{
+			desc: "with max size",
+			in: `
+			tools:
+				example_tool:
+					kind: gcs-get-object
+					source: my-gcs-source
+					description: some description
+					maxSize: 4096
+			`,
+			want: server.ToolConfigs{
+				"example_tool": gcsgetobject.Config{
+					Name:         "example_tool",
+					Kind:         "gcs-get-object",
+					Source:       "my-gcs-source",
+					Description:  "some description",
+					MaxSize:      4096,
+					AuthRequired: []string{},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func initTool(t *testing.T, maxSize int64) tools.Tool {
+	t.Helper()
+	ctx := util.WithUserAgent(context.Background(), "test")
+	src, err := gcs.Config{Name: "my-gcs-source", Kind: gcs.SourceKind, Project: "my-project", Bucket: "my-bucket"}.Initialize(ctx, noop.NewTracerProvider().Tracer("test"))
+	if err != nil {
+		t.Fatalf("unable to initialize source: %s", err)
+	}
+	cfg := gcsgetobject.Config{Name: "get", Kind: "gcs-get-object", Source: "my-gcs-source", Description: "get an object", MaxSize: maxSize}
+	tool, err := cfg.Initialize(map[string]sources.Source{"my-gcs-source": src})
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	return tool
+}
+
+func TestInvokeGCSGetObject(t *testing.T) {
+	fake := fakegcs.NewServer(t)
+	t.Setenv("STORAGE_EMULATOR_HOST", fake.Host())
+	fake.PutObject("my-bucket", "notes/hello.txt", "text/plain", []byte("hello, world"))
+	fake.PutObject("my-bucket", "images/pixel.bin", "application/octet-stream", []byte{0xff, 0x00, 0xfe})
+	fake.PutObject("my-bucket", "big.txt", "text/plain", []byte(strings.Repeat("x", 17)))
+	fake.PutObject("other-bucket", "other.txt", "text/plain", []byte("other"))
+	tool := initTool(t, 16)
+
+	tcs := []struct {
+		desc         string
+		params       map[string]any
+		wantEncoding string
+		wantContent  string
+		wantErr      string
+	}{
+		{
+			desc:         "text object",
+			params:       map[string]any{"name": "notes/hello.txt"},
+			wantEncoding: "text",
+			wantContent:  "hello, world",
+		},
+		{
+			desc:         "binary object",
+			params:       map[string]any{"name": "images/pixel.bin"},
+			wantEncoding: "base64",
+			wantContent:  "/wD+",
+		},
+		{
+			desc:         "other bucket",
+			params:       map[string]any{"bucket": "other-bucket", "name": "other.txt"},
+			wantEncoding: "text",
+			wantContent:  "other",
+		},
+		{
+			desc:    "object above the size cap",
+			params:  map[string]any{"name": "big.txt"},
+			wantErr: `object "big.txt" is 17 bytes, which exceeds the maximum size of 16 bytes`,
+		},
+		{
+			desc:    "missing object",
+			params:  map[string]any{"name": "missing.txt"},
+			wantErr: `failed to get object "missing.txt"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			params, err := tool.ParseParams(tc.params, nil)
+			if err != nil {
+				t.Fatalf("unable to parse params: %s", err)
+			}
+			got, err := tool.Invoke(context.Background(), tools.Invocation{Params: params})
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("got error %v, want one containing %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			result := got.(map[string]any)
+			if result["encoding"] != tc.wantEncoding {
+				t.Errorf("got encoding %v, want %q", result["encoding"], tc.wantEncoding)
+			}
+			if result["content"] != tc.wantContent {
+				t.Errorf("got content %v, want %q", result["content"], tc.wantContent)
+			}
+			if result["name"] != tc.params["name"] {
+				t.Errorf("got name %v, want %v", result["name"], tc.params["name"])
+			}
+		})
+	}
+}
+
+func TestInitializeGCSGetObjectNegativeMaxSize(t *testing.T) {
+	fake := fakegcs.NewServer(t)
+	t.Setenv("STORAGE_EMULATOR_HOST", fake.Host())
+	ctx := util.WithUserAgent(context.Background(), "test")
+	src, err := gcs.Config{Name: "my-gcs-source", Kind: gcs.SourceKind, Project: "my-project"}.Initialize(ctx, noop.NewTracerProvider().Tracer("test"))
+	if err != nil {
+		t.Fatalf("unable to initialize source: %s", err)
+	}
+	cfg := gcsgetobject.Config{Name: "get", Kind: "gcs-get-object", Source: "my-gcs-source", Description: "get an object", MaxSize: -1}
+	if _, err := cfg.Initialize(map[string]sources.Source{"my-gcs-source": src}); err == nil {
+		t.Fatalf("expected an error for a negative maxSize")
+	}
+}
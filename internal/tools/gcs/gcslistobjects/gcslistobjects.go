@@ -0,0 +1,190 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcslistobjects
+
+import (
+	"context"
+	"fmt"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	gcsds "github.com/googleapis/genai-toolbox/internal/sources/gcs"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/gcs/gcscommon"
+	storageapi "google.golang.org/api/storage/v1"
+)
+
+const kind string = "gcs-list-objects"
+
+const (
+	prefixKey     = "prefix"
+	delimiterKey  = "delimiter"
+	maxResultsKey = "maxResults"
+
+	defaultMaxResults = 100
+	maxMaxResults     = 1000
+)
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	StorageService() *storageapi.Service
+	DefaultBucket() string
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &gcsds.Source{}
+
+var compatibleSources = [...]string{gcsds.SourceKind}
+
+type Config struct {
+	Name         string   `yaml:"name" validate:"required"`
+	Kind         string   `yaml:"kind" validate:"required"`
+	Source       string   `yaml:"source" validate:"required"`
+	Description  string   `yaml:"description" validate:"required"`
+	AuthRequired []string `yaml:"authRequired"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	minResults, maxResults := 1, maxMaxResults
+	maxResultsParameter := tools.NewIntParameterWithRange(maxResultsKey, fmt.Sprintf("The maximum number of objects and prefixes to return. Defaults to %d.", defaultMaxResults), &minResults, &maxResults)
+	defaultMaxResultsV := defaultMaxResults
+	maxResultsParameter.Default = &defaultMaxResultsV
+	parameters := tools.Parameters{
+		gcscommon.NewBucketParameter(s.DefaultBucket()),
+		tools.NewStringParameterWithDefault(prefixKey, "", "Only list objects whose names begin with this prefix."),
+		tools.NewStringParameterWithDefault(delimiterKey, "", "Group the objects whose names contain the delimiter after the prefix into prefixes, e.g. '/' to list a single directory level."),
+		maxResultsParameter,
+	}
+
+	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, parameters)
+
+	// finish tool setup
+	t := Tool{
+		Name:         cfg.Name,
+		Kind:         kind,
+		Parameters:   parameters,
+		AuthRequired: cfg.AuthRequired,
+		Service:      s.StorageService(),
+		manifest:     tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string           `yaml:"name"`
+	Kind         string           `yaml:"kind"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+
+	Service     *storageapi.Service
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	mapParams := inv.Params.AsMap()
+	bucket, ok := mapParams[gcscommon.BucketKey].(string)
+	if !ok || bucket == "" {
+		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected a non-empty string", gcscommon.BucketKey)
+	}
+	prefix, _ := mapParams[prefixKey].(string)
+	delimiter, _ := mapParams[delimiterKey].(string)
+	maxResults, ok := mapParams[maxResultsKey].(int)
+	if !ok {
+		return nil, fmt.Errorf("invalid '%s' parameter; expected an integer", maxResultsKey)
+	}
+
+	call := t.Service.Objects.List(bucket).Context(ctx).MaxResults(int64(maxResults))
+	if prefix != "" {
+		call = call.Prefix(prefix)
+	}
+	if delimiter != "" {
+		call = call.Delimiter(delimiter)
+	}
+	resp, err := call.Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objects of bucket %q: %w", bucket, err)
+	}
+
+	objects := make([]any, 0, len(resp.Items))
+	for _, o := range resp.Items {
+		objects = append(objects, gcscommon.ObjectMetadata(o))
+	}
+	prefixes := resp.Prefixes
+	if prefixes == nil {
+		prefixes = []string{}
+	}
+	return map[string]any{
+		"objects":   objects,
+		"prefixes":  prefixes,
+		"truncated": resp.NextPageToken != "",
+	}, nil
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return false
+}
@@ -0,0 +1,187 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcslistobjects_test
+
+import (
+	"context"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/gcs"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/testutils/fakegcs"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/gcs/gcslistobjects"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestParseFromYamlGCSListObjects(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				example_tool:
+					kind: gcs-list-objects
+					source: my-gcs-source
+					description: some description
+			`,
+			want: server.ToolConfigs{
+				"example_tool": gcslistobjects.Config{
+					Name:         "example_tool",
+					Kind:         "gcs-list-objects",
+					Source:       "my-gcs-source",
+					Description:  "some description",
+					AuthRequired: []string{},
+				},
+			},
+		},
+		{
+			desc: "with auth",
+			in: `
+			tools:
+				example_tool:
+					kind: gcs-list-objects
+					source: my-gcs-source
+					description: some description
+					authRequired:
+						- my-google-auth-service
+			`,
+			want: server.ToolConfigs{
+				"example_tool": gcslistobjects.Config{
+					Name:         "example_tool",
+					Kind:         "gcs-list-objects",
+					Source:       "my-gcs-source",
+					Description:  "some description",
+					AuthRequired: []string{"my-google-auth-service"},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func initTool(t *testing.T, bucket string) tools.Tool {
+	t.Helper()
+	ctx := util.WithUserAgent(context.Background(), "test")
+	src, err := gcs.Config{Name: "my-gcs-source", Kind: gcs.SourceKind, Project: "my-project", Bucket: bucket}.Initialize(ctx, noop.NewTracerProvider().Tracer("test"))
+	if err != nil {
+		t.Fatalf("unable to initialize source: %s", err)
+	}
+	cfg := gcslistobjects.Config{Name: "list", Kind: "gcs-list-objects", Source: "my-gcs-source", Description: "list objects"}
+	tool, err := cfg.Initialize(map[string]sources.Source{"my-gcs-source": src})
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	return tool
+}
+
+func TestInvokeGCSListObjects(t *testing.T) {
+	fake := fakegcs.NewServer(t)
+	t.Setenv("STORAGE_EMULATOR_HOST", fake.Host())
+	for _, name := range []string{"a.txt", "docs/b.txt", "docs/c.txt", "docs/sub/d.txt"} {
+		fake.PutObject("my-bucket", name, "text/plain", []byte(name))
+	}
+	tool := initTool(t, "my-bucket")
+
+	tcs := []struct {
+		desc         string
+		params       map[string]any
+		wantNames    []string
+		wantPrefixes []string
+		wantTrunc    bool
+	}{
+		{
+			desc:         "all objects",
+			params:       map[string]any{},
+			wantNames:    []string{"a.txt", "docs/b.txt", "docs/c.txt", "docs/sub/d.txt"},
+			wantPrefixes: []string{},
+		},
+		{
+			desc:         "prefix and delimiter",
+			params:       map[string]any{"prefix": "docs/", "delimiter": "/"},
+			wantNames:    []string{"docs/b.txt", "docs/c.txt"},
+			wantPrefixes: []string{"docs/sub/"},
+		},
+		{
+			desc:         "max results",
+			params:       map[string]any{"maxResults": 2},
+			wantNames:    []string{"a.txt", "docs/b.txt"},
+			wantPrefixes: []string{},
+			wantTrunc:    true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			params, err := tool.ParseParams(tc.params, nil)
+			if err != nil {
+				t.Fatalf("unable to parse params: %s", err)
+			}
+			got, err := tool.Invoke(context.Background(), tools.Invocation{Params: params})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			result := got.(map[string]any)
+			names := []string{}
+			for _, o := range result["objects"].([]any) {
+				names = append(names, o.(map[string]any)["name"].(string))
+			}
+			if diff := cmp.Diff(tc.wantNames, names); diff != "" {
+				t.Errorf("incorrect objects: diff %v", diff)
+			}
+			if diff := cmp.Diff(tc.wantPrefixes, result["prefixes"]); diff != "" {
+				t.Errorf("incorrect prefixes: diff %v", diff)
+			}
+			if result["truncated"] != tc.wantTrunc {
+				t.Errorf("got truncated %v, want %v", result["truncated"], tc.wantTrunc)
+			}
+		})
+	}
+}
+
+func TestInvokeGCSListObjectsRequiresBucket(t *testing.T) {
+	fake := fakegcs.NewServer(t)
+	t.Setenv("STORAGE_EMULATOR_HOST", fake.Host())
+	tool := initTool(t, "")
+	if _, err := tool.ParseParams(map[string]any{}, nil); err == nil {
+		t.Fatalf("expected an error for a missing bucket")
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsputobject
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	gcsds "github.com/googleapis/genai-toolbox/internal/sources/gcs"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/gcs/gcscommon"
+	"google.golang.org/api/googleapi"
+	storageapi "google.golang.org/api/storage/v1"
+)
+
+const kind string = "gcs-put-object"
+
+const (
+	nameKey        = "name"
+	contentKey     = "content"
+	contentTypeKey = "contentType"
+
+	defaultContentType = "text/plain; charset=utf-8"
+)
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	StorageService() *storageapi.Service
+	DefaultBucket() string
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &gcsds.Source{}
+
+var compatibleSources = [...]string{gcsds.SourceKind}
+
+type Config struct {
+	Name        string `yaml:"name" validate:"required"`
+	Kind        string `yaml:"kind" validate:"required"`
+	Source      string `yaml:"source" validate:"required"`
+	Description string `yaml:"description" validate:"required"`
+	// AllowedPrefixes are the prefixes of the names of the objects that can
+	// be written.
+	AllowedPrefixes []string `yaml:"allowedPrefixes" validate:"required,min=1,dive,required"`
+	AuthRequired    []string `yaml:"authRequired"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	parameters := tools.Parameters{
+		gcscommon.NewBucketParameter(s.DefaultBucket()),
+		tools.NewStringParameter(nameKey, fmt.Sprintf("The name of the object to write. It must begin with one of %q.", cfg.AllowedPrefixes)),
+		tools.NewStringParameter(contentKey, "The content of the object."),
+		tools.NewStringParameterWithDefault(contentTypeKey, defaultContentType, "The content type of the object."),
+	}
+
+	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, parameters)
+
+	// finish tool setup
+	t := Tool{
+		Name:            cfg.Name,
+		Kind:            kind,
+		Parameters:      parameters,
+		AuthRequired:    cfg.AuthRequired,
+		AllowedPrefixes: cfg.AllowedPrefixes,
+		Service:         s.StorageService(),
+		manifest:        tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:     mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name            string           `yaml:"name"`
+	Kind            string           `yaml:"kind"`
+	AuthRequired    []string         `yaml:"authRequired"`
+	Parameters      tools.Parameters `yaml:"parameters"`
+	AllowedPrefixes []string         `yaml:"allowedPrefixes"`
+
+	Service     *storageapi.Service
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	mapParams := inv.Params.AsMap()
+	bucket, ok := mapParams[gcscommon.BucketKey].(string)
+	if !ok || bucket == "" {
+		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected a non-empty string", gcscommon.BucketKey)
+	}
+	name, ok := mapParams[nameKey].(string)
+	if !ok || name == "" {
+		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected a non-empty string", nameKey)
+	}
+	if !t.isAllowed(name) {
+		return nil, fmt.Errorf("object %q cannot be written: its name must begin with one of %q", name, t.AllowedPrefixes)
+	}
+	content, ok := mapParams[contentKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected a string", contentKey)
+	}
+	contentType, _ := mapParams[contentTypeKey].(string)
+
+	obj := &storageapi.Object{Name: name, ContentType: contentType}
+	written, err := t.Service.Objects.Insert(bucket, obj).Media(strings.NewReader(content), googleapi.ContentType(contentType)).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to write object %q of bucket %q: %w", name, bucket, err)
+	}
+	return gcscommon.ObjectMetadata(written), nil
+}
+
+// isAllowed reports whether the object name begins with one of the allowed
+// prefixes. Names with `..` segments are never allowed, so that a name that
+// looks like a path cannot appear to escape its prefix.
+func (t Tool) isAllowed(name string) bool {
+	for _, segment := range strings.Split(name, "/") {
+		if segment == ".." {
+			return false
+		}
+	}
+	for _, prefix := range t.AllowedPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return false
+}
@@ -0,0 +1,218 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsputobject_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/gcs"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/testutils/fakegcs"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/gcs/gcsputobject"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestParseFromYamlGCSPutObject(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				example_tool:
+					kind: gcs-put-object
+					source: my-gcs-source
+					description: some description
+					allowedPrefixes:
+						- uploads/
+						- reports/
+			`,
+			want: server.ToolConfigs{
+				"example_tool": gcsputobject.Config{
+					Name:            "example_tool",
+					Kind:            "gcs-put-object",
+					Source:          "my-gcs-source",
+					Description:     "some description",
+					AllowedPrefixes: []string{"uploads/", "reports/"},
+					AuthRequired:    []string{},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestFailParseFromYamlGCSPutObject(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+	}{
+		{
+			desc: "missing allowed prefixes",
+			in: `
+			tools:
+				example_tool:
+					kind: gcs-put-object
+					source: my-gcs-source
+					description: some description
+			`,
+		},
+		{
+			desc: "empty allowed prefix",
+			in: `
+			tools:
+				example_tool:
+					kind: gcs-put-object
+					source: my-gcs-source
+					description: some description
+					allowedPrefixes:
+						- ""
+			`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err == nil || !strings.Contains(err.Error(), "AllowedPrefixes") {
+				t.Fatalf("expected an allowedPrefixes error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestInvokeGCSPutObject(t *testing.T) {
+	fake := fakegcs.NewServer(t)
+	t.Setenv("STORAGE_EMULATOR_HOST", fake.Host())
+	ctx := util.WithUserAgent(context.Background(), "test")
+	src, err := gcs.Config{Name: "my-gcs-source", Kind: gcs.SourceKind, Project: "my-project", Bucket: "my-bucket"}.Initialize(ctx, noop.NewTracerProvider().Tracer("test"))
+	if err != nil {
+		t.Fatalf("unable to initialize source: %s", err)
+	}
+	cfg := gcsputobject.Config{
+		Name:            "put",
+		Kind:            "gcs-put-object",
+		Source:          "my-gcs-source",
+		Description:     "put an object",
+		AllowedPrefixes: []string{"uploads/", "reports/2025-"},
+	}
+	tool, err := cfg.Initialize(map[string]sources.Source{"my-gcs-source": src})
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+
+	tcs := []struct {
+		desc            string
+		params          map[string]any
+		wantContentType string
+		wantErr         string
+	}{
+		{
+			desc:            "allowed prefix",
+			params:          map[string]any{"name": "uploads/notes.txt", "content": "hello"},
+			wantContentType: "text/plain; charset=utf-8",
+		},
+		{
+			desc:            "second allowed prefix with content type",
+			params:          map[string]any{"name": "reports/2025-01.json", "content": `{"a": 1}`, "contentType": "application/json"},
+			wantContentType: "application/json",
+		},
+		{
+			desc:    "disallowed prefix",
+			params:  map[string]any{"name": "secrets/key.txt", "content": "hello"},
+			wantErr: `object "secrets/key.txt" cannot be written: its name must begin with one of ["uploads/" "reports/2025-"]`,
+		},
+		{
+			desc:    "partial prefix",
+			params:  map[string]any{"name": "uploads", "content": "hello"},
+			wantErr: "cannot be written",
+		},
+		{
+			desc:    "dot dot segment",
+			params:  map[string]any{"name": "uploads/../secrets/key.txt", "content": "hello"},
+			wantErr: "cannot be written",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			params, err := tool.ParseParams(tc.params, nil)
+			if err != nil {
+				t.Fatalf("unable to parse params: %s", err)
+			}
+			name := tc.params["name"].(string)
+			got, err := tool.Invoke(context.Background(), tools.Invocation{Params: params})
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("got error %v, want one containing %q", err, tc.wantErr)
+				}
+				if _, _, ok := fake.Object("my-bucket", name); ok {
+					t.Fatalf("object %q was written", name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got.(map[string]any)["name"] != name {
+				t.Errorf("got name %v, want %q", got.(map[string]any)["name"], name)
+			}
+			data, contentType, ok := fake.Object("my-bucket", name)
+			if !ok {
+				t.Fatalf("object %q was not written", name)
+			}
+			if string(data) != tc.params["content"] {
+				t.Errorf("got content %q, want %q", data, tc.params["content"])
+			}
+			if contentType != tc.wantContentType {
+				t.Errorf("got content type %q, want %q", contentType, tc.wantContentType)
+			}
+		})
+	}
+}
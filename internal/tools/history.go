@@ -0,0 +1,192 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HistoryEntry is the record of an invocation of a tool with a statement in
+// a SessionHistory.
+type HistoryEntry struct {
+	// ID identifies the entry within its session.
+	ID   int    `json:"id"`
+	Tool string `json:"tool"`
+	// Fingerprint identifies the resolved statement of the invocation, so
+	// that invocations running the same statement can be recognized.
+	Fingerprint string `json:"fingerprint"`
+	// Params are the arguments of the invocation, with the values of
+	// sensitive parameters redacted.
+	Params   map[string]any `json:"params"`
+	Time     time.Time      `json:"timestamp"`
+	RowCount int            `json:"rowCount"`
+	// sensitive lists the parameters whose values are redacted.
+	sensitive []string
+}
+
+// Sensitive returns whether the value of the parameter name is redacted.
+func (e HistoryEntry) Sensitive(name string) bool {
+	for _, s := range e.sensitive {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// SessionHistory keeps the most recent invocations of the tools with a
+// statement made by a session, in a ring buffer.
+type SessionHistory struct {
+	// lookup returns the tool with the given name, so that entries can be
+	// rerun.
+	lookup func(name string) (Tool, bool)
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries []HistoryEntry
+	// next is the index of entries that the next entry is written to.
+	next   int
+	nextID int
+}
+
+// NewSessionHistory returns a SessionHistory keeping at most size entries,
+// whose tools are looked up with lookup.
+func NewSessionHistory(size int, lookup func(name string) (Tool, bool)) *SessionHistory {
+	return &SessionHistory{
+		lookup:  lookup,
+		now:     time.Now,
+		entries: make([]HistoryEntry, 0, size),
+		nextID:  1,
+	}
+}
+
+// Record records an invocation of the tool name that returned rows rows. It
+// does nothing unless the tool has a statement.
+func (h *SessionHistory) Record(name string, tool Tool, params ParamValues, rows int) {
+	renderer, ok := As[Renderer](tool)
+	if !ok {
+		return
+	}
+	rendered, err := renderer.Render(params)
+	if err != nil {
+		return
+	}
+	entry := HistoryEntry{
+		Tool:        name,
+		Fingerprint: fingerprint(rendered.Statement),
+		Params:      make(map[string]any, len(params)),
+		RowCount:    rows,
+	}
+	for _, p := range params.Redacted() {
+		if p.Name == IdempotencyKeyParameter || p.Name == IfNoneMatchParameter {
+			// a rerun must not replay the recorded invocation, nor return
+			// it unchanged
+			continue
+		}
+		entry.Params[p.Name] = p.Value
+		if p.Sensitive {
+			entry.sensitive = append(entry.sensitive, p.Name)
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	entry.ID = h.nextID
+	entry.Time = h.now()
+	h.nextID++
+	if len(h.entries) < cap(h.entries) {
+		h.entries = append(h.entries, entry)
+		return
+	}
+	if len(h.entries) == 0 {
+		return
+	}
+	h.entries[h.next] = entry
+	h.next = (h.next + 1) % len(h.entries)
+}
+
+// Entries returns at most limit of the recorded entries, newest first. If
+// limit is not positive, all of them are returned.
+func (h *SessionHistory) Entries(limit int) []HistoryEntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	n := len(h.entries)
+	if limit <= 0 || limit > n {
+		limit = n
+	}
+	entries := make([]HistoryEntry, 0, limit)
+	for i := 0; i < limit; i++ {
+		// the newest entry is right before next
+		entries = append(entries, h.entries[((h.next-1-i)%n+n)%n])
+	}
+	return entries
+}
+
+// Entry returns the entry with the given id, if it is still recorded.
+func (h *SessionHistory) Entry(id int) (HistoryEntry, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, e := range h.entries {
+		if e.ID == id {
+			return e, true
+		}
+	}
+	return HistoryEntry{}, false
+}
+
+// Tool returns the tool with the given name.
+func (h *SessionHistory) Tool(name string) (Tool, bool) {
+	if h.lookup == nil {
+		return nil, false
+	}
+	return h.lookup(name)
+}
+
+// fingerprint returns a short hash of statement, ignoring differences in
+// whitespace.
+func fingerprint(statement string) string {
+	sum := sha256.Sum256([]byte(strings.Join(strings.Fields(statement), " ")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// sessionHistoryKey is the key used to store the SessionHistory within
+// context.
+type sessionHistoryKey struct{}
+
+// WithSessionHistory adds the history of the session of an invocation into
+// the context.
+func WithSessionHistory(ctx context.Context, h *SessionHistory) context.Context {
+	return context.WithValue(ctx, sessionHistoryKey{}, h)
+}
+
+// SessionHistoryFromContext returns the SessionHistory of the context, or nil
+// if the invocation has no session or history is disabled.
+func SessionHistoryFromContext(ctx context.Context) *SessionHistory {
+	h, _ := ctx.Value(sessionHistoryKey{}).(*SessionHistory)
+	return h
+}
+
+// RecordInvocation records an invocation of the tool name that returned rows
+// rows in the SessionHistory of the context, if any.
+func RecordInvocation(ctx context.Context, name string, tool Tool, params ParamValues, rows int) {
+	if h := SessionHistoryFromContext(ctx); h != nil {
+		h.Record(name, tool, params, rows)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// statementTool is a tool running a statement, which returns its arguments.
+type statementTool struct {
+	mockTool
+	statement string
+	params    tools.Parameters
+}
+
+func (t statementTool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.params, data, claims)
+}
+
+func (t statementTool) Invoke(_ context.Context, inv tools.Invocation) (any, error) {
+	return []any{inv.Params.AsMap()}, nil
+}
+
+func (t statementTool) Render(params tools.ParamValues) (tools.RenderedStatement, error) {
+	return tools.NewRenderedStatement(t.statement, params, params), nil
+}
+
+func newStatementTool() statementTool {
+	return statementTool{
+		mockTool:  mockTool{name: "find_user"},
+		statement: "SELECT * FROM users WHERE name = $1 AND password = $2",
+		params: tools.Parameters{
+			tools.NewStringParameter("name", "the name of the user"),
+			newSensitiveStringParameter("password", "the password of the user"),
+		},
+	}
+}
+
+func TestSessionHistoryRecord(t *testing.T) {
+	tool := newStatementTool()
+	h := tools.NewSessionHistory(10, nil)
+	params := tools.ParamValues{
+		{Name: "name", Value: "alice"},
+		{Name: "password", Value: "hunter2", Sensitive: true},
+		{Name: tools.IdempotencyKeyParameter, Value: "k1"},
+	}
+	h.Record("find_user", tool, params, 3)
+	// tools without a statement are not recorded
+	h.Record("mock", mockTool{name: "mock"}, nil, 1)
+
+	entries := h.Entries(0)
+	if len(entries) != 1 {
+		t.Fatalf("unexpected number of entries: got %d, want 1", len(entries))
+	}
+	got := entries[0]
+	if got.ID != 1 || got.Tool != "find_user" || got.RowCount != 3 || got.Time.IsZero() {
+		t.Fatalf("unexpected entry: %+v", got)
+	}
+	want := map[string]any{"name": "alice", "password": tools.Redacted}
+	if diff := cmp.Diff(want, got.Params); diff != "" {
+		t.Fatalf("unexpected params (-want +got):\n%s", diff)
+	}
+	if !got.Sensitive("password") || got.Sensitive("name") {
+		t.Fatalf("unexpected sensitive parameters of entry %+v", got)
+	}
+
+	// the fingerprint ignores whitespace and the values of the parameters
+	other := newStatementTool()
+	other.statement = "SELECT *\n  FROM users WHERE name = $1 AND password = $2"
+	h.Record("find_user", other, tools.ParamValues{{Name: "name", Value: "bob"}}, 0)
+	entries = h.Entries(0)
+	if entries[0].Fingerprint != entries[1].Fingerprint {
+		t.Fatalf("expected equal fingerprints, got %q and %q", entries[0].Fingerprint, entries[1].Fingerprint)
+	}
+	if e, ok := h.Entry(2); !ok || e.Params["name"] != "bob" {
+		t.Fatalf("unexpected entry 2: %+v", e)
+	}
+}
+
+func TestSessionHistoryBound(t *testing.T) {
+	tool := newStatementTool()
+	h := tools.NewSessionHistory(3, nil)
+	for i := 0; i < 5; i++ {
+		h.Record("find_user", tool, tools.ParamValues{{Name: "name", Value: "alice"}}, i)
+	}
+	ids := func(entries []tools.HistoryEntry) []int {
+		var ids []int
+		for _, e := range entries {
+			ids = append(ids, e.ID)
+		}
+		return ids
+	}
+	if diff := cmp.Diff([]int{5, 4, 3}, ids(h.Entries(0))); diff != "" {
+		t.Fatalf("unexpected entries (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]int{5, 4}, ids(h.Entries(2))); diff != "" {
+		t.Fatalf("unexpected limited entries (-want +got):\n%s", diff)
+	}
+	if _, ok := h.Entry(2); ok {
+		t.Fatalf("expected entry 2 to be evicted")
+	}
+}
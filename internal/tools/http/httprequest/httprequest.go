@@ -0,0 +1,321 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httprequest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	httpsrc "github.com/googleapis/genai-toolbox/internal/sources/http"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+const kind string = "http-request"
+
+// maxErrorBodySize is the number of bytes of a non-2xx response body included
+// in the returned error.
+const maxErrorBodySize = 4096
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type Config struct {
+	Name            string            `yaml:"name" validate:"required"`
+	Kind            string            `yaml:"kind" validate:"required"`
+	Source          string            `yaml:"source" validate:"required"`
+	Description     string            `yaml:"description" validate:"required"`
+	AuthRequired    []string          `yaml:"authRequired"`
+	Method          tools.HTTPMethod  `yaml:"method" validate:"required"`
+	Path            string            `yaml:"path" validate:"required"`
+	Headers         map[string]string `yaml:"headers"`
+	QueryParams     map[string]string `yaml:"queryParams"`
+	Body            string            `yaml:"body"`
+	ResponseHeaders []string          `yaml:"responseHeaders"`
+	Parameters      tools.Parameters  `yaml:"parameters"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(*httpsrc.Source)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be `http`", kind)
+	}
+
+	if err := tools.CheckDuplicateParameters(cfg.Parameters); err != nil {
+		return nil, err
+	}
+	declared := make(map[string]bool, len(cfg.Parameters))
+	for _, p := range cfg.Parameters {
+		declared[p.GetName()] = true
+	}
+	for key, name := range cfg.QueryParams {
+		if !declared[name] {
+			return nil, fmt.Errorf("query parameter %q refers to undeclared parameter %q", key, name)
+		}
+	}
+
+	pathTmpl, err := template.New("path").Option("missingkey=error").Parse(cfg.Path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse path template: %w", err)
+	}
+	if _, err := template.New("body").Funcs(template.FuncMap{"json": json.Marshal}).Parse(cfg.Body); err != nil {
+		return nil, fmt.Errorf("unable to parse body template: %w", err)
+	}
+
+	// Tool headers override source headers
+	headers := make(map[string]string)
+	maps.Copy(headers, s.DefaultHeaders)
+	maps.Copy(headers, cfg.Headers)
+	if cfg.Body != "" {
+		if _, ok := headers["Content-Type"]; !ok {
+			headers["Content-Type"] = "application/json"
+		}
+	}
+
+	paramManifest := cfg.Parameters.Manifest()
+	if paramManifest == nil {
+		paramManifest = make([]tools.ParameterManifest, 0)
+	}
+	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, cfg.Parameters)
+
+	// finish tool setup
+	return Tool{
+		Name:               cfg.Name,
+		Kind:               kind,
+		AuthRequired:       cfg.AuthRequired,
+		Parameters:         cfg.Parameters,
+		BaseURL:            s.BaseURL,
+		Method:             cfg.Method,
+		Headers:            headers,
+		DefaultQueryParams: s.QueryParams,
+		QueryParams:        cfg.QueryParams,
+		Body:               cfg.Body,
+		ResponseHeaders:    cfg.ResponseHeaders,
+		Client:             s.Client,
+		pathTmpl:           pathTmpl,
+		manifest:           tools.Manifest{Description: cfg.Description, Parameters: paramManifest, AuthRequired: cfg.AuthRequired},
+		mcpManifest:        mcpManifest,
+	}, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string           `yaml:"name"`
+	Kind         string           `yaml:"kind"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+
+	BaseURL            string
+	Method             tools.HTTPMethod
+	Headers            map[string]string
+	DefaultQueryParams map[string]string
+	QueryParams        map[string]string
+	Body               string
+	ResponseHeaders    []string
+	Client             *http.Client
+
+	pathTmpl    *template.Template
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+// StatusError is returned when the server responds with a non-2xx status
+// code. Body holds at most maxErrorBodySize bytes of the response body.
+type StatusError struct {
+	StatusCode int
+	Body       string
+	Truncated  bool
+}
+
+func (e *StatusError) Error() string {
+	body := e.Body
+	if e.Truncated {
+		body += "... (truncated)"
+	}
+	return fmt.Sprintf("unexpected status code: %d, response body: %s", e.StatusCode, body)
+}
+
+// buildURL substitutes the URL-encoded parameter values into the path
+// template and attaches the query parameters.
+func (t Tool) buildURL(paramsMap map[string]any) (string, error) {
+	escaped := make(map[string]string, len(paramsMap))
+	for k, v := range paramsMap {
+		if v == nil {
+			escaped[k] = ""
+			continue
+		}
+		escaped[k] = url.PathEscape(fmt.Sprint(v))
+	}
+	var path strings.Builder
+	if err := t.pathTmpl.Execute(&path, escaped); err != nil {
+		return "", fmt.Errorf("error populating path: %w", err)
+	}
+
+	u, err := url.Parse(t.BaseURL + path.String())
+	if err != nil {
+		return "", fmt.Errorf("error parsing URL: %w", err)
+	}
+	query := u.Query()
+	for k, v := range t.DefaultQueryParams {
+		query.Add(k, v)
+	}
+	for key, name := range t.QueryParams {
+		switch v := paramsMap[name].(type) {
+		case nil:
+			// optional parameters that are not provided are omitted
+		case []any:
+			for _, item := range v {
+				query.Add(key, fmt.Sprint(item))
+			}
+		default:
+			query.Add(key, fmt.Sprint(v))
+		}
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// isJSON reports whether a Content-Type header value denotes a JSON document.
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
+
+	urlString, err := t.buildURL(paramsMap)
+	if err != nil {
+		return nil, err
+	}
+
+	var body io.Reader
+	if t.Body != "" {
+		b, err := tools.PopulateTemplateWithJSON("HTTPRequestBody", t.Body, paramsMap)
+		if err != nil {
+			return nil, fmt.Errorf("error populating request body: %w", err)
+		}
+		body = strings.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, string(t.Method), urlString, body)
+	if err != nil {
+		return nil, fmt.Errorf("error creating HTTP request: %w", err)
+	}
+	for k, v := range t.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := t.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		b, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize+1))
+		if err != nil {
+			return nil, fmt.Errorf("unable to read response body: %w", err)
+		}
+		statusErr := &StatusError{StatusCode: resp.StatusCode, Body: string(b)}
+		if len(b) > maxErrorBodySize {
+			statusErr.Body = string(b[:maxErrorBodySize])
+			statusErr.Truncated = true
+		}
+		return nil, statusErr
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read response body: %w", err)
+	}
+	var data any = string(b)
+	if isJSON(resp.Header.Get("Content-Type")) && len(b) > 0 {
+		if err := json.Unmarshal(b, &data); err != nil {
+			return nil, fmt.Errorf("unable to parse JSON response: %w", err)
+		}
+	}
+
+	headers := make(map[string]string, len(t.ResponseHeaders))
+	for _, h := range t.ResponseHeaders {
+		if v := resp.Header.Get(h); v != "" {
+			headers[h] = v
+		}
+	}
+	return map[string]any{
+		"status":  resp.StatusCode,
+		"headers": headers,
+		"body":    data,
+	}, nil
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return false
+}
@@ -0,0 +1,379 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httprequest_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	httpsrc "github.com/googleapis/genai-toolbox/internal/sources/http"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/http/httprequest"
+)
+
+func TestParseFromYamlHTTPRequest(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				example_tool:
+					kind: http-request
+					source: my-instance
+					description: some description
+					method: get
+					path: /users/{{.id}}
+					parameters:
+						- name: id
+						  type: string
+						  description: user id
+			`,
+			want: server.ToolConfigs{
+				"example_tool": httprequest.Config{
+					Name:         "example_tool",
+					Kind:         "http-request",
+					Source:       "my-instance",
+					Description:  "some description",
+					AuthRequired: []string{},
+					Method:       "GET",
+					Path:         "/users/{{.id}}",
+					Parameters: []tools.Parameter{
+						tools.NewStringParameter("id", "user id"),
+					},
+				},
+			},
+		},
+		{
+			desc: "advanced example",
+			in: `
+			tools:
+				example_tool:
+					kind: http-request
+					source: my-instance
+					description: some description
+					authRequired:
+						- my-google-auth-service
+					method: POST
+					path: /orders
+					headers:
+						X-Custom: custom
+					queryParams:
+						dry_run: dryRun
+					body: |
+						{"item": {{json .item}}}
+					responseHeaders:
+						- X-Request-Id
+					parameters:
+						- name: item
+						  type: string
+						  description: item name
+						- name: dryRun
+						  type: boolean
+						  description: validate only
+			`,
+			want: server.ToolConfigs{
+				"example_tool": httprequest.Config{
+					Name:            "example_tool",
+					Kind:            "http-request",
+					Source:          "my-instance",
+					Description:     "some description",
+					AuthRequired:    []string{"my-google-auth-service"},
+					Method:          "POST",
+					Path:            "/orders",
+					Headers:         map[string]string{"X-Custom": "custom"},
+					QueryParams:     map[string]string{"dry_run": "dryRun"},
+					Body:            "{\"item\": {{json .item}}}\n",
+					ResponseHeaders: []string{"X-Request-Id"},
+					Parameters: []tools.Parameter{
+						tools.NewStringParameter("item", "item name"),
+						tools.NewBooleanParameter("dryRun", "validate only"),
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestInitializeHTTPRequest(t *testing.T) {
+	srcs := map[string]sources.Source{
+		"my-instance": &httpsrc.Source{BaseURL: "http://example.com", Client: http.DefaultClient},
+	}
+	tcs := []struct {
+		desc string
+		cfg  httprequest.Config
+		err  string
+	}{
+		{
+			desc: "undeclared query parameter",
+			cfg: httprequest.Config{
+				Name:        "example_tool",
+				Source:      "my-instance",
+				Method:      "GET",
+				Path:        "/search",
+				QueryParams: map[string]string{"q": "query"},
+			},
+			err: `query parameter "q" refers to undeclared parameter "query"`,
+		},
+		{
+			desc: "invalid path template",
+			cfg: httprequest.Config{
+				Name:   "example_tool",
+				Source: "my-instance",
+				Method: "GET",
+				Path:   "/users/{{.id",
+			},
+			err: "unable to parse path template",
+		},
+		{
+			desc: "unknown source",
+			cfg: httprequest.Config{
+				Name:   "example_tool",
+				Source: "missing",
+				Method: "GET",
+				Path:   "/",
+			},
+			err: `no source named "missing" configured`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := tc.cfg.Initialize(srcs)
+			if err == nil {
+				t.Fatalf("expect initialization to fail")
+			}
+			if !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("unexpected error: got %q, want substring %q", err, tc.err)
+			}
+		})
+	}
+}
+
+// request is a request received by the test server.
+type request struct {
+	method      string
+	uri         string
+	body        string
+	contentType string
+	auth        string
+}
+
+func newTestTool(t *testing.T, handler http.HandlerFunc, cfg httprequest.Config) (tools.Tool, *request) {
+	t.Helper()
+	got := &request{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		*got = request{
+			method:      r.Method,
+			uri:         r.RequestURI,
+			body:        string(b),
+			contentType: r.Header.Get("Content-Type"),
+			auth:        r.Header.Get("Authorization"),
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(ts.Close)
+
+	src := &httpsrc.Source{
+		Name:           "my-instance",
+		Kind:           httpsrc.SourceKind,
+		BaseURL:        ts.URL,
+		DefaultHeaders: map[string]string{"Authorization": "Bearer token"},
+		Client:         ts.Client(),
+	}
+	cfg.Name = "example_tool"
+	cfg.Kind = "http-request"
+	cfg.Source = "my-instance"
+	tool, err := cfg.Initialize(map[string]sources.Source{"my-instance": src})
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	return tool, got
+}
+
+func invoke(t *testing.T, tool tools.Tool, data map[string]any) (any, error) {
+	t.Helper()
+	params, err := tool.ParseParams(data, nil)
+	if err != nil {
+		t.Fatalf("unable to parse params: %s", err)
+	}
+	return tool.Invoke(context.Background(), tools.Invocation{Params: params})
+}
+
+func TestInvokeGet(t *testing.T) {
+	tool, got := newTestTool(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("X-Request-Id", "req-1")
+		w.Header().Set("X-Other", "ignored")
+		_, _ = io.WriteString(w, `{"id": 7, "name": "alice"}`)
+	}, httprequest.Config{
+		Method:          "GET",
+		Path:            "/users/{{.id}}/files",
+		QueryParams:     map[string]string{"q": "query", "tag": "tags", "limit": "limit"},
+		ResponseHeaders: []string{"X-Request-Id"},
+		Parameters: tools.Parameters{
+			tools.NewStringParameter("id", "user id"),
+			tools.NewStringParameter("query", "search query"),
+			tools.NewArrayParameter("tags", "tags", tools.NewStringParameter("tag", "tag")),
+			tools.NewIntParameterWithRequired("limit", "max results", false),
+		},
+	})
+
+	res, err := invoke(t, tool, map[string]any{
+		"id":    "a b/../c?d",
+		"query": "x&y=z #1",
+		"tags":  []any{"red", "blue"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if got.method != http.MethodGet {
+		t.Errorf("unexpected method: got %q", got.method)
+	}
+	wantURI := "/users/a%20b%2F..%2Fc%3Fd/files?q=x%26y%3Dz+%231&tag=red&tag=blue"
+	if got.uri != wantURI {
+		t.Errorf("unexpected request URI: got %q, want %q", got.uri, wantURI)
+	}
+	if got.auth != "Bearer token" {
+		t.Errorf("source headers were not sent: got %q", got.auth)
+	}
+	want := map[string]any{
+		"status":  200,
+		"headers": map[string]string{"X-Request-Id": "req-1"},
+		"body":    map[string]any{"id": float64(7), "name": "alice"},
+	}
+	if diff := cmp.Diff(want, res); diff != "" {
+		t.Fatalf("unexpected result: diff %v", diff)
+	}
+}
+
+func TestInvokePost(t *testing.T) {
+	tool, got := newTestTool(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, `{"not": "parsed"}`)
+	}, httprequest.Config{
+		Method: "POST",
+		Path:   "/orders",
+		Body:   `{"item": {{json .item}}, "quantity": {{.quantity}}}`,
+		Parameters: tools.Parameters{
+			tools.NewStringParameter("item", "item name"),
+			tools.NewIntParameter("quantity", "quantity"),
+		},
+	})
+
+	res, err := invoke(t, tool, map[string]any{"item": `say "hi"`, "quantity": 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if got.method != http.MethodPost {
+		t.Errorf("unexpected method: got %q", got.method)
+	}
+	if want := `{"item": "say \"hi\"", "quantity": 3}`; got.body != want {
+		t.Errorf("unexpected request body: got %q, want %q", got.body, want)
+	}
+	if got.contentType != "application/json" {
+		t.Errorf("unexpected content type: got %q", got.contentType)
+	}
+	want := map[string]any{
+		"status":  201,
+		"headers": map[string]string{},
+		"body":    `{"not": "parsed"}`,
+	}
+	if diff := cmp.Diff(want, res); diff != "" {
+		t.Fatalf("unexpected result: diff %v", diff)
+	}
+}
+
+func TestInvokeErrorStatus(t *testing.T) {
+	tcs := []struct {
+		desc          string
+		status        int
+		body          string
+		wantBody      string
+		wantTruncated bool
+	}{
+		{
+			desc:     "not found",
+			status:   http.StatusNotFound,
+			body:     `{"error": "no such user"}`,
+			wantBody: `{"error": "no such user"}`,
+		},
+		{
+			desc:          "large body is capped",
+			status:        http.StatusInternalServerError,
+			body:          strings.Repeat("x", 10000),
+			wantBody:      strings.Repeat("x", 4096),
+			wantTruncated: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			tool, _ := newTestTool(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				_, _ = io.WriteString(w, tc.body)
+			}, httprequest.Config{Method: "GET", Path: "/"})
+
+			_, err := invoke(t, tool, map[string]any{})
+			var statusErr *httprequest.StatusError
+			if !errors.As(err, &statusErr) {
+				t.Fatalf("expected a StatusError, got %v", err)
+			}
+			if statusErr.StatusCode != tc.status {
+				t.Errorf("unexpected status code: got %d, want %d", statusErr.StatusCode, tc.status)
+			}
+			if statusErr.Body != tc.wantBody {
+				t.Errorf("unexpected body: got %d bytes, want %d", len(statusErr.Body), len(tc.wantBody))
+			}
+			if statusErr.Truncated != tc.wantTruncated {
+				t.Errorf("unexpected truncation: got %t, want %t", statusErr.Truncated, tc.wantTruncated)
+			}
+		})
+	}
+}
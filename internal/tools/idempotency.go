@@ -0,0 +1,307 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/state"
+)
+
+const (
+	// IdempotencyKeyParameter is the argument holding the idempotency key of
+	// an invocation, for clients that cannot set the Idempotency-Key header,
+	// such as MCP clients.
+	IdempotencyKeyParameter = "_idempotencyKey"
+	// IdempotencyKeysAccepted is the value of the kind-agnostic
+	// `idempotencyKeys` field of tools that deduplicate invocations by key.
+	IdempotencyKeysAccepted = "accepted"
+	// IdempotencyKeysIgnored is the default value of `idempotencyKeys`.
+	IdempotencyKeysIgnored = "ignored"
+)
+
+// Idempotency records the results of invocations by idempotency key.
+type Idempotency struct {
+	Store state.Store
+	// TTL is how long the result of an invocation is replayed.
+	TTL time.Duration
+}
+
+// idempotencyKey is the key used to store the Idempotency within context.
+type idempotencyKey struct{}
+
+// idempotencyKeyKey is the key used to store the idempotency key of an
+// invocation within context.
+type idempotencyKeyKey struct{}
+
+// WithIdempotency adds the Idempotency that records invocations into the
+// context.
+func WithIdempotency(ctx context.Context, idem *Idempotency) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, idem)
+}
+
+// IdempotencyFromContext returns the Idempotency of the context, or nil if
+// there is none.
+func IdempotencyFromContext(ctx context.Context) *Idempotency {
+	idem, _ := ctx.Value(idempotencyKey{}).(*Idempotency)
+	return idem
+}
+
+// WithIdempotencyKey adds the idempotency key of an invocation, such as the
+// value of its Idempotency-Key header, into the context.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyKey{}, key)
+}
+
+// IdempotencyKeyFromContext returns the idempotency key of the context, or ""
+// if there is none.
+func IdempotencyKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyKey{}).(string)
+	return key
+}
+
+// idempotencyRecord is the record of an invocation kept at its idempotency
+// key.
+type idempotencyRecord struct {
+	// Hash is the hash of the tool and arguments of the invocation.
+	Hash string `json:"hash"`
+	// Done is false while the invocation is in progress.
+	Done   bool            `json:"done"`
+	Result json.RawMessage `json:"result,omitempty"`
+}
+
+// IdempotencyConfig wraps a ToolConfig whose kind-agnostic `idempotencyKeys`
+// field is "accepted".
+type IdempotencyConfig struct {
+	ToolConfig
+	Name string
+}
+
+// validate interface
+var _ ToolConfig = IdempotencyConfig{}
+
+func (cfg IdempotencyConfig) Initialize(srcs map[string]sources.Source) (Tool, error) {
+	t, err := cfg.ToolConfig.Initialize(srcs)
+	if err != nil {
+		return nil, err
+	}
+	return IdempotentTool{Tool: t, Name: cfg.Name}, nil
+}
+
+// IdempotentTool deduplicates the invocations of a Tool that share an
+// idempotency key: a repeat with the same arguments returns the recorded
+// result of the first invocation instead of invoking the Tool again, and a
+// repeat with other arguments is rejected.
+type IdempotentTool struct {
+	Tool
+	Name string
+}
+
+func (t IdempotentTool) unwrap() Tool {
+	return t.Tool
+}
+
+func idempotencyKeyParameter() *StringParameter {
+	return NewStringParameterWithRequired(IdempotencyKeyParameter, "Unique key of this invocation. Retrying an invocation with the same key and arguments returns its result instead of invoking the tool again.", false)
+}
+
+func (t IdempotentTool) Manifest() Manifest {
+	m := t.Tool.Manifest()
+	m.Parameters = append(append([]ParameterManifest{}, m.Parameters...), idempotencyKeyParameter().Manifest())
+	return m
+}
+
+func (t IdempotentTool) McpManifest() McpManifest {
+	m := t.Tool.McpManifest()
+	props := make(map[string]ParameterMcpManifest, len(m.InputSchema.Properties)+1)
+	for name, p := range m.InputSchema.Properties {
+		props[name] = p
+	}
+	props[IdempotencyKeyParameter], _ = idempotencyKeyParameter().McpManifest()
+	m.InputSchema.Properties = props
+	return m
+}
+
+func (t IdempotentTool) ParseParams(data map[string]any, claims map[string]map[string]any) (ParamValues, error) {
+	params, err := t.Tool.ParseParams(data, claims)
+	if err != nil {
+		return nil, err
+	}
+	raw, ok := data[IdempotencyKeyParameter]
+	if !ok || raw == nil {
+		return params, nil
+	}
+	key, ok := raw.(string)
+	if !ok {
+		return nil, fmt.Errorf("parameter %q must be a string", IdempotencyKeyParameter)
+	}
+	return append(params, ParamValue{Name: IdempotencyKeyParameter, Value: key}), nil
+}
+
+func (t IdempotentTool) Invoke(ctx context.Context, inv Invocation) (any, error) {
+	key := IdempotencyKeyFromContext(ctx)
+	args := make(ParamValues, 0, len(inv.Params))
+	for _, p := range inv.Params {
+		if p.Name == IdempotencyKeyParameter {
+			key = p.Value.(string)
+			continue
+		}
+		args = append(args, p)
+	}
+	idem := IdempotencyFromContext(ctx)
+	if key == "" || idem == nil {
+		return t.Tool.Invoke(ctx, inv.WithParams(args))
+	}
+
+	hash, err := invocationHash(t.Name, args)
+	if err != nil {
+		return nil, err
+	}
+	// the key of a caller must not replay the result of another one, such as
+	// one fetched with their access token
+	recordKey := "idempotency:" + key
+	if scope := callerScope(inv); scope != "" {
+		recordKey = "idempotency:" + scope + ":" + key
+	}
+	pending, err := json.Marshal(idempotencyRecord{Hash: hash})
+	if err != nil {
+		return nil, err
+	}
+	added, err := idem.Store.Add(ctx, recordKey, pending, idem.TTL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to record idempotency key: %w", err)
+	}
+	if !added {
+		return replay(ctx, idem.Store, key, recordKey, hash)
+	}
+
+	res, raw, err := collectResult(ctx, t.Tool, inv.WithParams(args))
+	if err != nil {
+		// failed invocations are not recorded, so that they can be retried
+		_ = idem.Store.Delete(ctx, recordKey)
+		return nil, err
+	}
+	done, err := json.Marshal(idempotencyRecord{Hash: hash, Done: true, Result: raw})
+	if err == nil {
+		err = idem.Store.Set(ctx, recordKey, done, idem.TTL)
+	}
+	if err != nil {
+		_ = idem.Store.Delete(ctx, recordKey)
+	}
+	return res, nil
+}
+
+// collectResult invokes tool and returns its result, with rows collected so
+// that they can be recorded, along with the result serialized as JSON.
+func collectResult(ctx context.Context, tool Tool, inv Invocation) (any, json.RawMessage, error) {
+	res, err := tool.Invoke(ctx, inv)
+	if err != nil {
+		return nil, nil, err
+	}
+	inner := res
+	metered, isMetered := res.(MeteredResult)
+	if isMetered {
+		inner = metered.Result
+	}
+	if it, ok := inner.(RowIterator); ok {
+		rows, err := CollectRows(it)
+		if err != nil {
+			return nil, nil, err
+		}
+		inner = rows
+		if isMetered {
+			metered.Result = rows
+			res = metered
+		} else {
+			res = rows
+		}
+	}
+	raw, err := json.Marshal(inner)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to record result: %w", err)
+	}
+	return res, raw, nil
+}
+
+// callerScope returns the hash of the identity of the caller of an
+// invocation: the subjects of its verified auth services, or their claims if
+// they have none, and its access token. It is empty for anonymous callers.
+func callerScope(inv Invocation) string {
+	if len(inv.Claims) == 0 && inv.AccessToken == "" {
+		return ""
+	}
+	h := sha256.New()
+	for _, name := range slices.Sorted(maps.Keys(inv.Claims)) {
+		var identity any = inv.Claims[name]
+		if sub, ok := inv.Claims[name]["sub"]; ok {
+			identity = sub
+		}
+		b, _ := json.Marshal(identity)
+		fmt.Fprintf(h, "%s\x00%s\x00", name, b)
+	}
+	fmt.Fprintf(h, "token\x00%s", inv.AccessToken)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// invocationHash returns the hash of the name of a tool and of params.
+func invocationHash(name string, params ParamValues) (string, error) {
+	b, err := json.Marshal(params.AsMap())
+	if err != nil {
+		return "", fmt.Errorf("unable to hash arguments: %w", err)
+	}
+	sum := sha256.Sum256(append([]byte(name+"\x00"), b...))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// replay returns the recorded result of the invocation with the given
+// idempotency key, or a 409 error if it has other arguments or is in
+// progress.
+func replay(ctx context.Context, store state.Store, key, recordKey, hash string) (any, error) {
+	b, ok, err := store.Get(ctx, recordKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read idempotency key: %w", err)
+	}
+	var rec idempotencyRecord
+	if ok {
+		if err := json.Unmarshal(b, &rec); err != nil {
+			return nil, fmt.Errorf("unable to read idempotency key: %w", err)
+		}
+	}
+	switch {
+	case ok && rec.Hash != hash:
+		return nil, &StatusError{Err: fmt.Errorf("idempotency key %q was used by an invocation with other arguments", key), Code: http.StatusConflict}
+	case !ok || !rec.Done:
+		return nil, &StatusError{Err: fmt.Errorf("an invocation with idempotency key %q is in progress", key), Code: http.StatusConflict}
+	}
+	// decode the result so that it has the shape of a live one, such as a
+	// []any of rows
+	var res any
+	d := json.NewDecoder(bytes.NewReader(rec.Result))
+	d.UseNumber()
+	if err := d.Decode(&res); err != nil {
+		return nil, fmt.Errorf("unable to read recorded result: %w", err)
+	}
+	return res, nil
+}
@@ -0,0 +1,272 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/state"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// keyedTool returns the number of times it was invoked, or err.
+type keyedTool struct {
+	mockTool
+	mu    *sync.Mutex
+	calls *int
+	err   error
+	block chan struct{}
+}
+
+func newKeyedTool() keyedTool {
+	return keyedTool{mockTool: mockTool{name: "insert_order"}, mu: &sync.Mutex{}, calls: new(int)}
+}
+
+func (t keyedTool) Invoke(context.Context, tools.Invocation) (any, error) {
+	if t.block != nil {
+		<-t.block
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	*t.calls++
+	if t.err != nil {
+		return nil, t.err
+	}
+	return []any{map[string]any{"call": *t.calls}}, nil
+}
+
+func (t keyedTool) ParseParams(map[string]any, map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParamValues{}, nil
+}
+
+func (t keyedTool) count() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return *t.calls
+}
+
+func idempotencyParams(key string, id int) tools.ParamValues {
+	params := tools.ParamValues{{Name: "id", Value: id}}
+	if key != "" {
+		params = append(params, tools.ParamValue{Name: tools.IdempotencyKeyParameter, Value: key})
+	}
+	return params
+}
+
+func TestIdempotentToolInvoke(t *testing.T) {
+	idem := &tools.Idempotency{Store: state.NewMemory(), TTL: time.Hour}
+	ctx := tools.WithIdempotency(context.Background(), idem)
+
+	t.Run("replay", func(t *testing.T) {
+		inner := newKeyedTool()
+		tool := tools.IdempotentTool{Tool: inner, Name: "insert_order"}
+		first, err := tool.Invoke(ctx, tools.Invocation{Params: idempotencyParams("replay", 1)})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		second, err := tool.Invoke(ctx, tools.Invocation{Params: idempotencyParams("replay", 1)})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if inner.count() != 1 {
+			t.Fatalf("unexpected number of invocations: got %d, want 1", inner.count())
+		}
+		if _, ok := second.([]any); !ok {
+			t.Fatalf("expected the replayed result to be a []any, got %T", second)
+		}
+		want := `[{"call":1}]`
+		for _, res := range []any{first, second} {
+			b, err := json.Marshal(res)
+			if err != nil {
+				t.Fatalf("unable to marshal result: %s", err)
+			}
+			if diff := cmp.Diff(want, string(b)); diff != "" {
+				t.Fatalf("unexpected result (-want +got):\n%s", diff)
+			}
+		}
+	})
+
+	t.Run("key from context", func(t *testing.T) {
+		inner := newKeyedTool()
+		tool := tools.IdempotentTool{Tool: inner, Name: "insert_order"}
+		ctx := tools.WithIdempotencyKey(ctx, "header")
+		for i := 0; i < 2; i++ {
+			if _, err := tool.Invoke(ctx, tools.Invocation{Params: idempotencyParams("", 1)}); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		}
+		if inner.count() != 1 {
+			t.Fatalf("unexpected number of invocations: got %d, want 1", inner.count())
+		}
+	})
+
+	t.Run("callers sharing a key", func(t *testing.T) {
+		inner := newKeyedTool()
+		tool := tools.IdempotentTool{Tool: inner, Name: "insert_order"}
+		callers := []tools.Invocation{
+			{Claims: map[string]map[string]any{"my-google-auth": {"sub": "alice", "email": "alice@example.com"}}},
+			{Claims: map[string]map[string]any{"my-google-auth": {"sub": "bob", "email": "bob@example.com"}}},
+			{AccessToken: "Bearer alice-token"},
+			{AccessToken: "Bearer bob-token"},
+		}
+		results := make([]string, len(callers))
+		for i, caller := range callers {
+			// each caller retries, and only replays its own result
+			for range 2 {
+				res, err := tool.Invoke(ctx, caller.WithParams(idempotencyParams("shared", 1)))
+				if err != nil {
+					t.Fatalf("caller %d: unexpected error: %s", i, err)
+				}
+				b, err := json.Marshal(res)
+				if err != nil {
+					t.Fatalf("unable to marshal result: %s", err)
+				}
+				if results[i] != "" && results[i] != string(b) {
+					t.Fatalf("caller %d: expected the retry to replay %s, got %s", i, results[i], b)
+				}
+				results[i] = string(b)
+			}
+		}
+		if inner.count() != len(callers) {
+			t.Fatalf("unexpected number of invocations: got %d, want %d", inner.count(), len(callers))
+		}
+		want := []string{`[{"call":1}]`, `[{"call":2}]`, `[{"call":3}]`, `[{"call":4}]`}
+		if diff := cmp.Diff(want, results); diff != "" {
+			t.Fatalf("unexpected results (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("without key", func(t *testing.T) {
+		inner := newKeyedTool()
+		tool := tools.IdempotentTool{Tool: inner, Name: "insert_order"}
+		for i := 0; i < 2; i++ {
+			if _, err := tool.Invoke(ctx, tools.Invocation{Params: idempotencyParams("", 1)}); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		}
+		if inner.count() != 2 {
+			t.Fatalf("unexpected number of invocations: got %d, want 2", inner.count())
+		}
+	})
+
+	t.Run("conflicting arguments", func(t *testing.T) {
+		inner := newKeyedTool()
+		tool := tools.IdempotentTool{Tool: inner, Name: "insert_order"}
+		if _, err := tool.Invoke(ctx, tools.Invocation{Params: idempotencyParams("conflict", 1)}); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		_, err := tool.Invoke(ctx, tools.Invocation{Params: idempotencyParams("conflict", 2)})
+		assertConflict(t, err, `idempotency key "conflict" was used by an invocation with other arguments`)
+
+		other := tools.IdempotentTool{Tool: inner, Name: "delete_order"}
+		_, err = other.Invoke(ctx, tools.Invocation{Params: idempotencyParams("conflict", 1)})
+		assertConflict(t, err, `idempotency key "conflict" was used by an invocation with other arguments`)
+		if inner.count() != 1 {
+			t.Fatalf("unexpected number of invocations: got %d, want 1", inner.count())
+		}
+	})
+
+	t.Run("in progress", func(t *testing.T) {
+		inner := newKeyedTool()
+		inner.block = make(chan struct{})
+		tool := tools.IdempotentTool{Tool: inner, Name: "insert_order"}
+		done := make(chan error)
+		go func() {
+			_, err := tool.Invoke(ctx, tools.Invocation{Params: idempotencyParams("progress", 1)})
+			done <- err
+		}()
+		// wait for the first invocation to record the key
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			if _, ok, _ := idem.Store.Get(ctx, "idempotency:progress"); ok {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("idempotency key was not recorded")
+			}
+			time.Sleep(time.Millisecond)
+		}
+		_, err := tool.Invoke(ctx, tools.Invocation{Params: idempotencyParams("progress", 1)})
+		assertConflict(t, err, `an invocation with idempotency key "progress" is in progress`)
+		close(inner.block)
+		if err := <-done; err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	})
+
+	t.Run("errors are not recorded", func(t *testing.T) {
+		inner := newKeyedTool()
+		inner.err = errors.New("connection reset")
+		tool := tools.IdempotentTool{Tool: inner, Name: "insert_order"}
+		for i := 0; i < 2; i++ {
+			if _, err := tool.Invoke(ctx, tools.Invocation{Params: idempotencyParams("failure", 1)}); err == nil {
+				t.Fatalf("expected an error")
+			}
+		}
+		if inner.count() != 2 {
+			t.Fatalf("unexpected number of invocations: got %d, want 2", inner.count())
+		}
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		idem := &tools.Idempotency{Store: state.NewMemory(), TTL: 10 * time.Millisecond}
+		ctx := tools.WithIdempotency(context.Background(), idem)
+		inner := newKeyedTool()
+		tool := tools.IdempotentTool{Tool: inner, Name: "insert_order"}
+		if _, err := tool.Invoke(ctx, tools.Invocation{Params: idempotencyParams("expired", 1)}); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+		if _, err := tool.Invoke(ctx, tools.Invocation{Params: idempotencyParams("expired", 1)}); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if inner.count() != 2 {
+			t.Fatalf("unexpected number of invocations: got %d, want 2", inner.count())
+		}
+	})
+}
+
+func TestIdempotentToolParseParams(t *testing.T) {
+	tool := tools.IdempotentTool{Tool: newKeyedTool(), Name: "insert_order"}
+	if _, err := tool.ParseParams(map[string]any{tools.IdempotencyKeyParameter: 1}, nil); err == nil {
+		t.Fatalf("expected an error for a non-string key")
+	}
+	m := tool.McpManifest()
+	if _, ok := m.InputSchema.Properties[tools.IdempotencyKeyParameter]; !ok {
+		t.Fatalf("expected %q in the MCP manifest", tools.IdempotencyKeyParameter)
+	}
+	params := tool.Manifest().Parameters
+	if len(params) != 1 || params[0].Name != tools.IdempotencyKeyParameter || params[0].Required {
+		t.Fatalf("unexpected manifest parameters: %+v", params)
+	}
+}
+
+func assertConflict(t *testing.T, err error, want string) {
+	t.Helper()
+	var statusErr *tools.StatusError
+	if !errors.As(err, &statusErr) || statusErr.Code != http.StatusConflict {
+		t.Fatalf("expected a 409 StatusError, got %v", err)
+	}
+	if err.Error() != want {
+		t.Fatalf("unexpected error: got %q, want %q", err, want)
+	}
+}
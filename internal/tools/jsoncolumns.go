@@ -0,0 +1,70 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// DecodeJSONColumn returns v, the value of a JSON column read from a driver,
+// as parsed JSON, so that it is not encoded twice in the result of a tool.
+// Drivers return JSON either parsed, or encoded as bytes or a string,
+// depending on the type and codec, so detecting the JSON columns is left to
+// the callers. Arrays of JSON, such as JSONB[], have their elements decoded.
+//
+// Strings are only decoded if they hold an object or an array, since drivers
+// that parse JSON return its strings as they are. Values that are not valid
+// JSON, such as text cast to a JSON column type, are returned as strings with
+// a warning.
+func DecodeJSONColumn(ctx context.Context, column string, v any) any {
+	if arr, ok := v.([]any); ok {
+		decoded := make([]any, len(arr))
+		for i, e := range arr {
+			decoded[i] = decodeJSONScalar(ctx, column, e)
+		}
+		return decoded
+	}
+	return decodeJSONScalar(ctx, column, v)
+}
+
+func decodeJSONScalar(ctx context.Context, column string, v any) any {
+	var data []byte
+	switch v := v.(type) {
+	case []byte:
+		data = v
+	case json.RawMessage:
+		data = v
+	case string:
+		if s := strings.TrimSpace(v); s == "" || (s[0] != '{' && s[0] != '[') {
+			return v
+		}
+		data = []byte(v)
+	default:
+		return v
+	}
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		if logger, lErr := util.LoggerFromContext(ctx); lErr == nil {
+			logger.WarnContext(ctx, fmt.Sprintf("column %q holds invalid JSON, returning it as a string: %s", column, err))
+		}
+		return string(data)
+	}
+	return decoded
+}
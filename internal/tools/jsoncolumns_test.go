@@ -0,0 +1,114 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+func TestDecodeJSONColumn(t *testing.T) {
+	tcs := []struct {
+		name string
+		in   any
+		want any
+	}{
+		{
+			name: "object as bytes",
+			in:   []byte(`{"a": 1, "b": {"c": [true, "x"]}}`),
+			want: map[string]any{"a": float64(1), "b": map[string]any{"c": []any{true, "x"}}},
+		},
+		{
+			name: "object as string",
+			in:   `{"a": "b"}`,
+			want: map[string]any{"a": "b"},
+		},
+		{
+			name: "raw message",
+			in:   json.RawMessage(`[1, 2]`),
+			want: []any{float64(1), float64(2)},
+		},
+		{
+			name: "nested nulls",
+			in:   []byte(`{"a": null, "b": [null, {"c": null}]}`),
+			want: map[string]any{"a": nil, "b": []any{nil, map[string]any{"c": nil}}},
+		},
+		{
+			name: "already parsed",
+			in:   map[string]any{"a": "[1]"},
+			want: map[string]any{"a": "[1]"},
+		},
+		{
+			name: "parsed string",
+			in:   "hello",
+			want: "hello",
+		},
+		{
+			name: "parsed number",
+			in:   float64(1),
+			want: float64(1),
+		},
+		{
+			name: "array of JSON",
+			in:   []any{[]byte(`{"a": 1}`), `[null]`, map[string]any{"b": 2}, nil},
+			want: []any{map[string]any{"a": float64(1)}, []any{nil}, map[string]any{"b": 2}, nil},
+		},
+		{
+			name: "already parsed array",
+			in:   []any{"[1]", []any{"{}"}},
+			want: []any{[]any{float64(1)}, []any{"{}"}},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tools.DecodeJSONColumn(context.Background(), "doc", tc.in)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect decoded value: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestDecodeJSONColumnInvalid(t *testing.T) {
+	var warnings bytes.Buffer
+	logger, err := log.NewStdLogger(&bytes.Buffer{}, &warnings, "warn")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	ctx := util.WithLogger(context.Background(), logger)
+
+	for _, in := range []any{[]byte(`{"a": 1`), `[not json]`} {
+		warnings.Reset()
+		got := tools.DecodeJSONColumn(ctx, "doc", in)
+		want := in
+		if b, ok := in.([]byte); ok {
+			want = string(b)
+		}
+		if got != want {
+			t.Fatalf("unexpected value: got %#v, want %#v", got, want)
+		}
+		if !strings.Contains(warnings.String(), "holds invalid JSON") {
+			t.Fatalf("expected a warning, got %q", warnings.String())
+		}
+	}
+}
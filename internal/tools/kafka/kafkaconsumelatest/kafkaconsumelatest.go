@@ -0,0 +1,398 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaconsumelatest
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	kafkasrc "github.com/googleapis/genai-toolbox/internal/sources/kafka"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+const kind string = "kafka-consume-latest"
+
+const (
+	topicKey          = "topic"
+	maxMessagesKey    = "maxMessages"
+	maxWaitSecondsKey = "maxWaitSeconds"
+	sinceKey          = "since"
+)
+
+const (
+	// defaultMaxMessages is the limit of `maxMessages` used when it is not
+	// configured.
+	defaultMaxMessages = 100
+	// defaultMaxWait is the limit of `maxWaitSeconds` used when `maxWait` is
+	// not configured.
+	defaultMaxWait = 10 * time.Second
+	// defaultMessages is the number of messages read when the `maxMessages`
+	// parameter is not set.
+	defaultMessages = 10
+)
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	KafkaClient() *kgo.Client
+	NewKafkaClient(opts ...kgo.Opt) (*kgo.Client, error)
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &kafkasrc.Source{}
+
+var compatibleSources = [...]string{kafkasrc.SourceKind}
+
+type Config struct {
+	Name        string `yaml:"name" validate:"required"`
+	Kind        string `yaml:"kind" validate:"required"`
+	Source      string `yaml:"source" validate:"required"`
+	Description string `yaml:"description" validate:"required"`
+	// Topics restricts the topics that can be read. Any topic can be read if
+	// it is empty.
+	Topics []string `yaml:"topics"`
+	// MaxMessages is the largest number of messages an invocation can read.
+	MaxMessages int `yaml:"maxMessages"`
+	// MaxWait is the longest an invocation can wait for messages, such as
+	// "10s".
+	MaxWait      string   `yaml:"maxWait"`
+	AuthRequired []string `yaml:"authRequired"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	if cfg.MaxMessages < 0 {
+		return nil, fmt.Errorf("invalid maxMessages %d for tool %q: must not be negative", cfg.MaxMessages, cfg.Name)
+	}
+	maxMessages := cfg.MaxMessages
+	if maxMessages == 0 {
+		maxMessages = defaultMaxMessages
+	}
+	maxWait := defaultMaxWait
+	if cfg.MaxWait != "" {
+		d, err := time.ParseDuration(cfg.MaxWait)
+		if err != nil || d < time.Second {
+			return nil, fmt.Errorf("invalid maxWait %q for tool %q: must be a duration of at least one second such as \"10s\"", cfg.MaxWait, cfg.Name)
+		}
+		maxWait = d
+	}
+
+	topicDesc := "The topic to read from."
+	if len(cfg.Topics) > 0 {
+		topicDesc = fmt.Sprintf("The topic to read from. Must be one of: %s.", strings.Join(cfg.Topics, ", "))
+	}
+	minOne := 1
+	maxMessagesParam := tools.NewIntParameterWithDefault(maxMessagesKey, min(defaultMessages, maxMessages), fmt.Sprintf("The maximum number of messages to read, at most %d. Without `since`, the latest messages are read.", maxMessages))
+	maxMessagesParam.MinValue, maxMessagesParam.MaxValue = &minOne, &maxMessages
+	maxWaitSeconds := int(maxWait / time.Second)
+	maxWaitParam := tools.NewIntParameterWithDefault(maxWaitSecondsKey, maxWaitSeconds, fmt.Sprintf("How long to wait for messages, in seconds, at most %d.", maxWaitSeconds))
+	maxWaitParam.MinValue, maxWaitParam.MaxValue = &minOne, &maxWaitSeconds
+	parameters := tools.Parameters{
+		tools.NewStringParameter(topicKey, topicDesc),
+		maxMessagesParam,
+		maxWaitParam,
+		tools.NewStringParameterWithRequired(sinceKey, "Read the messages produced at or after this time, formatted as RFC 3339 such as 2025-01-02T15:04:05Z, instead of the latest messages.", false),
+	}
+
+	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, parameters)
+
+	// finish tool setup
+	t := Tool{
+		Name:         cfg.Name,
+		Kind:         kind,
+		Parameters:   parameters,
+		AuthRequired: cfg.AuthRequired,
+		Topics:       cfg.Topics,
+		Source:       s,
+		manifest:     tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string           `yaml:"name"`
+	Kind         string           `yaml:"kind"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+
+	Topics      []string
+	Source      compatibleSource
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
+	topic, ok := paramsMap[topicKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected a string", topicKey)
+	}
+	if len(t.Topics) > 0 && !slices.Contains(t.Topics, topic) {
+		return nil, fmt.Errorf("topic %q is not allowed; must be one of %q", topic, t.Topics)
+	}
+	maxMessages, ok := paramsMap[maxMessagesKey].(int)
+	if !ok {
+		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected an integer", maxMessagesKey)
+	}
+	maxWaitSeconds, ok := paramsMap[maxWaitSecondsKey].(int)
+	if !ok {
+		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected an integer", maxWaitSecondsKey)
+	}
+	var since time.Time
+	if raw, ok := paramsMap[sinceKey].(string); ok && raw != "" {
+		var err error
+		if since, err = time.Parse(time.RFC3339, raw); err != nil {
+			return nil, fmt.Errorf("invalid '%s' parameter %q: must be formatted as RFC 3339", sinceKey, raw)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(maxWaitSeconds)*time.Second)
+	defer cancel()
+
+	starts, ends, err := t.offsets(ctx, topic, maxMessages, since)
+	if err != nil {
+		return nil, err
+	}
+	return t.consume(ctx, topic, starts, ends, maxMessages, since.IsZero())
+}
+
+// offsets returns the offset to start reading each partition of topic from,
+// and the end offset of each partition when the invocation started.
+func (t Tool) offsets(ctx context.Context, topic string, maxMessages int, since time.Time) (map[int32]int64, map[int32]int64, error) {
+	// the admin client shares the client of the source, so it must not be
+	// closed
+	adm := kadm.NewClient(t.Source.KafkaClient())
+	listed, err := adm.ListEndOffsets(ctx, topic)
+	if err == nil {
+		err = listed.Error()
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to list the offsets of topic %q: %w", topic, err)
+	}
+	if _, ok := listed[topic]; !ok {
+		return nil, nil, fmt.Errorf("topic %q does not exist", topic)
+	}
+	ends := make(map[int32]int64)
+	listed.Each(func(o kadm.ListedOffset) { ends[o.Partition] = o.Offset })
+
+	if since.IsZero() {
+		listed, err = adm.ListStartOffsets(ctx, topic)
+	} else {
+		listed, err = adm.ListOffsetsAfterMilli(ctx, since.UnixMilli(), topic)
+	}
+	if err == nil {
+		err = listed.Error()
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to list the offsets of topic %q: %w", topic, err)
+	}
+	starts := make(map[int32]int64)
+	listed.Each(func(o kadm.ListedOffset) {
+		start := o.Offset
+		if start < 0 {
+			// no message was produced after since
+			start = ends[o.Partition]
+		}
+		if since.IsZero() {
+			// only read the latest messages of each partition
+			start = max(start, ends[o.Partition]-int64(maxMessages))
+		}
+		starts[o.Partition] = start
+	})
+	return starts, ends, nil
+}
+
+// consume reads messages from the start offsets, without joining a consumer
+// group or committing offsets. It returns once every partition is read up to
+// its end offset or, if no message was available, once a new message
+// arrives. It returns the messages read so far when ctx expires. If latest is
+// true, only the latest maxMessages messages are returned, otherwise the
+// earliest ones.
+func (t Tool) consume(ctx context.Context, topic string, starts, ends map[int32]int64, maxMessages int, latest bool) ([]any, error) {
+	partitions := make(map[int32]kgo.Offset, len(starts))
+	next := make(map[int32]int64, len(starts))
+	available := false
+	for p, start := range starts {
+		partitions[p] = kgo.NewOffset().At(start)
+		next[p] = start
+		if start < ends[p] {
+			available = true
+		}
+	}
+	client, err := t.Source.NewKafkaClient(kgo.ConsumePartitions(map[string]map[int32]kgo.Offset{topic: partitions}))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create Kafka consumer: %w", err)
+	}
+	defer client.Close()
+
+	// caughtUp reports whether every partition is read up to its end offset.
+	caughtUp := func() bool {
+		for p, end := range ends {
+			if next[p] < end {
+				return false
+			}
+		}
+		return true
+	}
+
+	var records []*kgo.Record
+	for latest || len(records) < maxMessages {
+		if available && caughtUp() {
+			break
+		}
+		// the latest messages are only known once every partition is read
+		limit := 0
+		if !latest {
+			limit = maxMessages - len(records)
+		}
+		fetches := client.PollRecords(ctx, limit)
+		if ctx.Err() != nil {
+			break
+		}
+		var fetchErr error
+		fetches.EachError(func(_ string, _ int32, err error) {
+			if fetchErr == nil && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+				fetchErr = err
+			}
+		})
+		if fetchErr != nil {
+			return nil, fmt.Errorf("unable to read from topic %q: %w", topic, fetchErr)
+		}
+		fetches.EachRecord(func(r *kgo.Record) {
+			records = append(records, r)
+			next[r.Partition] = r.Offset + 1
+		})
+		if !available && len(records) > 0 {
+			break
+		}
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		if !records[i].Timestamp.Equal(records[j].Timestamp) {
+			return records[i].Timestamp.Before(records[j].Timestamp)
+		}
+		if records[i].Partition != records[j].Partition {
+			return records[i].Partition < records[j].Partition
+		}
+		return records[i].Offset < records[j].Offset
+	})
+	if len(records) > maxMessages {
+		if latest {
+			records = records[len(records)-maxMessages:]
+		} else {
+			records = records[:maxMessages]
+		}
+	}
+	out := make([]any, 0, len(records))
+	for _, r := range records {
+		out = append(out, message(r))
+	}
+	return out, nil
+}
+
+// message converts a record into the message returned by the tool. Keys,
+// values and header values are returned as strings if they are valid UTF-8,
+// and base64-encoded otherwise.
+func message(r *kgo.Record) map[string]any {
+	m := map[string]any{
+		"partition": r.Partition,
+		"offset":    r.Offset,
+		"timestamp": r.Timestamp.UTC().Format(time.RFC3339Nano),
+		"key":       nil,
+	}
+	if r.Key != nil {
+		m["key"], m["keyEncoding"] = decode(r.Key)
+	}
+	m["value"], m["valueEncoding"] = decode(r.Value)
+	headers := make([]any, 0, len(r.Headers))
+	for _, h := range r.Headers {
+		v, enc := decode(h.Value)
+		headers = append(headers, map[string]any{"key": h.Key, "value": v, "valueEncoding": enc})
+	}
+	m["headers"] = headers
+	return m
+}
+
+func decode(b []byte) (string, string) {
+	if utf8.Valid(b) {
+		return string(b), "utf-8"
+	}
+	return base64.StdEncoding.EncodeToString(b), "base64"
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return false
+}
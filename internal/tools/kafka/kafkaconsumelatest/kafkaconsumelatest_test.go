@@ -0,0 +1,235 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaconsumelatest
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	kafkasrc "github.com/googleapis/genai-toolbox/internal/sources/kafka"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestParseFromYamlKafkaConsumeLatest(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				peek_commands:
+					kind: kafka-consume-latest
+					source: my-kafka-instance
+					description: some description
+			`,
+			want: server.ToolConfigs{
+				"peek_commands": Config{
+					Name:         "peek_commands",
+					Kind:         "kafka-consume-latest",
+					Source:       "my-kafka-instance",
+					Description:  "some description",
+					AuthRequired: []string{},
+				},
+			},
+		},
+		{
+			desc: "with limits",
+			in: `
+			tools:
+				peek_commands:
+					kind: kafka-consume-latest
+					source: my-kafka-instance
+					description: some description
+					topics:
+						- commands
+					maxMessages: 20
+					maxWait: 30s
+			`,
+			want: server.ToolConfigs{
+				"peek_commands": Config{
+					Name:         "peek_commands",
+					Kind:         "kafka-consume-latest",
+					Source:       "my-kafka-instance",
+					Description:  "some description",
+					Topics:       []string{"commands"},
+					MaxMessages:  20,
+					MaxWait:      "30s",
+					AuthRequired: []string{},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestInitializeInvalidLimits(t *testing.T) {
+	srcs := map[string]sources.Source{"my-kafka-instance": &kafkasrc.Source{}}
+	tcs := []struct {
+		desc string
+		cfg  Config
+		err  string
+	}{
+		{
+			desc: "negative maxMessages",
+			cfg:  Config{Name: "peek", Source: "my-kafka-instance", MaxMessages: -1},
+			err:  `invalid maxMessages -1 for tool "peek": must not be negative`,
+		},
+		{
+			desc: "invalid maxWait",
+			cfg:  Config{Name: "peek", Source: "my-kafka-instance", MaxWait: "soon"},
+			err:  `invalid maxWait "soon" for tool "peek"`,
+		},
+		{
+			desc: "maxWait below one second",
+			cfg:  Config{Name: "peek", Source: "my-kafka-instance", MaxWait: "500ms"},
+			err:  `invalid maxWait "500ms" for tool "peek"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := tc.cfg.Initialize(srcs)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestParseParamsLimits(t *testing.T) {
+	cfg := Config{Name: "peek", Source: "my-kafka-instance", MaxMessages: 5, MaxWait: "3s"}
+	tool, err := cfg.Initialize(map[string]sources.Source{"my-kafka-instance": &kafkasrc.Source{}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	params, err := tool.ParseParams(map[string]any{"topic": "commands"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	got := params.AsMap()
+	if got[maxMessagesKey] != 5 || got[maxWaitSecondsKey] != 3 {
+		t.Fatalf("unexpected defaults: got %v", got)
+	}
+
+	for _, data := range []map[string]any{
+		{"topic": "commands", "maxMessages": 6},
+		{"topic": "commands", "maxWaitSeconds": 4},
+		{"topic": "commands", "maxWaitSeconds": 0},
+	} {
+		if _, err := tool.ParseParams(data, nil); err == nil {
+			t.Fatalf("expected parameters %v to be out of range", data)
+		}
+	}
+}
+
+func TestInvokeTopicNotAllowed(t *testing.T) {
+	cfg := Config{Name: "peek", Source: "my-kafka-instance", Topics: []string{"commands"}}
+	tool, err := cfg.Initialize(map[string]sources.Source{"my-kafka-instance": &kafkasrc.Source{}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	params, err := tool.ParseParams(map[string]any{"topic": "payments"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	_, err = tool.Invoke(context.Background(), tools.Invocation{Params: params})
+	want := `topic "payments" is not allowed; must be one of ["commands"]`
+	if err == nil || err.Error() != want {
+		t.Fatalf("unexpected error: got %v, want %q", err, want)
+	}
+}
+
+func TestMessage(t *testing.T) {
+	ts := time.Date(2025, time.June, 2, 12, 0, 0, 0, time.UTC)
+	tcs := []struct {
+		desc   string
+		record *kgo.Record
+		want   map[string]any
+	}{
+		{
+			desc: "utf-8",
+			record: &kgo.Record{
+				Partition: 1,
+				Offset:    42,
+				Timestamp: ts,
+				Key:       []byte("order-1"),
+				Value:     []byte(`{"status":"shipped"}`),
+				Headers:   []kgo.RecordHeader{{Key: "source", Value: []byte("agent")}},
+			},
+			want: map[string]any{
+				"partition":     int32(1),
+				"offset":        int64(42),
+				"timestamp":     "2025-06-02T12:00:00Z",
+				"key":           "order-1",
+				"keyEncoding":   "utf-8",
+				"value":         `{"status":"shipped"}`,
+				"valueEncoding": "utf-8",
+				"headers":       []any{map[string]any{"key": "source", "value": "agent", "valueEncoding": "utf-8"}},
+			},
+		},
+		{
+			desc: "binary without key",
+			record: &kgo.Record{
+				Timestamp: ts,
+				Value:     []byte{0xff, 0x00, 0x01},
+			},
+			want: map[string]any{
+				"partition":     int32(0),
+				"offset":        int64(0),
+				"timestamp":     "2025-06-02T12:00:00Z",
+				"key":           nil,
+				"value":         "/wAB",
+				"valueEncoding": "base64",
+				"headers":       []any{},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, message(tc.record)); diff != "" {
+				t.Fatalf("unexpected message: diff %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaproduce
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	kafkasrc "github.com/googleapis/genai-toolbox/internal/sources/kafka"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+const kind string = "kafka-produce"
+
+const (
+	topicKey   = "topic"
+	keyKey     = "key"
+	valueKey   = "value"
+	headersKey = "headers"
+)
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	KafkaClient() *kgo.Client
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &kafkasrc.Source{}
+
+var compatibleSources = [...]string{kafkasrc.SourceKind}
+
+type Config struct {
+	Name         string   `yaml:"name" validate:"required"`
+	Kind         string   `yaml:"kind" validate:"required"`
+	Source       string   `yaml:"source" validate:"required"`
+	Description  string   `yaml:"description" validate:"required"`
+	Topics       []string `yaml:"topics" validate:"required"`
+	AuthRequired []string `yaml:"authRequired"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	if len(cfg.Topics) == 0 {
+		return nil, fmt.Errorf("%q tool %q must allow at least one topic", kind, cfg.Name)
+	}
+
+	parameters := tools.Parameters{
+		tools.NewStringParameter(topicKey, fmt.Sprintf("The topic to produce to. Must be one of: %s.", strings.Join(cfg.Topics, ", "))),
+		tools.NewStringParameterWithRequired(keyKey, "The key of the message. Messages with the same key go to the same partition.", false),
+		tools.NewStringParameter(valueKey, "The value of the message."),
+		tools.NewMapParameterWithRequired(headersKey, "The headers of the message.", false, "string"),
+	}
+
+	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, parameters)
+
+	// finish tool setup
+	t := Tool{
+		Name:         cfg.Name,
+		Kind:         kind,
+		Parameters:   parameters,
+		AuthRequired: cfg.AuthRequired,
+		Topics:       cfg.Topics,
+		Client:       s.KafkaClient(),
+		manifest:     tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string           `yaml:"name"`
+	Kind         string           `yaml:"kind"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+
+	Topics      []string
+	Client      *kgo.Client
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	record, err := t.record(inv.Params.AsMap())
+	if err != nil {
+		return nil, err
+	}
+	if err := t.Client.ProduceSync(ctx, record).FirstErr(); err != nil {
+		return nil, fmt.Errorf("unable to produce to topic %q: %w", record.Topic, err)
+	}
+	return map[string]any{
+		"topic":     record.Topic,
+		"partition": record.Partition,
+		"offset":    record.Offset,
+	}, nil
+}
+
+// record builds the record to produce from the parameters of an invocation.
+func (t Tool) record(paramsMap map[string]any) (*kgo.Record, error) {
+	topic, ok := paramsMap[topicKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected a string", topicKey)
+	}
+	value, ok := paramsMap[valueKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected a string", valueKey)
+	}
+	if !slices.Contains(t.Topics, topic) {
+		return nil, fmt.Errorf("topic %q is not allowed; must be one of %q", topic, t.Topics)
+	}
+
+	record := &kgo.Record{Topic: topic, Value: []byte(value)}
+	if key, ok := paramsMap[keyKey].(string); ok {
+		record.Key = []byte(key)
+	}
+	if headers, ok := paramsMap[headersKey].(map[string]any); ok {
+		// sort the headers so that they are produced in a stable order
+		names := make([]string, 0, len(headers))
+		for name := range headers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			v, ok := headers[name].(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid value for header %q; expected a string", name)
+			}
+			record.Headers = append(record.Headers, kgo.RecordHeader{Key: name, Value: []byte(v)})
+		}
+	}
+	return record, nil
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return false
+}
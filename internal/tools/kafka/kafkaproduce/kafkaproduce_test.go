@@ -0,0 +1,155 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaproduce_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	kafkasrc "github.com/googleapis/genai-toolbox/internal/sources/kafka"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/kafka/kafkaproduce"
+)
+
+func TestParseFromYamlKafkaProduce(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				send_command:
+					kind: kafka-produce
+					source: my-kafka-instance
+					description: some description
+					topics:
+						- commands
+						- audit
+			`,
+			want: server.ToolConfigs{
+				"send_command": kafkaproduce.Config{
+					Name:         "send_command",
+					Kind:         "kafka-produce",
+					Source:       "my-kafka-instance",
+					Description:  "some description",
+					Topics:       []string{"commands", "audit"},
+					AuthRequired: []string{},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestFailParseFromYamlKafkaProduce(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		send_command:
+			kind: kafka-produce
+			source: my-kafka-instance
+			description: some description
+	`
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	err = yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got)
+	if err == nil {
+		t.Fatalf("expect parsing to fail")
+	}
+	want := "Key: 'Config.Topics' Error:Field validation for 'Topics' failed on the 'required' tag"
+	if !strings.Contains(err.Error(), want) {
+		t.Fatalf("unexpected error: got %q, want %q", err.Error(), want)
+	}
+}
+
+func TestInvokeTopicNotAllowed(t *testing.T) {
+	cfg := kafkaproduce.Config{
+		Name:        "send_command",
+		Kind:        "kafka-produce",
+		Source:      "my-kafka-instance",
+		Description: "some description",
+		Topics:      []string{"commands"},
+	}
+	tool, err := cfg.Initialize(map[string]sources.Source{"my-kafka-instance": &kafkasrc.Source{}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	params, err := tool.ParseParams(map[string]any{"topic": "payments", "value": "refund"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	_, err = tool.Invoke(context.Background(), tools.Invocation{Params: params})
+	want := `topic "payments" is not allowed; must be one of ["commands"]`
+	if err == nil || err.Error() != want {
+		t.Fatalf("unexpected error: got %v, want %q", err, want)
+	}
+}
+
+func TestInitializeWithoutTopics(t *testing.T) {
+	cfg := kafkaproduce.Config{Name: "send_command", Source: "my-kafka-instance", Topics: []string{}}
+	_, err := cfg.Initialize(map[string]sources.Source{"my-kafka-instance": &kafkasrc.Source{}})
+	want := `"kafka-produce" tool "send_command" must allow at least one topic`
+	if err == nil || err.Error() != want {
+		t.Fatalf("unexpected error: got %v, want %q", err, want)
+	}
+}
+
+func TestManifest(t *testing.T) {
+	cfg := kafkaproduce.Config{Name: "send_command", Source: "my-kafka-instance", Description: "some description", Topics: []string{"commands"}}
+	tool, err := cfg.Initialize(map[string]sources.Source{"my-kafka-instance": &kafkasrc.Source{}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var required []string
+	for _, p := range tool.Manifest().Parameters {
+		if p.Required {
+			required = append(required, p.Name)
+		}
+	}
+	if diff := cmp.Diff([]string{"topic", "value"}, required); diff != "" {
+		t.Fatalf("unexpected required parameters: diff %v", diff)
+	}
+}
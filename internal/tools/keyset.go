@@ -0,0 +1,293 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+const (
+	// AfterKeyParameter is the parameter holding the key of the last row of
+	// the previous page, in the invocations of tools paginated by key.
+	AfterKeyParameter = "_afterKey"
+	// defaultKeysetPageSize is the number of rows of a page when pageSize is
+	// not set.
+	defaultKeysetPageSize = 100
+)
+
+// keysetColumnRe matches the names of the columns a page can be ordered by.
+// They are written into the statement, so quoted or qualified names are not
+// supported.
+var keysetColumnRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// KeysetPaginationSpec is the `keysetPagination` block of a SQL tool config.
+// The rows of the statement are returned a page at a time, ordered by
+// Columns, each page starting after the key of the last row of the previous
+// one.
+type KeysetPaginationSpec struct {
+	// Columns are the columns the rows are ordered by. Together, they must
+	// identify a row, or rows sharing a key may be skipped.
+	Columns []KeysetColumn `yaml:"columns" validate:"required"`
+	// PageSize is the maximum number of rows of a page.
+	PageSize int `yaml:"pageSize"`
+}
+
+// KeysetColumn is a column rows are ordered by. It is written either as the
+// name of the column, ordered ascending, or as a mapping.
+type KeysetColumn struct {
+	Name       string `yaml:"name" validate:"required"`
+	Descending bool   `yaml:"descending"`
+}
+
+func (c *KeysetColumn) UnmarshalYAML(ctx context.Context, unmarshal func(interface{}) error) error {
+	var name string
+	if err := unmarshal(&name); err == nil {
+		*c = KeysetColumn{Name: name}
+		return nil
+	}
+	var raw struct {
+		Name       string `yaml:"name"`
+		Descending bool   `yaml:"descending"`
+	}
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+	*c = KeysetColumn(raw)
+	return nil
+}
+
+// KeysetPagination is a validated KeysetPaginationSpec.
+//
+// Rows are ordered with NULLs last, whatever the direction of their column,
+// so that a page can start after a key holding NULLs.
+type KeysetPagination struct {
+	columns  []KeysetColumn
+	pageSize int
+}
+
+// NewKeysetPagination validates spec and fills in its defaults. It returns
+// nil if spec is nil.
+func NewKeysetPagination(spec *KeysetPaginationSpec) (*KeysetPagination, error) {
+	if spec == nil {
+		return nil, nil
+	}
+	if len(spec.Columns) == 0 {
+		return nil, fmt.Errorf("keysetPagination must list at least one column")
+	}
+	seen := make(map[string]bool, len(spec.Columns))
+	for _, c := range spec.Columns {
+		if !keysetColumnRe.MatchString(c.Name) {
+			return nil, fmt.Errorf("invalid keysetPagination column %q: must be an unquoted column name", c.Name)
+		}
+		if seen[c.Name] {
+			return nil, fmt.Errorf("keysetPagination column %q is listed more than once", c.Name)
+		}
+		seen[c.Name] = true
+	}
+	if spec.PageSize < 0 {
+		return nil, fmt.Errorf("keysetPagination pageSize must not be negative, got %d", spec.PageSize)
+	}
+	pageSize := spec.PageSize
+	if pageSize == 0 {
+		pageSize = defaultKeysetPageSize
+	}
+	return &KeysetPagination{columns: spec.Columns, pageSize: pageSize}, nil
+}
+
+// Parameters returns the optional AfterKeyParameter that tools paginated by
+// key add to their parameters, or none if k is nil.
+func (k *KeysetPagination) Parameters() Parameters {
+	if k == nil {
+		return nil
+	}
+	return Parameters{&keysetKeyParameter{size: len(k.columns)}}
+}
+
+// AfterKey returns the value of the AfterKeyParameter of an invocation, or
+// nil for its first page.
+func (k *KeysetPagination) AfterKey(params ParamValues) []any {
+	key, _ := params.AsMap()[AfterKeyParameter].([]any)
+	return key
+}
+
+// Statement returns the statement of a page of the rows of statement, bound
+// to params, starting after afterKey unless it is nil. placeholder returns
+// the placeholder of the n-th parameter bound to the statement, starting
+// from 1. The parameters bound to the returned statement are returned along
+// with it.
+//
+// The page holds one more row than the page size, telling Page whether
+// there is a next page.
+func (k *KeysetPagination) Statement(statement string, params []any, afterKey []any, placeholder func(n int) string) (string, []any) {
+	statement = strings.TrimRight(strings.TrimSpace(statement), ";")
+	bound := params
+	bind := func(v any) string {
+		bound = append(bound, v)
+		return placeholder(len(bound))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "SELECT * FROM (\n%s\n) AS keyset_page", statement)
+	if afterKey != nil {
+		fmt.Fprintf(&b, "\nWHERE %s", k.predicate(afterKey, bind))
+	}
+	order := make([]string, 0, len(k.columns))
+	for _, c := range k.columns {
+		direction := ""
+		if c.Descending {
+			direction = " DESC"
+		}
+		order = append(order, fmt.Sprintf("%s IS NULL, %s%s", c.Name, c.Name, direction))
+	}
+	fmt.Fprintf(&b, "\nORDER BY %s\nLIMIT %d", strings.Join(order, ", "), k.pageSize+1)
+	return b.String(), bound
+}
+
+// Render returns the rendering of the statement of a page, given the
+// rendering of the statement of the tool, as Statement builds it.
+func (k *KeysetPagination) Render(rendered RenderedStatement, params ParamValues, placeholder func(n int) string) RenderedStatement {
+	statement, bound := k.Statement(rendered.Statement, make([]any, len(rendered.Params)), k.AfterKey(params), placeholder)
+	rendered.Statement = statement
+	for _, v := range bound[len(rendered.Params):] {
+		rendered.Params = append(rendered.Params, RenderedParam{Name: AfterKeyParameter, Value: v})
+	}
+	return rendered
+}
+
+// predicate returns the condition selecting the rows that come after key,
+// binding its values with bind. For columns (a, b), ascending, it is the
+// expansion of `(a, b) > (x, y)`:
+//
+//	(a > x OR a IS NULL) OR (a = x AND (b > y OR b IS NULL))
+//
+// with comparisons flipped for descending columns, and NULLs, which come
+// last, compared with IS NULL.
+func (k *KeysetPagination) predicate(key []any, bind func(v any) string) string {
+	var terms []string
+	for i, c := range k.columns {
+		// no row comes after NULL, since NULLs come last
+		if key[i] == nil {
+			continue
+		}
+		conds := make([]string, 0, i+1)
+		for j, prev := range k.columns[:i] {
+			if key[j] == nil {
+				conds = append(conds, prev.Name+" IS NULL")
+			} else {
+				conds = append(conds, fmt.Sprintf("%s = %s", prev.Name, bind(key[j])))
+			}
+		}
+		op := ">"
+		if c.Descending {
+			op = "<"
+		}
+		conds = append(conds, fmt.Sprintf("(%s %s %s OR %s IS NULL)", c.Name, op, bind(key[i]), c.Name))
+		terms = append(terms, "("+strings.Join(conds, " AND ")+")")
+	}
+	if len(terms) == 0 {
+		return "1 = 0"
+	}
+	return strings.Join(terms, " OR ")
+}
+
+// Page returns the result of a page whose rows, returned by the statement
+// of Statement, are maps of column names to values. It holds the rows of the
+// page, their count, and the key of the last one as `nextKey`, or nil if
+// there is no next page.
+func (k *KeysetPagination) Page(rows []any) (map[string]any, error) {
+	var nextKey []any
+	if len(rows) > k.pageSize {
+		rows = rows[:k.pageSize]
+		row, ok := rows[len(rows)-1].(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("unable to read the key of a row of type %T", rows[len(rows)-1])
+		}
+		nextKey = make([]any, 0, len(k.columns))
+		for _, c := range k.columns {
+			v, ok := row[c.Name]
+			if !ok {
+				return nil, fmt.Errorf("keysetPagination column %q is not a column of the results", c.Name)
+			}
+			nextKey = append(nextKey, v)
+		}
+	}
+	if rows == nil {
+		rows = []any{}
+	}
+	return map[string]any{"rows": rows, "rowCount": len(rows), "nextKey": nextKey}, nil
+}
+
+// keysetKeyParameter is the AfterKeyParameter: the values of the key
+// columns of a row, in order.
+type keysetKeyParameter struct {
+	size int
+}
+
+var _ Parameter = &keysetKeyParameter{}
+
+const keysetKeyDescription = "The nextKey returned with the previous page, to get the page that follows it. Omit it to get the first page."
+
+func (p *keysetKeyParameter) GetName() string                     { return AfterKeyParameter }
+func (p *keysetKeyParameter) GetType() string                     { return "array" }
+func (p *keysetKeyParameter) GetDefault() any                     { return nil }
+func (p *keysetKeyParameter) GetRequired() bool                   { return false }
+func (p *keysetKeyParameter) GetAuthServices() []ParamAuthService { return nil }
+
+func (p *keysetKeyParameter) Parse(v any) (any, error) {
+	key, ok := v.([]any)
+	if !ok {
+		return nil, &ParseTypeError{AfterKeyParameter, "array", v}
+	}
+	if len(key) != p.size {
+		return nil, fmt.Errorf("key has %d values, expected %d", len(key), p.size)
+	}
+	for i, e := range key {
+		switch e.(type) {
+		case map[string]any, []any:
+			return nil, fmt.Errorf("value at index %d of the key is not a scalar", i)
+		}
+	}
+	converted, err := util.ConvertNumbers(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse integer or float values in key: %s", err)
+	}
+	return converted, nil
+}
+
+func (p *keysetKeyParameter) Manifest() ParameterManifest {
+	return ParameterManifest{
+		Name:         AfterKeyParameter,
+		Type:         "array",
+		Required:     false,
+		Description:  keysetKeyDescription,
+		AuthServices: []string{},
+		MinItems:     &p.size,
+		MaxItems:     &p.size,
+	}
+}
+
+func (p *keysetKeyParameter) McpManifest() (ParameterMcpManifest, []string) {
+	return ParameterMcpManifest{
+		Type:        "array",
+		Description: keysetKeyDescription,
+		MinItems:    &p.size,
+		MaxItems:    &p.size,
+	}, []string{}
+}
@@ -0,0 +1,246 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func dollar(n int) string {
+	return fmt.Sprintf("$%d", n)
+}
+
+func newKeyset(t *testing.T, spec tools.KeysetPaginationSpec) *tools.KeysetPagination {
+	t.Helper()
+	k, err := tools.NewKeysetPagination(&spec)
+	if err != nil {
+		t.Fatalf("unable to create keyset pagination: %s", err)
+	}
+	return k
+}
+
+func TestNewKeysetPagination(t *testing.T) {
+	tcs := []struct {
+		desc    string
+		spec    tools.KeysetPaginationSpec
+		wantErr string
+	}{
+		{desc: "columns only", spec: tools.KeysetPaginationSpec{Columns: []tools.KeysetColumn{{Name: "id"}}}},
+		{desc: "all fields", spec: tools.KeysetPaginationSpec{Columns: []tools.KeysetColumn{{Name: "created_at", Descending: true}, {Name: "id"}}, PageSize: 10}},
+		{desc: "no columns", spec: tools.KeysetPaginationSpec{}, wantErr: "at least one column"},
+		{desc: "quoted column", spec: tools.KeysetPaginationSpec{Columns: []tools.KeysetColumn{{Name: `"id"`}}}, wantErr: "must be an unquoted column name"},
+		{desc: "expression", spec: tools.KeysetPaginationSpec{Columns: []tools.KeysetColumn{{Name: "id; DROP TABLE t"}}}, wantErr: "must be an unquoted column name"},
+		{desc: "duplicate column", spec: tools.KeysetPaginationSpec{Columns: []tools.KeysetColumn{{Name: "id"}, {Name: "id", Descending: true}}}, wantErr: "listed more than once"},
+		{desc: "negative page size", spec: tools.KeysetPaginationSpec{Columns: []tools.KeysetColumn{{Name: "id"}}, PageSize: -1}, wantErr: "pageSize must not be negative"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := tools.NewKeysetPagination(&tc.spec)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("got error %v, want one containing %q", err, tc.wantErr)
+			}
+		})
+	}
+
+	if k, err := tools.NewKeysetPagination(nil); k != nil || err != nil {
+		t.Fatalf("got %v, %v for a nil spec, want nil, nil", k, err)
+	}
+}
+
+func TestKeysetPaginationStatement(t *testing.T) {
+	twoColumns := tools.KeysetPaginationSpec{
+		Columns:  []tools.KeysetColumn{{Name: "a"}, {Name: "b"}},
+		PageSize: 10,
+	}
+	mixed := tools.KeysetPaginationSpec{
+		Columns:  []tools.KeysetColumn{{Name: "created_at", Descending: true}, {Name: "id"}},
+		PageSize: 10,
+	}
+	tcs := []struct {
+		desc       string
+		spec       tools.KeysetPaginationSpec
+		afterKey   []any
+		wantWhere  string
+		wantOrder  string
+		wantParams []any
+	}{
+		{
+			desc:       "first page",
+			spec:       twoColumns,
+			wantOrder:  "a IS NULL, a, b IS NULL, b",
+			wantParams: []any{"x"},
+		},
+		{
+			desc:       "ascending",
+			spec:       twoColumns,
+			afterKey:   []any{1, 2},
+			wantWhere:  "((a > $2 OR a IS NULL)) OR (a = $3 AND (b > $4 OR b IS NULL))",
+			wantOrder:  "a IS NULL, a, b IS NULL, b",
+			wantParams: []any{"x", 1, 1, 2},
+		},
+		{
+			desc:       "descending",
+			spec:       mixed,
+			afterKey:   []any{"2025-01-01", 7},
+			wantWhere:  "((created_at < $2 OR created_at IS NULL)) OR (created_at = $3 AND (id > $4 OR id IS NULL))",
+			wantOrder:  "created_at IS NULL, created_at DESC, id IS NULL, id",
+			wantParams: []any{"x", "2025-01-01", "2025-01-01", 7},
+		},
+		{
+			desc:       "null in the middle of the key",
+			spec:       twoColumns,
+			afterKey:   []any{nil, 2},
+			wantWhere:  "(a IS NULL AND (b > $2 OR b IS NULL))",
+			wantOrder:  "a IS NULL, a, b IS NULL, b",
+			wantParams: []any{"x", 2},
+		},
+		{
+			desc:       "null at the end of the key",
+			spec:       twoColumns,
+			afterKey:   []any{1, nil},
+			wantWhere:  "((a > $2 OR a IS NULL))",
+			wantOrder:  "a IS NULL, a, b IS NULL, b",
+			wantParams: []any{"x", 1},
+		},
+		{
+			desc:       "last key",
+			spec:       twoColumns,
+			afterKey:   []any{nil, nil},
+			wantWhere:  "1 = 0",
+			wantOrder:  "a IS NULL, a, b IS NULL, b",
+			wantParams: []any{"x"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			k := newKeyset(t, tc.spec)
+			got, params := k.Statement("SELECT * FROM t WHERE c = $1;\n", []any{"x"}, tc.afterKey, dollar)
+			want := "SELECT * FROM (\nSELECT * FROM t WHERE c = $1\n) AS keyset_page"
+			if tc.wantWhere != "" {
+				want += "\nWHERE " + tc.wantWhere
+			}
+			want += "\nORDER BY " + tc.wantOrder + "\nLIMIT 11"
+			if got != want {
+				t.Fatalf("got statement:\n%s\nwant:\n%s", got, want)
+			}
+			if diff := cmp.Diff(tc.wantParams, params); diff != "" {
+				t.Fatalf("incorrect params: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestKeysetPaginationPage(t *testing.T) {
+	k := newKeyset(t, tools.KeysetPaginationSpec{
+		Columns:  []tools.KeysetColumn{{Name: "a"}, {Name: "b"}},
+		PageSize: 2,
+	})
+	row := func(a, b any) any {
+		return map[string]any{"a": a, "b": b, "c": "value"}
+	}
+	tcs := []struct {
+		desc string
+		rows []any
+		want map[string]any
+	}{
+		{
+			desc: "no rows",
+			want: map[string]any{"rows": []any{}, "rowCount": 0, "nextKey": []any(nil)},
+		},
+		{
+			desc: "last page",
+			rows: []any{row(1, 1), row(1, 2)},
+			want: map[string]any{"rows": []any{row(1, 1), row(1, 2)}, "rowCount": 2, "nextKey": []any(nil)},
+		},
+		{
+			desc: "next page",
+			rows: []any{row(1, 1), row(1, nil), row(2, 1)},
+			want: map[string]any{"rows": []any{row(1, 1), row(1, nil)}, "rowCount": 2, "nextKey": []any{1, nil}},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := k.Page(tc.rows)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect page: diff %v", diff)
+			}
+		})
+	}
+
+	missing := []any{map[string]any{"a": 1}, map[string]any{"a": 2}, map[string]any{"a": 3}}
+	if _, err := k.Page(missing); err == nil || !strings.Contains(err.Error(), `column "b" is not a column of the results`) {
+		t.Fatalf("got error %v for rows missing a key column", err)
+	}
+}
+
+func TestKeysetPaginationAfterKey(t *testing.T) {
+	k := newKeyset(t, tools.KeysetPaginationSpec{Columns: []tools.KeysetColumn{{Name: "a"}, {Name: "b"}}})
+	params := k.Parameters()
+	if len(params) != 1 || params[0].GetName() != tools.AfterKeyParameter || params[0].GetRequired() {
+		t.Fatalf("got parameters %v, want an optional %q", params, tools.AfterKeyParameter)
+	}
+
+	got, err := tools.ParseParams(params, map[string]any{tools.AfterKeyParameter: []any{"x", 1}}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff([]any{"x", 1}, k.AfterKey(got)); diff != "" {
+		t.Fatalf("incorrect key: diff %v", diff)
+	}
+
+	got, err = tools.ParseParams(params, map[string]any{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if key := k.AfterKey(got); key != nil {
+		t.Fatalf("got key %v for the first page, want nil", key)
+	}
+
+	for _, invalid := range []any{"x", []any{1}, []any{1, []any{2}}} {
+		if _, err := tools.ParseParams(params, map[string]any{tools.AfterKeyParameter: invalid}, nil); err == nil {
+			t.Errorf("expected an error parsing key %v", invalid)
+		}
+	}
+}
+
+func TestKeysetPaginationRender(t *testing.T) {
+	k := newKeyset(t, tools.KeysetPaginationSpec{Columns: []tools.KeysetColumn{{Name: "id"}}, PageSize: 5})
+	params := tools.ParamValues{{Name: "c", Value: "x"}, {Name: tools.AfterKeyParameter, Value: []any{3}}}
+	rendered := tools.RenderedStatement{
+		Statement: "SELECT * FROM t WHERE c = $1",
+		Params:    []tools.RenderedParam{{Name: "c", Value: "x"}},
+	}
+	want := tools.RenderedStatement{
+		Statement: "SELECT * FROM (\nSELECT * FROM t WHERE c = $1\n) AS keyset_page\nWHERE ((id > $2 OR id IS NULL))\nORDER BY id IS NULL, id\nLIMIT 6",
+		Params:    []tools.RenderedParam{{Name: "c", Value: "x"}, {Name: tools.AfterKeyParameter, Value: 3}},
+	}
+	if diff := cmp.Diff(want, k.Render(rendered, params, dollar)); diff != "" {
+		t.Fatalf("incorrect rendering: diff %v", diff)
+	}
+}
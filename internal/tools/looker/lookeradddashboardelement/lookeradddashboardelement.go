@@ -125,18 +125,18 @@ var (
 	visType  string = "vis"
 )
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
 	logger, err := util.LoggerFromContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get logger from ctx: %s", err)
 	}
-	logger.DebugContext(ctx, "params = ", params)
-	wq, err := lookercommon.ProcessQueryArgs(ctx, params)
+	logger.DebugContext(ctx, "params = ", inv.Params.Redacted())
+	wq, err := lookercommon.ProcessQueryArgs(ctx, inv.Params)
 	if err != nil {
 		return nil, fmt.Errorf("error building query request: %w", err)
 	}
 
-	paramsMap := params.AsMap()
+	paramsMap := inv.Params.AsMap()
 	dashboard_id := paramsMap["dashboard_id"].(string)
 	title := paramsMap["title"].(string)
 
@@ -145,7 +145,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 
 	qrespFields := "id"
 
-	sdk, err := lookercommon.GetLookerSDK(t.UseClientOAuth, t.ApiSettings, t.Client, accessToken)
+	sdk, err := lookercommon.GetLookerSDK(ctx, t.UseClientOAuth, t.ApiSettings, t.Client, inv.AccessToken)
 	if err != nil {
 		return nil, fmt.Errorf("error getting sdk: %w", err)
 	}
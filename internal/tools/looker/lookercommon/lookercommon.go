@@ -16,6 +16,7 @@ package lookercommon
 import (
 	"context"
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -40,7 +41,7 @@ func (t *transportWithAuthHeader) RoundTrip(req *http.Request) (*http.Response,
 	return t.Base.RoundTrip(req)
 }
 
-func GetLookerSDK(useClientOAuth bool, config *rtl.ApiSettings, client *v4.LookerSDK, accessToken tools.AccessToken) (*v4.LookerSDK, error) {
+func GetLookerSDK(ctx context.Context, useClientOAuth bool, config *rtl.ApiSettings, client *v4.LookerSDK, accessToken tools.AccessToken) (*v4.LookerSDK, error) {
 
 	if useClientOAuth {
 		if accessToken == "" {
@@ -60,18 +61,69 @@ func GetLookerSDK(useClientOAuth bool, config *rtl.ApiSettings, client *v4.Looke
 		}
 
 		// return SDK with new Transport
-		return v4.NewLookerSDK(&rtl.AuthSession{
+		client = v4.NewLookerSDK(&rtl.AuthSession{
 			Config: *config,
 			Client: http.Client{Transport: newTransport},
-		}), nil
+		})
 	}
 
 	if client == nil {
 		return nil, fmt.Errorf("client id or client secret not valid")
 	}
+	if identity := util.ClientIdentityFromContext(ctx); identity != "" {
+		client = v4.NewLookerSDK(attributedSession{AuthSessionDoer: client.AuthSession, identity: identity})
+	}
 	return client, nil
 }
 
+// attributedSession appends the identity of the caller of a tool to the user
+// agent of the requests sent through an AuthSession.
+type attributedSession struct {
+	v4.AuthSessionDoer
+	identity string
+}
+
+func (s attributedSession) Do(result interface{}, method, ver, path string, reqPars map[string]interface{}, body interface{}, options *rtl.ApiSettings) error {
+	var settings rtl.ApiSettings
+	if options != nil {
+		settings = *options
+	}
+	settings.AgentTag = strings.TrimSpace(settings.AgentTag + " client/" + s.identity)
+	return s.AuthSessionDoer.Do(result, method, ver, path, reqPars, body, &settings)
+}
+
+// SDKError converts an error returned by the Looker SDK into one carrying
+// the message from Looker, naming the offending fields when Looker reports
+// them. Errors that do not carry a Looker error body are returned unchanged.
+func SDKError(err error) error {
+	msg := err.Error()
+	i := strings.Index(msg, "error=")
+	if i < 0 {
+		return err
+	}
+	var ve v4.ValidationError
+	if e := json.Unmarshal([]byte(msg[i+len("error="):]), &ve); e != nil || ve.Message == "" {
+		return err
+	}
+	var details []string
+	if ve.Errors != nil {
+		for _, d := range *ve.Errors {
+			if d.Field == nil || *d.Field == "" {
+				continue
+			}
+			detail := fmt.Sprintf("field %q", *d.Field)
+			if d.Message != nil && *d.Message != "" {
+				detail += ": " + *d.Message
+			}
+			details = append(details, detail)
+		}
+	}
+	if len(details) == 0 {
+		return fmt.Errorf("%s", ve.Message)
+	}
+	return fmt.Errorf("%s (%s)", ve.Message, strings.Join(details, "; "))
+}
+
 const (
 	DimensionsFields = "fields(dimensions(name,type,label,label_short,description,synonyms,tags,hidden,suggestable,suggestions,suggest_dimension,suggest_explore))"
 	FiltersFields    = "fields(filters(name,type,label,label_short,description,synonyms,tags,hidden,suggestable,suggestions,suggest_dimension,suggest_explore))"
@@ -211,7 +263,7 @@ func ProcessQueryArgs(ctx context.Context, params tools.ParamValues) (*v4.WriteQ
 		return nil, fmt.Errorf("unable to get logger from ctx: %s", err)
 	}
 
-	logger.DebugContext(ctx, "params = ", params)
+	logger.DebugContext(ctx, "params = ", params.Redacted())
 	paramsMap := params.AsMap()
 
 	f, err := tools.ConvertAnySliceToTyped(paramsMap["fields"].([]any), "string")
@@ -15,12 +15,16 @@
 package lookercommon_test
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
 	"github.com/googleapis/genai-toolbox/internal/tools/looker/lookercommon"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	rtl "github.com/looker-open-source/sdk-codegen/go/rtl"
 	v4 "github.com/looker-open-source/sdk-codegen/go/sdk/v4"
 )
 
@@ -199,3 +203,86 @@ func TestRequestRunInlineQuery2(t *testing.T) {
 	}
 
 }
+
+func TestSDKError(t *testing.T) {
+	tcs := []struct {
+		desc string
+		err  error
+		want string
+	}{
+		{
+			desc: "validation error with field",
+			err:  errors.New(`response error. status=422 Unprocessable Entity. error={"message":"Validation Failed","errors":[{"field":"view.bogus","code":"invalid","message":"Unknown field"}],"documentation_url":"https://cloud.google.com/looker/docs/"}`),
+			want: `Validation Failed (field "view.bogus": Unknown field)`,
+		},
+		{
+			desc: "error without field",
+			err:  errors.New(`response error. status=404 Not Found. error={"message":"Not found","documentation_url":"https://cloud.google.com/looker/docs/"}`),
+			want: "Not found",
+		},
+		{
+			desc: "error body is not json",
+			err:  errors.New("response error. status=502 Bad Gateway. error=<html></html>"),
+			want: "response error. status=502 Bad Gateway. error=<html></html>",
+		},
+		{
+			desc: "not a response error",
+			err:  errors.New("connection refused"),
+			want: "connection refused",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := lookercommon.SDKError(tc.err)
+			if got.Error() != tc.want {
+				t.Fatalf("unexpected error: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+// agentTagSession is a v4.AuthSessionDoer that records the agent tag of the
+// requests it sends.
+type agentTagSession struct {
+	agentTag *string
+}
+
+func (s agentTagSession) Do(result interface{}, method, ver, path string, reqPars map[string]interface{}, body interface{}, options *rtl.ApiSettings) error {
+	*s.agentTag = options.AgentTag
+	return nil
+}
+
+func TestGetLookerSDKClientAttribution(t *testing.T) {
+	settings := &rtl.ApiSettings{AgentTag: "genai-toolbox/1.0.0"}
+	tcs := []struct {
+		desc string
+		ctx  context.Context
+		want string
+	}{
+		{desc: "not attributed", ctx: context.Background(), want: "genai-toolbox/1.0.0"},
+		{
+			desc: "attributed",
+			ctx:  util.WithClientAttribution(context.Background(), util.ClientAttributionHeader, "Billing Agent"),
+			want: "genai-toolbox/1.0.0 client/billing_agent",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			var got string
+			client := v4.NewLookerSDK(agentTagSession{agentTag: &got})
+			sdk, err := lookercommon.GetLookerSDK(tc.ctx, false, settings, client, "")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if _, err := sdk.Me("", settings); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != tc.want {
+				t.Fatalf("unexpected agent tag: got %q, want %q", got, tc.want)
+			}
+			if settings.AgentTag != "genai-toolbox/1.0.0" {
+				t.Fatalf("the settings of the tool were modified: %q", settings.AgentTag)
+			}
+		})
+	}
+}
@@ -216,7 +216,7 @@ type Tool struct {
 	mcpManifest    tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
 	var tokenStr string
 	var err error
 
@@ -232,7 +232,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	tokenStr = token.AccessToken
 
 	// Extract parameters from the map
-	mapParams := params.AsMap()
+	mapParams := inv.Params.AsMap()
 	userQuery, _ := mapParams["user_query_with_context"].(string)
 	exploreReferences, _ := mapParams["explore_references"].([]any)
 
@@ -246,7 +246,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	}
 	oauth_creds := OAuthCredentials{}
 	if t.UseClientOAuth {
-		oauth_creds.Token = TokenBased{AccessToken: string(accessToken)}
+		oauth_creds.Token = TokenBased{AccessToken: string(inv.AccessToken)}
 	} else {
 		oauth_creds.Secret = SecretBased{ClientId: t.ApiSettings.ClientId, ClientSecret: t.ApiSettings.ClientSecret}
 	}
@@ -110,13 +110,13 @@ type Tool struct {
 	mcpManifest    tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	sdk, err := lookercommon.GetLookerSDK(t.UseClientOAuth, t.ApiSettings, t.Client, accessToken)
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	sdk, err := lookercommon.GetLookerSDK(ctx, t.UseClientOAuth, t.ApiSettings, t.Client, inv.AccessToken)
 	if err != nil {
 		return nil, fmt.Errorf("error getting sdk: %w", err)
 	}
 
-	mapParams := params.AsMap()
+	mapParams := inv.Params.AsMap()
 	projectId, ok := mapParams["project_id"].(string)
 	if !ok {
 		return nil, fmt.Errorf("'project_id' must be a string, got %T", mapParams["project_id"])
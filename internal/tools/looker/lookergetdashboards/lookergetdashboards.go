@@ -74,11 +74,13 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 	titleParameter := tools.NewStringParameterWithDefault("title", "", "The title of the dashboard.")
 	descParameter := tools.NewStringParameterWithDefault("desc", "", "The description of the dashboard.")
+	folderParameter := tools.NewStringParameterWithDefault("folder_id", "", "The id of the folder containing the dashboards.")
 	limitParameter := tools.NewIntParameterWithDefault("limit", 100, "The number of dashboards to fetch. Default 100")
 	offsetParameter := tools.NewIntParameterWithDefault("offset", 0, "The number of dashboards to skip before fetching. Default 0")
 	parameters := tools.Parameters{
 		titleParameter,
 		descParameter,
+		folderParameter,
 		limitParameter,
 		offsetParameter,
 	}
@@ -118,12 +120,12 @@ type Tool struct {
 	mcpManifest    tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
 	logger, err := util.LoggerFromContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get logger from ctx: %s", err)
 	}
-	paramsMap := params.AsMap()
+	paramsMap := inv.Params.AsMap()
 	title := paramsMap["title"].(string)
 	title_ptr := &title
 	if *title_ptr == "" {
@@ -134,16 +136,24 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	if *desc_ptr == "" {
 		desc_ptr = nil
 	}
+	folderId := paramsMap["folder_id"].(string)
+	folderId_ptr := &folderId
+	if *folderId_ptr == "" {
+		folderId_ptr = nil
+	}
 	limit := int64(paramsMap["limit"].(int))
 	offset := int64(paramsMap["offset"].(int))
 
-	sdk, err := lookercommon.GetLookerSDK(t.UseClientOAuth, t.ApiSettings, t.Client, accessToken)
+	sdk, err := lookercommon.GetLookerSDK(ctx, t.UseClientOAuth, t.ApiSettings, t.Client, inv.AccessToken)
 	if err != nil {
 		return nil, fmt.Errorf("error getting sdk: %w", err)
 	}
+	fields := "id,title,description,folder(id,name),dashboard_elements(id)"
 	req := v4.RequestSearchDashboards{
 		Title:       title_ptr,
 		Description: desc_ptr,
+		FolderId:    folderId_ptr,
+		Fields:      &fields,
 		Limit:       &limit,
 		Offset:      &offset,
 	}
@@ -166,6 +176,17 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		if v.Description != nil {
 			vMap["description"] = *v.Description
 		}
+		if v.Folder != nil {
+			if v.Folder.Id != nil {
+				vMap["folder_id"] = *v.Folder.Id
+			}
+			vMap["folder"] = v.Folder.Name
+		}
+		tileCount := 0
+		if v.DashboardElements != nil {
+			tileCount = len(*v.DashboardElements)
+		}
+		vMap["tile_count"] = tileCount
 		logger.DebugContext(ctx, "Converted to %v\n", vMap)
 		data = append(data, vMap)
 	}
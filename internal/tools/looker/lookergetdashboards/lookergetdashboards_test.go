@@ -15,14 +15,22 @@
 package lookergetdashboards_test
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	lookersrc "github.com/googleapis/genai-toolbox/internal/sources/looker"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
 	lkr "github.com/googleapis/genai-toolbox/internal/tools/looker/lookergetdashboards"
+	"github.com/looker-open-source/sdk-codegen/go/rtl"
+	v4 "github.com/looker-open-source/sdk-codegen/go/sdk/v4"
 )
 
 func TestParseFromYamlLookerGetDashboards(t *testing.T) {
@@ -114,3 +122,56 @@ func TestFailParseFromYamlLookerGetDashboards(t *testing.T) {
 	}
 
 }
+
+func TestInvokeLookerGetDashboards(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var gotQuery url.Values
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/4.0/dashboards/search", func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[
+			{"id": "1", "title": "Sales", "description": "Sales overview", "folder": {"id": "42", "name": "Shared"}, "dashboard_elements": [{"id": "e1"}, {"id": "e2"}]},
+			{"id": "2", "title": "Empty"}
+		]`))
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	settings := rtl.ApiSettings{BaseUrl: ts.URL, ApiVersion: "4.0"}
+	sdk := v4.NewLookerSDK(&rtl.AuthSession{Config: settings, Client: http.Client{}})
+	srcs := map[string]sources.Source{
+		"my-instance": &lookersrc.Source{Name: "my-instance", Kind: lookersrc.SourceKind, Client: sdk, ApiSettings: &settings},
+	}
+	cfg := lkr.Config{Name: "example_tool", Kind: "looker-get-dashboards", Source: "my-instance", Description: "some description"}
+	tool, err := cfg.Initialize(srcs)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+
+	params, err := tool.ParseParams(map[string]any{"title": "Sa%", "folder_id": "42"}, nil)
+	if err != nil {
+		t.Fatalf("unable to parse params: %s", err)
+	}
+	got, err := tool.Invoke(ctx, tools.Invocation{Params: params})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []any{
+		map[string]any{"id": "1", "title": "Sales", "description": "Sales overview", "folder_id": "42", "folder": "Shared", "tile_count": 2},
+		map[string]any{"id": "2", "title": "Empty", "tile_count": 0},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected result (-want +got):\n%s", diff)
+	}
+	if gotQuery.Get("title") != "Sa%" || gotQuery.Get("folder_id") != "42" {
+		t.Fatalf("unexpected search query: %v", gotQuery)
+	}
+	if gotQuery.Get("description") != "" {
+		t.Fatalf("empty description should not be sent: %v", gotQuery)
+	}
+}
@@ -111,18 +111,18 @@ type Tool struct {
 	ShowHiddenFields bool
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
 	logger, err := util.LoggerFromContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get logger from ctx: %s", err)
 	}
-	model, explore, err := lookercommon.ProcessFieldArgs(ctx, params)
+	model, explore, err := lookercommon.ProcessFieldArgs(ctx, inv.Params)
 	if err != nil {
 		return nil, fmt.Errorf("error processing model or explore: %w", err)
 	}
 
 	fields := lookercommon.MeasuresFields
-	sdk, err := lookercommon.GetLookerSDK(t.UseClientOAuth, t.ApiSettings, t.Client, accessToken)
+	sdk, err := lookercommon.GetLookerSDK(ctx, t.UseClientOAuth, t.ApiSettings, t.Client, inv.AccessToken)
 	if err != nil {
 		return nil, fmt.Errorf("error getting sdk: %w", err)
 	}
@@ -0,0 +1,236 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package lookergetsql
+
+import (
+	"context"
+	"fmt"
+	"unicode/utf8"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	lookersrc "github.com/googleapis/genai-toolbox/internal/sources/looker"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/looker/lookercommon"
+	"github.com/googleapis/genai-toolbox/internal/util"
+
+	"github.com/looker-open-source/sdk-codegen/go/rtl"
+	v4 "github.com/looker-open-source/sdk-codegen/go/sdk/v4"
+)
+
+const (
+	kind string = "looker-get-sql"
+	// targetKey is the name of the union of the ways to specify the query.
+	targetKey   = "target"
+	lookBranch  = "look"
+	queryBranch = "query"
+)
+
+// defaultMaxSqlLength is the number of characters of SQL returned when the
+// tool does not configure maxSqlLength.
+const defaultMaxSqlLength = 20000
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type Config struct {
+	Name         string   `yaml:"name" validate:"required"`
+	Kind         string   `yaml:"kind" validate:"required"`
+	Source       string   `yaml:"source" validate:"required"`
+	Description  string   `yaml:"description" validate:"required"`
+	AuthRequired []string `yaml:"authRequired"`
+	MaxSqlLength int      `yaml:"maxSqlLength"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(*lookersrc.Source)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be `looker`", kind)
+	}
+
+	maxSqlLength := cfg.MaxSqlLength
+	if maxSqlLength < 0 {
+		return nil, fmt.Errorf("invalid maxSqlLength for %q tool: must not be negative, got %d", kind, maxSqlLength)
+	}
+	if maxSqlLength == 0 {
+		maxSqlLength = defaultMaxSqlLength
+	}
+
+	target := tools.NewOneOfParameter(targetKey, "The query to generate the SQL for, either a saved Look or an inline query.",
+		tools.OneOfBranch{
+			Name: lookBranch,
+			Parameters: tools.Parameters{
+				tools.NewStringParameter("look_id", "The id of the look to generate the SQL for."),
+			},
+		},
+		tools.OneOfBranch{
+			Name:       queryBranch,
+			Parameters: lookercommon.GetQueryParameters(),
+		},
+	)
+	parameters := tools.Parameters{target}
+
+	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, parameters)
+
+	// finish tool setup
+	return Tool{
+		Name:           cfg.Name,
+		Kind:           kind,
+		Parameters:     parameters,
+		AuthRequired:   cfg.AuthRequired,
+		UseClientOAuth: s.UseClientOAuth,
+		Client:         s.Client,
+		ApiSettings:    s.ApiSettings,
+		MaxSqlLength:   maxSqlLength,
+		manifest: tools.Manifest{
+			Description:  cfg.Description,
+			Parameters:   parameters.Manifest(),
+			AuthRequired: cfg.AuthRequired,
+		},
+		mcpManifest: mcpManifest,
+	}, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name           string `yaml:"name"`
+	Kind           string `yaml:"kind"`
+	UseClientOAuth bool
+	Client         *v4.LookerSDK
+	ApiSettings    *rtl.ApiSettings
+	AuthRequired   []string         `yaml:"authRequired"`
+	Parameters     tools.Parameters `yaml:"parameters"`
+	MaxSqlLength   int
+	manifest       tools.Manifest
+	mcpManifest    tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	logger, err := util.LoggerFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get logger from ctx: %s", err)
+	}
+	target, ok := inv.Params.AsMap()[targetKey].(tools.OneOfValue)
+	if !ok {
+		return nil, fmt.Errorf("invalid or missing '%s' parameter", targetKey)
+	}
+	sdk, err := lookercommon.GetLookerSDK(ctx, t.UseClientOAuth, t.ApiSettings, t.Client, inv.AccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("error getting sdk: %w", err)
+	}
+
+	// The "sql" result format only generates the SQL; Looker does not run it
+	// against the database.
+	var sql, model, explore string
+	switch target.Branch {
+	case lookBranch:
+		lookId, _ := target.Params.AsMap()["look_id"].(string)
+		look, err := sdk.Look(lookId, "query", t.ApiSettings)
+		if err != nil {
+			return nil, fmt.Errorf("error getting look %s: %w", lookId, lookercommon.SDKError(err))
+		}
+		if look.Query == nil {
+			return nil, fmt.Errorf("error getting look %s: looker did not return its query", lookId)
+		}
+		model, explore = look.Query.Model, look.Query.View
+		sql, err = sdk.RunLook(v4.RequestRunLook{
+			LookId:       lookId,
+			ResultFormat: "sql",
+		}, t.ApiSettings)
+		if err != nil {
+			return nil, fmt.Errorf("error generating sql for look %s: %w", lookId, lookercommon.SDKError(err))
+		}
+	case queryBranch:
+		wq, err := lookercommon.ProcessQueryArgs(ctx, target.Params)
+		if err != nil {
+			return nil, fmt.Errorf("error building query request: %w", err)
+		}
+		model, explore = wq.Model, wq.View
+		sql, err = lookercommon.RunInlineQuery(ctx, sdk, wq, "sql", t.ApiSettings)
+		if err != nil {
+			return nil, fmt.Errorf("error generating sql: %w", lookercommon.SDKError(err))
+		}
+	default:
+		return nil, fmt.Errorf("unknown branch %q of the '%s' parameter", target.Branch, targetKey)
+	}
+	logger.DebugContext(ctx, "resp = ", sql)
+
+	sql, truncated := truncate(sql, t.MaxSqlLength)
+	return map[string]any{
+		"sql":       sql,
+		"model":     model,
+		"explore":   explore,
+		"truncated": truncated,
+	}, nil
+}
+
+// truncate shortens sql to at most max bytes, cutting at a character boundary
+// and marking the cut with a trailing SQL comment.
+func truncate(sql string, max int) (string, bool) {
+	if len(sql) <= max {
+		return sql, false
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(sql[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s\n-- truncated: showing %d of %d bytes", sql[:cut], cut, len(sql)), true
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return t.UseClientOAuth
+}
@@ -0,0 +1,301 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lookergetsql_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	lookersrc "github.com/googleapis/genai-toolbox/internal/sources/looker"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	lkr "github.com/googleapis/genai-toolbox/internal/tools/looker/lookergetsql"
+	"github.com/looker-open-source/sdk-codegen/go/rtl"
+)
+
+func TestParseFromYamlLookerGetSql(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				example_tool:
+					kind: looker-get-sql
+					source: my-instance
+					description: some description
+			`,
+			want: server.ToolConfigs{
+				"example_tool": lkr.Config{
+					Name:         "example_tool",
+					Kind:         "looker-get-sql",
+					Source:       "my-instance",
+					Description:  "some description",
+					AuthRequired: []string{},
+				},
+			},
+		},
+		{
+			desc: "with maxSqlLength",
+			in: `
+			tools:
+				example_tool:
+					kind: looker-get-sql
+					source: my-instance
+					description: some description
+					maxSqlLength: 1000
+			`,
+			want: server.ToolConfigs{
+				"example_tool": lkr.Config{
+					Name:         "example_tool",
+					Kind:         "looker-get-sql",
+					Source:       "my-instance",
+					Description:  "some description",
+					AuthRequired: []string{},
+					MaxSqlLength: 1000,
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestInitializeNegativeMaxSqlLength(t *testing.T) {
+	srcs := map[string]sources.Source{"my-instance": &lookersrc.Source{}}
+	cfg := lkr.Config{Name: "example_tool", Source: "my-instance", MaxSqlLength: -1}
+	_, err := cfg.Initialize(srcs)
+	want := `invalid maxSqlLength for "looker-get-sql" tool: must not be negative, got -1`
+	if err == nil || err.Error() != want {
+		t.Fatalf("unexpected error: got %v, want %q", err, want)
+	}
+}
+
+func TestParseParamsLookerGetSql(t *testing.T) {
+	tool := initializeMockTool(t, "http://localhost", 0)
+	tcs := []struct {
+		desc   string
+		data   map[string]any
+		branch string
+		err    string
+	}{
+		{
+			desc:   "look id",
+			data:   map[string]any{"look_id": "7"},
+			branch: "look",
+		},
+		{
+			desc:   "inline query",
+			data:   map[string]any{"model": "thelook", "explore": "orders", "fields": []any{"orders.count"}},
+			branch: "query",
+		},
+		{
+			desc: "neither",
+			data: map[string]any{},
+			err:  "but none was provided",
+		},
+		{
+			desc: "both",
+			data: map[string]any{"look_id": "7", "model": "thelook"},
+			err:  "were all provided",
+		},
+		{
+			desc: "incomplete inline query",
+			data: map[string]any{"model": "thelook"},
+			err:  `parameter "explore" is required`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			params, err := tool.ParseParams(tc.data, nil)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("unexpected error: got %v, want substring %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			target, ok := params.AsMap()["target"].(tools.OneOfValue)
+			if !ok || target.Branch != tc.branch {
+				t.Fatalf("unexpected target: got %v, want branch %q", params.AsMap()["target"], tc.branch)
+			}
+		})
+	}
+}
+
+// newMockLooker starts a server that answers the Looker API calls made by
+// the tool. sqlStatus and sqlBody are returned when the SQL is generated.
+func newMockLooker(t *testing.T, sqlStatus int, sqlBody string) (*httptest.Server, *map[string]any) {
+	t.Helper()
+	var inline map[string]any
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/4.0/looks/7":
+			_, _ = w.Write([]byte(`{"id":"7","query":{"model":"thelook","view":"orders"}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/api/4.0/looks/7/run/sql",
+			r.Method == http.MethodPost && r.URL.Path == "/api/4.0/queries/run_inline",
+			r.Method == http.MethodPost && r.URL.Path == "/api/4.0/queries/run/sql":
+			if r.Method == http.MethodPost {
+				b, _ := io.ReadAll(r.Body)
+				_ = json.Unmarshal(b, &inline)
+			}
+			w.WriteHeader(sqlStatus)
+			_, _ = w.Write([]byte(sqlBody))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts, &inline
+}
+
+func initializeMockTool(t *testing.T, url string, maxSqlLength int) tools.Tool {
+	t.Helper()
+	srcs := map[string]sources.Source{
+		"my-instance": &lookersrc.Source{
+			UseClientOAuth: true,
+			ApiSettings: &rtl.ApiSettings{
+				BaseUrl:    url,
+				ApiVersion: "4.0",
+				VerifySsl:  true,
+			},
+		},
+	}
+	cfg := lkr.Config{
+		Name:         "example_tool",
+		Kind:         "looker-get-sql",
+		Source:       "my-instance",
+		Description:  "some description",
+		MaxSqlLength: maxSqlLength,
+	}
+	tool, err := cfg.Initialize(srcs)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	return tool
+}
+
+func TestInvokeLookerGetSql(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	sql := "SELECT COUNT(*) AS \"orders.count\" FROM orders"
+	ts, inline := newMockLooker(t, http.StatusOK, sql)
+
+	tcs := []struct {
+		desc         string
+		maxSqlLength int
+		data         map[string]any
+		want         map[string]any
+	}{
+		{
+			desc: "look id",
+			data: map[string]any{"look_id": "7"},
+			want: map[string]any{"sql": sql, "model": "thelook", "explore": "orders", "truncated": false},
+		},
+		{
+			desc: "inline query",
+			data: map[string]any{"model": "thelook", "explore": "orders", "fields": []any{"orders.count"}, "tz": "UTC"},
+			want: map[string]any{"sql": sql, "model": "thelook", "explore": "orders", "truncated": false},
+		},
+		{
+			desc:         "truncated",
+			maxSqlLength: 12,
+			data:         map[string]any{"look_id": "7"},
+			want: map[string]any{
+				"sql":       "SELECT COUNT\n-- truncated: showing 12 of 45 bytes",
+				"model":     "thelook",
+				"explore":   "orders",
+				"truncated": true,
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			tool := initializeMockTool(t, ts.URL, tc.maxSqlLength)
+			params, err := tool.ParseParams(tc.data, nil)
+			if err != nil {
+				t.Fatalf("unable to parse params: %s", err)
+			}
+			got, err := tool.Invoke(ctx, tools.Invocation{Params: params, AccessToken: "Bearer token"})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+	query, _ := (*inline)["query"].(map[string]any)
+	if query["model"] != "thelook" || query["view"] != "orders" {
+		t.Fatalf("unexpected inline query: %v", *inline)
+	}
+}
+
+func TestInvokeLookerGetSqlInvalidField(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	body := `{"message":"Validation Failed","errors":[{"field":"orders.bogus","code":"invalid","message":"Unknown field","documentation_url":""}],"documentation_url":""}`
+	ts, _ := newMockLooker(t, http.StatusUnprocessableEntity, body)
+	tool := initializeMockTool(t, ts.URL, 0)
+
+	params, err := tool.ParseParams(map[string]any{
+		"model":   "thelook",
+		"explore": "orders",
+		"fields":  []any{"orders.bogus"},
+		"tz":      "UTC",
+	}, nil)
+	if err != nil {
+		t.Fatalf("unable to parse params: %s", err)
+	}
+	_, err = tool.Invoke(ctx, tools.Invocation{Params: params, AccessToken: "Bearer token"})
+	want := `error generating sql: Validation Failed (field "orders.bogus": Unknown field)`
+	if err == nil || err.Error() != want {
+		t.Fatalf("unexpected error: got %v, want %q", err, want)
+	}
+}
@@ -119,13 +119,13 @@ type Tool struct {
 	mcpManifest    tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
 	logger, err := util.LoggerFromContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get logger from ctx: %s", err)
 	}
 
-	sdk, err := lookercommon.GetLookerSDK(t.UseClientOAuth, t.ApiSettings, t.Client, accessToken)
+	sdk, err := lookercommon.GetLookerSDK(ctx, t.UseClientOAuth, t.ApiSettings, t.Client, inv.AccessToken)
 	if err != nil {
 		return nil, fmt.Errorf("error getting sdk: %w", err)
 	}
@@ -135,7 +135,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		SdkClient:   sdk,
 	}
 
-	paramsMap := params.AsMap()
+	paramsMap := inv.Params.AsMap()
 	action, ok := paramsMap["action"].(string)
 	if !ok {
 		return nil, fmt.Errorf("action parameter not found")
@@ -124,13 +124,13 @@ type Tool struct {
 	mcpManifest    tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	sdk, err := lookercommon.GetLookerSDK(t.UseClientOAuth, t.ApiSettings, t.Client, accessToken)
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	sdk, err := lookercommon.GetLookerSDK(ctx, t.UseClientOAuth, t.ApiSettings, t.Client, inv.AccessToken)
 	if err != nil {
 		return nil, fmt.Errorf("error getting sdk: %w", err)
 	}
 
-	paramsMap := params.AsMap()
+	paramsMap := inv.Params.AsMap()
 	timeframe, _ := paramsMap["timeframe"].(int)
 	if timeframe == 0 {
 		timeframe = 90
@@ -80,6 +80,20 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	parameters = append(parameters, titleParameter)
 	descParameter := tools.NewStringParameterWithDefault("description", "", "The description of the Dashboard")
 	parameters = append(parameters, descParameter)
+	folderParameter := tools.NewStringParameterWithDefault("folder_id", "", "The id of the folder to create the Dashboard in. Defaults to the user's personal folder.")
+	parameters = append(parameters, folderParameter)
+	lookIdsParameter := tools.NewArrayParameterWithDefault("look_ids",
+		[]any{},
+		"The ids of Looks to add to the Dashboard as tiles.",
+		tools.NewStringParameter("look_id", "The id of a Look"),
+	)
+	parameters = append(parameters, lookIdsParameter)
+	queriesParameter := tools.NewArrayParameterWithDefault("queries",
+		[]any{},
+		`Inline queries to add to the Dashboard as tiles. Each query is an object with the keys "title", "model", "explore", "fields", and optionally "filters", "pivots", "sorts", "limit" and "vis_config".`,
+		tools.NewMapParameter("query", "An inline query definition", ""),
+	)
+	parameters = append(parameters, queriesParameter)
 
 	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, parameters)
 
@@ -116,32 +130,44 @@ type Tool struct {
 	mcpManifest    tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
 	logger, err := util.LoggerFromContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get logger from ctx: %s", err)
 	}
-	logger.DebugContext(ctx, "params = ", params)
+	logger.DebugContext(ctx, "params = ", inv.Params.Redacted())
 
-	sdk, err := lookercommon.GetLookerSDK(t.UseClientOAuth, t.ApiSettings, t.Client, accessToken)
+	sdk, err := lookercommon.GetLookerSDK(ctx, t.UseClientOAuth, t.ApiSettings, t.Client, inv.AccessToken)
 	if err != nil {
 		return nil, fmt.Errorf("error getting sdk: %w", err)
 	}
-	mrespFields := "id,personal_folder_id"
-	mresp, err := sdk.Me(mrespFields, t.ApiSettings)
-	if err != nil {
-		return nil, fmt.Errorf("error making me request: %s", err)
-	}
 
-	paramsMap := params.AsMap()
+	paramsMap := inv.Params.AsMap()
 	title := paramsMap["title"].(string)
 	description := paramsMap["description"].(string)
+	folderId := paramsMap["folder_id"].(string)
+	lookIds, err := tools.ConvertAnySliceToTyped(paramsMap["look_ids"].([]any), "string")
+	if err != nil {
+		return nil, fmt.Errorf("can't convert look_ids to array of strings: %s", err)
+	}
+	queries, err := parseQueries(paramsMap["queries"].([]any))
+	if err != nil {
+		return nil, err
+	}
 
-	if mresp.PersonalFolderId == nil || *mresp.PersonalFolderId == "" {
-		return nil, fmt.Errorf("user does not have a personal folder. cannot continue")
+	if folderId == "" {
+		mrespFields := "id,personal_folder_id"
+		mresp, err := sdk.Me(mrespFields, t.ApiSettings)
+		if err != nil {
+			return nil, fmt.Errorf("error making me request: %s", err)
+		}
+		if mresp.PersonalFolderId == nil || *mresp.PersonalFolderId == "" {
+			return nil, fmt.Errorf("user does not have a personal folder. cannot continue")
+		}
+		folderId = *mresp.PersonalFolderId
 	}
 
-	dashs, err := sdk.FolderDashboards(*mresp.PersonalFolderId, "title", t.ApiSettings)
+	dashs, err := sdk.FolderDashboards(folderId, "title", t.ApiSettings)
 	if err != nil {
 		return nil, fmt.Errorf("error getting existing dashboards in folder: %s", err)
 	}
@@ -152,19 +178,30 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	}
 	if slices.Contains(dashTitles, title) {
 		lt, _ := json.Marshal(dashTitles)
-		return nil, fmt.Errorf("title %s already used in user's folder. Currently used titles are %v. Make the call again with a unique title", title, string(lt))
+		return nil, fmt.Errorf("title %s already used in folder. Currently used titles are %v. Make the call again with a unique title", title, string(lt))
 	}
 
 	wd := v4.WriteDashboard{
 		Title:       &title,
 		Description: &description,
-		FolderId:    mresp.PersonalFolderId,
+		FolderId:    &folderId,
 	}
 	resp, err := sdk.CreateDashboard(wd, t.ApiSettings)
 	if err != nil {
 		return nil, fmt.Errorf("error making create dashboard request: %s", err)
 	}
 	logger.DebugContext(ctx, "resp = %v", resp)
+	if resp.Id == nil {
+		return nil, fmt.Errorf("create dashboard response did not include a dashboard id")
+	}
+
+	if err := addTiles(sdk, t.ApiSettings, *resp.Id, lookIds.([]string), queries); err != nil {
+		// do not leave an empty or partially built dashboard behind
+		if _, delErr := sdk.DeleteDashboard(*resp.Id, t.ApiSettings); delErr != nil {
+			logger.ErrorContext(ctx, fmt.Sprintf("error deleting dashboard %s after failure: %s", *resp.Id, delErr))
+		}
+		return nil, fmt.Errorf("error adding tiles to dashboard, dashboard was removed: %w", err)
+	}
 
 	setting, err := sdk.GetSetting("host_url", t.ApiSettings)
 	if err != nil {
@@ -172,9 +209,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	}
 
 	data := make(map[string]any)
-	if resp.Id != nil {
-		data["id"] = *resp.Id
-	}
+	data["id"] = *resp.Id
 	if resp.Url != nil {
 		if setting.HostUrl != nil {
 			data["url"] = *setting.HostUrl + *resp.Url
@@ -187,6 +222,182 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	return data, nil
 }
 
+// tileQuery is an inline query definition provided for a dashboard tile.
+type tileQuery struct {
+	title     string
+	query     v4.WriteQuery
+	visConfig map[string]any
+}
+
+// parseQueries converts the inline query definitions into Looker queries.
+func parseQueries(raw []any) ([]tileQuery, error) {
+	queries := make([]tileQuery, 0, len(raw))
+	for i, r := range raw {
+		q := r.(map[string]any)
+		title, _ := q["title"].(string)
+		model, ok := q["model"].(string)
+		if !ok || model == "" {
+			return nil, fmt.Errorf("query #%d must have a 'model'", i)
+		}
+		explore, ok := q["explore"].(string)
+		if !ok || explore == "" {
+			return nil, fmt.Errorf("query #%d must have an 'explore'", i)
+		}
+		fields, err := stringSlice(q, "fields")
+		if err != nil {
+			return nil, fmt.Errorf("query #%d: %w", i, err)
+		}
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("query #%d must have at least one field", i)
+		}
+		pivots, err := stringSlice(q, "pivots")
+		if err != nil {
+			return nil, fmt.Errorf("query #%d: %w", i, err)
+		}
+		sorts, err := stringSlice(q, "sorts")
+		if err != nil {
+			return nil, fmt.Errorf("query #%d: %w", i, err)
+		}
+		filters, _ := q["filters"].(map[string]any)
+		if filters == nil {
+			filters = map[string]any{}
+		}
+		visConfig, _ := q["vis_config"].(map[string]any)
+		wq := v4.WriteQuery{
+			Model:   model,
+			View:    explore,
+			Fields:  &fields,
+			Pivots:  &pivots,
+			Filters: &filters,
+			Sorts:   &sorts,
+		}
+		if limit, ok := q["limit"]; ok {
+			l := fmt.Sprintf("%v", limit)
+			wq.Limit = &l
+		}
+		if len(visConfig) > 0 {
+			wq.VisConfig = &visConfig
+		}
+		queries = append(queries, tileQuery{title: title, query: wq, visConfig: visConfig})
+	}
+	return queries, nil
+}
+
+func stringSlice(m map[string]any, key string) ([]string, error) {
+	raw, ok := m[key]
+	if !ok || raw == nil {
+		return []string{}, nil
+	}
+	list, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("'%s' must be a list of strings", key)
+	}
+	s, err := tools.ConvertAnySliceToTyped(list, "string")
+	if err != nil {
+		return nil, fmt.Errorf("can't convert %s to array of strings: %s", key, err)
+	}
+	return s.([]string), nil
+}
+
+var (
+	dataType string = "data"
+	visType  string = "vis"
+)
+
+const (
+	// tiles are placed two per row on Looker's 24 column grid
+	tilesPerRow int64 = 2
+	tileWidth   int64 = 12
+	tileHeight  int64 = 6
+)
+
+// addTiles adds the Looks and inline queries as tiles of the dashboard and
+// places them in a grid.
+func addTiles(sdk *v4.LookerSDK, options *rtl.ApiSettings, dashboardId string, lookIds []string, queries []tileQuery) error {
+	fields := "id"
+	elementIds := []string{}
+	for _, lookId := range lookIds {
+		wde := v4.WriteDashboardElement{
+			DashboardId: &dashboardId,
+			LookId:      &lookId,
+			Type:        &visType,
+		}
+		resp, err := sdk.CreateDashboardElement(v4.RequestCreateDashboardElement{Body: wde, Fields: &fields}, options)
+		if err != nil {
+			return fmt.Errorf("error adding look %s: %w", lookId, err)
+		}
+		if resp.Id != nil {
+			elementIds = append(elementIds, *resp.Id)
+		}
+	}
+	for i, q := range queries {
+		qresp, err := sdk.CreateQuery(q.query, "id", options)
+		if err != nil {
+			return fmt.Errorf("error creating query #%d: %w", i, err)
+		}
+		wde := v4.WriteDashboardElement{
+			DashboardId: &dashboardId,
+			Title:       &q.title,
+			QueryId:     qresp.Id,
+			Type:        &dataType,
+		}
+		if len(q.visConfig) > 0 {
+			wde.Type = &visType
+		}
+		resp, err := sdk.CreateDashboardElement(v4.RequestCreateDashboardElement{Body: wde, Fields: &fields}, options)
+		if err != nil {
+			return fmt.Errorf("error adding query #%d: %w", i, err)
+		}
+		if resp.Id != nil {
+			elementIds = append(elementIds, *resp.Id)
+		}
+	}
+	if len(elementIds) == 0 {
+		return nil
+	}
+
+	layouts, err := sdk.DashboardDashboardLayouts(dashboardId, "id,active,dashboard_layout_components(id,dashboard_element_id)", options)
+	if err != nil {
+		return fmt.Errorf("error getting dashboard layouts: %w", err)
+	}
+	var layout *v4.DashboardLayout
+	for i := range layouts {
+		if layouts[i].Active != nil && *layouts[i].Active {
+			layout = &layouts[i]
+			break
+		}
+	}
+	if layout == nil || layout.DashboardLayoutComponents == nil {
+		// nothing to arrange, Looker will place the tiles itself
+		return nil
+	}
+	components := make(map[string]string)
+	for _, c := range *layout.DashboardLayoutComponents {
+		if c.Id != nil && c.DashboardElementId != nil {
+			components[*c.DashboardElementId] = *c.Id
+		}
+	}
+	for i, elementId := range elementIds {
+		componentId, ok := components[elementId]
+		if !ok {
+			continue
+		}
+		row := int64(i) / tilesPerRow * tileHeight
+		column := int64(i) % tilesPerRow * tileWidth
+		width, height := tileWidth, tileHeight
+		wc := v4.WriteDashboardLayoutComponent{
+			Row:    &row,
+			Column: &column,
+			Width:  &width,
+			Height: &height,
+		}
+		if _, err := sdk.UpdateDashboardLayoutComponent(componentId, wc, "id", options); err != nil {
+			return fmt.Errorf("error placing tile %s: %w", elementId, err)
+		}
+	}
+	return nil
+}
+
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
 	return tools.ParseParams(t.Parameters, data, claims)
 }
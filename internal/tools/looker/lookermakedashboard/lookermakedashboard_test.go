@@ -15,14 +15,24 @@
 package lookermakedashboard_test
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	lookersrc "github.com/googleapis/genai-toolbox/internal/sources/looker"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
 	lkr "github.com/googleapis/genai-toolbox/internal/tools/looker/lookermakedashboard"
+	"github.com/looker-open-source/sdk-codegen/go/rtl"
+	v4 "github.com/looker-open-source/sdk-codegen/go/sdk/v4"
 )
 
 func TestParseFromYamlLookerMakeDashboard(t *testing.T) {
@@ -114,3 +124,207 @@ func TestFailParseFromYamlMakeDashboard(t *testing.T) {
 	}
 
 }
+
+// fakeLooker is a minimal fake of the Looker API used by looker-make-dashboard.
+type fakeLooker struct {
+	mu                sync.Mutex
+	existingTitles    []string
+	failElement       bool
+	created           []map[string]any
+	elements          []map[string]any
+	layoutUpdates     map[string]map[string]any
+	deletedDashboards []string
+}
+
+func (f *fakeLooker) handler(t *testing.T) http.Handler {
+	writeJSON := func(w http.ResponseWriter, v any) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(v); err != nil {
+			t.Errorf("unable to encode response: %s", err)
+		}
+	}
+	readJSON := func(r *http.Request) map[string]any {
+		var m map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+			t.Errorf("unable to decode request: %s", err)
+		}
+		return m
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/4.0/user", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{"id": "1", "personal_folder_id": "42"})
+	})
+	mux.HandleFunc("GET /api/4.0/folders/{id}/dashboards", func(w http.ResponseWriter, r *http.Request) {
+		dashs := []map[string]any{}
+		for _, title := range f.existingTitles {
+			dashs = append(dashs, map[string]any{"title": title})
+		}
+		writeJSON(w, dashs)
+	})
+	mux.HandleFunc("POST /api/4.0/dashboards", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.created = append(f.created, readJSON(r))
+		writeJSON(w, map[string]any{"id": "7", "url": "/dashboards/7"})
+	})
+	mux.HandleFunc("DELETE /api/4.0/dashboards/{id}", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.deletedDashboards = append(f.deletedDashboards, r.PathValue("id"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("POST /api/4.0/queries", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{"id": "q1"})
+	})
+	mux.HandleFunc("POST /api/4.0/dashboard_elements", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if f.failElement && len(f.elements) > 0 {
+			http.Error(w, `{"message": "boom"}`, http.StatusInternalServerError)
+			return
+		}
+		e := readJSON(r)
+		f.elements = append(f.elements, e)
+		writeJSON(w, map[string]any{"id": fmt.Sprintf("e%d", len(f.elements))})
+	})
+	mux.HandleFunc("GET /api/4.0/dashboards/{id}/dashboard_layouts", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		components := []map[string]any{}
+		for i := range f.elements {
+			components = append(components, map[string]any{"id": fmt.Sprintf("c%d", i+1), "dashboard_element_id": fmt.Sprintf("e%d", i+1)})
+		}
+		writeJSON(w, []map[string]any{{"id": "l1", "active": true, "dashboard_layout_components": components}})
+	})
+	mux.HandleFunc("PATCH /api/4.0/dashboard_layout_components/{id}", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.layoutUpdates[r.PathValue("id")] = readJSON(r)
+		writeJSON(w, map[string]any{"id": r.PathValue("id")})
+	})
+	mux.HandleFunc("GET /api/4.0/setting", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{"host_url": "https://looker.example.com"})
+	})
+	return mux
+}
+
+func newTestTool(t *testing.T, f *fakeLooker) (tools.Tool, func()) {
+	ts := httptest.NewServer(f.handler(t))
+	settings := rtl.ApiSettings{BaseUrl: ts.URL, ApiVersion: "4.0"}
+	sdk := v4.NewLookerSDK(&rtl.AuthSession{Config: settings, Client: http.Client{}})
+	srcs := map[string]sources.Source{
+		"my-instance": &lookersrc.Source{Name: "my-instance", Kind: lookersrc.SourceKind, Client: sdk, ApiSettings: &settings},
+	}
+	cfg := lkr.Config{Name: "example_tool", Kind: "looker-make-dashboard", Source: "my-instance", Description: "some description"}
+	tool, err := cfg.Initialize(srcs)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	return tool, ts.Close
+}
+
+func TestInvokeLookerMakeDashboard(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	f := &fakeLooker{layoutUpdates: map[string]map[string]any{}}
+	tool, cleanup := newTestTool(t, f)
+	defer cleanup()
+
+	params, err := tool.ParseParams(map[string]any{
+		"title":    "My Dashboard",
+		"look_ids": []any{"11"},
+		"queries": []any{map[string]any{
+			"title":   "Orders",
+			"model":   "ecommerce",
+			"explore": "orders",
+			"fields":  []any{"orders.count"},
+		}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("unable to parse params: %s", err)
+	}
+	got, err := tool.Invoke(ctx, tools.Invocation{Params: params})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := map[string]any{"id": "7", "url": "https://looker.example.com/dashboards/7"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected result (-want +got):\n%s", diff)
+	}
+	if folder := f.created[0]["folder_id"]; folder != "42" {
+		t.Fatalf("dashboard should be created in the personal folder, got %v", folder)
+	}
+	if len(f.elements) != 2 {
+		t.Fatalf("expected 2 tiles, got %d", len(f.elements))
+	}
+	if f.elements[0]["look_id"] != "11" || f.elements[1]["query_id"] != "q1" {
+		t.Fatalf("unexpected tiles: %v", f.elements)
+	}
+	wantLayout := map[string]map[string]any{
+		"c1": {"row": float64(0), "column": float64(0), "width": float64(12), "height": float64(6)},
+		"c2": {"row": float64(0), "column": float64(12), "width": float64(12), "height": float64(6)},
+	}
+	if diff := cmp.Diff(wantLayout, f.layoutUpdates); diff != "" {
+		t.Fatalf("unexpected layout (-want +got):\n%s", diff)
+	}
+	if len(f.deletedDashboards) != 0 {
+		t.Fatalf("dashboard should not be deleted")
+	}
+}
+
+func TestFailInvokeLookerMakeDashboard(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc        string
+		fake        *fakeLooker
+		params      map[string]any
+		err         string
+		wantDeleted []string
+	}{
+		{
+			desc:   "duplicate title",
+			fake:   &fakeLooker{existingTitles: []string{"My Dashboard"}},
+			params: map[string]any{"title": "My Dashboard"},
+			err:    "title My Dashboard already used in folder",
+		},
+		{
+			desc:        "tile failure rolls back dashboard",
+			fake:        &fakeLooker{failElement: true},
+			params:      map[string]any{"title": "My Dashboard", "look_ids": []any{"11", "12"}},
+			err:         "error adding tiles to dashboard, dashboard was removed",
+			wantDeleted: []string{"7"},
+		},
+		{
+			desc:   "invalid inline query",
+			fake:   &fakeLooker{},
+			params: map[string]any{"title": "My Dashboard", "queries": []any{map[string]any{"model": "ecommerce"}}},
+			err:    "query #0 must have an 'explore'",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			tc.fake.layoutUpdates = map[string]map[string]any{}
+			tool, cleanup := newTestTool(t, tc.fake)
+			defer cleanup()
+			params, err := tool.ParseParams(tc.params, nil)
+			if err != nil {
+				t.Fatalf("unable to parse params: %s", err)
+			}
+			_, err = tool.Invoke(ctx, tools.Invocation{Params: params})
+			if err == nil {
+				t.Fatalf("expected invocation to fail")
+			}
+			if !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("unexpected error string: got %q, want substring %q", err, tc.err)
+			}
+			if diff := cmp.Diff(tc.wantDeleted, tc.fake.deletedDashboards); diff != "" {
+				t.Fatalf("unexpected deleted dashboards (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
@@ -31,6 +32,10 @@ import (
 
 const kind string = "looker-query"
 
+// defaultMaxLimit is the largest row limit a query may request when the tool
+// does not configure maxLimit.
+const defaultMaxLimit = 5000
+
 func init() {
 	if !tools.Register(kind, newConfig) {
 		panic(fmt.Sprintf("tool kind %q already registered", kind))
@@ -51,6 +56,7 @@ type Config struct {
 	Source       string   `yaml:"source" validate:"required"`
 	Description  string   `yaml:"description" validate:"required"`
 	AuthRequired []string `yaml:"authRequired"`
+	MaxLimit     int      `yaml:"maxLimit"`
 }
 
 // validate interface
@@ -73,6 +79,14 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		return nil, fmt.Errorf("invalid source for %q tool: source kind must be `looker`", kind)
 	}
 
+	maxLimit := cfg.MaxLimit
+	if maxLimit < 0 {
+		return nil, fmt.Errorf("invalid maxLimit for %q tool: must not be negative, got %d", kind, maxLimit)
+	}
+	if maxLimit == 0 {
+		maxLimit = defaultMaxLimit
+	}
+
 	parameters := lookercommon.GetQueryParameters()
 
 	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, parameters)
@@ -83,6 +97,7 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		Kind:           kind,
 		Parameters:     parameters,
 		AuthRequired:   cfg.AuthRequired,
+		MaxLimit:       maxLimit,
 		UseClientOAuth: s.UseClientOAuth,
 		Client:         s.Client,
 		ApiSettings:    s.ApiSettings,
@@ -105,37 +120,68 @@ type Tool struct {
 	Client         *v4.LookerSDK
 	ApiSettings    *rtl.ApiSettings
 	AuthRequired   []string         `yaml:"authRequired"`
+	MaxLimit       int              `yaml:"maxLimit"`
 	Parameters     tools.Parameters `yaml:"parameters"`
 	manifest       tools.Manifest
 	mcpManifest    tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
 	logger, err := util.LoggerFromContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get logger from ctx: %s", err)
 	}
-	wq, err := lookercommon.ProcessQueryArgs(ctx, params)
+	wq, err := lookercommon.ProcessQueryArgs(ctx, inv.Params)
 	if err != nil {
 		return nil, fmt.Errorf("error building WriteQuery request: %w", err)
 	}
-	sdk, err := lookercommon.GetLookerSDK(t.UseClientOAuth, t.ApiSettings, t.Client, accessToken)
+	// enforce the row limit here rather than trusting the requested one
+	limit := inv.Params.AsMap()["limit"].(int)
+	if limit <= 0 || limit > t.MaxLimit {
+		logger.DebugContext(ctx, fmt.Sprintf("limiting query to %d rows, requested %d", t.MaxLimit, limit))
+		limit = t.MaxLimit
+	}
+	limitStr := strconv.Itoa(limit)
+	wq.Limit = &limitStr
+
+	sdk, err := lookercommon.GetLookerSDK(ctx, t.UseClientOAuth, t.ApiSettings, t.Client, inv.AccessToken)
 	if err != nil {
 		return nil, fmt.Errorf("error getting sdk: %w", err)
 	}
-	resp, err := lookercommon.RunInlineQuery(ctx, sdk, wq, "json", t.ApiSettings)
+	query, err := sdk.CreateQuery(*wq, "id,share_url", t.ApiSettings)
 	if err != nil {
-		return nil, fmt.Errorf("error making query request: %s", err)
+		return nil, fmt.Errorf("error creating query: %w", lookercommon.SDKError(err))
+	}
+	if query.Id == nil {
+		return nil, fmt.Errorf("error creating query: looker did not return a query id")
+	}
+
+	limit64 := int64(limit)
+	resp, err := sdk.RunQuery(v4.RequestRunQuery{
+		QueryId:      *query.Id,
+		ResultFormat: "json",
+		Limit:        &limit64,
+	}, t.ApiSettings)
+	if err != nil {
+		return nil, fmt.Errorf("error running query %s: %w", *query.Id, lookercommon.SDKError(err))
 	}
 
 	logger.DebugContext(ctx, "resp = ", resp)
 
-	var data []any
-	e := json.Unmarshal([]byte(resp), &data)
+	var rows []any
+	e := json.Unmarshal([]byte(resp), &rows)
 	if e != nil {
 		return nil, fmt.Errorf("error unmarshaling query response: %s", e)
 	}
 
+	data := map[string]any{
+		"query_id": *query.Id,
+		"rows":     rows,
+	}
+	if query.ShareUrl != nil {
+		data["share_url"] = *query.ShareUrl
+	}
+
 	logger.DebugContext(ctx, "data = ", data)
 
 	return data, nil
@@ -15,14 +15,22 @@
 package lookerquery_test
 
 import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	lookersrc "github.com/googleapis/genai-toolbox/internal/sources/looker"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
 	lkr "github.com/googleapis/genai-toolbox/internal/tools/looker/lookerquery"
+	"github.com/looker-open-source/sdk-codegen/go/rtl"
 )
 
 func TestParseFromYamlLookerQuery(t *testing.T) {
@@ -54,6 +62,27 @@ func TestParseFromYamlLookerQuery(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with max limit",
+			in: `
+			tools:
+				example_tool:
+					kind: looker-query
+					source: my-instance
+					description: some description
+					maxLimit: 100
+				`,
+			want: server.ToolConfigs{
+				"example_tool": lkr.Config{
+					Name:         "example_tool",
+					Kind:         "looker-query",
+					Source:       "my-instance",
+					Description:  "some description",
+					AuthRequired: []string{},
+					MaxLimit:     100,
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -114,3 +143,120 @@ func TestFailParseFromYamlLookerQuery(t *testing.T) {
 	}
 
 }
+
+// newMockLooker starts a server that answers the Looker API calls made by
+// the tool. runStatus and runBody are returned when the query is run.
+func newMockLooker(t *testing.T, runStatus int, runBody string) (*httptest.Server, *map[string]any, *string) {
+	t.Helper()
+	var created map[string]any
+	var runLimit string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/4.0/queries":
+			b, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(b, &created)
+			_, _ = w.Write([]byte(`{"id":"42","share_url":"https://looker.example.com/x/abc"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/api/4.0/queries/42/run/json":
+			runLimit = r.URL.Query().Get("limit")
+			w.WriteHeader(runStatus)
+			_, _ = w.Write([]byte(runBody))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts, &created, &runLimit
+}
+
+func initializeMockTool(t *testing.T, url string, maxLimit int) tools.Tool {
+	t.Helper()
+	srcs := map[string]sources.Source{
+		"my-instance": &lookersrc.Source{
+			UseClientOAuth: true,
+			ApiSettings: &rtl.ApiSettings{
+				BaseUrl:    url,
+				ApiVersion: "4.0",
+				VerifySsl:  true,
+			},
+		},
+	}
+	cfg := lkr.Config{
+		Name:        "example_tool",
+		Kind:        "looker-query",
+		Source:      "my-instance",
+		Description: "some description",
+		MaxLimit:    maxLimit,
+	}
+	tool, err := cfg.Initialize(srcs)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	return tool
+}
+
+func TestInvokeLookerQuery(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ts, created, runLimit := newMockLooker(t, http.StatusOK, `[{"look.count":3}]`)
+	tool := initializeMockTool(t, ts.URL, 100)
+
+	params, err := tool.ParseParams(map[string]any{
+		"model":   "system__activity",
+		"explore": "look",
+		"fields":  []any{"look.count"},
+		"limit":   1000,
+		"tz":      "UTC",
+	}, nil)
+	if err != nil {
+		t.Fatalf("unable to parse params: %s", err)
+	}
+	got, err := tool.Invoke(ctx, tools.Invocation{Params: params, AccessToken: "Bearer token"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := map[string]any{
+		"query_id":  "42",
+		"share_url": "https://looker.example.com/x/abc",
+		"rows":      []any{map[string]any{"look.count": float64(3)}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected result (-want +got):\n%s", diff)
+	}
+	if (*created)["model"] != "system__activity" || (*created)["view"] != "look" {
+		t.Fatalf("unexpected query created: %v", *created)
+	}
+	// the requested limit is capped to maxLimit
+	if (*created)["limit"] != "100" || *runLimit != "100" {
+		t.Fatalf("expected limit to be capped to 100, got query limit %v and run limit %q", (*created)["limit"], *runLimit)
+	}
+}
+
+func TestInvokeLookerQueryInvalidField(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	body := `{"message":"Validation Failed","errors":[{"field":"look.bogus","code":"invalid","message":"Unknown field","documentation_url":""}],"documentation_url":""}`
+	ts, _, _ := newMockLooker(t, http.StatusUnprocessableEntity, body)
+	tool := initializeMockTool(t, ts.URL, 0)
+
+	params, err := tool.ParseParams(map[string]any{
+		"model":   "system__activity",
+		"explore": "look",
+		"fields":  []any{"look.bogus"},
+		"tz":      "UTC",
+	}, nil)
+	if err != nil {
+		t.Fatalf("unable to parse params: %s", err)
+	}
+	_, err = tool.Invoke(ctx, tools.Invocation{Params: params, AccessToken: "Bearer token"})
+	if err == nil {
+		t.Fatalf("expected an error, but got nil")
+	}
+	want := `error running query 42: Validation Failed (field "look.bogus": Unknown field)`
+	if err.Error() != want {
+		t.Fatalf("unexpected error: got %q, want %q", err, want)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mindsdbcommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"slices"
+)
+
+// DedupeAll is the `deduplicate` value that removes fully identical rows.
+const DedupeAll = "all"
+
+// Deduplicate is the `deduplicate` field of the mindsdb tools. It is either
+// "all", which removes fully identical rows, or a list of columns, which
+// removes rows whose values for those columns were already seen.
+type Deduplicate struct {
+	All     bool
+	Columns []string
+}
+
+func (d *Deduplicate) UnmarshalYAML(ctx context.Context, unmarshal func(interface{}) error) error {
+	var all string
+	if err := unmarshal(&all); err == nil {
+		if all != DedupeAll {
+			return fmt.Errorf(`deduplicate invalid: must be %q or a list of column names, got %q`, DedupeAll, all)
+		}
+		*d = Deduplicate{All: true}
+		return nil
+	}
+	var columns []string
+	if err := unmarshal(&columns); err != nil {
+		return fmt.Errorf(`deduplicate invalid: must be %q or a list of column names`, DedupeAll)
+	}
+	if len(columns) == 0 {
+		return fmt.Errorf("deduplicate invalid: the list of column names must not be empty")
+	}
+	for i, c := range columns {
+		if c == "" {
+			return fmt.Errorf("deduplicate invalid: column names must not be empty")
+		}
+		if slices.Contains(columns[:i], c) {
+			return fmt.Errorf("deduplicate invalid: column %q is listed more than once", c)
+		}
+	}
+	*d = Deduplicate{Columns: columns}
+	return nil
+}
+
+// NewDeduper returns a Deduper for a result with the given columns.
+func (d *Deduplicate) NewDeduper(columns []string) (*Deduper, error) {
+	if d.All {
+		return newDeduper(columns), nil
+	}
+	for _, c := range d.Columns {
+		if !slices.Contains(columns, c) {
+			return nil, fmt.Errorf("unable to deduplicate rows: column %q is not in the result", c)
+		}
+	}
+	return newDeduper(d.Columns), nil
+}
+
+// Deduper removes duplicate rows while they are scanned. Only a fingerprint
+// of the key of each row is hashed; the keys sharing a fingerprint are
+// compared by value, so hash collisions never drop distinct rows. NULL values
+// are equal to each other, as with SELECT DISTINCT.
+type Deduper struct {
+	columns []string
+	seen    map[uint64][][]any
+	hash    func([]byte) uint64
+	// Removed is the number of duplicate rows seen so far.
+	Removed int
+}
+
+func newDeduper(columns []string) *Deduper {
+	return &Deduper{
+		columns: columns,
+		seen:    make(map[uint64][][]any),
+		hash:    fnv64a,
+	}
+}
+
+func fnv64a(b []byte) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write(b)
+	return h.Sum64()
+}
+
+// Duplicate reports whether the key of row was already seen, and records it
+// otherwise.
+func (d *Deduper) Duplicate(row map[string]any) (bool, error) {
+	key := make([]any, len(d.columns))
+	for i, c := range d.columns {
+		key[i] = row[c]
+	}
+	b, err := json.Marshal(key)
+	if err != nil {
+		return false, fmt.Errorf("unable to deduplicate rows: %w", err)
+	}
+	fp := d.hash(b)
+	for _, k := range d.seen[fp] {
+		if reflect.DeepEqual(k, key) {
+			d.Removed++
+			return true, nil
+		}
+	}
+	d.seen[fp] = append(d.seen[fp], key)
+	return false, nil
+}
+
+// Result returns the deduplicated rows along with the number of duplicates
+// that were removed.
+func (d *Deduper) Result(rows []any) any {
+	return map[string]any{
+		"rows":              rows,
+		"duplicatesRemoved": d.Removed,
+	}
+}
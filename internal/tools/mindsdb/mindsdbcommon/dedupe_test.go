@@ -0,0 +1,151 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mindsdbcommon
+
+import (
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+)
+
+// dedupe runs rows through a Deduper and returns its result.
+func dedupe(t *testing.T, d *Deduper, rows []map[string]any) any {
+	t.Helper()
+	var out []any
+	for _, row := range rows {
+		dup, err := d.Duplicate(row)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !dup {
+			out = append(out, row)
+		}
+	}
+	return d.Result(out)
+}
+
+func TestDeduper(t *testing.T) {
+	columns := []string{"id", "name", "region"}
+	rows := []map[string]any{
+		{"id": int64(1), "name": "ada", "region": "eu"},
+		{"id": int64(1), "name": "ada", "region": "eu"},
+		{"id": int64(1), "name": "ada", "region": "us"},
+		{"id": int64(2), "name": nil, "region": nil},
+		{"id": int64(2), "name": nil, "region": nil},
+		{"id": int64(3), "name": "null", "region": nil},
+	}
+	tcs := []struct {
+		desc string
+		d    Deduplicate
+		want any
+	}{
+		{
+			desc: "all columns",
+			d:    Deduplicate{All: true},
+			want: map[string]any{
+				"rows":              []any{rows[0], rows[2], rows[3], rows[5]},
+				"duplicatesRemoved": 2,
+			},
+		},
+		{
+			desc: "keyed columns keep the first occurrence",
+			d:    Deduplicate{Columns: []string{"id"}},
+			want: map[string]any{
+				"rows":              []any{rows[0], rows[3], rows[5]},
+				"duplicatesRemoved": 3,
+			},
+		},
+		{
+			desc: "keys containing NULL",
+			d:    Deduplicate{Columns: []string{"name"}},
+			want: map[string]any{
+				"rows":              []any{rows[0], rows[3], rows[5]},
+				"duplicatesRemoved": 3,
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			d, err := tc.d.NewDeduper(columns)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, dedupe(t, d, rows)); diff != "" {
+				t.Fatalf("unexpected result: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestDeduperHashCollision(t *testing.T) {
+	d, err := (&Deduplicate{All: true}).NewDeduper([]string{"id"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// every key shares the same fingerprint
+	d.hash = func([]byte) uint64 { return 42 }
+	rows := []map[string]any{{"id": int64(1)}, {"id": int64(2)}, {"id": int64(1)}, {"id": int64(3)}}
+	want := map[string]any{
+		"rows":              []any{rows[0], rows[1], rows[3]},
+		"duplicatesRemoved": 1,
+	}
+	if diff := cmp.Diff(want, dedupe(t, d, rows)); diff != "" {
+		t.Fatalf("unexpected result: diff %v", diff)
+	}
+}
+
+func TestNewDeduperUnknownColumn(t *testing.T) {
+	_, err := (&Deduplicate{Columns: []string{"id", "email"}}).NewDeduper([]string{"id", "name"})
+	want := `unable to deduplicate rows: column "email" is not in the result`
+	if err == nil || err.Error() != want {
+		t.Fatalf("unexpected error: got %v, want %q", err, want)
+	}
+}
+
+func TestUnmarshalDeduplicate(t *testing.T) {
+	tcs := []struct {
+		desc string
+		in   string
+		want Deduplicate
+		err  string
+	}{
+		{desc: "all", in: `all`, want: Deduplicate{All: true}},
+		{desc: "columns", in: `[id, region]`, want: Deduplicate{Columns: []string{"id", "region"}}},
+		{desc: "unknown mode", in: `some`, err: `must be "all" or a list of column names, got "some"`},
+		{desc: "empty list", in: `[]`, err: "must not be empty"},
+		{desc: "repeated column", in: `[id, id]`, err: `column "id" is listed more than once`},
+		{desc: "invalid type", in: `{id: 1}`, err: `must be "all" or a list of column names`},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			var got Deduplicate
+			err := yaml.Unmarshal([]byte(tc.in), &got)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("unexpected error: got %v, want substring %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected result: diff %v", diff)
+			}
+		})
+	}
+}
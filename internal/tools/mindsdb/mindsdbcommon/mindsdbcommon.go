@@ -0,0 +1,187 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mindsdbcommon
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// errorHint pairs a pattern matching the message of a MindsDB error with a
+// hint on how to resolve it.
+type errorHint struct {
+	pattern *regexp.Regexp
+	hint    string
+}
+
+// errorHints are matched in order against the message of an error, and the
+// hint of the first match is used. More specific patterns must come first.
+var errorHints = []errorHint{
+	{
+		// e.g. "Knowledge base 'docs' not found", "KNOWLEDGE_BASE docs does not exist"
+		pattern: regexp.MustCompile(`(?i)\bknowledge[ _]bases?\b.*\b(not found|does not exist|doesn't exist)`),
+		hint:    "run SHOW KNOWLEDGE_BASES to list available knowledge bases",
+	},
+	{
+		// e.g. "Table 'files.sales' not found", "File 'sales' does not exist"
+		pattern: regexp.MustCompile(`(?i)\bfiles?\b.*\b(not found|does not exist|doesn't exist)`),
+		hint:    "run SHOW TABLES FROM files to list the uploaded files",
+	},
+	{
+		// e.g. "Model 'churn' is still training", "model status is generating"
+		pattern: regexp.MustCompile(`(?i)\bmodel\b.*\b(training|generating|not ready|status is error)\b`),
+		hint:    "check model status via SELECT name, status, error FROM information_schema.models, and retry once it is complete",
+	},
+	{
+		// e.g. "Model 'churn' not found", "Predictor 'churn' does not exist"
+		pattern: regexp.MustCompile(`(?i)\b(model|predictor)\b.*\b(not found|does not exist|doesn't exist)`),
+		hint:    "run SHOW MODELS to list available models",
+	},
+	{
+		// e.g. "Database 'pg' does not exist", "Unknown database 'pg'",
+		// "Integration 'pg' not found"
+		pattern: regexp.MustCompile(`(?i)\b(database|integration|project|datasource)\b.*\b(not found|does not exist|doesn't exist)|\bunknown (database|integration|project)\b`),
+		hint:    "run SHOW DATABASES to list available integrations",
+	},
+	{
+		// e.g. "Table not found: xyz", "Table 'pg.xyz' doesn't exist"
+		pattern: regexp.MustCompile(`(?i)\btable\b.*\b(not found|does not exist|doesn't exist)|\bunknown table\b`),
+		hint:    "run SHOW TABLES FROM <integration> to list the tables of an integration",
+	},
+	{
+		// e.g. "Can't connect to the data source", "connection refused"
+		pattern: regexp.MustCompile(`(?i)\b(can't|cannot|could not|couldn't|unable to|failed to) connect\b|\bconnection (refused|reset|timed out)\b`),
+		hint:    "check that the data source of the integration is reachable and its connection parameters are correct, then recreate it with CREATE DATABASE if needed",
+	},
+	{
+		// e.g. "Statement is not supported", "Unsupported command: ALTER"
+		pattern: regexp.MustCompile(`(?i)\b(not (supported|implemented)|unsupported)\b`),
+		hint:    "the statement is not supported over the MySQL protocol; rewrite it using MindsDB SQL such as SELECT, SHOW, DESCRIBE, CREATE MODEL or CREATE DATABASE",
+	},
+}
+
+// EnrichError wraps err in a tools.HintError when its message matches a
+// common MindsDB failure. Other errors are returned as is.
+func EnrichError(err error) error {
+	if err == nil {
+		return nil
+	}
+	msg := err.Error()
+	for _, h := range errorHints {
+		if h.pattern.MatchString(msg) {
+			return &tools.HintError{Err: err, Hint: h.hint}
+		}
+	}
+	return err
+}
+
+// QuoteJSONParams returns a copy of paramsMap in which the values of json
+// parameters are replaced by single-quoted SQL string literals of their JSON
+// text, so they can be interpolated into statements through templateParameters.
+func QuoteJSONParams(paramsMap map[string]any) map[string]any {
+	rtn := make(map[string]any, len(paramsMap))
+	for k, v := range paramsMap {
+		if jv, ok := v.(tools.JSONValue); ok {
+			v = QuoteString(jv.String())
+		}
+		rtn[k] = v
+	}
+	return rtn
+}
+
+// QuoteString returns s as a single-quoted string literal, escaping
+// backslashes and single quotes as MindsDB's MySQL dialect requires.
+func QuoteString(s string) string {
+	var sb strings.Builder
+	sb.Grow(escapedLen(s) + 2)
+	sb.WriteByte('\'')
+	writeEscaped(&sb, s)
+	sb.WriteByte('\'')
+	return sb.String()
+}
+
+// escapedLen returns the length of s once escaped.
+func escapedLen(s string) int {
+	return len(s) + strings.Count(s, `\`) + strings.Count(s, `'`)
+}
+
+// writeEscaped writes s to sb, doubling its backslashes and single quotes, in
+// a single pass over s. Both are ASCII, so they never occur within the
+// multi-byte sequences of other characters.
+func writeEscaped(sb *strings.Builder, s string) {
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c == '\\' || c == '\'' {
+			sb.WriteString(s[start : i+1])
+			sb.WriteByte(c)
+			start = i + 1
+		}
+	}
+	sb.WriteString(s[start:])
+}
+
+// Conn returns a connection of pool and a function that releases it. If
+// database is set, it is made the default database of the session with USE,
+// so that unqualified table names resolve against it whatever the database of
+// the source is. Such a connection is discarded on release rather than
+// returned to the pool, so the USE does not leak into other tools.
+func Conn(ctx context.Context, pool *sql.DB, database string) (*sql.Conn, func(), error) {
+	conn, err := pool.Conn(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get connection: %w", err)
+	}
+	if database == "" {
+		return conn, func() { conn.Close() }, nil
+	}
+
+	release := func() {
+		// returning driver.ErrBadConn makes database/sql close the connection
+		_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+		conn.Close()
+	}
+	if _, err := conn.ExecContext(ctx, "USE "+QuoteIdentifier(database)); err != nil {
+		release()
+		return nil, nil, EnrichError(fmt.Errorf("unable to use database %q: %w", database, err))
+	}
+	return conn, release, nil
+}
+
+// QuoteIdentifier returns s as a backtick-quoted identifier.
+func QuoteIdentifier(s string) string {
+	return "`" + strings.ReplaceAll(s, "`", "``") + "`"
+}
+
+// nameRegex matches the names of MindsDB objects, optionally qualified by the
+// project or integration holding them, such as `docs_kb` or `mindsdb.docs_kb`.
+var nameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// QuoteName validates name, optionally qualified by a project or an
+// integration, and returns it with each of its parts backtick-quoted.
+func QuoteName(name string) (string, error) {
+	if !nameRegex.MatchString(name) {
+		return "", fmt.Errorf("invalid name %q: it must start with a letter or an underscore, followed by letters, digits or underscores, and may be qualified as project.name", name)
+	}
+	parts := strings.Split(name, ".")
+	for i, p := range parts {
+		parts[i] = QuoteIdentifier(p)
+	}
+	return strings.Join(parts, "."), nil
+}
@@ -0,0 +1,323 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mindsdbcommon_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/mindsdb/mindsdbcommon"
+)
+
+func TestEnrichError(t *testing.T) {
+	tcs := []struct {
+		desc string
+		err  string
+		hint string
+	}{
+		{
+			desc: "unknown database",
+			err:  "Error 1149 (42000): Database 'my_pg' does not exist",
+			hint: "SHOW DATABASES",
+		},
+		{
+			desc: "unknown integration",
+			err:  "Error 1149 (42000): Integration 'my_pg' not found",
+			hint: "SHOW DATABASES",
+		},
+		{
+			desc: "table not found",
+			err:  "Error 1149 (42000): Table not found: xyz",
+			hint: "SHOW TABLES FROM <integration>",
+		},
+		{
+			desc: "file table missing",
+			err:  "Error 1149 (42000): Table 'files.sales' not found",
+			hint: "SHOW TABLES FROM files",
+		},
+		{
+			desc: "knowledge base missing",
+			err:  "Error 1149 (42000): Knowledge base 'docs_kb' does not exist",
+			hint: "SHOW KNOWLEDGE_BASES",
+		},
+		{
+			desc: "model still training",
+			err:  "Error 1149 (42000): Model 'churn' is still training",
+			hint: "information_schema.models",
+		},
+		{
+			desc: "model generating",
+			err:  "Error 1149 (42000): The model status is generating, please wait",
+			hint: "information_schema.models",
+		},
+		{
+			desc: "model not found",
+			err:  "Error 1149 (42000): Model 'churn' not found",
+			hint: "SHOW MODELS",
+		},
+		{
+			desc: "integration connection failure",
+			err:  "Error 1149 (42000): Can't connect to db: connection refused",
+			hint: "connection parameters",
+		},
+		{
+			desc: "unsupported statement",
+			err:  "Error 1149 (42000): Statement is not supported: LOCK TABLES",
+			hint: "MySQL protocol",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			raw := errors.New(tc.err)
+			err := mindsdbcommon.EnrichError(fmt.Errorf("unable to execute query: %w", raw))
+			if !errors.Is(err, raw) {
+				t.Fatalf("enriched error does not wrap the original error")
+			}
+			if got := err.Error(); got != "unable to execute query: "+tc.err {
+				t.Fatalf("unexpected message: got %q", got)
+			}
+			if hint := tools.ErrorHint(err); !strings.Contains(hint, tc.hint) {
+				t.Fatalf("unexpected hint: got %q, want substring %q", hint, tc.hint)
+			}
+		})
+	}
+}
+
+func TestEnrichErrorNoMatch(t *testing.T) {
+	raw := errors.New("Error 1064 (42000): You have an error in your SQL syntax")
+	if err := mindsdbcommon.EnrichError(raw); err != raw {
+		t.Fatalf("unexpected error: got %v, want the original error", err)
+	}
+	if err := mindsdbcommon.EnrichError(nil); err != nil {
+		t.Fatalf("unexpected error: got %v, want nil", err)
+	}
+}
+
+func TestQuoteJSONParams(t *testing.T) {
+	jv, err := tools.NewJSONValue(map[string]any{"note": `it's a \ path`})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	got := mindsdbcommon.QuoteJSONParams(map[string]any{"payload": jv, "table": "files.sales"})
+	want := map[string]any{
+		"payload": `'{"note":"it''s a \\\\ path"}'`,
+		"table":   "files.sales",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected params: diff %v", diff)
+	}
+
+	stmt, err := tools.ResolveTemplateParams(
+		tools.Parameters{tools.NewJSONParameter("payload", "a json value")},
+		"SELECT * FROM mindsdb.models WHERE options = {{.payload}}",
+		got,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := `SELECT * FROM mindsdb.models WHERE options = '{"note":"it''s a \\\\ path"}'`; stmt != want {
+		t.Fatalf("unexpected statement: got %q, want %q", stmt, want)
+	}
+}
+
+// legacyQuoteString is the former implementation of QuoteString, which the
+// single-pass one must agree with byte for byte.
+func legacyQuoteString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `'`, `''`)
+	return "'" + s + "'"
+}
+
+// quoteCorpus holds the strings the golden tests of the escaping run over.
+var quoteCorpus = []string{
+	"",
+	"plain",
+	"'",
+	`\`,
+	`''`,
+	`\'`,
+	`'\`,
+	`it's a \ path`,
+	`C:\Users\o'brien\`,
+	`{"note":"say \"hi\"","path":"a\\b"}`,
+	"line\nbreak\ttab\r\x00nul",
+	"ünïcødé 'quotes' and \\ backslashes ✓",
+	"日本語の'テキスト'",
+	"\xff\xfe' invalid \\ utf-8 \xc3",
+	"'; DROP TABLE users; --",
+	strings.Repeat(`'a\`, 1000),
+	strings.Repeat("x", 4096),
+}
+
+func TestQuoteString(t *testing.T) {
+	golden := map[string]string{
+		"":              `''`,
+		"plain":         `'plain'`,
+		`it's a \ path`: `'it''s a \\ path'`,
+		`'\`:            `'''\\'`,
+	}
+	for in, want := range golden {
+		if got := mindsdbcommon.QuoteString(in); got != want {
+			t.Errorf("QuoteString(%q) = %q, want %q", in, got, want)
+		}
+	}
+	for _, in := range quoteCorpus {
+		want := legacyQuoteString(in)
+		if got := mindsdbcommon.QuoteString(in); got != want {
+			t.Errorf("QuoteString(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// BenchmarkQuoteJSONParams quotes the values of a statement of 100 json
+// parameters, each a multi-kilobyte list such as agents send for IN clauses.
+func BenchmarkQuoteJSONParams(b *testing.B) {
+	ids := make([]string, 200)
+	for i := range ids {
+		ids[i] = fmt.Sprintf(`o'neil\%d`, i)
+	}
+	params := make(map[string]any, 100)
+	for i := 0; i < 100; i++ {
+		jv, err := tools.NewJSONValue(ids)
+		if err != nil {
+			b.Fatalf("unexpected error: %s", err)
+		}
+		params[fmt.Sprintf("p%d", i)] = jv
+	}
+
+	b.Run("legacy", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, v := range params {
+				_ = legacyQuoteString(v.(tools.JSONValue).String())
+			}
+		}
+	})
+	b.Run("single-pass", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = mindsdbcommon.QuoteJSONParams(params)
+		}
+	})
+}
+
+// recordingDriver is a database/sql driver whose connections record the
+// statements they execute and whether they were closed.
+type recordingDriver struct {
+	mu    sync.Mutex
+	conns []*recordingConn
+}
+
+func (d *recordingDriver) Open(string) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	c := &recordingConn{}
+	d.conns = append(d.conns, c)
+	return c, nil
+}
+
+type recordingConn struct {
+	execs  []string
+	closed bool
+}
+
+func (c *recordingConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *recordingConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func (c *recordingConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *recordingConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.execs = append(c.execs, query)
+	return driver.RowsAffected(0), nil
+}
+
+func TestConn(t *testing.T) {
+	tcs := []struct {
+		desc       string
+		database   string
+		wantExecs  []string
+		wantClosed bool
+	}{
+		{
+			desc:     "without default database",
+			database: "",
+		},
+		{
+			desc:       "with default database",
+			database:   "files",
+			wantExecs:  []string{"USE `files`"},
+			wantClosed: true,
+		},
+		{
+			desc:       "with quoted default database",
+			database:   "my`db",
+			wantExecs:  []string{"USE `my``db`"},
+			wantClosed: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			d := &recordingDriver{}
+			pool := sql.OpenDB(connector{d})
+			defer pool.Close()
+
+			_, release, err := mindsdbcommon.Conn(context.Background(), pool, tc.database)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			release()
+
+			if len(d.conns) != 1 {
+				t.Fatalf("got %d connections, want 1", len(d.conns))
+			}
+			c := d.conns[0]
+			if diff := cmp.Diff(tc.wantExecs, c.execs); diff != "" {
+				t.Errorf("incorrect statements: diff %v", diff)
+			}
+			// a connection whose database was changed must not return to the pool
+			if c.closed != tc.wantClosed {
+				t.Errorf("got closed %v, want %v", c.closed, tc.wantClosed)
+			}
+		})
+	}
+}
+
+type connector struct {
+	d *recordingDriver
+}
+
+func (c connector) Connect(context.Context) (driver.Conn, error) {
+	return c.d.Open("")
+}
+
+func (c connector) Driver() driver.Driver {
+	return c.d
+}
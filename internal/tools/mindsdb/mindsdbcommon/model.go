@@ -0,0 +1,149 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mindsdbcommon
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// DefaultProject is the project that MindsDB creates models in unless told
+// otherwise.
+const DefaultProject = "mindsdb"
+
+// ModelParameters returns the parameters naming a model of the model
+// lifecycle tools. The project is only a parameter if project, the project
+// the tool is restricted to, is empty.
+func ModelParameters(project string) tools.Parameters {
+	nameParameter := tools.NewStringParameter("name", "The name of the model.")
+	if project != "" {
+		return tools.Parameters{nameParameter}
+	}
+	projectParameter := tools.NewStringParameterWithDefault("project", DefaultProject, "The project holding the model.")
+	return tools.Parameters{nameParameter, projectParameter}
+}
+
+// ModelTarget returns the project and the name of the model named by the
+// parameters of paramsMap. See ModelParameters.
+func ModelTarget(project string, paramsMap map[string]any) (string, string, error) {
+	name, ok := paramsMap["name"].(string)
+	if !ok {
+		return "", "", fmt.Errorf("unable to get cast %s", paramsMap["name"])
+	}
+	if project == "" {
+		if project, ok = paramsMap["project"].(string); !ok {
+			return "", "", fmt.Errorf("unable to get cast %s", paramsMap["project"])
+		}
+	}
+	return project, name, nil
+}
+
+// QuoteModel validates the names of a model and of its project, and returns
+// the backtick-quoted name of the model qualified by its project.
+func QuoteModel(project, name string) (string, error) {
+	if err := ValidateProject(project); err != nil {
+		return "", err
+	}
+	if !identifierRegex.MatchString(name) {
+		return "", fmt.Errorf("invalid model name %q: it must start with a letter or an underscore, followed by letters, digits or underscores", name)
+	}
+	return QuoteIdentifier(project) + "." + QuoteIdentifier(name), nil
+}
+
+// modelsQuery lists the names of the models of a project.
+const modelsQuery = "SELECT NAME FROM information_schema.models WHERE PROJECT = ?"
+
+// Models returns the names of the models of project.
+func Models(ctx context.Context, pool *sql.DB, project string) ([]string, error) {
+	results, err := pool.QueryContext(ctx, modelsQuery, project)
+	if err != nil {
+		return nil, EnrichError(fmt.Errorf("unable to list models: %w", err))
+	}
+	defer results.Close()
+
+	var models []string
+	for results.Next() {
+		var name sql.NullString
+		if err := results.Scan(&name); err != nil {
+			return nil, fmt.Errorf("unable to parse row: %w", err)
+		}
+		models = append(models, name.String)
+	}
+	if err := results.Err(); err != nil {
+		return nil, EnrichError(fmt.Errorf("unable to list models: %w", err))
+	}
+	return models, nil
+}
+
+// CheckModel returns an error listing the models of project unless one of
+// them is name.
+func CheckModel(project, name string, models []string) error {
+	for _, m := range models {
+		if strings.EqualFold(m, name) {
+			return nil
+		}
+	}
+	err := fmt.Errorf("model %q does not exist in project %q", name, project)
+	if len(models) == 0 {
+		return &tools.HintError{Err: err, Hint: fmt.Sprintf("project %q has no models yet; create one with CREATE MODEL", project)}
+	}
+	return &tools.HintError{Err: err, Hint: fmt.Sprintf("the models of project %q are %s", project, strings.Join(models, ", "))}
+}
+
+// RequireModel returns an error listing the models of project unless name is
+// one of them, so that tools acting on a model do not act on another object
+// of the same name.
+func RequireModel(ctx context.Context, pool *sql.DB, project, name string) error {
+	models, err := Models(ctx, pool, project)
+	if err != nil {
+		return err
+	}
+	return CheckModel(project, name, models)
+}
+
+// StringRows returns the rows of results, keyed by the lowercased names of
+// their columns, with their values as strings. NULL values are left out.
+func StringRows(results *sql.Rows) ([]map[string]string, error) {
+	cols, err := results.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve rows column name: %w", err)
+	}
+	rawValues := make([]sql.NullString, len(cols))
+	values := make([]any, len(cols))
+	for i := range rawValues {
+		values[i] = &rawValues[i]
+	}
+	var rows []map[string]string
+	for results.Next() {
+		if err := results.Scan(values...); err != nil {
+			return nil, fmt.Errorf("unable to parse row: %w", err)
+		}
+		row := make(map[string]string, len(cols))
+		for i, c := range cols {
+			if rawValues[i].Valid {
+				row[strings.ToLower(c)] = rawValues[i].String
+			}
+		}
+		rows = append(rows, row)
+	}
+	if err := results.Err(); err != nil {
+		return nil, EnrichError(fmt.Errorf("errors encountered during row iteration: %w", err))
+	}
+	return rows, nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mindsdbcommon_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/mindsdb/mindsdbcommon"
+)
+
+func TestQuoteModel(t *testing.T) {
+	got, err := mindsdbcommon.QuoteModel("sales", "churn_v2")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "`sales`.`churn_v2`"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	tcs := []struct {
+		project string
+		name    string
+		want    string
+	}{
+		{project: "sales", name: "", want: "invalid model name"},
+		{project: "sales", name: "churn`; DROP MODEL x", want: "invalid model name"},
+		{project: "sales", name: "mindsdb.churn", want: "invalid model name"},
+		{project: "sales; --", name: "churn", want: "invalid project name"},
+	}
+	for _, tc := range tcs {
+		if _, err := mindsdbcommon.QuoteModel(tc.project, tc.name); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("QuoteModel(%q, %q): got error %v, want %q", tc.project, tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestCheckModel(t *testing.T) {
+	if err := mindsdbcommon.CheckModel("sales", "Churn", []string{"leads", "churn"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	err := mindsdbcommon.CheckModel("sales", "forecast", []string{"leads", "churn"})
+	if err == nil || !strings.Contains(err.Error(), `model "forecast" does not exist in project "sales"`) {
+		t.Fatalf("got error %v, want a missing model", err)
+	}
+	if hint := tools.ErrorHint(err); hint != `the models of project "sales" are leads, churn` {
+		t.Fatalf("unexpected hint %q", hint)
+	}
+
+	err = mindsdbcommon.CheckModel("sales", "forecast", nil)
+	if hint := tools.ErrorHint(err); !strings.Contains(hint, "has no models yet") {
+		t.Fatalf("unexpected hint %q", hint)
+	}
+}
+
+func TestModelTarget(t *testing.T) {
+	if got := len(mindsdbcommon.ModelParameters("")); got != 2 {
+		t.Fatalf("got %d parameters, want name and project", got)
+	}
+	if got := len(mindsdbcommon.ModelParameters("sales")); got != 1 {
+		t.Fatalf("got %d parameters, want only name", got)
+	}
+
+	project, name, err := mindsdbcommon.ModelTarget("", map[string]any{"name": "churn", "project": "marketing"})
+	if err != nil || project != "marketing" || name != "churn" {
+		t.Fatalf("got %q, %q, %v, want marketing, churn", project, name, err)
+	}
+	// the project of the tool cannot be overridden
+	project, name, err = mindsdbcommon.ModelTarget("sales", map[string]any{"name": "churn", "project": "marketing"})
+	if err != nil || project != "sales" || name != "churn" {
+		t.Fatalf("got %q, %q, %v, want sales, churn", project, name, err)
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mindsdbcommon
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// identifierRegex matches the unqualified names of MindsDB objects.
+var identifierRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ValidateProject returns an error unless project is a valid project name.
+func ValidateProject(project string) error {
+	if !identifierRegex.MatchString(project) {
+		return fmt.Errorf("invalid project name %q: it must start with a letter or an underscore, followed by letters, digits or underscores", project)
+	}
+	return nil
+}
+
+// Qualify returns name qualified by project, unless name is already
+// qualified or project is empty.
+func Qualify(project, name string) string {
+	if project == "" || strings.Contains(name, ".") {
+		return name
+	}
+	return project + "." + name
+}
+
+// projectsQuery lists the projects of MindsDB, which it lists among its
+// databases along with the integrations.
+const projectsQuery = "SELECT NAME FROM information_schema.databases WHERE TYPE = 'project'"
+
+// Projects returns the names of the projects of MindsDB.
+func Projects(ctx context.Context, conn *sql.Conn) ([]string, error) {
+	results, err := conn.QueryContext(ctx, projectsQuery)
+	if err != nil {
+		return nil, EnrichError(fmt.Errorf("unable to list projects: %w", err))
+	}
+	defer results.Close()
+
+	var projects []string
+	for results.Next() {
+		var name sql.NullString
+		if err := results.Scan(&name); err != nil {
+			return nil, fmt.Errorf("unable to parse row: %w", err)
+		}
+		projects = append(projects, name.String)
+	}
+	if err := results.Err(); err != nil {
+		return nil, EnrichError(fmt.Errorf("unable to list projects: %w", err))
+	}
+	return projects, nil
+}
+
+// objectRegex matches the names following the keywords of MindsDB objects,
+// such as CREATE MODEL sales.churn or DROP PROJECT sales, which the
+// references of a tools.Scope do not cover.
+var objectRegex = regexp.MustCompile("(?i)\\b(project|model|predictor|knowledge_base|job|agent|skill|chatbot|trigger)\\s+(?:if\\s+(?:not\\s+)?exists\\s+)?(" + nameExpr + ")(?:\\s*\\.\\s*(" + nameExpr + "))?")
+
+// nameExpr matches a name, possibly backtick-quoted.
+const nameExpr = "`(?:[^`]|``)+`|[A-Za-z_][A-Za-z0-9_]*"
+
+// CheckProject returns an error if statement references one of projects
+// other than project. References to integrations, which are not projects,
+// are let through.
+func CheckProject(statement, project string, projects []string) error {
+	refs := tools.MySQLDialect.References(statement)
+	for _, m := range objectRegex.FindAllStringSubmatch(statement, -1) {
+		// the name of a project, or the qualifier of the name of an object
+		if strings.EqualFold(m[1], "project") || m[3] != "" {
+			refs = append(refs, unquote(m[2]))
+		}
+	}
+	for _, ref := range refs {
+		if strings.EqualFold(ref, project) {
+			continue
+		}
+		if slices.ContainsFunc(projects, func(p string) bool { return strings.EqualFold(p, ref) }) {
+			return &tools.HintError{
+				Err:  fmt.Errorf("statement references project %q, but the tool is restricted to project %q", ref, project),
+				Hint: fmt.Sprintf("leave the names of the models and tables of project %q unqualified, or qualify them as %s.name", project, project),
+			}
+		}
+	}
+	return nil
+}
+
+// unquote returns the name of the possibly backtick-quoted identifier s.
+func unquote(s string) string {
+	if strings.HasPrefix(s, "`") {
+		return strings.ReplaceAll(s[1:len(s)-1], "``", "`")
+	}
+	return s
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mindsdbcommon_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/mindsdb/mindsdbcommon"
+)
+
+func TestQualify(t *testing.T) {
+	tcs := []struct {
+		project string
+		name    string
+		want    string
+	}{
+		{project: "", name: "churn", want: "churn"},
+		{project: "sales", name: "churn", want: "sales.churn"},
+		{project: "sales", name: "mindsdb.churn", want: "mindsdb.churn"},
+	}
+	for _, tc := range tcs {
+		if got := mindsdbcommon.Qualify(tc.project, tc.name); got != tc.want {
+			t.Errorf("Qualify(%q, %q) = %q, want %q", tc.project, tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestValidateProject(t *testing.T) {
+	for _, p := range []string{"sales", "_team_2"} {
+		if err := mindsdbcommon.ValidateProject(p); err != nil {
+			t.Errorf("unexpected error for %q: %s", p, err)
+		}
+	}
+	for _, p := range []string{"", "2sales", "mindsdb.sales", "sales; DROP PROJECT x"} {
+		if err := mindsdbcommon.ValidateProject(p); err == nil {
+			t.Errorf("expected an error for %q", p)
+		}
+	}
+}
+
+func TestCheckProject(t *testing.T) {
+	projects := []string{"mindsdb", "sales", "Marketing"}
+	tcs := []struct {
+		desc      string
+		statement string
+		wantRef   string
+	}{
+		{desc: "unqualified model", statement: "SELECT * FROM churn WHERE age = 30"},
+		{desc: "own project", statement: "SELECT * FROM sales.churn JOIN `SALES`.leads"},
+		{desc: "integration", statement: "SELECT * FROM my_pg.orders AS o JOIN churn AS m"},
+		{desc: "qualified column", statement: "SELECT m.churn FROM churn AS m"},
+		{desc: "other project", statement: "SELECT * FROM mindsdb.churn", wantRef: "mindsdb"},
+		{desc: "other project in a join", statement: "SELECT * FROM my_pg.orders JOIN `marketing`.leads", wantRef: "marketing"},
+		{desc: "use", statement: "USE mindsdb; SELECT * FROM churn", wantRef: "mindsdb"},
+		{desc: "drop project", statement: "DROP PROJECT IF EXISTS `Marketing`", wantRef: "Marketing"},
+		{desc: "create model in other project", statement: "CREATE MODEL mindsdb.churn FROM my_pg (SELECT * FROM customers) PREDICT churn", wantRef: "mindsdb"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := mindsdbcommon.CheckProject(tc.statement, "sales", projects)
+			if tc.wantRef == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), `references project "`+tc.wantRef+`"`) {
+				t.Fatalf("got error %v, want one for project %q", err, tc.wantRef)
+			}
+			if tools.ErrorHint(err) == "" {
+				t.Fatalf("expected a hint")
+			}
+		})
+	}
+}
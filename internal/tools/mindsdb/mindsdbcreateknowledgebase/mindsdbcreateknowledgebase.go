@@ -0,0 +1,250 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mindsdbcreateknowledgebase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/mindsdb"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/mindsdb/mindsdbcommon"
+)
+
+const kind string = "mindsdb-create-knowledge-base"
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	MindsDBPool() *sql.DB
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &mindsdb.Source{}
+
+var compatibleSources = [...]string{mindsdb.SourceKind}
+
+type Config struct {
+	Name         string   `yaml:"name" validate:"required"`
+	Kind         string   `yaml:"kind" validate:"required"`
+	Source       string   `yaml:"source" validate:"required"`
+	Description  string   `yaml:"description" validate:"required"`
+	AuthRequired []string `yaml:"authRequired"`
+	// EmbeddingModel configures the model that embeds the content of the
+	// knowledge bases, such as its provider, model_name and api_key.
+	EmbeddingModel map[string]any `yaml:"embeddingModel" validate:"required"`
+	// RerankingModel, if set, configures the model that reranks the results
+	// of queries.
+	RerankingModel map[string]any `yaml:"rerankingModel"`
+	// Storage, if set, is the table of a vector database integration that
+	// holds the embeddings, such as `my_pgvector.docs`. MindsDB uses its
+	// default vector store otherwise.
+	Storage         string   `yaml:"storage"`
+	MetadataColumns []string `yaml:"metadataColumns"`
+	ContentColumns  []string `yaml:"contentColumns"`
+	IDColumn        string   `yaml:"idColumn"`
+	// Project, if set, is the project that unqualified knowledge base names
+	// are qualified by, instead of the default mindsdb project.
+	Project string `yaml:"project"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	if cfg.Project != "" {
+		if err := mindsdbcommon.ValidateProject(cfg.Project); err != nil {
+			return nil, err
+		}
+	}
+
+	// the USING clause does not depend on the invocation, so it is built
+	// and validated once
+	using, err := usingClause(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config of tool %q: %w", cfg.Name, err)
+	}
+
+	nameParameter := tools.NewStringParameter("name", "The name of the knowledge base to create, optionally qualified by its project as project.name. It must start with a letter or an underscore, followed by letters, digits or underscores.")
+	parameters := tools.Parameters{nameParameter}
+
+	inputSchema, _ := parameters.McpManifest()
+	mcpManifest := tools.McpManifest{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		InputSchema: inputSchema,
+	}
+
+	// finish tool setup
+	t := Tool{
+		Name:         cfg.Name,
+		Kind:         kind,
+		Parameters:   parameters,
+		AuthRequired: cfg.AuthRequired,
+		Pool:         s.MindsDBPool(),
+		Using:        using,
+		Project:      cfg.Project,
+		manifest:     tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}
+	return t, nil
+}
+
+// usingClause returns the settings of the USING clause of the CREATE
+// KNOWLEDGE_BASE statements of cfg.
+func usingClause(cfg Config) (string, error) {
+	if len(cfg.EmbeddingModel) == 0 {
+		return "", fmt.Errorf("embeddingModel must not be empty")
+	}
+	var settings []string
+	model, err := json.Marshal(cfg.EmbeddingModel)
+	if err != nil {
+		return "", fmt.Errorf("invalid embeddingModel: %w", err)
+	}
+	settings = append(settings, "embedding_model = "+string(model))
+	if len(cfg.RerankingModel) > 0 {
+		model, err := json.Marshal(cfg.RerankingModel)
+		if err != nil {
+			return "", fmt.Errorf("invalid rerankingModel: %w", err)
+		}
+		settings = append(settings, "reranking_model = "+string(model))
+	}
+	if cfg.Storage != "" {
+		if !strings.Contains(cfg.Storage, ".") {
+			return "", fmt.Errorf("invalid storage %q: it must be qualified by its vector database as integration.table", cfg.Storage)
+		}
+		storage, err := mindsdbcommon.QuoteName(cfg.Storage)
+		if err != nil {
+			return "", fmt.Errorf("invalid storage: %w", err)
+		}
+		settings = append(settings, "storage = "+storage)
+	}
+	if len(cfg.MetadataColumns) > 0 {
+		settings = append(settings, "metadata_columns = "+stringList(cfg.MetadataColumns))
+	}
+	if len(cfg.ContentColumns) > 0 {
+		settings = append(settings, "content_columns = "+stringList(cfg.ContentColumns))
+	}
+	if cfg.IDColumn != "" {
+		settings = append(settings, "id_column = "+mindsdbcommon.QuoteString(cfg.IDColumn))
+	}
+	return strings.Join(settings, ",\n    "), nil
+}
+
+// stringList returns values as a list of string literals.
+func stringList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = mindsdbcommon.QuoteString(v)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string           `yaml:"name"`
+	Kind         string           `yaml:"kind"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+	Project      string           `yaml:"project"`
+
+	Pool *sql.DB
+	// Using holds the settings of the USING clause of the statement.
+	Using       string
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
+	name, ok := paramsMap["name"].(string)
+	if !ok {
+		return nil, fmt.Errorf("unable to get cast %s", paramsMap["name"])
+	}
+	name = mindsdbcommon.Qualify(t.Project, name)
+	stmt, err := t.statement(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := t.Pool.ExecContext(ctx, stmt); err != nil {
+		return nil, mindsdbcommon.EnrichError(fmt.Errorf("unable to create knowledge base %q: %w", name, err))
+	}
+	return map[string]any{"knowledgeBase": name}, nil
+}
+
+// statement returns the CREATE KNOWLEDGE_BASE statement of the knowledge base
+// name.
+func (t Tool) statement(name string) (string, error) {
+	quoted, err := mindsdbcommon.QuoteName(name)
+	if err != nil {
+		return "", fmt.Errorf("invalid knowledge base name: %w", err)
+	}
+	return fmt.Sprintf("CREATE KNOWLEDGE_BASE %s\nUSING\n    %s", quoted, t.Using), nil
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return false
+}
@@ -0,0 +1,172 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mindsdbcreateknowledgebase
+
+import (
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+func TestParseFromYamlMindsDBCreateKnowledgeBase(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		create_kb:
+			kind: mindsdb-create-knowledge-base
+			source: my-mindsdb-instance
+			description: Create a knowledge base.
+			embeddingModel:
+				provider: openai
+				model_name: text-embedding-3-small
+				api_key: sk-test
+			storage: my_pgvector.docs
+			metadataColumns: [product]
+			contentColumns: [notes]
+			idColumn: order_id
+			project: support
+	`
+	want := server.ToolConfigs{
+		"create_kb": Config{
+			Name:         "create_kb",
+			Kind:         "mindsdb-create-knowledge-base",
+			Source:       "my-mindsdb-instance",
+			Description:  "Create a knowledge base.",
+			AuthRequired: []string{},
+			EmbeddingModel: map[string]any{
+				"provider":   "openai",
+				"model_name": "text-embedding-3-small",
+				"api_key":    "sk-test",
+			},
+			Storage:         "my_pgvector.docs",
+			MetadataColumns: []string{"product"},
+			ContentColumns:  []string{"notes"},
+			IDColumn:        "order_id",
+			Project:         "support",
+		},
+	}
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	if diff := cmp.Diff(want, got.Tools); diff != "" {
+		t.Fatalf("incorrect parse: diff %v", diff)
+	}
+}
+
+func TestStatement(t *testing.T) {
+	tcs := []struct {
+		desc string
+		cfg  Config
+		kb   string
+		want string
+	}{
+		{
+			desc: "embedding model only",
+			cfg:  Config{EmbeddingModel: map[string]any{"provider": "openai", "model_name": "text-embedding-3-small"}},
+			kb:   "docs_kb",
+			want: "CREATE KNOWLEDGE_BASE `docs_kb`\nUSING\n" +
+				`    embedding_model = {"model_name":"text-embedding-3-small","provider":"openai"}`,
+		},
+		{
+			desc: "every setting",
+			cfg: Config{
+				EmbeddingModel:  map[string]any{"provider": "openai", "api_key": `it's "secret"`},
+				RerankingModel:  map[string]any{"provider": "openai", "model_name": "gpt-4o"},
+				Storage:         "my_pgvector.docs",
+				MetadataColumns: []string{"product", "o'brien"},
+				ContentColumns:  []string{"notes"},
+				IDColumn:        "order_id",
+			},
+			kb: "sales.docs_kb",
+			want: "CREATE KNOWLEDGE_BASE `sales`.`docs_kb`\nUSING\n" +
+				`    embedding_model = {"api_key":"it's \"secret\"","provider":"openai"},` + "\n" +
+				`    reranking_model = {"model_name":"gpt-4o","provider":"openai"},` + "\n" +
+				"    storage = `my_pgvector`.`docs`,\n" +
+				"    metadata_columns = ['product', 'o''brien'],\n" +
+				"    content_columns = ['notes'],\n" +
+				"    id_column = 'order_id'",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			using, err := usingClause(tc.cfg)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			got, err := Tool{Using: using}.statement(tc.kb)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected statement (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestStatementInvalid(t *testing.T) {
+	tcs := []struct {
+		desc    string
+		cfg     Config
+		kb      string
+		wantErr string
+	}{
+		{
+			desc:    "missing embedding model",
+			cfg:     Config{},
+			kb:      "docs_kb",
+			wantErr: "embeddingModel must not be empty",
+		},
+		{
+			desc:    "unqualified storage",
+			cfg:     Config{EmbeddingModel: map[string]any{"provider": "openai"}, Storage: "docs"},
+			kb:      "docs_kb",
+			wantErr: `invalid storage "docs": it must be qualified by its vector database`,
+		},
+		{
+			desc:    "storage injection",
+			cfg:     Config{EmbeddingModel: map[string]any{"provider": "openai"}, Storage: "pg.docs; DROP DATABASE files"},
+			kb:      "docs_kb",
+			wantErr: "invalid storage: invalid name",
+		},
+		{
+			desc:    "knowledge base name injection",
+			cfg:     Config{EmbeddingModel: map[string]any{"provider": "openai"}},
+			kb:      "docs_kb USING engine = 'x'",
+			wantErr: "invalid knowledge base name: invalid name",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			using, err := usingClause(tc.cfg)
+			if err == nil {
+				_, err = Tool{Using: using}.statement(tc.kb)
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
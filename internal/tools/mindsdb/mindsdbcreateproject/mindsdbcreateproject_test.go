@@ -0,0 +1,82 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mindsdbcreateproject
+
+import (
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+func TestParseFromYamlMindsDBCreateProject(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		create_project:
+			kind: mindsdb-create-project
+			source: my-mindsdb-instance
+			description: Create a project.
+			ifNotExists: true
+	`
+	want := server.ToolConfigs{
+		"create_project": Config{
+			Name:         "create_project",
+			Kind:         "mindsdb-create-project",
+			Source:       "my-mindsdb-instance",
+			Description:  "Create a project.",
+			AuthRequired: []string{},
+			IfNotExists:  true,
+		},
+	}
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	if diff := cmp.Diff(want, got.Tools); diff != "" {
+		t.Fatalf("incorrect parse: diff %v", diff)
+	}
+}
+
+func TestStatement(t *testing.T) {
+	got, err := Tool{}.statement("sales")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "CREATE PROJECT `sales`"; got != want {
+		t.Fatalf("got statement %q, want %q", got, want)
+	}
+	got, err = Tool{IfNotExists: true}.statement("sales")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "CREATE PROJECT IF NOT EXISTS `sales`"; got != want {
+		t.Fatalf("got statement %q, want %q", got, want)
+	}
+
+	for _, name := range []string{"", "mindsdb.sales", "sales; DROP PROJECT mindsdb"} {
+		if _, err := (Tool{}).statement(name); err == nil || !strings.Contains(err.Error(), "invalid project name") {
+			t.Fatalf("got error %v for %q, want an invalid project name", err, name)
+		}
+	}
+}
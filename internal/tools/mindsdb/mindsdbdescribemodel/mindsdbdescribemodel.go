@@ -0,0 +1,253 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mindsdbdescribemodel
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/mindsdb"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/mindsdb/mindsdbcommon"
+)
+
+const kind string = "mindsdb-describe-model"
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	MindsDBPool() *sql.DB
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &mindsdb.Source{}
+
+var compatibleSources = [...]string{mindsdb.SourceKind}
+
+type Config struct {
+	Name         string   `yaml:"name" validate:"required"`
+	Kind         string   `yaml:"kind" validate:"required"`
+	Source       string   `yaml:"source" validate:"required"`
+	Description  string   `yaml:"description" validate:"required"`
+	AuthRequired []string `yaml:"authRequired"`
+	// Project, if set, is the only project whose models can be described.
+	// Otherwise the project is a parameter, defaulting to mindsdb.
+	Project string `yaml:"project"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	if cfg.Project != "" {
+		if err := mindsdbcommon.ValidateProject(cfg.Project); err != nil {
+			return nil, err
+		}
+	}
+
+	parameters := mindsdbcommon.ModelParameters(cfg.Project)
+	inputSchema, _ := parameters.McpManifest()
+	mcpManifest := tools.McpManifest{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		InputSchema: inputSchema,
+	}
+
+	// finish tool setup
+	t := Tool{
+		Name:         cfg.Name,
+		Kind:         kind,
+		Parameters:   parameters,
+		AuthRequired: cfg.AuthRequired,
+		Project:      cfg.Project,
+		Pool:         s.MindsDBPool(),
+		manifest:     tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string           `yaml:"name"`
+	Kind         string           `yaml:"kind"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+	Project      string           `yaml:"project"`
+
+	Pool        *sql.DB
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+// modelQuery selects the active version of a model of a project.
+const modelQuery = "SELECT * FROM information_schema.models WHERE PROJECT = ? AND NAME = ?"
+
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	project, name, err := mindsdbcommon.ModelTarget(t.Project, inv.Params.AsMap())
+	if err != nil {
+		return nil, err
+	}
+	quoted, err := mindsdbcommon.QuoteModel(project, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := mindsdbcommon.RequireModel(ctx, t.Pool, project, name); err != nil {
+		return nil, err
+	}
+
+	results, err := t.Pool.QueryContext(ctx, modelQuery, project, name)
+	if err != nil {
+		return nil, mindsdbcommon.EnrichError(fmt.Errorf("unable to describe model %q: %w", name, err))
+	}
+	defer results.Close()
+	rows, err := mindsdbcommon.StringRows(results)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("model %q of project %q has no active version", name, project)
+	}
+	out := describeModel(project, name, activeRow(rows))
+
+	// not every engine describes the features its models were trained on
+	if features, err := t.features(ctx, quoted); err == nil {
+		out["trainingColumns"] = features
+	}
+	return out, nil
+}
+
+// activeRow returns the row of the active version of a model, or the first
+// one if none is marked as active.
+func activeRow(rows []map[string]string) map[string]string {
+	for _, row := range rows {
+		if active := strings.ToLower(row["active"]); active == "1" || active == "true" {
+			return row
+		}
+	}
+	return rows[0]
+}
+
+// describeModel returns the description of a model from its row of
+// information_schema.models.
+func describeModel(project, name string, row map[string]string) map[string]any {
+	out := map[string]any{
+		"name":    name,
+		"project": project,
+		"status":  row["status"],
+	}
+	if v, ok := row["version"]; ok {
+		if n, err := strconv.Atoi(v); err == nil {
+			out["version"] = n
+		}
+	}
+	if v, ok := row["accuracy"]; ok {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			out["accuracy"] = f
+		}
+	}
+	for _, c := range []string{"engine", "predict", "error"} {
+		if v := row[c]; v != "" {
+			out[c] = v
+		}
+	}
+	return out
+}
+
+// featuresStatement returns the statement describing the columns that the
+// model quoted was trained on.
+func featuresStatement(quoted string) string {
+	return "DESCRIBE MODEL " + quoted + ".features"
+}
+
+// features returns the columns that the model quoted was trained on, with
+// their type and role.
+func (t Tool) features(ctx context.Context, quoted string) ([]map[string]string, error) {
+	results, err := t.Pool.QueryContext(ctx, featuresStatement(quoted))
+	if err != nil {
+		return nil, err
+	}
+	defer results.Close()
+	rows, err := mindsdbcommon.StringRows(results)
+	if err != nil {
+		return nil, err
+	}
+	features := make([]map[string]string, 0, len(rows))
+	for _, row := range rows {
+		feature := map[string]string{"column": row["column"]}
+		for _, c := range []string{"type", "role"} {
+			if v := row[c]; v != "" {
+				feature[c] = v
+			}
+		}
+		features = append(features, feature)
+	}
+	return features, nil
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return false
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mindsdbdescribemodel
+
+import (
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+func TestParseFromYamlMindsDBDescribeModel(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		model_tool:
+			kind: mindsdb-describe-model
+			source: my-mindsdb-instance
+			description: Manage models.
+			project: sales
+	`
+	want := server.ToolConfigs{
+		"model_tool": Config{
+			Name:         "model_tool",
+			Kind:         "mindsdb-describe-model",
+			Source:       "my-mindsdb-instance",
+			Description:  "Manage models.",
+			AuthRequired: []string{},
+			Project:      "sales",
+		},
+	}
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	if diff := cmp.Diff(want, got.Tools); diff != "" {
+		t.Fatalf("incorrect parse: diff %v", diff)
+	}
+}
+
+func TestFeaturesStatement(t *testing.T) {
+	if got, want := featuresStatement("`sales`.`churn`"), "DESCRIBE MODEL `sales`.`churn`.features"; got != want {
+		t.Fatalf("got statement %q, want %q", got, want)
+	}
+}
+
+func TestDescribeModel(t *testing.T) {
+	rows := []map[string]string{
+		{"name": "churn", "version": "1", "active": "0", "status": "complete", "accuracy": "0.81"},
+		{"name": "churn", "version": "2", "active": "1", "status": "complete", "accuracy": "0.87", "engine": "lightwood", "predict": "churned"},
+	}
+	got := describeModel("sales", "churn", activeRow(rows))
+	want := map[string]any{
+		"name":     "churn",
+		"project":  "sales",
+		"status":   "complete",
+		"version":  2,
+		"accuracy": 0.87,
+		"engine":   "lightwood",
+		"predict":  "churned",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected description (-want +got):\n%s", diff)
+	}
+
+	got = describeModel("sales", "churn", map[string]string{"status": "error", "error": "column churned not found"})
+	if got["error"] != "column churned not found" || got["accuracy"] != nil {
+		t.Fatalf("unexpected description of a failed model: %v", got)
+	}
+}
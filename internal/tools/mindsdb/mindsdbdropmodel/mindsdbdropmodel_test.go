@@ -0,0 +1,90 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mindsdbdropmodel
+
+import (
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/mindsdb"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+func TestParseFromYamlMindsDBDropModel(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		model_tool:
+			kind: mindsdb-drop-model
+			source: my-mindsdb-instance
+			description: Manage models.
+			project: sales
+	`
+	want := server.ToolConfigs{
+		"model_tool": Config{
+			Name:         "model_tool",
+			Kind:         "mindsdb-drop-model",
+			Source:       "my-mindsdb-instance",
+			Description:  "Manage models.",
+			AuthRequired: []string{},
+			Project:      "sales",
+		},
+	}
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	if diff := cmp.Diff(want, got.Tools); diff != "" {
+		t.Fatalf("incorrect parse: diff %v", diff)
+	}
+}
+
+func TestDropStatement(t *testing.T) {
+	got, err := dropStatement("sales", "churn")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "DROP MODEL `sales`.`churn`"; got != want {
+		t.Fatalf("got statement %q, want %q", got, want)
+	}
+	for _, name := range []string{"", "churn`", "churn; DROP PROJECT sales"} {
+		if _, err := dropStatement("sales", name); err == nil || !strings.Contains(err.Error(), "invalid model name") {
+			t.Fatalf("got error %v for %q, want an invalid model name", err, name)
+		}
+	}
+}
+
+func TestManifestDestructive(t *testing.T) {
+	cfg := Config{Name: "drop_model", Kind: "mindsdb-drop-model", Source: "my-mindsdb-instance", Description: "Drop a model."}
+	tool, err := cfg.Initialize(map[string]sources.Source{"my-mindsdb-instance": &mindsdb.Source{}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !tool.Manifest().Destructive {
+		t.Fatalf("expected the manifest to be marked as destructive")
+	}
+	if a := tool.McpManifest().Annotations; a == nil || !a.DestructiveHint {
+		t.Fatalf("expected a destructive hint, got %+v", a)
+	}
+}
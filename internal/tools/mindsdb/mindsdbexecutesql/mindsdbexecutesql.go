@@ -23,6 +23,7 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/sources/mindsdb"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/mindsdb/mindsdbcommon"
 	"github.com/googleapis/genai-toolbox/internal/tools/mysql/mysqlcommon"
 )
 
@@ -32,6 +33,7 @@ func init() {
 	if !tools.Register(kind, newConfig) {
 		panic(fmt.Sprintf("tool kind %q already registered", kind))
 	}
+	tools.RegisterOutputSchema(kind, tools.RowsOutputSchema)
 }
 
 func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
@@ -52,11 +54,27 @@ var _ compatibleSource = &mindsdb.Source{}
 var compatibleSources = [...]string{mindsdb.SourceKind}
 
 type Config struct {
-	Name         string   `yaml:"name" validate:"required"`
-	Kind         string   `yaml:"kind" validate:"required"`
-	Source       string   `yaml:"source" validate:"required"`
-	Description  string   `yaml:"description" validate:"required"`
-	AuthRequired []string `yaml:"authRequired"`
+	Name         string                     `yaml:"name" validate:"required"`
+	Kind         string                     `yaml:"kind" validate:"required"`
+	Source       string                     `yaml:"source" validate:"required"`
+	Description  string                     `yaml:"description" validate:"required"`
+	AuthRequired []string                   `yaml:"authRequired"`
+	Deduplicate  *mindsdbcommon.Deduplicate `yaml:"deduplicate"`
+	// DefaultDatabase, if set, is the database that unqualified table names
+	// resolve against, overriding the database of the source.
+	DefaultDatabase string `yaml:"defaultDatabase"`
+	// Project, if set, is the project that unqualified model and table names
+	// resolve against, instead of the default mindsdb project.
+	Project string `yaml:"project"`
+	// RestrictToProject, if set, rejects statements referencing projects
+	// other than Project.
+	RestrictToProject bool `yaml:"restrictToProject"`
+	// DatabaseScope, if set, lists the only databases statements may
+	// reference.
+	DatabaseScope []string `yaml:"databaseScope"`
+	// MaxResultBytes, if set, aborts queries once the rows they return are
+	// over this approximate size.
+	MaxResultBytes int64 `yaml:"maxResultBytes"`
 }
 
 // validate interface
@@ -79,6 +97,33 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
 	}
 
+	if cfg.MaxResultBytes < 0 {
+		return nil, fmt.Errorf("maxResultBytes must not be negative, got %d", cfg.MaxResultBytes)
+	}
+
+	if cfg.Project != "" {
+		if err := mindsdbcommon.ValidateProject(cfg.Project); err != nil {
+			return nil, err
+		}
+		if cfg.DefaultDatabase != "" {
+			return nil, fmt.Errorf("project and defaultDatabase must not both be set")
+		}
+	}
+	if cfg.RestrictToProject && cfg.Project == "" {
+		return nil, fmt.Errorf("restrictToProject requires a project")
+	}
+
+	databaseScope, err := tools.NewScope(tools.MySQLDialect, "database", cfg.DatabaseScope)
+	if err != nil {
+		return nil, err
+	}
+	if databaseScope != nil && cfg.DefaultDatabase != "" && !databaseScope.Contains(cfg.DefaultDatabase) {
+		return nil, fmt.Errorf("defaultDatabase %q is not in the databaseScope", cfg.DefaultDatabase)
+	}
+	if databaseScope != nil && cfg.Project != "" && !databaseScope.Contains(cfg.Project) {
+		return nil, fmt.Errorf("project %q is not in the databaseScope", cfg.Project)
+	}
+
 	sqlParameter := tools.NewStringParameter("sql", "The sql to execute.")
 	parameters := tools.Parameters{sqlParameter}
 
@@ -91,13 +136,19 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 	// finish tool setup
 	t := Tool{
-		Name:         cfg.Name,
-		Kind:         kind,
-		Parameters:   parameters,
-		AuthRequired: cfg.AuthRequired,
-		Pool:         s.MindsDBPool(),
-		manifest:     tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
-		mcpManifest:  mcpManifest,
+		Name:              cfg.Name,
+		Kind:              kind,
+		Parameters:        parameters,
+		AuthRequired:      cfg.AuthRequired,
+		Pool:              s.MindsDBPool(),
+		Deduplicate:       cfg.Deduplicate,
+		DefaultDatabase:   cfg.DefaultDatabase,
+		Project:           cfg.Project,
+		RestrictToProject: cfg.RestrictToProject,
+		MaxResultBytes:    cfg.MaxResultBytes,
+		DatabaseScope:     databaseScope,
+		manifest:          tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:       mcpManifest,
 	}
 	return t, nil
 }
@@ -106,26 +157,57 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 var _ tools.Tool = Tool{}
 
 type Tool struct {
-	Name         string           `yaml:"name"`
-	Kind         string           `yaml:"kind"`
-	AuthRequired []string         `yaml:"authRequired"`
-	Parameters   tools.Parameters `yaml:"parameters"`
-
-	Pool        *sql.DB
-	manifest    tools.Manifest
-	mcpManifest tools.McpManifest
+	Name              string                     `yaml:"name"`
+	Kind              string                     `yaml:"kind"`
+	AuthRequired      []string                   `yaml:"authRequired"`
+	Parameters        tools.Parameters           `yaml:"parameters"`
+	Deduplicate       *mindsdbcommon.Deduplicate `yaml:"deduplicate"`
+	DefaultDatabase   string                     `yaml:"defaultDatabase"`
+	Project           string                     `yaml:"project"`
+	RestrictToProject bool                       `yaml:"restrictToProject"`
+	MaxResultBytes    int64                      `yaml:"maxResultBytes"`
+
+	Pool          *sql.DB
+	DatabaseScope *tools.Scope
+	manifest      tools.Manifest
+	mcpManifest   tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	paramsMap := params.AsMap()
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
 	sql, ok := paramsMap["sql"].(string)
 	if !ok {
 		return nil, fmt.Errorf("unable to get cast %s", paramsMap["sql"])
 	}
+	if err := t.DatabaseScope.Check(sql); err != nil {
+		return nil, err
+	}
+
+	// a project is used like a default database, as MindsDB resolves
+	// unqualified model names against the database in use
+	database := t.DefaultDatabase
+	if t.Project != "" {
+		database = t.Project
+	}
+	conn, release, err := mindsdbcommon.Conn(ctx, t.Pool, database)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 
-	results, err := t.Pool.QueryContext(ctx, sql)
+	if t.RestrictToProject {
+		projects, err := mindsdbcommon.Projects(ctx, conn)
+		if err != nil {
+			return nil, err
+		}
+		if err := mindsdbcommon.CheckProject(sql, t.Project, projects); err != nil {
+			return nil, err
+		}
+	}
+
+	results, err := conn.QueryContext(ctx, sql)
 	if err != nil {
-		return nil, fmt.Errorf("unable to execute query: %w", err)
+		return nil, mindsdbcommon.EnrichError(fmt.Errorf("unable to execute query: %w", err))
 	}
 	defer results.Close()
 
@@ -146,12 +228,24 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		return nil, fmt.Errorf("unable to get column types: %w", err)
 	}
 
+	var dedupe *mindsdbcommon.Deduper
+	if t.Deduplicate != nil {
+		dedupe, err = t.Deduplicate.NewDeduper(cols)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	size := tools.NewResultSizeLimit(t.MaxResultBytes)
 	var out []any
 	for results.Next() {
 		err := results.Scan(values...)
 		if err != nil {
 			return nil, fmt.Errorf("unable to parse row: %w", err)
 		}
+		if err := size.Add(rawValues...); err != nil {
+			return nil, err
+		}
 		vMap := make(map[string]any)
 		for i, name := range cols {
 			val := rawValues[i]
@@ -161,18 +255,30 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 			}
 
 			// MindsDB uses mysql driver
-			vMap[name], err = mysqlcommon.ConvertToType(colTypes[i], val)
+			vMap[name], err = mysqlcommon.ConvertToType(ctx, colTypes[i], val)
 			if err != nil {
 				return nil, fmt.Errorf("errors encountered when converting values: %w", err)
 			}
 		}
+		if dedupe != nil {
+			dup, err := dedupe.Duplicate(vMap)
+			if err != nil {
+				return nil, err
+			}
+			if dup {
+				continue
+			}
+		}
 		out = append(out, vMap)
 	}
 
 	if err := results.Err(); err != nil {
-		return nil, fmt.Errorf("errors encountered during row iteration: %w", err)
+		return nil, mindsdbcommon.EnrichError(fmt.Errorf("errors encountered during row iteration: %w", err))
 	}
 
+	if dedupe != nil {
+		return dedupe.Result(out), nil
+	}
 	return out, nil
 }
 
@@ -15,12 +15,20 @@
 package mindsdbexecutesql_test
 
 import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
 	"testing"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/testutils/fakesql"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/mindsdb/mindsdbcommon"
 	"github.com/googleapis/genai-toolbox/internal/tools/mindsdb/mindsdbexecutesql"
 )
 
@@ -56,6 +64,115 @@ func TestParseFromYamlExecuteSql(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with deduplicate",
+			in: `
+			tools:
+				example_tool:
+					kind: mindsdb-execute-sql
+					source: my-instance
+					description: some description
+					deduplicate: all
+			`,
+			want: server.ToolConfigs{
+				"example_tool": mindsdbexecutesql.Config{
+					Name:         "example_tool",
+					Kind:         "mindsdb-execute-sql",
+					Source:       "my-instance",
+					Description:  "some description",
+					AuthRequired: []string{},
+					Deduplicate:  &mindsdbcommon.Deduplicate{All: true},
+				},
+			},
+		},
+		{
+			desc: "with default database",
+			in: `
+			tools:
+				example_tool:
+					kind: mindsdb-execute-sql
+					source: my-instance
+					description: some description
+					defaultDatabase: files
+			`,
+			want: server.ToolConfigs{
+				"example_tool": mindsdbexecutesql.Config{
+					Name:            "example_tool",
+					Kind:            "mindsdb-execute-sql",
+					Source:          "my-instance",
+					Description:     "some description",
+					AuthRequired:    []string{},
+					DefaultDatabase: "files",
+				},
+			},
+		},
+		{
+			desc: "with databaseScope",
+			in: `
+			tools:
+				example_tool:
+					kind: mindsdb-execute-sql
+					source: my-instance
+					description: some description
+					databaseScope:
+						- files
+						- shop
+			`,
+			want: server.ToolConfigs{
+				"example_tool": mindsdbexecutesql.Config{
+					Name:          "example_tool",
+					Kind:          "mindsdb-execute-sql",
+					Source:        "my-instance",
+					Description:   "some description",
+					AuthRequired:  []string{},
+					DatabaseScope: []string{"files", "shop"},
+				},
+			},
+		},
+		{
+			desc: "with project",
+			in: `
+			tools:
+				example_tool:
+					kind: mindsdb-execute-sql
+					source: my-instance
+					description: some description
+					project: sales
+					restrictToProject: true
+			`,
+			want: server.ToolConfigs{
+				"example_tool": mindsdbexecutesql.Config{
+					Name:              "example_tool",
+					Kind:              "mindsdb-execute-sql",
+					Source:            "my-instance",
+					Description:       "some description",
+					AuthRequired:      []string{},
+					Project:           "sales",
+					RestrictToProject: true,
+				},
+			},
+		},
+		{
+			desc: "with maxResultBytes",
+			in: `
+			tools:
+				example_tool:
+					kind: mindsdb-execute-sql
+					source: my-instance
+					description: some description
+					maxResultBytes: 1048576
+			`,
+			want: server.ToolConfigs{
+				"example_tool": mindsdbexecutesql.Config{
+					Name:           "example_tool",
+					Kind:           "mindsdb-execute-sql",
+					Source:         "my-instance",
+					Description:    "some description",
+					AuthRequired:   []string{},
+					MaxResultBytes: 1048576,
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -74,3 +191,229 @@ func TestParseFromYamlExecuteSql(t *testing.T) {
 	}
 
 }
+
+type fakeSource struct {
+	pool *sql.DB
+}
+
+func (s fakeSource) SourceKind() string {
+	return "mindsdb"
+}
+
+func (s fakeSource) MindsDBPool() *sql.DB {
+	return s.pool
+}
+
+func TestDatabaseScope(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	db := fakesql.Open(t, fakesql.Result{
+		Columns: []fakesql.Column{{Name: "id", Type: "INT"}},
+		Rows:    [][]driver.Value{{int64(1)}},
+	})
+	srcs := map[string]sources.Source{"my-instance": fakeSource{pool: db}}
+	cfg := mindsdbexecutesql.Config{
+		Name:          "example_tool",
+		Kind:          "mindsdb-execute-sql",
+		Source:        "my-instance",
+		Description:   "some description",
+		DatabaseScope: []string{"files", "shop"},
+	}
+	tool, err := cfg.Initialize(srcs)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	invoke := func(statement string) error {
+		params, err := tool.ParseParams(map[string]any{"sql": statement}, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		_, err = tool.Invoke(ctx, tools.Invocation{Params: params})
+		return err
+	}
+	if err := invoke("SELECT id FROM shop.orders JOIN `files`.uploads"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	err = invoke("SELECT id FROM `other`.orders")
+	var scopeErr *tools.ScopeError
+	if !errors.As(err, &scopeErr) || scopeErr.Reference != "other" {
+		t.Fatalf("got error %v, want a ScopeError for %q", err, "other")
+	}
+
+	cfg.DefaultDatabase = "other"
+	if _, err := cfg.Initialize(srcs); err == nil || !strings.Contains(err.Error(), `defaultDatabase "other" is not in the databaseScope`) {
+		t.Fatalf("got error %v for a defaultDatabase outside the scope", err)
+	}
+}
+
+func TestMaxResultBytes(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// each row holds a 1 KiB JSON document
+	wide := strings.Repeat("x", 1024)
+	rows := make([][]driver.Value, 100)
+	for i := range rows {
+		rows[i] = []driver.Value{int64(i), []byte(`{"doc": "` + wide + `"}`)}
+	}
+	tcs := []struct {
+		desc     string
+		limit    int64
+		wantRows int
+		// wantReads counts the reads of the rows, including the one finding
+		// that there are no more rows
+		wantReads int
+	}{
+		{desc: "unlimited", limit: 0, wantRows: 100, wantReads: 101},
+		{desc: "within the limit", limit: 1 << 20, wantRows: 100, wantReads: 101},
+		{desc: "over the limit", limit: 10 * 1024, wantReads: 10},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			reads := 0
+			db := fakesql.Open(t, fakesql.Result{
+				Columns:   []fakesql.Column{{Name: "id", Type: "INT"}, {Name: "doc", Type: "JSON"}},
+				Rows:      rows,
+				BeforeRow: func(int) { reads++ },
+			})
+			cfg := mindsdbexecutesql.Config{
+				Name:           "example_tool",
+				Kind:           "mindsdb-execute-sql",
+				Source:         "my-instance",
+				Description:    "some description",
+				MaxResultBytes: tc.limit,
+			}
+			tool, err := cfg.Initialize(map[string]sources.Source{"my-instance": fakeSource{pool: db}})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			params, err := tool.ParseParams(map[string]any{"sql": "SELECT * FROM wide"}, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			got, err := tool.Invoke(ctx, tools.Invocation{Params: params})
+
+			if tc.wantRows > 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				if n := len(got.([]any)); n != tc.wantRows {
+					t.Fatalf("got %d rows, want %d", n, tc.wantRows)
+				}
+			} else {
+				var tooLarge *tools.ResultTooLargeError
+				if !errors.As(err, &tooLarge) || tooLarge.Limit != tc.limit || tooLarge.Size <= tc.limit {
+					t.Fatalf("got error %v, want a ResultTooLargeError over %d", err, tc.limit)
+				}
+				if !strings.HasPrefix(err.Error(), "result too large, ") {
+					t.Fatalf("unexpected error message: %q", err)
+				}
+			}
+			// the rows stop being read once over the limit
+			if reads != tc.wantReads {
+				t.Fatalf("got %d reads, want %d", reads, tc.wantReads)
+			}
+			// and the connection returns to the pool
+			if inUse := db.Stats().InUse; inUse != 0 {
+				t.Fatalf("got %d connections in use, want 0", inUse)
+			}
+		})
+	}
+}
+
+func TestRestrictToProject(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// every query, including the one listing the projects, returns the names
+	// of the projects
+	db := fakesql.Open(t, fakesql.Result{
+		Columns: []fakesql.Column{{Name: "NAME", Type: "VARCHAR"}},
+		Rows:    [][]driver.Value{{"mindsdb"}, {"sales"}, {"marketing"}},
+	})
+	srcs := map[string]sources.Source{"my-instance": fakeSource{pool: db}}
+	cfg := mindsdbexecutesql.Config{
+		Name:              "example_tool",
+		Kind:              "mindsdb-execute-sql",
+		Source:            "my-instance",
+		Description:       "some description",
+		Project:           "sales",
+		RestrictToProject: true,
+	}
+	tool, err := cfg.Initialize(srcs)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	invoke := func(statement string) error {
+		params, err := tool.ParseParams(map[string]any{"sql": statement}, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		_, err = tool.Invoke(ctx, tools.Invocation{Params: params})
+		return err
+	}
+	for _, statement := range []string{
+		"SELECT * FROM churn",
+		"SELECT * FROM sales.churn AS m JOIN my_pg.customers AS c",
+	} {
+		if err := invoke(statement); err != nil {
+			t.Fatalf("unexpected error for %q: %s", statement, err)
+		}
+	}
+	for _, statement := range []string{
+		"SELECT * FROM mindsdb.churn",
+		"DROP MODEL marketing.leads",
+	} {
+		if err := invoke(statement); err == nil || !strings.Contains(err.Error(), "restricted to project \"sales\"") {
+			t.Fatalf("got error %v for %q, want it rejected", err, statement)
+		}
+	}
+	if inUse := db.Stats().InUse; inUse != 0 {
+		t.Fatalf("got %d connections in use, want 0", inUse)
+	}
+}
+
+func TestProjectConfig(t *testing.T) {
+	db := fakesql.Open(t, fakesql.Result{})
+	srcs := map[string]sources.Source{"my-instance": fakeSource{pool: db}}
+	tcs := []struct {
+		desc    string
+		cfg     mindsdbexecutesql.Config
+		wantErr string
+	}{
+		{
+			desc:    "invalid project",
+			cfg:     mindsdbexecutesql.Config{Project: "sales.models"},
+			wantErr: `invalid project name "sales.models"`,
+		},
+		{
+			desc:    "project and defaultDatabase",
+			cfg:     mindsdbexecutesql.Config{Project: "sales", DefaultDatabase: "files"},
+			wantErr: "project and defaultDatabase must not both be set",
+		},
+		{
+			desc:    "restrictToProject without project",
+			cfg:     mindsdbexecutesql.Config{RestrictToProject: true},
+			wantErr: "restrictToProject requires a project",
+		},
+		{
+			desc:    "project outside the databaseScope",
+			cfg:     mindsdbexecutesql.Config{Project: "sales", DatabaseScope: []string{"files"}},
+			wantErr: `project "sales" is not in the databaseScope`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			tc.cfg.Name, tc.cfg.Kind, tc.cfg.Source = "example_tool", "mindsdb-execute-sql", "my-instance"
+			if _, err := tc.cfg.Initialize(srcs); err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("got error %v, want one containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
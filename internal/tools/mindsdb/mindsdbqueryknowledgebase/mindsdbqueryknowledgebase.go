@@ -0,0 +1,441 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mindsdbqueryknowledgebase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/mindsdb"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/mindsdb/mindsdbcommon"
+	"github.com/googleapis/genai-toolbox/internal/tools/mysql/mysqlcommon"
+)
+
+const kind string = "mindsdb-query-knowledge-base"
+
+const (
+	// defaultTopK is the number of chunks returned when defaultTopK is not
+	// set.
+	defaultTopK = 10
+	// maxTopK is the largest number of chunks a query can return.
+	maxTopK = 100
+)
+
+// filterColumnRegex matches the metadata columns that results can be
+// filtered by.
+var filterColumnRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// reservedColumns are the columns of knowledge bases that are not metadata,
+// and so cannot be filtered by.
+var reservedColumns = []string{"content", "chunk_content", "relevance", "distance", "metadata"}
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+	tools.RegisterOutputSchema(kind, tools.RowsOutputSchema)
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	MindsDBPool() *sql.DB
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &mindsdb.Source{}
+
+var compatibleSources = [...]string{mindsdb.SourceKind}
+
+type Config struct {
+	Name         string   `yaml:"name" validate:"required"`
+	Kind         string   `yaml:"kind" validate:"required"`
+	Source       string   `yaml:"source" validate:"required"`
+	Description  string   `yaml:"description" validate:"required"`
+	AuthRequired []string `yaml:"authRequired"`
+	// DefaultTopK is the number of chunks returned when the invocation does
+	// not set top_k.
+	DefaultTopK int `yaml:"defaultTopK"`
+	// Project, if set, is the project that unqualified knowledge base names
+	// are qualified by, instead of the default mindsdb project.
+	Project string `yaml:"project"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	if cfg.Project != "" {
+		if err := mindsdbcommon.ValidateProject(cfg.Project); err != nil {
+			return nil, err
+		}
+	}
+
+	topK := cfg.DefaultTopK
+	if topK == 0 {
+		topK = defaultTopK
+	}
+	if topK < 1 || topK > maxTopK {
+		return nil, fmt.Errorf("defaultTopK must be between 1 and %d, got %d", maxTopK, cfg.DefaultTopK)
+	}
+
+	kbParameter := tools.NewStringParameter("knowledge_base", "The name of the knowledge base to query, optionally qualified by its project as project.name.")
+	queryParameter := tools.NewStringParameter("query", "The natural-language query to find relevant content for.")
+	topKParameter := tools.NewIntParameterWithDefault("top_k", topK, fmt.Sprintf("The number of most relevant chunks to return, at most %d.", maxTopK))
+	minTopK, maxTopKValue := 1, maxTopK
+	topKParameter.MinValue = &minTopK
+	topKParameter.MaxValue = &maxTopKValue
+	filtersParameter := tools.NewMapParameterWithRequired("filters", "Optional values of metadata columns that the chunks must have, by column name. A list of values matches any of them.", false, "")
+	parameters := tools.Parameters{kbParameter, queryParameter, topKParameter, filtersParameter}
+
+	inputSchema, _ := parameters.McpManifest()
+	mcpManifest := tools.McpManifest{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		InputSchema: inputSchema,
+	}
+
+	// finish tool setup
+	t := Tool{
+		Name:         cfg.Name,
+		Kind:         kind,
+		Parameters:   parameters,
+		AuthRequired: cfg.AuthRequired,
+		Project:      cfg.Project,
+		Pool:         s.MindsDBPool(),
+		manifest:     tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string           `yaml:"name"`
+	Kind         string           `yaml:"kind"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+	Project      string           `yaml:"project"`
+
+	Pool        *sql.DB
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
+	kb, ok := paramsMap["knowledge_base"].(string)
+	if !ok {
+		return nil, fmt.Errorf("unable to get cast %s", paramsMap["knowledge_base"])
+	}
+	kb = mindsdbcommon.Qualify(t.Project, kb)
+	query, ok := paramsMap["query"].(string)
+	if !ok {
+		return nil, fmt.Errorf("unable to get cast %s", paramsMap["query"])
+	}
+	topK, ok := paramsMap["top_k"].(int)
+	if !ok {
+		return nil, fmt.Errorf("unable to get cast %s", paramsMap["top_k"])
+	}
+	filters, _ := paramsMap["filters"].(map[string]any)
+
+	stmt, err := queryStatement(kb, query, topK, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	// MindsDB reports a missing knowledge base as a missing table, so it is
+	// looked up first to list the available ones instead
+	available, err := t.knowledgeBases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkExists(kb, available); err != nil {
+		return nil, err
+	}
+
+	results, err := t.Pool.QueryContext(ctx, stmt)
+	if err != nil {
+		return nil, mindsdbcommon.EnrichError(fmt.Errorf("unable to query knowledge base %q: %w", kb, err))
+	}
+	defer results.Close()
+
+	cols, err := results.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve rows column name: %w", err)
+	}
+	colTypes, err := results.ColumnTypes()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get column types: %w", err)
+	}
+	rawValues := make([]any, len(cols))
+	values := make([]any, len(cols))
+	for i := range rawValues {
+		values[i] = &rawValues[i]
+	}
+
+	out := []any{}
+	for results.Next() {
+		if err := results.Scan(values...); err != nil {
+			return nil, fmt.Errorf("unable to parse row: %w", err)
+		}
+		vMap := make(map[string]any, len(cols))
+		for i, name := range cols {
+			if rawValues[i] == nil {
+				vMap[name] = nil
+				continue
+			}
+			// MindsDB uses mysql driver
+			vMap[name], err = mysqlcommon.ConvertToType(ctx, colTypes[i], rawValues[i])
+			if err != nil {
+				return nil, fmt.Errorf("errors encountered when converting values: %w", err)
+			}
+		}
+		out = append(out, chunkRow(vMap))
+	}
+	if err := results.Err(); err != nil {
+		return nil, mindsdbcommon.EnrichError(fmt.Errorf("errors encountered during row iteration: %w", err))
+	}
+	return out, nil
+}
+
+// queryStatement returns the statement selecting the topK chunks of the
+// knowledge base kb that are the most relevant to query, among those whose
+// metadata match filters.
+func queryStatement(kb, query string, topK int, filters map[string]any) (string, error) {
+	quoted, err := mindsdbcommon.QuoteName(kb)
+	if err != nil {
+		return "", fmt.Errorf("invalid knowledge base name: %w", err)
+	}
+	conditions := []string{"content = " + mindsdbcommon.QuoteString(query)}
+
+	columns := make([]string, 0, len(filters))
+	for c := range filters {
+		columns = append(columns, c)
+	}
+	slices.Sort(columns)
+	for _, c := range columns {
+		if !filterColumnRegex.MatchString(c) {
+			return "", fmt.Errorf("invalid filter column %q: it must start with a letter or an underscore, followed by letters, digits or underscores", c)
+		}
+		if slices.Contains(reservedColumns, strings.ToLower(c)) {
+			return "", fmt.Errorf("invalid filter column %q: only metadata columns can be filtered by", c)
+		}
+		condition, err := filterCondition(mindsdbcommon.QuoteIdentifier(c), filters[c])
+		if err != nil {
+			return "", fmt.Errorf("invalid filter on column %q: %w", c, err)
+		}
+		conditions = append(conditions, condition)
+	}
+	return fmt.Sprintf("SELECT * FROM %s WHERE %s LIMIT %d", quoted, strings.Join(conditions, " AND "), topK), nil
+}
+
+// filterCondition returns the condition matching column against v, or any of
+// the elements of v if it is a list.
+func filterCondition(column string, v any) (string, error) {
+	list, ok := v.([]any)
+	if !ok {
+		literal, err := filterLiteral(v)
+		if err != nil {
+			return "", err
+		}
+		return column + " = " + literal, nil
+	}
+	if len(list) == 0 {
+		return "", fmt.Errorf("list of values must not be empty")
+	}
+	literals := make([]string, len(list))
+	for i, e := range list {
+		literal, err := filterLiteral(e)
+		if err != nil {
+			return "", err
+		}
+		literals[i] = literal
+	}
+	return column + " IN (" + strings.Join(literals, ", ") + ")", nil
+}
+
+// filterLiteral returns v as a SQL literal.
+func filterLiteral(v any) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return mindsdbcommon.QuoteString(v), nil
+	case bool:
+		return strings.ToUpper(strconv.FormatBool(v)), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	}
+	return "", fmt.Errorf("unsupported value %v: values must be strings, numbers or booleans", v)
+}
+
+// chunkRow returns a row of a knowledge base with its metadata decoded, and
+// its scores as numbers, as MindsDB often reports every column as TEXT.
+func chunkRow(row map[string]any) map[string]any {
+	if s, ok := row["metadata"].(string); ok {
+		var metadata any
+		if err := json.Unmarshal([]byte(s), &metadata); err == nil {
+			row["metadata"] = metadata
+		}
+	}
+	for _, c := range []string{"relevance", "distance"} {
+		if s, ok := row[c].(string); ok {
+			if f, err := strconv.ParseFloat(s, 64); err == nil {
+				row[c] = f
+			}
+		}
+	}
+	return row
+}
+
+// knowledgeBase is a knowledge base listed by SHOW KNOWLEDGE_BASES.
+type knowledgeBase struct {
+	project string
+	name    string
+}
+
+func (kb knowledgeBase) String() string {
+	if kb.project == "" {
+		return kb.name
+	}
+	return kb.project + "." + kb.name
+}
+
+// knowledgeBases lists the knowledge bases of every project.
+func (t Tool) knowledgeBases(ctx context.Context) ([]knowledgeBase, error) {
+	results, err := t.Pool.QueryContext(ctx, "SHOW KNOWLEDGE_BASES")
+	if err != nil {
+		return nil, mindsdbcommon.EnrichError(fmt.Errorf("unable to list knowledge bases: %w", err))
+	}
+	defer results.Close()
+
+	cols, err := results.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve rows column name: %w", err)
+	}
+	nameIdx, projectIdx := -1, -1
+	for i, c := range cols {
+		switch strings.ToLower(c) {
+		case "name":
+			nameIdx = i
+		case "project":
+			projectIdx = i
+		}
+	}
+	if nameIdx < 0 {
+		return nil, fmt.Errorf("unable to list knowledge bases: SHOW KNOWLEDGE_BASES returned no name column")
+	}
+
+	rawValues := make([]sql.NullString, len(cols))
+	values := make([]any, len(cols))
+	for i := range rawValues {
+		values[i] = &rawValues[i]
+	}
+	var kbs []knowledgeBase
+	for results.Next() {
+		if err := results.Scan(values...); err != nil {
+			return nil, fmt.Errorf("unable to parse row: %w", err)
+		}
+		kb := knowledgeBase{name: rawValues[nameIdx].String}
+		if projectIdx >= 0 {
+			kb.project = rawValues[projectIdx].String
+		}
+		kbs = append(kbs, kb)
+	}
+	if err := results.Err(); err != nil {
+		return nil, mindsdbcommon.EnrichError(fmt.Errorf("unable to list knowledge bases: %w", err))
+	}
+	return kbs, nil
+}
+
+// checkExists returns an error listing the available knowledge bases unless
+// one of them is name. An unqualified name matches a knowledge base of any
+// project.
+func checkExists(name string, available []knowledgeBase) error {
+	project, kbName, qualified := strings.Cut(name, ".")
+	if !qualified {
+		project, kbName = "", name
+	}
+	for _, kb := range available {
+		if strings.EqualFold(kb.name, kbName) && (!qualified || strings.EqualFold(kb.project, project)) {
+			return nil
+		}
+	}
+	err := fmt.Errorf("knowledge base %q does not exist", name)
+	if len(available) == 0 {
+		return &tools.HintError{Err: err, Hint: "no knowledge bases exist yet; create one with CREATE KNOWLEDGE_BASE"}
+	}
+	names := make([]string, len(available))
+	for i, kb := range available {
+		names[i] = kb.String()
+	}
+	return &tools.HintError{Err: err, Hint: "available knowledge bases are " + strings.Join(names, ", ")}
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return false
+}
@@ -0,0 +1,231 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mindsdbqueryknowledgebase
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestParseFromYamlMindsDBQueryKnowledgeBase(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		search_docs:
+			kind: mindsdb-query-knowledge-base
+			source: my-mindsdb-instance
+			description: Search the documentation.
+			defaultTopK: 5
+			project: support
+	`
+	want := server.ToolConfigs{
+		"search_docs": Config{
+			Name:         "search_docs",
+			Kind:         "mindsdb-query-knowledge-base",
+			Source:       "my-mindsdb-instance",
+			Description:  "Search the documentation.",
+			AuthRequired: []string{},
+			DefaultTopK:  5,
+			Project:      "support",
+		},
+	}
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	if diff := cmp.Diff(want, got.Tools); diff != "" {
+		t.Fatalf("incorrect parse: diff %v", diff)
+	}
+}
+
+func TestQueryStatement(t *testing.T) {
+	tcs := []struct {
+		desc    string
+		kb      string
+		query   string
+		topK    int
+		filters map[string]any
+		want    string
+	}{
+		{
+			desc:  "query only",
+			kb:    "docs_kb",
+			query: "how do I reset my password?",
+			topK:  5,
+			want:  "SELECT * FROM `docs_kb` WHERE content = 'how do I reset my password?' LIMIT 5",
+		},
+		{
+			desc:  "escaped query",
+			kb:    "mindsdb.docs_kb",
+			query: `it's a \ test'; DROP DATABASE files; --`,
+			topK:  1,
+			want:  "SELECT * FROM `mindsdb`.`docs_kb` WHERE content = 'it''s a \\\\ test''; DROP DATABASE files; --' LIMIT 1",
+		},
+		{
+			desc:  "metadata filters",
+			kb:    "docs_kb",
+			query: "refunds",
+			topK:  10,
+			filters: map[string]any{
+				"product":  "o'reilly",
+				"version":  int64(3),
+				"score":    0.5,
+				"archived": false,
+				"region":   []any{"emea", "apac"},
+			},
+			want: "SELECT * FROM `docs_kb` WHERE content = 'refunds' AND `archived` = FALSE AND `product` = 'o''reilly' " +
+				"AND `region` IN ('emea', 'apac') AND `score` = 0.5 AND `version` = 3 LIMIT 10",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := queryStatement(tc.kb, tc.query, tc.topK, tc.filters)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected statement (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestQueryStatementInvalid(t *testing.T) {
+	tcs := []struct {
+		desc    string
+		kb      string
+		filters map[string]any
+		wantErr string
+	}{
+		{
+			desc:    "knowledge base name injection",
+			kb:      "docs_kb WHERE 1=1 --",
+			wantErr: "invalid knowledge base name",
+		},
+		{
+			desc:    "filter column injection",
+			kb:      "docs_kb",
+			filters: map[string]any{"product` = 'x' OR `1": "y"},
+			wantErr: "invalid filter column",
+		},
+		{
+			desc:    "reserved column",
+			kb:      "docs_kb",
+			filters: map[string]any{"Relevance": 0.9},
+			wantErr: `invalid filter column "Relevance": only metadata columns can be filtered by`,
+		},
+		{
+			desc:    "unsupported value",
+			kb:      "docs_kb",
+			filters: map[string]any{"product": map[string]any{"a": "b"}},
+			wantErr: `invalid filter on column "product": unsupported value`,
+		},
+		{
+			desc:    "empty list",
+			kb:      "docs_kb",
+			filters: map[string]any{"product": []any{}},
+			wantErr: `invalid filter on column "product": list of values must not be empty`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := queryStatement(tc.kb, "query", 10, tc.filters)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckExists(t *testing.T) {
+	available := []knowledgeBase{{project: "mindsdb", name: "docs_kb"}, {project: "sales", name: "orders_kb"}}
+	for _, name := range []string{"docs_kb", "mindsdb.docs_kb", "SALES.Orders_KB"} {
+		if err := checkExists(name, available); err != nil {
+			t.Fatalf("unexpected error for %q: %s", name, err)
+		}
+	}
+
+	tcs := []struct {
+		desc      string
+		name      string
+		available []knowledgeBase
+		wantHint  string
+	}{
+		{
+			desc:      "missing",
+			name:      "faq_kb",
+			available: available,
+			wantHint:  "available knowledge bases are mindsdb.docs_kb, sales.orders_kb",
+		},
+		{
+			desc:      "wrong project",
+			name:      "sales.docs_kb",
+			available: available,
+			wantHint:  "available knowledge bases are mindsdb.docs_kb, sales.orders_kb",
+		},
+		{
+			desc:     "no knowledge bases",
+			name:     "docs_kb",
+			wantHint: "no knowledge bases exist yet; create one with CREATE KNOWLEDGE_BASE",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := checkExists(tc.name, tc.available)
+			var hintErr *tools.HintError
+			if !errors.As(err, &hintErr) {
+				t.Fatalf("got error %v, want a HintError", err)
+			}
+			if want := `knowledge base "` + tc.name + `" does not exist`; err.Error() != want {
+				t.Fatalf("unexpected error: got %q, want %q", err, want)
+			}
+			if hintErr.Hint != tc.wantHint {
+				t.Fatalf("unexpected hint: got %q, want %q", hintErr.Hint, tc.wantHint)
+			}
+		})
+	}
+}
+
+func TestChunkRow(t *testing.T) {
+	got := chunkRow(map[string]any{
+		"chunk_id":      "1:notes:1of1:0to42",
+		"chunk_content": "Refunds are processed within 5 days.",
+		"metadata":      `{"product": "shop", "version": 3}`,
+		"relevance":     "0.8731",
+		"distance":      0.1269,
+	})
+	want := map[string]any{
+		"chunk_id":      "1:notes:1of1:0to42",
+		"chunk_content": "Refunds are processed within 5 days.",
+		"metadata":      map[string]any{"product": "shop", "version": float64(3)},
+		"relevance":     0.8731,
+		"distance":      0.1269,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected row (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,250 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mindsdbretrainmodel
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/mindsdb"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/mindsdb/mindsdbcommon"
+)
+
+const kind string = "mindsdb-retrain-model"
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	MindsDBPool() *sql.DB
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &mindsdb.Source{}
+
+var compatibleSources = [...]string{mindsdb.SourceKind}
+
+type Config struct {
+	Name         string   `yaml:"name" validate:"required"`
+	Kind         string   `yaml:"kind" validate:"required"`
+	Source       string   `yaml:"source" validate:"required"`
+	Description  string   `yaml:"description" validate:"required"`
+	AuthRequired []string `yaml:"authRequired"`
+	// Project, if set, is the only project whose models can be retrained.
+	// Otherwise the project is a parameter, defaulting to mindsdb.
+	Project string `yaml:"project"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	if cfg.Project != "" {
+		if err := mindsdbcommon.ValidateProject(cfg.Project); err != nil {
+			return nil, err
+		}
+	}
+
+	usingParameter := tools.NewMapParameterWithRequired("using", "Optional training options overriding those of the model, such as {\"engine\": \"lightwood\"}, by option name.", false, "")
+	parameters := append(mindsdbcommon.ModelParameters(cfg.Project), usingParameter)
+	inputSchema, _ := parameters.McpManifest()
+	mcpManifest := tools.McpManifest{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		InputSchema: inputSchema,
+	}
+
+	// finish tool setup
+	t := Tool{
+		Name:         cfg.Name,
+		Kind:         kind,
+		Parameters:   parameters,
+		AuthRequired: cfg.AuthRequired,
+		Project:      cfg.Project,
+		Pool:         s.MindsDBPool(),
+		manifest:     tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string           `yaml:"name"`
+	Kind         string           `yaml:"kind"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+	Project      string           `yaml:"project"`
+
+	Pool        *sql.DB
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
+	project, name, err := mindsdbcommon.ModelTarget(t.Project, paramsMap)
+	if err != nil {
+		return nil, err
+	}
+	using, _ := paramsMap["using"].(map[string]any)
+	stmt, err := retrainStatement(project, name, using)
+	if err != nil {
+		return nil, err
+	}
+	if err := mindsdbcommon.RequireModel(ctx, t.Pool, project, name); err != nil {
+		return nil, err
+	}
+
+	results, err := t.Pool.QueryContext(ctx, stmt)
+	if err != nil {
+		return nil, mindsdbcommon.EnrichError(fmt.Errorf("unable to retrain model %q: %w", name, err))
+	}
+	defer results.Close()
+	rows, err := mindsdbcommon.StringRows(results)
+	if err != nil {
+		return nil, err
+	}
+
+	out := map[string]any{"name": name, "project": project}
+	// RETRAIN returns the row of the new version of the model
+	if len(rows) > 0 {
+		row := rows[0]
+		if n, err := strconv.Atoi(row["version"]); err == nil {
+			out["version"] = n
+		}
+		if v := row["status"]; v != "" {
+			out["status"] = v
+		}
+	}
+	return out, nil
+}
+
+// optionRegex matches the names of training options, which may be nested as
+// in timeseries_settings.window.
+var optionRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// retrainStatement returns the RETRAIN statement of the model name of
+// project, with the training options of using.
+func retrainStatement(project, name string, using map[string]any) (string, error) {
+	quoted, err := mindsdbcommon.QuoteModel(project, name)
+	if err != nil {
+		return "", err
+	}
+	stmt := "RETRAIN " + quoted
+	if len(using) == 0 {
+		return stmt, nil
+	}
+
+	options := make([]string, 0, len(using))
+	for o := range using {
+		options = append(options, o)
+	}
+	slices.Sort(options)
+	assignments := make([]string, len(options))
+	for i, o := range options {
+		if !optionRegex.MatchString(o) {
+			return "", fmt.Errorf("invalid training option %q: it must start with a letter or an underscore, followed by letters, digits or underscores, and may be nested with dots", o)
+		}
+		literal, err := optionLiteral(using[o])
+		if err != nil {
+			return "", fmt.Errorf("invalid value of training option %q: %w", o, err)
+		}
+		assignments[i] = o + " = " + literal
+	}
+	return stmt + " USING " + strings.Join(assignments, ", "), nil
+}
+
+// optionLiteral returns v as the literal of a training option. Objects and
+// lists are written as JSON, which MindsDB parses as such.
+func optionLiteral(v any) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return mindsdbcommon.QuoteString(v), nil
+	case bool:
+		return strings.ToUpper(strconv.FormatBool(v)), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case map[string]any, []any:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+	return "", fmt.Errorf("unsupported value %v: values must be strings, numbers, booleans, objects or lists", v)
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return false
+}
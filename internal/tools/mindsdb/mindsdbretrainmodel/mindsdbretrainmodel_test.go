@@ -0,0 +1,110 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mindsdbretrainmodel
+
+import (
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+func TestParseFromYamlMindsDBRetrainModel(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		model_tool:
+			kind: mindsdb-retrain-model
+			source: my-mindsdb-instance
+			description: Manage models.
+			project: sales
+	`
+	want := server.ToolConfigs{
+		"model_tool": Config{
+			Name:         "model_tool",
+			Kind:         "mindsdb-retrain-model",
+			Source:       "my-mindsdb-instance",
+			Description:  "Manage models.",
+			AuthRequired: []string{},
+			Project:      "sales",
+		},
+	}
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	if diff := cmp.Diff(want, got.Tools); diff != "" {
+		t.Fatalf("incorrect parse: diff %v", diff)
+	}
+}
+
+func TestRetrainStatement(t *testing.T) {
+	tcs := []struct {
+		desc  string
+		using map[string]any
+		want  string
+	}{
+		{desc: "no options", want: "RETRAIN `sales`.`churn`"},
+		{
+			desc: "options",
+			using: map[string]any{
+				"engine":                     "lightwood",
+				"tag":                        "it's new",
+				"timeseries_settings.window": 12,
+				"encoders":                   map[string]any{"notes": map[string]any{"module": "TextRnnEncoder"}},
+				"use_gpu":                    false,
+				"sample_rate":                0.5,
+			},
+			want: "RETRAIN `sales`.`churn` USING encoders = {\"notes\":{\"module\":\"TextRnnEncoder\"}}, engine = 'lightwood', sample_rate = 0.5, tag = 'it''s new', timeseries_settings.window = 12, use_gpu = FALSE",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := retrainStatement("sales", "churn", tc.using)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != tc.want {
+				t.Fatalf("got statement %q, want %q", got, tc.want)
+			}
+		})
+	}
+
+	errTcs := []struct {
+		desc    string
+		name    string
+		using   map[string]any
+		wantErr string
+	}{
+		{desc: "invalid name", name: "churn`; DROP MODEL x", wantErr: "invalid model name"},
+		{desc: "invalid option", name: "churn", using: map[string]any{"engine = 'x'; --": "y"}, wantErr: "invalid training option"},
+		{desc: "invalid value", name: "churn", using: map[string]any{"engine": nil}, wantErr: "invalid value of training option"},
+	}
+	for _, tc := range errTcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			if _, err := retrainStatement("sales", tc.name, tc.using); err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("got error %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/sources/mindsdb"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/mindsdb/mindsdbcommon"
 	"github.com/googleapis/genai-toolbox/internal/tools/mysql/mysqlcommon"
 )
 
@@ -32,6 +33,7 @@ func init() {
 	if !tools.Register(kind, newConfig) {
 		panic(fmt.Sprintf("tool kind %q already registered", kind))
 	}
+	tools.RegisterOutputSchema(kind, tools.RowsOutputSchema)
 }
 
 func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
@@ -52,14 +54,23 @@ var _ compatibleSource = &mindsdb.Source{}
 var compatibleSources = [...]string{mindsdb.SourceKind}
 
 type Config struct {
-	Name               string           `yaml:"name" validate:"required"`
-	Kind               string           `yaml:"kind" validate:"required"`
-	Source             string           `yaml:"source" validate:"required"`
-	Description        string           `yaml:"description" validate:"required"`
-	Statement          string           `yaml:"statement" validate:"required"`
-	AuthRequired       []string         `yaml:"authRequired"`
-	Parameters         tools.Parameters `yaml:"parameters"`
-	TemplateParameters tools.Parameters `yaml:"templateParameters"`
+	Name               string                     `yaml:"name" validate:"required"`
+	Kind               string                     `yaml:"kind" validate:"required"`
+	Source             string                     `yaml:"source" validate:"required"`
+	Description        string                     `yaml:"description" validate:"required"`
+	Statement          string                     `yaml:"statement" validate:"required"`
+	AuthRequired       []string                   `yaml:"authRequired"`
+	Parameters         tools.Parameters           `yaml:"parameters"`
+	TemplateParameters tools.Parameters           `yaml:"templateParameters"`
+	Deduplicate        *mindsdbcommon.Deduplicate `yaml:"deduplicate"`
+	ColumnTypes        tools.ColumnTypes          `yaml:"columnTypes"`
+	LenientCoercion    bool                       `yaml:"lenientCoercion"`
+	// DefaultDatabase, if set, is the database that unqualified table names
+	// resolve against, overriding the database of the source.
+	DefaultDatabase string `yaml:"defaultDatabase"`
+	// Project, if set, is the project that unqualified model and table names
+	// resolve against, instead of the default mindsdb project.
+	Project string `yaml:"project"`
 }
 
 // validate interface
@@ -82,6 +93,20 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
 	}
 
+	if cfg.Project != "" {
+		if err := mindsdbcommon.ValidateProject(cfg.Project); err != nil {
+			return nil, err
+		}
+		if cfg.DefaultDatabase != "" {
+			return nil, fmt.Errorf("project and defaultDatabase must not both be set")
+		}
+	}
+
+	// MindsDB only reports a mismatch with a confusing error once invoked
+	if err := tools.QuestionPlaceholders(cfg.Statement).CheckCount(len(cfg.Parameters)); err != nil {
+		return nil, fmt.Errorf("invalid statement of tool %q: %w", cfg.Name, err)
+	}
+
 	allParameters, paramManifest, err := tools.ProcessParameters(cfg.TemplateParameters, cfg.Parameters)
 	if err != nil {
 		return nil, err
@@ -105,6 +130,11 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		Statement:          cfg.Statement,
 		AuthRequired:       cfg.AuthRequired,
 		Pool:               s.MindsDBPool(),
+		Deduplicate:        cfg.Deduplicate,
+		ColumnTypes:        cfg.ColumnTypes,
+		LenientCoercion:    cfg.LenientCoercion,
+		DefaultDatabase:    cfg.DefaultDatabase,
+		Project:            cfg.Project,
 		manifest:           tools.Manifest{Description: cfg.Description, Parameters: paramManifest, AuthRequired: cfg.AuthRequired},
 		mcpManifest:        mcpManifest,
 	}
@@ -113,14 +143,21 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 // validate interface
 var _ tools.Tool = Tool{}
+var _ tools.Renderer = Tool{}
+var _ tools.ArrayParamsExpander = Tool{}
 
 type Tool struct {
-	Name               string           `yaml:"name"`
-	Kind               string           `yaml:"kind"`
-	AuthRequired       []string         `yaml:"authRequired"`
-	Parameters         tools.Parameters `yaml:"parameters"`
-	TemplateParameters tools.Parameters `yaml:"templateParameters"`
-	AllParams          tools.Parameters `yaml:"allParams"`
+	Name               string                     `yaml:"name"`
+	Kind               string                     `yaml:"kind"`
+	AuthRequired       []string                   `yaml:"authRequired"`
+	Parameters         tools.Parameters           `yaml:"parameters"`
+	TemplateParameters tools.Parameters           `yaml:"templateParameters"`
+	AllParams          tools.Parameters           `yaml:"allParams"`
+	Deduplicate        *mindsdbcommon.Deduplicate `yaml:"deduplicate"`
+	ColumnTypes        tools.ColumnTypes          `yaml:"columnTypes"`
+	LenientCoercion    bool                       `yaml:"lenientCoercion"`
+	DefaultDatabase    string                     `yaml:"defaultDatabase"`
+	Project            string                     `yaml:"project"`
 
 	Pool        *sql.DB
 	Statement   string
@@ -128,9 +165,10 @@ type Tool struct {
 	mcpManifest tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	paramsMap := params.AsMap()
-	newStatement, err := tools.ResolveTemplateParams(t.TemplateParameters, t.Statement, paramsMap)
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
+	// json template parameters are interpolated as quoted string literals
+	newStatement, err := tools.ResolveTemplateParams(t.TemplateParameters, t.Statement, mindsdbcommon.QuoteJSONParams(paramsMap))
 	if err != nil {
 		return nil, fmt.Errorf("unable to extract template params %w", err)
 	}
@@ -140,13 +178,27 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		return nil, fmt.Errorf("unable to extract standard params %w", err)
 	}
 
-	sliceParams := newParams.AsSlice()
+	// expand array parameters into one placeholder per element for `IN (?)`
+	newStatement, sliceParams := mysqlcommon.ExpandArrayParams(newStatement, newParams.AsSlice())
+
+	// a project is used like a default database, as MindsDB resolves
+	// unqualified model names against the database in use
+	database := t.DefaultDatabase
+	if t.Project != "" {
+		database = t.Project
+	}
+	conn, release, err := mindsdbcommon.Conn(ctx, t.Pool, database)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 
 	// MindsDB now supports MySQL prepared statements natively
-	results, err := t.Pool.QueryContext(ctx, newStatement, sliceParams...)
+	results, err := conn.QueryContext(ctx, newStatement, sliceParams...)
 	if err != nil {
-		return nil, fmt.Errorf("unable to execute query: %w", err)
+		return nil, mindsdbcommon.EnrichError(fmt.Errorf("unable to execute query: %w", err))
 	}
+	defer results.Close()
 
 	cols, err := results.Columns()
 	if err != nil {
@@ -159,13 +211,20 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	for i := range rawValues {
 		values[i] = &rawValues[i]
 	}
-	defer results.Close()
 
 	colTypes, err := results.ColumnTypes()
 	if err != nil {
 		return nil, fmt.Errorf("unable to get column types: %w", err)
 	}
 
+	var dedupe *mindsdbcommon.Deduper
+	if t.Deduplicate != nil {
+		dedupe, err = t.Deduplicate.NewDeduper(cols)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var out []any
 	for results.Next() {
 		err := results.Scan(values...)
@@ -181,21 +240,52 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 			}
 
 			// MindsDB uses mysql driver
-			vMap[name], err = mysqlcommon.ConvertToType(colTypes[i], val)
+			vMap[name], err = mysqlcommon.ConvertToType(ctx, colTypes[i], val)
 			if err != nil {
 				return nil, fmt.Errorf("errors encountered when converting values: %w", err)
 			}
 		}
+		// MindsDB often reports every column as TEXT
+		if err := t.ColumnTypes.CoerceRow(vMap, t.LenientCoercion); err != nil {
+			return nil, err
+		}
+		if dedupe != nil {
+			dup, err := dedupe.Duplicate(vMap)
+			if err != nil {
+				return nil, err
+			}
+			if dup {
+				continue
+			}
+		}
 		out = append(out, vMap)
 	}
 
 	if err := results.Err(); err != nil {
-		return nil, fmt.Errorf("errors encountered during row iteration: %w", err)
+		return nil, mindsdbcommon.EnrichError(fmt.Errorf("errors encountered during row iteration: %w", err))
 	}
 
+	if dedupe != nil {
+		return dedupe.Result(out), nil
+	}
 	return out, nil
 }
 
+// Render resolves the statement of an invocation without running it.
+func (t Tool) Render(params tools.ParamValues) (tools.RenderedStatement, error) {
+	paramsMap := params.AsMap()
+	newStatement, err := tools.ResolveTemplateParams(t.TemplateParameters, t.Statement, mindsdbcommon.QuoteJSONParams(paramsMap))
+	if err != nil {
+		return tools.RenderedStatement{}, params.RedactError(tools.NewTemplateError(t.Statement, err))
+	}
+	newParams, err := tools.GetParams(t.Parameters, paramsMap)
+	if err != nil {
+		return tools.RenderedStatement{}, err
+	}
+	newStatement, _ = mysqlcommon.ExpandArrayParams(newStatement, newParams.AsSlice())
+	return tools.NewRenderedStatement(newStatement, newParams, params), nil
+}
+
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
 	return tools.ParseParams(t.AllParams, data, claims)
 }
@@ -215,3 +305,9 @@ func (t Tool) Authorized(verifiedAuthServices []string) bool {
 func (t Tool) RequiresClientAuthorization() bool {
 	return false
 }
+
+// ExpandsArrayParams reports that array parameters are expanded into one
+// placeholder per element, which MindsDB binds one by one.
+func (t Tool) ExpandsArrayParams() bool {
+	return true
+}
@@ -15,13 +15,21 @@
 package mindsdbsql_test
 
 import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
 	"testing"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/mindsdb"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/testutils/fakesql"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/mindsdb/mindsdbcommon"
 	"github.com/googleapis/genai-toolbox/internal/tools/mindsdb/mindsdbsql"
 )
 
@@ -74,6 +82,108 @@ func TestParseFromYamlmindsdbsql(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with deduplicate",
+			in: `
+			tools:
+				example_tool:
+					kind: mindsdb-sql
+					source: my-mindsdbsql-instance
+					description: some description
+					statement: |
+						SELECT * FROM SQL_STATEMENT;
+					deduplicate:
+						- customer_id
+						- region
+			`,
+			want: server.ToolConfigs{
+				"example_tool": mindsdbsql.Config{
+					Name:         "example_tool",
+					Kind:         "mindsdb-sql",
+					Source:       "my-mindsdbsql-instance",
+					Description:  "some description",
+					Statement:    "SELECT * FROM SQL_STATEMENT;\n",
+					AuthRequired: []string{},
+					Deduplicate:  &mindsdbcommon.Deduplicate{Columns: []string{"customer_id", "region"}},
+				},
+			},
+		},
+		{
+			desc: "with column types",
+			in: `
+			tools:
+				example_tool:
+					kind: mindsdb-sql
+					source: my-mindsdbsql-instance
+					description: some description
+					statement: |
+						SELECT * FROM SQL_STATEMENT;
+					columnTypes:
+						id: integer
+						price: float
+					lenientCoercion: true
+			`,
+			want: server.ToolConfigs{
+				"example_tool": mindsdbsql.Config{
+					Name:            "example_tool",
+					Kind:            "mindsdb-sql",
+					Source:          "my-mindsdbsql-instance",
+					Description:     "some description",
+					Statement:       "SELECT * FROM SQL_STATEMENT;\n",
+					AuthRequired:    []string{},
+					ColumnTypes:     tools.ColumnTypes{"id": "integer", "price": "float"},
+					LenientCoercion: true,
+				},
+			},
+		},
+		{
+			desc: "with default database",
+			in: `
+			tools:
+				example_tool:
+					kind: mindsdb-sql
+					source: my-mindsdbsql-instance
+					description: some description
+					statement: |
+						SELECT * FROM sales;
+					defaultDatabase: files
+			`,
+			want: server.ToolConfigs{
+				"example_tool": mindsdbsql.Config{
+					Name:            "example_tool",
+					Kind:            "mindsdb-sql",
+					Source:          "my-mindsdbsql-instance",
+					Description:     "some description",
+					Statement:       "SELECT * FROM sales;\n",
+					AuthRequired:    []string{},
+					DefaultDatabase: "files",
+				},
+			},
+		},
+		{
+			desc: "with project",
+			in: `
+			tools:
+				example_tool:
+					kind: mindsdb-sql
+					source: my-mindsdbsql-instance
+					description: some description
+					statement: |
+						SELECT * FROM churn;
+					project: sales
+			`,
+			want: server.ToolConfigs{
+				"example_tool": mindsdbsql.Config{
+					Name:         "example_tool",
+					Kind:         "mindsdb-sql",
+					Source:       "my-mindsdbsql-instance",
+					Description:  "some description",
+					Statement:    "SELECT * FROM churn;\n",
+					AuthRequired: []string{},
+					Project:      "sales",
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -173,3 +283,164 @@ func TestParseFromYamlWithTemplateParamsmindsdbsql(t *testing.T) {
 		})
 	}
 }
+
+func TestInitializeStatementPlaceholders(t *testing.T) {
+	srcs := map[string]sources.Source{"my-instance": &mindsdb.Source{}}
+	params := tools.Parameters{
+		tools.NewStringParameter("country", "some description"),
+		tools.NewIntParameter("limit", "some description"),
+	}
+	tcs := []struct {
+		desc      string
+		statement string
+		wantErr   string
+	}{
+		{
+			desc:      "matching",
+			statement: "SELECT * FROM t WHERE country = ? LIMIT ?",
+		},
+		{
+			desc:      "placeholder in a string literal",
+			statement: "SELECT * FROM t WHERE country = ? AND note != 'why?' LIMIT ?",
+		},
+		{
+			desc:      "too few placeholders",
+			statement: "SELECT * FROM t WHERE country = ?",
+			wantErr:   `invalid statement of tool "example_tool": statement binds 1 parameters but 2 are declared (placeholders at 1:33)`,
+		},
+		{
+			desc:      "too many placeholders",
+			statement: "SELECT * FROM t WHERE country = ? AND city = ? LIMIT ?",
+			wantErr:   `invalid statement of tool "example_tool": statement binds 3 parameters but 2 are declared (placeholders at 1:33, 1:46, 1:54)`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg := mindsdbsql.Config{
+				Name:       "example_tool",
+				Kind:       "mindsdb-sql",
+				Source:     "my-instance",
+				Statement:  tc.statement,
+				Parameters: params,
+			}
+			_, err := cfg.Initialize(srcs)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.wantErr {
+				t.Fatalf("got error %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestManifest(t *testing.T) {
+	srcs := map[string]sources.Source{"my-instance": &mindsdb.Source{}}
+	cfg := mindsdbsql.Config{
+		Name:         "example_tool",
+		Kind:         "mindsdb-sql",
+		Source:       "my-instance",
+		Description:  "some description",
+		Statement:    "SELECT * FROM t WHERE user_id = ? AND country = ?",
+		AuthRequired: []string{"my-google-auth-service"},
+		Parameters: tools.Parameters{
+			tools.NewStringParameterWithAuth("user_id", "some description",
+				[]tools.ParamAuthService{{Name: "my-google-auth-service", Field: "sub"}}),
+			tools.NewStringParameterWithDefault("country", "US", "some description"),
+		},
+	}
+	tool, err := cfg.Initialize(srcs)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	got, err := json.Marshal(tool.Manifest())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := `{"description":"some description","parameters":[` +
+		`{"name":"user_id","type":"string","required":true,"description":"some description","authSources":["my-google-auth-service"]},` +
+		`{"name":"country","type":"string","required":false,"description":"some description","authSources":[],"default":"US","examples":["US"]}` +
+		`],"authRequired":["my-google-auth-service"]}`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Fatalf("incorrect manifest: diff %v", diff)
+	}
+
+	// the input schema agrees with the manifest on the required parameters
+	if diff := cmp.Diff([]string{"user_id"}, tool.McpManifest().InputSchema.Required); diff != "" {
+		t.Fatalf("incorrect required parameters: diff %v", diff)
+	}
+}
+
+func TestInvokeReleasesConnections(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// MindsDB reports every column as TEXT
+	result := fakesql.Result{
+		Columns: []fakesql.Column{{Name: "id", Type: "TEXT"}, {Name: "name", Type: "TEXT"}},
+		Rows: [][]driver.Value{
+			{[]byte("1"), []byte("Alice")},
+			{[]byte("2"), []byte("Bob")},
+			{[]byte("3"), []byte("Carol")},
+		},
+	}
+	failing := result
+	failing.Err, failing.FailAt = errors.New("connection reset"), 1
+
+	var cancel context.CancelFunc
+	cancelled := result
+	cancelled.BeforeRow = func(i int) {
+		if i == 1 {
+			cancel()
+		}
+	}
+
+	uncoercible := result
+	uncoercible.Rows = [][]driver.Value{
+		{[]byte("1"), []byte("Alice")},
+		{[]byte("two"), []byte("Bob")},
+		{[]byte("3"), []byte("Carol")},
+	}
+
+	for _, tc := range []struct {
+		desc    string
+		result  fakesql.Result
+		wantErr bool
+	}{
+		{desc: "success", result: result},
+		{desc: "read error mid-iteration", result: failing, wantErr: true},
+		{desc: "context cancelled mid-iteration", result: cancelled, wantErr: true},
+		{desc: "coercion error mid-iteration", result: uncoercible, wantErr: true},
+	} {
+		for _, database := range []string{"", "my_db"} {
+			t.Run(tc.desc+" "+database, func(t *testing.T) {
+				db := fakesql.Open(t, tc.result)
+				tool := mindsdbsql.Tool{
+					Name:            "example_tool",
+					Kind:            "mindsdb-sql",
+					Pool:            db,
+					Statement:       "SELECT id, name FROM users",
+					ColumnTypes:     tools.ColumnTypes{"id": "integer"},
+					DefaultDatabase: database,
+				}
+				testutils.CheckLeaks(t, func() int { return db.Stats().InUse }, func() {
+					for range 20 {
+						var invokeCtx context.Context
+						invokeCtx, cancel = context.WithCancel(ctx)
+						_, err := tool.Invoke(invokeCtx, tools.Invocation{})
+						cancel()
+						if (err != nil) != tc.wantErr {
+							t.Errorf("unexpected error: got %v, want error %t", err, tc.wantErr)
+							return
+						}
+					}
+				})
+			})
+		}
+	}
+}
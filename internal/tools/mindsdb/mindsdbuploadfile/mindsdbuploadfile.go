@@ -0,0 +1,384 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mindsdbuploadfile
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/mindsdb"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/mindsdb/mindsdbcommon"
+)
+
+const kind string = "mindsdb-upload-file"
+
+const (
+	// defaultMaxFileSize is the largest file accepted when maxFileSize is
+	// not set.
+	defaultMaxFileSize = 10 << 20
+	// defaultMaxRows is the largest number of rows accepted when maxRows is
+	// not set.
+	defaultMaxRows = 100000
+	// insertBatchRows is the number of rows inserted by each statement when
+	// the file is loaded over the MySQL protocol.
+	insertBatchRows = 500
+	// maxPlaceholders is the largest number of placeholders of a prepared
+	// statement over the MySQL protocol.
+	maxPlaceholders = 65535
+)
+
+// tableNameRegex matches the names of the tables that files are uploaded to.
+var tableNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	MindsDBPool() *sql.DB
+	MindsDBHTTPURL() string
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &mindsdb.Source{}
+
+var compatibleSources = [...]string{mindsdb.SourceKind}
+
+type Config struct {
+	Name         string   `yaml:"name" validate:"required"`
+	Kind         string   `yaml:"kind" validate:"required"`
+	Source       string   `yaml:"source" validate:"required"`
+	Description  string   `yaml:"description" validate:"required"`
+	AuthRequired []string `yaml:"authRequired"`
+	// MaxFileSize is the largest file accepted, in bytes.
+	MaxFileSize int64 `yaml:"maxFileSize"`
+	// MaxRows is the largest number of rows accepted, excluding the header.
+	MaxRows int `yaml:"maxRows"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	if cfg.MaxFileSize < 0 {
+		return nil, fmt.Errorf("maxFileSize must not be negative, got %d", cfg.MaxFileSize)
+	}
+	if cfg.MaxRows < 0 {
+		return nil, fmt.Errorf("maxRows must not be negative, got %d", cfg.MaxRows)
+	}
+	maxFileSize := cfg.MaxFileSize
+	if maxFileSize == 0 {
+		maxFileSize = defaultMaxFileSize
+	}
+	maxRows := cfg.MaxRows
+	if maxRows == 0 {
+		maxRows = defaultMaxRows
+	}
+
+	tableParameter := tools.NewStringParameter("table", "The name of the table to create in the files database. It must start with a letter or an underscore, followed by letters, digits or underscores.")
+	fileParameter := tools.NewFileParameter("file", "The content of the CSV file to upload, starting with a header row.", maxFileSize)
+	parameters := tools.Parameters{tableParameter, fileParameter}
+
+	inputSchema, _ := parameters.McpManifest()
+	mcpManifest := tools.McpManifest{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		InputSchema: inputSchema,
+	}
+
+	// finish tool setup
+	t := Tool{
+		Name:         cfg.Name,
+		Kind:         kind,
+		Parameters:   parameters,
+		AuthRequired: cfg.AuthRequired,
+		Pool:         s.MindsDBPool(),
+		HTTPURL:      s.MindsDBHTTPURL(),
+		Client:       http.DefaultClient,
+		MaxFileSize:  maxFileSize,
+		MaxRows:      maxRows,
+		manifest:     tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+var _ tools.FileTool = Tool{}
+
+type Tool struct {
+	Name         string           `yaml:"name"`
+	Kind         string           `yaml:"kind"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+
+	Pool *sql.DB
+	// HTTPURL is the base URL of the MindsDB HTTP API. If empty, files are
+	// loaded with INSERT statements over the MySQL protocol.
+	HTTPURL     string
+	Client      *http.Client
+	MaxFileSize int64
+	MaxRows     int
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
+	table, ok := paramsMap["table"].(string)
+	if !ok {
+		return nil, fmt.Errorf("unable to get cast %s", paramsMap["table"])
+	}
+	if !tableNameRegex.MatchString(table) {
+		return nil, fmt.Errorf("invalid table name %q: it must start with a letter or an underscore, followed by letters, digits or underscores", table)
+	}
+	file, ok := paramsMap["file"].(*tools.File)
+	if !ok {
+		return nil, fmt.Errorf("unable to get cast %s", paramsMap["file"])
+	}
+
+	// the file is read once to validate it before anything is written
+	columns, rows, err := t.scan(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.HTTPURL != "" {
+		err = t.upload(ctx, table, file)
+	} else {
+		err = t.insert(ctx, table, columns, file)
+	}
+	if err != nil {
+		return nil, mindsdbcommon.EnrichError(err)
+	}
+	return map[string]any{
+		"table":        "files." + table,
+		"rowsIngested": rows,
+	}, nil
+}
+
+// scan validates the CSV content of file, and returns its columns and number
+// of rows.
+func (t Tool) scan(file *tools.File) ([]string, int, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, 0, fmt.Errorf("unable to open file: %w", err)
+	}
+	defer rc.Close()
+
+	r := csv.NewReader(rc)
+	r.ReuseRecord = true
+	header, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, 0, fmt.Errorf("file is empty: a header row is required")
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid CSV: %w", err)
+	}
+	columns := slices.Clone(header)
+	for i, c := range columns {
+		if c == "" {
+			return nil, 0, fmt.Errorf("invalid CSV: column %d of the header row is empty", i+1)
+		}
+		if slices.Contains(columns[:i], c) {
+			return nil, 0, fmt.Errorf("invalid CSV: column %q appears more than once in the header row", c)
+		}
+	}
+
+	rows := 0
+	for {
+		_, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid CSV: %w", err)
+		}
+		rows++
+		if rows > t.MaxRows {
+			return nil, 0, fmt.Errorf("file has more than %d rows", t.MaxRows)
+		}
+	}
+	return columns, rows, nil
+}
+
+// upload streams file to the file upload endpoint of the MindsDB HTTP API.
+func (t Tool) upload(ctx context.Context, table string, file *tools.File) error {
+	rc, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("unable to open file: %w", err)
+	}
+	defer rc.Close()
+
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		// MindsDB infers the format of a file from its extension
+		filename := table + ".csv"
+		err := mw.WriteField("original_file_name", filename)
+		if err == nil {
+			var part io.Writer
+			if part, err = mw.CreateFormFile("file", filename); err == nil {
+				_, err = io.Copy(part, rc)
+			}
+		}
+		if err == nil {
+			err = mw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	endpoint := fmt.Sprintf("%s/api/files/%s", t.HTTPURL, url.PathEscape(table))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, pr)
+	if err != nil {
+		pr.Close()
+		return fmt.Errorf("unable to create upload request: %w", err)
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	resp, err := t.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to upload file: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unable to upload file: MindsDB responded with %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// insert loads the rows of file into files.table with batches of INSERT
+// statements.
+func (t Tool) insert(ctx context.Context, table string, columns []string, file *tools.File) error {
+	rc, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("unable to open file: %w", err)
+	}
+	defer rc.Close()
+
+	r := csv.NewReader(rc)
+	// skip the header row, which was validated by scan
+	if _, err := r.Read(); err != nil {
+		return fmt.Errorf("invalid CSV: %w", err)
+	}
+
+	batchRows := min(insertBatchRows, maxPlaceholders/len(columns))
+	batch := make([]any, 0, batchRows*len(columns))
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		stmt := insertStatement(table, columns, len(batch)/len(columns))
+		if _, err := t.Pool.ExecContext(ctx, stmt, batch...); err != nil {
+			return fmt.Errorf("unable to insert rows: %w", err)
+		}
+		batch = batch[:0]
+		return nil
+	}
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("invalid CSV: %w", err)
+		}
+		for _, v := range record {
+			batch = append(batch, v)
+		}
+		if len(batch) == cap(batch) {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return flush()
+}
+
+// insertStatement returns an INSERT statement of n rows into files.table.
+func insertStatement(table string, columns []string, n int) string {
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		quoted[i] = "`" + strings.ReplaceAll(c, "`", "``") + "`"
+	}
+	row := "(" + strings.Repeat("?, ", len(columns)-1) + "?)"
+	rows := strings.Repeat(row+", ", n-1) + row
+	return fmt.Sprintf("INSERT INTO files.`%s` (%s) VALUES %s", table, strings.Join(quoted, ", "), rows)
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return false
+}
+
+// MaxUploadBytes implements tools.FileTool.
+func (t Tool) MaxUploadBytes() int64 {
+	return t.MaxFileSize
+}
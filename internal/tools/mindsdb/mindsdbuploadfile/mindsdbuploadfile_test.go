@@ -0,0 +1,175 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mindsdbuploadfile
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestParseFromYamlMindsDBUploadFile(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		upload_csv:
+			kind: mindsdb-upload-file
+			source: my-mindsdb-instance
+			description: Upload a CSV file to the files database.
+			maxFileSize: 1048576
+			maxRows: 5000
+	`
+	want := server.ToolConfigs{
+		"upload_csv": Config{
+			Name:         "upload_csv",
+			Kind:         "mindsdb-upload-file",
+			Source:       "my-mindsdb-instance",
+			Description:  "Upload a CSV file to the files database.",
+			AuthRequired: []string{},
+			MaxFileSize:  1 << 20,
+			MaxRows:      5000,
+		},
+	}
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	if diff := cmp.Diff(want, got.Tools); diff != "" {
+		t.Fatalf("incorrect parse: diff %v", diff)
+	}
+}
+
+// invoke parses the table and file parameters of t and invokes it.
+func invoke(t *testing.T, tool Tool, table string, file any) (any, error) {
+	t.Helper()
+	params, err := tool.ParseParams(map[string]any{"table": table, "file": file}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return tool.Invoke(context.Background(), tools.Invocation{Params: params})
+}
+
+func newTool(httpURL string) Tool {
+	return Tool{
+		Parameters: tools.Parameters{
+			tools.NewStringParameter("table", "table"),
+			tools.NewFileParameter("file", "file", 64),
+		},
+		HTTPURL:     httpURL,
+		Client:      http.DefaultClient,
+		MaxFileSize: 64,
+		MaxRows:     2,
+	}
+}
+
+func TestUpload(t *testing.T) {
+	type upload struct {
+		method, path, originalName, filename, content string
+	}
+	var got []upload
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f, h, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("unable to read file part: %s", err)
+			return
+		}
+		defer f.Close()
+		b, _ := io.ReadAll(f)
+		got = append(got, upload{r.Method, r.URL.Path, r.FormValue("original_file_name"), h.Filename, string(b)})
+		if strings.Contains(string(b), "fail") {
+			http.Error(w, "file is not valid", http.StatusBadRequest)
+		}
+	}))
+	defer backend.Close()
+	tool := newTool(backend.URL)
+
+	res, err := invoke(t, tool, "sales", tools.NewTextFile("sales.csv", "id,amount\n1,9.5\n2,3\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := map[string]any{"table": "files.sales", "rowsIngested": 2}
+	if diff := cmp.Diff(want, res); diff != "" {
+		t.Fatalf("unexpected result: diff %v", diff)
+	}
+	wantUploads := []upload{{http.MethodPut, "/api/files/sales", "sales.csv", "sales.csv", "id,amount\n1,9.5\n2,3\n"}}
+	if diff := cmp.Diff(wantUploads, got, cmp.AllowUnexported(upload{})); diff != "" {
+		t.Fatalf("unexpected uploads: diff %v", diff)
+	}
+
+	// content passed as a string, such as over MCP
+	if _, err := invoke(t, tool, "notes", "id\n1\n"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(got) != 2 || got[1].content != "id\n1\n" {
+		t.Fatalf("unexpected uploads: %+v", got)
+	}
+
+	_, err = invoke(t, tool, "sales", "id\nfail\n")
+	if err == nil || !strings.Contains(err.Error(), "MindsDB responded with 400 Bad Request: file is not valid") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestUploadInvalid(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected upload of an invalid file")
+	}))
+	defer backend.Close()
+	tool := newTool(backend.URL)
+
+	tcs := []struct {
+		desc  string
+		table string
+		file  string
+		err   string
+	}{
+		{desc: "invalid table name", table: "files.sales; DROP", file: "id\n1\n", err: `invalid table name "files.sales; DROP"`},
+		{desc: "too many rows", table: "sales", file: "id\n1\n2\n3\n", err: "file has more than 2 rows"},
+		{desc: "too large", table: "sales", file: strings.Repeat("x", 65), err: "exceeds the limit of 64 bytes"},
+		{desc: "empty file", table: "sales", file: "", err: "file is empty"},
+		{desc: "inconsistent rows", table: "sales", file: "id,amount\n1\n", err: "invalid CSV"},
+		{desc: "repeated column", table: "sales", file: "id,id\n1,2\n", err: `column "id" appears more than once`},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := invoke(t, tool, tc.table, tc.file)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("unexpected error: got %v, want substring %q", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestInsertStatement(t *testing.T) {
+	got := insertStatement("sales", []string{"id", "odd`name"}, 2)
+	want := "INSERT INTO files.`sales` (`id`, `odd``name`) VALUES (?, ?), (?, ?)"
+	if got != want {
+		t.Fatalf("unexpected statement: got %q, want %q", got, want)
+	}
+}
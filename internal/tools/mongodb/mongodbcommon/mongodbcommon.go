@@ -0,0 +1,65 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mongodbcommon holds the parsing of filters and hints shared by the
+// MongoDB tools counting documents.
+package mongodbcommon
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// HasFilter reports whether a filter payload is set. Tools without a filter
+// apply to the whole collection.
+func HasFilter(filterPayload string) bool {
+	return strings.TrimSpace(filterPayload) != ""
+}
+
+// ParseFilter populates a filter payload with the parameters of an
+// invocation, and parses it as an extended JSON document, in canonical mode
+// if canonical is set, or else relaxed mode. Payloads resolving to other
+// values, such as strings or arrays, are an error.
+func ParseFilter(name, filterPayload string, canonical bool, paramsMap map[string]any) (bson.D, error) {
+	filterString, err := tools.PopulateTemplateWithJSON(name, filterPayload, paramsMap)
+	if err != nil {
+		return nil, fmt.Errorf("error populating filter: %s", err)
+	}
+	filter := bson.D{}
+	if err := bson.UnmarshalExtJSON([]byte(filterString), canonical, &filter); err != nil {
+		return nil, fmt.Errorf("filter must be a document: %s", err)
+	}
+	return filter, nil
+}
+
+// ParseHint parses the hint of a tool, either the name of an index, or its
+// key pattern as an extended JSON document, such as `{ "name": 1 }`. It
+// returns nil if hint is empty.
+func ParseHint(hint string) (any, error) {
+	hint = strings.TrimSpace(hint)
+	if hint == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(hint, "{") {
+		return hint, nil
+	}
+	keys := bson.D{}
+	if err := bson.UnmarshalExtJSON([]byte(hint), false, &keys); err != nil {
+		return nil, fmt.Errorf("invalid hint: %s", err)
+	}
+	return keys, nil
+}
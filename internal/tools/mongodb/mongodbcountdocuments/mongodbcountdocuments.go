@@ -0,0 +1,221 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package mongodbcountdocuments
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/goccy/go-yaml"
+	mongosrc "github.com/googleapis/genai-toolbox/internal/sources/mongodb"
+	"github.com/googleapis/genai-toolbox/internal/tools/mongodb/mongodbcommon"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+const kind string = "mongodb-count-documents"
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type Config struct {
+	Name          string           `yaml:"name" validate:"required"`
+	Kind          string           `yaml:"kind" validate:"required"`
+	Source        string           `yaml:"source" validate:"required"`
+	AuthRequired  []string         `yaml:"authRequired" validate:"required"`
+	Description   string           `yaml:"description" validate:"required"`
+	Database      string           `yaml:"database" validate:"required"`
+	Collection    string           `yaml:"collection" validate:"required"`
+	FilterPayload string           `yaml:"filterPayload"`
+	FilterParams  tools.Parameters `yaml:"filterParams"`
+	Canonical     bool             `yaml:"canonical"`
+	Limit         int64            `yaml:"limit"`
+	Hint          string           `yaml:"hint"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(*mongosrc.Source)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be `mongodb`", kind)
+	}
+
+	// Verify 'limit' value
+	if cfg.Limit < 0 {
+		return nil, fmt.Errorf("limit must not be negative, but got %d", cfg.Limit)
+	}
+
+	hint, err := mongodbcommon.ParseHint(cfg.Hint)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify the filter is a document, if it takes no parameters
+	if !mongodbcommon.HasFilter(cfg.FilterPayload) && len(cfg.FilterParams) > 0 {
+		return nil, fmt.Errorf("filterParams are set, but filterPayload is empty")
+	}
+	if mongodbcommon.HasFilter(cfg.FilterPayload) && len(cfg.FilterParams) == 0 {
+		if _, err := mongodbcommon.ParseFilter("MongoDBCountFilterString", cfg.FilterPayload, cfg.Canonical, map[string]any{}); err != nil {
+			return nil, err
+		}
+	}
+
+	// Verify no duplicate parameter names
+	err = tools.CheckDuplicateParameters(cfg.FilterParams)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create Toolbox manifest
+	paramManifest := cfg.FilterParams.Manifest()
+
+	if paramManifest == nil {
+		paramManifest = make([]tools.ParameterManifest, 0)
+	}
+
+	// Create MCP manifest
+	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, cfg.FilterParams)
+
+	// finish tool setup
+	return Tool{
+		Name:          cfg.Name,
+		Kind:          kind,
+		AuthRequired:  cfg.AuthRequired,
+		Collection:    cfg.Collection,
+		FilterPayload: cfg.FilterPayload,
+		FilterParams:  cfg.FilterParams,
+		Canonical:     cfg.Canonical,
+		Limit:         cfg.Limit,
+		Hint:          cfg.Hint,
+		hint:          hint,
+		database:      s.Client.Database(cfg.Database),
+		manifest:      tools.Manifest{Description: cfg.Description, Parameters: paramManifest, AuthRequired: cfg.AuthRequired},
+		mcpManifest:   mcpManifest,
+	}, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name          string           `yaml:"name"`
+	Kind          string           `yaml:"kind"`
+	AuthRequired  []string         `yaml:"authRequired"`
+	Description   string           `yaml:"description"`
+	Collection    string           `yaml:"collection"`
+	FilterPayload string           `yaml:"filterPayload"`
+	FilterParams  tools.Parameters `yaml:"filterParams"`
+	Canonical     bool             `yaml:"canonical"`
+	Limit         int64            `yaml:"limit"`
+	Hint          string           `yaml:"hint"`
+
+	hint        any
+	database    *mongo.Database
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+// Result is the number of documents counted.
+type Result struct {
+	Count int64 `json:"count"`
+	// Capped is set if the count reached the limit of the tool, so that there
+	// may be more documents.
+	Capped bool `json:"capped"`
+	// Estimated is set if the count is estimated from the metadata of the
+	// collection, as it has no filter.
+	Estimated bool `json:"estimated"`
+}
+
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	coll := t.database.Collection(t.Collection)
+
+	var res Result
+	if !mongodbcommon.HasFilter(t.FilterPayload) {
+		count, err := coll.EstimatedDocumentCount(ctx)
+		if err != nil {
+			return nil, err
+		}
+		res.Count, res.Estimated = count, true
+	} else {
+		filter, err := mongodbcommon.ParseFilter("MongoDBCountFilterString", t.FilterPayload, t.Canonical, inv.Params.AsMap())
+		if err != nil {
+			return nil, err
+		}
+
+		opts := options.Count()
+		if t.Limit > 0 {
+			opts = opts.SetLimit(t.Limit)
+		}
+		if t.hint != nil {
+			opts = opts.SetHint(t.hint)
+		}
+		count, err := coll.CountDocuments(ctx, filter, opts)
+		if err != nil {
+			return nil, err
+		}
+		res.Count = count
+	}
+
+	if t.Limit > 0 && res.Count >= t.Limit {
+		res.Count, res.Capped = t.Limit, true
+	}
+	return res, nil
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.FilterParams, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return false
+}
@@ -0,0 +1,274 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongodbexists_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	mongosrc "github.com/googleapis/genai-toolbox/internal/sources/mongodb"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/mongodb/mongodbexists"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+func TestParseFromYamlMongoExists(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				example_tool:
+					kind: mongodb-exists
+					source: my-instance
+					description: some description
+					database: test_db
+					collection: test_coll
+					filterPayload: |
+					    { name: {{json .name}} }
+					filterParams:
+                        - name: name 
+                          type: string
+                          description: small description
+					canonical: true
+					hint: "{ name: 1 }"
+			`,
+			want: server.ToolConfigs{
+				"example_tool": mongodbexists.Config{
+					Name:          "example_tool",
+					Kind:          "mongodb-exists",
+					Source:        "my-instance",
+					AuthRequired:  []string{},
+					Database:      "test_db",
+					Collection:    "test_coll",
+					Description:   "some description",
+					FilterPayload: "{ name: {{json .name}} }\n",
+					FilterParams: tools.Parameters{
+						&tools.StringParameter{
+							CommonParameter: tools.CommonParameter{
+								Name: "name",
+								Type: "string",
+								Desc: "small description",
+							},
+						},
+					},
+					Canonical: true,
+					Hint:      "{ name: 1 }",
+				},
+			},
+		},
+		{
+			desc: "without filter",
+			in: `
+			tools:
+				example_tool:
+					kind: mongodb-exists
+					source: my-instance
+					description: some description
+					database: test_db
+					collection: test_coll
+			`,
+			want: server.ToolConfigs{
+				"example_tool": mongodbexists.Config{
+					Name:         "example_tool",
+					Kind:         "mongodb-exists",
+					Source:       "my-instance",
+					AuthRequired: []string{},
+					Database:     "test_db",
+					Collection:   "test_coll",
+					Description:  "some description",
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+
+}
+
+func TestFailParseFromYamlMongoExists(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		err  string
+	}{
+		{
+			desc: "missing database",
+			in: `
+			tools:
+				example_tool:
+					kind: mongodb-exists
+					source: my-instance
+					description: some description
+					collection: test_coll
+			`,
+			err: `unable to parse tool "example_tool" as kind "mongodb-exists"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err == nil {
+				t.Fatalf("expect parsing to fail")
+			}
+			errStr := err.Error()
+			if !strings.Contains(errStr, tc.err) {
+				t.Fatalf("unexpected error string: got %q, want substring %q", errStr, tc.err)
+			}
+		})
+	}
+
+}
+
+func TestInitializeMongoExists(t *testing.T) {
+	srcs := map[string]sources.Source{"my-instance": &mongosrc.Source{}}
+	tcs := []struct {
+		desc string
+		cfg  mongodbexists.Config
+		err  string
+	}{
+		{
+			desc: "filter not a document",
+			cfg:  mongodbexists.Config{FilterPayload: `[{ "name": "Alice" }]`},
+			err:  "filter must be a document",
+		},
+		{
+			desc: "filter params without filter",
+			cfg:  mongodbexists.Config{FilterParams: tools.Parameters{tools.NewStringParameter("name", "name")}},
+			err:  "filterPayload is empty",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			tc.cfg.Name, tc.cfg.Kind, tc.cfg.Source = "example_tool", "mongodb-exists", "my-instance"
+			_, err := tc.cfg.Initialize(srcs)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("unexpected error: got %v, want substring %q", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestInvokeMongoExists(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	tcs := []struct {
+		desc      string
+		cfg       mongodbexists.Config
+		params    map[string]any
+		response  bson.D
+		want      bool
+		wantInCmd []string
+	}{
+		{
+			desc: "exists",
+			cfg: mongodbexists.Config{
+				FilterPayload: `{ "name": {{json .name}} }`,
+				FilterParams:  tools.Parameters{tools.NewStringParameter("name", "name")},
+			},
+			params:    map[string]any{"name": "Alice"},
+			response:  mtest.CreateCursorResponse(0, "test_db.test_coll", mtest.FirstBatch, bson.D{{Key: "_id", Value: int32(1)}}),
+			want:      true,
+			wantInCmd: []string{`"filter": {"name": "Alice"}`, `"projection": {"_id": {"$numberInt":"1"}}`, `"limit": {"$numberLong":"1"}`},
+		},
+		{
+			desc: "does not exist",
+			cfg: mongodbexists.Config{
+				FilterPayload: `{ "name": {{json .name}} }`,
+				FilterParams:  tools.Parameters{tools.NewStringParameter("name", "name")},
+				Hint:          "name_1",
+			},
+			params:    map[string]any{"name": "Zed"},
+			response:  mtest.CreateCursorResponse(0, "test_db.test_coll", mtest.FirstBatch),
+			want:      false,
+			wantInCmd: []string{`"hint": "name_1"`},
+		},
+		{
+			desc:      "without filter",
+			response:  mtest.CreateCursorResponse(0, "test_db.test_coll", mtest.FirstBatch, bson.D{{Key: "_id", Value: int32(1)}}),
+			want:      true,
+			wantInCmd: []string{`"filter": {}`},
+		},
+	}
+	for _, tc := range tcs {
+		mt.Run(tc.desc, func(mt *mtest.T) {
+			cfg := tc.cfg
+			cfg.Name, cfg.Kind, cfg.Source = "example_tool", "mongodb-exists", "my-instance"
+			cfg.Database, cfg.Collection = "test_db", "test_coll"
+			tool, err := cfg.Initialize(map[string]sources.Source{"my-instance": &mongosrc.Source{Client: mt.Client}})
+			if err != nil {
+				t.Fatalf("unable to initialize tool: %s", err)
+			}
+			params, err := tool.ParseParams(tc.params, nil)
+			if err != nil {
+				t.Fatalf("unable to parse params: %s", err)
+			}
+
+			mt.AddMockResponses(tc.response)
+			got, err := tool.Invoke(context.Background(), tools.Invocation{Params: params})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != tc.want {
+				t.Fatalf("unexpected result: got %v, want %v", got, tc.want)
+			}
+			started := mt.GetStartedEvent()
+			if started.CommandName != "find" {
+				t.Fatalf("unexpected command: got %q, want %q", started.CommandName, "find")
+			}
+			for _, want := range tc.wantInCmd {
+				if !strings.Contains(started.Command.String(), want) {
+					t.Fatalf("expected command %s to contain %s", started.Command, want)
+				}
+			}
+		})
+	}
+}
@@ -54,6 +54,7 @@ type Config struct {
 	Database     string   `yaml:"database" validate:"required"`
 	Collection   string   `yaml:"collection" validate:"required"`
 	Canonical    bool     `yaml:"canonical" validate:"required"` //i want to force the user to choose
+	FailFast     bool     `yaml:"failFast"`
 }
 
 // validate interface
@@ -96,6 +97,7 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		AuthRequired:  cfg.AuthRequired,
 		Collection:    cfg.Collection,
 		Canonical:     cfg.Canonical,
+		FailFast:      cfg.FailFast,
 		PayloadParams: allParameters,
 		database:      s.Client.Database(cfg.Database),
 		manifest:      tools.Manifest{Description: cfg.Description, Parameters: paramManifest, AuthRequired: cfg.AuthRequired},
@@ -113,6 +115,7 @@ type Tool struct {
 	Description   string   `yaml:"description"`
 	Collection    string   `yaml:"collection"`
 	Canonical     bool     `yaml:"canonical" validation:"required"` //i want to force the user to choose
+	FailFast      bool     `yaml:"failFast"`
 	PayloadParams tools.Parameters
 
 	database    *mongo.Database
@@ -120,12 +123,12 @@ type Tool struct {
 	mcpManifest tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	if len(params) == 0 {
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	if len(inv.Params) == 0 {
 		return nil, errors.New("no input found")
 	}
 
-	paramsMap := params.AsMap()
+	paramsMap := inv.Params.AsMap()
 
 	var jsonData, ok = paramsMap[paramDataKey].(string)
 	if !ok {
@@ -138,12 +141,21 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		return nil, err
 	}
 
-	res, err := t.database.Collection(t.Collection).InsertMany(ctx, data, options.InsertMany())
-	if err != nil {
+	// Unless failing fast, insert without ordering so that the documents
+	// after one that fails are still inserted.
+	res, err := t.database.Collection(t.Collection).InsertMany(ctx, data, options.InsertMany().SetOrdered(t.FailFast))
+	if err != nil && t.FailFast {
 		return nil, err
 	}
 
-	return res.InsertedIDs, nil
+	result, err := insertManyResult(len(data), res, err)
+	if err != nil {
+		return nil, err
+	}
+	if err := result.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
@@ -61,6 +61,33 @@ func TestParseFromYamlMongoQuery(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "fail fast",
+			in: `
+			tools:
+				example_tool:
+					kind: mongodb-insert-many
+					source: my-instance
+					description: some description
+					database: test_db
+					collection: test_coll
+					canonical: true
+					failFast: true
+			`,
+			want: server.ToolConfigs{
+				"example_tool": mongodbinsertmany.Config{
+					Name:         "example_tool",
+					Kind:         "mongodb-insert-many",
+					Source:       "my-instance",
+					AuthRequired: []string{},
+					Database:     "test_db",
+					Collection:   "test_coll",
+					Description:  "some description",
+					Canonical:    true,
+					FailFast:     true,
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -0,0 +1,55 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongodbinsertmany
+
+import (
+	"errors"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// insertManyResult returns the bulk result of inserting n documents without
+// ordering, given the outcome of InsertMany. Errors other than per-document
+// write errors are returned as is.
+func insertManyResult(n int, res *mongo.InsertManyResult, err error) (*tools.BulkResult, error) {
+	failed := make(map[int]error)
+	if err != nil {
+		var bulkErr mongo.BulkWriteException
+		if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil || res == nil {
+			return nil, err
+		}
+		for _, we := range bulkErr.WriteErrors {
+			failed[we.Index] = errors.New(we.Message)
+		}
+	}
+
+	// InsertedIDs only holds the ids of the documents that were inserted,
+	// in input order.
+	ids := res.InsertedIDs
+	result := tools.NewBulkResult(n)
+	for i := 0; i < n; i++ {
+		if err, ok := failed[i]; ok {
+			result.Add(nil, err)
+			continue
+		}
+		if len(ids) == 0 {
+			return nil, errors.New("missing id of inserted document")
+		}
+		result.Add(ids[0], nil)
+		ids = ids[1:]
+	}
+	return result, nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongodbinsertmany
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestInsertManyResult(t *testing.T) {
+	duplicate := func(indexes ...int) error {
+		bulkErr := mongo.BulkWriteException{}
+		for _, i := range indexes {
+			bulkErr.WriteErrors = append(bulkErr.WriteErrors, mongo.BulkWriteError{
+				WriteError: mongo.WriteError{Index: i, Code: 11000, Message: "E11000 duplicate key error"},
+			})
+		}
+		return bulkErr
+	}
+	tcs := []struct {
+		desc    string
+		n       int
+		res     *mongo.InsertManyResult
+		err     error
+		want    *tools.BulkResult
+		wantErr string
+	}{
+		{
+			desc: "all inserted",
+			n:    2,
+			res:  &mongo.InsertManyResult{InsertedIDs: []any{"a", "b"}},
+			want: &tools.BulkResult{Succeeded: 2, Errors: []tools.BulkItemError{}, Results: []any{"a", "b"}},
+		},
+		{
+			desc: "mixed",
+			n:    4,
+			res:  &mongo.InsertManyResult{InsertedIDs: []any{"a", "c"}},
+			err:  duplicate(1, 3),
+			want: &tools.BulkResult{
+				Succeeded: 2,
+				Failed:    2,
+				Errors: []tools.BulkItemError{
+					{Index: 1, Message: "E11000 duplicate key error"},
+					{Index: 3, Message: "E11000 duplicate key error"},
+				},
+				Results: []any{"a", nil, "c", nil},
+			},
+		},
+		{
+			desc: "none inserted",
+			n:    2,
+			res:  &mongo.InsertManyResult{},
+			err:  duplicate(0, 1),
+			want: &tools.BulkResult{
+				Failed: 2,
+				Errors: []tools.BulkItemError{
+					{Index: 0, Message: "E11000 duplicate key error"},
+					{Index: 1, Message: "E11000 duplicate key error"},
+				},
+				Results: []any{nil, nil},
+			},
+		},
+		{
+			desc:    "write concern error",
+			n:       1,
+			res:     &mongo.InsertManyResult{InsertedIDs: []any{"a"}},
+			err:     mongo.BulkWriteException{WriteConcernError: &mongo.WriteConcernError{Message: "waiting for replication timed out"}},
+			wantErr: "bulk write exception: write concern error: waiting for replication timed out",
+		},
+		{
+			desc:    "other error",
+			n:       1,
+			err:     errors.New("connection reset"),
+			wantErr: "connection reset",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := insertManyResult(tc.n, tc.res, tc.err)
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Fatalf("unexpected error: got %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect result: diff %v", diff)
+			}
+			if (got.Err() != nil) != (got.Succeeded == 0) {
+				t.Fatalf("unexpected total failure error: %v", got.Err())
+			}
+		})
+	}
+}
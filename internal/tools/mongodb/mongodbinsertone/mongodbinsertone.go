@@ -121,12 +121,12 @@ type Tool struct {
 	mcpManifest tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	if len(params) == 0 {
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	if len(inv.Params) == 0 {
 		return nil, errors.New("no input found")
 	}
 	// use the first, assume it's a string
-	var jsonData, ok = params[0].Value.(string)
+	var jsonData, ok = inv.Params[0].Value.(string)
 	if !ok {
 		return nil, errors.New("no input found")
 	}
@@ -0,0 +1,231 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlcommon
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// maxGeometryDepth bounds the nesting of geometry collections.
+const maxGeometryDepth = 32
+
+// geometryToText returns the WKT of a GEOMETRY value in MySQL's internal
+// format: a 4-byte little-endian SRID followed by the WKB of the geometry.
+// A non-zero SRID is prefixed as in EWKT, e.g. `SRID=4326;POINT(1 2)`.
+// Values that cannot be decoded are returned in base64.
+func geometryToText(b []byte) string {
+	if len(b) < 4 {
+		return base64.StdEncoding.EncodeToString(b)
+	}
+	srid := binary.LittleEndian.Uint32(b)
+	r := &wkbReader{b: b[4:]}
+	var sb strings.Builder
+	if srid != 0 {
+		fmt.Fprintf(&sb, "SRID=%d;", srid)
+	}
+	if err := r.geometry(&sb, 0); err != nil || len(r.b) != 0 {
+		return base64.StdEncoding.EncodeToString(b)
+	}
+	return sb.String()
+}
+
+// wkbReader decodes WKB into WKT.
+type wkbReader struct {
+	b     []byte
+	order binary.ByteOrder
+}
+
+var errWKBTruncated = fmt.Errorf("truncated WKB")
+
+func (r *wkbReader) uint32() (uint32, error) {
+	if len(r.b) < 4 {
+		return 0, errWKBTruncated
+	}
+	v := r.order.Uint32(r.b)
+	r.b = r.b[4:]
+	return v, nil
+}
+
+// count reads a number of elements, each at least size bytes long.
+func (r *wkbReader) count(size int) (int, error) {
+	n, err := r.uint32()
+	if err != nil {
+		return 0, err
+	}
+	if int(n) > len(r.b)/size {
+		return 0, errWKBTruncated
+	}
+	return int(n), nil
+}
+
+func (r *wkbReader) point(sb *strings.Builder) error {
+	if len(r.b) < 16 {
+		return errWKBTruncated
+	}
+	x := math.Float64frombits(r.order.Uint64(r.b))
+	y := math.Float64frombits(r.order.Uint64(r.b[8:]))
+	r.b = r.b[16:]
+	sb.WriteString(strconv.FormatFloat(x, 'f', -1, 64))
+	sb.WriteByte(' ')
+	sb.WriteString(strconv.FormatFloat(y, 'f', -1, 64))
+	return nil
+}
+
+func (r *wkbReader) points(sb *strings.Builder) error {
+	n, err := r.count(16)
+	if err != nil {
+		return err
+	}
+	sb.WriteByte('(')
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		if err := r.point(sb); err != nil {
+			return err
+		}
+	}
+	sb.WriteByte(')')
+	return nil
+}
+
+func (r *wkbReader) rings(sb *strings.Builder) error {
+	n, err := r.count(4)
+	if err != nil {
+		return err
+	}
+	sb.WriteByte('(')
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		if err := r.points(sb); err != nil {
+			return err
+		}
+	}
+	sb.WriteByte(')')
+	return nil
+}
+
+// header reads the byte order and the type of a geometry.
+func (r *wkbReader) header() (uint32, error) {
+	if len(r.b) < 1 {
+		return 0, errWKBTruncated
+	}
+	switch r.b[0] {
+	case 0:
+		r.order = binary.BigEndian
+	case 1:
+		r.order = binary.LittleEndian
+	default:
+		return 0, fmt.Errorf("invalid WKB byte order %d", r.b[0])
+	}
+	r.b = r.b[1:]
+	return r.uint32()
+}
+
+// members writes the n geometries of a multi-geometry or a collection,
+// each with its own header. Members of multi-geometries must be of type
+// want, and are written without their type.
+func (r *wkbReader) members(sb *strings.Builder, want uint32, depth int) error {
+	n, err := r.count(5)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		sb.WriteString(" EMPTY")
+		return nil
+	}
+	sb.WriteByte('(')
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		if want == 0 {
+			if err := r.geometry(sb, depth+1); err != nil {
+				return err
+			}
+			continue
+		}
+		typ, err := r.header()
+		if err != nil {
+			return err
+		}
+		if typ != want {
+			return fmt.Errorf("unexpected WKB member type %d", typ)
+		}
+		if err := r.body(sb, typ); err != nil {
+			return err
+		}
+	}
+	sb.WriteByte(')')
+	return nil
+}
+
+// body writes the coordinates of a point, line string or polygon.
+func (r *wkbReader) body(sb *strings.Builder, typ uint32) error {
+	switch typ {
+	case 1:
+		sb.WriteByte('(')
+		if err := r.point(sb); err != nil {
+			return err
+		}
+		sb.WriteByte(')')
+		return nil
+	case 2:
+		return r.points(sb)
+	case 3:
+		return r.rings(sb)
+	}
+	return fmt.Errorf("unsupported WKB type %d", typ)
+}
+
+func (r *wkbReader) geometry(sb *strings.Builder, depth int) error {
+	if depth > maxGeometryDepth {
+		return fmt.Errorf("WKB nested too deeply")
+	}
+	typ, err := r.header()
+	if err != nil {
+		return err
+	}
+	switch typ {
+	case 1:
+		sb.WriteString("POINT")
+	case 2:
+		sb.WriteString("LINESTRING")
+	case 3:
+		sb.WriteString("POLYGON")
+	case 4:
+		sb.WriteString("MULTIPOINT")
+		return r.members(sb, 1, depth)
+	case 5:
+		sb.WriteString("MULTILINESTRING")
+		return r.members(sb, 2, depth)
+	case 6:
+		sb.WriteString("MULTIPOLYGON")
+		return r.members(sb, 3, depth)
+	case 7:
+		sb.WriteString("GEOMETRYCOLLECTION")
+		return r.members(sb, 0, depth)
+	default:
+		return fmt.Errorf("unsupported WKB type %d", typ)
+	}
+	return r.body(sb, typ)
+}
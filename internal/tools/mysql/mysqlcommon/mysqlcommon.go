@@ -15,15 +15,51 @@
 package mysqlcommon
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
 )
 
 // ConvertToType handles casting mysql returns to the right type
 // types for mysql driver: https://github.com/go-sql-driver/mysql/blob/v1.9.3/fields.go
 // all numeric type or unknown type will be return as is.
-func ConvertToType(t *sql.ColumnType, v any) (any, error) {
+// DATETIME and TIMESTAMP values, parsed with `parseTime`, are converted to
+// the result timezone of ctx, if any. DATE values are not.
+// YEAR values are returned as integers, SET values as lists of their members
+// and GEOMETRY values as WKT, or base64 if they cannot be decoded.
+func ConvertToType(ctx context.Context, t *sql.ColumnType, v any) (any, error) {
+	switch t.DatabaseTypeName() {
+	case "DATETIME", "TIMESTAMP":
+		return tools.ConvertTimestamp(ctx, v), nil
+	case "YEAR":
+		if b, ok := v.([]byte); ok {
+			return strconv.ParseInt(string(b), 10, 64)
+		}
+		return v, nil
+	case "ENUM":
+		return asString(v), nil
+	case "SET":
+		s := asString(v)
+		if s == "" {
+			return []any{}, nil
+		}
+		members := []any{}
+		for _, m := range strings.Split(s, ",") {
+			members = append(members, m)
+		}
+		return members, nil
+	case "GEOMETRY":
+		if b, ok := v.([]byte); ok {
+			return geometryToText(b), nil
+		}
+		return v, nil
+	}
 	switch t.ScanType() {
 	case reflect.TypeOf(""), reflect.TypeOf([]byte{}), reflect.TypeOf(sql.NullString{}):
 		// unmarshal JSON data before returning to prevent double marshaling
@@ -41,3 +77,55 @@ func ConvertToType(t *sql.ColumnType, v any) (any, error) {
 		return v, nil
 	}
 }
+
+// asString returns v, scanned as bytes or a string, as a string.
+func asString(v any) string {
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
+
+// ExpandArrayParams expands each `?` placeholder whose parameter is an array
+// into one placeholder per element, so that arrays can be used in `IN (?)`
+// expressions. An empty array is replaced by NULL, which matches nothing.
+// Placeholders inside quoted strings or identifiers are left untouched.
+func ExpandArrayParams(statement string, params []any) (string, []any) {
+	var sb strings.Builder
+	newParams := make([]any, 0, len(params))
+	idx := 0
+	var quote rune
+	escaped := false
+	for _, r := range statement {
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0:
+			if r == '\\' && quote != '`' {
+				escaped = true
+			} else if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '?' && idx < len(params):
+			p := params[idx]
+			idx++
+			if arr, ok := p.([]any); ok {
+				if len(arr) == 0 {
+					sb.WriteString("NULL")
+				} else {
+					sb.WriteString(strings.TrimSuffix(strings.Repeat("?, ", len(arr)), ", "))
+					newParams = append(newParams, arr...)
+				}
+				continue
+			}
+			newParams = append(newParams, p)
+		}
+		sb.WriteRune(r)
+	}
+	// keep any parameters without a matching placeholder so that the driver
+	// reports the mismatch
+	newParams = append(newParams, params[idx:]...)
+	return sb.String(), newParams
+}
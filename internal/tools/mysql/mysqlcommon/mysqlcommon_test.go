@@ -0,0 +1,205 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlcommon_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/testutils/fakesql"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/mysql/mysqlcommon"
+)
+
+func TestExpandArrayParams(t *testing.T) {
+	doc, err := tools.NewJSONValue([]any{"a", "b"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		name       string
+		statement  string
+		params     []any
+		wantStmt   string
+		wantParams []any
+	}{
+		{
+			name:       "no arrays",
+			statement:  "SELECT * FROM t WHERE a = ? AND b = ?",
+			params:     []any{1, "x"},
+			wantStmt:   "SELECT * FROM t WHERE a = ? AND b = ?",
+			wantParams: []any{1, "x"},
+		},
+		{
+			name:       "array in IN clause",
+			statement:  "SELECT * FROM t WHERE a = ? AND id IN (?)",
+			params:     []any{"x", []any{1, 2, 3}},
+			wantStmt:   "SELECT * FROM t WHERE a = ? AND id IN (?, ?, ?)",
+			wantParams: []any{"x", 1, 2, 3},
+		},
+		{
+			name:       "empty array",
+			statement:  "SELECT * FROM t WHERE id IN (?)",
+			params:     []any{[]any{}},
+			wantStmt:   "SELECT * FROM t WHERE id IN (NULL)",
+			wantParams: []any{},
+		},
+		{
+			name:       "placeholders in quotes are ignored",
+			statement:  "SELECT '?', \"it\\\"s?\", `col?` FROM t WHERE id IN (?)",
+			params:     []any{[]any{"a", "b"}},
+			wantStmt:   "SELECT '?', \"it\\\"s?\", `col?` FROM t WHERE id IN (?, ?)",
+			wantParams: []any{"a", "b"},
+		},
+		{
+			name:       "json values are not expanded",
+			statement:  "INSERT INTO t (doc) VALUES (?)",
+			params:     []any{doc},
+			wantStmt:   "INSERT INTO t (doc) VALUES (?)",
+			wantParams: []any{doc},
+		},
+		{
+			name:       "extra params are kept",
+			statement:  "SELECT ?",
+			params:     []any{1, 2},
+			wantStmt:   "SELECT ?",
+			wantParams: []any{1, 2},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			gotStmt, gotParams := mysqlcommon.ExpandArrayParams(tc.statement, tc.params)
+			if gotStmt != tc.wantStmt {
+				t.Fatalf("unexpected statement: got %q, want %q", gotStmt, tc.wantStmt)
+			}
+			if diff := cmp.Diff(tc.wantParams, gotParams, cmp.Comparer(func(a, b tools.JSONValue) bool { return a.String() == b.String() })); diff != "" {
+				t.Fatalf("unexpected params (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// wkb builds WKB, in little-endian order unless bigEndian is set.
+type wkb struct {
+	bigEndian bool
+	buf       bytes.Buffer
+}
+
+func (w *wkb) order() binary.ByteOrder {
+	if w.bigEndian {
+		return binary.BigEndian
+	}
+	return binary.LittleEndian
+}
+
+func (w *wkb) header(typ uint32) *wkb {
+	if w.bigEndian {
+		w.buf.WriteByte(0)
+	} else {
+		w.buf.WriteByte(1)
+	}
+	return w.uint32(typ)
+}
+
+func (w *wkb) uint32(v uint32) *wkb {
+	_ = binary.Write(&w.buf, w.order(), v)
+	return w
+}
+
+func (w *wkb) points(coords ...float64) *wkb {
+	for _, c := range coords {
+		_ = binary.Write(&w.buf, w.order(), math.Float64bits(c))
+	}
+	return w
+}
+
+// geometry returns b in MySQL's internal format, with srid.
+func geometry(srid uint32, b []byte) []byte {
+	out := binary.LittleEndian.AppendUint32(nil, srid)
+	return append(out, b...)
+}
+
+func TestConvertToType(t *testing.T) {
+	point := new(wkb).header(1).points(1, 2.5).buf.Bytes()
+	bigEndianPoint := (&wkb{bigEndian: true}).header(1).points(-3, 4).buf.Bytes()
+	lineString := new(wkb).header(2).uint32(2).points(0, 0, 1, 1).buf.Bytes()
+	polygon := new(wkb).header(3).uint32(1).uint32(4).points(0, 0, 1, 0, 1, 1, 0, 0).buf.Bytes()
+	multiPoint := new(wkb).header(4).uint32(2)
+	multiPoint.header(1).points(1, 2).header(1).points(3, 4)
+	collection := new(wkb).header(7).uint32(2)
+	collection.buf.Write(point)
+	collection.buf.Write(lineString)
+	emptyCollection := new(wkb).header(7).uint32(0).buf.Bytes()
+	truncated := point[:10]
+
+	tcs := []struct {
+		desc     string
+		dataType string
+		value    driver.Value
+		want     any
+	}{
+		{desc: "year", dataType: "YEAR", value: int64(2025), want: int64(2025)},
+		{desc: "year as text", dataType: "YEAR", value: []byte("1999"), want: int64(1999)},
+		{desc: "enum", dataType: "ENUM", value: []byte("medium"), want: "medium"},
+		{desc: "set", dataType: "SET", value: []byte("read,write"), want: []any{"read", "write"}},
+		{desc: "empty set", dataType: "SET", value: []byte(""), want: []any{}},
+		{desc: "point", dataType: "GEOMETRY", value: geometry(0, point), want: "POINT(1 2.5)"},
+		{desc: "point with srid", dataType: "GEOMETRY", value: geometry(4326, point), want: "SRID=4326;POINT(1 2.5)"},
+		{desc: "big-endian point", dataType: "GEOMETRY", value: geometry(0, bigEndianPoint), want: "POINT(-3 4)"},
+		{desc: "line string", dataType: "GEOMETRY", value: geometry(0, lineString), want: "LINESTRING(0 0,1 1)"},
+		{desc: "polygon", dataType: "GEOMETRY", value: geometry(0, polygon), want: "POLYGON((0 0,1 0,1 1,0 0))"},
+		{desc: "multi point", dataType: "GEOMETRY", value: geometry(0, multiPoint.buf.Bytes()), want: "MULTIPOINT((1 2),(3 4))"},
+		{desc: "collection", dataType: "GEOMETRY", value: geometry(0, collection.buf.Bytes()), want: "GEOMETRYCOLLECTION(POINT(1 2.5),LINESTRING(0 0,1 1))"},
+		{desc: "empty collection", dataType: "GEOMETRY", value: geometry(0, emptyCollection), want: "GEOMETRYCOLLECTION EMPTY"},
+		{desc: "truncated geometry", dataType: "GEOMETRY", value: geometry(0, truncated), want: base64.StdEncoding.EncodeToString(geometry(0, truncated))},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			db := fakesql.Open(t, fakesql.Result{
+				Columns: []fakesql.Column{{Name: "v", Type: tc.dataType}},
+				Rows:    [][]driver.Value{{tc.value}},
+			})
+			rows, err := db.QueryContext(context.Background(), "SELECT v")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			defer rows.Close()
+			colTypes, err := rows.ColumnTypes()
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !rows.Next() {
+				t.Fatalf("no row: %v", rows.Err())
+			}
+			var v any
+			if err := rows.Scan(&v); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			got, err := mysqlcommon.ConvertToType(context.Background(), colTypes[0], v)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect value: diff %v", diff)
+			}
+		})
+	}
+}
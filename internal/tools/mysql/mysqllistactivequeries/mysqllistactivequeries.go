@@ -189,8 +189,8 @@ type Tool struct {
 	statement    string
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	paramsMap := params.AsMap()
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
 
 	duration, ok := paramsMap["min_duration_secs"].(int)
 	if !ok {
@@ -245,7 +245,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 				continue
 			}
 
-			vMap[name], err = mysqlcommon.ConvertToType(colTypes[i], val)
+			vMap[name], err = mysqlcommon.ConvertToType(ctx, colTypes[i], val)
 			if err != nil {
 				return nil, fmt.Errorf("errors encountered when converting values: %w", err)
 			}
@@ -138,8 +138,8 @@ type Tool struct {
 	mcpManifest  tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	paramsMap := params.AsMap()
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
 
 	table_schema, ok := paramsMap["table_schema"].(string)
 	if !ok {
@@ -202,7 +202,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 				continue
 			}
 
-			vMap[name], err = mysqlcommon.ConvertToType(colTypes[i], val)
+			vMap[name], err = mysqlcommon.ConvertToType(ctx, colTypes[i], val)
 			if err != nil {
 				return nil, fmt.Errorf("errors encountered when converting values: %w", err)
 			}
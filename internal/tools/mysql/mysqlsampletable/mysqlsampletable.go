@@ -0,0 +1,347 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlsampletable
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/cloudsqlmysql"
+	"github.com/googleapis/genai-toolbox/internal/sources/mysql"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/mysql/mysqlcommon"
+)
+
+const kind string = "mysql-sample-table"
+
+const (
+	// strategyLimit returns the first rows of the table.
+	strategyLimit = "limit"
+	// strategyOrderByRand shuffles every row of the table.
+	strategyOrderByRand = "orderByRand"
+	// strategyRandFilter keeps each row with a probability, until the sample
+	// is full.
+	strategyRandFilter = "randFilter"
+)
+
+const (
+	// smallTableRows is the most rows of a table shuffled whole for a random
+	// sample.
+	smallTableRows = 10000
+	// sampleOversampling is how many more rows than the sample size the
+	// filter of strategyRandFilter is expected to keep over the whole table,
+	// so that the sample is rarely short and the scan stops early.
+	sampleOversampling = 3
+)
+
+// tableStatement returns the schema, the name and the estimated rows of a
+// base table, in the current database unless its schema is given.
+const tableStatement = `
+	SELECT TABLE_SCHEMA, TABLE_NAME, TABLE_ROWS
+	FROM information_schema.TABLES
+	WHERE TABLE_SCHEMA = COALESCE(?, DATABASE()) AND TABLE_NAME = ? AND TABLE_TYPE = 'BASE TABLE'
+`
+
+// statsStatement returns the number of distinct values estimated for the
+// columns leading an index of a table.
+const statsStatement = `
+	SELECT COLUMN_NAME, MAX(CARDINALITY)
+	FROM information_schema.STATISTICS
+	WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND SEQ_IN_INDEX = 1 AND CARDINALITY IS NOT NULL
+	GROUP BY COLUMN_NAME
+`
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	MySQLPool() *sql.DB
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &mysql.Source{}
+var _ compatibleSource = &cloudsqlmysql.Source{}
+
+var compatibleSources = [...]string{mysql.SourceKind, cloudsqlmysql.SourceKind}
+
+type Config struct {
+	Name         string   `yaml:"name" validate:"required"`
+	Kind         string   `yaml:"kind" validate:"required"`
+	Source       string   `yaml:"source" validate:"required"`
+	Description  string   `yaml:"description" validate:"required"`
+	AuthRequired []string `yaml:"authRequired"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	parameters := tools.SampleTableParameters()
+	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, parameters)
+
+	// finish tool setup
+	t := Tool{
+		Name:         cfg.Name,
+		Kind:         kind,
+		AuthRequired: cfg.AuthRequired,
+		Pool:         s.MySQLPool(),
+		allParams:    parameters,
+		manifest:     tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string           `yaml:"name"`
+	Kind         string           `yaml:"kind"`
+	AuthRequired []string         `yaml:"authRequired"`
+	allParams    tools.Parameters `yaml:"parameters"`
+	Pool         *sql.DB
+	manifest     tools.Manifest
+	mcpManifest  tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
+	table, ok := paramsMap["table"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid 'table' parameter; expected a string")
+	}
+	size, ok := paramsMap["sample_size"].(int)
+	if !ok {
+		return nil, fmt.Errorf("invalid 'sample_size' parameter; expected an integer")
+	}
+	method, ok := paramsMap["method"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid 'method' parameter; expected a string")
+	}
+	schema, name, err := tools.ParseSampleTable(table)
+	if err != nil {
+		return nil, err
+	}
+
+	var schemaParam any
+	if schema != "" {
+		schemaParam = schema
+	}
+	var rows sql.NullInt64
+	err = t.Pool.QueryRowContext(ctx, tableStatement, schemaParam, name).Scan(&schema, &name, &rows)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("table %q does not exist", table)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to look up table %q: %w", table, err)
+	}
+
+	strategy := strategyLimit
+	var probability float64
+	if method == tools.SampleMethodRandom {
+		estimated := int64(-1)
+		if rows.Valid {
+			estimated = rows.Int64
+		}
+		strategy, probability = randomStrategy(size, estimated)
+	}
+	statement, args := SampleStatement(quoteIdentifier(schema)+"."+quoteIdentifier(name), strategy, probability, size)
+	results, err := t.Pool.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sample table %q: %w", table, err)
+	}
+	defer results.Close()
+
+	cols, err := results.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve rows column name: %w", err)
+	}
+	colTypes, err := results.ColumnTypes()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get column types: %w", err)
+	}
+
+	// create an array of values for each column, which can be re-used to scan each row
+	rawValues := make([]any, len(cols))
+	values := make([]any, len(cols))
+	for i := range rawValues {
+		values[i] = &rawValues[i]
+	}
+
+	sampled := make([]map[string]any, 0, size)
+	for results.Next() {
+		if err := results.Scan(values...); err != nil {
+			return nil, fmt.Errorf("unable to parse row: %w", err)
+		}
+		vMap := make(map[string]any, len(cols))
+		for i, name := range cols {
+			val := rawValues[i]
+			if val == nil {
+				vMap[name] = nil
+				continue
+			}
+			vMap[name], err = mysqlcommon.ConvertToType(ctx, colTypes[i], val)
+			if err != nil {
+				return nil, fmt.Errorf("errors encountered when converting values: %w", err)
+			}
+		}
+		sampled = append(sampled, vMap)
+	}
+	if err := results.Err(); err != nil {
+		return nil, fmt.Errorf("errors encountered during row iteration: %w", err)
+	}
+
+	stats, err := t.stats(ctx, schema, name)
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]tools.SampleColumn, len(cols))
+	for i, c := range cols {
+		columns[i] = tools.SampleColumn{Name: c, Ordered: isOrdered(colTypes[i].DatabaseTypeName())}
+	}
+
+	result := tools.SampleResult{
+		Table:    schema + "." + name,
+		Method:   method,
+		Strategy: strategy,
+		Rows:     tools.SampleRows(sampled),
+		Columns:  tools.SummarizeSample(columns, sampled, stats),
+	}
+	if rows.Valid {
+		result.EstimatedRows = &rows.Int64
+	}
+	return result, nil
+}
+
+// stats returns the number of distinct values of the columns of a table
+// leading an index. MySQL keeps no statistics of the NULL values of a
+// column.
+func (t Tool) stats(ctx context.Context, schema, name string) (map[string]tools.ColumnStatistics, error) {
+	results, err := t.Pool.QueryContext(ctx, statsStatement, schema, name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read statistics: %w", err)
+	}
+	defer results.Close()
+
+	stats := make(map[string]tools.ColumnStatistics)
+	for results.Next() {
+		var column string
+		var cardinality float64
+		if err := results.Scan(&column, &cardinality); err != nil {
+			return nil, fmt.Errorf("unable to parse row: %w", err)
+		}
+		stats[column] = tools.ColumnStatistics{DistinctCount: &cardinality}
+	}
+	if err := results.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read statistics: %w", err)
+	}
+	return stats, nil
+}
+
+// randomStrategy returns how size random rows are sampled from a table of an
+// estimated rows rows, negative if unknown, and the probability of keeping a
+// row for strategyRandFilter. Small tables are shuffled whole; larger ones
+// are filtered without sorting, so that the scan stops once the sample is
+// full.
+func randomStrategy(size int, rows int64) (string, float64) {
+	if rows <= smallTableRows {
+		return strategyOrderByRand, 0
+	}
+	return strategyRandFilter, min(float64(size*sampleOversampling)/float64(rows), 1)
+}
+
+// SampleStatement returns the statement sampling size rows of the table
+// quoted with strategy, keeping rows with probability for strategyRandFilter,
+// and its arguments.
+func SampleStatement(quoted, strategy string, probability float64, size int) (string, []any) {
+	switch strategy {
+	case strategyOrderByRand:
+		return "SELECT * FROM " + quoted + " ORDER BY RAND() LIMIT ?", []any{size}
+	case strategyRandFilter:
+		return "SELECT * FROM " + quoted + " WHERE RAND() < ? LIMIT ?", []any{probability, size}
+	default:
+		return "SELECT * FROM " + quoted + " LIMIT ?", []any{size}
+	}
+}
+
+// quoteIdentifier returns s quoted with backticks.
+func quoteIdentifier(s string) string {
+	return "`" + strings.ReplaceAll(s, "`", "``") + "`"
+}
+
+// isOrdered returns whether a column of databaseType is numeric or holds
+// timestamps or dates, whose minimum and maximum are summarized.
+func isOrdered(databaseType string) bool {
+	switch strings.TrimPrefix(databaseType, "UNSIGNED ") {
+	case "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "BIGINT", "DECIMAL", "FLOAT", "DOUBLE",
+		"DATE", "DATETIME", "TIMESTAMP":
+		return true
+	default:
+		return false
+	}
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.allParams, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return false
+}
@@ -0,0 +1,103 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlsampletable
+
+import (
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+func TestParseFromYamlMySQLSampleTable(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		example_tool:
+			kind: mysql-sample-table
+			source: my-mysql-instance
+			description: some description
+			authRequired:
+				- my-google-auth-service
+	`
+	want := server.ToolConfigs{
+		"example_tool": Config{
+			Name:         "example_tool",
+			Kind:         "mysql-sample-table",
+			Source:       "my-mysql-instance",
+			Description:  "some description",
+			AuthRequired: []string{"my-google-auth-service"},
+		},
+	}
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	if diff := cmp.Diff(want, got.Tools); diff != "" {
+		t.Fatalf("incorrect parse: diff %v", diff)
+	}
+}
+
+func TestSampleStatement(t *testing.T) {
+	tcs := []struct {
+		desc     string
+		size     int
+		rows     int64
+		want     string
+		wantArgs []any
+	}{
+		{desc: "small table", size: 10, rows: 5000, want: "SELECT * FROM `db`.`orders` ORDER BY RAND() LIMIT ?", wantArgs: []any{10}},
+		{desc: "unknown rows", size: 10, rows: -1, want: "SELECT * FROM `db`.`orders` ORDER BY RAND() LIMIT ?", wantArgs: []any{10}},
+		{desc: "large table", size: 10, rows: 1e6, want: "SELECT * FROM `db`.`orders` WHERE RAND() < ? LIMIT ?", wantArgs: []any{3e-05, 10}},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			strategy, probability := randomStrategy(tc.size, tc.rows)
+			got, args := SampleStatement(quoteIdentifier("db")+"."+quoteIdentifier("orders"), strategy, probability, tc.size)
+			if got != tc.want {
+				t.Fatalf("unexpected statement: got %q, want %q", got, tc.want)
+			}
+			if diff := cmp.Diff(tc.wantArgs, args); diff != "" {
+				t.Fatalf("unexpected arguments (-want +got):\n%s", diff)
+			}
+		})
+	}
+	if got, _ := SampleStatement("`orders`", strategyLimit, 0, 10); got != "SELECT * FROM `orders` LIMIT ?" {
+		t.Fatalf("unexpected statement: got %q", got)
+	}
+}
+
+func TestIsOrdered(t *testing.T) {
+	for typ, want := range map[string]bool{
+		"INT":          true,
+		"UNSIGNED INT": true,
+		"DECIMAL":      true,
+		"DATETIME":     true,
+		"VARCHAR":      false,
+		"JSON":         false,
+		"YEAR":         false,
+	} {
+		if got := isOrdered(typ); got != want {
+			t.Errorf("isOrdered(%q): got %t, want %t", typ, got, want)
+		}
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/sources/mysql"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/tools/mysql/mysqlcommon"
+	"github.com/googleapis/genai-toolbox/internal/tools/sqlsyntax"
 )
 
 const kind string = "mysql-sql"
@@ -34,6 +35,7 @@ func init() {
 	if !tools.Register(kind, newConfig) {
 		panic(fmt.Sprintf("tool kind %q already registered", kind))
 	}
+	tools.RegisterOutputSchema(kind, tools.RowsOutputSchema)
 }
 
 func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
@@ -56,14 +58,17 @@ var _ compatibleSource = &mindsdb.Source{}
 var compatibleSources = [...]string{cloudsqlmysql.SourceKind, mysql.SourceKind, mindsdb.SourceKind}
 
 type Config struct {
-	Name               string           `yaml:"name" validate:"required"`
-	Kind               string           `yaml:"kind" validate:"required"`
-	Source             string           `yaml:"source" validate:"required"`
-	Description        string           `yaml:"description" validate:"required"`
-	Statement          string           `yaml:"statement" validate:"required"`
-	AuthRequired       []string         `yaml:"authRequired"`
-	Parameters         tools.Parameters `yaml:"parameters"`
-	TemplateParameters tools.Parameters `yaml:"templateParameters"`
+	Name               string            `yaml:"name" validate:"required"`
+	Kind               string            `yaml:"kind" validate:"required"`
+	Source             string            `yaml:"source" validate:"required"`
+	Description        string            `yaml:"description" validate:"required"`
+	Statement          string            `yaml:"statement" validate:"required"`
+	AuthRequired       []string          `yaml:"authRequired"`
+	Parameters         tools.Parameters  `yaml:"parameters"`
+	TemplateParameters tools.Parameters  `yaml:"templateParameters"`
+	ColumnTypes        tools.ColumnTypes `yaml:"columnTypes"`
+	LenientCoercion    bool              `yaml:"lenientCoercion"`
+	ValidateSyntax     bool              `yaml:"validateSyntax"`
 }
 
 // validate interface
@@ -73,6 +78,19 @@ func (cfg Config) ToolConfigKind() string {
 	return kind
 }
 
+// validate interface
+var _ tools.ConfigWarner = Config{}
+
+// ConfigWarnings warns when the placeholders of the statement do not match
+// the declared parameters. The driver only reports it when the tool is
+// invoked.
+func (cfg Config) ConfigWarnings() []string {
+	if err := tools.QuestionPlaceholders(cfg.Statement).CheckCount(len(cfg.Parameters)); err != nil {
+		return []string{err.Error()}
+	}
+	return nil
+}
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	// verify source exists
 	rawS, ok := srcs[cfg.Source]
@@ -102,7 +120,10 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		AllParams:          allParameters,
 		Statement:          cfg.Statement,
 		AuthRequired:       cfg.AuthRequired,
+		ValidateSyntax:     cfg.ValidateSyntax,
 		Pool:               s.MySQLPool(),
+		ColumnTypes:        cfg.ColumnTypes,
+		LenientCoercion:    cfg.LenientCoercion,
 		manifest:           tools.Manifest{Description: cfg.Description, Parameters: paramManifest, AuthRequired: cfg.AuthRequired},
 		mcpManifest:        mcpManifest,
 	}
@@ -111,14 +132,18 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 // validate interface
 var _ tools.Tool = Tool{}
+var _ tools.Renderer = Tool{}
 
 type Tool struct {
-	Name               string           `yaml:"name"`
-	Kind               string           `yaml:"kind"`
-	AuthRequired       []string         `yaml:"authRequired"`
-	Parameters         tools.Parameters `yaml:"parameters"`
-	TemplateParameters tools.Parameters `yaml:"templateParameters"`
-	AllParams          tools.Parameters `yaml:"allParams"`
+	Name               string            `yaml:"name"`
+	Kind               string            `yaml:"kind"`
+	AuthRequired       []string          `yaml:"authRequired"`
+	Parameters         tools.Parameters  `yaml:"parameters"`
+	TemplateParameters tools.Parameters  `yaml:"templateParameters"`
+	AllParams          tools.Parameters  `yaml:"allParams"`
+	ColumnTypes        tools.ColumnTypes `yaml:"columnTypes"`
+	LenientCoercion    bool              `yaml:"lenientCoercion"`
+	ValidateSyntax     bool              `yaml:"validateSyntax"`
 
 	Pool        *sql.DB
 	Statement   string
@@ -126,13 +151,19 @@ type Tool struct {
 	mcpManifest tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	paramsMap := params.AsMap()
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
 	newStatement, err := tools.ResolveTemplateParams(t.TemplateParameters, t.Statement, paramsMap)
 	if err != nil {
 		return nil, fmt.Errorf("unable to extract template params %w", err)
 	}
 
+	if t.ValidateSyntax {
+		if err := sqlsyntax.Check(ctx, sqlsyntax.MySQL, newStatement); err != nil {
+			return nil, err
+		}
+	}
+
 	newParams, err := tools.GetParams(t.Parameters, paramsMap)
 	if err != nil {
 		return nil, fmt.Errorf("unable to extract standard params %w", err)
@@ -176,11 +207,14 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 				continue
 			}
 
-			vMap[name], err = mysqlcommon.ConvertToType(colTypes[i], val)
+			vMap[name], err = mysqlcommon.ConvertToType(ctx, colTypes[i], val)
 			if err != nil {
 				return nil, fmt.Errorf("errors encountered when converting values: %w", err)
 			}
 		}
+		if err := t.ColumnTypes.CoerceRow(vMap, t.LenientCoercion); err != nil {
+			return nil, err
+		}
 		out = append(out, vMap)
 	}
 
@@ -191,6 +225,11 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	return out, nil
 }
 
+// Render resolves the statement of an invocation without running it.
+func (t Tool) Render(params tools.ParamValues) (tools.RenderedStatement, error) {
+	return tools.RenderStatement(t.Statement, t.TemplateParameters, t.Parameters, params)
+}
+
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
 	return tools.ParseParams(t.AllParams, data, claims)
 }
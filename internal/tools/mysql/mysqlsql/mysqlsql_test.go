@@ -74,6 +74,29 @@ func TestParseFromYamlMySQL(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with validateSyntax",
+			in: `
+			tools:
+				example_tool:
+					kind: mysql-sql
+					source: my-instance
+					description: some description
+					statement: SELECT * FROM hotels WHERE id = 1
+					validateSyntax: true
+			`,
+			want: server.ToolConfigs{
+				"example_tool": mysqlsql.Config{
+					Name:           "example_tool",
+					Kind:           "mysql-sql",
+					Source:         "my-instance",
+					Description:    "some description",
+					Statement:      "SELECT * FROM hotels WHERE id = 1",
+					AuthRequired:   []string{},
+					ValidateSyntax: true,
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
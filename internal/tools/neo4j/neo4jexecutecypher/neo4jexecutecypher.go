@@ -126,8 +126,8 @@ type Tool struct {
 	mcpManifest  tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	paramsMap := params.AsMap()
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
 	cypherStr, ok := paramsMap["cypher"].(string)
 	if !ok {
 		return nil, fmt.Errorf("unable to cast cypher parameter %s", paramsMap["cypher"])
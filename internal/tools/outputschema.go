@@ -0,0 +1,236 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+)
+
+// jsonSchemaTypes are the types of JSON Schema.
+var jsonSchemaTypes = []string{"array", "boolean", "integer", "null", "number", "object", "string"}
+
+var (
+	outputSchemasMu sync.RWMutex
+	outputSchemas   = make(map[string]func() map[string]any)
+)
+
+// RegisterOutputSchema registers the output schema of the tools of a kind
+// whose results have a fixed shape. Their structured content, described by
+// the schema, is built by StructuredContent.
+func RegisterOutputSchema(kind string, schema func() map[string]any) {
+	outputSchemasMu.Lock()
+	defer outputSchemasMu.Unlock()
+	outputSchemas[kind] = schema
+}
+
+// DefaultOutputSchema returns the output schema registered for kind, or nil.
+func DefaultOutputSchema(kind string) map[string]any {
+	outputSchemasMu.RLock()
+	defer outputSchemasMu.RUnlock()
+	schema, ok := outputSchemas[kind]
+	if !ok {
+		return nil
+	}
+	return schema()
+}
+
+// RowsOutputSchema describes the structured content of tools returning rows,
+// as built by StructuredContent.
+func RowsOutputSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"rows": map[string]any{
+				"type":        "array",
+				"description": "The rows of the result, usually objects mapping column names to values.",
+				"items":       map[string]any{},
+			},
+			"rowCount": map[string]any{
+				"type":        "integer",
+				"description": "The number of rows of the result.",
+			},
+		},
+		"required": []any{"rows", "rowCount"},
+	}
+}
+
+// StructuredContent returns the structured content of a result: the result
+// itself if it is a JSON object, or else its rows and their count, as
+// described by RowsOutputSchema.
+func StructuredContent(result any) (map[string]any, error) {
+	if _, ok := result.([]any); !ok && result != nil {
+		b, err := json.Marshal(result)
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal result: %w", err)
+		}
+		var obj map[string]any
+		if err := json.Unmarshal(b, &obj); err == nil && obj != nil {
+			return obj, nil
+		}
+	}
+	rows, ok := result.([]any)
+	if !ok {
+		rows = []any{}
+		if result != nil {
+			rows = append(rows, result)
+		}
+	}
+	return map[string]any{"rows": rows, "rowCount": len(rows)}, nil
+}
+
+// ValidateOutputSchema verifies that schema is a well-formed JSON Schema
+// describing an object, as MCP requires of the output schemas of tools.
+func ValidateOutputSchema(schema map[string]any) error {
+	if err := validateJSONSchema("", schema); err != nil {
+		return err
+	}
+	if schema["type"] != "object" {
+		return fmt.Errorf("the output schema must be of type \"object\"")
+	}
+	return nil
+}
+
+// validateJSONSchema verifies the structure of the keywords of schema that
+// hold schemas or names. Other keywords are passed through as they are.
+func validateJSONSchema(path string, schema any) error {
+	// booleans are valid schemas, accepting or rejecting everything
+	if _, ok := schema.(bool); ok {
+		return nil
+	}
+	s, ok := schema.(map[string]any)
+	if !ok {
+		return fmt.Errorf("schema%s must be an object or a boolean", path)
+	}
+	for keyword, value := range s {
+		at := fmt.Sprintf("%s.%s", path, keyword)
+		switch keyword {
+		case "type":
+			if err := validateSchemaType(at, value); err != nil {
+				return err
+			}
+		case "properties", "patternProperties", "$defs", "definitions":
+			m, ok := value.(map[string]any)
+			if !ok {
+				return fmt.Errorf("schema%s must be an object of schemas", at)
+			}
+			for name, sub := range m {
+				if err := validateJSONSchema(fmt.Sprintf("%s.%s", at, name), sub); err != nil {
+					return err
+				}
+			}
+		case "items", "additionalProperties", "additionalItems", "not", "contains", "propertyNames", "if", "then", "else":
+			if err := validateJSONSchema(at, value); err != nil {
+				return err
+			}
+		case "allOf", "anyOf", "oneOf", "prefixItems":
+			list, ok := value.([]any)
+			if !ok || len(list) == 0 {
+				return fmt.Errorf("schema%s must be a non-empty list of schemas", at)
+			}
+			for i, sub := range list {
+				if err := validateJSONSchema(fmt.Sprintf("%s[%d]", at, i), sub); err != nil {
+					return err
+				}
+			}
+		case "required":
+			list, ok := value.([]any)
+			if !ok {
+				return fmt.Errorf("schema%s must be a list of strings", at)
+			}
+			for _, name := range list {
+				if _, ok := name.(string); !ok {
+					return fmt.Errorf("schema%s must be a list of strings", at)
+				}
+			}
+		case "enum":
+			if _, ok := value.([]any); !ok {
+				return fmt.Errorf("schema%s must be a list", at)
+			}
+		}
+	}
+	return nil
+}
+
+func validateSchemaType(at string, value any) error {
+	switch t := value.(type) {
+	case string:
+		if slices.Contains(jsonSchemaTypes, t) {
+			return nil
+		}
+	case []any:
+		if len(t) == 0 {
+			break
+		}
+		for _, e := range t {
+			if s, ok := e.(string); !ok || !slices.Contains(jsonSchemaTypes, s) {
+				return fmt.Errorf("schema%s has an invalid type %v, must be one of %v", at, e, jsonSchemaTypes)
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("schema%s has an invalid type %v, must be one of %v", at, value, jsonSchemaTypes)
+}
+
+// OutputSchemaConfig wraps a ToolConfig with the output schema of its tool,
+// set by the kind-agnostic `outputSchema` field or registered for its kind.
+type OutputSchemaConfig struct {
+	ToolConfig
+	Schema map[string]any
+}
+
+// validate interface
+var _ ToolConfig = OutputSchemaConfig{}
+
+func (cfg OutputSchemaConfig) Initialize(srcs map[string]sources.Source) (Tool, error) {
+	t, err := cfg.ToolConfig.Initialize(srcs)
+	if err != nil {
+		return nil, err
+	}
+	return OutputSchemaTool{Tool: t, Schema: cfg.Schema}, nil
+}
+
+// StructuredTool is implemented by tools with an output schema. Their results
+// are also returned as structured content to the MCP clients supporting it.
+type StructuredTool interface {
+	StructuredContent(result any) (map[string]any, error)
+}
+
+// OutputSchemaTool adds an output schema to the MCP manifest of a Tool.
+type OutputSchemaTool struct {
+	Tool
+	Schema map[string]any
+}
+
+// validate interface
+var _ StructuredTool = OutputSchemaTool{}
+
+func (t OutputSchemaTool) unwrap() Tool {
+	return t.Tool
+}
+
+func (t OutputSchemaTool) McpManifest() McpManifest {
+	m := t.Tool.McpManifest()
+	m.OutputSchema = t.Schema
+	return m
+}
+
+func (t OutputSchemaTool) StructuredContent(result any) (map[string]any, error) {
+	return StructuredContent(result)
+}
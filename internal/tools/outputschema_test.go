@@ -0,0 +1,158 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestValidateOutputSchema(t *testing.T) {
+	tcs := []struct {
+		desc    string
+		schema  map[string]any
+		wantErr string
+	}{
+		{
+			desc:   "rows envelope",
+			schema: tools.RowsOutputSchema(),
+		},
+		{
+			desc: "nested schemas",
+			schema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"id":   map[string]any{"type": []any{"integer", "null"}},
+					"tags": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+					"any":  true,
+				},
+				"additionalProperties": false,
+				"required":             []any{"id"},
+			},
+		},
+		{
+			desc:    "not an object",
+			schema:  map[string]any{"type": "array"},
+			wantErr: `the output schema must be of type "object"`,
+		},
+		{
+			desc:    "invalid type",
+			schema:  map[string]any{"type": "object", "properties": map[string]any{"id": map[string]any{"type": "int"}}},
+			wantErr: "schema.properties.id.type has an invalid type int",
+		},
+		{
+			desc:    "invalid properties",
+			schema:  map[string]any{"type": "object", "properties": []any{"id"}},
+			wantErr: "schema.properties must be an object of schemas",
+		},
+		{
+			desc:    "invalid property",
+			schema:  map[string]any{"type": "object", "properties": map[string]any{"id": "integer"}},
+			wantErr: "schema.properties.id must be an object or a boolean",
+		},
+		{
+			desc:    "invalid required",
+			schema:  map[string]any{"type": "object", "required": "id"},
+			wantErr: "schema.required must be a list of strings",
+		},
+		{
+			desc:    "invalid anyOf",
+			schema:  map[string]any{"type": "object", "anyOf": []any{}},
+			wantErr: "schema.anyOf must be a non-empty list of schemas",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := tools.ValidateOutputSchema(tc.schema)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestStructuredContent(t *testing.T) {
+	type metadata struct {
+		Name     string
+		Location string
+	}
+	tcs := []struct {
+		desc   string
+		result any
+		want   map[string]any
+	}{
+		{
+			desc:   "rows",
+			result: []any{map[string]any{"id": 1}, map[string]any{"id": 2}},
+			want:   map[string]any{"rows": []any{map[string]any{"id": 1}, map[string]any{"id": 2}}, "rowCount": 2},
+		},
+		{
+			desc:   "no rows",
+			result: nil,
+			want:   map[string]any{"rows": []any{}, "rowCount": 0},
+		},
+		{
+			desc:   "scalar",
+			result: "done",
+			want:   map[string]any{"rows": []any{"done"}, "rowCount": 1},
+		},
+		{
+			desc:   "object",
+			result: &metadata{Name: "flights", Location: "US"},
+			want:   map[string]any{"Name": "flights", "Location": "US"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := tools.StructuredContent(tc.result)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected structured content: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestOutputSchemaTool(t *testing.T) {
+	tools.RegisterOutputSchema("mock-rows", tools.RowsOutputSchema)
+	if got := tools.DefaultOutputSchema("mock-rows"); !cmp.Equal(got, tools.RowsOutputSchema()) {
+		t.Fatalf("unexpected registered schema: %v", got)
+	}
+	if got := tools.DefaultOutputSchema("mock-unregistered"); got != nil {
+		t.Fatalf("unexpected schema of an unregistered kind: %v", got)
+	}
+
+	wrapped := tools.AliasTool{Tool: tools.OutputSchemaTool{Tool: mockTool{name: "rows"}, Schema: tools.RowsOutputSchema()}, Name: "old"}
+	if got := wrapped.McpManifest().OutputSchema; !cmp.Equal(got, tools.RowsOutputSchema()) {
+		t.Fatalf("unexpected output schema: %v", got)
+	}
+	if _, ok := tools.As[tools.StructuredTool](wrapped); !ok {
+		t.Fatalf("expected the wrapped tool to be a StructuredTool")
+	}
+	if _, ok := tools.As[tools.StructuredTool](mockTool{name: "rows"}); ok {
+		t.Fatalf("expected a tool without output schema not to be a StructuredTool")
+	}
+}
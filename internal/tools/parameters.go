@@ -17,6 +17,7 @@ package tools
 import (
 	"bytes"
 	"context"
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 	"reflect"
@@ -35,6 +36,8 @@ const (
 	typeBool   = "boolean"
 	typeArray  = "array"
 	typeMap    = "map"
+	typeJSON   = "json"
+	typeOneOf  = "oneOf"
 )
 
 // delimiters for string parameter escaping
@@ -52,6 +55,9 @@ type ParamValues []ParamValue
 type ParamValue struct {
 	Name  string
 	Value any
+	// Sensitive is set if the parameter is declared `sensitive`, in which
+	// case the value must not be logged. See ParamValues.Redacted.
+	Sensitive bool
 }
 
 // AsSlice returns a slice of the Param's values (in order).
@@ -125,10 +131,37 @@ func CheckParamRequired(required bool, defaultV any) bool {
 	return required && defaultV == nil
 }
 
+// paramRequired returns whether invocations must set p. Both the manifest and
+// the MCP input schema of a tool derive whether a parameter is required from
+// it, so that they agree.
+func paramRequired(p Parameter) bool {
+	return CheckParamRequired(p.GetRequired(), p.GetDefault())
+}
+
 // ParseParams is a helper function for parsing Parameters from an arbitraryJSON object.
+// All invalid parameters are reported at once, in a ParamErrors.
 func ParseParams(ps Parameters, data map[string]any, claimsMap map[string]map[string]any) (ParamValues, error) {
+	params, errs := parseParams(ps, data, claimsMap)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return params, nil
+}
+
+func parseParams(ps Parameters, data map[string]any, claimsMap map[string]map[string]any) (ParamValues, ParamErrors) {
 	params := make([]ParamValue, 0, len(ps))
+	var errs ParamErrors
 	for _, p := range ps {
+		if o, ok := p.(*OneOfParameter); ok {
+			// the parameters of a union are read from the arguments directly
+			v, oErrs := o.parse(data, claimsMap)
+			if len(oErrs) > 0 {
+				errs = append(errs, oErrs...)
+				continue
+			}
+			params = append(params, ParamValue{Name: o.Name, Value: v, Sensitive: isSensitive(o)})
+			continue
+		}
 		var v, newV any
 		var err error
 		paramAuthServices := p.GetAuthServices()
@@ -141,25 +174,143 @@ func ParseParams(ps Parameters, data map[string]any, claimsMap map[string]map[st
 				v = p.GetDefault()
 				// if the parameter is required and no value given, throw an error
 				if CheckParamRequired(p.GetRequired(), v) {
-					return nil, fmt.Errorf("parameter %q is required", name)
+					errs = append(errs, newParamError(p, fmt.Sprintf("parameter %q is required", name), "is required", nil))
+					continue
 				}
 			}
 		} else {
 			// parse authenticated parameter
 			v, err = parseFromAuthService(paramAuthServices, claimsMap)
 			if err != nil {
-				return nil, fmt.Errorf("error parsing authenticated parameter %q: %w", name, err)
+				errs = append(errs, newParamError(p, fmt.Sprintf("error parsing authenticated parameter %q: %s", name, err), err.Error(), err))
+				continue
 			}
 		}
 		if v != nil {
 			newV, err = p.Parse(v)
 			if err != nil {
-				return nil, fmt.Errorf("unable to parse value for %q: %w", name, err)
+				msg, problem := fmt.Sprintf("unable to parse value for %q: %s", name, err), err.Error()
+				if isSensitive(p) {
+					// parse errors may quote the value
+					raw := ParamValues{{Name: name, Value: v, Sensitive: true}}
+					msg, problem = raw.RedactString(msg), raw.RedactString(problem)
+				}
+				errs = append(errs, newParamError(p, msg, problem, err))
+				continue
 			}
 		}
-		params = append(params, ParamValue{Name: name, Value: newV})
+		params = append(params, ParamValue{Name: name, Value: newV, Sensitive: isSensitive(p)})
 	}
-	return params, nil
+	return params, errs
+}
+
+// ParamError describes why the value of a single parameter is invalid.
+type ParamError struct {
+	// Name is the name of the parameter.
+	Name string `json:"name"`
+	// Problem describes what is wrong with the value.
+	Problem string `json:"problem"`
+	// Expected describes the type and constraints of valid values.
+	Expected string `json:"expected"`
+
+	msg string
+	err error
+}
+
+func newParamError(p Parameter, msg, problem string, err error) *ParamError {
+	return &ParamError{Name: p.GetName(), Problem: problem, Expected: describeExpected(p), msg: msg, err: err}
+}
+
+func (e *ParamError) Error() string {
+	return e.msg
+}
+
+func (e *ParamError) Unwrap() error {
+	return e.err
+}
+
+// ParamErrors is the error returned by ParseParams, listing every invalid
+// parameter.
+type ParamErrors []*ParamError
+
+func (e ParamErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, pe := range e {
+		msgs[i] = pe.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e ParamErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, pe := range e {
+		errs[i] = pe
+	}
+	return errs
+}
+
+// describeExpected returns a short description of the values accepted by a
+// parameter, such as "integer, >= 1, <= 10".
+func describeExpected(p Parameter) string {
+	expected := []string{p.GetType()}
+	var common *CommonParameter
+	switch p := p.(type) {
+	case *StringParameter:
+		common = &p.CommonParameter
+	case *IntParameter:
+		common = &p.CommonParameter
+		if p.MinValue != nil {
+			expected = append(expected, fmt.Sprintf(">= %d", *p.MinValue))
+		}
+		if p.MaxValue != nil {
+			expected = append(expected, fmt.Sprintf("<= %d", *p.MaxValue))
+		}
+	case *FloatParameter:
+		common = &p.CommonParameter
+		if p.MinValue != nil {
+			expected = append(expected, fmt.Sprintf(">= %g", *p.MinValue))
+		}
+		if p.MaxValue != nil {
+			expected = append(expected, fmt.Sprintf("<= %g", *p.MaxValue))
+		}
+	case *BooleanParameter:
+		common = &p.CommonParameter
+	case *ArrayParameter:
+		common = &p.CommonParameter
+		if p.Items != nil {
+			expected[0] = fmt.Sprintf("array of %s", describeExpected(p.Items))
+		}
+		if p.MinItems != nil {
+			expected = append(expected, fmt.Sprintf("at least %d items", *p.MinItems))
+		}
+		if p.MaxItems != nil {
+			expected = append(expected, fmt.Sprintf("at most %d items", *p.MaxItems))
+		}
+	case *MapParameter:
+		common = &p.CommonParameter
+		if p.ValueType != "" {
+			expected[0] = fmt.Sprintf("map of %s", p.ValueType)
+		}
+	case *FileParameter:
+		common = &p.CommonParameter
+		if p.MaxSize > 0 {
+			expected = append(expected, fmt.Sprintf("at most %d bytes", p.MaxSize))
+		}
+	case *JSONParameter:
+		common = &p.CommonParameter
+		expected[0] = "any JSON value"
+	case *OneOfParameter:
+		expected[0] = fmt.Sprintf("exactly one of %s", p.describeBranches())
+	}
+	if common != nil {
+		if len(common.AllowedValues) > 0 {
+			expected = append(expected, fmt.Sprintf("one of %v", common.AllowedValues))
+		}
+		if len(common.ExcludedValues) > 0 {
+			expected = append(expected, fmt.Sprintf("not one of %v", common.ExcludedValues))
+		}
+	}
+	return strings.Join(expected, ", ")
 }
 
 // helper function to convert a string array parameter to a comma separated string
@@ -189,7 +340,7 @@ func GetParams(params Parameters, paramValuesMap map[string]any) (ParamValues, e
 		if !ok {
 			return nil, fmt.Errorf("missing parameter %s", k)
 		}
-		resultParamValues = append(resultParamValues, ParamValue{Name: k, Value: v})
+		resultParamValues = append(resultParamValues, ParamValue{Name: k, Value: v, Sensitive: isSensitive(p)})
 	}
 	return resultParamValues, nil
 }
@@ -256,6 +407,18 @@ type McpToolsSchema struct {
 	Type       string                          `json:"type"`
 	Properties map[string]ParameterMcpManifest `json:"properties"`
 	Required   []string                        `json:"required"`
+	// OneOf and AllOf express the constraints of OneOfParameters. A single
+	// union is expressed with OneOf, several with an AllOf of their OneOfs.
+	OneOf []McpSchemaConstraint `json:"oneOf,omitempty"`
+	AllOf []McpSchemaConstraint `json:"allOf,omitempty"`
+}
+
+// McpSchemaConstraint is a JSON Schema subschema constraining which
+// properties of McpToolsSchema are present.
+type McpSchemaConstraint struct {
+	Required []string              `json:"required,omitempty"`
+	AnyOf    []McpSchemaConstraint `json:"anyOf,omitempty"`
+	OneOf    []McpSchemaConstraint `json:"oneOf,omitempty"`
 }
 
 // Parameters is a type used to allow unmarshal a list of parameters
@@ -366,6 +529,17 @@ func parseParamFromDelayedUnmarshaler(ctx context.Context, u *util.DelayedUnmars
 			a.AuthSources = nil
 		}
 		return a, nil
+	case typeJSON:
+		a := &JSONParameter{}
+		if err := dec.DecodeContext(ctx, a); err != nil {
+			return nil, fmt.Errorf("unable to parse as %q: %w", t, err)
+		}
+		if a.AuthSources != nil {
+			logger.WarnContext(ctx, "`authSources` is deprecated, use `authServices` for parameters instead")
+			a.AuthServices = append(a.AuthServices, a.AuthSources...)
+			a.AuthSources = nil
+		}
+		return a, nil
 	}
 	return nil, fmt.Errorf("%q is not valid type for a parameter", t)
 }
@@ -373,7 +547,27 @@ func parseParamFromDelayedUnmarshaler(ctx context.Context, u *util.DelayedUnmars
 func (ps Parameters) Manifest() []ParameterManifest {
 	rtn := make([]ParameterManifest, 0, len(ps))
 	for _, p := range ps {
-		rtn = append(rtn, p.Manifest())
+		if o, ok := p.(*OneOfParameter); ok {
+			// the parameters of every branch are flattened, and none of
+			// them is required on its own
+			for _, b := range o.Branches {
+				for _, bp := range b.Parameters {
+					m := bp.Manifest()
+					m.Required = false
+					m.Default = bp.GetDefault()
+					m.Sensitive = isSensitive(bp)
+					m.Examples, m.ExampleGenerated = paramExamples(bp, m.Enum)
+					rtn = append(rtn, m)
+				}
+			}
+			continue
+		}
+		m := p.Manifest()
+		m.Required = paramRequired(p)
+		m.Default = p.GetDefault()
+		m.Sensitive = isSensitive(p)
+		m.Examples, m.ExampleGenerated = paramExamples(p, m.Enum)
+		rtn = append(rtn, m)
 	}
 	return rtn
 }
@@ -382,24 +576,48 @@ func (ps Parameters) McpManifest() (McpToolsSchema, map[string][]string) {
 	properties := make(map[string]ParameterMcpManifest)
 	required := make([]string, 0)
 	authParam := make(map[string][]string)
+	var unions []McpSchemaConstraint
 
 	for _, p := range ps {
+		if o, ok := p.(*OneOfParameter); ok {
+			for _, b := range o.Branches {
+				for _, bp := range b.Parameters {
+					paramManifest, authParamList := bp.McpManifest()
+					paramManifest.Sensitive = isSensitive(bp)
+					paramManifest.Examples, paramManifest.ExampleGenerated = paramExamples(bp, paramManifest.Enum)
+					properties[bp.GetName()] = paramManifest
+					if len(authParamList) > 0 {
+						authParam[bp.GetName()] = authParamList
+					}
+				}
+			}
+			unions = append(unions, McpSchemaConstraint{OneOf: o.mcpConstraints()})
+			continue
+		}
 		name := p.GetName()
 		paramManifest, authParamList := p.McpManifest()
+		paramManifest.Sensitive = isSensitive(p)
+		paramManifest.Examples, paramManifest.ExampleGenerated = paramExamples(p, paramManifest.Enum)
 		properties[name] = paramManifest
 		// parameters that doesn't have a default value are added to the required field
-		if CheckParamRequired(p.GetRequired(), p.GetDefault()) {
+		if paramRequired(p) {
 			required = append(required, name)
 		}
 		if len(authParamList) > 0 {
 			authParam[name] = authParamList
 		}
 	}
-	return McpToolsSchema{
+	schema := McpToolsSchema{
 		Type:       "object",
 		Properties: properties,
 		Required:   required,
-	}, authParam
+	}
+	if len(unions) == 1 {
+		schema.OneOf = unions[0].OneOf
+	} else if len(unions) > 1 {
+		schema.AllOf = unions
+	}
+	return schema, authParam
 }
 
 // ParameterManifest represents parameters when served as part of a ToolManifest.
@@ -410,7 +628,20 @@ type ParameterManifest struct {
 	Description          string             `json:"description"`
 	AuthServices         []string           `json:"authSources"`
 	Items                *ParameterManifest `json:"items,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
 	AdditionalProperties any                `json:"additionalProperties,omitempty"`
+	Sensitive            bool               `json:"sensitive,omitempty"`
+	// Default is the value of the parameter when invocations do not set it,
+	// if any.
+	Default any `json:"default,omitempty"`
+	// Enum lists the values the parameter accepts, if it is limited to a few.
+	Enum []string `json:"enum,omitempty"`
+	// Examples holds an example value of the parameter, declared or
+	// generated.
+	Examples []any `json:"examples,omitempty"`
+	// ExampleGenerated is set if Examples was generated rather than declared.
+	ExampleGenerated bool `json:"-"`
 }
 
 // ParameterMcpManifest represents properties when served as part of a ToolMcpManifest.
@@ -418,7 +649,18 @@ type ParameterMcpManifest struct {
 	Type                 string                `json:"type"`
 	Description          string                `json:"description"`
 	Items                *ParameterMcpManifest `json:"items,omitempty"`
+	MinItems             *int                  `json:"minItems,omitempty"`
+	MaxItems             *int                  `json:"maxItems,omitempty"`
 	AdditionalProperties any                   `json:"additionalProperties,omitempty"`
+	// Sensitive tells clients to mask the value of the parameter.
+	Sensitive bool `json:"sensitive,omitempty"`
+	// Enum lists the values the parameter accepts, if it is limited to a few.
+	Enum []string `json:"enum,omitempty"`
+	// Examples holds an example value of the parameter, declared or
+	// generated.
+	Examples []any `json:"examples,omitempty"`
+	// ExampleGenerated is set if Examples was generated rather than declared.
+	ExampleGenerated bool `json:"-"`
 }
 
 // CommonParameter are default fields that are emebdding in most Parameter implementations. Embedding this stuct will give the object Name() and Type() functions.
@@ -431,6 +673,11 @@ type CommonParameter struct {
 	ExcludedValues []any              `yaml:"excludedValues"`
 	AuthServices   []ParamAuthService `yaml:"authServices"`
 	AuthSources    []ParamAuthService `yaml:"authSources"` // Deprecated: Kept for compatibility.
+	// Sensitive values are redacted from logs and error messages.
+	Sensitive bool `yaml:"sensitive"`
+	// Example is an example value of the parameter, shown to clients in the
+	// manifests.
+	Example any `yaml:"example"`
 }
 
 // GetName returns the name specified for the Parameter.
@@ -452,6 +699,16 @@ func (p *CommonParameter) GetRequired() bool {
 	return *p.Required
 }
 
+// IsSensitive returns whether the value of the Parameter must be redacted.
+func (p *CommonParameter) IsSensitive() bool {
+	return p.Sensitive
+}
+
+// GetExample returns the example value declared for the Parameter, if any.
+func (p *CommonParameter) GetExample() any {
+	return p.Example
+}
+
 // GetAllowedValues returns the allowed values for the Parameter.
 func (p *CommonParameter) GetAllowedValues() []any {
 	return p.AllowedValues
@@ -1167,6 +1424,21 @@ func NewArrayParameterWithDefault(name string, defaultV []any, desc string, item
 	}
 }
 
+// NewArrayParameterWithRange is a convenience function for initializing a ArrayParameter with a minimum and maximum number of items.
+func NewArrayParameterWithRange(name string, desc string, minItems *int, maxItems *int, items Parameter) *ArrayParameter {
+	return &ArrayParameter{
+		CommonParameter: CommonParameter{
+			Name:         name,
+			Type:         typeArray,
+			Desc:         desc,
+			AuthServices: nil,
+		},
+		Items:    items,
+		MinItems: minItems,
+		MaxItems: maxItems,
+	}
+}
+
 // NewArrayParameterWithRequired is a convenience function for initializing a ArrayParameter with default value.
 func NewArrayParameterWithRequired(name string, desc string, required bool, items Parameter) *ArrayParameter {
 	return &ArrayParameter{
@@ -1229,13 +1501,21 @@ type ArrayParameter struct {
 	CommonParameter `yaml:",inline"`
 	Default         *[]any    `yaml:"default"`
 	Items           Parameter `yaml:"items"`
+	MinItems        *int      `yaml:"minItems"`
+	MaxItems        *int      `yaml:"maxItems"`
 }
 
+// arrayItemTypes are the item types supported for array parameters that are
+// defined in a tools file.
+var arrayItemTypes = []string{typeString, typeInt, typeFloat, typeBool}
+
 func (p *ArrayParameter) UnmarshalYAML(ctx context.Context, unmarshal func(interface{}) error) error {
 	var rawItem struct {
 		CommonParameter `yaml:",inline"`
 		Default         *[]any                  `yaml:"default"`
 		Items           util.DelayedUnmarshaler `yaml:"items"`
+		MinItems        *int                    `yaml:"minItems"`
+		MaxItems        *int                    `yaml:"maxItems"`
 	}
 	if err := unmarshal(&rawItem); err != nil {
 		return err
@@ -1249,8 +1529,23 @@ func (p *ArrayParameter) UnmarshalYAML(ctx context.Context, unmarshal func(inter
 	if i.GetAuthServices() != nil && len(i.GetAuthServices()) != 0 {
 		return fmt.Errorf("nested items should not have auth services")
 	}
+	if !slices.Contains(arrayItemTypes, i.GetType()) {
+		return fmt.Errorf("unsupported items type %q for array parameter %q: must be one of %q", i.GetType(), p.Name, arrayItemTypes)
+	}
 	p.Items = i
 
+	if rawItem.MinItems != nil && *rawItem.MinItems < 0 {
+		return fmt.Errorf("minItems for array parameter %q must not be negative", p.Name)
+	}
+	if rawItem.MaxItems != nil && *rawItem.MaxItems < 0 {
+		return fmt.Errorf("maxItems for array parameter %q must not be negative", p.Name)
+	}
+	if rawItem.MinItems != nil && rawItem.MaxItems != nil && *rawItem.MinItems > *rawItem.MaxItems {
+		return fmt.Errorf("minItems for array parameter %q must not be greater than maxItems", p.Name)
+	}
+	p.MinItems = rawItem.MinItems
+	p.MaxItems = rawItem.MaxItems
+
 	return nil
 }
 
@@ -1291,11 +1586,17 @@ func (p *ArrayParameter) Parse(v any) (any, error) {
 	if p.IsExcludedValues(arrVal) {
 		return nil, fmt.Errorf("%s is an excluded value", arrVal)
 	}
+	if p.MinItems != nil && len(arrVal) < *p.MinItems {
+		return nil, fmt.Errorf("array has %d items, fewer than the minimum of %d", len(arrVal), *p.MinItems)
+	}
+	if p.MaxItems != nil && len(arrVal) > *p.MaxItems {
+		return nil, fmt.Errorf("array has %d items, more than the maximum of %d", len(arrVal), *p.MaxItems)
+	}
 	rtn := make([]any, 0, len(arrVal))
 	for idx, val := range arrVal {
 		val, err := p.Items.Parse(val)
 		if err != nil {
-			return nil, fmt.Errorf("unable to parse element #%d: %w", idx, err)
+			return nil, fmt.Errorf("unable to parse element at index %d: %w", idx, err)
 		}
 		rtn = append(rtn, val)
 	}
@@ -1332,6 +1633,8 @@ func (p *ArrayParameter) Manifest() ParameterManifest {
 		Description:  p.Desc,
 		AuthServices: authServiceNames,
 		Items:        &items,
+		MinItems:     p.MinItems,
+		MaxItems:     p.MaxItems,
 	}
 }
 
@@ -1344,6 +1647,8 @@ func (p *ArrayParameter) McpManifest() (ParameterMcpManifest, []string) {
 		Type:        p.Type,
 		Description: p.Desc,
 		Items:       &items,
+		MinItems:    p.MinItems,
+		MaxItems:    p.MaxItems,
 	}, authServiceNames
 }
 
@@ -1610,3 +1915,351 @@ func (p *MapParameter) McpManifest() (ParameterMcpManifest, []string) {
 		AdditionalProperties: additionalProperties,
 	}, authServiceNames
 }
+
+// JSONParameter is a parameter representing any JSON value: an object, an
+// array or a scalar. Its values are parsed into a JSONValue, which is bound
+// to SQL statements as JSON text.
+type JSONParameter struct {
+	CommonParameter `yaml:",inline"`
+	Default         *any `yaml:"default"`
+}
+
+// Ensure JSONParameter implements the Parameter interface.
+var _ Parameter = &JSONParameter{}
+
+// NewJSONParameter is a convenience function for initializing a JSONParameter.
+func NewJSONParameter(name string, desc string) *JSONParameter {
+	return &JSONParameter{
+		CommonParameter: CommonParameter{
+			Name: name,
+			Type: typeJSON,
+			Desc: desc,
+		},
+	}
+}
+
+// NewJSONParameterWithDefault is a convenience function for initializing a JSONParameter with a default value.
+func NewJSONParameterWithDefault(name string, defaultV any, desc string) *JSONParameter {
+	return &JSONParameter{
+		CommonParameter: CommonParameter{
+			Name: name,
+			Type: typeJSON,
+			Desc: desc,
+		},
+		Default: &defaultV,
+	}
+}
+
+// NewJSONParameterWithRequired is a convenience function for initializing a JSONParameter as required.
+func NewJSONParameterWithRequired(name string, desc string, required bool) *JSONParameter {
+	return &JSONParameter{
+		CommonParameter: CommonParameter{
+			Name:     name,
+			Type:     typeJSON,
+			Desc:     desc,
+			Required: &required,
+		},
+	}
+}
+
+// NewJSONParameterWithAuth is a convenience function for initializing a JSONParameter with auth services.
+func NewJSONParameterWithAuth(name string, desc string, authServices []ParamAuthService) *JSONParameter {
+	return &JSONParameter{
+		CommonParameter: CommonParameter{
+			Name:         name,
+			Type:         typeJSON,
+			Desc:         desc,
+			AuthServices: authServices,
+		},
+	}
+}
+
+// isJSONValueIn reports whether v is equal to the JSON encoding of any of the
+// values in vs.
+func isJSONValueIn(v JSONValue, vs []any) bool {
+	for _, av := range vs {
+		b, err := json.Marshal(av)
+		if err != nil {
+			continue
+		}
+		if ajv, err := NewJSONValue(json.RawMessage(b)); err == nil && bytes.Equal(v.raw, ajv.raw) {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse validates and parses an incoming value for the json parameter.
+func (p *JSONParameter) Parse(v any) (any, error) {
+	jv, err := NewJSONValue(v)
+	if err != nil {
+		return nil, &ParseTypeError{p.Name, p.Type, v}
+	}
+	if len(p.AllowedValues) > 0 && !isJSONValueIn(jv, p.AllowedValues) {
+		return nil, fmt.Errorf("%s is not an allowed value", jv)
+	}
+	if isJSONValueIn(jv, p.ExcludedValues) {
+		return nil, fmt.Errorf("%s is an excluded value", jv)
+	}
+	return jv, nil
+}
+
+func (p *JSONParameter) GetAuthServices() []ParamAuthService {
+	return p.AuthServices
+}
+
+func (p *JSONParameter) GetDefault() any {
+	if p.Default == nil {
+		return nil
+	}
+	return *p.Default
+}
+
+// jsonDescription notes in the description of a JSONParameter that values
+// are sent as JSON rather than as encoded strings.
+func (p *JSONParameter) jsonDescription() string {
+	note := "(any JSON value, not a string containing JSON)"
+	if p.Desc == "" {
+		return note
+	}
+	return p.Desc + " " + note
+}
+
+// Manifest returns the manifest for the JSONParameter.
+func (p *JSONParameter) Manifest() ParameterManifest {
+	authServiceNames := getAuthServiceNames(p.AuthServices)
+	r := CheckParamRequired(p.GetRequired(), p.GetDefault())
+	return ParameterManifest{
+		Name:                 p.Name,
+		Type:                 "object",
+		Required:             r,
+		Description:          p.jsonDescription(),
+		AuthServices:         authServiceNames,
+		AdditionalProperties: true,
+	}
+}
+
+// McpManifest returns the MCP manifest for the JSONParameter.
+func (p *JSONParameter) McpManifest() (ParameterMcpManifest, []string) {
+	authServiceNames := getAuthServiceNames(p.AuthServices)
+	return ParameterMcpManifest{
+		Type:                 "object",
+		Description:          p.jsonDescription(),
+		AdditionalProperties: true,
+	}, authServiceNames
+}
+
+// JSONValue is the parsed value of a JSONParameter. It holds compact JSON text
+// and implements driver.Valuer, so drivers bind it as a JSON string: pgx
+// encodes it into json and jsonb columns, and the MySQL family of drivers
+// send it as a string that the server casts to JSON.
+type JSONValue struct {
+	raw json.RawMessage
+}
+
+// NewJSONValue returns the JSONValue of v. A json.RawMessage is validated and
+// used as is, any other value is encoded as JSON.
+func NewJSONValue(v any) (JSONValue, error) {
+	b, ok := v.(json.RawMessage)
+	if !ok {
+		var err error
+		if b, err = json.Marshal(v); err != nil {
+			return JSONValue{}, fmt.Errorf("unable to encode value as JSON: %w", err)
+		}
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, b); err != nil {
+		return JSONValue{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return JSONValue{raw: buf.Bytes()}, nil
+}
+
+// String returns the JSON text of the value.
+func (v JSONValue) String() string {
+	return string(v.raw)
+}
+
+// MarshalJSON returns the JSON text of the value.
+func (v JSONValue) MarshalJSON() ([]byte, error) {
+	return v.raw, nil
+}
+
+// Value implements driver.Valuer.
+func (v JSONValue) Value() (driver.Value, error) {
+	return string(v.raw), nil
+}
+
+// OneOfBranch is one of the alternative sets of parameters of a
+// OneOfParameter.
+type OneOfBranch struct {
+	Name       string
+	Parameters Parameters
+}
+
+// OneOfValue is the value of a OneOfParameter. Branch is the name of the
+// branch that was supplied, and Params holds the values of its parameters.
+type OneOfValue struct {
+	Branch string
+	Params ParamValues
+}
+
+// OneOfParameter groups alternative sets of parameters, of which exactly one
+// must be supplied. The parameters of every branch are flattened into the
+// arguments of the tool, and a branch is supplied when any of its parameters
+// is. The parameter parses to a OneOfValue.
+type OneOfParameter struct {
+	Name     string
+	Desc     string
+	Branches []OneOfBranch
+}
+
+// Ensure OneOfParameter implements the Parameter interface.
+var _ Parameter = &OneOfParameter{}
+
+// NewOneOfParameter is a convenience function for initializing a OneOfParameter.
+func NewOneOfParameter(name string, desc string, branches ...OneOfBranch) *OneOfParameter {
+	return &OneOfParameter{
+		Name:     name,
+		Desc:     desc,
+		Branches: branches,
+	}
+}
+
+func (p *OneOfParameter) GetName() string {
+	return p.Name
+}
+
+func (p *OneOfParameter) GetType() string {
+	return typeOneOf
+}
+
+func (p *OneOfParameter) GetDefault() any {
+	return nil
+}
+
+func (p *OneOfParameter) GetRequired() bool {
+	return true
+}
+
+func (p *OneOfParameter) GetAuthServices() []ParamAuthService {
+	return nil
+}
+
+// IsSensitive returns whether any parameter of the branches is sensitive, in
+// which case the whole value is redacted.
+func (p *OneOfParameter) IsSensitive() bool {
+	for _, b := range p.Branches {
+		if slices.ContainsFunc(b.Parameters, isSensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse parses the arguments of a tool, given as a map, into a OneOfValue.
+func (p *OneOfParameter) Parse(v any) (any, error) {
+	data, ok := v.(map[string]any)
+	if !ok {
+		return nil, &ParseTypeError{p.Name, typeMap, v}
+	}
+	value, errs := p.parse(data, nil)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return value, nil
+}
+
+// parse finds the branch supplied in the arguments of a tool and parses its
+// parameters.
+func (p *OneOfParameter) parse(data map[string]any, claimsMap map[string]map[string]any) (OneOfValue, ParamErrors) {
+	var supplied []OneOfBranch
+	for _, b := range p.Branches {
+		for _, bp := range b.Parameters {
+			if _, ok := data[bp.GetName()]; ok {
+				supplied = append(supplied, b)
+				break
+			}
+		}
+	}
+	if len(supplied) != 1 {
+		problem := "none was provided"
+		if len(supplied) > 1 {
+			names := make([]string, len(supplied))
+			for i, b := range supplied {
+				names[i] = describeBranch(b)
+			}
+			problem = fmt.Sprintf("%s were all provided", strings.Join(names, " and "))
+		}
+		msg := fmt.Sprintf("exactly one of %s must be provided, but %s", p.describeBranches(), problem)
+		return OneOfValue{}, ParamErrors{newParamError(p, msg, problem, nil)}
+	}
+	params, errs := parseParams(supplied[0].Parameters, data, claimsMap)
+	if len(errs) > 0 {
+		return OneOfValue{}, errs
+	}
+	return OneOfValue{Branch: supplied[0].Name, Params: params}, nil
+}
+
+// describeBranches lists the alternatives of the union, such as
+// "(entry) or (name, entryGroup)".
+func (p *OneOfParameter) describeBranches() string {
+	names := make([]string, len(p.Branches))
+	for i, b := range p.Branches {
+		names[i] = describeBranch(b)
+	}
+	return strings.Join(names, " or ")
+}
+
+func describeBranch(b OneOfBranch) string {
+	names := make([]string, len(b.Parameters))
+	for i, bp := range b.Parameters {
+		names[i] = bp.GetName()
+	}
+	return "(" + strings.Join(names, ", ") + ")"
+}
+
+// mcpConstraints returns a subschema for each branch, matching the arguments
+// in which the branch is supplied.
+func (p *OneOfParameter) mcpConstraints() []McpSchemaConstraint {
+	constraints := make([]McpSchemaConstraint, 0, len(p.Branches))
+	for _, b := range p.Branches {
+		var required []string
+		for _, bp := range b.Parameters {
+			if paramRequired(bp) {
+				required = append(required, bp.GetName())
+			}
+		}
+		if len(required) > 0 {
+			constraints = append(constraints, McpSchemaConstraint{Required: required})
+			continue
+		}
+		// without a required parameter, any parameter supplies the branch
+		anyOf := make([]McpSchemaConstraint, 0, len(b.Parameters))
+		for _, bp := range b.Parameters {
+			anyOf = append(anyOf, McpSchemaConstraint{Required: []string{bp.GetName()}})
+		}
+		constraints = append(constraints, McpSchemaConstraint{AnyOf: anyOf})
+	}
+	return constraints
+}
+
+// Manifest returns the manifest for the OneOfParameter. The manifest of
+// Parameters lists the parameters of its branches instead.
+func (p *OneOfParameter) Manifest() ParameterManifest {
+	return ParameterManifest{
+		Name:         p.Name,
+		Type:         typeOneOf,
+		Required:     true,
+		Description:  p.Desc,
+		AuthServices: []string{},
+	}
+}
+
+// McpManifest returns the MCP manifest for the OneOfParameter. The MCP
+// manifest of Parameters lists the parameters of its branches instead.
+func (p *OneOfParameter) McpManifest() (ParameterMcpManifest, []string) {
+	return ParameterMcpManifest{
+		Type:        "object",
+		Description: p.Desc,
+	}, nil
+}
@@ -16,7 +16,9 @@ package tools_test
 
 import (
 	"bytes"
+	"database/sql/driver"
 	"encoding/json"
+	"errors"
 	"math"
 	"reflect"
 	"slices"
@@ -27,6 +29,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 func TestParametersMarshal(t *testing.T) {
@@ -34,6 +37,7 @@ func TestParametersMarshal(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error: %s", err)
 	}
+	minItems, maxItems := 1, 3
 	tcs := []struct {
 		name string
 		in   []map[string]any
@@ -202,6 +206,26 @@ func TestParametersMarshal(t *testing.T) {
 				tools.NewArrayParameter("my_array", "this param is an array of floats", tools.NewFloatParameter("my_float", "float item")),
 			},
 		},
+		{
+			name: "int array with range",
+			in: []map[string]any{
+				{
+					"name":        "my_array",
+					"type":        "array",
+					"description": "this param is an array of ints",
+					"minItems":    1,
+					"maxItems":    3,
+					"items": map[string]string{
+						"name":        "my_int",
+						"type":        "integer",
+						"description": "int item",
+					},
+				},
+			},
+			want: tools.Parameters{
+				tools.NewArrayParameterWithRange("my_array", "this param is an array of ints", &minItems, &maxItems, tools.NewIntParameter("my_int", "int item")),
+			},
+		},
 		{
 			name: "string default",
 			in: []map[string]any{
@@ -353,6 +377,33 @@ func TestParametersMarshal(t *testing.T) {
 				tools.NewMapParameter("my_generic_map", "this param is a generic map", ""),
 			},
 		},
+		{
+			name: "json",
+			in: []map[string]any{
+				{
+					"name":        "payload",
+					"type":        "json",
+					"description": "this param is any json value",
+				},
+			},
+			want: tools.Parameters{
+				tools.NewJSONParameter("payload", "this param is any json value"),
+			},
+		},
+		{
+			name: "json with default",
+			in: []map[string]any{
+				{
+					"name":        "payload",
+					"type":        "json",
+					"description": "this param is any json value",
+					"default":     []any{"a", "b"},
+				},
+			},
+			want: tools.Parameters{
+				tools.NewJSONParameterWithDefault("payload", []any{"a", "b"}, "this param is any json value"),
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
@@ -695,6 +746,7 @@ func TestAuthParametersMarshal(t *testing.T) {
 func TestParametersParse(t *testing.T) {
 	intValue := 2
 	floatValue := 1.5
+	minItems := 1
 	tcs := []struct {
 		name   string
 		params tools.Parameters
@@ -1243,6 +1295,82 @@ func TestParametersParse(t *testing.T) {
 			},
 			want: tools.ParamValues{tools.ParamValue{Name: "my_array", Value: []any{string("`val1`"), string("`val2`")}}},
 		},
+		{
+			name: "array of int",
+			params: tools.Parameters{
+				tools.NewArrayParameter("my_array", "an array", tools.NewIntParameter("my_int", "int item")),
+			},
+			in: map[string]any{
+				"my_array": []any{1, 2},
+			},
+			want: tools.ParamValues{tools.ParamValue{Name: "my_array", Value: []any{1, 2}}},
+		},
+		{
+			name: "array of float",
+			params: tools.Parameters{
+				tools.NewArrayParameter("my_array", "an array", tools.NewFloatParameter("my_float", "float item")),
+			},
+			in: map[string]any{
+				"my_array": []any{1.5, 2},
+			},
+			want: tools.ParamValues{tools.ParamValue{Name: "my_array", Value: []any{1.5, 2.0}}},
+		},
+		{
+			name: "array of bool",
+			params: tools.Parameters{
+				tools.NewArrayParameter("my_array", "an array", tools.NewBooleanParameter("my_bool", "bool item")),
+			},
+			in: map[string]any{
+				"my_array": []any{true, false},
+			},
+			want: tools.ParamValues{tools.ParamValue{Name: "my_array", Value: []any{true, false}}},
+		},
+		{
+			name: "array of int with mixed types",
+			params: tools.Parameters{
+				tools.NewArrayParameter("my_array", "an array", tools.NewIntParameter("my_int", "int item")),
+			},
+			in: map[string]any{
+				"my_array": []any{1, "two"},
+			},
+		},
+		{
+			name: "array of string with mixed types",
+			params: tools.Parameters{
+				tools.NewArrayParameter("my_array", "an array", tools.NewStringParameter("my_string", "string item")),
+			},
+			in: map[string]any{
+				"my_array": []any{"one", true},
+			},
+		},
+		{
+			name: "array within bounds",
+			params: tools.Parameters{
+				tools.NewArrayParameterWithRange("my_array", "an array", &minItems, &intValue, tools.NewStringParameter("my_string", "string item")),
+			},
+			in: map[string]any{
+				"my_array": []any{"one", "two"},
+			},
+			want: tools.ParamValues{tools.ParamValue{Name: "my_array", Value: []any{"one", "two"}}},
+		},
+		{
+			name: "array fewer than minItems",
+			params: tools.Parameters{
+				tools.NewArrayParameterWithRange("my_array", "an array", &minItems, nil, tools.NewStringParameter("my_string", "string item")),
+			},
+			in: map[string]any{
+				"my_array": []any{},
+			},
+		},
+		{
+			name: "array more than maxItems",
+			params: tools.Parameters{
+				tools.NewArrayParameterWithRange("my_array", "an array", nil, &intValue, tools.NewStringParameter("my_string", "string item")),
+			},
+			in: map[string]any{
+				"my_array": []any{"one", "two", "three"},
+			},
+		},
 		{
 			name: "map",
 			params: tools.Parameters{
@@ -1364,6 +1492,321 @@ func TestParametersParse(t *testing.T) {
 	}
 }
 
+func TestParseJSONParams(t *testing.T) {
+	tcs := []struct {
+		name   string
+		params tools.Parameters
+		in     string
+		want   string
+		err    string
+	}{
+		{
+			name:   "object",
+			params: tools.Parameters{tools.NewJSONParameter("payload", "a json value")},
+			in:     `{"payload": {"tags": ["a", "b"], "size": 12.50, "id": 9007199254740993, "note": "it's"}}`,
+			want:   `{"id":9007199254740993,"note":"it's","size":12.50,"tags":["a","b"]}`,
+		},
+		{
+			name:   "array",
+			params: tools.Parameters{tools.NewJSONParameter("payload", "a json value")},
+			in:     `{"payload": [1, {"a": null}]}`,
+			want:   `[1,{"a":null}]`,
+		},
+		{
+			name:   "scalar",
+			params: tools.Parameters{tools.NewJSONParameter("payload", "a json value")},
+			in:     `{"payload": "{\"a\": 1}"}`,
+			want:   `"{\"a\": 1}"`,
+		},
+		{
+			name:   "default",
+			params: tools.Parameters{tools.NewJSONParameterWithDefault("payload", map[string]any{"limit": uint64(10)}, "a json value")},
+			in:     `{}`,
+			want:   `{"limit":10}`,
+		},
+		{
+			name:   "required",
+			params: tools.Parameters{tools.NewJSONParameter("payload", "a json value")},
+			in:     `{}`,
+			err:    `parameter "payload" is required`,
+		},
+		{
+			name: "allowed",
+			params: tools.Parameters{&tools.JSONParameter{CommonParameter: tools.CommonParameter{
+				Name: "payload", Type: "json", Desc: "a json value", AllowedValues: []any{map[string]any{"mode": "fast"}},
+			}}},
+			in:   `{"payload": {"mode": "fast"}}`,
+			want: `{"mode":"fast"}`,
+		},
+		{
+			name: "not allowed",
+			params: tools.Parameters{&tools.JSONParameter{CommonParameter: tools.CommonParameter{
+				Name: "payload", Type: "json", Desc: "a json value", AllowedValues: []any{map[string]any{"mode": "fast"}},
+			}}},
+			in:  `{"payload": {"mode": "slow"}}`,
+			err: `{"mode":"slow"} is not an allowed value`,
+		},
+		{
+			name: "excluded",
+			params: tools.Parameters{&tools.JSONParameter{CommonParameter: tools.CommonParameter{
+				Name: "payload", Type: "json", Desc: "a json value", ExcludedValues: []any{[]any{}},
+			}}},
+			in:  `{"payload": []}`,
+			err: `[] is an excluded value`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var m map[string]any
+			d := json.NewDecoder(strings.NewReader(tc.in))
+			d.UseNumber()
+			if err := d.Decode(&m); err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			got, err := tools.ParseParams(tc.params, m, make(map[string]map[string]any))
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("unexpected error: got %v, want substring %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error from ParseParams: %s", err)
+			}
+			jv, ok := got[0].Value.(tools.JSONValue)
+			if !ok {
+				t.Fatalf("unexpected value type: got %T", got[0].Value)
+			}
+			if jv.String() != tc.want {
+				t.Fatalf("unexpected value: got %s, want %s", jv, tc.want)
+			}
+		})
+	}
+}
+
+func TestJSONValueBinding(t *testing.T) {
+	jv, err := tools.NewJSONValue(map[string]any{"tags": []any{"a", "b"}, "note": `it's "quoted"`})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := `{"note":"it's \"quoted\"","tags":["a","b"]}`
+
+	// drivers of the MySQL family bind the value as a JSON string
+	v, err := driver.DefaultParameterConverter.ConvertValue(jv)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if v != want {
+		t.Fatalf("unexpected driver value: got %#v, want %q", v, want)
+	}
+
+	// pgx encodes the value as jsonb, whose binary format is a version byte
+	// followed by the JSON text
+	m := pgtype.NewMap()
+	b, err := m.Encode(pgtype.JSONBOID, pgtype.BinaryFormatCode, jv, nil)
+	if err != nil {
+		t.Fatalf("unable to encode jsonb: %s", err)
+	}
+	if string(b) != "\x01"+want {
+		t.Fatalf("unexpected jsonb encoding: got %q", b)
+	}
+	b, err = m.Encode(pgtype.JSONOID, pgtype.TextFormatCode, jv, nil)
+	if err != nil {
+		t.Fatalf("unable to encode json: %s", err)
+	}
+	if string(b) != want {
+		t.Fatalf("unexpected json encoding: got %q", b)
+	}
+
+	// results embed the value as JSON rather than as a string
+	b, err = json.Marshal(map[string]any{"payload": jv})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(b) != `{"payload":`+want+`}` {
+		t.Fatalf("unexpected marshaled value: got %s", b)
+	}
+
+	if _, err := tools.NewJSONValue(json.RawMessage(`{"a":`)); err == nil {
+		t.Fatalf("expected an error for malformed JSON")
+	}
+}
+
+func TestParseArrayElementError(t *testing.T) {
+	params := tools.Parameters{
+		tools.NewArrayParameter("my_array", "an array", tools.NewIntParameter("my_int", "int item")),
+	}
+	in := map[string]any{"my_array": []any{1, 2, "three"}}
+	_, err := tools.ParseParams(params, in, make(map[string]map[string]any))
+	if err == nil {
+		t.Fatalf("expected error but Param parsed successfully")
+	}
+	want := "unable to parse element at index 2"
+	if !strings.Contains(err.Error(), want) {
+		t.Fatalf("unexpected error: got %q, want to contain %q", err, want)
+	}
+	var paramErrs tools.ParamErrors
+	if !errors.As(err, &paramErrs) || len(paramErrs) != 1 {
+		t.Fatalf("expected a single parameter error, got %#v", err)
+	}
+	if got := paramErrs[0].Expected; got != "array of integer" {
+		t.Fatalf("unexpected expected value: got %q, want %q", got, "array of integer")
+	}
+}
+
+func TestParseParamsReportsAllErrors(t *testing.T) {
+	minV, maxV := 1, 10
+	authServices := []tools.ParamAuthService{{Name: "my-google-auth-service", Field: "auth_field"}}
+	params := tools.Parameters{
+		tools.NewStringParameter("my_string", "a string"),
+		tools.NewIntParameterWithRange("my_int", "an int", &minV, &maxV),
+		tools.NewStringParameterWithAllowedValues("my_enum", "an enum", []any{"a", "b"}),
+		tools.NewBooleanParameter("my_bool", "a bool"),
+		tools.NewStringParameterWithAuth("my_auth", "an authenticated string", authServices),
+		tools.NewFloatParameter("my_float", "a float"),
+	}
+	in := map[string]any{
+		"my_int":   20,
+		"my_enum":  "c",
+		"my_bool":  "yes",
+		"my_float": 1.5,
+	}
+	_, err := tools.ParseParams(params, in, make(map[string]map[string]any))
+	if err == nil {
+		t.Fatalf("expected error but Param parsed successfully")
+	}
+
+	var paramErrs tools.ParamErrors
+	if !errors.As(err, &paramErrs) {
+		t.Fatalf("expected tools.ParamErrors, got %T", err)
+	}
+	type paramError struct {
+		Name, Problem, Expected string
+	}
+	var got []paramError
+	for _, pe := range paramErrs {
+		got = append(got, paramError{pe.Name, pe.Problem, pe.Expected})
+	}
+	want := []paramError{
+		{Name: "my_string", Problem: "is required", Expected: "string"},
+		{Name: "my_int", Problem: "20 is above the maximum value", Expected: "integer, >= 1, <= 10"},
+		{Name: "my_enum", Problem: "c is not an allowed value", Expected: "string, one of [a b]"},
+		{Name: "my_bool", Problem: `"yes" not type "boolean"`, Expected: "boolean"},
+		{Name: "my_auth", Problem: "missing or invalid authentication header: unauthorized", Expected: "string"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected errors (-want +got):\n%s", diff)
+	}
+
+	wantMsg := `parameter "my_string" is required; unable to parse value for "my_int": 20 is above the maximum value; ` +
+		`unable to parse value for "my_enum": c is not an allowed value; unable to parse value for "my_bool": "yes" not type "boolean"; ` +
+		`error parsing authenticated parameter "my_auth": missing or invalid authentication header: unauthorized`
+	if err.Error() != wantMsg {
+		t.Fatalf("unexpected error message: got %q, want %q", err, wantMsg)
+	}
+
+	// the errors of each parameter remain reachable
+	if !errors.Is(err, tools.ErrUnauthorized) {
+		t.Fatalf("expected error to wrap tools.ErrUnauthorized")
+	}
+	var typeErr *tools.ParseTypeError
+	if !errors.As(err, &typeErr) || typeErr.Name != "my_bool" {
+		t.Fatalf("expected error to wrap a tools.ParseTypeError for %q, got %v", "my_bool", typeErr)
+	}
+}
+
+func TestParseOneOfParams(t *testing.T) {
+	params := tools.Parameters{
+		tools.NewOneOfParameter("target", "what to delete",
+			tools.OneOfBranch{Name: "paths", Parameters: tools.Parameters{
+				tools.NewArrayParameter("paths", "the paths", tools.NewStringParameter("path", "a path")),
+			}},
+			tools.OneOfBranch{Name: "filter", Parameters: tools.Parameters{
+				tools.NewStringParameter("collection", "a collection"),
+				tools.NewStringParameter("filter", "a filter"),
+			}},
+		),
+		tools.NewBooleanParameterWithDefault("dryRun", false, "a flag"),
+	}
+	tcs := []struct {
+		name    string
+		in      map[string]any
+		want    tools.ParamValues
+		wantErr string
+	}{
+		{
+			name:    "no branch",
+			in:      map[string]any{"dryRun": true},
+			wantErr: "exactly one of (paths) or (collection, filter) must be provided, but none was provided",
+		},
+		{
+			name: "first branch",
+			in:   map[string]any{"paths": []any{"a/b"}},
+			want: tools.ParamValues{
+				{Name: "target", Value: tools.OneOfValue{Branch: "paths", Params: tools.ParamValues{{Name: "paths", Value: []any{"a/b"}}}}},
+				{Name: "dryRun", Value: false},
+			},
+		},
+		{
+			name: "second branch",
+			in:   map[string]any{"collection": "users", "filter": "age > 30", "dryRun": true},
+			want: tools.ParamValues{
+				{Name: "target", Value: tools.OneOfValue{Branch: "filter", Params: tools.ParamValues{{Name: "collection", Value: "users"}, {Name: "filter", Value: "age > 30"}}}},
+				{Name: "dryRun", Value: true},
+			},
+		},
+		{
+			name:    "incomplete branch",
+			in:      map[string]any{"filter": "age > 30"},
+			wantErr: `parameter "collection" is required`,
+		},
+		{
+			name:    "both branches",
+			in:      map[string]any{"paths": []any{"a/b"}, "filter": "age > 30"},
+			wantErr: "exactly one of (paths) or (collection, filter) must be provided, but (paths) and (collection, filter) were all provided",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tools.ParseParams(params, tc.in, make(map[string]map[string]any))
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Fatalf("unexpected error: got %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected values (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	// the alternatives are reported as expected values
+	_, err := tools.ParseParams(params, map[string]any{}, nil)
+	var paramErrs tools.ParamErrors
+	if !errors.As(err, &paramErrs) || len(paramErrs) != 1 {
+		t.Fatalf("expected a single tools.ParamError, got %v", err)
+	}
+	if got, want := paramErrs[0].Expected, "exactly one of (paths) or (collection, filter)"; got != want {
+		t.Fatalf("unexpected expected values: got %q, want %q", got, want)
+	}
+
+	// the native manifest lists the parameters of every branch as optional
+	var names []string
+	for _, m := range params.Manifest() {
+		if m.Required {
+			t.Fatalf("expected parameter %q not to be required", m.Name)
+		}
+		names = append(names, m.Name)
+	}
+	if diff := cmp.Diff([]string{"paths", "collection", "filter", "dryRun"}, names); diff != "" {
+		t.Fatalf("unexpected manifest (-want +got):\n%s", diff)
+	}
+}
+
 func TestAuthParametersParse(t *testing.T) {
 	authServices := []tools.ParamAuthService{
 		{
@@ -1585,6 +2028,7 @@ func TestParamValues(t *testing.T) {
 }
 
 func TestParamManifest(t *testing.T) {
+	minItems, maxItems := 1, 3
 	tcs := []struct {
 		name string
 		in   tools.Parameter
@@ -1622,6 +2066,20 @@ func TestParamManifest(t *testing.T) {
 				Items:        &tools.ParameterManifest{Name: "foo-string", Type: "string", Required: true, Description: "bar", AuthServices: []string{}},
 			},
 		},
+		{
+			name: "array of int with range",
+			in:   tools.NewArrayParameterWithRange("foo-array", "bar", &minItems, &maxItems, tools.NewIntParameter("foo-int", "bar")),
+			want: tools.ParameterManifest{
+				Name:         "foo-array",
+				Type:         "array",
+				Required:     true,
+				Description:  "bar",
+				AuthServices: []string{},
+				Items:        &tools.ParameterManifest{Name: "foo-int", Type: "integer", Required: true, Description: "bar", AuthServices: []string{}},
+				MinItems:     &minItems,
+				MaxItems:     &maxItems,
+			},
+		},
 		{
 			name: "string default",
 			in:   tools.NewStringParameterWithDefault("foo-string", "foo", "bar"),
@@ -1710,6 +2168,18 @@ func TestParamManifest(t *testing.T) {
 				AdditionalProperties: map[string]any{"type": "string"},
 			},
 		},
+		{
+			name: "json",
+			in:   tools.NewJSONParameterWithRequired("foo-json", "bar", false),
+			want: tools.ParameterManifest{
+				Name:                 "foo-json",
+				Type:                 "object",
+				Required:             false,
+				Description:          "bar (any JSON value, not a string containing JSON)",
+				AuthServices:         []string{},
+				AdditionalProperties: true,
+			},
+		},
 		{
 			name: "generic map (additionalProperties true)",
 			in:   tools.NewMapParameter("foo-map", "bar", ""),
@@ -1734,6 +2204,7 @@ func TestParamManifest(t *testing.T) {
 }
 
 func TestParamMcpManifest(t *testing.T) {
+	minItems, maxItems := 1, 3
 	tcs := []struct {
 		name          string
 		in            tools.Parameter
@@ -1774,6 +2245,18 @@ func TestParamMcpManifest(t *testing.T) {
 			},
 			wantAuthParam: []string{},
 		},
+		{
+			name: "array of int with range",
+			in:   tools.NewArrayParameterWithRange("foo-array", "bar", &minItems, &maxItems, tools.NewIntParameter("foo-int", "bar")),
+			want: tools.ParameterMcpManifest{
+				Type:        "array",
+				Description: "bar",
+				Items:       &tools.ParameterMcpManifest{Type: "integer", Description: "bar"},
+				MinItems:    &minItems,
+				MaxItems:    &maxItems,
+			},
+			wantAuthParam: []string{},
+		},
 
 		{
 			name: "map with string values",
@@ -1795,6 +2278,16 @@ func TestParamMcpManifest(t *testing.T) {
 			},
 			wantAuthParam: []string{},
 		},
+		{
+			name: "json",
+			in:   tools.NewJSONParameter("foo-json", "bar"),
+			want: tools.ParameterMcpManifest{
+				Type:                 "object",
+				Description:          "bar (any JSON value, not a string containing JSON)",
+				AdditionalProperties: true,
+			},
+			wantAuthParam: []string{},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
@@ -1841,25 +2334,31 @@ func TestMcpManifest(t *testing.T) {
 			wantSchema: tools.McpToolsSchema{
 				Type: "object",
 				Properties: map[string]tools.ParameterMcpManifest{
-					"foo-string":       {Type: "string", Description: "bar"},
-					"foo-string2":      {Type: "string", Description: "bar"},
+					"foo-string":       {Type: "string", Description: "bar", Examples: []any{"foo"}, ExampleGenerated: true},
+					"foo-string2":      {Type: "string", Description: "bar", Examples: []any{"example"}, ExampleGenerated: true},
 					"foo-string3-auth": {Type: "string", Description: "bar"},
-					"foo-int2":         {Type: "integer", Description: "bar"},
-					"foo-float":        {Type: "number", Description: "bar"},
+					"foo-int2":         {Type: "integer", Description: "bar", Examples: []any{42}, ExampleGenerated: true},
+					"foo-float":        {Type: "number", Description: "bar", Examples: []any{3.14}, ExampleGenerated: true},
 					"foo-array2": {
-						Type:        "array",
-						Description: "bar",
-						Items:       &tools.ParameterMcpManifest{Type: "string", Description: "bar"},
+						Type:             "array",
+						Description:      "bar",
+						Items:            &tools.ParameterMcpManifest{Type: "string", Description: "bar"},
+						Examples:         []any{[]any{"example"}},
+						ExampleGenerated: true,
 					},
 					"foo-map-int": {
 						Type:                 "object",
 						Description:          "a map of ints",
 						AdditionalProperties: map[string]any{"type": "integer"},
+						Examples:             []any{map[string]any{"key": 42}},
+						ExampleGenerated:     true,
 					},
 					"foo-map-any": {
 						Type:                 "object",
 						Description:          "a map of any",
 						AdditionalProperties: true,
+						Examples:             []any{map[string]any{"key": "value"}},
+						ExampleGenerated:     true,
 					},
 				},
 				Required: []string{"foo-string2", "foo-string3-auth", "foo-int2", "foo-float", "foo-array2", "foo-map-int", "foo-map-any"},
@@ -1868,6 +2367,36 @@ func TestMcpManifest(t *testing.T) {
 				"foo-string3-auth": []string{"my-google-auth-service", "other-auth-service"},
 			},
 		},
+		{
+			name: "one of",
+			in: tools.Parameters{
+				tools.NewOneOfParameter("target", "bar",
+					tools.OneOfBranch{Name: "by-path", Parameters: tools.Parameters{
+						tools.NewStringParameter("path", "bar"),
+					}},
+					tools.OneOfBranch{Name: "by-filter", Parameters: tools.Parameters{
+						tools.NewStringParameterWithRequired("filter", "bar", false),
+						tools.NewIntParameterWithRequired("limit", "bar", false),
+					}},
+				),
+				tools.NewIntParameter("foo-int", "bar"),
+			},
+			wantSchema: tools.McpToolsSchema{
+				Type: "object",
+				Properties: map[string]tools.ParameterMcpManifest{
+					"path":    {Type: "string", Description: "bar", Examples: []any{"example"}, ExampleGenerated: true},
+					"filter":  {Type: "string", Description: "bar", Examples: []any{"example"}, ExampleGenerated: true},
+					"limit":   {Type: "integer", Description: "bar", Examples: []any{42}, ExampleGenerated: true},
+					"foo-int": {Type: "integer", Description: "bar", Examples: []any{42}, ExampleGenerated: true},
+				},
+				Required: []string{"foo-int"},
+				OneOf: []tools.McpSchemaConstraint{
+					{Required: []string{"path"}},
+					{AnyOf: []tools.McpSchemaConstraint{{Required: []string{"filter"}}, {Required: []string{"limit"}}}},
+				},
+			},
+			wantAuthParam: map[string][]string{},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
@@ -1971,6 +2500,57 @@ func TestFailParametersUnmarshal(t *testing.T) {
 			},
 			err: "unsupported valueType \"not-a-real-type\" for map parameter",
 		},
+		{
+			name: "array parameter with map items",
+			in: []map[string]any{
+				{
+					"name":        "my_array",
+					"type":        "array",
+					"description": "this param is an array of maps",
+					"items": map[string]string{
+						"name":        "my_map",
+						"type":        "map",
+						"description": "map item",
+					},
+				},
+			},
+			err: "unsupported items type \"map\" for array parameter \"my_array\"",
+		},
+		{
+			name: "array parameter with negative minItems",
+			in: []map[string]any{
+				{
+					"name":        "my_array",
+					"type":        "array",
+					"description": "this param is an array of strings",
+					"minItems":    -1,
+					"items": map[string]string{
+						"name":        "my_string",
+						"type":        "string",
+						"description": "string item",
+					},
+				},
+			},
+			err: "minItems for array parameter \"my_array\" must not be negative",
+		},
+		{
+			name: "array parameter with minItems greater than maxItems",
+			in: []map[string]any{
+				{
+					"name":        "my_array",
+					"type":        "array",
+					"description": "this param is an array of strings",
+					"minItems":    3,
+					"maxItems":    2,
+					"items": map[string]string{
+						"name":        "my_string",
+						"type":        "string",
+						"description": "string item",
+					},
+				},
+			},
+			err: "minItems for array parameter \"my_array\" must not be greater than maxItems",
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
@@ -0,0 +1,135 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ConfigWarner is implemented by tool configs that are valid but likely
+// mistaken. The warnings are logged when the tool is initialized.
+type ConfigWarner interface {
+	ConfigWarnings() []string
+}
+
+// Placeholders are the placeholders of a statement.
+type Placeholders struct {
+	statement string
+	// offsets are the byte offsets of the placeholders in the statement.
+	offsets []int
+	// Count is the number of parameters the placeholders bind.
+	Count int
+}
+
+// QuestionPlaceholders returns the `?` placeholders of statement. Question
+// marks inside quoted strings or identifiers are not placeholders.
+func QuestionPlaceholders(statement string) Placeholders {
+	p := Placeholders{statement: statement}
+	var quote rune
+	escaped := false
+	for i, r := range statement {
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0:
+			if r == '\\' && quote != '`' {
+				escaped = true
+			} else if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '?':
+			p.offsets = append(p.offsets, i)
+		}
+	}
+	p.Count = len(p.offsets)
+	return p
+}
+
+// DollarPlaceholders returns the `$n` placeholders of statement. They bind as
+// many parameters as the highest n, since a placeholder can be repeated.
+// Placeholders inside quoted strings, quoted identifiers or dollar-quoted
+// strings are ignored.
+func DollarPlaceholders(statement string) Placeholders {
+	p := Placeholders{statement: statement}
+	for i := 0; i < len(statement); i++ {
+		switch c := statement[i]; c {
+		case '\'', '"':
+			if end := strings.IndexByte(statement[i+1:], c); end >= 0 {
+				i += end + 1
+			} else {
+				i = len(statement)
+			}
+		case '$':
+			j := i + 1
+			for j < len(statement) && statement[j] >= '0' && statement[j] <= '9' {
+				j++
+			}
+			if j > i+1 {
+				n, _ := strconv.Atoi(statement[i+1 : j])
+				p.offsets = append(p.offsets, i)
+				p.Count = max(p.Count, n)
+				i = j - 1
+				continue
+			}
+			// skip dollar-quoted strings, such as $$...$$ or $tag$...$tag$
+			for j < len(statement) && (statement[j] == '_' || isLetter(statement[j])) {
+				j++
+			}
+			if j < len(statement) && statement[j] == '$' {
+				tag := statement[i : j+1]
+				if end := strings.Index(statement[j+1:], tag); end >= 0 {
+					i = j + end + len(tag)
+				} else {
+					i = len(statement)
+				}
+			}
+		}
+	}
+	return p
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// Positions returns the positions of the placeholders, as "line:column".
+func (p Placeholders) Positions() []string {
+	positions := make([]string, 0, len(p.offsets))
+	for _, offset := range p.offsets {
+		before := p.statement[:offset]
+		line := strings.Count(before, "\n") + 1
+		column := offset - strings.LastIndex(before, "\n")
+		positions = append(positions, fmt.Sprintf("%d:%d", line, column))
+	}
+	return positions
+}
+
+// CheckCount returns an error if the placeholders do not bind exactly the
+// given number of parameters.
+func (p Placeholders) CheckCount(params int) error {
+	if p.Count == params {
+		return nil
+	}
+	msg := fmt.Sprintf("statement binds %d parameters but %d are declared", p.Count, params)
+	if len(p.offsets) > 0 {
+		msg += fmt.Sprintf(" (placeholders at %s)", strings.Join(p.Positions(), ", "))
+	}
+	return errors.New(msg)
+}
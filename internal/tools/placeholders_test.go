@@ -0,0 +1,141 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestQuestionPlaceholders(t *testing.T) {
+	tcs := []struct {
+		desc          string
+		statement     string
+		params        int
+		wantPositions []string
+		wantErr       string
+	}{
+		{
+			desc:          "matching",
+			statement:     "SELECT * FROM t WHERE a = ? AND b = ?",
+			params:        2,
+			wantPositions: []string{"1:27", "1:37"},
+		},
+		{
+			desc:          "too few placeholders",
+			statement:     "SELECT * FROM t\nWHERE a = ?",
+			params:        2,
+			wantPositions: []string{"2:11"},
+			wantErr:       "statement binds 1 parameters but 2 are declared (placeholders at 2:11)",
+		},
+		{
+			desc:          "too many placeholders",
+			statement:     "SELECT * FROM t WHERE a = ? AND b = ?",
+			params:        1,
+			wantPositions: []string{"1:27", "1:37"},
+			wantErr:       "statement binds 2 parameters but 1 are declared (placeholders at 1:27, 1:37)",
+		},
+		{
+			desc:      "no placeholders",
+			statement: "SELECT 1",
+			params:    1,
+			wantErr:   "statement binds 0 parameters but 1 are declared",
+		},
+		{
+			desc:          "placeholders in literals",
+			statement:     `SELECT 'what?', "who?", ` + "`why?`" + `, 'it\'s?' FROM t WHERE a = ?`,
+			params:        1,
+			wantPositions: []string{"1:59"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			p := tools.QuestionPlaceholders(tc.statement)
+			if diff := cmp.Diff(tc.wantPositions, p.Positions(), cmpopts.EquateEmpty()); diff != "" {
+				t.Fatalf("unexpected positions: diff %v", diff)
+			}
+			checkCount(t, p, tc.params, tc.wantErr)
+		})
+	}
+}
+
+func TestDollarPlaceholders(t *testing.T) {
+	tcs := []struct {
+		desc      string
+		statement string
+		params    int
+		wantCount int
+		wantErr   string
+	}{
+		{
+			desc:      "matching",
+			statement: "SELECT * FROM t WHERE a = $1 AND b = $2",
+			params:    2,
+			wantCount: 2,
+		},
+		{
+			desc:      "repeated placeholder",
+			statement: "SELECT * FROM t WHERE a = $1 OR b = $1",
+			params:    1,
+			wantCount: 1,
+		},
+		{
+			desc:      "too few placeholders",
+			statement: "SELECT * FROM t WHERE a = $1",
+			params:    2,
+			wantCount: 1,
+			wantErr:   "statement binds 1 parameters but 2 are declared (placeholders at 1:27)",
+		},
+		{
+			desc:      "too many placeholders",
+			statement: "SELECT * FROM t WHERE a = $1 AND b = $10",
+			params:    2,
+			wantCount: 10,
+			wantErr:   "statement binds 10 parameters but 2 are declared (placeholders at 1:27, 1:38)",
+		},
+		{
+			desc:      "placeholders in literals",
+			statement: `SELECT '$2', "$3", $$ $4 $$, $fn$ $5 $fn$ FROM t WHERE a = $1`,
+			params:    1,
+			wantCount: 1,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			p := tools.DollarPlaceholders(tc.statement)
+			if p.Count != tc.wantCount {
+				t.Fatalf("got count %d, want %d", p.Count, tc.wantCount)
+			}
+			checkCount(t, p, tc.params, tc.wantErr)
+		})
+	}
+}
+
+func checkCount(t *testing.T, p tools.Placeholders, params int, wantErr string) {
+	t.Helper()
+	err := p.CheckCount(params)
+	if wantErr == "" {
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		return
+	}
+	if err == nil || err.Error() != wantErr {
+		t.Fatalf("got error %v, want %q", err, wantErr)
+	}
+}
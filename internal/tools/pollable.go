@@ -0,0 +1,306 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+)
+
+const (
+	// IfNoneMatchParameter is the argument holding the ETag of the last
+	// result of an invocation, for clients that cannot set the If-None-Match
+	// header, such as MCP clients.
+	IfNoneMatchParameter = "_ifNoneMatch"
+	// DefaultPollTTL is the default of PollableSpec.TTL.
+	DefaultPollTTL = 10 * time.Second
+	// maxPollEntries bounds the ETags recorded by a Poll.
+	maxPollEntries = 1024
+)
+
+// PollableSpec is the kind-agnostic `pollable` field of a tool, when set to
+// a mapping rather than true.
+type PollableSpec struct {
+	// TTL is how long the ETag of the result of an invocation is recorded,
+	// such as "10s". It defaults to DefaultPollTTL.
+	TTL string `yaml:"ttl" json:"ttl"`
+	// TrustCache returns a result unchanged within TTL without invoking the
+	// tool again.
+	TrustCache bool `yaml:"trustCache" json:"trustCache"`
+}
+
+// Poll records the ETags of the results of the invocations of a tool by
+// arguments, in the memory of the replica, so that repeated invocations whose
+// result did not change return it as unchanged.
+type Poll struct {
+	name       string
+	ttl        time.Duration
+	trustCache bool
+	now        func() time.Time
+
+	mu sync.Mutex
+	// etags holds the ETag of the last result by hash of the arguments.
+	etags map[string]pollEntry
+}
+
+type pollEntry struct {
+	etag    string
+	expires time.Time
+}
+
+// NewPoll returns the Poll of the tool with the given name.
+func NewPoll(name string, spec PollableSpec) (*Poll, error) {
+	return NewPollWithClock(name, spec, time.Now)
+}
+
+// NewPollWithClock returns the Poll of the tool with the given name, using
+// now as its clock.
+func NewPollWithClock(name string, spec PollableSpec, now func() time.Time) (*Poll, error) {
+	ttl := DefaultPollTTL
+	if spec.TTL != "" {
+		var err error
+		ttl, err = time.ParseDuration(spec.TTL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ttl %q: %w", spec.TTL, err)
+		}
+		if ttl <= 0 {
+			return nil, fmt.Errorf("invalid ttl %q: must be positive", spec.TTL)
+		}
+	}
+	return &Poll{
+		name:       name,
+		ttl:        ttl,
+		trustCache: spec.TrustCache,
+		now:        now,
+		etags:      make(map[string]pollEntry),
+	}, nil
+}
+
+// etag returns the ETag recorded for the arguments with the given hash, if
+// it has not expired.
+func (p *Poll) etag(key string) (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	e, ok := p.etags[key]
+	if !ok || !p.now().Before(e.expires) {
+		return "", false
+	}
+	return e.etag, true
+}
+
+// record records the ETag of the result of the arguments with the given hash.
+func (p *Poll) record(key, etag string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	if _, ok := p.etags[key]; !ok && len(p.etags) >= maxPollEntries {
+		for k, e := range p.etags {
+			if !now.Before(e.expires) {
+				delete(p.etags, k)
+			}
+		}
+		if len(p.etags) >= maxPollEntries {
+			clear(p.etags)
+		}
+	}
+	p.etags[key] = pollEntry{etag: etag, expires: now.Add(p.ttl)}
+}
+
+// ResultETag returns the ETag of a result: a strong, quoted ETag of the hash
+// of its JSON serialization.
+func ResultETag(result any) (string, error) {
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("unable to hash result: %w", err)
+	}
+	return resultETag(raw), nil
+}
+
+func resultETag(raw json.RawMessage) string {
+	sum := sha256.Sum256(raw)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches reports whether etag is one of the ETags of the value of an
+// If-None-Match header. Weak ETags match by their value, and "*" matches any.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// PollState is the ETag state of an invocation, shared by the handler of its
+// request with the pollable tool it invokes.
+type PollState struct {
+	// IfNoneMatch is the value of the If-None-Match header of the request.
+	IfNoneMatch string
+	// ETag is the ETag of the result, set by a pollable tool.
+	ETag string
+	// Unchanged is set by a pollable tool when it returns that the result is
+	// unchanged rather than the result.
+	Unchanged bool
+	// NotModified is set along with Unchanged when the ETag was presented in
+	// IfNoneMatch, so that the handler responds without a result.
+	NotModified bool
+}
+
+// pollStateKey is the key used to store the PollState within context.
+type pollStateKey struct{}
+
+// WithPollState adds a PollState with the value of the If-None-Match header
+// of a request into the context, and returns it.
+func WithPollState(ctx context.Context, ifNoneMatch string) (context.Context, *PollState) {
+	state := &PollState{IfNoneMatch: ifNoneMatch}
+	return context.WithValue(ctx, pollStateKey{}, state), state
+}
+
+// PollStateFromContext returns the PollState of the context, or nil if there
+// is none.
+func PollStateFromContext(ctx context.Context) *PollState {
+	state, _ := ctx.Value(pollStateKey{}).(*PollState)
+	return state
+}
+
+// PollableConfig wraps a ToolConfig with the kind-agnostic `pollable` field.
+type PollableConfig struct {
+	ToolConfig
+	Poll *Poll
+}
+
+// validate interface
+var _ ToolConfig = PollableConfig{}
+
+func (cfg PollableConfig) Initialize(srcs map[string]sources.Source) (Tool, error) {
+	t, err := cfg.ToolConfig.Initialize(srcs)
+	if err != nil {
+		return nil, err
+	}
+	return PollableTool{Tool: t, Poll: cfg.Poll}, nil
+}
+
+// PollableTool returns the results of a Tool polled with the ETag of its
+// last result as unchanged, when they did not change since.
+type PollableTool struct {
+	Tool
+	Poll *Poll
+}
+
+func (t PollableTool) unwrap() Tool {
+	return t.Tool
+}
+
+func ifNoneMatchParameter() *StringParameter {
+	return NewStringParameterWithRequired(IfNoneMatchParameter, "ETag of the last result of an invocation with the same arguments. If the result did not change, only returns that it is unchanged.", false)
+}
+
+func (t PollableTool) Manifest() Manifest {
+	m := t.Tool.Manifest()
+	m.Parameters = append(append([]ParameterManifest{}, m.Parameters...), ifNoneMatchParameter().Manifest())
+	return m
+}
+
+func (t PollableTool) McpManifest() McpManifest {
+	m := t.Tool.McpManifest()
+	props := make(map[string]ParameterMcpManifest, len(m.InputSchema.Properties)+1)
+	for name, p := range m.InputSchema.Properties {
+		props[name] = p
+	}
+	props[IfNoneMatchParameter], _ = ifNoneMatchParameter().McpManifest()
+	m.InputSchema.Properties = props
+	return m
+}
+
+func (t PollableTool) ParseParams(data map[string]any, claims map[string]map[string]any) (ParamValues, error) {
+	params, err := t.Tool.ParseParams(data, claims)
+	if err != nil {
+		return nil, err
+	}
+	raw, ok := data[IfNoneMatchParameter]
+	if !ok || raw == nil {
+		return params, nil
+	}
+	etag, ok := raw.(string)
+	if !ok {
+		return nil, fmt.Errorf("parameter %q must be a string", IfNoneMatchParameter)
+	}
+	return append(params, ParamValue{Name: IfNoneMatchParameter, Value: etag}), nil
+}
+
+// Invoke invokes the Tool, unless its Poll trusts the ETag recorded for the
+// arguments, and returns an unchanged result if the ETag of the result was
+// recorded for them and matches the one presented by the client.
+func (t PollableTool) Invoke(ctx context.Context, inv Invocation) (any, error) {
+	state := PollStateFromContext(ctx)
+	var ifNoneMatch string
+	if state != nil {
+		ifNoneMatch = state.IfNoneMatch
+	}
+	args := make(ParamValues, 0, len(inv.Params))
+	fromArg := false
+	for _, p := range inv.Params {
+		if p.Name == IfNoneMatchParameter {
+			ifNoneMatch, fromArg = p.Value.(string), true
+			continue
+		}
+		args = append(args, p)
+	}
+
+	key, err := invocationHash(t.Poll.name, args)
+	if err != nil {
+		return nil, err
+	}
+	recorded, ok := t.Poll.etag(key)
+	if ok && t.Poll.trustCache && ifNoneMatch != "" && etagMatches(ifNoneMatch, recorded) {
+		return unchanged(state, recorded, fromArg), nil
+	}
+
+	res, raw, err := collectResult(ctx, t.Tool, inv.WithParams(args))
+	if err != nil {
+		return nil, err
+	}
+	etag := resultETag(raw)
+	t.Poll.record(key, etag)
+	if state != nil {
+		state.ETag = etag
+	}
+	if ok && recorded == etag && ifNoneMatch != "" && etagMatches(ifNoneMatch, etag) {
+		return unchanged(state, etag, fromArg), nil
+	}
+	return res, nil
+}
+
+// unchanged returns the result of an invocation whose result did not change.
+// Unless the ETag was presented as an argument, the handler of the request
+// is told to respond without a result, such as with status 304.
+func unchanged(state *PollState, etag string, fromArg bool) any {
+	if state != nil {
+		state.ETag = etag
+		state.Unchanged = true
+		state.NotModified = !fromArg
+	}
+	return map[string]any{"unchanged": true, "etag": etag}
+}
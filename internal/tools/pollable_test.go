@@ -0,0 +1,266 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// polledTool returns the status of a job, and counts its invocations.
+type polledTool struct {
+	mockTool
+	status *string
+	calls  *int
+}
+
+func newPolledTool() polledTool {
+	status := "running"
+	return polledTool{mockTool: mockTool{name: "job_status"}, status: &status, calls: new(int)}
+}
+
+func (t polledTool) Invoke(context.Context, tools.Invocation) (any, error) {
+	*t.calls++
+	return map[string]any{"status": *t.status, "progress": []any{1, 2, 3}}, nil
+}
+
+// pollTool returns a pollable tool over inner, and a clock to advance.
+func pollTool(t *testing.T, inner polledTool, spec tools.PollableSpec) (tools.PollableTool, *time.Time) {
+	t.Helper()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	poll, err := tools.NewPollWithClock("job_status", spec, func() time.Time { return now })
+	if err != nil {
+		t.Fatalf("unable to create poll: %s", err)
+	}
+	return tools.PollableTool{Tool: inner, Poll: poll}, &now
+}
+
+// poll invokes tool with the ETag in the If-None-Match header, and returns
+// its result and poll state.
+func poll(t *testing.T, tool tools.Tool, ifNoneMatch string, params tools.ParamValues) (any, *tools.PollState) {
+	t.Helper()
+	ctx, state := tools.WithPollState(context.Background(), ifNoneMatch)
+	res, err := tool.Invoke(ctx, tools.Invocation{Params: params})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return res, state
+}
+
+func jobParams(id int) tools.ParamValues {
+	return tools.ParamValues{{Name: "id", Value: id}}
+}
+
+func TestResultETag(t *testing.T) {
+	a := map[string]any{"status": "done", "progress": 100, "steps": []any{"a", "b"}}
+	b := map[string]any{}
+	b["steps"] = []any{"a", "b"}
+	b["progress"] = 100
+	b["status"] = "done"
+
+	etagA, err := tools.ResultETag(a)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	etagB, err := tools.ResultETag(b)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if etagA != etagB {
+		t.Fatalf("ETags of equal results differ: %s and %s", etagA, etagB)
+	}
+	if !strings.HasPrefix(etagA, `"`) || !strings.HasSuffix(etagA, `"`) || len(etagA) != 34 {
+		t.Fatalf("ETag is not a quoted hash: %s", etagA)
+	}
+
+	b["status"] = "running"
+	etagB, err = tools.ResultETag(b)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if etagA == etagB {
+		t.Fatalf("ETags of different results are equal: %s", etagA)
+	}
+}
+
+func TestNewPoll(t *testing.T) {
+	for _, tc := range []struct {
+		ttl     string
+		wantErr string
+	}{
+		{ttl: "soon", wantErr: `invalid ttl "soon"`},
+		{ttl: "-5s", wantErr: `invalid ttl "-5s": must be positive`},
+	} {
+		_, err := tools.NewPoll("job_status", tools.PollableSpec{TTL: tc.ttl})
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("ttl %q: got error %v, want it to contain %q", tc.ttl, err, tc.wantErr)
+		}
+	}
+}
+
+func TestPollableToolInvoke(t *testing.T) {
+	t.Run("not modified", func(t *testing.T) {
+		inner := newPolledTool()
+		tool, _ := pollTool(t, inner, tools.PollableSpec{})
+		first, state := poll(t, tool, "", jobParams(1))
+		etag := state.ETag
+		if wantETag, _ := tools.ResultETag(first); etag != wantETag {
+			t.Fatalf("incorrect ETag: got %s, want %s", etag, wantETag)
+		}
+		if state.NotModified {
+			t.Fatalf("first invocation is not modified")
+		}
+
+		res, state := poll(t, tool, etag, jobParams(1))
+		if !state.NotModified || state.ETag != etag {
+			t.Fatalf("expected the result to be not modified, got state %+v", state)
+		}
+		want := map[string]any{"unchanged": true, "etag": etag}
+		if diff := cmp.Diff(want, res); diff != "" {
+			t.Fatalf("incorrect result (-want +got):\n%s", diff)
+		}
+		// the result is computed again to be compared
+		if *inner.calls != 2 {
+			t.Fatalf("unexpected number of invocations: got %d, want 2", *inner.calls)
+		}
+
+		// weak ETags and lists of ETags match too
+		_, state = poll(t, tool, `W/"other", W/`+etag, jobParams(1))
+		if !state.NotModified {
+			t.Fatalf("expected the result to be not modified, got state %+v", state)
+		}
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		inner := newPolledTool()
+		tool, _ := pollTool(t, inner, tools.PollableSpec{})
+		_, state := poll(t, tool, "", jobParams(1))
+		etag := state.ETag
+
+		*inner.status = "done"
+		res, state := poll(t, tool, etag, jobParams(1))
+		if state.NotModified || state.ETag == etag {
+			t.Fatalf("expected a new result, got state %+v", state)
+		}
+		if res.(map[string]any)["status"] != "done" {
+			t.Fatalf("incorrect result: %v", res)
+		}
+	})
+
+	t.Run("other arguments", func(t *testing.T) {
+		inner := newPolledTool()
+		tool, _ := pollTool(t, inner, tools.PollableSpec{})
+		_, state := poll(t, tool, "", jobParams(1))
+
+		// the ETag was not recorded for the arguments of the invocation
+		_, state = poll(t, tool, state.ETag, jobParams(2))
+		if state.NotModified {
+			t.Fatalf("expected the result of other arguments to be returned")
+		}
+	})
+
+	t.Run("trust cache", func(t *testing.T) {
+		inner := newPolledTool()
+		tool, _ := pollTool(t, inner, tools.PollableSpec{TrustCache: true})
+		_, state := poll(t, tool, "", jobParams(1))
+		etag := state.ETag
+
+		*inner.status = "done"
+		_, state = poll(t, tool, etag, jobParams(1))
+		if !state.NotModified || state.ETag != etag {
+			t.Fatalf("expected the result to be not modified, got state %+v", state)
+		}
+		if *inner.calls != 1 {
+			t.Fatalf("unexpected number of invocations: got %d, want 1", *inner.calls)
+		}
+
+		// without an ETag, the tool is invoked
+		res, _ := poll(t, tool, "", jobParams(1))
+		if res.(map[string]any)["status"] != "done" || *inner.calls != 2 {
+			t.Fatalf("expected the tool to be invoked, got %v after %d invocations", res, *inner.calls)
+		}
+	})
+
+	t.Run("ttl expiry", func(t *testing.T) {
+		for _, trust := range []bool{false, true} {
+			inner := newPolledTool()
+			tool, now := pollTool(t, inner, tools.PollableSpec{TTL: "5s", TrustCache: trust})
+			_, state := poll(t, tool, "", jobParams(1))
+			etag := state.ETag
+
+			*now = now.Add(4 * time.Second)
+			if _, state = poll(t, tool, etag, jobParams(1)); !state.NotModified {
+				t.Fatalf("trustCache %t: expected the result to be not modified within the TTL", trust)
+			}
+			*now = now.Add(5 * time.Second)
+			calls := *inner.calls
+			if _, state = poll(t, tool, etag, jobParams(1)); state.NotModified {
+				t.Fatalf("trustCache %t: expected the result to be returned once the TTL expired", trust)
+			}
+			if *inner.calls != calls+1 {
+				t.Fatalf("trustCache %t: expected the tool to be invoked once the TTL expired", trust)
+			}
+		}
+	})
+
+	t.Run("argument", func(t *testing.T) {
+		inner := newPolledTool()
+		tool, _ := pollTool(t, inner, tools.PollableSpec{})
+		_, state := poll(t, tool, "", jobParams(1))
+		etag := state.ETag
+
+		params := append(jobParams(1), tools.ParamValue{Name: tools.IfNoneMatchParameter, Value: etag})
+		res, state := poll(t, tool, "", params)
+		want := map[string]any{"unchanged": true, "etag": etag}
+		if diff := cmp.Diff(want, res); diff != "" {
+			t.Fatalf("incorrect result (-want +got):\n%s", diff)
+		}
+		// the unchanged result is returned rather than no result
+		if !state.Unchanged || state.NotModified {
+			t.Fatalf("expected an unchanged result, got state %+v", state)
+		}
+
+		// without a poll state, such as when invoked in bulk
+		res, err := tool.Invoke(context.Background(), tools.Invocation{Params: params})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if diff := cmp.Diff(want, res); diff != "" {
+			t.Fatalf("incorrect result (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestPollableToolParams(t *testing.T) {
+	tool := tools.PollableTool{Tool: newKeyedTool()}
+	if _, err := tool.ParseParams(map[string]any{tools.IfNoneMatchParameter: 42}, nil); err == nil {
+		t.Fatalf("expected an error for a non-string ETag")
+	}
+	params, err := tool.ParseParams(map[string]any{tools.IfNoneMatchParameter: `"abc"`}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(map[string]any{tools.IfNoneMatchParameter: `"abc"`}, params.AsMap()); diff != "" {
+		t.Fatalf("incorrect params (-want +got):\n%s", diff)
+	}
+	if _, ok := tool.McpManifest().InputSchema.Properties[tools.IfNoneMatchParameter]; !ok {
+		t.Fatalf("expected %s in the input schema", tools.IfNoneMatchParameter)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+)
+
+// CategoryPoolExhausted is the category of the errors of the invocations that
+// failed waiting for a connection of the exhausted pool of their source.
+const CategoryPoolExhausted = "pool_exhausted"
+
+// PoolExhaustedError is returned when an invocation failed waiting for a
+// connection of the exhausted pool of its source.
+type PoolExhaustedError struct {
+	Source string `json:"source"`
+	sources.PoolStats
+	// RetryAfter is how long to wait for a connection to be released.
+	RetryAfter time.Duration `json:"-"`
+	err        error
+}
+
+func (e *PoolExhaustedError) Error() string {
+	return fmt.Sprintf("%s, retry after %ds: the connection pool of source %q is exhausted (%d in use, %d idle, %d max): %s", ErrBackendUnavailable, int(e.RetryAfter.Seconds()), e.Source, e.InUse, e.Idle, e.Max, e.err)
+}
+
+func (e *PoolExhaustedError) Unwrap() []error {
+	return []error{ErrBackendUnavailable, e.err}
+}
+
+// PoolExhaustionTool wraps a Tool acting on a PoolSource. Its invocations that
+// fail waiting for a connection of the exhausted pool of the source return a
+// PoolExhaustedError, rather than the context error of the query.
+type PoolExhaustionTool struct {
+	Tool
+	SourceName string
+	Source     sources.PoolSource
+}
+
+func (t PoolExhaustionTool) unwrap() Tool {
+	return t.Tool
+}
+
+func (t PoolExhaustionTool) Invoke(ctx context.Context, inv Invocation) (any, error) {
+	monitor := t.Source.PoolMonitor()
+	if monitor == nil {
+		return t.Tool.Invoke(ctx, inv)
+	}
+	ctx, watch := monitor.Watch(ctx)
+	start := time.Now()
+	res, err := t.Tool.Invoke(ctx, inv)
+	if err == nil {
+		monitor.RecordInvocation(time.Since(start))
+		return res, nil
+	}
+	stats, ok := watch.Exhausted(err)
+	if !ok {
+		return res, err
+	}
+	return nil, &PoolExhaustedError{Source: t.SourceName, PoolStats: stats, RetryAfter: monitor.RetryAfter(), err: err}
+}
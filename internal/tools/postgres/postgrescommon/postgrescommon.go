@@ -0,0 +1,208 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgrescommon
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// IsJSON returns whether the column described by f holds JSON, or JSONB, or an
+// array of either.
+func IsJSON(f pgconn.FieldDescription) bool {
+	switch f.DataTypeOID {
+	case pgtype.JSONOID, pgtype.JSONBOID, pgtype.JSONArrayOID, pgtype.JSONBArrayOID:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsTimestamp returns whether the column described by f holds a timestamp,
+// with or without time zone. Dates are not timestamps.
+func IsTimestamp(f pgconn.FieldDescription) bool {
+	switch f.DataTypeOID {
+	case pgtype.TimestampOID, pgtype.TimestamptzOID:
+		return true
+	default:
+		return false
+	}
+}
+
+// RowToMap returns the values of a row, keyed by the names of their columns.
+// The values of JSON columns are parsed, so that they are not encoded twice.
+// The values of timestamp columns are converted to the result timezone of
+// ctx, if any.
+func RowToMap(ctx context.Context, fields []pgconn.FieldDescription, values []any) map[string]any {
+	row := make(map[string]any, len(fields))
+	for i, f := range fields {
+		v := values[i]
+		switch {
+		case v == nil:
+		case IsJSON(f):
+			v = tools.DecodeJSONColumn(ctx, f.Name, v)
+		case IsTimestamp(f):
+			v = tools.ConvertTimestamp(ctx, v)
+		}
+		row[f.Name] = v
+	}
+	return row
+}
+
+// RowQuerier is implemented by pools and transactions.
+type RowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// Estimate returns the estimates of the planner for statement when run with
+// params, without running it.
+func Estimate(ctx context.Context, q RowQuerier, statement string, params ...any) (tools.BudgetEstimate, error) {
+	var plan any
+	if err := q.QueryRow(ctx, "EXPLAIN (FORMAT JSON) "+statement, params...).Scan(&plan); err != nil {
+		return nil, fmt.Errorf("unable to estimate query: %w", err)
+	}
+	return PlanEstimate(plan)
+}
+
+// PlanEstimate returns the rows and the total cost estimated for the top
+// node of plan, the output of EXPLAIN (FORMAT JSON).
+func PlanEstimate(plan any) (tools.BudgetEstimate, error) {
+	plans, ok := plan.([]any)
+	if !ok || len(plans) == 0 {
+		return nil, fmt.Errorf("unexpected plan of type %T", plan)
+	}
+	top, ok := plans[0].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected plan of type %T", plans[0])
+	}
+	node, ok := top["Plan"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("plan has no top node")
+	}
+	rows, ok := node["Plan Rows"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("plan has no row estimate")
+	}
+	cost, ok := node["Total Cost"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("plan has no cost estimate")
+	}
+	return tools.BudgetEstimate{tools.BudgetRows: rows, tools.BudgetCost: cost}, nil
+}
+
+// Querier is implemented by pools and the connections they pin to sessions.
+type Querier interface {
+	RowQuerier
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// resetTimeout bounds how long a pinned connection is reset before it is
+// given back to its pool.
+const resetTimeout = 5 * time.Second
+
+// pinnedConn is a connection of a pool pinned to a session.
+type pinnedConn struct {
+	conn *pgxpool.Conn
+}
+
+// Close rolls back the open transaction of the connection, if any, and
+// discards its session state, such as temporary tables, before releasing it
+// to its pool. The connection is closed instead if it cannot be reset.
+func (c pinnedConn) Close() {
+	ctx, cancel := context.WithTimeout(context.Background(), resetTimeout)
+	defer cancel()
+	err := func() error {
+		if c.conn.Conn().PgConn().TxStatus() != 'I' {
+			if _, err := c.conn.Exec(ctx, "ROLLBACK"); err != nil {
+				return err
+			}
+		}
+		if _, err := c.conn.Exec(ctx, "DISCARD ALL"); err != nil {
+			return err
+		}
+		// the statements cached by pgx were deallocated by DISCARD ALL
+		return c.conn.Conn().DeallocateAll(ctx)
+	}()
+	if err != nil {
+		_ = c.conn.Hijack().Close(ctx)
+		return
+	}
+	c.conn.Release()
+}
+
+// SessionQuerier returns what the invocation of ctx runs its statements on:
+// the connection of pool pinned to the session of the invocation, if any, or
+// else pool. If pin is set, a connection of pool is pinned to the session
+// unless one already is. release must be called once the results are read.
+func SessionQuerier(ctx context.Context, pool *pgxpool.Pool, source string, pin bool) (q Querier, release func(), err error) {
+	sessions, id := tools.StickySessionFromContext(ctx)
+	if sessions == nil || id == "" {
+		return pool, func() {}, nil
+	}
+	var open func(context.Context) (tools.PinnedConn, error)
+	if pin {
+		open = func(ctx context.Context) (tools.PinnedConn, error) {
+			conn, err := pool.Acquire(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return pinnedConn{conn: conn}, nil
+		}
+	}
+	conn, release, err := sessions.Acquire(ctx, id, source, open)
+	if err != nil {
+		return nil, nil, err
+	}
+	if conn == nil {
+		return pool, release, nil
+	}
+	return conn.(pinnedConn).conn, release, nil
+}
+
+// MaterializedView identifies a materialized view of the database of a pool.
+type MaterializedView struct {
+	Pool   *pgxpool.Pool
+	Schema string
+	Name   string
+}
+
+// refreshes holds when materialized views were last refreshed by Toolbox,
+// keyed by MaterializedView. Postgres does not track it.
+var refreshes sync.Map
+
+// RecordRefresh records that a materialized view was refreshed at a time.
+func RecordRefresh(v MaterializedView, at time.Time) {
+	refreshes.Store(v, at)
+}
+
+// LastRefresh returns when a materialized view was last refreshed by
+// Toolbox, or false if it was not since Toolbox started.
+func LastRefresh(v MaterializedView) (time.Time, bool) {
+	at, ok := refreshes.Load(v)
+	if !ok {
+		return time.Time{}, false
+	}
+	return at.(time.Time), true
+}
@@ -0,0 +1,146 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgrescommon_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/postgres/postgrescommon"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestRowToMap(t *testing.T) {
+	fields := []pgconn.FieldDescription{
+		{Name: "id", DataTypeOID: pgtype.Int8OID},
+		{Name: "name", DataTypeOID: pgtype.TextOID},
+		{Name: "doc", DataTypeOID: pgtype.JSONBOID},
+		{Name: "raw", DataTypeOID: pgtype.JSONOID},
+		{Name: "docs", DataTypeOID: pgtype.JSONBArrayOID},
+		{Name: "missing", DataTypeOID: pgtype.JSONBOID},
+		{Name: "broken", DataTypeOID: pgtype.JSONOID},
+	}
+	values := []any{
+		int64(1),
+		`{"a": 1}`,
+		map[string]any{"a": nil},
+		[]byte(`{"b": [1, null]}`),
+		[]any{[]byte(`{"c": true}`), map[string]any{"d": "e"}},
+		nil,
+		"{broken",
+	}
+	got := postgrescommon.RowToMap(context.Background(), fields, values)
+	want := map[string]any{
+		"id":      int64(1),
+		"name":    `{"a": 1}`,
+		"doc":     map[string]any{"a": nil},
+		"raw":     map[string]any{"b": []any{float64(1), nil}},
+		"docs":    []any{map[string]any{"c": true}, map[string]any{"d": "e"}},
+		"missing": nil,
+		"broken":  "{broken",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("incorrect row: diff %v", diff)
+	}
+}
+
+func TestRowToMapResultTimezone(t *testing.T) {
+	tz, err := tools.NewResultTimezone("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx := tools.WithResultTimezone(context.Background(), tz)
+	fields := []pgconn.FieldDescription{
+		{Name: "created", DataTypeOID: pgtype.TimestamptzOID},
+		{Name: "updated", DataTypeOID: pgtype.TimestampOID},
+		{Name: "birthday", DataTypeOID: pgtype.DateOID},
+		{Name: "deleted", DataTypeOID: pgtype.TimestamptzOID},
+	}
+	birthday := time.Date(2025, 3, 30, 0, 0, 0, 0, time.UTC)
+	values := []any{
+		time.Date(2025, 3, 30, 1, 30, 0, 0, time.UTC),
+		time.Date(2025, 10, 26, 1, 30, 0, 0, time.UTC),
+		birthday,
+		nil,
+	}
+	got := postgrescommon.RowToMap(ctx, fields, values)
+	want := map[string]any{
+		"created":  "2025-03-30T03:30:00+02:00",
+		"updated":  "2025-10-26T02:30:00+01:00",
+		"birthday": birthday,
+		"deleted":  nil,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("incorrect row: diff %v", diff)
+	}
+}
+
+func TestPlanEstimate(t *testing.T) {
+	tcs := []struct {
+		desc    string
+		plan    any
+		want    tools.BudgetEstimate
+		wantErr string
+	}{
+		{
+			desc: "top node",
+			plan: []any{map[string]any{
+				"Plan": map[string]any{
+					"Node Type":  "Hash Join",
+					"Total Cost": 1234.5,
+					"Plan Rows":  float64(80000),
+					"Plans":      []any{map[string]any{"Node Type": "Seq Scan", "Total Cost": 900.0, "Plan Rows": float64(1e6)}},
+				},
+			}},
+			want: tools.BudgetEstimate{tools.BudgetRows: 80000, tools.BudgetCost: 1234.5},
+		},
+		{
+			desc:    "not a plan",
+			plan:    map[string]any{},
+			wantErr: "unexpected plan of type map[string]interface {}",
+		},
+		{
+			desc:    "no top node",
+			plan:    []any{map[string]any{}},
+			wantErr: "plan has no top node",
+		},
+		{
+			desc:    "no rows",
+			plan:    []any{map[string]any{"Plan": map[string]any{"Total Cost": 1.0}}},
+			wantErr: "plan has no row estimate",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := postgrescommon.PlanEstimate(tc.plan)
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Fatalf("unexpected error: got %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect estimate: diff %v", diff)
+			}
+		})
+	}
+}
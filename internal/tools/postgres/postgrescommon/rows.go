@@ -0,0 +1,113 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgrescommon
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// StreamRows returns the rows of a query as a tools.RowIterator, so that they
+// are read as the result is encoded rather than all at once. Each row is
+// converted by RowToMap, and then by convert, if set. The first row is read
+// before returning, so that the errors of the query are returned as they
+// are, and a nil []any is returned if there are no rows. done is called once
+// the rows are closed, with the error of reading them, if any.
+func StreamRows(ctx context.Context, rows pgx.Rows, convert func(map[string]any) error, done func(error)) (any, error) {
+	it := &rowIterator{ctx: ctx, rows: rows, fields: rows.FieldDescriptions(), convert: convert, done: done}
+	if !it.next() {
+		err := it.Err()
+		it.Close()
+		if err != nil {
+			return nil, err
+		}
+		return []any(nil), nil
+	}
+	it.primed = true
+	return it, nil
+}
+
+// rowIterator is a tools.RowIterator over pgx.Rows.
+type rowIterator struct {
+	ctx     context.Context
+	rows    pgx.Rows
+	fields  []pgconn.FieldDescription
+	convert func(map[string]any) error
+	done    func(error)
+
+	// primed is set while the row read by StreamRows is not returned yet.
+	primed bool
+	row    map[string]any
+	err    error
+	closed bool
+}
+
+// validate interface
+var _ tools.RowIterator = &rowIterator{}
+
+func (it *rowIterator) Next() bool {
+	if it.primed {
+		it.primed = false
+		return true
+	}
+	return it.next()
+}
+
+// next reads the next row, and returns false once there are none or reading
+// it failed.
+func (it *rowIterator) next() bool {
+	if it.err != nil || !it.rows.Next() {
+		return false
+	}
+	v, err := it.rows.Values()
+	if err != nil {
+		it.err = fmt.Errorf("unable to parse row: %w", err)
+		return false
+	}
+	it.row = RowToMap(it.ctx, it.fields, v)
+	if it.convert != nil {
+		if err := it.convert(it.row); err != nil {
+			it.err = err
+			return false
+		}
+	}
+	return true
+}
+
+func (it *rowIterator) Row() (any, error) {
+	return it.row, nil
+}
+
+func (it *rowIterator) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.rows.Err()
+}
+
+func (it *rowIterator) Close() {
+	if it.closed {
+		return
+	}
+	it.closed = true
+	it.rows.Close()
+	if it.done != nil {
+		it.done(it.Err())
+	}
+}
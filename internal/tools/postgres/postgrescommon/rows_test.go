@@ -0,0 +1,153 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgrescommon_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/postgres/postgrescommon"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// fakeRows returns values as the rows of a query, and then err.
+type fakeRows struct {
+	pgx.Rows
+	values [][]any
+	err    error
+	pos    int
+	closed bool
+}
+
+func (r *fakeRows) FieldDescriptions() []pgconn.FieldDescription {
+	return []pgconn.FieldDescription{{Name: "id", DataTypeOID: pgtype.Int8OID}}
+}
+
+func (r *fakeRows) Next() bool {
+	if r.closed || r.pos >= len(r.values) {
+		return false
+	}
+	r.pos++
+	return true
+}
+
+func (r *fakeRows) Values() ([]any, error) {
+	return r.values[r.pos-1], nil
+}
+
+func (r *fakeRows) Err() error {
+	if r.pos < len(r.values) {
+		return nil
+	}
+	return r.err
+}
+
+func (r *fakeRows) Close() {
+	r.closed = true
+}
+
+func TestStreamRows(t *testing.T) {
+	ctx := context.Background()
+	values := [][]any{{int64(1)}, {int64(2)}, {int64(3)}}
+
+	t.Run("rows", func(t *testing.T) {
+		rows := &fakeRows{values: values}
+		var done []error
+		res, err := postgrescommon.StreamRows(ctx, rows, nil, func(err error) { done = append(done, err) })
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		it, ok := res.(tools.RowIterator)
+		if !ok {
+			t.Fatalf("expected a tools.RowIterator, got %T", res)
+		}
+		// only the first row is read before the result is encoded
+		if rows.pos != 1 {
+			t.Fatalf("unexpected number of rows read: got %d, want 1", rows.pos)
+		}
+		got, err := tools.CollectRows(it)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		want := []any{map[string]any{"id": int64(1)}, map[string]any{"id": int64(2)}, map[string]any{"id": int64(3)}}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Fatalf("incorrect rows: diff %v", diff)
+		}
+		if !rows.closed || len(done) != 1 || done[0] != nil {
+			t.Fatalf("expected the rows to be closed once without error, got %v", done)
+		}
+	})
+
+	t.Run("no rows", func(t *testing.T) {
+		rows := &fakeRows{}
+		calls := 0
+		res, err := postgrescommon.StreamRows(ctx, rows, nil, func(error) { calls++ })
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if diff := cmp.Diff([]any(nil), res); diff != "" {
+			t.Fatalf("incorrect result: diff %v", diff)
+		}
+		if !rows.closed || calls != 1 {
+			t.Fatalf("expected the rows to be closed once, got %d calls", calls)
+		}
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		queryErr := errors.New("division by zero")
+		rows := &fakeRows{err: queryErr}
+		var done error
+		_, err := postgrescommon.StreamRows(ctx, rows, nil, func(err error) { done = err })
+		if !errors.Is(err, queryErr) || !errors.Is(done, queryErr) {
+			t.Fatalf("expected the query error, got %v and %v", err, done)
+		}
+	})
+
+	t.Run("convert", func(t *testing.T) {
+		rows := &fakeRows{values: values}
+		convert := func(row map[string]any) error {
+			if row["id"] == int64(2) {
+				return fmt.Errorf("invalid row %v", row["id"])
+			}
+			row["id"] = fmt.Sprint(row["id"])
+			return nil
+		}
+		var done error
+		res, err := postgrescommon.StreamRows(ctx, rows, convert, func(err error) { done = err })
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		it := res.(tools.RowIterator)
+		if !it.Next() {
+			t.Fatalf("expected a first row")
+		}
+		if row, _ := it.Row(); !cmp.Equal(map[string]any{"id": "1"}, row) {
+			t.Fatalf("incorrect first row: %v", row)
+		}
+		if it.Next() {
+			t.Fatalf("expected the iteration to stop at the invalid row")
+		}
+		it.Close()
+		if it.Err() == nil || done == nil || done.Error() != "invalid row 2" {
+			t.Fatalf("expected the error of the invalid row, got %v and %v", it.Err(), done)
+		}
+	})
+}
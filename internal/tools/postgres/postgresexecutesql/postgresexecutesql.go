@@ -17,6 +17,7 @@ package postgresexecutesql
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
@@ -24,7 +25,10 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/sources/cloudsqlpg"
 	"github.com/googleapis/genai-toolbox/internal/sources/postgres"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/postgres/postgrescommon"
+	"github.com/googleapis/genai-toolbox/internal/tools/sqlsyntax"
 	"github.com/googleapis/genai-toolbox/internal/util"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -34,6 +38,7 @@ func init() {
 	if !tools.Register(kind, newConfig) {
 		panic(fmt.Sprintf("tool kind %q already registered", kind))
 	}
+	tools.RegisterOutputSchema(kind, tools.RowsOutputSchema)
 }
 
 func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
@@ -61,6 +66,19 @@ type Config struct {
 	Source       string   `yaml:"source" validate:"required"`
 	Description  string   `yaml:"description" validate:"required"`
 	AuthRequired []string `yaml:"authRequired"`
+	// SchemaScope, if set, lists the only schemas statements may reference.
+	SchemaScope []string `yaml:"schemaScope"`
+	// Budget, if set, rejects statements whose plan is estimated over its
+	// limits.
+	Budget *tools.BudgetSpec `yaml:"budget"`
+	// ValidateSyntax, if set, parses statements before executing them.
+	ValidateSyntax bool `yaml:"validateSyntax"`
+	// MaxResultBytes, if set, aborts queries once the rows they return are
+	// over this approximate size.
+	MaxResultBytes int64 `yaml:"maxResultBytes"`
+	// StickySession, if set, pins a connection of the source to the session
+	// of the caller, which runs its statements on it.
+	StickySession bool `yaml:"stickySession"`
 }
 
 // validate interface
@@ -83,20 +101,46 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
 	}
 
+	if cfg.ValidateSyntax {
+		if err := sqlsyntax.CheckSupported(sqlsyntax.Postgres); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.MaxResultBytes < 0 {
+		return nil, fmt.Errorf("maxResultBytes must not be negative, got %d", cfg.MaxResultBytes)
+	}
+
+	schemaScope, err := tools.NewScope(tools.PostgresDialect, "schema", cfg.SchemaScope)
+	if err != nil {
+		return nil, err
+	}
+
+	budget, err := tools.NewBudget(cfg.Budget, tools.BudgetRows, tools.BudgetCost)
+	if err != nil {
+		return nil, err
+	}
+
 	sqlParameter := tools.NewStringParameter("sql", "The sql to execute.")
-	parameters := tools.Parameters{sqlParameter}
+	parameters := append(tools.Parameters{sqlParameter}, budget.Parameters()...)
 
 	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, parameters)
 
 	// finish tool setup
 	t := Tool{
-		Name:         cfg.Name,
-		Kind:         kind,
-		Parameters:   parameters,
-		AuthRequired: cfg.AuthRequired,
-		Pool:         s.PostgresPool(),
-		manifest:     tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
-		mcpManifest:  mcpManifest,
+		Name:           cfg.Name,
+		Kind:           kind,
+		Parameters:     parameters,
+		AuthRequired:   cfg.AuthRequired,
+		ValidateSyntax: cfg.ValidateSyntax,
+		MaxResultBytes: cfg.MaxResultBytes,
+		StickySession:  cfg.StickySession,
+		Source:         cfg.Source,
+		Pool:           s.PostgresPool(),
+		SchemaScope:    schemaScope,
+		Budget:         budget,
+		manifest:       tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:    mcpManifest,
 	}
 	return t, nil
 }
@@ -105,22 +149,35 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 var _ tools.Tool = Tool{}
 
 type Tool struct {
-	Name         string           `yaml:"name"`
-	Kind         string           `yaml:"kind"`
-	AuthRequired []string         `yaml:"authRequired"`
-	Parameters   tools.Parameters `yaml:"parameters"`
+	Name           string           `yaml:"name"`
+	Kind           string           `yaml:"kind"`
+	AuthRequired   []string         `yaml:"authRequired"`
+	Parameters     tools.Parameters `yaml:"parameters"`
+	ValidateSyntax bool             `yaml:"validateSyntax"`
+	MaxResultBytes int64            `yaml:"maxResultBytes"`
+	StickySession  bool             `yaml:"stickySession"`
 
+	Source      string
 	Pool        *pgxpool.Pool
+	SchemaScope *tools.Scope
+	Budget      *tools.Budget
 	manifest    tools.Manifest
 	mcpManifest tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	paramsMap := params.AsMap()
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
 	sql, ok := paramsMap["sql"].(string)
 	if !ok {
 		return nil, fmt.Errorf("unable to get cast %s", paramsMap["sql"])
 	}
+
+	if t.ValidateSyntax {
+		if err := sqlsyntax.Check(ctx, sqlsyntax.Postgres, sql); err != nil {
+			return nil, err
+		}
+	}
+
 	// Log the query executed for debugging.
 	logger, err := util.LoggerFromContext(ctx)
 	if err != nil {
@@ -128,7 +185,92 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	}
 	logger.DebugContext(ctx, fmt.Sprintf("executing `%s` tool query: %s", kind, sql))
 
-	results, err := t.Pool.Query(ctx, sql)
+	q, release, err := postgrescommon.SessionQuerier(ctx, t.Pool, t.Source, t.StickySession)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.SchemaScope != nil {
+		defer release()
+		return t.invokeInScope(ctx, q, sql, inv.Params)
+	}
+	if err := t.enforceBudget(ctx, q, sql, inv.Params); err != nil {
+		release()
+		return nil, err
+	}
+	if t.MaxResultBytes > 0 {
+		defer release()
+		return query(ctx, q, sql, t.MaxResultBytes)
+	}
+	// the rows are read as the result is encoded, so the session is released
+	// once they are closed
+	return stream(ctx, q, sql, release)
+}
+
+// enforceBudget checks sql against the budget of the tool, if any, using q
+// to estimate it.
+func (t Tool) enforceBudget(ctx context.Context, q postgrescommon.RowQuerier, sql string, params tools.ParamValues) error {
+	return t.Budget.Enforce(ctx, t.Name, params, func() (tools.BudgetEstimate, error) {
+		return postgrescommon.Estimate(ctx, q, sql)
+	})
+}
+
+// invokeInScope runs sql with q in a transaction whose search_path is set to the
+// schemas of the scope, once sql is checked not to reference other schemas.
+func (t Tool) invokeInScope(ctx context.Context, q postgrescommon.Querier, sql string, params tools.ParamValues) (any, error) {
+	if err := t.SchemaScope.Check(sql); err != nil {
+		return nil, err
+	}
+	tx, err := q.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	schemas := make([]string, 0, len(t.SchemaScope.Names()))
+	for _, name := range t.SchemaScope.Names() {
+		schemas = append(schemas, pgx.Identifier{name}.Sanitize())
+	}
+	if _, err := tx.Exec(ctx, "SET LOCAL search_path TO "+strings.Join(schemas, ", ")); err != nil {
+		return nil, fmt.Errorf("unable to set search_path: %w", err)
+	}
+	if err := t.enforceBudget(ctx, tx, sql, params); err != nil {
+		return nil, err
+	}
+	out, err := query(ctx, tx, sql, t.MaxResultBytes)
+	if err != nil {
+		return out, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("unable to commit transaction: %w", err)
+	}
+	return out, nil
+}
+
+// querier is implemented by pools and transactions.
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// stream runs sql with q and returns its rows as they are read, calling
+// release once they are closed.
+func stream(ctx context.Context, q querier, sql string, release func()) (any, error) {
+	results, err := q.Query(ctx, sql)
+	if err != nil {
+		release()
+		return nil, fmt.Errorf("unable to execute query: %w", err)
+	}
+	res, err := postgrescommon.StreamRows(ctx, results, nil, func(error) { release() })
+	if err != nil {
+		return err.Error(), fmt.Errorf("unable to execute query: %w", err)
+	}
+	return res, nil
+}
+
+// query runs sql with q and returns its rows, aborting once they are over
+// maxResultBytes, if positive.
+func query(ctx context.Context, q querier, sql string, maxResultBytes int64) (any, error) {
+	results, err := q.Query(ctx, sql)
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute query: %w", err)
 	}
@@ -136,17 +278,17 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 
 	fields := results.FieldDescriptions()
 
+	size := tools.NewResultSizeLimit(maxResultBytes)
 	var out []any
 	for results.Next() {
 		v, err := results.Values()
 		if err != nil {
 			return nil, fmt.Errorf("unable to parse row: %w", err)
 		}
-		vMap := make(map[string]any)
-		for i, f := range fields {
-			vMap[f.Name] = v[i]
+		if err := size.Add(v...); err != nil {
+			return nil, err
 		}
-		out = append(out, vMap)
+		out = append(out, postgrescommon.RowToMap(ctx, fields, v))
 	}
 
 	if err := results.Err(); err != nil {
@@ -21,6 +21,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/tools/postgres/postgresexecutesql"
 )
 
@@ -56,6 +57,93 @@ func TestParseFromYamlExecuteSql(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with validateSyntax",
+			in: `
+			tools:
+				example_tool:
+					kind: postgres-execute-sql
+					source: my-instance
+					description: some description
+					validateSyntax: true
+			`,
+			want: server.ToolConfigs{
+				"example_tool": postgresexecutesql.Config{
+					Name:           "example_tool",
+					Kind:           "postgres-execute-sql",
+					Source:         "my-instance",
+					Description:    "some description",
+					AuthRequired:   []string{},
+					ValidateSyntax: true,
+				},
+			},
+		},
+		{
+			desc: "with schemaScope",
+			in: `
+			tools:
+				example_tool:
+					kind: postgres-execute-sql
+					source: my-instance
+					description: some description
+					schemaScope:
+						- public
+						- sales
+			`,
+			want: server.ToolConfigs{
+				"example_tool": postgresexecutesql.Config{
+					Name:         "example_tool",
+					Kind:         "postgres-execute-sql",
+					Source:       "my-instance",
+					Description:  "some description",
+					AuthRequired: []string{},
+					SchemaScope:  []string{"public", "sales"},
+				},
+			},
+		},
+		{
+			desc: "with budget",
+			in: `
+			tools:
+				example_tool:
+					kind: postgres-execute-sql
+					source: my-instance
+					description: some description
+					budget:
+						maxRows: 5000
+			`,
+			want: server.ToolConfigs{
+				"example_tool": postgresexecutesql.Config{
+					Name:         "example_tool",
+					Kind:         "postgres-execute-sql",
+					Source:       "my-instance",
+					Description:  "some description",
+					AuthRequired: []string{},
+					Budget:       &tools.BudgetSpec{MaxRows: 5000},
+				},
+			},
+		},
+		{
+			desc: "with maxResultBytes",
+			in: `
+			tools:
+				example_tool:
+					kind: postgres-execute-sql
+					source: my-instance
+					description: some description
+					maxResultBytes: 1048576
+			`,
+			want: server.ToolConfigs{
+				"example_tool": postgresexecutesql.Config{
+					Name:           "example_tool",
+					Kind:           "postgres-execute-sql",
+					Source:         "my-instance",
+					Description:    "some description",
+					AuthRequired:   []string{},
+					MaxResultBytes: 1048576,
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
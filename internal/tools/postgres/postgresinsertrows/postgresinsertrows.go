@@ -0,0 +1,336 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgresinsertrows
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/alloydbpg"
+	"github.com/googleapis/genai-toolbox/internal/sources/cloudsqlpg"
+	"github.com/googleapis/genai-toolbox/internal/sources/postgres"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const kind string = "postgres-insert-rows"
+const rowsKey string = "rows"
+
+const (
+	// defaultMaxRows bounds the rows inserted by an invocation.
+	defaultMaxRows = 1000
+	// insertBatchRows bounds the rows of each INSERT statement of the
+	// fallback path.
+	insertBatchRows = 500
+	// maxBindParams is the most parameters a statement can bind.
+	maxBindParams = 65535
+)
+
+const (
+	onConflictError  = "error"
+	onConflictIgnore = "ignore"
+)
+
+const (
+	methodCopy   = "copy"
+	methodInsert = "insert"
+)
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	PostgresPool() *pgxpool.Pool
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &alloydbpg.Source{}
+var _ compatibleSource = &cloudsqlpg.Source{}
+var _ compatibleSource = &postgres.Source{}
+
+var compatibleSources = [...]string{alloydbpg.SourceKind, cloudsqlpg.SourceKind, postgres.SourceKind}
+
+type Config struct {
+	Name        string `yaml:"name" validate:"required"`
+	Kind        string `yaml:"kind" validate:"required"`
+	Source      string `yaml:"source" validate:"required"`
+	Description string `yaml:"description" validate:"required"`
+	// Table is the table the rows are inserted into, optionally qualified by
+	// its schema.
+	Table string `yaml:"table" validate:"required"`
+	// Columns are the columns the rows may set. Columns a row does not set
+	// are NULL.
+	Columns []string `yaml:"columns" validate:"required"`
+	// MaxRows bounds the rows inserted by an invocation.
+	MaxRows int `yaml:"maxRows"`
+	// OnConflict is "error" (the default) to fail on rows violating a unique
+	// constraint, or "ignore" to skip them.
+	OnConflict   string   `yaml:"onConflict"`
+	AuthRequired []string `yaml:"authRequired"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	if len(cfg.Columns) == 0 {
+		return nil, fmt.Errorf("invalid %q tool: columns must not be empty", kind)
+	}
+	for i, c := range cfg.Columns {
+		if c == "" || slices.Contains(cfg.Columns[:i], c) {
+			return nil, fmt.Errorf("invalid %q tool: columns must be unique and non-empty, got %q", kind, cfg.Columns)
+		}
+	}
+	if cfg.MaxRows < 0 {
+		return nil, fmt.Errorf("invalid %q tool: maxRows must not be negative", kind)
+	}
+	maxRows := cfg.MaxRows
+	if maxRows == 0 {
+		maxRows = defaultMaxRows
+	}
+	onConflict := cfg.OnConflict
+	if onConflict == "" {
+		onConflict = onConflictError
+	}
+	if onConflict != onConflictError && onConflict != onConflictIgnore {
+		return nil, fmt.Errorf("invalid %q tool: onConflict must be %q or %q, got %q", kind, onConflictError, onConflictIgnore, cfg.OnConflict)
+	}
+
+	minRows := 1
+	rowsParameter := tools.NewArrayParameterWithRange(
+		rowsKey,
+		fmt.Sprintf("The rows to insert, at most %d. Each row is an object mapping column names to values, among the columns %s. Columns a row does not set are NULL.", maxRows, strings.Join(cfg.Columns, ", ")),
+		&minRows,
+		&maxRows,
+		tools.NewMapParameter("row", "A row mapping column names to values.", ""),
+	)
+	parameters := tools.Parameters{rowsParameter}
+	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, parameters)
+
+	// finish tool setup
+	t := Tool{
+		Name:         cfg.Name,
+		Kind:         kind,
+		Parameters:   parameters,
+		AuthRequired: cfg.AuthRequired,
+		Pool:         s.PostgresPool(),
+		Table:        pgx.Identifier(strings.Split(cfg.Table, ".")),
+		Columns:      cfg.Columns,
+		OnConflict:   onConflict,
+		manifest:     tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string           `yaml:"name"`
+	Kind         string           `yaml:"kind"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+
+	Pool        *pgxpool.Pool
+	Table       pgx.Identifier
+	Columns     []string
+	OnConflict  string
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+// Result is the result of an invocation.
+type Result struct {
+	// Inserted is the number of rows inserted, which excludes the rows
+	// skipped on conflict.
+	Inserted int64 `json:"inserted"`
+	// Method is "copy" if the rows were loaded with COPY, or "insert" if
+	// they were inserted with INSERT statements.
+	Method string `json:"method"`
+}
+
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	rawRows, ok := inv.Params.AsMap()[rowsKey].([]any)
+	if !ok {
+		return nil, fmt.Errorf("unable to get cast %s", rowsKey)
+	}
+	rows, err := RowValues(t.Columns, rawRows)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.OnConflict == onConflictIgnore {
+		// COPY has no way to skip conflicting rows
+		return t.insert(ctx, rows)
+	}
+	n, err := t.Pool.CopyFrom(ctx, t.Table, t.Columns, pgx.CopyFromRows(rows))
+	if err == nil {
+		return Result{Inserted: n, Method: methodCopy}, nil
+	}
+	if !copyUnsupported(err) {
+		return nil, fmt.Errorf("unable to copy rows: %w", err)
+	}
+	logger, lErr := util.LoggerFromContext(ctx)
+	if lErr == nil {
+		logger.DebugContext(ctx, fmt.Sprintf("unable to copy rows into %s, inserting them instead: %s", t.Table.Sanitize(), err))
+	}
+	return t.insert(ctx, rows)
+}
+
+// copyUnsupported returns whether err, returned by a COPY, shows that the
+// rows cannot be copied into the table but may be inserted, such as into a
+// view, or with values that cannot be encoded in the binary format of COPY.
+// Errors of the data, such as constraint violations, are not retried.
+func copyUnsupported(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		// the error happened on the client, before any row was copied
+		return true
+	}
+	switch pgErr.Code {
+	case "42809", // wrong_object_type, such as a view
+		"0A000": // feature_not_supported
+		return true
+	}
+	return false
+}
+
+// insert inserts rows with batched INSERT statements, in a transaction.
+func (t Tool) insert(ctx context.Context, rows [][]any) (any, error) {
+	batchRows := min(insertBatchRows, maxBindParams/len(t.Columns))
+	var inserted int64
+	err := pgx.BeginFunc(ctx, t.Pool, func(tx pgx.Tx) error {
+		for batch := range slices.Chunk(rows, batchRows) {
+			tag, err := tx.Exec(ctx, InsertStatement(t.Table, t.Columns, len(batch), t.OnConflict == onConflictIgnore), slices.Concat(batch...)...)
+			if err != nil {
+				return err
+			}
+			inserted += tag.RowsAffected()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to insert rows: %w", err)
+	}
+	return Result{Inserted: inserted, Method: methodInsert}, nil
+}
+
+// RowValues returns the values of rows, objects mapping column names to
+// values, in the order of columns. It fails if a row sets another column.
+func RowValues(columns []string, rows []any) ([][]any, error) {
+	values := make([][]any, len(rows))
+	for i, raw := range rows {
+		row, ok := raw.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("row %d is not an object mapping column names to values", i)
+		}
+		for name := range row {
+			if !slices.Contains(columns, name) {
+				return nil, fmt.Errorf("row %d sets unknown column %q, must be one of %q", i, name, columns)
+			}
+		}
+		values[i] = make([]any, len(columns))
+		for j, c := range columns {
+			values[i][j] = row[c]
+		}
+	}
+	return values, nil
+}
+
+// InsertStatement returns the INSERT statement of n rows of columns into
+// table, skipping conflicting rows if ignoreConflicts is true.
+func InsertStatement(table pgx.Identifier, columns []string, n int, ignoreConflicts bool) string {
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		quoted[i] = pgx.Identifier{c}.Sanitize()
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "INSERT INTO %s (%s) VALUES ", table.Sanitize(), strings.Join(quoted, ", "))
+	param := 1
+	for r := range n {
+		if r > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString("(")
+		for c := range columns {
+			if c > 0 {
+				sb.WriteString(", ")
+			}
+			fmt.Fprintf(&sb, "$%d", param)
+			param++
+		}
+		sb.WriteString(")")
+	}
+	if ignoreConflicts {
+		sb.WriteString(" ON CONFLICT DO NOTHING")
+	}
+	return sb.String()
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return false
+}
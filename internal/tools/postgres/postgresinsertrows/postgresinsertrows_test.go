@@ -0,0 +1,177 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgresinsertrows_test
+
+import (
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/postgres"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools/postgres/postgresinsertrows"
+	"github.com/jackc/pgx/v5"
+)
+
+func TestParseFromYamlPostgresInsertRows(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		example_tool:
+			kind: postgres-insert-rows
+			source: my-pg-instance
+			description: some description
+			table: public.flights
+			columns: [id, airline]
+			maxRows: 5000
+			onConflict: ignore
+			authRequired:
+				- my-google-auth-service
+	`
+	want := server.ToolConfigs{
+		"example_tool": postgresinsertrows.Config{
+			Name:         "example_tool",
+			Kind:         "postgres-insert-rows",
+			Source:       "my-pg-instance",
+			Description:  "some description",
+			Table:        "public.flights",
+			Columns:      []string{"id", "airline"},
+			MaxRows:      5000,
+			OnConflict:   "ignore",
+			AuthRequired: []string{"my-google-auth-service"},
+		},
+	}
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	// Parse contents
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	if diff := cmp.Diff(want, got.Tools); diff != "" {
+		t.Fatalf("incorrect parse: diff %v", diff)
+	}
+}
+
+func TestFailInitialize(t *testing.T) {
+	srcs := map[string]sources.Source{"my-pg-instance": &postgres.Source{}}
+	base := postgresinsertrows.Config{
+		Name:        "example_tool",
+		Kind:        "postgres-insert-rows",
+		Source:      "my-pg-instance",
+		Description: "some description",
+		Table:       "flights",
+		Columns:     []string{"id", "airline"},
+	}
+	tcs := []struct {
+		desc    string
+		modify  func(*postgresinsertrows.Config)
+		wantErr string
+	}{
+		{
+			desc:    "no columns",
+			modify:  func(c *postgresinsertrows.Config) { c.Columns = nil },
+			wantErr: "columns must not be empty",
+		},
+		{
+			desc:    "duplicate columns",
+			modify:  func(c *postgresinsertrows.Config) { c.Columns = []string{"id", "id"} },
+			wantErr: "columns must be unique and non-empty",
+		},
+		{
+			desc:    "negative maxRows",
+			modify:  func(c *postgresinsertrows.Config) { c.MaxRows = -1 },
+			wantErr: "maxRows must not be negative",
+		},
+		{
+			desc:    "invalid onConflict",
+			modify:  func(c *postgresinsertrows.Config) { c.OnConflict = "update" },
+			wantErr: `onConflict must be "error" or "ignore", got "update"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg := base
+			tc.modify(&cfg)
+			_, err := cfg.Initialize(srcs)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+
+	tool, err := base.Initialize(srcs)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	rows := make([]any, 1001)
+	for i := range rows {
+		rows[i] = map[string]any{"id": i}
+	}
+	_, err = tool.ParseParams(map[string]any{"rows": rows}, nil)
+	if err == nil || !strings.Contains(err.Error(), "more than the maximum of 1000") {
+		t.Fatalf("expected the rows to exceed the default maxRows, got %v", err)
+	}
+}
+
+func TestRowValues(t *testing.T) {
+	columns := []string{"id", "airline", "departure"}
+	got, err := postgresinsertrows.RowValues(columns, []any{
+		map[string]any{"id": 1, "airline": "CY", "departure": "2025-10-15"},
+		map[string]any{"airline": "UA", "id": 2},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := [][]any{{1, "CY", "2025-10-15"}, {2, "UA", nil}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected values: diff %v", diff)
+	}
+
+	_, err = postgresinsertrows.RowValues(columns, []any{
+		map[string]any{"id": 1},
+		map[string]any{"id": 2, "arline": "UA"},
+	})
+	wantErr := `row 1 sets unknown column "arline", must be one of ["id" "airline" "departure"]`
+	if err == nil || err.Error() != wantErr {
+		t.Fatalf("unexpected error: got %v, want %q", err, wantErr)
+	}
+
+	_, err = postgresinsertrows.RowValues(columns, []any{"CY"})
+	if err == nil || !strings.Contains(err.Error(), "row 0 is not an object") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestInsertStatement(t *testing.T) {
+	table := pgx.Identifier{"public", "flights"}
+	columns := []string{"id", "Airline"}
+	got := postgresinsertrows.InsertStatement(table, columns, 2, false)
+	want := `INSERT INTO "public"."flights" ("id", "Airline") VALUES ($1, $2), ($3, $4)`
+	if got != want {
+		t.Fatalf("unexpected statement: got %q, want %q", got, want)
+	}
+	got = postgresinsertrows.InsertStatement(table, columns, 1, true)
+	want = `INSERT INTO "public"."flights" ("id", "Airline") VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	if got != want {
+		t.Fatalf("unexpected statement: got %q, want %q", got, want)
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/sources/cloudsqlpg"
 	"github.com/googleapis/genai-toolbox/internal/sources/postgres"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/postgres/postgrescommon"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -143,8 +144,8 @@ type Tool struct {
 	mcpManifest  tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	paramsMap := params.AsMap()
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
 
 	newParams, err := tools.GetParams(t.allParams, paramsMap)
 	if err != nil {
@@ -166,11 +167,11 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		if err != nil {
 			return nil, fmt.Errorf("unable to parse row: %w", err)
 		}
-		rowMap := make(map[string]any)
-		for i, field := range fields {
-			rowMap[string(field.Name)] = values[i]
-		}
-		out = append(out, rowMap)
+		out = append(out, postgrescommon.RowToMap(ctx, fields, values))
+	}
+
+	if err := results.Err(); err != nil {
+		return nil, fmt.Errorf("error reading query results: %w", err)
 	}
 
 	return out, nil
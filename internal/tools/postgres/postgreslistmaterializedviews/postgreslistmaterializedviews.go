@@ -0,0 +1,218 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgreslistmaterializedviews
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/alloydbpg"
+	"github.com/googleapis/genai-toolbox/internal/sources/cloudsqlpg"
+	"github.com/googleapis/genai-toolbox/internal/sources/postgres"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/postgres/postgrescommon"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const kind string = "postgres-list-materialized-views"
+
+// definitionExcerptLength is the most characters of the definition of a
+// materialized view returned.
+const definitionExcerptLength = 200
+
+const listMaterializedViewsStatement = `
+	SELECT m.schemaname, m.matviewname, m.matviewowner, m.ispopulated, m.definition,
+		pg_total_relation_size(c.oid) AS size_bytes,
+		pg_size_pretty(pg_total_relation_size(c.oid)) AS size
+	FROM pg_matviews m
+	JOIN pg_namespace n ON n.nspname = m.schemaname
+	JOIN pg_class c ON c.relnamespace = n.oid AND c.relname = m.matviewname
+	WHERE
+		m.schemaname NOT IN ('pg_catalog', 'information_schema')
+		AND ($1::text = '' OR m.schemaname = $1::text)
+	ORDER BY m.schemaname, m.matviewname
+	LIMIT COALESCE($2::int, 50);
+`
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	PostgresPool() *pgxpool.Pool
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &alloydbpg.Source{}
+var _ compatibleSource = &cloudsqlpg.Source{}
+var _ compatibleSource = &postgres.Source{}
+
+var compatibleSources = [...]string{alloydbpg.SourceKind, cloudsqlpg.SourceKind, postgres.SourceKind}
+
+type Config struct {
+	Name         string   `yaml:"name" validate:"required"`
+	Kind         string   `yaml:"kind" validate:"required"`
+	Source       string   `yaml:"source" validate:"required"`
+	Description  string   `yaml:"description"`
+	AuthRequired []string `yaml:"authRequired"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	allParameters := tools.Parameters{
+		tools.NewStringParameterWithDefault("schema", "", "Optional: The schema to list the materialized views of. Defaults to all schemas."),
+		tools.NewIntParameterWithDefault("limit", 50, "Optional: The maximum number of rows to return."),
+	}
+	paramManifest := allParameters.Manifest()
+	description := cfg.Description
+	if description == "" {
+		description = "Lists materialized views in the database from pg_matviews with a default limit of 50 rows. Returns their schema, name, owner, whether they are populated, an excerpt of their definition, their size, and when Toolbox last refreshed them."
+	}
+	mcpManifest := tools.GetMcpManifest(cfg.Name, description, cfg.AuthRequired, allParameters)
+
+	// finish tool setup
+	return Tool{
+		name:         cfg.Name,
+		kind:         kind,
+		authRequired: cfg.AuthRequired,
+		allParams:    allParameters,
+		pool:         s.PostgresPool(),
+		manifest: tools.Manifest{
+			Description:  cfg.Description,
+			Parameters:   paramManifest,
+			AuthRequired: cfg.AuthRequired,
+		},
+		mcpManifest: mcpManifest,
+	}, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	name         string           `yaml:"name"`
+	kind         string           `yaml:"kind"`
+	authRequired []string         `yaml:"authRequired"`
+	allParams    tools.Parameters `yaml:"allParams"`
+	pool         *pgxpool.Pool
+	manifest     tools.Manifest
+	mcpManifest  tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
+
+	newParams, err := tools.GetParams(t.allParams, paramsMap)
+	if err != nil {
+		return nil, fmt.Errorf("unable to extract standard params %w", err)
+	}
+	sliceParams := newParams.AsSlice()
+
+	results, err := t.pool.Query(ctx, listMaterializedViewsStatement, sliceParams...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute query: %w", err)
+	}
+	defer results.Close()
+
+	fields := results.FieldDescriptions()
+	var out []map[string]any
+
+	for results.Next() {
+		values, err := results.Values()
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse row: %w", err)
+		}
+		row := postgrescommon.RowToMap(ctx, fields, values)
+		if definition, ok := row["definition"].(string); ok {
+			row["definition"] = DefinitionExcerpt(definition)
+		}
+		// Postgres does not track when a materialized view was refreshed
+		row["last_refreshed"] = nil
+		schema, _ := row["schemaname"].(string)
+		name, _ := row["matviewname"].(string)
+		if at, ok := postgrescommon.LastRefresh(postgrescommon.MaterializedView{Pool: t.pool, Schema: schema, Name: name}); ok {
+			row["last_refreshed"] = tools.ConvertTimestamp(ctx, at)
+		}
+		out = append(out, row)
+	}
+
+	if err := results.Err(); err != nil {
+		return nil, fmt.Errorf("error reading query results: %w", err)
+	}
+
+	return out, nil
+}
+
+// DefinitionExcerpt returns the first definitionExcerptLength characters of
+// the definition of a materialized view, with its whitespace collapsed.
+func DefinitionExcerpt(definition string) string {
+	excerpt := strings.Join(strings.Fields(definition), " ")
+	runes := []rune(excerpt)
+	if len(runes) <= definitionExcerptLength {
+		return excerpt
+	}
+	return string(runes[:definitionExcerptLength]) + "..."
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.allParams, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.authRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return false
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgreslistmaterializedviews_test
+
+import (
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools/postgres/postgreslistmaterializedviews"
+)
+
+func TestParseFromYamlPostgresListMaterializedViews(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				example_tool:
+					kind: postgres-list-materialized-views
+					source: my-postgres-instance
+					description: some description
+					authRequired:
+						- my-google-auth-service
+			`,
+			want: server.ToolConfigs{
+				"example_tool": postgreslistmaterializedviews.Config{
+					Name:         "example_tool",
+					Kind:         "postgres-list-materialized-views",
+					Source:       "my-postgres-instance",
+					Description:  "some description",
+					AuthRequired: []string{"my-google-auth-service"},
+				},
+			},
+		},
+		{
+			desc: "no description",
+			in: `
+			tools:
+				example_tool:
+					kind: postgres-list-materialized-views
+					source: my-postgres-instance
+			`,
+			want: server.ToolConfigs{
+				"example_tool": postgreslistmaterializedviews.Config{
+					Name:         "example_tool",
+					Kind:         "postgres-list-materialized-views",
+					Source:       "my-postgres-instance",
+					AuthRequired: []string{},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestDefinitionExcerpt(t *testing.T) {
+	got := postgreslistmaterializedviews.DefinitionExcerpt(" SELECT day,\n    sum(amount) AS total\n   FROM sales\n  GROUP BY day;")
+	want := "SELECT day, sum(amount) AS total FROM sales GROUP BY day;"
+	if got != want {
+		t.Fatalf("unexpected excerpt: got %q, want %q", got, want)
+	}
+
+	long := " SELECT " + strings.Repeat("é, ", 100)
+	got = postgreslistmaterializedviews.DefinitionExcerpt(long)
+	want = "SELECT " + strings.Repeat("é, ", 64) + "é..."
+	if got != want {
+		t.Fatalf("unexpected excerpt: got %q, want %q", got, want)
+	}
+}
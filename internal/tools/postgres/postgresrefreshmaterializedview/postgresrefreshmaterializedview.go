@@ -0,0 +1,279 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgresrefreshmaterializedview
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/alloydbpg"
+	"github.com/googleapis/genai-toolbox/internal/sources/cloudsqlpg"
+	"github.com/googleapis/genai-toolbox/internal/sources/postgres"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/postgres/postgrescommon"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const kind string = "postgres-refresh-materialized-view"
+
+const (
+	viewKey         = "view"
+	concurrentlyKey = "concurrently"
+)
+
+// viewStatement returns the schema and the name of a materialized view,
+// whether it is populated, and whether it has a unique index that
+// REFRESH MATERIALIZED VIEW CONCURRENTLY can use: a valid one on columns
+// only, without a WHERE clause.
+const viewStatement = `
+	SELECT n.nspname, c.relname, c.relispopulated,
+		EXISTS (
+			SELECT 1 FROM pg_index i
+			WHERE i.indrelid = c.oid AND i.indisunique AND i.indisvalid
+				AND i.indpred IS NULL AND i.indexprs IS NULL
+		)
+	FROM pg_class c
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	WHERE c.oid = to_regclass($1) AND c.relkind = 'm'
+`
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	PostgresPool() *pgxpool.Pool
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &alloydbpg.Source{}
+var _ compatibleSource = &cloudsqlpg.Source{}
+var _ compatibleSource = &postgres.Source{}
+
+var compatibleSources = [...]string{alloydbpg.SourceKind, cloudsqlpg.SourceKind, postgres.SourceKind}
+
+type Config struct {
+	Name        string `yaml:"name" validate:"required"`
+	Kind        string `yaml:"kind" validate:"required"`
+	Source      string `yaml:"source" validate:"required"`
+	Description string `yaml:"description" validate:"required"`
+	// MaterializedViews are the materialized views that may be refreshed,
+	// optionally qualified by their schema.
+	MaterializedViews []string `yaml:"materializedViews" validate:"required"`
+	AuthRequired      []string `yaml:"authRequired"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	if len(cfg.MaterializedViews) == 0 {
+		return nil, fmt.Errorf("%q tool %q must allow at least one materialized view", kind, cfg.Name)
+	}
+	for _, v := range cfg.MaterializedViews {
+		parts := strings.Split(v, ".")
+		if len(parts) > 2 || slices.Contains(parts, "") {
+			return nil, fmt.Errorf("invalid %q tool: materialized view %q must be a name, optionally qualified by its schema", kind, v)
+		}
+	}
+
+	parameters := tools.Parameters{
+		tools.NewStringParameter(viewKey, fmt.Sprintf("The materialized view to refresh. Must be one of: %s.", strings.Join(cfg.MaterializedViews, ", "))),
+		tools.NewBooleanParameterWithDefault(concurrentlyKey, false, "Whether to refresh the materialized view without locking out concurrent reads. It requires a unique index on the view, and is slower."),
+	}
+	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, parameters)
+
+	// finish tool setup
+	t := Tool{
+		Name:              cfg.Name,
+		Kind:              kind,
+		Parameters:        parameters,
+		AuthRequired:      cfg.AuthRequired,
+		MaterializedViews: cfg.MaterializedViews,
+		Pool:              s.PostgresPool(),
+		manifest:          tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:       mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string           `yaml:"name"`
+	Kind         string           `yaml:"kind"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+
+	MaterializedViews []string
+	Pool              *pgxpool.Pool
+	manifest          tools.Manifest
+	mcpManifest       tools.McpManifest
+}
+
+// Result is the result of an invocation.
+type Result struct {
+	// View is the refreshed materialized view, qualified by its schema.
+	View         string `json:"view"`
+	Concurrently bool   `json:"concurrently"`
+	// Duration is how long the refresh took.
+	Duration string `json:"duration"`
+	// Rows is the number of rows of the view once refreshed.
+	Rows int64 `json:"rows"`
+}
+
+// refreshing holds a channel per MaterializedView being refreshed, so that
+// the refreshes of a view are serialized rather than failing or deadlocking
+// on each other's locks.
+var refreshing sync.Map
+
+// lock waits for the refreshes of v in progress to finish. It returns the
+// function releasing the lock.
+func lock(ctx context.Context, v postgrescommon.MaterializedView) (func(), error) {
+	ch, _ := refreshing.LoadOrStore(v, make(chan struct{}, 1))
+	sem := ch.(chan struct{})
+	select {
+	case sem <- struct{}{}:
+		return func() { <-sem }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("gave up waiting for another refresh of %q to finish: %w", v.Schema+"."+v.Name, ctx.Err())
+	}
+}
+
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
+	view, err := t.view(paramsMap)
+	if err != nil {
+		return nil, err
+	}
+	concurrently, _ := paramsMap[concurrentlyKey].(bool)
+
+	v := postgrescommon.MaterializedView{Pool: t.Pool}
+	var populated, hasUniqueIndex bool
+	err = t.Pool.QueryRow(ctx, viewStatement, view.Sanitize()).Scan(&v.Schema, &v.Name, &populated, &hasUniqueIndex)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("materialized view %q does not exist", strings.Join(view, "."))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to look up materialized view %q: %w", strings.Join(view, "."), err)
+	}
+	if concurrently && !hasUniqueIndex {
+		return nil, fmt.Errorf("materialized view %q cannot be refreshed concurrently: it has no unique index on columns only, without a WHERE clause", strings.Join(view, "."))
+	}
+	if concurrently && !populated {
+		return nil, fmt.Errorf("materialized view %q cannot be refreshed concurrently: it is not populated yet", strings.Join(view, "."))
+	}
+
+	unlock, err := lock(ctx, v)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	qualified := pgx.Identifier{v.Schema, v.Name}.Sanitize()
+	start := time.Now()
+	if _, err := t.Pool.Exec(ctx, RefreshStatement(qualified, concurrently)); err != nil {
+		return nil, fmt.Errorf("unable to refresh materialized view %q: %w", v.Schema+"."+v.Name, err)
+	}
+	elapsed := time.Since(start)
+	postgrescommon.RecordRefresh(v, time.Now())
+
+	var rows int64
+	if err := t.Pool.QueryRow(ctx, "SELECT count(*) FROM "+qualified).Scan(&rows); err != nil {
+		return nil, fmt.Errorf("unable to count the rows of materialized view %q: %w", v.Schema+"."+v.Name, err)
+	}
+	return Result{
+		View:         v.Schema + "." + v.Name,
+		Concurrently: concurrently,
+		Duration:     elapsed.Round(time.Millisecond).String(),
+		Rows:         rows,
+	}, nil
+}
+
+// view returns the materialized view to refresh, if it is allowed.
+func (t Tool) view(paramsMap map[string]any) (pgx.Identifier, error) {
+	view, ok := paramsMap[viewKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected a string", viewKey)
+	}
+	if !slices.Contains(t.MaterializedViews, view) {
+		return nil, fmt.Errorf("materialized view %q is not allowed; must be one of %q", view, t.MaterializedViews)
+	}
+	return pgx.Identifier(strings.Split(view, ".")), nil
+}
+
+// RefreshStatement returns the statement refreshing the materialized view
+// quoted.
+func RefreshStatement(quoted string, concurrently bool) string {
+	if concurrently {
+		return "REFRESH MATERIALIZED VIEW CONCURRENTLY " + quoted
+	}
+	return "REFRESH MATERIALIZED VIEW " + quoted
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return false
+}
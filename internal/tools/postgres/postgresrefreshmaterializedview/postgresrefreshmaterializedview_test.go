@@ -0,0 +1,170 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgresrefreshmaterializedview_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/postgres"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/postgres/postgresrefreshmaterializedview"
+)
+
+func TestParseFromYamlPostgresRefreshMaterializedView(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		example_tool:
+			kind: postgres-refresh-materialized-view
+			source: my-pg-instance
+			description: some description
+			materializedViews:
+				- public.daily_sales
+				- monthly_sales
+			authRequired:
+				- my-google-auth-service
+	`
+	want := server.ToolConfigs{
+		"example_tool": postgresrefreshmaterializedview.Config{
+			Name:              "example_tool",
+			Kind:              "postgres-refresh-materialized-view",
+			Source:            "my-pg-instance",
+			Description:       "some description",
+			MaterializedViews: []string{"public.daily_sales", "monthly_sales"},
+			AuthRequired:      []string{"my-google-auth-service"},
+		},
+	}
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	// Parse contents
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	if diff := cmp.Diff(want, got.Tools); diff != "" {
+		t.Fatalf("incorrect parse: diff %v", diff)
+	}
+}
+
+func TestFailInitialize(t *testing.T) {
+	srcs := map[string]sources.Source{"my-pg-instance": &postgres.Source{}}
+	tcs := []struct {
+		desc    string
+		views   []string
+		wantErr string
+	}{
+		{
+			desc:    "no views",
+			views:   []string{},
+			wantErr: "must allow at least one materialized view",
+		},
+		{
+			desc:    "too qualified",
+			views:   []string{"public.daily_sales", "db.public.monthly_sales"},
+			wantErr: `materialized view "db.public.monthly_sales" must be a name, optionally qualified by its schema`,
+		},
+		{
+			desc:    "empty schema",
+			views:   []string{".daily_sales"},
+			wantErr: `materialized view ".daily_sales" must be a name`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg := postgresrefreshmaterializedview.Config{
+				Name:              "example_tool",
+				Kind:              "postgres-refresh-materialized-view",
+				Source:            "my-pg-instance",
+				Description:       "some description",
+				MaterializedViews: tc.views,
+			}
+			_, err := cfg.Initialize(srcs)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestInvokeAllowList(t *testing.T) {
+	srcs := map[string]sources.Source{"my-pg-instance": &postgres.Source{}}
+	cfg := postgresrefreshmaterializedview.Config{
+		Name:              "example_tool",
+		Kind:              "postgres-refresh-materialized-view",
+		Source:            "my-pg-instance",
+		Description:       "some description",
+		MaterializedViews: []string{"public.daily_sales", "monthly_sales"},
+	}
+	tool, err := cfg.Initialize(srcs)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc    string
+		view    string
+		wantErr string
+	}{
+		{
+			desc:    "other view",
+			view:    "public.users_summary",
+			wantErr: `materialized view "public.users_summary" is not allowed; must be one of ["public.daily_sales" "monthly_sales"]`,
+		},
+		{
+			desc:    "differently qualified",
+			view:    "daily_sales",
+			wantErr: `materialized view "daily_sales" is not allowed`,
+		},
+		{
+			desc:    "injection",
+			view:    "monthly_sales; DROP TABLE users",
+			wantErr: `materialized view "monthly_sales; DROP TABLE users" is not allowed`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			params, err := tool.ParseParams(map[string]any{"view": tc.view}, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			_, err = tool.Invoke(context.Background(), tools.Invocation{Params: params})
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestRefreshStatement(t *testing.T) {
+	got := postgresrefreshmaterializedview.RefreshStatement(`"public"."daily_sales"`, false)
+	want := `REFRESH MATERIALIZED VIEW "public"."daily_sales"`
+	if got != want {
+		t.Fatalf("unexpected statement: got %q, want %q", got, want)
+	}
+	got = postgresrefreshmaterializedview.RefreshStatement(`"public"."daily_sales"`, true)
+	want = `REFRESH MATERIALIZED VIEW CONCURRENTLY "public"."daily_sales"`
+	if got != want {
+		t.Fatalf("unexpected statement: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,355 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgressampletable
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/alloydbpg"
+	"github.com/googleapis/genai-toolbox/internal/sources/cloudsqlpg"
+	"github.com/googleapis/genai-toolbox/internal/sources/postgres"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/postgres/postgrescommon"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const kind string = "postgres-sample-table"
+
+const (
+	// strategyLimit returns the first rows of the table.
+	strategyLimit = "limit"
+	// strategyOrderByRandom shuffles every row of the table.
+	strategyOrderByRandom = "orderByRandom"
+	// strategyTableSample shuffles the rows of random pages of the table.
+	strategyTableSample = "tablesample"
+)
+
+const (
+	// smallTableRows is the most rows of a table shuffled whole for a random
+	// sample.
+	smallTableRows = 10000
+	// sampleOversampling is how many more rows than the sample size the pages
+	// read by TABLESAMPLE are expected to hold, so that the sample is rarely
+	// short.
+	sampleOversampling = 3
+	// minSamplePages is the fewest pages read by TABLESAMPLE, so that a
+	// sample is not drawn from a handful of neighbouring rows.
+	minSamplePages = 32
+)
+
+// tableStatement returns the schema, the name, the estimated rows, negative
+// if unknown, and the pages of a table, a materialized view or a partitioned
+// table. The pages of a partitioned table are those of its partitions.
+const tableStatement = `
+	SELECT n.nspname, c.relname, c.reltuples::float8, c.relpages::float8,
+		(CASE c.relkind
+			WHEN 'p' THEN (SELECT COALESCE(sum(pg_relation_size(pt.relid)), 0) FROM pg_partition_tree(c.oid) pt)
+			ELSE pg_relation_size(c.oid)
+		END / current_setting('block_size')::int)::float8
+	FROM pg_class c
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	WHERE c.oid = to_regclass($1) AND c.relkind IN ('r', 'p', 'm')
+`
+
+// statsStatement returns the fraction of NULL values and the number of
+// distinct values estimated by ANALYZE for the columns of a table. A negative
+// number of distinct values is a fraction of the rows of the table.
+const statsStatement = `
+	SELECT DISTINCT ON (attname) attname, null_frac::float8, n_distinct::float8
+	FROM pg_stats
+	WHERE schemaname = $1 AND tablename = $2
+	ORDER BY attname, inherited DESC
+`
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	PostgresPool() *pgxpool.Pool
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &alloydbpg.Source{}
+var _ compatibleSource = &cloudsqlpg.Source{}
+var _ compatibleSource = &postgres.Source{}
+
+var compatibleSources = [...]string{alloydbpg.SourceKind, cloudsqlpg.SourceKind, postgres.SourceKind}
+
+type Config struct {
+	Name         string   `yaml:"name" validate:"required"`
+	Kind         string   `yaml:"kind" validate:"required"`
+	Source       string   `yaml:"source" validate:"required"`
+	Description  string   `yaml:"description" validate:"required"`
+	AuthRequired []string `yaml:"authRequired"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	parameters := tools.SampleTableParameters()
+	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, parameters)
+
+	// finish tool setup
+	t := Tool{
+		Name:         cfg.Name,
+		Kind:         kind,
+		Parameters:   parameters,
+		AuthRequired: cfg.AuthRequired,
+		Pool:         s.PostgresPool(),
+		manifest:     tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string           `yaml:"name"`
+	Kind         string           `yaml:"kind"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+
+	Pool        *pgxpool.Pool
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
+	table, ok := paramsMap["table"].(string)
+	if !ok {
+		return nil, fmt.Errorf("unable to get cast %s", paramsMap["table"])
+	}
+	size, ok := paramsMap["sample_size"].(int)
+	if !ok {
+		return nil, fmt.Errorf("unable to get cast %s", paramsMap["sample_size"])
+	}
+	method, ok := paramsMap["method"].(string)
+	if !ok {
+		return nil, fmt.Errorf("unable to get cast %s", paramsMap["method"])
+	}
+	schema, name, err := tools.ParseSampleTable(table)
+	if err != nil {
+		return nil, err
+	}
+	identifier := pgx.Identifier{name}
+	if schema != "" {
+		identifier = pgx.Identifier{schema, name}
+	}
+
+	var rows, relPages, pages float64
+	err = t.Pool.QueryRow(ctx, tableStatement, identifier.Sanitize()).Scan(&schema, &name, &rows, &relPages, &pages)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("table %q does not exist", table)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to look up table %q: %w", table, err)
+	}
+	if rows <= 0 && relPages == 0 {
+		// never analyzed, or empty
+		rows = -1
+	}
+
+	strategy := strategyLimit
+	var percent float64
+	if method == tools.SampleMethodRandom {
+		strategy, percent = randomStrategy(size, rows, pages)
+	}
+	quoted := pgx.Identifier{schema, name}.Sanitize()
+	results, err := t.Pool.Query(ctx, SampleStatement(quoted, strategy, percent), size)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sample table %q: %w", table, err)
+	}
+	defer results.Close()
+
+	fields := results.FieldDescriptions()
+	sampled := make([]map[string]any, 0, size)
+	for results.Next() {
+		values, err := results.Values()
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse row: %w", err)
+		}
+		sampled = append(sampled, postgrescommon.RowToMap(ctx, fields, values))
+	}
+	if err := results.Err(); err != nil {
+		return nil, fmt.Errorf("unable to sample table %q: %w", table, err)
+	}
+
+	stats, err := t.stats(ctx, schema, name, rows)
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]tools.SampleColumn, len(fields))
+	for i, f := range fields {
+		columns[i] = tools.SampleColumn{Name: f.Name, Ordered: isOrdered(f)}
+	}
+
+	result := tools.SampleResult{
+		Table:    schema + "." + name,
+		Method:   method,
+		Strategy: strategy,
+		Rows:     tools.SampleRows(sampled),
+		Columns:  tools.SummarizeSample(columns, sampled, stats),
+	}
+	if rows >= 0 {
+		estimated := int64(rows)
+		result.EstimatedRows = &estimated
+	}
+	return result, nil
+}
+
+// stats returns the statistics of the columns of a table of an estimated
+// rows rows, negative if unknown. Columns that were never analyzed have
+// none.
+func (t Tool) stats(ctx context.Context, schema, name string, rows float64) (map[string]tools.ColumnStatistics, error) {
+	results, err := t.Pool.Query(ctx, statsStatement, schema, name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read statistics: %w", err)
+	}
+	defer results.Close()
+
+	stats := make(map[string]tools.ColumnStatistics)
+	for results.Next() {
+		var column string
+		var nullFraction, nDistinct float64
+		if err := results.Scan(&column, &nullFraction, &nDistinct); err != nil {
+			return nil, fmt.Errorf("unable to parse row: %w", err)
+		}
+		s := tools.ColumnStatistics{NullFraction: &nullFraction}
+		if distinct, ok := DistinctCount(nDistinct, rows); ok {
+			s.DistinctCount = &distinct
+		}
+		stats[column] = s
+	}
+	if err := results.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read statistics: %w", err)
+	}
+	return stats, nil
+}
+
+// DistinctCount returns the number of distinct values of a column from the
+// n_distinct of pg_stats, which is a fraction of the rows of the table when
+// negative. It returns false if the fraction cannot be converted since the
+// rows, negative, are unknown.
+func DistinctCount(nDistinct, rows float64) (float64, bool) {
+	if nDistinct >= 0 {
+		return nDistinct, true
+	}
+	if rows < 0 {
+		return 0, false
+	}
+	return -nDistinct * rows, true
+}
+
+// randomStrategy returns how size random rows are sampled from a table of an
+// estimated rows rows, negative if unknown, stored in pages pages, and the
+// percentage of its pages that TABLESAMPLE reads. Small tables are shuffled
+// whole; larger ones are sampled by page, so that no more than a bounded
+// number of pages is read.
+func randomStrategy(size int, rows, pages float64) (string, float64) {
+	if pages <= minSamplePages || (rows >= 0 && rows <= smallTableRows) {
+		return strategyOrderByRandom, 0
+	}
+	rowsPerPage := 1.0
+	if rows > 0 {
+		rowsPerPage = max(rows/pages, 1)
+	}
+	samplePages := max(float64(size*sampleOversampling)/rowsPerPage, minSamplePages)
+	return strategyTableSample, min(100*samplePages/pages, 100)
+}
+
+// SampleStatement returns the statement sampling the table quoted with
+// strategy, reading percent of its pages for TABLESAMPLE. The sample size is
+// its only parameter.
+func SampleStatement(quoted, strategy string, percent float64) string {
+	switch strategy {
+	case strategyOrderByRandom:
+		return "SELECT * FROM " + quoted + " ORDER BY random() LIMIT $1"
+	case strategyTableSample:
+		return "SELECT * FROM " + quoted + " TABLESAMPLE SYSTEM (" + strconv.FormatFloat(percent, 'f', -1, 64) + ") ORDER BY random() LIMIT $1"
+	default:
+		return "SELECT * FROM " + quoted + " LIMIT $1"
+	}
+}
+
+// isOrdered returns whether the column described by f is numeric or holds
+// timestamps or dates, whose minimum and maximum are summarized.
+func isOrdered(f pgconn.FieldDescription) bool {
+	switch f.DataTypeOID {
+	case pgtype.Int2OID, pgtype.Int4OID, pgtype.Int8OID, pgtype.Float4OID, pgtype.Float8OID, pgtype.NumericOID,
+		pgtype.DateOID, pgtype.TimestampOID, pgtype.TimestamptzOID:
+		return true
+	default:
+		return false
+	}
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return false
+}
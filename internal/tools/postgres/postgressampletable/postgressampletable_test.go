@@ -0,0 +1,126 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgressampletable
+
+import (
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+func TestParseFromYamlPostgresSampleTable(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		example_tool:
+			kind: postgres-sample-table
+			source: my-postgres-instance
+			description: some description
+			authRequired:
+				- my-google-auth-service
+	`
+	want := server.ToolConfigs{
+		"example_tool": Config{
+			Name:         "example_tool",
+			Kind:         "postgres-sample-table",
+			Source:       "my-postgres-instance",
+			Description:  "some description",
+			AuthRequired: []string{"my-google-auth-service"},
+		},
+	}
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	if diff := cmp.Diff(want, got.Tools); diff != "" {
+		t.Fatalf("incorrect parse: diff %v", diff)
+	}
+}
+
+func TestRandomStrategy(t *testing.T) {
+	tcs := []struct {
+		desc         string
+		size         int
+		rows         float64
+		pages        float64
+		wantStrategy string
+		wantPercent  float64
+	}{
+		{desc: "small table", size: 10, rows: 5000, pages: 50, wantStrategy: strategyOrderByRandom},
+		{desc: "few pages", size: 10, rows: -1, pages: 20, wantStrategy: strategyOrderByRandom},
+		{desc: "minimum pages", size: 10, rows: 1e6, pages: 10000, wantStrategy: strategyTableSample, wantPercent: 0.32},
+		{desc: "pages for the sample", size: 1000, rows: 1e5, pages: 10000, wantStrategy: strategyTableSample, wantPercent: 3},
+		{desc: "unknown rows", size: 100, rows: -1, pages: 10000, wantStrategy: strategyTableSample, wantPercent: 3},
+		{desc: "whole table", size: 1000, rows: -1, pages: 1000, wantStrategy: strategyTableSample, wantPercent: 100},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			strategy, percent := randomStrategy(tc.size, tc.rows, tc.pages)
+			if strategy != tc.wantStrategy || percent != tc.wantPercent {
+				t.Fatalf("unexpected strategy: got %s %v%%, want %s %v%%", strategy, percent, tc.wantStrategy, tc.wantPercent)
+			}
+		})
+	}
+}
+
+func TestSampleStatement(t *testing.T) {
+	tcs := []struct {
+		strategy string
+		percent  float64
+		want     string
+	}{
+		{strategy: strategyLimit, want: `SELECT * FROM "public"."orders" LIMIT $1`},
+		{strategy: strategyOrderByRandom, want: `SELECT * FROM "public"."orders" ORDER BY random() LIMIT $1`},
+		{strategy: strategyTableSample, percent: 0.32, want: `SELECT * FROM "public"."orders" TABLESAMPLE SYSTEM (0.32) ORDER BY random() LIMIT $1`},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.strategy, func(t *testing.T) {
+			if got := SampleStatement(`"public"."orders"`, tc.strategy, tc.percent); got != tc.want {
+				t.Fatalf("unexpected statement: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDistinctCount(t *testing.T) {
+	tcs := []struct {
+		desc      string
+		nDistinct float64
+		rows      float64
+		want      float64
+		wantOK    bool
+	}{
+		{desc: "count", nDistinct: 42, rows: 1000, want: 42, wantOK: true},
+		{desc: "fraction of the rows", nDistinct: -0.5, rows: 1000, want: 500, wantOK: true},
+		{desc: "unique", nDistinct: -1, rows: 1000, want: 1000, wantOK: true},
+		{desc: "fraction of unknown rows", nDistinct: -0.5, rows: -1},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, ok := DistinctCount(tc.nDistinct, tc.rows)
+			if got != tc.want || ok != tc.wantOK {
+				t.Fatalf("unexpected distinct count: got %v, %t, want %v, %t", got, ok, tc.want, tc.wantOK)
+			}
+		})
+	}
+}
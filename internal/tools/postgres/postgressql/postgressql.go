@@ -17,6 +17,8 @@ package postgressql
 import (
 	"context"
 	"fmt"
+	"slices"
+	"time"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
@@ -24,6 +26,8 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/sources/cloudsqlpg"
 	"github.com/googleapis/genai-toolbox/internal/sources/postgres"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/postgres/postgrescommon"
+	"github.com/googleapis/genai-toolbox/internal/tools/sqlsyntax"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -33,6 +37,7 @@ func init() {
 	if !tools.Register(kind, newConfig) {
 		panic(fmt.Sprintf("tool kind %q already registered", kind))
 	}
+	tools.RegisterOutputSchema(kind, tools.RowsOutputSchema)
 }
 
 func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
@@ -55,14 +60,30 @@ var _ compatibleSource = &postgres.Source{}
 var compatibleSources = [...]string{alloydbpg.SourceKind, cloudsqlpg.SourceKind, postgres.SourceKind}
 
 type Config struct {
-	Name               string           `yaml:"name" validate:"required"`
-	Kind               string           `yaml:"kind" validate:"required"`
-	Source             string           `yaml:"source" validate:"required"`
-	Description        string           `yaml:"description" validate:"required"`
-	Statement          string           `yaml:"statement" validate:"required"`
-	AuthRequired       []string         `yaml:"authRequired"`
-	Parameters         tools.Parameters `yaml:"parameters"`
-	TemplateParameters tools.Parameters `yaml:"templateParameters"`
+	Name               string            `yaml:"name" validate:"required"`
+	Kind               string            `yaml:"kind" validate:"required"`
+	Source             string            `yaml:"source" validate:"required"`
+	Description        string            `yaml:"description" validate:"required"`
+	Statement          string            `yaml:"statement" validate:"required"`
+	AuthRequired       []string          `yaml:"authRequired"`
+	Parameters         tools.Parameters  `yaml:"parameters"`
+	TemplateParameters tools.Parameters  `yaml:"templateParameters"`
+	ColumnTypes        tools.ColumnTypes `yaml:"columnTypes"`
+	LenientCoercion    bool              `yaml:"lenientCoercion"`
+	// SlowQueryExplain, if set, captures the plans of slow invocations.
+	SlowQueryExplain *tools.SlowQueryExplainSpec `yaml:"slowQueryExplain"`
+	// KeysetPagination, if set, returns the rows a page at a time.
+	KeysetPagination *tools.KeysetPaginationSpec `yaml:"keysetPagination"`
+	// DeterministicOrder, if set, returns the rows of statements without an
+	// ORDER BY in a stable order.
+	DeterministicOrder *tools.DeterministicOrderSpec `yaml:"deterministicOrder"`
+	// Budget, if set, rejects queries whose plan is estimated over its limits.
+	Budget *tools.BudgetSpec `yaml:"budget"`
+	// ValidateSyntax, if set, parses the statement before executing it.
+	ValidateSyntax bool `yaml:"validateSyntax"`
+	// StickySession, if set, pins a connection of the source to the session
+	// of the caller, which runs its statements on it.
+	StickySession bool `yaml:"stickySession"`
 }
 
 // validate interface
@@ -72,6 +93,19 @@ func (cfg Config) ToolConfigKind() string {
 	return kind
 }
 
+// validate interface
+var _ tools.ConfigWarner = Config{}
+
+// ConfigWarnings warns when the placeholders of the statement do not match
+// the declared parameters. The driver only reports it when the tool is
+// invoked.
+func (cfg Config) ConfigWarnings() []string {
+	if err := tools.DollarPlaceholders(cfg.Statement).CheckCount(len(cfg.Parameters)); err != nil {
+		return []string{err.Error()}
+	}
+	return nil
+}
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	// verify source exists
 	rawS, ok := srcs[cfg.Source]
@@ -85,13 +119,48 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
 	}
 
-	allParameters, paramManifest, err := tools.ProcessParameters(cfg.TemplateParameters, cfg.Parameters)
+	if cfg.ValidateSyntax {
+		if err := sqlsyntax.CheckSupported(sqlsyntax.Postgres); err != nil {
+			return nil, err
+		}
+	}
+
+	keysetPagination, err := tools.NewKeysetPagination(cfg.KeysetPagination)
+	if err != nil {
+		return nil, err
+	}
+
+	deterministicOrder, err := tools.NewDeterministicOrder(cfg.DeterministicOrder, tools.PostgresDialect)
+	if err != nil {
+		return nil, err
+	}
+	if deterministicOrder != nil && keysetPagination != nil {
+		return nil, fmt.Errorf("deterministicOrder cannot be used with keysetPagination, which orders the rows by its columns")
+	}
+	// statements with template parameters are only checked once resolved
+	if len(cfg.TemplateParameters) == 0 {
+		if err := deterministicOrder.Check(cfg.Statement); err != nil {
+			return nil, err
+		}
+	}
+
+	budget, err := tools.NewBudget(cfg.Budget, tools.BudgetRows, tools.BudgetCost)
+	if err != nil {
+		return nil, err
+	}
+
+	allParameters, paramManifest, err := tools.ProcessParameters(cfg.TemplateParameters, slices.Concat(cfg.Parameters, keysetPagination.Parameters(), budget.Parameters()))
 	if err != nil {
 		return nil, err
 	}
 
 	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, allParameters)
 
+	slowQueryExplain, err := tools.NewSlowQueryExplain(cfg.SlowQueryExplain)
+	if err != nil {
+		return nil, err
+	}
+
 	// finish tool setup
 	t := Tool{
 		Name:               cfg.Name,
@@ -101,7 +170,16 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		AllParams:          allParameters,
 		Statement:          cfg.Statement,
 		AuthRequired:       cfg.AuthRequired,
+		ValidateSyntax:     cfg.ValidateSyntax,
+		StickySession:      cfg.StickySession,
+		Source:             cfg.Source,
 		Pool:               s.PostgresPool(),
+		ColumnTypes:        cfg.ColumnTypes,
+		LenientCoercion:    cfg.LenientCoercion,
+		SlowQueryExplain:   slowQueryExplain,
+		KeysetPagination:   keysetPagination,
+		DeterministicOrder: deterministicOrder,
+		Budget:             budget,
 		manifest:           tools.Manifest{Description: cfg.Description, Parameters: paramManifest, AuthRequired: cfg.AuthRequired},
 		mcpManifest:        mcpManifest,
 	}
@@ -110,37 +188,131 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 // validate interface
 var _ tools.Tool = Tool{}
+var _ tools.SlowQueryTool = Tool{}
+var _ tools.Renderer = Tool{}
 
 type Tool struct {
-	Name               string           `yaml:"name"`
-	Kind               string           `yaml:"kind"`
-	AuthRequired       []string         `yaml:"authRequired"`
-	Parameters         tools.Parameters `yaml:"parameters"`
-	TemplateParameters tools.Parameters `yaml:"templateParameters"`
-	AllParams          tools.Parameters `yaml:"allParams"`
+	Name               string            `yaml:"name"`
+	Kind               string            `yaml:"kind"`
+	AuthRequired       []string          `yaml:"authRequired"`
+	Parameters         tools.Parameters  `yaml:"parameters"`
+	TemplateParameters tools.Parameters  `yaml:"templateParameters"`
+	AllParams          tools.Parameters  `yaml:"allParams"`
+	ColumnTypes        tools.ColumnTypes `yaml:"columnTypes"`
+	LenientCoercion    bool              `yaml:"lenientCoercion"`
+	ValidateSyntax     bool              `yaml:"validateSyntax"`
+	StickySession      bool              `yaml:"stickySession"`
 
-	Pool        *pgxpool.Pool
-	Statement   string
-	manifest    tools.Manifest
-	mcpManifest tools.McpManifest
+	Source             string
+	Pool               *pgxpool.Pool
+	Statement          string
+	SlowQueryExplain   *tools.SlowQueryExplain
+	KeysetPagination   *tools.KeysetPagination
+	DeterministicOrder *tools.DeterministicOrder
+	Budget             *tools.Budget
+	manifest           tools.Manifest
+	mcpManifest        tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	paramsMap := params.AsMap()
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
 	newStatement, err := tools.ResolveTemplateParams(t.TemplateParameters, t.Statement, paramsMap)
 	if err != nil {
 		return nil, fmt.Errorf("unable to extract template params %w", err)
 	}
 
+	if t.ValidateSyntax {
+		if err := sqlsyntax.Check(ctx, sqlsyntax.Postgres, newStatement); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := t.DeterministicOrder.Check(newStatement); err != nil {
+		return nil, err
+	}
+
 	newParams, err := tools.GetParams(t.Parameters, paramsMap)
 	if err != nil {
 		return nil, fmt.Errorf("unable to extract standard params %w", err)
 	}
 	sliceParams := newParams.AsSlice()
-	results, err := t.Pool.Query(ctx, newStatement, sliceParams...)
+	// bind arrays as typed slices so they map to the matching Postgres array type
+	for i, p := range t.Parameters {
+		arrayParam, ok := p.(*tools.ArrayParameter)
+		if !ok {
+			continue
+		}
+		arrayParamValue, ok := sliceParams[i].([]any)
+		if !ok {
+			continue
+		}
+		sliceParams[i], err = tools.ConvertAnySliceToTyped(arrayParamValue, arrayParam.GetItems().GetType())
+		if err != nil {
+			return nil, fmt.Errorf("unable to convert parameter `%s` from []any to typed slice: %w", p.GetName(), err)
+		}
+	}
+	if t.KeysetPagination != nil {
+		newStatement, sliceParams = t.KeysetPagination.Statement(newStatement, sliceParams, t.KeysetPagination.AfterKey(inv.Params), dollarPlaceholder)
+	}
+	q, release, err := postgrescommon.SessionQuerier(ctx, t.Pool, t.Source, t.StickySession)
+	if err != nil {
+		return nil, err
+	}
+	// streamed rows release the session once they are closed
+	streamed := false
+	defer func() {
+		if !streamed {
+			release()
+		}
+	}()
+	statement := newStatement
+	if t.DeterministicOrder.Rewrites(statement) {
+		columns, err := t.DeterministicOrder.Columns(statement, func(columnsStatement string) ([]string, error) {
+			rows, err := q.Query(ctx, columnsStatement, sliceParams...)
+			if err != nil {
+				return nil, err
+			}
+			fields := rows.FieldDescriptions()
+			columns := make([]string, len(fields))
+			for i, f := range fields {
+				columns[i] = f.Name
+			}
+			rows.Close()
+			return columns, rows.Err()
+		})
+		if err != nil {
+			return nil, err
+		}
+		newStatement = t.DeterministicOrder.Statement(statement, columns)
+	}
+	err = t.Budget.Enforce(ctx, t.Name, inv.Params, func() (tools.BudgetEstimate, error) {
+		return postgrescommon.Estimate(ctx, q, newStatement, sliceParams...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	results, err := q.Query(ctx, newStatement, sliceParams...)
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute query: %w", err)
 	}
+	if t.KeysetPagination == nil && !t.DeterministicOrder.Sorts(statement) {
+		streamed = true
+		coerce := func(row map[string]any) error {
+			return t.ColumnTypes.CoerceRow(row, t.LenientCoercion)
+		}
+		res, err := postgrescommon.StreamRows(ctx, results, coerce, func(err error) {
+			if err == nil {
+				t.SlowQueryExplain.Observe(ctx, t.Name, newStatement, sliceParams, inv.Params, time.Since(start), t.explain)
+			}
+			release()
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error reading query results: %w", err)
+		}
+		return res, nil
+	}
+	defer results.Close()
 
 	fields := results.FieldDescriptions()
 
@@ -150,16 +322,52 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		if err != nil {
 			return nil, fmt.Errorf("unable to parse row: %w", err)
 		}
-		vMap := make(map[string]any)
-		for i, f := range fields {
-			vMap[f.Name] = v[i]
+		vMap := postgrescommon.RowToMap(ctx, fields, v)
+		if err := t.ColumnTypes.CoerceRow(vMap, t.LenientCoercion); err != nil {
+			return nil, err
 		}
 		out = append(out, vMap)
 	}
+	if err := results.Err(); err != nil {
+		return nil, fmt.Errorf("error reading query results: %w", err)
+	}
+	t.SlowQueryExplain.Observe(ctx, t.Name, newStatement, sliceParams, inv.Params, time.Since(start), t.explain)
+	t.DeterministicOrder.Sort(statement, out)
 
+	if t.KeysetPagination != nil {
+		return t.KeysetPagination.Page(out)
+	}
 	return out, nil
 }
 
+// dollarPlaceholder returns the placeholder of the n-th parameter bound to a
+// statement.
+func dollarPlaceholder(n int) string {
+	return fmt.Sprintf("$%d", n)
+}
+
+// explain returns the JSON plan of statement when run with params.
+func (t Tool) explain(ctx context.Context, statement string, params []any) (any, error) {
+	var plan any
+	if err := t.Pool.QueryRow(ctx, "EXPLAIN (FORMAT JSON) "+statement, params...).Scan(&plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+func (t Tool) SlowQueries() []tools.SlowQueryPlan {
+	return t.SlowQueryExplain.Plans()
+}
+
+// Render resolves the statement of an invocation without running it.
+func (t Tool) Render(params tools.ParamValues) (tools.RenderedStatement, error) {
+	rendered, err := tools.RenderStatement(t.Statement, t.TemplateParameters, t.Parameters, params)
+	if err != nil || t.KeysetPagination == nil {
+		return rendered, err
+	}
+	return t.KeysetPagination.Render(rendered, params, dollarPlaceholder), nil
+}
+
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
 	return tools.ParseParams(t.AllParams, data, claims)
 }
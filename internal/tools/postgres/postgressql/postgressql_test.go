@@ -74,6 +74,154 @@ func TestParseFromYamlPostgres(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with validateSyntax",
+			in: `
+			tools:
+				example_tool:
+					kind: postgres-sql
+					source: my-instance
+					description: some description
+					statement: SELECT * FROM hotels WHERE id = 1
+					validateSyntax: true
+			`,
+			want: server.ToolConfigs{
+				"example_tool": postgressql.Config{
+					Name:           "example_tool",
+					Kind:           "postgres-sql",
+					Source:         "my-instance",
+					Description:    "some description",
+					Statement:      "SELECT * FROM hotels WHERE id = 1",
+					AuthRequired:   []string{},
+					ValidateSyntax: true,
+				},
+			},
+		},
+		{
+			desc: "with slow query explain",
+			in: `
+			tools:
+				example_tool:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: |
+						SELECT * FROM SQL_STATEMENT;
+					slowQueryExplain:
+						threshold: 500ms
+						maxPlans: 20
+						minInterval: 30s
+			`,
+			want: server.ToolConfigs{
+				"example_tool": postgressql.Config{
+					Name:         "example_tool",
+					Kind:         "postgres-sql",
+					Source:       "my-pg-instance",
+					Description:  "some description",
+					Statement:    "SELECT * FROM SQL_STATEMENT;\n",
+					AuthRequired: []string{},
+					SlowQueryExplain: &tools.SlowQueryExplainSpec{
+						Threshold:   "500ms",
+						MaxPlans:    20,
+						MinInterval: "30s",
+					},
+				},
+			},
+		},
+		{
+			desc: "with keyset pagination",
+			in: `
+			tools:
+				example_tool:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: |
+						SELECT * FROM SQL_STATEMENT;
+					keysetPagination:
+						columns:
+							- name: created_at
+							  descending: true
+							- id
+						pageSize: 50
+			`,
+			want: server.ToolConfigs{
+				"example_tool": postgressql.Config{
+					Name:         "example_tool",
+					Kind:         "postgres-sql",
+					Source:       "my-pg-instance",
+					Description:  "some description",
+					Statement:    "SELECT * FROM SQL_STATEMENT;\n",
+					AuthRequired: []string{},
+					KeysetPagination: &tools.KeysetPaginationSpec{
+						Columns: []tools.KeysetColumn{
+							{Name: "created_at", Descending: true},
+							{Name: "id"},
+						},
+						PageSize: 50,
+					},
+				},
+			},
+		},
+		{
+			desc: "with budget",
+			in: `
+			tools:
+				example_tool:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: |
+						SELECT * FROM SQL_STATEMENT;
+					budget:
+						maxRows: 100000
+						maxCost: 25000.5
+						allowOverride: true
+						skipAuthServices:
+							- my-google-auth-service
+			`,
+			want: server.ToolConfigs{
+				"example_tool": postgressql.Config{
+					Name:         "example_tool",
+					Kind:         "postgres-sql",
+					Source:       "my-pg-instance",
+					Description:  "some description",
+					Statement:    "SELECT * FROM SQL_STATEMENT;\n",
+					AuthRequired: []string{},
+					Budget: &tools.BudgetSpec{
+						MaxRows:          100000,
+						MaxCost:          25000.5,
+						AllowOverride:    true,
+						SkipAuthServices: []string{"my-google-auth-service"},
+					},
+				},
+			},
+		},
+		{
+			desc: "with deterministic order",
+			in: `
+			tools:
+				example_tool:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: |
+						SELECT * FROM SQL_STATEMENT;
+					deterministicOrder:
+						mode: rewrite
+			`,
+			want: server.ToolConfigs{
+				"example_tool": postgressql.Config{
+					Name:               "example_tool",
+					Kind:               "postgres-sql",
+					Source:             "my-pg-instance",
+					Description:        "some description",
+					Statement:          "SELECT * FROM SQL_STATEMENT;\n",
+					AuthRequired:       []string{},
+					DeterministicOrder: &tools.DeterministicOrderSpec{Mode: tools.DeterministicOrderRewrite},
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -0,0 +1,158 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Redacted replaces the values of sensitive parameters.
+const Redacted = "[REDACTED]"
+
+// isSensitive returns whether p is declared `sensitive`.
+func isSensitive(p Parameter) bool {
+	s, ok := p.(interface{ IsSensitive() bool })
+	return ok && s.IsSensitive()
+}
+
+// Redacted returns a copy of p in which the values of sensitive parameters
+// are replaced by Redacted. It is meant for logs.
+func (p ParamValues) Redacted() ParamValues {
+	redacted := make(ParamValues, len(p))
+	for i, v := range p {
+		if v.Sensitive && v.Value != nil {
+			v.Value = Redacted
+		}
+		redacted[i] = v
+	}
+	return redacted
+}
+
+// String formats p for logs, with the values of sensitive parameters
+// redacted.
+func (p ParamValues) String() string {
+	parts := make([]string, len(p))
+	for i, v := range p.Redacted() {
+		parts[i] = fmt.Sprintf("%s=%v", v.Name, v.Value)
+	}
+	return "[" + strings.Join(parts, " ") + "]"
+}
+
+// RedactString replaces the values of sensitive parameters in s, such as an
+// error message quoting a statement, by Redacted.
+func (p ParamValues) RedactString(s string) string {
+	for _, l := range p.sensitiveLiterals() {
+		s = strings.ReplaceAll(s, l, Redacted)
+	}
+	return s
+}
+
+// RedactError returns err if its message contains no value of a sensitive
+// parameter. Otherwise, it returns an error wrapping err whose message has the
+// values replaced by Redacted.
+func (p ParamValues) RedactError(err error) error {
+	if err == nil {
+		return nil
+	}
+	msg := err.Error()
+	if redacted := p.RedactString(msg); redacted != msg {
+		return &redactedError{msg: redacted, err: err}
+	}
+	return err
+}
+
+// RedactArgs returns a copy of args, the arguments bound to a statement, in
+// which the values of sensitive parameters are replaced by Redacted.
+func (p ParamValues) RedactArgs(args []any) []any {
+	var sensitive []string
+	for _, v := range p {
+		if v.Sensitive && v.Value != nil {
+			sensitive = append(sensitive, fmt.Sprint(v.Value))
+		}
+	}
+	redacted := make([]any, len(args))
+	for i, a := range args {
+		// arguments may be converted, such as arrays to typed slices, so
+		// they are compared by their text
+		if a != nil && slices.Contains(sensitive, fmt.Sprint(a)) {
+			a = Redacted
+		}
+		redacted[i] = a
+	}
+	return redacted
+}
+
+// sensitiveLiterals returns the texts of the values of sensitive parameters,
+// longest first so that a value containing another is replaced whole.
+func (p ParamValues) sensitiveLiterals() []string {
+	var literals []string
+	for _, v := range p {
+		if v.Sensitive {
+			literals = appendLiterals(literals, v.Value)
+		}
+	}
+	slices.SortFunc(literals, func(a, b string) int { return len(b) - len(a) })
+	return slices.Compact(literals)
+}
+
+func appendLiterals(literals []string, v any) []string {
+	switch v := v.(type) {
+	case nil:
+		return literals
+	case string:
+		if v == "" {
+			return literals
+		}
+		literals = append(literals, v)
+		// the value may be quoted with escapes
+		if q := strconv.Quote(v); q[1:len(q)-1] != v {
+			literals = append(literals, q[1:len(q)-1])
+		}
+		return literals
+	case []any:
+		for _, e := range v {
+			literals = appendLiterals(literals, e)
+		}
+	case map[string]any:
+		for _, e := range v {
+			literals = appendLiterals(literals, e)
+		}
+	default:
+		literals = append(literals, fmt.Sprint(v))
+	}
+	if b, err := json.Marshal(v); err == nil {
+		literals = append(literals, string(b))
+	}
+	return literals
+}
+
+// redactedError is an error whose message has the values of sensitive
+// parameters redacted.
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
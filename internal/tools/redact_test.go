@@ -0,0 +1,201 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func newSensitiveStringParameter(name, desc string) *tools.StringParameter {
+	p := tools.NewStringParameter(name, desc)
+	p.Sensitive = true
+	return p
+}
+
+func TestSensitiveParameterUnmarshal(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+- name: password
+  type: string
+  description: the password of the user
+  sensitive: true
+- name: email
+  type: string
+  description: the email of the user
+`
+	var got tools.Parameters
+	if err := yaml.UnmarshalContext(ctx, []byte(in), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	want := tools.Parameters{
+		newSensitiveStringParameter("password", "the password of the user"),
+		tools.NewStringParameter("email", "the email of the user"),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("incorrect parse: diff %v", diff)
+	}
+}
+
+func TestSensitiveParameterManifest(t *testing.T) {
+	params := tools.Parameters{
+		newSensitiveStringParameter("password", "the password of the user"),
+		tools.NewStringParameter("email", "the email of the user"),
+	}
+
+	manifest := params.Manifest()
+	if !manifest[0].Sensitive || manifest[1].Sensitive {
+		t.Fatalf("unexpected sensitive flags in manifest: %+v", manifest)
+	}
+
+	schema, _ := params.McpManifest()
+	b, err := json.Marshal(schema.Properties)
+	if err != nil {
+		t.Fatalf("unable to marshal schema: %s", err)
+	}
+	want := `{"email":{"type":"string","description":"the email of the user","examples":["example"]},"password":{"type":"string","description":"the password of the user","sensitive":true}}`
+	if string(b) != want {
+		t.Fatalf("unexpected mcp manifest: got %s, want %s", b, want)
+	}
+}
+
+func TestSensitiveParamValues(t *testing.T) {
+	params := tools.Parameters{
+		newSensitiveStringParameter("password", "the password of the user"),
+		tools.NewStringParameter("email", "the email of the user"),
+	}
+	values, err := tools.ParseParams(params, map[string]any{"password": `hunter"2`, "email": "jane@example.com"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// the values are bound as they are
+	if got := values.AsSlice(); !cmp.Equal(got, []any{`hunter"2`, "jane@example.com"}) {
+		t.Fatalf("unexpected values: %v", got)
+	}
+
+	// logs
+	want := `[password=[REDACTED] email=jane@example.com]`
+	if got := fmt.Sprintf("invocation params: %s", values); got != "invocation params: "+want {
+		t.Fatalf("unexpected log: got %q, want %q", got, want)
+	}
+	if got := fmt.Sprint(values.Redacted()); got != want {
+		t.Fatalf("unexpected redacted values: got %q, want %q", got, want)
+	}
+
+	// error messages quoting the value, as is or escaped
+	msg := `unable to execute query: SELECT * FROM users WHERE email = 'jane@example.com' AND password = 'hunter"2' -- "hunter\"2"`
+	wantMsg := `unable to execute query: SELECT * FROM users WHERE email = 'jane@example.com' AND password = '[REDACTED]' -- "[REDACTED]"`
+	if got := values.RedactString(msg); got != wantMsg {
+		t.Fatalf("unexpected redacted message: got %q, want %q", got, wantMsg)
+	}
+
+	// errors keep wrapping the original error
+	errSentinel := errors.New("sentinel")
+	err = values.RedactError(fmt.Errorf("%s: %w", msg, errSentinel))
+	if err.Error() != wantMsg+": sentinel" {
+		t.Fatalf("unexpected redacted error: got %q", err)
+	}
+	if !errors.Is(err, errSentinel) {
+		t.Fatalf("expected the redacted error to wrap the original error")
+	}
+	if plain := errors.New("no value here"); values.RedactError(plain) != plain {
+		t.Fatalf("expected errors without values to be returned as is")
+	}
+
+	// arguments bound to statements
+	got := values.RedactArgs([]any{`hunter"2`, "jane@example.com"})
+	if diff := cmp.Diff([]any{tools.Redacted, "jane@example.com"}, got); diff != "" {
+		t.Fatalf("unexpected redacted args: diff %v", diff)
+	}
+}
+
+func TestSensitiveParseErrors(t *testing.T) {
+	ssn := tools.NewStringParameterWithAllowedValues("ssn", "a social security number", []any{`^\d{3}-\d{2}-\d{4}$`})
+	ssn.Sensitive = true
+	pin := tools.NewIntParameter("pin", "a pin")
+	pin.Sensitive = true
+	params := tools.Parameters{ssn, pin}
+
+	_, err := tools.ParseParams(params, map[string]any{"ssn": "123-45-678", "pin": "98765"}, nil)
+	if err == nil {
+		t.Fatalf("expected error but Param parsed successfully")
+	}
+	for _, leak := range []string{"123-45-678", "98765"} {
+		if strings.Contains(err.Error(), leak) {
+			t.Fatalf("error leaks %q: %s", leak, err)
+		}
+	}
+	var paramErrs tools.ParamErrors
+	if !errors.As(err, &paramErrs) {
+		t.Fatalf("expected tools.ParamErrors, got %T", err)
+	}
+	b, err := json.Marshal(paramErrs)
+	if err != nil {
+		t.Fatalf("unable to marshal errors: %s", err)
+	}
+	if !strings.Contains(string(b), tools.Redacted) || strings.Contains(string(b), "123-45-678") {
+		t.Fatalf("unexpected error data: %s", b)
+	}
+}
+
+func TestSlowQueryExplainRedactsSensitiveParams(t *testing.T) {
+	s, err := tools.NewSlowQueryExplain(&tools.SlowQueryExplainSpec{Threshold: "100ms", MinInterval: "0s"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	values := tools.ParamValues{
+		{Name: "email", Value: "jane@example.com"},
+		{Name: "tokens", Value: []any{"a1b2", "c3d4"}, Sensitive: true},
+	}
+	var explained []any
+	explain := func(_ context.Context, statement string, params []any) (any, error) {
+		explained = params
+		return nil, fmt.Errorf("no token in (%s, %s)", "a1b2", "c3d4")
+	}
+	args := []any{"jane@example.com", []string{"a1b2", "c3d4"}}
+	s.Observe(context.Background(), "my-tool", "SELECT 1 -- a1b2", args, values, time.Second, explain)
+	s.Wait()
+
+	// the EXPLAIN runs with the actual values
+	if diff := cmp.Diff(args, explained); diff != "" {
+		t.Fatalf("unexpected explained params: diff %v", diff)
+	}
+	got := s.Plans()
+	got[0].CapturedAt = time.Time{}
+	want := []tools.SlowQueryPlan{{
+		Tool:      "my-tool",
+		Statement: "SELECT 1 -- [REDACTED]",
+		Params:    []any{"jane@example.com", tools.Redacted},
+		Duration:  "1s",
+		Error:     "no token in ([REDACTED], [REDACTED])",
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected plans: diff %v", diff)
+	}
+}
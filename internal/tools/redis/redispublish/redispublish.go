@@ -0,0 +1,219 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package redispublish
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	redissrc "github.com/googleapis/genai-toolbox/internal/sources/redis"
+	valkeysrc "github.com/googleapis/genai-toolbox/internal/sources/valkey"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/valkey-io/valkey-go"
+)
+
+const kind string = "redis-publish"
+
+const (
+	channelKey = "channel"
+	messageKey = "message"
+)
+
+// defaultMaxMessageSize is the message size limit, in bytes, used when
+// `maxMessageSize` is not configured.
+const defaultMaxMessageSize = 64 * 1024
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type redisSource interface {
+	RedisClient() redissrc.RedisClient
+}
+
+type valkeySource interface {
+	ValkeyClient() valkey.Client
+}
+
+// validate compatible sources are still compatible
+var (
+	_ redisSource  = &redissrc.Source{}
+	_ valkeySource = &valkeysrc.Source{}
+)
+
+var compatibleSources = [...]string{redissrc.SourceKind, valkeysrc.SourceKind}
+
+// publishFunc publishes message to channel, and returns the number of
+// clients that received it.
+type publishFunc func(ctx context.Context, channel, message string) (int64, error)
+
+type Config struct {
+	Name           string   `yaml:"name" validate:"required"`
+	Kind           string   `yaml:"kind" validate:"required"`
+	Source         string   `yaml:"source" validate:"required"`
+	Description    string   `yaml:"description" validate:"required"`
+	Channels       []string `yaml:"channels" validate:"required"`
+	RequireJson    bool     `yaml:"requireJson"`
+	MaxMessageSize int      `yaml:"maxMessageSize"`
+	AuthRequired   []string `yaml:"authRequired"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	var publish publishFunc
+	switch s := rawS.(type) {
+	case redisSource:
+		client := s.RedisClient()
+		publish = func(ctx context.Context, channel, message string) (int64, error) {
+			return client.Do(ctx, "PUBLISH", channel, message).Int64()
+		}
+	case valkeySource:
+		client := s.ValkeyClient()
+		publish = func(ctx context.Context, channel, message string) (int64, error) {
+			return client.Do(ctx, client.B().Publish().Channel(channel).Message(message).Build()).AsInt64()
+		}
+	default:
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	if len(cfg.Channels) == 0 {
+		return nil, fmt.Errorf("%q tool %q must allow at least one channel", kind, cfg.Name)
+	}
+	if cfg.MaxMessageSize < 0 {
+		return nil, fmt.Errorf("invalid maxMessageSize %d for tool %q: must not be negative", cfg.MaxMessageSize, cfg.Name)
+	}
+	maxMessageSize := cfg.MaxMessageSize
+	if maxMessageSize == 0 {
+		maxMessageSize = defaultMaxMessageSize
+	}
+
+	messageDesc := "The message to publish."
+	if cfg.RequireJson {
+		messageDesc = "The message to publish. Must be a valid JSON document."
+	}
+	parameters := tools.Parameters{
+		tools.NewStringParameter(channelKey, fmt.Sprintf("The channel to publish to. Must be one of: %s.", strings.Join(cfg.Channels, ", "))),
+		tools.NewStringParameter(messageKey, messageDesc),
+	}
+
+	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, parameters)
+
+	// finish tool setup
+	t := Tool{
+		Name:           cfg.Name,
+		Kind:           kind,
+		Parameters:     parameters,
+		AuthRequired:   cfg.AuthRequired,
+		Channels:       cfg.Channels,
+		RequireJson:    cfg.RequireJson,
+		MaxMessageSize: maxMessageSize,
+		publish:        publish,
+		manifest:       tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:    mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string           `yaml:"name"`
+	Kind         string           `yaml:"kind"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+
+	Channels       []string
+	RequireJson    bool
+	MaxMessageSize int
+	publish        publishFunc
+	manifest       tools.Manifest
+	mcpManifest    tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
+	channel, ok := paramsMap[channelKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected a string", channelKey)
+	}
+	message, ok := paramsMap[messageKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected a string", messageKey)
+	}
+
+	if !slices.Contains(t.Channels, channel) {
+		return nil, fmt.Errorf("channel %q is not allowed; must be one of %q", channel, t.Channels)
+	}
+	if len(message) > t.MaxMessageSize {
+		return nil, fmt.Errorf("message is %d bytes, which exceeds the limit of %d bytes", len(message), t.MaxMessageSize)
+	}
+	if t.RequireJson && !json.Valid([]byte(message)) {
+		return nil, fmt.Errorf("message is not valid JSON")
+	}
+
+	receivers, err := t.publish(ctx, channel, message)
+	if err != nil {
+		return nil, fmt.Errorf("unable to publish to channel %q: %w", channel, err)
+	}
+	return map[string]any{"receivers": receivers}, nil
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return false
+}
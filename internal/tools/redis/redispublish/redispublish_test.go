@@ -0,0 +1,316 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redispublish_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	redissrc "github.com/googleapis/genai-toolbox/internal/sources/redis"
+	valkeysrc "github.com/googleapis/genai-toolbox/internal/sources/valkey"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/redis/redispublish"
+	"github.com/redis/go-redis/v9"
+	"github.com/valkey-io/valkey-go"
+)
+
+func TestParseFromYamlRedisPublish(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				publish_event:
+					kind: redis-publish
+					source: my-redis-instance
+					description: some description
+					channels:
+						- orders
+						- alerts
+			`,
+			want: server.ToolConfigs{
+				"publish_event": redispublish.Config{
+					Name:         "publish_event",
+					Kind:         "redis-publish",
+					Source:       "my-redis-instance",
+					Description:  "some description",
+					Channels:     []string{"orders", "alerts"},
+					AuthRequired: []string{},
+				},
+			},
+		},
+		{
+			desc: "with json validation and size limit",
+			in: `
+			tools:
+				publish_event:
+					kind: redis-publish
+					source: my-redis-instance
+					description: some description
+					channels:
+						- orders
+					requireJson: true
+					maxMessageSize: 1024
+					authRequired:
+						- my-google-auth
+			`,
+			want: server.ToolConfigs{
+				"publish_event": redispublish.Config{
+					Name:           "publish_event",
+					Kind:           "redis-publish",
+					Source:         "my-redis-instance",
+					Description:    "some description",
+					Channels:       []string{"orders"},
+					RequireJson:    true,
+					MaxMessageSize: 1024,
+					AuthRequired:   []string{"my-google-auth"},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestFailParseFromYamlRedisPublish(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		publish_event:
+			kind: redis-publish
+			source: my-redis-instance
+			description: some description
+	`
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	err = yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got)
+	if err == nil {
+		t.Fatalf("expect parsing to fail")
+	}
+	if !strings.Contains(err.Error(), "Channels") {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+// newTool starts a miniredis server and initializes a redis-publish tool
+// against it.
+func newTool(t *testing.T, cfg redispublish.Config) (tools.Tool, *redis.Client) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	cfg.Name = "publish_event"
+	cfg.Kind = "redis-publish"
+	cfg.Source = "my-redis-instance"
+	cfg.Description = "some description"
+	srcs := map[string]sources.Source{
+		"my-redis-instance": &redissrc.Source{Name: "my-redis-instance", Kind: redissrc.SourceKind, Client: client},
+	}
+	tool, err := cfg.Initialize(srcs)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	return tool, client
+}
+
+func invoke(t *testing.T, tool tools.Tool, data map[string]any) (any, error) {
+	t.Helper()
+	params, err := tool.ParseParams(data, nil)
+	if err != nil {
+		t.Fatalf("unable to parse params: %s", err)
+	}
+	return tool.Invoke(context.Background(), tools.Invocation{Params: params})
+}
+
+func TestInvokeDeliversMessage(t *testing.T) {
+	tool, client := newTool(t, redispublish.Config{Channels: []string{"orders"}})
+
+	ctx := context.Background()
+	sub := client.Subscribe(ctx, "orders")
+	defer sub.Close()
+	if _, err := sub.Receive(ctx); err != nil {
+		t.Fatalf("unable to subscribe: %s", err)
+	}
+
+	got, err := invoke(t, tool, map[string]any{"channel": "orders", "message": "hello"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := map[string]any{"receivers": int64(1)}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected result: diff %v", diff)
+	}
+
+	select {
+	case msg := <-sub.Channel():
+		if msg.Channel != "orders" || msg.Payload != "hello" {
+			t.Fatalf("unexpected message: %+v", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for message")
+	}
+}
+
+func TestInvokeValkeySource(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client, err := valkey.NewClient(valkey.ClientOption{InitAddress: []string{mr.Addr()}, DisableCache: true})
+	if err != nil {
+		t.Fatalf("unable to create valkey client: %s", err)
+	}
+	t.Cleanup(client.Close)
+
+	cfg := redispublish.Config{
+		Name:        "publish_event",
+		Kind:        "redis-publish",
+		Source:      "my-valkey-instance",
+		Description: "some description",
+		Channels:    []string{"orders"},
+	}
+	srcs := map[string]sources.Source{
+		"my-valkey-instance": &valkeysrc.Source{Name: "my-valkey-instance", Kind: valkeysrc.SourceKind, Client: client},
+	}
+	tool, err := cfg.Initialize(srcs)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+
+	ctx := context.Background()
+	subscriber := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { subscriber.Close() })
+	sub := subscriber.Subscribe(ctx, "orders")
+	defer sub.Close()
+	if _, err := sub.Receive(ctx); err != nil {
+		t.Fatalf("unable to subscribe: %s", err)
+	}
+
+	got, err := invoke(t, tool, map[string]any{"channel": "orders", "message": "hello"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := map[string]any{"receivers": int64(1)}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected result: diff %v", diff)
+	}
+
+	select {
+	case msg := <-sub.Channel():
+		if msg.Channel != "orders" || msg.Payload != "hello" {
+			t.Fatalf("unexpected message: %+v", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for message")
+	}
+}
+
+func TestInvokeWithoutSubscribers(t *testing.T) {
+	tool, _ := newTool(t, redispublish.Config{Channels: []string{"orders"}})
+
+	got, err := invoke(t, tool, map[string]any{"channel": "orders", "message": "hello"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := map[string]any{"receivers": int64(0)}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected result: diff %v", diff)
+	}
+}
+
+func TestInvokeRejected(t *testing.T) {
+	tcs := []struct {
+		desc    string
+		cfg     redispublish.Config
+		data    map[string]any
+		wantErr string
+	}{
+		{
+			desc:    "channel not in allow-list",
+			cfg:     redispublish.Config{Channels: []string{"orders", "alerts"}},
+			data:    map[string]any{"channel": "admin", "message": "hello"},
+			wantErr: `channel "admin" is not allowed`,
+		},
+		{
+			desc:    "channel pattern is not expanded",
+			cfg:     redispublish.Config{Channels: []string{"orders"}},
+			data:    map[string]any{"channel": "order*", "message": "hello"},
+			wantErr: `channel "order*" is not allowed`,
+		},
+		{
+			desc:    "invalid json",
+			cfg:     redispublish.Config{Channels: []string{"orders"}, RequireJson: true},
+			data:    map[string]any{"channel": "orders", "message": `{"id": 1`},
+			wantErr: "message is not valid JSON",
+		},
+		{
+			desc:    "message too large",
+			cfg:     redispublish.Config{Channels: []string{"orders"}, MaxMessageSize: 4},
+			data:    map[string]any{"channel": "orders", "message": "hello"},
+			wantErr: "message is 5 bytes, which exceeds the limit of 4 bytes",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			tool, _ := newTool(t, tc.cfg)
+			_, err := invoke(t, tool, tc.data)
+			if err == nil {
+				t.Fatalf("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("unexpected error: got %q, want substring %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestInvokeValidJson(t *testing.T) {
+	tool, _ := newTool(t, redispublish.Config{Channels: []string{"orders"}, RequireJson: true})
+
+	if _, err := invoke(t, tool, map[string]any{"channel": "orders", "message": `{"id": 1}`}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Renderer is implemented by tools that run a statement resolved from their
+// template parameters. Render resolves the statement of an invocation without
+// running it, nor connecting to the source, so that tool authors can check
+// their templates.
+type Renderer interface {
+	Render(params ParamValues) (RenderedStatement, error)
+}
+
+// RenderedStatement is the statement an invocation would run, and the
+// parameters it would bind. The values of sensitive parameters are redacted.
+type RenderedStatement struct {
+	Statement string          `json:"statement"`
+	Params    []RenderedParam `json:"params"`
+}
+
+// RenderedParam is a parameter bound to a RenderedStatement.
+type RenderedParam struct {
+	Name  string `json:"name"`
+	Value any    `json:"value"`
+}
+
+// RenderStatement resolves the template parameters of statement with values,
+// and lists the parameters bound to it, as the invocations of most tools
+// with a statement do.
+func RenderStatement(statement string, templateParams, params Parameters, values ParamValues) (RenderedStatement, error) {
+	paramsMap := values.AsMap()
+	resolved, err := ResolveTemplateParams(templateParams, statement, paramsMap)
+	if err != nil {
+		return RenderedStatement{}, values.RedactError(NewTemplateError(statement, err))
+	}
+	bound, err := GetParams(params, paramsMap)
+	if err != nil {
+		return RenderedStatement{}, err
+	}
+	return NewRenderedStatement(resolved, bound, values), nil
+}
+
+// NewRenderedStatement returns the RenderedStatement of statement bound to
+// params, redacting the values of the sensitive parameters among values.
+func NewRenderedStatement(statement string, params ParamValues, values ParamValues) RenderedStatement {
+	rendered := RenderedStatement{
+		Statement: values.RedactString(statement),
+		Params:    make([]RenderedParam, 0, len(params)),
+	}
+	for _, p := range params.Redacted() {
+		rendered.Params = append(rendered.Params, RenderedParam{Name: p.Name, Value: p.Value})
+	}
+	return rendered
+}
+
+// templateErrorRe matches the location in the errors of text/template, such as
+// `statement:1:16: executing "statement" at <.table>: ...`, where the column
+// is a 0-based byte offset in the line.
+var templateErrorRe = regexp.MustCompile(`statement:(\d+)(?::(\d+))?: (?:executing "statement" at <.*?>: )?`)
+
+// TemplateError locates the expression of a statement template that failed to
+// resolve.
+type TemplateError struct {
+	// Line is the 1-based line of the expression.
+	Line int `json:"line"`
+	// Column is the 1-based column of the expression, or 0 if unknown.
+	Column int `json:"column,omitempty"`
+	// Expression is the action of the template that failed, such as
+	// "{{.table}}", if known.
+	Expression string `json:"expression,omitempty"`
+
+	err error
+}
+
+// NewTemplateError returns err, an error resolving the template parameters of
+// statement, as a TemplateError if it can be located.
+func NewTemplateError(statement string, err error) error {
+	m := templateErrorRe.FindStringSubmatch(err.Error())
+	if m == nil {
+		return err
+	}
+	e := &TemplateError{err: err}
+	e.Line, _ = strconv.Atoi(m[1])
+	lines := strings.Split(statement, "\n")
+	if m[2] == "" || e.Line < 1 || e.Line > len(lines) {
+		return e
+	}
+	offset, _ := strconv.Atoi(m[2])
+	line := lines[e.Line-1]
+	if offset > len(line) {
+		return e
+	}
+	// the offset points into the action, such as at a field or function
+	start := strings.LastIndex(line[:offset], "{{")
+	if start < 0 {
+		return e
+	}
+	e.Column = start + 1
+	e.Expression = line[start:]
+	if end := strings.Index(e.Expression, "}}"); end >= 0 {
+		e.Expression = e.Expression[:end+2]
+	}
+	return e
+}
+
+func (e *TemplateError) Error() string {
+	if e.Expression != "" {
+		return fmt.Sprintf("template expression %s at line %d, column %d failed: %s", e.Expression, e.Line, e.Column, e.err)
+	}
+	return fmt.Sprintf("template of the statement failed at line %d: %s", e.Line, e.err)
+}
+
+func (e *TemplateError) Unwrap() error {
+	return e.err
+}
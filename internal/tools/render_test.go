@@ -0,0 +1,105 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestRenderStatement(t *testing.T) {
+	templateParams := tools.Parameters{tools.NewStringParameter("tableName", "the table to search")}
+	params := tools.Parameters{
+		tools.NewStringParameter("email", "the email of the user"),
+		newSensitiveStringParameter("password", "the password of the user"),
+	}
+	values := tools.ParamValues{
+		{Name: "tableName", Value: "users"},
+		{Name: "email", Value: "jane@example.com"},
+		{Name: "password", Value: "hunter2", Sensitive: true},
+	}
+
+	got, err := tools.RenderStatement("SELECT id FROM {{.tableName}} WHERE email = $1 AND password = $2", templateParams, params, values)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := tools.RenderedStatement{
+		Statement: "SELECT id FROM users WHERE email = $1 AND password = $2",
+		Params: []tools.RenderedParam{
+			{Name: "email", Value: "jane@example.com"},
+			{Name: "password", Value: tools.Redacted},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("incorrect rendered statement: diff %v", diff)
+	}
+}
+
+func TestRenderStatementTemplateError(t *testing.T) {
+	tcs := []struct {
+		name      string
+		statement string
+		want      tools.TemplateError
+	}{
+		{
+			name:      "execution error",
+			statement: "SELECT *\nFROM {{.tableName .wrong}} WHERE id = 1",
+			want:      tools.TemplateError{Line: 2, Column: 6, Expression: "{{.tableName .wrong}}"},
+		},
+		{
+			name:      "function error",
+			statement: "SELECT {{array .columns}} FROM {{.tableName}}",
+			want:      tools.TemplateError{Line: 1, Column: 8, Expression: "{{array .columns}}"},
+		},
+		{
+			name:      "parse error",
+			statement: "SELECT *\nFROM {{.tableName}\n",
+			want:      tools.TemplateError{Line: 2},
+		},
+	}
+	templateParams := tools.Parameters{
+		tools.NewStringParameter("tableName", "the table to search"),
+		tools.NewStringParameter("columns", "the columns to select"),
+	}
+	values := tools.ParamValues{{Name: "tableName", Value: "users"}, {Name: "columns", Value: "id"}}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tools.RenderStatement(tc.statement, templateParams, nil, values)
+			var got *tools.TemplateError
+			if !errors.As(err, &got) {
+				t.Fatalf("expected a TemplateError, got %v", err)
+			}
+			if got.Line != tc.want.Line || got.Column != tc.want.Column || got.Expression != tc.want.Expression {
+				t.Fatalf("incorrect template error: got %+v, want %+v", *got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRenderStatementRedactsTemplateErrors(t *testing.T) {
+	templateParams := tools.Parameters{newSensitiveStringParameter("secret", "a secret")}
+	values := tools.ParamValues{{Name: "secret", Value: "hunter2", Sensitive: true}}
+	_, err := tools.RenderStatement("SELECT {{.secret.wrong}}", templateParams, nil, values)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if strings.Contains(err.Error(), "hunter2") {
+		t.Fatalf("error leaks the secret: %s", err)
+	}
+}
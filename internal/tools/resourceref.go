@@ -0,0 +1,145 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+)
+
+const (
+	// ResourceReferenceKey is the only key of an argument value referencing a
+	// resource, such as `{"$resource": "toolbox://artifacts/{id}"}`.
+	ResourceReferenceKey = "$resource"
+	// ArtifactResourcePrefix prefixes the URIs of the resources referencing
+	// an artifact, followed by its ID.
+	ArtifactResourcePrefix = "toolbox://artifacts/"
+)
+
+// ErrResourceTooLarge is returned by a ResourceResolver when the content of a
+// resource is longer than the bytes left to resolve.
+var ErrResourceTooLarge = errors.New("resource is too large")
+
+// ResourceResolver reads the content of the resources referenced by the
+// arguments of a tool.
+type ResourceResolver interface {
+	// Resolve returns the content of the resource at uri, if the caller, as
+	// verified by the authServices of the context, is authorized to read it.
+	// It returns ErrResourceTooLarge if the content is longer than limit
+	// bytes.
+	Resolve(ctx context.Context, uri string, limit int64) (string, error)
+	// MaxResolvedBytes is the maximum total length in bytes of the resources
+	// resolved for an invocation, or zero if unlimited.
+	MaxResolvedBytes() int64
+}
+
+// resourceResolverKey is the key used to store the ResourceResolver within
+// context.
+type resourceResolverKey struct{}
+
+// WithResourceResolver adds the ResourceResolver that resolves the resource
+// references of arguments into the context.
+func WithResourceResolver(ctx context.Context, resolver ResourceResolver) context.Context {
+	return context.WithValue(ctx, resourceResolverKey{}, resolver)
+}
+
+// ResourceResolverFromContext returns the ResourceResolver of the context, or
+// nil if there is none.
+func ResourceResolverFromContext(ctx context.Context) ResourceResolver {
+	resolver, _ := ctx.Value(resourceResolverKey{}).(ResourceResolver)
+	return resolver
+}
+
+// ResolveResourceReferences replaces, at any depth of the arguments in data,
+// the resource references with the content of their resources, using the
+// ResourceResolver of the context. It is called before the arguments are
+// parsed, so that a reference can stand for any string argument.
+func ResolveResourceReferences(ctx context.Context, data map[string]any) error {
+	r := &resourceReferences{ctx: ctx, resolver: ResourceResolverFromContext(ctx)}
+	if r.resolver != nil {
+		r.maxBytes = r.resolver.MaxResolvedBytes()
+	}
+	// sorted, so that the same argument exceeds the limit on every call
+	for _, k := range slices.Sorted(maps.Keys(data)) {
+		v, err := r.resolve(data[k])
+		if err != nil {
+			return fmt.Errorf("argument %q: %w", k, err)
+		}
+		data[k] = v
+	}
+	return nil
+}
+
+// resourceReferences resolves the resource references of the arguments of an
+// invocation, keeping count of the bytes resolved.
+type resourceReferences struct {
+	ctx      context.Context
+	resolver ResourceResolver
+	maxBytes int64
+	resolved int64
+}
+
+func (r *resourceReferences) resolve(v any) (any, error) {
+	switch v := v.(type) {
+	case map[string]any:
+		ref, ok := v[ResourceReferenceKey]
+		if ok {
+			uri, ok := ref.(string)
+			if !ok || len(v) != 1 {
+				return nil, fmt.Errorf("invalid resource reference: it must be an object whose only key is %q, with a URI as value", ResourceReferenceKey)
+			}
+			return r.content(uri)
+		}
+		for k, e := range v {
+			resolved, err := r.resolve(e)
+			if err != nil {
+				return nil, err
+			}
+			v[k] = resolved
+		}
+	case []any:
+		for i, e := range v {
+			resolved, err := r.resolve(e)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = resolved
+		}
+	}
+	return v, nil
+}
+
+func (r *resourceReferences) content(uri string) (string, error) {
+	if r.resolver == nil {
+		return "", fmt.Errorf("unable to resolve resource %q: resource references are not supported", uri)
+	}
+	limit := int64(math.MaxInt64)
+	if r.maxBytes > 0 {
+		limit = r.maxBytes - r.resolved
+	}
+	content, err := r.resolver.Resolve(r.ctx, uri, limit)
+	if errors.Is(err, ErrResourceTooLarge) {
+		return "", fmt.Errorf("unable to resolve resource %q: resolved resources exceed the limit of %d bytes per invocation", uri, r.maxBytes)
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve resource %q: %w", uri, err)
+	}
+	r.resolved += int64(len(content))
+	return content, nil
+}
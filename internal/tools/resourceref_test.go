@@ -0,0 +1,149 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// mapResolver resolves the resources of a map, with a limit per invocation.
+type mapResolver struct {
+	resources map[string]string
+	maxBytes  int64
+}
+
+func (r mapResolver) Resolve(_ context.Context, uri string, limit int64) (string, error) {
+	content, ok := r.resources[uri]
+	if !ok {
+		return "", fmt.Errorf("resource does not exist")
+	}
+	if int64(len(content)) > limit {
+		return "", tools.ErrResourceTooLarge
+	}
+	return content, nil
+}
+
+func (r mapResolver) MaxResolvedBytes() int64 {
+	return r.maxBytes
+}
+
+func ref(uri string) map[string]any {
+	return map[string]any{tools.ResourceReferenceKey: uri}
+}
+
+func TestResolveResourceReferences(t *testing.T) {
+	resolver := mapResolver{
+		resources: map[string]string{
+			"toolbox://artifacts/a": "SELECT 1",
+			"toolbox://artifacts/b": "hello",
+		},
+		maxBytes: 20,
+	}
+	tcs := []struct {
+		desc     string
+		resolver tools.ResourceResolver
+		data     map[string]any
+		want     map[string]any
+		wantErr  string
+	}{
+		{
+			desc:     "no references",
+			resolver: resolver,
+			data:     map[string]any{"query": "SELECT 2", "limit": 10},
+			want:     map[string]any{"query": "SELECT 2", "limit": 10},
+		},
+		{
+			desc:     "nested references",
+			resolver: resolver,
+			data: map[string]any{
+				"query": ref("toolbox://artifacts/a"),
+				"docs":  []any{ref("toolbox://artifacts/b"), "inline"},
+				"meta":  map[string]any{"body": ref("toolbox://artifacts/b")},
+			},
+			want: map[string]any{
+				"query": "SELECT 1",
+				"docs":  []any{"hello", "inline"},
+				"meta":  map[string]any{"body": "hello"},
+			},
+		},
+		{
+			desc: "no references without a resolver",
+			data: map[string]any{"query": "SELECT 2"},
+			want: map[string]any{"query": "SELECT 2"},
+		},
+		{
+			desc:    "reference without a resolver",
+			data:    map[string]any{"query": ref("toolbox://artifacts/a")},
+			wantErr: `argument "query": unable to resolve resource "toolbox://artifacts/a": resource references are not supported`,
+		},
+		{
+			desc:     "unknown resource",
+			resolver: resolver,
+			data:     map[string]any{"query": ref("toolbox://artifacts/c")},
+			wantErr:  `argument "query": unable to resolve resource "toolbox://artifacts/c": resource does not exist`,
+		},
+		{
+			desc:     "reference with other keys",
+			resolver: resolver,
+			data:     map[string]any{"query": map[string]any{"$resource": "toolbox://artifacts/a", "other": 1}},
+			wantErr:  `argument "query": invalid resource reference`,
+		},
+		{
+			desc:     "reference to a number",
+			resolver: resolver,
+			data:     map[string]any{"query": map[string]any{"$resource": 1}},
+			wantErr:  `argument "query": invalid resource reference`,
+		},
+		{
+			desc:     "limit of the invocation",
+			resolver: resolver,
+			data:     map[string]any{"a": ref("toolbox://artifacts/a"), "b": ref("toolbox://artifacts/a"), "c": ref("toolbox://artifacts/a")},
+			wantErr:  `argument "c": unable to resolve resource "toolbox://artifacts/a": resolved resources exceed the limit of 20 bytes per invocation`,
+		},
+		{
+			desc:     "unlimited",
+			resolver: mapResolver{resources: resolver.resources},
+			data:     map[string]any{"a": ref("toolbox://artifacts/a"), "b": ref("toolbox://artifacts/a"), "c": ref("toolbox://artifacts/a")},
+			want:     map[string]any{"a": "SELECT 1", "b": "SELECT 1", "c": "SELECT 1"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.resolver != nil {
+				ctx = tools.WithResourceResolver(ctx, tc.resolver)
+			}
+			err := tools.ResolveResourceReferences(ctx, tc.data)
+			if tc.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tc.wantErr) {
+					t.Fatalf("unexpected error: got %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, tc.data); diff != "" {
+				t.Fatalf("unexpected arguments (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import "fmt"
+
+// resultValueOverhead is the size accounted for each value of a result, on
+// top of the length of its strings and byte slices.
+const resultValueOverhead = 16
+
+// ResultSizeLimit accounts for the approximate size of the rows of a result
+// as they are scanned, so that a query returning a few very wide rows is
+// aborted before it exhausts memory. A nil *ResultSizeLimit has no limit.
+type ResultSizeLimit struct {
+	limit int64
+	size  int64
+}
+
+// NewResultSizeLimit returns a ResultSizeLimit of limit bytes, or nil if limit
+// is not positive.
+func NewResultSizeLimit(limit int64) *ResultSizeLimit {
+	if limit <= 0 {
+		return nil
+	}
+	return &ResultSizeLimit{limit: limit}
+}
+
+// Add accounts for the values of a row. It returns a ResultTooLargeError once
+// the size of the rows added so far is over the limit, after which the
+// caller should stop scanning and close the rows.
+func (l *ResultSizeLimit) Add(values ...any) error {
+	if l == nil {
+		return nil
+	}
+	for _, v := range values {
+		l.size += valueSize(v)
+	}
+	if l.size > l.limit {
+		return &HintError{
+			Err:  &ResultTooLargeError{Size: l.size, Limit: l.limit},
+			Hint: "Narrow the query, e.g. by filtering, selecting fewer columns or adding a LIMIT, so that its result is within the limit.",
+		}
+	}
+	return nil
+}
+
+// valueSize returns the approximate size of v, counting the length of its
+// strings and byte slices and a fixed overhead for each value.
+func valueSize(v any) int64 {
+	size := int64(resultValueOverhead)
+	switch v := v.(type) {
+	case string:
+		size += int64(len(v))
+	case []byte:
+		size += int64(len(v))
+	case map[string]any:
+		for k, e := range v {
+			size += int64(len(k)) + valueSize(e)
+		}
+	case []any:
+		for _, e := range v {
+			size += valueSize(e)
+		}
+	}
+	return size
+}
+
+// ResultTooLargeError is returned when the rows scanned for a result are over
+// the maxResultBytes of its tool.
+type ResultTooLargeError struct {
+	// Size is the approximate size of the rows scanned, in bytes.
+	Size  int64 `json:"size"`
+	Limit int64 `json:"limit"`
+}
+
+func (e *ResultTooLargeError) Error() string {
+	return fmt.Sprintf("result too large, %d bytes scanned over limit %d", e.Size, e.Limit)
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestResultSizeLimit(t *testing.T) {
+	tcs := []struct {
+		desc    string
+		limit   int64
+		rows    [][]any
+		wantErr string
+	}{
+		{
+			desc:  "no limit",
+			limit: 0,
+			rows:  [][]any{{strings.Repeat("x", 1<<20)}},
+		},
+		{
+			desc:  "within the limit",
+			limit: 100,
+			rows:  [][]any{{"abc", []byte("de"), int64(1), nil}},
+		},
+		{
+			desc:    "wide string",
+			limit:   100,
+			rows:    [][]any{{"a"}, {strings.Repeat("x", 100)}},
+			wantErr: "result too large, 133 bytes scanned over limit 100",
+		},
+		{
+			desc:    "nested values",
+			limit:   100,
+			rows:    [][]any{{map[string]any{"doc": []any{strings.Repeat("x", 50), strings.Repeat("y", 50)}}}},
+			wantErr: "result too large, 167 bytes scanned over limit 100",
+		},
+		{
+			desc:    "many small values",
+			limit:   100,
+			rows:    [][]any{{int64(1), int64(2), int64(3)}, {int64(4), int64(5), int64(6), int64(7)}},
+			wantErr: "result too large, 112 bytes scanned over limit 100",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			l := tools.NewResultSizeLimit(tc.limit)
+			var err error
+			for _, row := range tc.rows {
+				if err = l.Add(row...); err != nil {
+					break
+				}
+			}
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			var tooLarge *tools.ResultTooLargeError
+			if !errors.As(err, &tooLarge) {
+				t.Fatalf("got error %v, want a ResultTooLargeError", err)
+			}
+			if err.Error() != tc.wantErr {
+				t.Fatalf("got error %q, want %q", err, tc.wantErr)
+			}
+			if tools.ErrorHint(err) == "" {
+				t.Fatalf("expected a hint")
+			}
+		})
+	}
+}
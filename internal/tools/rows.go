@@ -0,0 +1,119 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+// RowIterator is an optional result type that tools may return from Invoke
+// instead of a fully materialized []any. It allows the server to encode rows
+// one at a time, which reduces peak memory for large results.
+type RowIterator interface {
+	// Next advances to the next row. It returns false when there are no more
+	// rows or an error occurred.
+	Next() bool
+	// Row returns the current row.
+	Row() (any, error)
+	// Err returns the error, if any, that was encountered during iteration.
+	Err() error
+	// Close releases the resources held by the iterator.
+	Close()
+}
+
+// CollectRows drains a RowIterator into a slice. It returns nil if the
+// iterator has no rows, matching the result of tools that build their output
+// by appending to a nil slice.
+func CollectRows(it RowIterator) ([]any, error) {
+	defer it.Close()
+	var out []any
+	for it.Next() {
+		row, err := it.Row()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, row)
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SliceRowIterator is a RowIterator over an in-memory slice of rows.
+type SliceRowIterator struct {
+	rows []any
+	pos  int
+}
+
+// NewSliceRowIterator returns a RowIterator over rows.
+func NewSliceRowIterator(rows []any) *SliceRowIterator {
+	return &SliceRowIterator{rows: rows}
+}
+
+func (it *SliceRowIterator) Next() bool {
+	if it.pos >= len(it.rows) {
+		return false
+	}
+	it.pos++
+	return true
+}
+
+func (it *SliceRowIterator) Row() (any, error) {
+	return it.rows[it.pos-1], nil
+}
+
+func (it *SliceRowIterator) Err() error {
+	return nil
+}
+
+func (it *SliceRowIterator) Close() {}
+
+// OnRowsClosed returns res, the result of an invocation, calling done with the
+// error of reading its rows once they are closed if it is a RowIterator, so
+// that the wrappers of a Tool outlive its streamed rows. It returns false if
+// res is not a RowIterator, in which case done is not called.
+func OnRowsClosed(res any, done func(error)) (any, bool) {
+	it, ok := res.(RowIterator)
+	if !ok {
+		return res, false
+	}
+	return &closeNotifyingRowIterator{RowIterator: it, done: done}, true
+}
+
+// closeNotifyingRowIterator calls done once its RowIterator is closed.
+type closeNotifyingRowIterator struct {
+	RowIterator
+	done   func(error)
+	err    error
+	closed bool
+}
+
+func (it *closeNotifyingRowIterator) Row() (any, error) {
+	row, err := it.RowIterator.Row()
+	if err != nil && it.err == nil {
+		it.err = err
+	}
+	return row, err
+}
+
+func (it *closeNotifyingRowIterator) Close() {
+	if it.closed {
+		return
+	}
+	it.closed = true
+	it.RowIterator.Close()
+	err := it.err
+	if err == nil {
+		err = it.RowIterator.Err()
+	}
+	it.done(err)
+}
@@ -0,0 +1,251 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// SampleMethodFirst samples the first rows of a table, in no particular
+	// order.
+	SampleMethodFirst = "first"
+	// SampleMethodRandom samples random rows of a table.
+	SampleMethodRandom = "random"
+
+	// DefaultSampleSize is the number of rows sampled when the size is not
+	// given.
+	DefaultSampleSize = 10
+	// MaxSampleSize bounds the number of rows of a sample.
+	MaxSampleSize = 1000
+
+	// SummarySourceStatistics marks a summary taken from the statistics the
+	// database keeps of a table.
+	SummarySourceStatistics = "statistics"
+	// SummarySourceSample marks a summary computed on the sampled rows.
+	SummarySourceSample = "sample"
+)
+
+// sampleTableRe matches the names of the tables that can be sampled,
+// optionally qualified by their schema. They are written into the statement,
+// so quoted names are not supported.
+var sampleTableRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*(\.[A-Za-z_][A-Za-z0-9_$]*)?$`)
+
+// SampleTableParameters returns the parameters of the tools sampling a table.
+func SampleTableParameters() Parameters {
+	minSize, maxSize, defaultSize := 1, MaxSampleSize, DefaultSampleSize
+	sizeParameter := NewIntParameterWithRange("sample_size", fmt.Sprintf("The number of rows to sample, at most %d.", MaxSampleSize), &minSize, &maxSize)
+	sizeParameter.Default = &defaultSize
+	methodParameter := NewStringParameterWithAllowedValues("method", "How rows are sampled: 'random' for a random sample, or 'first' for the first rows the database returns, which is cheaper but often less representative.", []any{SampleMethodRandom, SampleMethodFirst})
+	defaultMethod := SampleMethodRandom
+	methodParameter.Default = &defaultMethod
+	return Parameters{
+		NewStringParameter("table", "The name of the table to sample, optionally qualified by its schema, such as 'public.orders'."),
+		sizeParameter,
+		methodParameter,
+	}
+}
+
+// ParseSampleTable returns the schema, empty if not given, and the name of
+// the table to sample.
+func ParseSampleTable(table string) (string, string, error) {
+	if !sampleTableRe.MatchString(table) {
+		return "", "", fmt.Errorf("invalid table %q: it must be a name, optionally qualified by its schema, made of letters, digits and underscores", table)
+	}
+	if schema, name, ok := strings.Cut(table, "."); ok {
+		return schema, name, nil
+	}
+	return "", table, nil
+}
+
+// SampleResult is the result of the tools sampling a table.
+type SampleResult struct {
+	Table string `json:"table"`
+	// Method is the method the rows were sampled with, see SampleMethodFirst
+	// and SampleMethodRandom.
+	Method string `json:"method"`
+	// Strategy is how the database sampled the rows, chosen from the
+	// estimated size of the table.
+	Strategy string `json:"strategy"`
+	// EstimatedRows is the number of rows of the table estimated by the
+	// database, if known.
+	EstimatedRows *int64 `json:"estimatedRows,omitempty"`
+	Rows          []any  `json:"rows"`
+	// Columns summarize the columns of the table.
+	Columns []ColumnSummary `json:"columns"`
+}
+
+// SampleRows returns rows as the rows of a SampleResult.
+func SampleRows(rows []map[string]any) []any {
+	out := make([]any, len(rows))
+	for i, row := range rows {
+		out[i] = row
+	}
+	return out
+}
+
+// SampleColumn is a column of a sampled table.
+type SampleColumn struct {
+	Name string
+	// Ordered is true for the numeric and timestamp columns, whose minimum
+	// and maximum are summarized.
+	Ordered bool
+}
+
+// ColumnStatistics are the statistics the database keeps of a column. Nil
+// fields are unknown.
+type ColumnStatistics struct {
+	NullFraction *float64
+	// DistinctCount is the estimated number of distinct non-NULL values.
+	DistinctCount *float64
+}
+
+// ColumnSummary summarizes a column of a sampled table.
+type ColumnSummary struct {
+	Column string `json:"column"`
+	// NullFraction is the fraction of the values of the column that are NULL.
+	NullFraction       float64 `json:"nullFraction"`
+	NullFractionSource string  `json:"nullFractionSource"`
+	// DistinctCount is the estimated number of distinct non-NULL values of
+	// the column. Computed on the sample, it is at most the sample size.
+	DistinctCount       float64 `json:"distinctCount"`
+	DistinctCountSource string  `json:"distinctCountSource"`
+	// Min and Max are the smallest and the largest sampled values of an
+	// ordered column.
+	Min any `json:"min,omitempty"`
+	Max any `json:"max,omitempty"`
+}
+
+// SummarizeSample summarizes columns from the sampled rows, preferring the
+// statistics of the database when known, so that no more than the sample is
+// read.
+func SummarizeSample(columns []SampleColumn, rows []map[string]any, stats map[string]ColumnStatistics) []ColumnSummary {
+	summaries := make([]ColumnSummary, 0, len(columns))
+	for _, c := range columns {
+		s := ColumnSummary{
+			Column:              c.Name,
+			NullFractionSource:  SummarySourceSample,
+			DistinctCountSource: SummarySourceSample,
+		}
+		nulls := 0
+		distinct := make(map[string]bool)
+		var minKey, maxKey any
+		for _, row := range rows {
+			v := row[c.Name]
+			if v == nil {
+				nulls++
+				continue
+			}
+			distinct[fmt.Sprintf("%T:%v", v, v)] = true
+			if !c.Ordered {
+				continue
+			}
+			key, ok := sampleOrderKey(v)
+			if !ok {
+				continue
+			}
+			if s.Min == nil || compareOrderKeys(key, minKey) < 0 {
+				s.Min, minKey = v, key
+			}
+			if s.Max == nil || compareOrderKeys(key, maxKey) > 0 {
+				s.Max, maxKey = v, key
+			}
+		}
+		if len(rows) > 0 {
+			s.NullFraction = float64(nulls) / float64(len(rows))
+		}
+		s.DistinctCount = float64(len(distinct))
+
+		if st, ok := stats[c.Name]; ok {
+			if st.NullFraction != nil {
+				s.NullFraction, s.NullFractionSource = *st.NullFraction, SummarySourceStatistics
+			}
+			if st.DistinctCount != nil {
+				s.DistinctCount, s.DistinctCountSource = *st.DistinctCount, SummarySourceStatistics
+			}
+		}
+		summaries = append(summaries, s)
+	}
+	return summaries
+}
+
+// sampleTimeLayouts are the layouts of the timestamps drivers return as text.
+var sampleTimeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999", "2006-01-02"}
+
+// sampleOrderKey returns v as a float64 or a time.Time that the values of an
+// ordered column can be compared by, or false if v is neither a number nor a
+// timestamp.
+func sampleOrderKey(v any) (any, bool) {
+	switch v := v.(type) {
+	case time.Time:
+		return v, true
+	case []byte:
+		return sampleOrderKey(string(v))
+	case string:
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f, true
+		}
+		for _, layout := range sampleTimeLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, true
+			}
+		}
+		return nil, false
+	case driver.Valuer:
+		// such as the arbitrary precision numbers of pgx
+		dv, err := v.Value()
+		if err != nil || dv == nil {
+			return nil, false
+		}
+		return sampleOrderKey(dv)
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return nil, false
+}
+
+// compareOrderKeys compares two keys returned by sampleOrderKey. Keys of
+// different types are equal.
+func compareOrderKeys(a, b any) int {
+	switch a := a.(type) {
+	case float64:
+		if b, ok := b.(float64); ok {
+			switch {
+			case a < b:
+				return -1
+			case a > b:
+				return 1
+			}
+		}
+	case time.Time:
+		if b, ok := b.(time.Time); ok {
+			return a.Compare(b)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,158 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestParseSampleTable(t *testing.T) {
+	tcs := []struct {
+		table      string
+		wantSchema string
+		wantName   string
+		wantErr    bool
+	}{
+		{table: "orders", wantName: "orders"},
+		{table: "public.orders", wantSchema: "public", wantName: "orders"},
+		{table: "_tmp$1", wantName: "_tmp$1"},
+		{table: "", wantErr: true},
+		{table: "1orders", wantErr: true},
+		{table: "a.b.c", wantErr: true},
+		{table: `"Orders"`, wantErr: true},
+		{table: "orders; DROP TABLE orders", wantErr: true},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.table, func(t *testing.T) {
+			schema, name, err := tools.ParseSampleTable(tc.table)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %q.%q", schema, name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if schema != tc.wantSchema || name != tc.wantName {
+				t.Fatalf("unexpected table: got %q.%q, want %q.%q", schema, name, tc.wantSchema, tc.wantName)
+			}
+		})
+	}
+}
+
+// decimal is a driver value encoded as text, like the arbitrary precision
+// numbers of pgx.
+type decimal string
+
+func (d decimal) Value() (driver.Value, error) {
+	return string(d), nil
+}
+
+func TestSummarizeSample(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	float := func(f float64) *float64 { return &f }
+	price := decimal("12.50")
+
+	columns := []tools.SampleColumn{
+		{Name: "id", Ordered: true},
+		{Name: "name"},
+		{Name: "created", Ordered: true},
+		{Name: "price", Ordered: true},
+		{Name: "amount", Ordered: true},
+		{Name: "empty", Ordered: true},
+	}
+	rows := []map[string]any{
+		{"id": int64(3), "name": "Alice", "created": day(2), "price": price, "amount": "7.5", "empty": nil},
+		{"id": int64(1), "name": "Jane", "created": day(1), "price": 3.25, "amount": "10", "empty": nil},
+		{"id": int64(4), "name": nil, "created": day(3), "price": nil, "amount": "2", "empty": nil},
+		{"id": int64(2), "name": "Alice", "created": nil, "price": nil, "amount": nil, "empty": nil},
+	}
+
+	tcs := []struct {
+		name  string
+		stats map[string]tools.ColumnStatistics
+		want  []tools.ColumnSummary
+	}{
+		{
+			name: "sample",
+			want: []tools.ColumnSummary{
+				{Column: "id", NullFraction: 0, NullFractionSource: "sample", DistinctCount: 4, DistinctCountSource: "sample", Min: int64(1), Max: int64(4)},
+				{Column: "name", NullFraction: 0.25, NullFractionSource: "sample", DistinctCount: 2, DistinctCountSource: "sample"},
+				{Column: "created", NullFraction: 0.25, NullFractionSource: "sample", DistinctCount: 3, DistinctCountSource: "sample", Min: day(1), Max: day(3)},
+				{Column: "price", NullFraction: 0.5, NullFractionSource: "sample", DistinctCount: 2, DistinctCountSource: "sample", Min: 3.25, Max: price},
+				{Column: "amount", NullFraction: 0.25, NullFractionSource: "sample", DistinctCount: 3, DistinctCountSource: "sample", Min: "2", Max: "10"},
+				{Column: "empty", NullFraction: 1, NullFractionSource: "sample", DistinctCount: 0, DistinctCountSource: "sample"},
+			},
+		},
+		{
+			name: "statistics",
+			stats: map[string]tools.ColumnStatistics{
+				"id":   {NullFraction: float(0), DistinctCount: float(1000)},
+				"name": {DistinctCount: float(42)},
+			},
+			want: []tools.ColumnSummary{
+				{Column: "id", NullFraction: 0, NullFractionSource: "statistics", DistinctCount: 1000, DistinctCountSource: "statistics", Min: int64(1), Max: int64(4)},
+				{Column: "name", NullFraction: 0.25, NullFractionSource: "sample", DistinctCount: 42, DistinctCountSource: "statistics"},
+				{Column: "created", NullFraction: 0.25, NullFractionSource: "sample", DistinctCount: 3, DistinctCountSource: "sample", Min: day(1), Max: day(3)},
+				{Column: "price", NullFraction: 0.5, NullFractionSource: "sample", DistinctCount: 2, DistinctCountSource: "sample", Min: 3.25, Max: price},
+				{Column: "amount", NullFraction: 0.25, NullFractionSource: "sample", DistinctCount: 3, DistinctCountSource: "sample", Min: "2", Max: "10"},
+				{Column: "empty", NullFraction: 1, NullFractionSource: "sample", DistinctCount: 0, DistinctCountSource: "sample"},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tools.SummarizeSample(columns, rows, tc.stats)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected summaries (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSummarizeSampleNoRows(t *testing.T) {
+	got := tools.SummarizeSample([]tools.SampleColumn{{Name: "id", Ordered: true}}, nil, nil)
+	want := []tools.ColumnSummary{{Column: "id", NullFractionSource: "sample", DistinctCountSource: "sample"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected summaries (-want +got):\n%s", diff)
+	}
+}
+
+func TestSampleTableParameters(t *testing.T) {
+	params := tools.SampleTableParameters()
+	got, err := tools.ParseParams(params, map[string]any{"table": "orders"}, nil)
+	if err != nil {
+		t.Fatalf("unable to parse params: %s", err)
+	}
+	want := map[string]any{"table": "orders", "sample_size": tools.DefaultSampleSize, "method": tools.SampleMethodRandom}
+	if diff := cmp.Diff(want, got.AsMap()); diff != "" {
+		t.Fatalf("unexpected params (-want +got):\n%s", diff)
+	}
+	for _, data := range []map[string]any{
+		{"table": "orders", "sample_size": 0},
+		{"table": "orders", "sample_size": tools.MaxSampleSize + 1},
+		{"table": "orders", "method": "last"},
+	} {
+		if _, err := tools.ParseParams(params, data, nil); err == nil {
+			t.Errorf("expected an error parsing %v", data)
+		}
+	}
+}
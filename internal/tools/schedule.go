@@ -0,0 +1,515 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// ErrToolUnavailable is wrapped by the errors returned when a tool is invoked
+// outside of its allowed schedule.
+var ErrToolUnavailable = errors.New("tool unavailable")
+
+// scheduleHorizon is how far ahead the next allowed window is searched for.
+const scheduleHorizon = 8 * 24 * time.Hour
+
+// ScheduleSpec is the kind-agnostic `schedule` block of a tool, or the
+// default schedule of all tools. Invocations are allowed while at least one of
+// the windows is open.
+type ScheduleSpec struct {
+	// Timezone is the IANA name of the timezone the windows are expressed in.
+	// It defaults to UTC.
+	Timezone string           `yaml:"timezone" json:"timezone"`
+	Windows  []ScheduleWindow `yaml:"windows" json:"windows"`
+	// OverrideAuthServices lists the authServices allowed to invoke the tool
+	// outside of its windows with the `override` query parameter.
+	OverrideAuthServices []string `yaml:"overrideAuthServices" json:"overrideAuthServices,omitempty"`
+}
+
+// ScheduleWindow is a recurring period during which a tool can be invoked. It
+// is given either as a cron expression, matching the minutes that are allowed,
+// or as days of the week and a range of hours.
+type ScheduleWindow struct {
+	// Cron is a five field cron expression, such as "* 18-23,0-7 * * 1-5".
+	Cron string `yaml:"cron" json:"cron,omitempty"`
+	// Days lists days of the week, such as "Mon-Fri" or "Sat,Sun". It
+	// defaults to every day.
+	Days string `yaml:"days" json:"days,omitempty"`
+	// Hours is a range of times of day, such as "09:00-17:00". A range ending
+	// before it starts extends past midnight into the next day. It defaults to
+	// the whole day.
+	Hours string `yaml:"hours" json:"hours,omitempty"`
+}
+
+// window reports whether a time, in the timezone of its schedule, is allowed.
+type window interface {
+	allows(t time.Time) bool
+}
+
+// Schedule restricts the times at which a tool can be invoked.
+type Schedule struct {
+	spec    ScheduleSpec
+	loc     *time.Location
+	windows []window
+}
+
+// NewSchedule validates spec and returns the Schedule it describes.
+func NewSchedule(spec ScheduleSpec) (*Schedule, error) {
+	tz := spec.Timezone
+	if tz == "" {
+		tz = "UTC"
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", spec.Timezone, err)
+	}
+	if len(spec.Windows) == 0 {
+		return nil, fmt.Errorf("at least one window is required")
+	}
+
+	s := &Schedule{spec: spec, loc: loc}
+	for i, w := range spec.Windows {
+		var parsed window
+		if w.Cron != "" {
+			if w.Days != "" || w.Hours != "" {
+				return nil, fmt.Errorf("window %d: `cron` cannot be combined with `days` or `hours`", i)
+			}
+			parsed, err = parseCronWindow(w.Cron)
+		} else {
+			parsed, err = parseRangeWindow(w.Days, w.Hours)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("window %d: %w", i, err)
+		}
+		s.windows = append(s.windows, parsed)
+	}
+	return s, nil
+}
+
+// UnmarshalYAML decodes and validates a ScheduleSpec, such as the default
+// schedule of a tools file.
+func (s *Schedule) UnmarshalYAML(ctx context.Context, unmarshal func(interface{}) error) error {
+	var spec ScheduleSpec
+	if err := unmarshal(&spec); err != nil {
+		return err
+	}
+	parsed, err := NewSchedule(spec)
+	if err != nil {
+		return fmt.Errorf("invalid 'schedule' field: %w", err)
+	}
+	*s = *parsed
+	return nil
+}
+
+// Spec returns the spec the Schedule was created from.
+func (s *Schedule) Spec() ScheduleSpec {
+	return s.spec
+}
+
+// Location returns the timezone of the Schedule.
+func (s *Schedule) Location() *time.Location {
+	return s.loc
+}
+
+// Allows reports whether a tool can be invoked at t.
+func (s *Schedule) Allows(t time.Time) bool {
+	local := t.In(s.loc)
+	for _, w := range s.windows {
+		if w.allows(local) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextAllowed returns the first minute at or after t at which a tool can be
+// invoked. It returns false if no window opens within the next week.
+func (s *Schedule) NextAllowed(t time.Time) (time.Time, bool) {
+	if s.Allows(t) {
+		return t, true
+	}
+	// stepping through absolute time keeps DST transitions correct: skipped
+	// local times are never visited and repeated ones are visited twice
+	next := t.Truncate(time.Minute).Add(time.Minute)
+	for end := t.Add(scheduleHorizon); next.Before(end); next = next.Add(time.Minute) {
+		if s.Allows(next) {
+			return next, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// CanOverride reports whether one of the verified authServices may invoke a
+// tool outside of the Schedule.
+func (s *Schedule) CanOverride(verifiedAuthServices []string) bool {
+	for _, a := range s.spec.OverrideAuthServices {
+		if slices.Contains(verifiedAuthServices, a) {
+			return true
+		}
+	}
+	return false
+}
+
+// UnavailableError is returned when a tool is invoked outside of its allowed
+// schedule.
+type UnavailableError struct {
+	Tool string
+	// Until is when the tool becomes available again. It is zero if no window
+	// opens within the next week.
+	Until time.Time
+	// at is when the invocation was rejected.
+	at time.Time
+}
+
+func (e *UnavailableError) Error() string {
+	if e.Until.IsZero() {
+		return fmt.Sprintf("%s: %q is outside of its allowed schedule and no window opens within the next week", ErrToolUnavailable, e.Tool)
+	}
+	return fmt.Sprintf("%s until %s: %q is outside of its allowed schedule", ErrToolUnavailable, e.UntilString(), e.Tool)
+}
+
+func (e *UnavailableError) Unwrap() error {
+	return ErrToolUnavailable
+}
+
+// UntilString formats Until as "HH:MM TZ", prefixed with the day of the week
+// if the tool does not become available again within a day.
+func (e *UnavailableError) UntilString() string {
+	if e.Until.IsZero() {
+		return ""
+	}
+	at := e.at
+	if at.IsZero() {
+		at = time.Now()
+	}
+	// Until is expressed in the timezone of the schedule
+	if e.Until.Sub(at) < 24*time.Hour {
+		return e.Until.Format("15:04 MST")
+	}
+	return e.Until.Format("Mon 15:04 MST")
+}
+
+// ScheduleConfig wraps a ToolConfig with the kind-agnostic `schedule` field.
+type ScheduleConfig struct {
+	ToolConfig
+	Schedule *Schedule
+}
+
+// validate interface
+var _ ToolConfig = ScheduleConfig{}
+
+func (cfg ScheduleConfig) Initialize(srcs map[string]sources.Source) (Tool, error) {
+	t, err := cfg.ToolConfig.Initialize(srcs)
+	if err != nil {
+		return nil, err
+	}
+	return ScheduledTool{Tool: t, Schedule: cfg.Schedule}, nil
+}
+
+// ScheduledTool rejects the invocations of a Tool made outside of its
+// Schedule, unless they are overridden by an authorized user.
+type ScheduledTool struct {
+	Tool
+	Schedule *Schedule
+	// now returns the current time. It defaults to time.Now.
+	now func() time.Time
+}
+
+func (t ScheduledTool) unwrap() Tool {
+	return t.Tool
+}
+
+// NewScheduledToolWithClock returns a ScheduledTool that reads the time from
+// now, for tests.
+func NewScheduledToolWithClock(t Tool, s *Schedule, now func() time.Time) ScheduledTool {
+	return ScheduledTool{Tool: t, Schedule: s, now: now}
+}
+
+func (t ScheduledTool) Invoke(ctx context.Context, inv Invocation) (any, error) {
+	now := time.Now()
+	if t.now != nil {
+		now = t.now()
+	}
+	if t.Schedule.Allows(now) {
+		return t.Tool.Invoke(ctx, inv)
+	}
+
+	name := t.McpManifest().Name
+	if util.ScheduleOverrideFromContext(ctx) {
+		verified := util.VerifiedAuthServicesFromContext(ctx)
+		if !t.Schedule.CanOverride(verified) {
+			return nil, fmt.Errorf("overriding the schedule of %q requires one of the authServices %q: %w", name, t.Schedule.Spec().OverrideAuthServices, ErrUnauthorized)
+		}
+		if logger, err := util.LoggerFromContext(ctx); err == nil {
+			logger.WarnContext(ctx, fmt.Sprintf("tool %q was invoked outside of its allowed schedule using an override by authServices %q", name, verified))
+		}
+		return t.Tool.Invoke(ctx, inv)
+	}
+
+	until, _ := t.Schedule.NextAllowed(now)
+	if !until.IsZero() {
+		until = until.In(t.Schedule.Location())
+	}
+	return nil, &UnavailableError{Tool: name, Until: until, at: now}
+}
+
+func (t ScheduledTool) Manifest() Manifest {
+	m := t.Tool.Manifest()
+	spec := t.Schedule.Spec()
+	m.Schedule = &spec
+	return m
+}
+
+func (t ScheduledTool) McpManifest() McpManifest {
+	m := t.Tool.McpManifest()
+	meta := make(map[string]any, len(m.Metadata)+1)
+	for k, v := range m.Metadata {
+		meta[k] = v
+	}
+	meta["toolbox/schedule"] = t.Schedule.Spec()
+	m.Metadata = meta
+	return m
+}
+
+// rangeWindow is a window given as days of the week and a range of hours.
+type rangeWindow struct {
+	days [7]bool
+	// start and end are minutes since midnight. end is exclusive and may be
+	// smaller than start for ranges crossing midnight.
+	start, end int
+	allDay     bool
+}
+
+func (w rangeWindow) allows(t time.Time) bool {
+	d, m := t.Weekday(), t.Hour()*60+t.Minute()
+	switch {
+	case w.allDay:
+		return w.days[d]
+	case w.start < w.end:
+		return w.days[d] && w.start <= m && m < w.end
+	default:
+		// the part after midnight belongs to the day the range started on
+		prev := (d + 6) % 7
+		return (w.days[d] && m >= w.start) || (w.days[prev] && m < w.end)
+	}
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+func parseWeekday(s string) (time.Weekday, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if len(s) >= 3 {
+		if d, ok := weekdays[s[:3]]; ok && strings.HasPrefix(strings.ToLower(d.String()), s) {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid day %q", s)
+}
+
+func parseRangeWindow(days, hours string) (window, error) {
+	w := rangeWindow{}
+	if strings.TrimSpace(days) == "" {
+		for i := range w.days {
+			w.days[i] = true
+		}
+	} else {
+		for _, part := range strings.Split(days, ",") {
+			from, to, isRange := strings.Cut(part, "-")
+			start, err := parseWeekday(from)
+			if err != nil {
+				return nil, err
+			}
+			end := start
+			if isRange {
+				if end, err = parseWeekday(to); err != nil {
+					return nil, err
+				}
+			}
+			// ranges such as "Fri-Mon" wrap around the end of the week
+			for d := start; ; d = (d + 1) % 7 {
+				w.days[d] = true
+				if d == end {
+					break
+				}
+			}
+		}
+	}
+
+	if strings.TrimSpace(hours) == "" {
+		w.allDay = true
+		return w, nil
+	}
+	from, to, ok := strings.Cut(hours, "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid hours %q: must be a range such as \"09:00-17:00\"", hours)
+	}
+	var err error
+	if w.start, err = parseTimeOfDay(from, false); err != nil {
+		return nil, err
+	}
+	if w.end, err = parseTimeOfDay(to, true); err != nil {
+		return nil, err
+	}
+	if w.start == w.end {
+		return nil, fmt.Errorf("invalid hours %q: range is empty", hours)
+	}
+	if w.start == 0 && w.end == 24*60 {
+		w.allDay = true
+	}
+	return w, nil
+}
+
+// parseTimeOfDay parses "HH:MM" into minutes since midnight. "24:00" is only
+// accepted as the end of a range.
+func parseTimeOfDay(s string, isEnd bool) (int, error) {
+	s = strings.TrimSpace(s)
+	hh, mm, ok := strings.Cut(s, ":")
+	h, errH := strconv.Atoi(hh)
+	m, errM := strconv.Atoi(mm)
+	if !ok || errH != nil || errM != nil || len(mm) != 2 || h < 0 || m < 0 || m > 59 {
+		return 0, fmt.Errorf("invalid time %q: must be formatted as HH:MM", s)
+	}
+	if h > 23 && !(isEnd && h == 24 && m == 0) {
+		return 0, fmt.Errorf("invalid time %q: must be formatted as HH:MM", s)
+	}
+	return h*60 + m, nil
+}
+
+// cronWindow is a window given as a five field cron expression. Each field
+// holds the allowed values of the minute, hour, day of the month, month and
+// day of the week.
+type cronWindow struct {
+	minute, hour, dom, month, dow []bool
+	// domStar and dowStar record unrestricted fields: as in cron, when both
+	// days are restricted, a time matching either of them is allowed.
+	domStar, dowStar bool
+}
+
+func (w cronWindow) allows(t time.Time) bool {
+	if !w.minute[t.Minute()] || !w.hour[t.Hour()] || !w.month[t.Month()] {
+		return false
+	}
+	dom, dow := w.dom[t.Day()], w.dow[t.Weekday()]
+	if w.domStar || w.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+var (
+	cronMonths = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+	cronDays   = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+)
+
+func parseCronWindow(expr string) (window, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: must have 5 fields", expr)
+	}
+	var w cronWindow
+	var err error
+	if w.minute, _, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("invalid minute field in cron expression %q: %w", expr, err)
+	}
+	if w.hour, _, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("invalid hour field in cron expression %q: %w", expr, err)
+	}
+	if w.dom, w.domStar, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("invalid day of month field in cron expression %q: %w", expr, err)
+	}
+	if w.month, _, err = parseCronField(fields[3], 1, 12, cronMonths); err != nil {
+		return nil, fmt.Errorf("invalid month field in cron expression %q: %w", expr, err)
+	}
+	// 7 is accepted as an alias of Sunday
+	if w.dow, w.dowStar, err = parseCronField(fields[4], 0, 7, cronDays); err != nil {
+		return nil, fmt.Errorf("invalid day of week field in cron expression %q: %w", expr, err)
+	}
+	if w.dow[7] {
+		w.dow[0] = true
+	}
+	return w, nil
+}
+
+// parseCronField parses a comma separated list of `*`, values and ranges, each
+// with an optional `/step`. names, if set, are accepted in place of the values
+// starting at min. It returns the allowed values, indexed by value, and
+// whether the field is unrestricted.
+func parseCronField(field string, min, max int, names []string) ([]bool, bool, error) {
+	allowed := make([]bool, max+1)
+	value := func(s string) (int, error) {
+		for i, n := range names {
+			if strings.EqualFold(s, n) {
+				return min + i, nil
+			}
+		}
+		v, err := strconv.Atoi(s)
+		if err != nil || v < min || v > max {
+			return 0, fmt.Errorf("%q is not a value between %d and %d", s, min, max)
+		}
+		return v, nil
+	}
+
+	for _, part := range strings.Split(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepStr); err != nil || step <= 0 {
+				return nil, false, fmt.Errorf("invalid step %q", stepStr)
+			}
+		}
+		var lo, hi int
+		switch from, to, isRange := strings.Cut(rng, "-"); {
+		case rng == "*":
+			lo, hi = min, max
+		case isRange:
+			var err error
+			if lo, err = value(from); err != nil {
+				return nil, false, err
+			}
+			if hi, err = value(to); err != nil {
+				return nil, false, err
+			}
+			if lo > hi {
+				return nil, false, fmt.Errorf("invalid range %q", rng)
+			}
+		default:
+			var err error
+			if lo, err = value(rng); err != nil {
+				return nil, false, err
+			}
+			hi = lo
+			if hasStep {
+				hi = max
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			allowed[v] = true
+		}
+	}
+	return allowed, field == "*", nil
+}
@@ -0,0 +1,424 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+func mustSchedule(t *testing.T, spec tools.ScheduleSpec) *tools.Schedule {
+	t.Helper()
+	s, err := tools.NewSchedule(spec)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return s
+}
+
+func mustParseTime(t *testing.T, value string) time.Time {
+	t.Helper()
+	v, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatalf("unable to parse time: %s", err)
+	}
+	return v
+}
+
+func TestScheduleAllows(t *testing.T) {
+	tcs := []struct {
+		desc  string
+		spec  tools.ScheduleSpec
+		times map[string]bool
+	}{
+		{
+			desc: "business hours",
+			spec: tools.ScheduleSpec{Windows: []tools.ScheduleWindow{{Days: "Mon-Fri", Hours: "09:00-17:00"}}},
+			times: map[string]bool{
+				// 2025-06-02 is a Monday
+				"2025-06-02T09:00:00Z": true,
+				"2025-06-02T16:59:00Z": true,
+				"2025-06-02T17:00:00Z": false,
+				"2025-06-02T08:59:00Z": false,
+				"2025-06-07T12:00:00Z": false,
+			},
+		},
+		{
+			desc: "overnight hours belong to the day they start",
+			spec: tools.ScheduleSpec{Windows: []tools.ScheduleWindow{{Days: "Fri", Hours: "22:00-06:00"}}},
+			times: map[string]bool{
+				"2025-06-06T22:00:00Z": true,
+				"2025-06-07T05:59:00Z": true,
+				"2025-06-07T06:00:00Z": false,
+				"2025-06-06T05:00:00Z": false,
+				"2025-06-07T23:00:00Z": false,
+			},
+		},
+		{
+			desc: "day range wrapping around the week",
+			spec: tools.ScheduleSpec{Windows: []tools.ScheduleWindow{{Days: "Sat-Mon"}}},
+			times: map[string]bool{
+				"2025-06-07T00:00:00Z": true,
+				"2025-06-08T12:00:00Z": true,
+				"2025-06-09T23:59:00Z": true,
+				"2025-06-10T00:00:00Z": false,
+			},
+		},
+		{
+			desc: "several windows",
+			spec: tools.ScheduleSpec{Windows: []tools.ScheduleWindow{
+				{Days: "Mon-Fri", Hours: "18:00-24:00"},
+				{Days: "saturday,SUN"},
+			}},
+			times: map[string]bool{
+				"2025-06-02T23:59:00Z": true,
+				"2025-06-03T00:00:00Z": false,
+				"2025-06-08T10:00:00Z": true,
+			},
+		},
+		{
+			desc: "timezone",
+			spec: tools.ScheduleSpec{Timezone: "Asia/Tokyo", Windows: []tools.ScheduleWindow{{Days: "Mon-Fri", Hours: "09:00-17:00"}}},
+			times: map[string]bool{
+				// 09:00 on Monday in Tokyo is 00:00 UTC
+				"2025-06-02T00:00:00Z": true,
+				"2025-06-02T09:00:00Z": false,
+				// 08:00 on Saturday in Tokyo is still Friday in UTC
+				"2025-06-06T23:00:00Z": false,
+			},
+		},
+		{
+			desc: "cron",
+			spec: tools.ScheduleSpec{Timezone: "Europe/Paris", Windows: []tools.ScheduleWindow{{Cron: "* 0-7,19-23 * * MON-FRI"}}},
+			times: map[string]bool{
+				// Paris is UTC+2 in June
+				"2025-06-02T17:00:00Z": true,
+				"2025-06-02T16:59:00Z": false,
+				"2025-06-03T05:59:00Z": true,
+				"2025-06-03T06:00:00Z": false,
+				"2025-06-07T20:00:00Z": false,
+			},
+		},
+		{
+			desc: "cron with steps",
+			spec: tools.ScheduleSpec{Windows: []tools.ScheduleWindow{{Cron: "0-14/5 */6 * * *"}}},
+			times: map[string]bool{
+				"2025-06-02T06:10:00Z": true,
+				"2025-06-02T06:11:00Z": false,
+				"2025-06-02T06:15:00Z": false,
+				"2025-06-02T07:00:00Z": false,
+			},
+		},
+		{
+			desc: "cron days of month or of week",
+			spec: tools.ScheduleSpec{Windows: []tools.ScheduleWindow{{Cron: "* * 1 * 0"}}},
+			times: map[string]bool{
+				// the 1st of the month or a Sunday
+				"2025-06-01T12:00:00Z": true,
+				"2025-07-01T12:00:00Z": true,
+				"2025-06-08T12:00:00Z": true,
+				"2025-06-09T12:00:00Z": false,
+			},
+		},
+		{
+			desc: "cron Sunday as 7",
+			spec: tools.ScheduleSpec{Windows: []tools.ScheduleWindow{{Cron: "* * * jan-dec 7"}}},
+			times: map[string]bool{
+				"2025-06-08T12:00:00Z": true,
+				"2025-06-09T12:00:00Z": false,
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			s := mustSchedule(t, tc.spec)
+			for value, want := range tc.times {
+				if got := s.Allows(mustParseTime(t, value)); got != want {
+					t.Errorf("Allows(%s): got %t, want %t", value, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestScheduleNextAllowed(t *testing.T) {
+	tcs := []struct {
+		desc string
+		spec tools.ScheduleSpec
+		at   string
+		want string
+	}{
+		{
+			desc: "already allowed",
+			spec: tools.ScheduleSpec{Windows: []tools.ScheduleWindow{{Hours: "09:00-17:00"}}},
+			at:   "2025-06-02T10:30:15Z",
+			want: "2025-06-02T10:30:15Z",
+		},
+		{
+			desc: "later today",
+			spec: tools.ScheduleSpec{Windows: []tools.ScheduleWindow{{Hours: "18:00-06:00"}}},
+			at:   "2025-06-02T10:30:15Z",
+			want: "2025-06-02T18:00:00Z",
+		},
+		{
+			desc: "after the weekend",
+			spec: tools.ScheduleSpec{Timezone: "America/Los_Angeles", Windows: []tools.ScheduleWindow{{Days: "Mon-Fri", Hours: "09:00-17:00"}}},
+			// Friday 17:00 in Los Angeles
+			at:   "2025-06-07T00:00:00Z",
+			want: "2025-06-09T16:00:00Z",
+		},
+		{
+			desc: "window starting in the skipped hour of spring forward",
+			spec: tools.ScheduleSpec{Timezone: "America/New_York", Windows: []tools.ScheduleWindow{{Hours: "02:30-04:00"}}},
+			// 01:00 EST, an hour before clocks move from 02:00 EST to 03:00 EDT
+			at: "2025-03-09T06:00:00Z",
+			// 03:00 EDT
+			want: "2025-03-09T07:00:00Z",
+		},
+		{
+			desc: "offset change over the weekend of fall back",
+			spec: tools.ScheduleSpec{Timezone: "America/New_York", Windows: []tools.ScheduleWindow{{Days: "Mon-Fri", Hours: "09:00-17:00"}}},
+			// Saturday 12:00 EDT
+			at: "2025-11-01T16:00:00Z",
+			// Monday 09:00 EST
+			want: "2025-11-03T14:00:00Z",
+		},
+		{
+			desc: "first occurrence of the repeated hour of fall back",
+			spec: tools.ScheduleSpec{Timezone: "America/New_York", Windows: []tools.ScheduleWindow{{Hours: "01:30-02:00"}}},
+			// 00:00 EDT
+			at: "2025-11-02T04:00:00Z",
+			// 01:30 EDT
+			want: "2025-11-02T05:30:00Z",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			s := mustSchedule(t, tc.spec)
+			got, ok := s.NextAllowed(mustParseTime(t, tc.at))
+			if !ok {
+				t.Fatalf("expected an allowed time")
+			}
+			if want := mustParseTime(t, tc.want); !got.Equal(want) {
+				t.Fatalf("unexpected next allowed time: got %s, want %s", got.UTC().Format(time.RFC3339), tc.want)
+			}
+		})
+	}
+}
+
+func TestScheduleNextAllowedNever(t *testing.T) {
+	// February 31st never happens
+	s := mustSchedule(t, tools.ScheduleSpec{Windows: []tools.ScheduleWindow{{Cron: "* * 31 2 *"}}})
+	if got, ok := s.NextAllowed(mustParseTime(t, "2025-02-01T00:00:00Z")); ok {
+		t.Fatalf("expected no allowed time, got %s", got)
+	}
+}
+
+func TestNewScheduleErrors(t *testing.T) {
+	tcs := []struct {
+		desc string
+		spec tools.ScheduleSpec
+		err  string
+	}{
+		{
+			desc: "no window",
+			spec: tools.ScheduleSpec{},
+			err:  "at least one window is required",
+		},
+		{
+			desc: "invalid timezone",
+			spec: tools.ScheduleSpec{Timezone: "Mars/Olympus_Mons", Windows: []tools.ScheduleWindow{{Days: "Mon"}}},
+			err:  `invalid timezone "Mars/Olympus_Mons"`,
+		},
+		{
+			desc: "invalid day",
+			spec: tools.ScheduleSpec{Windows: []tools.ScheduleWindow{{Days: "Mon-Fry"}}},
+			err:  `window 0: invalid day "fry"`,
+		},
+		{
+			desc: "hours without range",
+			spec: tools.ScheduleSpec{Windows: []tools.ScheduleWindow{{Hours: "09:00"}}},
+			err:  `window 0: invalid hours "09:00"`,
+		},
+		{
+			desc: "invalid time",
+			spec: tools.ScheduleSpec{Windows: []tools.ScheduleWindow{{Hours: "24:00-06:00"}}},
+			err:  `window 0: invalid time "24:00"`,
+		},
+		{
+			desc: "empty hours",
+			spec: tools.ScheduleSpec{Windows: []tools.ScheduleWindow{{Hours: "09:00-09:00"}}},
+			err:  `window 0: invalid hours "09:00-09:00": range is empty`,
+		},
+		{
+			desc: "cron with days",
+			spec: tools.ScheduleSpec{Windows: []tools.ScheduleWindow{{Days: "Mon"}, {Cron: "* * * * *", Days: "Mon"}}},
+			err:  "window 1: `cron` cannot be combined with `days` or `hours`",
+		},
+		{
+			desc: "cron with missing fields",
+			spec: tools.ScheduleSpec{Windows: []tools.ScheduleWindow{{Cron: "* * *"}}},
+			err:  `invalid cron expression "* * *": must have 5 fields`,
+		},
+		{
+			desc: "cron value out of range",
+			spec: tools.ScheduleSpec{Windows: []tools.ScheduleWindow{{Cron: "* 24 * * *"}}},
+			err:  `invalid hour field in cron expression "* 24 * * *": "24" is not a value between 0 and 23`,
+		},
+		{
+			desc: "cron invalid step",
+			spec: tools.ScheduleSpec{Windows: []tools.ScheduleWindow{{Cron: "*/0 * * * *"}}},
+			err:  `invalid step "0"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := tools.NewSchedule(tc.spec)
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			if !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("unexpected error: got %q, want substring %q", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestScheduledToolInvoke(t *testing.T) {
+	spec := tools.ScheduleSpec{
+		Timezone:             "America/New_York",
+		Windows:              []tools.ScheduleWindow{{Days: "Mon-Fri", Hours: "18:00-08:00"}},
+		OverrideAuthServices: []string{"dba-auth"},
+	}
+	s := mustSchedule(t, spec)
+	inner := rowsTool{mockTool: mockTool{name: "heavy_report"}, res: "done"}
+	// Monday 12:00 EST
+	now := mustParseTime(t, "2025-01-06T17:00:00Z")
+	tool := tools.NewScheduledToolWithClock(inner, s, func() time.Time { return now })
+
+	var logs bytes.Buffer
+	logger, err := log.NewStdLogger(&logs, &logs, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	ctx := util.WithLogger(context.Background(), logger)
+
+	t.Run("outside of schedule", func(t *testing.T) {
+		_, err := tool.Invoke(ctx, tools.Invocation{})
+		var unavailableErr *tools.UnavailableError
+		if !errors.As(err, &unavailableErr) {
+			t.Fatalf("expected an UnavailableError, got %v", err)
+		}
+		if !errors.Is(err, tools.ErrToolUnavailable) {
+			t.Fatalf("expected error to wrap ErrToolUnavailable")
+		}
+		if want := mustParseTime(t, "2025-01-06T23:00:00Z"); !unavailableErr.Until.Equal(want) {
+			t.Fatalf("unexpected until: got %s, want %s", unavailableErr.Until, want)
+		}
+		want := `tool unavailable until 18:00 EST: "heavy_report" is outside of its allowed schedule`
+		if err.Error() != want {
+			t.Fatalf("unexpected error: got %q, want %q", err, want)
+		}
+	})
+
+	t.Run("within schedule", func(t *testing.T) {
+		// Monday 20:00 EST
+		tool := tools.NewScheduledToolWithClock(inner, s, func() time.Time { return mustParseTime(t, "2025-01-07T01:00:00Z") })
+		got, err := tool.Invoke(ctx, tools.Invocation{})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if got != "done" {
+			t.Fatalf("unexpected result: %v", got)
+		}
+	})
+
+	t.Run("override without authorization", func(t *testing.T) {
+		ctx := util.WithScheduleOverride(util.WithVerifiedAuthServices(ctx, []string{"other-auth"}))
+		_, err := tool.Invoke(ctx, tools.Invocation{})
+		if !errors.Is(err, tools.ErrUnauthorized) {
+			t.Fatalf("expected error to wrap ErrUnauthorized, got %v", err)
+		}
+	})
+
+	t.Run("authorized override", func(t *testing.T) {
+		logs.Reset()
+		ctx := util.WithScheduleOverride(util.WithVerifiedAuthServices(ctx, []string{"other-auth", "dba-auth"}))
+		got, err := tool.Invoke(ctx, tools.Invocation{})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if got != "done" {
+			t.Fatalf("unexpected result: %v", got)
+		}
+		if !strings.Contains(logs.String(), "WARN") || !strings.Contains(logs.String(), `tool \"heavy_report\" was invoked outside of its allowed schedule`) {
+			t.Fatalf("expected a warning to be logged, got %q", logs.String())
+		}
+	})
+
+	t.Run("authentication without override", func(t *testing.T) {
+		ctx := util.WithVerifiedAuthServices(ctx, []string{"dba-auth"})
+		if _, err := tool.Invoke(ctx, tools.Invocation{}); !errors.Is(err, tools.ErrToolUnavailable) {
+			t.Fatalf("expected error to wrap ErrToolUnavailable, got %v", err)
+		}
+	})
+}
+
+func TestUnavailableErrorUntilString(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatalf("unable to load location: %s", err)
+	}
+	soon := time.Now().Add(time.Hour).In(loc)
+	later := time.Now().Add(3 * 24 * time.Hour).In(loc)
+	tcs := []struct {
+		desc  string
+		until time.Time
+		want  string
+	}{
+		{desc: "within a day", until: soon, want: soon.Format("15:04 MST")},
+		{desc: "after a day", until: later, want: later.Format("Mon 15:04 MST")},
+		{desc: "never", want: ""},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			e := tools.UnavailableError{Tool: "t", Until: tc.until}
+			if got := e.UntilString(); got != tc.want {
+				t.Fatalf("unexpected until: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestScheduledToolManifests(t *testing.T) {
+	spec := tools.ScheduleSpec{Timezone: "UTC", Windows: []tools.ScheduleWindow{{Days: "Sat,Sun"}}}
+	tool := tools.ScheduledTool{Tool: mockTool{name: "report"}, Schedule: mustSchedule(t, spec)}
+
+	if diff := cmp.Diff(&spec, tool.Manifest().Schedule); diff != "" {
+		t.Fatalf("unexpected manifest schedule: diff %v", diff)
+	}
+	if diff := cmp.Diff(spec, tool.McpManifest().Metadata["toolbox/schedule"]); diff != "" {
+		t.Fatalf("unexpected mcp manifest schedule: diff %v", diff)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+)
+
+// ErrToolBusy is returned when a serialized tool could not start an
+// invocation before its queue timeout expired.
+var ErrToolBusy = errors.New("tool busy")
+
+// SerializeConfig wraps a ToolConfig with the kind-agnostic `serialize` and
+// `queueTimeout` fields. Tools initialized from it run at most one invocation
+// at a time.
+type SerializeConfig struct {
+	ToolConfig
+	// QueueTimeout is how long an invocation waits for its turn before
+	// failing with ErrToolBusy. Zero means wait until the request is canceled.
+	QueueTimeout time.Duration
+}
+
+// validate interface
+var _ ToolConfig = SerializeConfig{}
+
+func (cfg SerializeConfig) Initialize(srcs map[string]sources.Source) (Tool, error) {
+	t, err := cfg.ToolConfig.Initialize(srcs)
+	if err != nil {
+		return nil, err
+	}
+	return NewSerializedTool(t, cfg.QueueTimeout), nil
+}
+
+// SerializedTool wraps a Tool so that at most one invocation runs at a time.
+// Waiting invocations are started in the order they arrived. Copies of a
+// SerializedTool share the same lock.
+type SerializedTool struct {
+	Tool
+	queueTimeout time.Duration
+	// sem holds a token while an invocation is running.
+	sem chan struct{}
+	// pending counts the invocations that are running or waiting.
+	pending *atomic.Int64
+}
+
+func (t SerializedTool) unwrap() Tool {
+	return t.Tool
+}
+
+// NewSerializedTool returns a SerializedTool wrapping t.
+func NewSerializedTool(t Tool, queueTimeout time.Duration) SerializedTool {
+	return SerializedTool{
+		Tool:         t,
+		queueTimeout: queueTimeout,
+		sem:          make(chan struct{}, 1),
+		pending:      &atomic.Int64{},
+	}
+}
+
+func (t SerializedTool) Invoke(ctx context.Context, inv Invocation) (any, error) {
+	// position is the number of invocations ahead of this one
+	position := t.pending.Add(1) - 1
+	defer t.pending.Add(-1)
+
+	var timeout <-chan time.Time
+	if t.queueTimeout > 0 {
+		timer := time.NewTimer(t.queueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case t.sem <- struct{}{}:
+	case <-timeout:
+		return nil, fmt.Errorf("%w: %q did not start within %s (queue position %d)", ErrToolBusy, t.McpManifest().Name, t.queueTimeout, position)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	// the lock is held until the rows of a streamed result are closed
+	held := true
+	defer func() {
+		if held {
+			<-t.sem
+		}
+	}()
+	res, err := t.Tool.Invoke(ctx, inv)
+	if err != nil {
+		return res, err
+	}
+	res, streamed := OnRowsClosed(res, func(error) { <-t.sem })
+	held = !streamed
+	return res, nil
+}
@@ -100,6 +100,9 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	}, nil
 }
 
+// validate interface
+var _ tools.Tool = Tool{}
+
 // Tool is the implementation of the tool.
 type Tool struct {
 	Name         string   `yaml:"name"`
@@ -130,7 +133,7 @@ type Batch struct {
 }
 
 // Invoke executes the tool's operation.
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
 	client := t.Source.GetBatchControllerClient()
 
 	parent := fmt.Sprintf("projects/%s/locations/%s", t.Source.Project, t.Source.Location)
@@ -139,7 +142,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		OrderBy: "create_time desc",
 	}
 
-	paramMap := params.AsMap()
+	paramMap := inv.Params.AsMap()
 	if ps, ok := paramMap["pageSize"]; ok && ps != nil {
 		req.PageSize = int32(ps.(int))
 		if (req.PageSize) <= 0 {
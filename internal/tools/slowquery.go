@@ -0,0 +1,206 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+const (
+	// defaultSlowQueryMaxPlans is the number of plans kept when maxPlans is
+	// not set.
+	defaultSlowQueryMaxPlans = 10
+	// defaultSlowQueryMinInterval is the minimum time between two EXPLAINs of
+	// a tool when minInterval is not set.
+	defaultSlowQueryMinInterval = time.Minute
+	// slowQueryExplainTimeout bounds the time an EXPLAIN can take.
+	slowQueryExplainTimeout = 30 * time.Second
+)
+
+// SlowQueryExplainSpec is the `slowQueryExplain` block of a SQL tool config.
+// When an invocation takes longer than Threshold, the plan of its statement
+// is captured in the background.
+type SlowQueryExplainSpec struct {
+	// Threshold is the duration above which a query is slow, e.g. "500ms".
+	Threshold string `yaml:"threshold" validate:"required"`
+	// MaxPlans is the number of plans kept for the tool. Once it is reached,
+	// the oldest plan is evicted.
+	MaxPlans int `yaml:"maxPlans"`
+	// MinInterval is the minimum time between two EXPLAINs of the tool.
+	MinInterval string `yaml:"minInterval"`
+}
+
+// SlowQueryPlan is the plan captured for a slow invocation of a tool.
+type SlowQueryPlan struct {
+	Tool       string    `json:"tool"`
+	Statement  string    `json:"statement"`
+	Params     []any     `json:"params"`
+	Duration   string    `json:"duration"`
+	CapturedAt time.Time `json:"capturedAt"`
+	// Plan is the output of EXPLAIN, unless it failed, in which case Error is
+	// set instead.
+	Plan  any    `json:"plan,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// SlowQueryTool is implemented by tools that capture the plans of their slow
+// invocations.
+type SlowQueryTool interface {
+	// SlowQueries returns the captured plans, newest first.
+	SlowQueries() []SlowQueryPlan
+}
+
+// ExplainFunc returns the plan of statement when run with params.
+type ExplainFunc func(ctx context.Context, statement string, params []any) (any, error)
+
+// SlowQueryExplain is a validated SlowQueryExplainSpec, along with the plans
+// captured so far. Its methods are safe for concurrent use.
+type SlowQueryExplain struct {
+	threshold   time.Duration
+	minInterval time.Duration
+
+	mu sync.Mutex
+	// plans is a ring buffer whose oldest plan is at next once it is full.
+	plans       []SlowQueryPlan
+	next        int
+	lastExplain time.Time
+	running     bool
+	// wg tracks the running EXPLAIN, for tests.
+	wg sync.WaitGroup
+}
+
+// NewSlowQueryExplain validates spec and fills in its defaults. It returns
+// nil if spec is nil.
+func NewSlowQueryExplain(spec *SlowQueryExplainSpec) (*SlowQueryExplain, error) {
+	if spec == nil {
+		return nil, nil
+	}
+	threshold, err := time.ParseDuration(spec.Threshold)
+	if err != nil {
+		return nil, fmt.Errorf("invalid slowQueryExplain threshold %q: %w", spec.Threshold, err)
+	}
+	if threshold <= 0 {
+		return nil, fmt.Errorf("slowQueryExplain threshold must be positive, got %q", spec.Threshold)
+	}
+	if spec.MaxPlans < 0 {
+		return nil, fmt.Errorf("slowQueryExplain maxPlans must not be negative, got %d", spec.MaxPlans)
+	}
+	maxPlans := spec.MaxPlans
+	if maxPlans == 0 {
+		maxPlans = defaultSlowQueryMaxPlans
+	}
+	minInterval := defaultSlowQueryMinInterval
+	if spec.MinInterval != "" {
+		minInterval, err = time.ParseDuration(spec.MinInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid slowQueryExplain minInterval %q: %w", spec.MinInterval, err)
+		}
+		if minInterval < 0 {
+			return nil, fmt.Errorf("slowQueryExplain minInterval must not be negative, got %q", spec.MinInterval)
+		}
+	}
+	return &SlowQueryExplain{
+		threshold:   threshold,
+		minInterval: minInterval,
+		plans:       make([]SlowQueryPlan, 0, maxPlans),
+	}, nil
+}
+
+// Observe records that statement took elapsed to run with params. If it is
+// slow, and no EXPLAIN of the tool ran within the minimum interval, explain
+// is called in the background and its plan stored. Observe never waits for
+// explain. The values of the sensitive parameters among values, those of the
+// invocation, are redacted from the stored plan.
+func (s *SlowQueryExplain) Observe(ctx context.Context, toolName, statement string, params []any, values ParamValues, elapsed time.Duration, explain ExplainFunc) {
+	if s == nil || elapsed < s.threshold {
+		return
+	}
+	s.mu.Lock()
+	now := time.Now()
+	if s.running || (!s.lastExplain.IsZero() && now.Sub(s.lastExplain) < s.minInterval) {
+		s.mu.Unlock()
+		return
+	}
+	s.running = true
+	s.lastExplain = now
+	s.wg.Add(1)
+	s.mu.Unlock()
+
+	// the EXPLAIN outlives the invocation, so it must not be canceled with it
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		defer s.wg.Done()
+		ctx, cancel := context.WithTimeout(ctx, slowQueryExplainTimeout)
+		defer cancel()
+
+		plan := SlowQueryPlan{
+			Tool:      toolName,
+			Statement: values.RedactString(statement),
+			Params:    values.RedactArgs(params),
+			Duration:  elapsed.String(),
+		}
+		p, err := explain(ctx, statement, params)
+		if err != nil {
+			plan.Error = values.RedactString(err.Error())
+			if logger, lErr := util.LoggerFromContext(ctx); lErr == nil {
+				logger.WarnContext(ctx, fmt.Sprintf("unable to explain slow query of tool %q: %s", toolName, plan.Error))
+			}
+		} else {
+			plan.Plan = p
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		plan.CapturedAt = time.Now()
+		s.store(plan)
+		s.running = false
+	}()
+}
+
+// store adds plan to the ring buffer, evicting the oldest plan if it is full.
+func (s *SlowQueryExplain) store(plan SlowQueryPlan) {
+	if len(s.plans) < cap(s.plans) {
+		s.plans = append(s.plans, plan)
+		return
+	}
+	s.plans[s.next] = plan
+	s.next = (s.next + 1) % len(s.plans)
+}
+
+// Plans returns the captured plans, newest first.
+func (s *SlowQueryExplain) Plans() []SlowQueryPlan {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	plans := make([]SlowQueryPlan, 0, len(s.plans))
+	for i := len(s.plans) - 1; i >= 0; i-- {
+		plans = append(plans, s.plans[(s.next+i)%len(s.plans)])
+	}
+	return plans
+}
+
+// Wait waits for the running EXPLAIN, if any, to complete.
+func (s *SlowQueryExplain) Wait() {
+	if s != nil {
+		s.wg.Wait()
+	}
+}
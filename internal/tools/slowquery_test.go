@@ -0,0 +1,143 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestNewSlowQueryExplain(t *testing.T) {
+	tcs := []struct {
+		desc    string
+		spec    tools.SlowQueryExplainSpec
+		wantErr string
+	}{
+		{desc: "threshold only", spec: tools.SlowQueryExplainSpec{Threshold: "500ms"}},
+		{desc: "all fields", spec: tools.SlowQueryExplainSpec{Threshold: "1s", MaxPlans: 5, MinInterval: "10s"}},
+		{desc: "invalid threshold", spec: tools.SlowQueryExplainSpec{Threshold: "fast"}, wantErr: "invalid slowQueryExplain threshold"},
+		{desc: "zero threshold", spec: tools.SlowQueryExplainSpec{Threshold: "0s"}, wantErr: "must be positive"},
+		{desc: "negative max plans", spec: tools.SlowQueryExplainSpec{Threshold: "1s", MaxPlans: -1}, wantErr: "maxPlans must not be negative"},
+		{desc: "invalid min interval", spec: tools.SlowQueryExplainSpec{Threshold: "1s", MinInterval: "often"}, wantErr: "invalid slowQueryExplain minInterval"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := tools.NewSlowQueryExplain(&tc.spec)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("got error %v, want one containing %q", err, tc.wantErr)
+			}
+		})
+	}
+
+	if s, err := tools.NewSlowQueryExplain(nil); s != nil || err != nil {
+		t.Fatalf("got %v, %v for a nil spec, want nil, nil", s, err)
+	}
+}
+
+func TestSlowQueryExplainCapture(t *testing.T) {
+	s, err := tools.NewSlowQueryExplain(&tools.SlowQueryExplainSpec{Threshold: "100ms", MaxPlans: 2, MinInterval: "0s"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	explain := func(_ context.Context, statement string, params []any) (any, error) {
+		if strings.Contains(statement, "broken") {
+			return nil, errors.New("syntax error")
+		}
+		return map[string]any{"Plan": statement}, nil
+	}
+
+	// fast queries are not explained
+	s.Observe(context.Background(), "my-tool", "SELECT 0", nil, nil, 10*time.Millisecond, explain)
+	s.Wait()
+	if got := s.Plans(); len(got) != 0 {
+		t.Fatalf("unexpected plans for a fast query: %v", got)
+	}
+
+	for i := 1; i <= 3; i++ {
+		s.Observe(context.Background(), "my-tool", fmt.Sprintf("SELECT %d", i), []any{i}, nil, time.Duration(i)*time.Second, explain)
+		s.Wait()
+	}
+	s.Observe(context.Background(), "my-tool", "SELECT broken", nil, nil, time.Second, explain)
+	s.Wait()
+
+	// only the two newest plans are kept, newest first
+	got := s.Plans()
+	for i := range got {
+		if got[i].CapturedAt.IsZero() {
+			t.Errorf("plan %d has no capture time", i)
+		}
+		got[i].CapturedAt = time.Time{}
+	}
+	want := []tools.SlowQueryPlan{
+		{Tool: "my-tool", Statement: "SELECT broken", Params: []any{}, Duration: "1s", Error: "syntax error"},
+		{Tool: "my-tool", Statement: "SELECT 3", Params: []any{3}, Duration: "3s", Plan: map[string]any{"Plan": "SELECT 3"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected plans: diff %v", diff)
+	}
+}
+
+func TestSlowQueryExplainRateLimit(t *testing.T) {
+	s, err := tools.NewSlowQueryExplain(&tools.SlowQueryExplainSpec{Threshold: "100ms"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	release := make(chan struct{})
+	calls := 0
+	explain := func(ctx context.Context, statement string, params []any) (any, error) {
+		calls++
+		<-release
+		return "plan", nil
+	}
+
+	// Observe returns while the EXPLAIN is still running
+	done := make(chan struct{})
+	go func() {
+		s.Observe(context.Background(), "my-tool", "SELECT 1", nil, nil, time.Second, explain)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Observe blocked on the EXPLAIN")
+	}
+
+	// slow queries within the minimum interval are not explained
+	s.Observe(context.Background(), "my-tool", "SELECT 2", nil, nil, time.Second, explain)
+	close(release)
+	s.Wait()
+	s.Observe(context.Background(), "my-tool", "SELECT 3", nil, nil, time.Second, explain)
+	s.Wait()
+
+	if calls != 1 {
+		t.Fatalf("got %d EXPLAINs, want 1", calls)
+	}
+	if got := s.Plans(); len(got) != 1 || got[0].Statement != "SELECT 1" {
+		t.Fatalf("unexpected plans: %v", got)
+	}
+}
@@ -165,8 +165,8 @@ func (t Tool) getStatement() string {
 	}
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	paramsMap := params.AsMap()
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
 
 	// Get the appropriate SQL statement based on dialect
 	statement := t.getStatement()
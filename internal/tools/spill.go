@@ -0,0 +1,267 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+)
+
+// Formats of the files that results are spilled to.
+const (
+	SpillFormatCSV    = "csv"
+	SpillFormatNDJSON = "ndjson"
+)
+
+const (
+	// defaultSpillMaxBytes is the threshold of a SpillSpec that sets neither
+	// maxRows nor maxBytes.
+	defaultSpillMaxBytes = 1 << 20
+	// defaultSpillPreviewRows is the number of rows returned inline when
+	// previewRows is not set.
+	defaultSpillPreviewRows = 10
+)
+
+// SpillSpec is the kind-agnostic `spillToFile` block of a tool config. A
+// result that exceeds one of its thresholds is written to a file that can be
+// downloaded, and only a preview of it is returned.
+type SpillSpec struct {
+	// MaxRows is the largest number of rows returned inline. Zero means no
+	// limit on the number of rows.
+	MaxRows int `yaml:"maxRows"`
+	// MaxBytes is the largest size of the rows, serialized as JSON, returned
+	// inline. Zero means no limit on the size, unless MaxRows is also zero.
+	MaxBytes int `yaml:"maxBytes"`
+	// PreviewRows is the number of rows returned with a spilled result. It
+	// defaults to 10, or to MaxRows if that is lower.
+	PreviewRows int `yaml:"previewRows"`
+	// Format is the format of the file, either "csv" or "ndjson".
+	Format string `yaml:"format"`
+}
+
+// Spill is a validated SpillSpec.
+type Spill struct {
+	maxRows     int
+	maxBytes    int
+	previewRows int
+	format      string
+}
+
+// NewSpill validates spec and fills in its defaults.
+func NewSpill(spec SpillSpec) (*Spill, error) {
+	if spec.MaxRows < 0 {
+		return nil, fmt.Errorf("maxRows must not be negative, got %d", spec.MaxRows)
+	}
+	if spec.MaxBytes < 0 {
+		return nil, fmt.Errorf("maxBytes must not be negative, got %d", spec.MaxBytes)
+	}
+	if spec.PreviewRows < 0 {
+		return nil, fmt.Errorf("previewRows must not be negative, got %d", spec.PreviewRows)
+	}
+	s := &Spill{
+		maxRows:     spec.MaxRows,
+		maxBytes:    spec.MaxBytes,
+		previewRows: spec.PreviewRows,
+		format:      spec.Format,
+	}
+	if s.maxRows == 0 && s.maxBytes == 0 {
+		s.maxBytes = defaultSpillMaxBytes
+	}
+	if s.previewRows == 0 {
+		s.previewRows = defaultSpillPreviewRows
+		if s.maxRows > 0 {
+			s.previewRows = min(s.previewRows, s.maxRows)
+		}
+	}
+	switch s.format {
+	case "":
+		s.format = SpillFormatCSV
+	case SpillFormatCSV, SpillFormatNDJSON:
+	default:
+		return nil, fmt.Errorf("format must be %q or %q, got %q", SpillFormatCSV, SpillFormatNDJSON, s.format)
+	}
+	if s.maxRows > 0 && s.previewRows > s.maxRows {
+		return nil, fmt.Errorf("previewRows (%d) must not exceed maxRows (%d)", s.previewRows, s.maxRows)
+	}
+	return s, nil
+}
+
+// exceeds reports whether a result of the given number of rows and bytes
+// must be spilled.
+func (s *Spill) exceeds(rows, bytes int) bool {
+	return (s.maxRows > 0 && rows > s.maxRows) || (s.maxBytes > 0 && bytes > s.maxBytes)
+}
+
+// Artifact describes a result that was spilled to a file.
+type Artifact struct {
+	ID     string `json:"id"`
+	Format string `json:"format"`
+	Rows   int    `json:"rows"`
+	// DownloadURL is a signed URL that the file can be downloaded from until
+	// ExpiresAt, using the same credentials as the tool that produced it.
+	DownloadURL string    `json:"downloadUrl"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+// ArtifactStore stores the results spilled by tools.
+type ArtifactStore interface {
+	// Create starts a new artifact in the given format holding a result of
+	// the named tool.
+	Create(ctx context.Context, toolName string, format string) (ArtifactWriter, error)
+}
+
+// ArtifactWriter writes the rows of an artifact.
+type ArtifactWriter interface {
+	WriteRow(row any) error
+	// Commit completes the artifact and makes it available for download.
+	Commit() (Artifact, error)
+	// Abort discards the artifact. It does nothing after Commit.
+	Abort()
+}
+
+// artifactStoreKey is the key used to store the ArtifactStore within context.
+type artifactStoreKey struct{}
+
+// WithArtifactStore adds the ArtifactStore that tools spill results to into
+// the context.
+func WithArtifactStore(ctx context.Context, store ArtifactStore) context.Context {
+	return context.WithValue(ctx, artifactStoreKey{}, store)
+}
+
+// ArtifactStoreFromContext returns the ArtifactStore of the context, or nil
+// if there is none.
+func ArtifactStoreFromContext(ctx context.Context) ArtifactStore {
+	store, _ := ctx.Value(artifactStoreKey{}).(ArtifactStore)
+	return store
+}
+
+// SpillConfig wraps a ToolConfig with the kind-agnostic `spillToFile` field.
+type SpillConfig struct {
+	ToolConfig
+	Spill *Spill
+}
+
+// validate interface
+var _ ToolConfig = SpillConfig{}
+
+func (cfg SpillConfig) Initialize(srcs map[string]sources.Source) (Tool, error) {
+	t, err := cfg.ToolConfig.Initialize(srcs)
+	if err != nil {
+		return nil, err
+	}
+	return SpillTool{Tool: t, Spill: cfg.Spill}, nil
+}
+
+// SpillTool writes the results of a Tool that exceed the thresholds of its
+// Spill to the ArtifactStore of the context, and returns a preview of them
+// along with the Artifact.
+type SpillTool struct {
+	Tool
+	Spill *Spill
+}
+
+func (t SpillTool) unwrap() Tool {
+	return t.Tool
+}
+
+func (t SpillTool) Invoke(ctx context.Context, inv Invocation) (any, error) {
+	res, err := t.Tool.Invoke(ctx, inv)
+	if err != nil {
+		return nil, err
+	}
+	var it RowIterator
+	switch r := res.(type) {
+	case RowIterator:
+		it = r
+	case []any:
+		it = NewSliceRowIterator(r)
+	default:
+		return res, nil
+	}
+	defer it.Close()
+
+	// rows are buffered until a threshold is exceeded, after which they are
+	// streamed to the artifact
+	var rows []any
+	size := 0
+	for it.Next() {
+		row, err := it.Row()
+		if err != nil {
+			return nil, err
+		}
+		b, err := json.Marshal(row)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+		size += len(b)
+		if t.Spill.exceeds(len(rows), size) {
+			return t.spill(ctx, rows, it)
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// spill writes the buffered rows and the remaining rows of it to a new
+// artifact.
+func (t SpillTool) spill(ctx context.Context, rows []any, it RowIterator) (any, error) {
+	name := t.McpManifest().Name
+	store := ArtifactStoreFromContext(ctx)
+	if store == nil {
+		return nil, fmt.Errorf("the result of tool %q exceeds its `spillToFile` threshold, but spilling results to files is not available", name)
+	}
+	w, err := store.Create(ctx, name, t.Spill.format)
+	if err != nil {
+		return nil, fmt.Errorf("unable to spill result to file: %w", err)
+	}
+	defer w.Abort()
+
+	preview := rows[:min(len(rows), t.Spill.previewRows)]
+	for _, row := range rows {
+		if err := w.WriteRow(row); err != nil {
+			return nil, fmt.Errorf("unable to spill result to file: %w", err)
+		}
+	}
+	for it.Next() {
+		row, err := it.Row()
+		if err != nil {
+			return nil, err
+		}
+		if err := w.WriteRow(row); err != nil {
+			return nil, fmt.Errorf("unable to spill result to file: %w", err)
+		}
+		if len(preview) < t.Spill.previewRows {
+			preview = append(preview, row)
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	artifact, err := w.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("unable to spill result to file: %w", err)
+	}
+	return map[string]any{
+		"preview":  preview,
+		"artifact": artifact,
+	}, nil
+}
@@ -0,0 +1,193 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func mustSpill(t *testing.T, spec tools.SpillSpec) *tools.Spill {
+	t.Helper()
+	s, err := tools.NewSpill(spec)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return s
+}
+
+func TestNewSpillErrors(t *testing.T) {
+	tcs := []struct {
+		desc string
+		spec tools.SpillSpec
+		err  string
+	}{
+		{desc: "negative maxRows", spec: tools.SpillSpec{MaxRows: -1}, err: "maxRows must not be negative"},
+		{desc: "negative maxBytes", spec: tools.SpillSpec{MaxBytes: -1}, err: "maxBytes must not be negative"},
+		{desc: "negative previewRows", spec: tools.SpillSpec{PreviewRows: -1}, err: "previewRows must not be negative"},
+		{desc: "unknown format", spec: tools.SpillSpec{Format: "xlsx"}, err: `format must be "csv" or "ndjson", got "xlsx"`},
+		{desc: "preview larger than maxRows", spec: tools.SpillSpec{MaxRows: 5, PreviewRows: 6}, err: "previewRows (6) must not exceed maxRows (5)"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := tools.NewSpill(tc.spec)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("unexpected error: got %v, want substring %q", err, tc.err)
+			}
+		})
+	}
+}
+
+// memoryArtifactStore keeps spilled artifacts in memory.
+type memoryArtifactStore struct {
+	artifacts map[string]*memoryArtifact
+}
+
+type memoryArtifact struct {
+	tool      string
+	format    string
+	rows      []any
+	committed bool
+	aborted   bool
+}
+
+func (s *memoryArtifactStore) Create(_ context.Context, toolName string, format string) (tools.ArtifactWriter, error) {
+	a := &memoryArtifact{tool: toolName, format: format}
+	s.artifacts[toolName] = a
+	return a, nil
+}
+
+func (a *memoryArtifact) WriteRow(row any) error {
+	a.rows = append(a.rows, row)
+	return nil
+}
+
+func (a *memoryArtifact) Commit() (tools.Artifact, error) {
+	a.committed = true
+	return tools.Artifact{ID: "1", Format: a.format, Rows: len(a.rows), DownloadURL: "/api/artifacts/1"}, nil
+}
+
+func (a *memoryArtifact) Abort() {
+	if !a.committed {
+		a.aborted = true
+	}
+}
+
+func numberedRows(n int) []any {
+	rows := make([]any, n)
+	for i := range rows {
+		rows[i] = map[string]any{"id": i}
+	}
+	return rows
+}
+
+func TestSpillToolInvoke(t *testing.T) {
+	tcs := []struct {
+		desc        string
+		spec        tools.SpillSpec
+		res         any
+		wantSpilled bool
+		wantRows    int
+		wantPreview []any
+		want        any
+	}{
+		{
+			desc: "below row threshold",
+			spec: tools.SpillSpec{MaxRows: 3},
+			res:  numberedRows(3),
+			want: numberedRows(3),
+		},
+		{
+			desc:        "above row threshold",
+			spec:        tools.SpillSpec{MaxRows: 3, PreviewRows: 2},
+			res:         numberedRows(4),
+			wantSpilled: true,
+			wantRows:    4,
+			wantPreview: numberedRows(2),
+		},
+		{
+			desc:        "above byte threshold",
+			spec:        tools.SpillSpec{MaxBytes: 20},
+			res:         numberedRows(5),
+			wantSpilled: true,
+			wantRows:    5,
+			wantPreview: numberedRows(5),
+		},
+		{
+			desc:        "preview streamed past the threshold",
+			spec:        tools.SpillSpec{MaxBytes: 10, PreviewRows: 4},
+			res:         tools.NewSliceRowIterator(numberedRows(6)),
+			wantSpilled: true,
+			wantRows:    6,
+			wantPreview: numberedRows(4),
+		},
+		{
+			desc: "other result",
+			spec: tools.SpillSpec{MaxRows: 1},
+			res:  "done",
+			want: "done",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			store := &memoryArtifactStore{artifacts: map[string]*memoryArtifact{}}
+			ctx := tools.WithArtifactStore(context.Background(), store)
+			inner := rowsTool{mockTool: mockTool{name: "export"}, res: tc.res}
+			tool := tools.SpillTool{Tool: inner, Spill: mustSpill(t, tc.spec)}
+
+			got, err := tool.Invoke(ctx, tools.Invocation{})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			a, spilled := store.artifacts["export"]
+			if spilled != tc.wantSpilled {
+				t.Fatalf("unexpected spill: got %t, want %t", spilled, tc.wantSpilled)
+			}
+			if !spilled {
+				if diff := cmp.Diff(tc.want, got); diff != "" {
+					t.Fatalf("unexpected result: diff %v", diff)
+				}
+				return
+			}
+			if !a.committed || a.format != tools.SpillFormatCSV {
+				t.Fatalf("unexpected artifact: %+v", a)
+			}
+			if diff := cmp.Diff(numberedRows(tc.wantRows), a.rows); diff != "" {
+				t.Fatalf("unexpected spilled rows: diff %v", diff)
+			}
+			want := map[string]any{
+				"preview":  tc.wantPreview,
+				"artifact": tools.Artifact{ID: "1", Format: "csv", Rows: tc.wantRows, DownloadURL: "/api/artifacts/1"},
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Fatalf("unexpected result: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestSpillToolInvokeWithoutStore(t *testing.T) {
+	inner := rowsTool{mockTool: mockTool{name: "export"}, res: numberedRows(2)}
+	tool := tools.SpillTool{Tool: inner, Spill: mustSpill(t, tools.SpillSpec{MaxRows: 1})}
+	_, err := tool.Invoke(context.Background(), tools.Invocation{})
+	want := `the result of tool "export" exceeds its ` + "`spillToFile`" + ` threshold, but spilling results to files is not available`
+	if err == nil || err.Error() != want {
+		t.Fatalf("unexpected error: got %v, want %q", err, want)
+	}
+}
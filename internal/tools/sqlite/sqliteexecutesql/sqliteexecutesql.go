@@ -33,6 +33,7 @@ func init() {
 	if !tools.Register(kind, newConfig) {
 		panic(fmt.Sprintf("tool kind %q already registered", kind))
 	}
+	tools.RegisterOutputSchema(kind, tools.RowsOutputSchema)
 }
 
 func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
@@ -111,8 +112,8 @@ type Tool struct {
 	mcpManifest tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	sql, ok := params.AsMap()["sql"].(string)
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	sql, ok := inv.Params.AsMap()["sql"].(string)
 	if !ok {
 		return nil, fmt.Errorf("missing or invalid 'sql' parameter")
 	}
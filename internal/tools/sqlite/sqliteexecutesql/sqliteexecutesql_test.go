@@ -279,7 +279,7 @@ func TestTool_Invoke(t *testing.T) {
 				Parameters:   tt.fields.Parameters,
 				DB:           tt.fields.DB,
 			}
-			got, err := tr.Invoke(tt.args.ctx, tt.args.params, tt.args.accessToken)
+			got, err := tr.Invoke(tt.args.ctx, tools.Invocation{Params: tt.args.params, AccessToken: tt.args.accessToken})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Tool.Invoke() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -0,0 +1,442 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ScopeDialect is the SQL dialect of the statements checked by a Scope.
+type ScopeDialect int
+
+const (
+	// PostgresDialect quotes identifiers with double quotes, strings with
+	// single quotes or dollar quotes, and nests block comments.
+	PostgresDialect ScopeDialect = iota
+	// MySQLDialect quotes identifiers with backquotes, strings with single
+	// or double quotes, and starts comments with `#` too. Names are compared
+	// case-insensitively.
+	MySQLDialect
+)
+
+// Scope is the list of schemas, or databases, that the statements of an
+// execute-sql tool may reference, such as the `schemaScope` of
+// postgres-execute-sql.
+//
+// Statements are tokenized to find the qualifiers of the names of tables
+// and functions, which are checked against the scope. This is a defense in
+// depth, not a replacement for grants: references that cannot be told apart
+// from the columns of aliased tables without a parser are let through.
+type Scope struct {
+	dialect ScopeDialect
+	// kind is what the scope lists, "schema" or "database".
+	kind  string
+	names []string
+}
+
+// NewScope returns the Scope of kind listing names, or nil if names is
+// empty.
+func NewScope(dialect ScopeDialect, kind string, names []string) (*Scope, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	for i, n := range names {
+		if n == "" {
+			return nil, fmt.Errorf("%sScope must not list an empty name", kind)
+		}
+		if slices.ContainsFunc(names[:i], func(m string) bool { return dialect.equal(m, n) }) {
+			return nil, fmt.Errorf("%sScope lists %q more than once", kind, n)
+		}
+	}
+	return &Scope{dialect: dialect, kind: kind, names: names}, nil
+}
+
+// Names returns the names listed by s.
+func (s *Scope) Names() []string {
+	return s.names
+}
+
+// Contains returns whether s lists name.
+func (s *Scope) Contains(name string) bool {
+	return slices.ContainsFunc(s.names, func(n string) bool { return s.dialect.equal(n, name) })
+}
+
+// ScopeError is returned for statements referencing a schema, or database,
+// outside of the Scope of their tool.
+type ScopeError struct {
+	Kind      string
+	Reference string
+	Scope     []string
+}
+
+func (e *ScopeError) Error() string {
+	return fmt.Sprintf("statement references %s %q, which is not in the %sScope %q", e.Kind, e.Reference, e.Kind, e.Scope)
+}
+
+// scopeOverrides are the names of Postgres settings and functions that
+// would let a statement change the search_path set for the scope.
+var scopeOverrides = []string{"search_path", "set_config"}
+
+// Check returns a *ScopeError if statement references a schema, or
+// database, outside of s. A nil Scope allows all statements.
+func (s *Scope) Check(statement string) error {
+	if s == nil {
+		return nil
+	}
+	tokens := scanSQL(statement, s.dialect)
+	if s.dialect == PostgresDialect {
+		for _, t := range tokens {
+			if t.kind == sqlIdent && slices.Contains(scopeOverrides, strings.ToLower(t.text)) {
+				return fmt.Errorf("statement must not reference %s, which is set by the %sScope", t.text, s.kind)
+			}
+		}
+	}
+	for _, ref := range s.dialect.references(tokens) {
+		if !s.Contains(ref) {
+			return &ScopeError{Kind: s.kind, Reference: ref, Scope: s.names}
+		}
+	}
+	return nil
+}
+
+// References returns the schemas, or databases, that statement references,
+// as found by Check.
+func (d ScopeDialect) References(statement string) []string {
+	return d.references(scanSQL(statement, d))
+}
+
+func (d ScopeDialect) equal(a, b string) bool {
+	if d == MySQLDialect {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+type sqlTokenKind int
+
+const (
+	sqlIdent sqlTokenKind = iota
+	sqlPunct
+	sqlOther
+)
+
+// sqlToken is a token of a statement. The text of identifiers is their
+// name: unquoted, and folded to lower case if they are unquoted Postgres
+// identifiers. The text of literals is empty.
+type sqlToken struct {
+	kind   sqlTokenKind
+	text   string
+	quoted bool
+}
+
+func (t sqlToken) is(kind sqlTokenKind, text string) bool {
+	return t.kind == kind && t.text == text
+}
+
+// keyword returns the keyword t is, in lower case, or "" if t is not an
+// unquoted identifier.
+func (t sqlToken) keyword() string {
+	if t.kind != sqlIdent || t.quoted {
+		return ""
+	}
+	return strings.ToLower(t.text)
+}
+
+// scanSQL splits statement into tokens, skipping whitespace and comments.
+func scanSQL(s string, dialect ScopeDialect) []sqlToken {
+	var tokens []sqlToken
+	other := sqlToken{kind: sqlOther}
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v':
+			i++
+		case strings.HasPrefix(s[i:], "--") && (dialect == PostgresDialect || i+2 == len(s) || isSQLSpace(s[i+2])):
+			i = skipLine(s, i)
+		case c == '#' && dialect == MySQLDialect:
+			i = skipLine(s, i)
+		case strings.HasPrefix(s[i:], "/*"):
+			i = skipBlockComment(s, i, dialect == PostgresDialect)
+		case c == '\'':
+			i = skipString(s, i, dialect == MySQLDialect)
+			tokens = append(tokens, other)
+		case c == '"' && dialect == MySQLDialect:
+			i = skipString(s, i, true)
+			tokens = append(tokens, other)
+		case c == '"' || (c == '`' && dialect == MySQLDialect):
+			var name string
+			name, i = readQuotedIdent(s, i)
+			tokens = append(tokens, sqlToken{kind: sqlIdent, text: name, quoted: true})
+		case c == '$' && dialect == PostgresDialect:
+			end, ok := skipDollarQuoted(s, i)
+			if !ok {
+				end = i + 1
+			}
+			i = end
+			tokens = append(tokens, other)
+		case isIdentStart(c):
+			j := i + 1
+			for j < len(s) && isIdentPart(s[j]) {
+				j++
+			}
+			word := s[i:j]
+			if dialect == PostgresDialect && j < len(s) {
+				// escape strings, whose backslashes escape quotes, and
+				// Unicode identifiers and strings
+				switch {
+				case strings.EqualFold(word, "e") && s[j] == '\'':
+					i = skipString(s, j, true)
+					tokens = append(tokens, other)
+					continue
+				case strings.EqualFold(word, "u") && strings.HasPrefix(s[j:], "&'"):
+					i = skipString(s, j+1, false)
+					tokens = append(tokens, other)
+					continue
+				case strings.EqualFold(word, "u") && strings.HasPrefix(s[j:], `&"`):
+					var name string
+					name, i = readQuotedIdent(s, j+1)
+					tokens = append(tokens, sqlToken{kind: sqlIdent, text: name, quoted: true})
+					continue
+				}
+			}
+			if dialect == PostgresDialect {
+				word = strings.ToLower(word)
+			}
+			tokens = append(tokens, sqlToken{kind: sqlIdent, text: word})
+			i = j
+		case c >= '0' && c <= '9':
+			j := i + 1
+			for j < len(s) && (isIdentPart(s[j]) || s[j] == '.') {
+				j++
+			}
+			i = j
+			tokens = append(tokens, other)
+		case c == '.' || c == '(' || c == ')' || c == ',' || c == ';':
+			tokens = append(tokens, sqlToken{kind: sqlPunct, text: string(c)})
+			i++
+		default:
+			tokens = append(tokens, sqlToken{kind: sqlOther, text: string(c)})
+			i++
+		}
+	}
+	return tokens
+}
+
+func isSQLSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9') || c == '$'
+}
+
+// skipLine returns the end of the line comment starting at i.
+func skipLine(s string, i int) int {
+	if end := strings.IndexByte(s[i:], '\n'); end >= 0 {
+		return i + end + 1
+	}
+	return len(s)
+}
+
+// skipBlockComment returns the end of the block comment starting at i.
+func skipBlockComment(s string, i int, nested bool) int {
+	depth := 0
+	for j := i; j < len(s); {
+		switch {
+		case strings.HasPrefix(s[j:], "/*") && (nested || depth == 0):
+			depth++
+			j += 2
+		case strings.HasPrefix(s[j:], "*/"):
+			depth--
+			j += 2
+			if depth == 0 {
+				return j
+			}
+		default:
+			j++
+		}
+	}
+	return len(s)
+}
+
+// skipString returns the end of the string starting with the quote at i,
+// in which doubled quotes, and backslashes if backslash is set, escape
+// quotes.
+func skipString(s string, i int, backslash bool) int {
+	q := s[i]
+	for j := i + 1; j < len(s); j++ {
+		switch {
+		case backslash && s[j] == '\\':
+			j++
+		case s[j] == q && j+1 < len(s) && s[j+1] == q:
+			j++
+		case s[j] == q:
+			return j + 1
+		}
+	}
+	return len(s)
+}
+
+// readQuotedIdent returns the name of the identifier quoted by the quote at
+// i, in which doubled quotes escape quotes, and its end.
+func readQuotedIdent(s string, i int) (string, int) {
+	q := s[i]
+	var name strings.Builder
+	for j := i + 1; j < len(s); j++ {
+		if s[j] != q {
+			name.WriteByte(s[j])
+			continue
+		}
+		if j+1 < len(s) && s[j+1] == q {
+			name.WriteByte(q)
+			j++
+			continue
+		}
+		return name.String(), j + 1
+	}
+	return name.String(), len(s)
+}
+
+// skipDollarQuoted returns the end of the Postgres dollar-quoted string
+// starting at i, such as $tag$...$tag$, and whether there is one.
+func skipDollarQuoted(s string, i int) (int, bool) {
+	j := i + 1
+	if j < len(s) && isIdentStart(s[j]) {
+		for j < len(s) && isIdentPart(s[j]) && s[j] != '$' {
+			j++
+		}
+	}
+	if j >= len(s) || s[j] != '$' {
+		return i, false
+	}
+	delim := s[i : j+1]
+	if end := strings.Index(s[j+1:], delim); end >= 0 {
+		return j + 1 + end + len(delim), true
+	}
+	return len(s), true
+}
+
+var (
+	// tableKeywords are followed by the name of a table, like FROM.
+	tableKeywords = []string{"join", "into", "update", "table", "truncate", "view", "copy", "describe"}
+	// clauseKeywords end the list of tables of a FROM clause.
+	clauseKeywords = []string{"where", "group", "order", "having", "limit", "on", "using", "window", "union", "except", "intersect", "set", "values", "returning", "select", "offset", "fetch", "for"}
+	// modifierKeywords may come between a keyword and the name it is
+	// followed by.
+	modifierKeywords = []string{"only", "lateral", "if", "not", "exists"}
+	// queryKeywords start a query, when they open a parenthesis.
+	queryKeywords = []string{"select", "with", "values"}
+)
+
+// scopeKeywords are followed by the name of a schema, or database, in d.
+func (d ScopeDialect) scopeKeywords() []string {
+	if d == MySQLDialect {
+		return []string{"use", "database", "schema"}
+	}
+	return []string{"schema"}
+}
+
+// level is the state of a level of parentheses.
+type level struct {
+	// query is whether the level holds a query, rather than the arguments
+	// of a function or an expression.
+	query bool
+	// inFrom is whether the level is in the list of tables of a FROM.
+	inFrom bool
+}
+
+// references returns the schemas, or databases, referenced by tokens:
+//
+//   - the qualifiers of the names following FROM, JOIN, INTO, UPDATE and
+//     other keywords followed by table names, and of the names in the
+//     list of tables of a FROM;
+//   - the qualifiers of the names of functions;
+//   - the first of three or more qualified names, such as
+//     schema.table.column;
+//   - the names following SCHEMA, and USE and DATABASE in MySQL.
+//
+// The qualified columns of tables, such as alias.column, are not
+// references.
+func (d ScopeDialect) references(tokens []sqlToken) []string {
+	var refs []string
+	levels := []level{{query: true}}
+	// tablePos and scopePos are whether the next name is the name of a
+	// table, or of a schema or database
+	tablePos, scopePos := false, false
+	prev := ""
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		cur := &levels[len(levels)-1]
+		if t.kind != sqlIdent {
+			tablePos, scopePos = false, false
+			switch {
+			case t.is(sqlPunct, "("):
+				query := i+1 < len(tokens) && slices.Contains(queryKeywords, tokens[i+1].keyword())
+				levels = append(levels, level{query: query})
+			case t.is(sqlPunct, ")"):
+				if len(levels) > 1 {
+					levels = levels[:len(levels)-1]
+				}
+			case t.is(sqlPunct, ","):
+				tablePos = cur.inFrom
+			case t.is(sqlPunct, ";"):
+				levels = []level{{query: true}}
+			}
+			prev = ""
+			continue
+		}
+
+		kw := t.keyword()
+		switch {
+		case kw == "from" && cur.query && prev != "distinct":
+			cur.inFrom, tablePos, scopePos = true, true, false
+		case kw == "update" && prev == "key":
+			// ON DUPLICATE KEY UPDATE is followed by columns
+			tablePos, scopePos = false, false
+		case slices.Contains(tableKeywords, kw) && cur.query:
+			tablePos, scopePos = true, false
+		case slices.Contains(d.scopeKeywords(), kw):
+			tablePos, scopePos = false, true
+		case slices.Contains(clauseKeywords, kw):
+			cur.inFrom, tablePos, scopePos = false, false, false
+		case slices.Contains(modifierKeywords, kw) && (tablePos || scopePos):
+		default:
+			// a name, possibly qualified
+			parts := []string{t.text}
+			for i+2 < len(tokens) && tokens[i+1].is(sqlPunct, ".") && (tokens[i+2].kind == sqlIdent || tokens[i+2].is(sqlOther, "*")) {
+				parts = append(parts, tokens[i+2].text)
+				i += 2
+			}
+			function := i+1 < len(tokens) && tokens[i+1].is(sqlPunct, "(")
+			switch {
+			case scopePos:
+				refs = append(refs, parts[0])
+			case (tablePos || function) && len(parts) >= 2:
+				refs = append(refs, parts[len(parts)-2])
+			case len(parts) >= 3:
+				refs = append(refs, parts[len(parts)-3])
+			}
+			tablePos, scopePos = false, false
+		}
+		prev = kw
+	}
+	return refs
+}
@@ -0,0 +1,185 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestNewScope(t *testing.T) {
+	if s, err := tools.NewScope(tools.PostgresDialect, "schema", nil); s != nil || err != nil {
+		t.Fatalf("got %v, %v for no names, want nil, nil", s, err)
+	}
+	tcs := []struct {
+		desc    string
+		dialect tools.ScopeDialect
+		names   []string
+		wantErr string
+	}{
+		{desc: "valid", dialect: tools.PostgresDialect, names: []string{"public", "Sales"}},
+		{desc: "case-sensitive names", dialect: tools.PostgresDialect, names: []string{"sales", "Sales"}},
+		{desc: "empty name", dialect: tools.PostgresDialect, names: []string{"public", ""}, wantErr: "schemaScope must not list an empty name"},
+		{desc: "duplicate", dialect: tools.PostgresDialect, names: []string{"public", "public"}, wantErr: `schemaScope lists "public" more than once`},
+		{desc: "case-insensitive duplicate", dialect: tools.MySQLDialect, names: []string{"sales", "SALES"}, wantErr: `schemaScope lists "SALES" more than once`},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := tools.NewScope(tc.dialect, "schema", tc.names)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("got error %v, want one containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func checkScope(t *testing.T, scope *tools.Scope, statement, wantRef string) {
+	t.Helper()
+	err := scope.Check(statement)
+	if wantRef == "" {
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %s", statement, err)
+		}
+		return
+	}
+	var scopeErr *tools.ScopeError
+	if !errors.As(err, &scopeErr) {
+		t.Fatalf("got error %v for %q, want a ScopeError", err, statement)
+	}
+	if scopeErr.Reference != wantRef {
+		t.Fatalf("got reference %q for %q, want %q", scopeErr.Reference, statement, wantRef)
+	}
+}
+
+func TestPostgresScope(t *testing.T) {
+	scope, err := tools.NewScope(tools.PostgresDialect, "schema", []string{"public", "Sales"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc      string
+		statement string
+		wantRef   string
+	}{
+		{desc: "unqualified", statement: "SELECT * FROM orders"},
+		{desc: "qualified in scope", statement: `SELECT * FROM public.orders JOIN "Sales".leads ON true`},
+		{desc: "qualified out of scope", statement: "SELECT * FROM other.orders", wantRef: "other"},
+		{desc: "unquoted names are folded", statement: "SELECT * FROM SALES.leads", wantRef: "sales"},
+		{desc: "quoted names are not folded", statement: `SELECT * FROM "Sales".leads`},
+		{desc: "join", statement: "SELECT * FROM orders o JOIN other.customers c ON o.id = c.id", wantRef: "other"},
+		{desc: "list of tables", statement: "SELECT * FROM orders o, other.customers c WHERE o.id = c.id", wantRef: "other"},
+		{desc: "catalog", statement: "SELECT * FROM db.other.orders", wantRef: "other"},
+		{desc: "only", statement: "SELECT * FROM ONLY other.orders", wantRef: "other"},
+		{desc: "insert", statement: "INSERT INTO other.orders (id) VALUES (1)", wantRef: "other"},
+		{desc: "update", statement: "UPDATE other.orders SET id = 1", wantRef: "other"},
+		{desc: "delete", statement: "DELETE FROM other.orders", wantRef: "other"},
+		{desc: "create table", statement: "CREATE TABLE IF NOT EXISTS other.orders (id int)", wantRef: "other"},
+		{desc: "truncate", statement: "TRUNCATE other.orders", wantRef: "other"},
+		{desc: "drop schema", statement: "DROP SCHEMA other CASCADE", wantRef: "other"},
+		{desc: "set schema", statement: "ALTER TABLE orders SET SCHEMA other", wantRef: "other"},
+		{desc: "function", statement: "SELECT other.secret(id) FROM orders", wantRef: "other"},
+		{desc: "column of a qualified table", statement: "SELECT other.orders.id FROM orders", wantRef: "other"},
+		{desc: "subquery", statement: "SELECT * FROM orders WHERE id IN (SELECT id FROM other.orders)", wantRef: "other"},
+		{desc: "after a subquery", statement: "SELECT * FROM (SELECT 1) s, other.orders", wantRef: "other"},
+		{desc: "second statement", statement: "SELECT 1; SELECT * FROM other.orders", wantRef: "other"},
+		{desc: "columns of aliases", statement: "SELECT o.id, c.name FROM orders o JOIN customers c ON o.customer_id = c.id WHERE o.total > 10"},
+		{desc: "all columns of an alias", statement: "SELECT o.* FROM orders o"},
+		{desc: "extract", statement: "SELECT EXTRACT(YEAR FROM o.created_at) FROM orders o"},
+		{desc: "substring", statement: "SELECT substring(o.name FROM 2 FOR 3) FROM orders o"},
+		{desc: "is distinct from", statement: "SELECT * FROM orders o WHERE o.a IS DISTINCT FROM o.b"},
+		{desc: "cast", statement: "SELECT o.id::text FROM orders o"},
+		{desc: "quoted name with a dot", statement: `SELECT * FROM "other.orders"`},
+		{desc: "quoted keyword", statement: `SELECT "from".id FROM orders "from"`},
+		{desc: "quoted name with quotes", statement: `SELECT * FROM "other"".orders"`},
+		{desc: "string", statement: "SELECT 'FROM other.orders' FROM orders"},
+		{desc: "string with doubled quotes", statement: "SELECT 'it''s FROM other.orders' FROM orders"},
+		{desc: "backslash in string", statement: `SELECT 'C:\' FROM orders`},
+		{desc: "escape string", statement: `SELECT E'it\'s FROM other.orders' FROM orders`},
+		{desc: "dollar quotes", statement: "SELECT $$FROM other.orders$$, $fn$ FROM other.t $fn$ FROM orders"},
+		{desc: "positional parameter", statement: "SELECT * FROM orders WHERE id = $1"},
+		{desc: "line comment", statement: "SELECT * FROM orders -- FROM other.orders"},
+		{desc: "block comment", statement: "SELECT * /* FROM other.orders */ FROM orders"},
+		{desc: "nested block comment", statement: "SELECT * /* /* nested */ FROM other.orders */ FROM orders"},
+		{desc: "reference after a comment", statement: "SELECT * /* comment */ FROM other.orders", wantRef: "other"},
+		{desc: "quoted reference", statement: `SELECT * FROM "other"."orders"`, wantRef: "other"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			checkScope(t, scope, tc.statement, tc.wantRef)
+		})
+	}
+
+	for _, statement := range []string{"SET search_path TO other", "SELECT set_config('search_path', 'other', false)"} {
+		if err := scope.Check(statement); err == nil || !strings.Contains(err.Error(), "set by the schemaScope") {
+			t.Errorf("got error %v for %q, want one about the search_path", err, statement)
+		}
+	}
+}
+
+func TestMySQLScope(t *testing.T) {
+	scope, err := tools.NewScope(tools.MySQLDialect, "database", []string{"shop"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc      string
+		statement string
+		wantRef   string
+	}{
+		{desc: "unqualified", statement: "SELECT * FROM orders"},
+		{desc: "qualified in scope", statement: "SELECT * FROM shop.orders"},
+		{desc: "names are case-insensitive", statement: "SELECT * FROM SHOP.orders"},
+		{desc: "qualified out of scope", statement: "SELECT * FROM other.orders", wantRef: "other"},
+		{desc: "backquotes", statement: "SELECT * FROM `other`.`orders`", wantRef: "other"},
+		{desc: "backquoted name with a dot", statement: "SELECT * FROM `other.orders`"},
+		{desc: "backquoted name with backquotes", statement: "SELECT * FROM `other``.orders`"},
+		{desc: "use", statement: "USE other", wantRef: "other"},
+		{desc: "use in scope", statement: "USE `shop`"},
+		{desc: "drop database", statement: "DROP DATABASE IF EXISTS other", wantRef: "other"},
+		{desc: "database function", statement: "SELECT DATABASE()"},
+		{desc: "describe", statement: "DESCRIBE other.orders", wantRef: "other"},
+		{desc: "replace into", statement: "REPLACE INTO other.orders VALUES (1)", wantRef: "other"},
+		{desc: "on duplicate key update", statement: "INSERT INTO orders (id) VALUES (1) ON DUPLICATE KEY UPDATE o.id = 2"},
+		{desc: "columns of aliases", statement: "SELECT o.id FROM orders o JOIN customers c ON o.customer_id = c.id"},
+		{desc: "double-quoted string", statement: `SELECT "FROM other.orders" FROM orders`},
+		{desc: "backslash in string", statement: `SELECT 'it\'s FROM other.orders' FROM orders`},
+		{desc: "hash comment", statement: "SELECT * FROM orders # FROM other.orders"},
+		{desc: "dash comment", statement: "SELECT * FROM orders -- FROM other.orders"},
+		{desc: "double minus", statement: "SELECT 1--1 FROM other.orders", wantRef: "other"},
+		{desc: "block comment", statement: "SELECT * /* FROM other.orders */ FROM orders"},
+		{desc: "block comments do not nest", statement: "SELECT * /* /* */ FROM other.orders", wantRef: "other"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			checkScope(t, scope, tc.statement, tc.wantRef)
+		})
+	}
+}
+
+func TestNilScope(t *testing.T) {
+	var scope *tools.Scope
+	if err := scope.Check("SELECT * FROM other.orders"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
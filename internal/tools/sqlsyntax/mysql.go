@@ -0,0 +1,67 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlsyntax
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/pingcap/tidb/parser"
+	// the parser requires a driver for the values it parses
+	_ "github.com/pingcap/tidb/parser/test_driver"
+)
+
+// mysqlUnsupported matches the statements of MySQL that the TiDB parser does
+// not support, such as stored routines.
+var mysqlUnsupported = regexp.MustCompile(`(?is)^\s*(DELIMITER\b|(CREATE|ALTER|DROP)\s+(OR\s+REPLACE\s+)?(DEFINER\s*=\s*\S+\s+)?(AGGREGATE\s+)?(FUNCTION|TRIGGER|EVENT)\b)`)
+
+// mysqlErrorPattern matches the position in the errors of the TiDB parser.
+var mysqlErrorPattern = regexp.MustCompile(`^line (\d+) column (\d+) near "(.*)"`)
+
+// validateMySQL parses statement with the parser of TiDB, which covers the
+// syntax of MySQL.
+func validateMySQL(statement string) error {
+	if mysqlUnsupported.MatchString(stripLiterals(statement)) {
+		return fmt.Errorf("%w: stored routines, triggers and events are not supported by the MySQL parser", ErrUnsupported)
+	}
+	// MySQL accepts `$1` as an identifier, so that a Postgres placeholder
+	// parses but fails when executed
+	if p := tools.DollarPlaceholders(statement); p.Count > 0 {
+		return &SyntaxError{
+			Dialect: MySQL,
+			Msg:     fmt.Sprintf("$n placeholders are not supported (at %s), use ? instead", strings.Join(p.Positions(), ", ")),
+		}
+	}
+	_, _, err := parser.New().Parse(statement, "", "")
+	if err == nil {
+		return nil
+	}
+	m := mysqlErrorPattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return &SyntaxError{Dialect: MySQL, Msg: err.Error()}
+	}
+	line, _ := strconv.Atoi(m[1])
+	column, _ := strconv.Atoi(m[2])
+	near := m[3]
+	// the parser reports the end of the unexpected token; report its start
+	// when the rest of the statement is quoted in full
+	if near != "" && strings.HasSuffix(statement, near) {
+		line, column = position(statement, len(statement)-len(near))
+	}
+	return &SyntaxError{Dialect: MySQL, Line: line, Column: column, Msg: fmt.Sprintf("near %q", near)}
+}
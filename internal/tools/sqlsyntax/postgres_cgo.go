@@ -0,0 +1,48 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build cgo
+
+package sqlsyntax
+
+import (
+	"errors"
+
+	pg_query "github.com/pganalyze/pg_query_go/v6"
+	pgparser "github.com/pganalyze/pg_query_go/v6/parser"
+)
+
+// postgresSupported is true in builds with cgo, which the Postgres parser
+// requires.
+const postgresSupported = true
+
+// validatePostgres parses statement with the parser of Postgres itself.
+func validatePostgres(statement string) error {
+	_, err := pg_query.Parse(statement)
+	if err == nil {
+		return nil
+	}
+	var perr *pgparser.Error
+	if !errors.As(err, &perr) {
+		return &SyntaxError{Dialect: Postgres, Msg: err.Error()}
+	}
+	synErr := &SyntaxError{Dialect: Postgres, Msg: perr.Message}
+	if perr.Cursorpos > 0 {
+		// the cursor position is a 1-based character offset
+		runes := []rune(statement)
+		offset := len(string(runes[:min(perr.Cursorpos-1, len(runes))]))
+		synErr.Line, synErr.Column = position(statement, offset)
+	}
+	return synErr
+}
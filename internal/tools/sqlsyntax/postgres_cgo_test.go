@@ -0,0 +1,78 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build cgo
+
+package sqlsyntax_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools/sqlsyntax"
+)
+
+func TestValidatePostgres(t *testing.T) {
+	if !sqlsyntax.Supported(sqlsyntax.Postgres) {
+		t.Fatalf("expected Postgres to be supported in builds with cgo")
+	}
+	tcs := []struct {
+		desc      string
+		statement string
+		want      *sqlsyntax.SyntaxError
+	}{
+		{
+			desc:      "select with placeholders and casts",
+			statement: "SELECT id::text, doc ? 'key' FROM t WHERE name ILIKE $1 LIMIT 20 OFFSET 10",
+		},
+		{
+			desc:      "dollar quoting",
+			statement: "DO $$ BEGIN RAISE NOTICE 'hi'; END $$; SELECT 1",
+		},
+		{
+			desc:      "typo",
+			statement: "SELECT 1;\nSELECT * FORM t",
+			want:      &sqlsyntax.SyntaxError{Dialect: sqlsyntax.Postgres, Line: 2, Column: 10, Msg: `syntax error at or near "FORM"`},
+		},
+		{
+			desc:      "mysql backticks",
+			statement: "SELECT `id` FROM t",
+			want:      &sqlsyntax.SyntaxError{Dialect: sqlsyntax.Postgres, Line: 1, Column: 13, Msg: `syntax error at or near "FROM"`},
+		},
+		{
+			desc:      "mysql limit",
+			statement: "SELECT é FROM t LIMIT 10, 20",
+			want:      &sqlsyntax.SyntaxError{Dialect: sqlsyntax.Postgres, Line: 1, Column: 17, Msg: "LIMIT #,# syntax is not supported"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := sqlsyntax.Validate(sqlsyntax.Postgres, tc.statement)
+			if tc.want == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			var got *sqlsyntax.SyntaxError
+			if !errors.As(err, &got) {
+				t.Fatalf("expected syntax error, got %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected error (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sqlsyntax validates the syntax of SQL statements locally, before
+// they are sent to a database, so that a statement written for the wrong
+// dialect fails with its position and a hint rather than a cryptic backend
+// error.
+package sqlsyntax
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// Dialect is a SQL dialect.
+type Dialect string
+
+const (
+	Postgres Dialect = "Postgres"
+	MySQL    Dialect = "MySQL"
+)
+
+// ErrUnsupported is returned for statements that the parser of a dialect is
+// unable to validate. They are executed without validation.
+var ErrUnsupported = errors.New("statement cannot be validated")
+
+// SyntaxError is a syntax error in a statement.
+type SyntaxError struct {
+	Dialect Dialect
+	// Line and Column are the 1-based position of the error, or zero if the
+	// parser did not report it.
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("invalid %s syntax: %s", e.Dialect, e.Msg)
+	}
+	return fmt.Sprintf("invalid %s syntax at line %d, column %d: %s", e.Dialect, e.Line, e.Column, e.Msg)
+}
+
+// position returns the 1-based line and column of the character at offset
+// in statement.
+func position(statement string, offset int) (int, int) {
+	offset = min(max(offset, 0), len(statement))
+	before := statement[:offset]
+	line := strings.Count(before, "\n") + 1
+	column := len([]rune(before[strings.LastIndex(before, "\n")+1:])) + 1
+	return line, column
+}
+
+// Validate parses statement, which may hold several statements, in dialect.
+// It returns a *SyntaxError if the statement is invalid, and an error
+// wrapping ErrUnsupported if the parser is unable to validate it.
+func Validate(dialect Dialect, statement string) (err error) {
+	defer func() {
+		// the parsers are not expected to panic, but a statement must not
+		// take the server down if they do
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %s parser failed: %v", ErrUnsupported, dialect, r)
+		}
+	}()
+	switch dialect {
+	case Postgres:
+		return validatePostgres(statement)
+	case MySQL:
+		return validateMySQL(statement)
+	}
+	return fmt.Errorf("%w: unknown dialect %q", ErrUnsupported, dialect)
+}
+
+// Supported reports whether statements of dialect can be validated by this
+// build.
+func Supported(dialect Dialect) bool {
+	switch dialect {
+	case Postgres:
+		return postgresSupported
+	case MySQL:
+		return true
+	}
+	return false
+}
+
+// CheckSupported returns an error if this build cannot validate statements of
+// dialect, so that the tools set to validate them fail to load rather than
+// execute statements unvalidated.
+func CheckSupported(dialect Dialect) error {
+	if Supported(dialect) {
+		return nil
+	}
+	return fmt.Errorf("validateSyntax is not supported by this build, which cannot parse %s statements", dialect)
+}
+
+// Check validates statement before a tool executes it. It returns the error
+// the tool must return, with a hint if the statement looks like it was
+// written for another dialect, or nil if the statement can be executed.
+// Statements that cannot be validated are executed, and logged at debug
+// level.
+func Check(ctx context.Context, dialect Dialect, statement string) error {
+	err := Validate(dialect, statement)
+	if errors.Is(err, ErrUnsupported) {
+		if logger, lerr := util.LoggerFromContext(ctx); lerr == nil {
+			logger.DebugContext(ctx, fmt.Sprintf("skipping syntax validation: %s", err))
+		}
+		return nil
+	}
+	if err == nil {
+		return nil
+	}
+	if hint := Hint(dialect, statement); hint != "" {
+		return &tools.HintError{Err: err, Hint: hint}
+	}
+	return err
+}
+
+var (
+	castPattern       = regexp.MustCompile(`::\s*[A-Za-z_]`)
+	limitPattern      = regexp.MustCompile(`(?i)\bLIMIT\s+\d+\s*,\s*\d+`)
+	autoIncPattern    = regexp.MustCompile(`(?i)\bAUTO_INCREMENT\b`)
+	postgresOnlyWords = regexp.MustCompile(`(?i)\b(ILIKE|RETURNING)\b`)
+)
+
+// Hint returns a hint if statement looks like it was written for another
+// dialect than dialect, or "" if it does not. Quoted strings and comments
+// are ignored.
+func Hint(dialect Dialect, statement string) string {
+	code := stripLiterals(statement)
+	var other Dialect
+	var signs []string
+	switch dialect {
+	case MySQL:
+		other = Postgres
+		if tools.DollarPlaceholders(statement).Count > 0 {
+			signs = append(signs, "$1 placeholders")
+		}
+		if castPattern.MatchString(code) {
+			signs = append(signs, ":: casts")
+		}
+		if m := postgresOnlyWords.FindString(code); m != "" {
+			signs = append(signs, strings.ToUpper(m))
+		}
+	case Postgres:
+		other = MySQL
+		if strings.ContainsRune(code, '`') {
+			signs = append(signs, "backtick-quoted identifiers")
+		}
+		if questionPlaceholders(code) {
+			signs = append(signs, "? placeholders")
+		}
+		if limitPattern.MatchString(code) {
+			signs = append(signs, "LIMIT offset, count")
+		}
+		if autoIncPattern.MatchString(code) {
+			signs = append(signs, "AUTO_INCREMENT")
+		}
+	default:
+		return ""
+	}
+	if len(signs) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("this looks like %s syntax (%s); this tool targets %s", other, strings.Join(signs, ", "), dialect)
+}
+
+// questionPlaceholderPattern matches `?` where a value is expected, so that
+// the jsonb `?` operators of Postgres are not mistaken for placeholders.
+var questionPlaceholderPattern = regexp.MustCompile(`(=|<|>|\(|,|\bIN|\bVALUES)\s*\?`)
+
+func questionPlaceholders(code string) bool {
+	return questionPlaceholderPattern.MatchString(strings.ToUpper(code))
+}
+
+// stripLiterals returns statement without its quoted strings and comments,
+// which are replaced by spaces. Backtick-quoted identifiers are kept.
+func stripLiterals(statement string) string {
+	var b strings.Builder
+	for i := 0; i < len(statement); i++ {
+		c := statement[i]
+		switch {
+		case c == '\'' || c == '"':
+			end := i + 1
+			for end < len(statement) && statement[end] != c {
+				if statement[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			b.WriteByte(' ')
+			i = end
+		case c == '-' && strings.HasPrefix(statement[i:], "--"):
+			if end := strings.IndexByte(statement[i:], '\n'); end >= 0 {
+				i += end - 1
+			} else {
+				i = len(statement)
+			}
+			b.WriteByte(' ')
+		case c == '/' && strings.HasPrefix(statement[i:], "/*"):
+			if end := strings.Index(statement[i+2:], "*/"); end >= 0 {
+				i += end + 3
+			} else {
+				i = len(statement)
+			}
+			b.WriteByte(' ')
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,203 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlsyntax_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/sqlsyntax"
+)
+
+func TestValidateMySQL(t *testing.T) {
+	tcs := []struct {
+		desc      string
+		statement string
+		want      *sqlsyntax.SyntaxError
+	}{
+		{
+			desc:      "select with placeholders",
+			statement: "SELECT `id`, JSON_EXTRACT(doc, '$.name') FROM t WHERE a = ? LIMIT 10, 20",
+		},
+		{
+			desc:      "upsert",
+			statement: "INSERT INTO t (a) VALUES (?) ON DUPLICATE KEY UPDATE a = VALUES(a)",
+		},
+		{
+			desc:      "several statements",
+			statement: "SELECT 1; SHOW FULL PROCESSLIST",
+		},
+		{
+			desc:      "typo",
+			statement: "SELECT 1;\nSELECT * FORM t",
+			want:      &sqlsyntax.SyntaxError{Dialect: sqlsyntax.MySQL, Line: 2, Column: 10, Msg: `near "FORM t"`},
+		},
+		{
+			desc:      "postgres cast",
+			statement: "SELECT id::text FROM t",
+			want:      &sqlsyntax.SyntaxError{Dialect: sqlsyntax.MySQL, Line: 1, Column: 10, Msg: `near "::text FROM t"`},
+		},
+		{
+			desc:      "postgres placeholder",
+			statement: "SELECT * FROM t WHERE id = $1",
+			want:      &sqlsyntax.SyntaxError{Dialect: sqlsyntax.MySQL, Msg: "$n placeholders are not supported (at 1:28), use ? instead"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := sqlsyntax.Validate(sqlsyntax.MySQL, tc.statement)
+			if tc.want == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			var got *sqlsyntax.SyntaxError
+			if !errors.As(err, &got) {
+				t.Fatalf("expected syntax error, got %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected error (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateMySQLUnsupported(t *testing.T) {
+	statements := []string{
+		"DELIMITER //",
+		"CREATE FUNCTION f() RETURNS INT RETURN 1",
+		"CREATE DEFINER=`root`@`%` TRIGGER tr BEFORE INSERT ON t FOR EACH ROW SET NEW.a = 1",
+		"/* event */ CREATE EVENT e ON SCHEDULE EVERY 1 HOUR DO DELETE FROM t",
+	}
+	for _, statement := range statements {
+		if err := sqlsyntax.Validate(sqlsyntax.MySQL, statement); !errors.Is(err, sqlsyntax.ErrUnsupported) {
+			t.Errorf("Validate(%q) = %v, want ErrUnsupported", statement, err)
+		}
+	}
+}
+
+func TestSyntaxErrorMessage(t *testing.T) {
+	tcs := []struct {
+		err  *sqlsyntax.SyntaxError
+		want string
+	}{
+		{
+			err:  &sqlsyntax.SyntaxError{Dialect: sqlsyntax.MySQL, Line: 2, Column: 10, Msg: `near "FORM t"`},
+			want: `invalid MySQL syntax at line 2, column 10: near "FORM t"`,
+		},
+		{
+			err:  &sqlsyntax.SyntaxError{Dialect: sqlsyntax.Postgres, Msg: "unterminated quoted string"},
+			want: "invalid Postgres syntax: unterminated quoted string",
+		},
+	}
+	for _, tc := range tcs {
+		if got := tc.err.Error(); got != tc.want {
+			t.Errorf("unexpected message: got %q, want %q", got, tc.want)
+		}
+	}
+}
+
+func TestHint(t *testing.T) {
+	tcs := []struct {
+		desc      string
+		dialect   sqlsyntax.Dialect
+		statement string
+		want      string
+	}{
+		{
+			desc:      "postgres placeholders and casts at mysql",
+			dialect:   sqlsyntax.MySQL,
+			statement: "SELECT id::text FROM t WHERE name ILIKE $1",
+			want:      "this looks like Postgres syntax ($1 placeholders, :: casts, ILIKE); this tool targets MySQL",
+		},
+		{
+			desc:      "mysql syntax at mysql",
+			dialect:   sqlsyntax.MySQL,
+			statement: "SELECT * FORM t WHERE a = ?",
+		},
+		{
+			desc:      "postgres syntax in literals and comments",
+			dialect:   sqlsyntax.MySQL,
+			statement: "SELECT '$1::text' -- ILIKE\nFROM t /* RETURNING */ WHERE a = \"x::int\"",
+		},
+		{
+			desc:      "mysql syntax at postgres",
+			dialect:   sqlsyntax.Postgres,
+			statement: "SELECT `id` FROM t WHERE a = ? LIMIT 10, 20",
+			want:      "this looks like MySQL syntax (backtick-quoted identifiers, ? placeholders, LIMIT offset, count); this tool targets Postgres",
+		},
+		{
+			desc:      "mysql DDL at postgres",
+			dialect:   sqlsyntax.Postgres,
+			statement: "CREATE TABLE t (id INT AUTO_INCREMENT PRIMARY KEY)",
+			want:      "this looks like MySQL syntax (AUTO_INCREMENT); this tool targets Postgres",
+		},
+		{
+			desc:      "jsonb operator at postgres",
+			dialect:   sqlsyntax.Postgres,
+			statement: "SELECT * FROM t WHERE doc ? 'key'",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := sqlsyntax.Hint(tc.dialect, tc.statement); got != tc.want {
+				t.Fatalf("unexpected hint: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	ctx := context.Background()
+
+	if err := sqlsyntax.Check(ctx, sqlsyntax.MySQL, "SELECT 1"); err != nil {
+		t.Fatalf("unexpected error for a valid statement: %s", err)
+	}
+	if err := sqlsyntax.Check(ctx, sqlsyntax.MySQL, "CREATE TRIGGER tr BEFORE INSERT ON t FOR EACH ROW SET NEW.a = 1"); err != nil {
+		t.Fatalf("expected unsupported statement to be executed, got %s", err)
+	}
+
+	err := sqlsyntax.Check(ctx, sqlsyntax.MySQL, "SELECT * FROM t WHERE id = $1")
+	var synErr *sqlsyntax.SyntaxError
+	if !errors.As(err, &synErr) {
+		t.Fatalf("expected syntax error, got %v", err)
+	}
+	if got, want := tools.ErrorHint(err), "this looks like Postgres syntax ($1 placeholders); this tool targets MySQL"; got != want {
+		t.Fatalf("unexpected hint: got %q, want %q", got, want)
+	}
+
+	err = sqlsyntax.Check(ctx, sqlsyntax.MySQL, "SELECT * FORM t")
+	if !errors.As(err, &synErr) {
+		t.Fatalf("expected syntax error, got %v", err)
+	}
+	if hint := tools.ErrorHint(err); hint != "" {
+		t.Fatalf("expected no hint, got %q", hint)
+	}
+}
+
+func TestCheckSupported(t *testing.T) {
+	if err := sqlsyntax.CheckSupported(sqlsyntax.MySQL); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// builds without cgo cannot parse Postgres statements
+	err := sqlsyntax.CheckSupported(sqlsyntax.Postgres)
+	if (err == nil) != sqlsyntax.Supported(sqlsyntax.Postgres) {
+		t.Fatalf("unexpected error for Postgres: %v", err)
+	}
+}
@@ -0,0 +1,295 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// PinnedConn is a connection of a source pinned to a session, so that the
+// state of the connection, such as temporary tables, outlives an invocation.
+type PinnedConn interface {
+	// Close rolls back the open transaction of the connection, if any, and
+	// gives it back to its source.
+	Close()
+}
+
+// StickySessions pins connections of sources to sessions, in the memory of
+// the replica. A session has at most one connection per source, used by one
+// invocation at a time. A connection is closed when its session ends, or
+// once it was not used for ttl.
+type StickySessions struct {
+	ttl          time.Duration
+	maxPerSource int
+	now          func() time.Time
+
+	mu sync.Mutex
+	// conns holds the pinned connections by session and source.
+	conns map[stickyKey]*stickyConn
+	// pinned counts the pinned connections of each source.
+	pinned map[string]int
+	stop   chan struct{}
+	closed bool
+}
+
+type stickyKey struct {
+	session string
+	source  string
+}
+
+type stickyConn struct {
+	// sem holds a token while the connection is opened or used.
+	sem      chan struct{}
+	conn     PinnedConn
+	lastUsed time.Time
+	// removed is set once the connection is unpinned, so that it is closed
+	// rather than reused once its invocation is done.
+	removed bool
+}
+
+// NewStickySessions returns StickySessions closing the connections not used
+// for ttl, which pin at most maxPerSource connections of each source.
+func NewStickySessions(ttl time.Duration, maxPerSource int) *StickySessions {
+	s := NewStickySessionsWithClock(ttl, maxPerSource, time.Now)
+	go s.sweepEvery(ttl / 2)
+	return s
+}
+
+// NewStickySessionsWithClock returns StickySessions using now as its clock,
+// which only closes the idle connections when Sweep is called or a
+// connection is pinned.
+func NewStickySessionsWithClock(ttl time.Duration, maxPerSource int, now func() time.Time) *StickySessions {
+	return &StickySessions{
+		ttl:          ttl,
+		maxPerSource: maxPerSource,
+		now:          now,
+		conns:        make(map[stickyKey]*stickyConn),
+		pinned:       make(map[string]int),
+		stop:         make(chan struct{}),
+	}
+}
+
+func (s *StickySessions) sweepEvery(interval time.Duration) {
+	ticker := time.NewTicker(max(interval, time.Second))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.Sweep()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// Acquire returns the connection of source pinned to the session id, for
+// the exclusive use of an invocation until release is called. If none is
+// pinned, it pins the one returned by open, or returns a nil connection if
+// open is nil. It fails with ErrToolBusy if maxPerSource connections of the
+// source are already pinned.
+func (s *StickySessions) Acquire(ctx context.Context, id, source string, open func(context.Context) (PinnedConn, error)) (conn PinnedConn, release func(), err error) {
+	s.Sweep()
+	key := stickyKey{session: id, source: source}
+	for {
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			return nil, nil, fmt.Errorf("sticky sessions are closed")
+		}
+		c, ok := s.conns[key]
+		if !ok {
+			if open == nil {
+				s.mu.Unlock()
+				return nil, func() {}, nil
+			}
+			if s.pinned[source] >= s.maxPerSource {
+				s.mu.Unlock()
+				return nil, nil, fmt.Errorf("%w: %d connections of source %q are already pinned to sessions", ErrToolBusy, s.maxPerSource, source)
+			}
+			c = &stickyConn{sem: make(chan struct{}, 1)}
+			c.sem <- struct{}{}
+			s.conns[key] = c
+			s.pinned[source]++
+			s.mu.Unlock()
+
+			conn, err := open(ctx)
+			s.mu.Lock()
+			if err != nil {
+				s.removeLocked(key, c)
+				s.mu.Unlock()
+				<-c.sem
+				return nil, nil, fmt.Errorf("unable to pin a connection of source %q: %w", source, err)
+			}
+			c.conn = conn
+			s.mu.Unlock()
+			return conn, s.releaser(c), nil
+		}
+		s.mu.Unlock()
+
+		select {
+		case c.sem <- struct{}{}:
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+		s.mu.Lock()
+		removed := c.removed
+		s.mu.Unlock()
+		if !removed {
+			return c.conn, s.releaser(c), nil
+		}
+		// the connection was unpinned while waiting for it
+		<-c.sem
+	}
+}
+
+// releaser returns the function ending the use of c by an invocation.
+func (s *StickySessions) releaser(c *stickyConn) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			c.lastUsed = s.now()
+			removed := c.removed
+			s.mu.Unlock()
+			if removed {
+				c.conn.Close()
+			}
+			<-c.sem
+		})
+	}
+}
+
+// removeLocked unpins c, the connection of key, unless it already was.
+// s.mu must be held.
+func (s *StickySessions) removeLocked(key stickyKey, c *stickyConn) {
+	if c.removed {
+		return
+	}
+	if s.conns[key] == c {
+		delete(s.conns, key)
+	}
+	s.pinned[key.source]--
+	if s.pinned[key.source] == 0 {
+		delete(s.pinned, key.source)
+	}
+	c.removed = true
+}
+
+// unpinLocked unpins c, the connection of key, and reports whether it is
+// idle, in which case the caller closes it with closeIdle. A connection in
+// use is closed by its invocation once done. s.mu must be held.
+func (s *StickySessions) unpinLocked(key stickyKey, c *stickyConn) bool {
+	s.removeLocked(key, c)
+	select {
+	case c.sem <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// closeIdle closes the connections of cs, which were unpinned while idle.
+func closeIdle(cs []*stickyConn) {
+	for _, c := range cs {
+		c.conn.Close()
+		<-c.sem
+	}
+}
+
+// Sweep closes the connections that were not used for ttl.
+func (s *StickySessions) Sweep() {
+	s.mu.Lock()
+	now := s.now()
+	var idle []*stickyConn
+	for key, c := range s.conns {
+		if now.Sub(c.lastUsed) < s.ttl {
+			continue
+		}
+		// connections being opened or used are not idle
+		select {
+		case c.sem <- struct{}{}:
+			s.removeLocked(key, c)
+			idle = append(idle, c)
+		default:
+		}
+	}
+	s.mu.Unlock()
+	closeIdle(idle)
+}
+
+// End closes the connections pinned to the session id.
+func (s *StickySessions) End(id string) {
+	s.mu.Lock()
+	var idle []*stickyConn
+	for key, c := range s.conns {
+		if key.session == id && s.unpinLocked(key, c) {
+			idle = append(idle, c)
+		}
+	}
+	s.mu.Unlock()
+	closeIdle(idle)
+}
+
+// Pinned returns the number of connections of source pinned to sessions.
+func (s *StickySessions) Pinned(source string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pinned[source]
+}
+
+// Close closes all the pinned connections, and fails the later calls to
+// Acquire.
+func (s *StickySessions) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.stop)
+	var idle []*stickyConn
+	for key, c := range s.conns {
+		if s.unpinLocked(key, c) {
+			idle = append(idle, c)
+		}
+	}
+	s.mu.Unlock()
+	closeIdle(idle)
+}
+
+// stickySessionKey is the key used to store the sticky session within
+// context.
+type stickySessionKey struct{}
+
+type stickySession struct {
+	sessions *StickySessions
+	id       string
+}
+
+// WithStickySession adds the session id of an invocation, whose connections
+// are pinned by sessions, into the context.
+func WithStickySession(ctx context.Context, sessions *StickySessions, id string) context.Context {
+	return context.WithValue(ctx, stickySessionKey{}, stickySession{sessions: sessions, id: id})
+}
+
+// StickySessionFromContext returns the StickySessions and the session id of
+// the context, or nil if the invocation has no session.
+func StickySessionFromContext(ctx context.Context) (*StickySessions, string) {
+	s, _ := ctx.Value(stickySessionKey{}).(stickySession)
+	return s.sessions, s.id
+}
@@ -0,0 +1,195 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// fakeConn counts how many times it was closed.
+type fakeConn struct {
+	closed int
+}
+
+func (c *fakeConn) Close() {
+	c.closed++
+}
+
+// stickyFixture is StickySessions with a fake clock, recording the
+// connections it opens.
+type stickyFixture struct {
+	sessions *tools.StickySessions
+	now      time.Time
+	opened   []*fakeConn
+}
+
+func newStickyFixture(t *testing.T, maxPerSource int) *stickyFixture {
+	t.Helper()
+	f := &stickyFixture{now: mustParseTime(t, "2025-01-06T12:00:00Z")}
+	f.sessions = tools.NewStickySessionsWithClock(10*time.Minute, maxPerSource, func() time.Time { return f.now })
+	return f
+}
+
+func (f *stickyFixture) open(context.Context) (tools.PinnedConn, error) {
+	c := &fakeConn{}
+	f.opened = append(f.opened, c)
+	return c, nil
+}
+
+// acquire acquires the connection of source pinned to the session id,
+// pinning one if pin is set, and releases it.
+func (f *stickyFixture) acquire(t *testing.T, id, source string, pin bool) tools.PinnedConn {
+	t.Helper()
+	open := f.open
+	if !pin {
+		open = nil
+	}
+	conn, release, err := f.sessions.Acquire(context.Background(), id, source, open)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	release()
+	return conn
+}
+
+func TestStickySessionsReuse(t *testing.T) {
+	f := newStickyFixture(t, 10)
+	if conn := f.acquire(t, "s1", "pg", false); conn != nil {
+		t.Fatalf("expected no connection before one is pinned, got %v", conn)
+	}
+	first := f.acquire(t, "s1", "pg", true)
+	if got := f.acquire(t, "s1", "pg", true); got != first {
+		t.Fatalf("expected the session to reuse its connection")
+	}
+	// tools without stickySession use the connection pinned to the session
+	if got := f.acquire(t, "s1", "pg", false); got != first {
+		t.Fatalf("expected the session to reuse its connection")
+	}
+	if got := f.acquire(t, "s2", "pg", true); got == first {
+		t.Fatalf("expected another session to pin another connection")
+	}
+	if got := f.acquire(t, "s1", "other", true); got == first {
+		t.Fatalf("expected another source to pin another connection")
+	}
+	if len(f.opened) != 3 {
+		t.Fatalf("unexpected number of connections opened: got %d, want 3", len(f.opened))
+	}
+	if got := f.sessions.Pinned("pg"); got != 2 {
+		t.Fatalf("unexpected number of pinned connections: got %d, want 2", got)
+	}
+}
+
+func TestStickySessionsTTL(t *testing.T) {
+	f := newStickyFixture(t, 10)
+	conn := f.acquire(t, "s1", "pg", true).(*fakeConn)
+
+	f.now = f.now.Add(9 * time.Minute)
+	f.sessions.Sweep()
+	if conn.closed != 0 {
+		t.Fatalf("expected the connection to be kept before its TTL")
+	}
+	// using the connection extends its TTL
+	f.acquire(t, "s1", "pg", false)
+	f.now = f.now.Add(9 * time.Minute)
+	f.sessions.Sweep()
+	if conn.closed != 0 {
+		t.Fatalf("expected the connection to be kept before its TTL")
+	}
+
+	f.now = f.now.Add(time.Minute)
+	f.sessions.Sweep()
+	if conn.closed != 1 {
+		t.Fatalf("expected the idle connection to be closed once, got %d", conn.closed)
+	}
+	if got := f.sessions.Pinned("pg"); got != 0 {
+		t.Fatalf("unexpected number of pinned connections: got %d, want 0", got)
+	}
+	if got := f.acquire(t, "s1", "pg", true); got == tools.PinnedConn(conn) {
+		t.Fatalf("expected a new connection once the TTL expired")
+	}
+}
+
+func TestStickySessionsMaxPerSource(t *testing.T) {
+	f := newStickyFixture(t, 2)
+	f.acquire(t, "s1", "pg", true)
+	f.acquire(t, "s2", "pg", true)
+
+	_, _, err := f.sessions.Acquire(context.Background(), "s3", "pg", f.open)
+	if !errors.Is(err, tools.ErrToolBusy) {
+		t.Fatalf("expected ErrToolBusy, got %v", err)
+	}
+	// the cap is per source
+	f.acquire(t, "s3", "other", true)
+
+	f.sessions.End("s1")
+	if f.opened[0].closed != 1 {
+		t.Fatalf("expected the connection of the ended session to be closed")
+	}
+	f.acquire(t, "s3", "pg", true)
+	if got := f.sessions.Pinned("pg"); got != 2 {
+		t.Fatalf("unexpected number of pinned connections: got %d, want 2", got)
+	}
+}
+
+func TestStickySessionsEndInUse(t *testing.T) {
+	f := newStickyFixture(t, 10)
+	conn, release, err := f.sessions.Acquire(context.Background(), "s1", "pg", f.open)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	f.sessions.End("s1")
+	if conn.(*fakeConn).closed != 0 {
+		t.Fatalf("expected the connection in use not to be closed")
+	}
+	release()
+	release()
+	if conn.(*fakeConn).closed != 1 {
+		t.Fatalf("expected the connection to be closed once released, got %d", conn.(*fakeConn).closed)
+	}
+	if got := f.sessions.Pinned("pg"); got != 0 {
+		t.Fatalf("unexpected number of pinned connections: got %d, want 0", got)
+	}
+}
+
+func TestStickySessionsOpenError(t *testing.T) {
+	f := newStickyFixture(t, 1)
+	failing := func(context.Context) (tools.PinnedConn, error) {
+		return nil, errors.New("too many clients")
+	}
+	if _, _, err := f.sessions.Acquire(context.Background(), "s1", "pg", failing); err == nil {
+		t.Fatalf("expected an error")
+	}
+	if got := f.sessions.Pinned("pg"); got != 0 {
+		t.Fatalf("unexpected number of pinned connections: got %d, want 0", got)
+	}
+	f.acquire(t, "s1", "pg", true)
+}
+
+func TestStickySessionsClose(t *testing.T) {
+	f := newStickyFixture(t, 10)
+	conn := f.acquire(t, "s1", "pg", true).(*fakeConn)
+	f.sessions.Close()
+	if conn.closed != 1 {
+		t.Fatalf("expected the connection to be closed once, got %d", conn.closed)
+	}
+	if _, _, err := f.sessions.Acquire(context.Background(), "s1", "pg", f.open); err == nil {
+		t.Fatalf("expected an error once closed")
+	}
+}
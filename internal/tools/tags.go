@@ -0,0 +1,113 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+)
+
+// maxTagLength is the longest tag a tool can have.
+const maxTagLength = 64
+
+var tagRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ValidateTag returns an error unless tag is made of lowercase letters,
+// digits, underscores and hyphens, and starts with a letter or a digit.
+func ValidateTag(tag string) error {
+	if len(tag) > maxTagLength {
+		return fmt.Errorf("tag %q is longer than %d characters", tag, maxTagLength)
+	}
+	if !tagRegex.MatchString(tag) {
+		return fmt.Errorf("tag %q must only contain lowercase letters, digits, '_' and '-', and start with a letter or a digit", tag)
+	}
+	return nil
+}
+
+// ParseTags parses a comma-separated list of tags, such as the `tags` query
+// parameter of the toolset endpoint. Empty entries are ignored.
+func ParseTags(s string) ([]string, error) {
+	var tags []string
+	for _, tag := range strings.Split(s, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		if err := ValidateTag(tag); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// TagsConfig wraps a ToolConfig with the kind-agnostic `tags` field.
+type TagsConfig struct {
+	ToolConfig
+	Tags []string
+}
+
+// validate interface
+var _ ToolConfig = TagsConfig{}
+
+func (cfg TagsConfig) Initialize(srcs map[string]sources.Source) (Tool, error) {
+	t, err := cfg.ToolConfig.Initialize(srcs)
+	if err != nil {
+		return nil, err
+	}
+	return TaggedTool{Tool: t, Tags: cfg.Tags}, nil
+}
+
+// TaggedTool decorates the manifests of a Tool with its tags, so that clients
+// can list the tools of a toolset that have a given tag.
+type TaggedTool struct {
+	Tool
+	Tags []string
+}
+
+func (t TaggedTool) unwrap() Tool {
+	return t.Tool
+}
+
+func (t TaggedTool) Manifest() Manifest {
+	m := t.Tool.Manifest()
+	m.Tags = t.Tags
+	return m
+}
+
+func (t TaggedTool) McpManifest() McpManifest {
+	m := t.Tool.McpManifest()
+	meta := make(map[string]any, len(m.Metadata)+1)
+	for k, v := range m.Metadata {
+		meta[k] = v
+	}
+	meta["toolbox/tags"] = t.Tags
+	m.Metadata = meta
+	return m
+}
+
+// hasAnyTag reports whether tags holds at least one of want.
+func hasAnyTag(tags, want []string) bool {
+	for _, tag := range want {
+		if slices.Contains(tags, tag) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestParseTags(t *testing.T) {
+	tcs := []struct {
+		desc    string
+		in      string
+		want    []string
+		wantErr string
+	}{
+		{desc: "empty", in: "", want: nil},
+		{desc: "single", in: "reporting", want: []string{"reporting"}},
+		{desc: "multiple", in: "reporting, finance,,q3-2025", want: []string{"reporting", "finance", "q3-2025"}},
+		{desc: "uppercase", in: "reporting,Finance", wantErr: `tag "Finance" must only contain lowercase letters`},
+		{desc: "leading hyphen", in: "-reporting", wantErr: `tag "-reporting" must only contain lowercase letters`},
+		{desc: "too long", in: strings.Repeat("a", 65), wantErr: "is longer than 64 characters"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := tools.ParseTags(tc.in)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("unexpected error: got %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected tags (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTaggedToolManifests(t *testing.T) {
+	cfg := tools.TagsConfig{ToolConfig: mockToolConfig{name: "report"}, Tags: []string{"reporting", "finance"}}
+	tool, err := cfg.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff([]string{"reporting", "finance"}, tool.Manifest().Tags); diff != "" {
+		t.Fatalf("unexpected manifest tags (-want +got):\n%s", diff)
+	}
+	want := map[string]any{"toolbox/tags": []string{"reporting", "finance"}}
+	if diff := cmp.Diff(want, tool.McpManifest().Metadata); diff != "" {
+		t.Fatalf("unexpected mcp metadata (-want +got):\n%s", diff)
+	}
+}
+
+func TestToolsetWithTags(t *testing.T) {
+	toolsMap := map[string]tools.Tool{
+		"report":   tools.TaggedTool{Tool: mockTool{name: "report"}, Tags: []string{"reporting"}},
+		"ledger":   tools.TaggedTool{Tool: mockTool{name: "ledger"}, Tags: []string{"finance", "reporting"}},
+		"invoice":  tools.TaggedTool{Tool: mockTool{name: "invoice"}, Tags: []string{"finance"}},
+		"untagged": mockTool{name: "untagged"},
+	}
+	cfg := tools.ToolsetConfig{Name: "reports", ToolNames: []string{"report", "ledger", "untagged"}}
+	toolset, err := cfg.Initialize("0.0.0", toolsMap)
+	if err != nil {
+		t.Fatalf("unable to initialize toolset: %s", err)
+	}
+
+	tcs := []struct {
+		desc string
+		tags []string
+		want []string
+	}{
+		{desc: "no tags", tags: nil, want: []string{"report", "ledger", "untagged"}},
+		{desc: "single tag", tags: []string{"reporting"}, want: []string{"report", "ledger"}},
+		{desc: "any of the tags", tags: []string{"finance", "audit"}, want: []string{"ledger"}},
+		// invoice has the tag, but is not in the toolset
+		{desc: "tool outside of the toolset", tags: []string{"finance"}, want: []string{"ledger"}},
+		{desc: "no matching tool", tags: []string{"audit"}, want: []string{}},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := toolset.WithTags(tc.tags)
+			gotNames := []string{}
+			for _, m := range got.McpManifest {
+				gotNames = append(gotNames, m.Name)
+				if _, ok := got.Manifest.ToolsManifest[m.Name]; !ok {
+					t.Fatalf("tool %q is missing from the toolset manifest", m.Name)
+				}
+			}
+			if diff := cmp.Diff(tc.want, gotNames); diff != "" {
+				t.Fatalf("unexpected tools (-want +got):\n%s", diff)
+			}
+			if len(got.Manifest.ToolsManifest) != len(tc.want) {
+				t.Fatalf("unexpected toolset manifest: %+v", got.Manifest.ToolsManifest)
+			}
+			if got.Manifest.ServerVersion != "0.0.0" {
+				t.Fatalf("unexpected server version: %q", got.Manifest.ServerVersion)
+			}
+		})
+	}
+	// filtering leaves the toolset unchanged
+	if len(toolset.McpManifest) != 3 {
+		t.Fatalf("unexpected toolset after filtering: %+v", toolset.McpManifest)
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/sources/tidb"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/sqlsyntax"
 	"github.com/googleapis/genai-toolbox/internal/util"
 )
 
@@ -32,6 +33,7 @@ func init() {
 	if !tools.Register(kind, newConfig) {
 		panic(fmt.Sprintf("tool kind %q already registered", kind))
 	}
+	tools.RegisterOutputSchema(kind, tools.RowsOutputSchema)
 }
 
 func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
@@ -57,6 +59,13 @@ type Config struct {
 	Source       string   `yaml:"source" validate:"required"`
 	Description  string   `yaml:"description" validate:"required"`
 	AuthRequired []string `yaml:"authRequired"`
+	// DatabaseScope, if set, lists the only databases statements may
+	// reference.
+	DatabaseScope  []string `yaml:"databaseScope"`
+	ValidateSyntax bool     `yaml:"validateSyntax"`
+	// MaxResultBytes, if set, aborts queries once the rows they return are
+	// over this approximate size.
+	MaxResultBytes int64 `yaml:"maxResultBytes"`
 }
 
 // validate interface
@@ -79,6 +88,15 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
 	}
 
+	if cfg.MaxResultBytes < 0 {
+		return nil, fmt.Errorf("maxResultBytes must not be negative, got %d", cfg.MaxResultBytes)
+	}
+
+	databaseScope, err := tools.NewScope(tools.MySQLDialect, "database", cfg.DatabaseScope)
+	if err != nil {
+		return nil, err
+	}
+
 	sqlParameter := tools.NewStringParameter("sql", "The sql to execute.")
 	parameters := tools.Parameters{sqlParameter}
 
@@ -86,13 +104,16 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 	// finish tool setup
 	t := Tool{
-		Name:         cfg.Name,
-		Kind:         kind,
-		Parameters:   parameters,
-		AuthRequired: cfg.AuthRequired,
-		Pool:         s.TiDBPool(),
-		manifest:     tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
-		mcpManifest:  mcpManifest,
+		Name:           cfg.Name,
+		Kind:           kind,
+		Parameters:     parameters,
+		AuthRequired:   cfg.AuthRequired,
+		ValidateSyntax: cfg.ValidateSyntax,
+		MaxResultBytes: cfg.MaxResultBytes,
+		Pool:           s.TiDBPool(),
+		DatabaseScope:  databaseScope,
+		manifest:       tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:    mcpManifest,
 	}
 	return t, nil
 }
@@ -101,23 +122,35 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 var _ tools.Tool = Tool{}
 
 type Tool struct {
-	Name         string           `yaml:"name"`
-	Kind         string           `yaml:"kind"`
-	AuthRequired []string         `yaml:"authRequired"`
-	Parameters   tools.Parameters `yaml:"parameters"`
-
-	Pool        *sql.DB
-	manifest    tools.Manifest
-	mcpManifest tools.McpManifest
+	Name           string           `yaml:"name"`
+	Kind           string           `yaml:"kind"`
+	AuthRequired   []string         `yaml:"authRequired"`
+	Parameters     tools.Parameters `yaml:"parameters"`
+	ValidateSyntax bool             `yaml:"validateSyntax"`
+	MaxResultBytes int64            `yaml:"maxResultBytes"`
+
+	Pool          *sql.DB
+	DatabaseScope *tools.Scope
+	manifest      tools.Manifest
+	mcpManifest   tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	paramsMap := params.AsMap()
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
 	sql, ok := paramsMap["sql"].(string)
 	if !ok {
 		return nil, fmt.Errorf("unable to get cast %s", paramsMap["sql"])
 	}
 
+	if t.ValidateSyntax {
+		if err := sqlsyntax.Check(ctx, sqlsyntax.MySQL, sql); err != nil {
+			return nil, err
+		}
+	}
+	if err := t.DatabaseScope.Check(sql); err != nil {
+		return nil, err
+	}
+
 	// Log the query executed for debugging.
 	logger, err := util.LoggerFromContext(ctx)
 	if err != nil {
@@ -148,12 +181,16 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		return nil, fmt.Errorf("unable to get column types: %w", err)
 	}
 
+	size := tools.NewResultSizeLimit(t.MaxResultBytes)
 	var out []any
 	for results.Next() {
 		err := results.Scan(values...)
 		if err != nil {
 			return nil, fmt.Errorf("unable to parse row: %w", err)
 		}
+		if err := size.Add(rawValues...); err != nil {
+			return nil, err
+		}
 		vMap := make(map[string]any)
 		for i, name := range cols {
 			val := rawValues[i]
@@ -165,8 +202,13 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 			// mysql driver return []uint8 type for "TEXT", "VARCHAR", and "NVARCHAR"
 			// we'll need to cast it back to string
 			switch colTypes[i].DatabaseTypeName() {
+			case "JSON":
+				// unmarshal JSON data before storing to prevent double marshaling
+				vMap[name] = tools.DecodeJSONColumn(ctx, name, val)
 			case "TEXT", "VARCHAR", "NVARCHAR":
 				vMap[name] = string(val.([]byte))
+			case "DATETIME", "TIMESTAMP":
+				vMap[name] = tools.ConvertTimestamp(ctx, val)
 			default:
 				vMap[name] = val
 			}
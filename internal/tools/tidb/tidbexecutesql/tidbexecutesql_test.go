@@ -15,12 +15,19 @@
 package tidbexecutesql_test
 
 import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
 	"testing"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/testutils/fakesql"
+	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/tools/tidb/tidbexecutesql"
 )
 
@@ -56,6 +63,70 @@ func TestParseFromYamlExecuteSql(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with validateSyntax",
+			in: `
+			tools:
+				example_tool:
+					kind: tidb-execute-sql
+					source: my-instance
+					description: some description
+					validateSyntax: true
+			`,
+			want: server.ToolConfigs{
+				"example_tool": tidbexecutesql.Config{
+					Name:           "example_tool",
+					Kind:           "tidb-execute-sql",
+					Source:         "my-instance",
+					Description:    "some description",
+					AuthRequired:   []string{},
+					ValidateSyntax: true,
+				},
+			},
+		},
+		{
+			desc: "with databaseScope",
+			in: `
+			tools:
+				example_tool:
+					kind: tidb-execute-sql
+					source: my-instance
+					description: some description
+					databaseScope:
+						- shop
+			`,
+			want: server.ToolConfigs{
+				"example_tool": tidbexecutesql.Config{
+					Name:          "example_tool",
+					Kind:          "tidb-execute-sql",
+					Source:        "my-instance",
+					Description:   "some description",
+					AuthRequired:  []string{},
+					DatabaseScope: []string{"shop"},
+				},
+			},
+		},
+		{
+			desc: "with maxResultBytes",
+			in: `
+			tools:
+				example_tool:
+					kind: tidb-execute-sql
+					source: my-instance
+					description: some description
+					maxResultBytes: 1048576
+			`,
+			want: server.ToolConfigs{
+				"example_tool": tidbexecutesql.Config{
+					Name:           "example_tool",
+					Kind:           "tidb-execute-sql",
+					Source:         "my-instance",
+					Description:    "some description",
+					AuthRequired:   []string{},
+					MaxResultBytes: 1048576,
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -74,3 +145,65 @@ func TestParseFromYamlExecuteSql(t *testing.T) {
 	}
 
 }
+
+type fakeSource struct {
+	pool *sql.DB
+}
+
+func (s fakeSource) SourceKind() string {
+	return "tidb"
+}
+
+func (s fakeSource) TiDBPool() *sql.DB {
+	return s.pool
+}
+
+func TestMaxResultBytes(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// each row holds a 1 KiB text value
+	rows := make([][]driver.Value, 100)
+	for i := range rows {
+		rows[i] = []driver.Value{[]byte(strings.Repeat("x", 1024))}
+	}
+	reads := 0
+	db := fakesql.Open(t, fakesql.Result{
+		Columns:   []fakesql.Column{{Name: "body", Type: "TEXT"}},
+		Rows:      rows,
+		BeforeRow: func(int) { reads++ },
+	})
+	cfg := tidbexecutesql.Config{
+		Name:           "example_tool",
+		Kind:           "tidb-execute-sql",
+		Source:         "my-instance",
+		Description:    "some description",
+		MaxResultBytes: 4096,
+	}
+	tool, err := cfg.Initialize(map[string]sources.Source{"my-instance": fakeSource{pool: db}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	params, err := tool.ParseParams(map[string]any{"sql": "SELECT body FROM pages"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	_, err = tool.Invoke(ctx, tools.Invocation{Params: params})
+	var tooLarge *tools.ResultTooLargeError
+	if !errors.As(err, &tooLarge) || tooLarge.Limit != 4096 {
+		t.Fatalf("got error %v, want a ResultTooLargeError", err)
+	}
+	// the fourth row is over the limit, and no row is read after it
+	if reads != 4 {
+		t.Fatalf("got %d reads, want 4", reads)
+	}
+	if inUse := db.Stats().InUse; inUse != 0 {
+		t.Fatalf("got %d connections in use, want 0", inUse)
+	}
+
+	cfg.MaxResultBytes = -1
+	if _, err := cfg.Initialize(map[string]sources.Source{"my-instance": fakeSource{pool: db}}); err == nil {
+		t.Fatalf("expected an error for a negative maxResultBytes")
+	}
+}
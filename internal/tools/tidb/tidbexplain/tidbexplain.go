@@ -0,0 +1,391 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbexplain
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/tidb"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+const kind string = "tidb-explain"
+
+const (
+	sqlKey     = "sql"
+	paramsKey  = "params"
+	analyzeKey = "analyze"
+	rawKey     = "raw"
+)
+
+// mostExpensiveCount is the number of operators highlighted as the most
+// expensive of a plan.
+const mostExpensiveCount = 3
+
+// operatorInfoLength is the most characters of the operator info of an
+// operator returned in a summarized plan.
+const operatorInfoLength = 120
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	TiDBPool() *sql.DB
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &tidb.Source{}
+
+var compatibleSources = [...]string{tidb.SourceKind}
+
+type Config struct {
+	Name         string   `yaml:"name" validate:"required"`
+	Kind         string   `yaml:"kind" validate:"required"`
+	Source       string   `yaml:"source" validate:"required"`
+	Description  string   `yaml:"description" validate:"required"`
+	AuthRequired []string `yaml:"authRequired"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	parameters := tools.Parameters{
+		tools.NewStringParameter(sqlKey, "The sql statement to explain, with ? placeholders for its parameters."),
+		tools.NewArrayParameterWithDefault(paramsKey, []any{}, "Optional: The values bound to the ? placeholders of the statement, in order.", tools.NewStringParameter("param", "A value bound to a placeholder.")),
+		tools.NewBooleanParameterWithDefault(analyzeKey, false, "Whether to run the statement with EXPLAIN ANALYZE, to report the actual rows and time of each operator. The statement is executed."),
+		tools.NewBooleanParameterWithDefault(rawKey, false, "Whether to return the rows of EXPLAIN as they are, rather than a summarized plan."),
+	}
+	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, parameters)
+
+	// finish tool setup
+	t := Tool{
+		Name:         cfg.Name,
+		Kind:         kind,
+		Parameters:   parameters,
+		AuthRequired: cfg.AuthRequired,
+		Pool:         s.TiDBPool(),
+		manifest:     tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string           `yaml:"name"`
+	Kind         string           `yaml:"kind"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+
+	Pool        *sql.DB
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
+	statement, ok := paramsMap[sqlKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected a string", sqlKey)
+	}
+	params, _ := paramsMap[paramsKey].([]any)
+	analyze, _ := paramsMap[analyzeKey].(bool)
+	raw, _ := paramsMap[rawKey].(bool)
+
+	explain := "EXPLAIN "
+	if analyze {
+		explain = "EXPLAIN ANALYZE "
+	}
+	results, err := t.Pool.QueryContext(ctx, explain+statement, params...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to explain statement: %w", err)
+	}
+	defer results.Close()
+
+	cols, err := results.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve rows column name: %w", err)
+	}
+	rawValues := make([]sql.NullString, len(cols))
+	values := make([]any, len(cols))
+	for i := range rawValues {
+		values[i] = &rawValues[i]
+	}
+	var rows [][]string
+	for results.Next() {
+		if err := results.Scan(values...); err != nil {
+			return nil, fmt.Errorf("unable to parse row: %w", err)
+		}
+		row := make([]string, len(cols))
+		for i, v := range rawValues {
+			row[i] = v.String
+		}
+		rows = append(rows, row)
+	}
+	if err := results.Err(); err != nil {
+		return nil, fmt.Errorf("errors encountered during row iteration: %w", err)
+	}
+
+	if raw {
+		out := make([]any, 0, len(rows))
+		for _, row := range rows {
+			m := make(map[string]any, len(cols))
+			for i, name := range cols {
+				m[name] = row[i]
+			}
+			out = append(out, m)
+		}
+		return out, nil
+	}
+	return ParsePlan(cols, rows)
+}
+
+// Plan is a summarized EXPLAIN output.
+type Plan struct {
+	// Analyzed is set if the plan holds the actual rows and time of its
+	// operators, as reported by EXPLAIN ANALYZE.
+	Analyzed bool `json:"analyzed"`
+	// Operators are the roots of the operator tree.
+	Operators []*Operator `json:"operators"`
+	// MostExpensive lists the ids of the most expensive operators, by the
+	// time spent in them, excluding their children, if analyzed, or else by
+	// their estimated rows.
+	MostExpensive []string `json:"mostExpensive"`
+}
+
+// Operator is an operator of a plan.
+type Operator struct {
+	ID           string      `json:"id"`
+	Task         string      `json:"task,omitempty"`
+	AccessObject string      `json:"accessObject,omitempty"`
+	EstRows      *float64    `json:"estRows,omitempty"`
+	ActRows      *float64    `json:"actRows,omitempty"`
+	Time         string      `json:"time,omitempty"`
+	OperatorInfo string      `json:"operatorInfo,omitempty"`
+	Children     []*Operator `json:"children,omitempty"`
+
+	// time is Time parsed, and selfTime is the time spent in the operator
+	// excluding its children.
+	time, selfTime time.Duration
+}
+
+// columns holds the indexes of the columns of an EXPLAIN output, or -1 for
+// the ones missing. TiDB renamed and added columns across versions, such as
+// `count` to `estRows` in v4.0.
+type columns struct {
+	id, estRows, actRows, task, accessObject, executionInfo, operatorInfo int
+}
+
+func newColumns(names []string) (columns, error) {
+	index := func(aliases ...string) int {
+		for i, name := range names {
+			if slices.Contains(aliases, strings.ToLower(strings.TrimSpace(name))) {
+				return i
+			}
+		}
+		return -1
+	}
+	c := columns{
+		id:            index("id"),
+		estRows:       index("estrows", "count"),
+		actRows:       index("actrows"),
+		task:          index("task"),
+		accessObject:  index("access object"),
+		executionInfo: index("execution info"),
+		operatorInfo:  index("operator info"),
+	}
+	if c.id < 0 {
+		return c, fmt.Errorf("unrecognized EXPLAIN output: no id column in %q", names)
+	}
+	return c, nil
+}
+
+// treePrefix matches the tree drawn before the id of an operator, two
+// characters per level, such as "  └─".
+var treePrefix = regexp.MustCompile(`^[ │├└─]*`)
+
+// timeInfo matches the time of an operator in its execution info, such as
+// "time:1.2ms", but not "total_time:1.2ms".
+var timeInfo = regexp.MustCompile(`(?:^|[^\w])time:\s*([0-9.]+[a-zµ]+(?:[0-9.]+[a-zµ]+)*)`)
+
+// rowsInfo matches the actual rows of an operator in its execution info, as
+// reported before TiDB v4.0.
+var rowsInfo = regexp.MustCompile(`(?:^|[^\w])rows:\s*([0-9]+)`)
+
+// ParsePlan summarizes the rows of an EXPLAIN or EXPLAIN ANALYZE output,
+// whose columns are named by names.
+func ParsePlan(names []string, rows [][]string) (Plan, error) {
+	cols, err := newColumns(names)
+	if err != nil {
+		return Plan{}, err
+	}
+	cell := func(row []string, i int) string {
+		if i < 0 || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	plan := Plan{
+		// only EXPLAIN ANALYZE reports the actual rows or execution info
+		Analyzed:      cols.actRows >= 0 || cols.executionInfo >= 0,
+		Operators:     []*Operator{},
+		MostExpensive: []string{},
+	}
+	var all []*Operator
+	// stack holds the last operator of each level
+	var stack []*Operator
+	for _, row := range rows {
+		id := row[cols.id]
+		prefix := treePrefix.FindString(id)
+		depth := len([]rune(prefix)) / 2
+		op := &Operator{
+			ID:           strings.TrimPrefix(id, prefix),
+			Task:         cell(row, cols.task),
+			AccessObject: cell(row, cols.accessObject),
+			OperatorInfo: excerpt(cell(row, cols.operatorInfo)),
+		}
+		if v, err := strconv.ParseFloat(cell(row, cols.estRows), 64); err == nil {
+			op.EstRows = &v
+		}
+		info := cell(row, cols.executionInfo)
+		if v, err := strconv.ParseFloat(cell(row, cols.actRows), 64); err == nil {
+			op.ActRows = &v
+		} else if m := rowsInfo.FindStringSubmatch(info); m != nil {
+			v, _ := strconv.ParseFloat(m[1], 64)
+			op.ActRows = &v
+		}
+		if m := timeInfo.FindStringSubmatch(info); m != nil {
+			op.Time = m[1]
+			op.time, _ = time.ParseDuration(m[1])
+		}
+
+		if depth > len(stack) {
+			depth = len(stack)
+		}
+		stack = append(stack[:depth], op)
+		if depth == 0 {
+			plan.Operators = append(plan.Operators, op)
+		} else {
+			parent := stack[depth-1]
+			parent.Children = append(parent.Children, op)
+		}
+		all = append(all, op)
+	}
+
+	for _, op := range all {
+		op.selfTime = op.time
+		for _, c := range op.Children {
+			op.selfTime -= c.time
+		}
+		op.selfTime = max(op.selfTime, 0)
+	}
+	ranked := slices.Clone(all)
+	slices.SortStableFunc(ranked, func(a, b *Operator) int {
+		if plan.Analyzed {
+			return int(b.selfTime - a.selfTime)
+		}
+		return cmpRows(b.EstRows, a.EstRows)
+	})
+	for _, op := range ranked[:min(mostExpensiveCount, len(ranked))] {
+		plan.MostExpensive = append(plan.MostExpensive, op.ID)
+	}
+	return plan, nil
+}
+
+// cmpRows compares estimated rows, missing ones being the fewest.
+func cmpRows(a, b *float64) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	case *a < *b:
+		return -1
+	case *a > *b:
+		return 1
+	}
+	return 0
+}
+
+// excerpt returns the first operatorInfoLength characters of s.
+func excerpt(s string) string {
+	runes := []rune(s)
+	if len(runes) <= operatorInfoLength {
+		return s
+	}
+	return string(runes[:operatorInfoLength]) + "..."
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return false
+}
@@ -0,0 +1,240 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbexplain_test
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/testutils/fakesql"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/tidb/tidbexplain"
+)
+
+func TestParseFromYamlTiDBExplain(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				example_tool:
+					kind: tidb-explain
+					source: my-tidb-instance
+					description: some description
+					authRequired:
+						- my-google-auth-service
+			`,
+			want: server.ToolConfigs{
+				"example_tool": tidbexplain.Config{
+					Name:         "example_tool",
+					Kind:         "tidb-explain",
+					Source:       "my-tidb-instance",
+					Description:  "some description",
+					AuthRequired: []string{"my-google-auth-service"},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+// splitOutput splits an EXPLAIN output, as printed by the mysql client
+// without its borders, into its column names and rows.
+func splitOutput(out string) ([]string, [][]string) {
+	var names []string
+	var rows [][]string
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		cells := strings.Split(line, "|")
+		for i := range cells {
+			cells[i] = strings.TrimSpace(cells[i])
+		}
+		if names == nil {
+			names = cells
+			continue
+		}
+		// keep the tree drawn before the ids
+		cells[0] = strings.TrimRight(strings.Split(line, "|")[0], " ")
+		rows = append(rows, cells)
+	}
+	return names, rows
+}
+
+// explainAnalyzeV3 is the output of EXPLAIN ANALYZE of TiDB v3.0, which
+// names the estimated rows `count` and reports the actual ones in its
+// execution info.
+const explainAnalyzeV3 = `
+id                   | count    | task | operator info                                             | execution info
+StreamAgg_16         | 1.00     | root | funcs:count(col_0)                                        | time:1.9ms, loops:2, rows:1
+└─TableReader_17     | 1.00     | root | data:StreamAgg_8                                          | time:1.88ms, loops:2, rows:1
+  └─StreamAgg_8      | 1.00     | cop  | funcs:count(1)                                            | proc max:0s, min:0s, p80:0s, p95:0s, rows:1, iters:1, tasks:1
+    └─TableScan_15   | 10000.00 | cop  | table:t1, range:[-inf,+inf], keep order:false, stats:pseudo | proc max:0s, min:0s, p80:0s, p95:0s, rows:5, iters:1, tasks:1
+`
+
+// explainAnalyzeV7 is the output of EXPLAIN ANALYZE of TiDB v7.5.
+const explainAnalyzeV7 = `
+id                          | estRows  | actRows | task      | access object | execution info                                                                                                   | operator info                                  | memory    | disk
+HashJoin_22                 | 12487.50 | 3       | root      |               | time:1.26ms, loops:2, build_hash_table:{total:735.8µs, fetch:730.4µs, build:5.38µs}, probe:{concurrency:5, total:5.7ms} | inner join, equal:[eq(test.t1.id, test.t2.id)] | 25.3 KB   | 0 Bytes
+├─TableReader_38(Build)     | 9990.00  | 3       | root      |               | time:726.7µs, loops:2, cop_task: {num: 1, max: 687.5µs, proc_keys: 3, tot_proc: 0s, rpc_num: 1}                  | data:Selection_37                              | 300 Bytes | N/A
+│ └─Selection_37            | 9990.00  | 3       | cop[tikv] |               | tikv_task:{time:0s, loops:1}                                                                                     | not(isnull(test.t2.id))                        | N/A       | N/A
+│   └─TableFullScan_36      | 10000.00 | 3       | cop[tikv] | table:t2      | tikv_task:{time:0s, loops:1}                                                                                     | keep order:false, stats:pseudo                 | N/A       | N/A
+└─TableReader_32(Probe)     | 9990.00  | 3       | root      |               | time:1.12ms, loops:2, cop_task: {num: 1, max: 1.08ms, proc_keys: 3, tot_proc: 0s, rpc_num: 1}                    | data:Selection_31                              | 304 Bytes | N/A
+  └─Selection_31            | 9990.00  | 3       | cop[tikv] |               | tikv_task:{time:0s, loops:1}                                                                                     | not(isnull(test.t1.id))                        | N/A       | N/A
+    └─TableFullScan_30      | 10000.00 | 3       | cop[tikv] | table:t1      | tikv_task:{time:0s, loops:1}                                                                                     | keep order:false, stats:pseudo                 | N/A       | N/A
+`
+
+// explainV7 is the output of EXPLAIN of TiDB v7.5.
+const explainV7 = `
+id                        | estRows  | task      | access object | operator info
+Projection_4              | 10.00    | root      |               | test.t1.id, test.t1.name
+└─IndexLookUp_10          | 10.00    | root      |               |
+  ├─IndexRangeScan_8(Build) | 10.00  | cop[tikv] | table:t1, index:idx_name(name) | range:["a","a"], keep order:false, stats:pseudo
+  └─TableRowIDScan_9(Probe) | 10.00  | cop[tikv] | table:t1      | keep order:false, stats:pseudo
+`
+
+func TestParsePlan(t *testing.T) {
+	tcs := []struct {
+		desc string
+		out  string
+		want string
+	}{
+		{
+			desc: "explain analyze v3",
+			out:  explainAnalyzeV3,
+			want: `{"analyzed":true,"operators":[{"id":"StreamAgg_16","task":"root","estRows":1,"actRows":1,"time":"1.9ms","operatorInfo":"funcs:count(col_0)","children":[{"id":"TableReader_17","task":"root","estRows":1,"actRows":1,"time":"1.88ms","operatorInfo":"data:StreamAgg_8","children":[{"id":"StreamAgg_8","task":"cop","estRows":1,"actRows":1,"operatorInfo":"funcs:count(1)","children":[{"id":"TableScan_15","task":"cop","estRows":10000,"actRows":5,"operatorInfo":"table:t1, range:[-inf,+inf], keep order:false, stats:pseudo"}]}]}]}],"mostExpensive":["TableReader_17","StreamAgg_16","StreamAgg_8"]}`,
+		},
+		{
+			desc: "explain analyze v7",
+			out:  explainAnalyzeV7,
+			want: `{"analyzed":true,"operators":[{"id":"HashJoin_22","task":"root","estRows":12487.5,"actRows":3,"time":"1.26ms","operatorInfo":"inner join, equal:[eq(test.t1.id, test.t2.id)]","children":[{"id":"TableReader_38(Build)","task":"root","estRows":9990,"actRows":3,"time":"726.7µs","operatorInfo":"data:Selection_37","children":[{"id":"Selection_37","task":"cop[tikv]","estRows":9990,"actRows":3,"time":"0s","operatorInfo":"not(isnull(test.t2.id))","children":[{"id":"TableFullScan_36","task":"cop[tikv]","accessObject":"table:t2","estRows":10000,"actRows":3,"time":"0s","operatorInfo":"keep order:false, stats:pseudo"}]}]},{"id":"TableReader_32(Probe)","task":"root","estRows":9990,"actRows":3,"time":"1.12ms","operatorInfo":"data:Selection_31","children":[{"id":"Selection_31","task":"cop[tikv]","estRows":9990,"actRows":3,"time":"0s","operatorInfo":"not(isnull(test.t1.id))","children":[{"id":"TableFullScan_30","task":"cop[tikv]","accessObject":"table:t1","estRows":10000,"actRows":3,"time":"0s","operatorInfo":"keep order:false, stats:pseudo"}]}]}]}],"mostExpensive":["TableReader_32(Probe)","TableReader_38(Build)","HashJoin_22"]}`,
+		},
+		{
+			desc: "explain v7",
+			out:  explainV7,
+			want: `{"analyzed":false,"operators":[{"id":"Projection_4","task":"root","estRows":10,"operatorInfo":"test.t1.id, test.t1.name","children":[{"id":"IndexLookUp_10","task":"root","estRows":10,"children":[{"id":"IndexRangeScan_8(Build)","task":"cop[tikv]","accessObject":"table:t1, index:idx_name(name)","estRows":10,"operatorInfo":"range:[\"a\",\"a\"], keep order:false, stats:pseudo"},{"id":"TableRowIDScan_9(Probe)","task":"cop[tikv]","accessObject":"table:t1","estRows":10,"operatorInfo":"keep order:false, stats:pseudo"}]}]}],"mostExpensive":["Projection_4","IndexLookUp_10","IndexRangeScan_8(Build)"]}`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			names, rows := splitOutput(tc.out)
+			plan, err := tidbexplain.ParsePlan(names, rows)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			got, err := json.Marshal(plan)
+			if err != nil {
+				t.Fatalf("unable to marshal plan: %s", err)
+			}
+			if string(got) != tc.want {
+				t.Fatalf("unexpected plan: got %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParsePlanLongOperatorInfo(t *testing.T) {
+	info := strings.Repeat("x", 200)
+	plan, err := tidbexplain.ParsePlan([]string{"ID", "EstRows", "Operator Info"}, [][]string{{"TableFullScan_5", "10.00", info}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, want := plan.Operators[0].OperatorInfo, info[:120]+"..."; got != want {
+		t.Fatalf("unexpected operator info: got %q, want %q", got, want)
+	}
+}
+
+func TestParsePlanNoID(t *testing.T) {
+	if _, err := tidbexplain.ParsePlan([]string{"operator", "rows"}, [][]string{{"TableFullScan_5", "10"}}); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
+
+func TestInvokeRaw(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	db := fakesql.Open(t, fakesql.Result{
+		Columns: []fakesql.Column{{Name: "id", Type: "VARCHAR"}, {Name: "estRows", Type: "VARCHAR"}},
+		Rows: [][]driver.Value{
+			{[]byte("Projection_4"), []byte("10.00")},
+			{[]byte("└─TableFullScan_5"), []byte("10.00")},
+		},
+	})
+	tool := tidbexplain.Tool{Name: "example_tool", Kind: "tidb-explain", Pool: db}
+
+	for _, tc := range []struct {
+		desc string
+		raw  bool
+		want string
+	}{
+		{
+			desc: "summarized",
+			want: `{"analyzed":false,"operators":[{"id":"Projection_4","estRows":10,"children":[{"id":"TableFullScan_5","estRows":10}]}],"mostExpensive":["Projection_4","TableFullScan_5"]}`,
+		},
+		{
+			desc: "raw",
+			raw:  true,
+			want: `[{"estRows":"10.00","id":"Projection_4"},{"estRows":"10.00","id":"└─TableFullScan_5"}]`,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			params := tools.ParamValues{
+				{Name: "sql", Value: "SELECT * FROM t1"},
+				{Name: "params", Value: []any{}},
+				{Name: "analyze", Value: false},
+				{Name: "raw", Value: tc.raw},
+			}
+			result, err := tool.Invoke(ctx, tools.Invocation{Params: params})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			got, err := json.Marshal(result)
+			if err != nil {
+				t.Fatalf("unable to marshal result: %s", err)
+			}
+			if string(got) != tc.want {
+				t.Fatalf("unexpected result: got %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
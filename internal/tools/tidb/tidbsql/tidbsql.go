@@ -17,13 +17,15 @@ package tidbsql
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
+	"slices"
+	"time"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/sources/tidb"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/sqlsyntax"
 )
 
 const kind string = "tidb-sql"
@@ -32,6 +34,7 @@ func init() {
 	if !tools.Register(kind, newConfig) {
 		panic(fmt.Sprintf("tool kind %q already registered", kind))
 	}
+	tools.RegisterOutputSchema(kind, tools.RowsOutputSchema)
 }
 
 func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
@@ -60,6 +63,14 @@ type Config struct {
 	AuthRequired       []string         `yaml:"authRequired"`
 	Parameters         tools.Parameters `yaml:"parameters"`
 	TemplateParameters tools.Parameters `yaml:"templateParameters"`
+	// SlowQueryExplain, if set, captures the plans of slow invocations.
+	SlowQueryExplain *tools.SlowQueryExplainSpec `yaml:"slowQueryExplain"`
+	// KeysetPagination, if set, returns the rows a page at a time.
+	KeysetPagination *tools.KeysetPaginationSpec `yaml:"keysetPagination"`
+	// DeterministicOrder, if set, returns the rows of statements without an
+	// ORDER BY in a stable order.
+	DeterministicOrder *tools.DeterministicOrderSpec `yaml:"deterministicOrder"`
+	ValidateSyntax     bool                          `yaml:"validateSyntax"`
 }
 
 // validate interface
@@ -82,13 +93,37 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
 	}
 
-	allParameters, paramManifest, err := tools.ProcessParameters(cfg.TemplateParameters, cfg.Parameters)
+	keysetPagination, err := tools.NewKeysetPagination(cfg.KeysetPagination)
+	if err != nil {
+		return nil, err
+	}
+
+	deterministicOrder, err := tools.NewDeterministicOrder(cfg.DeterministicOrder, tools.MySQLDialect)
+	if err != nil {
+		return nil, err
+	}
+	if deterministicOrder != nil && keysetPagination != nil {
+		return nil, fmt.Errorf("deterministicOrder cannot be used with keysetPagination, which orders the rows by its columns")
+	}
+	// statements with template parameters are only checked once resolved
+	if len(cfg.TemplateParameters) == 0 {
+		if err := deterministicOrder.Check(cfg.Statement); err != nil {
+			return nil, err
+		}
+	}
+
+	allParameters, paramManifest, err := tools.ProcessParameters(cfg.TemplateParameters, slices.Concat(cfg.Parameters, keysetPagination.Parameters()))
 	if err != nil {
 		return nil, err
 	}
 
 	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, allParameters)
 
+	slowQueryExplain, err := tools.NewSlowQueryExplain(cfg.SlowQueryExplain)
+	if err != nil {
+		return nil, err
+	}
+
 	// finish tool setup
 	t := Tool{
 		Name:               cfg.Name,
@@ -98,7 +133,11 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		AllParams:          allParameters,
 		Statement:          cfg.Statement,
 		AuthRequired:       cfg.AuthRequired,
+		ValidateSyntax:     cfg.ValidateSyntax,
 		Pool:               s.TiDBPool(),
+		SlowQueryExplain:   slowQueryExplain,
+		KeysetPagination:   keysetPagination,
+		DeterministicOrder: deterministicOrder,
 		manifest:           tools.Manifest{Description: cfg.Description, Parameters: paramManifest, AuthRequired: cfg.AuthRequired},
 		mcpManifest:        mcpManifest,
 	}
@@ -107,6 +146,8 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 // validate interface
 var _ tools.Tool = Tool{}
+var _ tools.SlowQueryTool = Tool{}
+var _ tools.Renderer = Tool{}
 
 type Tool struct {
 	Name               string           `yaml:"name"`
@@ -115,30 +156,64 @@ type Tool struct {
 	Parameters         tools.Parameters `yaml:"parameters"`
 	TemplateParameters tools.Parameters `yaml:"templateParameters"`
 	AllParams          tools.Parameters `yaml:"allParams"`
-
-	Pool        *sql.DB
-	Statement   string
-	manifest    tools.Manifest
-	mcpManifest tools.McpManifest
+	ValidateSyntax     bool             `yaml:"validateSyntax"`
+
+	Pool               *sql.DB
+	Statement          string
+	SlowQueryExplain   *tools.SlowQueryExplain
+	KeysetPagination   *tools.KeysetPagination
+	DeterministicOrder *tools.DeterministicOrder
+	manifest           tools.Manifest
+	mcpManifest        tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	paramsMap := params.AsMap()
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
 	newStatement, err := tools.ResolveTemplateParams(t.TemplateParameters, t.Statement, paramsMap)
 	if err != nil {
 		return nil, fmt.Errorf("unable to extract template params %w", err)
 	}
 
+	if t.ValidateSyntax {
+		if err := sqlsyntax.Check(ctx, sqlsyntax.MySQL, newStatement); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := t.DeterministicOrder.Check(newStatement); err != nil {
+		return nil, err
+	}
+
 	newParams, err := tools.GetParams(t.Parameters, paramsMap)
 	if err != nil {
 		return nil, fmt.Errorf("unable to extract standard params %w", err)
 	}
 
 	sliceParams := newParams.AsSlice()
+	if t.KeysetPagination != nil {
+		newStatement, sliceParams = t.KeysetPagination.Statement(newStatement, sliceParams, t.KeysetPagination.AfterKey(inv.Params), questionPlaceholder)
+	}
+	statement := newStatement
+	if t.DeterministicOrder.Rewrites(statement) {
+		columns, err := t.DeterministicOrder.Columns(statement, func(columnsStatement string) ([]string, error) {
+			rows, err := t.Pool.QueryContext(ctx, columnsStatement, sliceParams...)
+			if err != nil {
+				return nil, err
+			}
+			defer rows.Close()
+			return rows.Columns()
+		})
+		if err != nil {
+			return nil, err
+		}
+		newStatement = t.DeterministicOrder.Statement(statement, columns)
+	}
+	start := time.Now()
 	results, err := t.Pool.QueryContext(ctx, newStatement, sliceParams...)
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute query: %w", err)
 	}
+	defer results.Close()
 
 	cols, err := results.Columns()
 	if err != nil {
@@ -151,7 +226,6 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	for i := range rawValues {
 		values[i] = &rawValues[i]
 	}
-	defer results.Close()
 
 	colTypes, err := results.ColumnTypes()
 	if err != nil {
@@ -177,14 +251,11 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 			switch colTypes[i].DatabaseTypeName() {
 			case "JSON":
 				// unmarshal JSON data before storing to prevent double marshaling
-				var unmarshaledData any
-				err := json.Unmarshal(val.([]byte), &unmarshaledData)
-				if err != nil {
-					return nil, fmt.Errorf("unable to unmarshal json data %s", val)
-				}
-				vMap[name] = unmarshaledData
+				vMap[name] = tools.DecodeJSONColumn(ctx, name, val)
 			case "TEXT", "VARCHAR", "NVARCHAR":
 				vMap[name] = string(val.([]byte))
+			case "DATETIME", "TIMESTAMP":
+				vMap[name] = tools.ConvertTimestamp(ctx, val)
 			default:
 				vMap[name] = val
 			}
@@ -195,10 +266,72 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	if err := results.Err(); err != nil {
 		return nil, fmt.Errorf("errors encountered during row iteration: %w", err)
 	}
+	t.SlowQueryExplain.Observe(ctx, t.Name, newStatement, sliceParams, inv.Params, time.Since(start), t.explain)
+	t.DeterministicOrder.Sort(statement, out)
 
+	if t.KeysetPagination != nil {
+		return t.KeysetPagination.Page(out)
+	}
 	return out, nil
 }
 
+// questionPlaceholder returns the placeholder of a parameter bound to a
+// statement, which is the same for all of them.
+func questionPlaceholder(int) string {
+	return "?"
+}
+
+// explain returns the rows of the EXPLAIN of statement when run with params.
+func (t Tool) explain(ctx context.Context, statement string, params []any) (any, error) {
+	results, err := t.Pool.QueryContext(ctx, "EXPLAIN "+statement, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer results.Close()
+
+	cols, err := results.Columns()
+	if err != nil {
+		return nil, err
+	}
+	rawValues := make([]sql.NullString, len(cols))
+	values := make([]any, len(cols))
+	for i := range rawValues {
+		values[i] = &rawValues[i]
+	}
+	plan := []any{}
+	for results.Next() {
+		if err := results.Scan(values...); err != nil {
+			return nil, err
+		}
+		row := make(map[string]any, len(cols))
+		for i, name := range cols {
+			if rawValues[i].Valid {
+				row[name] = rawValues[i].String
+			} else {
+				row[name] = nil
+			}
+		}
+		plan = append(plan, row)
+	}
+	if err := results.Err(); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+func (t Tool) SlowQueries() []tools.SlowQueryPlan {
+	return t.SlowQueryExplain.Plans()
+}
+
+// Render resolves the statement of an invocation without running it.
+func (t Tool) Render(params tools.ParamValues) (tools.RenderedStatement, error) {
+	rendered, err := tools.RenderStatement(t.Statement, t.TemplateParameters, t.Parameters, params)
+	if err != nil || t.KeysetPagination == nil {
+		return rendered, err
+	}
+	return t.KeysetPagination.Render(rendered, params, questionPlaceholder), nil
+}
+
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
 	return tools.ParseParams(t.AllParams, data, claims)
 }
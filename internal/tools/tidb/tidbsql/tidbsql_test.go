@@ -15,12 +15,16 @@
 package tidbsql_test
 
 import (
+	"context"
+	"database/sql/driver"
+	"errors"
 	"testing"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/testutils/fakesql"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/tools/tidb/tidbsql"
 )
@@ -74,6 +78,85 @@ func TestParseFromYamlTiDB(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with validateSyntax",
+			in: `
+			tools:
+				example_tool:
+					kind: tidb-sql
+					source: my-instance
+					description: some description
+					statement: SELECT * FROM hotels WHERE id = 1
+					validateSyntax: true
+			`,
+			want: server.ToolConfigs{
+				"example_tool": tidbsql.Config{
+					Name:           "example_tool",
+					Kind:           "tidb-sql",
+					Source:         "my-instance",
+					Description:    "some description",
+					Statement:      "SELECT * FROM hotels WHERE id = 1",
+					AuthRequired:   []string{},
+					ValidateSyntax: true,
+				},
+			},
+		},
+		{
+			desc: "with slow query explain",
+			in: `
+			tools:
+				example_tool:
+					kind: tidb-sql
+					source: my-tidb-instance
+					description: some description
+					statement: |
+						SELECT * FROM SQL_STATEMENT;
+					slowQueryExplain:
+						threshold: 500ms
+						maxPlans: 20
+						minInterval: 30s
+			`,
+			want: server.ToolConfigs{
+				"example_tool": tidbsql.Config{
+					Name:         "example_tool",
+					Kind:         "tidb-sql",
+					Source:       "my-tidb-instance",
+					Description:  "some description",
+					Statement:    "SELECT * FROM SQL_STATEMENT;\n",
+					AuthRequired: []string{},
+					SlowQueryExplain: &tools.SlowQueryExplainSpec{
+						Threshold:   "500ms",
+						MaxPlans:    20,
+						MinInterval: "30s",
+					},
+				},
+			},
+		},
+		{
+			desc: "with deterministic order",
+			in: `
+			tools:
+				example_tool:
+					kind: tidb-sql
+					source: my-tidb-instance
+					description: some description
+					statement: |
+						SELECT * FROM SQL_STATEMENT;
+					deterministicOrder:
+						mode: rewrite
+			`,
+			want: server.ToolConfigs{
+				"example_tool": tidbsql.Config{
+					Name:               "example_tool",
+					Kind:               "tidb-sql",
+					Source:             "my-tidb-instance",
+					Description:        "some description",
+					Statement:          "SELECT * FROM SQL_STATEMENT;\n",
+					AuthRequired:       []string{},
+					DeterministicOrder: &tools.DeterministicOrderSpec{Mode: tools.DeterministicOrderRewrite},
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -173,3 +256,116 @@ func TestParseFromYamlWithTemplateParamsTiDB(t *testing.T) {
 		})
 	}
 }
+
+func TestInvokeReleasesConnections(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	result := fakesql.Result{
+		Columns: []fakesql.Column{{Name: "id", Type: "INT"}, {Name: "name", Type: "VARCHAR"}},
+		Rows: [][]driver.Value{
+			{int64(1), []byte("Alice")},
+			{int64(2), []byte("Bob")},
+			{int64(3), []byte("Carol")},
+		},
+	}
+	failing := result
+	failing.Err, failing.FailAt = errors.New("connection reset"), 1
+
+	var cancel context.CancelFunc
+	cancelled := result
+	cancelled.BeforeRow = func(i int) {
+		if i == 1 {
+			cancel()
+		}
+	}
+
+	for _, tc := range []struct {
+		desc    string
+		result  fakesql.Result
+		wantErr bool
+	}{
+		{desc: "success", result: result},
+		{desc: "read error mid-iteration", result: failing, wantErr: true},
+		{desc: "context cancelled mid-iteration", result: cancelled, wantErr: true},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			db := fakesql.Open(t, tc.result)
+			tool := tidbsql.Tool{Name: "example_tool", Kind: "tidb-sql", Pool: db, Statement: "SELECT id, name FROM users"}
+			testutils.CheckLeaks(t, func() int { return db.Stats().InUse }, func() {
+				for range 20 {
+					var invokeCtx context.Context
+					invokeCtx, cancel = context.WithCancel(ctx)
+					_, err := tool.Invoke(invokeCtx, tools.Invocation{})
+					cancel()
+					if (err != nil) != tc.wantErr {
+						t.Errorf("unexpected error: got %v, want error %t", err, tc.wantErr)
+						return
+					}
+				}
+			})
+		})
+	}
+}
+
+func TestInvokeDeterministicOrder(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var queries []string
+	result := fakesql.Result{
+		Columns: []fakesql.Column{{Name: "id", Type: "INT"}, {Name: "name", Type: "VARCHAR"}},
+		Rows: [][]driver.Value{
+			{int64(3), []byte("Carol")},
+			{int64(1), []byte("Alice")},
+			{int64(2), []byte("Bob")},
+		},
+		OnQuery: func(query string) { queries = append(queries, query) },
+	}
+	db := fakesql.Open(t, result)
+
+	newTool := func(mode tools.DeterministicOrderMode) tidbsql.Tool {
+		d, err := tools.NewDeterministicOrder(&tools.DeterministicOrderSpec{Mode: mode}, tools.MySQLDialect)
+		if err != nil {
+			t.Fatalf("unable to create deterministic order: %s", err)
+		}
+		return tidbsql.Tool{Name: "example_tool", Kind: "tidb-sql", Pool: db, Statement: "SELECT id, name FROM users", DeterministicOrder: d}
+	}
+
+	// the columns are found once, and the statement wrapped to order them
+	tool := newTool(tools.DeterministicOrderRewrite)
+	for range 2 {
+		if _, err := tool.Invoke(ctx, tools.Invocation{}); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	ordered := "SELECT * FROM (\nSELECT id, name FROM users\n) AS deterministic_order\nORDER BY `id` IS NULL, `id`, `name` IS NULL, `name`"
+	want := []string{
+		"SELECT * FROM (\nSELECT id, name FROM users\n) AS deterministic_order LIMIT 0",
+		ordered,
+		ordered,
+	}
+	if diff := cmp.Diff(want, queries); diff != "" {
+		t.Fatalf("incorrect queries (-want +got):\n%s", diff)
+	}
+
+	// the rows are sorted once read
+	queries = nil
+	got, err := newTool(tools.DeterministicOrderSort).Invoke(ctx, tools.Invocation{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff([]string{"SELECT id, name FROM users"}, queries); diff != "" {
+		t.Fatalf("incorrect queries (-want +got):\n%s", diff)
+	}
+	wantRows := []any{
+		map[string]any{"id": int64(1), "name": "Alice"},
+		map[string]any{"id": int64(2), "name": "Bob"},
+		map[string]any{"id": int64(3), "name": "Carol"},
+	}
+	if diff := cmp.Diff(wantRows, got); diff != "" {
+		t.Fatalf("incorrect rows (-want +got):\n%s", diff)
+	}
+}
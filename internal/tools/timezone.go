@@ -0,0 +1,133 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+)
+
+// ResultTimezone is the timezone the timestamps of results are converted to,
+// so that agents do not mix up timestamps in UTC and in the local time of
+// their database.
+type ResultTimezone struct {
+	name string
+	loc  *time.Location
+}
+
+// NewResultTimezone returns the ResultTimezone of an IANA timezone name, such
+// as "Europe/Paris".
+func NewResultTimezone(name string) (*ResultTimezone, error) {
+	if name == "" {
+		return nil, fmt.Errorf("timezone must not be empty")
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", name, err)
+	}
+	return &ResultTimezone{name: name, loc: loc}, nil
+}
+
+// UnmarshalYAML decodes and validates the name of a ResultTimezone, such as
+// the default timezone of a tools file.
+func (tz *ResultTimezone) UnmarshalYAML(ctx context.Context, unmarshal func(interface{}) error) error {
+	var name string
+	if err := unmarshal(&name); err != nil {
+		return err
+	}
+	parsed, err := NewResultTimezone(name)
+	if err != nil {
+		return fmt.Errorf("invalid 'resultTimezone' field: %w", err)
+	}
+	*tz = *parsed
+	return nil
+}
+
+// Location returns the location of the timezone.
+func (tz *ResultTimezone) Location() *time.Location {
+	return tz.loc
+}
+
+func (tz *ResultTimezone) String() string {
+	return tz.name
+}
+
+type resultTimezoneKey struct{}
+
+// WithResultTimezone returns a context converting the timestamps of results
+// to tz.
+func WithResultTimezone(ctx context.Context, tz *ResultTimezone) context.Context {
+	return context.WithValue(ctx, resultTimezoneKey{}, tz)
+}
+
+// ResultTimezoneFromContext returns the timezone the timestamps of results
+// are converted to, or nil if they are returned as they are.
+func ResultTimezoneFromContext(ctx context.Context) *ResultTimezone {
+	tz, _ := ctx.Value(resultTimezoneKey{}).(*ResultTimezone)
+	return tz
+}
+
+// ConvertTimestamp converts v, the value of a timestamp column, to the
+// timezone of ctx and formats it as RFC 3339 with its offset. It returns v as
+// it is if it is not a time.Time, or if ctx has no timezone. The instant is
+// unchanged, only its representation differs. Date-only values must not be
+// passed, as they would be shifted to another day.
+func ConvertTimestamp(ctx context.Context, v any) any {
+	t, ok := v.(time.Time)
+	if !ok {
+		return v
+	}
+	tz := ResultTimezoneFromContext(ctx)
+	if tz == nil {
+		return v
+	}
+	return t.In(tz.loc).Format(time.RFC3339Nano)
+}
+
+// ResultTimezoneConfig wraps a ToolConfig with the kind-agnostic
+// `resultTimezone` field.
+type ResultTimezoneConfig struct {
+	ToolConfig
+	Timezone *ResultTimezone
+}
+
+// validate interface
+var _ ToolConfig = ResultTimezoneConfig{}
+
+func (cfg ResultTimezoneConfig) Initialize(srcs map[string]sources.Source) (Tool, error) {
+	t, err := cfg.ToolConfig.Initialize(srcs)
+	if err != nil {
+		return nil, err
+	}
+	return ResultTimezoneTool{Tool: t, Timezone: cfg.Timezone}, nil
+}
+
+// ResultTimezoneTool converts the timestamps of the results of a Tool to a
+// timezone.
+type ResultTimezoneTool struct {
+	Tool
+	Timezone *ResultTimezone
+}
+
+func (t ResultTimezoneTool) unwrap() Tool {
+	return t.Tool
+}
+
+func (t ResultTimezoneTool) Invoke(ctx context.Context, inv Invocation) (any, error) {
+	return t.Tool.Invoke(WithResultTimezone(ctx, t.Timezone), inv)
+}
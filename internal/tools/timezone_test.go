@@ -0,0 +1,152 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func mustResultTimezone(t *testing.T, name string) *tools.ResultTimezone {
+	t.Helper()
+	tz, err := tools.NewResultTimezone(name)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return tz
+}
+
+func TestConvertTimestampAcrossDST(t *testing.T) {
+	berlin := tools.WithResultTimezone(context.Background(), mustResultTimezone(t, "Europe/Berlin"))
+	newYork := tools.WithResultTimezone(context.Background(), mustResultTimezone(t, "America/New_York"))
+	tcs := []struct {
+		desc string
+		ctx  context.Context
+		in   time.Time
+		want string
+	}{
+		{
+			desc: "berlin before spring forward",
+			ctx:  berlin,
+			in:   time.Date(2025, 3, 30, 0, 59, 59, 0, time.UTC),
+			want: "2025-03-30T01:59:59+01:00",
+		},
+		{
+			desc: "berlin after spring forward",
+			ctx:  berlin,
+			in:   time.Date(2025, 3, 30, 1, 0, 0, 0, time.UTC),
+			want: "2025-03-30T03:00:00+02:00",
+		},
+		{
+			desc: "berlin before fall back",
+			ctx:  berlin,
+			in:   time.Date(2025, 10, 26, 0, 30, 0, 0, time.UTC),
+			want: "2025-10-26T02:30:00+02:00",
+		},
+		{
+			desc: "berlin after fall back",
+			ctx:  berlin,
+			in:   time.Date(2025, 10, 26, 1, 30, 0, 0, time.UTC),
+			want: "2025-10-26T02:30:00+01:00",
+		},
+		{
+			desc: "new york after spring forward, from another timezone",
+			ctx:  newYork,
+			in:   time.Date(2025, 3, 9, 8, 15, 0, 500_000_000, time.FixedZone("UTC+1", 3600)),
+			want: "2025-03-09T03:15:00.5-04:00",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := tools.ConvertTimestamp(tc.ctx, tc.in)
+			if got != tc.want {
+				t.Fatalf("unexpected timestamp: got %v, want %q", got, tc.want)
+			}
+			// the converted timestamp is the same instant
+			parsed, err := time.Parse(time.RFC3339Nano, got.(string))
+			if err != nil {
+				t.Fatalf("unable to parse converted timestamp: %s", err)
+			}
+			if !parsed.Equal(tc.in) {
+				t.Fatalf("converted timestamp %s is not the instant %s", parsed, tc.in)
+			}
+		})
+	}
+}
+
+func TestConvertTimestampPassthrough(t *testing.T) {
+	ts := time.Date(2025, 10, 15, 12, 0, 0, 0, time.UTC)
+	if got := tools.ConvertTimestamp(context.Background(), ts); got != ts {
+		t.Fatalf("expected timestamps to be unchanged without timezone, got %v", got)
+	}
+	ctx := tools.WithResultTimezone(context.Background(), mustResultTimezone(t, "Europe/Paris"))
+	for _, v := range []any{nil, "2025-10-15T12:00:00Z", int64(1760529600)} {
+		if got := tools.ConvertTimestamp(ctx, v); got != v {
+			t.Fatalf("expected %v to be unchanged, got %v", v, got)
+		}
+	}
+}
+
+func TestResultTimezone(t *testing.T) {
+	_, err := tools.NewResultTimezone("Europe/Atlantis")
+	if err == nil || !strings.Contains(err.Error(), `invalid timezone "Europe/Atlantis"`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got struct {
+		ResultTimezone *tools.ResultTimezone `yaml:"resultTimezone"`
+	}
+	if err := yaml.Unmarshal([]byte("resultTimezone: Europe/Lisbon"), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	if got.ResultTimezone.String() != "Europe/Lisbon" || got.ResultTimezone.Location().String() != "Europe/Lisbon" {
+		t.Fatalf("unexpected timezone: %v", got.ResultTimezone)
+	}
+	err = yaml.Unmarshal([]byte("resultTimezone: CEST"), &got)
+	if err == nil || !strings.Contains(err.Error(), "invalid 'resultTimezone' field") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// timezoneTool returns the timestamp it is invoked at, as converted to the
+// result timezone.
+type timezoneTool struct {
+	mockTool
+	at time.Time
+}
+
+func (t timezoneTool) Invoke(ctx context.Context, _ tools.Invocation) (any, error) {
+	return tools.ConvertTimestamp(ctx, t.at), nil
+}
+
+func TestResultTimezoneTool(t *testing.T) {
+	inner := timezoneTool{mockTool: mockTool{name: "now"}, at: time.Date(2025, 7, 1, 10, 0, 0, 0, time.UTC)}
+	tool := tools.ResultTimezoneTool{Tool: inner, Timezone: mustResultTimezone(t, "Asia/Kolkata")}
+	got, err := tool.Invoke(context.Background(), tools.Invocation{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "2025-07-01T15:30:00+05:30"; got != want {
+		t.Fatalf("unexpected result: got %v, want %q", got, want)
+	}
+	if got, _ := inner.Invoke(context.Background(), tools.Invocation{}); got != inner.at {
+		t.Fatalf("expected the unwrapped tool to return the timestamp as is, got %v", got)
+	}
+}
@@ -75,8 +75,38 @@ func (token AccessToken) ParseBearerToken() (string, error) {
 	return headerParts[1], nil
 }
 
+// Invocation is what a tool is invoked with: its parameters, and what is
+// known of the request invoking it.
+type Invocation struct {
+	Params ParamValues
+	// AccessToken is the client's "Authorization" header, for the tools
+	// requiring client authorization.
+	AccessToken AccessToken
+	// Claims maps the name of the verified auth services of the request to
+	// the claims retrieved from them.
+	Claims   map[string]map[string]any
+	Metadata RequestMetadata
+}
+
+// WithParams returns a copy of the invocation with params.
+func (inv Invocation) WithParams(params ParamValues) Invocation {
+	inv.Params = params
+	return inv
+}
+
+// ClientNameHeader is the header that callers send their client name in.
+const ClientNameHeader = "X-Toolbox-Client-Name"
+
+// RequestMetadata describes the request invoking a tool.
+type RequestMetadata struct {
+	// Protocol is the protocol of the request, such as "http" or "mcp".
+	Protocol string
+	// ClientName is the name the client sent in the ClientNameHeader, if any.
+	ClientName string
+}
+
 type Tool interface {
-	Invoke(context.Context, ParamValues, AccessToken) (any, error)
+	Invoke(context.Context, Invocation) (any, error)
 	ParseParams(map[string]any, map[string]map[string]any) (ParamValues, error)
 	Manifest() Manifest
 	McpManifest() McpManifest
@@ -86,9 +116,22 @@ type Tool interface {
 
 // Manifest is the representation of tools sent to Client SDKs.
 type Manifest struct {
-	Description  string              `json:"description"`
-	Parameters   []ParameterManifest `json:"parameters"`
-	AuthRequired []string            `json:"authRequired"`
+	Description        string              `json:"description"`
+	Parameters         []ParameterManifest `json:"parameters"`
+	AuthRequired       []string            `json:"authRequired"`
+	Deprecated         bool                `json:"deprecated,omitempty"`
+	DeprecationMessage string              `json:"deprecationMessage,omitempty"`
+	Schedule           *ScheduleSpec       `json:"schedule,omitempty"`
+	Tags               []string            `json:"tags,omitempty"`
+	// Disabled is set if the kind of the tool was disabled by the operator
+	// of the server, so that it cannot be invoked.
+	Disabled bool `json:"disabled,omitempty"`
+	// Destructive is set if the tool may destroy data, such that clients
+	// should confirm its invocations.
+	Destructive bool `json:"destructive,omitempty"`
+	// Capabilities describes what the database of the source of the tool
+	// supports, if the source describes it.
+	Capabilities *sources.Capabilities `json:"capabilities,omitempty"`
 }
 
 // Definition for a tool the MCP client can call.
@@ -99,7 +142,21 @@ type McpManifest struct {
 	Description string `json:"description,omitempty"`
 	// A JSON Schema object defining the expected parameters for the tool.
 	InputSchema McpToolsSchema `json:"inputSchema,omitempty"`
-	Metadata    map[string]any `json:"_meta,omitempty"`
+	// An optional JSON Schema object describing the structured content of
+	// the results of the tool.
+	OutputSchema map[string]any `json:"outputSchema,omitempty"`
+	// Optional hints describing the tool to clients.
+	Annotations *McpToolAnnotations `json:"annotations,omitempty"`
+	Metadata    map[string]any      `json:"_meta,omitempty"`
+}
+
+// Additional properties describing a Tool to MCP clients.
+type McpToolAnnotations struct {
+	// A human-readable title for the tool.
+	Title string `json:"title,omitempty"`
+	// DestructiveHint is set if the tool may destroy data, such that clients
+	// should confirm its invocations.
+	DestructiveHint bool `json:"destructiveHint,omitempty"`
 }
 
 func GetMcpManifest(name, desc string, authInvoke []string, params Parameters) McpManifest {
@@ -126,6 +183,46 @@ func GetMcpManifest(name, desc string, authInvoke []string, params Parameters) M
 
 var ErrUnauthorized = errors.New("unauthorized")
 
+// HintError wraps an error returned by a tool invocation with an actionable
+// hint on how to resolve it. The message of the wrapped error is kept as is.
+type HintError struct {
+	Err  error
+	Hint string
+}
+
+func (e *HintError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *HintError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorHint returns the hint of the first HintError in the chain of err, or
+// an empty string if there is none.
+func ErrorHint(err error) string {
+	var hintErr *HintError
+	if errors.As(err, &hintErr) {
+		return hintErr.Hint
+	}
+	return ""
+}
+
+// StatusError wraps an error returned by a tool invocation with the HTTP
+// status code the invoke endpoint responds with.
+type StatusError struct {
+	Err  error
+	Code int
+}
+
+func (e *StatusError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
 // Helper function that returns if a tool invocation request is authorized
 func IsAuthorized(authRequiredSources []string, verifiedAuthServices []string) bool {
 	if len(authRequiredSources) == 0 {
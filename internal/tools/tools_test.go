@@ -15,9 +15,15 @@
 package tools_test
 
 import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 )
 
@@ -91,3 +97,319 @@ func TestGetMcpManifestMetadata(t *testing.T) {
 		})
 	}
 }
+
+type mockToolConfig struct {
+	name string
+}
+
+func (c mockToolConfig) ToolConfigKind() string {
+	return "mock"
+}
+
+func (c mockToolConfig) Initialize(map[string]sources.Source) (tools.Tool, error) {
+	return mockTool{name: c.name}, nil
+}
+
+type mockTool struct {
+	tools.Tool
+	name string
+}
+
+func (t mockTool) Manifest() tools.Manifest {
+	return tools.Manifest{Description: "mock tool", Parameters: []tools.ParameterManifest{}, AuthRequired: []string{}}
+}
+
+func (t mockTool) McpManifest() tools.McpManifest {
+	return tools.McpManifest{Name: t.name, Description: "mock tool"}
+}
+
+func TestAliasConfigManifest(t *testing.T) {
+	tcs := []struct {
+		desc            string
+		cfg             tools.AliasConfig
+		wantManifest    tools.Manifest
+		wantAnnotations *tools.McpToolAnnotations
+	}{
+		{
+			desc: "aliases only",
+			cfg:  tools.AliasConfig{ToolConfig: mockToolConfig{name: "new"}, Aliases: []string{"old"}},
+			wantManifest: tools.Manifest{
+				Description:  "mock tool",
+				Parameters:   []tools.ParameterManifest{},
+				AuthRequired: []string{},
+			},
+		},
+		{
+			desc: "deprecated without message",
+			cfg:  tools.AliasConfig{ToolConfig: mockToolConfig{name: "new"}, Deprecated: true},
+			wantManifest: tools.Manifest{
+				Description:  "mock tool",
+				Parameters:   []tools.ParameterManifest{},
+				AuthRequired: []string{},
+				Deprecated:   true,
+			},
+			wantAnnotations: &tools.McpToolAnnotations{Title: "Deprecated"},
+		},
+		{
+			desc: "deprecated with message",
+			cfg:  tools.AliasConfig{ToolConfig: mockToolConfig{name: "new"}, Deprecated: true, DeprecationMessage: "use other-tool"},
+			wantManifest: tools.Manifest{
+				Description:        "mock tool",
+				Parameters:         []tools.ParameterManifest{},
+				AuthRequired:       []string{},
+				Deprecated:         true,
+				DeprecationMessage: "use other-tool",
+			},
+			wantAnnotations: &tools.McpToolAnnotations{Title: "Deprecated: use other-tool"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			tool, err := tc.cfg.Initialize(nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.wantManifest, tool.Manifest()); diff != "" {
+				t.Fatalf("unexpected manifest (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantAnnotations, tool.McpManifest().Annotations); diff != "" {
+				t.Fatalf("unexpected mcp annotations (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAliasToolMcpManifest(t *testing.T) {
+	tool := tools.AliasTool{Tool: mockTool{name: "new"}, Name: "old", Target: "new"}
+	if got := tool.McpManifest().Name; got != "old" {
+		t.Fatalf("unexpected mcp manifest name: got %q, want %q", got, "old")
+	}
+}
+
+func TestCollectRows(t *testing.T) {
+	rows := []any{map[string]any{"a": 1}, map[string]any{"a": 2}}
+	got, err := tools.CollectRows(tools.NewSliceRowIterator(rows))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(rows, got); diff != "" {
+		t.Fatalf("unexpected rows (-want +got):\n%s", diff)
+	}
+
+	got, err = tools.CollectRows(tools.NewSliceRowIterator(nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got != nil {
+		t.Fatalf("expected nil result for empty iterator, got %v", got)
+	}
+}
+
+// slowTool records the start and end of each invocation. Invocations block
+// until release is closed.
+type slowTool struct {
+	mockTool
+	mu      *sync.Mutex
+	events  *[]string
+	started chan string
+	release chan struct{}
+}
+
+func newSlowTool(name string) slowTool {
+	return slowTool{
+		mockTool: mockTool{name: name},
+		mu:       &sync.Mutex{},
+		events:   &[]string{},
+		started:  make(chan string, 10),
+		release:  make(chan struct{}),
+	}
+}
+
+func (t slowTool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	id := inv.Params.AsMap()["id"].(string)
+	t.record("start " + id)
+	t.started <- id
+	<-t.release
+	t.record("end " + id)
+	return id, nil
+}
+
+func (t slowTool) record(event string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	*t.events = append(*t.events, event)
+}
+
+func invokeWithID(tool tools.Tool, id string) (any, error) {
+	return tool.Invoke(context.Background(), tools.Invocation{Params: tools.ParamValues{{Name: "id", Value: id}}})
+}
+
+// waitForQueue gives a goroutine that was just started time to join the
+// queue of a serialized tool.
+func waitForQueue() {
+	time.Sleep(50 * time.Millisecond)
+}
+
+func TestSerializedToolOrder(t *testing.T) {
+	inner := newSlowTool("migrate")
+	tool := tools.NewSerializedTool(inner, 0)
+
+	var wg sync.WaitGroup
+	for _, id := range []string{"a", "b", "c"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := invokeWithID(tool, id); err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		}()
+		waitForQueue()
+	}
+
+	for _, want := range []string{"a", "b", "c"} {
+		select {
+		case got := <-inner.started:
+			if got != want {
+				t.Fatalf("unexpected invocation started: got %q, want %q", got, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for invocation %q", want)
+		}
+		select {
+		case id := <-inner.started:
+			t.Fatalf("invocation %q started while %q was running", id, want)
+		case <-time.After(50 * time.Millisecond):
+		}
+		inner.release <- struct{}{}
+	}
+	wg.Wait()
+
+	want := []string{"start a", "end a", "start b", "end b", "start c", "end c"}
+	if diff := cmp.Diff(want, *inner.events); diff != "" {
+		t.Fatalf("unexpected invocation order (-want +got):\n%s", diff)
+	}
+}
+
+func TestSerializedToolQueueTimeout(t *testing.T) {
+	inner := newSlowTool("migrate")
+	tool := tools.NewSerializedTool(inner, 100*time.Millisecond)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if _, err := invokeWithID(tool, "a"); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+	}()
+	<-inner.started
+
+	// copies of the tool, such as aliases, share the same lock
+	alias := tools.AliasTool{Tool: tool, Name: "old_migrate", Target: "migrate"}
+	for _, tc := range []struct {
+		desc string
+		tool tools.Tool
+	}{
+		{desc: "tool", tool: tool},
+		{desc: "alias", tool: alias},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := invokeWithID(tc.tool, "b")
+			if !errors.Is(err, tools.ErrToolBusy) {
+				t.Fatalf("expected ErrToolBusy, got %v", err)
+			}
+			if !strings.Contains(err.Error(), "queue position 1") {
+				t.Fatalf("expected queue position in error, got %q", err)
+			}
+		})
+	}
+
+	close(inner.release)
+	<-done
+	if _, err := invokeWithID(tool, "c"); err != nil {
+		t.Fatalf("unexpected error after release: %s", err)
+	}
+}
+
+// streamingTool returns its rows as a RowIterator, whose error once they are
+// read is err.
+type streamingTool struct {
+	mockTool
+	rows []any
+	err  error
+}
+
+func (t streamingTool) Invoke(context.Context, tools.Invocation) (any, error) {
+	return failingRowIterator{SliceRowIterator: tools.NewSliceRowIterator(t.rows), err: t.err}, nil
+}
+
+type failingRowIterator struct {
+	*tools.SliceRowIterator
+	err error
+}
+
+func (it failingRowIterator) Err() error {
+	return it.err
+}
+
+func TestSerializedToolStreamedRows(t *testing.T) {
+	tool := tools.NewSerializedTool(streamingTool{mockTool: mockTool{name: "export"}, rows: []any{1, 2}}, 50*time.Millisecond)
+	res, err := invokeWithID(tool, "a")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// the lock is held while the rows are read
+	if _, err := invokeWithID(tool, "b"); !errors.Is(err, tools.ErrToolBusy) {
+		t.Fatalf("expected ErrToolBusy, got %v", err)
+	}
+	rows, err := tools.CollectRows(res.(tools.RowIterator))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff([]any{1, 2}, rows); diff != "" {
+		t.Fatalf("incorrect rows (-want +got):\n%s", diff)
+	}
+	res, err = invokeWithID(tool, "c")
+	if err != nil {
+		t.Fatalf("unexpected error after the rows were closed: %s", err)
+	}
+	res.(tools.RowIterator).Close()
+}
+
+func TestSerializedToolOtherToolsUnaffected(t *testing.T) {
+	inner := newSlowTool("migrate")
+	tool := tools.NewSerializedTool(inner, 0)
+	go func() {
+		_, _ = invokeWithID(tool, "a")
+	}()
+	<-inner.started
+	defer close(inner.release)
+
+	other := newSlowTool("rebuild")
+	close(other.release)
+	for _, otherTool := range []tools.Tool{other, tools.NewSerializedTool(other, 0)} {
+		got, err := invokeWithID(otherTool, "b")
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if got != "b" {
+			t.Fatalf("unexpected result: got %v, want %q", got, "b")
+		}
+	}
+}
+
+func TestSerializedToolContextCanceled(t *testing.T) {
+	inner := newSlowTool("migrate")
+	tool := tools.NewSerializedTool(inner, 0)
+	go func() {
+		_, _ = invokeWithID(tool, "a")
+	}()
+	<-inner.started
+	defer close(inner.release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := tool.Invoke(ctx, tools.Invocation{Params: tools.ParamValues{{Name: "id", Value: "b"}}})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context deadline error, got %v", err)
+	}
+}
@@ -16,11 +16,26 @@ package tools
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 )
 
 type ToolsetConfig struct {
 	Name      string   `yaml:"name"`
 	ToolNames []string `yaml:",inline"`
+	// DescriptionOverrides replaces the descriptions of tools of the toolset,
+	// and of their parameters, in the manifests served for the toolset.
+	DescriptionOverrides map[string]DescriptionOverride `yaml:"descriptionOverrides"`
+	// AuthRequired lists authServices required to invoke the tools of the
+	// toolset, in addition to their own, in every toolset.
+	AuthRequired []string `yaml:"authRequired"`
+}
+
+// DescriptionOverride is an alternative description of a tool, and of its
+// parameters by name. Empty fields keep the descriptions of the tool.
+type DescriptionOverride struct {
+	Description string            `yaml:"description"`
+	Parameters  map[string]string `yaml:"parameters"`
 }
 
 type Toolset struct {
@@ -48,15 +63,82 @@ func (t ToolsetConfig) Initialize(serverVersion string, toolsMap map[string]Tool
 		ServerVersion: serverVersion,
 		ToolsManifest: make(map[string]Manifest),
 	}
+	for name := range t.DescriptionOverrides {
+		if !slices.Contains(t.ToolNames, name) {
+			return toolset, fmt.Errorf("description override for tool %q that is not in the toolset", name)
+		}
+	}
 	for _, toolName := range t.ToolNames {
 		tool, ok := toolsMap[toolName]
 		if !ok {
 			return toolset, fmt.Errorf("tool does not exist: %s", t)
 		}
+		manifest, mcpManifest := tool.Manifest(), tool.McpManifest()
+		if override, ok := t.DescriptionOverrides[toolName]; ok {
+			var err error
+			manifest, mcpManifest, err = override.apply(manifest, mcpManifest)
+			if err != nil {
+				return toolset, fmt.Errorf("invalid description override for tool %q: %w", toolName, err)
+			}
+		}
 		toolset.Tools = append(toolset.Tools, &tool)
-		toolset.Manifest.ToolsManifest[toolName] = tool.Manifest()
-		toolset.McpManifest = append(toolset.McpManifest, tool.McpManifest())
+		toolset.Manifest.ToolsManifest[toolName] = manifest
+		toolset.McpManifest = append(toolset.McpManifest, mcpManifest)
 	}
 
 	return toolset, nil
 }
+
+// WithTags returns a copy of the toolset holding only the tools that have at
+// least one of tags. The toolset is returned as is if tags is empty.
+func (t Toolset) WithTags(tags []string) Toolset {
+	if len(tags) == 0 {
+		return t
+	}
+	filtered := Toolset{
+		Name: t.Name,
+		Manifest: ToolsetManifest{
+			ServerVersion: t.Manifest.ServerVersion,
+			ToolsManifest: make(map[string]Manifest),
+		},
+		McpManifest: []McpManifest{},
+	}
+	for name, m := range t.Manifest.ToolsManifest {
+		if hasAnyTag(m.Tags, tags) {
+			filtered.Manifest.ToolsManifest[name] = m
+		}
+	}
+	for _, m := range t.McpManifest {
+		if _, ok := filtered.Manifest.ToolsManifest[m.Name]; ok {
+			filtered.McpManifest = append(filtered.McpManifest, m)
+		}
+	}
+	return filtered
+}
+
+// apply returns copies of the manifests of a tool with the descriptions of o.
+// The manifests of the tool are shared by its toolsets, so they are left
+// unchanged.
+func (o DescriptionOverride) apply(manifest Manifest, mcpManifest McpManifest) (Manifest, McpManifest, error) {
+	if o.Description != "" {
+		manifest.Description = o.Description
+		mcpManifest.Description = o.Description
+	}
+	if len(o.Parameters) == 0 {
+		return manifest, mcpManifest, nil
+	}
+	manifest.Parameters = slices.Clone(manifest.Parameters)
+	mcpManifest.InputSchema.Properties = maps.Clone(mcpManifest.InputSchema.Properties)
+	for name, desc := range o.Parameters {
+		i := slices.IndexFunc(manifest.Parameters, func(p ParameterManifest) bool { return p.Name == name })
+		if i < 0 {
+			return manifest, mcpManifest, fmt.Errorf("parameter %q does not exist", name)
+		}
+		manifest.Parameters[i].Description = desc
+		if p, ok := mcpManifest.InputSchema.Properties[name]; ok {
+			p.Description = desc
+			mcpManifest.InputSchema.Properties[name] = p
+		}
+	}
+	return manifest, mcpManifest, nil
+}
@@ -0,0 +1,555 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+)
+
+// ResultTransformSpec is the kind-agnostic `resultTransform` block of a tool
+// config. It reshapes the rows returned by a tool.
+type ResultTransformSpec struct {
+	// Rename maps column names to their new names.
+	Rename map[string]string `yaml:"rename"`
+	// Drop lists the columns removed from the result.
+	Drop []string `yaml:"drop"`
+	// Compute maps the names of new columns to expressions over the
+	// columns of the row.
+	Compute map[string]string `yaml:"compute"`
+}
+
+// ResultTransform is a compiled ResultTransformSpec. It is applied to each row
+// in three steps: columns are renamed, computed columns are evaluated over
+// the renamed row, and finally columns are dropped.
+type ResultTransform struct {
+	rename  map[string]string
+	drop    []string
+	compute map[string]expr
+}
+
+// NewResultTransform validates spec and compiles its expressions.
+func NewResultTransform(spec ResultTransformSpec) (*ResultTransform, error) {
+	targets := make(map[string]string, len(spec.Rename))
+	// check in a stable order so that errors are deterministic
+	for _, from := range slices.Sorted(maps.Keys(spec.Rename)) {
+		to := spec.Rename[from]
+		if to == "" {
+			return nil, fmt.Errorf("rename of column %q has an empty target", from)
+		}
+		if other, ok := targets[to]; ok {
+			return nil, fmt.Errorf("columns %q and %q are both renamed to %q", other, from, to)
+		}
+		targets[to] = from
+	}
+
+	compute := make(map[string]expr, len(spec.Compute))
+	for _, name := range slices.Sorted(maps.Keys(spec.Compute)) {
+		if _, ok := targets[name]; ok {
+			return nil, fmt.Errorf("computed column %q collides with a rename target", name)
+		}
+		e, err := parseExpr(spec.Compute[name])
+		if err != nil {
+			return nil, fmt.Errorf("invalid expression for computed column %q: %w", name, err)
+		}
+		compute[name] = e
+	}
+	for _, name := range spec.Drop {
+		if _, ok := compute[name]; ok {
+			return nil, fmt.Errorf("computed column %q cannot be dropped", name)
+		}
+	}
+	return &ResultTransform{rename: spec.Rename, drop: spec.Drop, compute: compute}, nil
+}
+
+// Apply returns a transformed copy of row. Rows that are not a map of column
+// names to values are returned unchanged.
+func (t *ResultTransform) Apply(row any) any {
+	m, ok := row.(map[string]any)
+	if !ok {
+		return row
+	}
+
+	out := make(map[string]any, len(m)+len(t.compute))
+	for k, v := range m {
+		if _, ok := t.rename[k]; !ok {
+			out[k] = v
+		}
+	}
+	// a renamed column replaces an existing column with the same name
+	for from, to := range t.rename {
+		if v, ok := m[from]; ok {
+			out[to] = v
+		}
+	}
+
+	// computed columns only see the renamed row, not each other
+	computed := make(map[string]any, len(t.compute))
+	for name, e := range t.compute {
+		computed[name] = e.eval(out)
+	}
+	maps.Copy(out, computed)
+
+	for _, name := range t.drop {
+		delete(out, name)
+	}
+	return out
+}
+
+// TransformConfig wraps a ToolConfig with the kind-agnostic `resultTransform`
+// field.
+type TransformConfig struct {
+	ToolConfig
+	Transform *ResultTransform
+}
+
+// validate interface
+var _ ToolConfig = TransformConfig{}
+
+func (cfg TransformConfig) Initialize(srcs map[string]sources.Source) (Tool, error) {
+	t, err := cfg.ToolConfig.Initialize(srcs)
+	if err != nil {
+		return nil, err
+	}
+	return TransformTool{Tool: t, Transform: cfg.Transform}, nil
+}
+
+// TransformTool applies a ResultTransform to each row returned by a Tool.
+type TransformTool struct {
+	Tool
+	Transform *ResultTransform
+}
+
+func (t TransformTool) unwrap() Tool {
+	return t.Tool
+}
+
+func (t TransformTool) Invoke(ctx context.Context, inv Invocation) (any, error) {
+	res, err := t.Tool.Invoke(ctx, inv)
+	if err != nil {
+		return nil, err
+	}
+	switch r := res.(type) {
+	case RowIterator:
+		return transformRowIterator{RowIterator: r, transform: t.Transform}, nil
+	case []any:
+		out := make([]any, len(r))
+		for i, row := range r {
+			out[i] = t.Transform.Apply(row)
+		}
+		return out, nil
+	}
+	return res, nil
+}
+
+// transformRowIterator applies a ResultTransform to the rows of a RowIterator.
+type transformRowIterator struct {
+	RowIterator
+	transform *ResultTransform
+}
+
+func (it transformRowIterator) Row() (any, error) {
+	row, err := it.RowIterator.Row()
+	if err != nil {
+		return nil, err
+	}
+	return it.transform.Apply(row), nil
+}
+
+// The expressions of computed columns support:
+//
+//   - column references, either bare (`first_name`) or double-quoted
+//     (`"first name"`)
+//   - string literals in single quotes, number literals and `null`
+//   - string concatenation with `||`
+//   - arithmetic with `+`, `-`, `*`, `/` and parentheses
+//   - the functions `coalesce(a, b, ...)` and `concat(a, b, ...)`
+//
+// Expressions never fail at runtime. Like in SQL, an operator with a null
+// operand yields null, and so do unknown columns, operands of the wrong type
+// and division by zero. `concat` skips null arguments.
+type expr interface {
+	eval(row map[string]any) any
+}
+
+type literalExpr struct {
+	value any
+}
+
+func (e literalExpr) eval(map[string]any) any {
+	return e.value
+}
+
+type columnExpr struct {
+	name string
+}
+
+func (e columnExpr) eval(row map[string]any) any {
+	return row[e.name]
+}
+
+type negateExpr struct {
+	operand expr
+}
+
+func (e negateExpr) eval(row map[string]any) any {
+	return arithmetic("-", int64(0), e.operand.eval(row))
+}
+
+type binaryExpr struct {
+	op          string
+	left, right expr
+}
+
+func (e binaryExpr) eval(row map[string]any) any {
+	l, r := e.left.eval(row), e.right.eval(row)
+	if e.op == "||" {
+		if l == nil || r == nil {
+			return nil
+		}
+		return fmt.Sprint(l) + fmt.Sprint(r)
+	}
+	return arithmetic(e.op, l, r)
+}
+
+type callExpr struct {
+	fn   string
+	args []expr
+}
+
+func (e callExpr) eval(row map[string]any) any {
+	switch e.fn {
+	case "coalesce":
+		for _, a := range e.args {
+			if v := a.eval(row); v != nil {
+				return v
+			}
+		}
+		return nil
+	default: // concat
+		var sb strings.Builder
+		for _, a := range e.args {
+			if v := a.eval(row); v != nil {
+				sb.WriteString(fmt.Sprint(v))
+			}
+		}
+		return sb.String()
+	}
+}
+
+// toNumber converts v to an int64 if it is an integer, or to a float64 if it
+// is a floating point number.
+func toNumber(v any) (i int64, f float64, isInt bool, ok bool) {
+	if v == nil {
+		return 0, 0, false, false
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), float64(rv.Int()), true, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(rv.Uint()), float64(rv.Uint()), true, true
+	case reflect.Float32, reflect.Float64:
+		return 0, rv.Float(), false, true
+	}
+	return 0, 0, false, false
+}
+
+// arithmetic applies op to two numbers. Integer operands give an integer
+// result, except for division, which always gives a float.
+func arithmetic(op string, l, r any) any {
+	li, lf, lInt, lok := toNumber(l)
+	ri, rf, rInt, rok := toNumber(r)
+	if !lok || !rok {
+		return nil
+	}
+	if lInt && rInt && op != "/" {
+		switch op {
+		case "+":
+			return li + ri
+		case "-":
+			return li - ri
+		default:
+			return li * ri
+		}
+	}
+	switch op {
+	case "+":
+		return lf + rf
+	case "-":
+		return lf - rf
+	case "*":
+		return lf * rf
+	default:
+		if rf == 0 {
+			return nil
+		}
+		return lf / rf
+	}
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenIdent
+	tokenQuotedIdent
+	tokenOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func tokenize(s string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(s); {
+		c := rune(s[i])
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '|':
+			if i+1 >= len(s) || s[i+1] != '|' {
+				return nil, fmt.Errorf("unexpected %q at position %d, did you mean \"||\"?", c, i)
+			}
+			toks = append(toks, token{tokenOp, "||", i})
+			i += 2
+		case strings.ContainsRune("+-*/(),", c):
+			toks = append(toks, token{tokenOp, string(c), i})
+			i++
+		case c == '\'' || c == '"':
+			// quotes are escaped by doubling them, as in SQL
+			var sb strings.Builder
+			j := i + 1
+			for {
+				if j >= len(s) {
+					return nil, fmt.Errorf("unterminated quote at position %d", i)
+				}
+				if rune(s[j]) == c {
+					if j+1 < len(s) && rune(s[j+1]) == c {
+						sb.WriteRune(c)
+						j += 2
+						continue
+					}
+					break
+				}
+				sb.WriteByte(s[j])
+				j++
+			}
+			kind := tokenString
+			if c == '"' {
+				kind = tokenQuotedIdent
+			}
+			toks = append(toks, token{kind, sb.String(), i})
+			i = j + 1
+		case c >= '0' && c <= '9' || c == '.':
+			j := i
+			for j < len(s) && (s[j] >= '0' && s[j] <= '9' || s[j] == '.') {
+				j++
+			}
+			toks = append(toks, token{tokenNumber, s[i:j], i})
+			i = j
+		case isIdentByte(s[i]):
+			j := i
+			for j < len(s) && (isIdentByte(s[j]) || s[j] >= '0' && s[j] <= '9') {
+				j++
+			}
+			toks = append(toks, token{tokenIdent, s[i:j], i})
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected %q at position %d", c, i)
+		}
+	}
+	return append(toks, token{tokenEOF, "", len(s)}), nil
+}
+
+// isIdentByte reports whether b can start a bare column name. Column names
+// with other characters must be double-quoted.
+func isIdentByte(b byte) bool {
+	return b == '_' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// exprParser is a recursive descent parser for the expressions of computed
+// columns. From lowest to highest, the precedence levels are `||`, `+` and
+// `-`, `*` and `/`, and unary minus.
+type exprParser struct {
+	toks []token
+	pos  int
+}
+
+func parseExpr(s string) (expr, error) {
+	toks, err := tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{toks: toks}
+	e, err := p.parseConcat()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", t.text, t.pos)
+	}
+	return e, nil
+}
+
+func (p *exprParser) peek() token {
+	return p.toks[p.pos]
+}
+
+func (p *exprParser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *exprParser) isOp(ops ...string) bool {
+	t := p.peek()
+	return t.kind == tokenOp && slices.Contains(ops, t.text)
+}
+
+func (p *exprParser) expectOp(op string) error {
+	t := p.next()
+	if t.kind != tokenOp || t.text != op {
+		if t.kind == tokenEOF {
+			return fmt.Errorf("expected %q at end of expression", op)
+		}
+		return fmt.Errorf("expected %q at position %d, got %q", op, t.pos, t.text)
+	}
+	return nil
+}
+
+func (p *exprParser) parseBinary(ops []string, operand func() (expr, error)) (expr, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp(ops...) {
+		op := p.next().text
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryExpr{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseConcat() (expr, error) {
+	return p.parseBinary([]string{"||"}, p.parseAdditive)
+}
+
+func (p *exprParser) parseAdditive() (expr, error) {
+	return p.parseBinary([]string{"+", "-"}, p.parseMultiplicative)
+}
+
+func (p *exprParser) parseMultiplicative() (expr, error) {
+	return p.parseBinary([]string{"*", "/"}, p.parseUnary)
+}
+
+func (p *exprParser) parseUnary() (expr, error) {
+	if p.isOp("-") {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return negateExpr{operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (expr, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenNumber:
+		if i, err := strconv.ParseInt(t.text, 10, 64); err == nil {
+			return literalExpr{value: i}, nil
+		}
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", t.text, t.pos)
+		}
+		return literalExpr{value: f}, nil
+	case tokenString:
+		return literalExpr{value: t.text}, nil
+	case tokenQuotedIdent:
+		return columnExpr{name: t.text}, nil
+	case tokenIdent:
+		if p.isOp("(") {
+			return p.parseCall(t)
+		}
+		if strings.EqualFold(t.text, "null") {
+			return literalExpr{value: nil}, nil
+		}
+		return columnExpr{name: t.text}, nil
+	case tokenOp:
+		if t.text == "(" {
+			e, err := p.parseConcat()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectOp(")"); err != nil {
+				return nil, err
+			}
+			return e, nil
+		}
+		return nil, fmt.Errorf("unexpected %q at position %d", t.text, t.pos)
+	default:
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+}
+
+func (p *exprParser) parseCall(name token) (expr, error) {
+	fn := strings.ToLower(name.text)
+	if fn != "coalesce" && fn != "concat" {
+		return nil, fmt.Errorf("unknown function %q at position %d", name.text, name.pos)
+	}
+	p.next() // (
+	var args []expr
+	if !p.isOp(")") {
+		for {
+			a, err := p.parseConcat()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, a)
+			if !p.isOp(",") {
+				break
+			}
+			p.next()
+		}
+	}
+	if err := p.expectOp(")"); err != nil {
+		return nil, err
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("function %q at position %d requires at least one argument", name.text, name.pos)
+	}
+	return callExpr{fn: fn, args: args}, nil
+}
@@ -0,0 +1,264 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func mustTransform(t *testing.T, spec tools.ResultTransformSpec) *tools.ResultTransform {
+	t.Helper()
+	tr, err := tools.NewResultTransform(spec)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return tr
+}
+
+func TestResultTransformRename(t *testing.T) {
+	tcs := []struct {
+		desc   string
+		rename map[string]string
+		row    map[string]any
+		want   map[string]any
+	}{
+		{
+			desc:   "rename",
+			rename: map[string]string{"cust_nm": "customer_name"},
+			row:    map[string]any{"cust_nm": "ACME", "id": 1},
+			want:   map[string]any{"customer_name": "ACME", "id": 1},
+		},
+		{
+			desc:   "missing column",
+			rename: map[string]string{"cust_nm": "customer_name"},
+			row:    map[string]any{"id": 1},
+			want:   map[string]any{"id": 1},
+		},
+		{
+			desc:   "renamed column replaces existing column",
+			rename: map[string]string{"cust_nm": "name"},
+			row:    map[string]any{"cust_nm": "ACME", "name": "legacy"},
+			want:   map[string]any{"name": "ACME"},
+		},
+		{
+			desc:   "swap",
+			rename: map[string]string{"a": "b", "b": "a"},
+			row:    map[string]any{"a": 1, "b": 2},
+			want:   map[string]any{"a": 2, "b": 1},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			tr := mustTransform(t, tools.ResultTransformSpec{Rename: tc.rename})
+			if diff := cmp.Diff(tc.want, tr.Apply(tc.row)); diff != "" {
+				t.Fatalf("unexpected row: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestResultTransformDrop(t *testing.T) {
+	tr := mustTransform(t, tools.ResultTransformSpec{
+		Rename:  map[string]string{"cust_nm": "customer_name"},
+		Drop:    []string{"internal_id", "customer_name", "missing"},
+		Compute: map[string]string{"label": "customer_name || '!'"},
+	})
+	got := tr.Apply(map[string]any{"internal_id": 7, "cust_nm": "ACME", "id": 1})
+	// dropped columns are still visible to computed columns
+	want := map[string]any{"id": 1, "label": "ACME!"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected row: diff %v", diff)
+	}
+}
+
+func TestResultTransformCompute(t *testing.T) {
+	row := map[string]any{
+		"first":    "Ada",
+		"last":     "Lovelace",
+		"nickname": nil,
+		"price":    2.5,
+		"quantity": int32(4),
+		"count":    int64(7),
+		"zero":     0,
+		"my col":   "quoted",
+	}
+	tcs := []struct {
+		desc string
+		expr string
+		want any
+	}{
+		{desc: "concat operator", expr: "first || ' ' || last", want: "Ada Lovelace"},
+		{desc: "concat operator with number", expr: "'#' || count", want: "#7"},
+		{desc: "concat operator with null", expr: "first || nickname", want: nil},
+		{desc: "concat function skips null", expr: "concat(first, nickname, '!')", want: "Ada!"},
+		{desc: "escaped quote", expr: "'it''s ' || first", want: "it's Ada"},
+		{desc: "quoted column", expr: `"my col"`, want: "quoted"},
+		{desc: "float arithmetic", expr: "price * quantity", want: 10.0},
+		{desc: "integer arithmetic", expr: "count * 2 + quantity - 1", want: int64(17)},
+		{desc: "precedence", expr: "(count + 1) * 2", want: int64(16)},
+		{desc: "division", expr: "count / 2", want: 3.5},
+		{desc: "division by zero", expr: "count / zero", want: nil},
+		{desc: "unary minus", expr: "-count + 1", want: int64(-6)},
+		{desc: "arithmetic on string", expr: "first + 1", want: nil},
+		{desc: "coalesce", expr: "coalesce(nickname, first)", want: "Ada"},
+		{desc: "coalesce all null", expr: "COALESCE(nickname, null)", want: nil},
+		{desc: "unknown column", expr: "unknown_col", want: nil},
+		{desc: "unknown column in arithmetic", expr: "unknown_col * 2", want: nil},
+		{desc: "unknown column in coalesce", expr: "coalesce(unknown_col, 'n/a')", want: "n/a"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			tr := mustTransform(t, tools.ResultTransformSpec{Compute: map[string]string{"out": tc.expr}})
+			got := tr.Apply(row).(map[string]any)
+			if diff := cmp.Diff(tc.want, got["out"]); diff != "" {
+				t.Fatalf("unexpected value: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestResultTransformComputeAfterRename(t *testing.T) {
+	tr := mustTransform(t, tools.ResultTransformSpec{
+		Rename:  map[string]string{"cust_nm": "customer_name"},
+		Compute: map[string]string{"greeting": "'Hello ' || customer_name", "legacy": "cust_nm", "first": "'overridden'"},
+	})
+	got := tr.Apply(map[string]any{"cust_nm": "ACME", "first": "Ada"})
+	want := map[string]any{"customer_name": "ACME", "greeting": "Hello ACME", "legacy": nil, "first": "overridden"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected row: diff %v", diff)
+	}
+}
+
+func TestNewResultTransformErrors(t *testing.T) {
+	tcs := []struct {
+		desc string
+		spec tools.ResultTransformSpec
+		err  string
+	}{
+		{
+			desc: "duplicate rename target",
+			spec: tools.ResultTransformSpec{Rename: map[string]string{"a": "c", "b": "c"}},
+			err:  `columns "a" and "b" are both renamed to "c"`,
+		},
+		{
+			desc: "empty rename target",
+			spec: tools.ResultTransformSpec{Rename: map[string]string{"a": ""}},
+			err:  `rename of column "a" has an empty target`,
+		},
+		{
+			desc: "computed column collides with rename target",
+			spec: tools.ResultTransformSpec{Rename: map[string]string{"a": "b"}, Compute: map[string]string{"b": "1"}},
+			err:  `computed column "b" collides with a rename target`,
+		},
+		{
+			desc: "dropped computed column",
+			spec: tools.ResultTransformSpec{Drop: []string{"b"}, Compute: map[string]string{"b": "1"}},
+			err:  `computed column "b" cannot be dropped`,
+		},
+		{
+			desc: "unknown function",
+			spec: tools.ResultTransformSpec{Compute: map[string]string{"b": "upper(a)"}},
+			err:  `unknown function "upper" at position 0`,
+		},
+		{
+			desc: "unbalanced parenthesis",
+			spec: tools.ResultTransformSpec{Compute: map[string]string{"b": "(a + 1"}},
+			err:  `expected ")" at end of expression`,
+		},
+		{
+			desc: "unterminated string",
+			spec: tools.ResultTransformSpec{Compute: map[string]string{"b": "'abc"}},
+			err:  "unterminated quote at position 0",
+		},
+		{
+			desc: "single pipe",
+			spec: tools.ResultTransformSpec{Compute: map[string]string{"b": "a | c"}},
+			err:  "unexpected '|' at position 2",
+		},
+		{
+			desc: "trailing operator",
+			spec: tools.ResultTransformSpec{Compute: map[string]string{"b": "a +"}},
+			err:  "unexpected end of expression",
+		},
+		{
+			desc: "coalesce without arguments",
+			spec: tools.ResultTransformSpec{Compute: map[string]string{"b": "coalesce()"}},
+			err:  `function "coalesce" at position 0 requires at least one argument`,
+		},
+		{
+			desc: "invalid number",
+			spec: tools.ResultTransformSpec{Compute: map[string]string{"b": "1.2.3"}},
+			err:  `invalid number "1.2.3" at position 0`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := tools.NewResultTransform(tc.spec)
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			if !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("unexpected error: got %q, want substring %q", err, tc.err)
+			}
+		})
+	}
+}
+
+type rowsTool struct {
+	mockTool
+	res any
+}
+
+func (t rowsTool) Invoke(context.Context, tools.Invocation) (any, error) {
+	return t.res, nil
+}
+
+func TestTransformToolInvoke(t *testing.T) {
+	tr := mustTransform(t, tools.ResultTransformSpec{Rename: map[string]string{"cust_nm": "customer_name"}})
+	rows := []any{map[string]any{"cust_nm": "ACME"}, map[string]any{"cust_nm": "Initech"}}
+	want := []any{map[string]any{"customer_name": "ACME"}, map[string]any{"customer_name": "Initech"}}
+
+	tcs := []struct {
+		desc string
+		res  any
+		want any
+	}{
+		{desc: "slice", res: rows, want: want},
+		{desc: "iterator", res: tools.NewSliceRowIterator(rows), want: want},
+		{desc: "other result", res: "done", want: "done"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			tool := tools.TransformTool{Tool: rowsTool{res: tc.res}, Transform: tr}
+			got, err := tool.Invoke(context.Background(), tools.Invocation{})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if it, ok := got.(tools.RowIterator); ok {
+				if got, err = tools.CollectRows(it); err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected result: diff %v", diff)
+			}
+		})
+	}
+}
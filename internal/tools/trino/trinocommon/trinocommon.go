@@ -0,0 +1,222 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trinocommon
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/trinodb/trino-go-client/trino"
+)
+
+// sessionHeader is the request header the Trino driver uses to set session
+// properties for a single query.
+const sessionHeader = "X-Trino-Session"
+
+// SessionArgs returns the query arguments that set the given session
+// properties. They must be passed after any positional arguments.
+func SessionArgs(props map[string]string) []any {
+	args := make([]any, 0, len(props))
+	for _, k := range slices.Sorted(maps.Keys(props)) {
+		args = append(args, sql.Named(sessionHeader, fmt.Sprintf("%s=%s", k, props[k])))
+	}
+	return args
+}
+
+// QueryError surfaces the error name and failure message reported by Trino for
+// a failed query. Other errors are returned unchanged.
+func QueryError(err error) error {
+	var te *trino.ErrTrino
+	if errors.As(err, &te) {
+		return fmt.Errorf("%s: %s", te.ErrorName, te.Message)
+	}
+	return err
+}
+
+// ScanRows reads all rows into a slice of column name to value maps,
+// converting Trino types into JSON friendly values.
+func ScanRows(rows *sql.Rows) ([]any, error) {
+	colTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve column types: %w", err)
+	}
+	types := make([]*trinoType, len(colTypes))
+	for i, ct := range colTypes {
+		types[i] = parseType(ct.DatabaseTypeName())
+	}
+
+	// create an array of values for each column, which can be re-used to scan each row
+	rawValues := make([]any, len(colTypes))
+	values := make([]any, len(colTypes))
+	for i := range rawValues {
+		values[i] = &rawValues[i]
+	}
+
+	var out []any
+	for rows.Next() {
+		err := rows.Scan(values...)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse row: %w", err)
+		}
+		vMap := make(map[string]any)
+		for i, ct := range colTypes {
+			vMap[ct.Name()] = types[i].convert(rawValues[i])
+		}
+		out = append(out, vMap)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("errors encountered during row iteration: %w", QueryError(err))
+	}
+	return out, nil
+}
+
+// ConvertValue converts a value returned by the Trino driver for a column of
+// the given database type into a JSON friendly value. DECIMAL values become
+// JSON numbers, ROW values become objects keyed by field name, and the
+// elements of ARRAY and MAP values are converted recursively.
+func ConvertValue(databaseType string, v any) any {
+	return parseType(databaseType).convert(v)
+}
+
+// trinoType is a parsed Trino type signature such as
+// `ROW(ID BIGINT, TAGS ARRAY(VARCHAR))`.
+type trinoType struct {
+	// name is the lower case base name of the type, e.g. "row".
+	name string
+	// args are the type arguments of array, map and row types.
+	args []*trinoType
+	// fields are the field names of a row type.
+	fields []string
+}
+
+func parseType(s string) *trinoType {
+	s = strings.TrimSpace(s)
+	t := &trinoType{}
+	open := strings.IndexByte(s, '(')
+	if open < 0 || !strings.HasSuffix(s, ")") {
+		t.name = strings.ToLower(strings.Fields(s + " x")[0])
+		return t
+	}
+	t.name = strings.ToLower(strings.TrimSpace(s[:open]))
+	switch t.name {
+	case "array", "map":
+		for _, arg := range splitArgs(s[open+1 : len(s)-1]) {
+			t.args = append(t.args, parseType(arg))
+		}
+	case "row":
+		for i, arg := range splitArgs(s[open+1 : len(s)-1]) {
+			name, typ := splitField(arg)
+			if name == "" {
+				name = fmt.Sprintf("field%d", i)
+			}
+			t.fields = append(t.fields, name)
+			t.args = append(t.args, parseType(typ))
+		}
+	}
+	return t
+}
+
+// splitArgs splits a comma separated list of type arguments, ignoring commas
+// nested in parentheses or quotes.
+func splitArgs(s string) []string {
+	var args []string
+	depth, start := 0, 0
+	quoted := false
+	for i, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case quoted:
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ',' && depth == 0:
+			args = append(args, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(args, s[start:])
+}
+
+// splitField splits a row field into its name and type. The name is empty for
+// anonymous fields.
+func splitField(s string) (string, string) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, `"`) {
+		if end := strings.Index(s[1:], `"`); end >= 0 {
+			return s[1 : end+1], s[end+2:]
+		}
+	}
+	name, typ, ok := strings.Cut(s, " ")
+	// names cannot contain parentheses, and `TIME WITH TIME ZONE` is a type
+	if !ok || strings.ContainsAny(name, "()") || strings.HasPrefix(strings.ToUpper(typ), "WITH ") {
+		return "", s
+	}
+	// the driver reports type names in upper case; unquoted identifiers are
+	// lower case in Trino
+	return strings.ToLower(name), typ
+}
+
+func (t *trinoType) convert(v any) any {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return string(v)
+	case string:
+		if t.name == "decimal" {
+			return json.Number(v)
+		}
+		return v
+	case []any:
+		switch {
+		case t.name == "row":
+			m := make(map[string]any, len(v))
+			for i, e := range v {
+				if i < len(t.fields) {
+					m[t.fields[i]] = t.args[i].convert(e)
+				} else {
+					m[fmt.Sprintf("field%d", i)] = e
+				}
+			}
+			return m
+		case t.name == "array" && len(t.args) == 1:
+			out := make([]any, len(v))
+			for i, e := range v {
+				out[i] = t.args[0].convert(e)
+			}
+			return out
+		}
+		return v
+	case map[string]any:
+		if t.name == "map" && len(t.args) == 2 {
+			out := make(map[string]any, len(v))
+			for k, e := range v {
+				out[k] = t.args[1].convert(e)
+			}
+			return out
+		}
+		return v
+	default:
+		return v
+	}
+}
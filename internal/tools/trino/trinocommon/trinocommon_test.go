@@ -0,0 +1,152 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trinocommon_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools/trino/trinocommon"
+	"github.com/trinodb/trino-go-client/trino"
+)
+
+func TestConvertValue(t *testing.T) {
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 0, time.FixedZone("", 2*60*60))
+	tcs := []struct {
+		name   string
+		dbType string
+		in     any
+		want   any
+	}{
+		{
+			name:   "null",
+			dbType: "VARCHAR",
+			in:     nil,
+			want:   nil,
+		},
+		{
+			name:   "varchar",
+			dbType: "VARCHAR",
+			in:     "foo",
+			want:   "foo",
+		},
+		{
+			name:   "varbinary",
+			dbType: "VARBINARY",
+			in:     []byte("foo"),
+			want:   "foo",
+		},
+		{
+			name:   "decimal",
+			dbType: "DECIMAL",
+			in:     "12345678901234567890.12",
+			want:   json.Number("12345678901234567890.12"),
+		},
+		{
+			name:   "timestamp with time zone",
+			dbType: "TIMESTAMP WITH TIME ZONE",
+			in:     ts,
+			want:   ts,
+		},
+		{
+			name:   "array of decimal",
+			dbType: "ARRAY(DECIMAL(10, 2))",
+			in:     []any{"1.50", nil},
+			want:   []any{json.Number("1.50"), nil},
+		},
+		{
+			name:   "map of decimal",
+			dbType: "MAP(VARCHAR, DECIMAL(10, 2))",
+			in:     map[string]any{"a": "1.50"},
+			want:   map[string]any{"a": json.Number("1.50")},
+		},
+		{
+			name:   "row",
+			dbType: "ROW(ID BIGINT, PRICE DECIMAL(10, 2), TAGS ARRAY(VARCHAR))",
+			in:     []any{json.Number("1"), "9.99", []any{"a", "b"}},
+			want:   map[string]any{"id": json.Number("1"), "price": json.Number("9.99"), "tags": []any{"a", "b"}},
+		},
+		{
+			name:   "row with quoted field names",
+			dbType: `ROW("First Name" VARCHAR, "a,b" INTEGER)`,
+			in:     []any{"Alice", json.Number("2")},
+			want:   map[string]any{"First Name": "Alice", "a,b": json.Number("2")},
+		},
+		{
+			name:   "anonymous row",
+			dbType: "ROW(INTEGER, TIME WITH TIME ZONE)",
+			in:     []any{json.Number("1"), "01:02:03.000 UTC"},
+			want:   map[string]any{"field0": json.Number("1"), "field1": "01:02:03.000 UTC"},
+		},
+		{
+			name:   "array of rows",
+			dbType: "ARRAY(ROW(NAME VARCHAR, SCORE DECIMAL(5, 1)))",
+			in:     []any{[]any{"a", "1.5"}, []any{"b", "2.0"}},
+			want: []any{
+				map[string]any{"name": "a", "score": json.Number("1.5")},
+				map[string]any{"name": "b", "score": json.Number("2.0")},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got := trinocommon.ConvertValue(tc.dbType, tc.in)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected value (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSessionArgs(t *testing.T) {
+	got := trinocommon.SessionArgs(map[string]string{
+		"query_max_run_time":     "10m",
+		"join_distribution_type": "BROADCAST",
+	})
+	want := []any{
+		sql.Named("X-Trino-Session", "join_distribution_type=BROADCAST"),
+		sql.Named("X-Trino-Session", "query_max_run_time=10m"),
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected args: got %v, want %v", got, want)
+	}
+}
+
+func TestQueryError(t *testing.T) {
+	failed := &trino.ErrQueryFailed{
+		StatusCode: 200,
+		Reason: &trino.ErrTrino{
+			Message:   "line 1:8: Column 'foo' cannot be resolved",
+			ErrorName: "COLUMN_NOT_FOUND",
+			ErrorType: "USER_ERROR",
+		},
+	}
+	got := trinocommon.QueryError(fmt.Errorf("wrapped: %w", failed))
+	want := "COLUMN_NOT_FOUND: line 1:8: Column 'foo' cannot be resolved"
+	if got.Error() != want {
+		t.Fatalf("unexpected error: got %q, want %q", got, want)
+	}
+
+	other := errors.New("connection refused")
+	if got := trinocommon.QueryError(other); got != other {
+		t.Fatalf("expected error to be unchanged, got %q", got)
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/sources/trino"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/trino/trinocommon"
 )
 
 const kind string = "trino-execute-sql"
@@ -31,6 +32,7 @@ func init() {
 	if !tools.Register(kind, newConfig) {
 		panic(fmt.Sprintf("tool kind %q already registered", kind))
 	}
+	tools.RegisterOutputSchema(kind, tools.RowsOutputSchema)
 }
 
 func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
@@ -51,11 +53,12 @@ var _ compatibleSource = &trino.Source{}
 var compatibleSources = [...]string{trino.SourceKind}
 
 type Config struct {
-	Name         string   `yaml:"name" validate:"required"`
-	Kind         string   `yaml:"kind" validate:"required"`
-	Source       string   `yaml:"source" validate:"required"`
-	Description  string   `yaml:"description" validate:"required"`
-	AuthRequired []string `yaml:"authRequired"`
+	Name              string            `yaml:"name" validate:"required"`
+	Kind              string            `yaml:"kind" validate:"required"`
+	Source            string            `yaml:"source" validate:"required"`
+	Description       string            `yaml:"description" validate:"required"`
+	AuthRequired      []string          `yaml:"authRequired"`
+	SessionProperties map[string]string `yaml:"sessionProperties"`
 }
 
 // validate interface
@@ -85,13 +88,14 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 	// finish tool setup
 	t := Tool{
-		Name:         cfg.Name,
-		Kind:         kind,
-		Parameters:   parameters,
-		AuthRequired: cfg.AuthRequired,
-		Db:           s.TrinoDB(),
-		manifest:     tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
-		mcpManifest:  mcpManifest,
+		Name:              cfg.Name,
+		Kind:              kind,
+		Parameters:        parameters,
+		AuthRequired:      cfg.AuthRequired,
+		SessionProperties: cfg.SessionProperties,
+		Db:                s.TrinoDB(),
+		manifest:          tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:       mcpManifest,
 	}
 	return t, nil
 }
@@ -105,65 +109,26 @@ type Tool struct {
 	AuthRequired []string         `yaml:"authRequired"`
 	Parameters   tools.Parameters `yaml:"parameters"`
 
-	Db          *sql.DB
-	manifest    tools.Manifest
-	mcpManifest tools.McpManifest
+	SessionProperties map[string]string
+	Db                *sql.DB
+	manifest          tools.Manifest
+	mcpManifest       tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	sliceParams := params.AsSlice()
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	sliceParams := inv.Params.AsSlice()
 	sql, ok := sliceParams[0].(string)
 	if !ok {
 		return nil, fmt.Errorf("unable to cast sql parameter: %v", sliceParams[0])
 	}
 
-	results, err := t.Db.QueryContext(ctx, sql)
+	results, err := t.Db.QueryContext(ctx, sql, trinocommon.SessionArgs(t.SessionProperties)...)
 	if err != nil {
-		return nil, fmt.Errorf("unable to execute query: %w", err)
+		return nil, fmt.Errorf("unable to execute query: %w", trinocommon.QueryError(err))
 	}
 	defer results.Close()
 
-	cols, err := results.Columns()
-	if err != nil {
-		return nil, fmt.Errorf("unable to retrieve column names: %w", err)
-	}
-
-	// create an array of values for each column, which can be re-used to scan each row
-	rawValues := make([]any, len(cols))
-	values := make([]any, len(cols))
-	for i := range rawValues {
-		values[i] = &rawValues[i]
-	}
-
-	var out []any
-	for results.Next() {
-		err := results.Scan(values...)
-		if err != nil {
-			return nil, fmt.Errorf("unable to parse row: %w", err)
-		}
-		vMap := make(map[string]any)
-		for i, name := range cols {
-			val := rawValues[i]
-			if val == nil {
-				vMap[name] = nil
-				continue
-			}
-
-			// Convert byte arrays to strings for text fields
-			if b, ok := val.([]byte); ok {
-				vMap[name] = string(b)
-			} else {
-				vMap[name] = val
-			}
-		}
-		out = append(out, vMap)
-	}
-
-	if err := results.Err(); err != nil {
-		return nil, fmt.Errorf("errors encountered during row iteration: %w", err)
-	}
-
-	return out, nil
+	return trinocommon.ScanRows(results)
 }
 
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
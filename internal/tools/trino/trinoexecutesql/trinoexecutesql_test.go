@@ -56,6 +56,32 @@ func TestParseFromYamlTrinoExecuteSQL(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with session properties",
+			in: `
+			tools:
+				example_tool:
+					kind: trino-execute-sql
+					source: my-trino-instance
+					description: some description
+					sessionProperties:
+						query_max_run_time: 10m
+						join_distribution_type: BROADCAST
+			`,
+			want: server.ToolConfigs{
+				"example_tool": trinoexecutesql.Config{
+					Name:         "example_tool",
+					Kind:         "trino-execute-sql",
+					Source:       "my-trino-instance",
+					Description:  "some description",
+					AuthRequired: []string{},
+					SessionProperties: map[string]string{
+						"query_max_run_time":     "10m",
+						"join_distribution_type": "BROADCAST",
+					},
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -74,6 +74,30 @@ func TestParseFromYamlTrino(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with session properties",
+			in: `
+			tools:
+				example_tool:
+					kind: trino-sql
+					source: my-trino-instance
+					description: some description
+					statement: SELECT * FROM catalog.schema.table;
+					sessionProperties:
+						query_max_run_time: 10m
+			`,
+			want: server.ToolConfigs{
+				"example_tool": trinosql.Config{
+					Name:              "example_tool",
+					Kind:              "trino-sql",
+					Source:            "my-trino-instance",
+					Description:       "some description",
+					Statement:         "SELECT * FROM catalog.schema.table;",
+					AuthRequired:      []string{},
+					SessionProperties: map[string]string{"query_max_run_time": "10m"},
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -0,0 +1,167 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"io"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+)
+
+// UsageMetadata describes the size of the result of an invocation, so that
+// clients can budget the context of an LLM.
+type UsageMetadata struct {
+	// Bytes is the size of the result serialized as JSON.
+	Bytes int `json:"bytes"`
+	// Rows is the number of rows of the result. A result that is not a list
+	// counts as a single row.
+	Rows int `json:"rows"`
+	// EstimatedTokens is an estimate of the number of LLM tokens of the
+	// serialized result.
+	EstimatedTokens int `json:"estimatedTokens"`
+}
+
+// TokenEstimator estimates the number of LLM tokens of a serialized result.
+// The result is written to it while it is being encoded, so that estimating
+// does not require a second serialization pass.
+type TokenEstimator interface {
+	io.Writer
+	EstimatedTokens() int
+}
+
+// bytesPerToken is the average number of bytes of a token used by
+// ByteTokenEstimator.
+const bytesPerToken = 4
+
+// ByteTokenEstimator estimates one token per four bytes, which is a common
+// rule of thumb for English text and JSON.
+type ByteTokenEstimator struct {
+	n int
+}
+
+func (e *ByteTokenEstimator) Write(p []byte) (int, error) {
+	e.n += len(p)
+	return len(p), nil
+}
+
+func (e *ByteTokenEstimator) EstimatedTokens() int {
+	return (e.n + bytesPerToken - 1) / bytesPerToken
+}
+
+// UsageCounter accumulates the UsageMetadata of a result while it is written.
+type UsageCounter struct {
+	bytes     int
+	rows      int
+	estimator TokenEstimator
+}
+
+// NewUsageCounter returns a UsageCounter estimating tokens with estimator. If
+// estimator is nil, a ByteTokenEstimator is used.
+func NewUsageCounter(estimator TokenEstimator) *UsageCounter {
+	if estimator == nil {
+		estimator = &ByteTokenEstimator{}
+	}
+	return &UsageCounter{estimator: estimator}
+}
+
+// Write counts p as part of the serialized result.
+func (c *UsageCounter) Write(p []byte) (int, error) {
+	c.bytes += len(p)
+	return c.estimator.Write(p)
+}
+
+// AddRows counts n more rows of the result.
+func (c *UsageCounter) AddRows(n int) {
+	c.rows += n
+}
+
+// Metadata returns the usage counted so far.
+func (c *UsageCounter) Metadata() UsageMetadata {
+	return UsageMetadata{
+		Bytes:           c.bytes,
+		Rows:            c.rows,
+		EstimatedTokens: c.estimator.EstimatedTokens(),
+	}
+}
+
+// RowCount returns the number of rows of a result that is not a RowIterator.
+func RowCount(res any) int {
+	switch r := res.(type) {
+	case nil:
+		return 0
+	case []any:
+		return len(r)
+	}
+	return 1
+}
+
+// MeteredResult is returned by a UsageMetadataTool. It marks a result whose
+// UsageMetadata should be included in the response.
+type MeteredResult struct {
+	Result any
+	// NewEstimator creates the TokenEstimator of the result. If nil, a
+	// ByteTokenEstimator is used.
+	NewEstimator func() TokenEstimator
+}
+
+// NewCounter returns a UsageCounter for the result.
+func (r MeteredResult) NewCounter() *UsageCounter {
+	if r.NewEstimator == nil {
+		return NewUsageCounter(nil)
+	}
+	return NewUsageCounter(r.NewEstimator())
+}
+
+// UsageMetadataConfig wraps a ToolConfig with the kind-agnostic
+// `includeUsageMetadata` field.
+type UsageMetadataConfig struct {
+	ToolConfig
+	Include bool
+}
+
+// validate interface
+var _ ToolConfig = UsageMetadataConfig{}
+
+func (cfg UsageMetadataConfig) Initialize(srcs map[string]sources.Source) (Tool, error) {
+	t, err := cfg.ToolConfig.Initialize(srcs)
+	if err != nil {
+		return nil, err
+	}
+	if !cfg.Include {
+		return t, nil
+	}
+	return UsageMetadataTool{Tool: t}, nil
+}
+
+// UsageMetadataTool wraps the results of a Tool in a MeteredResult.
+type UsageMetadataTool struct {
+	Tool
+	// NewEstimator creates the TokenEstimator of each result. If nil, a
+	// ByteTokenEstimator is used.
+	NewEstimator func() TokenEstimator
+}
+
+func (t UsageMetadataTool) unwrap() Tool {
+	return t.Tool
+}
+
+func (t UsageMetadataTool) Invoke(ctx context.Context, inv Invocation) (any, error) {
+	res, err := t.Tool.Invoke(ctx, inv)
+	if err != nil {
+		return nil, err
+	}
+	return MeteredResult{Result: res, NewEstimator: t.NewEstimator}, nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestByteTokenEstimatorAccuracy(t *testing.T) {
+	// "The quick brown fox jumps over the lazy dog." is 10 tokens for common
+	// BPE tokenizers.
+	const sentence = "The quick brown fox jumps over the lazy dog."
+	const wantTokens = 10 * 100
+	payload := strings.Repeat(sentence, 100)
+
+	c := tools.NewUsageCounter(nil)
+	// the payload is written in chunks, as it is while encoding
+	for i := 0; i < len(payload); i += 7 {
+		if _, err := c.Write([]byte(payload[i:min(i+7, len(payload))])); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	got := c.Metadata()
+	if got.Bytes != len(payload) {
+		t.Fatalf("unexpected bytes: got %d, want %d", got.Bytes, len(payload))
+	}
+	if got.EstimatedTokens != (len(payload)+3)/4 {
+		t.Fatalf("unexpected estimate: got %d, want %d", got.EstimatedTokens, (len(payload)+3)/4)
+	}
+	if got.EstimatedTokens < wantTokens*3/4 || got.EstimatedTokens > wantTokens*5/4 {
+		t.Fatalf("estimate %d is not within 25%% of %d tokens", got.EstimatedTokens, wantTokens)
+	}
+}
+
+// wordEstimator counts a token per space-separated word.
+type wordEstimator struct {
+	words int
+}
+
+func (e *wordEstimator) Write(p []byte) (int, error) {
+	e.words += strings.Count(string(p), " ")
+	return len(p), nil
+}
+
+func (e *wordEstimator) EstimatedTokens() int {
+	return e.words
+}
+
+func TestUsageCounterCustomEstimator(t *testing.T) {
+	c := tools.NewUsageCounter(&wordEstimator{})
+	if _, err := c.Write([]byte(`"a b c "`)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	c.AddRows(2)
+	want := tools.UsageMetadata{Bytes: 8, Rows: 2, EstimatedTokens: 3}
+	if got := c.Metadata(); got != want {
+		t.Fatalf("unexpected metadata: got %+v, want %+v", got, want)
+	}
+}
+
+func TestRowCount(t *testing.T) {
+	tcs := []struct {
+		desc string
+		res  any
+		want int
+	}{
+		{desc: "nil", res: nil, want: 0},
+		{desc: "rows", res: []any{1, 2, 3}, want: 3},
+		{desc: "single value", res: "some result", want: 1},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := tools.RowCount(tc.res); got != tc.want {
+				t.Fatalf("unexpected row count: got %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUsageMetadataConfigInitialize(t *testing.T) {
+	tcs := []struct {
+		desc        string
+		include     bool
+		wantWrapped bool
+	}{
+		{desc: "included", include: true, wantWrapped: true},
+		{desc: "not included", include: false, wantWrapped: false},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg := tools.UsageMetadataConfig{ToolConfig: mockToolConfig{name: "mock"}, Include: tc.include}
+			tool, err := cfg.Initialize(nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if _, ok := tool.(tools.UsageMetadataTool); ok != tc.wantWrapped {
+				t.Fatalf("unexpected tool type %T", tool)
+			}
+		})
+	}
+}
+
+func TestUsageMetadataToolInvoke(t *testing.T) {
+	rows := []any{map[string]any{"id": 1}}
+	tool := tools.UsageMetadataTool{Tool: rowsTool{res: rows}}
+	got, err := tool.Invoke(context.Background(), tools.Invocation{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	mr, ok := got.(tools.MeteredResult)
+	if !ok {
+		t.Fatalf("unexpected result type %T", got)
+	}
+	b, err := json.Marshal(mr.Result)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(b) != `[{"id":1}]` {
+		t.Fatalf("unexpected result: got %s", b)
+	}
+}
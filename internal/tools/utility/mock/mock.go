@@ -0,0 +1,284 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mock
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+const kind string = "mock"
+
+// Wildcard is the match value that matches any value of a parameter.
+const Wildcard = "*"
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+// Response is a canned response of a mock tool.
+type Response struct {
+	// Match maps parameter names to the values the response is returned for.
+	// A value of "*" matches any value. Parameters that are not listed match
+	// any value.
+	Match map[string]any `yaml:"match"`
+	// Result is returned as the result of the invocation.
+	Result any `yaml:"result"`
+	// Latency is the time to wait before responding, e.g. "200ms".
+	Latency string `yaml:"latency"`
+	// Error, if set, fails the invocation with this message instead of
+	// returning Result.
+	Error string `yaml:"error"`
+	// StatusCode is the HTTP status code of the response to a failed
+	// invocation. It defaults to 400.
+	StatusCode int `yaml:"statusCode"`
+}
+
+type Config struct {
+	Name         string           `yaml:"name" validate:"required"`
+	Kind         string           `yaml:"kind" validate:"required"`
+	Description  string           `yaml:"description" validate:"required"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+	Responses    []Response       `yaml:"responses"`
+	Default      *Response        `yaml:"default"`
+	AuthRequired []string         `yaml:"authRequired"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(_ map[string]sources.Source) (tools.Tool, error) {
+	declared := make(map[string]bool, len(cfg.Parameters))
+	for _, p := range cfg.Parameters {
+		declared[p.GetName()] = true
+	}
+
+	responses := make([]response, 0, len(cfg.Responses))
+	for i, r := range cfg.Responses {
+		for name := range r.Match {
+			if !declared[name] {
+				return nil, fmt.Errorf("response %d of mock tool %q matches on %q, which is not a declared parameter", i, cfg.Name, name)
+			}
+		}
+		resp, err := newResponse(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid response %d of mock tool %q: %w", i, cfg.Name, err)
+		}
+		responses = append(responses, resp)
+	}
+	var def *response
+	if cfg.Default != nil {
+		if len(cfg.Default.Match) > 0 {
+			return nil, fmt.Errorf("the default response of mock tool %q cannot have a match", cfg.Name)
+		}
+		resp, err := newResponse(*cfg.Default)
+		if err != nil {
+			return nil, fmt.Errorf("invalid default response of mock tool %q: %w", cfg.Name, err)
+		}
+		def = &resp
+	}
+
+	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, cfg.Parameters)
+
+	// finish tool setup
+	t := Tool{
+		Name:         cfg.Name,
+		Kind:         kind,
+		Parameters:   cfg.Parameters,
+		AuthRequired: cfg.AuthRequired,
+		responses:    responses,
+		def:          def,
+		manifest:     tools.Manifest{Description: cfg.Description, Parameters: cfg.Parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}
+	return t, nil
+}
+
+// response is a validated Response, whose match values are encoded as JSON
+// so that they compare equal to parameter values of any Go type.
+type response struct {
+	exact     map[string][]byte
+	wildcards []string
+	result    any
+	latency   time.Duration
+	err       error
+}
+
+func newResponse(r Response) (response, error) {
+	resp := response{exact: make(map[string][]byte), result: r.Result}
+	for name, v := range r.Match {
+		if v == Wildcard {
+			resp.wildcards = append(resp.wildcards, name)
+			continue
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return response{}, fmt.Errorf("unable to encode the match value of %q: %w", name, err)
+		}
+		resp.exact[name] = b
+	}
+	if r.Latency != "" {
+		latency, err := time.ParseDuration(r.Latency)
+		if err != nil {
+			return response{}, fmt.Errorf("invalid latency %q: %w", r.Latency, err)
+		}
+		if latency < 0 {
+			return response{}, fmt.Errorf("latency must not be negative, got %q", r.Latency)
+		}
+		resp.latency = latency
+	}
+	switch {
+	case r.Error != "" && r.Result != nil:
+		return response{}, fmt.Errorf("only one of result or error can be set")
+	case r.Error == "" && r.StatusCode != 0:
+		return response{}, fmt.Errorf("statusCode can only be set along with error")
+	case r.StatusCode != 0 && (r.StatusCode < 400 || r.StatusCode > 599):
+		return response{}, fmt.Errorf("statusCode must be between 400 and 599, got %d", r.StatusCode)
+	}
+	if r.Error != "" {
+		resp.err = errors.New(r.Error)
+		if r.StatusCode != 0 {
+			resp.err = &tools.StatusError{Err: resp.err, Code: r.StatusCode}
+		}
+	}
+	return resp, nil
+}
+
+// matches reports whether the response matches params, which are encoded as
+// JSON.
+func (r response) matches(params map[string][]byte) bool {
+	for name, want := range r.exact {
+		if got, ok := params[name]; !ok || !bytes.Equal(got, want) {
+			return false
+		}
+	}
+	for _, name := range r.wildcards {
+		if _, ok := params[name]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// moreSpecific reports whether r matches fewer parameter values than o: it
+// matches more parameters exactly, or as many but more with a wildcard.
+func (r response) moreSpecific(o response) bool {
+	if len(r.exact) != len(o.exact) {
+		return len(r.exact) > len(o.exact)
+	}
+	return len(r.wildcards) > len(o.wildcards)
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string           `yaml:"name"`
+	Kind         string           `yaml:"kind"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+
+	responses   []response
+	def         *response
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	// null values are treated as missing, so that they only match the
+	// responses that do not list them
+	encoded := make(map[string][]byte, len(inv.Params))
+	for _, p := range inv.Params {
+		if p.Value == nil {
+			continue
+		}
+		b, err := json.Marshal(p.Value)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode parameter %q: %w", p.Name, err)
+		}
+		encoded[p.Name] = b
+	}
+
+	var resp *response
+	for i := range t.responses {
+		r := &t.responses[i]
+		// the first of equally specific responses wins
+		if r.matches(encoded) && (resp == nil || r.moreSpecific(*resp)) {
+			resp = r
+		}
+	}
+	if resp == nil {
+		resp = t.def
+	}
+	if resp == nil {
+		return nil, fmt.Errorf("no response of mock tool %q matches the parameters", t.Name)
+	}
+
+	if resp.latency > 0 {
+		timer := time.NewTimer(resp.latency)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if resp.err != nil {
+		return nil, resp.err
+	}
+	return resp.result, nil
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return false
+}
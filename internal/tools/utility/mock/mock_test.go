@@ -0,0 +1,372 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mock_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/utility/mock"
+)
+
+func TestParseFromYamlMock(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				example_tool:
+					kind: mock
+					description: some description
+			`,
+			want: server.ToolConfigs{
+				"example_tool": mock.Config{
+					Name:         "example_tool",
+					Kind:         "mock",
+					Description:  "some description",
+					AuthRequired: []string{},
+				},
+			},
+		},
+		{
+			desc: "with responses",
+			in: `
+			tools:
+				example_tool:
+					kind: mock
+					description: some description
+					parameters:
+						- name: city
+						  type: string
+						  description: the city
+					responses:
+						- match:
+							  city: Paris
+						  result:
+							  temperature: 21
+						  latency: 200ms
+						- match:
+							  city: "*"
+						  error: unknown city
+						  statusCode: 404
+					default:
+						result: sunny
+					authRequired:
+						- my-google-auth-service
+			`,
+			want: server.ToolConfigs{
+				"example_tool": mock.Config{
+					Name:        "example_tool",
+					Kind:        "mock",
+					Description: "some description",
+					Parameters: tools.Parameters{
+						tools.NewStringParameter("city", "the city"),
+					},
+					Responses: []mock.Response{
+						{
+							Match:   map[string]any{"city": "Paris"},
+							Result:  map[string]any{"temperature": uint64(21)},
+							Latency: "200ms",
+						},
+						{
+							Match:      map[string]any{"city": "*"},
+							Error:      "unknown city",
+							StatusCode: 404,
+						},
+					},
+					Default:      &mock.Response{Result: "sunny"},
+					AuthRequired: []string{"my-google-auth-service"},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestInitializeMockErrors(t *testing.T) {
+	params := tools.Parameters{tools.NewStringParameter("city", "the city")}
+	tcs := []struct {
+		desc    string
+		cfg     mock.Config
+		wantErr string
+	}{
+		{
+			desc:    "undeclared parameter",
+			cfg:     mock.Config{Name: "t", Responses: []mock.Response{{Match: map[string]any{"country": "France"}}}},
+			wantErr: `matches on "country", which is not a declared parameter`,
+		},
+		{
+			desc:    "result and error",
+			cfg:     mock.Config{Name: "t", Parameters: params, Responses: []mock.Response{{Result: "ok", Error: "failed"}}},
+			wantErr: "only one of result or error can be set",
+		},
+		{
+			desc:    "status code without error",
+			cfg:     mock.Config{Name: "t", Responses: []mock.Response{{Result: "ok", StatusCode: 500}}},
+			wantErr: "statusCode can only be set along with error",
+		},
+		{
+			desc:    "invalid status code",
+			cfg:     mock.Config{Name: "t", Responses: []mock.Response{{Error: "failed", StatusCode: 200}}},
+			wantErr: "statusCode must be between 400 and 599",
+		},
+		{
+			desc:    "invalid latency",
+			cfg:     mock.Config{Name: "t", Default: &mock.Response{Latency: "slow"}},
+			wantErr: `invalid default response of mock tool "t": invalid latency "slow"`,
+		},
+		{
+			desc:    "default with match",
+			cfg:     mock.Config{Name: "t", Parameters: params, Default: &mock.Response{Match: map[string]any{"city": "*"}}},
+			wantErr: "cannot have a match",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := tc.cfg.Initialize(nil)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("got error %v, want one containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+// newTool initializes the mock tool described by in.
+func newTool(t *testing.T, in string) tools.Tool {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	tool, err := got.Tools["example_tool"].Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	return tool
+}
+
+func invoke(ctx context.Context, t *testing.T, tool tools.Tool, data map[string]any) (any, error) {
+	params, err := tool.ParseParams(data, nil)
+	if err != nil {
+		t.Fatalf("unable to parse params: %s", err)
+	}
+	return tool.Invoke(ctx, tools.Invocation{Params: params})
+}
+
+func TestMockMatcherPrecedence(t *testing.T) {
+	tool := newTool(t, `
+	tools:
+		example_tool:
+			kind: mock
+			description: some description
+			parameters:
+				- name: city
+				  type: string
+				  description: the city
+				- name: days
+				  type: integer
+				  description: the number of days
+				  default: 1
+				- name: unit
+				  type: string
+				  description: the unit
+				  required: false
+			responses:
+				- match:
+					  city: "*"
+				  result: any city
+				- match:
+					  city: Paris
+				  result: Paris
+				- match:
+					  city: Paris
+					  days: 3
+				  result: Paris for 3 days
+				- match:
+					  city: Paris
+					  days: "*"
+				  result: Paris for some days
+				- match:
+					  city: Rome
+				  result: first Rome
+				- match:
+					  city: Rome
+				  result: second Rome
+				- match:
+					  city: London
+					  unit: "*"
+				  result: London with a unit
+			default:
+				result: default
+	`)
+	tcs := []struct {
+		desc string
+		data map[string]any
+		want any
+	}{
+		{desc: "wildcard only", data: map[string]any{"city": "Oslo"}, want: "any city"},
+		{desc: "more exact values win", data: map[string]any{"city": "Paris", "days": 3}, want: "Paris for 3 days"},
+		{desc: "wildcards break ties", data: map[string]any{"city": "Paris", "days": 2}, want: "Paris for some days"},
+		{desc: "first of equally specific", data: map[string]any{"city": "Rome"}, want: "first Rome"},
+		{desc: "wildcard requires a value", data: map[string]any{"city": "London"}, want: "any city"},
+		{desc: "wildcard with a value", data: map[string]any{"city": "London", "unit": "celsius"}, want: "London with a unit"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := invoke(context.Background(), t, tool, tc.data)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected result: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestMockDefault(t *testing.T) {
+	in := `
+	tools:
+		example_tool:
+			kind: mock
+			description: some description
+			parameters:
+				- name: city
+				  type: string
+				  description: the city
+			responses:
+				- match:
+					  city: Paris
+				  result:
+					  temperature: 21
+	`
+	tool := newTool(t, in)
+	got, err := invoke(context.Background(), t, tool, map[string]any{"city": "Paris"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(map[string]any{"temperature": uint64(21)}, got); diff != "" {
+		t.Fatalf("unexpected result: diff %v", diff)
+	}
+
+	// without a default, unmatched invocations fail
+	_, err = invoke(context.Background(), t, tool, map[string]any{"city": "Oslo"})
+	if err == nil || !strings.Contains(err.Error(), `no response of mock tool "example_tool" matches the parameters`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tool = newTool(t, in+`
+			default:
+				result:
+					- unknown
+	`)
+	got, err = invoke(context.Background(), t, tool, map[string]any{"city": "Oslo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff([]any{"unknown"}, got); diff != "" {
+		t.Fatalf("unexpected result: diff %v", diff)
+	}
+}
+
+func TestMockLatencyAndErrors(t *testing.T) {
+	tool := newTool(t, `
+	tools:
+		example_tool:
+			kind: mock
+			description: some description
+			parameters:
+				- name: city
+				  type: string
+				  description: the city
+			responses:
+				- match:
+					  city: Paris
+				  result: slow
+				  latency: 100ms
+				- match:
+					  city: Atlantis
+				  error: city not found
+				  statusCode: 404
+				- match:
+					  city: Oslo
+				  error: bad city
+	`)
+
+	start := time.Now()
+	got, err := invoke(context.Background(), t, tool, map[string]any{"city": "Paris"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got != "slow" {
+		t.Fatalf("unexpected result: %v", got)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Fatalf("got a response after %s, want at least 100ms", elapsed)
+	}
+
+	// the latency is cut short by the cancellation of the invocation
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := invoke(ctx, t, tool, map[string]any{"city": "Paris"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	_, err = invoke(context.Background(), t, tool, map[string]any{"city": "Atlantis"})
+	var statusErr *tools.StatusError
+	if !errors.As(err, &statusErr) || statusErr.Code != 404 || err.Error() != "city not found" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = invoke(context.Background(), t, tool, map[string]any{"city": "Oslo"})
+	if err == nil || err.Error() != "bad city" || errors.As(err, &statusErr) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessionhistory
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+const kind string = "session-history"
+
+// defaultLimit is the number of entries listed when `limit` is not set.
+const defaultLimit = 20
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type Config struct {
+	Name         string   `yaml:"name" validate:"required"`
+	Kind         string   `yaml:"kind" validate:"required"`
+	Description  string   `yaml:"description" validate:"required"`
+	AuthRequired []string `yaml:"authRequired"`
+}
+
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(_ map[string]sources.Source) (tools.Tool, error) {
+	parameters := tools.Parameters{
+		tools.NewIntParameterWithRequired("limit", fmt.Sprintf("The maximum number of entries to list, newest first. Defaults to %d.", defaultLimit), false),
+		tools.NewIntParameterWithRequired("rerun", "The id of an entry to run again instead of listing the entries.", false),
+		tools.NewMapParameterWithRequired("overrides", "Arguments replacing those of the entry to run again.", false, ""),
+	}
+
+	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, parameters)
+
+	t := Tool{
+		Name:         cfg.Name,
+		Kind:         kind,
+		Parameters:   parameters,
+		AuthRequired: cfg.AuthRequired,
+		manifest:     tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string
+	Kind         string
+	Parameters   tools.Parameters
+	AuthRequired []string
+	manifest     tools.Manifest
+	mcpManifest  tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	h := tools.SessionHistoryFromContext(ctx)
+	if h == nil {
+		return nil, fmt.Errorf("session history is not available: the invocation has no session, or the server does not run with --session-history-size")
+	}
+	paramsMap := inv.Params.AsMap()
+
+	if id, ok := paramsMap["rerun"].(int); ok {
+		overrides, _ := paramsMap["overrides"].(map[string]any)
+		return rerun(ctx, h, id, overrides, inv)
+	}
+
+	limit := defaultLimit
+	if l, ok := paramsMap["limit"].(int); ok {
+		limit = l
+	}
+	if limit <= 0 {
+		return nil, fmt.Errorf("limit must be positive, got %d", limit)
+	}
+	entries := h.Entries(limit)
+	res := make([]any, len(entries))
+	for i, e := range entries {
+		res[i] = e
+	}
+	return res, nil
+}
+
+// rerun invokes the tool of the entry id again, with its arguments replaced
+// by overrides, and records the invocation as a new entry.
+func rerun(ctx context.Context, h *tools.SessionHistory, id int, overrides map[string]any, inv tools.Invocation) (any, error) {
+	entry, ok := h.Entry(id)
+	if !ok {
+		return nil, fmt.Errorf("no entry %d in the session history", id)
+	}
+	tool, ok := h.Tool(entry.Tool)
+	if !ok {
+		return nil, fmt.Errorf("tool %q of entry %d no longer exists", entry.Tool, id)
+	}
+	if !tool.Authorized(util.VerifiedAuthServicesFromContext(ctx)) {
+		return nil, fmt.Errorf("unauthorized to run tool %q: %w", entry.Tool, tools.ErrUnauthorized)
+	}
+	if tool.RequiresClientAuthorization() && inv.AccessToken == "" {
+		return nil, fmt.Errorf("tool %q requires an access token in the 'Authorization' header: %w", entry.Tool, tools.ErrUnauthorized)
+	}
+
+	data := maps.Clone(entry.Params)
+	maps.Copy(data, overrides)
+	for name := range entry.Params {
+		if _, ok := overrides[name]; !ok && entry.Sensitive(name) {
+			return nil, fmt.Errorf("parameter %q is sensitive, so its value is not recorded and must be passed in 'overrides'", name)
+		}
+	}
+	claims := inv.Claims
+	if claims == nil {
+		claims = map[string]map[string]any{}
+	}
+	rerunParams, err := tool.ParseParams(data, claims)
+	if err != nil {
+		return nil, fmt.Errorf("invalid arguments to run entry %d: %w", id, err)
+	}
+	res, err := tool.Invoke(ctx, inv.WithParams(rerunParams))
+	if err != nil {
+		return nil, err
+	}
+	if mr, ok := res.(tools.MeteredResult); ok {
+		res = mr.Result
+	}
+	if it, ok := res.(tools.RowIterator); ok {
+		if res, err = tools.CollectRows(it); err != nil {
+			return nil, err
+		}
+	}
+	h.Record(entry.Tool, tool, rerunParams, tools.RowCount(res))
+	return res, nil
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return false
+}
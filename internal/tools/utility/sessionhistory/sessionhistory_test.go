@@ -0,0 +1,196 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessionhistory_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+
+	"github.com/googleapis/genai-toolbox/internal/tools/utility/sessionhistory"
+)
+
+func TestParseFromYamlSessionHistory(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		history:
+			kind: session-history
+			description: some description
+			authRequired:
+				- my-google-auth-service
+	`
+	want := server.ToolConfigs{
+		"history": sessionhistory.Config{
+			Name:         "history",
+			Kind:         "session-history",
+			Description:  "some description",
+			AuthRequired: []string{"my-google-auth-service"},
+		},
+	}
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	if diff := cmp.Diff(want, got.Tools); diff != "" {
+		t.Fatalf("incorrect parse: diff %v", diff)
+	}
+}
+
+// ordersTool runs a statement, and returns its arguments.
+type ordersTool struct {
+	tools.Tool
+	params tools.Parameters
+}
+
+func (t ordersTool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.params, data, claims)
+}
+
+func (t ordersTool) Invoke(_ context.Context, inv tools.Invocation) (any, error) {
+	return []any{inv.Params.AsMap()}, nil
+}
+
+func (t ordersTool) Render(params tools.ParamValues) (tools.RenderedStatement, error) {
+	return tools.NewRenderedStatement("SELECT * FROM orders WHERE status = $1 LIMIT $2", params, params), nil
+}
+
+func (t ordersTool) Authorized([]string) bool {
+	return true
+}
+
+func (t ordersTool) RequiresClientAuthorization() bool {
+	return false
+}
+
+func TestInvoke(t *testing.T) {
+	apiKey := tools.NewStringParameter("api_key", "the API key")
+	apiKey.Sensitive = true
+	orders := ordersTool{params: tools.Parameters{
+		tools.NewStringParameter("status", "the status of the orders"),
+		tools.NewIntParameter("limit", "the maximum number of orders"),
+	}}
+	keyed := ordersTool{params: tools.Parameters{
+		tools.NewStringParameter("status", "the status of the orders"),
+		tools.NewIntParameter("limit", "the maximum number of orders"),
+		apiKey,
+	}}
+	lookup := func(name string) (tools.Tool, bool) {
+		switch name {
+		case "list_orders":
+			return orders, true
+		case "list_keyed_orders":
+			return keyed, true
+		}
+		return nil, false
+	}
+
+	tool, err := sessionhistory.Config{Name: "history", Kind: "session-history", Description: "some description"}.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	invoke := func(ctx context.Context, data map[string]any) (any, error) {
+		params, err := tool.ParseParams(data, nil)
+		if err != nil {
+			t.Fatalf("unable to parse params: %s", err)
+		}
+		return tool.Invoke(ctx, tools.Invocation{Params: params})
+	}
+
+	t.Run("without session", func(t *testing.T) {
+		_, err := invoke(context.Background(), map[string]any{})
+		if err == nil || !strings.Contains(err.Error(), "session history is not available") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	h := tools.NewSessionHistory(10, lookup)
+	ctx := tools.WithSessionHistory(context.Background(), h)
+	params, err := orders.ParseParams(map[string]any{"status": "open", "limit": 10}, nil)
+	if err != nil {
+		t.Fatalf("unable to parse params: %s", err)
+	}
+	h.Record("list_orders", orders, params, 1)
+
+	t.Run("list", func(t *testing.T) {
+		res, err := invoke(ctx, map[string]any{})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		entries, ok := res.([]any)
+		if !ok || len(entries) != 1 {
+			t.Fatalf("unexpected result: %v", res)
+		}
+		entry := entries[0].(tools.HistoryEntry)
+		if entry.ID != 1 || entry.Tool != "list_orders" {
+			t.Fatalf("unexpected entry: %+v", entry)
+		}
+	})
+
+	t.Run("rerun with overrides", func(t *testing.T) {
+		res, err := invoke(ctx, map[string]any{"rerun": 1, "overrides": map[string]any{"status": "shipped"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		want := []any{map[string]any{"status": "shipped", "limit": 10}}
+		if diff := cmp.Diff(want, res); diff != "" {
+			t.Fatalf("unexpected result (-want +got):\n%s", diff)
+		}
+		// the rerun is recorded as a new entry
+		entry, ok := h.Entry(2)
+		if !ok || entry.Params["status"] != "shipped" {
+			t.Fatalf("unexpected entry 2: %+v", entry)
+		}
+	})
+
+	t.Run("unknown entry", func(t *testing.T) {
+		_, err := invoke(ctx, map[string]any{"rerun": 42})
+		if err == nil || err.Error() != "no entry 42 in the session history" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("sensitive parameters must be overridden", func(t *testing.T) {
+		params, err := keyed.ParseParams(map[string]any{"status": "open", "limit": 10, "api_key": "s3cret"}, nil)
+		if err != nil {
+			t.Fatalf("unable to parse params: %s", err)
+		}
+		h.Record("list_keyed_orders", keyed, params, 1)
+		id := h.Entries(1)[0].ID
+		_, err = invoke(ctx, map[string]any{"rerun": id})
+		if err == nil || !strings.Contains(err.Error(), `parameter "api_key" is sensitive`) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		res, err := invoke(ctx, map[string]any{"rerun": id, "overrides": map[string]any{"api_key": "s3cret"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		want := []any{map[string]any{"status": "open", "limit": 10, "api_key": "s3cret"}}
+		if diff := cmp.Diff(want, res); diff != "" {
+			t.Fatalf("unexpected result (-want +got):\n%s", diff)
+		}
+	})
+}
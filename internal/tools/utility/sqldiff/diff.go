@@ -0,0 +1,187 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqldiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// Rows is the result of a statement.
+type Rows struct {
+	// Columns are the names of the columns, in the order of the statement.
+	Columns []string
+	Rows    []map[string]any
+}
+
+// Result is the difference between the results of statements A and B.
+type Result struct {
+	Summary Summary `json:"summary"`
+	// OnlyInA are the rows of A whose key is not in B, in the order of A.
+	OnlyInA []map[string]any `json:"onlyInA"`
+	// OnlyInB are the rows of B whose key is not in A, in the order of B.
+	OnlyInB []map[string]any `json:"onlyInB"`
+	// Changed are the rows whose key is in both, but whose values differ, in
+	// the order of A.
+	Changed []ChangedRow `json:"changed"`
+}
+
+// Summary counts the rows of a Result. The counts are exact even if the rows
+// listed are truncated.
+type Summary struct {
+	RowsA     int `json:"rowsA"`
+	RowsB     int `json:"rowsB"`
+	OnlyInA   int `json:"onlyInA"`
+	OnlyInB   int `json:"onlyInB"`
+	Changed   int `json:"changed"`
+	Unchanged int `json:"unchanged"`
+	// Truncated is whether some of the differing rows are not listed.
+	Truncated bool `json:"truncated"`
+	// ColumnsOnlyInA and ColumnsOnlyInB are the columns returned by a single
+	// statement, which are not compared.
+	ColumnsOnlyInA []string `json:"columnsOnlyInA,omitempty"`
+	ColumnsOnlyInB []string `json:"columnsOnlyInB,omitempty"`
+}
+
+// ChangedRow is a row whose values differ between A and B.
+type ChangedRow struct {
+	// Key holds the values of the key columns.
+	Key map[string]any `json:"key"`
+	// Columns are the names of the columns whose values differ.
+	Columns []string `json:"columns"`
+	// A and B hold the values of the differing columns.
+	A map[string]any `json:"a"`
+	B map[string]any `json:"b"`
+}
+
+// Diff joins the rows of a and b on the key columns, and returns the rows
+// only in a, only in b, and the rows whose values differ. At most maxDiffs
+// rows are listed in each category. Values are compared by their JSON
+// encoding, so that the same value read as different Go types, such as int32
+// and int64, is equal.
+func Diff(a, b Rows, keys []string, maxDiffs int) (Result, error) {
+	for _, k := range keys {
+		if !slices.Contains(a.Columns, k) {
+			return Result{}, fmt.Errorf("key column %q is not returned by statement A", k)
+		}
+		if !slices.Contains(b.Columns, k) {
+			return Result{}, fmt.Errorf("key column %q is not returned by statement B", k)
+		}
+	}
+	keysA, indexA, err := keyRows(a.Rows, keys, "A")
+	if err != nil {
+		return Result{}, err
+	}
+	keysB, indexB, err := keyRows(b.Rows, keys, "B")
+	if err != nil {
+		return Result{}, err
+	}
+
+	var common []string
+	res := Result{
+		Summary: Summary{RowsA: len(a.Rows), RowsB: len(b.Rows)},
+		OnlyInA: []map[string]any{},
+		OnlyInB: []map[string]any{},
+		Changed: []ChangedRow{},
+	}
+	for _, c := range a.Columns {
+		if !slices.Contains(b.Columns, c) {
+			res.Summary.ColumnsOnlyInA = append(res.Summary.ColumnsOnlyInA, c)
+		} else if !slices.Contains(keys, c) {
+			common = append(common, c)
+		}
+	}
+	for _, c := range b.Columns {
+		if !slices.Contains(a.Columns, c) {
+			res.Summary.ColumnsOnlyInB = append(res.Summary.ColumnsOnlyInB, c)
+		}
+	}
+
+	for i, rowA := range a.Rows {
+		j, ok := indexB[keysA[i]]
+		if !ok {
+			res.Summary.OnlyInA++
+			if len(res.OnlyInA) < maxDiffs {
+				res.OnlyInA = append(res.OnlyInA, rowA)
+			}
+			continue
+		}
+		rowB := b.Rows[j]
+		changed := ChangedRow{A: map[string]any{}, B: map[string]any{}}
+		for _, c := range common {
+			if !equal(rowA[c], rowB[c]) {
+				changed.Columns = append(changed.Columns, c)
+				changed.A[c] = rowA[c]
+				changed.B[c] = rowB[c]
+			}
+		}
+		if len(changed.Columns) == 0 {
+			res.Summary.Unchanged++
+			continue
+		}
+		res.Summary.Changed++
+		if len(res.Changed) < maxDiffs {
+			changed.Key = make(map[string]any, len(keys))
+			for _, k := range keys {
+				changed.Key[k] = rowA[k]
+			}
+			res.Changed = append(res.Changed, changed)
+		}
+	}
+	for i, rowB := range b.Rows {
+		if _, ok := indexA[keysB[i]]; !ok {
+			res.Summary.OnlyInB++
+			if len(res.OnlyInB) < maxDiffs {
+				res.OnlyInB = append(res.OnlyInB, rowB)
+			}
+		}
+	}
+	res.Summary.Truncated = res.Summary.OnlyInA > len(res.OnlyInA) || res.Summary.OnlyInB > len(res.OnlyInB) || res.Summary.Changed > len(res.Changed)
+	return res, nil
+}
+
+// keyRows returns the encoded key of each row, and the index of the row of
+// each key. Keys must be unique, or the rows could not be matched.
+func keyRows(rows []map[string]any, keys []string, statement string) ([]string, map[string]int, error) {
+	encoded := make([]string, len(rows))
+	index := make(map[string]int, len(rows))
+	for i, row := range rows {
+		values := make([]any, len(keys))
+		for j, k := range keys {
+			values[j] = row[k]
+		}
+		b, err := json.Marshal(values)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to encode key of row %d of statement %s: %w", i, statement, err)
+		}
+		encoded[i] = string(b)
+		if _, ok := index[encoded[i]]; ok {
+			return nil, nil, fmt.Errorf("key %s is not unique in the rows of statement %s", encoded[i], statement)
+		}
+		index[encoded[i]] = i
+	}
+	return encoded, index, nil
+}
+
+// equal returns whether a and b have the same JSON encoding.
+func equal(a, b any) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return string(encodedA) == string(encodedB)
+}
@@ -0,0 +1,172 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqldiff_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools/utility/sqldiff"
+)
+
+func TestDiff(t *testing.T) {
+	a := sqldiff.Rows{
+		Columns: []string{"id", "region", "total", "legacy"},
+		Rows: []map[string]any{
+			{"id": int32(1), "region": "eu", "total": 10.5, "legacy": true},
+			{"id": int32(2), "region": "eu", "total": 20.0, "legacy": true},
+			{"id": int32(3), "region": "us", "total": nil, "legacy": false},
+			{"id": int32(4), "region": "us", "total": 40.0, "legacy": false},
+		},
+	}
+	b := sqldiff.Rows{
+		Columns: []string{"id", "region", "total", "migrated_at"},
+		Rows: []map[string]any{
+			{"id": int64(5), "region": "us", "total": 50.0, "migrated_at": "2025-01-01"},
+			{"id": int64(4), "region": "us", "total": 40.0, "migrated_at": "2025-01-01"},
+			{"id": int64(3), "region": "eu", "total": 0.0, "migrated_at": nil},
+			{"id": int64(1), "region": "eu", "total": 10.5, "migrated_at": nil},
+		},
+	}
+	got, err := sqldiff.Diff(a, b, []string{"id"}, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := sqldiff.Result{
+		Summary: sqldiff.Summary{
+			RowsA:          4,
+			RowsB:          4,
+			OnlyInA:        1,
+			OnlyInB:        1,
+			Changed:        1,
+			Unchanged:      2,
+			ColumnsOnlyInA: []string{"legacy"},
+			ColumnsOnlyInB: []string{"migrated_at"},
+		},
+		OnlyInA: []map[string]any{a.Rows[1]},
+		OnlyInB: []map[string]any{b.Rows[0]},
+		Changed: []sqldiff.ChangedRow{{
+			Key:     map[string]any{"id": int32(3)},
+			Columns: []string{"region", "total"},
+			A:       map[string]any{"region": "us", "total": nil},
+			B:       map[string]any{"region": "eu", "total": 0.0},
+		}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("incorrect diff: diff %v", diff)
+	}
+}
+
+func TestDiffCompositeKey(t *testing.T) {
+	a := sqldiff.Rows{
+		Columns: []string{"tenant", "id", "name"},
+		Rows: []map[string]any{
+			{"tenant": "t1", "id": 1, "name": "a"},
+			{"tenant": "t2", "id": 1, "name": "b"},
+		},
+	}
+	b := sqldiff.Rows{
+		Columns: []string{"tenant", "id", "name"},
+		Rows: []map[string]any{
+			{"tenant": "t2", "id": 1, "name": "b"},
+			{"tenant": "t1", "id": 1, "name": "c"},
+		},
+	}
+	got, err := sqldiff.Diff(a, b, []string{"tenant", "id"}, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []sqldiff.ChangedRow{{
+		Key:     map[string]any{"tenant": "t1", "id": 1},
+		Columns: []string{"name"},
+		A:       map[string]any{"name": "a"},
+		B:       map[string]any{"name": "c"},
+	}}
+	if diff := cmp.Diff(want, got.Changed); diff != "" {
+		t.Fatalf("incorrect changed rows: diff %v", diff)
+	}
+	if got.Summary.Unchanged != 1 || got.Summary.OnlyInA != 0 || got.Summary.OnlyInB != 0 {
+		t.Fatalf("unexpected summary: %+v", got.Summary)
+	}
+}
+
+func TestDiffTruncated(t *testing.T) {
+	a := sqldiff.Rows{Columns: []string{"id", "v"}}
+	b := sqldiff.Rows{Columns: []string{"id", "v"}}
+	for i := 0; i < 1000; i++ {
+		a.Rows = append(a.Rows, map[string]any{"id": i, "v": "a"})
+		b.Rows = append(b.Rows, map[string]any{"id": i + 500, "v": "b"})
+	}
+	got, err := sqldiff.Diff(a, b, []string{"id"}, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	wantSummary := sqldiff.Summary{RowsA: 1000, RowsB: 1000, OnlyInA: 500, OnlyInB: 500, Changed: 500, Truncated: true}
+	if diff := cmp.Diff(wantSummary, got.Summary); diff != "" {
+		t.Fatalf("incorrect summary: diff %v", diff)
+	}
+	if len(got.OnlyInA) != 3 || len(got.OnlyInB) != 3 || len(got.Changed) != 3 {
+		t.Fatalf("expected 3 rows of each category, got %d, %d and %d", len(got.OnlyInA), len(got.OnlyInB), len(got.Changed))
+	}
+	if got.OnlyInA[0]["id"] != 0 || got.OnlyInB[0]["id"] != 1000 || got.Changed[0].Key["id"] != 500 {
+		t.Fatalf("expected the first rows of each category, got %v", got)
+	}
+}
+
+func TestDiffEmpty(t *testing.T) {
+	rows := sqldiff.Rows{Columns: []string{"id"}}
+	got, err := sqldiff.Diff(rows, rows, []string{"id"}, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := sqldiff.Result{
+		OnlyInA: []map[string]any{},
+		OnlyInB: []map[string]any{},
+		Changed: []sqldiff.ChangedRow{},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("incorrect diff: diff %v", diff)
+	}
+}
+
+func TestDiffErrors(t *testing.T) {
+	rows := sqldiff.Rows{
+		Columns: []string{"id", "v"},
+		Rows:    []map[string]any{{"id": 1, "v": "a"}},
+	}
+	duplicated := sqldiff.Rows{
+		Columns: []string{"id", "v"},
+		Rows:    []map[string]any{{"id": 1, "v": "a"}, {"id": 1, "v": "b"}},
+	}
+	tcs := []struct {
+		desc string
+		a, b sqldiff.Rows
+		keys []string
+		want string
+	}{
+		{desc: "missing key in A", a: sqldiff.Rows{Columns: []string{"v"}}, b: rows, keys: []string{"id"}, want: `key column "id" is not returned by statement A`},
+		{desc: "missing key in B", a: rows, b: sqldiff.Rows{Columns: []string{"v"}}, keys: []string{"id"}, want: `key column "id" is not returned by statement B`},
+		{desc: "duplicated key", a: rows, b: duplicated, keys: []string{"id"}, want: "key [1] is not unique in the rows of statement B"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := sqldiff.Diff(tc.a, tc.b, tc.keys, 10)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,349 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqldiff
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/alloydbpg"
+	"github.com/googleapis/genai-toolbox/internal/sources/cloudsqlmysql"
+	"github.com/googleapis/genai-toolbox/internal/sources/cloudsqlpg"
+	"github.com/googleapis/genai-toolbox/internal/sources/mindsdb"
+	"github.com/googleapis/genai-toolbox/internal/sources/mysql"
+	"github.com/googleapis/genai-toolbox/internal/sources/postgres"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/mysql/mysqlcommon"
+	"github.com/googleapis/genai-toolbox/internal/tools/postgres/postgrescommon"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const kind string = "sql-diff"
+
+const (
+	// defaultMaxRows bounds the rows read from each statement.
+	defaultMaxRows = 10000
+	// defaultMaxDiffs bounds the rows listed in each category of the diff.
+	defaultMaxDiffs = 100
+)
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type postgresSource interface {
+	PostgresPool() *pgxpool.Pool
+}
+
+type mysqlSource interface {
+	MySQLPool() *sql.DB
+}
+
+// validate compatible sources are still compatible
+var _ postgresSource = &alloydbpg.Source{}
+var _ postgresSource = &cloudsqlpg.Source{}
+var _ postgresSource = &postgres.Source{}
+var _ mysqlSource = &cloudsqlmysql.Source{}
+var _ mysqlSource = &mysql.Source{}
+var _ mysqlSource = &mindsdb.Source{}
+
+var compatibleSources = [...]string{alloydbpg.SourceKind, cloudsqlpg.SourceKind, postgres.SourceKind, cloudsqlmysql.SourceKind, mysql.SourceKind, mindsdb.SourceKind}
+
+type Config struct {
+	Name        string `yaml:"name" validate:"required"`
+	Kind        string `yaml:"kind" validate:"required"`
+	Source      string `yaml:"source" validate:"required"`
+	Description string `yaml:"description" validate:"required"`
+	// StatementA and StatementB are the statements compared. If they are not
+	// set, they are taken as the `statementA` and `statementB` parameters.
+	StatementA string `yaml:"statementA"`
+	StatementB string `yaml:"statementB"`
+	// KeyColumns are the columns the rows are matched on. If not set, they are
+	// taken as the `keyColumns` parameter.
+	KeyColumns []string `yaml:"keyColumns"`
+	// MaxRows bounds the rows read from each statement. Statements returning
+	// more rows fail.
+	MaxRows int `yaml:"maxRows"`
+	// MaxDiffs bounds the rows listed in each category of the diff.
+	MaxDiffs     int              `yaml:"maxDiffs"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	var query queryFunc
+	switch s := rawS.(type) {
+	case postgresSource:
+		query = postgresQuery(s.PostgresPool())
+	case mysqlSource:
+		query = mysqlQuery(s.MySQLPool())
+	default:
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	if (cfg.StatementA == "") != (cfg.StatementB == "") {
+		return nil, fmt.Errorf("invalid %q tool: statementA and statementB must be both set, or both unset to take them as parameters", kind)
+	}
+	if cfg.StatementA == "" && len(cfg.Parameters) > 0 {
+		return nil, fmt.Errorf("invalid %q tool: parameters can only be set with statementA and statementB", kind)
+	}
+	if cfg.MaxRows < 0 || cfg.MaxDiffs < 0 {
+		return nil, fmt.Errorf("invalid %q tool: maxRows and maxDiffs must not be negative", kind)
+	}
+	maxRows := cfg.MaxRows
+	if maxRows == 0 {
+		maxRows = defaultMaxRows
+	}
+	maxDiffs := cfg.MaxDiffs
+	if maxDiffs == 0 {
+		maxDiffs = defaultMaxDiffs
+	}
+
+	allParameters := cfg.Parameters
+	if cfg.StatementA == "" {
+		allParameters = tools.Parameters{
+			tools.NewStringParameter("statementA", "The first SQL statement to run, such as the original query."),
+			tools.NewStringParameter("statementB", "The second SQL statement to run, such as the migrated query."),
+		}
+	}
+	if len(cfg.KeyColumns) == 0 {
+		allParameters = append(allParameters, tools.NewArrayParameter("keyColumns", "The columns that identify a row in the results of both statements.", tools.NewStringParameter("column", "The name of a key column.")))
+	}
+	paramManifest := allParameters.Manifest()
+	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, allParameters)
+
+	// finish tool setup
+	t := Tool{
+		Name:         cfg.Name,
+		Kind:         kind,
+		Parameters:   cfg.Parameters,
+		AllParams:    allParameters,
+		StatementA:   cfg.StatementA,
+		StatementB:   cfg.StatementB,
+		KeyColumns:   cfg.KeyColumns,
+		MaxRows:      maxRows,
+		MaxDiffs:     maxDiffs,
+		AuthRequired: cfg.AuthRequired,
+		query:        query,
+		manifest:     tools.Manifest{Description: cfg.Description, Parameters: paramManifest, AuthRequired: cfg.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string           `yaml:"name"`
+	Kind         string           `yaml:"kind"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+	AllParams    tools.Parameters `yaml:"allParams"`
+
+	StatementA string
+	StatementB string
+	KeyColumns []string
+	MaxRows    int
+	MaxDiffs   int
+
+	query       queryFunc
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
+	statementA, statementB := t.StatementA, t.StatementB
+	var args []any
+	if statementA == "" {
+		var okA, okB bool
+		statementA, okA = paramsMap["statementA"].(string)
+		statementB, okB = paramsMap["statementB"].(string)
+		if !okA || !okB {
+			return nil, fmt.Errorf("unable to cast statementA or statementB")
+		}
+	} else {
+		newParams, err := tools.GetParams(t.Parameters, paramsMap)
+		if err != nil {
+			return nil, fmt.Errorf("unable to extract standard params %w", err)
+		}
+		args = newParams.AsSlice()
+	}
+
+	keys := t.KeyColumns
+	if len(keys) == 0 {
+		raw, ok := paramsMap["keyColumns"].([]any)
+		if !ok || len(raw) == 0 {
+			return nil, fmt.Errorf("keyColumns must list at least one column")
+		}
+		for _, k := range raw {
+			s, ok := k.(string)
+			if !ok {
+				return nil, fmt.Errorf("unable to cast key column %v", k)
+			}
+			keys = append(keys, s)
+		}
+	}
+
+	rowsA, err := t.run(ctx, "A", statementA, args)
+	if err != nil {
+		return nil, inv.Params.RedactError(err)
+	}
+	rowsB, err := t.run(ctx, "B", statementB, args)
+	if err != nil {
+		return nil, inv.Params.RedactError(err)
+	}
+	return Diff(rowsA, rowsB, keys, t.MaxDiffs)
+}
+
+// run runs statement, which fails if it returns more than MaxRows rows.
+func (t Tool) run(ctx context.Context, name, statement string, args []any) (Rows, error) {
+	rows, err := t.query(ctx, statement, args, t.MaxRows+1)
+	if err != nil {
+		return Rows{}, fmt.Errorf("unable to execute statement %s: %w", name, err)
+	}
+	if len(rows.Rows) > t.MaxRows {
+		return Rows{}, fmt.Errorf("statement %s returned more than %d rows, the maxRows of the tool: narrow the statements to compare fewer rows", name, t.MaxRows)
+	}
+	return rows, nil
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.AllParams, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return false
+}
+
+// queryFunc runs statement with args, and returns at most limit rows.
+type queryFunc func(ctx context.Context, statement string, args []any, limit int) (Rows, error)
+
+func postgresQuery(pool *pgxpool.Pool) queryFunc {
+	return func(ctx context.Context, statement string, args []any, limit int) (Rows, error) {
+		results, err := pool.Query(ctx, statement, args...)
+		if err != nil {
+			return Rows{}, err
+		}
+		defer results.Close()
+
+		fields := results.FieldDescriptions()
+		out := Rows{Columns: make([]string, len(fields))}
+		for i, f := range fields {
+			out.Columns[i] = f.Name
+		}
+		for len(out.Rows) < limit && results.Next() {
+			v, err := results.Values()
+			if err != nil {
+				return Rows{}, fmt.Errorf("unable to parse row: %w", err)
+			}
+			out.Rows = append(out.Rows, postgrescommon.RowToMap(ctx, fields, v))
+		}
+		// the rest of the rows are discarded when the results are closed
+		if err := results.Err(); err != nil {
+			return Rows{}, err
+		}
+		return out, nil
+	}
+}
+
+func mysqlQuery(pool *sql.DB) queryFunc {
+	return func(ctx context.Context, statement string, args []any, limit int) (Rows, error) {
+		results, err := pool.QueryContext(ctx, statement, args...)
+		if err != nil {
+			return Rows{}, err
+		}
+		defer results.Close()
+
+		cols, err := results.Columns()
+		if err != nil {
+			return Rows{}, fmt.Errorf("unable to retrieve rows column name: %w", err)
+		}
+		colTypes, err := results.ColumnTypes()
+		if err != nil {
+			return Rows{}, fmt.Errorf("unable to get column types: %w", err)
+		}
+
+		// create an array of values for each column, which can be re-used to scan each row
+		rawValues := make([]any, len(cols))
+		values := make([]any, len(cols))
+		for i := range rawValues {
+			values[i] = &rawValues[i]
+		}
+
+		out := Rows{Columns: cols}
+		for len(out.Rows) < limit && results.Next() {
+			if err := results.Scan(values...); err != nil {
+				return Rows{}, fmt.Errorf("unable to parse row: %w", err)
+			}
+			vMap := make(map[string]any, len(cols))
+			for i, name := range cols {
+				if rawValues[i] == nil {
+					vMap[name] = nil
+					continue
+				}
+				vMap[name], err = mysqlcommon.ConvertToType(ctx, colTypes[i], rawValues[i])
+				if err != nil {
+					return Rows{}, fmt.Errorf("errors encountered when converting values: %w", err)
+				}
+			}
+			out.Rows = append(out.Rows, vMap)
+		}
+		if err := results.Err(); err != nil {
+			return Rows{}, fmt.Errorf("errors encountered during row iteration: %w", err)
+		}
+		return out, nil
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqldiff_test
+
+import (
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/http"
+	"github.com/googleapis/genai-toolbox/internal/sources/postgres"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/utility/sqldiff"
+)
+
+func TestParseFromYamlSqlDiff(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "statements",
+			in: `
+			tools:
+				example_tool:
+					kind: sql-diff
+					source: my-pg-instance
+					description: some description
+					statementA: SELECT id, total FROM orders WHERE region = $1
+					statementB: SELECT id, total FROM orders_v2 WHERE region = $1
+					keyColumns:
+						- id
+					maxRows: 500
+					maxDiffs: 20
+					parameters:
+						- name: region
+						  type: string
+						  description: the region of the orders
+			`,
+			want: server.ToolConfigs{
+				"example_tool": sqldiff.Config{
+					Name:         "example_tool",
+					Kind:         "sql-diff",
+					Source:       "my-pg-instance",
+					Description:  "some description",
+					StatementA:   "SELECT id, total FROM orders WHERE region = $1",
+					StatementB:   "SELECT id, total FROM orders_v2 WHERE region = $1",
+					KeyColumns:   []string{"id"},
+					MaxRows:      500,
+					MaxDiffs:     20,
+					AuthRequired: []string{},
+					Parameters: []tools.Parameter{
+						tools.NewStringParameter("region", "the region of the orders"),
+					},
+				},
+			},
+		},
+		{
+			desc: "statements as parameters",
+			in: `
+			tools:
+				example_tool:
+					kind: sql-diff
+					source: my-pg-instance
+					description: some description
+			`,
+			want: server.ToolConfigs{
+				"example_tool": sqldiff.Config{
+					Name:         "example_tool",
+					Kind:         "sql-diff",
+					Source:       "my-pg-instance",
+					Description:  "some description",
+					AuthRequired: []string{},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestInitialize(t *testing.T) {
+	srcs := map[string]sources.Source{
+		"my-pg-instance":   &postgres.Source{},
+		"my-http-instance": &http.Source{},
+	}
+	tcs := []struct {
+		desc       string
+		cfg        sqldiff.Config
+		wantParams []string
+		wantErr    string
+	}{
+		{
+			desc:       "statements",
+			cfg:        sqldiff.Config{Source: "my-pg-instance", StatementA: "SELECT 1", StatementB: "SELECT 2", KeyColumns: []string{"id"}},
+			wantParams: []string{},
+		},
+		{
+			desc:       "statements as parameters",
+			cfg:        sqldiff.Config{Source: "my-pg-instance"},
+			wantParams: []string{"statementA", "statementB", "keyColumns"},
+		},
+		{
+			desc:       "key columns as parameter",
+			cfg:        sqldiff.Config{Source: "my-pg-instance", StatementA: "SELECT 1", StatementB: "SELECT 2"},
+			wantParams: []string{"keyColumns"},
+		},
+		{
+			desc:    "single statement",
+			cfg:     sqldiff.Config{Source: "my-pg-instance", StatementA: "SELECT 1"},
+			wantErr: "statementA and statementB must be both set",
+		},
+		{
+			desc:    "parameters without statements",
+			cfg:     sqldiff.Config{Source: "my-pg-instance", Parameters: tools.Parameters{tools.NewStringParameter("id", "an id")}},
+			wantErr: "parameters can only be set with statementA and statementB",
+		},
+		{
+			desc:    "negative max rows",
+			cfg:     sqldiff.Config{Source: "my-pg-instance", MaxRows: -1},
+			wantErr: "must not be negative",
+		},
+		{
+			desc:    "incompatible source",
+			cfg:     sqldiff.Config{Source: "my-http-instance"},
+			wantErr: "source kind must be one of",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			tc.cfg.Name, tc.cfg.Kind, tc.cfg.Description = "diff", "sql-diff", "some description"
+			tool, err := tc.cfg.Initialize(srcs)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			got := []string{}
+			for _, p := range tool.Manifest().Parameters {
+				got = append(got, p.Name)
+			}
+			if diff := cmp.Diff(tc.wantParams, got); diff != "" {
+				t.Fatalf("unexpected parameters: diff %v", diff)
+			}
+		})
+	}
+}
@@ -110,9 +110,9 @@ type Tool struct {
 	mcpManifest tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
 	// Replace parameters
-	commands, err := replaceCommandsParams(t.Commands, t.Parameters, params)
+	commands, err := replaceCommandsParams(t.Commands, t.Parameters, inv.Params)
 	if err != nil {
 		return nil, fmt.Errorf("error replacing commands' parameters: %s", err)
 	}
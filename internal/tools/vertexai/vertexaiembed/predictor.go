@@ -0,0 +1,152 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vertexaiembed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"golang.org/x/oauth2/google"
+)
+
+const cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+// Predictor generates the embeddings of a batch of texts.
+type Predictor interface {
+	Predict(ctx context.Context, texts []string) (*Prediction, error)
+}
+
+// Prediction is the result of embedding a batch of texts.
+type Prediction struct {
+	// Embeddings are the embeddings of the texts, in the same order.
+	Embeddings [][]float64
+	// TokenCount is the number of tokens of the texts billed by the model.
+	TokenCount int
+	// ModelVersion is the version of the model that served the request, if
+	// reported.
+	ModelVersion string
+}
+
+// PredictError is returned by a Predictor when the prediction endpoint
+// responds with an error status.
+type PredictError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *PredictError) Error() string {
+	return fmt.Sprintf("prediction failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+// restPredictor calls the predict method of the Vertex AI REST API. The
+// client is created on first use with Application Default Credentials.
+type restPredictor struct {
+	url string
+
+	once      sync.Once
+	client    *http.Client
+	clientErr error
+}
+
+func newRESTPredictor(project, location, model string) *restPredictor {
+	host := "aiplatform.googleapis.com"
+	if location != "global" {
+		host = location + "-" + host
+	}
+	return &restPredictor{
+		url: fmt.Sprintf("https://%s/v1/projects/%s/locations/%s/publishers/google/models/%s:predict", host, project, location, model),
+	}
+}
+
+type predictRequest struct {
+	Instances []predictInstance `json:"instances"`
+}
+
+type predictInstance struct {
+	Content string `json:"content"`
+}
+
+type predictResponse struct {
+	Predictions []struct {
+		Embeddings struct {
+			Values     []float64 `json:"values"`
+			Statistics struct {
+				TokenCount int `json:"token_count"`
+			} `json:"statistics"`
+		} `json:"embeddings"`
+	} `json:"predictions"`
+	ModelVersionID string `json:"modelVersionId"`
+}
+
+func (p *restPredictor) Predict(ctx context.Context, texts []string) (*Prediction, error) {
+	p.once.Do(func() {
+		// the token source outlives the invocation, so it must not use its context
+		p.client, p.clientErr = google.DefaultClient(context.Background(), cloudPlatformScope)
+	})
+	if p.clientErr != nil {
+		return nil, fmt.Errorf("failed to find default credentials: %w", p.clientErr)
+	}
+
+	reqBody := predictRequest{Instances: make([]predictInstance, len(texts))}
+	for i, text := range texts {
+		reqBody.Instances[i] = predictInstance{Content: text}
+	}
+	b, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if ua, err := util.UserAgentFromContext(ctx); err == nil {
+		req.Header.Set("User-Agent", ua)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &PredictError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	var result predictResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal json: %w, body: %s", err, string(body))
+	}
+	prediction := &Prediction{
+		Embeddings:   make([][]float64, len(result.Predictions)),
+		ModelVersion: result.ModelVersionID,
+	}
+	for i, pred := range result.Predictions {
+		prediction.Embeddings[i] = pred.Embeddings.Values
+		prediction.TokenCount += pred.Embeddings.Statistics.TokenCount
+	}
+	return prediction, nil
+}
@@ -0,0 +1,244 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vertexaiembed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+const kind string = "vertexai-embed"
+
+const (
+	defaultMaxTexts       = 250
+	defaultMaxTextLength  = 8192
+	defaultBatchSize      = 50
+	defaultMaxRetries     = 3
+	defaultInitialBackoff = time.Second
+)
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type Config struct {
+	Name          string   `yaml:"name" validate:"required"`
+	Kind          string   `yaml:"kind" validate:"required"`
+	Description   string   `yaml:"description" validate:"required"`
+	Project       string   `yaml:"project" validate:"required"`
+	Location      string   `yaml:"location" validate:"required"`
+	Model         string   `yaml:"model" validate:"required"`
+	MaxTexts      int      `yaml:"maxTexts"`
+	MaxTextLength int      `yaml:"maxTextLength"`
+	BatchSize     int      `yaml:"batchSize"`
+	MaxRetries    *int     `yaml:"maxRetries"`
+	AuthRequired  []string `yaml:"authRequired"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(_ map[string]sources.Source) (tools.Tool, error) {
+	maxTexts, err := positiveOrDefault("maxTexts", cfg.MaxTexts, defaultMaxTexts)
+	if err != nil {
+		return nil, err
+	}
+	maxTextLength, err := positiveOrDefault("maxTextLength", cfg.MaxTextLength, defaultMaxTextLength)
+	if err != nil {
+		return nil, err
+	}
+	batchSize, err := positiveOrDefault("batchSize", cfg.BatchSize, defaultBatchSize)
+	if err != nil {
+		return nil, err
+	}
+	maxRetries := defaultMaxRetries
+	if cfg.MaxRetries != nil {
+		if *cfg.MaxRetries < 0 {
+			return nil, fmt.Errorf("maxRetries must not be negative, got %d", *cfg.MaxRetries)
+		}
+		maxRetries = *cfg.MaxRetries
+	}
+
+	minItems := 1
+	textsParameter := tools.NewArrayParameterWithRange(
+		"texts",
+		fmt.Sprintf("The texts to generate embeddings for, at most %d texts of at most %d characters each.", maxTexts, maxTextLength),
+		&minItems,
+		&maxTexts,
+		tools.NewStringParameter("text", "A text to generate an embedding for."),
+	)
+	parameters := tools.Parameters{textsParameter}
+
+	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, parameters)
+
+	t := Tool{
+		Name:           cfg.Name,
+		Kind:           kind,
+		Model:          cfg.Model,
+		Parameters:     parameters,
+		AuthRequired:   cfg.AuthRequired,
+		MaxTextLength:  maxTextLength,
+		BatchSize:      batchSize,
+		MaxRetries:     maxRetries,
+		InitialBackoff: defaultInitialBackoff,
+		Predictor:      newRESTPredictor(cfg.Project, cfg.Location, cfg.Model),
+		manifest:       tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:    mcpManifest,
+	}
+	return t, nil
+}
+
+func positiveOrDefault(field string, v, defaultV int) (int, error) {
+	if v < 0 {
+		return 0, fmt.Errorf("%s must not be negative, got %d", field, v)
+	}
+	if v == 0 {
+		return defaultV, nil
+	}
+	return v, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name          string           `yaml:"name"`
+	Kind          string           `yaml:"kind"`
+	Model         string           `yaml:"model"`
+	Parameters    tools.Parameters `yaml:"parameters"`
+	AuthRequired  []string         `yaml:"authRequired"`
+	MaxTextLength int
+	BatchSize     int
+	MaxRetries    int
+	// InitialBackoff is the wait before the first retry of a rate limited
+	// batch. It doubles with every retry.
+	InitialBackoff time.Duration
+	Predictor      Predictor
+	manifest       tools.Manifest
+	mcpManifest    tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, inv tools.Invocation) (any, error) {
+	paramsMap := inv.Params.AsMap()
+	rawTexts, ok := paramsMap["texts"].([]any)
+	if !ok {
+		return nil, fmt.Errorf("texts parameter not found or not an array")
+	}
+	texts := make([]string, len(rawTexts))
+	for i, raw := range rawTexts {
+		text, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("text %d is not a string", i)
+		}
+		if text == "" {
+			return nil, fmt.Errorf("text %d is empty", i)
+		}
+		if n := utf8.RuneCountInString(text); n > t.MaxTextLength {
+			return nil, fmt.Errorf("text %d is %d characters long, which exceeds the maximum of %d", i, n, t.MaxTextLength)
+		}
+		texts[i] = text
+	}
+
+	embeddings := make([][]float64, 0, len(texts))
+	tokenCount := 0
+	modelVersion := t.Model
+	for start := 0; start < len(texts); start += t.BatchSize {
+		end := min(start+t.BatchSize, len(texts))
+		p, err := t.predict(ctx, texts[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("unable to embed texts %d to %d: %w", start, end-1, err)
+		}
+		if len(p.Embeddings) != end-start {
+			return nil, fmt.Errorf("expected %d embeddings, got %d", end-start, len(p.Embeddings))
+		}
+		embeddings = append(embeddings, p.Embeddings...)
+		tokenCount += p.TokenCount
+		if p.ModelVersion != "" {
+			modelVersion = p.ModelVersion
+		}
+	}
+
+	if logger, err := util.LoggerFromContext(ctx); err == nil {
+		logger.DebugContext(ctx, fmt.Sprintf("tool %q embedded %d texts using %d tokens", t.Name, len(texts), tokenCount))
+	}
+
+	return map[string]any{
+		"embeddings":   embeddings,
+		"modelVersion": modelVersion,
+		"tokenCount":   tokenCount,
+	}, nil
+}
+
+// predict embeds a single batch, retrying with exponential backoff while the
+// model is rate limited.
+func (t Tool) predict(ctx context.Context, texts []string) (*Prediction, error) {
+	backoff := t.InitialBackoff
+	for attempt := 0; ; attempt++ {
+		p, err := t.Predictor.Predict(ctx, texts)
+		var predictErr *PredictError
+		if err == nil || attempt >= t.MaxRetries || !errors.As(err, &predictErr) || predictErr.StatusCode != http.StatusTooManyRequests {
+			return p, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return false
+}
@@ -0,0 +1,297 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vertexaiembed_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/vertexai/vertexaiembed"
+)
+
+func TestParseFromYamlVertexAIEmbed(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	maxRetries := 0
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				example_tool:
+					kind: vertexai-embed
+					description: some description
+					project: my-project
+					location: us-central1
+					model: text-embedding-005
+			`,
+			want: server.ToolConfigs{
+				"example_tool": vertexaiembed.Config{
+					Name:         "example_tool",
+					Kind:         "vertexai-embed",
+					Description:  "some description",
+					Project:      "my-project",
+					Location:     "us-central1",
+					Model:        "text-embedding-005",
+					AuthRequired: []string{},
+				},
+			},
+		},
+		{
+			desc: "with limits",
+			in: `
+			tools:
+				example_tool:
+					kind: vertexai-embed
+					description: some description
+					project: my-project
+					location: us-central1
+					model: text-embedding-005
+					maxTexts: 10
+					maxTextLength: 100
+					batchSize: 5
+					maxRetries: 0
+					authRequired:
+						- my-google-auth-service
+			`,
+			want: server.ToolConfigs{
+				"example_tool": vertexaiembed.Config{
+					Name:          "example_tool",
+					Kind:          "vertexai-embed",
+					Description:   "some description",
+					Project:       "my-project",
+					Location:      "us-central1",
+					Model:         "text-embedding-005",
+					MaxTexts:      10,
+					MaxTextLength: 100,
+					BatchSize:     5,
+					MaxRetries:    &maxRetries,
+					AuthRequired:  []string{"my-google-auth-service"},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestFailParseFromYamlVertexAIEmbed(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		example_tool:
+			kind: vertexai-embed
+			description: some description
+			project: my-project
+			location: us-central1
+	`
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	err = yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got)
+	if err == nil || !strings.Contains(err.Error(), "Model") {
+		t.Fatalf("expected a missing model error, got %v", err)
+	}
+}
+
+// fakePredictor embeds each text as its length, and fails with a rate limit
+// error for the first rateLimited calls.
+type fakePredictor struct {
+	rateLimited int
+	batches     [][]string
+}
+
+func (p *fakePredictor) Predict(_ context.Context, texts []string) (*vertexaiembed.Prediction, error) {
+	if p.rateLimited > 0 {
+		p.rateLimited--
+		return nil, &vertexaiembed.PredictError{StatusCode: http.StatusTooManyRequests, Body: "quota exceeded"}
+	}
+	p.batches = append(p.batches, texts)
+	prediction := &vertexaiembed.Prediction{ModelVersion: "003"}
+	for _, text := range texts {
+		prediction.Embeddings = append(prediction.Embeddings, []float64{float64(len(text)), 1})
+		prediction.TokenCount += len(strings.Fields(text))
+	}
+	return prediction, nil
+}
+
+func newTestTool(t *testing.T, cfg vertexaiembed.Config, predictor vertexaiembed.Predictor) vertexaiembed.Tool {
+	t.Helper()
+	cfg.Name = "embed"
+	cfg.Kind = "vertexai-embed"
+	cfg.Project = "my-project"
+	cfg.Location = "us-central1"
+	cfg.Model = "text-embedding-005"
+	tool, err := cfg.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	embedTool := tool.(vertexaiembed.Tool)
+	embedTool.InitialBackoff = time.Millisecond
+	embedTool.Predictor = predictor
+	return embedTool
+}
+
+func TestInvoke(t *testing.T) {
+	tcs := []struct {
+		desc        string
+		cfg         vertexaiembed.Config
+		texts       []any
+		rateLimited int
+		want        map[string]any
+		wantBatches int
+		err         string
+	}{
+		{
+			desc:        "batches texts",
+			cfg:         vertexaiembed.Config{BatchSize: 2},
+			texts:       []any{"a", "bb", "ccc dd", "e"},
+			wantBatches: 2,
+			want: map[string]any{
+				"embeddings":   [][]float64{{1, 1}, {2, 1}, {6, 1}, {1, 1}},
+				"modelVersion": "003",
+				"tokenCount":   5,
+			},
+		},
+		{
+			desc:        "retries rate limited batches",
+			texts:       []any{"a"},
+			rateLimited: 2,
+			wantBatches: 1,
+			want: map[string]any{
+				"embeddings":   [][]float64{{1, 1}},
+				"modelVersion": "003",
+				"tokenCount":   1,
+			},
+		},
+		{
+			desc:        "gives up after max retries",
+			texts:       []any{"a"},
+			rateLimited: 4,
+			err:         "status 429",
+		},
+		{
+			desc:  "text too long",
+			cfg:   vertexaiembed.Config{MaxTextLength: 3},
+			texts: []any{"abc", "abcd"},
+			err:   "text 1 is 4 characters long, which exceeds the maximum of 3",
+		},
+		{
+			desc:  "too many texts",
+			cfg:   vertexaiembed.Config{MaxTexts: 1},
+			texts: []any{"a", "b"},
+			err:   "texts",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			predictor := &fakePredictor{rateLimited: tc.rateLimited}
+			tool := newTestTool(t, tc.cfg, predictor)
+			params, err := tool.ParseParams(map[string]any{"texts": tc.texts}, nil)
+			if err == nil {
+				var got any
+				got, err = tool.Invoke(context.Background(), tools.Invocation{Params: params})
+				if err == nil {
+					if diff := cmp.Diff(tc.want, got); diff != "" {
+						t.Fatalf("unexpected result: diff %v", diff)
+					}
+				}
+			}
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("unexpected error: got %v, want substring %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if len(predictor.batches) != tc.wantBatches {
+				t.Fatalf("unexpected number of batches: got %d, want %d", len(predictor.batches), tc.wantBatches)
+			}
+		})
+	}
+}
+
+// TestInvokeLive calls the Vertex AI API with Application Default Credentials
+// and is skipped unless VERTEXAI_PROJECT is set.
+func TestInvokeLive(t *testing.T) {
+	project := os.Getenv("VERTEXAI_PROJECT")
+	if project == "" {
+		t.Skip("'VERTEXAI_PROJECT' not set")
+	}
+	location := os.Getenv("VERTEXAI_LOCATION")
+	if location == "" {
+		location = "us-central1"
+	}
+	cfg := vertexaiembed.Config{
+		Name:     "embed",
+		Kind:     "vertexai-embed",
+		Project:  project,
+		Location: location,
+		Model:    "text-embedding-005",
+	}
+	tool, err := cfg.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	params, err := tool.ParseParams(map[string]any{"texts": []any{"hello world", "vector search"}}, nil)
+	if err != nil {
+		t.Fatalf("unable to parse params: %s", err)
+	}
+	got, err := tool.Invoke(context.Background(), tools.Invocation{Params: params})
+	if err != nil {
+		t.Fatalf("unable to invoke tool: %s", err)
+	}
+	res := got.(map[string]any)
+	embeddings := res["embeddings"].([][]float64)
+	if len(embeddings) != 2 || len(embeddings[0]) == 0 {
+		t.Fatalf("unexpected embeddings: %v", embeddings)
+	}
+	if res["tokenCount"].(int) <= 0 {
+		t.Fatalf("unexpected token count: %v", res["tokenCount"])
+	}
+}
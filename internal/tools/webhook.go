@@ -0,0 +1,320 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/telemetry"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+const (
+	// DefaultWebhookTimeout is how long a pre-invoke webhook is waited for
+	// when its timeout is not set.
+	DefaultWebhookTimeout = 5 * time.Second
+	// MaxWebhookTimeout bounds the latency a pre-invoke webhook adds to the
+	// invocations of a tool.
+	MaxWebhookTimeout = 30 * time.Second
+
+	// WebhookSignatureHeader holds the HMAC-SHA256 signature of a webhook
+	// request, as "sha256=<hex>". It signs the timestamp and the body of the
+	// request joined by a dot.
+	WebhookSignatureHeader = "X-Toolbox-Signature"
+	// WebhookTimestampHeader holds the time a webhook request was sent, in
+	// seconds since the Unix epoch.
+	WebhookTimestampHeader = "X-Toolbox-Timestamp"
+
+	webhookDecisionAllow = "allow"
+	// maxWebhookResponseSize bounds the response read from a webhook.
+	maxWebhookResponseSize = 64 << 10
+)
+
+// PreInvokeWebhookSpec is the `preInvokeWebhook` field of a tool.
+type PreInvokeWebhookSpec struct {
+	// URL is the endpoint the invocations of the tool are POSTed to.
+	URL string `yaml:"url" validate:"required"`
+	// Secret signs the requests with HMAC-SHA256.
+	Secret string `yaml:"secret" validate:"required"`
+	// Timeout is how long the webhook is waited for, such as "2s". It
+	// defaults to DefaultWebhookTimeout and is at most MaxWebhookTimeout.
+	Timeout string `yaml:"timeout"`
+	// FailOpen lets invocations run when the webhook cannot be reached or
+	// times out. By default, they are denied.
+	FailOpen bool `yaml:"failOpen"`
+}
+
+// PreInvokeWebhook asks an external service for approval before each
+// invocation of a tool.
+type PreInvokeWebhook struct {
+	url      string
+	secret   []byte
+	timeout  time.Duration
+	failOpen bool
+	client   *http.Client
+	// now returns the current time. It defaults to time.Now.
+	now func() time.Time
+}
+
+// NewPreInvokeWebhook validates spec and returns its PreInvokeWebhook.
+func NewPreInvokeWebhook(spec PreInvokeWebhookSpec) (*PreInvokeWebhook, error) {
+	u, err := url.Parse(spec.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("url must be an absolute http or https URL, got %q", spec.URL)
+	}
+	if spec.Secret == "" {
+		return nil, fmt.Errorf("secret must not be empty")
+	}
+	timeout := DefaultWebhookTimeout
+	if spec.Timeout != "" {
+		timeout, err = time.ParseDuration(spec.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout: %w", err)
+		}
+		if timeout <= 0 || timeout > MaxWebhookTimeout {
+			return nil, fmt.Errorf("timeout must be positive and at most %s, got %s", MaxWebhookTimeout, timeout)
+		}
+	}
+	return &PreInvokeWebhook{
+		url:      spec.URL,
+		secret:   []byte(spec.Secret),
+		timeout:  timeout,
+		failOpen: spec.FailOpen,
+		client:   &http.Client{},
+	}, nil
+}
+
+// NewPreInvokeWebhookWithClock returns a copy of w that reads the time from
+// now, for tests.
+func NewPreInvokeWebhookWithClock(w *PreInvokeWebhook, now func() time.Time) *PreInvokeWebhook {
+	c := *w
+	c.now = now
+	return &c
+}
+
+// WebhookRequest is the body POSTed to a pre-invoke webhook.
+type WebhookRequest struct {
+	Tool string `json:"tool"`
+	// Arguments are the parameters of the invocation, with the values of
+	// sensitive parameters redacted.
+	Arguments map[string]any `json:"arguments"`
+	Caller    WebhookCaller  `json:"caller"`
+}
+
+// WebhookCaller identifies the caller of an invocation to a pre-invoke
+// webhook.
+type WebhookCaller struct {
+	// AuthServices are the names of the authServices that verified the
+	// caller.
+	AuthServices []string `json:"authServices"`
+	// Subjects maps the authServices that verified the caller to the `sub`
+	// claim of the caller.
+	Subjects map[string]string `json:"subjects,omitempty"`
+	// ClientIdentity is the identity of the caller forwarded to backends,
+	// if client attribution is enabled.
+	ClientIdentity string `json:"clientIdentity,omitempty"`
+}
+
+// WebhookResponse is the body a pre-invoke webhook answers with. Invocations
+// run only if Decision is "allow".
+type WebhookResponse struct {
+	Decision string `json:"decision"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// PolicyDeniedError is returned by the invocations of a tool that its
+// pre-invoke webhook did not allow.
+type PolicyDeniedError struct {
+	Tool   string
+	Reason string
+}
+
+func (e *PolicyDeniedError) Error() string {
+	return "denied by policy: " + e.Reason
+}
+
+// Sign returns the value of the WebhookSignatureHeader of a request with
+// timestamp and body.
+func (w *PreInvokeWebhook) Sign(timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, w.secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Approve asks the webhook whether the invocation of tool with params, by the
+// caller in ctx, may run. It returns a PolicyDeniedError if not.
+func (w *PreInvokeWebhook) Approve(ctx context.Context, tool string, params ParamValues) (err error) {
+	ctx, span := otel.Tracer(telemetry.TracerName).Start(ctx, "toolbox/server/tool/webhook")
+	span.SetAttributes(attribute.String("tool_name", tool))
+	start := time.Now()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	decision, err := w.call(ctx, tool, params)
+	span.SetAttributes(attribute.Int64("webhook_latency_ms", time.Since(start).Milliseconds()))
+	if err != nil {
+		var denied *PolicyDeniedError
+		if errors.As(err, &denied) {
+			span.SetAttributes(attribute.String("webhook_decision", "deny"))
+			return err
+		}
+		// the webhook could not be reached or answered too late
+		if logger, lErr := util.LoggerFromContext(ctx); lErr == nil {
+			logger.WarnContext(ctx, fmt.Sprintf("pre-invoke webhook of tool %q failed (failOpen: %t): %s", tool, w.failOpen, err))
+		}
+		if w.failOpen {
+			span.SetAttributes(attribute.String("webhook_decision", "failOpen"))
+			return nil
+		}
+		span.SetAttributes(attribute.String("webhook_decision", "failClosed"))
+		return &PolicyDeniedError{Tool: tool, Reason: fmt.Sprintf("approval webhook unavailable: %s", err)}
+	}
+	span.SetAttributes(attribute.String("webhook_decision", decision))
+	return nil
+}
+
+// call POSTs the invocation to the webhook. It returns a PolicyDeniedError if
+// the webhook answered with anything but an allow decision, or another error
+// if it could not be reached.
+func (w *PreInvokeWebhook) call(ctx context.Context, tool string, params ParamValues) (string, error) {
+	body, err := json.Marshal(WebhookRequest{
+		Tool:      tool,
+		Arguments: params.Redacted().AsMap(),
+		Caller:    webhookCaller(ctx),
+	})
+	if err != nil {
+		return "", fmt.Errorf("unable to marshal webhook request: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	if w.now != nil {
+		now = w.now()
+	}
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, w.Sign(timestamp, body))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxWebhookResponseSize))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", &PolicyDeniedError{Tool: tool, Reason: fmt.Sprintf("approval webhook returned status %d", resp.StatusCode)}
+	}
+	var decision WebhookResponse
+	if err := json.Unmarshal(b, &decision); err != nil {
+		return "", &PolicyDeniedError{Tool: tool, Reason: "approval webhook returned an invalid response"}
+	}
+	if decision.Decision != webhookDecisionAllow {
+		reason := decision.Reason
+		if reason == "" {
+			reason = "no reason given"
+		}
+		return "", &PolicyDeniedError{Tool: tool, Reason: reason}
+	}
+	return decision.Decision, nil
+}
+
+// webhookCaller returns the identity of the caller of the invocation in ctx.
+func webhookCaller(ctx context.Context) WebhookCaller {
+	caller := WebhookCaller{
+		AuthServices:   util.VerifiedAuthServicesFromContext(ctx),
+		ClientIdentity: util.ClientIdentityFromContext(ctx),
+	}
+	if caller.AuthServices == nil {
+		caller.AuthServices = []string{}
+	}
+	sort.Strings(caller.AuthServices)
+	for name, claims := range util.AuthClaimsFromContext(ctx) {
+		if sub, ok := claims["sub"].(string); ok {
+			if caller.Subjects == nil {
+				caller.Subjects = make(map[string]string)
+			}
+			caller.Subjects[name] = sub
+		}
+	}
+	return caller
+}
+
+// PreInvokeWebhookConfig wraps a ToolConfig with the kind-agnostic
+// `preInvokeWebhook` field.
+type PreInvokeWebhookConfig struct {
+	ToolConfig
+	Webhook *PreInvokeWebhook
+}
+
+// validate interface
+var _ ToolConfig = PreInvokeWebhookConfig{}
+
+func (cfg PreInvokeWebhookConfig) Initialize(srcs map[string]sources.Source) (Tool, error) {
+	t, err := cfg.ToolConfig.Initialize(srcs)
+	if err != nil {
+		return nil, err
+	}
+	return PreInvokeWebhookTool{Tool: t, Webhook: cfg.Webhook}, nil
+}
+
+// PreInvokeWebhookTool runs the invocations of a Tool that its
+// PreInvokeWebhook approves.
+type PreInvokeWebhookTool struct {
+	Tool
+	Webhook *PreInvokeWebhook
+}
+
+func (t PreInvokeWebhookTool) unwrap() Tool {
+	return t.Tool
+}
+
+func (t PreInvokeWebhookTool) Invoke(ctx context.Context, inv Invocation) (any, error) {
+	if err := t.Webhook.Approve(ctx, t.McpManifest().Name, inv.Params); err != nil {
+		return nil, err
+	}
+	return t.Tool.Invoke(ctx, inv)
+}
@@ -0,0 +1,261 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+const webhookSecret = "s3cr3t"
+
+// approvalServer verifies the signature of the requests it receives with
+// webhookSecret, records them, and answers with respond.
+type approvalServer struct {
+	*httptest.Server
+	requests   []tools.WebhookRequest
+	timestamps []string
+}
+
+func newApprovalServer(t *testing.T, respond func(w http.ResponseWriter, req tools.WebhookRequest)) *approvalServer {
+	s := &approvalServer{}
+	verifier, err := tools.NewPreInvokeWebhook(tools.PreInvokeWebhookSpec{URL: "http://verifier", Secret: webhookSecret})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		want := verifier.Sign(r.Header.Get(tools.WebhookTimestampHeader), body)
+		if !hmac.Equal([]byte(r.Header.Get(tools.WebhookSignatureHeader)), []byte(want)) {
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+		var req tools.WebhookRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.requests = append(s.requests, req)
+		s.timestamps = append(s.timestamps, r.Header.Get(tools.WebhookTimestampHeader))
+		respond(w, req)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func mustWebhook(t *testing.T, spec tools.PreInvokeWebhookSpec) *tools.PreInvokeWebhook {
+	w, err := tools.NewPreInvokeWebhook(spec)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return w
+}
+
+// countingTool counts its invocations.
+type countingTool struct {
+	mockTool
+	invocations *int
+}
+
+func (t countingTool) Invoke(context.Context, tools.Invocation) (any, error) {
+	*t.invocations++
+	return "done", nil
+}
+
+func decide(decision, reason string) func(http.ResponseWriter, tools.WebhookRequest) {
+	return func(w http.ResponseWriter, _ tools.WebhookRequest) {
+		_ = json.NewEncoder(w).Encode(tools.WebhookResponse{Decision: decision, Reason: reason})
+	}
+}
+
+func TestPreInvokeWebhookTool(t *testing.T) {
+	slow := func(w http.ResponseWriter, _ tools.WebhookRequest) {
+		time.Sleep(200 * time.Millisecond)
+		_ = json.NewEncoder(w).Encode(tools.WebhookResponse{Decision: "allow"})
+	}
+	tcs := []struct {
+		desc       string
+		respond    func(http.ResponseWriter, tools.WebhookRequest)
+		secret     string
+		failOpen   bool
+		wantReason string
+	}{
+		{
+			desc:    "allow",
+			respond: decide("allow", ""),
+		},
+		{
+			desc:       "deny with reason",
+			respond:    decide("deny", "DDL requires a change ticket"),
+			wantReason: "DDL requires a change ticket",
+		},
+		{
+			desc:       "deny without reason",
+			respond:    decide("deny", ""),
+			wantReason: "no reason given",
+		},
+		{
+			desc: "allow with another status",
+			respond: func(w http.ResponseWriter, _ tools.WebhookRequest) {
+				w.WriteHeader(http.StatusAccepted)
+				_ = json.NewEncoder(w).Encode(tools.WebhookResponse{Decision: "allow"})
+			},
+			wantReason: "approval webhook returned status 202",
+		},
+		{
+			desc:       "invalid response",
+			respond:    func(w http.ResponseWriter, _ tools.WebhookRequest) { _, _ = w.Write([]byte("allow")) },
+			wantReason: "approval webhook returned an invalid response",
+		},
+		{
+			desc:       "invalid signature",
+			respond:    decide("allow", ""),
+			secret:     "wrong",
+			wantReason: "approval webhook returned status 401",
+		},
+		{
+			desc:       "timeout fail closed",
+			respond:    slow,
+			wantReason: "approval webhook unavailable",
+		},
+		{
+			desc:     "timeout fail open",
+			respond:  slow,
+			failOpen: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			srv := newApprovalServer(t, tc.respond)
+			secret := webhookSecret
+			if tc.secret != "" {
+				secret = tc.secret
+			}
+			webhook := mustWebhook(t, tools.PreInvokeWebhookSpec{URL: srv.URL, Secret: secret, Timeout: "50ms", FailOpen: tc.failOpen})
+
+			var invocations int
+			tool := tools.PreInvokeWebhookTool{Tool: countingTool{mockTool: mockTool{name: "drop_table"}, invocations: &invocations}, Webhook: webhook}
+			got, err := tool.Invoke(context.Background(), tools.Invocation{})
+			if tc.wantReason == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				if got != "done" || invocations != 1 {
+					t.Fatalf("expected the tool to run, got %v after %d invocations", got, invocations)
+				}
+				return
+			}
+			var denied *tools.PolicyDeniedError
+			if !errors.As(err, &denied) {
+				t.Fatalf("expected a PolicyDeniedError, got %v", err)
+			}
+			if !strings.HasPrefix(err.Error(), "denied by policy: "+tc.wantReason) || denied.Tool != "drop_table" {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if invocations != 0 {
+				t.Fatalf("expected the tool not to run, got %d invocations", invocations)
+			}
+		})
+	}
+}
+
+func TestPreInvokeWebhookRequest(t *testing.T) {
+	srv := newApprovalServer(t, decide("allow", ""))
+	at := time.Date(2025, 10, 15, 9, 0, 0, 0, time.UTC)
+	webhook := tools.NewPreInvokeWebhookWithClock(mustWebhook(t, tools.PreInvokeWebhookSpec{URL: srv.URL, Secret: webhookSecret}), func() time.Time { return at })
+
+	ctx := util.WithVerifiedAuthServices(context.Background(), []string{"okta", "google"})
+	ctx = util.WithAuthClaims(ctx, map[string]map[string]any{"google": {"sub": "1234", "email": "jane@example.com"}})
+	params := tools.ParamValues{
+		{Name: "table", Value: "flights"},
+		{Name: "password", Value: "hunter2", Sensitive: true},
+	}
+	if err := webhook.Approve(ctx, "drop_table", params); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []tools.WebhookRequest{{
+		Tool:      "drop_table",
+		Arguments: map[string]any{"table": "flights", "password": tools.Redacted},
+		Caller: tools.WebhookCaller{
+			AuthServices: []string{"google", "okta"},
+			Subjects:     map[string]string{"google": "1234"},
+		},
+	}}
+	if diff := cmp.Diff(want, srv.requests); diff != "" {
+		t.Fatalf("unexpected webhook requests: diff %v", diff)
+	}
+	if diff := cmp.Diff([]string{"1760518800"}, srv.timestamps); diff != "" {
+		t.Fatalf("unexpected webhook timestamps: diff %v", diff)
+	}
+
+	// the signature covers the timestamp, so that requests cannot be replayed
+	body := []byte(`{"tool":"drop_table"}`)
+	if webhook.Sign("1760518800", body) == webhook.Sign("1760518801", body) {
+		t.Fatalf("expected signatures of different timestamps to differ")
+	}
+	other := mustWebhook(t, tools.PreInvokeWebhookSpec{URL: srv.URL, Secret: "other"})
+	if webhook.Sign("1760518800", body) == other.Sign("1760518800", body) {
+		t.Fatalf("expected signatures of different secrets to differ")
+	}
+}
+
+func TestFailNewPreInvokeWebhook(t *testing.T) {
+	tcs := []struct {
+		desc string
+		spec tools.PreInvokeWebhookSpec
+		want string
+	}{
+		{
+			desc: "relative url",
+			spec: tools.PreInvokeWebhookSpec{URL: "/approve", Secret: webhookSecret},
+			want: `url must be an absolute http or https URL, got "/approve"`,
+		},
+		{
+			desc: "missing secret",
+			spec: tools.PreInvokeWebhookSpec{URL: "https://approvals.example.com"},
+			want: "secret must not be empty",
+		},
+		{
+			desc: "invalid timeout",
+			spec: tools.PreInvokeWebhookSpec{URL: "https://approvals.example.com", Secret: webhookSecret, Timeout: "soon"},
+			want: "invalid timeout",
+		},
+		{
+			desc: "timeout too long",
+			spec: tools.PreInvokeWebhookSpec{URL: "https://approvals.example.com", Secret: webhookSecret, Timeout: "1m"},
+			want: "timeout must be positive and at most 30s, got 1m0s",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := tools.NewPreInvokeWebhook(tc.spec)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// PayloadLimits bounds the JSON payloads of the invocations of tools, so that
+// pathological arguments are rejected before they are decoded. A zero limit
+// is not enforced.
+type PayloadLimits struct {
+	// MaxDepth is the maximum nesting depth of objects and arrays. The
+	// top-level object of the arguments is at depth 1.
+	MaxDepth int
+	// MaxKeys is the maximum total number of keys of the objects.
+	MaxKeys int
+	// MaxStringLength is the maximum length in bytes of a string, key or
+	// value.
+	MaxStringLength int
+}
+
+// PayloadLimitError is returned when a payload exceeds one of its
+// PayloadLimits.
+type PayloadLimitError struct {
+	// Limit names the limit exceeded, such as "nesting depth".
+	Limit string
+	Max   int
+}
+
+func (e *PayloadLimitError) Error() string {
+	return fmt.Sprintf("payload exceeds the maximum %s of %d", e.Limit, e.Max)
+}
+
+// frame is an object or array being walked by PayloadLimits.Check.
+type frame struct {
+	object bool
+	// key is set when the next token of an object is a key.
+	key bool
+}
+
+// Check walks the tokens of the JSON payload data, without building its
+// values, and returns a *PayloadLimitError as soon as one of l is exceeded.
+// Syntax errors are returned as the decoder reports them. An empty payload
+// is within the limits.
+func (l PayloadLimits) Check(data []byte) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var stack []frame
+	keys := 0
+	for {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		s, isString := tok.(string)
+		if isString && l.MaxStringLength > 0 && len(s) > l.MaxStringLength {
+			return &PayloadLimitError{Limit: "string length", Max: l.MaxStringLength}
+		}
+		if n := len(stack); isString && n > 0 && stack[n-1].object && stack[n-1].key {
+			keys++
+			if l.MaxKeys > 0 && keys > l.MaxKeys {
+				return &PayloadLimitError{Limit: "number of keys", Max: l.MaxKeys}
+			}
+			stack[n-1].key = false
+			continue
+		}
+
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			if l.MaxDepth > 0 && len(stack) == l.MaxDepth {
+				return &PayloadLimitError{Limit: "nesting depth", Max: l.MaxDepth}
+			}
+			stack = append(stack, frame{object: tok == json.Delim('{'), key: tok == json.Delim('{')})
+			continue
+		case json.Delim('}'), json.Delim(']'):
+			stack = stack[:len(stack)-1]
+		}
+		// a value was completed, so an enclosing object expects a key next
+		if n := len(stack); n > 0 && stack[n-1].object {
+			stack[n-1].key = true
+		}
+	}
+}
+
+// DecodeJSONWithLimits decodes r into v like DecodeJSON, once the payload
+// was checked against limits.
+func DecodeJSONWithLimits(r io.Reader, v any, limits PayloadLimits) error {
+	defer io.Copy(io.Discard, r) //nolint:errcheck
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if err := limits.Check(b); err != nil {
+		return err
+	}
+	return DecodeJSON(bytes.NewReader(b), v)
+}
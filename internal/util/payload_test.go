@@ -0,0 +1,147 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+var testLimits = util.PayloadLimits{MaxDepth: 10, MaxKeys: 20, MaxStringLength: 32}
+
+// nested returns arguments whose vis_config map parameter nests depth
+// objects and arrays.
+func nested(depth int) string {
+	var sb strings.Builder
+	sb.WriteString(`{"vis_config": `)
+	for i := 0; i < depth; i++ {
+		if i%2 == 0 {
+			sb.WriteString(`{"a": `)
+		} else {
+			sb.WriteString(`[`)
+		}
+	}
+	sb.WriteString(`1`)
+	for i := depth - 1; i >= 0; i-- {
+		if i%2 == 0 {
+			sb.WriteString(`}`)
+		} else {
+			sb.WriteString(`]`)
+		}
+	}
+	sb.WriteString(`}`)
+	return sb.String()
+}
+
+// manyKeys returns arguments whose map parameter has n keys.
+func manyKeys(n int) string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = fmt.Sprintf(`"k%d": %d`, i, i)
+	}
+	return `{"vis_config": {` + strings.Join(keys, ", ") + `}}`
+}
+
+func TestPayloadLimitsCheck(t *testing.T) {
+	tcs := []struct {
+		desc    string
+		payload string
+		limit   string
+	}{
+		{desc: "empty", payload: ""},
+		{desc: "flat", payload: `{"id": 1, "name": "alice", "tags": ["a", "b"], "ok": true, "none": null}`},
+		{desc: "depth at limit", payload: nested(9)},
+		{desc: "depth over limit", payload: nested(10), limit: "nesting depth"},
+		{desc: "very deep", payload: nested(100000), limit: "nesting depth"},
+		{desc: "keys at limit", payload: manyKeys(19)},
+		{desc: "keys over limit", payload: manyKeys(20), limit: "number of keys"},
+		{desc: "keys over limit in nested arrays", payload: `{"a": [{"b": 1, "c": 2}, {"d": [{"e": 1}]}], ` + strings.TrimPrefix(manyKeys(15), "{"), limit: "number of keys"},
+		{desc: "string at limit", payload: `{"s": "` + strings.Repeat("x", 32) + `"}`},
+		{desc: "string over limit", payload: `{"s": "` + strings.Repeat("x", 33) + `"}`, limit: "string length"},
+		{desc: "string over limit in array", payload: `{"s": ["a", "` + strings.Repeat("x", 1000) + `"]}`, limit: "string length"},
+		{desc: "key over limit", payload: `{"` + strings.Repeat("k", 33) + `": 1}`, limit: "string length"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := testLimits.Check([]byte(tc.payload))
+			if tc.limit == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			var limitErr *util.PayloadLimitError
+			if !errors.As(err, &limitErr) {
+				t.Fatalf("got error %v, want a PayloadLimitError", err)
+			}
+			if limitErr.Limit != tc.limit {
+				t.Fatalf("got limit %q, want %q", limitErr.Limit, tc.limit)
+			}
+		})
+	}
+}
+
+func TestPayloadLimitsCheckUnlimited(t *testing.T) {
+	if err := (util.PayloadLimits{}).Check([]byte(nested(1000))); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := testLimits.Check([]byte(`{"a": [1,}`)); err == nil {
+		t.Fatalf("expected a syntax error")
+	}
+}
+
+func TestDecodeJSONWithLimits(t *testing.T) {
+	var v map[string]any
+	if err := util.DecodeJSONWithLimits(strings.NewReader(`{"a": {"b": [1, 2.5]}}`), &v, testLimits); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := fmt.Sprint(v); got != "map[a:map[b:[1 2.5]]]" {
+		t.Fatalf("unexpected value: %s", got)
+	}
+	if err := util.DecodeJSONWithLimits(strings.NewReader(""), &v, testLimits); !errors.Is(err, io.EOF) {
+		t.Fatalf("got error %v, want io.EOF", err)
+	}
+	var limitErr *util.PayloadLimitError
+	if err := util.DecodeJSONWithLimits(strings.NewReader(nested(50)), &v, testLimits); !errors.As(err, &limitErr) {
+		t.Fatalf("got error %v, want a PayloadLimitError", err)
+	}
+}
+
+// BenchmarkDeepPayload compares the rejection of a deeply nested payload by
+// the limits with its full decoding.
+func BenchmarkDeepPayload(b *testing.B) {
+	payload := []byte(nested(10000))
+	limits := util.PayloadLimits{MaxDepth: 100}
+
+	b.Run("check", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = limits.Check(payload)
+		}
+	})
+	b.Run("decode", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var v map[string]any
+			_ = util.DecodeJSON(bytes.NewReader(payload), &v)
+		}
+	})
+}
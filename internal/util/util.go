@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
@@ -162,3 +163,155 @@ func InstrumentationFromContext(ctx context.Context) (*telemetry.Instrumentation
 	}
 	return nil, fmt.Errorf("unable to retrieve instrumentation")
 }
+
+// toolsFileInfoKey is the key used to store the tools file being parsed within context
+const toolsFileInfoKey contextKey = "toolsFileInfo"
+
+// ToolsFileInfo describes the tools file that is currently being parsed.
+type ToolsFileInfo struct {
+	// Dir is the directory that relative file references are resolved against.
+	Dir string
+	// ReferencedFiles collects the files loaded while parsing the tools file.
+	ReferencedFiles []string
+}
+
+// WithToolsFileInfo adds a ToolsFileInfo into the context as a value
+func WithToolsFileInfo(ctx context.Context, info *ToolsFileInfo) context.Context {
+	return context.WithValue(ctx, toolsFileInfoKey, info)
+}
+
+// ToolsFileInfoFromContext retrieves the ToolsFileInfo, or nil if none is set
+func ToolsFileInfoFromContext(ctx context.Context) *ToolsFileInfo {
+	info, _ := ctx.Value(toolsFileInfoKey).(*ToolsFileInfo)
+	return info
+}
+
+// scheduleOverrideKey is the key used to store whether the caller requested
+// to override the schedule of tools within context
+const scheduleOverrideKey contextKey = "scheduleOverride"
+
+// WithScheduleOverride marks the context as requesting to invoke tools outside
+// of their allowed schedule
+func WithScheduleOverride(ctx context.Context) context.Context {
+	return context.WithValue(ctx, scheduleOverrideKey, true)
+}
+
+// ScheduleOverrideFromContext reports whether the context requests to invoke
+// tools outside of their allowed schedule
+func ScheduleOverrideFromContext(ctx context.Context) bool {
+	override, _ := ctx.Value(scheduleOverrideKey).(bool)
+	return override
+}
+
+// verifiedAuthServicesKey is the key used to store the names of the
+// authServices that verified the caller within context
+const verifiedAuthServicesKey contextKey = "verifiedAuthServices"
+
+// WithVerifiedAuthServices adds the names of the authServices that verified
+// the caller into the context as a value
+func WithVerifiedAuthServices(ctx context.Context, authServices []string) context.Context {
+	return context.WithValue(ctx, verifiedAuthServicesKey, authServices)
+}
+
+// VerifiedAuthServicesFromContext retrieves the names of the authServices that
+// verified the caller, or nil if none is set
+func VerifiedAuthServicesFromContext(ctx context.Context) []string {
+	authServices, _ := ctx.Value(verifiedAuthServicesKey).([]string)
+	return authServices
+}
+
+// authClaimsKey is the key used to store the claims retrieved from the
+// authServices that verified the caller within context
+const authClaimsKey contextKey = "authClaims"
+
+// WithAuthClaims adds the claims retrieved from each authService that
+// verified the caller into the context as a value
+func WithAuthClaims(ctx context.Context, claims map[string]map[string]any) context.Context {
+	return context.WithValue(ctx, authClaimsKey, claims)
+}
+
+// AuthClaimsFromContext retrieves the claims retrieved from each authService
+// that verified the caller, or nil if none is set
+func AuthClaimsFromContext(ctx context.Context) map[string]map[string]any {
+	claims, _ := ctx.Value(authClaimsKey).(map[string]map[string]any)
+	return claims
+}
+
+const (
+	// ClientAttributionAuth identifies callers by the subject of the claims
+	// of the authServices that verified them.
+	ClientAttributionAuth = "auth"
+	// ClientAttributionHeader identifies callers by the client name they
+	// send in a request header.
+	ClientAttributionHeader = "header"
+)
+
+// maxClientIdentityLength caps the length of client identities, to fit the
+// limits of Postgres application names and BigQuery label values.
+const maxClientIdentityLength = 63
+
+// clientAttributionKey is the key used to store how the caller is identified
+// to backends within context
+const clientAttributionKey contextKey = "clientAttribution"
+
+type clientAttribution struct {
+	mode       string
+	clientName string
+}
+
+// WithClientAttribution marks the context as forwarding the identity of the
+// caller to backends. mode is ClientAttributionAuth or
+// ClientAttributionHeader, and clientName is the client name sent by the
+// caller, if any.
+func WithClientAttribution(ctx context.Context, mode, clientName string) context.Context {
+	return context.WithValue(ctx, clientAttributionKey, clientAttribution{mode: mode, clientName: clientName})
+}
+
+// ClientIdentityFromContext returns the sanitized identity of the caller to
+// forward to backends, or "" if client attribution is disabled or the caller
+// could not be identified. With ClientAttributionAuth, the `sub` claim of the
+// first verified authService, by name, is used.
+func ClientIdentityFromContext(ctx context.Context) string {
+	attribution, ok := ctx.Value(clientAttributionKey).(clientAttribution)
+	if !ok {
+		return ""
+	}
+	switch attribution.mode {
+	case ClientAttributionHeader:
+		return SanitizeClientIdentity(attribution.clientName)
+	case ClientAttributionAuth:
+		claims := AuthClaimsFromContext(ctx)
+		names := make([]string, 0, len(claims))
+		for name := range claims {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if sub, ok := claims[name]["sub"].(string); ok {
+				if identity := SanitizeClientIdentity(sub); identity != "" {
+					return identity
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// SanitizeClientIdentity lowercases s, replaces the characters other than
+// letters, digits, `_` and `-` with `_`, and caps its length, so that it can
+// be used in application names, job labels and user agents.
+func SanitizeClientIdentity(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	var sb strings.Builder
+	for _, r := range s {
+		if sb.Len() == maxClientIdentityLength {
+			break
+		}
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' || r == '-' {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteByte('_')
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+func TestSanitizeClientIdentity(t *testing.T) {
+	tcs := []struct {
+		in   string
+		want string
+	}{
+		{in: "billing-agent", want: "billing-agent"},
+		{in: " Alice@Example.com ", want: "alice_example_com"},
+		{in: "agent'; DROP TABLE x; --", want: "agent___drop_table_x__--"},
+		{in: "élan", want: "_lan"},
+		{in: strings.Repeat("a", 100), want: strings.Repeat("a", 63)},
+		{in: "", want: ""},
+	}
+	for _, tc := range tcs {
+		if got := util.SanitizeClientIdentity(tc.in); got != tc.want {
+			t.Errorf("SanitizeClientIdentity(%q): got %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestClientIdentityFromContext(t *testing.T) {
+	claims := map[string]map[string]any{
+		"b-auth": {"sub": "Second"},
+		"a-auth": {"sub": "First.User"},
+	}
+	tcs := []struct {
+		desc string
+		ctx  context.Context
+		want string
+	}{
+		{desc: "disabled", ctx: util.WithAuthClaims(context.Background(), claims), want: ""},
+		{desc: "header", ctx: util.WithClientAttribution(context.Background(), util.ClientAttributionHeader, "Billing Agent"), want: "billing_agent"},
+		{
+			desc: "auth uses the first verified auth service",
+			ctx:  util.WithAuthClaims(util.WithClientAttribution(context.Background(), util.ClientAttributionAuth, "ignored"), claims),
+			want: "first_user",
+		},
+		{
+			desc: "auth without subject",
+			ctx:  util.WithAuthClaims(util.WithClientAttribution(context.Background(), util.ClientAttributionAuth, ""), map[string]map[string]any{"a-auth": {"email": "a@b.c"}}),
+			want: "",
+		},
+		{desc: "auth without claims", ctx: util.WithClientAttribution(context.Background(), util.ClientAttributionAuth, ""), want: ""},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := util.ClientIdentityFromContext(tc.ctx); got != tc.want {
+				t.Fatalf("unexpected identity: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -25,7 +25,6 @@ import (
 	"net/url"
 	"os"
 	"reflect"
-	"regexp"
 	"sort"
 	"strings"
 	"testing"
@@ -162,7 +161,7 @@ func TestAlloyDBToolEndpoints(t *testing.T) {
 
 	waitCtx, cancelWait := context.WithTimeout(ctx, 20*time.Second)
 	defer cancelWait()
-	out, err := testutils.WaitForString(waitCtx, regexp.MustCompile(`Server ready to serve`), cmd.Out)
+	_, out, err := testutils.WaitForServerReady(waitCtx, cmd.Out)
 	if err != nil {
 		t.Logf("toolbox command logs: \n%s", out)
 		t.Fatalf("toolbox didn't start successfully: %v", err)
@@ -193,8 +192,8 @@ func runAlloyDBToolGetTest(t *testing.T) {
 				"my-simple-tool": map[string]any{
 					"description": "Simple tool to test end to end functionality.",
 					"parameters": []any{
-						map[string]any{"name": "project", "type": "string", "description": "The GCP project ID to list clusters for.", "required": true, "authSources": []any{}},
-						map[string]any{"name": "location", "type": "string", "description": "Optional: The location to list clusters in (e.g., 'us-central1'). Use '-' to list clusters across all locations.(Default: '-')", "required": false, "authSources": []any{}},
+						map[string]any{"name": "project", "type": "string", "description": "The GCP project ID to list clusters for.", "required": true, "authSources": []any{}, "examples": []any{"example"}},
+						map[string]any{"name": "location", "type": "string", "default": "-", "description": "Optional: The location to list clusters in (e.g., 'us-central1'). Use '-' to list clusters across all locations.(Default: '-')", "required": false, "authSources": []any{}, "examples": []any{"-"}},
 					},
 					"authRequired": []any{},
 				},
@@ -1114,7 +1113,7 @@ func TestAlloyDBCreateCluster(t *testing.T) {
 
 	waitCtx, cancelWait := context.WithTimeout(ctx, 10*time.Second)
 	defer cancelWait()
-	out, err := testutils.WaitForString(waitCtx, regexp.MustCompile(`Server ready to serve`), cmd.Out)
+	_, out, err := testutils.WaitForServerReady(waitCtx, cmd.Out)
 	if err != nil {
 		t.Logf("toolbox command logs: \n%s", out)
 		t.Fatalf("toolbox didn't start successfully: %s", err)
@@ -1224,7 +1223,7 @@ func TestAlloyDBCreateInstance(t *testing.T) {
 
 	waitCtx, cancelWait := context.WithTimeout(ctx, 10*time.Second)
 	defer cancelWait()
-	out, err := testutils.WaitForString(waitCtx, regexp.MustCompile(`Server ready to serve`), cmd.Out)
+	_, out, err := testutils.WaitForServerReady(waitCtx, cmd.Out)
 	if err != nil {
 		t.Logf("toolbox command logs: \n%s", out)
 		t.Fatalf("toolbox didn't start successfully: %s", err)
@@ -1350,7 +1349,7 @@ func TestAlloyDBCreateUser(t *testing.T) {
 
 	waitCtx, cancelWait := context.WithTimeout(ctx, 10*time.Second)
 	defer cancelWait()
-	out, err := testutils.WaitForString(waitCtx, regexp.MustCompile(`Server ready to serve`), cmd.Out)
+	_, out, err := testutils.WaitForServerReady(waitCtx, cmd.Out)
 	if err != nil {
 		t.Logf("toolbox command logs: \n%s", out)
 		t.Fatalf("toolbox didn't start successfully: %s", err)
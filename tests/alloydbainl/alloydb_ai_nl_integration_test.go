@@ -22,7 +22,6 @@ import (
 	"net/http"
 	"os"
 	"reflect"
-	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -91,7 +90,7 @@ func TestAlloyDBAINLToolEndpoints(t *testing.T) {
 
 	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
-	out, err := testutils.WaitForString(waitCtx, regexp.MustCompile(`Server ready to serve`), cmd.Out)
+	_, out, err := testutils.WaitForServerReady(waitCtx, cmd.Out)
 	if err != nil {
 		t.Logf("toolbox command logs: \n%s", out)
 		t.Fatalf("toolbox didn't start successfully: %s", err)
@@ -122,6 +121,7 @@ func runAINLToolGetTest(t *testing.T) {
 							"required":    true,
 							"description": "The natural language question to ask.",
 							"authSources": []any{},
+							"examples":    []any{"example"},
 						},
 					},
 					"authRequired": []any{},
@@ -396,7 +396,7 @@ func runAINLMCPToolCallMethod(t *testing.T) {
 					"arguments": map[string]any{},
 				},
 			},
-			want: `{"jsonrpc":"2.0","id":"invoke-without-parameter","error":{"code":-32602,"message":"provided parameters were invalid: parameter question is required"}}`,
+			want: `{"jsonrpc":"2.0","id":"invoke-without-parameter","error":{"code":-32602,"message":"provided parameters were invalid: parameter question is required","data":[{"name":"question","problem":"is required","expected":"string"}]}}`,
 		},
 	}
 	for _, tc := range invokeTcs {
@@ -161,7 +161,7 @@ func TestBigQueryToolEndpoints(t *testing.T) {
 
 	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
-	out, err := testutils.WaitForString(waitCtx, regexp.MustCompile(`Server ready to serve`), cmd.Out)
+	_, out, err := testutils.WaitForServerReady(waitCtx, cmd.Out)
 	if err != nil {
 		t.Logf("toolbox command logs: \n%s", out)
 		t.Fatalf("toolbox didn't start successfully: %s", err)
@@ -170,7 +170,8 @@ func TestBigQueryToolEndpoints(t *testing.T) {
 	// Get configs for tests
 	select1Want := "[{\"f0_\":1}]"
 	invokeParamWant := "[{\"id\":1,\"name\":\"Alice\"},{\"id\":3,\"name\":\"Sid\"}]"
-	datasetInfoWant := "\"Location\":\"US\",\"DefaultTableExpiration\":0,\"Labels\":null,\"Access\":"
+	// the access entries are stripped unless requested
+	datasetInfoWant := "\"Location\":\"US\",\"DefaultTableExpiration\":0,\"Labels\":null,\"DefaultEncryptionConfig\":"
 	tableInfoWant := "{\"Name\":\"\",\"Location\":\"US\",\"Description\":\"\",\"Schema\":[{\"Name\":\"id\""
 	ddlWant := `"Query executed successfully and returned no content."`
 	dataInsightsWant := `(?s)Schema Resolved.*Retrieval Query.*SQL Generated.*Answer`
@@ -344,7 +345,7 @@ func TestBigQueryToolWithDatasetRestriction(t *testing.T) {
 
 	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
-	out, err := testutils.WaitForString(waitCtx, regexp.MustCompile(`Server ready to serve`), cmd.Out)
+	_, out, err := testutils.WaitForServerReady(waitCtx, cmd.Out)
 	if err != nil {
 		t.Logf("toolbox command logs: \n%s", out)
 		t.Fatalf("toolbox didn't start successfully: %s", err)
@@ -413,7 +414,7 @@ func TestBigQueryWriteModeAllowed(t *testing.T) {
 
 	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
-	out, err := testutils.WaitForString(waitCtx, regexp.MustCompile(`Server ready to serve`), cmd.Out)
+	_, out, err := testutils.WaitForServerReady(waitCtx, cmd.Out)
 	if err != nil {
 		t.Logf("toolbox command logs: \n%s", out)
 		t.Fatalf("toolbox didn't start successfully: %s", err)
@@ -456,7 +457,7 @@ func TestBigQueryWriteModeBlocked(t *testing.T) {
 
 	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
-	out, err := testutils.WaitForString(waitCtx, regexp.MustCompile(`Server ready to serve`), cmd.Out)
+	_, out, err := testutils.WaitForServerReady(waitCtx, cmd.Out)
 	if err != nil {
 		t.Logf("toolbox command logs: \n%s", out)
 		t.Fatalf("toolbox didn't start successfully: %s", err)
@@ -518,7 +519,7 @@ func TestBigQueryWriteModeProtected(t *testing.T) {
 
 	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
-	out, err := testutils.WaitForString(waitCtx, regexp.MustCompile(`Server ready to serve`), cmd.Out)
+	_, out, err := testutils.WaitForServerReady(waitCtx, cmd.Out)
 	if err != nil {
 		t.Logf("toolbox command logs: \n%s", out)
 		t.Fatalf("toolbox didn't start successfully: %s", err)
@@ -784,6 +785,12 @@ func addBigQueryPrebuiltToolsConfig(t *testing.T, config map[string]any) map[str
 		"source":      "my-instance",
 		"description": "Tool to show dataset metadata",
 	}
+	tools["my-redacted-get-dataset-info-tool"] = map[string]any{
+		"kind":             "bigquery-get-dataset-info",
+		"source":           "my-instance",
+		"description":      "Tool to show dataset metadata without user emails",
+		"redactPrincipals": true,
+	}
 	tools["my-auth-get-dataset-info-tool"] = map[string]any{
 		"kind":        "bigquery-get-dataset-info",
 		"source":      "my-instance",
@@ -1924,6 +1931,23 @@ func runBigQueryGetDatasetInfoToolInvokeTest(t *testing.T, datasetName, datasetI
 			want:          datasetInfoWant,
 			isErr:         false,
 		},
+		{
+			name:          "invoke my-get-dataset-info-tool with access",
+			api:           "http://127.0.0.1:5000/api/tool/my-get-dataset-info-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"dataset\":\"%s\", \"includeAccess\": true}", datasetName))),
+			want:          `{"role":"OWNER","entityType":"specialGroup","entity":"projectOwners"}`,
+			isErr:         false,
+		},
+		{
+			// the dataset is owned by the user who created it
+			name:          "invoke my-redacted-get-dataset-info-tool with access",
+			api:           "http://127.0.0.1:5000/api/tool/my-redacted-get-dataset-info-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(fmt.Sprintf("{\"dataset\":\"%s\", \"includeAccess\": true}", datasetName))),
+			want:          `"entityType":"userByEmail","entity":"sha256:`,
+			isErr:         false,
+		},
 		{
 			name:          "Invoke my-auth-get-dataset-info-tool with correct project",
 			api:           "http://127.0.0.1:5000/api/tool/my-auth-get-dataset-info-tool/invoke",
@@ -19,7 +19,6 @@ import (
 	"database/sql"
 	"fmt"
 	"os"
-	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -143,7 +142,7 @@ func TestClickHouse(t *testing.T) {
 
 	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
-	out, err := testutils.WaitForString(waitCtx, regexp.MustCompile(`Server ready to serve`), cmd.Out)
+	_, out, err := testutils.WaitForServerReady(waitCtx, cmd.Out)
 	if err != nil {
 		t.Logf("toolbox command logs: \n%s", out)
 		t.Fatalf("toolbox didn't start successfully: %s", err)
@@ -330,7 +329,7 @@ func TestClickHouseBasicConnection(t *testing.T) {
 
 	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
-	out, err := testutils.WaitForString(waitCtx, regexp.MustCompile(`Server ready to serve`), cmd.Out)
+	_, out, err := testutils.WaitForServerReady(waitCtx, cmd.Out)
 	if err != nil {
 		t.Logf("toolbox command logs: \n%s", out)
 		t.Fatalf("toolbox didn't start successfully: %s", err)
@@ -403,7 +402,7 @@ func TestClickHouseSQLTool(t *testing.T) {
 			t.Fatalf("Failed to initialize tool: %v", err)
 		}
 
-		result, err := tool.Invoke(ctx, tools.ParamValues{}, "")
+		result, err := tool.Invoke(ctx, tools.Invocation{Params: tools.ParamValues{}})
 		if err != nil {
 			t.Fatalf("Failed to invoke tool: %v", err)
 		}
@@ -444,7 +443,7 @@ func TestClickHouseSQLTool(t *testing.T) {
 			{Name: "min_age", Value: 28},
 		}
 
-		result, err := tool.Invoke(ctx, params, "")
+		result, err := tool.Invoke(ctx, tools.Invocation{Params: params})
 		if err != nil {
 			t.Fatalf("Failed to invoke tool: %v", err)
 		}
@@ -485,7 +484,7 @@ func TestClickHouseSQLTool(t *testing.T) {
 			{Name: "id", Value: 999}, // Non-existent ID
 		}
 
-		result, err := tool.Invoke(ctx, params, "")
+		result, err := tool.Invoke(ctx, tools.Invocation{Params: params})
 		if err != nil {
 			t.Fatalf("Failed to invoke tool: %v", err)
 		}
@@ -519,7 +518,7 @@ func TestClickHouseSQLTool(t *testing.T) {
 			t.Fatalf("Failed to initialize tool: %v", err)
 		}
 
-		_, err = tool.Invoke(ctx, tools.ParamValues{}, "")
+		_, err = tool.Invoke(ctx, tools.Invocation{Params: tools.ParamValues{}})
 		if err == nil {
 			t.Error("Expected error for invalid SQL, got nil")
 		}
@@ -574,7 +573,7 @@ func TestClickHouseExecuteSQLTool(t *testing.T) {
 			{Name: "sql", Value: createSQL},
 		}
 
-		result, err := tool.Invoke(ctx, params, "")
+		result, err := tool.Invoke(ctx, tools.Invocation{Params: params})
 		if err != nil {
 			t.Fatalf("Failed to create table: %v", err)
 		}
@@ -612,7 +611,7 @@ func TestClickHouseExecuteSQLTool(t *testing.T) {
 			{Name: "sql", Value: insertSQL},
 		}
 
-		result, err := tool.Invoke(ctx, params, "")
+		result, err := tool.Invoke(ctx, tools.Invocation{Params: params})
 		if err != nil {
 			t.Fatalf("Failed to insert data: %v", err)
 		}
@@ -650,7 +649,7 @@ func TestClickHouseExecuteSQLTool(t *testing.T) {
 			{Name: "sql", Value: selectSQL},
 		}
 
-		result, err := tool.Invoke(ctx, params, "")
+		result, err := tool.Invoke(ctx, tools.Invocation{Params: params})
 		if err != nil {
 			t.Fatalf("Failed to select data: %v", err)
 		}
@@ -688,7 +687,7 @@ func TestClickHouseExecuteSQLTool(t *testing.T) {
 			{Name: "sql", Value: dropSQL},
 		}
 
-		result, err := tool.Invoke(ctx, params, "")
+		result, err := tool.Invoke(ctx, tools.Invocation{Params: params})
 		if err != nil {
 			t.Fatalf("Failed to drop table: %v", err)
 		}
@@ -726,7 +725,7 @@ func TestClickHouseExecuteSQLTool(t *testing.T) {
 			{Name: "sql", Value: ""},
 		}
 
-		_, err = tool.Invoke(ctx, params, "")
+		_, err = tool.Invoke(ctx, tools.Invocation{Params: params})
 		if err == nil {
 			t.Error("Expected error for empty SQL parameter, got nil")
 		} else {
@@ -758,7 +757,7 @@ func TestClickHouseExecuteSQLTool(t *testing.T) {
 			{Name: "sql", Value: injectionSQL},
 		}
 
-		_, err = tool.Invoke(ctx, params, "")
+		_, err = tool.Invoke(ctx, tools.Invocation{Params: params})
 		// This should either fail or only execute the first statement
 		// dont check the specific error as behavior may vary
 		_ = err // We're not checking the error intentionally
@@ -807,7 +806,7 @@ func TestClickHouseEdgeCases(t *testing.T) {
 			{Name: "sql", Value: longQuery},
 		}
 
-		result, err := tool.Invoke(ctx, params, "")
+		result, err := tool.Invoke(ctx, tools.Invocation{Params: params})
 		if err != nil {
 			t.Fatalf("Failed to execute long query: %v", err)
 		}
@@ -862,7 +861,7 @@ func TestClickHouseEdgeCases(t *testing.T) {
 			t.Fatalf("Failed to initialize tool: %v", err)
 		}
 
-		result, err := tool.Invoke(ctx, tools.ParamValues{}, "")
+		result, err := tool.Invoke(ctx, tools.Invocation{Params: tools.ParamValues{}})
 		if err != nil {
 			t.Fatalf("Failed to select null values: %v", err)
 		}
@@ -916,7 +915,7 @@ func TestClickHouseEdgeCases(t *testing.T) {
 					{Name: "limit", Value: n + 1},
 				}
 
-				result, err := tool.Invoke(ctx, params, "")
+				result, err := tool.Invoke(ctx, tools.Invocation{Params: params})
 				if err != nil {
 					t.Errorf("Concurrent query %d failed: %v", n, err)
 					return
@@ -1056,7 +1055,7 @@ func TestClickHouseListDatabasesTool(t *testing.T) {
 
 		params := tools.ParamValues{}
 
-		result, err := tool.Invoke(ctx, params, "")
+		result, err := tool.Invoke(ctx, tools.Invocation{Params: params})
 		if err != nil {
 			t.Fatalf("Failed to list databases: %v", err)
 		}
@@ -1170,7 +1169,7 @@ func TestClickHouseListTablesTool(t *testing.T) {
 			{Name: "database", Value: testDBName},
 		}
 
-		result, err := tool.Invoke(ctx, params, "")
+		result, err := tool.Invoke(ctx, tools.Invocation{Params: params})
 		if err != nil {
 			t.Fatalf("Failed to list tables: %v", err)
 		}
@@ -1234,7 +1233,7 @@ func TestClickHouseListTablesTool(t *testing.T) {
 
 		params := tools.ParamValues{}
 
-		_, err = tool.Invoke(ctx, params, "")
+		_, err = tool.Invoke(ctx, tools.Invocation{Params: params})
 		if err == nil {
 			t.Error("Expected error for missing database parameter, got nil")
 		} else {
@@ -62,7 +62,7 @@ func TestTool_Invoke(t *testing.T) {
 	}
 
 	// Invoke the tool
-	result, err := tool.Invoke(context.Background(), params, "")
+	result, err := tool.Invoke(context.Background(), tools.Invocation{Params: params})
 	if err != nil {
 		t.Fatalf("Invoke() error = %v", err)
 	}
@@ -106,7 +106,7 @@ func TestTool_Invoke_Error(t *testing.T) {
 	}
 
 	// Invoke the tool
-	_, err := tool.Invoke(context.Background(), params, "")
+	_, err := tool.Invoke(context.Background(), tools.Invocation{Params: params})
 	if err == nil {
 		t.Fatal("Invoke() error = nil, want error")
 	}
@@ -324,6 +324,11 @@ func AddMySQLPrebuiltToolConfig(t *testing.T, config map[string]any) map[string]
 		"source":      "my-instance",
 		"description": "Lists table fragmentation in the database.",
 	}
+	tools["sample_table"] = map[string]any{
+		"kind":        "mysql-sample-table",
+		"source":      "my-instance",
+		"description": "Samples the rows of a table.",
+	}
 	config["tools"] = tools
 	return config
 }
@@ -15,14 +15,12 @@
 package dataplex
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"os"
-	"regexp"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -31,8 +29,11 @@ import (
 	dataplex "cloud.google.com/go/dataplex/apiv1"
 	dataplexpb "cloud.google.com/go/dataplex/apiv1/dataplexpb"
 	"github.com/google/uuid"
+	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/testutils/httpreplay"
 	"github.com/googleapis/genai-toolbox/tests"
+	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/googleapi"
 	"google.golang.org/api/iterator"
@@ -47,6 +48,13 @@ var (
 	DataplexProject                   = os.Getenv("DATAPLEX_PROJECT")
 )
 
+// replayProject replaces the project id in recorded traffic.
+const replayProject = "test-project"
+
+// searchEntriesGolden is recorded by TestDataplexToolEndpoints when
+// TOOLBOX_TEST_RECORD=1 and replayed by TestDataplexSearchEntriesReplay.
+var searchEntriesGolden = filepath.Join("testdata", "search_entries.json")
+
 func getDataplexVars(t *testing.T) map[string]any {
 	switch "" {
 	case DataplexProject:
@@ -85,6 +93,25 @@ func initDataplexConnection(ctx context.Context) (*dataplex.CatalogClient, error
 	return client, nil
 }
 
+// newRecorder returns a Recorder sending authenticated requests to Google
+// APIs and writing the search entries golden file.
+func newRecorder(ctx context.Context) (*httpreplay.Recorder, error) {
+	cred, err := google.FindDefaultCredentials(ctx, "https://www.googleapis.com/auth/cloud-platform")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find default Google Cloud credentials: %w", err)
+	}
+	transport := &oauth2.Transport{Source: cred.TokenSource, Base: http.DefaultTransport}
+	return httpreplay.New(searchEntriesGolden, httpreplay.WithScrub(DataplexProject, replayProject), httpreplay.WithTransport(transport))
+}
+
+// recordedVar returns the value of generate, storing it in rec if recording.
+func recordedVar(rec *httpreplay.Recorder, name string, generate func() string) string {
+	if rec == nil {
+		return generate()
+	}
+	return rec.Var(name, generate)
+}
+
 func TestDataplexToolEndpoints(t *testing.T) {
 	sourceConfig := getDataplexVars(t)
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
@@ -92,6 +119,23 @@ func TestDataplexToolEndpoints(t *testing.T) {
 
 	var args []string
 
+	// When recording, the traffic of the toolbox server is captured
+	cmdCtx := ctx
+	var rec *httpreplay.Recorder
+	if httpreplay.Recording() {
+		var err error
+		rec, err = newRecorder(ctx)
+		if err != nil {
+			t.Fatalf("unable to create recorder: %s", err)
+		}
+		defer func() {
+			if err := rec.Close(); err != nil {
+				t.Errorf("unable to write golden file: %s", err)
+			}
+		}()
+		cmdCtx = sources.WithGoogleHTTPClient(ctx, rec.Client())
+	}
+
 	bigqueryClient, err := initBigQueryConnection(ctx, DataplexProject)
 	if err != nil {
 		t.Fatalf("unable to create Cloud SQL connection pool: %s", err)
@@ -103,8 +147,12 @@ func TestDataplexToolEndpoints(t *testing.T) {
 	}
 
 	// create resources with UUID
-	datasetName := fmt.Sprintf("temp_toolbox_test_%s", strings.ReplaceAll(uuid.New().String(), "-", ""))
-	tableName := fmt.Sprintf("param_table_%s", strings.ReplaceAll(uuid.New().String(), "-", ""))
+	datasetName := recordedVar(rec, "datasetName", func() string {
+		return fmt.Sprintf("temp_toolbox_test_%s", strings.ReplaceAll(uuid.New().String(), "-", ""))
+	})
+	tableName := recordedVar(rec, "tableName", func() string {
+		return fmt.Sprintf("param_table_%s", strings.ReplaceAll(uuid.New().String(), "-", ""))
+	})
 	aspectTypeId := fmt.Sprintf("param-aspect-type-%s", strings.ReplaceAll(uuid.New().String(), "-", ""))
 
 	teardownTable1 := setupBigQueryTable(t, ctx, bigqueryClient, datasetName, tableName)
@@ -113,9 +161,9 @@ func TestDataplexToolEndpoints(t *testing.T) {
 	defer teardownTable1(t)
 	defer teardownAspectType1(t)
 
-	toolsFile := getDataplexToolsConfig(sourceConfig)
+	toolsFile := getDataplexToolsConfig(sourceConfig, tests.ClientId)
 
-	cmd, cleanup, err := tests.StartCmd(ctx, toolsFile, args...)
+	cmd, cleanup, err := tests.StartCmd(cmdCtx, toolsFile, args...)
 	if err != nil {
 		t.Fatalf("command initialization returned an error: %s", err)
 	}
@@ -123,7 +171,7 @@ func TestDataplexToolEndpoints(t *testing.T) {
 
 	waitCtx, cancel := context.WithTimeout(ctx, 3*time.Minute)
 	defer cancel()
-	out, err := testutils.WaitForString(waitCtx, regexp.MustCompile(`Server ready to serve`), cmd.Out)
+	_, out, err := testutils.WaitForServerReady(waitCtx, cmd.Out)
 	if err != nil {
 		t.Logf("toolbox command logs: \n%s", out)
 		t.Fatalf("toolbox didn't start successfully: %s", err)
@@ -135,6 +183,50 @@ func TestDataplexToolEndpoints(t *testing.T) {
 	runDataplexSearchAspectTypesToolInvokeTest(t, aspectTypeId)
 }
 
+// TestDataplexSearchEntriesReplay runs the search entries tests against
+// responses recorded by TestDataplexToolEndpoints, without credentials or a
+// Google Cloud project.
+func TestDataplexSearchEntriesReplay(t *testing.T) {
+	if httpreplay.Recording() {
+		t.Skip("the golden file is recorded by TestDataplexToolEndpoints")
+	}
+	rec, err := httpreplay.New(searchEntriesGolden)
+	if httpreplay.IsNotRecorded(err) {
+		t.Skipf("no recorded responses: %s", err)
+	}
+	if err != nil {
+		t.Fatalf("unable to create replayer: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	// the auth service is only used to reject invalid tokens, which does not
+	// need a real client id
+	clientId := tests.ClientId
+	if clientId == "" {
+		clientId = "test-client-id"
+	}
+	toolsFile := getDataplexToolsConfig(map[string]any{"kind": DataplexSourceKind, "project": replayProject}, clientId)
+
+	cmd, cleanup, err := tests.StartCmd(sources.WithGoogleHTTPClient(ctx, rec.Client()), toolsFile)
+	if err != nil {
+		t.Fatalf("command initialization returned an error: %s", err)
+	}
+	defer cleanup()
+
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	_, out, err := testutils.WaitForServerReady(waitCtx, cmd.Out)
+	if err != nil {
+		t.Logf("toolbox command logs: \n%s", out)
+		t.Fatalf("toolbox didn't start successfully: %s", err)
+	}
+
+	runDataplexToolGetTest(t)
+	runDataplexSearchEntriesToolInvokeTest(t, rec.Var("tableName", nil), rec.Var("datasetName", nil), tests.SkipInvokeTests(searchAuthTest))
+}
+
 func setupBigQueryTable(t *testing.T, ctx context.Context, client *bigqueryapi.Client, datasetName string, tableName string) func(*testing.T) {
 	// Create dataset
 	dataset := client.Dataset(datasetName)
@@ -219,7 +311,7 @@ func setupDataplexThirdPartyAspectType(t *testing.T, ctx context.Context, client
 	}
 }
 
-func getDataplexToolsConfig(sourceConfig map[string]any) map[string]any {
+func getDataplexToolsConfig(sourceConfig map[string]any, clientId string) map[string]any {
 	// Write config into a file and pass it to command
 	toolsFile := map[string]any{
 		"sources": map[string]any{
@@ -228,7 +320,7 @@ func getDataplexToolsConfig(sourceConfig map[string]any) map[string]any {
 		"authServices": map[string]any{
 			"my-google-auth": map[string]any{
 				"kind":     "google",
-				"clientId": tests.ClientId,
+				"clientId": clientId,
 			},
 		},
 		"tools": map[string]any{
@@ -280,12 +372,12 @@ func runDataplexToolGetTest(t *testing.T) {
 		{
 			name:           "get my-dataplex-search-entries-tool",
 			toolName:       "my-dataplex-search-entries-tool",
-			expectedParams: []string{"pageSize", "query", "orderBy"},
+			expectedParams: []string{"pageSize", "query", "orderBy", "scope"},
 		},
 		{
 			name:           "get my-dataplex-lookup-entry-tool",
 			toolName:       "my-dataplex-lookup-entry-tool",
-			expectedParams: []string{"name", "view", "aspectTypes", "entry"},
+			expectedParams: []string{"entry", "name", "entryGroup", "entryId", "view", "aspectTypes"},
 		},
 		{
 			name:           "get my-dataplex-search-aspect-types-tool",
@@ -348,399 +440,244 @@ func runDataplexToolGetTest(t *testing.T) {
 	}
 }
 
-func runDataplexSearchEntriesToolInvokeTest(t *testing.T, tableName string, datasetName string) {
-	idToken, err := tests.GetGoogleIdToken(tests.ClientId)
-	if err != nil {
-		t.Fatalf("error getting Google ID token: %s", err)
+// searchAuthTest is the search entries case that needs a valid ID token, which
+// is skipped when replaying.
+const searchAuthTest = "Success with Authorization - Entry Found"
+
+// wantEntries returns a check that the result is a list of exactly one entry
+// with key, or is empty if key is empty.
+func wantEntries(key string) func(*testing.T, map[string]any) {
+	return func(t *testing.T, body map[string]any) {
+		resultStr, ok := body["result"].(string)
+		if !ok {
+			if body["result"] == nil && key == "" {
+				return
+			}
+			t.Fatalf("expected 'result' field to be a string, got %T", body["result"])
+		}
+		if key == "" && (resultStr == "" || resultStr == "[]") {
+			return
+		}
+		var entries []any
+		if err := json.Unmarshal([]byte(resultStr), &entries); err != nil {
+			t.Fatalf("error unmarshalling result string: %v", err)
+		}
+		if key == "" {
+			if len(entries) != 0 {
+				t.Fatalf("expected 0 entries, but got %d", len(entries))
+			}
+			return
+		}
+		if len(entries) != 1 {
+			t.Fatalf("expected exactly one entry, but got %d", len(entries))
+		}
+		entry, ok := entries[0].(map[string]any)
+		if !ok {
+			t.Fatalf("expected first entry to be a map, got %T", entries[0])
+		}
+		if _, ok := entry[key]; !ok {
+			t.Fatalf("expected entry to have key '%s', but it was not found in %v", key, entry)
+		}
 	}
+}
 
-	testCases := []struct {
-		name           string
-		api            string
-		requestHeader  map[string]string
-		requestBody    io.Reader
-		wantStatusCode int
-		expectResult   bool
-		wantContentKey string
-	}{
+// wantEntry returns a check of the entry of the result. If notKey is set, the
+// entry must not have it. If oneAspect is true, the entry must have exactly one
+// aspect.
+func wantEntry(notKey string, oneAspect bool) func(*testing.T, map[string]any) {
+	return func(t *testing.T, body map[string]any) {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(body["result"].(string)), &entry); err != nil {
+			t.Fatalf("Error unmarshalling result string into entry map: %v", err)
+		}
+		if _, ok := entry[notKey]; notKey != "" && ok {
+			t.Fatalf("Expected entry to not have key '%s', but it was found in %v", notKey, entry)
+		}
+		if oneAspect {
+			aspects, ok := entry["aspects"].(map[string]any)
+			if !ok {
+				t.Fatalf("Expected 'aspects' to be a map, got %T", entry["aspects"])
+			}
+			if len(aspects) != 1 {
+				t.Fatalf("Expected exactly one aspect, but got %d", len(aspects))
+			}
+		}
+	}
+}
+
+// runDataplexSearchEntriesToolInvokeTest invokes the search entries tools.
+func runDataplexSearchEntriesToolInvokeTest(t *testing.T, tableName string, datasetName string, options ...tests.InvokeTestOption) {
+	query := fmt.Sprintf("{\"query\":\"displayname=%s system=bigquery parent:%s\"}", tableName, datasetName)
+	tcs := []tests.InvokeTestCase{
 		{
-			name:           "Success - Entry Found",
-			api:            "http://127.0.0.1:5000/api/tool/my-dataplex-search-entries-tool/invoke",
-			requestHeader:  map[string]string{},
-			requestBody:    bytes.NewBuffer([]byte(fmt.Sprintf("{\"query\":\"displayname=%s system=bigquery parent:%s\"}", tableName, datasetName))),
-			wantStatusCode: 200,
-			expectResult:   true,
-			wantContentKey: "dataplex_entry",
+			Name:        "Success - Entry Found",
+			Tool:        "my-dataplex-search-entries-tool",
+			RequestBody: query,
+			Check:       wantEntries("dataplex_entry"),
 		},
 		{
-			name:           "Success with Authorization - Entry Found",
-			api:            "http://127.0.0.1:5000/api/tool/my-auth-dataplex-search-entries-tool/invoke",
-			requestHeader:  map[string]string{"my-google-auth_token": idToken},
-			requestBody:    bytes.NewBuffer([]byte(fmt.Sprintf("{\"query\":\"displayname=%s system=bigquery parent:%s\"}", tableName, datasetName))),
-			wantStatusCode: 200,
-			expectResult:   true,
-			wantContentKey: "dataplex_entry",
+			Name:               searchAuthTest,
+			Tool:               "my-auth-dataplex-search-entries-tool",
+			IdTokenAuthService: "my-google-auth",
+			RequestBody:        query,
+			Check:              wantEntries("dataplex_entry"),
 		},
 		{
-			name:           "Failure - Invalid Authorization Token",
-			api:            "http://127.0.0.1:5000/api/tool/my-auth-dataplex-search-entries-tool/invoke",
-			requestHeader:  map[string]string{"my-google-auth_token": "invalid_token"},
-			requestBody:    bytes.NewBuffer([]byte(fmt.Sprintf("{\"query\":\"displayname=%s system=bigquery parent:%s\"}", tableName, datasetName))),
-			wantStatusCode: 401,
-			expectResult:   false,
-			wantContentKey: "dataplex_entry",
+			Name:           "Failure - Invalid Authorization Token",
+			Tool:           "my-auth-dataplex-search-entries-tool",
+			RequestHeader:  map[string]string{"my-google-auth_token": "invalid_token"},
+			RequestBody:    query,
+			WantStatusCode: http.StatusUnauthorized,
 		},
 		{
-			name:           "Failure - Without Authorization Token",
-			api:            "http://127.0.0.1:5000/api/tool/my-auth-dataplex-search-entries-tool/invoke",
-			requestHeader:  map[string]string{},
-			requestBody:    bytes.NewBuffer([]byte(fmt.Sprintf("{\"query\":\"displayname=%s system=bigquery parent:%s\"}", tableName, datasetName))),
-			wantStatusCode: 401,
-			expectResult:   false,
-			wantContentKey: "dataplex_entry",
+			Name:           "Failure - Without Authorization Token",
+			Tool:           "my-auth-dataplex-search-entries-tool",
+			RequestBody:    query,
+			WantStatusCode: http.StatusUnauthorized,
 		},
 		{
-			name:           "Failure - Entry Not Found",
-			api:            "http://127.0.0.1:5000/api/tool/my-dataplex-search-entries-tool/invoke",
-			requestHeader:  map[string]string{},
-			requestBody:    bytes.NewBuffer([]byte(`{"query":"displayname=\"\" system=bigquery parent:\"\""}`)),
-			wantStatusCode: 200,
-			expectResult:   false,
-			wantContentKey: "",
+			Name:        "Failure - Entry Not Found",
+			Tool:        "my-dataplex-search-entries-tool",
+			RequestBody: `{"query":"displayname=\"\" system=bigquery parent:\"\""}`,
+			Check:       wantEntries(""),
 		},
 	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			req, err := http.NewRequest(http.MethodPost, tc.api, tc.requestBody)
-			if err != nil {
-				t.Fatalf("unable to create request: %s", err)
-			}
-			req.Header.Add("Content-type", "application/json")
-			for k, v := range tc.requestHeader {
-				req.Header.Add(k, v)
-			}
-			resp, err := http.DefaultClient.Do(req)
-			if err != nil {
-				t.Fatalf("unable to send request: %s", err)
-			}
-			defer resp.Body.Close()
-			if resp.StatusCode != tc.wantStatusCode {
-				t.Fatalf("response status code is not %d. It is %d", tc.wantStatusCode, resp.StatusCode)
-				bodyBytes, _ := io.ReadAll(resp.Body)
-				t.Fatalf("Response body: %s", string(bodyBytes))
-			}
-			var result map[string]interface{}
-			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-				t.Fatalf("error parsing response body: %s", err)
-			}
-			resultStr, ok := result["result"].(string)
-			if !ok {
-				if result["result"] == nil && !tc.expectResult {
-					return
-				}
-				t.Fatalf("expected 'result' field to be a string, got %T", result["result"])
-			}
-			if !tc.expectResult && (resultStr == "" || resultStr == "[]") {
-				return
-			}
-			var entries []interface{}
-			if err := json.Unmarshal([]byte(resultStr), &entries); err != nil {
-				t.Fatalf("error unmarshalling result string: %v", err)
-			}
-
-			if tc.expectResult {
-				if len(entries) != 1 {
-					t.Fatalf("expected exactly one entry, but got %d", len(entries))
-				}
-				entry, ok := entries[0].(map[string]interface{})
-				if !ok {
-					t.Fatalf("expected first entry to be a map, got %T", entries[0])
-				}
-				if _, ok := entry[tc.wantContentKey]; !ok {
-					t.Fatalf("expected entry to have key '%s', but it was not found in %v", tc.wantContentKey, entry)
-				}
-			} else {
-				if len(entries) != 0 {
-					t.Fatalf("expected 0 entries, but got %d", len(entries))
-				}
-			}
-		})
-	}
+	tests.RunToolInvokeTestCases(t, tcs, options...)
 }
 
 func runDataplexLookupEntryToolInvokeTest(t *testing.T, tableName string, datasetName string) {
-	idToken, err := tests.GetGoogleIdToken(tests.ClientId)
-	if err != nil {
-		t.Fatalf("error getting Google ID token: %s", err)
-	}
-
-	testCases := []struct {
-		name               string
-		wantStatusCode     int
-		api                string
-		requestHeader      map[string]string
-		requestBody        io.Reader
-		expectResult       bool
-		wantContentKey     string
-		dontWantContentKey string
-		aspectCheck        bool
-		reqBodyMap         map[string]any
-	}{
+	entryPrefix := fmt.Sprintf("projects/%s/locations/us/entryGroups/@bigquery/entries/bigquery.googleapis.com/projects/%s/datasets", DataplexProject, DataplexProject)
+	datasetEntry := fmt.Sprintf("{\"entry\":\"%s/%s\"}", entryPrefix, datasetName)
+	tcs := []tests.InvokeTestCase{
 		{
-			name:           "Success - Entry Found",
-			api:            "http://127.0.0.1:5000/api/tool/my-dataplex-lookup-entry-tool/invoke",
-			requestHeader:  map[string]string{},
-			requestBody:    bytes.NewBuffer([]byte(fmt.Sprintf("{\"name\":\"projects/%s/locations/us\", \"entry\":\"projects/%s/locations/us/entryGroups/@bigquery/entries/bigquery.googleapis.com/projects/%s/datasets/%s\"}", DataplexProject, DataplexProject, DataplexProject, datasetName))),
-			wantStatusCode: 200,
-			expectResult:   true,
-			wantContentKey: "name",
+			Name:           "Success - Entry Found",
+			Tool:           "my-dataplex-lookup-entry-tool",
+			RequestBody:    datasetEntry,
+			WantResultKeys: []string{"name"},
 		},
 		{
-			name:           "Success - Entry Found with Authorization",
-			api:            "http://127.0.0.1:5000/api/tool/my-auth-dataplex-lookup-entry-tool/invoke",
-			requestHeader:  map[string]string{"my-google-auth_token": idToken},
-			requestBody:    bytes.NewBuffer([]byte(fmt.Sprintf("{\"name\":\"projects/%s/locations/us\", \"entry\":\"projects/%s/locations/us/entryGroups/@bigquery/entries/bigquery.googleapis.com/projects/%s/datasets/%s\"}", DataplexProject, DataplexProject, DataplexProject, datasetName))),
-			wantStatusCode: 200,
-			expectResult:   true,
-			wantContentKey: "name",
+			Name:           "Success - Entry Found by Entry Group and ID",
+			Tool:           "my-dataplex-lookup-entry-tool",
+			RequestBody:    fmt.Sprintf("{\"name\":\"projects/%s/locations/us\", \"entryGroup\":\"@bigquery\", \"entryId\":\"bigquery.googleapis.com/projects/%s/datasets/%s\"}", DataplexProject, DataplexProject, datasetName),
+			WantResultKeys: []string{"name"},
 		},
 		{
-			name:           "Failure - Invalid Authorization Token",
-			api:            "http://127.0.0.1:5000/api/tool/my-auth-dataplex-lookup-entry-tool/invoke",
-			requestHeader:  map[string]string{"my-google-auth_token": "invalid_token"},
-			requestBody:    bytes.NewBuffer([]byte(fmt.Sprintf("{\"name\":\"projects/%s/locations/us\", \"entry\":\"projects/%s/locations/us/entryGroups/@bigquery/entries/bigquery.googleapis.com/projects/%s/datasets/%s\"}", DataplexProject, DataplexProject, DataplexProject, datasetName))),
-			wantStatusCode: 401,
-			expectResult:   false,
-			wantContentKey: "name",
+			Name:           "Failure - Both Entry and Name Provided",
+			Tool:           "my-dataplex-lookup-entry-tool",
+			RequestBody:    fmt.Sprintf("{\"name\":\"projects/%s/locations/us\", \"entry\":\"%s/%s\"}", DataplexProject, entryPrefix, datasetName),
+			WantStatusCode: http.StatusBadRequest,
+			WantError:      true,
 		},
 		{
-			name:           "Failure - Without Authorization Token",
-			api:            "http://127.0.0.1:5000/api/tool/my-auth-dataplex-lookup-entry-tool/invoke",
-			requestHeader:  map[string]string{},
-			requestBody:    bytes.NewBuffer([]byte(fmt.Sprintf("{\"name\":\"projects/%s/locations/us\", \"entry\":\"projects/%s/locations/us/entryGroups/@bigquery/entries/bigquery.googleapis.com/projects/%s/datasets/%s\"}", DataplexProject, DataplexProject, DataplexProject, datasetName))),
-			wantStatusCode: 401,
-			expectResult:   false,
-			wantContentKey: "name",
+			Name:               "Success - Entry Found with Authorization",
+			Tool:               "my-auth-dataplex-lookup-entry-tool",
+			IdTokenAuthService: "my-google-auth",
+			RequestBody:        datasetEntry,
+			WantResultKeys:     []string{"name"},
 		},
 		{
-			name:           "Failure - Entry Not Found or Permission Denied",
-			api:            "http://127.0.0.1:5000/api/tool/my-dataplex-lookup-entry-tool/invoke",
-			requestHeader:  map[string]string{},
-			requestBody:    bytes.NewBuffer([]byte(fmt.Sprintf("{\"name\":\"projects/%s/locations/us\", \"entry\":\"projects/%s/locations/us/entryGroups/@bigquery/entries/bigquery.googleapis.com/projects/%s/datasets/%s\"}", DataplexProject, DataplexProject, DataplexProject, "non-existent-dataset"))),
-			wantStatusCode: 400,
-			expectResult:   false,
+			Name:           "Failure - Invalid Authorization Token",
+			Tool:           "my-auth-dataplex-lookup-entry-tool",
+			RequestHeader:  map[string]string{"my-google-auth_token": "invalid_token"},
+			RequestBody:    datasetEntry,
+			WantStatusCode: http.StatusUnauthorized,
+			WantError:      true,
 		},
 		{
-			name:               "Success - Entry Found with Basic View",
-			api:                "http://127.0.0.1:5000/api/tool/my-dataplex-lookup-entry-tool/invoke",
-			requestHeader:      map[string]string{},
-			requestBody:        bytes.NewBuffer([]byte(fmt.Sprintf("{\"name\":\"projects/%s/locations/us\", \"entry\":\"projects/%s/locations/us/entryGroups/@bigquery/entries/bigquery.googleapis.com/projects/%s/datasets/%s/tables/%s\", \"view\": %d}", DataplexProject, DataplexProject, DataplexProject, datasetName, tableName, 1))),
-			wantStatusCode:     200,
-			expectResult:       true,
-			wantContentKey:     "name",
-			dontWantContentKey: "aspects",
+			Name:           "Failure - Without Authorization Token",
+			Tool:           "my-auth-dataplex-lookup-entry-tool",
+			RequestBody:    datasetEntry,
+			WantStatusCode: http.StatusUnauthorized,
+			WantError:      true,
 		},
 		{
-			name:           "Failure - Entry with Custom View without Aspect Types",
-			api:            "http://127.0.0.1:5000/api/tool/my-dataplex-lookup-entry-tool/invoke",
-			requestHeader:  map[string]string{},
-			requestBody:    bytes.NewBuffer([]byte(fmt.Sprintf("{\"name\":\"projects/%s/locations/us\", \"entry\":\"projects/%s/locations/us/entryGroups/@bigquery/entries/bigquery.googleapis.com/projects/%s/datasets/%s/tables/%s\", \"view\": %d}", DataplexProject, DataplexProject, DataplexProject, datasetName, tableName, 3))),
-			wantStatusCode: 400,
-			expectResult:   false,
+			Name:           "Failure - Entry Not Found or Permission Denied",
+			Tool:           "my-dataplex-lookup-entry-tool",
+			RequestBody:    fmt.Sprintf("{\"entry\":\"%s/%s\"}", entryPrefix, "non-existent-dataset"),
+			WantStatusCode: http.StatusBadRequest,
+			WantError:      true,
 		},
 		{
-			name:           "Success - Entry Found with only Schema Aspect",
-			api:            "http://127.0.0.1:5000/api/tool/my-dataplex-lookup-entry-tool/invoke",
-			requestHeader:  map[string]string{},
-			requestBody:    bytes.NewBuffer([]byte(fmt.Sprintf("{\"name\":\"projects/%s/locations/us\", \"entry\":\"projects/%s/locations/us/entryGroups/@bigquery/entries/bigquery.googleapis.com/projects/%s/datasets/%s/tables/%s\", \"aspectTypes\":[\"projects/dataplex-types/locations/global/aspectTypes/schema\"], \"view\": %d}", DataplexProject, DataplexProject, DataplexProject, datasetName, tableName, 3))),
-			wantStatusCode: 200,
-			expectResult:   true,
-			wantContentKey: "aspects",
-			aspectCheck:    true,
+			Name:           "Success - Entry Found with Basic View",
+			Tool:           "my-dataplex-lookup-entry-tool",
+			RequestBody:    fmt.Sprintf("{\"entry\":\"%s/%s/tables/%s\", \"view\": %q}", entryPrefix, datasetName, tableName, "BASIC"),
+			WantResultKeys: []string{"name"},
+			Check:          wantEntry("aspects", false),
+		},
+		{
+			Name:           "Failure - Entry with Custom View without Aspect Types",
+			Tool:           "my-dataplex-lookup-entry-tool",
+			RequestBody:    fmt.Sprintf("{\"entry\":\"%s/%s/tables/%s\", \"view\": %q}", entryPrefix, datasetName, tableName, "CUSTOM"),
+			WantStatusCode: http.StatusBadRequest,
+			WantError:      true,
+		},
+		{
+			Name:           "Success - Entry Found with only Schema Aspect",
+			Tool:           "my-dataplex-lookup-entry-tool",
+			RequestBody:    fmt.Sprintf("{\"entry\":\"%s/%s/tables/%s\", \"aspectTypes\":[\"projects/dataplex-types/locations/global/aspectTypes/schema\"], \"view\": %q}", entryPrefix, datasetName, tableName, "CUSTOM"),
+			WantResultKeys: []string{"aspects"},
+			Check:          wantEntry("", true),
+		},
+		{
+			Name:           "Success - Entry Found with lower case view",
+			Tool:           "my-dataplex-lookup-entry-tool",
+			RequestBody:    fmt.Sprintf("{\"entry\":\"%s/%s/tables/%s\", \"view\": %q}", entryPrefix, datasetName, tableName, "basic"),
+			WantResultKeys: []string{"name"},
+			Check:          wantEntry("aspects", false),
+		},
+		{
+			Name:           "Success - Entry Found with deprecated integer view",
+			Tool:           "my-dataplex-lookup-entry-tool",
+			RequestBody:    fmt.Sprintf("{\"entry\":\"%s/%s/tables/%s\", \"view\": %d}", entryPrefix, datasetName, tableName, 1),
+			WantResultKeys: []string{"name"},
+			Check:          wantEntry("aspects", false),
+		},
+		{
+			Name:           "Failure - Entry with unknown view",
+			Tool:           "my-dataplex-lookup-entry-tool",
+			RequestBody:    fmt.Sprintf("{\"entry\":\"%s/%s/tables/%s\", \"view\": %q}", entryPrefix, datasetName, tableName, "PARTIAL"),
+			WantStatusCode: http.StatusBadRequest,
+			WantError:      true,
 		},
 	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			req, err := http.NewRequest(http.MethodPost, tc.api, tc.requestBody)
-			if err != nil {
-				t.Fatalf("unable to create request: %s", err)
-			}
-			req.Header.Add("Content-type", "application/json")
-			for k, v := range tc.requestHeader {
-				req.Header.Add(k, v)
-			}
-			resp, err := http.DefaultClient.Do(req)
-			if err != nil {
-				t.Fatalf("unable to send request: %s", err)
-			}
-			defer resp.Body.Close()
-
-			if resp.StatusCode != tc.wantStatusCode {
-				bodyBytes, _ := io.ReadAll(resp.Body)
-				t.Fatalf("Response status code got %d, want %d\nResponse body: %s", resp.StatusCode, tc.wantStatusCode, string(bodyBytes))
-			}
-
-			var result map[string]interface{}
-			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-				t.Fatalf("Error parsing response body: %v", err)
-			}
-
-			if tc.expectResult {
-				resultStr, ok := result["result"].(string)
-				if !ok {
-					t.Fatalf("Expected 'result' field to be a string on success, got %T", result["result"])
-				}
-				if resultStr == "" || resultStr == "{}" || resultStr == "null" {
-					t.Fatal("Expected an entry, but got empty result")
-				}
-
-				var entry map[string]interface{}
-				if err := json.Unmarshal([]byte(resultStr), &entry); err != nil {
-					t.Fatalf("Error unmarshalling result string into entry map: %v", err)
-				}
-
-				if _, ok := entry[tc.wantContentKey]; !ok {
-					t.Fatalf("Expected entry to have key '%s', but it was not found in %v", tc.wantContentKey, entry)
-				}
-
-				if _, ok := entry[tc.dontWantContentKey]; ok {
-					t.Fatalf("Expected entry to not have key '%s', but it was found in %v", tc.dontWantContentKey, entry)
-				}
-
-				if tc.aspectCheck {
-					// Check length of aspects
-					aspects, ok := entry["aspects"].(map[string]interface{})
-					if !ok {
-						t.Fatalf("Expected 'aspects' to be a map, got %T", aspects)
-					}
-					if len(aspects) != 1 {
-						t.Fatalf("Expected exactly one aspect, but got %d", len(aspects))
-					}
-				}
-			} else { // Handle expected error response
-				_, ok := result["error"]
-				if !ok {
-					t.Fatalf("Expected 'error' field in response, got %v", result)
-				}
-			}
-		})
-	}
+	tests.RunToolInvokeTestCases(t, tcs)
 }
 
 func runDataplexSearchAspectTypesToolInvokeTest(t *testing.T, aspectTypeId string) {
-	idToken, err := tests.GetGoogleIdToken(tests.ClientId)
-	if err != nil {
-		t.Fatalf("error getting Google ID token: %s", err)
-	}
-
-	testCases := []struct {
-		name           string
-		api            string
-		requestHeader  map[string]string
-		requestBody    io.Reader
-		wantStatusCode int
-		expectResult   bool
-		wantContentKey string
-	}{
+	query := fmt.Sprintf("{\"query\":\"name:%s_aspectType\"}", aspectTypeId)
+	tcs := []tests.InvokeTestCase{
 		{
-			name:           "Success - Aspect Type Found",
-			api:            "http://127.0.0.1:5000/api/tool/my-dataplex-search-aspect-types-tool/invoke",
-			requestHeader:  map[string]string{},
-			requestBody:    bytes.NewBuffer([]byte(fmt.Sprintf("{\"query\":\"name:%s_aspectType\"}", aspectTypeId))),
-			wantStatusCode: 200,
-			expectResult:   true,
-			wantContentKey: "metadata_template",
+			Name:        "Success - Aspect Type Found",
+			Tool:        "my-dataplex-search-aspect-types-tool",
+			RequestBody: query,
+			Check:       wantEntries("metadata_template"),
 		},
 		{
-			name:           "Success - Aspect Type Found with Authorization",
-			api:            "http://127.0.0.1:5000/api/tool/my-auth-dataplex-search-aspect-types-tool/invoke",
-			requestHeader:  map[string]string{"my-google-auth_token": idToken},
-			requestBody:    bytes.NewBuffer([]byte(fmt.Sprintf("{\"query\":\"name:%s_aspectType\"}", aspectTypeId))),
-			wantStatusCode: 200,
-			expectResult:   true,
-			wantContentKey: "metadata_template",
+			Name:               "Success - Aspect Type Found with Authorization",
+			Tool:               "my-auth-dataplex-search-aspect-types-tool",
+			IdTokenAuthService: "my-google-auth",
+			RequestBody:        query,
+			Check:              wantEntries("metadata_template"),
 		},
 		{
-			name:           "Failure - Aspect Type Not Found",
-			api:            "http://127.0.0.1:5000/api/tool/my-dataplex-search-aspect-types-tool/invoke",
-			requestHeader:  map[string]string{},
-			requestBody:    bytes.NewBuffer([]byte(`"{\"query\":\"name:_aspectType\"}"`)),
-			wantStatusCode: 400,
-			expectResult:   false,
+			Name:           "Failure - Aspect Type Not Found",
+			Tool:           "my-dataplex-search-aspect-types-tool",
+			RequestBody:    `"{\"query\":\"name:_aspectType\"}"`,
+			WantStatusCode: http.StatusBadRequest,
 		},
 		{
-			name:           "Failure - Invalid Authorization Token",
-			api:            "http://127.0.0.1:5000/api/tool/my-auth-dataplex-search-aspect-types-tool/invoke",
-			requestHeader:  map[string]string{"my-google-auth_token": "invalid_token"},
-			requestBody:    bytes.NewBuffer([]byte(fmt.Sprintf("{\"query\":\"name:%s_aspectType\"}", aspectTypeId))),
-			wantStatusCode: 401,
-			expectResult:   false,
+			Name:           "Failure - Invalid Authorization Token",
+			Tool:           "my-auth-dataplex-search-aspect-types-tool",
+			RequestHeader:  map[string]string{"my-google-auth_token": "invalid_token"},
+			RequestBody:    query,
+			WantStatusCode: http.StatusUnauthorized,
 		},
 		{
-			name:           "Failure - No Authorization Token",
-			api:            "http://127.0.0.1:5000/api/tool/my-auth-dataplex-search-aspect-types-tool/invoke",
-			requestHeader:  map[string]string{},
-			requestBody:    bytes.NewBuffer([]byte(fmt.Sprintf("{\"query\":\"name:%s_aspectType\"}", aspectTypeId))),
-			wantStatusCode: 401,
-			expectResult:   false,
+			Name:           "Failure - No Authorization Token",
+			Tool:           "my-auth-dataplex-search-aspect-types-tool",
+			RequestBody:    query,
+			WantStatusCode: http.StatusUnauthorized,
 		},
 	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			req, err := http.NewRequest(http.MethodPost, tc.api, tc.requestBody)
-			if err != nil {
-				t.Fatalf("unable to create request: %s", err)
-			}
-			req.Header.Add("Content-type", "application/json")
-			for k, v := range tc.requestHeader {
-				req.Header.Add(k, v)
-			}
-			resp, err := http.DefaultClient.Do(req)
-			if err != nil {
-				t.Fatalf("unable to send request: %s", err)
-			}
-			defer resp.Body.Close()
-			if resp.StatusCode != tc.wantStatusCode {
-				t.Fatalf("response status code is not %d. It is %d", tc.wantStatusCode, resp.StatusCode)
-			}
-			var result map[string]interface{}
-			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-				t.Fatalf("error parsing response body: %s", err)
-			}
-			resultStr, ok := result["result"].(string)
-			if !ok {
-				if result["result"] == nil && !tc.expectResult {
-					return
-				}
-				t.Fatalf("expected 'result' field to be a string, got %T", result["result"])
-			}
-			if !tc.expectResult && (resultStr == "" || resultStr == "[]") {
-				return
-			}
-			var entries []interface{}
-			if err := json.Unmarshal([]byte(resultStr), &entries); err != nil {
-				t.Fatalf("error unmarshalling result string: %v", err)
-			}
-
-			if tc.expectResult {
-				if len(entries) != 1 {
-					t.Fatalf("expected exactly one entry, but got %d", len(entries))
-				}
-				entry, ok := entries[0].(map[string]interface{})
-				if !ok {
-					t.Fatalf("expected entry to be a map, got %T", entries[0])
-				}
-				if _, ok := entry[tc.wantContentKey]; !ok {
-					t.Fatalf("expected entry to have key '%s', but it was not found in %v", tc.wantContentKey, entry)
-				}
-			} else {
-				if len(entries) != 0 {
-					t.Fatalf("expected 0 entries, but got %d", len(entries))
-				}
-			}
-		})
-	}
+	tests.RunToolInvokeTestCases(t, tcs)
 }
@@ -116,7 +116,7 @@ func TestFirestoreToolEndpoints(t *testing.T) {
 
 	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
-	out, err := testutils.WaitForString(waitCtx, regexp.MustCompile(`Server ready to serve`), cmd.Out)
+	_, out, err := testutils.WaitForServerReady(waitCtx, cmd.Out)
 	if err != nil {
 		t.Logf("toolbox command logs: \n%s", out)
 		t.Fatalf("toolbox didn't start successfully: %s", err)
@@ -134,6 +134,7 @@ func TestFirestoreToolEndpoints(t *testing.T) {
 	runFirestoreQueryTest(t, testCollectionName)
 	runFirestoreQuerySelectArrayTest(t, testCollectionName)
 	runFirestoreListCollectionsTest(t, testCollectionName, testSubCollectionName, docPath1)
+	runFirestoreListCollectionsPaginationTest(t, ctx, client)
 	runFirestoreAddDocumentsTest(t, testCollectionName)
 	runFirestoreUpdateDocumentTest(t, testCollectionName, testDocID1)
 	runFirestoreDeleteDocumentsTest(t, docPath3)
@@ -168,6 +169,7 @@ func runFirestoreToolGetTest(t *testing.T) {
 								"authSources": []any{},
 							},
 							"authSources": []any{},
+							"examples":    []any{[]any{"example"}},
 						},
 					},
 					"authRequired": []any{},
@@ -559,6 +561,12 @@ func getFirestoreToolsConfig(sourceConfig map[string]any) map[string]any {
 			"source":      "my-instance",
 			"description": "Delete documents from Firestore",
 		},
+		"firestore-delete-docs-fail-fast": map[string]any{
+			"kind":        "firestore-delete-documents",
+			"source":      "my-instance",
+			"description": "Delete documents from Firestore in a single transaction",
+			"failFast":    true,
+		},
 		"firestore-query-coll": map[string]any{
 			"kind":        "firestore-query-collection",
 			"source":      "my-instance",
@@ -1297,6 +1305,19 @@ func runFirestoreListCollectionsTest(t *testing.T, collectionName, subCollection
 			want:        `[]`, // Empty array for no collections
 			isErr:       false,
 		},
+		{
+			name:        "list collections with prefix",
+			api:         "http://127.0.0.1:5000/api/tool/firestore-list-colls/invoke",
+			requestBody: bytes.NewBuffer([]byte(fmt.Sprintf(`{"prefix": "%s", "limit": 1}`, collectionName))),
+			want:        collectionName,
+			isErr:       false,
+		},
+		{
+			name:        "list collections with invalid page token",
+			api:         "http://127.0.0.1:5000/api/tool/firestore-list-colls/invoke",
+			requestBody: bytes.NewBuffer([]byte(`{"pageToken": "not a token!"}`)),
+			isErr:       true,
+		},
 	}
 
 	for _, tc := range invokeTcs {
@@ -1339,6 +1360,88 @@ func runFirestoreListCollectionsTest(t *testing.T, collectionName, subCollection
 	}
 }
 
+// runFirestoreListCollectionsPaginationTest lists collections sharing a
+// prefix in pages, checking the subcollections of the first one.
+func runFirestoreListCollectionsPaginationTest(t *testing.T, ctx context.Context, client *firestoreapi.Client) {
+	prefix := fmt.Sprintf("page_collection_%s_", strings.ReplaceAll(uuid.New().String(), "-", ""))
+	var want []string
+	for i := range 5 {
+		collectionName := fmt.Sprintf("%s%d", prefix, i)
+		want = append(want, collectionName)
+		if _, err := client.Collection(collectionName).Doc("doc").Set(ctx, map[string]any{"value": i}); err != nil {
+			t.Fatalf("failed to create test document: %v", err)
+		}
+	}
+	subDoc := client.Collection(want[0]).Doc("doc").Collection("sub").Doc("doc")
+	if _, err := subDoc.Set(ctx, map[string]any{"value": 0}); err != nil {
+		t.Fatalf("failed to create test document: %v", err)
+	}
+	defer func() {
+		_, _ = subDoc.Delete(ctx)
+		for _, collectionName := range want {
+			_, _ = client.Collection(collectionName).Doc("doc").Delete(ctx)
+		}
+	}()
+
+	type collection struct {
+		ID                string `json:"id"`
+		HasSubcollections *bool  `json:"hasSubcollections"`
+	}
+	var got []collection
+	pageToken := ""
+	for page := 0; page < 3; page++ {
+		reqBody, err := json.Marshal(map[string]any{"prefix": prefix, "limit": 2, "pageToken": pageToken, "checkSubcollections": true})
+		if err != nil {
+			t.Fatalf("unable to marshal request: %s", err)
+		}
+		resp, body := tests.RunRequest(t, http.MethodPost, "http://127.0.0.1:5000/api/tool/firestore-list-colls/invoke", bytes.NewBuffer(reqBody), nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, body)
+		}
+		var wrapper struct {
+			Result string `json:"result"`
+		}
+		if err := json.Unmarshal(body, &wrapper); err != nil {
+			t.Fatalf("error parsing response body: %s", err)
+		}
+		var result struct {
+			Collections   []collection `json:"collections"`
+			NextPageToken string       `json:"nextPageToken"`
+		}
+		if err := json.Unmarshal([]byte(wrapper.Result), &result); err != nil {
+			t.Fatalf("error parsing result: %s", err)
+		}
+		got = append(got, result.Collections...)
+		if (page == 2) != (result.NextPageToken == "") {
+			t.Fatalf("unexpected next page token %q on page %d: %s", result.NextPageToken, page, wrapper.Result)
+		}
+		if page == 1 {
+			// the token is rejected for another prefix
+			reqBody, err := json.Marshal(map[string]any{"prefix": "other", "pageToken": result.NextPageToken})
+			if err != nil {
+				t.Fatalf("unable to marshal request: %s", err)
+			}
+			resp, body := tests.RunRequest(t, http.MethodPost, "http://127.0.0.1:5000/api/tool/firestore-list-colls/invoke", bytes.NewBuffer(reqBody), nil)
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, http.StatusBadRequest, body)
+			}
+		}
+		pageToken = result.NextPageToken
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d collections, want %d: %v", len(got), len(want), got)
+	}
+	for i, c := range got {
+		if c.ID != want[i] {
+			t.Fatalf("unexpected collection %d: got %q, want %q", i, c.ID, want[i])
+		}
+		if c.HasSubcollections == nil || *c.HasSubcollections != (i == 0) {
+			t.Fatalf("unexpected hasSubcollections of %q: %v", c.ID, c.HasSubcollections)
+		}
+	}
+}
+
 func runFirestoreDeleteDocumentsTest(t *testing.T, docPath string) {
 	invokeTcs := []struct {
 		name        string
@@ -1351,14 +1454,21 @@ func runFirestoreDeleteDocumentsTest(t *testing.T, docPath string) {
 			name:        "delete single document",
 			api:         "http://127.0.0.1:5000/api/tool/firestore-delete-docs/invoke",
 			requestBody: bytes.NewBuffer([]byte(fmt.Sprintf(`{"documentPaths": ["%s"]}`, docPath))),
-			want:        `"success":true`,
+			want:        `"succeeded":1,"failed":0`,
 			isErr:       false,
 		},
 		{
 			name:        "delete non-existent document",
 			api:         "http://127.0.0.1:5000/api/tool/firestore-delete-docs/invoke",
 			requestBody: bytes.NewBuffer([]byte(`{"documentPaths": ["non-existent-collection/non-existent-doc"]}`)),
-			want:        `"success":true`, // Firestore delete succeeds even if doc doesn't exist
+			want:        `"succeeded":1,"failed":0`, // Firestore delete succeeds even if doc doesn't exist
+			isErr:       false,
+		},
+		{
+			name:        "delete documents atomically",
+			api:         "http://127.0.0.1:5000/api/tool/firestore-delete-docs-fail-fast/invoke",
+			requestBody: bytes.NewBuffer([]byte(`{"documentPaths": ["non-existent-collection/doc-a", "non-existent-collection/doc-b"]}`)),
+			want:        `"succeeded":2,"failed":0`,
 			isErr:       false,
 		},
 		{
@@ -0,0 +1,253 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/tests"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+var (
+	KafkaSourceKind = "kafka"
+	// KafkaBrokers is a comma-separated list of brokers, such as those of a
+	// single-broker test container.
+	KafkaBrokers = os.Getenv("KAFKA_BROKERS")
+)
+
+func getKafkaVars(t *testing.T) map[string]any {
+	if KafkaBrokers == "" {
+		t.Fatal("'KAFKA_BROKERS' not set")
+	}
+	return map[string]any{
+		"kind":     KafkaSourceKind,
+		"brokers":  strings.Split(KafkaBrokers, ","),
+		"clientId": "toolbox-integration-test",
+	}
+}
+
+func newAdminClient(t *testing.T) *kadm.Client {
+	client, err := kgo.NewClient(kgo.SeedBrokers(strings.Split(KafkaBrokers, ",")...))
+	if err != nil {
+		t.Fatalf("unable to create Kafka client: %s", err)
+	}
+	adm := kadm.NewClient(client)
+	t.Cleanup(adm.Close)
+	return adm
+}
+
+func setupTopics(t *testing.T, ctx context.Context, adm *kadm.Client, topics ...string) {
+	if _, err := adm.CreateTopics(ctx, 1, 1, nil, topics...); err != nil {
+		t.Fatalf("unable to create topics: %s", err)
+	}
+	t.Cleanup(func() {
+		if _, err := adm.DeleteTopics(context.Background(), topics...); err != nil {
+			t.Errorf("Teardown failed: %s", err)
+		}
+	})
+}
+
+func listGroups(t *testing.T, ctx context.Context, adm *kadm.Client) []string {
+	groups, err := adm.ListGroups(ctx)
+	if err != nil {
+		t.Fatalf("unable to list consumer groups: %s", err)
+	}
+	names := groups.Groups()
+	slices.Sort(names)
+	return names
+}
+
+func TestKafkaToolEndpoints(t *testing.T) {
+	sourceConfig := getKafkaVars(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	var args []string
+
+	suffix := strings.ReplaceAll(uuid.New().String(), "-", "")
+	commandsTopic := "commands_" + suffix
+	emptyTopic := "empty_" + suffix
+	adm := newAdminClient(t)
+	setupTopics(t, ctx, adm, commandsTopic, emptyTopic)
+	groupsBefore := listGroups(t, ctx, adm)
+
+	toolsFile := map[string]any{
+		"sources": map[string]any{
+			"my-kafka-instance": sourceConfig,
+		},
+		"tools": map[string]any{
+			"send-command": map[string]any{
+				"kind":        "kafka-produce",
+				"source":      "my-kafka-instance",
+				"description": "Send a command.",
+				"topics":      []string{commandsTopic},
+			},
+			"peek-commands": map[string]any{
+				"kind":        "kafka-consume-latest",
+				"source":      "my-kafka-instance",
+				"description": "Peek at the latest commands.",
+				"topics":      []string{commandsTopic, emptyTopic},
+				"maxWait":     "5s",
+			},
+		},
+	}
+	cmd, cleanup, err := tests.StartCmd(ctx, toolsFile, args...)
+	if err != nil {
+		t.Fatalf("command initialization returned an error: %s", err)
+	}
+	defer cleanup()
+
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	_, out, err := testutils.WaitForServerReady(waitCtx, cmd.Out)
+	if err != nil {
+		t.Logf("toolbox command logs: \n%s", out)
+		t.Fatalf("toolbox didn't start successfully: %s", err)
+	}
+
+	runKafkaProduceTest(t, commandsTopic)
+	runKafkaConsumeLatestTest(t, commandsTopic, emptyTopic)
+
+	if diff := listGroups(t, ctx, adm); !slices.Equal(groupsBefore, diff) {
+		t.Fatalf("consuming must not join a consumer group: got groups %q, want %q", diff, groupsBefore)
+	}
+}
+
+// invoke invokes a tool and returns the status code and its decoded result.
+func invoke(t *testing.T, tool string, body map[string]any) (int, any, string) {
+	b, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("unable to marshal request body: %s", err)
+	}
+	url := fmt.Sprintf("http://127.0.0.1:5000/api/tool/%s/invoke", tool)
+	resp, respBody := tests.RunRequest(t, http.MethodPost, url, bytes.NewBuffer(b), nil)
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, nil, string(respBody)
+	}
+	var envelope struct {
+		Result string `json:"result"`
+	}
+	if err := json.Unmarshal(respBody, &envelope); err != nil {
+		t.Fatalf("unable to decode response %q: %s", respBody, err)
+	}
+	var result any
+	if err := json.Unmarshal([]byte(envelope.Result), &result); err != nil {
+		t.Fatalf("unable to decode result %q: %s", envelope.Result, err)
+	}
+	return resp.StatusCode, result, string(respBody)
+}
+
+func runKafkaProduceTest(t *testing.T, topic string) {
+	t.Run("produce to an allowed topic", func(t *testing.T) {
+		for i, value := range []string{"start", "pause", "stop"} {
+			code, got, body := invoke(t, "send-command", map[string]any{
+				"topic":   topic,
+				"key":     "device-1",
+				"value":   value,
+				"headers": map[string]any{"issued-by": "agent"},
+			})
+			if code != http.StatusOK {
+				t.Fatalf("unexpected status code %d: %s", code, body)
+			}
+			want := map[string]any{"topic": topic, "partition": 0.0, "offset": float64(i)}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Fatalf("unexpected result: diff %v", diff)
+			}
+		}
+	})
+	t.Run("produce to a topic that is not allowed", func(t *testing.T) {
+		code, _, body := invoke(t, "send-command", map[string]any{"topic": "payments", "value": "refund"})
+		if code != http.StatusBadRequest {
+			t.Fatalf("unexpected status code %d: %s", code, body)
+		}
+		if !strings.Contains(body, `topic \"payments\" is not allowed`) {
+			t.Fatalf("unexpected error: %s", body)
+		}
+	})
+}
+
+func runKafkaConsumeLatestTest(t *testing.T, topic, emptyTopic string) {
+	t.Run("consume the latest messages", func(t *testing.T) {
+		code, got, body := invoke(t, "peek-commands", map[string]any{"topic": topic, "maxMessages": 2})
+		if code != http.StatusOK {
+			t.Fatalf("unexpected status code %d: %s", code, body)
+		}
+		messages, ok := got.([]any)
+		if !ok || len(messages) != 2 {
+			t.Fatalf("expected 2 messages, got %v", got)
+		}
+		for i, want := range []string{"pause", "stop"} {
+			m := messages[i].(map[string]any)
+			if m["value"] != want || m["key"] != "device-1" || m["valueEncoding"] != "utf-8" {
+				t.Fatalf("unexpected message %d: got %v, want value %q", i, m, want)
+			}
+			headers := []any{map[string]any{"key": "issued-by", "value": "agent", "valueEncoding": "utf-8"}}
+			if diff := cmp.Diff(headers, m["headers"]); diff != "" {
+				t.Fatalf("unexpected headers: diff %v", diff)
+			}
+		}
+	})
+	t.Run("consume since a timestamp", func(t *testing.T) {
+		since := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+		code, got, body := invoke(t, "peek-commands", map[string]any{"topic": topic, "maxMessages": 1, "since": since})
+		if code != http.StatusOK {
+			t.Fatalf("unexpected status code %d: %s", code, body)
+		}
+		messages, ok := got.([]any)
+		if !ok || len(messages) != 1 || messages[0].(map[string]any)["value"] != "start" {
+			t.Fatalf("expected the first message, got %v", got)
+		}
+	})
+	t.Run("bounded wait on an empty topic", func(t *testing.T) {
+		start := time.Now()
+		code, got, body := invoke(t, "peek-commands", map[string]any{"topic": emptyTopic, "maxWaitSeconds": 2})
+		elapsed := time.Since(start)
+		if code != http.StatusOK {
+			t.Fatalf("unexpected status code %d: %s", code, body)
+		}
+		if messages, ok := got.([]any); !ok || len(messages) != 0 {
+			t.Fatalf("expected no messages, got %v", got)
+		}
+		if elapsed < 2*time.Second || elapsed > 4*time.Second {
+			t.Fatalf("expected the invocation to wait about 2s, took %s", elapsed)
+		}
+	})
+	t.Run("wait longer than allowed", func(t *testing.T) {
+		code, _, body := invoke(t, "peek-commands", map[string]any{"topic": emptyTopic, "maxWaitSeconds": 60})
+		if code != http.StatusBadRequest {
+			t.Fatalf("unexpected status code %d: %s", code, body)
+		}
+	})
+	t.Run("consume a topic that is not allowed", func(t *testing.T) {
+		code, _, body := invoke(t, "peek-commands", map[string]any{"topic": "payments"})
+		if code != http.StatusBadRequest {
+			t.Fatalf("unexpected status code %d: %s", code, body)
+		}
+	})
+}
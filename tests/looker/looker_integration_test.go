@@ -21,7 +21,6 @@ import (
 	"fmt"
 	"net/http"
 	"os"
-	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -235,7 +234,7 @@ func TestLooker(t *testing.T) {
 
 	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
-	out, err := testutils.WaitForString(waitCtx, regexp.MustCompile(`Server ready to serve`), cmd.Out)
+	_, out, err := testutils.WaitForServerReady(waitCtx, cmd.Out)
 	if err != nil {
 		t.Logf("toolbox command logs: \n%s", out)
 		t.Fatalf("toolbox didn't start successfully: %s", err)
@@ -259,6 +258,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The model containing the explores.",
+						"examples":    []any{"example"},
 						"name":        "model",
 						"required":    true,
 						"type":        "string",
@@ -276,6 +276,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The model containing the explore.",
+						"examples":    []any{"example"},
 						"name":        "model",
 						"required":    true,
 						"type":        "string",
@@ -283,6 +284,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The explore containing the fields.",
+						"examples":    []any{"example"},
 						"name":        "explore",
 						"required":    true,
 						"type":        "string",
@@ -300,6 +302,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The model containing the explore.",
+						"examples":    []any{"example"},
 						"name":        "model",
 						"required":    true,
 						"type":        "string",
@@ -307,6 +310,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The explore containing the fields.",
+						"examples":    []any{"example"},
 						"name":        "explore",
 						"required":    true,
 						"type":        "string",
@@ -324,6 +328,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The model containing the explore.",
+						"examples":    []any{"example"},
 						"name":        "model",
 						"required":    true,
 						"type":        "string",
@@ -331,6 +336,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The explore containing the fields.",
+						"examples":    []any{"example"},
 						"name":        "explore",
 						"required":    true,
 						"type":        "string",
@@ -348,6 +354,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The model containing the explore.",
+						"examples":    []any{"example"},
 						"name":        "model",
 						"required":    true,
 						"type":        "string",
@@ -355,6 +362,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The explore containing the fields.",
+						"examples":    []any{"example"},
 						"name":        "explore",
 						"required":    true,
 						"type":        "string",
@@ -372,6 +380,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The model containing the explore.",
+						"examples":    []any{"example"},
 						"name":        "model",
 						"required":    true,
 						"type":        "string",
@@ -379,6 +388,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The explore to be queried.",
+						"examples":    []any{"example"},
 						"name":        "explore",
 						"required":    true,
 						"type":        "string",
@@ -386,6 +396,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The fields to be retrieved.",
+						"examples":    []any{[]any{"example"}},
 						"items": map[string]any{
 							"authSources": []any{},
 							"description": "A field to be returned in the query",
@@ -400,7 +411,9 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"additionalProperties": true,
 						"authSources":          []any{},
+						"default":              map[string]any{},
 						"description":          "The filters for the query",
+						"examples":             []any{map[string]any{"key": "value"}},
 						"name":                 "filters",
 						"required":             false,
 						"type":                 "object",
@@ -408,6 +421,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The query pivots (must be included in fields as well).",
+						"examples":    []any{[]any{"example"}},
 						"items": map[string]any{
 							"authSources": []any{},
 							"description": "A field to be used as a pivot in the query",
@@ -422,6 +436,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The sorts like \"field.id desc 0\".",
+						"examples":    []any{[]any{"example"}},
 						"items": map[string]any{
 							"authSources": []any{},
 							"description": "A field to be used as a sort in the query",
@@ -435,7 +450,9 @@ func TestLooker(t *testing.T) {
 					},
 					map[string]any{
 						"authSources": []any{},
+						"default":     float64(500),
 						"description": "The row limit.",
+						"examples":    []any{float64(500)},
 						"name":        "limit",
 						"required":    false,
 						"type":        "integer",
@@ -443,6 +460,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The query timezone.",
+						"examples":    []any{"example"},
 						"name":        "tz",
 						"required":    false,
 						"type":        "string",
@@ -460,6 +478,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The model containing the explore.",
+						"examples":    []any{"example"},
 						"name":        "model",
 						"required":    true,
 						"type":        "string",
@@ -467,6 +486,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The explore to be queried.",
+						"examples":    []any{"example"},
 						"name":        "explore",
 						"required":    true,
 						"type":        "string",
@@ -474,6 +494,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The fields to be retrieved.",
+						"examples":    []any{[]any{"example"}},
 						"items": map[string]any{
 							"authSources": []any{},
 							"description": "A field to be returned in the query",
@@ -488,7 +509,9 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"additionalProperties": true,
 						"authSources":          []any{},
+						"default":              map[string]any{},
 						"description":          "The filters for the query",
+						"examples":             []any{map[string]any{"key": "value"}},
 						"name":                 "filters",
 						"required":             false,
 						"type":                 "object",
@@ -496,6 +519,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The query pivots (must be included in fields as well).",
+						"examples":    []any{[]any{"example"}},
 						"items": map[string]any{
 							"authSources": []any{},
 							"description": "A field to be used as a pivot in the query",
@@ -510,6 +534,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The sorts like \"field.id desc 0\".",
+						"examples":    []any{[]any{"example"}},
 						"items": map[string]any{
 							"authSources": []any{},
 							"description": "A field to be used as a sort in the query",
@@ -523,7 +548,9 @@ func TestLooker(t *testing.T) {
 					},
 					map[string]any{
 						"authSources": []any{},
+						"default":     float64(500),
 						"description": "The row limit.",
+						"examples":    []any{float64(500)},
 						"name":        "limit",
 						"required":    false,
 						"type":        "integer",
@@ -531,6 +558,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The query timezone.",
+						"examples":    []any{"example"},
 						"name":        "tz",
 						"required":    false,
 						"type":        "string",
@@ -548,6 +576,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The model containing the explore.",
+						"examples":    []any{"example"},
 						"name":        "model",
 						"required":    true,
 						"type":        "string",
@@ -555,6 +584,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The explore to be queried.",
+						"examples":    []any{"example"},
 						"name":        "explore",
 						"required":    true,
 						"type":        "string",
@@ -562,6 +592,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The fields to be retrieved.",
+						"examples":    []any{[]any{"example"}},
 						"items": map[string]any{
 							"authSources": []any{},
 							"description": "A field to be returned in the query",
@@ -576,7 +607,9 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"additionalProperties": true,
 						"authSources":          []any{},
+						"default":              map[string]any{},
 						"description":          "The filters for the query",
+						"examples":             []any{map[string]any{"key": "value"}},
 						"name":                 "filters",
 						"required":             false,
 						"type":                 "object",
@@ -584,6 +617,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The query pivots (must be included in fields as well).",
+						"examples":    []any{[]any{"example"}},
 						"items": map[string]any{
 							"authSources": []any{},
 							"description": "A field to be used as a pivot in the query",
@@ -598,6 +632,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The sorts like \"field.id desc 0\".",
+						"examples":    []any{[]any{"example"}},
 						"items": map[string]any{
 							"authSources": []any{},
 							"description": "A field to be used as a sort in the query",
@@ -611,7 +646,9 @@ func TestLooker(t *testing.T) {
 					},
 					map[string]any{
 						"authSources": []any{},
+						"default":     float64(500),
 						"description": "The row limit.",
+						"examples":    []any{float64(500)},
 						"name":        "limit",
 						"required":    false,
 						"type":        "integer",
@@ -619,6 +656,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The query timezone.",
+						"examples":    []any{"example"},
 						"name":        "tz",
 						"required":    false,
 						"type":        "string",
@@ -626,7 +664,9 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"additionalProperties": true,
 						"authSources":          []any{},
+						"default":              map[string]any{},
 						"description":          "The visualization config for the query",
+						"examples":             []any{map[string]any{"key": "value"}},
 						"name":                 "vis_config",
 						"required":             false,
 						"type":                 "object",
@@ -643,28 +683,36 @@ func TestLooker(t *testing.T) {
 				"parameters": []any{
 					map[string]any{
 						"authSources": []any{},
+						"default":     "",
 						"description": "The title of the look.",
+						"examples":    []any{"example"},
 						"name":        "title",
 						"required":    false,
 						"type":        "string",
 					},
 					map[string]any{
 						"authSources": []any{},
+						"default":     "",
 						"description": "The description of the look.",
+						"examples":    []any{"example"},
 						"name":        "desc",
 						"required":    false,
 						"type":        "string",
 					},
 					map[string]any{
 						"authSources": []any{},
+						"default":     float64(100),
 						"description": "The number of looks to fetch. Default 100",
+						"examples":    []any{float64(100)},
 						"name":        "limit",
 						"required":    false,
 						"type":        "integer",
 					},
 					map[string]any{
 						"authSources": []any{},
+						"default":     float64(0),
 						"description": "The number of looks to skip before fetching. Default 0",
+						"examples":    []any{float64(0)},
 						"name":        "offset",
 						"required":    false,
 						"type":        "integer",
@@ -681,28 +729,45 @@ func TestLooker(t *testing.T) {
 				"parameters": []any{
 					map[string]any{
 						"authSources": []any{},
+						"default":     "",
 						"description": "The title of the dashboard.",
+						"examples":    []any{"example"},
 						"name":        "title",
 						"required":    false,
 						"type":        "string",
 					},
 					map[string]any{
 						"authSources": []any{},
+						"default":     "",
 						"description": "The description of the dashboard.",
+						"examples":    []any{"example"},
 						"name":        "desc",
 						"required":    false,
 						"type":        "string",
 					},
 					map[string]any{
 						"authSources": []any{},
+						"default":     "",
+						"description": "The id of the folder containing the dashboards.",
+						"examples":    []any{"example"},
+						"name":        "folder_id",
+						"required":    false,
+						"type":        "string",
+					},
+					map[string]any{
+						"authSources": []any{},
+						"default":     float64(100),
 						"description": "The number of dashboards to fetch. Default 100",
+						"examples":    []any{float64(100)},
 						"name":        "limit",
 						"required":    false,
 						"type":        "integer",
 					},
 					map[string]any{
 						"authSources": []any{},
+						"default":     float64(0),
 						"description": "The number of dashboards to skip before fetching. Default 0",
+						"examples":    []any{float64(0)},
 						"name":        "offset",
 						"required":    false,
 						"type":        "integer",
@@ -720,6 +785,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The user's question, potentially including conversation history and system instructions for context.",
+						"examples":    []any{"example"},
 						"name":        "user_query_with_context",
 						"required":    true,
 						"type":        "string",
@@ -727,6 +793,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "An Array of at least one and up to 5 explore references like [{'model': 'MODEL_NAME', 'explore': 'EXPLORE_NAME'}]",
+						"examples":    []any{[]any{map[string]any{"key": "value"}}},
 						"items": map[string]any{
 							"additionalProperties": true,
 							"authSources":          []any{},
@@ -752,6 +819,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The health check to run. Can be either: `check_db_connections`, `check_dashboard_performance`,`check_dashboard_errors`,`check_explore_performance`,`check_schedule_failures`, or `check_legacy_features`",
+						"examples":    []any{"example"},
 						"name":        "action",
 						"required":    true,
 						"type":        "string",
@@ -769,6 +837,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The analysis to run. Can be 'projects', 'models', or 'explores'.",
+						"examples":    []any{"example"},
 						"name":        "action",
 						"required":    true,
 						"type":        "string",
@@ -776,6 +845,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The Looker project to analyze (optional).",
+						"examples":    []any{"example"},
 						"name":        "project",
 						"required":    false,
 						"type":        "string",
@@ -783,6 +853,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The Looker model to analyze (optional).",
+						"examples":    []any{"example"},
 						"name":        "model",
 						"required":    false,
 						"type":        "string",
@@ -790,20 +861,25 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The Looker explore to analyze (optional).",
+						"examples":    []any{"example"},
 						"name":        "explore",
 						"required":    false,
 						"type":        "string",
 					},
 					map[string]any{
 						"authSources": []any{},
+						"default":     float64(90),
 						"description": "The timeframe in days to analyze.",
+						"examples":    []any{float64(90)},
 						"name":        "timeframe",
 						"required":    false,
 						"type":        "integer",
 					},
 					map[string]any{
 						"authSources": []any{},
+						"default":     float64(0),
 						"description": "The minimum number of queries for a model or explore to be considered used.",
+						"examples":    []any{float64(0)},
 						"name":        "min_queries",
 						"required":    false,
 						"type":        "integer",
@@ -821,41 +897,52 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The vacuum action to run. Can be 'models', or 'explores'.",
+						"examples":    []any{"example"},
 						"name":        "action",
 						"required":    true,
 						"type":        "string",
 					},
 					map[string]any{
 						"authSources": []any{},
+						"default":     "",
 						"description": "The Looker project to vacuum (optional).",
+						"examples":    []any{"example"},
 						"name":        "project",
 						"required":    false,
 						"type":        "string",
 					},
 					map[string]any{
 						"authSources": []any{},
+						"default":     "",
 						"description": "The Looker model to vacuum (optional).",
+						"examples":    []any{"example"},
 						"name":        "model",
 						"required":    false,
 						"type":        "string",
 					},
 					map[string]any{
 						"authSources": []any{},
+						"default":     "",
 						"description": "The Looker explore to vacuum (optional).",
+						"examples":    []any{"example"},
 						"name":        "explore",
 						"required":    false,
 						"type":        "string",
 					},
 					map[string]any{
 						"authSources": []any{},
+						"default":     float64(90),
 						"description": "The timeframe in days to analyze.",
+						"examples":    []any{float64(90)},
 						"name":        "timeframe",
 						"required":    false,
 						"type":        "integer",
 					},
 					map[string]any{
 						"authSources": []any{},
+						"default":     float64(0),
 						"description": "The minimum number of queries for a model or explore to be considered used.",
+						"examples":    []any{float64(1)},
 						"name":        "min_queries",
 						"required":    false,
 						"type":        "integer",
@@ -872,7 +959,9 @@ func TestLooker(t *testing.T) {
 				"parameters": []any{
 					map[string]any{
 						"authSources": []any{},
+						"default":     true,
 						"description": "Whether to set Dev Mode.",
+						"examples":    []any{true},
 						"name":        "devMode",
 						"required":    false,
 						"type":        "boolean",
@@ -899,6 +988,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The id of the project containing the files",
+						"examples":    []any{"example"},
 						"name":        "project_id",
 						"required":    true,
 						"type":        "string",
@@ -916,6 +1006,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The id of the project containing the files",
+						"examples":    []any{"example"},
 						"name":        "project_id",
 						"required":    true,
 						"type":        "string",
@@ -923,6 +1014,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The path of the file within the project",
+						"examples":    []any{"example"},
 						"name":        "file_path",
 						"required":    true,
 						"type":        "string",
@@ -940,6 +1032,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The id of the project containing the files",
+						"examples":    []any{"example"},
 						"name":        "project_id",
 						"required":    true,
 						"type":        "string",
@@ -947,6 +1040,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The path of the file within the project",
+						"examples":    []any{"example"},
 						"name":        "file_path",
 						"required":    true,
 						"type":        "string",
@@ -954,6 +1048,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The content of the file",
+						"examples":    []any{"example"},
 						"name":        "file_content",
 						"required":    true,
 						"type":        "string",
@@ -971,6 +1066,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The id of the project containing the files",
+						"examples":    []any{"example"},
 						"name":        "project_id",
 						"required":    true,
 						"type":        "string",
@@ -978,6 +1074,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The path of the file within the project",
+						"examples":    []any{"example"},
 						"name":        "file_path",
 						"required":    true,
 						"type":        "string",
@@ -985,6 +1082,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The content of the file",
+						"examples":    []any{"example"},
 						"name":        "file_content",
 						"required":    true,
 						"type":        "string",
@@ -1002,6 +1100,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The id of the project containing the files",
+						"examples":    []any{"example"},
 						"name":        "project_id",
 						"required":    true,
 						"type":        "string",
@@ -1009,6 +1108,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The path of the file within the project",
+						"examples":    []any{"example"},
 						"name":        "file_path",
 						"required":    true,
 						"type":        "string",
@@ -1035,6 +1135,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The connection containing the schemas.",
+						"examples":    []any{"example"},
 						"name":        "conn",
 						"required":    true,
 						"type":        "string",
@@ -1042,6 +1143,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The optional database to search",
+						"examples":    []any{"example"},
 						"name":        "db",
 						"required":    false,
 						"type":        "string",
@@ -1059,6 +1161,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The connection containing the databases.",
+						"examples":    []any{"example"},
 						"name":        "conn",
 						"required":    true,
 						"type":        "string",
@@ -1076,6 +1179,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The connection containing the tables.",
+						"examples":    []any{"example"},
 						"name":        "conn",
 						"required":    true,
 						"type":        "string",
@@ -1083,6 +1187,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The optional database to search",
+						"examples":    []any{"example"},
 						"name":        "db",
 						"required":    false,
 						"type":        "string",
@@ -1090,6 +1195,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The schema containing the tables.",
+						"examples":    []any{"example"},
 						"name":        "schema",
 						"required":    true,
 						"type":        "string",
@@ -1107,6 +1213,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The connection containing the tables.",
+						"examples":    []any{"example"},
 						"name":        "conn",
 						"required":    true,
 						"type":        "string",
@@ -1114,6 +1221,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The optional database to search",
+						"examples":    []any{"example"},
 						"name":        "db",
 						"required":    false,
 						"type":        "string",
@@ -1121,6 +1229,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "The schema containing the tables.",
+						"examples":    []any{"example"},
 						"name":        "schema",
 						"required":    true,
 						"type":        "string",
@@ -1128,6 +1237,7 @@ func TestLooker(t *testing.T) {
 					map[string]any{
 						"authSources": []any{},
 						"description": "A comma separated list of tables containing the columns.",
+						"examples":    []any{"example"},
 						"name":        "tables",
 						"required":    true,
 						"type":        "string",
@@ -0,0 +1,352 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// McpProtocolVersion describes how a version of the MCP protocol is spoken
+// over streamable HTTP, and the shapes of its responses that differ from the
+// other versions.
+type McpProtocolVersion struct {
+	Version string
+	// SessionHeader is whether initialize returns an `Mcp-Session-Id` header,
+	// which the later requests carry.
+	SessionHeader bool
+	// ProtocolHeader is whether the later requests carry the version in the
+	// `MCP-Protocol-Version` header.
+	ProtocolHeader bool
+	// StructuredContent is whether tools list their output schema, and return
+	// their results as structuredContent too.
+	StructuredContent bool
+}
+
+// McpProtocolVersions are the versions of the MCP protocol supported by the
+// server, which RunMCPProtocolMatrix checks.
+var McpProtocolVersions = []McpProtocolVersion{
+	{Version: "2024-11-05"},
+	{Version: "2025-03-26", SessionHeader: true},
+	{Version: "2025-06-18", ProtocolHeader: true, StructuredContent: true},
+}
+
+// McpMatrixToolName is the name of the mock tool added by
+// AddMcpProtocolMatrixConfig, which needs no source.
+const McpMatrixToolName = "my-mcp-matrix-tool"
+
+// AddMcpProtocolMatrixConfig adds the mock tool that RunMCPProtocolMatrix
+// calls to config. It returns Alice for the name "Alice", and fails for the
+// name "Mallory".
+func AddMcpProtocolMatrixConfig(t *testing.T, config map[string]any) map[string]any {
+	tools, ok := config["tools"].(map[string]any)
+	if !ok {
+		t.Fatalf("unable to get tools from config")
+	}
+	tools[McpMatrixToolName] = map[string]any{
+		"kind":        "mock",
+		"description": "Mock tool to test the MCP protocol versions.",
+		"parameters": []any{
+			map[string]any{
+				"name":        "name",
+				"type":        "string",
+				"description": "The name of the person.",
+			},
+		},
+		"responses": []any{
+			map[string]any{
+				"match":  map[string]any{"name": "Alice"},
+				"result": []any{map[string]any{"id": 1, "name": "Alice"}},
+			},
+			map[string]any{
+				"match": map[string]any{"name": "Mallory"},
+				"error": "name is not allowed",
+			},
+		},
+		"default": map[string]any{"result": []any{}},
+		"outputSchema": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"rows":     map[string]any{"type": "array"},
+				"rowCount": map[string]any{"type": "integer"},
+			},
+		},
+	}
+	config["tools"] = tools
+	return config
+}
+
+// McpToolCall is a tools/call of a tool of a suite, which RunMCPProtocolMatrix
+// makes with each protocol version.
+type McpToolCall struct {
+	Name      string
+	Tool      string
+	Arguments map[string]any
+	// WantContent is contained in the text content of the result, if set.
+	WantContent string
+	// WantError is whether the tool fails, returning a result flagged with
+	// isError rather than a JSON-RPC error.
+	WantError bool
+}
+
+// RunMCPProtocolMatrix runs initialize, tools/list and tools/call against the
+// server with each of the McpProtocolVersions, checking the shapes of the
+// responses of each version. It calls the tool added by
+// AddMcpProtocolMatrixConfig, and then the calls of the suite.
+func RunMCPProtocolMatrix(t *testing.T, calls ...McpToolCall) {
+	for _, v := range McpProtocolVersions {
+		t.Run("MCP "+v.Version, func(t *testing.T) {
+			s := initializeMcpSession(t, v)
+			s.checkToolsList(t)
+			s.checkToolsCall(t)
+			s.checkErrors(t)
+			for _, c := range calls {
+				t.Run(c.Name, func(t *testing.T) {
+					result := s.callTool(t, c.Tool, c.Arguments)
+					if isError, _ := result["isError"].(bool); isError != c.WantError {
+						t.Fatalf("unexpected isError: got %t, want %t: %v", isError, c.WantError, result)
+					}
+					if got := mcpTextContent(t, result); !strings.Contains(got, c.WantContent) {
+						t.Fatalf("unexpected content: got %q, want it to contain %q", got, c.WantContent)
+					}
+				})
+			}
+		})
+	}
+}
+
+// mcpSession sends the requests of an initialized MCP session.
+type mcpSession struct {
+	version McpProtocolVersion
+	header  map[string]string
+}
+
+// initializeMcpSession initializes a session with version v, checking the
+// capabilities advertised.
+func initializeMcpSession(t *testing.T, v McpProtocolVersion) *mcpSession {
+	t.Helper()
+	s := &mcpSession{version: v, header: map[string]string{}}
+	resp, msg := s.post(t, map[string]any{
+		"jsonrpc": "2.0",
+		"id":      "initialize",
+		"method":  "initialize",
+		"params": map[string]any{
+			"protocolVersion": v.Version,
+			"capabilities":    map[string]any{},
+			"clientInfo":      map[string]any{"name": "toolbox-tests", "version": "0.0.0"},
+		},
+	})
+	result := mcpResult(t, resp, msg)
+	if got := result["protocolVersion"]; got != v.Version {
+		t.Fatalf("unexpected protocol version: got %v, want %s", got, v.Version)
+	}
+	capabilities, _ := result["capabilities"].(map[string]any)
+	toolsCapability, ok := capabilities["tools"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected the tools capability to be advertised: %v", capabilities)
+	}
+	if got, ok := toolsCapability["listChanged"].(bool); !ok || got {
+		t.Fatalf("unexpected tools listChanged: %v", toolsCapability)
+	}
+	if _, ok := capabilities["resources"]; ok {
+		t.Fatalf("unexpected resources capability without a schema source: %v", capabilities)
+	}
+	if info, _ := result["serverInfo"].(map[string]any); info["name"] == nil || info["version"] == nil {
+		t.Fatalf("expected the server info to have a name and a version: %v", result["serverInfo"])
+	}
+
+	sessionID := resp.Header.Get("Mcp-Session-Id")
+	if v.SessionHeader != (sessionID != "") {
+		t.Fatalf("unexpected Mcp-Session-Id header %q for protocol version %s", sessionID, v.Version)
+	}
+	if sessionID != "" {
+		s.header["Mcp-Session-Id"] = sessionID
+	}
+	if v.ProtocolHeader {
+		s.header["MCP-Protocol-Version"] = v.Version
+	}
+
+	resp, _ = s.post(t, map[string]any{"jsonrpc": "2.0", "method": "notifications/initialized"})
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("unexpected status code of the initialized notification: got %d, want %d", resp.StatusCode, http.StatusAccepted)
+	}
+	return s
+}
+
+// post sends a JSON-RPC message of the session, returning the response and
+// its decoded body, if any.
+func (s *mcpSession) post(t *testing.T, message map[string]any) (*http.Response, map[string]any) {
+	t.Helper()
+	body, err := json.Marshal(message)
+	if err != nil {
+		t.Fatalf("unexpected error during marshaling of request body: %s", err)
+	}
+	resp, respBody := RunRequest(t, http.MethodPost, DefaultServerURL+"/mcp", bytes.NewBuffer(body), s.header)
+	var msg map[string]any
+	if len(bytes.TrimSpace(respBody)) > 0 {
+		if err := json.Unmarshal(respBody, &msg); err != nil {
+			t.Fatalf("unable to unmarshal response %q: %s", respBody, err)
+		}
+	}
+	return resp, msg
+}
+
+// request sends a JSON-RPC request of method with params.
+func (s *mcpSession) request(t *testing.T, method string, params map[string]any) (*http.Response, map[string]any) {
+	t.Helper()
+	return s.post(t, map[string]any{"jsonrpc": "2.0", "id": method, "method": method, "params": params})
+}
+
+// callTool calls the named tool, returning the result of the response.
+func (s *mcpSession) callTool(t *testing.T, name string, arguments map[string]any) map[string]any {
+	t.Helper()
+	resp, msg := s.request(t, "tools/call", map[string]any{"name": name, "arguments": arguments})
+	return mcpResult(t, resp, msg)
+}
+
+func (s *mcpSession) checkToolsList(t *testing.T) {
+	t.Helper()
+	resp, msg := s.request(t, "tools/list", map[string]any{})
+	result := mcpResult(t, resp, msg)
+	// all the tools are listed in one page
+	if cursor, ok := result["nextCursor"]; ok {
+		t.Fatalf("unexpected nextCursor %v", cursor)
+	}
+	list, _ := result["tools"].([]any)
+	var tool map[string]any
+	for _, e := range list {
+		if m, ok := e.(map[string]any); ok && m["name"] == McpMatrixToolName {
+			tool = m
+		}
+	}
+	if tool == nil {
+		t.Fatalf("expected tool %q to be listed: %v", McpMatrixToolName, list)
+	}
+	if schema, _ := tool["inputSchema"].(map[string]any); schema["type"] != "object" {
+		t.Fatalf("unexpected input schema: %v", tool["inputSchema"])
+	}
+	if _, ok := tool["outputSchema"]; ok != s.version.StructuredContent {
+		t.Fatalf("unexpected outputSchema for protocol version %s: %v", s.version.Version, tool)
+	}
+}
+
+func (s *mcpSession) checkToolsCall(t *testing.T) {
+	t.Helper()
+	result := s.callTool(t, McpMatrixToolName, map[string]any{"name": "Alice"})
+	if got := mcpTextContent(t, result); got != `{"id":1,"name":"Alice"}` {
+		t.Fatalf("unexpected content: %q", got)
+	}
+	structured, ok := result["structuredContent"].(map[string]any)
+	if ok != s.version.StructuredContent {
+		t.Fatalf("unexpected structuredContent for protocol version %s: %v", s.version.Version, result)
+	}
+	if ok && structured["rowCount"] != float64(1) {
+		t.Fatalf("unexpected structuredContent: %v", structured)
+	}
+
+	result = s.callTool(t, McpMatrixToolName, map[string]any{"name": "Mallory"})
+	if isError, _ := result["isError"].(bool); !isError {
+		t.Fatalf("expected the result of a failed tool to be flagged with isError: %v", result)
+	}
+	if got := mcpTextContent(t, result); !strings.Contains(got, "name is not allowed") {
+		t.Fatalf("unexpected content of a failed tool: %q", got)
+	}
+	if _, ok := result["structuredContent"]; ok {
+		t.Fatalf("unexpected structuredContent of a failed tool: %v", result)
+	}
+}
+
+// checkErrors checks the JSON-RPC errors of invalid requests.
+func (s *mcpSession) checkErrors(t *testing.T) {
+	t.Helper()
+	tcs := []struct {
+		method  string
+		params  map[string]any
+		code    float64
+		message string
+		data    bool
+	}{
+		{method: "tools/call", params: map[string]any{"name": "foo", "arguments": map[string]any{}}, code: -32602, message: `tool with name "foo" does not exist`},
+		{method: "tools/call", params: map[string]any{"name": McpMatrixToolName, "arguments": map[string]any{}}, code: -32602, message: `parameter "name" is required`, data: true},
+		{method: "tools/unknown", params: map[string]any{}, code: -32601, message: "invalid method tools/unknown"},
+	}
+	for _, tc := range tcs {
+		resp, msg := s.request(t, tc.method, tc.params)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status code of %s: got %d, want %d", tc.method, resp.StatusCode, http.StatusOK)
+		}
+		rpcErr, ok := msg["error"].(map[string]any)
+		if !ok {
+			t.Fatalf("expected a JSON-RPC error: %v", msg)
+		}
+		if rpcErr["code"] != tc.code {
+			t.Fatalf("unexpected error code: got %v, want %v", rpcErr["code"], tc.code)
+		}
+		if m, _ := rpcErr["message"].(string); !strings.Contains(m, tc.message) {
+			t.Fatalf("unexpected error message: got %q, want it to contain %q", m, tc.message)
+		}
+		if _, ok := rpcErr["data"]; ok != tc.data {
+			t.Fatalf("unexpected error data: %v", rpcErr)
+		}
+	}
+
+	if s.version.ProtocolHeader {
+		header := map[string]string{"MCP-Protocol-Version": "1999-01-01"}
+		body := bytes.NewBufferString(`{"jsonrpc":"2.0","id":"tools/list","method":"tools/list"}`)
+		resp, _ := RunRequest(t, http.MethodPost, DefaultServerURL+"/mcp", body, header)
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("unexpected status code of an unknown protocol version: got %d, want %d", resp.StatusCode, http.StatusBadRequest)
+		}
+	}
+}
+
+// mcpResult returns the result of a successful JSON-RPC response.
+func mcpResult(t *testing.T, resp *http.Response, msg map[string]any) map[string]any {
+	t.Helper()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: got %d, want %d: %v", resp.StatusCode, http.StatusOK, msg)
+	}
+	if msg["jsonrpc"] != "2.0" {
+		t.Fatalf("unexpected jsonrpc version: %v", msg)
+	}
+	result, ok := msg["result"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected a result: %v", msg)
+	}
+	return result
+}
+
+// mcpTextContent returns the texts of the content of the result of a
+// tools/call, joined by newlines.
+func mcpTextContent(t *testing.T, result map[string]any) string {
+	t.Helper()
+	content, ok := result["content"].([]any)
+	if !ok {
+		t.Fatalf("expected the result to have content: %v", result)
+	}
+	texts := make([]string, 0, len(content))
+	for _, c := range content {
+		m, _ := c.(map[string]any)
+		if m["type"] != "text" {
+			t.Fatalf("unexpected content type: %v", c)
+		}
+		text, _ := m["text"].(string)
+		texts = append(texts, text)
+	}
+	return strings.Join(texts, "\n")
+}
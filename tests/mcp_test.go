@@ -0,0 +1,61 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+// TestRunMCPProtocolMatrix runs the MCP protocol matrix against mock tools,
+// which need no database.
+func TestRunMCPProtocolMatrix(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	toolsFile := map[string]any{
+		"tools": map[string]any{
+			"my-weather-tool": map[string]any{
+				"kind":        "mock",
+				"description": "Get the weather forecast of a city.",
+				"default":     map[string]any{"result": map[string]any{"forecast": "sunny"}},
+			},
+		},
+	}
+	toolsFile = AddMcpProtocolMatrixConfig(t, toolsFile)
+	cmd, cleanup, err := StartCmd(ctx, toolsFile)
+	if err != nil {
+		t.Fatalf("command initialization returned an error: %s", err)
+	}
+	defer cleanup()
+
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	_, out, err := testutils.WaitForServerReady(waitCtx, cmd.Out)
+	if err != nil {
+		t.Logf("toolbox command logs: \n%s", out)
+		t.Fatalf("toolbox didn't start successfully: %s", err)
+	}
+
+	RunMCPProtocolMatrix(t, McpToolCall{
+		Name:        "call my-weather-tool",
+		Tool:        "my-weather-tool",
+		Arguments:   map[string]any{},
+		WantContent: `{"forecast":"sunny"}`,
+	})
+}
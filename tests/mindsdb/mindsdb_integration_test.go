@@ -15,13 +15,12 @@
 package mindsdb
 
 import (
-	"bytes"
 	"context"
 	"database/sql"
 	"fmt"
+	"maps"
 	"net/http"
 	"os"
-	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -95,6 +94,7 @@ func TestMindsDBToolEndpoints(t *testing.T) {
 	// Create unique table names with UUID
 	tableNameParam := "param_table_" + strings.ReplaceAll(uuid.New().String(), "-", "")
 	tableNameAuth := "auth_table_" + strings.ReplaceAll(uuid.New().String(), "-", "")
+	tableNameTyped := "typed_table_" + strings.ReplaceAll(uuid.New().String(), "-", "")
 
 	// These match GetMySQLParamToolInfo and GetMySQLAuthToolInfo patterns
 	// Add ORDER BY to guarantee consistent order in results
@@ -102,10 +102,17 @@ func TestMindsDBToolEndpoints(t *testing.T) {
 	idParamToolStmt := fmt.Sprintf("SELECT * FROM files.%s WHERE id = ? ORDER BY id", tableNameParam)
 	nameParamToolStmt := fmt.Sprintf("SELECT * FROM files.%s WHERE name = ? ORDER BY id", tableNameParam)
 	authToolStmt := fmt.Sprintf("SELECT name FROM files.%s WHERE email = ? ORDER BY name", tableNameAuth)
+	unqualifiedStmt := fmt.Sprintf("SELECT name FROM %s WHERE id = 1", tableNameParam)
+	qualifiedStmt := fmt.Sprintf("SELECT name FROM files.%s WHERE id = 1", tableNameParam)
+
+	// a second source whose connections default to the files database
+	filesSourceConfig := maps.Clone(sourceConfig)
+	filesSourceConfig["database"] = "files"
 
 	toolsFile := map[string]any{
 		"sources": map[string]any{
-			"my-instance": sourceConfig,
+			"my-instance":       sourceConfig,
+			"my-files-instance": filesSourceConfig,
 		},
 		"authServices": map[string]any{
 			"my-google-auth": map[string]any{
@@ -205,6 +212,43 @@ func TestMindsDBToolEndpoints(t *testing.T) {
 				"description": "Tool to test statement with incorrect syntax.",
 				"statement":   "INVALID SQL STATEMENT",
 			},
+			"my-typed-tool": map[string]any{
+				"kind":        MindsDBToolKind,
+				"source":      "my-instance",
+				"description": "Tool to test column type coercion.",
+				"statement":   fmt.Sprintf("SELECT * FROM files.%s ORDER BY id", tableNameTyped),
+				"columnTypes": map[string]any{
+					"id":     "integer",
+					"price":  "float",
+					"active": "boolean",
+				},
+			},
+			"my-source-database-tool": map[string]any{
+				"kind":        MindsDBToolKind,
+				"source":      "my-files-instance",
+				"description": "Tool to test unqualified names resolving against the database of the source.",
+				"statement":   unqualifiedStmt,
+			},
+			"my-default-database-tool": map[string]any{
+				"kind":            MindsDBToolKind,
+				"source":          "my-instance",
+				"description":     "Tool to test unqualified names resolving against the default database of the tool.",
+				"statement":       unqualifiedStmt,
+				"defaultDatabase": "files",
+			},
+			"my-qualified-tool": map[string]any{
+				"kind":            MindsDBToolKind,
+				"source":          "my-files-instance",
+				"description":     "Tool to test fully qualified names whatever the default database is.",
+				"statement":       qualifiedStmt,
+				"defaultDatabase": "mindsdb",
+			},
+			"my-default-database-exec-sql-tool": map[string]any{
+				"kind":            "mindsdb-execute-sql",
+				"source":          "my-instance",
+				"description":     "Tool to execute sql with a default database",
+				"defaultDatabase": "files",
+			},
 			"my-exec-sql-tool": map[string]any{
 				"kind":        "mindsdb-execute-sql",
 				"source":      "my-instance",
@@ -221,6 +265,8 @@ func TestMindsDBToolEndpoints(t *testing.T) {
 		},
 	}
 
+	toolsFile = tests.AddMcpProtocolMatrixConfig(t, toolsFile)
+
 	cmd, cleanup, err := tests.StartCmd(ctx, toolsFile, args...)
 	if err != nil {
 		t.Fatalf("command initialization returned an error: %s", err)
@@ -229,7 +275,7 @@ func TestMindsDBToolEndpoints(t *testing.T) {
 
 	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
-	out, err := testutils.WaitForString(waitCtx, regexp.MustCompile(`Server ready to serve`), cmd.Out)
+	_, out, err := testutils.WaitForServerReady(waitCtx, cmd.Out)
 	if err != nil {
 		t.Logf("toolbox command logs: \n%s", out)
 		t.Fatalf("toolbox didn't start successfully: %s", err)
@@ -257,10 +303,18 @@ func TestMindsDBToolEndpoints(t *testing.T) {
 		t.Fatalf("unable to create auth table: %s", err)
 	}
 
+	// Create a table whose values are stored as text, as with uploaded CSV files
+	createTypedSQL := fmt.Sprintf("CREATE TABLE files.%s (SELECT '1' as id, '19.99' as price, 'true' as active UNION ALL SELECT '2', '5', 'false')", tableNameTyped)
+	_, err = pool.ExecContext(ctx, createTypedSQL)
+	if err != nil {
+		t.Fatalf("unable to create typed table: %s", err)
+	}
+
 	// Cleanup function - executes AFTER test completes
 	defer func() {
 		pool.ExecContext(ctx, fmt.Sprintf("DROP TABLE IF EXISTS files.%s", tableNameParam))
 		pool.ExecContext(ctx, fmt.Sprintf("DROP TABLE IF EXISTS files.%s", tableNameAuth))
+		pool.ExecContext(ctx, fmt.Sprintf("DROP TABLE IF EXISTS files.%s", tableNameTyped))
 	}()
 
 	// Get configs for tests
@@ -268,7 +322,19 @@ func TestMindsDBToolEndpoints(t *testing.T) {
 
 	// Run tests following the same pattern as MySQL (as requested by reviewer)
 	// Now querying real data from files tables with parameter interpolation
-	tests.RunToolGetTest(t)
+	tests.RunToolGetTestByName(t, "my-simple-tool", map[string]any{
+		"my-simple-tool": map[string]any{
+			"description":  "Simple tool to test end to end functionality.",
+			"parameters":   []any{},
+			"authRequired": []any{},
+			"capabilities": map[string]any{
+				"supportsPreparedStatements": true,
+				"supportsArrayParams":        true,
+				"supportsTransactions":       false,
+				"maxIdentifierLength":        float64(64),
+			},
+		},
+	})
 	tests.RunToolInvokeTest(t, select1Want,
 		tests.DisableArrayTest(), // MindsDB doesn't support array parameters
 		// Adjust expectations for MindsDB's output format querying real data
@@ -281,24 +347,102 @@ func TestMindsDBToolEndpoints(t *testing.T) {
 		// my-tool-by-name: SELECT * FROM files.{table} WHERE name = NULL
 		// Returns empty result set when name is not provided
 		tests.WithNullWant("null"),
+		// MindsDB-specific queries, error paths and auth of the execute SQL tools
+		tests.WithInvokeTests(
+			tests.InvokeTestCase{
+				Name:        "invoke my-exec-sql-tool with SELECT 1",
+				Tool:        "my-exec-sql-tool",
+				RequestBody: `{"sql": "SELECT 1"}`,
+				WantBody:    select1Want,
+			},
+			tests.InvokeTestCase{
+				Name:        "invoke my-exec-sql-tool with arithmetic",
+				Tool:        "my-exec-sql-tool",
+				RequestBody: `{"sql": "SELECT 1+1 as result"}`,
+				WantBody:    "[{\"result\":2}]",
+			},
+			tests.InvokeTestCase{
+				Name:        "invoke my-exec-sql-tool with string literal",
+				Tool:        "my-exec-sql-tool",
+				RequestBody: `{"sql": "SELECT 'hello' as greeting"}`,
+				WantBody:    "[{\"greeting\":\"hello\"}]",
+			},
+			tests.InvokeTestCase{
+				Name:              "invoke my-exec-sql-tool with invalid SQL",
+				Tool:              "my-exec-sql-tool",
+				RequestBody:       `{"sql": "INVALID SQL QUERY"}`,
+				WantStatusCode:    http.StatusBadRequest,
+				WantErrorContains: "error while invoking tool",
+			},
+			tests.InvokeTestCase{
+				Name:           "invoke my-exec-sql-tool with empty SQL",
+				Tool:           "my-exec-sql-tool",
+				RequestBody:    `{"sql": ""}`,
+				WantStatusCode: http.StatusBadRequest,
+			},
+			// unqualified names resolve against the defaultDatabase of the
+			// tool, else the database of the source; qualified names always
+			// resolve the same way
+			tests.InvokeTestCase{
+				Name:     "invoke my-source-database-tool",
+				Tool:     "my-source-database-tool",
+				WantBody: "[{\"name\":\"Alice\"}]",
+			},
+			tests.InvokeTestCase{
+				Name:     "invoke my-default-database-tool",
+				Tool:     "my-default-database-tool",
+				WantBody: "[{\"name\":\"Alice\"}]",
+			},
+			tests.InvokeTestCase{
+				Name:     "invoke my-qualified-tool",
+				Tool:     "my-qualified-tool",
+				WantBody: "[{\"name\":\"Alice\"}]",
+			},
+			tests.InvokeTestCase{
+				Name:        "invoke my-default-database-exec-sql-tool with an unqualified name",
+				Tool:        "my-default-database-exec-sql-tool",
+				RequestBody: fmt.Sprintf(`{"sql": %q}`, unqualifiedStmt),
+				WantBody:    "[{\"name\":\"Alice\"}]",
+			},
+			tests.InvokeTestCase{
+				Name:              "invoke my-auth-exec-sql-tool without auth token",
+				Tool:              "my-auth-exec-sql-tool",
+				RequestBody:       `{"sql": "SELECT 1"}`,
+				WantStatusCode:    http.StatusUnauthorized,
+				WantErrorContains: "tool invocation not authorized",
+			},
+		),
 	)
 
-	// Run comprehensive MindsDB-specific tests that focus on what works
-	t.Run("mindsdb_core_functionality", func(t *testing.T) {
-		// Test simple tool invocation
-		tests.RunToolInvokeSimpleTest(t, "my-simple-tool", select1Want)
+	tests.RunMCPProtocolMatrix(t,
+		tests.McpToolCall{
+			Name:        "call my-tool",
+			Tool:        "my-tool",
+			Arguments:   map[string]any{"id": 3, "name": "Alice"},
+			WantContent: "{\"id\":1,\"name\":\"Alice\"}\n{\"id\":3,\"name\":\"Sid\"}",
+		},
+		tests.McpToolCall{
+			Name:        "call my-exec-sql-tool",
+			Tool:        "my-exec-sql-tool",
+			Arguments:   map[string]any{"sql": "SELECT 1+1 as result"},
+			WantContent: "{\"result\":2}",
+		},
+		tests.McpToolCall{
+			Name:      "call my-exec-sql-tool with invalid SQL",
+			Tool:      "my-exec-sql-tool",
+			Arguments: map[string]any{"sql": "INVALID SQL QUERY"},
+			WantError: true,
+		},
+	)
 
-		// Test execute SQL tool with basic queries
-		tests.RunToolInvokeParametersTest(t, "my-exec-sql-tool", []byte(`{"sql": "SELECT 1"}`), select1Want)
-		tests.RunToolInvokeParametersTest(t, "my-exec-sql-tool", []byte(`{"sql": "SELECT 1+1 as result"}`), "[{\"result\":2}]")
-		tests.RunToolInvokeParametersTest(t, "my-exec-sql-tool", []byte(`{"sql": "SELECT 'hello' as greeting"}`), "[{\"greeting\":\"hello\"}]")
+	// Numbers stored as text in files tables round-trip as numbers
+	t.Run("mindsdb_column_types", func(t *testing.T) {
+		tests.RunToolInvokeSimpleTest(t, "my-typed-tool",
+			"[{\"active\":true,\"id\":1,\"price\":19.99},{\"active\":false,\"id\":2,\"price\":5}]")
 	})
 
 	// Test comprehensive execute SQL functionality
 	t.Run("mindsdb_sql_tests", func(t *testing.T) {
-		// Test basic SELECT query
-		tests.RunToolInvokeParametersTest(t, "my-exec-sql-tool", []byte(`{"sql": "SELECT 1"}`), select1Want)
-
 		// Test SHOW DATABASES
 		tests.RunToolInvokeParametersTest(t, "my-exec-sql-tool", []byte(`{"sql": "SHOW DATABASES"}`), "")
 
@@ -308,9 +452,6 @@ func TestMindsDBToolEndpoints(t *testing.T) {
 		// Test SELECT from information_schema
 		tests.RunToolInvokeParametersTest(t, "my-exec-sql-tool", []byte(`{"sql": "SELECT TABLE_NAME FROM information_schema.TABLES LIMIT 1"}`), "")
 
-		// Test basic arithmetic
-		tests.RunToolInvokeParametersTest(t, "my-exec-sql-tool", []byte(`{"sql": "SELECT 1+1 as result"}`), "")
-
 		// Test string functions
 		tests.RunToolInvokeParametersTest(t, "my-exec-sql-tool", []byte(`{"sql": "SELECT UPPER('hello') as result"}`), "")
 
@@ -509,40 +650,291 @@ func TestMindsDBToolEndpoints(t *testing.T) {
 		tests.RunToolInvokeParametersTest(t, "my-exec-sql-tool",
 			[]byte(`{"sql": "DROP TABLE IF EXISTS files.test_customer_summary"}`), "")
 	})
+}
 
-	// Test error handling - these are expected to fail but exercise error paths
-	t.Run("mindsdb_error_handling", func(t *testing.T) {
-		// Test invalid SQL - expect this to fail with 400
-		resp, err := http.Post("http://127.0.0.1:5000/api/tool/my-exec-sql-tool/invoke", "application/json", bytes.NewBuffer([]byte(`{"sql": "INVALID SQL QUERY"}`)))
-		if err != nil {
-			t.Fatalf("error when sending request: %s", err)
-		}
-		defer resp.Body.Close()
-		if resp.StatusCode != http.StatusBadRequest {
-			t.Logf("Expected 400 for invalid SQL, got %d (this exercises error handling)", resp.StatusCode)
-		}
+// MindsDBEmbeddingAPIKey is the OpenAI API key of the embedding model of the
+// knowledge bases created by the tests.
+var MindsDBEmbeddingAPIKey = os.Getenv("MINDSDB_EMBEDDING_API_KEY")
 
-		// Test empty SQL - expect this to fail with 400
-		resp2, err := http.Post("http://127.0.0.1:5000/api/tool/my-exec-sql-tool/invoke", "application/json", bytes.NewBuffer([]byte(`{"sql": ""}`)))
-		if err != nil {
-			t.Fatalf("error when sending request: %s", err)
+// supportsKnowledgeBases reports whether the MindsDB version behind pool
+// supports knowledge bases.
+func supportsKnowledgeBases(ctx context.Context, pool *sql.DB) bool {
+	rows, err := pool.QueryContext(ctx, "SHOW KNOWLEDGE_BASES")
+	if err != nil {
+		return false
+	}
+	rows.Close()
+	return true
+}
+
+func TestMindsDBKnowledgeBaseTools(t *testing.T) {
+	sourceConfig := getMindsDBVars(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	pool, err := initMindsDBConnectionPool(MindsDBHost, MindsDBPort, MindsDBUser, MindsDBPass, MindsDBDatabase)
+	if err != nil {
+		t.Fatalf("unable to create MindsDB connection pool: %s", err)
+	}
+	defer pool.Close()
+	if !supportsKnowledgeBases(ctx, pool) {
+		t.Skip("MindsDB version does not support knowledge bases")
+	}
+	if MindsDBEmbeddingAPIKey == "" {
+		t.Skip("'MINDSDB_EMBEDDING_API_KEY' not set")
+	}
+
+	kbName := "kb_" + strings.ReplaceAll(uuid.New().String(), "-", "")
+	toolsFile := map[string]any{
+		"sources": map[string]any{
+			"my-instance": sourceConfig,
+		},
+		"tools": map[string]any{
+			"my-create-kb-tool": map[string]any{
+				"kind":        "mindsdb-create-knowledge-base",
+				"source":      "my-instance",
+				"description": "Tool to create knowledge bases.",
+				"embeddingModel": map[string]any{
+					"provider":   "openai",
+					"model_name": "text-embedding-3-small",
+					"api_key":    MindsDBEmbeddingAPIKey,
+				},
+				"metadataColumns": []string{"product"},
+				"contentColumns":  []string{"notes"},
+				"idColumn":        "id",
+			},
+			"my-query-kb-tool": map[string]any{
+				"kind":        "mindsdb-query-knowledge-base",
+				"source":      "my-instance",
+				"description": "Tool to query knowledge bases.",
+				"defaultTopK": 2,
+			},
+		},
+	}
+	cmd, cleanup, err := tests.StartCmd(ctx, toolsFile)
+	if err != nil {
+		t.Fatalf("command initialization returned an error: %s", err)
+	}
+	defer cleanup()
+
+	waitCtx, waitCancel := context.WithTimeout(ctx, 10*time.Second)
+	defer waitCancel()
+	_, out, err := testutils.WaitForServerReady(waitCtx, cmd.Out)
+	if err != nil {
+		t.Logf("toolbox command logs: \n%s", out)
+		t.Fatalf("toolbox didn't start successfully: %s", err)
+	}
+
+	tests.RunToolInvokeParametersTest(t, "my-create-kb-tool",
+		[]byte(`{"name": "`+kbName+`"}`), `"knowledgeBase":"`+kbName+`"`)
+	defer func() {
+		if _, err := pool.ExecContext(context.Background(), "DROP KNOWLEDGE_BASE "+kbName); err != nil {
+			t.Logf("unable to drop knowledge base %q: %s", kbName, err)
 		}
-		defer resp2.Body.Close()
-		if resp2.StatusCode != http.StatusBadRequest {
-			t.Logf("Expected 400 for empty SQL, got %d (this exercises error handling)", resp2.StatusCode)
+	}()
+
+	insert := fmt.Sprintf("INSERT INTO %s (id, notes, product) VALUES (1, 'Refunds are processed within five business days.', 'shop'), (2, 'The warranty covers manufacturing defects for two years.', 'shop'), (3, 'Invoices can be downloaded from the billing page.', 'billing')", kbName)
+	if _, err := pool.ExecContext(ctx, insert); err != nil {
+		t.Fatalf("unable to insert into knowledge base: %s", err)
+	}
+
+	t.Run("query", func(t *testing.T) {
+		tests.RunToolInvokeParametersTest(t, "my-query-kb-tool",
+			[]byte(`{"knowledge_base": "`+kbName+`", "query": "how long do refunds take?"}`),
+			"Refunds are processed within five business days.")
+	})
+	t.Run("query with metadata filter", func(t *testing.T) {
+		tests.RunToolInvokeParametersTest(t, "my-query-kb-tool",
+			[]byte(`{"knowledge_base": "`+kbName+`", "query": "where are my invoices?", "top_k": 1, "filters": {"product": "billing"}}`),
+			"Invoices can be downloaded from the billing page.")
+	})
+	tests.RunToolInvokeTestCases(t, []tests.InvokeTestCase{
+		{
+			// the hint lists the available knowledge bases
+			Name:             "missing knowledge base",
+			Tool:             "my-query-kb-tool",
+			RequestBody:      `{"knowledge_base": "missing_kb", "query": "anything"}`,
+			WantStatusCode:   http.StatusBadRequest,
+			WantHintContains: kbName,
+		},
+	})
+}
+
+func TestMindsDBProjectTools(t *testing.T) {
+	sourceConfig := getMindsDBVars(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	pool, err := initMindsDBConnectionPool(MindsDBHost, MindsDBPort, MindsDBUser, MindsDBPass, MindsDBDatabase)
+	if err != nil {
+		t.Fatalf("unable to create MindsDB connection pool: %s", err)
+	}
+	defer pool.Close()
+
+	project := "project_" + strings.ReplaceAll(uuid.New().String(), "-", "")
+	toolsFile := map[string]any{
+		"sources": map[string]any{
+			"my-instance": sourceConfig,
+		},
+		"tools": map[string]any{
+			"my-create-project-tool": map[string]any{
+				"kind":        "mindsdb-create-project",
+				"source":      "my-instance",
+				"description": "Tool to create projects.",
+			},
+			"my-project-exec-sql-tool": map[string]any{
+				"kind":              "mindsdb-execute-sql",
+				"source":            "my-instance",
+				"description":       "Tool to execute sql in a project.",
+				"project":           project,
+				"restrictToProject": true,
+			},
+		},
+	}
+	cmd, cleanup, err := tests.StartCmd(ctx, toolsFile)
+	if err != nil {
+		t.Fatalf("command initialization returned an error: %s", err)
+	}
+	defer cleanup()
+
+	waitCtx, waitCancel := context.WithTimeout(ctx, 10*time.Second)
+	defer waitCancel()
+	_, out, err := testutils.WaitForServerReady(waitCtx, cmd.Out)
+	if err != nil {
+		t.Logf("toolbox command logs: \n%s", out)
+		t.Fatalf("toolbox didn't start successfully: %s", err)
+	}
+
+	tests.RunToolInvokeParametersTest(t, "my-create-project-tool",
+		[]byte(`{"name": "`+project+`"}`), `"project":"`+project+`"`)
+	defer func() {
+		if _, err := pool.ExecContext(context.Background(), "DROP PROJECT "+project); err != nil {
+			t.Logf("unable to drop project %q: %s", project, err)
 		}
+	}()
+
+	t.Run("query in project", func(t *testing.T) {
+		tests.RunToolInvokeParametersTest(t, "my-project-exec-sql-tool",
+			[]byte(`{"sql": "SELECT 1 AS one"}`), `"one":1`)
 	})
+	tests.RunToolInvokeTestCases(t, []tests.InvokeTestCase{
+		{
+			Name:              "reference to another project",
+			Tool:              "my-project-exec-sql-tool",
+			RequestBody:       `{"sql": "SELECT * FROM mindsdb.models"}`,
+			WantStatusCode:    http.StatusBadRequest,
+			WantErrorContains: `restricted to project "` + project + `"`,
+		},
+	})
+}
 
-	// Test authentication - these are expected to fail but exercise auth code paths
-	t.Run("mindsdb_auth_tests", func(t *testing.T) {
-		// Test auth-required tool without auth - expect this to fail with 401
-		resp, err := http.Post("http://127.0.0.1:5000/api/tool/my-auth-exec-sql-tool/invoke", "application/json", bytes.NewBuffer([]byte(`{"sql": "SELECT 1"}`)))
+// waitForModel waits until the active version of the model name of project
+// has finished training, and returns its status.
+func waitForModel(ctx context.Context, pool *sql.DB, project, name string) (string, error) {
+	for {
+		var status string
+		err := pool.QueryRowContext(ctx, "SELECT STATUS FROM information_schema.models WHERE PROJECT = ? AND NAME = ? AND ACTIVE = true", project, name).Scan(&status)
 		if err != nil {
-			t.Fatalf("error when sending request: %s", err)
+			return "", err
 		}
-		defer resp.Body.Close()
-		if resp.StatusCode != http.StatusUnauthorized {
-			t.Logf("Expected 401 for missing auth, got %d (this exercises auth handling)", resp.StatusCode)
+		if status == "complete" || status == "error" {
+			return status, nil
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+func TestMindsDBModelLifecycleTools(t *testing.T) {
+	sourceConfig := getMindsDBVars(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	pool, err := initMindsDBConnectionPool(MindsDBHost, MindsDBPort, MindsDBUser, MindsDBPass, MindsDBDatabase)
+	if err != nil {
+		t.Fatalf("unable to create MindsDB connection pool: %s", err)
+	}
+	defer pool.Close()
+
+	suffix := strings.ReplaceAll(uuid.New().String(), "-", "")
+	tableName := "model_data_" + suffix
+	modelName := "model_" + suffix
+	createTable := fmt.Sprintf("CREATE TABLE files.%s (SELECT 1 AS x, 2 AS y UNION ALL SELECT 2, 4 UNION ALL SELECT 3, 6 UNION ALL SELECT 4, 8 UNION ALL SELECT 5, 10)", tableName)
+	if _, err := pool.ExecContext(ctx, createTable); err != nil {
+		t.Fatalf("unable to create training table: %s", err)
+	}
+	defer func() {
+		pool.ExecContext(context.Background(), "DROP TABLE IF EXISTS files."+tableName)
+		pool.ExecContext(context.Background(), "DROP MODEL IF EXISTS mindsdb."+modelName)
+	}()
+	createModel := fmt.Sprintf("CREATE MODEL mindsdb.%s FROM files (SELECT * FROM %s) PREDICT y", modelName, tableName)
+	if _, err := pool.ExecContext(ctx, createModel); err != nil {
+		t.Skipf("MindsDB instance cannot train models: %s", err)
+	}
+	if status, err := waitForModel(ctx, pool, "mindsdb", modelName); err != nil || status != "complete" {
+		t.Skipf("MindsDB instance did not train model: status %q, error %v", status, err)
+	}
+
+	toolsFile := map[string]any{
+		"sources": map[string]any{
+			"my-instance": sourceConfig,
+		},
+		"tools": map[string]any{
+			"my-describe-model-tool": map[string]any{
+				"kind":        "mindsdb-describe-model",
+				"source":      "my-instance",
+				"description": "Tool to describe models.",
+			},
+			"my-retrain-model-tool": map[string]any{
+				"kind":        "mindsdb-retrain-model",
+				"source":      "my-instance",
+				"description": "Tool to retrain models.",
+			},
+			"my-drop-model-tool": map[string]any{
+				"kind":        "mindsdb-drop-model",
+				"source":      "my-instance",
+				"description": "Tool to drop models.",
+			},
+		},
+	}
+	cmd, cleanup, err := tests.StartCmd(ctx, toolsFile)
+	if err != nil {
+		t.Fatalf("command initialization returned an error: %s", err)
+	}
+	defer cleanup()
+
+	waitCtx, waitCancel := context.WithTimeout(ctx, 10*time.Second)
+	defer waitCancel()
+	_, out, err := testutils.WaitForServerReady(waitCtx, cmd.Out)
+	if err != nil {
+		t.Logf("toolbox command logs: \n%s", out)
+		t.Fatalf("toolbox didn't start successfully: %s", err)
+	}
+
+	body := []byte(`{"name": "` + modelName + `"}`)
+	t.Run("describe", func(t *testing.T) {
+		tests.RunToolInvokeParametersTest(t, "my-describe-model-tool", body, `"status":"complete"`)
+	})
+	t.Run("retrain", func(t *testing.T) {
+		tests.RunToolInvokeParametersTest(t, "my-retrain-model-tool", body, `"name":"`+modelName+`"`)
+		if status, err := waitForModel(ctx, pool, "mindsdb", modelName); err != nil || status != "complete" {
+			t.Fatalf("retrained model has status %q, error %v", status, err)
 		}
+		tests.RunToolInvokeParametersTest(t, "my-describe-model-tool", body, `"version":2`)
+	})
+	t.Run("drop", func(t *testing.T) {
+		tests.RunToolInvokeParametersTest(t, "my-drop-model-tool", body, `"dropped":true`)
+	})
+	tests.RunToolInvokeTestCases(t, []tests.InvokeTestCase{
+		{
+			Name:              "describe missing model",
+			Tool:              "my-describe-model-tool",
+			RequestBody:       string(body),
+			WantStatusCode:    http.StatusBadRequest,
+			WantErrorContains: "does not exist in project",
+		},
 	})
 }
@@ -22,7 +22,7 @@ import (
 	"io"
 	"net/http"
 	"os"
-	"regexp"
+	"strings"
 	"testing"
 	"time"
 
@@ -93,7 +93,7 @@ func TestMongoDBToolEndpoints(t *testing.T) {
 
 	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
-	out, err := testutils.WaitForString(waitCtx, regexp.MustCompile(`Server ready to serve`), cmd.Out)
+	_, out, err := testutils.WaitForServerReady(waitCtx, cmd.Out)
 	if err != nil {
 		t.Logf("toolbox command logs: \n%s", out)
 		t.Fatalf("toolbox didn't start successfully: %s", err)
@@ -122,7 +122,7 @@ func TestMongoDBToolEndpoints(t *testing.T) {
 	runToolDeleteInvokeTest(t, delete1Want, deleteManyWant)
 
 	insert1Want := `["68666e1035bb36bf1b4d47fb"]`
-	insertManyWant := `["68667a6436ec7d0363668db7","68667a6436ec7d0363668db8","68667a6436ec7d0363668db9"]`
+	insertManyWant := `{"succeeded":3,"failed":0,"errors":[],"results":["68667a6436ec7d0363668db7","68667a6436ec7d0363668db8","68667a6436ec7d0363668db9"]}`
 	runToolInsertInvokeTest(t, insert1Want, insertManyWant)
 
 	update1Want := "1"
@@ -132,6 +132,90 @@ func TestMongoDBToolEndpoints(t *testing.T) {
 	aggregate1Want := `[{"id":2}]`
 	aggregateManyWant := `[{"id":500},{"id":501}]`
 	runToolAggregateInvokeTest(t, aggregate1Want, aggregateManyWant)
+
+	runToolCountInvokeTest(t)
+}
+
+// runToolCountInvokeTest counts the documents of the test collection, and
+// checks whether some exist.
+func runToolCountInvokeTest(t *testing.T) {
+	invokeTcs := []struct {
+		name        string
+		api         string
+		requestBody string
+		want        string
+		isErr       bool
+	}{
+		{
+			name:        "invoke my-count-tool",
+			api:         "http://127.0.0.1:5000/api/tool/my-count-tool/invoke",
+			requestBody: `{ "name": "ToBeAggregated" }`,
+			want:        `{"count":2,"capped":false,"estimated":false}`,
+		},
+		{
+			name:        "invoke my-count-tool with no match",
+			api:         "http://127.0.0.1:5000/api/tool/my-count-tool/invoke",
+			requestBody: `{ "name": "Nobody" }`,
+			want:        `{"count":0,"capped":false,"estimated":false}`,
+		},
+		{
+			name:        "invoke my-capped-count-tool",
+			api:         "http://127.0.0.1:5000/api/tool/my-capped-count-tool/invoke",
+			requestBody: `{}`,
+			want:        `{"count":1,"capped":true,"estimated":false}`,
+		},
+		{
+			name:        "invoke my-estimated-count-tool",
+			api:         "http://127.0.0.1:5000/api/tool/my-estimated-count-tool/invoke",
+			requestBody: `{}`,
+			want:        `"estimated":true`,
+		},
+		{
+			name:        "invoke my-exists-tool",
+			api:         "http://127.0.0.1:5000/api/tool/my-exists-tool/invoke",
+			requestBody: `{ "name": "ToBeAggregated" }`,
+			want:        "true",
+		},
+		{
+			name:        "invoke my-exists-tool with no match",
+			api:         "http://127.0.0.1:5000/api/tool/my-exists-tool/invoke",
+			requestBody: `{ "name": "Nobody" }`,
+			want:        "false",
+		},
+		{
+			name:        "invoke my-exists-tool without name",
+			api:         "http://127.0.0.1:5000/api/tool/my-exists-tool/invoke",
+			requestBody: `{}`,
+			isErr:       true,
+		},
+	}
+
+	for _, tc := range invokeTcs {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body := tests.RunRequest(t, http.MethodPost, tc.api, bytes.NewBufferString(tc.requestBody), nil)
+			if resp.StatusCode != http.StatusOK {
+				if tc.isErr {
+					return
+				}
+				t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, body)
+			}
+			if tc.isErr {
+				t.Fatalf("expected an error, got %s", body)
+			}
+
+			var result map[string]any
+			if err := json.Unmarshal(body, &result); err != nil {
+				t.Fatalf("error parsing response body: %s", err)
+			}
+			got, ok := result["result"].(string)
+			if !ok {
+				t.Fatalf("unable to find result in response body")
+			}
+			if !strings.Contains(got, tc.want) {
+				t.Fatalf("unexpected value: got %q, want it to contain %q", got, tc.want)
+			}
+		})
+	}
 }
 
 func runToolDeleteInvokeTest(t *testing.T, delete1Want, deleteManyWant string) {
@@ -233,6 +317,28 @@ func runToolInsertInvokeTest(t *testing.T, insert1Want, insertManyWant string) {
 			want:          insertManyWant,
 			isErr:         false,
 		},
+		{
+			name:          "invoke my-insert-many-tool with some duplicates",
+			api:           "http://127.0.0.1:5000/api/tool/my-insert-many-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(`{ "data" : "[{ \"_id\": { \"$oid\": \"68667a6436ec7d0363668db7\"} , \"id\" : 204 }, { \"_id\" : { \"$oid\": \"68667a6436ec7d0363668dba\"}, \"id\" : 205 }]" }`)),
+			want:          fmt.Sprintf(`{"succeeded":1,"failed":1,"errors":[{"index":0,"message":"E11000 duplicate key error collection: %s.test_collection index: _id_ dup key: { _id: ObjectId('68667a6436ec7d0363668db7') }"}],"results":[null,"68667a6436ec7d0363668dba"]}`, MongoDbDatabase),
+			isErr:         false,
+		},
+		{
+			name:          "invoke my-insert-many-tool with only duplicates",
+			api:           "http://127.0.0.1:5000/api/tool/my-insert-many-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(`{ "data" : "[{ \"_id\": { \"$oid\": \"68667a6436ec7d0363668db8\"} , \"id\" : 206 }, { \"_id\" : { \"$oid\": \"68667a6436ec7d0363668db9\"}, \"id\" : 207 }]" }`)),
+			isErr:         true,
+		},
+		{
+			name:          "invoke my-insert-many-fail-fast-tool with a duplicate",
+			api:           "http://127.0.0.1:5000/api/tool/my-insert-many-fail-fast-tool/invoke",
+			requestHeader: map[string]string{},
+			requestBody:   bytes.NewBuffer([]byte(`{ "data" : "[{ \"_id\": { \"$oid\": \"68667a6436ec7d0363668db8\"} , \"id\" : 208 }, { \"_id\" : { \"$oid\": \"68667a6436ec7d0363668dbb\"}, \"id\" : 209 }]" }`)),
+			isErr:         true,
+		},
 	}
 
 	for _, tc := range invokeTcs {
@@ -662,6 +768,16 @@ func getMongoDBToolsConfig(sourceConfig map[string]any, toolKind string) map[str
 				"canonical":    true,
 				"database":     MongoDbDatabase,
 			},
+			"my-insert-many-fail-fast-tool": map[string]any{
+				"kind":         "mongodb-insert-many",
+				"source":       "my-instance",
+				"description":  "Tool to test inserting multiple entries all or nothing.",
+				"authRequired": []string{},
+				"collection":   "test_collection",
+				"canonical":    true,
+				"failFast":     true,
+				"database":     MongoDbDatabase,
+			},
 			"my-update-one-tool": map[string]any{
 				"kind":          "mongodb-update-one",
 				"source":        "my-instance",
@@ -747,6 +863,57 @@ func getMongoDBToolsConfig(sourceConfig map[string]any, toolKind string) map[str
 				},
 				"database": MongoDbDatabase,
 			},
+			"my-count-tool": map[string]any{
+				"kind":          "mongodb-count-documents",
+				"source":        "my-instance",
+				"description":   "Tool to count documents by name.",
+				"authRequired":  []string{},
+				"collection":    "test_collection",
+				"filterPayload": `{ "name": {{json .name}} }`,
+				"filterParams": []map[string]any{
+					{
+						"name":        "name",
+						"type":        "string",
+						"description": "user name",
+					},
+				},
+				"database": MongoDbDatabase,
+			},
+			"my-capped-count-tool": map[string]any{
+				"kind":          "mongodb-count-documents",
+				"source":        "my-instance",
+				"description":   "Tool to count documents up to a limit.",
+				"authRequired":  []string{},
+				"collection":    "test_collection",
+				"filterPayload": `{}`,
+				"limit":         1,
+				"hint":          `{ "_id": 1 }`,
+				"database":      MongoDbDatabase,
+			},
+			"my-estimated-count-tool": map[string]any{
+				"kind":         "mongodb-count-documents",
+				"source":       "my-instance",
+				"description":  "Tool to estimate the number of documents.",
+				"authRequired": []string{},
+				"collection":   "test_collection",
+				"database":     MongoDbDatabase,
+			},
+			"my-exists-tool": map[string]any{
+				"kind":          "mongodb-exists",
+				"source":        "my-instance",
+				"description":   "Tool to check whether a document exists by name.",
+				"authRequired":  []string{},
+				"collection":    "test_collection",
+				"filterPayload": `{ "name": {{json .name}} }`,
+				"filterParams": []map[string]any{
+					{
+						"name":        "name",
+						"type":        "string",
+						"description": "user name",
+					},
+				},
+				"database": MongoDbDatabase,
+			},
 			"my-read-write-aggregate-tool": map[string]any{
 				"kind":            "mongodb-aggregate",
 				"source":          "my-instance",
@@ -15,11 +15,13 @@
 package mysql
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
-	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -94,6 +96,7 @@ func TestMySQLToolEndpoints(t *testing.T) {
 	tableNameParam := "param_table_" + strings.ReplaceAll(uuid.New().String(), "-", "")
 	tableNameAuth := "auth_table_" + strings.ReplaceAll(uuid.New().String(), "-", "")
 	tableNameTemplateParam := "template_param_table_" + strings.ReplaceAll(uuid.New().String(), "-", "")
+	tableNameTypes := "types_table_" + strings.ReplaceAll(uuid.New().String(), "-", "")
 
 	// set up data for param tool
 	createParamTableStmt, insertParamTableStmt, paramToolStmt, idParamToolStmt, nameParamToolStmt, arrayToolStmt, paramTestParams := tests.GetMySQLParamToolInfo(tableNameParam)
@@ -105,6 +108,10 @@ func TestMySQLToolEndpoints(t *testing.T) {
 	teardownTable2 := tests.SetupMySQLTable(t, ctx, pool, createAuthTableStmt, insertAuthTableStmt, tableNameAuth, authTestParams)
 	defer teardownTable2(t)
 
+	// set up data for the MySQL-specific types
+	teardownTable3 := setUpMySQLTypesTable(t, ctx, pool, tableNameTypes)
+	defer teardownTable3(t)
+
 	// Write config into a file and pass it to command
 	toolsFile := tests.GetToolsConfig(sourceConfig, MySQLToolKind, paramToolStmt, idParamToolStmt, nameParamToolStmt, arrayToolStmt, authToolStmt)
 	toolsFile = tests.AddMySqlExecuteSqlConfig(t, toolsFile)
@@ -112,6 +119,7 @@ func TestMySQLToolEndpoints(t *testing.T) {
 	toolsFile = tests.AddTemplateParamConfig(t, toolsFile, MySQLToolKind, tmplSelectCombined, tmplSelectFilterCombined, "")
 
 	toolsFile = tests.AddMySQLPrebuiltToolConfig(t, toolsFile)
+	toolsFile = addTypesToolConfig(t, toolsFile, tableNameTypes)
 
 	cmd, cleanup, err := tests.StartCmd(ctx, toolsFile, args...)
 	if err != nil {
@@ -121,7 +129,7 @@ func TestMySQLToolEndpoints(t *testing.T) {
 
 	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
-	out, err := testutils.WaitForString(waitCtx, regexp.MustCompile(`Server ready to serve`), cmd.Out)
+	_, out, err := testutils.WaitForServerReady(waitCtx, cmd.Out)
 	if err != nil {
 		t.Logf("toolbox command logs: \n%s", out)
 		t.Fatalf("toolbox didn't start successfully: %s", err)
@@ -142,4 +150,63 @@ func TestMySQLToolEndpoints(t *testing.T) {
 	tests.RunMySQLListActiveQueriesTest(t, ctx, pool)
 	tests.RunMySQLListTablesMissingUniqueIndexes(t, ctx, pool, MySQLDatabase)
 	tests.RunMySQLListTableFragmentationTest(t, MySQLDatabase, tableNameParam, tableNameAuth)
+	tests.RunSampleTableTest(t, tableNameParam)
+	runMySQLTypesTest(t)
+}
+
+func setUpMySQLTypesTable(t *testing.T, ctx context.Context, pool *sql.DB, tableName string) func(*testing.T) {
+	createStatement := fmt.Sprintf(`CREATE TABLE %s (
+		id INT PRIMARY KEY,
+		founded YEAR,
+		size ENUM('small', 'medium', 'large'),
+		perms SET('read', 'write', 'admin'),
+		location POINT
+	)`, tableName)
+	if _, err := pool.ExecContext(ctx, createStatement); err != nil {
+		t.Fatalf("unable to create test table %s: %s", tableName, err)
+	}
+	insertStatement := fmt.Sprintf(`INSERT INTO %s VALUES
+		(1, 1999, 'medium', 'read,write', ST_GeomFromText('POINT(1 2)')),
+		(2, NULL, NULL, '', NULL)`, tableName)
+	if _, err := pool.ExecContext(ctx, insertStatement); err != nil {
+		t.Fatalf("unable to insert test data: %s", err)
+	}
+	return func(t *testing.T) {
+		if _, err := pool.ExecContext(ctx, fmt.Sprintf("DROP TABLE IF EXISTS %s;", tableName)); err != nil {
+			t.Errorf("Teardown failed: %s", err)
+		}
+	}
+}
+
+func addTypesToolConfig(t *testing.T, config map[string]any, tableName string) map[string]any {
+	tools, ok := config["tools"].(map[string]any)
+	if !ok {
+		t.Fatalf("unable to get tools from config")
+	}
+	tools["my-types-tool"] = map[string]any{
+		"kind":        MySQLToolKind,
+		"source":      "my-instance",
+		"description": "Tool to test the MySQL-specific types.",
+		"statement":   fmt.Sprintf("SELECT * FROM %s ORDER BY id", tableName),
+	}
+	config["tools"] = tools
+	return config
+}
+
+func runMySQLTypesTest(t *testing.T) {
+	want := `[{"founded":1999,"id":1,"location":"POINT(1 2)","perms":["read","write"],"size":"medium"},` +
+		`{"founded":null,"id":2,"location":null,"perms":[],"size":null}]`
+	resp, body := tests.RunRequest(t, http.MethodPost, "http://127.0.0.1:5000/api/tool/my-types-tool/invoke", bytes.NewBufferString(`{}`), nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, http.StatusOK, body)
+	}
+	var envelope struct {
+		Result string `json:"result"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		t.Fatalf("error parsing response body: %s", err)
+	}
+	if envelope.Result != want {
+		t.Fatalf("unexpected result: got %s, want %s", envelope.Result, want)
+	}
 }
@@ -22,7 +22,6 @@ import (
 	"net/http"
 	"os"
 	"reflect"
-	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -123,7 +122,7 @@ func TestNeo4jToolEndpoints(t *testing.T) {
 
 	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
-	out, err := testutils.WaitForString(waitCtx, regexp.MustCompile(`Server ready to serve`), cmd.Out)
+	_, out, err := testutils.WaitForServerReady(waitCtx, cmd.Out)
 	if err != nil {
 		t.Logf("toolbox command logs: \n%s", out)
 		t.Fatalf("toolbox didn't start successfully: %s", err)
@@ -159,6 +158,7 @@ func TestNeo4jToolEndpoints(t *testing.T) {
 							"required":    true,
 							"description": "The cypher to execute.",
 							"authSources": []any{},
+							"examples":    []any{"example"},
 						},
 						map[string]any{
 							"name":        "dry_run",
@@ -166,6 +166,7 @@ func TestNeo4jToolEndpoints(t *testing.T) {
 							"required":    false,
 							"description": "If set to true, the query will be validated and information about the execution will be returned without running the query. Defaults to false.",
 							"authSources": []any{},
+							"examples":    []any{false},
 						},
 					},
 					"authRequired": []any{},
@@ -16,34 +16,57 @@ package tests
 
 /* Configurations for RunToolInvokeTest()  */
 
-// InvokeTestConfig represents the various configuration options for RunToolInvokeTest()
-type InvokeTestConfig struct {
-	myToolId3NameAliceWant   string
-	myToolById4Want          string
-	nullWant                 string
-	myArrayToolWant          string
-	supportSelect1Want       bool
-	supportOptionalNullParam bool
-	supportArrayParam        bool
-	supportClientAuth        bool
-	supportSelect1Auth       bool
+// InvokeExpectations are the invoke subtests run by RunToolInvokeExpectations
+// and RunToolInvokeTestCases, with their expected responses. Options adjust
+// them by subtest name, in the order they are given.
+type InvokeExpectations struct {
+	// Cases are the subtests, run in order. Cases with Skip set are reported
+	// as skipped.
+	Cases []InvokeTestCase
+
+	// unknown are the names that options referred to, but that no case had
+	// when the option was applied.
+	unknown []string
 }
 
-type InvokeTestOption func(*InvokeTestConfig)
+// Case returns the subtest with the given name, or nil if there is none.
+func (e *InvokeExpectations) Case(name string) *InvokeTestCase {
+	for i := range e.Cases {
+		if e.Cases[i].Name == name {
+			return &e.Cases[i]
+		}
+	}
+	return nil
+}
+
+// update applies f to the subtest with the given name. Unknown names fail the
+// test once it runs, rather than leaving a misspelled override unnoticed.
+func (e *InvokeExpectations) update(name string, f func(*InvokeTestCase)) {
+	tc := e.Case(name)
+	if tc == nil {
+		e.unknown = append(e.unknown, name)
+		return
+	}
+	f(tc)
+}
+
+// InvokeTestOption adjusts the InvokeExpectations of RunToolInvokeTest or
+// RunToolInvokeTestCases.
+type InvokeTestOption func(*InvokeExpectations)
 
 // WithMyToolId3NameAliceWant represents the response value for my-tool with id=3 and name=Alice.
 // e.g. tests.RunToolInvokeTest(t, select1Want, tests.WithMyToolId3NameAliceWant("custom"))
 func WithMyToolId3NameAliceWant(s string) InvokeTestOption {
-	return func(c *InvokeTestConfig) {
-		c.myToolId3NameAliceWant = s
+	return func(e *InvokeExpectations) {
+		e.update(invokeMyToolTest, func(tc *InvokeTestCase) { tc.WantBody = s })
 	}
 }
 
 // WithMyArrayToolWant represents the response value for my-array-tool.
 // e.g. tests.RunToolInvokeTest(t, select1Want, tests.WithMyArrayToolWant("custom"))
 func WithMyArrayToolWant(s string) InvokeTestOption {
-	return func(c *InvokeTestConfig) {
-		c.myArrayToolWant = s
+	return func(e *InvokeExpectations) {
+		e.update(invokeMyArrayToolTest, func(tc *InvokeTestCase) { tc.WantBody = s })
 	}
 }
 
@@ -51,57 +74,94 @@ func WithMyArrayToolWant(s string) InvokeTestOption {
 // This response includes a null value column.
 // e.g. tests.RunToolInvokeTest(t, select1Want, tests.WithMyToolById4Want("custom"))
 func WithMyToolById4Want(s string) InvokeTestOption {
-	return func(c *InvokeTestConfig) {
-		c.myToolById4Want = s
+	return func(e *InvokeExpectations) {
+		e.update(invokeMyToolById4Test, func(tc *InvokeTestCase) { tc.WantBody = s })
 	}
 }
 
 // WithNullWant represents a response value of null string.
 // e.g. tests.RunToolInvokeTest(t, select1Want, tests.WithNullWant("custom"))
 func WithNullWant(s string) InvokeTestOption {
-	return func(c *InvokeTestConfig) {
-		c.nullWant = s
+	return func(e *InvokeExpectations) {
+		e.update(invokeMyToolByNameTest, func(tc *InvokeTestCase) { tc.WantBody = s })
 	}
 }
 
 // DisableOptionalNullParamTest disables tests for optional null parameters.
 // e.g. tests.RunToolInvokeTest(t, select1Want, tests.DisableOptionalNullParamTest())
 func DisableOptionalNullParamTest() InvokeTestOption {
-	return func(c *InvokeTestConfig) {
-		c.supportOptionalNullParam = false
-	}
+	return SkipInvokeTests(invokeMyToolByNameTest)
 }
 
 // DisableArrayTest disables tests for sources that do not support array.
 // e.g. tests.RunToolInvokeTest(t, select1Want, tests.DisableArrayTest())
 func DisableArrayTest() InvokeTestOption {
-	return func(c *InvokeTestConfig) {
-		c.supportArrayParam = false
-	}
+	return SkipInvokeTests(invokeMyArrayToolTest)
 }
 
 // DisableSelect1Test disables tests for sources that do not support SELECT 1 query.
 // e.g. tests.RunToolInvokeTest(t, "", tests.DisableSelect1Test())
 func DisableSelect1Test() InvokeTestOption {
-	return func(c *InvokeTestConfig) {
-		c.supportSelect1Want = false
-	}
+	return SkipInvokeTests(invokeMySimpleToolTest)
 }
 
 // DisableSelect1AuthTest disables auth tests for sources that do not support SELECT 1 query.
 // e.g. tests.RunToolInvokeTest(t, "", tests.DisableSelect1AuthTest())
 func DisableSelect1AuthTest() InvokeTestOption {
-	return func(c *InvokeTestConfig) {
-		c.supportSelect1Auth = false
-	}
+	return SkipInvokeTests(select1AuthTests...)
 }
 
 // EnableClientAuthTest runs the client authorization tests.
 // Only enable it if your source supports the `useClientOAuth` configuration.
 // Currently, this should only be used with the BigQuery tests.
 func EnableClientAuthTest() InvokeTestOption {
-	return func(c *InvokeTestConfig) {
-		c.supportClientAuth = true
+	return func(e *InvokeExpectations) {
+		for _, name := range clientAuthTests {
+			e.update(name, func(tc *InvokeTestCase) { tc.Skip = false })
+		}
+	}
+}
+
+// WithInvokeRequestBody replaces the request body of the invoke subtest with the given name.
+// e.g. tests.RunToolInvokeTest(t, select1Want, tests.WithInvokeRequestBody("invoke my-tool", `{"id": 3}`))
+func WithInvokeRequestBody(name, body string) InvokeTestOption {
+	return func(e *InvokeExpectations) {
+		e.update(name, func(tc *InvokeTestCase) { tc.RequestBody = body })
+	}
+}
+
+// WithInvokeWantStatusCode replaces the expected status code of the invoke subtest with the given name.
+// e.g. tests.RunToolInvokeTest(t, select1Want, tests.WithInvokeWantStatusCode("invoke my-tool", http.StatusBadRequest))
+func WithInvokeWantStatusCode(name string, code int) InvokeTestOption {
+	return func(e *InvokeExpectations) {
+		e.update(name, func(tc *InvokeTestCase) { tc.WantStatusCode = code })
+	}
+}
+
+// WithInvokeWantError asserts that the error of the response of the invoke subtest with the given name contains substr.
+// e.g. tests.RunToolInvokeTest(t, select1Want, tests.WithInvokeWantError("Invoke my-tool without parameters", "parameter \"id\" is required"))
+func WithInvokeWantError(name, substr string) InvokeTestOption {
+	return func(e *InvokeExpectations) {
+		e.update(name, func(tc *InvokeTestCase) { tc.WantErrorContains = substr })
+	}
+}
+
+// SkipInvokeTests skips the invoke subtests with the given names.
+// e.g. tests.RunToolInvokeTest(t, select1Want, tests.SkipInvokeTests("invoke my-tool-by-name with nil response"))
+func SkipInvokeTests(names ...string) InvokeTestOption {
+	return func(e *InvokeExpectations) {
+		for _, name := range names {
+			e.update(name, func(tc *InvokeTestCase) { tc.Skip = true })
+		}
+	}
+}
+
+// WithInvokeTests adds invoke subtests, which run after the others. Later
+// options can refer to them by name.
+// e.g. tests.RunToolInvokeTest(t, select1Want, tests.WithInvokeTests(tests.InvokeTestCase{Name: "invoke my-other-tool", Tool: "my-other-tool"}))
+func WithInvokeTests(tcs ...InvokeTestCase) InvokeTestOption {
+	return func(e *InvokeExpectations) {
+		e.Cases = append(e.Cases, tcs...)
 	}
 }
 
@@ -0,0 +1,125 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// skipped returns the names of the skipped cases of e.
+func skipped(e *InvokeExpectations) []string {
+	var names []string
+	for _, tc := range e.Cases {
+		if tc.Skip {
+			names = append(names, tc.Name)
+		}
+	}
+	return names
+}
+
+func TestNewInvokeExpectations(t *testing.T) {
+	e := NewInvokeExpectations(`[{"1":1}]`)
+
+	names := make(map[string]bool)
+	for _, tc := range e.Cases {
+		if names[tc.Name] {
+			t.Fatalf("duplicate subtest %q", tc.Name)
+		}
+		names[tc.Name] = true
+	}
+	for _, name := range []string{invokeMySimpleToolTest, select1AuthTests[2], clientAuthTests[0]} {
+		if got := e.Case(name).WantBody; got != `[{"1":1}]` {
+			t.Fatalf("got result %q for %q, want the SELECT 1 result", got, name)
+		}
+	}
+	if got, want := e.Case(invokeMyToolById4Test).WantBody, `[{"id":4,"name":null}]`; got != want {
+		t.Fatalf("got result %q, want %q", got, want)
+	}
+	if diff := cmp.Diff(clientAuthTests, skipped(e)); diff != "" {
+		t.Fatalf("unexpected skipped subtests (-want +got):\n%s", diff)
+	}
+	if e.Case("invoke my-missing-tool") != nil {
+		t.Fatalf("expected no case for an unknown name")
+	}
+}
+
+func TestInvokeTestOptions(t *testing.T) {
+	e := NewInvokeExpectations("")
+	options := []InvokeTestOption{
+		WithMyToolId3NameAliceWant("alice"),
+		WithMyToolById4Want("id4"),
+		WithNullWant("[]"),
+		WithMyArrayToolWant("array"),
+		DisableArrayTest(),
+		DisableSelect1Test(),
+		DisableSelect1AuthTest(),
+		EnableClientAuthTest(),
+		WithInvokeTests(InvokeTestCase{Name: "invoke my-extra-tool", Tool: "my-extra-tool"}),
+		WithInvokeRequestBody("invoke my-extra-tool", `{"id": 1}`),
+		WithInvokeWantStatusCode("invoke my-extra-tool", http.StatusBadRequest),
+		WithInvokeWantError("invoke my-extra-tool", "invalid id"),
+		SkipInvokeTests("Invoke my-tool without parameters"),
+	}
+	for _, option := range options {
+		option(e)
+	}
+
+	for name, want := range map[string]string{
+		invokeMyToolTest:       "alice",
+		invokeMyToolById4Test:  "id4",
+		invokeMyToolByNameTest: "[]",
+		invokeMyArrayToolTest:  "array",
+	} {
+		if got := e.Case(name).WantBody; got != want {
+			t.Fatalf("got result %q for %q, want %q", got, name, want)
+		}
+	}
+	wantSkipped := append([]string{invokeMySimpleToolTest, "Invoke my-tool without parameters", invokeMyArrayToolTest}, select1AuthTests...)
+	if diff := cmp.Diff(wantSkipped, skipped(e)); diff != "" {
+		t.Fatalf("unexpected skipped subtests (-want +got):\n%s", diff)
+	}
+	want := InvokeTestCase{
+		Name:              "invoke my-extra-tool",
+		Tool:              "my-extra-tool",
+		RequestBody:       `{"id": 1}`,
+		WantStatusCode:    http.StatusBadRequest,
+		WantErrorContains: "invalid id",
+	}
+	if diff := cmp.Diff(want, *e.Case("invoke my-extra-tool")); diff != "" {
+		t.Fatalf("unexpected extra subtest (-want +got):\n%s", diff)
+	}
+	if len(e.unknown) != 0 {
+		t.Fatalf("unexpected unknown subtests: %q", e.unknown)
+	}
+}
+
+func TestInvokeTestOptionsUnknownName(t *testing.T) {
+	e := &InvokeExpectations{Cases: []InvokeTestCase{{Name: "invoke my-wait-tool"}}}
+	for _, option := range []InvokeTestOption{
+		// the MySQL expectations do not apply to custom cases
+		WithNullWant("null"),
+		// extra cases can only be referred to once added
+		SkipInvokeTests("invoke my-extra-tool"),
+		WithInvokeTests(InvokeTestCase{Name: "invoke my-extra-tool"}),
+	} {
+		option(e)
+	}
+	if diff := cmp.Diff([]string{invokeMyToolByNameTest, "invoke my-extra-tool"}, e.unknown); diff != "" {
+		t.Fatalf("unexpected unknown subtests (-want +got):\n%s", diff)
+	}
+}
@@ -24,7 +24,7 @@ import (
 	"net/url"
 	"os"
 	"reflect"
-	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -33,9 +33,15 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/uuid"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	sourcepg "github.com/googleapis/genai-toolbox/internal/sources/postgres"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/postgres/postgressql"
 	"github.com/googleapis/genai-toolbox/tests"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 var (
@@ -110,6 +116,12 @@ func addPrebuiltToolConfig(t *testing.T, config map[string]any) map[string]any {
 		"source": "my-instance",
 	}
 
+	tools["sample_table"] = map[string]any{
+		"kind":        "postgres-sample-table",
+		"source":      "my-instance",
+		"description": "Samples the rows of a table.",
+	}
+
 	config["tools"] = tools
 	return config
 }
@@ -136,7 +148,9 @@ func TestPostgres(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
 	defer cancel()
 
-	var args []string
+	// forward the client name header as the application_name, and guard the
+	// admin endpoints with the google auth service, for the service account
+	args := []string{"--client-attribution", "header", "--admin-auth-service", "my-google-auth", "--admin-emails", tests.ServiceAccountEmail}
 
 	pool, err := initPostgresConnectionPool(PostgresHost, PostgresPort, PostgresUser, PostgresPass, PostgresDatabase)
 	if err != nil {
@@ -150,6 +164,9 @@ func TestPostgres(t *testing.T) {
 	tableNameParam := "param_table_" + strings.ReplaceAll(uuid.New().String(), "-", "")
 	tableNameAuth := "auth_table_" + strings.ReplaceAll(uuid.New().String(), "-", "")
 	tableNameTemplateParam := "template_param_table_" + strings.ReplaceAll(uuid.New().String(), "-", "")
+	tableNameJSON := "json_table_" + strings.ReplaceAll(uuid.New().String(), "-", "")
+	tableNameInsert := "insert_table_" + strings.ReplaceAll(uuid.New().String(), "-", "")
+	matviewName := "matview_" + strings.ReplaceAll(uuid.New().String(), "-", "")
 
 	// set up data for param tool
 	createParamTableStmt, insertParamTableStmt, paramToolStmt, idParamToolStmt, nameParamToolStmt, arrayToolStmt, paramTestParams := tests.GetPostgresSQLParamToolInfo(tableNameParam)
@@ -161,6 +178,18 @@ func TestPostgres(t *testing.T) {
 	teardownTable2 := tests.SetupPostgresSQLTable(t, ctx, pool, createAuthTableStmt, insertAuthTableStmt, tableNameAuth, authTestParams)
 	defer teardownTable2(t)
 
+	// set up table for json tool
+	teardownTable3 := setUpPostgresJSONTable(t, ctx, pool, tableNameJSON)
+	defer teardownTable3(t)
+
+	// set up table and view for insert rows tools
+	teardownTable4 := setUpPostgresInsertTable(t, ctx, pool, tableNameInsert)
+	defer teardownTable4(t)
+
+	// set up materialized views for refresh tools
+	teardownMatviews := setUpPostgresMaterializedViews(t, ctx, pool, matviewName)
+	defer teardownMatviews(t)
+
 	// Write config into a file and pass it to command
 	toolsFile := tests.GetToolsConfig(sourceConfig, PostgresToolKind, paramToolStmt, idParamToolStmt, nameParamToolStmt, arrayToolStmt, authToolStmt)
 	toolsFile = tests.AddExecuteSqlConfig(t, toolsFile, "postgres-execute-sql")
@@ -168,6 +197,18 @@ func TestPostgres(t *testing.T) {
 	toolsFile = tests.AddTemplateParamConfig(t, toolsFile, PostgresToolKind, tmplSelectCombined, tmplSelectFilterCombined, "")
 
 	toolsFile = addPrebuiltToolConfig(t, toolsFile)
+	toolsFile = addIntArrayToolConfig(t, toolsFile, tableNameParam)
+	toolsFile = addJSONToolConfig(t, toolsFile, tableNameJSON)
+	toolsFile = addAttributionToolConfig(t, toolsFile)
+	toolsFile = addSlowQueryToolConfig(t, toolsFile)
+	toolsFile = addSqlDiffToolConfig(t, toolsFile)
+	toolsFile = addInsertRowsToolConfig(t, toolsFile, tableNameInsert)
+	toolsFile = addMaterializedViewToolConfig(t, toolsFile, matviewName)
+	toolsFile = addLeakToolConfig(t, toolsFile)
+	toolsFile = addKeysetToolConfig(t, toolsFile, tableNameParam)
+	toolsFile = addSchemaScopeToolConfig(t, toolsFile)
+	toolsFile = addStickySessionToolConfig(t, toolsFile)
+	toolsFile = tests.AddMcpProtocolMatrixConfig(t, toolsFile)
 
 	cmd, cleanup, err := tests.StartCmd(ctx, toolsFile, args...)
 	if err != nil {
@@ -177,7 +218,7 @@ func TestPostgres(t *testing.T) {
 
 	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
-	out, err := testutils.WaitForString(waitCtx, regexp.MustCompile(`Server ready to serve`), cmd.Out)
+	_, out, err := testutils.WaitForServerReady(waitCtx, cmd.Out)
 	if err != nil {
 		t.Logf("toolbox command logs: \n%s", out)
 		t.Fatalf("toolbox didn't start successfully: %s", err)
@@ -187,18 +228,94 @@ func TestPostgres(t *testing.T) {
 	select1Want, mcpMyFailToolWant, createTableStatement, mcpSelect1Want := tests.GetPostgresWants()
 
 	// Run tests
-	tests.RunToolGetTest(t)
+	tests.RunToolGetTestByName(t, "my-simple-tool", map[string]any{
+		"my-simple-tool": map[string]any{
+			"description":  "Simple tool to test end to end functionality.",
+			"parameters":   []any{},
+			"authRequired": []any{},
+			"capabilities": map[string]any{
+				"supportsPreparedStatements": true,
+				"supportsArrayParams":        true,
+				"supportsTransactions":       true,
+				"maxIdentifierLength":        float64(63),
+			},
+		},
+	})
 	tests.RunToolInvokeTest(t, select1Want)
 	tests.RunMCPToolCallMethod(t, mcpMyFailToolWant, mcpSelect1Want)
+	tests.RunMCPProtocolMatrix(t,
+		tests.McpToolCall{
+			Name:        "call my-simple-tool",
+			Tool:        "my-simple-tool",
+			Arguments:   map[string]any{},
+			WantContent: `{"?column?":1}`,
+		},
+		tests.McpToolCall{
+			Name:        "call my-tool",
+			Tool:        "my-tool",
+			Arguments:   map[string]any{"id": 3, "name": "Alice"},
+			WantContent: "{\"id\":1,\"name\":\"Alice\"}\n{\"id\":3,\"name\":\"Sid\"}",
+		},
+		tests.McpToolCall{
+			Name:        "call my-fail-tool",
+			Tool:        "my-fail-tool",
+			Arguments:   map[string]any{"id": 1},
+			WantContent: "syntax error",
+			WantError:   true,
+		},
+	)
 	tests.RunExecuteSqlToolInvokeTest(t, createTableStatement, select1Want)
 	tests.RunToolInvokeWithTemplateParameters(t, tableNameTemplateParam)
 
 	// Run specific Postgres tool tests
 	runPostgresListTablesTest(t, tableNameParam, tableNameAuth)
 	runPostgresListViewsTest(t, ctx, pool, tableNameParam)
+	tests.RunSampleTableTest(t, tableNameParam)
 	runPostgresListActiveQueriesTest(t, ctx, pool)
 	runPostgresListAvailableExtensionsTest(t)
 	runPostgresListInstalledExtensionsTest(t)
+	runPostgresIntArrayTest(t)
+	runPostgresJSONTest(t)
+	runPostgresClientAttributionTest(t)
+	runPostgresSlowQueryExplainTest(t)
+	runPostgresSqlDiffTest(t)
+	runPostgresInsertRowsTest(t, ctx, pool, tableNameInsert)
+	runPostgresMaterializedViewTest(t, ctx, pool, matviewName)
+	runPostgresLeakTest(t, ctx, pool)
+	runPostgresKeysetPaginationTest(t)
+	runPostgresSchemaScopeTest(t, tableNameParam)
+	runPostgresStickySessionTest(t)
+	runPostgresPoolExhaustionTest(t, ctx)
+}
+
+// addIntArrayToolConfig adds a tool that binds an integer array parameter
+func addIntArrayToolConfig(t *testing.T, config map[string]any, tableName string) map[string]any {
+	tools, ok := config["tools"].(map[string]any)
+	if !ok {
+		t.Fatalf("unable to get tools from config")
+	}
+	tools["my-int-array-tool"] = map[string]any{
+		"kind":        PostgresToolKind,
+		"source":      "my-instance",
+		"description": "Tool to select rows by a list of ids.",
+		"statement":   fmt.Sprintf("SELECT * FROM %s WHERE id = ANY($1) ORDER BY id;", tableName),
+		"parameters": []map[string]any{
+			{
+				"name":        "ids",
+				"type":        "array",
+				"description": "ids to select",
+				"minItems":    1,
+				"maxItems":    3,
+				"items": map[string]any{
+					"name":        "id",
+					"type":        "integer",
+					"description": "an id",
+				},
+			},
+		},
+	}
+	config["tools"] = tools
+	return config
 }
 
 func runPostgresListTablesTest(t *testing.T, tableNameParam, tableNameAuth string) {
@@ -547,6 +664,67 @@ func setUpPostgresViews(t *testing.T, ctx context.Context, pool *pgxpool.Pool, v
 	}
 }
 
+func runPostgresIntArrayTest(t *testing.T) {
+	invokeTcs := []struct {
+		name           string
+		requestBody    io.Reader
+		wantStatusCode int
+		want           string
+	}{
+		{
+			name:           "invoke my-int-array-tool",
+			requestBody:    bytes.NewBuffer([]byte(`{"ids": [1, 3]}`)),
+			wantStatusCode: http.StatusOK,
+			want:           `[{"id":1,"name":"Alice"},{"id":3,"name":"Sid"}]`,
+		},
+		{
+			name:           "invoke my-int-array-tool with mixed types",
+			requestBody:    bytes.NewBuffer([]byte(`{"ids": [1, "three"]}`)),
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "invoke my-int-array-tool with too many items",
+			requestBody:    bytes.NewBuffer([]byte(`{"ids": [1, 2, 3, 4]}`)),
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tc := range invokeTcs {
+		t.Run(tc.name, func(t *testing.T) {
+			const api = "http://127.0.0.1:5000/api/tool/my-int-array-tool/invoke"
+			req, err := http.NewRequest(http.MethodPost, api, tc.requestBody)
+			if err != nil {
+				t.Fatalf("unable to create request: %v", err)
+			}
+			req.Header.Add("Content-type", "application/json")
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("unable to send request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tc.wantStatusCode {
+				body, _ := io.ReadAll(resp.Body)
+				t.Fatalf("wrong status code: got %d, want %d, body: %s", resp.StatusCode, tc.wantStatusCode, string(body))
+			}
+			if tc.wantStatusCode != http.StatusOK {
+				return
+			}
+
+			var body map[string]any
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("error parsing response body: %v", err)
+			}
+			got, ok := body["result"].(string)
+			if !ok {
+				t.Fatalf("unable to find result in response body")
+			}
+			if got != tc.want {
+				t.Fatalf("unexpected value: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
 func runPostgresListViewsTest(t *testing.T, ctx context.Context, pool *pgxpool.Pool, tableName string) {
 	viewName1 := "test_view_1" + strings.ReplaceAll(uuid.New().String(), "-", "")
 	dropViewfunc1 := setUpPostgresViews(t, ctx, pool, viewName1, tableName)
@@ -695,3 +873,1017 @@ func runPostgresListInstalledExtensionsTest(t *testing.T) {
 		})
 	}
 }
+
+// setUpPostgresJSONTable creates a table with a jsonb column.
+func setUpPostgresJSONTable(t *testing.T, ctx context.Context, pool *pgxpool.Pool, tableName string) func(*testing.T) {
+	if _, err := pool.Exec(ctx, fmt.Sprintf("CREATE TABLE %s (id SERIAL PRIMARY KEY, doc JSONB NOT NULL);", tableName)); err != nil {
+		t.Fatalf("unable to create json table: %s", err)
+	}
+	return func(t *testing.T) {
+		if _, err := pool.Exec(ctx, fmt.Sprintf("DROP TABLE IF EXISTS %s;", tableName)); err != nil {
+			t.Errorf("Teardown failed: %s", err)
+		}
+	}
+}
+
+// addJSONToolConfig adds a tool that inserts a json parameter into a jsonb
+// column and reads it back
+func addJSONToolConfig(t *testing.T, config map[string]any, tableName string) map[string]any {
+	tools, ok := config["tools"].(map[string]any)
+	if !ok {
+		t.Fatalf("unable to get tools from config")
+	}
+	tools["my-json-tool"] = map[string]any{
+		"kind":        PostgresToolKind,
+		"source":      "my-instance",
+		"description": "Tool to store a document and read it back.",
+		"statement":   fmt.Sprintf("INSERT INTO %s (doc) VALUES ($1) RETURNING doc;", tableName),
+		"parameters": []map[string]any{
+			{
+				"name":        "doc",
+				"type":        "json",
+				"description": "the document to store",
+			},
+		},
+	}
+	config["tools"] = tools
+	return config
+}
+
+func runPostgresJSONTest(t *testing.T) {
+	invokeTcs := []struct {
+		name           string
+		requestBody    io.Reader
+		wantStatusCode int
+		want           string
+	}{
+		{
+			name:           "invoke my-json-tool with an object",
+			requestBody:    bytes.NewBuffer([]byte(`{"doc": {"tags": ["a", "b"], "id": 7, "note": "it's"}}`)),
+			wantStatusCode: http.StatusOK,
+			want:           `[{"doc":{"id":7,"note":"it's","tags":["a","b"]}}]`,
+		},
+		{
+			name:           "invoke my-json-tool with an array",
+			requestBody:    bytes.NewBuffer([]byte(`{"doc": [1, {"a": null}]}`)),
+			wantStatusCode: http.StatusOK,
+			want:           `[{"doc":[1,{"a":null}]}]`,
+		},
+		{
+			name:           "invoke my-json-tool without a document",
+			requestBody:    bytes.NewBuffer([]byte(`{}`)),
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tc := range invokeTcs {
+		t.Run(tc.name, func(t *testing.T) {
+			const api = "http://127.0.0.1:5000/api/tool/my-json-tool/invoke"
+			req, err := http.NewRequest(http.MethodPost, api, tc.requestBody)
+			if err != nil {
+				t.Fatalf("unable to create request: %v", err)
+			}
+			req.Header.Add("Content-type", "application/json")
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("unable to send request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tc.wantStatusCode {
+				body, _ := io.ReadAll(resp.Body)
+				t.Fatalf("wrong status code: got %d, want %d, body: %s", resp.StatusCode, tc.wantStatusCode, string(body))
+			}
+			if tc.wantStatusCode != http.StatusOK {
+				return
+			}
+
+			var body map[string]any
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("error parsing response body: %v", err)
+			}
+			got, ok := body["result"].(string)
+			if !ok {
+				t.Fatalf("unable to find result in response body")
+			}
+			if got != tc.want {
+				t.Fatalf("unexpected value: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+// addAttributionToolConfig adds a tool that returns the application_name of
+// its own query in pg_stat_activity
+func addAttributionToolConfig(t *testing.T, config map[string]any) map[string]any {
+	tools, ok := config["tools"].(map[string]any)
+	if !ok {
+		t.Fatalf("unable to get tools from config")
+	}
+	tools["my-attribution-tool"] = map[string]any{
+		"kind":        PostgresToolKind,
+		"source":      "my-instance",
+		"description": "Tool to return the application_name of the running query.",
+		"statement":   "SELECT application_name FROM pg_stat_activity WHERE pid = pg_backend_pid() AND state = 'active';",
+	}
+	config["tools"] = tools
+	return config
+}
+
+func runPostgresClientAttributionTest(t *testing.T) {
+	invokeTcs := []struct {
+		name       string
+		clientName string
+		want       string
+	}{
+		{
+			name:       "invoke my-attribution-tool with a client name",
+			clientName: "Billing Agent",
+			want:       `[{"application_name":"billing_agent"}]`,
+		},
+		{
+			name:       "invoke my-attribution-tool with another client name",
+			clientName: "ops-agent",
+			want:       `[{"application_name":"ops-agent"}]`,
+		},
+		{
+			name: "invoke my-attribution-tool without a client name",
+			want: `[{"application_name":"genai-toolbox/`,
+		},
+	}
+	for _, tc := range invokeTcs {
+		t.Run(tc.name, func(t *testing.T) {
+			const api = "http://127.0.0.1:5000/api/tool/my-attribution-tool/invoke"
+			req, err := http.NewRequest(http.MethodPost, api, bytes.NewBuffer([]byte(`{}`)))
+			if err != nil {
+				t.Fatalf("unable to create request: %v", err)
+			}
+			req.Header.Add("Content-type", "application/json")
+			if tc.clientName != "" {
+				req.Header.Add("X-Toolbox-Client-Name", tc.clientName)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("unable to send request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				body, _ := io.ReadAll(resp.Body)
+				t.Fatalf("wrong status code: got %d, want %d, body: %s", resp.StatusCode, http.StatusOK, string(body))
+			}
+			var body map[string]any
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("error parsing response body: %v", err)
+			}
+			got, ok := body["result"].(string)
+			if !ok {
+				t.Fatalf("unable to find result in response body")
+			}
+			if !strings.HasPrefix(got, tc.want) {
+				t.Fatalf("unexpected value: got %q, want prefix %q", got, tc.want)
+			}
+		})
+	}
+}
+
+// addSlowQueryToolConfig adds a tool that sleeps for longer than its
+// slowQueryExplain threshold
+func addSlowQueryToolConfig(t *testing.T, config map[string]any) map[string]any {
+	tools, ok := config["tools"].(map[string]any)
+	if !ok {
+		t.Fatalf("unable to get tools from config")
+	}
+	tools["my-slow-tool"] = map[string]any{
+		"kind":        PostgresToolKind,
+		"source":      "my-instance",
+		"description": "Tool to test the capture of slow query plans.",
+		"statement":   "SELECT 1 AS id FROM pg_sleep($1)",
+		"parameters": []map[string]any{
+			{
+				"name":        "seconds",
+				"type":        "float",
+				"description": "time to sleep",
+			},
+		},
+		"slowQueryExplain": map[string]any{
+			"threshold": "100ms",
+			"maxPlans":  2,
+		},
+	}
+	config["tools"] = tools
+	return config
+}
+
+func runPostgresSlowQueryExplainTest(t *testing.T) {
+	idToken, err := tests.GetGoogleIdToken(tests.ClientId)
+	if err != nil {
+		t.Fatalf("error getting Google ID token: %s", err)
+	}
+	adminHeader := map[string]string{"my-google-auth_token": idToken}
+	const slowQueriesURL = "http://127.0.0.1:5000/api/admin/slow-queries"
+
+	resp, body := tests.RunRequest(t, http.MethodGet, slowQueriesURL, nil, nil)
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("unexpected status code without auth: got %d, want %d: %s", resp.StatusCode, http.StatusUnauthorized, body)
+	}
+
+	// a fast invocation is not explained
+	resp, body = tests.RunRequest(t, http.MethodPost, "http://127.0.0.1:5000/api/tool/my-slow-tool/invoke", bytes.NewBufferString(`{"seconds": 0}`), nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, http.StatusOK, body)
+	}
+	resp, body = tests.RunRequest(t, http.MethodPost, "http://127.0.0.1:5000/api/tool/my-slow-tool/invoke", bytes.NewBufferString(`{"seconds": 0.3}`), nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, http.StatusOK, body)
+	}
+
+	// the plan is captured in the background
+	var got struct {
+		SlowQueries []tools.SlowQueryPlan `json:"slowQueries"`
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for len(got.SlowQueries) == 0 && time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
+		resp, body = tests.RunRequest(t, http.MethodGet, slowQueriesURL, nil, adminHeader)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, http.StatusOK, body)
+		}
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Fatalf("error parsing response body: %s", err)
+		}
+	}
+	if len(got.SlowQueries) != 1 {
+		t.Fatalf("got %d slow queries, want 1: %s", len(got.SlowQueries), body)
+	}
+	q := got.SlowQueries[0]
+	if q.Tool != "my-slow-tool" || q.Statement != "SELECT 1 AS id FROM pg_sleep($1)" || q.Error != "" {
+		t.Fatalf("unexpected slow query: %s", body)
+	}
+	plan, ok := q.Plan.([]any)
+	if !ok || len(plan) != 1 {
+		t.Fatalf("unexpected plan: %s", body)
+	}
+	if _, ok := plan[0].(map[string]any)["Plan"]; !ok {
+		t.Fatalf("plan has no Plan node: %s", body)
+	}
+}
+
+// addSqlDiffToolConfig adds sql-diff tools comparing configured statements,
+// and statements taken as parameters
+func addSqlDiffToolConfig(t *testing.T, config map[string]any) map[string]any {
+	tools, ok := config["tools"].(map[string]any)
+	if !ok {
+		t.Fatalf("unable to get tools from config")
+	}
+	tools["my-sql-diff-tool"] = map[string]any{
+		"kind":        "sql-diff",
+		"source":      "my-instance",
+		"description": "Tool to compare the results of two statements.",
+		"statementA":  "SELECT * FROM (VALUES (1, 'a'), (2, 'b'), (3, 'c')) AS t(id, name) WHERE id >= $1",
+		"statementB":  "SELECT * FROM (VALUES (1, 'a'), (2, 'x'), (4, 'd')) AS t(id, name) WHERE id >= $1",
+		"keyColumns":  []string{"id"},
+		"parameters": []map[string]any{
+			{
+				"name":        "minId",
+				"type":        "integer",
+				"description": "the smallest id compared",
+			},
+		},
+	}
+	tools["my-sql-diff-params-tool"] = map[string]any{
+		"kind":        "sql-diff",
+		"source":      "my-instance",
+		"description": "Tool to compare the results of two statements.",
+		"maxRows":     2,
+	}
+	config["tools"] = tools
+	return config
+}
+
+func runPostgresSqlDiffTest(t *testing.T) {
+	invokeTcs := []struct {
+		name           string
+		api            string
+		requestBody    io.Reader
+		wantStatusCode int
+		want           string
+	}{
+		{
+			name:           "invoke my-sql-diff-tool",
+			api:            "http://127.0.0.1:5000/api/tool/my-sql-diff-tool/invoke",
+			requestBody:    bytes.NewBuffer([]byte(`{"minId": 1}`)),
+			wantStatusCode: http.StatusOK,
+			want:           `{"summary":{"rowsA":3,"rowsB":3,"onlyInA":1,"onlyInB":1,"changed":1,"unchanged":1,"truncated":false},"onlyInA":[{"id":3,"name":"c"}],"onlyInB":[{"id":4,"name":"d"}],"changed":[{"key":{"id":2},"columns":["name"],"a":{"name":"b"},"b":{"name":"x"}}]}`,
+		},
+		{
+			name:           "invoke my-sql-diff-params-tool",
+			api:            "http://127.0.0.1:5000/api/tool/my-sql-diff-params-tool/invoke",
+			requestBody:    bytes.NewBuffer([]byte(`{"statementA": "SELECT 1 AS id, 'a' AS name", "statementB": "SELECT 1 AS id, 'b' AS name", "keyColumns": ["id"]}`)),
+			wantStatusCode: http.StatusOK,
+			want:           `{"summary":{"rowsA":1,"rowsB":1,"onlyInA":0,"onlyInB":0,"changed":1,"unchanged":0,"truncated":false},"onlyInA":[],"onlyInB":[],"changed":[{"key":{"id":1},"columns":["name"],"a":{"name":"a"},"b":{"name":"b"}}]}`,
+		},
+		{
+			name:           "invoke my-sql-diff-params-tool over maxRows",
+			api:            "http://127.0.0.1:5000/api/tool/my-sql-diff-params-tool/invoke",
+			requestBody:    bytes.NewBuffer([]byte(`{"statementA": "SELECT generate_series(1, 3) AS id", "statementB": "SELECT 1 AS id", "keyColumns": ["id"]}`)),
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tc := range invokeTcs {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, tc.api, tc.requestBody)
+			if err != nil {
+				t.Fatalf("unable to create request: %v", err)
+			}
+			req.Header.Add("Content-type", "application/json")
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("unable to send request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tc.wantStatusCode {
+				body, _ := io.ReadAll(resp.Body)
+				t.Fatalf("wrong status code: got %d, want %d, body: %s", resp.StatusCode, tc.wantStatusCode, string(body))
+			}
+			if tc.wantStatusCode != http.StatusOK {
+				return
+			}
+
+			var body map[string]any
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("error parsing response body: %v", err)
+			}
+			got, ok := body["result"].(string)
+			if !ok {
+				t.Fatalf("unable to find result in response body")
+			}
+			if got != tc.want {
+				t.Fatalf("unexpected value: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+// setUpPostgresInsertTable creates a table with a primary key, and a view of
+// it, into which rows cannot be copied.
+func setUpPostgresInsertTable(t *testing.T, ctx context.Context, pool *pgxpool.Pool, tableName string) func(*testing.T) {
+	stmts := []string{
+		fmt.Sprintf("CREATE TABLE %s (id INT PRIMARY KEY, airline TEXT, departure DATE);", tableName),
+		fmt.Sprintf("CREATE VIEW %s_view AS SELECT * FROM %s;", tableName, tableName),
+	}
+	for _, stmt := range stmts {
+		if _, err := pool.Exec(ctx, stmt); err != nil {
+			t.Fatalf("unable to set up insert table: %s", err)
+		}
+	}
+	return func(t *testing.T) {
+		if _, err := pool.Exec(ctx, fmt.Sprintf("DROP VIEW IF EXISTS %s_view; DROP TABLE IF EXISTS %s;", tableName, tableName)); err != nil {
+			t.Errorf("Teardown failed: %s", err)
+		}
+	}
+}
+
+// addInsertRowsToolConfig adds postgres-insert-rows tools copying into a
+// table, inserting into a view, and skipping conflicting rows
+func addInsertRowsToolConfig(t *testing.T, config map[string]any, tableName string) map[string]any {
+	tools, ok := config["tools"].(map[string]any)
+	if !ok {
+		t.Fatalf("unable to get tools from config")
+	}
+	tools["my-insert-rows-tool"] = map[string]any{
+		"kind":        "postgres-insert-rows",
+		"source":      "my-instance",
+		"description": "Tool to insert flights.",
+		"table":       "public." + tableName,
+		"columns":     []string{"id", "airline"},
+		"maxRows":     3,
+	}
+	tools["my-insert-rows-view-tool"] = map[string]any{
+		"kind":        "postgres-insert-rows",
+		"source":      "my-instance",
+		"description": "Tool to insert flights through a view.",
+		"table":       tableName + "_view",
+		"columns":     []string{"id", "airline", "departure"},
+	}
+	tools["my-insert-rows-ignore-tool"] = map[string]any{
+		"kind":        "postgres-insert-rows",
+		"source":      "my-instance",
+		"description": "Tool to insert new flights.",
+		"table":       tableName,
+		"columns":     []string{"id", "airline"},
+		"onConflict":  "ignore",
+	}
+	config["tools"] = tools
+	return config
+}
+
+func runPostgresInsertRowsTest(t *testing.T, ctx context.Context, pool *pgxpool.Pool, tableName string) {
+	invokeTcs := []struct {
+		name           string
+		api            string
+		requestBody    io.Reader
+		wantStatusCode int
+		want           string
+	}{
+		{
+			name:           "invoke my-insert-rows-tool with COPY",
+			api:            "http://127.0.0.1:5000/api/tool/my-insert-rows-tool/invoke",
+			requestBody:    bytes.NewBuffer([]byte(`{"rows": [{"id": 1, "airline": "CY"}, {"id": 2}]}`)),
+			wantStatusCode: http.StatusOK,
+			want:           `{"inserted":2,"method":"copy"}`,
+		},
+		{
+			name:           "invoke my-insert-rows-view-tool falling back to INSERT",
+			api:            "http://127.0.0.1:5000/api/tool/my-insert-rows-view-tool/invoke",
+			requestBody:    bytes.NewBuffer([]byte(`{"rows": [{"id": 3, "airline": "UA", "departure": "2025-10-15"}, {"id": 4, "airline": "DL"}]}`)),
+			wantStatusCode: http.StatusOK,
+			want:           `{"inserted":2,"method":"insert"}`,
+		},
+		{
+			name:           "invoke my-insert-rows-ignore-tool skipping conflicts",
+			api:            "http://127.0.0.1:5000/api/tool/my-insert-rows-ignore-tool/invoke",
+			requestBody:    bytes.NewBuffer([]byte(`{"rows": [{"id": 1, "airline": "AA"}, {"id": 5, "airline": "AA"}]}`)),
+			wantStatusCode: http.StatusOK,
+			want:           `{"inserted":1,"method":"insert"}`,
+		},
+		{
+			name:           "invoke my-insert-rows-tool with a conflict",
+			api:            "http://127.0.0.1:5000/api/tool/my-insert-rows-tool/invoke",
+			requestBody:    bytes.NewBuffer([]byte(`{"rows": [{"id": 6, "airline": "AA"}, {"id": 1, "airline": "AA"}]}`)),
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "invoke my-insert-rows-tool with an unknown column",
+			api:            "http://127.0.0.1:5000/api/tool/my-insert-rows-tool/invoke",
+			requestBody:    bytes.NewBuffer([]byte(`{"rows": [{"id": 7, "departure": "2025-10-15"}]}`)),
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "invoke my-insert-rows-tool over maxRows",
+			api:            "http://127.0.0.1:5000/api/tool/my-insert-rows-tool/invoke",
+			requestBody:    bytes.NewBuffer([]byte(`{"rows": [{"id": 8}, {"id": 9}, {"id": 10}, {"id": 11}]}`)),
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tc := range invokeTcs {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, tc.api, tc.requestBody)
+			if err != nil {
+				t.Fatalf("unable to create request: %v", err)
+			}
+			req.Header.Add("Content-type", "application/json")
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("unable to send request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tc.wantStatusCode {
+				body, _ := io.ReadAll(resp.Body)
+				t.Fatalf("wrong status code: got %d, want %d, body: %s", resp.StatusCode, tc.wantStatusCode, string(body))
+			}
+			if tc.wantStatusCode != http.StatusOK {
+				return
+			}
+
+			var body map[string]any
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("error parsing response body: %v", err)
+			}
+			got, ok := body["result"].(string)
+			if !ok {
+				t.Fatalf("unable to find result in response body")
+			}
+			if got != tc.want {
+				t.Fatalf("unexpected value: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+
+	// the failed invocations inserted no row
+	rows, err := pool.Query(ctx, fmt.Sprintf("SELECT id FROM %s ORDER BY id;", tableName))
+	if err != nil {
+		t.Fatalf("unable to query inserted rows: %s", err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		t.Fatalf("unable to collect inserted rows: %s", err)
+	}
+	if want := []int{1, 2, 3, 4, 5}; !slices.Equal(ids, want) {
+		t.Fatalf("unexpected inserted rows: got %v, want %v", ids, want)
+	}
+}
+
+// setUpPostgresMaterializedViews creates a table, a materialized view over it
+// with a unique index, and another one without
+func setUpPostgresMaterializedViews(t *testing.T, ctx context.Context, pool *pgxpool.Pool, name string) func(*testing.T) {
+	stmts := []string{
+		fmt.Sprintf("CREATE TABLE %s_sales (id INT PRIMARY KEY, day DATE, amount INT);", name),
+		fmt.Sprintf("INSERT INTO %s_sales VALUES (1, '2025-10-14', 10), (2, '2025-10-14', 20), (3, '2025-10-15', 30);", name),
+		fmt.Sprintf("CREATE MATERIALIZED VIEW %s AS SELECT day, sum(amount) AS total FROM %s_sales GROUP BY day;", name, name),
+		fmt.Sprintf("CREATE UNIQUE INDEX %s_day ON %s (day);", name, name),
+		fmt.Sprintf("CREATE MATERIALIZED VIEW %s_unindexed AS SELECT * FROM %s_sales;", name, name),
+	}
+	for _, stmt := range stmts {
+		if _, err := pool.Exec(ctx, stmt); err != nil {
+			t.Fatalf("unable to set up materialized views: %s", err)
+		}
+	}
+	return func(t *testing.T) {
+		if _, err := pool.Exec(ctx, fmt.Sprintf("DROP MATERIALIZED VIEW IF EXISTS %s, %s_unindexed; DROP TABLE IF EXISTS %s_sales;", name, name, name)); err != nil {
+			t.Errorf("Teardown failed: %s", err)
+		}
+	}
+}
+
+// addMaterializedViewToolConfig adds a postgres-refresh-materialized-view tool
+// allowed to refresh the materialized views, and a
+// postgres-list-materialized-views tool
+func addMaterializedViewToolConfig(t *testing.T, config map[string]any, name string) map[string]any {
+	tools, ok := config["tools"].(map[string]any)
+	if !ok {
+		t.Fatalf("unable to get tools from config")
+	}
+	tools["my-refresh-matview-tool"] = map[string]any{
+		"kind":              "postgres-refresh-materialized-view",
+		"source":            "my-instance",
+		"description":       "Tool to refresh sales reports.",
+		"materializedViews": []string{"public." + name, name + "_unindexed"},
+	}
+	tools["list_materialized_views"] = map[string]any{
+		"kind":   "postgres-list-materialized-views",
+		"source": "my-instance",
+	}
+	config["tools"] = tools
+	return config
+}
+
+func runPostgresMaterializedViewTest(t *testing.T, ctx context.Context, pool *pgxpool.Pool, name string) {
+	const refreshURL = "http://127.0.0.1:5000/api/tool/my-refresh-matview-tool/invoke"
+	// the refreshes see the rows inserted since the views were created
+	if _, err := pool.Exec(ctx, fmt.Sprintf("INSERT INTO %s_sales VALUES (4, '2025-10-16', 40);", name)); err != nil {
+		t.Fatalf("unable to insert sales: %s", err)
+	}
+
+	tcs := []struct {
+		name       string
+		args       map[string]any
+		wantStatus int
+		wantView   string
+		wantRows   float64
+		wantBody   string
+	}{
+		{
+			name:       "refresh",
+			args:       map[string]any{"view": "public." + name},
+			wantStatus: http.StatusOK,
+			wantView:   "public." + name,
+			wantRows:   3,
+		},
+		{
+			name:       "refresh concurrently",
+			args:       map[string]any{"view": "public." + name, "concurrently": true},
+			wantStatus: http.StatusOK,
+			wantView:   "public." + name,
+			wantRows:   3,
+		},
+		{
+			name:       "refresh unqualified view",
+			args:       map[string]any{"view": name + "_unindexed"},
+			wantStatus: http.StatusOK,
+			wantView:   "public." + name + "_unindexed",
+			wantRows:   4,
+		},
+		{
+			name:       "refresh concurrently without a unique index",
+			args:       map[string]any{"view": name + "_unindexed", "concurrently": true},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "has no unique index",
+		},
+		{
+			name:       "refresh a view not allowed",
+			args:       map[string]any{"view": name},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "is not allowed",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			reqBody, err := json.Marshal(tc.args)
+			if err != nil {
+				t.Fatalf("unable to marshal request: %s", err)
+			}
+			resp, body := tests.RunRequest(t, http.MethodPost, refreshURL, bytes.NewBuffer(reqBody), nil)
+			if resp.StatusCode != tc.wantStatus {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, tc.wantStatus, body)
+			}
+			if tc.wantStatus != http.StatusOK {
+				if !strings.Contains(string(body), tc.wantBody) {
+					t.Fatalf("unexpected response: got %s, want it to contain %s", body, tc.wantBody)
+				}
+				return
+			}
+			var wrapper struct {
+				Result string `json:"result"`
+			}
+			if err := json.Unmarshal(body, &wrapper); err != nil {
+				t.Fatalf("error parsing response body: %s", err)
+			}
+			var got map[string]any
+			if err := json.Unmarshal([]byte(wrapper.Result), &got); err != nil {
+				t.Fatalf("error parsing result: %s", err)
+			}
+			if got["view"] != tc.wantView || got["rows"] != tc.wantRows || got["duration"] == "" {
+				t.Fatalf("unexpected result: %v", got)
+			}
+		})
+	}
+
+	// the list reports when the views were refreshed
+	resp, body := tests.RunRequest(t, http.MethodPost, "http://127.0.0.1:5000/api/tool/list_materialized_views/invoke", bytes.NewBuffer([]byte(`{"schema": "public"}`)), nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: got %d: %s", resp.StatusCode, body)
+	}
+	var wrapper struct {
+		Result string `json:"result"`
+	}
+	if err := json.Unmarshal(body, &wrapper); err != nil {
+		t.Fatalf("error parsing response body: %s", err)
+	}
+	var views []map[string]any
+	if err := json.Unmarshal([]byte(wrapper.Result), &views); err != nil {
+		t.Fatalf("error parsing result: %s", err)
+	}
+	found := 0
+	for _, v := range views {
+		if v["matviewname"] != name && v["matviewname"] != name+"_unindexed" {
+			continue
+		}
+		found++
+		if v["ispopulated"] != true || v["last_refreshed"] == nil || !strings.Contains(v["definition"].(string), name+"_sales") {
+			t.Fatalf("unexpected materialized view: %v", v)
+		}
+	}
+	if found != 2 {
+		t.Fatalf("expected the list to include both materialized views, got %v", views)
+	}
+}
+
+// addLeakToolConfig adds tools whose invocations fail mid-iteration
+func addLeakToolConfig(t *testing.T, config map[string]any) map[string]any {
+	tools, ok := config["tools"].(map[string]any)
+	if !ok {
+		t.Fatalf("unable to get tools from config")
+	}
+	tools["my-division-fail-tool"] = map[string]any{
+		"kind":        PostgresToolKind,
+		"source":      "my-instance",
+		"description": "Tool failing on its third row.",
+		"statement":   "SELECT 10 / (3 - g) AS q FROM generate_series(1, 5) g;",
+	}
+	tools["my-coerce-fail-tool"] = map[string]any{
+		"kind":        PostgresToolKind,
+		"source":      "my-instance",
+		"description": "Tool whose second row cannot be coerced.",
+		"statement":   "SELECT CASE WHEN g = 2 THEN 'two' ELSE g::text END AS v FROM generate_series(1, 3) g;",
+		"columnTypes": map[string]any{"v": "integer"},
+	}
+	config["tools"] = tools
+	return config
+}
+
+// runPostgresLeakTest invokes tools failing mid-iteration more times than the
+// pool of the server has connections, and checks that the connections and
+// goroutines of the invocations are released.
+func runPostgresLeakTest(t *testing.T, ctx context.Context, pool *pgxpool.Pool) {
+	// sessions of the server running a query or holding a transaction open;
+	// the connections of pool are idle between the queries
+	inUse := func() int {
+		var n int
+		err := pool.QueryRow(ctx, `SELECT count(*) FROM pg_stat_activity
+			WHERE datname = current_database() AND pid <> pg_backend_pid()
+			AND state IN ('active', 'idle in transaction', 'idle in transaction (aborted)');`).Scan(&n)
+		if err != nil {
+			t.Errorf("unable to count sessions: %s", err)
+		}
+		return n
+	}
+	// connections are not kept alive, so that the goroutines serving them end
+	client := &http.Client{Timeout: 10 * time.Second, Transport: &http.Transport{DisableKeepAlives: true}}
+	invoke := func(api, body string) {
+		resp, err := client.Post(api, "application/json", bytes.NewBufferString(body))
+		if err != nil {
+			t.Errorf("unable to send request: %v", err)
+			return
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			b, _ := io.ReadAll(resp.Body)
+			t.Errorf("wrong status code: got %d, want %d, body: %s", resp.StatusCode, http.StatusBadRequest, string(b))
+		}
+	}
+
+	testutils.CheckLeaks(t, inUse, func() {
+		for range 50 {
+			invoke("http://127.0.0.1:5000/api/tool/my-division-fail-tool/invoke", `{}`)
+			invoke("http://127.0.0.1:5000/api/tool/my-coerce-fail-tool/invoke", `{}`)
+			invoke("http://127.0.0.1:5000/api/tool/my-exec-sql-tool/invoke", `{"sql": "SELECT 10 / (3 - g) FROM generate_series(1, 5) g"}`)
+		}
+	})
+}
+
+// addKeysetToolConfig adds a tool paging through the param table by name,
+// descending, then id
+func addKeysetToolConfig(t *testing.T, config map[string]any, tableName string) map[string]any {
+	tools, ok := config["tools"].(map[string]any)
+	if !ok {
+		t.Fatalf("unable to get tools from config")
+	}
+	tools["my-keyset-tool"] = map[string]any{
+		"kind":        PostgresToolKind,
+		"source":      "my-instance",
+		"description": "Tool to test keyset pagination.",
+		"statement":   fmt.Sprintf("SELECT id, name FROM %s WHERE id >= $1;", tableName),
+		"parameters": []map[string]any{
+			{
+				"name":        "min_id",
+				"type":        "integer",
+				"description": "the smallest id",
+			},
+		},
+		"keysetPagination": map[string]any{
+			"columns": []any{
+				map[string]any{"name": "name", "descending": true},
+				"id",
+			},
+			"pageSize": 1,
+		},
+	}
+	config["tools"] = tools
+	return config
+}
+
+func runPostgresKeysetPaginationTest(t *testing.T) {
+	const invokeURL = "http://127.0.0.1:5000/api/tool/my-keyset-tool/invoke"
+	type page struct {
+		Rows     []map[string]any `json:"rows"`
+		RowCount int              `json:"rowCount"`
+		NextKey  []any            `json:"nextKey"`
+	}
+	getPage := func(afterKey []any) page {
+		t.Helper()
+		args := map[string]any{"min_id": 1}
+		if afterKey != nil {
+			args["_afterKey"] = afterKey
+		}
+		reqBody, err := json.Marshal(args)
+		if err != nil {
+			t.Fatalf("unable to marshal request: %s", err)
+		}
+		resp, body := tests.RunRequest(t, http.MethodPost, invokeURL, bytes.NewBuffer(reqBody), nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, http.StatusOK, body)
+		}
+		var envelope struct {
+			Result string `json:"result"`
+		}
+		if err := json.Unmarshal(body, &envelope); err != nil {
+			t.Fatalf("error parsing response body: %s", err)
+		}
+		var got page
+		if err := json.Unmarshal([]byte(envelope.Result), &got); err != nil {
+			t.Fatalf("error parsing result %q: %s", envelope.Result, err)
+		}
+		return got
+	}
+
+	// names descending with NULLs last, each page starting after the last
+	// row of the previous one
+	var names []any
+	var keys [][]any
+	p := getPage(nil)
+	for {
+		if p.RowCount != len(p.Rows) || len(p.Rows) > 1 {
+			t.Fatalf("unexpected page: %+v", p)
+		}
+		for _, row := range p.Rows {
+			names = append(names, row["name"])
+		}
+		if p.NextKey == nil {
+			break
+		}
+		if len(keys) > 4 {
+			t.Fatalf("too many pages, keys so far: %v", keys)
+		}
+		keys = append(keys, p.NextKey)
+		p = getPage(p.NextKey)
+	}
+	wantNames := []any{"Sid", "Jane", "Alice", nil}
+	if diff := cmp.Diff(wantNames, names); diff != "" {
+		t.Fatalf("incorrect rows: diff %v", diff)
+	}
+	wantKeys := [][]any{{"Sid", float64(3)}, {"Jane", float64(2)}, {"Alice", float64(1)}}
+	if diff := cmp.Diff(wantKeys, keys); diff != "" {
+		t.Fatalf("incorrect keys: diff %v", diff)
+	}
+
+	// paging again from a key returns the same rows
+	if p := getPage(keys[1]); len(p.Rows) != 1 || p.Rows[0]["name"] != "Alice" {
+		t.Fatalf("unexpected page after %v: %+v", keys[1], p)
+	}
+}
+
+// addSchemaScopeToolConfig adds an execute-sql tool restricted to the public
+// schema
+func addSchemaScopeToolConfig(t *testing.T, config map[string]any) map[string]any {
+	tools, ok := config["tools"].(map[string]any)
+	if !ok {
+		t.Fatalf("unable to get tools from config")
+	}
+	tools["my-scoped-exec-sql-tool"] = map[string]any{
+		"kind":        "postgres-execute-sql",
+		"source":      "my-instance",
+		"description": "Tool to test schema scopes.",
+		"schemaScope": []string{"public"},
+	}
+	config["tools"] = tools
+	return config
+}
+
+func runPostgresSchemaScopeTest(t *testing.T, tableName string) {
+	const invokeURL = "http://127.0.0.1:5000/api/tool/my-scoped-exec-sql-tool/invoke"
+	tcs := []struct {
+		name       string
+		sql        string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "unqualified table in scope",
+			sql:        fmt.Sprintf("SELECT name FROM %s WHERE id = 1", tableName),
+			wantStatus: http.StatusOK,
+			wantBody:   `"Alice"`,
+		},
+		{
+			name:       "search_path is the scope",
+			sql:        "SELECT current_schemas(false) AS schemas",
+			wantStatus: http.StatusOK,
+			wantBody:   `public`,
+		},
+		{
+			name:       "qualified table out of scope",
+			sql:        "SELECT * FROM information_schema.tables",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `statement references schema \"information_schema\"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			reqBody, err := json.Marshal(map[string]any{"sql": tc.sql})
+			if err != nil {
+				t.Fatalf("unable to marshal request: %s", err)
+			}
+			resp, body := tests.RunRequest(t, http.MethodPost, invokeURL, bytes.NewBuffer(reqBody), nil)
+			if resp.StatusCode != tc.wantStatus {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, tc.wantStatus, body)
+			}
+			if !strings.Contains(string(body), tc.wantBody) {
+				t.Fatalf("unexpected response: got %s, want it to contain %s", body, tc.wantBody)
+			}
+		})
+	}
+}
+
+// addStickySessionToolConfig adds tools running the statements of a session
+// on a connection pinned to it.
+func addStickySessionToolConfig(t *testing.T, config map[string]any) map[string]any {
+	tools, ok := config["tools"].(map[string]any)
+	if !ok {
+		t.Fatalf("unable to get tools from config")
+	}
+	tools["my-sticky-exec-sql-tool"] = map[string]any{
+		"kind":          "postgres-execute-sql",
+		"source":        "my-instance",
+		"description":   "Tool to test sticky sessions.",
+		"stickySession": true,
+	}
+	tools["my-sticky-read-tool"] = map[string]any{
+		"kind":        "postgres-sql",
+		"source":      "my-instance",
+		"description": "Tool reading the temporary table of a sticky session.",
+		"statement":   "SELECT count(*) AS n FROM sticky_scratch",
+	}
+	config["tools"] = tools
+	return config
+}
+
+func runPostgresStickySessionTest(t *testing.T) {
+	const execURL = "http://127.0.0.1:5000/api/tool/my-sticky-exec-sql-tool/invoke"
+	const readURL = "http://127.0.0.1:5000/api/tool/my-sticky-read-tool/invoke"
+	tcs := []struct {
+		name       string
+		url        string
+		session    string
+		sql        string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "create temporary table",
+			url:        execURL,
+			session:    "sticky-a",
+			sql:        "CREATE TEMP TABLE sticky_scratch AS SELECT 1 AS id",
+			wantStatus: http.StatusOK,
+			wantBody:   `null`,
+		},
+		{
+			name:       "temporary table visible to the session",
+			url:        execURL,
+			session:    "sticky-a",
+			sql:        "SELECT count(*) AS n FROM sticky_scratch",
+			wantStatus: http.StatusOK,
+			wantBody:   `\"n\":1`,
+		},
+		{
+			name:       "temporary table visible to other tools of the session",
+			url:        readURL,
+			session:    "sticky-a",
+			wantStatus: http.StatusOK,
+			wantBody:   `\"n\":1`,
+		},
+		{
+			name:       "temporary table invisible to other sessions",
+			url:        execURL,
+			session:    "sticky-b",
+			sql:        "SELECT count(*) AS n FROM sticky_scratch",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `does not exist`,
+		},
+		{
+			name:       "temporary table invisible without session",
+			url:        execURL,
+			sql:        "SELECT count(*) AS n FROM sticky_scratch",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `does not exist`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			params := map[string]any{}
+			if tc.sql != "" {
+				params["sql"] = tc.sql
+			}
+			reqBody, err := json.Marshal(params)
+			if err != nil {
+				t.Fatalf("unable to marshal request: %s", err)
+			}
+			var header map[string]string
+			if tc.session != "" {
+				header = map[string]string{"Toolbox-Session-Id": tc.session}
+			}
+			resp, body := tests.RunRequest(t, http.MethodPost, tc.url, bytes.NewBuffer(reqBody), header)
+			if resp.StatusCode != tc.wantStatus {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, tc.wantStatus, body)
+			}
+			if !strings.Contains(string(body), tc.wantBody) {
+				t.Fatalf("unexpected response: got %s, want it to contain %s", body, tc.wantBody)
+			}
+		})
+	}
+}
+
+// runPostgresPoolExhaustionTest saturates a pool of a single connection with
+// a slow query, and checks that the queries waiting for it fail with a
+// PoolExhaustedError.
+func runPostgresPoolExhaustionTest(t *testing.T, ctx context.Context) {
+	cfg := sourcepg.Config{
+		Name:         "my-pool",
+		Kind:         PostgresSourceKind,
+		Host:         PostgresHost,
+		Port:         PostgresPort,
+		User:         PostgresUser,
+		Password:     PostgresPass,
+		Database:     PostgresDatabase,
+		PgPoolConfig: sources.PgPoolConfig{MaxConns: 1},
+	}
+	src, err := cfg.Initialize(ctx, noop.NewTracerProvider().Tracer(""))
+	if err != nil {
+		t.Fatalf("unable to initialize source: %s", err)
+	}
+	newTool := func(name, statement string) tools.Tool {
+		tool, err := postgressql.Config{
+			Name:         name,
+			Kind:         PostgresToolKind,
+			Source:       "my-pool",
+			Description:  "some description",
+			Statement:    statement,
+			AuthRequired: []string{},
+		}.Initialize(map[string]sources.Source{"my-pool": src})
+		if err != nil {
+			t.Fatalf("unable to initialize tool %q: %s", name, err)
+		}
+		return tool
+	}
+	tests.RunPoolExhaustionTest(t, src.(sources.PoolSource), newTool("my-slow-tool", "SELECT pg_sleep(2);"), newTool("my-tool", "SELECT 1;"))
+}
@@ -18,7 +18,11 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net"
+	"net/http"
 	"os"
+	"regexp"
+	"strings"
 
 	yaml "github.com/goccy/go-yaml"
 
@@ -130,3 +134,40 @@ func (c *CmdExec) Close() {
 		c.Close()
 	}
 }
+
+// DefaultServerURL is the base URL of a toolbox command started without
+// listen flags.
+const DefaultServerURL = "http://127.0.0.1:5000"
+
+var serverAddrRe = regexp.MustCompile(`Server listening on (\S+)`)
+
+// ServerAddrs returns the addresses a toolbox command reported listening on in
+// out, the output read from CmdExec.Out until the server was ready.
+func ServerAddrs(out string) []string {
+	var addrs []string
+	for _, m := range serverAddrRe.FindAllStringSubmatch(out, -1) {
+		addrs = append(addrs, strings.TrimRight(m[1], `"`))
+	}
+	return addrs
+}
+
+// ServerClient returns the base URL and an HTTP client for a listen address
+// reported by ServerAddrs. Requests to a `unix://` address are sent over the
+// socket.
+func ServerClient(addr string) (string, *http.Client) {
+	if path, ok := strings.CutPrefix(addr, "unix://"); ok {
+		client := &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var d net.Dialer
+					return d.DialContext(ctx, "unix", path)
+				},
+			},
+		}
+		return "http://unix", client
+	}
+	if hostPort, ok := strings.CutPrefix(addr, "tcp://"); ok {
+		return "http://" + hostPort, http.DefaultClient
+	}
+	return DefaultServerURL, http.DefaultClient
+}
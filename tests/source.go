@@ -19,14 +19,16 @@ package tests
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"regexp"
 	"strings"
 	"testing"
 	"time"
 
 	"cloud.google.com/go/cloudsqlconn"
+	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
 )
 
 // RunSourceConnection test for source connection
@@ -58,7 +60,7 @@ func RunSourceConnectionTest(t *testing.T, sourceConfig map[string]any, toolKind
 
 	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
-	out, err := testutils.WaitForString(waitCtx, regexp.MustCompile(`Server ready to serve`), cmd.Out)
+	_, out, err := testutils.WaitForServerReady(waitCtx, cmd.Out)
 	if err != nil {
 		t.Logf("toolbox command logs: \n%s", out)
 		return fmt.Errorf("toolbox didn't start successfully: %s", err)
@@ -77,3 +79,43 @@ func GetCloudSQLDialOpts(ipType string) ([]cloudsqlconn.DialOption, error) {
 		return nil, fmt.Errorf("invalid ipType %s", ipType)
 	}
 }
+
+// RunPoolExhaustionTest saturates src, whose pool must have a single
+// connection, with an invocation of slowTool, and checks that an invocation
+// of tool waiting for the connection fails with a PoolExhaustedError.
+func RunPoolExhaustionTest(t *testing.T, src sources.PoolSource, slowTool, tool tools.Tool) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	wrap := func(t tools.Tool) tools.Tool {
+		return tools.PoolExhaustionTool{Tool: t, SourceName: "my-pool", Source: src}
+	}
+
+	slowErr := make(chan error, 1)
+	go func() {
+		_, err := wrap(slowTool).Invoke(ctx, tools.Invocation{})
+		slowErr <- err
+	}()
+	// let the slow query get the connection
+	time.Sleep(500 * time.Millisecond)
+
+	invokeCtx, invokeCancel := context.WithTimeout(ctx, 200*time.Millisecond)
+	defer invokeCancel()
+	_, err := wrap(tool).Invoke(invokeCtx, tools.Invocation{})
+	var poolErr *tools.PoolExhaustedError
+	if !errors.As(err, &poolErr) {
+		t.Fatalf("unexpected error: got %v, want a PoolExhaustedError", err)
+	}
+	if want := (sources.PoolStats{InUse: 1, Idle: 0, Max: 1}); poolErr.PoolStats != want {
+		t.Fatalf("unexpected pool stats: got %+v, want %+v", poolErr.PoolStats, want)
+	}
+	if poolErr.RetryAfter < time.Second {
+		t.Fatalf("unexpected retry after: got %s, want at least 1s", poolErr.RetryAfter)
+	}
+
+	if err := <-slowErr; err != nil {
+		t.Fatalf("unexpected error of the slow query: %s", err)
+	}
+	if _, err := wrap(tool).Invoke(ctx, tools.Invocation{}); err != nil {
+		t.Fatalf("unexpected error once the connection is released: %s", err)
+	}
+}
@@ -15,18 +15,25 @@
 package tidb
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
-	"regexp"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	sourcetidb "github.com/googleapis/genai-toolbox/internal/sources/tidb"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/tidb/tidbsql"
 	"github.com/googleapis/genai-toolbox/tests"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 var (
@@ -107,6 +114,84 @@ func addTiDBExecuteSqlConfig(t *testing.T, config map[string]any) map[string]any
 	return config
 }
 
+// addTiDBExplainConfig gets the tools config for `tidb-explain`
+func addTiDBExplainConfig(t *testing.T, config map[string]any) map[string]any {
+	tools, ok := config["tools"].(map[string]any)
+	if !ok {
+		t.Fatalf("unable to get tools from config")
+	}
+	tools["my-explain-tool"] = map[string]any{
+		"kind":        "tidb-explain",
+		"source":      "my-instance",
+		"description": "Tool to explain sql",
+	}
+	config["tools"] = tools
+	return config
+}
+
+// runTiDBExplainTest explains a query of tableName, summarized and raw, with
+// EXPLAIN and EXPLAIN ANALYZE.
+func runTiDBExplainTest(t *testing.T, tableName string) {
+	api := "http://127.0.0.1:5000/api/tool/my-explain-tool/invoke"
+	statement := fmt.Sprintf("SELECT * FROM %s WHERE id = ? OR name = ?", tableName)
+	invoke := func(t *testing.T, analyze, raw bool) string {
+		t.Helper()
+		reqBody, err := json.Marshal(map[string]any{"sql": statement, "params": []string{"1", "Alice"}, "analyze": analyze, "raw": raw})
+		if err != nil {
+			t.Fatalf("unable to marshal request: %s", err)
+		}
+		resp, body := tests.RunRequest(t, http.MethodPost, api, bytes.NewBuffer(reqBody), nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, http.StatusOK, body)
+		}
+		var wrapper struct {
+			Result string `json:"result"`
+		}
+		if err := json.Unmarshal(body, &wrapper); err != nil {
+			t.Fatalf("error parsing response body: %s", err)
+		}
+		return wrapper.Result
+	}
+
+	for _, analyze := range []bool{false, true} {
+		t.Run(fmt.Sprintf("analyze %t", analyze), func(t *testing.T) {
+			var got struct {
+				Analyzed  bool `json:"analyzed"`
+				Operators []struct {
+					ID      string   `json:"id"`
+					EstRows *float64 `json:"estRows"`
+					Time    string   `json:"time"`
+				} `json:"operators"`
+				MostExpensive []string `json:"mostExpensive"`
+			}
+			result := invoke(t, analyze, false)
+			if err := json.Unmarshal([]byte(result), &got); err != nil {
+				t.Fatalf("error parsing result: %s", err)
+			}
+			if got.Analyzed != analyze || len(got.Operators) != 1 || got.Operators[0].ID == "" || got.Operators[0].EstRows == nil {
+				t.Fatalf("unexpected plan: %s", result)
+			}
+			if analyze && got.Operators[0].Time == "" {
+				t.Fatalf("expected the time of the root operator: %s", result)
+			}
+			if len(got.MostExpensive) == 0 || len(got.MostExpensive) > 3 {
+				t.Fatalf("unexpected most expensive operators: %s", result)
+			}
+		})
+	}
+
+	t.Run("raw", func(t *testing.T) {
+		var got []map[string]any
+		result := invoke(t, false, true)
+		if err := json.Unmarshal([]byte(result), &got); err != nil {
+			t.Fatalf("error parsing result: %s", err)
+		}
+		if len(got) == 0 || got[0]["id"] == nil || got[0]["estRows"] == nil {
+			t.Fatalf("unexpected rows: %s", result)
+		}
+	})
+}
+
 func TestTiDBToolEndpoints(t *testing.T) {
 	sourceConfig := getTiDBVars(t)
 	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
@@ -137,6 +222,7 @@ func TestTiDBToolEndpoints(t *testing.T) {
 	// Write config into a file and pass it to command
 	toolsFile := tests.GetToolsConfig(sourceConfig, TiDBToolKind, paramToolStmt, idParamToolStmt, nameParamToolStmt, arrayToolStmt, authToolStmt)
 	toolsFile = addTiDBExecuteSqlConfig(t, toolsFile)
+	toolsFile = addTiDBExplainConfig(t, toolsFile)
 	tmplSelectCombined, tmplSelectFilterCombined := tests.GetMySQLTmplToolStatement()
 	toolsFile = tests.AddTemplateParamConfig(t, toolsFile, TiDBToolKind, tmplSelectCombined, tmplSelectFilterCombined, "")
 
@@ -148,7 +234,7 @@ func TestTiDBToolEndpoints(t *testing.T) {
 
 	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
-	out, err := testutils.WaitForString(waitCtx, regexp.MustCompile(`Server ready to serve`), cmd.Out)
+	_, out, err := testutils.WaitForServerReady(waitCtx, cmd.Out)
 	if err != nil {
 		t.Logf("toolbox command logs: \n%s", out)
 		t.Fatalf("toolbox didn't start successfully: %s", err)
@@ -163,4 +249,42 @@ func TestTiDBToolEndpoints(t *testing.T) {
 	tests.RunMCPToolCallMethod(t, mcpMyFailToolWant, mcpSelect1Want)
 	tests.RunExecuteSqlToolInvokeTest(t, createTableStatement, select1Want)
 	tests.RunToolInvokeWithTemplateParameters(t, tableNameTemplateParam)
+	runTiDBExplainTest(t, tableNameParam)
+	runTiDBPoolExhaustionTest(t, ctx)
+}
+
+// runTiDBPoolExhaustionTest saturates a pool of a single connection with a
+// slow query, and checks that the queries waiting for it fail with a
+// PoolExhaustedError.
+func runTiDBPoolExhaustionTest(t *testing.T, ctx context.Context) {
+	cfg := sourcetidb.Config{
+		Name:         "my-pool",
+		Kind:         TiDBSourceKind,
+		Host:         TiDBHost,
+		Port:         TiDBPort,
+		User:         TiDBUser,
+		Password:     TiDBPass,
+		Database:     TiDBDatabase,
+		UseSSL:       sourcetidb.IsTiDBCloudHost(TiDBHost),
+		MaxOpenConns: 1,
+	}
+	src, err := cfg.Initialize(ctx, noop.NewTracerProvider().Tracer(""))
+	if err != nil {
+		t.Fatalf("unable to initialize source: %s", err)
+	}
+	newTool := func(name, statement string) tools.Tool {
+		tool, err := tidbsql.Config{
+			Name:         name,
+			Kind:         TiDBToolKind,
+			Source:       "my-pool",
+			Description:  "some description",
+			Statement:    statement,
+			AuthRequired: []string{},
+		}.Initialize(map[string]sources.Source{"my-pool": src})
+		if err != nil {
+			t.Fatalf("unable to initialize tool %q: %s", name, err)
+		}
+		return tool
+	}
+	tests.RunPoolExhaustionTest(t, src.(sources.PoolSource), newTool("my-slow-tool", "SELECT SLEEP(2);"), newTool("my-tool", "SELECT 1;"))
 }
@@ -23,6 +23,7 @@ import (
 	"io"
 	"net/http"
 	"reflect"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -251,243 +252,351 @@ func RunToolInvokeParametersTest(t *testing.T, name string, params []byte, simpl
 	}
 }
 
-// RunToolInvoke runs the tool invoke endpoint
-func RunToolInvokeTest(t *testing.T, select1Want string, options ...InvokeTestOption) {
-	// Resolve options
-	// Default values for InvokeTestConfig
-	configs := &InvokeTestConfig{
-		myToolId3NameAliceWant:   "[{\"id\":1,\"name\":\"Alice\"},{\"id\":3,\"name\":\"Sid\"}]",
-		myToolById4Want:          "[{\"id\":4,\"name\":null}]",
-		myArrayToolWant:          "[{\"id\":1,\"name\":\"Alice\"},{\"id\":3,\"name\":\"Sid\"}]",
-		nullWant:                 "null",
-		supportOptionalNullParam: true,
-		supportArrayParam:        true,
-		supportClientAuth:        false,
-		supportSelect1Want:       true,
-		supportSelect1Auth:       true,
-	}
-
-	// Apply provided options
-	for _, option := range options {
-		option(configs)
-	}
+// Names of the invoke subtests of RunToolInvokeTest that options refer to.
+const (
+	invokeMySimpleToolTest = "invoke my-simple-tool"
+	invokeMyToolTest       = "invoke my-tool"
+	invokeMyToolById4Test  = "invoke my-tool-by-id with nil response"
+	invokeMyToolByNameTest = "invoke my-tool-by-name with nil response"
+	invokeMyArrayToolTest  = "invoke my-array-tool"
+)
 
-	// Get ID token
-	idToken, err := GetGoogleIdToken(ClientId)
-	if err != nil {
-		t.Fatalf("error getting Google ID token: %s", err)
-	}
+// select1AuthTests are the auth subtests of RunToolInvokeTest that expect the
+// result of `SELECT 1`.
+var select1AuthTests = []string{
+	"Invoke my-auth-tool with auth token",
+	"Invoke my-auth-tool with invalid auth token",
+	"Invoke my-auth-required-tool with auth token",
+}
 
-	// Get access token
-	accessToken, err := sources.GetIAMAccessToken(t.Context())
-	if err != nil {
-		t.Fatalf("error getting access token from ADC: %s", err)
-	}
-	accessToken = "Bearer " + accessToken
+// clientAuthTests are the subtests of RunToolInvokeTest for tools with
+// `useClientOAuth`, which are skipped by default.
+var clientAuthTests = []string{
+	"Invoke my-client-auth-tool with auth token",
+	"Invoke my-client-auth-tool without auth token",
+	"Invoke my-client-auth-tool with invalid auth token",
+}
 
-	// Test tool invoke endpoint
-	invokeTcs := []struct {
-		name           string
-		api            string
-		enabled        bool
-		requestHeader  map[string]string
-		requestBody    io.Reader
-		wantStatusCode int
-		wantBody       string
-	}{
+// NewInvokeExpectations returns the subtests of RunToolInvokeTest, expecting
+// the results of the MySQL suite. select1Want is the expected result of the
+// `SELECT 1` tools.
+func NewInvokeExpectations(select1Want string) *InvokeExpectations {
+	return &InvokeExpectations{Cases: []InvokeTestCase{
 		{
-			name:           "invoke my-simple-tool",
-			api:            "http://127.0.0.1:5000/api/tool/my-simple-tool/invoke",
-			enabled:        configs.supportSelect1Want,
-			requestHeader:  map[string]string{},
-			requestBody:    bytes.NewBuffer([]byte(`{}`)),
-			wantBody:       select1Want,
-			wantStatusCode: http.StatusOK,
+			Name:     invokeMySimpleToolTest,
+			Tool:     "my-simple-tool",
+			WantBody: select1Want,
 		},
 		{
-			name:           "invoke my-tool",
-			api:            "http://127.0.0.1:5000/api/tool/my-tool/invoke",
-			enabled:        true,
-			requestHeader:  map[string]string{},
-			requestBody:    bytes.NewBuffer([]byte(`{"id": 3, "name": "Alice"}`)),
-			wantBody:       configs.myToolId3NameAliceWant,
-			wantStatusCode: http.StatusOK,
+			Name:        invokeMyToolTest,
+			Tool:        "my-tool",
+			RequestBody: `{"id": 3, "name": "Alice"}`,
+			WantBody:    "[{\"id\":1,\"name\":\"Alice\"},{\"id\":3,\"name\":\"Sid\"}]",
 		},
 		{
-			name:           "invoke my-tool-by-id with nil response",
-			api:            "http://127.0.0.1:5000/api/tool/my-tool-by-id/invoke",
-			enabled:        true,
-			requestHeader:  map[string]string{},
-			requestBody:    bytes.NewBuffer([]byte(`{"id": 4}`)),
-			wantBody:       configs.myToolById4Want,
-			wantStatusCode: http.StatusOK,
+			Name:        invokeMyToolById4Test,
+			Tool:        "my-tool-by-id",
+			RequestBody: `{"id": 4}`,
+			WantBody:    "[{\"id\":4,\"name\":null}]",
 		},
 		{
-			name:           "invoke my-tool-by-name with nil response",
-			api:            "http://127.0.0.1:5000/api/tool/my-tool-by-name/invoke",
-			enabled:        configs.supportOptionalNullParam,
-			requestHeader:  map[string]string{},
-			requestBody:    bytes.NewBuffer([]byte(`{}`)),
-			wantBody:       configs.nullWant,
-			wantStatusCode: http.StatusOK,
+			Name:     invokeMyToolByNameTest,
+			Tool:     "my-tool-by-name",
+			WantBody: "null",
 		},
 		{
-			name:           "Invoke my-tool without parameters",
-			api:            "http://127.0.0.1:5000/api/tool/my-tool/invoke",
-			enabled:        true,
-			requestHeader:  map[string]string{},
-			requestBody:    bytes.NewBuffer([]byte(`{}`)),
-			wantBody:       "",
-			wantStatusCode: http.StatusBadRequest,
+			Name:           "Invoke my-tool without parameters",
+			Tool:           "my-tool",
+			WantStatusCode: http.StatusBadRequest,
 		},
 		{
-			name:           "Invoke my-tool with insufficient parameters",
-			api:            "http://127.0.0.1:5000/api/tool/my-tool/invoke",
-			enabled:        true,
-			requestHeader:  map[string]string{},
-			requestBody:    bytes.NewBuffer([]byte(`{"id": 1}`)),
-			wantBody:       "",
-			wantStatusCode: http.StatusBadRequest,
+			Name:           "Invoke my-tool with insufficient parameters",
+			Tool:           "my-tool",
+			RequestBody:    `{"id": 1}`,
+			WantStatusCode: http.StatusBadRequest,
 		},
 		{
-			name:           "invoke my-array-tool",
-			api:            "http://127.0.0.1:5000/api/tool/my-array-tool/invoke",
-			enabled:        configs.supportArrayParam,
-			requestHeader:  map[string]string{},
-			requestBody:    bytes.NewBuffer([]byte(`{"idArray": [1,2,3], "nameArray": ["Alice", "Sid", "RandomName"], "cmdArray": ["HGETALL", "row3"]}`)),
-			wantBody:       configs.myArrayToolWant,
-			wantStatusCode: http.StatusOK,
+			Name:        invokeMyArrayToolTest,
+			Tool:        "my-array-tool",
+			RequestBody: `{"idArray": [1,2,3], "nameArray": ["Alice", "Sid", "RandomName"], "cmdArray": ["HGETALL", "row3"]}`,
+			WantBody:    "[{\"id\":1,\"name\":\"Alice\"},{\"id\":3,\"name\":\"Sid\"}]",
 		},
 		{
-			name:           "Invoke my-auth-tool with auth token",
-			api:            "http://127.0.0.1:5000/api/tool/my-auth-tool/invoke",
-			enabled:        configs.supportSelect1Auth,
-			requestHeader:  map[string]string{"my-google-auth_token": idToken},
-			requestBody:    bytes.NewBuffer([]byte(`{}`)),
-			wantBody:       "[{\"name\":\"Alice\"}]",
-			wantStatusCode: http.StatusOK,
+			Name:               select1AuthTests[0],
+			Tool:               "my-auth-tool",
+			IdTokenAuthService: "my-google-auth",
+			WantBody:           "[{\"name\":\"Alice\"}]",
 		},
 		{
-			name:           "Invoke my-auth-tool with invalid auth token",
-			api:            "http://127.0.0.1:5000/api/tool/my-auth-tool/invoke",
-			enabled:        configs.supportSelect1Auth,
-			requestHeader:  map[string]string{"my-google-auth_token": "INVALID_TOKEN"},
-			requestBody:    bytes.NewBuffer([]byte(`{}`)),
-			wantBody:       "",
-			wantStatusCode: http.StatusUnauthorized,
+			Name:           select1AuthTests[1],
+			Tool:           "my-auth-tool",
+			RequestHeader:  map[string]string{"my-google-auth_token": "INVALID_TOKEN"},
+			WantStatusCode: http.StatusUnauthorized,
 		},
 		{
-			name:           "Invoke my-auth-tool without auth token",
-			api:            "http://127.0.0.1:5000/api/tool/my-auth-tool/invoke",
-			enabled:        true,
-			requestHeader:  map[string]string{},
-			requestBody:    bytes.NewBuffer([]byte(`{}`)),
-			wantBody:       "",
-			wantStatusCode: http.StatusUnauthorized,
+			Name:           "Invoke my-auth-tool without auth token",
+			Tool:           "my-auth-tool",
+			WantStatusCode: http.StatusUnauthorized,
 		},
 		{
-			name:           "Invoke my-auth-required-tool with auth token",
-			api:            "http://127.0.0.1:5000/api/tool/my-auth-required-tool/invoke",
-			enabled:        configs.supportSelect1Auth,
-			requestHeader:  map[string]string{"my-google-auth_token": idToken},
-			requestBody:    bytes.NewBuffer([]byte(`{}`)),
-			wantBody:       select1Want,
-			wantStatusCode: http.StatusOK,
+			Name:               select1AuthTests[2],
+			Tool:               "my-auth-required-tool",
+			IdTokenAuthService: "my-google-auth",
+			WantBody:           select1Want,
 		},
 		{
-			name:           "Invoke my-auth-required-tool with invalid auth token",
-			api:            "http://127.0.0.1:5000/api/tool/my-auth-required-tool/invoke",
-			enabled:        true,
-			requestHeader:  map[string]string{"my-google-auth_token": "INVALID_TOKEN"},
-			requestBody:    bytes.NewBuffer([]byte(`{}`)),
-			wantBody:       "",
-			wantStatusCode: http.StatusUnauthorized,
+			Name:           "Invoke my-auth-required-tool with invalid auth token",
+			Tool:           "my-auth-required-tool",
+			RequestHeader:  map[string]string{"my-google-auth_token": "INVALID_TOKEN"},
+			WantStatusCode: http.StatusUnauthorized,
 		},
 		{
-			name:           "Invoke my-auth-required-tool without auth token",
-			api:            "http://127.0.0.1:5000/api/tool/my-auth-tool/invoke",
-			enabled:        true,
-			requestHeader:  map[string]string{},
-			requestBody:    bytes.NewBuffer([]byte(`{}`)),
-			wantBody:       "",
-			wantStatusCode: http.StatusUnauthorized,
+			Name:           "Invoke my-auth-required-tool without auth token",
+			Tool:           "my-auth-tool",
+			WantStatusCode: http.StatusUnauthorized,
 		},
 		{
-			name:           "Invoke my-client-auth-tool with auth token",
-			api:            "http://127.0.0.1:5000/api/tool/my-client-auth-tool/invoke",
-			enabled:        configs.supportClientAuth,
-			requestHeader:  map[string]string{"Authorization": accessToken},
-			requestBody:    bytes.NewBuffer([]byte(`{}`)),
-			wantBody:       select1Want,
-			wantStatusCode: http.StatusOK,
+			Name:           clientAuthTests[0],
+			Tool:           "my-client-auth-tool",
+			UseAccessToken: true,
+			WantBody:       select1Want,
+			Skip:           true,
 		},
 		{
-			name:           "Invoke my-client-auth-tool without auth token",
-			api:            "http://127.0.0.1:5000/api/tool/my-client-auth-tool/invoke",
-			enabled:        configs.supportClientAuth,
-			requestHeader:  map[string]string{},
-			requestBody:    bytes.NewBuffer([]byte(`{}`)),
-			wantStatusCode: http.StatusUnauthorized,
+			Name:           clientAuthTests[1],
+			Tool:           "my-client-auth-tool",
+			WantStatusCode: http.StatusUnauthorized,
+			Skip:           true,
 		},
 		{
-
-			name:           "Invoke my-client-auth-tool with invalid auth token",
-			api:            "http://127.0.0.1:5000/api/tool/my-client-auth-tool/invoke",
-			enabled:        configs.supportClientAuth,
-			requestHeader:  map[string]string{"Authorization": "Bearer invalid-token"},
-			requestBody:    bytes.NewBuffer([]byte(`{}`)),
-			wantStatusCode: http.StatusUnauthorized,
+			Name:           clientAuthTests[2],
+			Tool:           "my-client-auth-tool",
+			RequestHeader:  map[string]string{"Authorization": "Bearer invalid-token"},
+			WantStatusCode: http.StatusUnauthorized,
+			Skip:           true,
 		},
+	}}
+}
+
+// RunToolInvokeTest runs the subtests of NewInvokeExpectations, adjusted by
+// options, against the tool invoke endpoint.
+func RunToolInvokeTest(t *testing.T, select1Want string, options ...InvokeTestOption) {
+	e := NewInvokeExpectations(select1Want)
+	for _, option := range options {
+		option(e)
 	}
-	for _, tc := range invokeTcs {
-		t.Run(tc.name, func(t *testing.T) {
-			if !tc.enabled {
-				return
+	RunToolInvokeExpectations(t, e)
+}
+
+// InvokeTestCase is a request to the tool invoke endpoint and its expected
+// response, run by RunToolInvokeTestCases.
+type InvokeTestCase struct {
+	// Name is the name of the subtest, which options use to refer to it.
+	Name string
+	// Tool is the name of the tool to invoke.
+	Tool string
+	// Skip skips the subtest.
+	Skip bool
+	// RequestHeader are additional headers of the request.
+	RequestHeader map[string]string
+	// IdTokenAuthService, if set, is the name of the auth service whose
+	// `<name>_token` header is set to a Google ID token for ClientId.
+	IdTokenAuthService string
+	// UseAccessToken sets the Authorization header to an access token from
+	// Application Default Credentials.
+	UseAccessToken bool
+	// RequestBody is the JSON body of the request. Defaults to `{}`.
+	RequestBody string
+	// WantStatusCode is the expected status code. Defaults to 200.
+	WantStatusCode int
+	// WantBody is the expected `result` of the response. If empty, the
+	// result is not checked.
+	WantBody string
+	// WantResultKeys are keys that the `result` of the response must have,
+	// once decoded as JSON. If the result is a list, each of its elements
+	// must have them.
+	WantResultKeys []string
+	// WantError asserts that the response has an `error`, whatever it is.
+	WantError bool
+	// WantErrorContains is a substring of the expected `error` of the
+	// response. If empty, the error is not checked.
+	WantErrorContains string
+	// WantHintContains is a substring of the expected `hint` of the error
+	// of the response. If empty, the hint is not checked.
+	WantHintContains string
+	// Check, if set, makes additional assertions on the decoded response.
+	Check func(t *testing.T, body map[string]any)
+}
+
+// RunToolInvokeTestCases runs tcs against the tool invoke endpoint. Options
+// created with WithInvokeRequestBody, WithInvokeWantStatusCode,
+// WithInvokeWantError, SkipInvokeTests and WithInvokeTests apply to them.
+// e.g. tests.RunToolInvokeTestCases(t, tcs, tests.SkipInvokeTests("invoke my-auth-tool"))
+func RunToolInvokeTestCases(t *testing.T, tcs []InvokeTestCase, options ...InvokeTestOption) {
+	e := &InvokeExpectations{Cases: slices.Clone(tcs)}
+	for _, option := range options {
+		option(e)
+	}
+	RunToolInvokeExpectations(t, e)
+}
+
+// RunToolInvokeExpectations runs the subtests of e against the tool invoke
+// endpoint.
+// e.g.
+//
+//	e := tests.NewInvokeExpectations(select1Want)
+//	e.Case("invoke my-tool").WantBody = myToolWant
+//	tests.RunToolInvokeExpectations(t, e)
+func RunToolInvokeExpectations(t *testing.T, e *InvokeExpectations) {
+	for _, name := range e.unknown {
+		t.Errorf("options refer to invoke subtest %q, which does not exist", name)
+	}
+
+	// Tokens are only fetched for the cases that need them, so that suites
+	// without auth can run without Google credentials.
+	getIdToken := sync.OnceValues(func() (string, error) {
+		return GetGoogleIdToken(ClientId)
+	})
+	getAccessToken := sync.OnceValues(func() (string, error) {
+		return sources.GetIAMAccessToken(t.Context())
+	})
+
+	for _, tc := range e.Cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			if tc.Skip {
+				t.Skip("skipped by the test suite")
+			}
+			requestBody := tc.RequestBody
+			if requestBody == "" {
+				requestBody = "{}"
 			}
+
 			// Send Tool invocation request
-			req, err := http.NewRequest(http.MethodPost, tc.api, tc.requestBody)
+			api := fmt.Sprintf("http://127.0.0.1:5000/api/tool/%s/invoke", tc.Tool)
+			req, err := http.NewRequest(http.MethodPost, api, strings.NewReader(requestBody))
 			if err != nil {
 				t.Fatalf("unable to create request: %s", err)
 			}
 			req.Header.Add("Content-type", "application/json")
 			// Add headers
-			for k, v := range tc.requestHeader {
+			for k, v := range tc.RequestHeader {
 				req.Header.Add(k, v)
 			}
+			if tc.IdTokenAuthService != "" {
+				idToken, err := getIdToken()
+				if err != nil {
+					t.Fatalf("error getting Google ID token: %s", err)
+				}
+				req.Header.Add(tc.IdTokenAuthService+"_token", idToken)
+			}
+			if tc.UseAccessToken {
+				accessToken, err := getAccessToken()
+				if err != nil {
+					t.Fatalf("error getting access token from ADC: %s", err)
+				}
+				req.Header.Add("Authorization", "Bearer "+accessToken)
+			}
 			resp, err := http.DefaultClient.Do(req)
 			if err != nil {
 				t.Fatalf("unable to send request: %s", err)
 			}
 			defer resp.Body.Close()
-
-			// Check status code
-			if resp.StatusCode != tc.wantStatusCode {
-				body, _ := io.ReadAll(resp.Body)
-				t.Errorf("StatusCode mismatch: got %d, want %d. Response body: %s", resp.StatusCode, tc.wantStatusCode, string(body))
-			}
-
-			// skip response body check
-			if tc.wantBody == "" {
-				return
+			respBody, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("unable to read response body: %s", err)
 			}
 
-			// Check response body
-			var body map[string]interface{}
-			err = json.NewDecoder(resp.Body).Decode(&body)
+			body, err := checkInvokeResponse(tc, resp.StatusCode, respBody)
 			if err != nil {
-				t.Fatalf("error parsing response body: %s", err)
+				t.Fatal(err)
+			}
+			if tc.Check != nil {
+				tc.Check(t, body)
 			}
+		})
+	}
+}
 
-			got, ok := body["result"].(string)
-			if !ok {
-				t.Fatalf("unable to find result in response body")
+// checkInvokeResponse checks a response of the tool invoke endpoint against
+// the expectations of tc, and returns its decoded body. The body is only
+// decoded if tc checks it.
+func checkInvokeResponse(tc InvokeTestCase, statusCode int, respBody []byte) (map[string]any, error) {
+	wantStatusCode := tc.WantStatusCode
+	if wantStatusCode == 0 {
+		wantStatusCode = http.StatusOK
+	}
+	if statusCode != wantStatusCode {
+		return nil, fmt.Errorf("StatusCode mismatch: got %d, want %d. Response body: %s", statusCode, wantStatusCode, string(respBody))
+	}
+
+	// skip response body check
+	checksResult := tc.WantBody != "" || len(tc.WantResultKeys) > 0
+	checksError := tc.WantError || tc.WantErrorContains != "" || tc.WantHintContains != ""
+	if !checksResult && !checksError && tc.Check == nil {
+		return nil, nil
+	}
+
+	var body map[string]any
+	if err := json.Unmarshal(respBody, &body); err != nil {
+		return nil, fmt.Errorf("error parsing response body: %s", err)
+	}
+
+	if checksError {
+		got, ok := body["error"].(string)
+		if !ok {
+			return nil, fmt.Errorf("expected an error in response body, got %s", string(respBody))
+		}
+		if !strings.Contains(got, tc.WantErrorContains) {
+			return nil, fmt.Errorf("unexpected error: got %q, want substring %q", got, tc.WantErrorContains)
+		}
+		if hint, _ := body["hint"].(string); !strings.Contains(hint, tc.WantHintContains) {
+			return nil, fmt.Errorf("unexpected hint: got %q, want substring %q", hint, tc.WantHintContains)
+		}
+	}
+
+	if checksResult {
+		got, ok := body["result"].(string)
+		if !ok {
+			return nil, fmt.Errorf("unable to find result in response body")
+		}
+		if tc.WantBody != "" && got != tc.WantBody {
+			return nil, fmt.Errorf("unexpected value: got %q, want %q", got, tc.WantBody)
+		}
+		if len(tc.WantResultKeys) > 0 {
+			if err := checkResultKeys(got, tc.WantResultKeys); err != nil {
+				return nil, err
 			}
+		}
+	}
+	return body, nil
+}
 
-			if got != tc.wantBody {
-				t.Fatalf("unexpected value: got %q, want %q", got, tc.wantBody)
+// checkResultKeys checks that the JSON object result, or each element of the
+// JSON list result, has keys.
+func checkResultKeys(result string, keys []string) error {
+	var v any
+	if err := json.Unmarshal([]byte(result), &v); err != nil {
+		return fmt.Errorf("error parsing result %q: %s", result, err)
+	}
+	objects := []any{v}
+	if list, ok := v.([]any); ok {
+		if len(list) == 0 {
+			return fmt.Errorf("expected results with keys %q, got an empty list", keys)
+		}
+		objects = list
+	}
+	for _, o := range objects {
+		m, ok := o.(map[string]any)
+		if !ok {
+			return fmt.Errorf("expected an object with keys %q, got %v", keys, o)
+		}
+		for _, k := range keys {
+			if _, ok := m[k]; !ok {
+				return fmt.Errorf("expected key %q in %v", k, m)
 			}
-		})
+		}
 	}
+	return nil
 }
 
 // RunToolInvokeWithTemplateParameters runs tool invoke test cases with template parameters.
@@ -951,7 +1060,7 @@ func RunMCPToolCallMethod(t *testing.T, myFailToolWant, select1Want string, opti
 				},
 			},
 			wantStatusCode: http.StatusOK,
-			wantBody:       `{"jsonrpc":"2.0","id":"invoke-without-parameter","error":{"code":-32602,"message":"provided parameters were invalid: parameter \"id\" is required"}}`,
+			wantBody:       `{"jsonrpc":"2.0","id":"invoke-without-parameter","error":{"code":-32602,"message":"provided parameters were invalid: parameter \"id\" is required`,
 		},
 		{
 			name:          "MCP Invoke my-tool with insufficient parameters",
@@ -970,7 +1079,7 @@ func RunMCPToolCallMethod(t *testing.T, myFailToolWant, select1Want string, opti
 				},
 			},
 			wantStatusCode: http.StatusOK,
-			wantBody:       `{"jsonrpc":"2.0","id":"invoke-insufficient-parameter","error":{"code":-32602,"message":"provided parameters were invalid: parameter \"name\" is required"}}`,
+			wantBody:       `{"jsonrpc":"2.0","id":"invoke-insufficient-parameter","error":{"code":-32602,"message":"provided parameters were invalid: parameter \"name\" is required`,
 		},
 		{
 			name:          "MCP Invoke my-auth-required-tool",
@@ -2057,6 +2166,92 @@ func RunMSSQLListTablesTest(t *testing.T, tableNameParam, tableNameAuth string)
 	}
 }
 
+// RunSampleTableTest invokes the sample_table tool, of a postgres-sample-table
+// or mysql-sample-table kind, on the param table, whose rows are set up by
+// GetPostgresSQLParamToolInfo or GetMySQLParamToolInfo.
+func RunSampleTableTest(t *testing.T, tableNameParam string) {
+	api := "http://127.0.0.1:5000/api/tool/sample_table/invoke"
+	invoke := func(t *testing.T, body string) map[string]any {
+		t.Helper()
+		resp, respBody := RunRequest(t, http.MethodPost, api, bytes.NewBufferString(body), nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, http.StatusOK, respBody)
+		}
+		var envelope struct {
+			Result string `json:"result"`
+		}
+		if err := json.Unmarshal(respBody, &envelope); err != nil {
+			t.Fatalf("error parsing response body: %s", err)
+		}
+		var result map[string]any
+		if err := json.Unmarshal([]byte(envelope.Result), &result); err != nil {
+			t.Fatalf("error parsing result %q: %s", envelope.Result, err)
+		}
+		return result
+	}
+	column := func(t *testing.T, result map[string]any, name string) map[string]any {
+		t.Helper()
+		columns, _ := result["columns"].([]any)
+		for _, c := range columns {
+			if c, ok := c.(map[string]any); ok && c["column"] == name {
+				return c
+			}
+		}
+		t.Fatalf("no summary of column %q in %v", name, result["columns"])
+		return nil
+	}
+
+	t.Run("first rows", func(t *testing.T) {
+		result := invoke(t, fmt.Sprintf(`{"table": %q, "method": "first"}`, tableNameParam))
+		if result["method"] != "first" || result["strategy"] != "limit" {
+			t.Fatalf("unexpected method: %v", result)
+		}
+		if table, _ := result["table"].(string); !strings.HasSuffix(table, "."+tableNameParam) {
+			t.Fatalf("unexpected table: %v", result["table"])
+		}
+		if rows, _ := result["rows"].([]any); len(rows) != 4 {
+			t.Fatalf("unexpected rows: got %v, want the 4 rows of the table", result["rows"])
+		}
+		id := column(t, result, "id")
+		if id["min"] != float64(1) || id["max"] != float64(4) {
+			t.Fatalf("unexpected range of id: %v", id)
+		}
+		name := column(t, result, "name")
+		if _, ok := name["min"]; ok {
+			t.Fatalf("unexpected range of a text column: %v", name)
+		}
+		// the table may have been analyzed since it was created
+		if name["nullFractionSource"] == "sample" && name["nullFraction"] != 0.25 {
+			t.Fatalf("unexpected null fraction of name: %v", name)
+		}
+		if name["distinctCountSource"] == "sample" && name["distinctCount"] != float64(3) {
+			t.Fatalf("unexpected distinct count of name: %v", name)
+		}
+	})
+	t.Run("random rows", func(t *testing.T) {
+		result := invoke(t, fmt.Sprintf(`{"table": %q, "sample_size": 2}`, tableNameParam))
+		if result["method"] != "random" || result["strategy"] == "limit" {
+			t.Fatalf("unexpected method: %v", result)
+		}
+		if rows, _ := result["rows"].([]any); len(rows) != 2 {
+			t.Fatalf("unexpected rows: got %v, want 2 rows", result["rows"])
+		}
+	})
+	for name, body := range map[string]string{
+		"invalid table":       `{"table": "x; DROP TABLE x"}`,
+		"missing table":       `{"table": "missing_table_for_sample"}`,
+		"invalid sample size": fmt.Sprintf(`{"table": %q, "sample_size": 0}`, tableNameParam),
+		"invalid method":      fmt.Sprintf(`{"table": %q, "method": "last"}`, tableNameParam),
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp, respBody := RunRequest(t, http.MethodPost, api, bytes.NewBufferString(body), nil)
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, http.StatusBadRequest, respBody)
+			}
+		})
+	}
+}
+
 // RunRequest is a helper function to send HTTP requests and return the response
 func RunRequest(t *testing.T, method, url string, body io.Reader, headers map[string]string) (*http.Response, []byte) {
 	// Send request
@@ -0,0 +1,144 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+// TestRunToolInvokeTestCases checks the invoke test options against a wait
+// tool, which needs no database.
+func TestRunToolInvokeTestCases(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	toolsFile := map[string]any{
+		"tools": map[string]any{
+			"my-wait-tool": map[string]any{
+				"kind":        "wait",
+				"description": "Wait for a specified duration.",
+				"timeout":     "30s",
+			},
+		},
+	}
+	cmd, cleanup, err := StartCmd(ctx, toolsFile)
+	if err != nil {
+		t.Fatalf("command initialization returned an error: %s", err)
+	}
+	defer cleanup()
+
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	_, out, err := testutils.WaitForServerReady(waitCtx, cmd.Out)
+	if err != nil {
+		t.Logf("toolbox command logs: \n%s", out)
+		t.Fatalf("toolbox didn't start successfully: %s", err)
+	}
+
+	var checked bool
+	tcs := []InvokeTestCase{
+		{
+			Name:        "invoke my-wait-tool",
+			Tool:        "my-wait-tool",
+			RequestBody: `{"duration": "1ms"}`,
+			WantBody:    `"Wait for 1ms completed successfully."`,
+			Check: func(t *testing.T, body map[string]any) {
+				checked = true
+			},
+		},
+		{
+			Name:              "invoke my-wait-tool with invalid duration",
+			Tool:              "my-wait-tool",
+			RequestBody:       `{"duration": "invalid"}`,
+			WantStatusCode:    http.StatusBadRequest,
+			WantErrorContains: "invalid duration format",
+		},
+		{
+			Name:        "invoke my-wait-tool with overridden request",
+			Tool:        "my-wait-tool",
+			RequestBody: `{"duration": "1ms"}`,
+		},
+		{
+			// would fail if it was not skipped
+			Name:           "invoke missing tool",
+			Tool:           "my-missing-tool",
+			WantStatusCode: http.StatusOK,
+		},
+	}
+	RunToolInvokeTestCases(t, tcs,
+		WithInvokeRequestBody("invoke my-wait-tool with overridden request", `{"duration": "bad"}`),
+		WithInvokeWantStatusCode("invoke my-wait-tool with overridden request", http.StatusBadRequest),
+		WithInvokeWantError("invoke my-wait-tool with overridden request", `invalid duration format`),
+		SkipInvokeTests("invoke missing tool"),
+		WithInvokeTests(InvokeTestCase{
+			Name:              "invoke my-wait-tool without duration",
+			Tool:              "my-wait-tool",
+			WantStatusCode:    http.StatusBadRequest,
+			WantErrorContains: `parameter "duration" is required`,
+		}),
+	)
+	if !checked {
+		t.Fatalf("Check of invoke my-wait-tool was not called")
+	}
+}
+
+func TestCheckInvokeResponse(t *testing.T) {
+	result := `{"result": "[{\"name\":\"entry-1\",\"aspects\":{}},{\"name\":\"entry-2\"}]"}`
+	failure := `{"error": "error while invoking tool: entry not found", "hint": "the entries are entry-1, entry-2"}`
+	tcs := []struct {
+		desc    string
+		tc      InvokeTestCase
+		status  int
+		body    string
+		wantErr string
+	}{
+		{desc: "no checks", status: http.StatusOK, body: "not json"},
+		{desc: "default status", status: http.StatusBadRequest, body: failure, wantErr: "StatusCode mismatch"},
+		{desc: "result", tc: InvokeTestCase{WantBody: `[{"name":"entry-1","aspects":{}},{"name":"entry-2"}]`}, status: http.StatusOK, body: result},
+		{desc: "wrong result", tc: InvokeTestCase{WantBody: "[]"}, status: http.StatusOK, body: result, wantErr: "unexpected value"},
+		{desc: "result keys", tc: InvokeTestCase{WantResultKeys: []string{"name"}}, status: http.StatusOK, body: result},
+		{desc: "result key of some elements", tc: InvokeTestCase{WantResultKeys: []string{"aspects"}}, status: http.StatusOK, body: result, wantErr: `expected key "aspects"`},
+		{desc: "result keys of empty list", tc: InvokeTestCase{WantResultKeys: []string{"name"}}, status: http.StatusOK, body: `{"result": "[]"}`, wantErr: "empty list"},
+		{desc: "result keys of object", tc: InvokeTestCase{WantResultKeys: []string{"name"}}, status: http.StatusOK, body: `{"result": "{\"name\":\"entry-1\"}"}`},
+		{desc: "missing result", tc: InvokeTestCase{WantResultKeys: []string{"name"}}, status: http.StatusOK, body: `{"result": null}`, wantErr: "unable to find result"},
+		{desc: "any error", tc: InvokeTestCase{WantStatusCode: http.StatusBadRequest, WantError: true}, status: http.StatusBadRequest, body: failure},
+		{desc: "missing error", tc: InvokeTestCase{WantError: true}, status: http.StatusOK, body: result, wantErr: "expected an error"},
+		{desc: "error contains", tc: InvokeTestCase{WantStatusCode: http.StatusBadRequest, WantErrorContains: "not found"}, status: http.StatusBadRequest, body: failure},
+		{desc: "hint contains", tc: InvokeTestCase{WantStatusCode: http.StatusBadRequest, WantHintContains: "entry-2"}, status: http.StatusBadRequest, body: failure},
+		{desc: "wrong hint", tc: InvokeTestCase{WantStatusCode: http.StatusBadRequest, WantHintContains: "entry-3"}, status: http.StatusBadRequest, body: failure, wantErr: "unexpected hint"},
+		{desc: "missing hint", tc: InvokeTestCase{WantStatusCode: http.StatusBadRequest, WantHintContains: "entry"}, status: http.StatusBadRequest, body: `{"error": "entry not found"}`, wantErr: "unexpected hint"},
+		{desc: "wrong error", tc: InvokeTestCase{WantStatusCode: http.StatusBadRequest, WantErrorContains: "denied"}, status: http.StatusBadRequest, body: failure, wantErr: "unexpected error"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := checkInvokeResponse(tc.tc, tc.status, []byte(tc.body))
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("got error %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -15,11 +15,14 @@
 package trino
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
-	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -150,7 +153,7 @@ func getTrinoTmplToolStatement() (string, string) {
 // getTrinoWants return the expected wants for trino
 func getTrinoWants() (string, string, string, string) {
 	select1Want := `[{"_col0":1}]`
-	failInvocationWant := `{"jsonrpc":"2.0","id":"invoke-fail-tool","result":{"content":[{"type":"text","text":"unable to execute query: trino: query failed (200 OK): \"USER_ERROR: line 1:1: mismatched input 'SELEC'. Expecting: 'ALTER', 'ANALYZE', 'CALL', 'COMMENT', 'COMMIT', 'CREATE', 'DEALLOCATE', 'DELETE', 'DENY', 'DESC', 'DESCRIBE', 'DROP', 'EXECUTE', 'EXPLAIN', 'GRANT', 'INSERT', 'MERGE', 'PREPARE', 'REFRESH', 'RESET', 'REVOKE', 'ROLLBACK', 'SET', 'SHOW', 'START', 'TRUNCATE', 'UPDATE', 'USE', 'WITH', \u003cquery\u003e\""}],"isError":true}}`
+	failInvocationWant := `{"jsonrpc":"2.0","id":"invoke-fail-tool","result":{"content":[{"type":"text","text":"unable to execute query: SYNTAX_ERROR: line 1:1: mismatched input 'SELEC'. Expecting: 'ALTER', 'ANALYZE', 'CALL', 'COMMENT', 'COMMIT', 'CREATE', 'DEALLOCATE', 'DELETE', 'DENY', 'DESC', 'DESCRIBE', 'DROP', 'EXECUTE', 'EXPLAIN', 'GRANT', 'INSERT', 'MERGE', 'PREPARE', 'REFRESH', 'RESET', 'REVOKE', 'ROLLBACK', 'SET', 'SHOW', 'START', 'TRUNCATE', 'UPDATE', 'USE', 'WITH', \u003cquery\u003e"}],"isError":true}}`
 	createTableStatement := `"CREATE TABLE t (id BIGINT NOT NULL, name VARCHAR(255))"`
 	mcpSelect1Want := `{"jsonrpc":"2.0","id":"invoke my-auth-required-tool","result":{"content":[{"type":"text","text":"{\"_col0\":1}"}]}}`
 	return select1Want, failInvocationWant, createTableStatement, mcpSelect1Want
@@ -204,6 +207,14 @@ func addTrinoExecuteSqlConfig(t *testing.T, config map[string]any) map[string]an
 			"my-google-auth",
 		},
 	}
+	tools["my-session-exec-sql-tool"] = map[string]any{
+		"kind":        "trino-execute-sql",
+		"source":      "my-instance",
+		"description": "Tool to execute sql with session properties",
+		"sessionProperties": map[string]any{
+			"query_max_run_time": "42m",
+		},
+	}
 	config["tools"] = tools
 	return config
 }
@@ -249,7 +260,7 @@ func TestTrinoToolEndpoints(t *testing.T) {
 
 	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
-	out, err := testutils.WaitForString(waitCtx, regexp.MustCompile(`Server ready to serve`), cmd.Out)
+	_, out, err := testutils.WaitForServerReady(waitCtx, cmd.Out)
 	if err != nil {
 		t.Logf("toolbox command logs: \n%s", out)
 		t.Fatalf("toolbox didn't start successfully: %s", err)
@@ -264,4 +275,59 @@ func TestTrinoToolEndpoints(t *testing.T) {
 	tests.RunMCPToolCallMethod(t, mcpMyFailToolWant, mcpSelect1Want)
 	tests.RunExecuteSqlToolInvokeTest(t, createTableStatement, select1Want)
 	tests.RunToolInvokeWithTemplateParameters(t, tableNameTemplateParam, tests.WithInsert1Want(`[{"rows":1}]`))
+	runTrinoExecuteSqlTest(t)
+}
+
+// runTrinoExecuteSqlTest checks the conversion of Trino types and the use of
+// session properties.
+func runTrinoExecuteSqlTest(t *testing.T) {
+	invokeTcs := []struct {
+		name     string
+		api      string
+		sql      string
+		contains string
+	}{
+		{
+			name: "complex types",
+			api:  "http://127.0.0.1:5000/api/tool/my-exec-sql-tool/invoke",
+			sql: `SELECT ARRAY[1, 2] AS a, CAST(12.34 AS DECIMAL(10, 2)) AS d, MAP(ARRAY['k'], ARRAY[CAST(1.5 AS DECIMAL(3, 1))]) AS m, ` +
+				`CAST(ROW(1, 'x') AS ROW(id BIGINT, name VARCHAR)) AS r, TIMESTAMP '2025-01-02 03:04:05 UTC' AS ts`,
+			contains: `[{"a":[1,2],"d":12.34,"m":{"k":1.5},"r":{"id":1,"name":"x"},"ts":"2025-01-02T03:04:05Z"}]`,
+		},
+		{
+			name:     "session properties",
+			api:      "http://127.0.0.1:5000/api/tool/my-session-exec-sql-tool/invoke",
+			sql:      "SHOW SESSION LIKE 'query_max_run_time'",
+			contains: `"Value":"42m"`,
+		},
+	}
+	for _, tc := range invokeTcs {
+		t.Run(tc.name, func(t *testing.T) {
+			reqBody, err := json.Marshal(map[string]any{"sql": tc.sql})
+			if err != nil {
+				t.Fatalf("unable to marshal request body: %s", err)
+			}
+			resp, err := http.Post(tc.api, "application/json", bytes.NewBuffer(reqBody))
+			if err != nil {
+				t.Fatalf("unable to send request: %s", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				bodyBytes, _ := io.ReadAll(resp.Body)
+				t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, string(bodyBytes))
+			}
+
+			var body map[string]any
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("error parsing response body: %s", err)
+			}
+			got, ok := body["result"].(string)
+			if !ok {
+				t.Fatalf("unable to find result in response body")
+			}
+			if !strings.Contains(got, tc.contains) {
+				t.Fatalf("unexpected result: got %s, want to contain %s", got, tc.contains)
+			}
+		})
+	}
 }